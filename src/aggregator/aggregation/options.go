@@ -38,12 +38,16 @@ type Options struct {
 	// HasExpensiveAggregations means expensive (multiplication／division)
 	// aggregation types are enabled.
 	HasExpensiveAggregations bool
+	// HasSketch means timer values are aggregated into a mergeable sketch
+	// rather than a stream of fixed quantiles.
+	HasSketch bool
 }
 
 // Metrics is a set of metrics that can be used by elements.
 type Metrics struct {
 	Counter CounterMetrics
 	Gauge   GaugeMetrics
+	Timer   TimerMetrics
 }
 
 // CounterMetrics is a set of counter metrics can be used by all counters.
@@ -56,12 +60,19 @@ type GaugeMetrics struct {
 	valuesOutOfOrder tally.Counter
 }
 
+// TimerMetrics is a set of timer metrics can be used by all timers.
+type TimerMetrics struct {
+	invalidSketches tally.Counter
+	invalidForwards tally.Counter
+}
+
 // NewMetrics is a set of aggregation metrics.
 func NewMetrics(scope tally.Scope) Metrics {
 	scope = scope.SubScope("aggregation")
 	return Metrics{
 		Counter: newCounterMetrics(scope.SubScope("counters")),
 		Gauge:   newGaugeMetrics(scope.SubScope("gauges")),
+		Timer:   newTimerMetrics(scope.SubScope("timers")),
 	}
 }
 
@@ -91,6 +102,27 @@ func (m GaugeMetrics) IncValuesOutOfOrder() {
 	}
 }
 
+func newTimerMetrics(scope tally.Scope) TimerMetrics {
+	return TimerMetrics{
+		invalidSketches: scope.Counter("invalid-sketches"),
+		invalidForwards: scope.Counter("invalid-forwards"),
+	}
+}
+
+// IncInvalidSketches increments value or if not initialized is a no-op.
+func (m TimerMetrics) IncInvalidSketches() {
+	if m.invalidSketches != nil {
+		m.invalidSketches.Inc(1)
+	}
+}
+
+// IncInvalidForwards increments value or if not initialized is a no-op.
+func (m TimerMetrics) IncInvalidForwards() {
+	if m.invalidForwards != nil {
+		m.invalidForwards.Inc(1)
+	}
+}
+
 // NewOptions creates a new aggregation options.
 func NewOptions(instrumentOpts instrument.Options) Options {
 	return Options{
@@ -102,4 +134,5 @@ func NewOptions(instrumentOpts instrument.Options) Options {
 // ResetSetData resets the aggregation options.
 func (o *Options) ResetSetData(aggTypes aggregation.Types) {
 	o.HasExpensiveAggregations = isExpensive(aggTypes)
+	o.HasSketch = aggTypes.Contains(aggregation.Sketch)
 }
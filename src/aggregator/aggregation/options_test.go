@@ -41,4 +41,8 @@ func TestOptions(t *testing.T) {
 
 	o.ResetSetData(aggregation.Types{aggregation.Sum, aggregation.SumSq})
 	require.True(t, o.HasExpensiveAggregations)
+	require.False(t, o.HasSketch)
+
+	o.ResetSetData(aggregation.Types{aggregation.P99, aggregation.Sketch})
+	require.True(t, o.HasSketch)
 }
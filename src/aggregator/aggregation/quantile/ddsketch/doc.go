@@ -0,0 +1,35 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+/*
+Package ddsketch implements DDSketch, a mergeable sketch for computing
+quantiles with a relative error guarantee from "DDSketch: A Fast and
+Fully-Mergeable Quantile Sketch with Relative-Error Guarantees".
+
+Values are counted in buckets with logarithmically spaced boundaries. Unlike
+the original paper the base of the logarithm is restricted to 2^(2^-schema),
+the same as the buckets of native histograms, so that sketches of different
+accuracy can always be merged by merging pairs of adjacent buckets and a
+sketch can be stored as a native histogram without loss. When a sketch holds
+more buckets than configured, its schema is lowered rather than collapsing
+the lowest buckets, trading accuracy across the whole range for bounded
+memory.
+*/
+package ddsketch
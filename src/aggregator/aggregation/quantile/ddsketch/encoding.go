@@ -0,0 +1,240 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ddsketch
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+const encodingVersion = 1
+
+var (
+	// encodingMagic starts every encoded sketch. NB: the first byte is zero
+	// which never starts a protobuf message since zero is not a valid field
+	// number, so encoded sketches can be told apart from the protobuf
+	// annotations of other metrics.
+	encodingMagic = []byte{0x00, 'd', 'd', 's'}
+
+	errEncodedTooShort     = errors.New("encoded sketch is too short")
+	errEncodedTrailing     = errors.New("encoded sketch has trailing bytes")
+	errEncodedNotSketch    = errors.New("encoded sketch has no sketch header")
+	errEncodedCountInvalid = errors.New("encoded sketch count does not match its buckets")
+)
+
+// IsEncoded returns whether the bytes are an encoded sketch.
+func IsEncoded(b []byte) bool {
+	return len(b) > len(encodingMagic) && bytes.HasPrefix(b, encodingMagic)
+}
+
+// Encode encodes the sketch.
+//
+// The layout of an encoded sketch is:
+//
+//	magic (4 bytes) | version (1 byte) | schema (varint) | count (uvarint) |
+//	zero count (uvarint) | sum (8 bytes) | min (8 bytes) | max (8 bytes) |
+//	positive buckets | negative buckets
+//
+// where buckets are encoded as the index of the first bucket (varint), the
+// number of buckets (uvarint) and the count of each bucket (uvarint).
+func (s *Sketch) Encode() []byte {
+	enc := encoder{
+		buf: make([]byte, 0,
+			48+2*(len(s.positive.counts)+len(s.negative.counts))),
+	}
+	enc.buf = append(enc.buf, encodingMagic...)
+	enc.buf = append(enc.buf, encodingVersion)
+	enc.putVarint(int64(s.schema))
+	enc.putUvarint(s.count)
+	enc.putUvarint(s.zeroCount)
+	enc.putFloat(s.sum)
+	enc.putFloat(s.min)
+	enc.putFloat(s.max)
+	enc.putStore(s.positive)
+	enc.putStore(s.negative)
+	return enc.buf
+}
+
+// Decode resets the sketch and decodes an encoded sketch into it.
+func (s *Sketch) Decode(b []byte) error {
+	s.Reset()
+	if !IsEncoded(b) {
+		return errEncodedNotSketch
+	}
+
+	dec := decoder{buf: b[len(encodingMagic):]}
+	if version := dec.byte(); dec.err == nil && version != encodingVersion {
+		return fmt.Errorf("encoded sketch has unknown version %d", version)
+	}
+	schema := dec.varint()
+	s.count = dec.uvarint()
+	s.zeroCount = dec.uvarint()
+	s.sum = dec.float()
+	s.min = dec.float()
+	s.max = dec.float()
+	dec.store(&s.positive)
+	dec.store(&s.negative)
+	if dec.err != nil {
+		s.Reset()
+		return dec.err
+	}
+	if len(dec.buf) != 0 {
+		s.Reset()
+		return errEncodedTrailing
+	}
+	if schema < MinSchema || schema > MaxSchema {
+		s.Reset()
+		return fmt.Errorf("encoded sketch schema %d is not in range [%d, %d]",
+			schema, MinSchema, MaxSchema)
+	}
+	s.schema = int32(schema)
+
+	total := s.zeroCount
+	s.positive.forEach(func(_ int32, count uint64) { total += count })
+	s.negative.forEach(func(_ int32, count uint64) { total += count })
+	if total != s.count {
+		s.Reset()
+		return errEncodedCountInvalid
+	}
+
+	s.maybeDownscale()
+	return nil
+}
+
+// MergeEncoded merges the values of an encoded sketch into the sketch.
+func (s *Sketch) MergeEncoded(encoded []byte) error {
+	other := NewSketch(s.opts)
+	if err := other.Decode(encoded); err != nil {
+		return err
+	}
+	s.Merge(other)
+	return nil
+}
+
+type encoder struct {
+	buf     []byte
+	scratch [binary.MaxVarintLen64]byte
+}
+
+func (e *encoder) putVarint(v int64) {
+	n := binary.PutVarint(e.scratch[:], v)
+	e.buf = append(e.buf, e.scratch[:n]...)
+}
+
+func (e *encoder) putUvarint(v uint64) {
+	n := binary.PutUvarint(e.scratch[:], v)
+	e.buf = append(e.buf, e.scratch[:n]...)
+}
+
+func (e *encoder) putFloat(v float64) {
+	binary.LittleEndian.PutUint64(e.scratch[:8], math.Float64bits(v))
+	e.buf = append(e.buf, e.scratch[:8]...)
+}
+
+func (e *encoder) putStore(st store) {
+	e.putVarint(int64(st.offset))
+	e.putUvarint(uint64(len(st.counts)))
+	for _, count := range st.counts {
+		e.putUvarint(count)
+	}
+}
+
+type decoder struct {
+	buf []byte
+	err error
+}
+
+func (d *decoder) byte() byte {
+	if d.err != nil {
+		return 0
+	}
+	if len(d.buf) == 0 {
+		d.err = errEncodedTooShort
+		return 0
+	}
+	v := d.buf[0]
+	d.buf = d.buf[1:]
+	return v
+}
+
+func (d *decoder) varint() int64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(d.buf)
+	if n <= 0 {
+		d.err = errEncodedTooShort
+		return 0
+	}
+	d.buf = d.buf[n:]
+	return v
+}
+
+func (d *decoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(d.buf)
+	if n <= 0 {
+		d.err = errEncodedTooShort
+		return 0
+	}
+	d.buf = d.buf[n:]
+	return v
+}
+
+func (d *decoder) float() float64 {
+	if d.err != nil {
+		return 0
+	}
+	if len(d.buf) < 8 {
+		d.err = errEncodedTooShort
+		return 0
+	}
+	v := math.Float64frombits(binary.LittleEndian.Uint64(d.buf))
+	d.buf = d.buf[8:]
+	return v
+}
+
+func (d *decoder) store(st *store) {
+	offset := d.varint()
+	n := d.uvarint()
+	if d.err != nil {
+		return
+	}
+	// NB: each count takes at least a byte, which bounds the number of
+	// buckets allocated for a corrupt encoding.
+	if n > uint64(len(d.buf)) {
+		d.err = errEncodedTooShort
+		return
+	}
+	if offset < math.MinInt32 || offset+int64(n) > math.MaxInt32 {
+		d.err = fmt.Errorf("encoded sketch bucket index %d is out of range", offset)
+		return
+	}
+	for i := uint64(0); i < n; i++ {
+		st.counts = append(st.counts, d.uvarint())
+	}
+	st.offset = int32(offset)
+}
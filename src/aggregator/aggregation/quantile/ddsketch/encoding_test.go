@@ -0,0 +1,86 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ddsketch
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSketchEncodeDecode(t *testing.T) {
+	opts := NewOptions()
+	s := NewSketch(opts)
+	s.AddBatch(testSamples(1000, func(r *rand.Rand) float64 {
+		return r.NormFloat64() * 10
+	}))
+	s.Add(0)
+
+	encoded := s.Encode()
+	require.True(t, IsEncoded(encoded))
+
+	decoded := NewSketch(opts)
+	require.NoError(t, decoded.Decode(encoded))
+	require.Equal(t, s.Schema(), decoded.Schema())
+	require.Equal(t, s.Count(), decoded.Count())
+	require.Equal(t, s.ZeroCount(), decoded.ZeroCount())
+	require.Equal(t, s.Sum(), decoded.Sum())
+	require.Equal(t, s.Min(), decoded.Min())
+	require.Equal(t, s.Max(), decoded.Max())
+	for _, q := range testQuantiles {
+		require.Equal(t, s.Quantile(q), decoded.Quantile(q))
+	}
+	require.Equal(t, encoded, decoded.Encode())
+}
+
+func TestSketchDecodeDownscalesBeyondMaxNumBuckets(t *testing.T) {
+	s := NewSketch(NewOptions())
+	s.AddBatch([]float64{1, 10, 100, 1000})
+
+	decoded := NewSketch(NewOptions().SetMaxNumBuckets(4))
+	require.NoError(t, decoded.Decode(s.Encode()))
+	require.True(t, decoded.Schema() < s.Schema())
+	require.Equal(t, s.Count(), decoded.Count())
+}
+
+func TestSketchDecodeErrors(t *testing.T) {
+	s := NewSketch(NewOptions())
+	s.AddBatch([]float64{1, 2, 3})
+	encoded := s.Encode()
+
+	decoded := NewSketch(NewOptions())
+	require.Error(t, decoded.Decode([]byte("not a sketch")))
+	require.Error(t, decoded.Decode(encoded[:len(encoded)-1]))
+	require.Error(t, decoded.Decode(append(encoded, 0)))
+
+	corrupt := append([]byte(nil), encoded...)
+	corrupt[len(encodingMagic)] = encodingVersion + 1
+	require.Error(t, decoded.Decode(corrupt))
+	require.Equal(t, uint64(0), decoded.Count())
+}
+
+func TestIsEncoded(t *testing.T) {
+	require.False(t, IsEncoded(nil))
+	require.False(t, IsEncoded(encodingMagic))
+	// Protobuf messages never start with a zero byte.
+	require.False(t, IsEncoded([]byte{0x08, 0x01}))
+}
@@ -0,0 +1,115 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ddsketch
+
+import (
+	"fmt"
+)
+
+const (
+	// MinSchema is the lowest supported schema, the boundaries of consecutive
+	// buckets grow by a factor of 2^16.
+	MinSchema = -4
+	// MaxSchema is the highest supported schema, the boundaries of
+	// consecutive buckets grow by a factor of 2^(2^-8).
+	MaxSchema = 8
+
+	defaultRelativeAccuracy = 0.01
+	defaultMaxNumBuckets    = 2048
+)
+
+var (
+	// minRelativeAccuracy is the relative accuracy of the highest schema.
+	minRelativeAccuracy = relativeAccuracy(MaxSchema)
+
+	errInvalidRelativeAccuracy = fmt.Errorf(
+		"relative accuracy must be between %f and 1", minRelativeAccuracy)
+	errInvalidMaxNumBuckets = fmt.Errorf("max number of buckets must be positive")
+)
+
+type options struct {
+	relativeAccuracy float64
+	maxNumBuckets    int
+	schema           int32
+}
+
+// NewOptions creates a new options.
+func NewOptions() Options {
+	o := &options{
+		relativeAccuracy: defaultRelativeAccuracy,
+		maxNumBuckets:    defaultMaxNumBuckets,
+	}
+	o.schema = schemaForRelativeAccuracy(o.relativeAccuracy)
+	return o
+}
+
+func (o *options) SetRelativeAccuracy(value float64) Options {
+	o.relativeAccuracy = value
+	o.schema = schemaForRelativeAccuracy(value)
+	return o
+}
+
+func (o *options) RelativeAccuracy() float64 {
+	return o.relativeAccuracy
+}
+
+func (o *options) SetMaxNumBuckets(value int) Options {
+	o.maxNumBuckets = value
+	return o
+}
+
+func (o *options) MaxNumBuckets() int {
+	return o.maxNumBuckets
+}
+
+func (o *options) Schema() int32 {
+	return o.schema
+}
+
+func (o *options) Validate() error {
+	if !(o.relativeAccuracy >= minRelativeAccuracy && o.relativeAccuracy < 1) {
+		return errInvalidRelativeAccuracy
+	}
+	if o.maxNumBuckets <= 0 {
+		return errInvalidMaxNumBuckets
+	}
+	return nil
+}
+
+// schemaForRelativeAccuracy returns the lowest schema whose buckets meet the
+// relative accuracy, or the highest schema if none do.
+func schemaForRelativeAccuracy(accuracy float64) int32 {
+	for schema := int32(MinSchema); schema < MaxSchema; schema++ {
+		if relativeAccuracy(schema) <= accuracy {
+			return schema
+		}
+	}
+	return MaxSchema
+}
+
+// relativeAccuracy returns the relative accuracy of the buckets of a schema,
+// a value is estimated by the point of its bucket that is equally distant
+// relative to both boundaries, which for a base of gamma is (gamma-1)/(gamma+1)
+// distant relative to either boundary.
+func relativeAccuracy(schema int32) float64 {
+	gamma := upperBound(1, schema)
+	return (gamma - 1) / (gamma + 1)
+}
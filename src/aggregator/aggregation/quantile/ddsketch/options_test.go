@@ -0,0 +1,44 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ddsketch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptionsSchemaForRelativeAccuracy(t *testing.T) {
+	opts := NewOptions()
+	require.Equal(t, defaultRelativeAccuracy, opts.RelativeAccuracy())
+	require.Equal(t, int32(6), opts.Schema())
+	require.True(t, relativeAccuracy(opts.Schema()) <= opts.RelativeAccuracy())
+	require.True(t, relativeAccuracy(opts.Schema()-1) > opts.RelativeAccuracy())
+
+	require.Equal(t, int32(MinSchema), opts.SetRelativeAccuracy(0.99999).Schema())
+}
+
+func TestOptionsValidate(t *testing.T) {
+	require.NoError(t, NewOptions().Validate())
+	require.Error(t, NewOptions().SetRelativeAccuracy(0).Validate())
+	require.Error(t, NewOptions().SetRelativeAccuracy(1).Validate())
+	require.Error(t, NewOptions().SetMaxNumBuckets(0).Validate())
+}
@@ -0,0 +1,320 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ddsketch
+
+import (
+	"math"
+)
+
+// Sketch is a mergeable sketch for computing quantiles with a relative error
+// guarantee. Values are counted in the bucket whose boundaries contain them,
+// the positive bucket with index i counts values in (2^((i-1)*2^-s), 2^(i*2^-s)]
+// and the negative bucket with index i counts values in
+// [-2^(i*2^-s), -2^((i-1)*2^-s)) for a schema s. Sketch APIs are not
+// thread-safe.
+type Sketch struct {
+	opts      Options
+	schema    int32
+	positive  store
+	negative  store
+	zeroCount uint64
+	count     uint64
+	sum       float64
+	min       float64
+	max       float64
+}
+
+// NewSketch creates a new sketch.
+func NewSketch(opts Options) *Sketch {
+	s := &Sketch{opts: opts}
+	s.Reset()
+	return s
+}
+
+// Reset resets the sketch.
+func (s *Sketch) Reset() {
+	s.schema = s.opts.Schema()
+	s.positive.reset()
+	s.negative.reset()
+	s.zeroCount = 0
+	s.count = 0
+	s.sum = 0
+	s.min = 0
+	s.max = 0
+}
+
+// Add adds a value, values that are NaN or infinite are ignored since they
+// do not belong to any bucket.
+func (s *Sketch) Add(value float64) {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return
+	}
+
+	switch {
+	case value > 0:
+		s.positive.add(bucketIndex(value, s.schema), 1)
+	case value < 0:
+		s.negative.add(bucketIndex(-value, s.schema), 1)
+	default:
+		s.zeroCount++
+	}
+	if s.count == 0 || value < s.min {
+		s.min = value
+	}
+	if s.count == 0 || value > s.max {
+		s.max = value
+	}
+	s.count++
+	s.sum += value
+	s.maybeDownscale()
+}
+
+// AddBatch adds a batch of values.
+func (s *Sketch) AddBatch(values []float64) {
+	for _, v := range values {
+		s.Add(v)
+	}
+}
+
+// Merge merges the values of another sketch into the sketch, if the schemas
+// of the sketches differ the merged sketch has the lower schema of the two.
+func (s *Sketch) Merge(other *Sketch) {
+	if other.count == 0 {
+		return
+	}
+	if other.schema < s.schema {
+		s.downscale(uint(s.schema - other.schema))
+	}
+
+	by := uint(other.schema - s.schema)
+	other.positive.forEach(func(idx int32, count uint64) {
+		s.positive.add(downscaledIndex(idx, by), count)
+	})
+	other.negative.forEach(func(idx int32, count uint64) {
+		s.negative.add(downscaledIndex(idx, by), count)
+	})
+	if s.count == 0 || other.min < s.min {
+		s.min = other.min
+	}
+	if s.count == 0 || other.max > s.max {
+		s.max = other.max
+	}
+	s.zeroCount += other.zeroCount
+	s.count += other.count
+	s.sum += other.sum
+	s.maybeDownscale()
+}
+
+// Quantile returns the value at a given quantile, which is within the
+// relative accuracy of the schema of the sketch to the exact value.
+func (s *Sketch) Quantile(q float64) float64 {
+	if q < 0.0 || q > 1.0 {
+		return math.NaN()
+	}
+	if s.count == 0 {
+		return 0.0
+	}
+	if q == 0.0 {
+		return s.min
+	}
+	if q == 1.0 {
+		return s.max
+	}
+
+	var (
+		rank       = q * float64(s.count-1)
+		cumulative float64
+	)
+	// Negative buckets with a higher index hold lower values.
+	for i := len(s.negative.counts) - 1; i >= 0; i-- {
+		cumulative += float64(s.negative.counts[i])
+		if cumulative > rank {
+			return s.clamp(-s.bucketValue(s.negative.offset + int32(i)))
+		}
+	}
+	cumulative += float64(s.zeroCount)
+	if cumulative > rank {
+		return s.clamp(0)
+	}
+	for i, count := range s.positive.counts {
+		cumulative += float64(count)
+		if cumulative > rank {
+			return s.clamp(s.bucketValue(s.positive.offset + int32(i)))
+		}
+	}
+	return s.max
+}
+
+// Schema returns the schema of the sketch.
+func (s *Sketch) Schema() int32 { return s.schema }
+
+// Count returns the number of values added.
+func (s *Sketch) Count() uint64 { return s.count }
+
+// ZeroCount returns the number of values added that are zero.
+func (s *Sketch) ZeroCount() uint64 { return s.zeroCount }
+
+// Sum returns the sum of the values added.
+func (s *Sketch) Sum() float64 { return s.sum }
+
+// Min returns the minimum value added.
+func (s *Sketch) Min() float64 { return s.min }
+
+// Max returns the maximum value added.
+func (s *Sketch) Max() float64 { return s.max }
+
+// ForEachPositiveBucket calls the function with the index and the count of
+// each populated positive bucket in increasing order of index.
+func (s *Sketch) ForEachPositiveBucket(fn func(idx int32, count uint64)) {
+	s.positive.forEach(fn)
+}
+
+// ForEachNegativeBucket calls the function with the index and the count of
+// each populated negative bucket in increasing order of index.
+func (s *Sketch) ForEachNegativeBucket(fn func(idx int32, count uint64)) {
+	s.negative.forEach(fn)
+}
+
+// bucketValue returns the estimate of the values of the positive bucket at
+// the index, which is equally distant relative to both of its boundaries.
+func (s *Sketch) bucketValue(idx int32) float64 {
+	gamma := upperBound(1, s.schema)
+	return upperBound(idx, s.schema) * 2 / (1 + gamma)
+}
+
+// clamp clamps an estimated value to the exact range of the values added.
+func (s *Sketch) clamp(value float64) float64 {
+	return math.Max(s.min, math.Min(s.max, value))
+}
+
+func (s *Sketch) maybeDownscale() {
+	maxNumBuckets := s.opts.MaxNumBuckets()
+	for s.schema > MinSchema &&
+		len(s.positive.counts)+len(s.negative.counts) > maxNumBuckets {
+		s.downscale(1)
+	}
+}
+
+// downscale lowers the schema of the sketch, merging each 2^by consecutive
+// buckets into a single bucket.
+func (s *Sketch) downscale(by uint) {
+	if int32(by) > s.schema-MinSchema {
+		by = uint(s.schema - MinSchema)
+	}
+	if by == 0 {
+		return
+	}
+	s.positive.downscale(by)
+	s.negative.downscale(by)
+	s.schema -= int32(by)
+}
+
+// store holds the counts of consecutive buckets, counts[i] is the count of
+// the bucket with index offset+i.
+type store struct {
+	counts []uint64
+	offset int32
+}
+
+func (st *store) reset() {
+	st.counts = st.counts[:0]
+	st.offset = 0
+}
+
+func (st *store) add(idx int32, count uint64) {
+	if len(st.counts) == 0 {
+		st.counts = append(st.counts[:0], count)
+		st.offset = idx
+		return
+	}
+
+	if idx < st.offset {
+		var (
+			grow = int(st.offset - idx)
+			size = len(st.counts) + grow
+		)
+		if cap(st.counts) >= size {
+			st.counts = st.counts[:size]
+			copy(st.counts[grow:], st.counts[:size-grow])
+			for i := 0; i < grow; i++ {
+				st.counts[i] = 0
+			}
+		} else {
+			counts := make([]uint64, size, 2*size)
+			copy(counts[grow:], st.counts)
+			st.counts = counts
+		}
+		st.offset = idx
+	}
+	if i := int(idx - st.offset); i >= len(st.counts) {
+		st.counts = append(st.counts, make([]uint64, i-len(st.counts)+1)...)
+	}
+	st.counts[idx-st.offset] += count
+}
+
+func (st *store) forEach(fn func(idx int32, count uint64)) {
+	for i, count := range st.counts {
+		if count != 0 {
+			fn(st.offset+int32(i), count)
+		}
+	}
+}
+
+// downscale merges each 2^by consecutive buckets into a single bucket. The
+// buckets are merged in place since the merged index of a bucket is never
+// higher than its position.
+func (st *store) downscale(by uint) {
+	if len(st.counts) == 0 {
+		return
+	}
+
+	offset := downscaledIndex(st.offset, by)
+	for i, count := range st.counts {
+		j := int(downscaledIndex(st.offset+int32(i), by) - offset)
+		if j != i {
+			st.counts[j] += count
+			st.counts[i] = 0
+		}
+	}
+	last := downscaledIndex(st.offset+int32(len(st.counts)-1), by)
+	st.counts = st.counts[:last-offset+1]
+	st.offset = offset
+}
+
+// bucketIndex returns the index of the positive bucket containing the value.
+func bucketIndex(value float64, schema int32) int32 {
+	return int32(math.Ceil(math.Log2(value) * math.Ldexp(1, int(schema))))
+}
+
+// downscaledIndex returns the index of the bucket containing the bucket at
+// the index once the schema is lowered by the given amount, the bucket with
+// index i is contained by the bucket with index ceil(i/2^by).
+func downscaledIndex(idx int32, by uint) int32 {
+	return (idx + int32(1)<<by - 1) >> by
+}
+
+// upperBound returns the upper boundary of the positive bucket at the index.
+func upperBound(idx int32, schema int32) float64 {
+	if schema < 0 {
+		return math.Ldexp(1, int(idx)<<uint(-schema))
+	}
+	return math.Exp2(float64(idx) / float64(int32(1)<<uint(schema)))
+}
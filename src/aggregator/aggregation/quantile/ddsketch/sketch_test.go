@@ -0,0 +1,203 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ddsketch
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var testQuantiles = []float64{0.1, 0.5, 0.9, 0.95, 0.99, 0.999}
+
+func testSamples(num int, generator func(*rand.Rand) float64) []float64 {
+	var (
+		samples = make([]float64, num)
+		rnd     = rand.New(rand.NewSource(0)) //nolint:gosec
+	)
+	for i := range samples {
+		samples[i] = generator(rnd)
+	}
+	return samples
+}
+
+func requireQuantilesWithinAccuracy(
+	t *testing.T,
+	s *Sketch,
+	samples []float64,
+	accuracy float64,
+) {
+	sorted := append([]float64(nil), samples...)
+	sort.Float64s(sorted)
+	for _, q := range testQuantiles {
+		var (
+			expected = sorted[int(q*float64(len(sorted)-1))]
+			actual   = s.Quantile(q)
+		)
+		require.InDelta(t, expected, actual, accuracy*math.Abs(expected),
+			"quantile %v", q)
+	}
+}
+
+func TestSketchEmpty(t *testing.T) {
+	s := NewSketch(NewOptions())
+	require.Equal(t, uint64(0), s.Count())
+	require.Equal(t, 0.0, s.Min())
+	require.Equal(t, 0.0, s.Max())
+	require.Equal(t, 0.0, s.Quantile(0.5))
+	require.True(t, math.IsNaN(s.Quantile(1.5)))
+}
+
+func TestSketchQuantiles(t *testing.T) {
+	opts := NewOptions()
+	samples := testSamples(100000, func(r *rand.Rand) float64 {
+		return math.Exp(r.NormFloat64() * 2)
+	})
+
+	s := NewSketch(opts)
+	s.AddBatch(samples)
+
+	require.Equal(t, uint64(len(samples)), s.Count())
+	sorted := append([]float64(nil), samples...)
+	sort.Float64s(sorted)
+	require.Equal(t, sorted[0], s.Min())
+	require.Equal(t, sorted[len(sorted)-1], s.Max())
+	require.Equal(t, sorted[0], s.Quantile(0))
+	require.Equal(t, sorted[len(sorted)-1], s.Quantile(1))
+	requireQuantilesWithinAccuracy(t, s, samples, opts.RelativeAccuracy())
+}
+
+func TestSketchNegativeAndZeroValues(t *testing.T) {
+	opts := NewOptions()
+	samples := testSamples(10000, func(r *rand.Rand) float64 {
+		if r.Intn(10) == 0 {
+			return 0
+		}
+		return r.NormFloat64() * 100
+	})
+
+	s := NewSketch(opts)
+	s.AddBatch(samples)
+	s.Add(math.NaN())
+	s.Add(math.Inf(1))
+
+	require.Equal(t, uint64(len(samples)), s.Count())
+	require.True(t, s.ZeroCount() > 0)
+	requireQuantilesWithinAccuracy(t, s, samples, opts.RelativeAccuracy())
+}
+
+func TestSketchMerge(t *testing.T) {
+	var (
+		opts    = NewOptions()
+		samples = testSamples(10000, func(r *rand.Rand) float64 {
+			return r.Float64() * 1000
+		})
+		merged = NewSketch(opts)
+		all    = NewSketch(opts)
+	)
+	for i := 0; i < 4; i++ {
+		part := NewSketch(opts)
+		part.AddBatch(samples[i*2500 : (i+1)*2500])
+		merged.Merge(part)
+	}
+	all.AddBatch(samples)
+
+	require.Equal(t, all.Count(), merged.Count())
+	require.Equal(t, all.Min(), merged.Min())
+	require.Equal(t, all.Max(), merged.Max())
+	require.InDelta(t, all.Sum(), merged.Sum(), 1e-6)
+	for _, q := range testQuantiles {
+		require.Equal(t, all.Quantile(q), merged.Quantile(q))
+	}
+}
+
+func TestSketchMergeDifferentSchemas(t *testing.T) {
+	var (
+		fineOpts   = NewOptions().SetRelativeAccuracy(0.001)
+		coarseOpts = NewOptions().SetRelativeAccuracy(0.05)
+		samples    = testSamples(10000, func(r *rand.Rand) float64 {
+			return r.Float64() * 1000
+		})
+		fine   = NewSketch(fineOpts)
+		coarse = NewSketch(coarseOpts)
+	)
+	require.True(t, fineOpts.Schema() > coarseOpts.Schema())
+
+	fine.AddBatch(samples[:5000])
+	coarse.AddBatch(samples[5000:])
+	fine.Merge(coarse)
+
+	require.Equal(t, coarseOpts.Schema(), fine.Schema())
+	require.Equal(t, uint64(len(samples)), fine.Count())
+	requireQuantilesWithinAccuracy(t, fine, samples, relativeAccuracy(fine.Schema()))
+}
+
+func TestSketchDownscalesBeyondMaxNumBuckets(t *testing.T) {
+	opts := NewOptions().SetMaxNumBuckets(64)
+	samples := testSamples(10000, func(r *rand.Rand) float64 {
+		return math.Exp(r.Float64() * 20)
+	})
+
+	s := NewSketch(opts)
+	s.AddBatch(samples)
+
+	require.True(t, s.Schema() < opts.Schema())
+	require.True(t, len(s.positive.counts) <= 64)
+	requireQuantilesWithinAccuracy(t, s, samples, relativeAccuracy(s.Schema()))
+}
+
+func TestSketchReset(t *testing.T) {
+	s := NewSketch(NewOptions().SetMaxNumBuckets(8))
+	s.AddBatch([]float64{1, 10, 100, 1000, 10000})
+	require.True(t, s.Schema() < NewOptions().Schema())
+
+	s.Reset()
+	require.Equal(t, NewOptions().Schema(), s.Schema())
+	require.Equal(t, uint64(0), s.Count())
+	require.Equal(t, 0.0, s.Quantile(0.5))
+
+	s.Add(5)
+	require.Equal(t, 5.0, s.Quantile(0.5))
+}
+
+func TestStoreDownscale(t *testing.T) {
+	var st store
+	for idx := int32(-3); idx <= 4; idx++ {
+		st.add(idx, uint64(idx+4))
+	}
+
+	st.downscale(1)
+
+	var (
+		indexes []int32
+		counts  []uint64
+	)
+	st.forEach(func(idx int32, count uint64) {
+		indexes = append(indexes, idx)
+		counts = append(counts, count)
+	})
+	// Buckets -3 and -2 merge into -1, -1 and 0 into 0 and so on.
+	require.Equal(t, []int32{-1, 0, 1, 2}, indexes)
+	require.Equal(t, []uint64{1 + 2, 3 + 4, 5 + 6, 7 + 8}, counts)
+}
@@ -0,0 +1,45 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ddsketch
+
+// Options represent various options for computing quantiles with sketches.
+type Options interface {
+	// SetRelativeAccuracy sets the desired relative accuracy of quantiles.
+	SetRelativeAccuracy(value float64) Options
+
+	// RelativeAccuracy returns the desired relative accuracy of quantiles.
+	RelativeAccuracy() float64
+
+	// SetMaxNumBuckets sets the maximum number of buckets of a sketch, beyond
+	// which the accuracy of the sketch is lowered.
+	SetMaxNumBuckets(value int) Options
+
+	// MaxNumBuckets returns the maximum number of buckets of a sketch, beyond
+	// which the accuracy of the sketch is lowered.
+	MaxNumBuckets() int
+
+	// Schema returns the schema of new sketches, which is the lowest schema
+	// whose buckets meet the desired relative accuracy.
+	Schema() int32
+
+	// Validate validates the options.
+	Validate() error
+}
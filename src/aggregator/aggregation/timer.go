@@ -24,18 +24,21 @@ import (
 	"time"
 
 	"github.com/m3db/m3/src/aggregator/aggregation/quantile/cm"
+	"github.com/m3db/m3/src/aggregator/aggregation/quantile/ddsketch"
 	"github.com/m3db/m3/src/metrics/aggregation"
 )
 
 // Timer aggregates timer values. Timer APIs are not thread-safe.
 type Timer struct {
 	lastAt                   time.Time
-	stream                   *cm.Stream // Stream of values received.
+	stream                   *cm.Stream       // Stream of values received.
+	sketch                   *ddsketch.Sketch // Sketch of values received, replaces the stream.
 	annotation               []byte
 	count                    int64   // Number of values received.
 	sum                      float64 // Sum of the values.
 	sumSq                    float64 // Sum of squared values.
 	hasExpensiveAggregations bool
+	metrics                  TimerMetrics
 }
 
 // NewTimer creates a new timer
@@ -45,14 +48,53 @@ func NewTimer(quantiles []float64, streamOpts cm.Options, opts Options) Timer {
 	return Timer{
 		hasExpensiveAggregations: opts.HasExpensiveAggregations,
 		stream:                   stream,
+		metrics:                  opts.Metrics.Timer,
 	}
 }
 
-// Add adds a timer value.
+// NewSketchTimer creates a new timer aggregating values into a sketch, from
+// which any quantile can be computed and which can be merged with the
+// sketches of other timers.
+func NewSketchTimer(sketchOpts ddsketch.Options, opts Options) Timer {
+	return Timer{
+		hasExpensiveAggregations: opts.HasExpensiveAggregations,
+		sketch:                   ddsketch.NewSketch(sketchOpts),
+		metrics:                  opts.Metrics.Timer,
+	}
+}
+
+// Add adds a timer value. If the annotation is an encoded sketch, as
+// forwarded by timers aggregating with sketches, the sketch is merged into
+// the timer instead of adding the value, or dropped if the timer does not
+// aggregate with a sketch.
 func (t *Timer) Add(timestamp time.Time, value float64, annotation []byte) {
+	if ddsketch.IsEncoded(annotation) {
+		t.addSketch(timestamp, annotation)
+		return
+	}
 	t.AddBatch(timestamp, []float64{value}, annotation)
 }
 
+func (t *Timer) addSketch(timestamp time.Time, encoded []byte) {
+	if t.sketch == nil {
+		// NB: a stream can not merge a sketch and the forwarded value is the
+		// count of the sketch rather than a sample, so it is dropped.
+		t.metrics.IncInvalidForwards()
+		return
+	}
+
+	prevCount, prevSum := t.sketch.Count(), t.sketch.Sum()
+	if err := t.sketch.MergeEncoded(encoded); err != nil {
+		t.metrics.IncInvalidSketches()
+		return
+	}
+	t.recordLastAt(timestamp)
+	// NB: the squares of the values are not part of a sketch so the sum of
+	// squared values only accounts for the values added to this timer.
+	t.count += int64(t.sketch.Count() - prevCount)
+	t.sum += t.sketch.Sum() - prevSum
+}
+
 // AddBatch adds a batch of timer values.
 func (t *Timer) AddBatch(timestamp time.Time, values []float64, annotation []byte) {
 	// Record last at just once.
@@ -70,7 +112,11 @@ func (t *Timer) AddBatch(timestamp time.Time, values []float64, annotation []byt
 		}
 	}
 
-	t.stream.AddBatch(values)
+	if t.sketch != nil {
+		t.sketch.AddBatch(values)
+	} else {
+		t.stream.AddBatch(values)
+	}
 
 	t.annotation = maybeReplaceAnnotation(t.annotation, annotation)
 }
@@ -89,6 +135,9 @@ func (t *Timer) LastAt() time.Time { return t.lastAt }
 
 // Quantile returns the value at a given quantile.
 func (t *Timer) Quantile(q float64) float64 {
+	if t.sketch != nil {
+		return t.sketch.Quantile(q)
+	}
 	t.stream.Flush()
 	return t.stream.Quantile(q)
 }
@@ -98,12 +147,18 @@ func (t *Timer) Count() int64 { return t.count }
 
 // Min returns the minimum timer value.
 func (t *Timer) Min() float64 {
+	if t.sketch != nil {
+		return t.sketch.Min()
+	}
 	t.stream.Flush()
 	return t.stream.Min()
 }
 
 // Max returns the maximum timer value.
 func (t *Timer) Max() float64 {
+	if t.sketch != nil {
+		return t.sketch.Max()
+	}
 	t.stream.Flush()
 	return t.stream.Max()
 }
@@ -148,6 +203,8 @@ func (t *Timer) ValueOf(aggType aggregation.Type) float64 {
 		return t.SumSq()
 	case aggregation.Stdev:
 		return t.Stdev()
+	case aggregation.Sketch:
+		return float64(t.Count())
 	}
 	return 0
 }
@@ -157,5 +214,20 @@ func (t *Timer) Annotation() []byte {
 	return t.annotation
 }
 
+// AnnotationOf returns the annotation for the aggregation type, which is the
+// encoded sketch of the timer for the sketch aggregation type.
+func (t *Timer) AnnotationOf(aggType aggregation.Type) []byte {
+	if aggType == aggregation.Sketch && t.sketch != nil {
+		return t.sketch.Encode()
+	}
+	return t.annotation
+}
+
 // Close closes the timer.
-func (t *Timer) Close() { t.stream.Close() }
+func (t *Timer) Close() {
+	if t.sketch != nil {
+		t.sketch.Reset()
+		return
+	}
+	t.stream.Close()
+}
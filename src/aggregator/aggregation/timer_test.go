@@ -28,11 +28,13 @@ import (
 	"time"
 
 	"github.com/m3db/m3/src/aggregator/aggregation/quantile/cm"
+	"github.com/m3db/m3/src/aggregator/aggregation/quantile/ddsketch"
 	"github.com/m3db/m3/src/metrics/aggregation"
 	"github.com/m3db/m3/src/x/instrument"
 	"github.com/m3db/m3/src/x/pool"
 
 	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
 )
 
 var (
@@ -220,3 +222,107 @@ func TestTimerReturnsLastNonEmptyAnnotation(t *testing.T) {
 
 	require.Equal(t, []byte("second"), timer.Annotation())
 }
+
+func TestSketchTimerAggregations(t *testing.T) {
+	opts := NewOptions(instrument.NewOptions())
+	opts.ResetSetData(append(aggregation.Types{aggregation.Sketch}, testAggTypes...))
+	require.True(t, opts.HasSketch)
+
+	sketchOpts := ddsketch.NewOptions()
+	timer := NewSketchTimer(sketchOpts, opts)
+	require.Nil(t, timer.stream)
+
+	// Add values.
+	at := time.Now()
+	for i := 1; i <= 100; i++ {
+		timer.Add(at, float64(i), nil)
+	}
+
+	require.Equal(t, int64(100), timer.Count())
+	require.Equal(t, 5050.0, timer.Sum())
+	require.Equal(t, 338350.0, timer.SumSq())
+	require.Equal(t, 1.0, timer.Min())
+	require.Equal(t, 100.0, timer.Max())
+	require.Equal(t, 100.0, timer.ValueOf(aggregation.Sketch))
+
+	// Any quantile is within the relative accuracy of the sketch.
+	for _, q := range []float64{0.1, 0.25, 0.5, 0.75, 0.9, 0.95, 0.99, 0.999} {
+		expected := float64(int(99*q)) + 1
+		require.InEpsilon(t, expected, timer.Quantile(q), sketchOpts.RelativeAccuracy(), "q=%v", q)
+	}
+
+	// The annotation of the sketch aggregation is the encoded sketch.
+	annotation := timer.AnnotationOf(aggregation.Sketch)
+	require.True(t, ddsketch.IsEncoded(annotation))
+	decoded := ddsketch.NewSketch(sketchOpts)
+	require.NoError(t, decoded.Decode(annotation))
+	require.Equal(t, uint64(100), decoded.Count())
+	require.Nil(t, timer.AnnotationOf(aggregation.Count))
+
+	timer.Close()
+	require.Equal(t, uint64(0), timer.sketch.Count())
+}
+
+func TestSketchTimerMergesSketches(t *testing.T) {
+	opts := NewOptions(instrument.NewOptions())
+	opts.ResetSetData(aggregation.Types{aggregation.Sketch})
+	sketchOpts := ddsketch.NewOptions()
+
+	// Build the sketches of two source timers.
+	source1 := NewSketchTimer(sketchOpts, opts)
+	source2 := NewSketchTimer(sketchOpts, opts)
+	at := time.Now()
+	for i := 1; i <= 50; i++ {
+		source1.Add(at, float64(i), nil)
+		source2.Add(at, float64(i+50), nil)
+	}
+
+	timer := NewSketchTimer(sketchOpts, opts)
+	timer.Add(at, source1.ValueOf(aggregation.Sketch), source1.AnnotationOf(aggregation.Sketch))
+	timer.Add(at, source2.ValueOf(aggregation.Sketch), source2.AnnotationOf(aggregation.Sketch))
+
+	require.Equal(t, int64(100), timer.Count())
+	require.Equal(t, 5050.0, timer.Sum())
+	require.Equal(t, 1.0, timer.Min())
+	require.Equal(t, 100.0, timer.Max())
+	require.InEpsilon(t, 50.0, timer.Quantile(0.5), sketchOpts.RelativeAccuracy())
+	require.InEpsilon(t, 99.0, timer.Quantile(0.99), sketchOpts.RelativeAccuracy())
+	require.Nil(t, timer.Annotation())
+
+}
+
+func TestStreamTimerDropsSketches(t *testing.T) {
+	scope := tally.NewTestScope("", nil)
+	opts := NewOptions(instrument.NewOptions().SetMetricsScope(scope))
+	source := NewSketchTimer(ddsketch.NewOptions(), opts)
+	source.Add(time.Now(), 1, nil)
+
+	// A stream can not merge a sketch so the forwarded sketch is dropped.
+	timer := NewTimer(testQuantiles, testStreamOptions(), opts)
+	timer.Add(time.Now(), source.ValueOf(aggregation.Sketch), source.AnnotationOf(aggregation.Sketch))
+	require.Equal(t, int64(0), timer.Count())
+	require.Nil(t, timer.Annotation())
+
+	counters := scope.Snapshot().Counters()
+	counter, ok := counters["aggregation.timers.invalid-forwards+"]
+	require.True(t, ok)
+	require.Equal(t, int64(1), counter.Value())
+}
+
+func TestSketchTimerInvalidSketch(t *testing.T) {
+	scope := tally.NewTestScope("", nil)
+	opts := NewOptions(instrument.NewOptions().SetMetricsScope(scope))
+	opts.ResetSetData(aggregation.Types{aggregation.Sketch})
+	timer := NewSketchTimer(ddsketch.NewOptions(), opts)
+
+	source := ddsketch.NewSketch(ddsketch.NewOptions())
+	source.Add(1)
+	encoded := source.Encode()
+	timer.Add(time.Now(), 1, encoded[:len(encoded)-1])
+
+	require.Equal(t, int64(0), timer.Count())
+	counters := scope.Snapshot().Counters()
+	counter, ok := counters["aggregation.timers.invalid-sketches+"]
+	require.True(t, ok)
+	require.Equal(t, int64(1), counter.Value())
+}
@@ -25,6 +25,7 @@ import (
 	"time"
 
 	"github.com/m3db/m3/src/aggregator/aggregation"
+	maggregation "github.com/m3db/m3/src/metrics/aggregation"
 	"github.com/m3db/m3/src/metrics/metric/unaggregated"
 )
 
@@ -49,6 +50,10 @@ func (a *counterAggregation) AddUnion(t time.Time, mu unaggregated.MetricUnion)
 	a.Counter.Update(t, mu.CounterVal, mu.Annotation)
 }
 
+func (a *counterAggregation) AnnotationOf(_ maggregation.Type) []byte {
+	return a.Counter.Annotation()
+}
+
 // timerAggregation is a timer aggregation.
 type timerAggregation struct {
 	aggregation.Timer
@@ -91,3 +96,7 @@ func (a *gaugeAggregation) UpdateVal(t time.Time, value float64, prevValue float
 func (a *gaugeAggregation) AddUnion(t time.Time, mu unaggregated.MetricUnion) {
 	a.Gauge.Update(t, mu.GaugeVal, mu.Annotation)
 }
+
+func (a *gaugeAggregation) AnnotationOf(_ maggregation.Type) []byte {
+	return a.Gauge.Annotation()
+}
@@ -327,6 +327,9 @@ func (e timerElemBase) TypeStringFor(aggTypesOpts maggregation.TypesOptions, agg
 func (e timerElemBase) ElemPool(opts Options) TimerElemPool { return opts.TimerElemPool() }
 
 func (e timerElemBase) NewAggregation(opts Options, aggOpts raggregation.Options) timerAggregation {
+	if aggOpts.HasSketch {
+		return newTimerAggregation(raggregation.NewSketchTimer(opts.SketchOptions(), aggOpts))
+	}
 	newTimer := raggregation.NewTimer(e.quantiles, opts.StreamOptions(), aggOpts)
 	return newTimerAggregation(newTimer)
 }
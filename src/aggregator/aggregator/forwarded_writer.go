@@ -24,6 +24,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/m3db/m3/src/aggregator/aggregation/quantile/ddsketch"
 	"github.com/m3db/m3/src/aggregator/client"
 	"github.com/m3db/m3/src/aggregator/hash"
 	"github.com/m3db/m3/src/metrics/metadata"
@@ -162,6 +163,7 @@ type forwardedWriter struct {
 	aggregationMetrics         *forwardedAggregationMetrics
 	nowFn                      clock.NowFn
 	bufferForPastTimedMetricFn BufferForPastTimedMetricFn
+	sketchOpts                 ddsketch.Options
 }
 
 func newForwardedWriter(
@@ -178,6 +180,7 @@ func newForwardedWriter(
 		aggregationMetrics:         newForwardedAggregationMetrics(scope.SubScope("aggregations")),
 		bufferForPastTimedMetricFn: opts.BufferForPastTimedMetricFn(),
 		nowFn:                      opts.ClockOptions().NowFn(),
+		sketchOpts:                 opts.SketchOptions(),
 	}
 }
 
@@ -284,6 +287,9 @@ type forwardedAggregationBucket struct {
 	prevValues []float64
 	version    uint32
 	annotation []byte
+	// sketch merges the encoded sketches forwarded by timers aggregating with
+	// sketches, since a bucket is forwarded with a single annotation.
+	sketch *ddsketch.Sketch
 }
 
 type forwardedAggregationWithKey struct {
@@ -305,6 +311,7 @@ type forwardedAggregationWithKey struct {
 	bufferForPastTimedMetric int64
 	nowFn                    clock.NowFn
 	resendEnabled            bool
+	sketchOpts               ddsketch.Options
 }
 
 func (agg *forwardedAggregationWithKey) reset() {
@@ -322,6 +329,9 @@ func (agg *forwardedAggregationWithKey) reset() {
 		}
 		v.values = nil
 		v.prevValues = nil
+		if v.sketch != nil {
+			v.sketch.Reset()
+		}
 		agg.buckets[k] = v
 		// keep buckets around for the buffer period.
 		if agg.resendEnabled {
@@ -334,6 +344,10 @@ func (agg *forwardedAggregationWithKey) reset() {
 }
 
 func (agg *forwardedAggregationWithKey) add(timeNanos int64, value float64, prevValue float64, annotation []byte) {
+	if ddsketch.IsEncoded(annotation) {
+		agg.addSketch(timeNanos, value, prevValue, annotation)
+		return
+	}
 	if b, ok := agg.buckets[timeNanos]; ok {
 		b.values = append(b.values, value)
 		b.prevValues = append(b.prevValues, prevValue)
@@ -368,6 +382,32 @@ func (agg *forwardedAggregationWithKey) add(timeNanos int64, value float64, prev
 	agg.buckets[timeNanos] = bucket
 }
 
+// addSketch merges the encoded sketch into the sketch of the bucket. The bucket
+// holds a single value, the total count of the merged sketches, so that the
+// merged sketch is only added once by the destination.
+func (agg *forwardedAggregationWithKey) addSketch(
+	timeNanos int64,
+	value float64,
+	prevValue float64,
+	encoded []byte,
+) {
+	b, ok := agg.buckets[timeNanos]
+	if !ok || len(b.values) == 0 {
+		agg.add(timeNanos, value, prevValue, nil)
+		b = agg.buckets[timeNanos]
+	} else {
+		b.values[0] += value
+		b.prevValues[0] += prevValue
+	}
+	if b.sketch == nil {
+		b.sketch = ddsketch.NewSketch(agg.sketchOpts)
+	}
+	// NB: the value of an invalid sketch is still forwarded so that the
+	// destination is able to report the count.
+	_ = b.sketch.MergeEncoded(encoded)
+	agg.buckets[timeNanos] = b
+}
+
 type forwardedAggregationMetrics struct {
 	added                  tally.Counter
 	removed                tally.Counter
@@ -402,6 +442,7 @@ type forwardedAggregation struct {
 	onDoneFn                   onForwardedAggregationDoneFn
 	bufferForPastTimedMetricFn BufferForPastTimedMetricFn
 	nowFn                      clock.NowFn
+	sketchOpts                 ddsketch.Options
 }
 
 func (w *forwardedWriter) newForwardedAggregation(metricType metric.Type, metricID id.RawID) *forwardedAggregation {
@@ -414,6 +455,7 @@ func (w *forwardedWriter) newForwardedAggregation(metricType metric.Type, metric
 		metrics:                    w.aggregationMetrics,
 		bufferForPastTimedMetricFn: w.bufferForPastTimedMetricFn,
 		nowFn:                      w.nowFn,
+		sketchOpts:                 w.sketchOpts,
 	}
 	agg.writeFn = agg.write
 	agg.onDoneFn = agg.onDone
@@ -456,6 +498,7 @@ func (agg *forwardedAggregation) add(metric Registerable) error {
 		bufferForPastTimedMetric: int64(agg.bufferForPastTimedMetricFn(key.storagePolicy.Resolution().Window)),
 		nowFn:                    agg.nowFn,
 		resendEnabled:            metric.ResendEnabled(),
+		sketchOpts:               agg.sketchOpts,
 	}
 	agg.byKey = append(agg.byKey, aggregation)
 	agg.metrics.added.Inc(1)
@@ -515,13 +558,17 @@ func (agg *forwardedAggregation) onDone(key aggregationKey) error {
 			if len(b.values) == 0 {
 				continue
 			}
+			annotation := b.annotation
+			if b.sketch != nil && b.sketch.Count() > 0 {
+				annotation = b.sketch.Encode()
+			}
 			metric := aggregated.ForwardedMetric{
 				Type:       agg.metricType,
 				ID:         agg.metricID,
 				TimeNanos:  b.timeNanos,
 				Values:     b.values,
 				PrevValues: b.prevValues,
-				Annotation: b.annotation,
+				Annotation: annotation,
 				Version:    b.version,
 			}
 			b.version++
@@ -25,6 +25,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/m3db/m3/src/aggregator/aggregation/quantile/ddsketch"
 	"github.com/m3db/m3/src/aggregator/client"
 	"github.com/m3db/m3/src/metrics/aggregation"
 	"github.com/m3db/m3/src/metrics/metadata"
@@ -501,6 +502,69 @@ func TestForwardedWriterPrepare(t *testing.T) {
 	require.Equal(t, 0, len(agg.byKey[0].cachedValueArrays))
 }
 
+func TestForwardedWriterMergeSketches(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		c      = client.NewMockAdminClient(ctrl)
+		opts   = NewOptions(clock.NewOptions()).SetAdminClient(c)
+		w      = newForwardedWriter(0, opts)
+		mt     = metric.TimerType
+		mid    = id.RawID("foo")
+		aggKey = testForwardedWriterAggregationKey
+	)
+
+	writeFn, onDoneFn, err := w.Register(testRegisterable{
+		metricType: mt,
+		id:         mid,
+		key:        aggKey,
+	})
+	require.NoError(t, err)
+
+	sketch1 := ddsketch.NewSketch(opts.SketchOptions())
+	sketch1.AddBatch([]float64{1, 2, 3})
+	sketch2 := ddsketch.NewSketch(opts.SketchOptions())
+	sketch2.AddBatch([]float64{10, 20})
+
+	// Sketches of the same bucket are merged into a single value.
+	writeFn(aggKey, 1234, 3, 0, sketch1.Encode())
+	writeFn(aggKey, 1234, 2, 0, sketch2.Encode())
+
+	var written aggregated.ForwardedMetric
+	c.EXPECT().WriteForwarded(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(metric aggregated.ForwardedMetric, _ metadata.ForwardMetadata) error {
+			written = metric
+			return nil
+		})
+	require.NoError(t, onDoneFn(aggKey))
+
+	require.Equal(t, int64(1234), written.TimeNanos)
+	require.Equal(t, []float64{5}, written.Values)
+	require.Equal(t, []float64{0}, written.PrevValues)
+	require.True(t, ddsketch.IsEncoded(written.Annotation))
+
+	merged := ddsketch.NewSketch(opts.SketchOptions())
+	require.NoError(t, merged.Decode(written.Annotation))
+	require.Equal(t, uint64(5), merged.Count())
+	require.Equal(t, 36.0, merged.Sum())
+	require.Equal(t, 1.0, merged.Min())
+	require.Equal(t, 20.0, merged.Max())
+
+	// The merged sketch is cleared on the next flush.
+	w.Prepare()
+	writeFn(aggKey, 1244, 2, 0, sketch2.Encode())
+	c.EXPECT().WriteForwarded(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(metric aggregated.ForwardedMetric, _ metadata.ForwardMetadata) error {
+			written = metric
+			return nil
+		})
+	require.NoError(t, onDoneFn(aggKey))
+	require.Equal(t, []float64{2}, written.Values)
+	require.NoError(t, merged.Decode(written.Annotation))
+	require.Equal(t, uint64(2), merged.Count())
+}
+
 func TestForwardedWriterResend(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
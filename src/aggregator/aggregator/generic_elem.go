@@ -54,6 +54,9 @@ type typeSpecificAggregation interface {
 	// Annotation returns the last annotation of aggregated values.
 	Annotation() []byte
 
+	// AnnotationOf returns the annotation for the given aggregation type.
+	AnnotationOf(aggType maggregation.Type) []byte
+
 	// ValueOf returns the value for the given aggregation type.
 	ValueOf(aggType maggregation.Type) float64
 
@@ -597,6 +600,7 @@ func (e *GenericElem) processValueWithAggregationLock(
 		}
 		emitted = true
 
+		annotation := lockedAgg.aggregation.AnnotationOf(aggType)
 		if !e.parsedPipeline.HasRollup {
 			toFlush := make([]transformation.Datapoint, 0, 2)
 			toFlush = append(toFlush, transformation.Datapoint{
@@ -609,16 +613,16 @@ func (e *GenericElem) processValueWithAggregationLock(
 			for _, point := range toFlush {
 				switch e.idPrefixSuffixType {
 				case NoPrefixNoSuffix:
-					flushLocalFn(nil, e.id, nil, point.TimeNanos, point.Value, lockedAgg.aggregation.Annotation(), e.sp)
+					flushLocalFn(nil, e.id, nil, point.TimeNanos, point.Value, annotation, e.sp)
 				case WithPrefixWithSuffix:
 					flushLocalFn(e.FullPrefix(e.opts), e.id, e.TypeStringFor(e.aggTypesOpts, aggType),
-						point.TimeNanos, point.Value, lockedAgg.aggregation.Annotation(), e.sp)
+						point.TimeNanos, point.Value, annotation, e.sp)
 				}
 			}
 		} else {
 			forwardedAggregationKey, _ := e.ForwardedAggregationKey()
 			flushForwardedFn(e.writeForwardedMetricFn, forwardedAggregationKey,
-				int64(timeNanos), value, prevValue, lockedAgg.aggregation.Annotation())
+				int64(timeNanos), value, prevValue, annotation)
 		}
 	}
 	return emitted
@@ -25,6 +25,7 @@ import (
 	"time"
 
 	"github.com/m3db/m3/src/aggregator/aggregation/quantile/cm"
+	"github.com/m3db/m3/src/aggregator/aggregation/quantile/ddsketch"
 	"github.com/m3db/m3/src/aggregator/aggregator/handler"
 	"github.com/m3db/m3/src/aggregator/aggregator/handler/writer"
 	"github.com/m3db/m3/src/aggregator/client"
@@ -139,6 +140,12 @@ type Options interface {
 	// StreamOptions returns the stream options.
 	StreamOptions() cm.Options
 
+	// SetSketchOptions sets the sketch options of timers aggregating with sketches.
+	SetSketchOptions(value ddsketch.Options) Options
+
+	// SketchOptions returns the sketch options of timers aggregating with sketches.
+	SketchOptions() ddsketch.Options
+
 	// SetAdminClient sets the administrative client.
 	SetAdminClient(value client.AdminClient) Options
 
@@ -367,6 +374,7 @@ type options struct {
 	clockOpts                        clock.Options
 	instrumentOpts                   instrument.Options
 	streamOpts                       cm.Options
+	sketchOpts                       ddsketch.Options
 	adminClient                      client.AdminClient
 	runtimeOptsManager               runtime.OptionsManager
 	placementManager                 PlacementManager
@@ -424,6 +432,7 @@ func NewOptions(clockOpts clock.Options) Options {
 		clockOpts:                        clockOpts,
 		instrumentOpts:                   instrument.NewOptions(),
 		streamOpts:                       cm.NewOptions(),
+		sketchOpts:                       ddsketch.NewOptions(),
 		runtimeOptsManager:               runtime.NewOptionsManager(runtime.NewOptions()),
 		shardFn:                          sharding.Murmur32Hash.MustShardFn(),
 		bufferDurationBeforeShardCutover: defaultBufferDurationBeforeShardCutover,
@@ -547,6 +556,16 @@ func (o *options) StreamOptions() cm.Options {
 	return o.streamOpts
 }
 
+func (o *options) SetSketchOptions(value ddsketch.Options) Options {
+	opts := *o
+	opts.sketchOpts = value
+	return &opts
+}
+
+func (o *options) SketchOptions() ddsketch.Options {
+	return o.sketchOpts
+}
+
 func (o *options) SetAdminClient(value client.AdminClient) Options {
 	opts := *o
 	opts.adminClient = value
@@ -32,6 +32,8 @@
 		KeyValueUpdateResult
 		QueryLimits
 		QueryLimit
+		TenantQuotas
+		TenantQuota
 */
 package kvpb
 
@@ -196,11 +198,85 @@ func (m *QueryLimit) GetForceWaited() bool {
 	return false
 }
 
+type TenantQuotas struct {
+	DefaultQuota *TenantQuota   `protobuf:"bytes,1,opt,name=defaultQuota" json:"defaultQuota,omitempty"`
+	Tenants      []*TenantQuota `protobuf:"bytes,2,rep,name=tenants" json:"tenants,omitempty"`
+}
+
+func (m *TenantQuotas) Reset()                    { *m = TenantQuotas{} }
+func (m *TenantQuotas) String() string            { return proto.CompactTextString(m) }
+func (*TenantQuotas) ProtoMessage()               {}
+func (*TenantQuotas) Descriptor() ([]byte, []int) { return fileDescriptorKv, []int{4} }
+
+func (m *TenantQuotas) GetDefaultQuota() *TenantQuota {
+	if m != nil {
+		return m.DefaultQuota
+	}
+	return nil
+}
+
+func (m *TenantQuotas) GetTenants() []*TenantQuota {
+	if m != nil {
+		return m.Tenants
+	}
+	return nil
+}
+
+type TenantQuota struct {
+	Tenant                                string      `protobuf:"bytes,1,opt,name=tenant,proto3" json:"tenant,omitempty"`
+	MaxSeriesCreatedPerSecond             int64       `protobuf:"varint,2,opt,name=maxSeriesCreatedPerSecond,proto3" json:"maxSeriesCreatedPerSecond,omitempty"`
+	MaxDatapointsWrittenPerSecond         int64       `protobuf:"varint,3,opt,name=maxDatapointsWrittenPerSecond,proto3" json:"maxDatapointsWrittenPerSecond,omitempty"`
+	MaxRecentlyQueriedSeriesBlocks        *QueryLimit `protobuf:"bytes,4,opt,name=maxRecentlyQueriedSeriesBlocks" json:"maxRecentlyQueriedSeriesBlocks,omitempty"`
+	MaxRecentlyQueriedSeriesDiskBytesRead *QueryLimit `protobuf:"bytes,5,opt,name=maxRecentlyQueriedSeriesDiskBytesRead" json:"maxRecentlyQueriedSeriesDiskBytesRead,omitempty"`
+}
+
+func (m *TenantQuota) Reset()                    { *m = TenantQuota{} }
+func (m *TenantQuota) String() string            { return proto.CompactTextString(m) }
+func (*TenantQuota) ProtoMessage()               {}
+func (*TenantQuota) Descriptor() ([]byte, []int) { return fileDescriptorKv, []int{5} }
+
+func (m *TenantQuota) GetTenant() string {
+	if m != nil {
+		return m.Tenant
+	}
+	return ""
+}
+
+func (m *TenantQuota) GetMaxSeriesCreatedPerSecond() int64 {
+	if m != nil {
+		return m.MaxSeriesCreatedPerSecond
+	}
+	return 0
+}
+
+func (m *TenantQuota) GetMaxDatapointsWrittenPerSecond() int64 {
+	if m != nil {
+		return m.MaxDatapointsWrittenPerSecond
+	}
+	return 0
+}
+
+func (m *TenantQuota) GetMaxRecentlyQueriedSeriesBlocks() *QueryLimit {
+	if m != nil {
+		return m.MaxRecentlyQueriedSeriesBlocks
+	}
+	return nil
+}
+
+func (m *TenantQuota) GetMaxRecentlyQueriedSeriesDiskBytesRead() *QueryLimit {
+	if m != nil {
+		return m.MaxRecentlyQueriedSeriesDiskBytesRead
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*KeyValueUpdate)(nil), "kvpb.KeyValueUpdate")
 	proto.RegisterType((*KeyValueUpdateResult)(nil), "kvpb.KeyValueUpdateResult")
 	proto.RegisterType((*QueryLimits)(nil), "kvpb.QueryLimits")
 	proto.RegisterType((*QueryLimit)(nil), "kvpb.QueryLimit")
+	proto.RegisterType((*TenantQuotas)(nil), "kvpb.TenantQuotas")
+	proto.RegisterType((*TenantQuota)(nil), "kvpb.TenantQuota")
 }
 func (m *KeyValueUpdate) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
@@ -384,6 +460,100 @@ func (m *QueryLimit) MarshalTo(dAtA []byte) (int, error) {
 	return i, nil
 }
 
+func (m *TenantQuotas) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TenantQuotas) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.DefaultQuota != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintKv(dAtA, i, uint64(m.DefaultQuota.Size()))
+		n1, err := m.DefaultQuota.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n1
+	}
+	if len(m.Tenants) > 0 {
+		for _, msg := range m.Tenants {
+			dAtA[i] = 0x12
+			i++
+			i = encodeVarintKv(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	return i, nil
+}
+
+func (m *TenantQuota) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TenantQuota) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Tenant) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintKv(dAtA, i, uint64(len(m.Tenant)))
+		i += copy(dAtA[i:], m.Tenant)
+	}
+	if m.MaxSeriesCreatedPerSecond != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintKv(dAtA, i, uint64(m.MaxSeriesCreatedPerSecond))
+	}
+	if m.MaxDatapointsWrittenPerSecond != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintKv(dAtA, i, uint64(m.MaxDatapointsWrittenPerSecond))
+	}
+	if m.MaxRecentlyQueriedSeriesBlocks != nil {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintKv(dAtA, i, uint64(m.MaxRecentlyQueriedSeriesBlocks.Size()))
+		n1, err := m.MaxRecentlyQueriedSeriesBlocks.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n1
+	}
+	if m.MaxRecentlyQueriedSeriesDiskBytesRead != nil {
+		dAtA[i] = 0x2a
+		i++
+		i = encodeVarintKv(dAtA, i, uint64(m.MaxRecentlyQueriedSeriesDiskBytesRead.Size()))
+		n2, err := m.MaxRecentlyQueriedSeriesDiskBytesRead.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n2
+	}
+	return i, nil
+}
+
 func encodeVarintKv(dAtA []byte, offset int, v uint64) int {
 	for v >= 1<<7 {
 		dAtA[offset] = uint8(v&0x7f | 0x80)
@@ -468,6 +638,46 @@ func (m *QueryLimit) Size() (n int) {
 	return n
 }
 
+func (m *TenantQuotas) Size() (n int) {
+	var l int
+	_ = l
+	if m.DefaultQuota != nil {
+		l = m.DefaultQuota.Size()
+		n += 1 + l + sovKv(uint64(l))
+	}
+	if len(m.Tenants) > 0 {
+		for _, e := range m.Tenants {
+			l = e.Size()
+			n += 1 + l + sovKv(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *TenantQuota) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Tenant)
+	if l > 0 {
+		n += 1 + l + sovKv(uint64(l))
+	}
+	if m.MaxSeriesCreatedPerSecond != 0 {
+		n += 1 + sovKv(uint64(m.MaxSeriesCreatedPerSecond))
+	}
+	if m.MaxDatapointsWrittenPerSecond != 0 {
+		n += 1 + sovKv(uint64(m.MaxDatapointsWrittenPerSecond))
+	}
+	if m.MaxRecentlyQueriedSeriesBlocks != nil {
+		l = m.MaxRecentlyQueriedSeriesBlocks.Size()
+		n += 1 + l + sovKv(uint64(l))
+	}
+	if m.MaxRecentlyQueriedSeriesDiskBytesRead != nil {
+		l = m.MaxRecentlyQueriedSeriesDiskBytesRead.Size()
+		n += 1 + l + sovKv(uint64(l))
+	}
+	return n
+}
+
 func sovKv(x uint64) (n int) {
 	for {
 		n++
@@ -1056,6 +1266,303 @@ func (m *QueryLimit) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *TenantQuotas) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowKv
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TenantQuotas: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TenantQuotas: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DefaultQuota", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowKv
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthKv
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.DefaultQuota == nil {
+				m.DefaultQuota = &TenantQuota{}
+			}
+			if err := m.DefaultQuota.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Tenants", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowKv
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthKv
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Tenants = append(m.Tenants, &TenantQuota{})
+			if err := m.Tenants[len(m.Tenants)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipKv(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthKv
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *TenantQuota) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowKv
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TenantQuota: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TenantQuota: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Tenant", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowKv
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthKv
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Tenant = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxSeriesCreatedPerSecond", wireType)
+			}
+			m.MaxSeriesCreatedPerSecond = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowKv
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxSeriesCreatedPerSecond |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxDatapointsWrittenPerSecond", wireType)
+			}
+			m.MaxDatapointsWrittenPerSecond = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowKv
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxDatapointsWrittenPerSecond |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxRecentlyQueriedSeriesBlocks", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowKv
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthKv
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.MaxRecentlyQueriedSeriesBlocks == nil {
+				m.MaxRecentlyQueriedSeriesBlocks = &QueryLimit{}
+			}
+			if err := m.MaxRecentlyQueriedSeriesBlocks.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxRecentlyQueriedSeriesDiskBytesRead", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowKv
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthKv
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.MaxRecentlyQueriedSeriesDiskBytesRead == nil {
+				m.MaxRecentlyQueriedSeriesDiskBytesRead = &QueryLimit{}
+			}
+			if err := m.MaxRecentlyQueriedSeriesDiskBytesRead.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipKv(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthKv
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipKv(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
@@ -1166,30 +1673,37 @@ func init() {
 }
 
 var fileDescriptorKv = []byte{
-	// 391 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x52, 0x4d, 0xab, 0xd3, 0x40,
-	0x14, 0x35, 0xe6, 0xf9, 0x78, 0xef, 0x16, 0x35, 0x0c, 0x0f, 0xe9, 0x2a, 0x84, 0xa0, 0xd0, 0x55,
-	0x06, 0xec, 0xd6, 0x55, 0xd1, 0x95, 0x15, 0x74, 0x8a, 0x1f, 0x0b, 0x37, 0x93, 0x99, 0xdb, 0x1a,
-	0x32, 0xc9, 0x94, 0xcc, 0xa4, 0x36, 0xbf, 0xc0, 0xad, 0x0b, 0x7f, 0x94, 0x4b, 0x7f, 0x82, 0xd4,
-	0x3f, 0x22, 0x33, 0x29, 0xb4, 0x95, 0xd6, 0x76, 0x13, 0xee, 0x39, 0xb9, 0xe7, 0xdc, 0x3b, 0x87,
-	0x0b, 0x2f, 0x16, 0x85, 0xfd, 0xd2, 0xe6, 0x99, 0xd0, 0x15, 0xad, 0xc6, 0x32, 0xa7, 0xd5, 0x98,
-	0x9a, 0x46, 0x50, 0xa1, 0x5a, 0x63, 0xb1, 0xa1, 0x0b, 0xac, 0xb1, 0xe1, 0x16, 0x25, 0x5d, 0x36,
-	0xda, 0x6a, 0x5a, 0xae, 0x96, 0x39, 0x2d, 0x57, 0x99, 0x47, 0xe4, 0xca, 0xc1, 0xf4, 0x2d, 0x3c,
-	0x7a, 0x8d, 0xdd, 0x07, 0xae, 0x5a, 0x7c, 0xbf, 0x94, 0xdc, 0x22, 0x89, 0x20, 0x2c, 0xb1, 0x1b,
-	0x06, 0x49, 0x30, 0xba, 0x65, 0xae, 0x24, 0x77, 0xf0, 0x60, 0xe5, 0x1a, 0x86, 0xf7, 0x3d, 0xd7,
-	0x03, 0xf2, 0x04, 0xae, 0x85, 0xae, 0xaa, 0xc2, 0x0e, 0xc3, 0x24, 0x18, 0xdd, 0xb0, 0x2d, 0x4a,
-	0xa7, 0x70, 0x77, 0xe8, 0xc8, 0xd0, 0xb4, 0xca, 0x1e, 0xf1, 0x8d, 0x20, 0xd4, 0x4a, 0x6e, 0x5d,
-	0x5d, 0xe9, 0x98, 0x1a, 0xbf, 0x7a, 0xc3, 0x5b, 0xe6, 0xca, 0xf4, 0x5b, 0x08, 0x83, 0x77, 0x2d,
-	0x36, 0xdd, 0xb4, 0xa8, 0x0a, 0x6b, 0xc8, 0x27, 0x88, 0x2b, 0xbe, 0x66, 0x28, 0xb0, 0xb6, 0xaa,
-	0x73, 0x7f, 0x0a, 0x94, 0x33, 0xf7, 0x35, 0x13, 0xa5, 0x45, 0x69, 0xfc, 0x80, 0xc1, 0xf3, 0x28,
-	0x73, 0xcf, 0xcb, 0x76, 0x52, 0x76, 0x46, 0x47, 0xe6, 0xf0, 0xec, 0x54, 0xc7, 0xcb, 0xc2, 0x94,
-	0x93, 0xce, 0xa2, 0x61, 0xc8, 0xfb, 0x7d, 0x8f, 0x0d, 0xb8, 0x4c, 0x4e, 0x3e, 0x43, 0xf2, 0xbf,
-	0x46, 0x3f, 0x22, 0x3c, 0x31, 0xe2, 0xac, 0xf2, 0x78, 0x3e, 0x6f, 0xd0, 0x72, 0xc9, 0x2d, 0xf7,
-	0xde, 0x57, 0x97, 0xe7, 0xb3, 0xaf, 0x4b, 0x7f, 0x04, 0x00, 0xbb, 0x76, 0x77, 0x14, 0xca, 0x15,
-	0x3e, 0xef, 0x90, 0xf5, 0x80, 0x8c, 0xe0, 0xb1, 0xd2, 0xba, 0xcc, 0xb9, 0x28, 0x67, 0x28, 0x74,
-	0x2d, 0x8d, 0x8f, 0x2b, 0x64, 0xff, 0xd2, 0xe4, 0x29, 0x3c, 0x9c, 0xeb, 0x46, 0xe0, 0xab, 0xb5,
-	0x40, 0x94, 0x28, 0xb7, 0x57, 0x74, 0x48, 0x92, 0x04, 0x06, 0x9e, 0xf8, 0xc8, 0x0b, 0x8b, 0xfd,
-	0xee, 0x37, 0x6c, 0x9f, 0x9a, 0x44, 0x3f, 0x37, 0x71, 0xf0, 0x6b, 0x13, 0x07, 0xbf, 0x37, 0x71,
-	0xf0, 0xfd, 0x4f, 0x7c, 0x2f, 0xbf, 0xf6, 0xf7, 0x3d, 0xfe, 0x1b, 0x00, 0x00, 0xff, 0xff, 0x1c,
-	0x39, 0x9e, 0xf7, 0x1f, 0x03, 0x00, 0x00,
+	// 510 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x54, 0xcd, 0x6e, 0xd3, 0x40,
+	0x10, 0xc6, 0x75, 0x5b, 0xda, 0x49, 0x81, 0xb0, 0xaa, 0x50, 0x38, 0x10, 0x45, 0x16, 0x48, 0x91,
+	0x90, 0x62, 0x89, 0x88, 0x5b, 0x4f, 0x21, 0x9c, 0x28, 0x52, 0xbb, 0x05, 0xca, 0x81, 0xcb, 0xc6,
+	0x3b, 0x29, 0x96, 0x7f, 0x36, 0xda, 0x1d, 0x87, 0xf8, 0x09, 0xb8, 0x72, 0xe0, 0xa1, 0x38, 0xf2,
+	0x08, 0x28, 0x1c, 0x79, 0x09, 0xb4, 0x6b, 0xa3, 0x24, 0x28, 0x69, 0x23, 0x21, 0x2e, 0xd1, 0xcc,
+	0x37, 0xdf, 0x7c, 0x33, 0x3b, 0x99, 0x31, 0x9c, 0x5c, 0xc5, 0xf4, 0xb1, 0x18, 0xf5, 0x22, 0x95,
+	0x85, 0x59, 0x5f, 0x8e, 0xc2, 0xac, 0x1f, 0x1a, 0x1d, 0x85, 0x51, 0x5a, 0x18, 0x42, 0x1d, 0x5e,
+	0x61, 0x8e, 0x5a, 0x10, 0xca, 0x70, 0xa2, 0x15, 0xa9, 0x30, 0x99, 0x4e, 0x46, 0x61, 0x32, 0xed,
+	0x39, 0x8f, 0xed, 0x5a, 0x37, 0x38, 0x83, 0xbb, 0xaf, 0xb0, 0x7c, 0x27, 0xd2, 0x02, 0xdf, 0x4e,
+	0xa4, 0x20, 0x64, 0x4d, 0xf0, 0x13, 0x2c, 0x5b, 0x5e, 0xc7, 0xeb, 0x1e, 0x72, 0x6b, 0xb2, 0x63,
+	0xd8, 0x9b, 0x5a, 0x42, 0x6b, 0xc7, 0x61, 0x95, 0xc3, 0x1e, 0xc0, 0x7e, 0xa4, 0xb2, 0x2c, 0xa6,
+	0x96, 0xdf, 0xf1, 0xba, 0x07, 0xbc, 0xf6, 0x82, 0x53, 0x38, 0x5e, 0x55, 0xe4, 0x68, 0x8a, 0x94,
+	0xd6, 0xe8, 0x36, 0xc1, 0x57, 0xa9, 0xac, 0x55, 0xad, 0x69, 0x91, 0x1c, 0x3f, 0x39, 0xc1, 0x43,
+	0x6e, 0xcd, 0xe0, 0xb3, 0x0f, 0x8d, 0xf3, 0x02, 0x75, 0x79, 0x1a, 0x67, 0x31, 0x19, 0xf6, 0x1e,
+	0xda, 0x99, 0x98, 0x71, 0x8c, 0x30, 0xa7, 0xb4, 0xb4, 0x91, 0x18, 0xe5, 0x85, 0xfd, 0x35, 0x83,
+	0x54, 0x45, 0x89, 0x71, 0x05, 0x1a, 0xcf, 0x9a, 0x3d, 0xfb, 0xbc, 0xde, 0x22, 0x95, 0xdf, 0x90,
+	0xc7, 0xc6, 0xf0, 0x64, 0x13, 0x63, 0x18, 0x9b, 0x64, 0x50, 0x12, 0x1a, 0x8e, 0xa2, 0xea, 0x77,
+	0x5d, 0x81, 0xed, 0xd2, 0xd9, 0x07, 0xe8, 0x5c, 0x47, 0x74, 0x25, 0xfc, 0x0d, 0x25, 0x6e, 0xcc,
+	0x5c, 0x3f, 0x9f, 0xd7, 0x48, 0x42, 0x0a, 0x12, 0x4e, 0x7b, 0x77, 0xfb, 0xf9, 0x2c, 0xe7, 0x05,
+	0x5f, 0x3d, 0x80, 0x05, 0xdd, 0x2e, 0x45, 0x6a, 0x0d, 0x37, 0x6f, 0x9f, 0x57, 0x0e, 0xeb, 0xc2,
+	0xbd, 0x54, 0xa9, 0x64, 0x24, 0xa2, 0xe4, 0x02, 0x23, 0x95, 0x4b, 0xe3, 0xc6, 0xe5, 0xf3, 0xbf,
+	0x61, 0xf6, 0x18, 0xee, 0x8c, 0x95, 0x8e, 0xf0, 0xe5, 0x2c, 0x42, 0x94, 0x28, 0xeb, 0x2d, 0x5a,
+	0x05, 0x59, 0x07, 0x1a, 0x0e, 0xb8, 0x14, 0x31, 0x61, 0xd5, 0xfb, 0x01, 0x5f, 0x86, 0x02, 0x0d,
+	0x47, 0x6f, 0x30, 0x17, 0x39, 0x9d, 0x17, 0x8a, 0x84, 0x61, 0xcf, 0xe1, 0x48, 0xe2, 0x58, 0x14,
+	0x69, 0x05, 0xd4, 0xeb, 0x70, 0xbf, 0x7a, 0xee, 0x12, 0x93, 0xaf, 0xd0, 0xd8, 0x53, 0xb8, 0x4d,
+	0x2e, 0x68, 0x1b, 0xf6, 0xd7, 0x67, 0xfc, 0x61, 0x04, 0xbf, 0x76, 0xa0, 0xb1, 0x14, 0xb0, 0xa7,
+	0x50, 0x85, 0xea, 0xed, 0xae, 0x3d, 0x76, 0x02, 0x0f, 0x33, 0x31, 0xab, 0xfe, 0xa1, 0x17, 0x1a,
+	0xed, 0x29, 0x9e, 0xa1, 0xae, 0x26, 0x50, 0xcf, 0x65, 0x33, 0x81, 0x0d, 0xe1, 0x51, 0x26, 0x66,
+	0x43, 0x41, 0x62, 0xa2, 0xe2, 0x9c, 0xcc, 0xa5, 0x8e, 0x89, 0x30, 0x5f, 0x28, 0xf8, 0x4e, 0xe1,
+	0x7a, 0xd2, 0x16, 0x07, 0xb3, 0xfb, 0xbf, 0x0f, 0x66, 0xef, 0x9f, 0x0e, 0x66, 0xd0, 0xfc, 0x36,
+	0x6f, 0x7b, 0xdf, 0xe7, 0x6d, 0xef, 0xc7, 0xbc, 0xed, 0x7d, 0xf9, 0xd9, 0xbe, 0x35, 0xda, 0x77,
+	0x5f, 0xb0, 0xfe, 0xef, 0x01, 0x00, 0xc7, 0x13, 0x68, 0xdc, 0x01, 0x05, 0x00, 0x00,
 }
@@ -322,6 +322,18 @@ func (a mirroredAlgorithm) BalanceShards(
 	return placementFromMirror(mirrorPlacement, p.Instances(), p.ReplicaFactor())
 }
 
+func (a mirroredAlgorithm) SplitShards(
+	p placement.Placement,
+) (placement.Placement, error) {
+	if err := a.IsCompatibleWith(p); err != nil {
+		return nil, err
+	}
+
+	// NB: Every instance takes the children of the shards it owns, so instances
+	// sharing a shard set keep sharing the same shards after the split.
+	return a.shardedAlgo.SplitShards(p)
+}
+
 // returnInitializingShards tries to return initializing shards on the given instances
 // and retries until no more initializing shards could be returned.
 func (a mirroredAlgorithm) returnInitializingShards(
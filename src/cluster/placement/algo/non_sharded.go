@@ -167,3 +167,12 @@ func (a nonShardedAlgorithm) BalanceShards(
 	// There is no shards in non-sharded algorithm.
 	return p, nil
 }
+
+func (a nonShardedAlgorithm) SplitShards(
+	p placement.Placement,
+) (placement.Placement, error) {
+	if err := a.IsCompatibleWith(p); err != nil {
+		return nil, err
+	}
+	return nil, errShardsOnNonShardedAlgo
+}
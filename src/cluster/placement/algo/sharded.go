@@ -229,3 +229,18 @@ func (a shardedPlacementAlgorithm) BalanceShards(
 
 	return tryCleanupShardState(ph.generatePlacement(), a.opts)
 }
+
+func (a shardedPlacementAlgorithm) SplitShards(
+	p placement.Placement,
+) (placement.Placement, error) {
+	if err := a.IsCompatibleWith(p); err != nil {
+		return nil, err
+	}
+
+	p, err := splitShards(p.Clone(), a.opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return tryCleanupShardState(p, a.opts)
+}
@@ -36,6 +36,7 @@ var (
 	errAddingInstanceAlreadyExist         = errors.New("the adding instance is already in the placement")
 	errInstanceContainsNonLeavingShards   = errors.New("the adding instance contains non leaving shards")
 	errInstanceContainsInitializingShards = errors.New("the adding instance contains initializing shards")
+	errSplitNonContiguousShards           = errors.New("could not split shards, the placement shards are not contiguous from zero")
 )
 
 type instanceType int
@@ -865,3 +866,52 @@ func markAllShardsAvailable(
 	}
 	return p, updated, nil
 }
+
+// splitShards doubles the number of shards in the placement. For every shard
+// an instance owns, the instance takes the child shard with the ID of the
+// shard plus the current number of shards, the child shard is initializing
+// and redirects to its parent shard until the instance has split the data of
+// the parent shard into the child shard and the child shard is marked as
+// available. This relies on shards being assigned by hashing modulo the
+// number of shards, which makes a series in a child shard always belong to
+// its parent shard before the split.
+func splitShards(p placement.Placement, opts placement.Options) (placement.Placement, error) {
+	numShards := uint32(p.NumShards())
+	if numShards == 0 {
+		return nil, errSplitNonContiguousShards
+	}
+	for _, shardID := range p.Shards() {
+		if shardID >= numShards {
+			return nil, errSplitNonContiguousShards
+		}
+	}
+
+	for _, instance := range p.Instances() {
+		for _, s := range instance.Shards().All() {
+			if s.State() != shard.Available {
+				return nil, fmt.Errorf("could not split shards, shard %d on instance %s is %s",
+					s.ID(), instance.ID(), s.State().String())
+			}
+		}
+	}
+
+	for _, instance := range p.Instances() {
+		shards := instance.Shards()
+		for _, s := range shards.All() {
+			parentID := s.ID()
+			shards.Add(shard.NewShard(parentID + numShards).
+				SetState(shard.Initializing).
+				SetCutoverNanos(opts.ShardCutoverNanosFn()()).
+				SetRedirectToShardID(&parentID))
+		}
+	}
+
+	splitShards := make([]uint32, 0, 2*numShards)
+	for shardID := uint32(0); shardID < 2*numShards; shardID++ {
+		splitShards = append(splitShards, shardID)
+	}
+
+	return p.
+		SetShards(splitShards).
+		SetCutoverNanos(opts.PlacementCutoverNanosFn()()), nil
+}
@@ -1306,6 +1306,71 @@ func TestBalanceShardsForShardedWhenImbalanced(t *testing.T) {
 	assert.Equal(t, expectedInstances, balancedPlacement.Instances())
 }
 
+func TestSplitShardsForSharded(t *testing.T) {
+	i1 := newTestInstance("i1").
+		SetShards(shard.NewShards([]shard.Shard{
+			shard.NewShard(0).SetState(shard.Available),
+			shard.NewShard(1).SetState(shard.Available),
+		}))
+	i2 := newTestInstance("i2").
+		SetShards(shard.NewShards([]shard.Shard{
+			shard.NewShard(0).SetState(shard.Available),
+			shard.NewShard(1).SetState(shard.Available),
+		}))
+	p := placement.NewPlacement().
+		SetReplicaFactor(2).
+		SetShards([]uint32{0, 1}).
+		SetInstances([]placement.Instance{i1, i2}).
+		SetIsSharded(true)
+
+	opts := placement.NewOptions().SetShardStateMode(placement.IncludeTransitionalShardStates)
+	a := NewAlgorithm(opts)
+
+	splitPlacement, err := a.SplitShards(p)
+	require.NoError(t, err)
+	require.NoError(t, placement.Validate(splitPlacement))
+	require.Equal(t, []uint32{0, 1, 2, 3}, splitPlacement.Shards())
+
+	// The original placement is untouched.
+	require.Equal(t, 2, i1.Shards().NumShards())
+
+	for _, instance := range splitPlacement.Instances() {
+		shards := instance.Shards()
+		require.Equal(t, []uint32{0, 1}, shardIDs(shards.ShardsForState(shard.Available)))
+		require.Equal(t, []uint32{2, 3}, shardIDs(shards.ShardsForState(shard.Initializing)))
+		for _, s := range shards.ShardsForState(shard.Initializing) {
+			require.NotNil(t, s.RedirectToShardID())
+			require.Equal(t, s.ID()-2, *s.RedirectToShardID())
+			require.Equal(t, "", s.SourceID())
+		}
+	}
+
+	// Shards can not be split again until the children are available.
+	_, err = a.SplitShards(splitPlacement)
+	require.Error(t, err)
+
+	splitPlacement, _, err = a.MarkAllShardsAvailable(splitPlacement)
+	require.NoError(t, err)
+	for _, instance := range splitPlacement.Instances() {
+		for _, s := range instance.Shards().All() {
+			require.Equal(t, shard.Available, s.State())
+			require.Nil(t, s.RedirectToShardID())
+		}
+	}
+
+	// Placements with gaps in the shard IDs can not be split.
+	_, err = a.SplitShards(p.Clone().SetShards([]uint32{0, 2}))
+	require.Error(t, err)
+}
+
+func shardIDs(shards []shard.Shard) []uint32 {
+	ids := make([]uint32, 0, len(shards))
+	for _, s := range shards {
+		ids = append(ids, s.ID())
+	}
+	return ids
+}
+
 func verifyAllShardsInAvailableState(t *testing.T, p placement.Placement) {
 	for _, instance := range p.Instances() {
 		s := instance.Shards()
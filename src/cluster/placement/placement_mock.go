@@ -2094,6 +2094,21 @@ func (mr *MockServiceMockRecorder) SetProto(p interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetProto", reflect.TypeOf((*MockService)(nil).SetProto), p)
 }
 
+// SplitShards mocks base method.
+func (m *MockService) SplitShards() (Placement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SplitShards")
+	ret0, _ := ret[0].(Placement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SplitShards indicates an expected call of SplitShards.
+func (mr *MockServiceMockRecorder) SplitShards() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SplitShards", reflect.TypeOf((*MockService)(nil).SplitShards))
+}
+
 // Watch mocks base method.
 func (m *MockService) Watch() (Watch, error) {
 	m.ctrl.T.Helper()
@@ -2288,6 +2303,21 @@ func (mr *MockOperatorMockRecorder) ReplaceInstances(leavingInstanceIDs, candida
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceInstances", reflect.TypeOf((*MockOperator)(nil).ReplaceInstances), leavingInstanceIDs, candidates)
 }
 
+// SplitShards mocks base method.
+func (m *MockOperator) SplitShards() (Placement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SplitShards")
+	ret0, _ := ret[0].(Placement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SplitShards indicates an expected call of SplitShards.
+func (mr *MockOperatorMockRecorder) SplitShards() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SplitShards", reflect.TypeOf((*MockOperator)(nil).SplitShards))
+}
+
 // Mockoperations is a mock of operations interface.
 type Mockoperations struct {
 	ctrl     *gomock.Controller
@@ -2453,6 +2483,21 @@ func (mr *MockoperationsMockRecorder) ReplaceInstances(leavingInstanceIDs, candi
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceInstances", reflect.TypeOf((*Mockoperations)(nil).ReplaceInstances), leavingInstanceIDs, candidates)
 }
 
+// SplitShards mocks base method.
+func (m *Mockoperations) SplitShards() (Placement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SplitShards")
+	ret0, _ := ret[0].(Placement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SplitShards indicates an expected call of SplitShards.
+func (mr *MockoperationsMockRecorder) SplitShards() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SplitShards", reflect.TypeOf((*Mockoperations)(nil).SplitShards))
+}
+
 // MockAlgorithm is a mock of Algorithm interface.
 type MockAlgorithm struct {
 	ctrl     *gomock.Controller
@@ -2616,6 +2661,21 @@ func (mr *MockAlgorithmMockRecorder) ReplaceInstances(p, leavingInstanecIDs, add
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceInstances", reflect.TypeOf((*MockAlgorithm)(nil).ReplaceInstances), p, leavingInstanecIDs, addingInstances)
 }
 
+// SplitShards mocks base method.
+func (m *MockAlgorithm) SplitShards(p Placement) (Placement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SplitShards", p)
+	ret0, _ := ret[0].(Placement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SplitShards indicates an expected call of SplitShards.
+func (mr *MockAlgorithmMockRecorder) SplitShards(p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SplitShards", reflect.TypeOf((*MockAlgorithm)(nil).SplitShards), p)
+}
+
 // MockInstanceSelector is a mock of InstanceSelector interface.
 type MockInstanceSelector struct {
 	ctrl     *gomock.Controller
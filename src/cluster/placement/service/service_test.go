@@ -1007,6 +1007,47 @@ func TestBalanceShards(t *testing.T) {
 	assert.Equal(t, expectedInstances, p.Instances())
 }
 
+func TestSplitShards(t *testing.T) {
+	ms := newMockStorage()
+
+	i1 := placement.NewEmptyInstance("i1", "r1", "z1", "endpoint", 1)
+	i1.Shards().Add(shard.NewShard(0).SetState(shard.Available))
+
+	i2 := placement.NewEmptyInstance("i2", "r1", "z1", "endpoint", 1)
+	i2.Shards().Add(shard.NewShard(1).SetState(shard.Available))
+
+	p := placement.NewPlacement().
+		SetInstances([]placement.Instance{i1, i2}).
+		SetShards([]uint32{0, 1}).
+		SetReplicaFactor(1).
+		SetIsSharded(true)
+
+	_, err := ms.SetIfNotExist(p)
+	assert.NoError(t, err)
+
+	ps := NewPlacementService(ms, WithPlacementOptions(placement.NewOptions()))
+
+	p, err = ps.SplitShards()
+	assert.NoError(t, err)
+	assert.Equal(t, []uint32{0, 1, 2, 3}, p.Shards())
+
+	parent0, parent1 := uint32(0), uint32(1)
+	si1 := placement.NewEmptyInstance("i1", "r1", "z1", "endpoint", 1)
+	si1.Shards().Add(shard.NewShard(0).SetState(shard.Available))
+	si1.Shards().Add(shard.NewShard(2).SetState(shard.Initializing).SetRedirectToShardID(&parent0))
+
+	si2 := placement.NewEmptyInstance("i2", "r1", "z1", "endpoint", 1)
+	si2.Shards().Add(shard.NewShard(1).SetState(shard.Available))
+	si2.Shards().Add(shard.NewShard(3).SetState(shard.Initializing).SetRedirectToShardID(&parent1))
+
+	expectedInstances := []placement.Instance{si1, si2}
+	assert.Equal(t, expectedInstances, p.Instances())
+
+	p, err = ps.Placement()
+	assert.NoError(t, err)
+	assert.Equal(t, expectedInstances, p.Instances())
+}
+
 func newMockStorage() placement.Storage {
 	return storage.NewPlacementStorage(mem.NewStore(), "", nil)
 }
@@ -507,6 +507,10 @@ type operations interface {
 
 	// BalanceShards rebalances load in the cluster to achieve the most balanced shard distribution.
 	BalanceShards() (Placement, error)
+
+	// SplitShards doubles the number of shards in the placement by splitting every shard
+	// into itself and a child shard that is initialized on the instances owning the parent.
+	SplitShards() (Placement, error)
 }
 
 // Algorithm places shards on instances.
@@ -541,6 +545,10 @@ type Algorithm interface {
 
 	// BalanceShards rebalances load in the cluster to achieve the most balanced shard distribution.
 	BalanceShards(p Placement) (Placement, error)
+
+	// SplitShards doubles the number of shards in the placement by splitting every shard
+	// into itself and a child shard that is initialized on the instances owning the parent.
+	SplitShards(p Placement) (Placement, error)
 }
 
 // InstanceSelector selects valid instances for the placement change.
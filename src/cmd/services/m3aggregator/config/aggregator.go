@@ -32,6 +32,7 @@ import (
 	"time"
 
 	"github.com/m3db/m3/src/aggregator/aggregation/quantile/cm"
+	"github.com/m3db/m3/src/aggregator/aggregation/quantile/ddsketch"
 	"github.com/m3db/m3/src/aggregator/aggregator"
 	"github.com/m3db/m3/src/aggregator/aggregator/handler"
 	"github.com/m3db/m3/src/aggregator/aggregator/handler/writer"
@@ -91,6 +92,10 @@ type AggregatorConfiguration struct {
 	// Stream configuration for computing quantiles.
 	Stream streamConfiguration `yaml:"stream"`
 
+	// Sketch configuration for computing quantiles of timers aggregated
+	// with sketches.
+	Sketch sketchConfiguration `yaml:"sketch"`
+
 	// Client configuration.
 	Client aggclient.Configuration `yaml:"client"`
 
@@ -303,6 +308,13 @@ func (c *AggregatorConfiguration) NewAggregatorOptions(
 	}
 	opts = opts.SetStreamOptions(streamOpts)
 
+	// Set sketch options.
+	sketchOpts, err := c.Sketch.NewSketchOptions()
+	if err != nil {
+		return nil, err
+	}
+	opts = opts.SetSketchOptions(sketchOpts)
+
 	// Set administrative client.
 	// TODO(xichen): client retry threshold likely needs to be low for faster retries.
 	iOpts = instrumentOpts.SetMetricsScope(scope.SubScope("client"))
@@ -576,6 +588,31 @@ func (c *streamConfiguration) NewStreamOptions(_ instrument.Options) (cm.Options
 	return opts, nil
 }
 
+// sketchConfiguration contains configuration for timers aggregated with sketches.
+type sketchConfiguration struct {
+	// Relative accuracy of quantiles computed from sketches.
+	RelativeAccuracy float64 `yaml:"relativeAccuracy"`
+
+	// Maximum number of buckets of a sketch, beyond which the accuracy of
+	// the sketch is lowered.
+	MaxNumBuckets int `yaml:"maxNumBuckets"`
+}
+
+func (c *sketchConfiguration) NewSketchOptions() (ddsketch.Options, error) {
+	opts := ddsketch.NewOptions()
+	if c.RelativeAccuracy != 0 {
+		opts = opts.SetRelativeAccuracy(c.RelativeAccuracy)
+	}
+	if c.MaxNumBuckets != 0 {
+		opts = opts.SetMaxNumBuckets(c.MaxNumBuckets)
+	}
+
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	return opts, nil
+}
+
 type placementManagerConfiguration struct {
 	KVConfig kv.OverrideConfiguration       `yaml:"kvConfig"`
 	Watcher  placement.WatcherConfiguration `yaml:"placementWatcher"`
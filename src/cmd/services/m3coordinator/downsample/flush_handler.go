@@ -25,8 +25,10 @@ import (
 	"context"
 	"sync"
 
+	"github.com/m3db/m3/src/aggregator/aggregation/quantile/ddsketch"
 	"github.com/m3db/m3/src/aggregator/aggregator/handler"
 	"github.com/m3db/m3/src/aggregator/aggregator/handler/writer"
+	"github.com/m3db/m3/src/metrics/metric"
 	"github.com/m3db/m3/src/metrics/metric/aggregated"
 	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/query/storage"
@@ -49,8 +51,37 @@ var (
 	// GraphiteIDSchemeTagValue specifies that the graphite ID
 	// scheme should be used for a metric.
 	GraphiteIDSchemeTagValue = []byte("graphite")
+	// GraphiteTaggedIDSchemeTagValue specifies that the graphite tagged ID
+	// scheme should be used for a metric.
+	GraphiteTaggedIDSchemeTagValue = []byte("graphite_tagged")
 )
 
+// IDSchemeTagValue returns the value of the ID scheme meta tag of a metric
+// using the given ID scheme, or nil if the metric needs no ID scheme meta tag.
+func IDSchemeTagValue(scheme models.IDSchemeType) []byte {
+	switch scheme {
+	case models.TypeGraphite:
+		return GraphiteIDSchemeTagValue
+	case models.TypeGraphiteTagged:
+		return GraphiteTaggedIDSchemeTagValue
+	default:
+		return nil
+	}
+}
+
+// IDSchemeFromTagValue returns the ID scheme specified by the value of an
+// ID scheme meta tag.
+func IDSchemeFromTagValue(value []byte) (models.IDSchemeType, bool) {
+	switch {
+	case bytes.Equal(value, GraphiteIDSchemeTagValue):
+		return models.TypeGraphite, true
+	case bytes.Equal(value, GraphiteTaggedIDSchemeTagValue):
+		return models.TypeGraphiteTagged, true
+	default:
+		return models.TypeDefault, false
+	}
+}
+
 var (
 	aggregationSuffixTag = []byte("agg")
 )
@@ -146,10 +177,10 @@ func (w *downsamplerFlushHandlerWriter) Write(
 			// other path where flows back to the coordinator from the aggregator
 			// and this tag is interpreted, eventually need to handle more cleanly.
 			if bytes.Equal(name, MetricsOptionIDSchemeTagName) {
-				if bytes.Equal(value, GraphiteIDSchemeTagValue) &&
-					tags.Opts.IDSchemeType() != models.TypeGraphite {
+				if scheme, ok := IDSchemeFromTagValue(value); ok &&
+					tags.Opts.IDSchemeType() != scheme {
 					iter.Reset(mp.ChunkedID.Data)
-					tags.Opts = w.tagOptions.SetIDSchemeType(models.TypeGraphite)
+					tags.Opts = w.tagOptions.SetIDSchemeType(scheme)
 					tags.Tags = tags.Tags[:0]
 				}
 				// Continue, whether we updated and need to restart iteration,
@@ -172,6 +203,23 @@ func (w *downsamplerFlushHandlerWriter) Write(
 			return
 		}
 
+		annotation := mp.Annotation
+		if ddsketch.IsEncoded(annotation) {
+			// NB: timers aggregated with sketches are written as native
+			// histograms so that any quantile can be computed at query time.
+			annotation, err = storage.SketchAnnotationToHistogramAnnotation(
+				xtime.UnixNano(mp.TimeNanos), annotation)
+			if err != nil {
+				logger.Error("downsampler flush error decoding sketch", zap.Error(err))
+				w.handler.metrics.flushErrors.Inc(1)
+				return
+			}
+			tags = tags.AddTag(models.Tag{
+				Name:  metric.M3PromNativeHistogramTag,
+				Value: metric.PromNativeHistogramEncodedValue,
+			})
+		}
+
 		writeQuery, err := storage.NewWriteQuery(storage.WriteQueryOptions{
 			Tags: tags,
 			Datapoints: ts.Datapoints{ts.Datapoint{
@@ -179,7 +227,7 @@ func (w *downsamplerFlushHandlerWriter) Write(
 				Value:     mp.Value,
 			}},
 			Unit:       convert.UnitForM3DB(mp.StoragePolicy.Resolution().Precision),
-			Annotation: mp.Annotation,
+			Annotation: annotation,
 			Attributes: storagemetadata.Attributes{
 				MetricsType: storagemetadata.AggregatedMetricsType,
 				Retention:   mp.StoragePolicy.Retention().Duration(),
@@ -25,10 +25,13 @@ import (
 	"sync"
 	"testing"
 
+	"github.com/m3db/m3/src/aggregator/aggregation/quantile/ddsketch"
+	"github.com/m3db/m3/src/metrics/metric"
 	"github.com/m3db/m3/src/metrics/metric/aggregated"
 	"github.com/m3db/m3/src/metrics/metric/id"
 	"github.com/m3db/m3/src/metrics/policy"
 	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/storage"
 	"github.com/m3db/m3/src/query/storage/mock"
 	"github.com/m3db/m3/src/x/ident"
 	"github.com/m3db/m3/src/x/instrument"
@@ -112,6 +115,73 @@ func TestDownsamplerFlushHandlerCopiesTags(t *testing.T) {
 	assert.Equal(t, annotation, writes[0].Annotation())
 }
 
+func TestDownsamplerFlushHandlerWritesSketchAsHistogram(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	store := mock.NewMockStorage()
+	pool := serialize.NewMockMetricTagsIteratorPool(ctrl)
+
+	workers := xsync.NewWorkerPool(1)
+	workers.Init()
+
+	instrumentOpts := instrument.NewOptions()
+
+	handler := newDownsamplerFlushHandler(store, pool,
+		workers, models.NewTagOptions(), instrumentOpts)
+	writer, err := handler.NewWriter(tally.NoopScope)
+	require.NoError(t, err)
+
+	var (
+		expectedID = []byte("foo")
+		tagName    = []byte("name")
+		tagValue   = []byte("value")
+		sketch     = ddsketch.NewSketch(ddsketch.NewOptions())
+	)
+	sketch.AddBatch([]float64{1, 2, 3})
+
+	iter := serialize.NewMockMetricTagsIterator(ctrl)
+	gomock.InOrder(
+		iter.EXPECT().Reset(expectedID),
+		iter.EXPECT().NumTags().Return(1),
+		iter.EXPECT().Next().Return(true),
+		iter.EXPECT().Current().Return(tagName, tagValue),
+		iter.EXPECT().Next().Return(false),
+		iter.EXPECT().Err().Return(nil),
+		iter.EXPECT().Close(),
+	)
+
+	pool.EXPECT().Get().Return(iter)
+
+	// Write metric
+	err = writer.Write(aggregated.ChunkedMetricWithStoragePolicy{
+		ChunkedMetric: aggregated.ChunkedMetric{
+			ChunkedID:  id.ChunkedID{Data: expectedID},
+			TimeNanos:  123,
+			Value:      3,
+			Annotation: sketch.Encode(),
+		},
+		StoragePolicy: policy.MustParseStoragePolicy("1s:1d"),
+	})
+	require.NoError(t, err)
+
+	// Wait for flush
+	err = writer.Flush()
+	require.NoError(t, err)
+
+	// Inspect the write
+	writes := store.Writes()
+	require.Equal(t, 1, len(writes))
+
+	marker, ok := writes[0].Tags().Get(metric.M3PromNativeHistogramTag)
+	require.True(t, ok)
+	assert.Equal(t, metric.PromNativeHistogramEncodedValue, marker)
+
+	expected, err := storage.SketchAnnotationToHistogramAnnotation(123, sketch.Encode())
+	require.NoError(t, err)
+	assert.Equal(t, expected, writes[0].Annotation())
+}
+
 func graphiteTags(
 	t *testing.T, first string, encPool serialize.TagEncoderPool) []byte {
 	enc := encPool.Get()
@@ -65,6 +65,12 @@ var (
 	carbonSeparatorByte  = byte('.')
 	carbonSeparatorBytes = []byte{carbonSeparatorByte}
 
+	// Used for parsing tagged carbon names (i.e. path;tag=value) into tags.
+	graphiteTagSeparator      = byte(';')
+	graphiteTagSeparatorBytes = []byte{graphiteTagSeparator}
+	graphiteTagValueSeparator = byte('=')
+	graphiteNameTag           = []byte("name")
+
 	errCannotGenerateTagsFromEmptyName = errors.New("cannot generate tags from empty name")
 	errIOptsMustBeSet                  = errors.New("carbon ingester options: instrument options must be st")
 	errWorkerPoolMustBeSet             = errors.New("carbon ingester options: worker pool must be set")
@@ -109,6 +115,7 @@ func NewIngester(
 	if err != nil {
 		return nil, err
 	}
+	taggedTagOpts := tagOpts.SetIDSchemeType(models.TypeGraphiteTagged)
 
 	poolOpts := pool.NewObjectPoolOptions().
 		SetInstrumentOptions(opts.InstrumentOptions).
@@ -136,6 +143,7 @@ func NewIngester(
 		opts:                 opts,
 		logger:               opts.InstrumentOptions.Logger(),
 		tagOpts:              tagOpts,
+		taggedTagOpts:        taggedTagOpts,
 		metrics:              metrics,
 		lineResourcesPool:    resourcePool,
 	}
@@ -152,6 +160,7 @@ type ingester struct {
 	logger               *zap.Logger
 	metrics              carbonIngesterMetrics
 	tagOpts              models.TagOptions
+	taggedTagOpts        models.TagOptions
 
 	lineResourcesPool pool.ObjectPool
 
@@ -423,7 +432,11 @@ func (i *ingester) writeWithOptions(
 	opts ingest.WriteOptions,
 ) error {
 	resources.datapoints[0] = ts.Datapoint{Timestamp: timestamp, Value: value}
-	tags, err := GenerateTagsFromNameIntoSlice(resources.name, i.tagOpts, resources.tags)
+	tagOpts := i.tagOpts
+	if bytes.IndexByte(resources.name, graphiteTagSeparator) >= 0 {
+		tagOpts = i.taggedTagOpts
+	}
+	tags, err := GenerateTagsFromNameIntoSlice(resources.name, tagOpts, resources.tags)
 	if err != nil {
 		i.logger.Error("err generating tags from carbon",
 			zap.String("name", string(resources.name)), zap.Error(err))
@@ -476,6 +489,15 @@ func newCarbonIngesterMetrics(scope tally.Scope) (carbonIngesterMetrics, error)
 //      __g0__:foo
 //      __g1__:bar
 //      __g2__:baz
+// Tagged carbon metric names are also accepted such that an input like:
+//      foo.bar;env=prod;dc=east
+// becomes
+//      __g0__:foo
+//      __g1__:bar
+//      dc:east
+//      env:prod
+// Tagged names use the graphite tagged ID scheme if the options use the
+// graphite ID scheme, so that untagged names keep their graphite IDs.
 func GenerateTagsFromName(
 	name []byte,
 	opts models.TagOptions,
@@ -498,11 +520,19 @@ func generateTagsFromName(
 	opts models.TagOptions,
 	tags []models.Tag,
 ) (models.Tags, error) {
-	if len(name) == 0 {
+	path, taggedTags := name, []byte(nil)
+	if idx := bytes.IndexByte(name, graphiteTagSeparator); idx >= 0 {
+		path, taggedTags = name[:idx], name[idx+1:]
+	}
+
+	if len(path) == 0 {
 		return models.EmptyTags(), errCannotGenerateTagsFromEmptyName
 	}
 
-	numTags := bytes.Count(name, carbonSeparatorBytes) + 1
+	numTags := bytes.Count(path, carbonSeparatorBytes) + 1
+	if taggedTags != nil {
+		numTags += bytes.Count(taggedTags, graphiteTagSeparatorBytes) + 1
+	}
 
 	if cap(tags) >= numTags {
 		tags = tags[:0]
@@ -512,16 +542,16 @@ func generateTagsFromName(
 
 	startIdx := 0
 	tagNum := 0
-	for i, charByte := range name {
+	for i, charByte := range path {
 		if charByte == carbonSeparatorByte {
-			if i+1 < len(name) && name[i+1] == carbonSeparatorByte {
+			if i+1 < len(path) && path[i+1] == carbonSeparatorByte {
 				return models.EmptyTags(),
 					fmt.Errorf("carbon metric: %s has duplicate separator", string(name))
 			}
 
 			tags = append(tags, models.Tag{
 				Name:  graphite.TagName(tagNum),
-				Value: name[startIdx:i],
+				Value: path[startIdx:i],
 			})
 			startIdx = i + 1
 			tagNum++
@@ -537,16 +567,87 @@ func generateTagsFromName(
 	// append baz, however, if the input was:
 	//      foo.bar.baz.
 	// then the foor loop would have appended foo, bar, and baz already.
-	if name[len(name)-1] != carbonSeparatorByte {
+	if path[len(path)-1] != carbonSeparatorByte {
 		tags = append(tags, models.Tag{
 			Name:  graphite.TagName(tagNum),
-			Value: name[startIdx:],
+			Value: path[startIdx:],
 		})
 	}
 
+	if taggedTags == nil {
+		return models.Tags{Opts: opts, Tags: tags}, nil
+	}
+
+	tags, err := appendTaggedTags(name, taggedTags, tags)
+	if err != nil {
+		return models.EmptyTags(), err
+	}
+
+	if opts.IDSchemeType() == models.TypeGraphite {
+		opts = opts.SetIDSchemeType(models.TypeGraphiteTagged)
+	}
+
 	return models.Tags{Opts: opts, Tags: tags}, nil
 }
 
+// appendTaggedTags appends the tags of a tagged carbon metric name, following
+// the graphite rules for tags: tag names must not be empty and must not
+// contain any of ";!^=", tag values must not be empty and must not start
+// with "~". Tags are ordered lexically after the path tags, the last value
+// wins if a tag is repeated, and a "name" tag is ignored since the name of a
+// tagged series is always its path.
+func appendTaggedTags(
+	name []byte,
+	taggedTags []byte,
+	tags []models.Tag,
+) ([]models.Tag, error) {
+	numPathTags := len(tags)
+	for len(taggedTags) > 0 {
+		tag := taggedTags
+		if idx := bytes.IndexByte(taggedTags, graphiteTagSeparator); idx >= 0 {
+			tag, taggedTags = taggedTags[:idx], taggedTags[idx+1:]
+		} else {
+			taggedTags = nil
+		}
+
+		idx := bytes.IndexByte(tag, graphiteTagValueSeparator)
+		if idx <= 0 || idx == len(tag)-1 {
+			return nil, fmt.Errorf("carbon metric: %s has invalid tag: %s",
+				string(name), string(tag))
+		}
+
+		tagName, tagValue := tag[:idx], tag[idx+1:]
+		if bytes.ContainsAny(tagName, "!^") || tagValue[0] == '~' {
+			return nil, fmt.Errorf("carbon metric: %s has invalid tag: %s",
+				string(name), string(tag))
+		}
+
+		if bytes.Equal(tagName, graphiteNameTag) {
+			continue
+		}
+
+		tags = append(tags, models.Tag{Name: tagName, Value: tagValue})
+	}
+
+	taggedTagsSlice := tags[numPathTags:]
+	sort.SliceStable(taggedTagsSlice, func(i, j int) bool {
+		return bytes.Compare(taggedTagsSlice[i].Name, taggedTagsSlice[j].Name) < 0
+	})
+
+	// Remove repeated tags, keeping the last value of each.
+	n := numPathTags
+	for i, tag := range taggedTagsSlice {
+		if i+1 < len(taggedTagsSlice) &&
+			bytes.Equal(tag.Name, taggedTagsSlice[i+1].Name) {
+			continue
+		}
+		tags[n] = tag
+		n++
+	}
+
+	return tags[:n], nil
+}
+
 // Compile all the carbon ingestion rules into matcher so that we can
 // perform matching. Also, generate all the mapping rules and storage
 // policies that we will need to pass to the DownsamplerAndWriter upfront
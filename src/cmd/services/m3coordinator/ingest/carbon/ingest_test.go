@@ -30,6 +30,7 @@ import (
 	"net"
 	"reflect"
 	"sort"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -604,6 +605,45 @@ func TestGenerateTagsFromName(t *testing.T) {
 			expectedErr:  fmt.Errorf("carbon metric: foo.bar.baz.. has duplicate separator"),
 			expectedTags: []models.Tag{},
 		},
+		{
+			name: "foo.bar;env=prod;dc=east",
+			id:   "foo.bar;dc=east;env=prod",
+			expectedTags: []models.Tag{
+				{Name: graphite.TagName(0), Value: []byte("foo")},
+				{Name: graphite.TagName(1), Value: []byte("bar")},
+				{Name: []byte("dc"), Value: []byte("east")},
+				{Name: []byte("env"), Value: []byte("prod")},
+			},
+		},
+		{
+			name: "foo;env=prod;name=bar;env=dev;a=b=c",
+			id:   "foo;a=b=c;env=dev",
+			expectedTags: []models.Tag{
+				{Name: graphite.TagName(0), Value: []byte("foo")},
+				{Name: []byte("a"), Value: []byte("b=c")},
+				{Name: []byte("env"), Value: []byte("dev")},
+			},
+		},
+		{
+			name:         "foo;env=",
+			expectedErr:  fmt.Errorf("carbon metric: foo;env= has invalid tag: env="),
+			expectedTags: []models.Tag{},
+		},
+		{
+			name:         "foo;env!=prod",
+			expectedErr:  fmt.Errorf("carbon metric: foo;env!=prod has invalid tag: env!=prod"),
+			expectedTags: []models.Tag{},
+		},
+		{
+			name:         "foo;env=~prod",
+			expectedErr:  fmt.Errorf("carbon metric: foo;env=~prod has invalid tag: env=~prod"),
+			expectedTags: []models.Tag{},
+		},
+		{
+			name:         ";env=prod",
+			expectedErr:  errCannotGenerateTagsFromEmptyName,
+			expectedTags: []models.Tag{},
+		},
 	}
 
 	opts := models.NewTagOptions().SetIDSchemeType(models.TypeGraphite)
@@ -614,6 +654,12 @@ func TestGenerateTagsFromName(t *testing.T) {
 		} else {
 			require.NoError(t, err)
 			assert.Equal(t, []byte(tc.id), tags.ID())
+
+			scheme := models.TypeGraphite
+			if strings.Contains(tc.name, ";") {
+				scheme = models.TypeGraphiteTagged
+			}
+			assert.Equal(t, scheme, tags.Opts.IDSchemeType())
 		}
 		require.Equal(t, tc.expectedTags, tags.Tags)
 	}
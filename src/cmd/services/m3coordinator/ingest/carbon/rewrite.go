@@ -21,6 +21,8 @@
 package ingestcarbon
 
 import (
+	"bytes"
+
 	"github.com/m3db/m3/src/cmd/services/m3query/config"
 )
 
@@ -34,6 +36,13 @@ func copyAndRewrite(
 		return append(dst[:0], src...)
 	}
 
+	// Only the path of tagged metrics (i.e. path;tag=value) is rewritten,
+	// the tags are kept as is.
+	var tags []byte
+	if idx := bytes.IndexByte(src, graphiteTagSeparator); idx >= 0 {
+		src, tags = src[:idx], src[idx:]
+	}
+
 	// Copy into dst as we rewrite.
 	dst = dst[:0]
 	leadingDots := true
@@ -84,5 +93,5 @@ func copyAndRewrite(
 		// Remove trailing dot.
 		dst = dst[:i]
 	}
-	return dst
+	return append(dst, tags...)
 }
@@ -65,6 +65,14 @@ func TestCopyAndRewrite(t *testing.T) {
 				Cleanup: true,
 			},
 		},
+		{
+			name:     "tagged with rewrite cleanup",
+			input:    "foo$$.bar.;env=prod$$",
+			expected: "foo_.bar;env=prod$$",
+			cfg: &config.CarbonIngesterRewriteConfiguration{
+				Cleanup: true,
+			},
+		},
 		{
 			name:     "collapse two dots with rewrite cleanup",
 			input:    "foo..bar.baz",
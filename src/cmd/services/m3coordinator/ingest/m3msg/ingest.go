@@ -24,8 +24,10 @@ import (
 	"bytes"
 	"context"
 
+	"github.com/m3db/m3/src/aggregator/aggregation/quantile/ddsketch"
 	"github.com/m3db/m3/src/cmd/services/m3coordinator/downsample"
 	"github.com/m3db/m3/src/cmd/services/m3coordinator/server/m3msg"
+	"github.com/m3db/m3/src/metrics/metric"
 	"github.com/m3db/m3/src/metrics/metric/id"
 	"github.com/m3db/m3/src/metrics/policy"
 	"github.com/m3db/m3/src/query/models"
@@ -62,6 +64,7 @@ type ingestMetrics struct {
 	ingestInternalError     tally.Counter
 	ingestNonRetryableError tally.Counter
 	ingestSuccess           tally.Counter
+	ingestInvalidSketch     tally.Counter
 }
 
 func newIngestMetrics(scope tally.Scope) ingestMetrics {
@@ -72,7 +75,8 @@ func newIngestMetrics(scope tally.Scope) ingestMetrics {
 		ingestNonRetryableError: scope.Tagged(map[string]string{
 			"error_type": "non_retryable_error",
 		}).Counter("ingest-error"),
-		ingestSuccess: scope.Counter("ingest-success"),
+		ingestSuccess:       scope.Counter("ingest-success"),
+		ingestInvalidSketch: scope.Counter("ingest-invalid-sketch"),
 	}
 }
 
@@ -213,7 +217,8 @@ func (op *ingestOp) attempt() error {
 }
 
 func (op *ingestOp) resetWriteQuery() error {
-	if err := op.resetTags(); err != nil {
+	annotation, isHistogram := op.resetAnnotation()
+	if err := op.resetTags(isHistogram); err != nil {
 		return err
 	}
 	op.resetDataPoints()
@@ -226,11 +231,31 @@ func (op *ingestOp) resetWriteQuery() error {
 			Resolution:  op.sp.Resolution().Window,
 			Retention:   op.sp.Retention().Duration(),
 		},
-		Annotation: op.annotation,
+		Annotation: annotation,
 	})
 }
 
-func (op *ingestOp) resetTags() error {
+// resetAnnotation returns the annotation to write, converting the encoded
+// sketch of a timer aggregated with sketches to a native histogram so that
+// any quantile of the timer can be computed at query time.
+func (op *ingestOp) resetAnnotation() ([]byte, bool) {
+	if !ddsketch.IsEncoded(op.annotation) {
+		return op.annotation, false
+	}
+	annotation, err := storage.SketchAnnotationToHistogramAnnotation(
+		xtime.UnixNano(op.metricNanos), op.annotation)
+	if err != nil {
+		// NB: the count of the timer is still written, without its sketch.
+		op.m.ingestInvalidSketch.Inc(1)
+		if op.sample() {
+			op.logger.Error("could not decode sketch", zap.Error(err))
+		}
+		return nil, false
+	}
+	return annotation, true
+}
+
+func (op *ingestOp) resetTags(isHistogram bool) error {
 	op.it.Reset(op.id)
 	op.tags.Tags = op.tags.Tags[:0]
 	op.tags.Opts = op.tagOpts
@@ -242,12 +267,12 @@ func (op *ingestOp) resetTags() error {
 		// other path where flows back to the coordinator from the aggregator
 		// and this tag is interpreted, eventually need to handle more cleanly.
 		if bytes.Equal(name, downsample.MetricsOptionIDSchemeTagName) {
-			if bytes.Equal(value, downsample.GraphiteIDSchemeTagValue) &&
-				op.tags.Opts.IDSchemeType() != models.TypeGraphite {
+			if scheme, ok := downsample.IDSchemeFromTagValue(value); ok &&
+				op.tags.Opts.IDSchemeType() != scheme {
 				// Restart iteration with graphite tag options parsing
 				op.it.Reset(op.id)
 				op.tags.Tags = op.tags.Tags[:0]
-				op.tags.Opts = op.tags.Opts.SetIDSchemeType(models.TypeGraphite)
+				op.tags.Opts = op.tags.Opts.SetIDSchemeType(scheme)
 			}
 			// Continue, whether we updated and need to restart iteration,
 			// or if passing for the second time
@@ -259,6 +284,12 @@ func (op *ingestOp) resetTags() error {
 			Value: value,
 		}.Clone())
 	}
+	if isHistogram {
+		op.tags = op.tags.AddTagWithoutNormalizing(models.Tag{
+			Name:  metric.M3PromNativeHistogramTag,
+			Value: metric.PromNativeHistogramEncodedValue,
+		})
+	}
 	op.tags.Normalize()
 	return op.it.Err()
 }
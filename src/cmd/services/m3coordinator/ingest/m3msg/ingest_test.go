@@ -27,8 +27,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/m3db/m3/src/aggregator/aggregation/quantile/ddsketch"
 	"github.com/m3db/m3/src/cmd/services/m3coordinator/server/m3msg"
 	"github.com/m3db/m3/src/metrics/encoding/protobuf"
+	"github.com/m3db/m3/src/metrics/metric"
 	"github.com/m3db/m3/src/metrics/policy"
 	"github.com/m3db/m3/src/msg/consumer"
 	"github.com/m3db/m3/src/query/models"
@@ -114,6 +116,80 @@ func TestIngest(t *testing.T) {
 	require.Equal(t, id, op.id)
 }
 
+func TestIngestSketch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := Configuration{
+		WorkerPoolSize: 2,
+		OpPool: pool.ObjectPoolConfiguration{
+			Size: 1,
+		},
+	}
+	appender := &mockAppender{}
+	ingester, err := cfg.NewIngester(appender, models.NewTagOptions(),
+		instrument.NewOptions())
+	require.NoError(t, err)
+
+	sketch := ddsketch.NewSketch(ddsketch.NewOptions())
+	sketch.AddBatch([]float64{1, 2, 3})
+
+	id := newTestID(t, "__name__", "foo", "app", "bar")
+	metricNanos := int64(1234)
+	val := float64(3)
+	sp := policy.MustParseStoragePolicy("1m:40d")
+	m := consumer.NewMockMessage(ctrl)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	callback := m3msg.NewProtobufCallback(m, protobuf.NewAggregatedDecoder(nil), &wg)
+
+	m.EXPECT().Ack()
+	ingester.Ingest(context.TODO(), id, metricNanos, 0, val, sketch.Encode(), sp, callback)
+
+	for appender.cnt() != 1 {
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	// The sketch is written as a native histogram.
+	annotation, err := storage.SketchAnnotationToHistogramAnnotation(
+		xtime.UnixNano(metricNanos), sketch.Encode())
+	require.NoError(t, err)
+	expected, err := storage.NewWriteQuery(storage.WriteQueryOptions{
+		Annotation: annotation,
+		Attributes: storagemetadata.Attributes{
+			MetricsType: storagemetadata.AggregatedMetricsType,
+			Resolution:  time.Minute,
+			Retention:   40 * 24 * time.Hour,
+		},
+		Datapoints: ts.Datapoints{
+			ts.Datapoint{
+				Timestamp: xtime.UnixNano(metricNanos),
+				Value:     val,
+			},
+		},
+		Tags: models.NewTags(3, nil).AddTags(
+			[]models.Tag{
+				{
+					Name:  metric.M3PromNativeHistogramTag,
+					Value: metric.PromNativeHistogramEncodedValue,
+				},
+				{
+					Name:  []byte("__name__"),
+					Value: []byte("foo"),
+				},
+				{
+					Name:  []byte("app"),
+					Value: []byte("bar"),
+				},
+			},
+		),
+		Unit: xtime.Second,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, *expected, *appender.received[0])
+}
+
 func TestIngestNonRetryableError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
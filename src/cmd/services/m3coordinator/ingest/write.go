@@ -244,7 +244,8 @@ func (d *downsamplerAndWriter) writeToDownsampler(
 		appender.AddTag(tag.Name, tag.Value)
 	}
 
-	if tags.Opts.IDSchemeType() == models.TypeGraphite {
+	schemeValue := downsample.IDSchemeTagValue(tags.Opts.IDSchemeType())
+	if schemeValue != nil {
 		// NB(r): This is gross, but if this is a graphite metric then
 		// we are going to set a special tag that means the downsampler
 		// will write a graphite ID. This should really be plumbed
@@ -255,8 +256,7 @@ func (d *downsamplerAndWriter) writeToDownsampler(
 		// all places worth fixing this hack. There is at least one
 		// other path where flows back to the coordinator from the aggregator
 		// and this tag is interpreted, eventually need to handle more cleanly.
-		appender.AddTag(downsample.MetricsOptionIDSchemeTagName,
-			downsample.GraphiteIDSchemeTagValue)
+		appender.AddTag(downsample.MetricsOptionIDSchemeTagName, schemeValue)
 	}
 
 	// NB: we don't set series attributes on the sample appender options here.
@@ -466,7 +466,8 @@ func (d *downsamplerAndWriter) writeAggregatedBatch(
 			appender.AddTag(tag.Name, tag.Value)
 		}
 
-		if value.Tags.Opts.IDSchemeType() == models.TypeGraphite {
+		schemeValue := downsample.IDSchemeTagValue(value.Tags.Opts.IDSchemeType())
+		if schemeValue != nil {
 			// NB(r): This is gross, but if this is a graphite metric then
 			// we are going to set a special tag that means the downsampler
 			// will write a graphite ID. This should really be plumbed
@@ -477,8 +478,7 @@ func (d *downsamplerAndWriter) writeAggregatedBatch(
 			// all places worth fixing this hack. There is at least one
 			// other path where flows back to the coordinator from the aggregator
 			// and this tag is interpreted, eventually need to handle more cleanly.
-			appender.AddTag(downsample.MetricsOptionIDSchemeTagName,
-				downsample.GraphiteIDSchemeTagValue)
+			appender.AddTag(downsample.MetricsOptionIDSchemeTagName, schemeValue)
 		}
 
 		opts := downsample.SampleAppenderOptions{
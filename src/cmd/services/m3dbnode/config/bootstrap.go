@@ -23,13 +23,16 @@ package config
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/m3db/m3/src/dbnode/client"
 	"github.com/m3db/m3/src/dbnode/persist/fs"
+	fsbackup "github.com/m3db/m3/src/dbnode/persist/fs/backup"
 	"github.com/m3db/m3/src/dbnode/persist/fs/migration"
 	"github.com/m3db/m3/src/dbnode/storage"
 	"github.com/m3db/m3/src/dbnode/storage/bootstrap"
 	"github.com/m3db/m3/src/dbnode/storage/bootstrap/bootstrapper"
+	"github.com/m3db/m3/src/dbnode/storage/bootstrap/bootstrapper/backup"
 	"github.com/m3db/m3/src/dbnode/storage/bootstrap/bootstrapper/commitlog"
 	bfs "github.com/m3db/m3/src/dbnode/storage/bootstrap/bootstrapper/fs"
 	"github.com/m3db/m3/src/dbnode/storage/bootstrap/bootstrapper/peers"
@@ -39,6 +42,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/storage/index/compaction"
 	"github.com/m3db/m3/src/dbnode/topology"
 	"github.com/m3db/m3/src/m3ninx/index/segment/fst"
+	xtime "github.com/m3db/m3/src/x/time"
 )
 
 var (
@@ -82,6 +86,8 @@ var (
 	}
 
 	errReadBootstrapModeInvalid = errors.New("bootstrap mode invalid")
+
+	errBackupBootstrapperNotConfigured = errors.New("backup bootstrapper not configured")
 )
 
 // BootstrapMode defines the mode in which bootstrappers are run.
@@ -146,6 +152,10 @@ type BootstrapConfiguration struct {
 	// Peers bootstrapper configuration.
 	Peers *BootstrapPeersConfiguration `yaml:"peers"`
 
+	// Backup configures restoring filesets from backups, if set the backup
+	// bootstrapper runs before all other bootstrappers.
+	Backup *BootstrapBackupConfiguration `yaml:"backup"`
+
 	// CacheSeriesMetadata determines whether individual bootstrappers cache
 	// series metadata across all calls (namespaces / shards / blocks).
 	CacheSeriesMetadata *bool `yaml:"cacheSeriesMetadata"`
@@ -262,6 +272,20 @@ type BootstrapPeersConfiguration struct {
 	StreamPersistShardFlushConcurrency *int `yaml:"streamPersistShardFlushConcurrency"`
 }
 
+// BootstrapBackupConfiguration specifies config for the backup bootstrapper.
+type BootstrapBackupConfiguration struct {
+	// Store configures the store backups are restored from.
+	Store fsbackup.StoreConfiguration `yaml:"store"`
+
+	// RestoreTime selects the latest backup taken at or before the time to
+	// restore, if unset the latest backup is restored.
+	RestoreTime *time.Time `yaml:"restoreTime"`
+
+	// Namespaces are the namespaces to restore, if empty all namespaces are
+	// restored.
+	Namespaces []string `yaml:"namespaces"`
+}
+
 // New creates a bootstrap process based on the bootstrap configuration.
 func (bsc BootstrapConfiguration) New(
 	rsOpts result.Options,
@@ -374,6 +398,26 @@ func (bsc BootstrapConfiguration) New(
 			if err != nil {
 				return nil, err
 			}
+		case backup.BackupBootstrapperName:
+			bCfg := bsc.Backup
+			if bCfg == nil {
+				return nil, errBackupBootstrapperNotConfigured
+			}
+			backupOpts, err := bCfg.Store.NewOptions(fsOpts, opts.InstrumentOptions())
+			if err != nil {
+				return nil, err
+			}
+			bOpts := backup.NewOptions().
+				SetResultOptions(rsOpts).
+				SetBackupOptions(backupOpts).
+				SetNamespaces(bCfg.Namespaces)
+			if bCfg.RestoreTime != nil {
+				bOpts = bOpts.SetRestoreTime(xtime.ToUnixNano(*bCfg.RestoreTime))
+			}
+			bs, err = backup.NewBackupBootstrapperProvider(bOpts, bs)
+			if err != nil {
+				return nil, err
+			}
 		case uninitialized.UninitializedTopologyBootstrapperName:
 			uOpts := uninitialized.NewOptions().
 				SetResultOptions(rsOpts).
@@ -418,6 +462,16 @@ func (bsc BootstrapConfiguration) peersConfig() BootstrapPeersConfiguration {
 }
 
 func (bsc BootstrapConfiguration) orderedBootstrappers() []string {
+	ordered := bsc.modeOrderedBootstrappers()
+	if bsc.Backup == nil {
+		return ordered
+	}
+	// Restoring from backups must precede filesystem bootstrapping which
+	// loads the restored filesets.
+	return append([]string{backup.BackupBootstrapperName}, ordered...)
+}
+
+func (bsc BootstrapConfiguration) modeOrderedBootstrappers() []string {
 	if bsc.BootstrapMode != nil {
 		switch *bsc.BootstrapMode {
 		case DefaultBootstrapMode:
@@ -38,6 +38,9 @@ import (
 	"github.com/m3db/m3/src/dbnode/client"
 	"github.com/m3db/m3/src/dbnode/discovery"
 	"github.com/m3db/m3/src/dbnode/environment"
+	"github.com/m3db/m3/src/dbnode/persist/compression"
+	fsbackup "github.com/m3db/m3/src/dbnode/persist/fs/backup"
+	"github.com/m3db/m3/src/dbnode/storage/index/compaction"
 	"github.com/m3db/m3/src/dbnode/storage/repair"
 	"github.com/m3db/m3/src/dbnode/storage/series"
 	"github.com/m3db/m3/src/x/config/hostid"
@@ -154,6 +157,10 @@ type DBConfiguration struct {
 	// The host and port on which to listen for debug endpoints.
 	DebugListenAddress *string `yaml:"debugListenAddress"`
 
+	// The host and port on which to listen for the gRPC node service which
+	// streams reads to clients, the service is disabled if not set.
+	GRPCNodeListenAddress *string `yaml:"grpcNodeListenAddress"`
+
 	// HostID is the local host ID configuration.
 	HostID *hostid.Configuration `yaml:"hostID"`
 
@@ -226,6 +233,12 @@ type DBConfiguration struct {
 	// ForceColdWritesEnabled will force enable cold writes for all namespaces
 	// if set.
 	ForceColdWritesEnabled *bool `yaml:"forceColdWritesEnabled"`
+
+	// Exemplars configures how exemplars written to the node are stored.
+	Exemplars *ExemplarsConfiguration `yaml:"exemplars"`
+
+	// Backup configures periodic backups of the sealed filesets of the node.
+	Backup *fsbackup.Configuration `yaml:"backup"`
 }
 
 // LoggingOrDefault returns the logging configuration or defaults.
@@ -429,6 +442,60 @@ type IndexConfiguration struct {
 	// block boundaries by eagerly writing the series to the next block
 	// preemptively.
 	ForwardIndexThreshold float64 `yaml:"forwardIndexThreshold" validate:"min=0.0,max=1.0"`
+
+	// PersistedCompaction configures the background compaction of index
+	// filesets that have already been flushed to disk for cold blocks. Only
+	// the volumes of a single index block are merged together.
+	PersistedCompaction *IndexPersistedCompactionConfiguration `yaml:"persistedCompaction"`
+}
+
+// IndexPersistedCompactionConfiguration configures the size-tiered compaction
+// of persisted index volumes.
+type IndexPersistedCompactionConfiguration struct {
+	// Enabled determines whether persisted index volumes are compacted.
+	Enabled bool `yaml:"enabled"`
+
+	// MinBlockAge is the minimum time since the end of an index block before
+	// its persisted volumes are compacted.
+	MinBlockAge *time.Duration `yaml:"minBlockAge"`
+
+	// MinVolumesPerTask is the minimum number of volumes within a tier
+	// required to compact them together.
+	MinVolumesPerTask *int `yaml:"minVolumesPerTask" validate:"omitempty,min=2"`
+
+	// Tiers are the size tiers, in bytes on disk, volumes are grouped by.
+	Tiers []IndexCompactionTierConfiguration `yaml:"tiers"`
+}
+
+// IndexCompactionTierConfiguration is a size tier for index compaction.
+type IndexCompactionTierConfiguration struct {
+	// MinSize is the inclusive minimum size of the tier.
+	MinSize int64 `yaml:"minSize" validate:"min=0"`
+
+	// MaxSize is the exclusive maximum size of the tier.
+	MaxSize int64 `yaml:"maxSize" validate:"min=1"`
+}
+
+// PlannerOptions returns the persisted compaction planner options.
+func (c IndexPersistedCompactionConfiguration) PlannerOptions() compaction.PersistedPlannerOptions {
+	opts := compaction.DefaultPersistedOptions
+	opts.Enabled = c.Enabled
+	if c.MinBlockAge != nil {
+		opts.MinBlockAge = *c.MinBlockAge
+	}
+	if c.MinVolumesPerTask != nil {
+		opts.MinVolumesPerTask = *c.MinVolumesPerTask
+	}
+	if len(c.Tiers) > 0 {
+		opts.Levels = make([]compaction.Level, 0, len(c.Tiers))
+		for _, tier := range c.Tiers {
+			opts.Levels = append(opts.Levels, compaction.Level{
+				MinSizeInclusive: tier.MinSize,
+				MaxSizeExclusive: tier.MaxSize,
+			})
+		}
+	}
+	return opts
 }
 
 // RegexpDFALimitOrDefault returns the deterministic finite automaton states
@@ -522,6 +589,10 @@ type CommitLogPolicy struct {
 	// works in most cases because the default size of the QueueChannel should be large
 	// enough for almost all workloads assuming a reasonable batch size is used.
 	QueueChannel *CommitLogQueuePolicy `yaml:"queueChannel"`
+
+	// Compression is the compression applied to each chunk of the commit log,
+	// disabled if not set.
+	Compression compression.Type `yaml:"compression"`
 }
 
 // CalculationType is a type of configuration parameter.
@@ -569,6 +640,10 @@ type RepairPolicy struct {
 	// Concurrency sets the repair shard concurrency if set.
 	Concurrency int `yaml:"concurrency"`
 
+	// MerkleTreeDepth sets the depth of the merkle trees compared with peers
+	// when using the merkle repair type if set.
+	MerkleTreeDepth int `yaml:"merkleTreeDepth"`
+
 	// Whether debug shadow comparisons are enabled.
 	DebugShadowComparisonsEnabled bool `yaml:"debugShadowComparisonsEnabled"`
 
@@ -645,6 +720,17 @@ type WideConfiguration struct {
 	BatchSize int `yaml:"batchSize"`
 }
 
+// ExemplarsConfiguration is the configuration for storing exemplars.
+type ExemplarsConfiguration struct {
+	// MaxPerSeries is the number of most recent exemplars kept for each
+	// series, setting it to zero drops all exemplars.
+	MaxPerSeries *int `yaml:"maxPerSeries" validate:"min=0"`
+
+	// Persist enables persisting the exemplars of a block when it is
+	// flushed so that they are loaded again on bootstrap.
+	Persist bool `yaml:"persist"`
+}
+
 // NamespaceProtoSchema is the namespace protobuf schema.
 type NamespaceProtoSchema struct {
 	// For application m3db client integration test convenience (where a local dbnode is started as a docker container),
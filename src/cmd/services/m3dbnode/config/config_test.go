@@ -343,6 +343,7 @@ func TestConfiguration(t *testing.T) {
     regexpFSALimit: null
     forwardIndexProbability: 0
     forwardIndexThreshold: 0
+    persistedCompaction: null
   transforms:
     truncateBy: 0
     forceValue: null
@@ -368,6 +369,7 @@ func TestConfiguration(t *testing.T) {
   httpNodeListenAddress: 0.0.0.0:9002
   httpClusterListenAddress: 0.0.0.0:9003
   debugListenAddress: 0.0.0.0:9004
+  grpcNodeListenAddress: null
   hostID:
     resolver: config
     value: host1
@@ -410,6 +412,7 @@ func TestConfiguration(t *testing.T) {
     fetchSeriesBlocksBatchSize: null
     writeShardsInitializing: null
     shardsLeavingCountTowardsConsistency: null
+    streamingReads: null
   gcPercentage: 100
   tick: null
   bootstrap:
@@ -420,6 +423,7 @@ func TestConfiguration(t *testing.T) {
     commitlog:
       returnUnfulfilledForCorruptCommitLogFiles: false
     peers: null
+    backup: null
     cacheSeriesMetadata: null
     indexSegmentConcurrency: null
     verify: null
@@ -434,6 +438,7 @@ func TestConfiguration(t *testing.T) {
     regexp: null
   filesystem:
     filePathPrefix: /var/lib/m3db
+    secondaryTierFilePathPrefix: null
     writeBufferSize: 65536
     dataReadBufferSize: 65536
     infoReadBufferSize: 128
@@ -446,6 +451,7 @@ func TestConfiguration(t *testing.T) {
     force_index_summaries_mmap_memory: true
     force_bloom_filter_mmap_memory: true
     bloomFilterFalsePositivePercent: null
+    encryption: null
   commitlog:
     flushMaxBytes: 524288
     flushEvery: 1s
@@ -453,6 +459,7 @@ func TestConfiguration(t *testing.T) {
       calculationType: fixed
       size: 2097152
     queueChannel: null
+    compression: none
   repair:
     enabled: false
     type: 0
@@ -461,6 +468,7 @@ func TestConfiguration(t *testing.T) {
     throttle: 2m0s
     checkInterval: 1m0s
     concurrency: 0
+    merkleTreeDepth: 0
     debugShadowComparisonsEnabled: false
     debugShadowComparisonsPercentage: 0
   replication: null
@@ -751,12 +759,16 @@ func TestConfiguration(t *testing.T) {
     maxOutstandingRepairedBytes: 0
     maxEncodersPerBlock: 0
     writeNewSeriesPerSecond: 0
+    tenantQuotas: null
+    labelCardinality: null
   wide: null
   tchannel: null
   debug:
     mutexProfileFraction: 0
     blockProfileRate: 0
   forceColdWritesEnabled: null
+  exemplars: null
+  backup: null
 coordinator: null
 `
 
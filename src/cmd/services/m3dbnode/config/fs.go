@@ -23,6 +23,8 @@ package config
 import (
 	"fmt"
 	"os"
+
+	"github.com/m3db/m3/src/dbnode/persist/encryption"
 )
 
 const (
@@ -58,6 +60,10 @@ type FilesystemConfiguration struct {
 	// File path prefix for reading/writing TSDB files
 	FilePathPrefix *string `yaml:"filePathPrefix"`
 
+	// File path prefix of the secondary storage tier that the data and index
+	// files of old data filesets are offloaded to, disabled if not set.
+	SecondaryTierFilePathPrefix *string `yaml:"secondaryTierFilePathPrefix"`
+
 	// Write buffer size
 	WriteBufferSize *int `yaml:"writeBufferSize"`
 
@@ -98,6 +104,10 @@ type FilesystemConfiguration struct {
 	// BloomFilterFalsePositivePercent controls the target false positive percentage
 	// for the bloom filters for the fileset files.
 	BloomFilterFalsePositivePercent *float64 `yaml:"bloomFilterFalsePositivePercent"`
+
+	// Encryption configures the encryption at rest of commit logs and the
+	// data files of filesets.
+	Encryption *encryption.Configuration `yaml:"encryption"`
 }
 
 // Validate validates the Filesystem configuration. We use this method to validate
@@ -158,6 +168,16 @@ func (f FilesystemConfiguration) FilePathPrefixOrDefault() string {
 	return defaultFilePathPrefix
 }
 
+// SecondaryTierFilePathPrefixOrDefault returns the configured secondary tier
+// file path prefix if configured, or an empty prefix otherwise.
+func (f FilesystemConfiguration) SecondaryTierFilePathPrefixOrDefault() string {
+	if f.SecondaryTierFilePathPrefix != nil {
+		return *f.SecondaryTierFilePathPrefix
+	}
+
+	return ""
+}
+
 // WriteBufferSizeOrDefault returns the configured write buffer size if configured, or a
 // default value otherwise.
 func (f FilesystemConfiguration) WriteBufferSizeOrDefault() int {
@@ -20,7 +20,11 @@
 
 package config
 
-import "time"
+import (
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/storage/limits"
+)
 
 // LimitsConfiguration contains configuration for configurable limits that can be applied to M3DB.
 type LimitsConfiguration struct {
@@ -72,6 +76,15 @@ type LimitsConfiguration struct {
 
 	// Write new series limit per second to limit overwhelming during new ID bursts.
 	WriteNewSeriesPerSecond int `yaml:"writeNewSeriesPerSecond" validate:"min=0"`
+
+	// TenantQuotas sets quotas on the writes and queries of each tenant so that
+	// a single tenant cannot exhaust the resources of a dbnode.
+	TenantQuotas *TenantQuotasConfiguration `yaml:"tenantQuotas"`
+
+	// LabelCardinality limits the distinct values each label may have within
+	// an index block of a namespace, so that a single label with unbounded
+	// values cannot blow up the index.
+	LabelCardinality *LabelCardinalityConfiguration `yaml:"labelCardinality"`
 }
 
 // MaxRecentQueryResourceLimitConfiguration sets an upper limit on resources consumed by all queries
@@ -83,3 +96,117 @@ type MaxRecentQueryResourceLimitConfiguration struct {
 	// Lookback is the period in which a given resource limit is enforced.
 	Lookback time.Duration `yaml:"lookback" validate:"min=0"`
 }
+
+// TenantQuotasConfiguration sets the quotas of each tenant. Writes are
+// attributed to the tenant set as the value of the tenant tag of the series
+// written and queries to the tenant set as the source of the query.
+type TenantQuotasConfiguration struct {
+	// TenantTag is the name of the tag identifying the tenant of a series.
+	TenantTag string `yaml:"tenantTag" validate:"nonzero"`
+
+	// Default is the quota of each tenant without a quota of its own.
+	Default TenantQuotaConfiguration `yaml:"default"`
+
+	// Tenants sets the quotas of specific tenants keyed by tenant.
+	Tenants map[string]TenantQuotaConfiguration `yaml:"tenants"`
+}
+
+// TenantQuotaConfiguration sets the quotas of a tenant, unset or zero
+// quotas are not enforced.
+type TenantQuotaConfiguration struct {
+	// MaxSeriesCreatedPerSecond sets the upper limit on new series created
+	// by the tenant each second.
+	MaxSeriesCreatedPerSecond int64 `yaml:"maxSeriesCreatedPerSecond" validate:"min=0"`
+
+	// MaxDatapointsWrittenPerSecond sets the upper limit on datapoints written
+	// by the tenant each second.
+	MaxDatapointsWrittenPerSecond int64 `yaml:"maxDatapointsWrittenPerSecond" validate:"min=0"`
+
+	// MaxRecentlyQueriedSeriesBlocks sets the upper limit on time series blocks
+	// matched by queries of the tenant within a given lookback period.
+	MaxRecentlyQueriedSeriesBlocks *MaxRecentQueryResourceLimitConfiguration `yaml:"maxRecentlyQueriedSeriesBlocks"`
+
+	// MaxRecentlyQueriedSeriesDiskBytesRead sets the upper limit on time series
+	// bytes read from disk by queries of the tenant within a given lookback period.
+	MaxRecentlyQueriedSeriesDiskBytesRead *MaxRecentQueryResourceLimitConfiguration `yaml:"maxRecentlyQueriedSeriesDiskBytesRead"`
+}
+
+// Options returns the tenant quotas options.
+func (c TenantQuotasConfiguration) Options() limits.TenantQuotasOptions {
+	opts := limits.TenantQuotasOptions{
+		Default: c.Default.Quota(),
+		Tenants: make(map[string]limits.TenantQuota, len(c.Tenants)),
+	}
+	for tenant, quota := range c.Tenants {
+		opts.Tenants[tenant] = quota.Quota()
+	}
+	return opts
+}
+
+// Quota returns the tenant quota.
+func (c TenantQuotaConfiguration) Quota() limits.TenantQuota {
+	quota := limits.TenantQuota{
+		SeriesCreatedPerSecond:     c.MaxSeriesCreatedPerSecond,
+		DatapointsWrittenPerSecond: c.MaxDatapointsWrittenPerSecond,
+	}
+	if limitConfig := c.MaxRecentlyQueriedSeriesBlocks; limitConfig != nil {
+		quota.DocsMatched.Limit = limitConfig.Value
+		quota.DocsMatched.Lookback = limitConfig.Lookback
+	}
+	if limitConfig := c.MaxRecentlyQueriedSeriesDiskBytesRead; limitConfig != nil {
+		quota.BytesRead.Limit = limitConfig.Value
+		quota.BytesRead.Lookback = limitConfig.Lookback
+	}
+	return quota
+}
+
+// LabelCardinalityConfiguration sets the label cardinality limits of each
+// namespace.
+type LabelCardinalityConfiguration struct {
+	// Default is the limit of each namespace without a limit of its own.
+	Default LabelCardinalityLimitConfiguration `yaml:"default"`
+
+	// Namespaces sets the limits of specific namespaces keyed by namespace.
+	Namespaces map[string]LabelCardinalityLimitConfiguration `yaml:"namespaces"`
+
+	// ExemptLabels are label names that are never tracked or limited.
+	ExemptLabels []string `yaml:"exemptLabels"`
+
+	// ExactValuesThreshold is the number of distinct values of a label
+	// tracked exactly before switching to an estimating sketch.
+	ExactValuesThreshold int `yaml:"exactValuesThreshold" validate:"min=0"`
+}
+
+// LabelCardinalityLimitConfiguration sets the label cardinality limit of a
+// namespace, an unset or zero limit is not enforced.
+type LabelCardinalityLimitConfiguration struct {
+	// MaxValuesPerLabel sets the upper limit on distinct values of each label
+	// within an index block.
+	MaxValuesPerLabel int64 `yaml:"maxValuesPerLabel" validate:"min=0"`
+
+	// Action is the action taken on label values beyond the limit, either
+	// reject to not index the series or strip to index it without the label.
+	Action limits.LabelCardinalityAction `yaml:"action"`
+}
+
+// Options returns the label cardinality options.
+func (c LabelCardinalityConfiguration) Options() limits.LabelCardinalityOptions {
+	opts := limits.LabelCardinalityOptions{
+		Default:              c.Default.Limit(),
+		Namespaces:           make(map[string]limits.LabelCardinalityLimit, len(c.Namespaces)),
+		ExemptLabels:         c.ExemptLabels,
+		ExactValuesThreshold: c.ExactValuesThreshold,
+	}
+	for namespace, limit := range c.Namespaces {
+		opts.Namespaces[namespace] = limit.Limit()
+	}
+	return opts
+}
+
+// Limit returns the label cardinality limit.
+func (c LabelCardinalityLimitConfiguration) Limit() limits.LabelCardinalityLimit {
+	return limits.LabelCardinalityLimit{
+		MaxValuesPerLabel: c.MaxValuesPerLabel,
+		Action:            c.Action,
+	}
+}
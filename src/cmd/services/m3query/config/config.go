@@ -31,8 +31,10 @@ import (
 	"github.com/m3db/m3/src/cmd/services/m3coordinator/server/m3msg"
 	"github.com/m3db/m3/src/metrics/aggregation"
 	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/handleroptions"
+	"github.com/m3db/m3/src/query/cache"
 	"github.com/m3db/m3/src/query/graphite/graphite"
 	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/ruler"
 	"github.com/m3db/m3/src/query/storage"
 	"github.com/m3db/m3/src/query/storage/m3"
 	"github.com/m3db/m3/src/query/storage/m3/consolidators"
@@ -165,6 +167,9 @@ type Configuration struct {
 	// WriteForwarding is the write forwarding options.
 	WriteForwarding WriteForwardingConfiguration `yaml:"writeForwarding"`
 
+	// OTLP is the OpenTelemetry metrics ingestion configuration.
+	OTLP OTLPConfiguration `yaml:"otlp"`
+
 	// Downsample configures how the metrics should be downsampled.
 	Downsample downsample.Configuration `yaml:"downsample"`
 
@@ -174,6 +179,9 @@ type Configuration struct {
 	// Carbon is the carbon configuration.
 	Carbon *CarbonConfiguration `yaml:"carbon"`
 
+	// Rules configures the evaluation of recording and alerting rules.
+	Rules *ruler.Configuration `yaml:"rules"`
+
 	// Middleware is middleware-specific configuration.
 	Middleware MiddlewareConfiguration `yaml:"middleware"`
 
@@ -244,6 +252,36 @@ type WriteForwardingConfiguration struct {
 	PromRemoteWrite handleroptions.PromWriteHandlerForwardingOptions `yaml:"promRemoteWrite"`
 }
 
+// OTLPConfiguration is the OpenTelemetry (OTLP) metrics ingestion configuration.
+type OTLPConfiguration struct {
+	// ResourceAttributes controls which resource attributes become tags.
+	ResourceAttributes OTLPAttributesConfiguration `yaml:"resourceAttributes"`
+
+	// ScopeAttributes controls which instrumentation scope attributes
+	// (scope name and version) become tags.
+	ScopeAttributes OTLPAttributesConfiguration `yaml:"scopeAttributes"`
+
+	// DeltaStaleness is how long delta temporality accumulators are kept
+	// without receiving new points before their running totals are reset.
+	// Running totals are held in memory by each coordinator, so all points of
+	// a delta temporality series must be sent to the same coordinator.
+	DeltaStaleness time.Duration `yaml:"deltaStaleness"`
+}
+
+// OTLPAttributesConfiguration is the tag promotion policy for a set of
+// OTLP attributes.
+type OTLPAttributesConfiguration struct {
+	// PromoteAll promotes every attribute to a tag unless it is dropped.
+	PromoteAll bool `yaml:"promoteAll"`
+
+	// Promote is the list of attribute keys promoted to tags when
+	// PromoteAll is not set.
+	Promote []string `yaml:"promote"`
+
+	// Drop is the list of attribute keys that are never promoted to tags.
+	Drop []string `yaml:"drop"`
+}
+
 // Filter is a query filter type.
 type Filter string
 
@@ -291,6 +329,13 @@ type QueryConfiguration struct {
 	// RequireSeriesEndpointStartEndTime requires requests to /series endpoint
 	// to specify a start and end time to prevent unbounded queries.
 	RequireSeriesEndpointStartEndTime bool `yaml:"requireSeriesEndpointStartEndTime"`
+	// ResultCache is an optional configuration that enables caching of
+	// range query results split into step-aligned extents.
+	ResultCache *cache.Configuration `yaml:"resultCache"`
+	// TileAverages evaluates avg_over_time in the M3 query engine as the sum
+	// over time divided by the count over time, fetching the series twice,
+	// so that averages over downsampled tiles are weighted by their samples.
+	TileAverages bool `yaml:"tileAverages"`
 }
 
 // TimeoutOrDefault returns the configured timeout or default value.
@@ -812,6 +857,10 @@ type TagOptionsConfiguration struct {
 
 	// AllowTagValueEmpty allows for empty tags to appear on series.
 	AllowTagValueEmpty bool `yaml:"allowTagValueEmpty"`
+	// NumericFields are tag names whose values are numeric, regexp matchers on
+	// these tags that match a range of integers are evaluated as numeric range
+	// index queries.
+	NumericFields []string `yaml:"numericFields"`
 }
 
 // TagFilter is a tag filter.
@@ -868,6 +917,14 @@ func TagOptionsFromConfig(cfg TagOptionsConfiguration) (models.TagOptions, error
 	opts = opts.SetAllowTagNameDuplicates(cfg.AllowTagNameDuplicates)
 	opts = opts.SetAllowTagValueEmpty(cfg.AllowTagValueEmpty)
 
+	if len(cfg.NumericFields) > 0 {
+		numericFields := make([][]byte, 0, len(cfg.NumericFields))
+		for _, field := range cfg.NumericFields {
+			numericFields = append(numericFields, []byte(field))
+		}
+		opts = opts.SetNumericFields(numericFields)
+	}
+
 	return opts, nil
 }
 
@@ -178,13 +178,15 @@ func TestTagOptionsConfigWithTagGenerationScheme(t *testing.T) {
 
 func TestTagOptionsConfig(t *testing.T) {
 	var cfg TagOptionsConfiguration
-	config := "metricName: abcdefg\nidScheme: prepend_meta\nbucketName: foo"
+	config := "metricName: abcdefg\nidScheme: prepend_meta\nbucketName: foo\n" +
+		"numericFields: [code, shard]"
 	require.NoError(t, yaml.Unmarshal([]byte(config), &cfg))
 	opts, err := TagOptionsFromConfig(cfg)
 	require.NoError(t, err)
 	assert.Equal(t, []byte("abcdefg"), opts.MetricName())
 	assert.Equal(t, []byte("foo"), opts.BucketName())
 	assert.Equal(t, models.TypePrependMeta, opts.IDSchemeType())
+	assert.Equal(t, [][]byte{[]byte("code"), []byte("shard")}, opts.NumericFields())
 }
 
 func TestKeepNaNsDefault(t *testing.T) {
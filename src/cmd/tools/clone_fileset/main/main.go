@@ -25,6 +25,7 @@ import (
 	"log"
 	"os"
 
+	"github.com/m3db/m3/src/dbnode/persist/compression"
 	"github.com/m3db/m3/src/dbnode/persist/fs/clone"
 	xtime "github.com/m3db/m3/src/x/time"
 
@@ -41,6 +42,8 @@ var (
 	optDestShard      = flag.Uint("dest-shard", 0, "Destination Shard ID")
 	optDestBlockstart = flag.Int64("dest-block-start", 0, "Destination Block Start Time [in nsec]")
 	optDestBlockSize  = flag.Duration("dest-block-size", 0, "Destination Block Size")
+	optDestCompress   = flag.String("dest-compression", "",
+		"Destination data file compression (none, snappy or zstd), defaults to the source compression")
 )
 
 func main() {
@@ -78,6 +81,13 @@ func main() {
 	logger.Infof("destination: %+v", dest)
 
 	opts := clone.NewOptions()
+	if *optDestCompress != "" {
+		destCompression, err := compression.ParseType(*optDestCompress)
+		if err != nil {
+			logger.Fatalf("invalid destination compression: %v", err)
+		}
+		opts = opts.SetCompression(&destCompression)
+	}
 	cloner := clone.New(opts)
 	if err := cloner.Clone(src, dest, *optDestBlockSize); err != nil {
 		logger.Fatalf("unable to clone: %v", err)
@@ -173,6 +173,10 @@ func (f *fakeShardSet) LookupStateByID(shardID uint32) (shard.State, error) {
 	return shard.Available, nil
 }
 
+func (f *fakeShardSet) LookupShardByID(shardID uint32) (shard.Shard, error) {
+	return shard.NewShard(f.shardID).SetState(shard.Available), nil
+}
+
 func (f *fakeShardSet) Min() uint32 {
 	return f.shardID
 }
@@ -33,6 +33,7 @@ import (
 	"github.com/pborman/getopt"
 	"go.uber.org/zap"
 
+	"github.com/m3db/m3/src/dbnode/persist/encryption"
 	"github.com/m3db/m3/src/dbnode/persist/fs/commitlog"
 )
 
@@ -40,6 +41,8 @@ func main() {
 	var (
 		path     = getopt.StringLong("path", 'p', "", "file path [e.g. /var/lib/m3db/commitlogs/commitlog-0-161023.db]")
 		idFilter = getopt.StringLong("id-filter", 'f', "", "ID Contains Filter (optional)")
+		keysPath = getopt.StringLong("encryption-keys-path", 'k', "",
+			"directory of encryption keys to read encrypted commitlogs with (optional)")
 	)
 	getopt.Parse()
 
@@ -54,7 +57,17 @@ func main() {
 		os.Exit(1)
 	}
 
-	opts := commitlog.NewReaderOptions(commitlog.NewOptions(), false)
+	commitLogOpts := commitlog.NewOptions()
+	if *keysPath != "" {
+		keyProvider, err := encryption.NewFileKeyProvider(*keysPath, "")
+		if err != nil {
+			logger.Fatalf("unable to create encryption key provider: %v", err)
+		}
+		commitLogOpts = commitLogOpts.SetFilesystemOptions(commitLogOpts.FilesystemOptions().
+			SetEncryptionCipher(encryption.NewCipher(keyProvider)))
+	}
+
+	opts := commitlog.NewReaderOptions(commitLogOpts, false)
 	reader := commitlog.NewReader(opts)
 
 	_, err = reader.Open(*path)
@@ -144,6 +144,7 @@ func main() {
 	if err != nil {
 		log.Fatalf("unable to open reader: %v", err)
 	}
+	log.Infof("reading fileset with data compression: %s", reader.Status().Compression)
 
 	for {
 		entry, err := reader.StreamingRead()
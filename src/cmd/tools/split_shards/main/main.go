@@ -197,6 +197,7 @@ func splitFileSet(
 			BlockStart:          blockStart,
 			BlockSize:           srcReader.Status().BlockSize,
 			VolumeIndex:         volume + 1,
+			Compression:         srcReader.Status().Compression,
 			PlannedRecordsCount: plannedRecordsCount,
 		}
 		if err := dstWriters[i].Open(writeOpts); err != nil {
@@ -375,6 +375,7 @@ func fixFileSet(
 		FileSetContentType: fileSet.ID.FileSetContentType,
 		Identifier:         fileSet.ID,
 		BlockSize:          reader.Status().BlockSize,
+		Compression:        reader.Status().Compression,
 	})
 	if err != nil {
 		return err
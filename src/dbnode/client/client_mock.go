@@ -172,6 +172,21 @@ func (mr *MockSessionMockRecorder) Aggregate(ctx, namespace, q, opts interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Aggregate", reflect.TypeOf((*MockSession)(nil).Aggregate), ctx, namespace, q, opts)
 }
 
+// Cardinality mocks base method.
+func (m *MockSession) Cardinality(ctx context.Context, namespace ident.ID, opts index.CardinalityOptions) (index.CardinalityResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cardinality", ctx, namespace, opts)
+	ret0, _ := ret[0].(index.CardinalityResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Cardinality indicates an expected call of Cardinality.
+func (mr *MockSessionMockRecorder) Cardinality(ctx, namespace, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cardinality", reflect.TypeOf((*MockSession)(nil).Cardinality), ctx, namespace, opts)
+}
+
 // Close mocks base method.
 func (m *MockSession) Close() error {
 	m.ctrl.T.Helper()
@@ -201,6 +216,22 @@ func (mr *MockSessionMockRecorder) Fetch(namespace, id, startInclusive, endExclu
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Fetch", reflect.TypeOf((*MockSession)(nil).Fetch), namespace, id, startInclusive, endExclusive)
 }
 
+// FetchExemplars mocks base method.
+func (m *MockSession) FetchExemplars(ctx context.Context, namespace ident.ID, q index.Query, opts index.QueryOptions) ([]SeriesExemplars, FetchResponseMetadata, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchExemplars", ctx, namespace, q, opts)
+	ret0, _ := ret[0].([]SeriesExemplars)
+	ret1, _ := ret[1].(FetchResponseMetadata)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FetchExemplars indicates an expected call of FetchExemplars.
+func (mr *MockSessionMockRecorder) FetchExemplars(ctx, namespace, q, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchExemplars", reflect.TypeOf((*MockSession)(nil).FetchExemplars), ctx, namespace, q, opts)
+}
+
 // FetchIDs mocks base method.
 func (m *MockSession) FetchIDs(namespace ident.ID, ids ident.Iterator, startInclusive, endExclusive time0.UnixNano) (encoding.SeriesIterators, error) {
 	m.ctrl.T.Helper()
@@ -248,6 +279,21 @@ func (mr *MockSessionMockRecorder) FetchTaggedIDs(ctx, namespace, q, opts interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchTaggedIDs", reflect.TypeOf((*MockSession)(nil).FetchTaggedIDs), ctx, namespace, q, opts)
 }
 
+// FetchTaggedStream mocks base method.
+func (m *MockSession) FetchTaggedStream(ctx context.Context, namespace ident.ID, q index.Query, opts index.QueryOptions, fn func(encoding.SeriesIterator) error) (FetchResponseMetadata, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchTaggedStream", ctx, namespace, q, opts, fn)
+	ret0, _ := ret[0].(FetchResponseMetadata)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchTaggedStream indicates an expected call of FetchTaggedStream.
+func (mr *MockSessionMockRecorder) FetchTaggedStream(ctx, namespace, q, opts, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchTaggedStream", reflect.TypeOf((*MockSession)(nil).FetchTaggedStream), ctx, namespace, q, opts, fn)
+}
+
 // IteratorPools mocks base method.
 func (m *MockSession) IteratorPools() (encoding.IteratorPools, error) {
 	m.ctrl.T.Helper()
@@ -322,6 +368,20 @@ func (mr *MockSessionMockRecorder) WriteClusterAvailability() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteClusterAvailability", reflect.TypeOf((*MockSession)(nil).WriteClusterAvailability))
 }
 
+// WriteExemplars mocks base method.
+func (m *MockSession) WriteExemplars(namespace ident.ID, series []SeriesExemplars) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WriteExemplars", namespace, series)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WriteExemplars indicates an expected call of WriteExemplars.
+func (mr *MockSessionMockRecorder) WriteExemplars(namespace, series interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteExemplars", reflect.TypeOf((*MockSession)(nil).WriteExemplars), namespace, series)
+}
+
 // WriteTagged mocks base method.
 func (m *MockSession) WriteTagged(namespace, id ident.ID, tags ident.TagIterator, t time0.UnixNano, value float64, unit time0.Unit, annotation []byte) error {
 	m.ctrl.T.Helper()
@@ -820,6 +880,21 @@ func (mr *MockAdminSessionMockRecorder) BorrowConnections(shardID, fn, opts inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BorrowConnections", reflect.TypeOf((*MockAdminSession)(nil).BorrowConnections), shardID, fn, opts)
 }
 
+// Cardinality mocks base method.
+func (m *MockAdminSession) Cardinality(ctx context.Context, namespace ident.ID, opts index.CardinalityOptions) (index.CardinalityResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cardinality", ctx, namespace, opts)
+	ret0, _ := ret[0].(index.CardinalityResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Cardinality indicates an expected call of Cardinality.
+func (mr *MockAdminSessionMockRecorder) Cardinality(ctx, namespace, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cardinality", reflect.TypeOf((*MockAdminSession)(nil).Cardinality), ctx, namespace, opts)
+}
+
 // Close mocks base method.
 func (m *MockAdminSession) Close() error {
 	m.ctrl.T.Helper()
@@ -850,6 +925,21 @@ func (mr *MockAdminSessionMockRecorder) DedicatedConnection(shardID, opts interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DedicatedConnection", reflect.TypeOf((*MockAdminSession)(nil).DedicatedConnection), shardID, opts)
 }
 
+// DeleteSeries mocks base method.
+func (m *MockAdminSession) DeleteSeries(namespace ident.ID, q index.Query, start, end time0.UnixNano) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSeries", namespace, q, start, end)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteSeries indicates an expected call of DeleteSeries.
+func (mr *MockAdminSessionMockRecorder) DeleteSeries(namespace, q, start, end interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSeries", reflect.TypeOf((*MockAdminSession)(nil).DeleteSeries), namespace, q, start, end)
+}
+
 // Fetch mocks base method.
 func (m *MockAdminSession) Fetch(namespace, id ident.ID, startInclusive, endExclusive time0.UnixNano) (encoding.SeriesIterator, error) {
 	m.ctrl.T.Helper()
@@ -925,6 +1015,22 @@ func (mr *MockAdminSessionMockRecorder) FetchBootstrapBlocksMetadataFromPeers(na
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchBootstrapBlocksMetadataFromPeers", reflect.TypeOf((*MockAdminSession)(nil).FetchBootstrapBlocksMetadataFromPeers), namespace, shard, start, end, result)
 }
 
+// FetchExemplars mocks base method.
+func (m *MockAdminSession) FetchExemplars(ctx context.Context, namespace ident.ID, q index.Query, opts index.QueryOptions) ([]SeriesExemplars, FetchResponseMetadata, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchExemplars", ctx, namespace, q, opts)
+	ret0, _ := ret[0].([]SeriesExemplars)
+	ret1, _ := ret[1].(FetchResponseMetadata)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FetchExemplars indicates an expected call of FetchExemplars.
+func (mr *MockAdminSessionMockRecorder) FetchExemplars(ctx, namespace, q, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchExemplars", reflect.TypeOf((*MockAdminSession)(nil).FetchExemplars), ctx, namespace, q, opts)
+}
+
 // FetchIDs mocks base method.
 func (m *MockAdminSession) FetchIDs(namespace ident.ID, ids ident.Iterator, startInclusive, endExclusive time0.UnixNano) (encoding.SeriesIterators, error) {
 	m.ctrl.T.Helper()
@@ -940,6 +1046,21 @@ func (mr *MockAdminSessionMockRecorder) FetchIDs(namespace, ids, startInclusive,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchIDs", reflect.TypeOf((*MockAdminSession)(nil).FetchIDs), namespace, ids, startInclusive, endExclusive)
 }
 
+// FetchMerkleTreeNodesFromPeer mocks base method.
+func (m *MockAdminSession) FetchMerkleTreeNodesFromPeer(peer topology.Host, opts FetchMerkleTreeNodesOptions) (MerkleTreeNodes, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchMerkleTreeNodesFromPeer", peer, opts)
+	ret0, _ := ret[0].(MerkleTreeNodes)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchMerkleTreeNodesFromPeer indicates an expected call of FetchMerkleTreeNodesFromPeer.
+func (mr *MockAdminSessionMockRecorder) FetchMerkleTreeNodesFromPeer(peer, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchMerkleTreeNodesFromPeer", reflect.TypeOf((*MockAdminSession)(nil).FetchMerkleTreeNodesFromPeer), peer, opts)
+}
+
 // FetchTagged mocks base method.
 func (m *MockAdminSession) FetchTagged(ctx context.Context, namespace ident.ID, q index.Query, opts index.QueryOptions) (encoding.SeriesIterators, FetchResponseMetadata, error) {
 	m.ctrl.T.Helper()
@@ -972,6 +1093,21 @@ func (mr *MockAdminSessionMockRecorder) FetchTaggedIDs(ctx, namespace, q, opts i
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchTaggedIDs", reflect.TypeOf((*MockAdminSession)(nil).FetchTaggedIDs), ctx, namespace, q, opts)
 }
 
+// FetchTaggedStream mocks base method.
+func (m *MockAdminSession) FetchTaggedStream(ctx context.Context, namespace ident.ID, q index.Query, opts index.QueryOptions, fn func(encoding.SeriesIterator) error) (FetchResponseMetadata, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchTaggedStream", ctx, namespace, q, opts, fn)
+	ret0, _ := ret[0].(FetchResponseMetadata)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchTaggedStream indicates an expected call of FetchTaggedStream.
+func (mr *MockAdminSessionMockRecorder) FetchTaggedStream(ctx, namespace, q, opts, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchTaggedStream", reflect.TypeOf((*MockAdminSession)(nil).FetchTaggedStream), ctx, namespace, q, opts, fn)
+}
+
 // IteratorPools mocks base method.
 func (m *MockAdminSession) IteratorPools() (encoding.IteratorPools, error) {
 	m.ctrl.T.Helper()
@@ -1104,6 +1240,20 @@ func (mr *MockAdminSessionMockRecorder) WriteClusterAvailability() *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteClusterAvailability", reflect.TypeOf((*MockAdminSession)(nil).WriteClusterAvailability))
 }
 
+// WriteExemplars mocks base method.
+func (m *MockAdminSession) WriteExemplars(namespace ident.ID, series []SeriesExemplars) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WriteExemplars", namespace, series)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WriteExemplars indicates an expected call of WriteExemplars.
+func (mr *MockAdminSessionMockRecorder) WriteExemplars(namespace, series interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteExemplars", reflect.TypeOf((*MockAdminSession)(nil).WriteExemplars), namespace, series)
+}
+
 // WriteTagged mocks base method.
 func (m *MockAdminSession) WriteTagged(namespace, id ident.ID, tags ident.TagIterator, t time0.UnixNano, value float64, unit time0.Unit, annotation []byte) error {
 	m.ctrl.T.Helper()
@@ -1365,6 +1515,20 @@ func (mr *MockOptionsMockRecorder) ContextPool() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ContextPool", reflect.TypeOf((*MockOptions)(nil).ContextPool))
 }
 
+// DeleteSeriesRequestTimeout mocks base method.
+func (m *MockOptions) DeleteSeriesRequestTimeout() time.Duration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSeriesRequestTimeout")
+	ret0, _ := ret[0].(time.Duration)
+	return ret0
+}
+
+// DeleteSeriesRequestTimeout indicates an expected call of DeleteSeriesRequestTimeout.
+func (mr *MockOptionsMockRecorder) DeleteSeriesRequestTimeout() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSeriesRequestTimeout", reflect.TypeOf((*MockOptions)(nil).DeleteSeriesRequestTimeout))
+}
+
 // FetchBatchOpPoolSize mocks base method.
 func (m *MockOptions) FetchBatchOpPoolSize() int {
 	m.ctrl.T.Helper()
@@ -1939,6 +2103,20 @@ func (mr *MockOptionsMockRecorder) SetContextPool(value interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetContextPool", reflect.TypeOf((*MockOptions)(nil).SetContextPool), value)
 }
 
+// SetDeleteSeriesRequestTimeout mocks base method.
+func (m *MockOptions) SetDeleteSeriesRequestTimeout(value time.Duration) Options {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDeleteSeriesRequestTimeout", value)
+	ret0, _ := ret[0].(Options)
+	return ret0
+}
+
+// SetDeleteSeriesRequestTimeout indicates an expected call of SetDeleteSeriesRequestTimeout.
+func (mr *MockOptionsMockRecorder) SetDeleteSeriesRequestTimeout(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDeleteSeriesRequestTimeout", reflect.TypeOf((*MockOptions)(nil).SetDeleteSeriesRequestTimeout), value)
+}
+
 // SetEncodingM3TSZ mocks base method.
 func (m *MockOptions) SetEncodingM3TSZ() Options {
 	m.ctrl.T.Helper()
@@ -2317,6 +2495,62 @@ func (mr *MockOptionsMockRecorder) SetShardsLeavingCountTowardsConsistency(value
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetShardsLeavingCountTowardsConsistency", reflect.TypeOf((*MockOptions)(nil).SetShardsLeavingCountTowardsConsistency), value)
 }
 
+// SetStreamingReadsBatchSize mocks base method.
+func (m *MockOptions) SetStreamingReadsBatchSize(value int) Options {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetStreamingReadsBatchSize", value)
+	ret0, _ := ret[0].(Options)
+	return ret0
+}
+
+// SetStreamingReadsBatchSize indicates an expected call of SetStreamingReadsBatchSize.
+func (mr *MockOptionsMockRecorder) SetStreamingReadsBatchSize(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetStreamingReadsBatchSize", reflect.TypeOf((*MockOptions)(nil).SetStreamingReadsBatchSize), value)
+}
+
+// SetStreamingReadsEnabled mocks base method.
+func (m *MockOptions) SetStreamingReadsEnabled(value bool) Options {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetStreamingReadsEnabled", value)
+	ret0, _ := ret[0].(Options)
+	return ret0
+}
+
+// SetStreamingReadsEnabled indicates an expected call of SetStreamingReadsEnabled.
+func (mr *MockOptionsMockRecorder) SetStreamingReadsEnabled(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetStreamingReadsEnabled", reflect.TypeOf((*MockOptions)(nil).SetStreamingReadsEnabled), value)
+}
+
+// SetStreamingReadsMaxBytes mocks base method.
+func (m *MockOptions) SetStreamingReadsMaxBytes(value int) Options {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetStreamingReadsMaxBytes", value)
+	ret0, _ := ret[0].(Options)
+	return ret0
+}
+
+// SetStreamingReadsMaxBytes indicates an expected call of SetStreamingReadsMaxBytes.
+func (mr *MockOptionsMockRecorder) SetStreamingReadsMaxBytes(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetStreamingReadsMaxBytes", reflect.TypeOf((*MockOptions)(nil).SetStreamingReadsMaxBytes), value)
+}
+
+// SetStreamingReadsPort mocks base method.
+func (m *MockOptions) SetStreamingReadsPort(value int) Options {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetStreamingReadsPort", value)
+	ret0, _ := ret[0].(Options)
+	return ret0
+}
+
+// SetStreamingReadsPort indicates an expected call of SetStreamingReadsPort.
+func (mr *MockOptionsMockRecorder) SetStreamingReadsPort(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetStreamingReadsPort", reflect.TypeOf((*MockOptions)(nil).SetStreamingReadsPort), value)
+}
+
 // SetTagDecoderOptions mocks base method.
 func (m *MockOptions) SetTagDecoderOptions(value serialize.TagDecoderOptions) Options {
 	m.ctrl.T.Helper()
@@ -2555,6 +2789,62 @@ func (mr *MockOptionsMockRecorder) ShardsLeavingCountTowardsConsistency() *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ShardsLeavingCountTowardsConsistency", reflect.TypeOf((*MockOptions)(nil).ShardsLeavingCountTowardsConsistency))
 }
 
+// StreamingReadsBatchSize mocks base method.
+func (m *MockOptions) StreamingReadsBatchSize() int {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamingReadsBatchSize")
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// StreamingReadsBatchSize indicates an expected call of StreamingReadsBatchSize.
+func (mr *MockOptionsMockRecorder) StreamingReadsBatchSize() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamingReadsBatchSize", reflect.TypeOf((*MockOptions)(nil).StreamingReadsBatchSize))
+}
+
+// StreamingReadsEnabled mocks base method.
+func (m *MockOptions) StreamingReadsEnabled() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamingReadsEnabled")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// StreamingReadsEnabled indicates an expected call of StreamingReadsEnabled.
+func (mr *MockOptionsMockRecorder) StreamingReadsEnabled() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamingReadsEnabled", reflect.TypeOf((*MockOptions)(nil).StreamingReadsEnabled))
+}
+
+// StreamingReadsMaxBytes mocks base method.
+func (m *MockOptions) StreamingReadsMaxBytes() int {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamingReadsMaxBytes")
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// StreamingReadsMaxBytes indicates an expected call of StreamingReadsMaxBytes.
+func (mr *MockOptionsMockRecorder) StreamingReadsMaxBytes() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamingReadsMaxBytes", reflect.TypeOf((*MockOptions)(nil).StreamingReadsMaxBytes))
+}
+
+// StreamingReadsPort mocks base method.
+func (m *MockOptions) StreamingReadsPort() int {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamingReadsPort")
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// StreamingReadsPort indicates an expected call of StreamingReadsPort.
+func (mr *MockOptionsMockRecorder) StreamingReadsPort() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamingReadsPort", reflect.TypeOf((*MockOptions)(nil).StreamingReadsPort))
+}
+
 // TagDecoderOptions mocks base method.
 func (m *MockOptions) TagDecoderOptions() serialize.TagDecoderOptions {
 	m.ctrl.T.Helper()
@@ -3054,6 +3344,20 @@ func (mr *MockAdminOptionsMockRecorder) ContextPool() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ContextPool", reflect.TypeOf((*MockAdminOptions)(nil).ContextPool))
 }
 
+// DeleteSeriesRequestTimeout mocks base method.
+func (m *MockAdminOptions) DeleteSeriesRequestTimeout() time.Duration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSeriesRequestTimeout")
+	ret0, _ := ret[0].(time.Duration)
+	return ret0
+}
+
+// DeleteSeriesRequestTimeout indicates an expected call of DeleteSeriesRequestTimeout.
+func (mr *MockAdminOptionsMockRecorder) DeleteSeriesRequestTimeout() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSeriesRequestTimeout", reflect.TypeOf((*MockAdminOptions)(nil).DeleteSeriesRequestTimeout))
+}
+
 // FetchBatchOpPoolSize mocks base method.
 func (m *MockAdminOptions) FetchBatchOpPoolSize() int {
 	m.ctrl.T.Helper()
@@ -3726,6 +4030,20 @@ func (mr *MockAdminOptionsMockRecorder) SetContextPool(value interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetContextPool", reflect.TypeOf((*MockAdminOptions)(nil).SetContextPool), value)
 }
 
+// SetDeleteSeriesRequestTimeout mocks base method.
+func (m *MockAdminOptions) SetDeleteSeriesRequestTimeout(value time.Duration) Options {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDeleteSeriesRequestTimeout", value)
+	ret0, _ := ret[0].(Options)
+	return ret0
+}
+
+// SetDeleteSeriesRequestTimeout indicates an expected call of SetDeleteSeriesRequestTimeout.
+func (mr *MockAdminOptionsMockRecorder) SetDeleteSeriesRequestTimeout(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDeleteSeriesRequestTimeout", reflect.TypeOf((*MockAdminOptions)(nil).SetDeleteSeriesRequestTimeout), value)
+}
+
 // SetEncodingM3TSZ mocks base method.
 func (m *MockAdminOptions) SetEncodingM3TSZ() Options {
 	m.ctrl.T.Helper()
@@ -4202,6 +4520,62 @@ func (mr *MockAdminOptionsMockRecorder) SetStreamBlocksRetrier(value interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetStreamBlocksRetrier", reflect.TypeOf((*MockAdminOptions)(nil).SetStreamBlocksRetrier), value)
 }
 
+// SetStreamingReadsBatchSize mocks base method.
+func (m *MockAdminOptions) SetStreamingReadsBatchSize(value int) Options {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetStreamingReadsBatchSize", value)
+	ret0, _ := ret[0].(Options)
+	return ret0
+}
+
+// SetStreamingReadsBatchSize indicates an expected call of SetStreamingReadsBatchSize.
+func (mr *MockAdminOptionsMockRecorder) SetStreamingReadsBatchSize(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetStreamingReadsBatchSize", reflect.TypeOf((*MockAdminOptions)(nil).SetStreamingReadsBatchSize), value)
+}
+
+// SetStreamingReadsEnabled mocks base method.
+func (m *MockAdminOptions) SetStreamingReadsEnabled(value bool) Options {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetStreamingReadsEnabled", value)
+	ret0, _ := ret[0].(Options)
+	return ret0
+}
+
+// SetStreamingReadsEnabled indicates an expected call of SetStreamingReadsEnabled.
+func (mr *MockAdminOptionsMockRecorder) SetStreamingReadsEnabled(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetStreamingReadsEnabled", reflect.TypeOf((*MockAdminOptions)(nil).SetStreamingReadsEnabled), value)
+}
+
+// SetStreamingReadsMaxBytes mocks base method.
+func (m *MockAdminOptions) SetStreamingReadsMaxBytes(value int) Options {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetStreamingReadsMaxBytes", value)
+	ret0, _ := ret[0].(Options)
+	return ret0
+}
+
+// SetStreamingReadsMaxBytes indicates an expected call of SetStreamingReadsMaxBytes.
+func (mr *MockAdminOptionsMockRecorder) SetStreamingReadsMaxBytes(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetStreamingReadsMaxBytes", reflect.TypeOf((*MockAdminOptions)(nil).SetStreamingReadsMaxBytes), value)
+}
+
+// SetStreamingReadsPort mocks base method.
+func (m *MockAdminOptions) SetStreamingReadsPort(value int) Options {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetStreamingReadsPort", value)
+	ret0, _ := ret[0].(Options)
+	return ret0
+}
+
+// SetStreamingReadsPort indicates an expected call of SetStreamingReadsPort.
+func (mr *MockAdminOptionsMockRecorder) SetStreamingReadsPort(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetStreamingReadsPort", reflect.TypeOf((*MockAdminOptions)(nil).SetStreamingReadsPort), value)
+}
+
 // SetTagDecoderOptions mocks base method.
 func (m *MockAdminOptions) SetTagDecoderOptions(value serialize.TagDecoderOptions) Options {
 	m.ctrl.T.Helper()
@@ -4454,6 +4828,62 @@ func (mr *MockAdminOptionsMockRecorder) StreamBlocksRetrier() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamBlocksRetrier", reflect.TypeOf((*MockAdminOptions)(nil).StreamBlocksRetrier))
 }
 
+// StreamingReadsBatchSize mocks base method.
+func (m *MockAdminOptions) StreamingReadsBatchSize() int {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamingReadsBatchSize")
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// StreamingReadsBatchSize indicates an expected call of StreamingReadsBatchSize.
+func (mr *MockAdminOptionsMockRecorder) StreamingReadsBatchSize() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamingReadsBatchSize", reflect.TypeOf((*MockAdminOptions)(nil).StreamingReadsBatchSize))
+}
+
+// StreamingReadsEnabled mocks base method.
+func (m *MockAdminOptions) StreamingReadsEnabled() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamingReadsEnabled")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// StreamingReadsEnabled indicates an expected call of StreamingReadsEnabled.
+func (mr *MockAdminOptionsMockRecorder) StreamingReadsEnabled() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamingReadsEnabled", reflect.TypeOf((*MockAdminOptions)(nil).StreamingReadsEnabled))
+}
+
+// StreamingReadsMaxBytes mocks base method.
+func (m *MockAdminOptions) StreamingReadsMaxBytes() int {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamingReadsMaxBytes")
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// StreamingReadsMaxBytes indicates an expected call of StreamingReadsMaxBytes.
+func (mr *MockAdminOptionsMockRecorder) StreamingReadsMaxBytes() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamingReadsMaxBytes", reflect.TypeOf((*MockAdminOptions)(nil).StreamingReadsMaxBytes))
+}
+
+// StreamingReadsPort mocks base method.
+func (m *MockAdminOptions) StreamingReadsPort() int {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamingReadsPort")
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// StreamingReadsPort indicates an expected call of StreamingReadsPort.
+func (mr *MockAdminOptionsMockRecorder) StreamingReadsPort() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamingReadsPort", reflect.TypeOf((*MockAdminOptions)(nil).StreamingReadsPort))
+}
+
 // TagDecoderOptions mocks base method.
 func (m *MockAdminOptions) TagDecoderOptions() serialize.TagDecoderOptions {
 	m.ctrl.T.Helper()
@@ -4746,6 +5176,21 @@ func (mr *MockclientSessionMockRecorder) BorrowConnections(shardID, fn, opts int
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BorrowConnections", reflect.TypeOf((*MockclientSession)(nil).BorrowConnections), shardID, fn, opts)
 }
 
+// Cardinality mocks base method.
+func (m *MockclientSession) Cardinality(ctx context.Context, namespace ident.ID, opts index.CardinalityOptions) (index.CardinalityResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cardinality", ctx, namespace, opts)
+	ret0, _ := ret[0].(index.CardinalityResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Cardinality indicates an expected call of Cardinality.
+func (mr *MockclientSessionMockRecorder) Cardinality(ctx, namespace, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cardinality", reflect.TypeOf((*MockclientSession)(nil).Cardinality), ctx, namespace, opts)
+}
+
 // Close mocks base method.
 func (m *MockclientSession) Close() error {
 	m.ctrl.T.Helper()
@@ -4776,6 +5221,21 @@ func (mr *MockclientSessionMockRecorder) DedicatedConnection(shardID, opts inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DedicatedConnection", reflect.TypeOf((*MockclientSession)(nil).DedicatedConnection), shardID, opts)
 }
 
+// DeleteSeries mocks base method.
+func (m *MockclientSession) DeleteSeries(namespace ident.ID, q index.Query, start, end time0.UnixNano) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSeries", namespace, q, start, end)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteSeries indicates an expected call of DeleteSeries.
+func (mr *MockclientSessionMockRecorder) DeleteSeries(namespace, q, start, end interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSeries", reflect.TypeOf((*MockclientSession)(nil).DeleteSeries), namespace, q, start, end)
+}
+
 // Fetch mocks base method.
 func (m *MockclientSession) Fetch(namespace, id ident.ID, startInclusive, endExclusive time0.UnixNano) (encoding.SeriesIterator, error) {
 	m.ctrl.T.Helper()
@@ -4851,6 +5311,22 @@ func (mr *MockclientSessionMockRecorder) FetchBootstrapBlocksMetadataFromPeers(n
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchBootstrapBlocksMetadataFromPeers", reflect.TypeOf((*MockclientSession)(nil).FetchBootstrapBlocksMetadataFromPeers), namespace, shard, start, end, result)
 }
 
+// FetchExemplars mocks base method.
+func (m *MockclientSession) FetchExemplars(ctx context.Context, namespace ident.ID, q index.Query, opts index.QueryOptions) ([]SeriesExemplars, FetchResponseMetadata, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchExemplars", ctx, namespace, q, opts)
+	ret0, _ := ret[0].([]SeriesExemplars)
+	ret1, _ := ret[1].(FetchResponseMetadata)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FetchExemplars indicates an expected call of FetchExemplars.
+func (mr *MockclientSessionMockRecorder) FetchExemplars(ctx, namespace, q, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchExemplars", reflect.TypeOf((*MockclientSession)(nil).FetchExemplars), ctx, namespace, q, opts)
+}
+
 // FetchIDs mocks base method.
 func (m *MockclientSession) FetchIDs(namespace ident.ID, ids ident.Iterator, startInclusive, endExclusive time0.UnixNano) (encoding.SeriesIterators, error) {
 	m.ctrl.T.Helper()
@@ -4866,6 +5342,21 @@ func (mr *MockclientSessionMockRecorder) FetchIDs(namespace, ids, startInclusive
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchIDs", reflect.TypeOf((*MockclientSession)(nil).FetchIDs), namespace, ids, startInclusive, endExclusive)
 }
 
+// FetchMerkleTreeNodesFromPeer mocks base method.
+func (m *MockclientSession) FetchMerkleTreeNodesFromPeer(peer topology.Host, opts FetchMerkleTreeNodesOptions) (MerkleTreeNodes, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchMerkleTreeNodesFromPeer", peer, opts)
+	ret0, _ := ret[0].(MerkleTreeNodes)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchMerkleTreeNodesFromPeer indicates an expected call of FetchMerkleTreeNodesFromPeer.
+func (mr *MockclientSessionMockRecorder) FetchMerkleTreeNodesFromPeer(peer, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchMerkleTreeNodesFromPeer", reflect.TypeOf((*MockclientSession)(nil).FetchMerkleTreeNodesFromPeer), peer, opts)
+}
+
 // FetchTagged mocks base method.
 func (m *MockclientSession) FetchTagged(ctx context.Context, namespace ident.ID, q index.Query, opts index.QueryOptions) (encoding.SeriesIterators, FetchResponseMetadata, error) {
 	m.ctrl.T.Helper()
@@ -4898,6 +5389,21 @@ func (mr *MockclientSessionMockRecorder) FetchTaggedIDs(ctx, namespace, q, opts
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchTaggedIDs", reflect.TypeOf((*MockclientSession)(nil).FetchTaggedIDs), ctx, namespace, q, opts)
 }
 
+// FetchTaggedStream mocks base method.
+func (m *MockclientSession) FetchTaggedStream(ctx context.Context, namespace ident.ID, q index.Query, opts index.QueryOptions, fn func(encoding.SeriesIterator) error) (FetchResponseMetadata, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchTaggedStream", ctx, namespace, q, opts, fn)
+	ret0, _ := ret[0].(FetchResponseMetadata)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchTaggedStream indicates an expected call of FetchTaggedStream.
+func (mr *MockclientSessionMockRecorder) FetchTaggedStream(ctx, namespace, q, opts, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchTaggedStream", reflect.TypeOf((*MockclientSession)(nil).FetchTaggedStream), ctx, namespace, q, opts, fn)
+}
+
 // IteratorPools mocks base method.
 func (m *MockclientSession) IteratorPools() (encoding.IteratorPools, error) {
 	m.ctrl.T.Helper()
@@ -5044,6 +5550,20 @@ func (mr *MockclientSessionMockRecorder) WriteClusterAvailability() *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteClusterAvailability", reflect.TypeOf((*MockclientSession)(nil).WriteClusterAvailability))
 }
 
+// WriteExemplars mocks base method.
+func (m *MockclientSession) WriteExemplars(namespace ident.ID, series []SeriesExemplars) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WriteExemplars", namespace, series)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WriteExemplars indicates an expected call of WriteExemplars.
+func (mr *MockclientSessionMockRecorder) WriteExemplars(namespace, series interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteExemplars", reflect.TypeOf((*MockclientSession)(nil).WriteExemplars), namespace, series)
+}
+
 // WriteTagged mocks base method.
 func (m *MockclientSession) WriteTagged(namespace, id ident.ID, tags ident.TagIterator, t time0.UnixNano, value float64, unit time0.Unit, annotation []byte) error {
 	m.ctrl.T.Helper()
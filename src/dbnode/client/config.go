@@ -117,6 +117,29 @@ type Configuration struct {
 	// ShardsLeavingCountTowardsConsistency sets whether or not writes to leaving shards
 	// count towards consistency, by default they do not.
 	ShardsLeavingCountTowardsConsistency *bool `yaml:"shardsLeavingCountTowardsConsistency"`
+
+	// StreamingReads is the configuration for streaming reads from the nodes
+	// over their gRPC streaming service.
+	StreamingReads *StreamingReadsConfiguration `yaml:"streamingReads"`
+}
+
+// StreamingReadsConfiguration is the configuration for streaming reads.
+type StreamingReadsConfiguration struct {
+	// Enabled specifies whether reads are streamed from the nodes, the nodes
+	// must have the gRPC node service enabled. Each shard is streamed from a
+	// single replica, so reads are only streamed while the read consistency
+	// level is one and use FetchTagged otherwise.
+	Enabled bool `yaml:"enabled"`
+
+	// Port is the port of the gRPC node service on the nodes.
+	Port *int `yaml:"port"`
+
+	// BatchSize is the number of series in each streamed response.
+	BatchSize *int `yaml:"batchSize"`
+
+	// MaxBytes is the maximum size of the series streamed by a single read,
+	// zero for no limit.
+	MaxBytes *int `yaml:"maxBytes"`
 }
 
 // ProtoConfiguration is the configuration for running with ProtoDataMode enabled.
@@ -437,6 +460,18 @@ func (c Configuration) NewAdminClient(
 	if c.ShardsLeavingCountTowardsConsistency != nil {
 		v = v.SetShardsLeavingCountTowardsConsistency(*c.ShardsLeavingCountTowardsConsistency)
 	}
+	if c.StreamingReads != nil {
+		v = v.SetStreamingReadsEnabled(c.StreamingReads.Enabled)
+		if c.StreamingReads.Port != nil {
+			v = v.SetStreamingReadsPort(*c.StreamingReads.Port)
+		}
+		if c.StreamingReads.BatchSize != nil {
+			v = v.SetStreamingReadsBatchSize(*c.StreamingReads.BatchSize)
+		}
+		if c.StreamingReads.MaxBytes != nil {
+			v = v.SetStreamingReadsMaxBytes(*c.StreamingReads.MaxBytes)
+		}
+	}
 
 	// Cast to admin options to apply admin config options.
 	opts := v.(AdminOptions)
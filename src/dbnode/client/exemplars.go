@@ -0,0 +1,59 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package client
+
+import (
+	gocontext "context"
+
+	"github.com/m3db/m3/src/dbnode/generated/thrift/rpc"
+)
+
+type writeExemplarsOp struct {
+	request rpc.WriteExemplarsRequest
+	// seriesIdxs are the indexes of the series of each request element in
+	// the series passed to WriteExemplars.
+	seriesIdxs   []int
+	completionFn completionFn
+}
+
+func (w *writeExemplarsOp) Size() int {
+	// Write exemplars is always a single op
+	return 1
+}
+
+func (w *writeExemplarsOp) CompletionFn() completionFn {
+	return w.completionFn
+}
+
+type fetchExemplarsOp struct {
+	context      gocontext.Context
+	request      rpc.FetchExemplarsRequest
+	completionFn completionFn
+}
+
+func (f *fetchExemplarsOp) Size() int {
+	// Fetch exemplars is always a single op
+	return 1
+}
+
+func (f *fetchExemplarsOp) CompletionFn() completionFn {
+	return f.completionFn
+}
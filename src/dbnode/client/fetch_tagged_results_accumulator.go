@@ -25,7 +25,6 @@ import (
 	"fmt"
 	"sort"
 
-	"github.com/m3db/m3/src/cluster/shard"
 	"github.com/m3db/m3/src/dbnode/encoding"
 	"github.com/m3db/m3/src/dbnode/generated/thrift/rpc"
 	"github.com/m3db/m3/src/dbnode/namespace"
@@ -175,7 +174,7 @@ func (accum *fetchTaggedResultAccumulator) accumulatedResult(
 			continue // already been marked done, don't need to do anything for this shard
 		}
 
-		if hs.State() != shard.Available {
+		if !shardAvailableForReads(hs) {
 			// Currently, we only accept responses from shard's which are available
 			// NB: as a possible enhancement, we could accept a response from
 			// a shard that's not available if we tracked response pairs from
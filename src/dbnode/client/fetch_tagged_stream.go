@@ -0,0 +1,377 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package client
+
+import (
+	gocontext "context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/dbnode/generated/proto/streaming"
+	"github.com/m3db/m3/src/dbnode/namespace"
+	grpcconvert "github.com/m3db/m3/src/dbnode/network/server/grpc/convert"
+	"github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/convert"
+	"github.com/m3db/m3/src/dbnode/storage/index"
+	"github.com/m3db/m3/src/dbnode/storage/limits"
+	"github.com/m3db/m3/src/dbnode/topology"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	"github.com/m3db/m3/src/x/ident"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
+)
+
+const (
+	// streamingMaxRecvMsgSize is the maximum size of a streamed response, the
+	// nodes bound the size of each response however a response always
+	// contains at least one series.
+	streamingMaxRecvMsgSize = 256 << 20
+)
+
+var (
+	errSessionStreamingReadsNotEnabled = errors.New("session streaming reads are not enabled")
+	errStreamEndedBeforeDone           = errors.New("stream from node ended before it was done")
+)
+
+// streamingReadConsistencyLevel returns whether reads at the consistency level
+// are satisfied by reading each shard from a single replica, which is how
+// series are streamed. Reads at other levels use FetchTagged so that results
+// from several replicas are merged.
+func streamingReadConsistencyLevel(level topology.ReadConsistencyLevel) bool {
+	switch level {
+	case topology.ReadConsistencyLevelNone, topology.ReadConsistencyLevelOne:
+		return true
+	default:
+		return false
+	}
+}
+
+type newStreamingConnFn func(address string) (*grpc.ClientConn, error)
+
+func newStreamingConn(address string) (*grpc.ClientConn, error) {
+	return grpc.Dial(address,
+		grpc.WithInsecure(),
+		grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                10 * time.Second,
+			Timeout:             20 * time.Second,
+			PermitWithoutStream: true,
+		}),
+		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(streamingMaxRecvMsgSize)))
+}
+
+// streamingConns are the connections to the node streaming services, keyed
+// by the address of the streaming service.
+type streamingConns struct {
+	sync.Mutex
+	closed bool
+	conns  map[string]*grpc.ClientConn
+}
+
+func (c *streamingConns) client(
+	address string,
+	newConnFn newStreamingConnFn,
+) (streaming.NodeClient, error) {
+	c.Lock()
+	defer c.Unlock()
+	if c.closed {
+		return nil, errSessionStatusNotOpen
+	}
+	conn, ok := c.conns[address]
+	if !ok {
+		var err error
+		conn, err = newConnFn(address)
+		if err != nil {
+			return nil, err
+		}
+		if c.conns == nil {
+			c.conns = make(map[string]*grpc.ClientConn)
+		}
+		c.conns[address] = conn
+	}
+	return streaming.NewNodeClient(conn), nil
+}
+
+func (c *streamingConns) close() {
+	c.Lock()
+	defer c.Unlock()
+	c.closed = true
+	for address, conn := range c.conns {
+		conn.Close()
+		delete(c.conns, address)
+	}
+}
+
+// streamingAddress returns the address of the streaming service of a host.
+func streamingAddress(host topology.Host, port int) (string, error) {
+	hostname, _, err := net.SplitHostPort(host.Address())
+	if err != nil {
+		return "", fmt.Errorf("invalid host address %s: %w", host.Address(), err)
+	}
+	return net.JoinHostPort(hostname, strconv.Itoa(port)), nil
+}
+
+type streamedResponse struct {
+	response *streaming.FetchTaggedResponse
+	err      error
+}
+
+func (s *session) StreamingReadsEnabled() bool {
+	if !s.opts.StreamingReadsEnabled() {
+		return false
+	}
+	s.state.RLock()
+	level := s.state.readLevel
+	s.state.RUnlock()
+	return streamingReadConsistencyLevel(level)
+}
+
+func (s *session) FetchTaggedStream(
+	ctx gocontext.Context,
+	ns ident.ID,
+	q index.Query,
+	opts index.QueryOptions,
+	fn func(encoding.SeriesIterator) error,
+) (FetchResponseMetadata, error) {
+	if !s.opts.StreamingReadsEnabled() {
+		return FetchResponseMetadata{}, errSessionStreamingReadsNotEnabled
+	}
+
+	nsCtx, err := s.nsCtxFor(ns)
+	if err != nil {
+		return FetchResponseMetadata{}, err
+	}
+
+	const fetchData = true
+	req, err := convert.ToRPCFetchTaggedRequest(ns, q, opts, fetchData)
+	if err != nil {
+		return FetchResponseMetadata{}, xerrors.NewInvalidParamsError(err)
+	}
+
+	s.state.RLock()
+	if s.state.status != statusOpen {
+		s.state.RUnlock()
+		return FetchResponseMetadata{}, errSessionStatusNotOpen
+	}
+	if level := s.state.readLevel; !streamingReadConsistencyLevel(level) {
+		s.state.RUnlock()
+		return FetchResponseMetadata{}, fmt.Errorf(
+			"streaming reads require read consistency level one: level=%v", level)
+	}
+
+	// Stream every shard from a single replica that has the shard available
+	// so that series do not need to be merged across replicas, which would
+	// require buffering them.
+	queueIdxs, shardsByQueue, err := s.assignShardsWithRLock(nil)
+	if err != nil {
+		s.state.RUnlock()
+		return FetchResponseMetadata{}, err
+	}
+
+	var (
+		batchSize = s.opts.StreamingReadsBatchSize()
+		port      = s.opts.StreamingReadsPort()
+		clients   = make([]streaming.NodeClient, 0, len(queueIdxs))
+		requests  = make([]*streaming.FetchTaggedRequest, 0, len(queueIdxs))
+	)
+	for _, idx := range queueIdxs {
+		address, err := streamingAddress(s.state.queues[idx].Host(), port)
+		if err != nil {
+			s.state.RUnlock()
+			return FetchResponseMetadata{}, err
+		}
+		client, err := s.streamingConns.client(address, s.newStreamingConnFn)
+		if err != nil {
+			s.state.RUnlock()
+			return FetchResponseMetadata{}, err
+		}
+		clients = append(clients, client)
+		requests = append(requests,
+			grpcconvert.ToStreamingFetchTaggedRequest(req, shardsByQueue[idx], batchSize))
+	}
+	s.state.RUnlock()
+
+	ctx, cancel := gocontext.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg sync.WaitGroup
+		// NB: at most one response per host is buffered, the hosts are
+		// blocked by flow control until the responses are consumed.
+		responses = make(chan streamedResponse, len(clients))
+	)
+	for i := range clients {
+		wg.Add(1)
+		go func(client streaming.NodeClient, req *streaming.FetchTaggedRequest) {
+			defer wg.Done()
+			streamFetchTagged(ctx, client, req, responses)
+		}(clients[i], requests[i])
+	}
+	go func() {
+		wg.Wait()
+		close(responses)
+	}()
+
+	var (
+		meta     = FetchResponseMetadata{Exhaustive: true}
+		maxBytes = s.opts.StreamingReadsMaxBytes()
+		count    int
+		done     int
+		stopped  bool
+		resErr   error
+	)
+	// limitExceeded stops the stream once a limit is reached, the series
+	// streamed so far are returned unless the query requires exhaustive
+	// results.
+	limitExceeded := func(limit string) {
+		meta.Exhaustive = false
+		stopped = true
+		cancel()
+		if opts.RequireExhaustive {
+			resErr = xerrors.NewInvalidParamsError(limits.NewQueryLimitExceededError(fmt.Sprintf(
+				"query exceeded %s limit: require_exhaustive=%v, series_limit=%d, streaming_max_bytes=%d",
+				limit, opts.RequireExhaustive, opts.SeriesLimit, maxBytes)))
+		}
+	}
+	for r := range responses {
+		if stopped {
+			// Drain the remaining responses so that the streams complete.
+			continue
+		}
+		if r.err != nil {
+			resErr = r.err
+			stopped = true
+			cancel()
+			continue
+		}
+
+		size := r.response.Size()
+		if maxBytes > 0 && meta.EstimateTotalBytes+size > maxBytes {
+			limitExceeded("bytes")
+			continue
+		}
+		meta.Responses++
+		meta.EstimateTotalBytes += size
+		for _, elem := range r.response.Elements {
+			if opts.SeriesLimit > 0 && count >= opts.SeriesLimit {
+				limitExceeded("series")
+				break
+			}
+			iter := s.streamedSeriesIterator(ns, elem, nsCtx.Schema, opts)
+			if err := fn(iter); err != nil {
+				resErr = err
+				stopped = true
+				cancel()
+				break
+			}
+			count++
+		}
+		if r.response.Done {
+			done++
+			meta.Exhaustive = meta.Exhaustive && r.response.Exhaustive
+			meta.WaitedIndex += int(r.response.WaitedIndex)
+			meta.WaitedSeriesRead += int(r.response.WaitedSeriesRead)
+		}
+	}
+
+	if resErr != nil {
+		return FetchResponseMetadata{}, resErr
+	}
+	if !stopped && done != len(clients) {
+		return FetchResponseMetadata{}, errStreamEndedBeforeDone
+	}
+	return meta, nil
+}
+
+func streamFetchTagged(
+	ctx gocontext.Context,
+	client streaming.NodeClient,
+	req *streaming.FetchTaggedRequest,
+	responses chan<- streamedResponse,
+) {
+	send := func(r streamedResponse) bool {
+		select {
+		case responses <- r:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	stream, err := client.FetchTagged(ctx, req)
+	if err != nil {
+		send(streamedResponse{err: grpcconvert.FromStatusError(err)})
+		return
+	}
+	done := false
+	for {
+		response, err := stream.Recv()
+		if err == io.EOF {
+			if !done {
+				send(streamedResponse{err: errStreamEndedBeforeDone})
+			}
+			return
+		}
+		if err != nil {
+			send(streamedResponse{err: grpcconvert.FromStatusError(err)})
+			return
+		}
+		done = response.Done
+		if !send(streamedResponse{response: response}) {
+			return
+		}
+	}
+}
+
+func (s *session) streamedSeriesIterator(
+	ns ident.ID,
+	elem *streaming.FetchTaggedElement,
+	descr namespace.SchemaDescr,
+	opts index.QueryOptions,
+) encoding.SeriesIterator {
+	slicesIter := s.pools.ReaderSliceOfSlicesIterator().Get()
+	slicesIter.Reset(grpcconvert.FromStreamingSegments(elem.Segments))
+	multiIter := s.pools.MultiReaderIterator().Get()
+	multiIter.ResetSliceOfSlices(slicesIter, descr)
+	iters := s.pools.MultiReaderIteratorArray().Get(1)[:1]
+	iters[0] = multiIter
+
+	decoder := s.pools.TagDecoder().Get()
+	decoder.Reset(s.pools.CheckedBytesWrapper().Get(elem.EncodedTags))
+
+	tsID := s.pools.CheckedBytesWrapper().Get(elem.Id)
+	seriesIter := s.pools.SeriesIterator().Get()
+	seriesIter.Reset(encoding.SeriesIteratorOptions{
+		ID:                         s.pools.ID().BinaryID(tsID),
+		Namespace:                  s.pools.ID().Clone(ns),
+		Tags:                       decoder,
+		StartInclusive:             opts.StartInclusive,
+		EndExclusive:               opts.EndExclusive,
+		Replicas:                   iters,
+		SeriesIteratorConsolidator: s.opts.IterationOptions().SeriesIteratorConsolidator,
+	})
+	return seriesIter
+}
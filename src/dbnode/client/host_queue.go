@@ -269,6 +269,14 @@ func (q *queue) drain() {
 				}
 			case *truncateOp:
 				q.asyncTruncate(v)
+			case *deleteSeriesOp:
+				q.asyncDeleteSeries(v)
+			case *writeExemplarsOp:
+				q.asyncWriteExemplars(v)
+			case *fetchExemplarsOp:
+				q.asyncFetchExemplars(v)
+			case *cardinalityOp:
+				q.asyncCardinality(v)
 			default:
 				completionFn := ops[i].CompletionFn()
 				completionFn(nil, errQueueUnknownOperation(q.host.ID()))
@@ -973,6 +981,112 @@ func (q *queue) asyncTruncate(op *truncateOp) {
 	})
 }
 
+func (q *queue) asyncDeleteSeries(op *deleteSeriesOp) {
+	q.Add(1)
+
+	q.workerPool.Go(func() {
+		cleanup := q.Done
+
+		client, _, err := q.connPool.NextClient()
+		if err != nil {
+			// No client available
+			op.completionFn(nil, err)
+			cleanup()
+			return
+		}
+
+		ctx, _ := thrift.NewContext(q.opts.DeleteSeriesRequestTimeout())
+		if res, err := client.DeleteSeries(ctx, &op.request); err != nil {
+			op.completionFn(nil, err)
+		} else {
+			op.completionFn(res, nil)
+		}
+
+		cleanup()
+	})
+}
+
+func (q *queue) asyncWriteExemplars(op *writeExemplarsOp) {
+	q.Add(1)
+
+	q.workerPool.Go(func() {
+		cleanup := q.Done
+
+		client, _, err := q.connPool.NextClient()
+		if err != nil {
+			// No client available
+			op.completionFn(nil, err)
+			cleanup()
+			return
+		}
+
+		ctx, _ := thrift.NewContext(q.opts.WriteRequestTimeout())
+		if err := client.WriteExemplars(ctx, &op.request); err != nil {
+			op.completionFn(nil, err)
+		} else {
+			op.completionFn(nil, nil)
+		}
+
+		cleanup()
+	})
+}
+
+func (q *queue) asyncFetchExemplars(op *fetchExemplarsOp) {
+	q.Add(1)
+
+	go func() {
+		defer q.Done()
+
+		// All fetch exemplars calls are required to provide a context with a deadline.
+		ctx, err := q.mustWrapAndCheckContext(op.context, "fetchExemplars")
+		if err != nil {
+			op.completionFn(nil, err)
+			return
+		}
+
+		client, _, err := q.connPool.NextClient()
+		if err != nil {
+			// No client available
+			op.completionFn(nil, err)
+			return
+		}
+
+		if res, err := client.FetchExemplars(ctx, &op.request); err != nil {
+			op.completionFn(nil, err)
+		} else {
+			op.completionFn(res, nil)
+		}
+	}()
+}
+
+func (q *queue) asyncCardinality(op *cardinalityOp) {
+	q.Add(1)
+
+	go func() {
+		defer q.Done()
+
+		// All cardinality calls are required to provide a context with a deadline.
+		ctx, err := q.mustWrapAndCheckContext(op.context, "cardinality")
+		if err != nil {
+			op.completionFn(nil, err)
+			return
+		}
+
+		client, _, err := q.connPool.NextClient()
+		if err != nil {
+			// No client available
+			op.completionFn(nil, err)
+			return
+		}
+
+		if res, err := client.Cardinality(ctx, &op.request); err != nil {
+			op.completionFn(nil, err)
+		} else {
+			op.completionFn(res, nil)
+		}
+	}()
+}
+
 func (q *queue) mustWrapAndCheckContext(
 	callingContext context.Context,
 	method string,
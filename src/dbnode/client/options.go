@@ -88,6 +88,9 @@ const (
 	// defaultTruncateRequestTimeout is the default truncate request timeout
 	defaultTruncateRequestTimeout = 60 * time.Second
 
+	// defaultDeleteSeriesRequestTimeout is the default delete series request timeout
+	defaultDeleteSeriesRequestTimeout = 60 * time.Second
+
 	// defaultWriteShardsInitializing is the default write to shards intializing value
 	defaultWriteShardsInitializing = true
 
@@ -175,6 +178,21 @@ const (
 	// defaultHostQueueWorkerPoolKillProbability is the default host queue worker pool
 	// kill probability.
 	defaultHostQueueWorkerPoolKillProbability = 0.01
+
+	// defaultStreamingReadsEnabled is the default setting for whether reads are
+	// streamed from the nodes over gRPC.
+	defaultStreamingReadsEnabled = false
+
+	// defaultStreamingReadsPort is the default port of the node gRPC streaming service.
+	defaultStreamingReadsPort = 9005
+
+	// defaultStreamingReadsBatchSize is the default number of series in each
+	// streamed response.
+	defaultStreamingReadsBatchSize = 128
+
+	// defaultStreamingReadsMaxBytes is the default maximum size of the series
+	// streamed by a single read.
+	defaultStreamingReadsMaxBytes = 1 << 30
 )
 
 var (
@@ -229,6 +247,9 @@ var (
 
 	errNoTopologyInitializerSet    = errors.New("no topology initializer set")
 	errNoReaderIteratorAllocateSet = errors.New("no reader iterator allocator set, encoding not set")
+	errStreamingReadsPortInvalid   = errors.New("streaming reads port must be positive")
+	errStreamingReadsBatchInvalid  = errors.New("streaming reads batch size must be positive")
+	errStreamingReadsBytesInvalid  = errors.New("streaming reads max bytes must not be negative")
 )
 
 type options struct {
@@ -249,6 +270,7 @@ type options struct {
 	writeRequestTimeout                     time.Duration
 	fetchRequestTimeout                     time.Duration
 	truncateRequestTimeout                  time.Duration
+	deleteSeriesRequestTimeout              time.Duration
 	backgroundConnectInterval               time.Duration
 	backgroundConnectStutter                time.Duration
 	backgroundHealthCheckInterval           time.Duration
@@ -295,6 +317,10 @@ type options struct {
 	asyncWriteMaxConcurrency                int
 	useV2BatchAPIs                          bool
 	iterationOptions                        index.IterationOptions
+	streamingReadsEnabled                   bool
+	streamingReadsPort                      int
+	streamingReadsBatchSize                 int
+	streamingReadsMaxBytes                  int
 	writeTimestampOffset                    time.Duration
 	namespaceInitializer                    namespace.Initializer
 	thriftContextFn                         ThriftContextFn
@@ -406,6 +432,7 @@ func newOptions() *options {
 		writeRequestTimeout:                     defaultWriteRequestTimeout,
 		fetchRequestTimeout:                     defaultFetchRequestTimeout,
 		truncateRequestTimeout:                  defaultTruncateRequestTimeout,
+		deleteSeriesRequestTimeout:              defaultDeleteSeriesRequestTimeout,
 		backgroundConnectInterval:               defaultBackgroundConnectInterval,
 		backgroundConnectStutter:                defaultBackgroundConnectStutter,
 		backgroundHealthCheckInterval:           defaultBackgroundHealthCheckInterval,
@@ -447,6 +474,10 @@ func newOptions() *options {
 		asyncTopologyInitializers:               []topology.Initializer{},
 		asyncWriteMaxConcurrency:                defaultAsyncWriteMaxConcurrency,
 		useV2BatchAPIs:                          defaultUseV2BatchAPIs,
+		streamingReadsEnabled:                   defaultStreamingReadsEnabled,
+		streamingReadsPort:                      defaultStreamingReadsPort,
+		streamingReadsBatchSize:                 defaultStreamingReadsBatchSize,
+		streamingReadsMaxBytes:                  defaultStreamingReadsMaxBytes,
 		thriftContextFn:                         defaultThriftContextFn,
 	}
 	return opts.SetEncodingM3TSZ().(*options)
@@ -479,6 +510,15 @@ func validate(opts *options) error {
 	); err != nil {
 		return err
 	}
+	if opts.streamingReadsPort <= 0 {
+		return errStreamingReadsPortInvalid
+	}
+	if opts.streamingReadsBatchSize <= 0 {
+		return errStreamingReadsBatchInvalid
+	}
+	if opts.streamingReadsMaxBytes < 0 {
+		return errStreamingReadsBytesInvalid
+	}
 	return opts.logErrorSampleRate.Validate()
 }
 
@@ -679,6 +719,16 @@ func (o *options) TruncateRequestTimeout() time.Duration {
 	return o.truncateRequestTimeout
 }
 
+func (o *options) SetDeleteSeriesRequestTimeout(value time.Duration) Options {
+	opts := *o
+	opts.deleteSeriesRequestTimeout = value
+	return &opts
+}
+
+func (o *options) DeleteSeriesRequestTimeout() time.Duration {
+	return o.deleteSeriesRequestTimeout
+}
+
 func (o *options) SetBackgroundConnectInterval(value time.Duration) Options {
 	opts := *o
 	opts.backgroundConnectInterval = value
@@ -1129,6 +1179,46 @@ func (o *options) IterationOptions() index.IterationOptions {
 	return o.iterationOptions
 }
 
+func (o *options) SetStreamingReadsEnabled(value bool) Options {
+	opts := *o
+	opts.streamingReadsEnabled = value
+	return &opts
+}
+
+func (o *options) StreamingReadsEnabled() bool {
+	return o.streamingReadsEnabled
+}
+
+func (o *options) SetStreamingReadsPort(value int) Options {
+	opts := *o
+	opts.streamingReadsPort = value
+	return &opts
+}
+
+func (o *options) StreamingReadsPort() int {
+	return o.streamingReadsPort
+}
+
+func (o *options) SetStreamingReadsBatchSize(value int) Options {
+	opts := *o
+	opts.streamingReadsBatchSize = value
+	return &opts
+}
+
+func (o *options) StreamingReadsBatchSize() int {
+	return o.streamingReadsBatchSize
+}
+
+func (o *options) SetStreamingReadsMaxBytes(value int) Options {
+	opts := *o
+	opts.streamingReadsMaxBytes = value
+	return &opts
+}
+
+func (o *options) StreamingReadsMaxBytes() int {
+	return o.streamingReadsMaxBytes
+}
+
 func (o *options) SetWriteTimestampOffset(value time.Duration) AdminOptions {
 	opts := *o
 	opts.writeTimestampOffset = value
@@ -292,6 +292,24 @@ func (s replicatedSession) FetchTagged(
 	return s.session.FetchTagged(ctx, namespace, q, opts)
 }
 
+// FetchTaggedStream resolves the provided query to known IDs, and streams
+// the data for them.
+func (s replicatedSession) FetchTaggedStream(
+	ctx context.Context,
+	namespace ident.ID,
+	q index.Query,
+	opts index.QueryOptions,
+	fn func(encoding.SeriesIterator) error,
+) (FetchResponseMetadata, error) {
+	return s.session.FetchTaggedStream(ctx, namespace, q, opts, fn)
+}
+
+// StreamingReadsEnabled returns whether streaming reads are enabled.
+func (s replicatedSession) StreamingReadsEnabled() bool {
+	session, ok := s.session.(StreamingReadsSession)
+	return ok && session.StreamingReadsEnabled()
+}
+
 // FetchTaggedIDs resolves the provided query to known IDs.
 func (s replicatedSession) FetchTaggedIDs(
 	ctx context.Context,
@@ -302,6 +320,36 @@ func (s replicatedSession) FetchTaggedIDs(
 	return s.session.FetchTaggedIDs(ctx, namespace, q, opts)
 }
 
+// WriteExemplars writes the exemplars for each of the series, exemplars are
+// not replicated to the async clusters.
+func (s replicatedSession) WriteExemplars(
+	namespace ident.ID,
+	series []SeriesExemplars,
+) error {
+	return s.session.WriteExemplars(namespace, series)
+}
+
+// FetchExemplars resolves the provided query to known IDs, and fetches the
+// exemplars for them.
+func (s replicatedSession) FetchExemplars(
+	ctx context.Context,
+	namespace ident.ID,
+	q index.Query,
+	opts index.QueryOptions,
+) ([]SeriesExemplars, FetchResponseMetadata, error) {
+	return s.session.FetchExemplars(ctx, namespace, q, opts)
+}
+
+// Cardinality returns the cardinality statistics of the series indexed
+// within the time range.
+func (s replicatedSession) Cardinality(
+	ctx context.Context,
+	namespace ident.ID,
+	opts index.CardinalityOptions,
+) (index.CardinalityResult, error) {
+	return s.session.Cardinality(ctx, namespace, opts)
+}
+
 // ShardID returns the given shard for an ID for callers
 // to easily discern what shard is failing when operations
 // for given IDs begin failing.
@@ -348,6 +396,15 @@ func (s replicatedSession) Truncate(namespace ident.ID) (int64, error) {
 	return s.session.Truncate(namespace)
 }
 
+// DeleteSeries tombstones the series matching the query for the given range.
+func (s replicatedSession) DeleteSeries(
+	namespace ident.ID,
+	q index.Query,
+	start, end xtime.UnixNano,
+) (int64, error) {
+	return s.session.DeleteSeries(namespace, q, start, end)
+}
+
 // FetchBootstrapBlocksFromPeers will fetch the most fulfilled block
 // for each series using the runtime configurable bootstrap level consistency.
 func (s replicatedSession) FetchBootstrapBlocksFromPeers(
@@ -394,6 +451,15 @@ func (s replicatedSession) FetchBlocksFromPeers(
 	return s.session.FetchBlocksFromPeers(namespace, shard, consistencyLevel, metadatas, opts)
 }
 
+// FetchMerkleTreeNodesFromPeer will fetch the digests of nodes of the
+// merkle tree of a shard block from the peer specified.
+func (s replicatedSession) FetchMerkleTreeNodesFromPeer(
+	peer topology.Host,
+	opts FetchMerkleTreeNodesOptions,
+) (MerkleTreeNodes, error) {
+	return s.session.FetchMerkleTreeNodesFromPeer(peer, opts)
+}
+
 func (s *replicatedSession) BorrowConnections(
 	shardID uint32,
 	fn WithBorrowConnectionFn,
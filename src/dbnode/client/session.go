@@ -39,6 +39,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/namespace"
 	"github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/convert"
 	"github.com/m3db/m3/src/dbnode/runtime"
+	"github.com/m3db/m3/src/dbnode/sharding"
 	"github.com/m3db/m3/src/dbnode/storage/block"
 	"github.com/m3db/m3/src/dbnode/storage/bootstrap/result"
 	"github.com/m3db/m3/src/dbnode/storage/index"
@@ -114,6 +115,9 @@ var (
 	errUnableToEncodeTags = errors.New("unable to include tags")
 	// errEnqueueChIsClosed is returned when attempting to use a closed enqueuCh.
 	errEnqueueChIsClosed = errors.New("error enqueueCh is cosed")
+	// errSessionNoAvailableReplicaForShard is raised when a shard has no
+	// available replica to serve a request that requires every shard.
+	errSessionNoAvailableReplicaForShard = errors.New("session has no available replica for shard")
 )
 
 // sessionState is volatile state that is protected by a
@@ -163,6 +167,8 @@ type session struct {
 	streamBlocksBatchTimeout             time.Duration
 	writeShardsInitializing              bool
 	shardsLeavingCountTowardsConsistency bool
+	streamingConns                       streamingConns
+	newStreamingConnFn                   newStreamingConnFn
 	metrics                              sessionMetrics
 }
 
@@ -287,6 +293,7 @@ func newSession(opts Options) (clientSession, error) {
 		fetchBatchSize:       opts.FetchBatchSize(),
 		newPeerBlocksQueueFn: newPeerBlocksQueue,
 		healthCheckNewConnFn: healthCheck,
+		newStreamingConnFn:   newStreamingConn,
 		writeRetrier:         opts.WriteRetrier(),
 		fetchRetrier:         opts.FetchRetrier(),
 		pools: sessionPools{
@@ -1368,11 +1375,14 @@ func (s *session) writeAttemptWithRLock(
 		hostShard shard.Shard,
 		host topology.Host,
 	) {
-		if !s.writeShardsInitializing && hostShard.State() == shard.Initializing {
+		if !s.writeShardsInitializing && hostShard.State() == shard.Initializing &&
+			!isSplittingShard(hostShard) {
 			// NB(r): Do not write to this node as the shard is initializing
 			// and writing to intialized shards is not enabled (also
 			// depending on your config initializing shards won't count
 			// towards quorum, current defaults, so this is ok consistency wise).
+			// Shards being split are still written to since the node keeps
+			// serving them from their parent shard.
 			return
 		}
 
@@ -2125,6 +2135,7 @@ func (s *session) Close() error {
 
 	topoWatch.Close()
 	topo.Close()
+	s.streamingConns.close()
 
 	if closer := s.runtimeOptsListenerCloser; closer != nil {
 		closer.Close()
@@ -2211,6 +2222,470 @@ func (s *session) Truncate(namespace ident.ID) (int64, error) {
 	return truncated, resultErr.FinalError()
 }
 
+func (s *session) DeleteSeries(
+	namespace ident.ID,
+	q index.Query,
+	start, end xtime.UnixNano,
+) (int64, error) {
+	request, err := convert.ToRPCDeleteSeriesRequest(namespace, q, start, end)
+	if err != nil {
+		return 0, xerrors.NewInvalidParamsError(err)
+	}
+
+	var (
+		wg         sync.WaitGroup
+		enqueueErr xerrors.MultiError
+		resultLock sync.Mutex
+		resultErr  xerrors.MultiError
+		// Each shard is deleted on every replica, so take the largest number
+		// of series deleted for each shard across replicas rather than the
+		// sum. Hosts that do not return per shard counts only contribute
+		// their total.
+		deletedByShard = make(map[int32]int64)
+		deletedTotal   int64
+	)
+
+	d := &deleteSeriesOp{request: request}
+	d.completionFn = func(result interface{}, err error) {
+		defer wg.Done()
+
+		resultLock.Lock()
+		defer resultLock.Unlock()
+		if err != nil {
+			resultErr = resultErr.Add(err)
+			return
+		}
+
+		res := result.(*rpc.DeleteSeriesResult_)
+		if !res.IsSetNumSeriesByShard() {
+			if res.NumSeries > deletedTotal {
+				deletedTotal = res.NumSeries
+			}
+			return
+		}
+		for shard, n := range res.NumSeriesByShard {
+			if n > deletedByShard[shard] {
+				deletedByShard[shard] = n
+			}
+		}
+	}
+
+	s.state.RLock()
+	for idx := range s.state.queues {
+		wg.Add(1)
+		if err := s.state.queues[idx].Enqueue(d); err != nil {
+			wg.Done()
+			enqueueErr = enqueueErr.Add(err)
+		}
+	}
+	s.state.RUnlock()
+
+	if err := enqueueErr.FinalError(); err != nil {
+		s.log.Error("failed to enqueue request", zap.Error(err))
+		return 0, err
+	}
+
+	// Wait for series to be tombstoned on all replicas
+	wg.Wait()
+
+	var deleted int64
+	for _, n := range deletedByShard {
+		deleted += n
+	}
+	if deletedTotal > deleted {
+		deleted = deletedTotal
+	}
+	return deleted, resultErr.FinalError()
+}
+
+func (s *session) WriteExemplars(
+	namespace ident.ID,
+	series []SeriesExemplars,
+) error {
+	if len(series) == 0 {
+		return nil
+	}
+
+	var (
+		wg            sync.WaitGroup
+		enqueueErr    xerrors.MultiError
+		resultErrLock sync.Mutex
+		resultErr     xerrors.MultiError
+		pending       = make([]int, len(series))
+		successes     = make([]int32, len(series))
+		opsByQueueIdx = make(map[int]*writeExemplarsOp)
+	)
+
+	s.state.RLock()
+	if s.state.status != statusOpen {
+		s.state.RUnlock()
+		return errSessionStatusNotOpen
+	}
+
+	var (
+		topoMap    = s.state.topoMap
+		majority   = topoMap.MajorityReplicas()
+		writeLevel = s.state.writeLevel
+	)
+	for i, elem := range series {
+		rpcElem := &rpc.WriteExemplarsRequestElement{
+			ID:        elem.ID.Bytes(),
+			Exemplars: convert.ToRPCExemplars(elem.Exemplars),
+		}
+		err := topoMap.RouteForEach(elem.ID, func(
+			idx int,
+			hostShard shard.Shard,
+			_ topology.Host,
+		) {
+			if !s.writeShardsInitializing && hostShard.State() == shard.Initializing &&
+				!isSplittingShard(hostShard) {
+				// Do not write to this node as the shard is initializing and
+				// writing to initializing shards is not enabled.
+				return
+			}
+
+			op, ok := opsByQueueIdx[idx]
+			if !ok {
+				op = &writeExemplarsOp{
+					request: rpc.WriteExemplarsRequest{NameSpace: namespace.Bytes()},
+				}
+				opsByQueueIdx[idx] = op
+			}
+			op.request.Elements = append(op.request.Elements, rpcElem)
+			op.seriesIdxs = append(op.seriesIdxs, i)
+			pending[i]++
+		})
+		if err != nil {
+			s.state.RUnlock()
+			return err
+		}
+	}
+
+	for idx, op := range opsByQueueIdx {
+		op := op
+		op.completionFn = func(_ interface{}, err error) {
+			if err != nil {
+				resultErrLock.Lock()
+				resultErr = resultErr.Add(err)
+				resultErrLock.Unlock()
+			} else {
+				for _, i := range op.seriesIdxs {
+					atomic.AddInt32(&successes[i], 1)
+				}
+			}
+			wg.Done()
+		}
+
+		wg.Add(1)
+		if err := s.state.queues[idx].Enqueue(op); err != nil {
+			wg.Done()
+			enqueueErr = enqueueErr.Add(err)
+		}
+	}
+	s.state.RUnlock()
+
+	if err := enqueueErr.FinalError(); err != nil {
+		s.log.Error("failed to enqueue request", zap.Error(err))
+		return err
+	}
+
+	// Wait for exemplars to be written to all replicas
+	wg.Wait()
+
+	for i := range series {
+		success := int(atomic.LoadInt32(&successes[i]))
+		if !topology.WriteConsistencyAchieved(writeLevel, majority, pending[i], success) {
+			return consistencyResultErr{
+				level:       writeLevel,
+				success:     success,
+				enqueued:    pending[i],
+				responded:   pending[i],
+				topLevelErr: resultErr.FinalError(),
+				errs:        resultErr.Errors(),
+			}
+		}
+	}
+	return nil
+}
+
+func (s *session) FetchExemplars(
+	ctx gocontext.Context,
+	namespace ident.ID,
+	q index.Query,
+	opts index.QueryOptions,
+) ([]SeriesExemplars, FetchResponseMetadata, error) {
+	request, err := convert.ToRPCFetchExemplarsRequest(namespace, q, opts)
+	if err != nil {
+		return nil, FetchResponseMetadata{}, xerrors.NewInvalidParamsError(err)
+	}
+
+	var (
+		wg              sync.WaitGroup
+		enqueueErr      xerrors.MultiError
+		resultLock      sync.Mutex
+		resultErr       xerrors.MultiError
+		successByShard  = make(map[uint32]int)
+		seriesByID      = make(map[string]*SeriesExemplars)
+		metadata        = FetchResponseMetadata{Exhaustive: true}
+		decodeExemplars = func(res *rpc.FetchExemplarsResult_) error {
+			for _, elem := range res.Elements {
+				exemplars, err := convert.FromRPCExemplars(elem.Exemplars)
+				if err != nil {
+					return err
+				}
+
+				series, ok := seriesByID[string(elem.ID)]
+				if !ok {
+					md, err := idxconvert.FromSeriesIDAndEncodedTags(elem.ID, elem.EncodedTags)
+					if err != nil {
+						return err
+					}
+					tags := make([]ident.Tag, 0, len(md.Fields))
+					for _, field := range md.Fields {
+						tags = append(tags, ident.Tag{
+							Name:  ident.BytesID(field.Name),
+							Value: ident.BytesID(field.Value),
+						})
+					}
+					series = &SeriesExemplars{
+						ID:   ident.BytesID(md.ID),
+						Tags: ident.NewTags(tags...),
+					}
+					seriesByID[string(elem.ID)] = series
+				}
+				series.Exemplars = mergeExemplars(series.Exemplars, exemplars)
+			}
+			return nil
+		}
+	)
+
+	s.state.RLock()
+	if s.state.status != statusOpen {
+		s.state.RUnlock()
+		return nil, FetchResponseMetadata{}, errSessionStatusNotOpen
+	}
+
+	var (
+		topoMap   = s.state.topoMap
+		majority  = topoMap.MajorityReplicas()
+		replicas  = topoMap.Replicas()
+		readLevel = s.state.readLevel
+	)
+	for idx := range s.state.queues {
+		hostShardSet, ok := topoMap.LookupHostShardSet(s.state.queues[idx].Host().ID())
+		if !ok {
+			continue
+		}
+
+		op := &fetchExemplarsOp{context: ctx, request: request}
+		op.completionFn = func(result interface{}, err error) {
+			defer wg.Done()
+
+			resultLock.Lock()
+			defer resultLock.Unlock()
+			if err == nil {
+				res := result.(*rpc.FetchExemplarsResult_)
+				err = decodeExemplars(res)
+				metadata.Exhaustive = metadata.Exhaustive && res.Exhaustive
+				metadata.Responses++
+			}
+			if err != nil {
+				resultErr = resultErr.Add(err)
+				return
+			}
+			for _, hostShard := range hostShardSet.ShardSet().All() {
+				if shardAvailableForReads(hostShard) {
+					successByShard[hostShard.ID()]++
+				}
+			}
+		}
+
+		wg.Add(1)
+		if err := s.state.queues[idx].Enqueue(op); err != nil {
+			wg.Done()
+			enqueueErr = enqueueErr.Add(err)
+		}
+	}
+	s.state.RUnlock()
+
+	if err := enqueueErr.FinalError(); err != nil {
+		s.log.Error("failed to enqueue request", zap.Error(err))
+		return nil, FetchResponseMetadata{}, err
+	}
+
+	// Wait for all replicas to respond
+	wg.Wait()
+
+	for _, shardID := range topoMap.ShardSet().AllIDs() {
+		success := successByShard[shardID]
+		if !topology.ReadConsistencyAchieved(readLevel, majority, replicas, success) {
+			return nil, FetchResponseMetadata{}, consistencyResultErr{
+				level:       readLevel,
+				success:     success,
+				enqueued:    replicas,
+				responded:   replicas,
+				topLevelErr: resultErr.FinalError(),
+				errs:        resultErr.Errors(),
+			}
+		}
+	}
+
+	results := make([]SeriesExemplars, 0, len(seriesByID))
+	for _, series := range seriesByID {
+		results = append(results, *series)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return bytes.Compare(results[i].ID.Bytes(), results[j].ID.Bytes()) < 0
+	})
+	return results, metadata, nil
+}
+
+func (s *session) Cardinality(
+	ctx gocontext.Context,
+	namespace ident.ID,
+	opts index.CardinalityOptions,
+) (index.CardinalityResult, error) {
+	request, err := convert.ToRPCCardinalityRequest(namespace, opts)
+	if err != nil {
+		return index.CardinalityResult{}, xerrors.NewInvalidParamsError(err)
+	}
+
+	s.state.RLock()
+	if s.state.status != statusOpen {
+		s.state.RUnlock()
+		return index.CardinalityResult{}, errSessionStatusNotOpen
+	}
+
+	// Each series must be counted exactly once, so assign every shard to a
+	// single host with the shard available and query each host only for
+	// the shards assigned to it.
+	queueIdxs, shardsByQueue, err := s.assignShardsWithRLock(opts.Shards)
+	if err != nil {
+		s.state.RUnlock()
+		return index.CardinalityResult{}, err
+	}
+
+	var (
+		wg         sync.WaitGroup
+		enqueueErr xerrors.MultiError
+		resultLock sync.Mutex
+		resultErr  xerrors.MultiError
+		results    = make([]index.CardinalityResult, 0, len(queueIdxs))
+	)
+	for _, idx := range queueIdxs {
+		hostRequest := request
+		hostRequest.Shards = make([]int32, 0, len(shardsByQueue[idx]))
+		for _, shardID := range shardsByQueue[idx] {
+			hostRequest.Shards = append(hostRequest.Shards, int32(shardID))
+		}
+
+		op := &cardinalityOp{context: ctx, request: hostRequest}
+		op.completionFn = func(result interface{}, err error) {
+			defer wg.Done()
+
+			resultLock.Lock()
+			defer resultLock.Unlock()
+			if err != nil {
+				resultErr = resultErr.Add(err)
+				return
+			}
+			res := result.(*rpc.CardinalityResult_)
+			results = append(results, convert.FromRPCCardinalityResult(res))
+		}
+
+		wg.Add(1)
+		if err := s.state.queues[idx].Enqueue(op); err != nil {
+			wg.Done()
+			enqueueErr = enqueueErr.Add(err)
+		}
+	}
+	s.state.RUnlock()
+
+	if err := enqueueErr.FinalError(); err != nil {
+		s.log.Error("failed to enqueue request", zap.Error(err))
+		return index.CardinalityResult{}, err
+	}
+
+	// Wait for all hosts to respond
+	wg.Wait()
+
+	if err := resultErr.FinalError(); err != nil {
+		return index.CardinalityResult{}, err
+	}
+	return index.MergeCardinalityResults(results, opts.Limit), nil
+}
+
+// assignShardsWithRLock assigns each shard, or each of the requested shards
+// if any are specified, to a single host queue that has the shard available
+// for reads. It returns the indexes of the queues with shards assigned and
+// the shards assigned to each of them. Must be called with the state read
+// lock held.
+func (s *session) assignShardsWithRLock(
+	requestedShards []uint32,
+) ([]int, map[int][]uint32, error) {
+	var (
+		topoMap        = s.state.topoMap
+		assigned       = make(map[uint32]struct{})
+		shardsByQueue  = make(map[int][]uint32)
+		queueIdxs      []int
+		requestedShard = func(uint32) bool { return true }
+	)
+	if len(requestedShards) > 0 {
+		requested := make(map[uint32]struct{}, len(requestedShards))
+		for _, shardID := range requestedShards {
+			requested[shardID] = struct{}{}
+		}
+		requestedShard = func(shardID uint32) bool {
+			_, ok := requested[shardID]
+			return ok
+		}
+	}
+	for idx := range s.state.queues {
+		hostShardSet, ok := topoMap.LookupHostShardSet(s.state.queues[idx].Host().ID())
+		if !ok {
+			continue
+		}
+		for _, hostShard := range hostShardSet.ShardSet().All() {
+			shardID := hostShard.ID()
+			if !shardAvailableForReads(hostShard) || !requestedShard(shardID) {
+				continue
+			}
+			if _, ok := assigned[shardID]; ok {
+				continue
+			}
+			assigned[shardID] = struct{}{}
+			if _, ok := shardsByQueue[idx]; !ok {
+				queueIdxs = append(queueIdxs, idx)
+			}
+			shardsByQueue[idx] = append(shardsByQueue[idx], shardID)
+		}
+	}
+	for _, shardID := range topoMap.ShardSet().AllIDs() {
+		if _, ok := assigned[shardID]; !ok && requestedShard(shardID) {
+			return nil, nil, fmt.Errorf("%w: shard=%d",
+				errSessionNoAvailableReplicaForShard, shardID)
+		}
+	}
+	return queueIdxs, shardsByQueue, nil
+}
+
+// mergeExemplars merges the exemplars returned by a replica with those
+// already fetched, removing duplicates and keeping timestamp order.
+func mergeExemplars(existing, exemplars []ts.Exemplar) []ts.Exemplar {
+	merged := append(existing, exemplars...)
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].TimestampNanos < merged[j].TimestampNanos
+	})
+	result := merged[:0]
+	for _, exemplar := range merged {
+		if n := len(result); n > 0 && result[n-1].Equal(exemplar) {
+			continue
+		}
+		result = append(result, exemplar)
+	}
+	return result
+}
+
 // NB(r): Excluding maligned struct check here as we can
 // live with a few extra bytes since this struct is only
 // ever passed by stack, its much more readable not optimized
@@ -2223,6 +2698,20 @@ type peers struct {
 	selfHostShardSet topology.HostShardSet
 }
 
+// isSplittingShard returns whether a shard is being split out of a parent
+// shard on the same host, which keeps serving reads and writes for the shard
+// from the parent shard until the split completes.
+func isSplittingShard(s shard.Shard) bool {
+	_, ok := sharding.SplitParentShardID(s)
+	return ok
+}
+
+// shardAvailableForReads returns whether reads from a shard count towards
+// consistency.
+func shardAvailableForReads(s shard.Shard) bool {
+	return s.State() == shard.Available || isSplittingShard(s)
+}
+
 func (p peers) selfExcludedAndSelfHasShardAvailable() bool {
 	if !p.selfExcluded {
 		return false
@@ -2397,6 +2886,59 @@ func (s *session) FetchBootstrapBlocksFromPeers(
 	return result.result, nil
 }
 
+func (s *session) FetchMerkleTreeNodesFromPeer(
+	peer topology.Host,
+	opts FetchMerkleTreeNodesOptions,
+) (MerkleTreeNodes, error) {
+	req := rpc.NewFetchMerkleTreeRequest()
+	req.NameSpace = opts.Namespace.Bytes()
+	req.Shard = int32(opts.Shard)
+	req.BlockStart = int64(opts.BlockStart)
+	req.Depth = int32(opts.Depth)
+	req.Level = int32(opts.Level)
+	req.IncludeLeafEntries = opts.IncludeLeafEntries
+	if len(opts.Indices) > 0 {
+		req.Indices = make([]int32, 0, len(opts.Indices))
+		for _, idx := range opts.Indices {
+			req.Indices = append(req.Indices, int32(idx))
+		}
+	}
+
+	var (
+		result   *rpc.FetchMerkleTreeResult_
+		fetchErr error
+	)
+	borrowErr := s.BorrowConnection(peer.ID(), func(client rpc.TChanNode, _ Channel) {
+		tctx, _ := thrift.NewContext(s.opts.FetchRequestTimeout())
+		result, fetchErr = client.FetchMerkleTree(tctx, req)
+	})
+	if borrowErr != nil {
+		return MerkleTreeNodes{}, borrowErr
+	}
+	if fetchErr != nil {
+		return MerkleTreeNodes{}, fetchErr
+	}
+
+	nodes := MerkleTreeNodes{
+		Digests: make([]uint64, 0, len(result.Digests)),
+	}
+	for _, digest := range result.Digests {
+		nodes.Digests = append(nodes.Digests, uint64(digest))
+	}
+	if len(result.LeafEntries) > 0 {
+		nodes.LeafEntries = make([]MerkleTreeLeafEntry, 0, len(result.LeafEntries))
+		for _, entry := range result.LeafEntries {
+			nodes.LeafEntries = append(nodes.LeafEntries, MerkleTreeLeafEntry{
+				Leaf:     int(entry.Leaf),
+				ID:       ident.BytesID(entry.ID),
+				Size:     entry.Size,
+				Checksum: uint32(entry.Checksum),
+			})
+		}
+	}
+	return nodes, nil
+}
+
 func (s *session) FetchBlocksFromPeers(
 	nsMetadata namespace.Metadata,
 	shard uint32,
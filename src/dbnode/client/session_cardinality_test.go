@@ -0,0 +1,94 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/convert"
+	"github.com/m3db/m3/src/dbnode/storage/index"
+	"github.com/m3db/m3/src/x/ident"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionCardinality(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	opts := newSessionTestOptions()
+	s, err := newSession(opts)
+	require.NoError(t, err)
+	session := s.(*session)
+
+	mockHostQueues(ctrl, session, sessionTestReplicas, nil)
+	require.NoError(t, session.Open())
+
+	// Every shard is available on every replica so all shards are assigned
+	// to the first host queue and no other host is queried.
+	queue := session.state.queues[0].(*MockhostQueue)
+	queue.EXPECT().Enqueue(gomock.Any()).DoAndReturn(func(o op) error {
+		op, ok := o.(*cardinalityOp)
+		require.True(t, ok)
+		assert.Equal(t, "metrics", op.request.NameSpace)
+		assert.Equal(t, []int32{0, 1, 2}, op.request.Shards)
+		op.completionFn(convert.ToRPCCardinalityResult(index.CardinalityResult{
+			NumSeries:               2,
+			SeriesCountByMetricName: []index.CardinalityStat{{Name: "up", Value: 2}},
+		}), nil)
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	now := xtime.Now()
+	result, err := s.Cardinality(ctx, ident.StringID("metrics"), index.CardinalityOptions{
+		StartInclusive: now.Add(-time.Hour),
+		EndExclusive:   now,
+		NameField:      []byte("__name__"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), result.NumSeries)
+	assert.Equal(t, []index.CardinalityStat{{Name: "up", Value: 2}},
+		result.SeriesCountByMetricName)
+
+	// Only the requested shards are queried and host errors are returned.
+	queue.EXPECT().Enqueue(gomock.Any()).DoAndReturn(func(o op) error {
+		op := o.(*cardinalityOp)
+		assert.Equal(t, []int32{1}, op.request.Shards)
+		op.completionFn(nil, errors.New("an error"))
+		return nil
+	})
+	_, err = s.Cardinality(ctx, ident.StringID("metrics"), index.CardinalityOptions{
+		StartInclusive: now.Add(-time.Hour),
+		EndExclusive:   now,
+		Shards:         []uint32{1},
+	})
+	require.Error(t, err)
+
+	require.NoError(t, session.Close())
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package client
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/generated/thrift/rpc"
+	"github.com/m3db/m3/src/dbnode/storage/index"
+	"github.com/m3db/m3/src/m3ninx/idx"
+	"github.com/m3db/m3/src/x/ident"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionDeleteSeriesCountsEachShardOnce(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	opts := newSessionTestOptions()
+	s, err := newSession(opts)
+	require.NoError(t, err)
+	session := s.(*session)
+
+	mockHostQueues(ctrl, session, sessionTestReplicas, nil)
+	require.NoError(t, session.Open())
+
+	// Every replica owns every shard and has indexed a different subset of
+	// the series, the last replica does not return per shard counts.
+	results := []*rpc.DeleteSeriesResult_{
+		{NumSeries: 6, NumSeriesByShard: map[int32]int64{0: 3, 1: 3}},
+		{NumSeries: 6, NumSeriesByShard: map[int32]int64{0: 2, 1: 4}},
+		{NumSeries: 5},
+	}
+	require.Len(t, session.state.queues, len(results))
+	for i, queue := range session.state.queues {
+		res := results[i]
+		queue.(*MockhostQueue).EXPECT().Enqueue(gomock.Any()).DoAndReturn(func(o op) error {
+			op, ok := o.(*deleteSeriesOp)
+			require.True(t, ok)
+			assert.Equal(t, []byte("metrics"), op.request.NameSpace)
+			op.completionFn(res, nil)
+			return nil
+		})
+	}
+
+	now := xtime.Now()
+	deleted, err := s.DeleteSeries(ident.StringID("metrics"), index.Query{
+		Query: idx.NewTermQuery([]byte("foo"), []byte("bar")),
+	}, now.Add(-time.Hour), now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), deleted)
+
+	require.NoError(t, session.Close())
+}
@@ -0,0 +1,150 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/generated/thrift/rpc"
+	"github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/convert"
+	"github.com/m3db/m3/src/dbnode/storage/index"
+	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/m3ninx/idx"
+	"github.com/m3db/m3/src/x/ident"
+	"github.com/m3db/m3/src/x/serialize"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testExemplar(t xtime.UnixNano, traceID string) ts.Exemplar {
+	return ts.Exemplar{
+		TimestampNanos: t,
+		Value:          1,
+		Labels: []ts.ExemplarLabel{
+			{Name: []byte("trace_id"), Value: []byte(traceID)},
+		},
+	}
+}
+
+func TestSessionWriteExemplars(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	opts := newSessionTestOptions()
+	s, err := newSession(opts)
+	require.NoError(t, err)
+	session := s.(*session)
+
+	now := xtime.Now()
+	mockHostQueues(ctrl, session, sessionTestReplicas, []testEnqueueFn{
+		func(idx int, op op) {
+			write, ok := op.(*writeExemplarsOp)
+			require.True(t, ok)
+			assert.Equal(t, []byte("metrics"), write.request.NameSpace)
+			require.Equal(t, 1, len(write.request.Elements))
+			assert.Equal(t, []byte("foo"), write.request.Elements[0].ID)
+			assert.Equal(t, 1, len(write.request.Elements[0].Exemplars))
+
+			var err error
+			if idx == 0 {
+				err = errors.New("an error")
+			}
+			write.completionFn(nil, err)
+		},
+	})
+
+	require.NoError(t, session.Open())
+
+	// A majority of replicas succeeding is sufficient.
+	require.NoError(t, s.WriteExemplars(ident.StringID("metrics"), []SeriesExemplars{
+		{ID: ident.StringID("foo"), Exemplars: []ts.Exemplar{testExemplar(now, "a")}},
+	}))
+
+	require.NoError(t, session.Close())
+}
+
+func TestSessionFetchExemplars(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	opts := newSessionTestOptions()
+	s, err := newSession(opts)
+	require.NoError(t, err)
+	session := s.(*session)
+
+	encoder := serialize.NewTagEncoderPool(serialize.NewTagEncoderOptions(), nil)
+	encoder.Init()
+	enc := encoder.Get()
+	require.NoError(t, enc.Encode(ident.MustNewTagStringsIterator("foo", "bar")))
+	encodedTags, ok := enc.Data()
+	require.True(t, ok)
+
+	var (
+		now    = xtime.Now()
+		first  = testExemplar(now, "a")
+		second = testExemplar(now.Add(time.Second), "b")
+	)
+	mockHostQueues(ctrl, session, sessionTestReplicas, []testEnqueueFn{
+		func(idx int, op op) {
+			fetch, ok := op.(*fetchExemplarsOp)
+			require.True(t, ok)
+			assert.Equal(t, []byte("metrics"), fetch.request.NameSpace)
+
+			// Replicas return overlapping exemplars that are merged.
+			exemplars := []ts.Exemplar{first}
+			if idx == 0 {
+				exemplars = append(exemplars, second)
+			}
+			fetch.completionFn(&rpc.FetchExemplarsResult_{
+				Elements: []*rpc.FetchExemplarsResultElement{{
+					ID:          []byte("foo"),
+					EncodedTags: encodedTags.Bytes(),
+					Exemplars:   convert.ToRPCExemplars(exemplars),
+				}},
+				Exhaustive: true,
+			}, nil)
+		},
+	})
+
+	require.NoError(t, session.Open())
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	results, metadata, err := s.FetchExemplars(ctx, ident.StringID("metrics"),
+		index.Query{Query: idx.NewTermQuery([]byte("foo"), []byte("bar"))},
+		index.QueryOptions{StartInclusive: now, EndExclusive: now.Add(time.Minute)})
+	require.NoError(t, err)
+	assert.True(t, metadata.Exhaustive)
+	assert.Equal(t, sessionTestReplicas, metadata.Responses)
+	require.Equal(t, 1, len(results))
+	assert.Equal(t, "foo", results[0].ID.String())
+	require.Equal(t, 1, len(results[0].Tags.Values()))
+	assert.Equal(t, "bar", results[0].Tags.Values()[0].Value.String())
+	assert.Equal(t, []ts.Exemplar{first, second}, results[0].Exemplars)
+
+	require.NoError(t, session.Close())
+}
@@ -0,0 +1,375 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package client
+
+import (
+	gocontext "context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/dbnode/generated/proto/streaming"
+	grpcconvert "github.com/m3db/m3/src/dbnode/network/server/grpc/convert"
+	"github.com/m3db/m3/src/dbnode/storage/limits"
+	"github.com/m3db/m3/src/dbnode/topology"
+	"github.com/m3db/m3/src/x/ident"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	grpcstatus "google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type testStreamingNode struct {
+	sync.Mutex
+	requests  []*streaming.FetchTaggedRequest
+	responses []*streaming.FetchTaggedResponse
+	err       error
+}
+
+func (n *testStreamingNode) FetchTagged(
+	req *streaming.FetchTaggedRequest,
+	stream streaming.Node_FetchTaggedServer,
+) error {
+	n.Lock()
+	n.requests = append(n.requests, req)
+	n.Unlock()
+	for _, r := range n.responses {
+		if err := stream.Send(r); err != nil {
+			return err
+		}
+	}
+	return n.err
+}
+
+func newStreamingTestSession(
+	t *testing.T,
+	ctrl *gomock.Controller,
+	node streaming.NodeServer,
+) (*session, func()) {
+	return newStreamingTestSessionWithOptions(t, ctrl, node, newSessionTestOptions().
+		SetReadConsistencyLevel(topology.ReadConsistencyLevelOne))
+}
+
+func newStreamingTestSessionWithOptions(
+	t *testing.T,
+	ctrl *gomock.Controller,
+	node streaming.NodeServer,
+	opts Options,
+) (*session, func()) {
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	streaming.RegisterNodeServer(server, node)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	s, err := newSession(opts.SetStreamingReadsEnabled(true))
+	require.NoError(t, err)
+	session := s.(*session)
+	session.newStreamingConnFn = func(address string) (*grpc.ClientConn, error) {
+		_, port, err := net.SplitHostPort(address)
+		require.NoError(t, err)
+		assert.Equal(t, "9005", port)
+		return grpc.Dial(address, grpc.WithInsecure(),
+			grpc.WithContextDialer(func(gocontext.Context, string) (net.Conn, error) {
+				return listener.Dial()
+			}))
+	}
+
+	mockHostQueues(ctrl, session, sessionTestReplicas, nil)
+	require.NoError(t, session.Open())
+
+	return session, func() {
+		assert.NoError(t, session.Close())
+		server.Stop()
+	}
+}
+
+func testStreamingElement(
+	th testFetchTaggedHelper,
+	id string,
+	tags ident.Tags,
+	dps testDatapoints,
+	start xtime.UnixNano,
+) *streaming.FetchTaggedElement {
+	return &streaming.FetchTaggedElement{
+		Id:          []byte(id),
+		EncodedTags: th.encodeTags(tags),
+		Segments:    grpcconvert.ToStreamingSegments(dps.toRPCSegments(th, start)),
+	}
+}
+
+func TestSessionFetchTaggedStream(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		th    = newTestFetchTaggedHelper(t)
+		start = xtime.Now().Truncate(time.Hour)
+		end   = start.Add(time.Hour)
+		dps   = newTestDatapoints(10, start, end)
+		node  = &testStreamingNode{
+			responses: []*streaming.FetchTaggedResponse{
+				{
+					Elements: []*streaming.FetchTaggedElement{
+						testStreamingElement(th, "foo",
+							ident.NewTags(ident.StringTag("a", "b")), dps, start),
+					},
+				},
+				{
+					Elements: []*streaming.FetchTaggedElement{
+						testStreamingElement(th, "bar",
+							ident.NewTags(ident.StringTag("a", "c")), dps, start),
+					},
+					Done:             true,
+					Exhaustive:       true,
+					WaitedIndex:      1,
+					WaitedSeriesRead: 2,
+				},
+			},
+		}
+	)
+
+	session, closeFn := newStreamingTestSession(t, ctrl, node)
+	defer closeFn()
+
+	var ids []string
+	meta, err := session.FetchTaggedStream(testContext(), ident.StringID("namespace"),
+		testSessionFetchTaggedQuery, testSessionFetchTaggedQueryOpts(start, end),
+		func(iter encoding.SeriesIterator) error {
+			defer iter.Close()
+			ids = append(ids, iter.ID().String())
+			require.True(t, iter.Tags().Next())
+			assert.Equal(t, "a", iter.Tags().Current().Name.String())
+			dps.assertMatchesEncodingIter(t, iter)
+			return nil
+		})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"foo", "bar"}, ids)
+	assert.True(t, meta.Exhaustive)
+	assert.Equal(t, 2, meta.Responses)
+	assert.Equal(t, 1, meta.WaitedIndex)
+	assert.Equal(t, 2, meta.WaitedSeriesRead)
+
+	// Every shard is streamed from a single replica.
+	require.Len(t, node.requests, 1)
+	req := node.requests[0]
+	assert.Equal(t, []uint32{0, 1, 2}, req.Shards)
+	assert.Equal(t, int64(defaultStreamingReadsBatchSize), req.BatchSize)
+	assert.Equal(t, int64(start), req.RangeStartNanos)
+	assert.Equal(t, int64(end), req.RangeEndNanos)
+	assert.True(t, req.FetchData)
+}
+
+func TestSessionFetchTaggedStreamSeriesLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		th    = newTestFetchTaggedHelper(t)
+		start = xtime.Now().Truncate(time.Hour)
+		end   = start.Add(time.Hour)
+		dps   = newTestDatapoints(5, start, end)
+		node  = &testStreamingNode{
+			responses: []*streaming.FetchTaggedResponse{
+				{
+					Elements: []*streaming.FetchTaggedElement{
+						testStreamingElement(th, "foo", ident.Tags{}, dps, start),
+						testStreamingElement(th, "bar", ident.Tags{}, dps, start),
+					},
+					Done:       true,
+					Exhaustive: true,
+				},
+			},
+		}
+	)
+
+	session, closeFn := newStreamingTestSession(t, ctrl, node)
+	defer closeFn()
+
+	opts := testSessionFetchTaggedQueryOpts(start, end)
+	opts.SeriesLimit = 1
+	calls := 0
+	meta, err := session.FetchTaggedStream(testContext(), ident.StringID("namespace"),
+		testSessionFetchTaggedQuery, opts,
+		func(iter encoding.SeriesIterator) error {
+			iter.Close()
+			calls++
+			return nil
+		})
+	require.NoError(t, err)
+	assert.Equal(t, 1, calls)
+	assert.False(t, meta.Exhaustive)
+}
+
+func TestSessionFetchTaggedStreamLimitsRequireExhaustive(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		th       = newTestFetchTaggedHelper(t)
+		start    = xtime.Now().Truncate(time.Hour)
+		end      = start.Add(time.Hour)
+		dps      = newTestDatapoints(5, start, end)
+		response = &streaming.FetchTaggedResponse{
+			Elements: []*streaming.FetchTaggedElement{
+				testStreamingElement(th, "foo", ident.Tags{}, dps, start),
+				testStreamingElement(th, "bar", ident.Tags{}, dps, start),
+			},
+			Done:       true,
+			Exhaustive: true,
+		}
+	)
+
+	tests := []struct {
+		name        string
+		maxBytes    int
+		seriesLimit int
+	}{
+		{name: "series limit", seriesLimit: 1},
+		{name: "bytes limit", maxBytes: response.Size() - 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := &testStreamingNode{responses: []*streaming.FetchTaggedResponse{response}}
+			session, closeFn := newStreamingTestSessionWithOptions(t, ctrl, node,
+				newSessionTestOptions().
+					SetReadConsistencyLevel(topology.ReadConsistencyLevelOne).
+					SetStreamingReadsMaxBytes(tt.maxBytes))
+			defer closeFn()
+
+			for _, requireExhaustive := range []bool{false, true} {
+				opts := testSessionFetchTaggedQueryOpts(start, end)
+				opts.SeriesLimit = tt.seriesLimit
+				opts.RequireExhaustive = requireExhaustive
+				meta, err := session.FetchTaggedStream(testContext(), ident.StringID("namespace"),
+					testSessionFetchTaggedQuery, opts,
+					func(iter encoding.SeriesIterator) error {
+						iter.Close()
+						return nil
+					})
+				if requireExhaustive {
+					require.Error(t, err)
+					assert.True(t, limits.IsQueryLimitExceededError(err))
+					assert.True(t, IsBadRequestError(err))
+					continue
+				}
+				require.NoError(t, err)
+				assert.False(t, meta.Exhaustive)
+			}
+		})
+	}
+}
+
+func TestSessionFetchTaggedStreamReadConsistencyLevel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	node := &testStreamingNode{}
+	session, closeFn := newStreamingTestSessionWithOptions(t, ctrl, node,
+		newSessionTestOptions().SetReadConsistencyLevel(topology.ReadConsistencyLevelMajority))
+	defer closeFn()
+
+	// Each shard is streamed from a single replica, so reads at stricter
+	// consistency levels must use FetchTagged.
+	assert.False(t, session.StreamingReadsEnabled())
+
+	start := xtime.Now().Truncate(time.Hour)
+	_, err := session.FetchTaggedStream(testContext(), ident.StringID("namespace"),
+		testSessionFetchTaggedQuery, testSessionFetchTaggedQueryOpts(start, start.Add(time.Hour)),
+		func(iter encoding.SeriesIterator) error {
+			iter.Close()
+			return nil
+		})
+	require.Error(t, err)
+	assert.Empty(t, node.requests)
+
+	session.state.Lock()
+	session.state.readLevel = topology.ReadConsistencyLevelOne
+	session.state.Unlock()
+	assert.True(t, session.StreamingReadsEnabled())
+}
+
+func TestSessionFetchTaggedStreamErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		node    *testStreamingNode
+		checkFn func(t *testing.T, err error)
+	}{
+		{
+			name: "resource exhausted",
+			node: &testStreamingNode{
+				err: grpcstatus.Error(codes.ResourceExhausted, "query limit exceeded"),
+			},
+			checkFn: func(t *testing.T, err error) {
+				assert.True(t, IsResourceExhaustedError(err))
+				assert.True(t, IsBadRequestError(err))
+			},
+		},
+		{
+			name: "ended before done",
+			node: &testStreamingNode{
+				responses: []*streaming.FetchTaggedResponse{{}},
+			},
+			checkFn: func(t *testing.T, err error) {
+				assert.Equal(t, errStreamEndedBeforeDone, err)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			session, closeFn := newStreamingTestSession(t, ctrl, tt.node)
+			defer closeFn()
+
+			start := xtime.Now().Truncate(time.Hour)
+			_, err := session.FetchTaggedStream(testContext(), ident.StringID("namespace"),
+				testSessionFetchTaggedQuery, testSessionFetchTaggedQueryOpts(start, start.Add(time.Hour)),
+				func(iter encoding.SeriesIterator) error {
+					iter.Close()
+					return nil
+				})
+			require.Error(t, err)
+			tt.checkFn(t, err)
+		})
+	}
+}
+
+func TestSessionFetchTaggedStreamNotEnabled(t *testing.T) {
+	s, err := newSession(newSessionTestOptions())
+	require.NoError(t, err)
+
+	start := xtime.Now()
+	_, err = s.FetchTaggedStream(testContext(), ident.StringID("namespace"),
+		testSessionFetchTaggedQuery, testSessionFetchTaggedQueryOpts(start, start),
+		func(iter encoding.SeriesIterator) error { return nil })
+	assert.Equal(t, errSessionStreamingReadsNotEnabled, err)
+}
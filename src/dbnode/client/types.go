@@ -36,6 +36,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/storage/bootstrap/result"
 	"github.com/m3db/m3/src/dbnode/storage/index"
 	"github.com/m3db/m3/src/dbnode/topology"
+	"github.com/m3db/m3/src/dbnode/ts"
 	"github.com/m3db/m3/src/x/clock"
 	"github.com/m3db/m3/src/x/context"
 	"github.com/m3db/m3/src/x/ident"
@@ -116,6 +117,19 @@ type Session interface {
 		opts index.QueryOptions,
 	) (encoding.SeriesIterators, FetchResponseMetadata, error)
 
+	// FetchTaggedStream resolves the provided query to known IDs and streams
+	// the data for them from the nodes, calling fn with each series as it is
+	// received rather than buffering all of the results. Each shard is read
+	// from a single replica and fn takes ownership of the series iterator.
+	// Requires streaming reads to be enabled.
+	FetchTaggedStream(
+		ctx gocontext.Context,
+		namespace ident.ID,
+		q index.Query,
+		opts index.QueryOptions,
+		fn func(encoding.SeriesIterator) error,
+	) (FetchResponseMetadata, error)
+
 	// FetchTaggedIDs resolves the provided query to known IDs.
 	FetchTaggedIDs(
 		ctx gocontext.Context,
@@ -124,6 +138,27 @@ type Session interface {
 		opts index.QueryOptions,
 	) (TaggedIDsIterator, FetchResponseMetadata, error)
 
+	// WriteExemplars writes the exemplars for each of the series to the
+	// database.
+	WriteExemplars(namespace ident.ID, series []SeriesExemplars) error
+
+	// FetchExemplars resolves the provided query to known IDs, and fetches
+	// the exemplars for them within the query time range.
+	FetchExemplars(
+		ctx gocontext.Context,
+		namespace ident.ID,
+		q index.Query,
+		opts index.QueryOptions,
+	) ([]SeriesExemplars, FetchResponseMetadata, error)
+
+	// Cardinality returns the cardinality statistics of the series indexed
+	// within the time range, each shard is queried from a single replica.
+	Cardinality(
+		ctx gocontext.Context,
+		namespace ident.ID,
+		opts index.CardinalityOptions,
+	) (index.CardinalityResult, error)
+
 	// Aggregate aggregates values from the database for the given set of constraints.
 	Aggregate(
 		ctx gocontext.Context,
@@ -144,6 +179,14 @@ type Session interface {
 	Close() error
 }
 
+// SeriesExemplars are the exemplars of a series.
+type SeriesExemplars struct {
+	ID ident.ID
+	// Tags are the series tags, they are only set for fetched exemplars.
+	Tags      ident.Tags
+	Exemplars []ts.Exemplar
+}
+
 // FetchResponseMetadata is metadata about a fetch response.
 type FetchResponseMetadata struct {
 	// Exhaustive indicates whether the underlying data set presents a full
@@ -159,6 +202,13 @@ type FetchResponseMetadata struct {
 	WaitedSeriesRead int
 }
 
+// StreamingReadsSession is implemented by sessions that report whether
+// reads can be streamed from the nodes with FetchTaggedStream.
+type StreamingReadsSession interface {
+	// StreamingReadsEnabled returns whether streaming reads are enabled.
+	StreamingReadsEnabled() bool
+}
+
 // AggregatedTagsIterator iterates over a collection of tag names with optionally
 // associated values.
 type AggregatedTagsIterator interface {
@@ -255,6 +305,15 @@ type AdminSession interface {
 	// Truncate will truncate the namespace for a given shard.
 	Truncate(namespace ident.ID) (int64, error)
 
+	// DeleteSeries tombstones the series matching the query for all blocks
+	// that overlap the given time range on every host, returning the number
+	// of series tombstoned, each counted once regardless of replication.
+	DeleteSeries(
+		namespace ident.ID,
+		q index.Query,
+		start, end xtime.UnixNano,
+	) (int64, error)
+
 	// FetchBootstrapBlocksFromPeers will fetch the most fulfilled block
 	// for each series using the runtime configurable bootstrap level consistency.
 	FetchBootstrapBlocksFromPeers(
@@ -293,6 +352,13 @@ type AdminSession interface {
 		opts result.Options,
 	) (PeerBlocksIter, error)
 
+	// FetchMerkleTreeNodesFromPeer will fetch the digests of nodes of the
+	// merkle tree of a shard block from the peer specified.
+	FetchMerkleTreeNodesFromPeer(
+		peer topology.Host,
+		opts FetchMerkleTreeNodesOptions,
+	) (MerkleTreeNodes, error)
+
 	// BorrowConnections will borrow connection for hosts belonging to a shard.
 	BorrowConnections(
 		shardID uint32,
@@ -309,6 +375,46 @@ type AdminSession interface {
 	) (rpc.TChanNode, Channel, error)
 }
 
+// FetchMerkleTreeNodesOptions are options to use when fetching the nodes of
+// the merkle tree of a shard block from a peer.
+type FetchMerkleTreeNodesOptions struct {
+	// Namespace is the namespace of the shard.
+	Namespace ident.ID
+	// Shard is the shard to fetch the merkle tree of.
+	Shard uint32
+	// BlockStart is the block start to fetch the merkle tree of.
+	BlockStart xtime.UnixNano
+	// Depth is the depth of the merkle tree.
+	Depth int
+	// Level is the level of the merkle tree to fetch the digests of nodes of.
+	Level int
+	// Indices are the indices of the nodes to fetch the digests of, all nodes
+	// of the level are fetched if none are specified.
+	Indices []int
+	// IncludeLeafEntries will include the checksums of the series blocks that
+	// belong to the nodes when fetching leaves.
+	IncludeLeafEntries bool
+}
+
+// MerkleTreeNodes are the nodes of the merkle tree of a shard block fetched
+// from a peer.
+type MerkleTreeNodes struct {
+	// Digests are the digests of the nodes in the order requested.
+	Digests []uint64
+	// LeafEntries are the checksums of the series blocks that belong to the
+	// nodes if requested.
+	LeafEntries []MerkleTreeLeafEntry
+}
+
+// MerkleTreeLeafEntry is the checksum of a series block that belongs to a
+// leaf of the merkle tree of a shard block.
+type MerkleTreeLeafEntry struct {
+	Leaf     int
+	ID       ident.ID
+	Size     int64
+	Checksum uint32
+}
+
 // BorrowConnectionOptions are options to use when borrowing a connection
 type BorrowConnectionOptions struct {
 	// ContinueOnBorrowError allows skipping hosts that cannot borrow
@@ -454,6 +560,12 @@ type Options interface {
 	// TruncateRequestTimeout returns the truncateRequestTimeout.
 	TruncateRequestTimeout() time.Duration
 
+	// SetDeleteSeriesRequestTimeout sets the deleteSeriesRequestTimeout.
+	SetDeleteSeriesRequestTimeout(value time.Duration) Options
+
+	// DeleteSeriesRequestTimeout returns the deleteSeriesRequestTimeout.
+	DeleteSeriesRequestTimeout() time.Duration
+
 	// SetBackgroundConnectInterval sets the backgroundConnectInterval.
 	SetBackgroundConnectInterval(value time.Duration) Options
 
@@ -698,6 +810,37 @@ type Options interface {
 	// IterationOptions returns experimental iteration options.
 	IterationOptions() index.IterationOptions
 
+	// SetStreamingReadsEnabled sets whether FetchTaggedStream reads series
+	// from the nodes over their gRPC streaming service. Note that the M3DB
+	// nodes must have the streaming service enabled, and that each shard is
+	// streamed from a single replica so reads are only streamed while the
+	// read consistency level is one.
+	SetStreamingReadsEnabled(value bool) Options
+
+	// StreamingReadsEnabled returns whether FetchTaggedStream reads series
+	// from the nodes over their gRPC streaming service.
+	StreamingReadsEnabled() bool
+
+	// SetStreamingReadsPort sets the port of the node gRPC streaming service.
+	SetStreamingReadsPort(value int) Options
+
+	// StreamingReadsPort returns the port of the node gRPC streaming service.
+	StreamingReadsPort() int
+
+	// SetStreamingReadsBatchSize sets the number of series in each streamed response.
+	SetStreamingReadsBatchSize(value int) Options
+
+	// StreamingReadsBatchSize returns the number of series in each streamed response.
+	StreamingReadsBatchSize() int
+
+	// SetStreamingReadsMaxBytes sets the maximum size of the series streamed
+	// by a single read, zero for no limit.
+	SetStreamingReadsMaxBytes(value int) Options
+
+	// StreamingReadsMaxBytes returns the maximum size of the series streamed
+	// by a single read, zero for no limit.
+	StreamingReadsMaxBytes() int
+
 	// SetWriteTimestampOffset sets the write timestamp offset.
 	SetWriteTimestampOffset(value time.Duration) AdminOptions
 
@@ -133,11 +133,14 @@ func (w *writeState) completionFn(result interface{}, err error) {
 	} else if hostShardSet, ok := w.topoMap.LookupHostShardSet(hostID); !ok {
 		errStr := "missing host shard in writeState completionFn: %s"
 		wErr = xerrors.NewRetryableError(fmt.Errorf(errStr, hostID))
-	} else if shardState, err := hostShardSet.ShardSet().LookupStateByID(w.op.ShardID()); err != nil {
+	} else if hostShard, err := hostShardSet.ShardSet().LookupShardByID(w.op.ShardID()); err != nil {
 		errStr := "missing shard %d in host %s"
 		wErr = xerrors.NewRetryableError(fmt.Errorf(errStr, w.op.ShardID(), hostID))
 	} else {
-		available := shardState == shard.Available
+		shardState := hostShard.State()
+		// NB: Shards being split count as available since the host keeps
+		// serving them from their parent shard until the split completes.
+		available := shardAvailableForReads(hostShard)
 		leaving := shardState == shard.Leaving
 		leavingAndShardsLeavingCountTowardsConsistency := leaving &&
 			w.shardsLeavingCountTowardsConsistency
@@ -135,6 +135,27 @@ func TestShardLeavingWithShardsLeavingCountTowardsConsistency(t *testing.T) {
 	writeTestTeardown(wState, &writeWg)
 }
 
+func TestWriteToSplittingShards(t *testing.T) {
+	var writeWg sync.WaitGroup
+
+	wState, s, host := writeTestSetup(t, &writeWg)
+	setShardStates(t, s, host, shard.Initializing)
+
+	s.state.RLock()
+	hostShardSet, ok := s.state.topoMap.LookupHostShardSet(host.ID())
+	s.state.RUnlock()
+	require.True(t, ok)
+
+	parentID := uint32(1)
+	for _, hostShard := range hostShardSet.ShardSet().All() {
+		hostShard.SetRedirectToShardID(&parentID)
+	}
+
+	wState.completionFn(host, nil)
+	assert.Equal(t, int32(1), wState.success)
+	writeTestTeardown(wState, &writeWg)
+}
+
 // utils
 
 func getWriteState(s *session, w writeStub) *writeState {
@@ -0,0 +1,305 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package histogram
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+const (
+	encodingVersion = 1
+
+	// flagIntegerCounts is set when all counts of the histogram are integers
+	// and are encoded as varints, otherwise counts are encoded as floats.
+	flagIntegerCounts = 1 << 0
+
+	// maxIntegerCount is the largest count that is encoded as an integer
+	// since larger integers can not be represented exactly as a float.
+	maxIntegerCount = 1 << 53
+)
+
+var (
+	errEncodedTooShort = errors.New("encoded histogram is too short")
+	errEncodedTrailing = errors.New("encoded histogram has trailing bytes")
+)
+
+// Encode encodes a histogram sampled at the given time.
+//
+// The layout of an encoded histogram is:
+//   version (1 byte) | flags (1 byte) | timestamp (varint) | schema (varint) |
+//   zero threshold (8 bytes) | sum (8 bytes) | count | zero count |
+//   positive spans | positive buckets | negative spans | negative buckets
+// where counts are uvarints if all counts are integers and floats otherwise,
+// and buckets are encoded as the delta to the previous bucket for integers.
+//
+// NB: the timestamp is encoded so that the encoded histogram of each sample
+// is unique. Annotations are only written to a series when they differ from
+// the previous annotation and are only returned with the datapoint they were
+// written with, so a histogram repeating the previous sample would otherwise
+// be lost to reads that start between the two samples.
+func Encode(t xtime.UnixNano, h Histogram) []byte {
+	var flags byte
+	if isIntegerCounts(h) {
+		flags |= flagIntegerCounts
+	}
+
+	enc := encoder{
+		buf: make([]byte, 0,
+			32+10*(len(h.PositiveBuckets)+len(h.NegativeBuckets))),
+		integerCounts: flags&flagIntegerCounts != 0,
+	}
+	enc.buf = append(enc.buf, encodingVersion, flags)
+	enc.putVarint(int64(t))
+	enc.putVarint(int64(h.Schema))
+	enc.putFloat(h.ZeroThreshold)
+	enc.putFloat(h.Sum)
+	enc.putCount(h.Count)
+	enc.putCount(h.ZeroCount)
+	enc.putSpans(h.PositiveSpans)
+	enc.putBuckets(h.PositiveBuckets)
+	enc.putSpans(h.NegativeSpans)
+	enc.putBuckets(h.NegativeBuckets)
+	return enc.buf
+}
+
+// Decode decodes an encoded histogram and the time it was sampled at.
+func Decode(b []byte) (xtime.UnixNano, Histogram, error) {
+	if len(b) < 2 {
+		return 0, Histogram{}, errEncodedTooShort
+	}
+	if b[0] != encodingVersion {
+		return 0, Histogram{}, fmt.Errorf(
+			"unsupported encoded histogram version: %d", b[0])
+	}
+
+	dec := decoder{
+		buf:           b[2:],
+		integerCounts: b[1]&flagIntegerCounts != 0,
+	}
+
+	var h Histogram
+	t := xtime.UnixNano(dec.varint())
+	h.Schema = int32(dec.varint())
+	h.ZeroThreshold = dec.float()
+	h.Sum = dec.float()
+	h.Count = dec.count()
+	h.ZeroCount = dec.count()
+	h.PositiveSpans = dec.spans()
+	h.PositiveBuckets = dec.buckets(h.PositiveSpans)
+	h.NegativeSpans = dec.spans()
+	h.NegativeBuckets = dec.buckets(h.NegativeSpans)
+	if dec.err != nil {
+		return 0, Histogram{}, dec.err
+	}
+	if len(dec.buf) != 0 {
+		return 0, Histogram{}, errEncodedTrailing
+	}
+	if err := h.Validate(); err != nil {
+		return 0, Histogram{}, err
+	}
+	return t, h, nil
+}
+
+func isIntegerCounts(h Histogram) bool {
+	if !isInteger(h.Count) || !isInteger(h.ZeroCount) {
+		return false
+	}
+	for _, count := range h.PositiveBuckets {
+		if !isInteger(count) {
+			return false
+		}
+	}
+	for _, count := range h.NegativeBuckets {
+		if !isInteger(count) {
+			return false
+		}
+	}
+	return true
+}
+
+func isInteger(v float64) bool {
+	return v >= 0 && v <= maxIntegerCount && v == math.Trunc(v)
+}
+
+type encoder struct {
+	buf           []byte
+	scratch       [binary.MaxVarintLen64]byte
+	integerCounts bool
+}
+
+func (e *encoder) putVarint(v int64) {
+	n := binary.PutVarint(e.scratch[:], v)
+	e.buf = append(e.buf, e.scratch[:n]...)
+}
+
+func (e *encoder) putUvarint(v uint64) {
+	n := binary.PutUvarint(e.scratch[:], v)
+	e.buf = append(e.buf, e.scratch[:n]...)
+}
+
+func (e *encoder) putFloat(v float64) {
+	binary.LittleEndian.PutUint64(e.scratch[:8], math.Float64bits(v))
+	e.buf = append(e.buf, e.scratch[:8]...)
+}
+
+func (e *encoder) putCount(v float64) {
+	if e.integerCounts {
+		e.putUvarint(uint64(v))
+		return
+	}
+	e.putFloat(v)
+}
+
+func (e *encoder) putSpans(spans []Span) {
+	e.putUvarint(uint64(len(spans)))
+	for _, span := range spans {
+		e.putVarint(int64(span.Offset))
+		e.putUvarint(uint64(span.Length))
+	}
+}
+
+func (e *encoder) putBuckets(buckets []float64) {
+	if !e.integerCounts {
+		for _, count := range buckets {
+			e.putFloat(count)
+		}
+		return
+	}
+
+	// Neighbouring buckets tend to have similar counts so the deltas
+	// between them encode to fewer bytes than the counts themselves.
+	var prev int64
+	for _, count := range buckets {
+		curr := int64(count)
+		e.putVarint(curr - prev)
+		prev = curr
+	}
+}
+
+type decoder struct {
+	buf           []byte
+	integerCounts bool
+	err           error
+}
+
+func (d *decoder) varint() int64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(d.buf)
+	if n <= 0 {
+		d.err = errEncodedTooShort
+		return 0
+	}
+	d.buf = d.buf[n:]
+	return v
+}
+
+func (d *decoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(d.buf)
+	if n <= 0 {
+		d.err = errEncodedTooShort
+		return 0
+	}
+	d.buf = d.buf[n:]
+	return v
+}
+
+func (d *decoder) float() float64 {
+	if d.err != nil {
+		return 0
+	}
+	if len(d.buf) < 8 {
+		d.err = errEncodedTooShort
+		return 0
+	}
+	v := math.Float64frombits(binary.LittleEndian.Uint64(d.buf))
+	d.buf = d.buf[8:]
+	return v
+}
+
+func (d *decoder) count() float64 {
+	if d.integerCounts {
+		return float64(d.uvarint())
+	}
+	return d.float()
+}
+
+func (d *decoder) spans() []Span {
+	n := d.uvarint()
+	// Each span takes at least two bytes, which bounds the allocation for
+	// corrupt input.
+	if d.err != nil || n > uint64(len(d.buf)/2) {
+		if d.err == nil {
+			d.err = errEncodedTooShort
+		}
+		return nil
+	}
+	if n == 0 {
+		return nil
+	}
+	spans := make([]Span, 0, n)
+	for i := uint64(0); i < n; i++ {
+		spans = append(spans, Span{
+			Offset: int32(d.varint()),
+			Length: uint32(d.uvarint()),
+		})
+	}
+	return spans
+}
+
+func (d *decoder) buckets(spans []Span) []float64 {
+	var n uint64
+	for _, span := range spans {
+		n += uint64(span.Length)
+	}
+	// Each bucket takes at least one byte, which bounds the allocation for
+	// corrupt input.
+	if d.err != nil || n > uint64(len(d.buf)) {
+		if d.err == nil {
+			d.err = errEncodedTooShort
+		}
+		return nil
+	}
+	if n == 0 {
+		return nil
+	}
+
+	buckets := make([]float64, 0, n)
+	var prev int64
+	for i := uint64(0); i < n; i++ {
+		if !d.integerCounts {
+			buckets = append(buckets, d.float())
+			continue
+		}
+		prev += d.varint()
+		buckets = append(buckets, float64(prev))
+	}
+	return buckets
+}
@@ -0,0 +1,105 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package histogram
+
+import (
+	"testing"
+
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeDecodeRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		h    Histogram
+	}{
+		{
+			name: "empty",
+			h:    Histogram{},
+		},
+		{
+			name: "integer counts",
+			h: Histogram{
+				Schema:          3,
+				ZeroThreshold:   1e-128,
+				ZeroCount:       5,
+				Count:           27,
+				Sum:             -12.5,
+				PositiveSpans:   []Span{{Offset: -2, Length: 2}, {Offset: 3, Length: 1}},
+				PositiveBuckets: []float64{10, 2, 4},
+				NegativeSpans:   []Span{{Offset: 4, Length: 2}},
+				NegativeBuckets: []float64{1, 5},
+			},
+		},
+		{
+			name: "float counts",
+			h: Histogram{
+				Schema:          -2,
+				Count:           3.5,
+				Sum:             100,
+				PositiveSpans:   []Span{{Offset: 0, Length: 2}},
+				PositiveBuckets: []float64{1.25, 2.25},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := xtime.UnixNano(1600000000123456789)
+			encoded := Encode(ts, tt.h)
+
+			decodedTime, decoded, err := Decode(encoded)
+			require.NoError(t, err)
+			assert.Equal(t, ts, decodedTime)
+			assert.Equal(t, tt.h, decoded)
+		})
+	}
+}
+
+func TestEncodeUniquePerTimestamp(t *testing.T) {
+	h := Histogram{Count: 1, ZeroCount: 1}
+	assert.NotEqual(t, Encode(1, h), Encode(2, h))
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	h := Histogram{
+		Count:           3,
+		PositiveSpans:   []Span{{Offset: 0, Length: 2}},
+		PositiveBuckets: []float64{1, 2},
+	}
+	encoded := Encode(1, h)
+
+	for i := 0; i < len(encoded); i++ {
+		_, _, err := Decode(encoded[:i])
+		require.Error(t, err, "truncated at %d", i)
+	}
+
+	_, _, err := Decode(append(encoded, 0))
+	require.Error(t, err)
+
+	invalidVersion := append([]byte(nil), encoded...)
+	invalidVersion[0] = 0
+	_, _, err = Decode(invalidVersion)
+	require.Error(t, err)
+}
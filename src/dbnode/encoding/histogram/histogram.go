@@ -0,0 +1,184 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package histogram implements native histograms, histograms with exponential
+// bucket boundaries at a resolution set by their schema, and a compact
+// encoding of them that is stored alongside the datapoints of a series.
+package histogram
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+const (
+	// MinSchema is the lowest supported schema, the boundaries of consecutive
+	// buckets grow by a factor of 2^16.
+	MinSchema = -4
+	// MaxSchema is the highest supported schema, the boundaries of
+	// consecutive buckets grow by a factor of 2^(2^-8).
+	MaxSchema = 8
+)
+
+var (
+	errNegativeCount        = errors.New("histogram has a negative count")
+	errNegativeThreshold    = errors.New("histogram has a negative zero threshold")
+	errSpansBucketsMismatch = errors.New("histogram spans do not match the number of buckets")
+)
+
+// Span is a run of consecutive buckets. The offset of the first span is the
+// index of its first bucket, the offset of any following span is the number
+// of empty buckets between it and the previous span.
+type Span struct {
+	Offset int32
+	Length uint32
+}
+
+// Histogram is a native histogram. The buckets of a schema s have boundaries
+// at powers of 2^(2^-s), the positive bucket with index i counts observations
+// in (2^((i-1)*2^-s), 2^(i*2^-s)] and the negative bucket with index i counts
+// observations in [-2^(i*2^-s), -2^((i-1)*2^-s)). Observations within the
+// zero threshold of zero are counted by the zero bucket.
+type Histogram struct {
+	Schema        int32
+	ZeroThreshold float64
+	ZeroCount     float64
+	Count         float64
+	Sum           float64
+	// PositiveSpans are the spans of the populated positive buckets.
+	PositiveSpans []Span
+	// PositiveBuckets are the counts of the buckets of the positive spans.
+	PositiveBuckets []float64
+	// NegativeSpans are the spans of the populated negative buckets.
+	NegativeSpans []Span
+	// NegativeBuckets are the counts of the buckets of the negative spans.
+	NegativeBuckets []float64
+}
+
+// Bucket is a single bucket of a histogram.
+type Bucket struct {
+	Lower float64
+	Upper float64
+	Count float64
+}
+
+// Validate validates the histogram.
+func (h Histogram) Validate() error {
+	if h.Schema < MinSchema || h.Schema > MaxSchema {
+		return fmt.Errorf("histogram schema %d is not in range [%d, %d]",
+			h.Schema, MinSchema, MaxSchema)
+	}
+	if h.ZeroThreshold < 0 || math.IsNaN(h.ZeroThreshold) {
+		return errNegativeThreshold
+	}
+	if h.Count < 0 || h.ZeroCount < 0 {
+		return errNegativeCount
+	}
+	if err := validateSpans(h.PositiveSpans, h.PositiveBuckets); err != nil {
+		return err
+	}
+	return validateSpans(h.NegativeSpans, h.NegativeBuckets)
+}
+
+func validateSpans(spans []Span, buckets []float64) error {
+	var n int
+	for _, span := range spans {
+		n += int(span.Length)
+	}
+	if n != len(buckets) {
+		return errSpansBucketsMismatch
+	}
+	for _, count := range buckets {
+		if count < 0 {
+			return errNegativeCount
+		}
+	}
+	return nil
+}
+
+// Buckets returns the populated buckets of the histogram, including the zero
+// bucket, in increasing order of their boundaries.
+func (h Histogram) Buckets() []Bucket {
+	buckets := make([]Bucket, 0,
+		len(h.NegativeBuckets)+len(h.PositiveBuckets)+1)
+
+	// Negative buckets with a higher index have lower boundaries, so they
+	// are appended in reverse.
+	forEachBucket(h.NegativeSpans, h.NegativeBuckets, func(idx int32, count float64) {
+		buckets = append(buckets, Bucket{
+			Lower: -upperBound(idx, h.Schema),
+			Upper: -upperBound(idx-1, h.Schema),
+			Count: count,
+		})
+	})
+	for i, j := 0, len(buckets)-1; i < j; i, j = i+1, j-1 {
+		buckets[i], buckets[j] = buckets[j], buckets[i]
+	}
+
+	if h.ZeroCount > 0 {
+		buckets = append(buckets, Bucket{
+			Lower: -h.ZeroThreshold,
+			Upper: h.ZeroThreshold,
+			Count: h.ZeroCount,
+		})
+	}
+
+	forEachBucket(h.PositiveSpans, h.PositiveBuckets, func(idx int32, count float64) {
+		buckets = append(buckets, Bucket{
+			Lower: upperBound(idx-1, h.Schema),
+			Upper: upperBound(idx, h.Schema),
+			Count: count,
+		})
+	})
+
+	return buckets
+}
+
+func forEachBucket(
+	spans []Span,
+	buckets []float64,
+	fn func(idx int32, count float64),
+) {
+	var (
+		idx int32
+		i   int
+	)
+	for s, span := range spans {
+		if s == 0 {
+			idx = span.Offset
+		} else {
+			idx += span.Offset
+		}
+		for j := uint32(0); j < span.Length && i < len(buckets); j++ {
+			fn(idx, buckets[i])
+			idx++
+			i++
+		}
+	}
+}
+
+// upperBound returns the upper boundary of the positive bucket at the index.
+func upperBound(idx int32, schema int32) float64 {
+	if schema < 0 {
+		return math.Ldexp(1, int(idx)<<uint(-schema))
+	}
+	return math.Exp2(float64(idx) / float64(int32(1)<<uint(schema)))
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package histogram
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistogramValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		h    Histogram
+		err  bool
+	}{
+		{
+			name: "valid",
+			h: Histogram{
+				PositiveSpans:   []Span{{Offset: 0, Length: 2}},
+				PositiveBuckets: []float64{1, 2},
+			},
+		},
+		{
+			name: "schema out of range",
+			h:    Histogram{Schema: MaxSchema + 1},
+			err:  true,
+		},
+		{
+			name: "negative threshold",
+			h:    Histogram{ZeroThreshold: -1},
+			err:  true,
+		},
+		{
+			name: "negative count",
+			h:    Histogram{Count: -1},
+			err:  true,
+		},
+		{
+			name: "negative bucket",
+			h: Histogram{
+				NegativeSpans:   []Span{{Offset: 0, Length: 1}},
+				NegativeBuckets: []float64{-1},
+			},
+			err: true,
+		},
+		{
+			name: "spans buckets mismatch",
+			h: Histogram{
+				PositiveSpans:   []Span{{Offset: 0, Length: 3}},
+				PositiveBuckets: []float64{1, 2},
+			},
+			err: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.h.Validate()
+			if tt.err {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestHistogramBuckets(t *testing.T) {
+	h := Histogram{
+		Schema:          0,
+		ZeroThreshold:   0.001,
+		ZeroCount:       2,
+		PositiveSpans:   []Span{{Offset: 0, Length: 2}, {Offset: 1, Length: 1}},
+		PositiveBuckets: []float64{1, 3, 5},
+		NegativeSpans:   []Span{{Offset: 1, Length: 2}},
+		NegativeBuckets: []float64{4, 6},
+	}
+
+	assert.Equal(t, []Bucket{
+		{Lower: -4, Upper: -2, Count: 6},
+		{Lower: -2, Upper: -1, Count: 4},
+		{Lower: -0.001, Upper: 0.001, Count: 2},
+		{Lower: 0.5, Upper: 1, Count: 1},
+		{Lower: 1, Upper: 2, Count: 3},
+		{Lower: 4, Upper: 8, Count: 5},
+	}, h.Buckets())
+}
+
+func TestUpperBound(t *testing.T) {
+	assert.Equal(t, 1.0, upperBound(0, 0))
+	assert.Equal(t, 8.0, upperBound(3, 0))
+	assert.Equal(t, 0.25, upperBound(-2, 0))
+	assert.InDelta(t, math.Sqrt2, upperBound(1, 1), 1e-15)
+	assert.Equal(t, 16.0, upperBound(1, -2))
+	assert.Equal(t, 1.0/16, upperBound(-1, -2))
+}
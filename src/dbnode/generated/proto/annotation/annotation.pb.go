@@ -22,13 +22,16 @@
 // THE SOFTWARE.
 
 /*
-	Package annotation is a generated protocol buffer package.
+Package annotation is a generated protocol buffer package.
 
-	It is generated from these files:
-		github.com/m3db/m3/src/dbnode/generated/proto/annotation/annotation.proto
+It is generated from these files:
 
-	It has these top-level messages:
-		Payload
+	github.com/m3db/m3/src/dbnode/generated/proto/annotation/annotation.proto
+
+It has these top-level messages:
+
+	Payload
+	Tile
 */
 package annotation
 
@@ -36,6 +39,8 @@ import proto "github.com/gogo/protobuf/proto"
 import fmt "fmt"
 import math "math"
 
+import binary "encoding/binary"
+
 import io "io"
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -91,6 +96,8 @@ func (MetricType) EnumDescriptor() ([]byte, []int) { return fileDescriptorAnnota
 type Payload struct {
 	MetricType        MetricType `protobuf:"varint,1,opt,name=metric_type,json=metricType,proto3,enum=annotation.MetricType" json:"metric_type,omitempty"`
 	HandleValueResets bool       `protobuf:"varint,2,opt,name=handle_value_resets,json=handleValueResets,proto3" json:"handle_value_resets,omitempty"`
+	NativeHistogram   []byte     `protobuf:"bytes,3,opt,name=native_histogram,json=nativeHistogram,proto3" json:"native_histogram,omitempty"`
+	Tile              *Tile      `protobuf:"bytes,4,opt,name=tile" json:"tile,omitempty"`
 }
 
 func (m *Payload) Reset()                    { *m = Payload{} }
@@ -112,8 +119,77 @@ func (m *Payload) GetHandleValueResets() bool {
 	return false
 }
 
+func (m *Payload) GetNativeHistogram() []byte {
+	if m != nil {
+		return m.NativeHistogram
+	}
+	return nil
+}
+
+func (m *Payload) GetTile() *Tile {
+	if m != nil {
+		return m.Tile
+	}
+	return nil
+}
+
+// Tile holds the aggregates of the samples of a series that were downsampled
+// into a single datapoint by tile aggregation.
+type Tile struct {
+	Min float64 `protobuf:"fixed64,1,opt,name=min,proto3" json:"min,omitempty"`
+	Max float64 `protobuf:"fixed64,2,opt,name=max,proto3" json:"max,omitempty"`
+	Sum float64 `protobuf:"fixed64,3,opt,name=sum,proto3" json:"sum,omitempty"`
+	// count is the number of samples in the tile, it is zero for counter
+	// tiles which have no aggregates other than the counter value.
+	Count uint64 `protobuf:"varint,4,opt,name=count,proto3" json:"count,omitempty"`
+	// reset_offset is added to the samples of a counter so that its value
+	// is cumulative across the resets of the counter.
+	ResetOffset float64 `protobuf:"fixed64,5,opt,name=reset_offset,json=resetOffset,proto3" json:"reset_offset,omitempty"`
+}
+
+func (m *Tile) Reset()                    { *m = Tile{} }
+func (m *Tile) String() string            { return proto.CompactTextString(m) }
+func (*Tile) ProtoMessage()               {}
+func (*Tile) Descriptor() ([]byte, []int) { return fileDescriptorAnnotation, []int{1} }
+
+func (m *Tile) GetMin() float64 {
+	if m != nil {
+		return m.Min
+	}
+	return 0
+}
+
+func (m *Tile) GetMax() float64 {
+	if m != nil {
+		return m.Max
+	}
+	return 0
+}
+
+func (m *Tile) GetSum() float64 {
+	if m != nil {
+		return m.Sum
+	}
+	return 0
+}
+
+func (m *Tile) GetCount() uint64 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+func (m *Tile) GetResetOffset() float64 {
+	if m != nil {
+		return m.ResetOffset
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*Payload)(nil), "annotation.Payload")
+	proto.RegisterType((*Tile)(nil), "annotation.Tile")
 	proto.RegisterEnum("annotation.MetricType", MetricType_name, MetricType_value)
 }
 func (m *Payload) Marshal() (dAtA []byte, err error) {
@@ -146,6 +222,69 @@ func (m *Payload) MarshalTo(dAtA []byte) (int, error) {
 		}
 		i++
 	}
+	if len(m.NativeHistogram) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintAnnotation(dAtA, i, uint64(len(m.NativeHistogram)))
+		i += copy(dAtA[i:], m.NativeHistogram)
+	}
+	if m.Tile != nil {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintAnnotation(dAtA, i, uint64(m.Tile.Size()))
+		n1, err := m.Tile.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n1
+	}
+	return i, nil
+}
+
+func (m *Tile) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Tile) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Min != 0 {
+		dAtA[i] = 0x9
+		i++
+		binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.Min))))
+		i += 8
+	}
+	if m.Max != 0 {
+		dAtA[i] = 0x11
+		i++
+		binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.Max))))
+		i += 8
+	}
+	if m.Sum != 0 {
+		dAtA[i] = 0x19
+		i++
+		binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.Sum))))
+		i += 8
+	}
+	if m.Count != 0 {
+		dAtA[i] = 0x20
+		i++
+		i = encodeVarintAnnotation(dAtA, i, uint64(m.Count))
+	}
+	if m.ResetOffset != 0 {
+		dAtA[i] = 0x29
+		i++
+		binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.ResetOffset))))
+		i += 8
+	}
 	return i, nil
 }
 
@@ -167,6 +306,35 @@ func (m *Payload) Size() (n int) {
 	if m.HandleValueResets {
 		n += 2
 	}
+	l = len(m.NativeHistogram)
+	if l > 0 {
+		n += 1 + l + sovAnnotation(uint64(l))
+	}
+	if m.Tile != nil {
+		l = m.Tile.Size()
+		n += 1 + l + sovAnnotation(uint64(l))
+	}
+	return n
+}
+
+func (m *Tile) Size() (n int) {
+	var l int
+	_ = l
+	if m.Min != 0 {
+		n += 9
+	}
+	if m.Max != 0 {
+		n += 9
+	}
+	if m.Sum != 0 {
+		n += 9
+	}
+	if m.Count != 0 {
+		n += 1 + sovAnnotation(uint64(m.Count))
+	}
+	if m.ResetOffset != 0 {
+		n += 9
+	}
 	return n
 }
 
@@ -251,6 +419,183 @@ func (m *Payload) Unmarshal(dAtA []byte) error {
 				}
 			}
 			m.HandleValueResets = bool(v != 0)
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NativeHistogram", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAnnotation
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthAnnotation
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.NativeHistogram = append(m.NativeHistogram[:0], dAtA[iNdEx:postIndex]...)
+			if m.NativeHistogram == nil {
+				m.NativeHistogram = []byte{}
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Tile", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAnnotation
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthAnnotation
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Tile == nil {
+				m.Tile = &Tile{}
+			}
+			if err := m.Tile.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipAnnotation(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthAnnotation
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Tile) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowAnnotation
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Tile: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Tile: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Min", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.Min = float64(math.Float64frombits(v))
+		case 2:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Max", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.Max = float64(math.Float64frombits(v))
+		case 3:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Sum", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.Sum = float64(math.Float64frombits(v))
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Count", wireType)
+			}
+			m.Count = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAnnotation
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Count |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ResetOffset", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.ResetOffset = float64(math.Float64frombits(v))
 		default:
 			iNdEx = preIndex
 			skippy, err := skipAnnotation(dAtA[iNdEx:])
@@ -382,24 +727,31 @@ func init() {
 }
 
 var fileDescriptorAnnotation = []byte{
-	// 297 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x4c, 0xd0, 0x4d, 0x4b, 0xc3, 0x30,
-	0x1c, 0x06, 0xf0, 0x65, 0xee, 0xf5, 0x3f, 0x5f, 0x62, 0x06, 0xb2, 0x53, 0x19, 0x9e, 0x86, 0x87,
-	0x06, 0xdc, 0xc1, 0x73, 0x95, 0x3a, 0x87, 0xb4, 0x95, 0x36, 0x55, 0x3c, 0x95, 0x74, 0x09, 0xdb,
-	0x60, 0x4d, 0x46, 0x96, 0x09, 0xf3, 0x53, 0xf8, 0xb1, 0x3c, 0xfa, 0x11, 0x64, 0x7e, 0x11, 0x59,
-	0x05, 0xb7, 0xdb, 0x93, 0xe7, 0xc7, 0x93, 0xc3, 0x1f, 0xc6, 0xd3, 0xb9, 0x9d, 0xad, 0x73, 0x77,
-	0xa2, 0x0b, 0x5a, 0x0c, 0x45, 0x4e, 0x8b, 0x21, 0x5d, 0x99, 0x09, 0x15, 0xb9, 0xd2, 0x42, 0xd2,
-	0xa9, 0x54, 0xd2, 0x70, 0x2b, 0x05, 0x5d, 0x1a, 0x6d, 0x35, 0xe5, 0x4a, 0x69, 0xcb, 0xed, 0x5c,
-	0xab, 0x83, 0xe8, 0x96, 0x46, 0x60, 0xdf, 0x5c, 0x1a, 0x68, 0x3e, 0xf1, 0xcd, 0x42, 0x73, 0x41,
-	0x6e, 0xa0, 0x53, 0x48, 0x6b, 0xe6, 0x93, 0xcc, 0x6e, 0x96, 0xb2, 0x87, 0xfa, 0x68, 0x70, 0x7a,
-	0x7d, 0xe1, 0x1e, 0xcc, 0x83, 0x92, 0xd9, 0x66, 0x29, 0x63, 0x28, 0xfe, 0x33, 0x71, 0xa1, 0x3b,
-	0xe3, 0x4a, 0x2c, 0x64, 0xf6, 0xc6, 0x17, 0x6b, 0x99, 0x19, 0xb9, 0x92, 0x76, 0xd5, 0xab, 0xf6,
-	0xd1, 0xa0, 0x15, 0x9f, 0xff, 0xd1, 0xf3, 0x4e, 0xe2, 0x12, 0xae, 0xde, 0x01, 0xf6, 0x3f, 0x91,
-	0x0e, 0x34, 0xd3, 0xf0, 0x31, 0x8c, 0x5e, 0x42, 0x5c, 0xd9, 0x3d, 0xee, 0xa2, 0x34, 0x64, 0x7e,
-	0x8c, 0x11, 0x69, 0x43, 0x7d, 0xe4, 0xa5, 0x23, 0x1f, 0x57, 0xc9, 0x09, 0xb4, 0x1f, 0xc6, 0x09,
-	0x8b, 0x46, 0xb1, 0x17, 0xe0, 0x23, 0xd2, 0x85, 0xb3, 0x52, 0xb2, 0x7d, 0x59, 0xdb, 0x6d, 0x93,
-	0x34, 0x08, 0xbc, 0xf8, 0x15, 0xd7, 0x49, 0x0b, 0x6a, 0xe3, 0xf0, 0x3e, 0xc2, 0x0d, 0x72, 0x0c,
-	0xad, 0x84, 0x79, 0xcc, 0x4f, 0x7c, 0x86, 0x9b, 0xb7, 0xf8, 0x73, 0xeb, 0xa0, 0xaf, 0xad, 0x83,
-	0xbe, 0xb7, 0x0e, 0xfa, 0xf8, 0x71, 0x2a, 0x79, 0xa3, 0x3c, 0xca, 0xf0, 0x37, 0x00, 0x00, 0xff,
-	0xff, 0x25, 0xe6, 0x7a, 0x90, 0x61, 0x01, 0x00, 0x00,
+	// 408 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x4c, 0x91, 0xc1, 0x6e, 0xd3, 0x30,
+	0x18, 0xc7, 0xe7, 0x36, 0x5d, 0xbb, 0xaf, 0x85, 0x19, 0x0f, 0xa1, 0x9c, 0xaa, 0x32, 0x71, 0x28,
+	0x1c, 0x1a, 0x69, 0x3d, 0x70, 0x2e, 0xa8, 0x74, 0x15, 0x4a, 0x82, 0x9c, 0x04, 0xc4, 0x29, 0x72,
+	0x12, 0xaf, 0xb5, 0x94, 0xd8, 0x55, 0xe2, 0x4c, 0x2b, 0x4f, 0xc1, 0x3b, 0x71, 0xe1, 0xc8, 0x23,
+	0xa0, 0xf2, 0x22, 0x28, 0x0e, 0xac, 0xbd, 0xfd, 0xbf, 0xdf, 0x4f, 0x7f, 0x7d, 0x5f, 0x62, 0x58,
+	0x6f, 0x84, 0xde, 0xd6, 0xc9, 0x2c, 0x55, 0x85, 0x53, 0xcc, 0xb3, 0xc4, 0x29, 0xe6, 0x4e, 0x55,
+	0xa6, 0x4e, 0x96, 0x48, 0x95, 0x71, 0x67, 0xc3, 0x25, 0x2f, 0x99, 0xe6, 0x99, 0xb3, 0x2b, 0x95,
+	0x56, 0x0e, 0x93, 0x52, 0x69, 0xa6, 0x85, 0x92, 0x27, 0x71, 0x66, 0x1c, 0x81, 0x23, 0xb9, 0xfe,
+	0x81, 0xa0, 0xff, 0x89, 0xed, 0x73, 0xc5, 0x32, 0xf2, 0x16, 0x86, 0x05, 0xd7, 0xa5, 0x48, 0x63,
+	0xbd, 0xdf, 0x71, 0x1b, 0x4d, 0xd0, 0xf4, 0xe9, 0xcd, 0x8b, 0xd9, 0x49, 0xdf, 0x35, 0x3a, 0xdc,
+	0xef, 0x38, 0x85, 0xe2, 0x31, 0x93, 0x19, 0x5c, 0x6d, 0x99, 0xcc, 0x72, 0x1e, 0xdf, 0xb3, 0xbc,
+	0xe6, 0x71, 0xc9, 0x2b, 0xae, 0x2b, 0xbb, 0x33, 0x41, 0xd3, 0x01, 0x7d, 0xd6, 0xaa, 0xcf, 0x8d,
+	0xa1, 0x46, 0x90, 0xd7, 0x80, 0x25, 0xd3, 0xe2, 0x9e, 0xc7, 0x5b, 0x51, 0x69, 0xb5, 0x29, 0x59,
+	0x61, 0x77, 0x27, 0x68, 0x3a, 0xa2, 0x97, 0x2d, 0xbf, 0xfd, 0x8f, 0xc9, 0x2b, 0xb0, 0xb4, 0xc8,
+	0xb9, 0x6d, 0x4d, 0xd0, 0x74, 0x78, 0x83, 0x4f, 0x8f, 0x09, 0x45, 0xce, 0xa9, 0xb1, 0xd7, 0x35,
+	0x58, 0xcd, 0x44, 0x30, 0x74, 0x0b, 0x21, 0xcd, 0xe5, 0x88, 0x36, 0xd1, 0x10, 0xf6, 0x60, 0x77,
+	0xfe, 0x11, 0xf6, 0xd0, 0x90, 0xaa, 0x6e, 0xf7, 0x21, 0xda, 0x44, 0xf2, 0x1c, 0x7a, 0xa9, 0xaa,
+	0xa5, 0x36, 0x4b, 0x2c, 0xda, 0x0e, 0xe4, 0x25, 0x8c, 0xcc, 0x77, 0xc4, 0xea, 0xee, 0xae, 0xe2,
+	0xda, 0xee, 0x99, 0xc2, 0xd0, 0x30, 0xdf, 0xa0, 0x37, 0xdf, 0x00, 0x8e, 0x7f, 0x84, 0x0c, 0xa1,
+	0x1f, 0x79, 0x1f, 0x3d, 0xff, 0x8b, 0x87, 0xcf, 0x9a, 0xe1, 0xbd, 0x1f, 0x79, 0xe1, 0x92, 0x62,
+	0x44, 0x2e, 0xa0, 0xb7, 0x5a, 0x44, 0xab, 0x25, 0xee, 0x90, 0x27, 0x70, 0x71, 0xbb, 0x0e, 0x42,
+	0x7f, 0x45, 0x17, 0x2e, 0xee, 0x92, 0x2b, 0xb8, 0x34, 0x26, 0x3e, 0x42, 0xab, 0xe9, 0x06, 0x91,
+	0xeb, 0x2e, 0xe8, 0x57, 0xdc, 0x23, 0x03, 0xb0, 0xd6, 0xde, 0x07, 0x1f, 0x9f, 0x93, 0x11, 0x0c,
+	0x82, 0x70, 0x11, 0x2e, 0x83, 0x65, 0x88, 0xfb, 0xef, 0xf0, 0xcf, 0xc3, 0x18, 0xfd, 0x3a, 0x8c,
+	0xd1, 0xef, 0xc3, 0x18, 0x7d, 0xff, 0x33, 0x3e, 0x4b, 0xce, 0xcd, 0xeb, 0xce, 0xff, 0x0e, 0x00,
+	0xd0, 0xa9, 0x6e, 0xf7, 0x2a, 0x02, 0x00, 0x00,
 }
@@ -63,12 +63,17 @@ service Node {
 	FetchBlocksRawResult           fetchBlocksRaw(1: FetchBlocksRawRequest req) throws (1: Error err)
 	FetchTaggedResult              fetchTagged(1: FetchTaggedRequest req) throws (1: Error err)
 	FetchBlocksMetadataRawV2Result fetchBlocksMetadataRawV2(1: FetchBlocksMetadataRawV2Request req) throws (1: Error err)
+	FetchMerkleTreeResult          fetchMerkleTree(1: FetchMerkleTreeRequest req) throws (1: Error err)
 	void                           writeBatchRaw(1: WriteBatchRawRequest req) throws (1: WriteBatchRawErrors err)
 	void                           writeBatchRawV2(1: WriteBatchRawV2Request req) throws (1: WriteBatchRawErrors err)
 	void                           writeTaggedBatchRaw(1: WriteTaggedBatchRawRequest req) throws (1: WriteBatchRawErrors err)
 	void                           writeTaggedBatchRawV2(1: WriteTaggedBatchRawV2Request req) throws (1: WriteBatchRawErrors err)
 	void                           repair() throws (1: Error err)
 	TruncateResult                 truncate(1: TruncateRequest req) throws (1: Error err)
+	DeleteSeriesResult             deleteSeries(1: DeleteSeriesRequest req) throws (1: Error err)
+	void                           writeExemplars(1: WriteExemplarsRequest req) throws (1: Error err)
+	FetchExemplarsResult           fetchExemplars(1: FetchExemplarsRequest req) throws (1: Error err)
+	CardinalityResult              cardinality(1: CardinalityRequest req) throws (1: Error err)
 
 	AggregateTilesResult aggregateTiles(1: AggregateTilesRequest req) throws (1: Error err)
 
@@ -86,6 +91,7 @@ service Node {
 	NodeWriteNewSeriesBackoffDurationResult        setWriteNewSeriesBackoffDuration(1: NodeSetWriteNewSeriesBackoffDurationRequest req) throws (1: Error err)
 	NodeWriteNewSeriesLimitPerShardPerSecondResult getWriteNewSeriesLimitPerShardPerSecond() throws (1: Error err)
 	NodeWriteNewSeriesLimitPerShardPerSecondResult setWriteNewSeriesLimitPerShardPerSecond(1: NodeSetWriteNewSeriesLimitPerShardPerSecondRequest req) throws (1: Error err)
+	RepairStatusResult                             repairStatus(1: RepairStatusRequest req) throws (1: Error err)
 
 	// Debug endpoints
 	DebugProfileStartResult        debugProfileStart(1: DebugProfileStartRequest req) throws (1: Error err)
@@ -265,6 +271,28 @@ struct BlockMetadataV2 {
 	8: optional binary encodedTags
 }
 
+struct FetchMerkleTreeRequest {
+	1: required binary nameSpace
+	2: required i32 shard
+	3: required i64 blockStart
+	4: required i32 depth
+	5: required i32 level
+	6: optional list<i32> indices
+	7: optional bool includeLeafEntries = false
+}
+
+struct FetchMerkleTreeResult {
+	1: required list<i64> digests
+	2: optional list<MerkleTreeLeafEntry> leafEntries
+}
+
+struct MerkleTreeLeafEntry {
+	1: required i32 leaf
+	2: required binary id
+	3: required i64 size
+	4: required i64 checksum
+}
+
 struct WriteBatchRawRequest {
 	1: required binary nameSpace
 	2: required list<WriteBatchRawRequestElement> elements
@@ -322,6 +350,86 @@ struct TruncateResult {
 	1: required i64 numSeries
 }
 
+struct DeleteSeriesRequest {
+	1: required binary nameSpace
+	2: required binary query
+	3: required i64 rangeStart
+	4: required i64 rangeEnd
+	5: optional TimeType rangeTimeType = TimeType.UNIX_SECONDS
+	6: optional binary source
+}
+
+struct DeleteSeriesResult {
+	1: required i64 numSeries
+	2: optional map<i32,i64> numSeriesByShard
+}
+
+struct Exemplar {
+	1: required i64 timestamp
+	2: required double value
+	3: required list<Tag> labels
+	4: optional TimeType timestampTimeType = TimeType.UNIX_SECONDS
+}
+
+struct WriteExemplarsRequest {
+	1: required binary nameSpace
+	2: required list<WriteExemplarsRequestElement> elements
+}
+
+struct WriteExemplarsRequestElement {
+	1: required binary id
+	2: required list<Exemplar> exemplars
+}
+
+struct FetchExemplarsRequest {
+	1: required binary nameSpace
+	2: required binary query
+	3: required i64 rangeStart
+	4: required i64 rangeEnd
+	5: optional TimeType rangeTimeType = TimeType.UNIX_SECONDS
+	6: optional i64 seriesLimit
+	7: optional binary source
+}
+
+struct FetchExemplarsResult {
+	1: required list<FetchExemplarsResultElement> elements
+	2: required bool exhaustive
+}
+
+struct FetchExemplarsResultElement {
+	1: required binary id
+	2: required binary encodedTags
+	3: required list<Exemplar> exemplars
+}
+
+struct CardinalityRequest {
+	1: required string nameSpace
+	2: required i64 rangeStart
+	3: required i64 rangeEnd
+	4: optional TimeType rangeTimeType = TimeType.UNIX_SECONDS
+	5: optional list<i32> shards
+	6: optional string nameTag
+	7: optional i64 limit
+	8: optional binary source
+	9: optional i64 seriesLimit
+	10: optional i64 docsLimit
+	11: optional bool requireExhaustive = false
+}
+
+struct CardinalityResult {
+	1: required i64 numSeries
+	2: required list<CardinalityStat> seriesCountByMetricName
+	3: required list<CardinalityStat> labelValueCountByLabelName
+	4: required list<CardinalityStat> memoryInBytesByLabelName
+	5: required list<CardinalityStat> seriesCountByLabelValuePair
+	6: optional bool exhaustive = true
+}
+
+struct CardinalityStat {
+	1: required string name
+	2: required i64 value
+}
+
 struct NodeHealthResult {
 	1: required bool ok
 	2: required string status
@@ -532,6 +640,29 @@ struct AggregateTilesResult {
 	1: required i64 processedTileCount
 }
 
+struct RepairStatusRequest {
+	1: optional string nameSpace
+}
+
+struct RepairStatusResult {
+	1: required list<RepairShardStatus> shards
+}
+
+struct RepairShardStatus {
+	1: required string nameSpace
+	2: required i32 shard
+	3: required string repairType
+	4: required bool running
+	5: required i64 blockStart
+	6: required i64 lastStarted
+	7: required i64 lastCompleted
+	8: optional string lastError
+	9: required i64 numRepairs
+	10: required i64 numComparedMerkleTreeNodes
+	11: required i64 numDivergentMerkleTreeLeaves
+	12: required i64 numRepairedBlocks
+}
+
 struct DebugProfileStartRequest {
 	1: required string name
 	2: required string filePathTemplate
@@ -255,6 +255,21 @@ func (mr *MockTChanNodeMockRecorder) BootstrappedInPlacementOrNoPlacement(ctx in
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BootstrappedInPlacementOrNoPlacement", reflect.TypeOf((*MockTChanNode)(nil).BootstrappedInPlacementOrNoPlacement), ctx)
 }
 
+// Cardinality mocks base method.
+func (m *MockTChanNode) Cardinality(ctx thrift.Context, req *CardinalityRequest) (*CardinalityResult_, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cardinality", ctx, req)
+	ret0, _ := ret[0].(*CardinalityResult_)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Cardinality indicates an expected call of Cardinality.
+func (mr *MockTChanNodeMockRecorder) Cardinality(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cardinality", reflect.TypeOf((*MockTChanNode)(nil).Cardinality), ctx, req)
+}
+
 // DebugIndexMemorySegments mocks base method.
 func (m *MockTChanNode) DebugIndexMemorySegments(ctx thrift.Context, req *DebugIndexMemorySegmentsRequest) (*DebugIndexMemorySegmentsResult_, error) {
 	m.ctrl.T.Helper()
@@ -300,6 +315,21 @@ func (mr *MockTChanNodeMockRecorder) DebugProfileStop(ctx, req interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DebugProfileStop", reflect.TypeOf((*MockTChanNode)(nil).DebugProfileStop), ctx, req)
 }
 
+// DeleteSeries mocks base method.
+func (m *MockTChanNode) DeleteSeries(ctx thrift.Context, req *DeleteSeriesRequest) (*DeleteSeriesResult_, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSeries", ctx, req)
+	ret0, _ := ret[0].(*DeleteSeriesResult_)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteSeries indicates an expected call of DeleteSeries.
+func (mr *MockTChanNodeMockRecorder) DeleteSeries(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSeries", reflect.TypeOf((*MockTChanNode)(nil).DeleteSeries), ctx, req)
+}
+
 // Fetch mocks base method.
 func (m *MockTChanNode) Fetch(ctx thrift.Context, req *FetchRequest) (*FetchResult_, error) {
 	m.ctrl.T.Helper()
@@ -375,6 +405,36 @@ func (mr *MockTChanNodeMockRecorder) FetchBlocksRaw(ctx, req interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchBlocksRaw", reflect.TypeOf((*MockTChanNode)(nil).FetchBlocksRaw), ctx, req)
 }
 
+// FetchExemplars mocks base method.
+func (m *MockTChanNode) FetchExemplars(ctx thrift.Context, req *FetchExemplarsRequest) (*FetchExemplarsResult_, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchExemplars", ctx, req)
+	ret0, _ := ret[0].(*FetchExemplarsResult_)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchExemplars indicates an expected call of FetchExemplars.
+func (mr *MockTChanNodeMockRecorder) FetchExemplars(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchExemplars", reflect.TypeOf((*MockTChanNode)(nil).FetchExemplars), ctx, req)
+}
+
+// FetchMerkleTree mocks base method.
+func (m *MockTChanNode) FetchMerkleTree(ctx thrift.Context, req *FetchMerkleTreeRequest) (*FetchMerkleTreeResult_, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchMerkleTree", ctx, req)
+	ret0, _ := ret[0].(*FetchMerkleTreeResult_)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchMerkleTree indicates an expected call of FetchMerkleTree.
+func (mr *MockTChanNodeMockRecorder) FetchMerkleTree(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchMerkleTree", reflect.TypeOf((*MockTChanNode)(nil).FetchMerkleTree), ctx, req)
+}
+
 // FetchTagged mocks base method.
 func (m *MockTChanNode) FetchTagged(ctx thrift.Context, req *FetchTaggedRequest) (*FetchTaggedResult_, error) {
 	m.ctrl.T.Helper()
@@ -494,6 +554,21 @@ func (mr *MockTChanNodeMockRecorder) Repair(ctx interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Repair", reflect.TypeOf((*MockTChanNode)(nil).Repair), ctx)
 }
 
+// RepairStatus mocks base method.
+func (m *MockTChanNode) RepairStatus(ctx thrift.Context, req *RepairStatusRequest) (*RepairStatusResult_, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RepairStatus", ctx, req)
+	ret0, _ := ret[0].(*RepairStatusResult_)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RepairStatus indicates an expected call of RepairStatus.
+func (mr *MockTChanNodeMockRecorder) RepairStatus(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RepairStatus", reflect.TypeOf((*MockTChanNode)(nil).RepairStatus), ctx, req)
+}
+
 // SetPersistRateLimit mocks base method.
 func (m *MockTChanNode) SetPersistRateLimit(ctx thrift.Context, req *NodeSetPersistRateLimitRequest) (*NodePersistRateLimitResult_, error) {
 	m.ctrl.T.Helper()
@@ -611,6 +686,20 @@ func (mr *MockTChanNodeMockRecorder) WriteBatchRawV2(ctx, req interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteBatchRawV2", reflect.TypeOf((*MockTChanNode)(nil).WriteBatchRawV2), ctx, req)
 }
 
+// WriteExemplars mocks base method.
+func (m *MockTChanNode) WriteExemplars(ctx thrift.Context, req *WriteExemplarsRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WriteExemplars", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WriteExemplars indicates an expected call of WriteExemplars.
+func (mr *MockTChanNodeMockRecorder) WriteExemplars(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteExemplars", reflect.TypeOf((*MockTChanNode)(nil).WriteExemplars), ctx, req)
+}
+
 // WriteTagged mocks base method.
 func (m *MockTChanNode) WriteTagged(ctx thrift.Context, req *WriteTaggedRequest) error {
 	m.ctrl.T.Helper()
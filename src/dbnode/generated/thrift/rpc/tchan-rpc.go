@@ -50,14 +50,18 @@ type TChanNode interface {
 	AggregateTiles(ctx thrift.Context, req *AggregateTilesRequest) (*AggregateTilesResult_, error)
 	Bootstrapped(ctx thrift.Context) (*NodeBootstrappedResult_, error)
 	BootstrappedInPlacementOrNoPlacement(ctx thrift.Context) (*NodeBootstrappedInPlacementOrNoPlacementResult_, error)
+	Cardinality(ctx thrift.Context, req *CardinalityRequest) (*CardinalityResult_, error)
 	DebugIndexMemorySegments(ctx thrift.Context, req *DebugIndexMemorySegmentsRequest) (*DebugIndexMemorySegmentsResult_, error)
 	DebugProfileStart(ctx thrift.Context, req *DebugProfileStartRequest) (*DebugProfileStartResult_, error)
 	DebugProfileStop(ctx thrift.Context, req *DebugProfileStopRequest) (*DebugProfileStopResult_, error)
+	DeleteSeries(ctx thrift.Context, req *DeleteSeriesRequest) (*DeleteSeriesResult_, error)
 	Fetch(ctx thrift.Context, req *FetchRequest) (*FetchResult_, error)
 	FetchBatchRaw(ctx thrift.Context, req *FetchBatchRawRequest) (*FetchBatchRawResult_, error)
 	FetchBatchRawV2(ctx thrift.Context, req *FetchBatchRawV2Request) (*FetchBatchRawResult_, error)
 	FetchBlocksMetadataRawV2(ctx thrift.Context, req *FetchBlocksMetadataRawV2Request) (*FetchBlocksMetadataRawV2Result_, error)
 	FetchBlocksRaw(ctx thrift.Context, req *FetchBlocksRawRequest) (*FetchBlocksRawResult_, error)
+	FetchExemplars(ctx thrift.Context, req *FetchExemplarsRequest) (*FetchExemplarsResult_, error)
+	FetchMerkleTree(ctx thrift.Context, req *FetchMerkleTreeRequest) (*FetchMerkleTreeResult_, error)
 	FetchTagged(ctx thrift.Context, req *FetchTaggedRequest) (*FetchTaggedResult_, error)
 	GetPersistRateLimit(ctx thrift.Context) (*NodePersistRateLimitResult_, error)
 	GetWriteNewSeriesAsync(ctx thrift.Context) (*NodeWriteNewSeriesAsyncResult_, error)
@@ -66,6 +70,7 @@ type TChanNode interface {
 	Health(ctx thrift.Context) (*NodeHealthResult_, error)
 	Query(ctx thrift.Context, req *QueryRequest) (*QueryResult_, error)
 	Repair(ctx thrift.Context) error
+	RepairStatus(ctx thrift.Context, req *RepairStatusRequest) (*RepairStatusResult_, error)
 	SetPersistRateLimit(ctx thrift.Context, req *NodeSetPersistRateLimitRequest) (*NodePersistRateLimitResult_, error)
 	SetWriteNewSeriesAsync(ctx thrift.Context, req *NodeSetWriteNewSeriesAsyncRequest) (*NodeWriteNewSeriesAsyncResult_, error)
 	SetWriteNewSeriesBackoffDuration(ctx thrift.Context, req *NodeSetWriteNewSeriesBackoffDurationRequest) (*NodeWriteNewSeriesBackoffDurationResult_, error)
@@ -74,6 +79,7 @@ type TChanNode interface {
 	Write(ctx thrift.Context, req *WriteRequest) error
 	WriteBatchRaw(ctx thrift.Context, req *WriteBatchRawRequest) error
 	WriteBatchRawV2(ctx thrift.Context, req *WriteBatchRawV2Request) error
+	WriteExemplars(ctx thrift.Context, req *WriteExemplarsRequest) error
 	WriteTagged(ctx thrift.Context, req *WriteTaggedRequest) error
 	WriteTaggedBatchRaw(ctx thrift.Context, req *WriteTaggedBatchRawRequest) error
 	WriteTaggedBatchRawV2(ctx thrift.Context, req *WriteTaggedBatchRawV2Request) error
@@ -569,6 +575,24 @@ func (c *tchanNodeClient) BootstrappedInPlacementOrNoPlacement(ctx thrift.Contex
 	return resp.GetSuccess(), err
 }
 
+func (c *tchanNodeClient) Cardinality(ctx thrift.Context, req *CardinalityRequest) (*CardinalityResult_, error) {
+	var resp NodeCardinalityResult
+	args := NodeCardinalityArgs{
+		Req: req,
+	}
+	success, err := c.client.Call(ctx, c.thriftService, "cardinality", &args, &resp)
+	if err == nil && !success {
+		switch {
+		case resp.Err != nil:
+			err = resp.Err
+		default:
+			err = fmt.Errorf("received no result or unknown exception for cardinality")
+		}
+	}
+
+	return resp.GetSuccess(), err
+}
+
 func (c *tchanNodeClient) DebugIndexMemorySegments(ctx thrift.Context, req *DebugIndexMemorySegmentsRequest) (*DebugIndexMemorySegmentsResult_, error) {
 	var resp NodeDebugIndexMemorySegmentsResult
 	args := NodeDebugIndexMemorySegmentsArgs{
@@ -623,6 +647,24 @@ func (c *tchanNodeClient) DebugProfileStop(ctx thrift.Context, req *DebugProfile
 	return resp.GetSuccess(), err
 }
 
+func (c *tchanNodeClient) DeleteSeries(ctx thrift.Context, req *DeleteSeriesRequest) (*DeleteSeriesResult_, error) {
+	var resp NodeDeleteSeriesResult
+	args := NodeDeleteSeriesArgs{
+		Req: req,
+	}
+	success, err := c.client.Call(ctx, c.thriftService, "deleteSeries", &args, &resp)
+	if err == nil && !success {
+		switch {
+		case resp.Err != nil:
+			err = resp.Err
+		default:
+			err = fmt.Errorf("received no result or unknown exception for deleteSeries")
+		}
+	}
+
+	return resp.GetSuccess(), err
+}
+
 func (c *tchanNodeClient) Fetch(ctx thrift.Context, req *FetchRequest) (*FetchResult_, error) {
 	var resp NodeFetchResult
 	args := NodeFetchArgs{
@@ -713,6 +755,42 @@ func (c *tchanNodeClient) FetchBlocksRaw(ctx thrift.Context, req *FetchBlocksRaw
 	return resp.GetSuccess(), err
 }
 
+func (c *tchanNodeClient) FetchExemplars(ctx thrift.Context, req *FetchExemplarsRequest) (*FetchExemplarsResult_, error) {
+	var resp NodeFetchExemplarsResult
+	args := NodeFetchExemplarsArgs{
+		Req: req,
+	}
+	success, err := c.client.Call(ctx, c.thriftService, "fetchExemplars", &args, &resp)
+	if err == nil && !success {
+		switch {
+		case resp.Err != nil:
+			err = resp.Err
+		default:
+			err = fmt.Errorf("received no result or unknown exception for fetchExemplars")
+		}
+	}
+
+	return resp.GetSuccess(), err
+}
+
+func (c *tchanNodeClient) FetchMerkleTree(ctx thrift.Context, req *FetchMerkleTreeRequest) (*FetchMerkleTreeResult_, error) {
+	var resp NodeFetchMerkleTreeResult
+	args := NodeFetchMerkleTreeArgs{
+		Req: req,
+	}
+	success, err := c.client.Call(ctx, c.thriftService, "fetchMerkleTree", &args, &resp)
+	if err == nil && !success {
+		switch {
+		case resp.Err != nil:
+			err = resp.Err
+		default:
+			err = fmt.Errorf("received no result or unknown exception for fetchMerkleTree")
+		}
+	}
+
+	return resp.GetSuccess(), err
+}
+
 func (c *tchanNodeClient) FetchTagged(ctx thrift.Context, req *FetchTaggedRequest) (*FetchTaggedResult_, error) {
 	var resp NodeFetchTaggedResult
 	args := NodeFetchTaggedArgs{
@@ -845,6 +923,24 @@ func (c *tchanNodeClient) Repair(ctx thrift.Context) error {
 	return err
 }
 
+func (c *tchanNodeClient) RepairStatus(ctx thrift.Context, req *RepairStatusRequest) (*RepairStatusResult_, error) {
+	var resp NodeRepairStatusResult
+	args := NodeRepairStatusArgs{
+		Req: req,
+	}
+	success, err := c.client.Call(ctx, c.thriftService, "repairStatus", &args, &resp)
+	if err == nil && !success {
+		switch {
+		case resp.Err != nil:
+			err = resp.Err
+		default:
+			err = fmt.Errorf("received no result or unknown exception for repairStatus")
+		}
+	}
+
+	return resp.GetSuccess(), err
+}
+
 func (c *tchanNodeClient) SetPersistRateLimit(ctx thrift.Context, req *NodeSetPersistRateLimitRequest) (*NodePersistRateLimitResult_, error) {
 	var resp NodeSetPersistRateLimitResult
 	args := NodeSetPersistRateLimitArgs{
@@ -989,6 +1085,24 @@ func (c *tchanNodeClient) WriteBatchRawV2(ctx thrift.Context, req *WriteBatchRaw
 	return err
 }
 
+func (c *tchanNodeClient) WriteExemplars(ctx thrift.Context, req *WriteExemplarsRequest) error {
+	var resp NodeWriteExemplarsResult
+	args := NodeWriteExemplarsArgs{
+		Req: req,
+	}
+	success, err := c.client.Call(ctx, c.thriftService, "writeExemplars", &args, &resp)
+	if err == nil && !success {
+		switch {
+		case resp.Err != nil:
+			err = resp.Err
+		default:
+			err = fmt.Errorf("received no result or unknown exception for writeExemplars")
+		}
+	}
+
+	return err
+}
+
 func (c *tchanNodeClient) WriteTagged(ctx thrift.Context, req *WriteTaggedRequest) error {
 	var resp NodeWriteTaggedResult
 	args := NodeWriteTaggedArgs{
@@ -1066,14 +1180,18 @@ func (s *tchanNodeServer) Methods() []string {
 		"aggregateTiles",
 		"bootstrapped",
 		"bootstrappedInPlacementOrNoPlacement",
+		"cardinality",
 		"debugIndexMemorySegments",
 		"debugProfileStart",
 		"debugProfileStop",
+		"deleteSeries",
 		"fetch",
 		"fetchBatchRaw",
 		"fetchBatchRawV2",
 		"fetchBlocksMetadataRawV2",
 		"fetchBlocksRaw",
+		"fetchExemplars",
+		"fetchMerkleTree",
 		"fetchTagged",
 		"getPersistRateLimit",
 		"getWriteNewSeriesAsync",
@@ -1082,6 +1200,7 @@ func (s *tchanNodeServer) Methods() []string {
 		"health",
 		"query",
 		"repair",
+		"repairStatus",
 		"setPersistRateLimit",
 		"setWriteNewSeriesAsync",
 		"setWriteNewSeriesBackoffDuration",
@@ -1090,6 +1209,7 @@ func (s *tchanNodeServer) Methods() []string {
 		"write",
 		"writeBatchRaw",
 		"writeBatchRawV2",
+		"writeExemplars",
 		"writeTagged",
 		"writeTaggedBatchRaw",
 		"writeTaggedBatchRawV2",
@@ -1108,12 +1228,16 @@ func (s *tchanNodeServer) Handle(ctx thrift.Context, methodName string, protocol
 		return s.handleBootstrapped(ctx, protocol)
 	case "bootstrappedInPlacementOrNoPlacement":
 		return s.handleBootstrappedInPlacementOrNoPlacement(ctx, protocol)
+	case "cardinality":
+		return s.handleCardinality(ctx, protocol)
 	case "debugIndexMemorySegments":
 		return s.handleDebugIndexMemorySegments(ctx, protocol)
 	case "debugProfileStart":
 		return s.handleDebugProfileStart(ctx, protocol)
 	case "debugProfileStop":
 		return s.handleDebugProfileStop(ctx, protocol)
+	case "deleteSeries":
+		return s.handleDeleteSeries(ctx, protocol)
 	case "fetch":
 		return s.handleFetch(ctx, protocol)
 	case "fetchBatchRaw":
@@ -1124,6 +1248,10 @@ func (s *tchanNodeServer) Handle(ctx thrift.Context, methodName string, protocol
 		return s.handleFetchBlocksMetadataRawV2(ctx, protocol)
 	case "fetchBlocksRaw":
 		return s.handleFetchBlocksRaw(ctx, protocol)
+	case "fetchExemplars":
+		return s.handleFetchExemplars(ctx, protocol)
+	case "fetchMerkleTree":
+		return s.handleFetchMerkleTree(ctx, protocol)
 	case "fetchTagged":
 		return s.handleFetchTagged(ctx, protocol)
 	case "getPersistRateLimit":
@@ -1140,6 +1268,8 @@ func (s *tchanNodeServer) Handle(ctx thrift.Context, methodName string, protocol
 		return s.handleQuery(ctx, protocol)
 	case "repair":
 		return s.handleRepair(ctx, protocol)
+	case "repairStatus":
+		return s.handleRepairStatus(ctx, protocol)
 	case "setPersistRateLimit":
 		return s.handleSetPersistRateLimit(ctx, protocol)
 	case "setWriteNewSeriesAsync":
@@ -1156,6 +1286,8 @@ func (s *tchanNodeServer) Handle(ctx thrift.Context, methodName string, protocol
 		return s.handleWriteBatchRaw(ctx, protocol)
 	case "writeBatchRawV2":
 		return s.handleWriteBatchRawV2(ctx, protocol)
+	case "writeExemplars":
+		return s.handleWriteExemplars(ctx, protocol)
 	case "writeTagged":
 		return s.handleWriteTagged(ctx, protocol)
 	case "writeTaggedBatchRaw":
@@ -1308,6 +1440,34 @@ func (s *tchanNodeServer) handleBootstrappedInPlacementOrNoPlacement(ctx thrift.
 	return err == nil, &res, nil
 }
 
+func (s *tchanNodeServer) handleCardinality(ctx thrift.Context, protocol athrift.TProtocol) (bool, athrift.TStruct, error) {
+	var req NodeCardinalityArgs
+	var res NodeCardinalityResult
+
+	if err := req.Read(protocol); err != nil {
+		return false, nil, err
+	}
+
+	r, err :=
+		s.handler.Cardinality(ctx, req.Req)
+
+	if err != nil {
+		switch v := err.(type) {
+		case *Error:
+			if v == nil {
+				return false, nil, fmt.Errorf("Handler for err returned non-nil error type *Error but nil value")
+			}
+			res.Err = v
+		default:
+			return false, nil, err
+		}
+	} else {
+		res.Success = r
+	}
+
+	return err == nil, &res, nil
+}
+
 func (s *tchanNodeServer) handleDebugIndexMemorySegments(ctx thrift.Context, protocol athrift.TProtocol) (bool, athrift.TStruct, error) {
 	var req NodeDebugIndexMemorySegmentsArgs
 	var res NodeDebugIndexMemorySegmentsResult
@@ -1392,6 +1552,34 @@ func (s *tchanNodeServer) handleDebugProfileStop(ctx thrift.Context, protocol at
 	return err == nil, &res, nil
 }
 
+func (s *tchanNodeServer) handleDeleteSeries(ctx thrift.Context, protocol athrift.TProtocol) (bool, athrift.TStruct, error) {
+	var req NodeDeleteSeriesArgs
+	var res NodeDeleteSeriesResult
+
+	if err := req.Read(protocol); err != nil {
+		return false, nil, err
+	}
+
+	r, err :=
+		s.handler.DeleteSeries(ctx, req.Req)
+
+	if err != nil {
+		switch v := err.(type) {
+		case *Error:
+			if v == nil {
+				return false, nil, fmt.Errorf("Handler for err returned non-nil error type *Error but nil value")
+			}
+			res.Err = v
+		default:
+			return false, nil, err
+		}
+	} else {
+		res.Success = r
+	}
+
+	return err == nil, &res, nil
+}
+
 func (s *tchanNodeServer) handleFetch(ctx thrift.Context, protocol athrift.TProtocol) (bool, athrift.TStruct, error) {
 	var req NodeFetchArgs
 	var res NodeFetchResult
@@ -1532,6 +1720,62 @@ func (s *tchanNodeServer) handleFetchBlocksRaw(ctx thrift.Context, protocol athr
 	return err == nil, &res, nil
 }
 
+func (s *tchanNodeServer) handleFetchExemplars(ctx thrift.Context, protocol athrift.TProtocol) (bool, athrift.TStruct, error) {
+	var req NodeFetchExemplarsArgs
+	var res NodeFetchExemplarsResult
+
+	if err := req.Read(protocol); err != nil {
+		return false, nil, err
+	}
+
+	r, err :=
+		s.handler.FetchExemplars(ctx, req.Req)
+
+	if err != nil {
+		switch v := err.(type) {
+		case *Error:
+			if v == nil {
+				return false, nil, fmt.Errorf("Handler for err returned non-nil error type *Error but nil value")
+			}
+			res.Err = v
+		default:
+			return false, nil, err
+		}
+	} else {
+		res.Success = r
+	}
+
+	return err == nil, &res, nil
+}
+
+func (s *tchanNodeServer) handleFetchMerkleTree(ctx thrift.Context, protocol athrift.TProtocol) (bool, athrift.TStruct, error) {
+	var req NodeFetchMerkleTreeArgs
+	var res NodeFetchMerkleTreeResult
+
+	if err := req.Read(protocol); err != nil {
+		return false, nil, err
+	}
+
+	r, err :=
+		s.handler.FetchMerkleTree(ctx, req.Req)
+
+	if err != nil {
+		switch v := err.(type) {
+		case *Error:
+			if v == nil {
+				return false, nil, fmt.Errorf("Handler for err returned non-nil error type *Error but nil value")
+			}
+			res.Err = v
+		default:
+			return false, nil, err
+		}
+	} else {
+		res.Success = r
+	}
+
+	return err == nil, &res, nil
+}
+
 func (s *tchanNodeServer) handleFetchTagged(ctx thrift.Context, protocol athrift.TProtocol) (bool, athrift.TStruct, error) {
 	var req NodeFetchTaggedArgs
 	var res NodeFetchTaggedResult
@@ -1755,6 +1999,34 @@ func (s *tchanNodeServer) handleRepair(ctx thrift.Context, protocol athrift.TPro
 	return err == nil, &res, nil
 }
 
+func (s *tchanNodeServer) handleRepairStatus(ctx thrift.Context, protocol athrift.TProtocol) (bool, athrift.TStruct, error) {
+	var req NodeRepairStatusArgs
+	var res NodeRepairStatusResult
+
+	if err := req.Read(protocol); err != nil {
+		return false, nil, err
+	}
+
+	r, err :=
+		s.handler.RepairStatus(ctx, req.Req)
+
+	if err != nil {
+		switch v := err.(type) {
+		case *Error:
+			if v == nil {
+				return false, nil, fmt.Errorf("Handler for err returned non-nil error type *Error but nil value")
+			}
+			res.Err = v
+		default:
+			return false, nil, err
+		}
+	} else {
+		res.Success = r
+	}
+
+	return err == nil, &res, nil
+}
+
 func (s *tchanNodeServer) handleSetPersistRateLimit(ctx thrift.Context, protocol athrift.TProtocol) (bool, athrift.TStruct, error) {
 	var req NodeSetPersistRateLimitArgs
 	var res NodeSetPersistRateLimitResult
@@ -1976,6 +2248,33 @@ func (s *tchanNodeServer) handleWriteBatchRawV2(ctx thrift.Context, protocol ath
 	return err == nil, &res, nil
 }
 
+func (s *tchanNodeServer) handleWriteExemplars(ctx thrift.Context, protocol athrift.TProtocol) (bool, athrift.TStruct, error) {
+	var req NodeWriteExemplarsArgs
+	var res NodeWriteExemplarsResult
+
+	if err := req.Read(protocol); err != nil {
+		return false, nil, err
+	}
+
+	err :=
+		s.handler.WriteExemplars(ctx, req.Req)
+
+	if err != nil {
+		switch v := err.(type) {
+		case *Error:
+			if v == nil {
+				return false, nil, fmt.Errorf("Handler for err returned non-nil error type *Error but nil value")
+			}
+			res.Err = v
+		default:
+			return false, nil, err
+		}
+	} else {
+	}
+
+	return err == nil, &res, nil
+}
+
 func (s *tchanNodeServer) handleWriteTagged(ctx thrift.Context, protocol athrift.TProtocol) (bool, athrift.TStruct, error) {
 	var req NodeWriteTaggedArgs
 	var res NodeWriteTaggedResult
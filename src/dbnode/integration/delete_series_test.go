@@ -0,0 +1,193 @@
+// +build integration
+//
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package integration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/retention"
+	"github.com/m3db/m3/src/dbnode/storage/index"
+	"github.com/m3db/m3/src/m3ninx/idx"
+	xclock "github.com/m3db/m3/src/x/clock"
+	"github.com/m3db/m3/src/x/ident"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/stretchr/testify/require"
+)
+
+/*
+ * This test writes and flushes series to a namespace with cold writes disabled, deletes one
+ * of the series once its block is on disk, and ensures the block is rewritten without it.
+ */
+func TestDeleteSeriesColdWritesDisabled(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow() // Just skip if we're doing a short run
+	}
+
+	var (
+		numWrites     = 20
+		numTags       = 2
+		blockSize     = 2 * time.Hour
+		bufferFuture  = 20 * time.Minute
+		bufferPast    = 10 * time.Minute
+		verifyTimeout = 2 * time.Minute
+	)
+
+	// Test setup
+	md, err := namespace.NewMetadata(testNamespaces[0],
+		namespace.NewOptions().
+			SetRepairEnabled(false).
+			SetColdWritesEnabled(false).
+			SetRetentionOptions(retention.NewOptions().
+				SetRetentionPeriod(12*time.Hour).
+				SetBufferPast(bufferPast).
+				SetBufferFuture(bufferFuture).
+				SetBlockSize(blockSize)).
+			SetIndexOptions(namespace.NewIndexOptions().
+				SetBlockSize(blockSize).SetEnabled(true)))
+	require.NoError(t, err)
+
+	testOpts := NewTestOptions(t).
+		SetTickMinimumInterval(time.Second).
+		SetNamespaces([]namespace.Metadata{md})
+	testSetup, err := NewTestSetup(t, testOpts, nil)
+	require.NoError(t, err)
+	defer testSetup.Close()
+
+	t0 := testSetup.NowFn()().Truncate(blockSize)
+	testSetup.SetNowFn(t0)
+	writes := GenerateTestIndexWrite(0, numWrites, numTags, t0, t0.Add(bufferFuture))
+
+	// Start the server
+	log := testSetup.StorageOpts().InstrumentOptions().Logger()
+	require.NoError(t, testSetup.StartServer())
+
+	// Stop the server
+	defer func() {
+		require.NoError(t, testSetup.StopServer())
+		log.Debug("server is now down")
+	}()
+
+	session, err := testSetup.M3DBClient().DefaultSession()
+	require.NoError(t, err)
+
+	writes.Write(t, md.ID(), session)
+	indexed := xclock.WaitUntil(func() bool {
+		return writes.NumIndexed(t, md.ID(), session) == len(writes)
+	}, verifyTimeout)
+	require.True(t, indexed)
+
+	var (
+		shardSet       = testSetup.ShardSet()
+		filePathPrefix = testSetup.FilePathPrefix()
+		deletedID      = writes[0].ID
+		deletedShard   = shardSet.Lookup(deletedID)
+		fileSet        = func(volumeIndex int) fs.FileSetFileIdentifier {
+			return fs.FileSetFileIdentifier{
+				Namespace:   md.ID(),
+				Shard:       deletedShard,
+				BlockStart:  t0,
+				VolumeIndex: volumeIndex,
+			}
+		}
+	)
+
+	// Move time forward so the block is warm flushed.
+	testSetup.SetNowFn(t0.Add(2 * blockSize))
+	require.NoError(t, waitUntilFileSetFilesExist(filePathPrefix,
+		[]fs.FileSetFileIdentifier{fileSet(0)}, verifyTimeout))
+	require.Contains(t, latestFileSetIDs(t, testSetup, md.ID(), deletedShard, t0),
+		deletedID.String())
+
+	// "common_j" is unique to each series written.
+	query := index.Query{
+		Query: idx.NewTermQuery([]byte("common_j"), []byte("0")),
+	}
+	adminSession, err := testSetup.M3DBVerificationAdminClient().DefaultAdminSession()
+	require.NoError(t, err)
+	deleted, err := adminSession.DeleteSeries(md.ID(), query, t0, t0.Add(blockSize))
+	require.NoError(t, err)
+	require.Equal(t, int64(1), deleted)
+
+	// The block is rewritten without the deleted series despite cold writes
+	// being disabled.
+	require.NoError(t, waitUntilFileSetFilesExist(filePathPrefix,
+		[]fs.FileSetFileIdentifier{fileSet(1)}, verifyTimeout))
+
+	ids := make(map[uint32][]string)
+	for _, w := range writes {
+		shard := shardSet.Lookup(w.ID)
+		if _, ok := ids[shard]; !ok {
+			ids[shard] = latestFileSetIDs(t, testSetup, md.ID(), shard, t0)
+		}
+		if w.ID.Equal(deletedID) {
+			require.NotContains(t, ids[shard], w.ID.String())
+			continue
+		}
+		require.Contains(t, ids[shard], w.ID.String())
+	}
+}
+
+// latestFileSetIDs returns the IDs of the series in the latest data volume of
+// the block.
+func latestFileSetIDs(
+	t *testing.T,
+	testSetup TestSetup,
+	nsID ident.ID,
+	shard uint32,
+	blockStart xtime.UnixNano,
+) []string {
+	var (
+		storageOpts = testSetup.StorageOpts()
+		fsOpts      = storageOpts.CommitLogOptions().FilesystemOptions()
+	)
+	dataFiles, err := fs.DataFiles(fsOpts.FilePathPrefix(), nsID, shard)
+	require.NoError(t, err)
+	latest, ok := dataFiles.LatestVolumeForBlock(blockStart)
+	require.True(t, ok)
+
+	reader, err := fs.NewReader(storageOpts.BytesPool(), fsOpts)
+	require.NoError(t, err)
+	require.NoError(t, reader.Open(fs.DataReaderOpenOptions{
+		Identifier:  latest.ID,
+		FileSetType: persist.FileSetFlushType,
+	}))
+	defer func() {
+		require.NoError(t, reader.Close())
+	}()
+
+	ids := make([]string, 0, reader.Entries())
+	for i := 0; i < reader.Entries(); i++ {
+		id, tags, data, _, err := reader.Read()
+		require.NoError(t, err)
+		ids = append(ids, id.String())
+		id.Finalize()
+		tags.Close()
+		data.Finalize()
+	}
+	return ids
+}
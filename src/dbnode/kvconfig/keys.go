@@ -52,4 +52,8 @@ const (
 
 	// QueryLimits is the KV config key for query limits enforced on each dbnode.
 	QueryLimits = "m3db.query.limits"
+
+	// TenantQuotasKey is the KV config key for the per tenant quotas enforced
+	// on each dbnode.
+	TenantQuotasKey = "m3db.node.tenant-quotas"
 )
@@ -24,6 +24,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/m3db/m3/src/dbnode/persist/compression"
 	"github.com/m3db/m3/src/dbnode/retention"
 	"github.com/m3db/m3/src/x/ident"
 )
@@ -56,6 +57,9 @@ type MetadataConfiguration struct {
 	RepairEnabled         *bool                   `yaml:"repairEnabled"`
 	ColdWritesEnabled     *bool                   `yaml:"coldWritesEnabled"`
 	CacheBlocksOnRetrieve *bool                   `yaml:"cacheBlocksOnRetrieve"`
+	FileSetCompression    *compression.Type       `yaml:"fileSetCompression"`
+	SecondaryTierAge      *time.Duration          `yaml:"secondaryTierAge"`
+	OutOfOrderWindow      *time.Duration          `yaml:"outOfOrderWindow"`
 	Retention             retention.Configuration `yaml:"retention" validate:"nonzero"`
 	Index                 IndexConfiguration      `yaml:"index"`
 }
@@ -88,6 +92,15 @@ func (mc *MetadataConfiguration) Metadata() (Metadata, error) {
 	if v := mc.CacheBlocksOnRetrieve; v != nil {
 		opts = opts.SetCacheBlocksOnRetrieve(*v)
 	}
+	if v := mc.FileSetCompression; v != nil {
+		opts = opts.SetFileSetCompression(*v)
+	}
+	if v := mc.SecondaryTierAge; v != nil {
+		opts = opts.SetSecondaryTierAge(*v)
+	}
+	if v := mc.OutOfOrderWindow; v != nil {
+		opts = opts.SetOutOfOrderWindow(*v)
+	}
 	return NewMetadata(ident.StringID(mc.ID), opts)
 }
 
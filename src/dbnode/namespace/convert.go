@@ -28,6 +28,7 @@ import (
 	"time"
 
 	nsproto "github.com/m3db/m3/src/dbnode/generated/proto/namespace"
+	"github.com/m3db/m3/src/dbnode/persist/compression"
 	"github.com/m3db/m3/src/dbnode/retention"
 	"github.com/m3db/m3/src/x/ident"
 	xtime "github.com/m3db/m3/src/x/time"
@@ -196,6 +197,11 @@ func ToMetadata(
 		return nil, err
 	}
 
+	fileSetCompression, err := ToFileSetCompression(opts.FileSetCompression)
+	if err != nil {
+		return nil, err
+	}
+
 	mOpts := NewOptions().
 		SetBootstrapEnabled(opts.BootstrapEnabled).
 		SetFlushEnabled(opts.FlushEnabled).
@@ -210,7 +216,10 @@ func ToMetadata(
 		SetRuntimeOptions(runtimeOpts).
 		SetExtendedOptions(extendedOpts).
 		SetAggregationOptions(aggOpts).
-		SetStagingState(stagingState)
+		SetStagingState(stagingState).
+		SetFileSetCompression(fileSetCompression).
+		SetSecondaryTierAge(time.Duration(opts.SecondaryTierAgeNanos)).
+		SetOutOfOrderWindow(time.Duration(opts.OutOfOrderWindowNanos))
 
 	if opts.CacheBlocksOnRetrieve != nil {
 		mOpts = mOpts.SetCacheBlocksOnRetrieve(opts.CacheBlocksOnRetrieve.Value)
@@ -232,6 +241,19 @@ func ToStagingState(state *nsproto.StagingState) (StagingState, error) {
 	return NewStagingState(state.Status)
 }
 
+// ToFileSetCompression converts nsproto.FileSetCompression to a compression type.
+func ToFileSetCompression(value nsproto.FileSetCompression) (compression.Type, error) {
+	switch value {
+	case nsproto.FileSetCompression_NONE:
+		return compression.None, nil
+	case nsproto.FileSetCompression_SNAPPY:
+		return compression.Snappy, nil
+	case nsproto.FileSetCompression_ZSTD:
+		return compression.Zstd, nil
+	}
+	return compression.None, fmt.Errorf("invalid fileset compression: %v", value)
+}
+
 // ToAggregationOptions converts nsproto.AggregationOptions to AggregationOptions.
 func ToAggregationOptions(opts *nsproto.AggregationOptions) (AggregationOptions, error) {
 	aggOpts := NewAggregationOptions()
@@ -313,6 +335,11 @@ func OptionsToProto(opts Options) (*nsproto.NamespaceOptions, error) {
 		return nil, err
 	}
 
+	fileSetCompression, err := toProtoFileSetCompression(opts.FileSetCompression())
+	if err != nil {
+		return nil, err
+	}
+
 	nsOpts := &nsproto.NamespaceOptions{
 		BootstrapEnabled:  opts.BootstrapEnabled(),
 		FlushEnabled:      opts.FlushEnabled(),
@@ -340,11 +367,26 @@ func OptionsToProto(opts Options) (*nsproto.NamespaceOptions, error) {
 		ExtendedOptions:       extendedOpts,
 		AggregationOptions:    toProtoAggregationOptions(opts.AggregationOptions()),
 		StagingState:          stagingState,
+		FileSetCompression:    fileSetCompression,
+		SecondaryTierAgeNanos: opts.SecondaryTierAge().Nanoseconds(),
+		OutOfOrderWindowNanos: opts.OutOfOrderWindow().Nanoseconds(),
 	}
 
 	return nsOpts, nil
 }
 
+func toProtoFileSetCompression(value compression.Type) (nsproto.FileSetCompression, error) {
+	switch value {
+	case compression.None:
+		return nsproto.FileSetCompression_NONE, nil
+	case compression.Snappy:
+		return nsproto.FileSetCompression_SNAPPY, nil
+	case compression.Zstd:
+		return nsproto.FileSetCompression_ZSTD, nil
+	}
+	return nsproto.FileSetCompression_NONE, fmt.Errorf("invalid fileset compression: %v", value)
+}
+
 func toProtoStagingState(state StagingState) (*nsproto.StagingState, error) {
 	var protoStatus nsproto.StagingStatus
 	switch state.Status() {
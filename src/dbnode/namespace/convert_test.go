@@ -26,6 +26,7 @@ import (
 
 	nsproto "github.com/m3db/m3/src/dbnode/generated/proto/namespace"
 	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/persist/compression"
 	"github.com/m3db/m3/src/dbnode/retention"
 	"github.com/m3db/m3/src/x/ident"
 	xtest "github.com/m3db/m3/src/x/test"
@@ -76,6 +77,9 @@ var (
 			SchemaOptions:         testSchemaOptions,
 			ExtendedOptions:       validExtendedOpts,
 			StagingState:          &nsproto.StagingState{Status: nsproto.StagingStatus_INITIALIZING},
+			FileSetCompression:    nsproto.FileSetCompression_ZSTD,
+			SecondaryTierAgeNanos: toNanos(600), // 10h
+			OutOfOrderWindowNanos: toNanos(30),
 		},
 		{
 			BootstrapEnabled:  true,
@@ -220,7 +224,8 @@ func TestToProto(t *testing.T) {
 	md1, err := namespace.NewMetadata(ident.StringID("ns1"),
 		namespace.NewOptions().
 			SetBootstrapEnabled(true).
-			SetStagingState(state))
+			SetStagingState(state).
+			SetFileSetCompression(compression.Snappy))
 	require.NoError(t, err)
 	md2, err := namespace.NewMetadata(ident.StringID("ns2"),
 		namespace.NewOptions().SetBootstrapEnabled(false))
@@ -407,6 +412,9 @@ func assertEqualMetadata(t *testing.T, name string, expected nsproto.NamespaceOp
 
 	assertEqualRetentions(t, *expected.RetentionOptions, opts.RetentionOptions())
 	assertEqualStagingState(t, expected.StagingState, opts.StagingState())
+	assertEqualFileSetCompression(t, expected.FileSetCompression, opts.FileSetCompression())
+	require.Equal(t, expected.SecondaryTierAgeNanos, opts.SecondaryTierAge().Nanoseconds())
+	require.Equal(t, expected.OutOfOrderWindowNanos, opts.OutOfOrderWindow().Nanoseconds())
 	assertEqualExtendedOpts(t, expected.ExtendedOptions, opts.ExtendedOptions())
 }
 
@@ -434,6 +442,16 @@ func assertEqualExtendedOpts(t *testing.T, expectedProto *nsproto.ExtendedOption
 	assert.Equal(t, expected, observed)
 }
 
+func assertEqualFileSetCompression(
+	t *testing.T,
+	expected nsproto.FileSetCompression,
+	observed compression.Type,
+) {
+	compressionType, err := namespace.ToFileSetCompression(expected)
+	require.NoError(t, err)
+	assert.Equal(t, compressionType, observed)
+}
+
 func assertEqualStagingState(t *testing.T, expected *nsproto.StagingState, observed namespace.StagingState) {
 	if expected == nil {
 		assert.Equal(t, namespace.StagingState{}, observed)
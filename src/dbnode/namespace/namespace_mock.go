@@ -29,6 +29,7 @@ import (
 	"time"
 
 	"github.com/m3db/m3/src/cluster/client"
+	"github.com/m3db/m3/src/dbnode/persist/compression"
 	"github.com/m3db/m3/src/dbnode/retention"
 	"github.com/m3db/m3/src/x/ident"
 	"github.com/m3db/m3/src/x/instrument"
@@ -159,6 +160,20 @@ func (mr *MockOptionsMockRecorder) ExtendedOptions() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExtendedOptions", reflect.TypeOf((*MockOptions)(nil).ExtendedOptions))
 }
 
+// FileSetCompression mocks base method.
+func (m *MockOptions) FileSetCompression() compression.Type {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FileSetCompression")
+	ret0, _ := ret[0].(compression.Type)
+	return ret0
+}
+
+// FileSetCompression indicates an expected call of FileSetCompression.
+func (mr *MockOptionsMockRecorder) FileSetCompression() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FileSetCompression", reflect.TypeOf((*MockOptions)(nil).FileSetCompression))
+}
+
 // FlushEnabled mocks base method.
 func (m *MockOptions) FlushEnabled() bool {
 	m.ctrl.T.Helper()
@@ -187,6 +202,20 @@ func (mr *MockOptionsMockRecorder) IndexOptions() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IndexOptions", reflect.TypeOf((*MockOptions)(nil).IndexOptions))
 }
 
+// OutOfOrderWindow mocks base method.
+func (m *MockOptions) OutOfOrderWindow() time.Duration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OutOfOrderWindow")
+	ret0, _ := ret[0].(time.Duration)
+	return ret0
+}
+
+// OutOfOrderWindow indicates an expected call of OutOfOrderWindow.
+func (mr *MockOptionsMockRecorder) OutOfOrderWindow() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OutOfOrderWindow", reflect.TypeOf((*MockOptions)(nil).OutOfOrderWindow))
+}
+
 // RepairEnabled mocks base method.
 func (m *MockOptions) RepairEnabled() bool {
 	m.ctrl.T.Helper()
@@ -243,6 +272,20 @@ func (mr *MockOptionsMockRecorder) SchemaHistory() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SchemaHistory", reflect.TypeOf((*MockOptions)(nil).SchemaHistory))
 }
 
+// SecondaryTierAge mocks base method.
+func (m *MockOptions) SecondaryTierAge() time.Duration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SecondaryTierAge")
+	ret0, _ := ret[0].(time.Duration)
+	return ret0
+}
+
+// SecondaryTierAge indicates an expected call of SecondaryTierAge.
+func (mr *MockOptionsMockRecorder) SecondaryTierAge() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SecondaryTierAge", reflect.TypeOf((*MockOptions)(nil).SecondaryTierAge))
+}
+
 // SetAggregationOptions mocks base method.
 func (m *MockOptions) SetAggregationOptions(value AggregationOptions) Options {
 	m.ctrl.T.Helper()
@@ -327,6 +370,20 @@ func (mr *MockOptionsMockRecorder) SetExtendedOptions(value interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetExtendedOptions", reflect.TypeOf((*MockOptions)(nil).SetExtendedOptions), value)
 }
 
+// SetFileSetCompression mocks base method.
+func (m *MockOptions) SetFileSetCompression(value compression.Type) Options {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetFileSetCompression", value)
+	ret0, _ := ret[0].(Options)
+	return ret0
+}
+
+// SetFileSetCompression indicates an expected call of SetFileSetCompression.
+func (mr *MockOptionsMockRecorder) SetFileSetCompression(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetFileSetCompression", reflect.TypeOf((*MockOptions)(nil).SetFileSetCompression), value)
+}
+
 // SetFlushEnabled mocks base method.
 func (m *MockOptions) SetFlushEnabled(value bool) Options {
 	m.ctrl.T.Helper()
@@ -355,6 +412,20 @@ func (mr *MockOptionsMockRecorder) SetIndexOptions(value interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetIndexOptions", reflect.TypeOf((*MockOptions)(nil).SetIndexOptions), value)
 }
 
+// SetOutOfOrderWindow mocks base method.
+func (m *MockOptions) SetOutOfOrderWindow(value time.Duration) Options {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetOutOfOrderWindow", value)
+	ret0, _ := ret[0].(Options)
+	return ret0
+}
+
+// SetOutOfOrderWindow indicates an expected call of SetOutOfOrderWindow.
+func (mr *MockOptionsMockRecorder) SetOutOfOrderWindow(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetOutOfOrderWindow", reflect.TypeOf((*MockOptions)(nil).SetOutOfOrderWindow), value)
+}
+
 // SetRepairEnabled mocks base method.
 func (m *MockOptions) SetRepairEnabled(value bool) Options {
 	m.ctrl.T.Helper()
@@ -411,6 +482,20 @@ func (mr *MockOptionsMockRecorder) SetSchemaHistory(value interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSchemaHistory", reflect.TypeOf((*MockOptions)(nil).SetSchemaHistory), value)
 }
 
+// SetSecondaryTierAge mocks base method.
+func (m *MockOptions) SetSecondaryTierAge(value time.Duration) Options {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetSecondaryTierAge", value)
+	ret0, _ := ret[0].(Options)
+	return ret0
+}
+
+// SetSecondaryTierAge indicates an expected call of SetSecondaryTierAge.
+func (mr *MockOptionsMockRecorder) SetSecondaryTierAge(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSecondaryTierAge", reflect.TypeOf((*MockOptions)(nil).SetSecondaryTierAge), value)
+}
+
 // SetSnapshotEnabled mocks base method.
 func (m *MockOptions) SetSnapshotEnabled(value bool) Options {
 	m.ctrl.T.Helper()
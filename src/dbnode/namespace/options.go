@@ -22,7 +22,9 @@ package namespace
 
 import (
 	"errors"
+	"time"
 
+	"github.com/m3db/m3/src/dbnode/persist/compression"
 	"github.com/m3db/m3/src/dbnode/retention"
 )
 
@@ -54,6 +56,14 @@ const (
 )
 
 var (
+	errSecondaryTierAgeTooSmall = errors.New(
+		"secondary tier age must be >= namespace block size + buffer past + out of order window")
+	errOutOfOrderWindowNegative = errors.New(
+		"out of order window must be >= 0")
+	errOutOfOrderWindowTooLarge = errors.New(
+		"out of order window must be < namespace retention period")
+	errSecondaryTierAgeTooLarge = errors.New(
+		"secondary tier age must be < namespace retention period")
 	errIndexBlockSizePositive                       = errors.New("index block size must positive")
 	errIndexBlockSizeTooLarge                       = errors.New("index block size needs to be <= namespace retention period")
 	errIndexBlockSizeMustBeAMultipleOfDataBlockSize = errors.New("index block size must be a multiple of data block size")
@@ -77,6 +87,9 @@ type options struct {
 	extendedOpts          ExtendedOptions
 	aggregationOpts       AggregationOptions
 	stagingState          StagingState
+	fileSetCompression    compression.Type
+	secondaryTierAge      time.Duration
+	outOfOrderWindow      time.Duration
 }
 
 // NewSchemaHistory returns an empty schema history.
@@ -118,6 +131,18 @@ func (o *options) Validate() error {
 		return err
 	}
 
+	if err := o.fileSetCompression.Validate(); err != nil {
+		return err
+	}
+
+	if err := o.validateOutOfOrderWindow(); err != nil {
+		return err
+	}
+
+	if err := o.validateSecondaryTierAge(); err != nil {
+		return err
+	}
+
 	if !o.indexOpts.Enabled() {
 		return nil
 	}
@@ -160,7 +185,10 @@ func (o *options) Equal(value Options) bool {
 		o.schemaHis.Equal(value.SchemaHistory()) &&
 		o.runtimeOpts.Equal(value.RuntimeOptions()) &&
 		o.aggregationOpts.Equal(value.AggregationOptions()) &&
-		o.stagingState == value.StagingState()
+		o.stagingState == value.StagingState() &&
+		o.fileSetCompression == value.FileSetCompression() &&
+		o.secondaryTierAge == value.SecondaryTierAge() &&
+		o.outOfOrderWindow == value.OutOfOrderWindow()
 }
 
 func (o *options) SetBootstrapEnabled(value bool) Options {
@@ -312,3 +340,60 @@ func (o *options) SetStagingState(value StagingState) Options {
 func (o *options) StagingState() StagingState {
 	return o.stagingState
 }
+
+func (o *options) SetFileSetCompression(value compression.Type) Options {
+	opts := *o
+	opts.fileSetCompression = value
+	return &opts
+}
+
+func (o *options) FileSetCompression() compression.Type {
+	return o.fileSetCompression
+}
+
+func (o *options) SetSecondaryTierAge(value time.Duration) Options {
+	opts := *o
+	opts.secondaryTierAge = value
+	return &opts
+}
+
+func (o *options) SecondaryTierAge() time.Duration {
+	return o.secondaryTierAge
+}
+
+func (o *options) validateSecondaryTierAge() error {
+	if o.secondaryTierAge == 0 {
+		return nil
+	}
+	// Only blocks that can no longer be warm flushed are offloaded.
+	if o.secondaryTierAge < o.retentionOpts.BlockSize()+o.retentionOpts.BufferPast()+o.outOfOrderWindow {
+		return errSecondaryTierAgeTooSmall
+	}
+	if o.secondaryTierAge >= o.retentionOpts.RetentionPeriod() {
+		return errSecondaryTierAgeTooLarge
+	}
+	return nil
+}
+
+func (o *options) SetOutOfOrderWindow(value time.Duration) Options {
+	opts := *o
+	opts.outOfOrderWindow = value
+	return &opts
+}
+
+func (o *options) OutOfOrderWindow() time.Duration {
+	return o.outOfOrderWindow
+}
+
+func (o *options) validateOutOfOrderWindow() error {
+	if o.outOfOrderWindow == 0 {
+		return nil
+	}
+	if o.outOfOrderWindow < 0 {
+		return errOutOfOrderWindowNegative
+	}
+	if o.outOfOrderWindow >= o.retentionOpts.RetentionPeriod() {
+		return errOutOfOrderWindowTooLarge
+	}
+	return nil
+}
@@ -25,6 +25,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/m3db/m3/src/dbnode/persist/compression"
 	"github.com/m3db/m3/src/dbnode/retention"
 
 	"github.com/golang/mock/gomock"
@@ -217,3 +218,55 @@ func TestOptionsValidateStagingStatus(t *testing.T) {
 	o1 = o1.SetStagingState(StagingState{status: StagingStatus(12)})
 	require.Error(t, o1.Validate())
 }
+
+func TestOptionsValidateFileSetCompression(t *testing.T) {
+	o1 := NewOptions().SetFileSetCompression(compression.Zstd)
+	require.NoError(t, o1.Validate())
+	require.Equal(t, compression.Zstd, o1.FileSetCompression())
+	require.False(t, o1.Equal(NewOptions()))
+
+	o1 = o1.SetFileSetCompression(compression.Type(12))
+	require.Error(t, o1.Validate())
+}
+
+func TestOptionsValidateSecondaryTierAge(t *testing.T) {
+	rOpts := retention.NewOptions().
+		SetRetentionPeriod(48 * time.Hour).
+		SetBlockSize(2 * time.Hour).
+		SetBufferPast(10 * time.Minute)
+	o1 := NewOptions().
+		SetRetentionOptions(rOpts).
+		SetSecondaryTierAge(24 * time.Hour)
+	require.NoError(t, o1.Validate())
+	require.Equal(t, 24*time.Hour, o1.SecondaryTierAge())
+	require.False(t, o1.Equal(NewOptions().SetRetentionOptions(rOpts)))
+
+	o1 = o1.SetSecondaryTierAge(2 * time.Hour)
+	require.Equal(t, errSecondaryTierAgeTooSmall, o1.Validate())
+
+	o1 = o1.SetSecondaryTierAge(48 * time.Hour)
+	require.Equal(t, errSecondaryTierAgeTooLarge, o1.Validate())
+
+	o1 = o1.SetSecondaryTierAge(2*time.Hour + 10*time.Minute).
+		SetOutOfOrderWindow(time.Hour)
+	require.Equal(t, errSecondaryTierAgeTooSmall, o1.Validate())
+}
+
+func TestOptionsValidateOutOfOrderWindow(t *testing.T) {
+	rOpts := retention.NewOptions().
+		SetRetentionPeriod(48 * time.Hour).
+		SetBlockSize(2 * time.Hour).
+		SetBufferPast(10 * time.Minute)
+	o1 := NewOptions().
+		SetRetentionOptions(rOpts).
+		SetOutOfOrderWindow(time.Hour)
+	require.NoError(t, o1.Validate())
+	require.Equal(t, time.Hour, o1.OutOfOrderWindow())
+	require.False(t, o1.Equal(NewOptions().SetRetentionOptions(rOpts)))
+
+	o1 = o1.SetOutOfOrderWindow(-time.Minute)
+	require.Equal(t, errOutOfOrderWindowNegative, o1.Validate())
+
+	o1 = o1.SetOutOfOrderWindow(48 * time.Hour)
+	require.Equal(t, errOutOfOrderWindowTooLarge, o1.Validate())
+}
@@ -26,6 +26,7 @@ import (
 	protobuftypes "github.com/gogo/protobuf/types"
 
 	"github.com/m3db/m3/src/cluster/client"
+	"github.com/m3db/m3/src/dbnode/persist/compression"
 	"github.com/m3db/m3/src/dbnode/retention"
 	"github.com/m3db/m3/src/x/ident"
 	"github.com/m3db/m3/src/x/instrument"
@@ -131,6 +132,34 @@ type Options interface {
 
 	// StagingState returns the state related to a namespace's availability for use.
 	StagingState() StagingState
+
+	// SetFileSetCompression sets the compression applied to the data files of
+	// filesets written for this namespace.
+	SetFileSetCompression(value compression.Type) Options
+
+	// FileSetCompression returns the compression applied to the data files of
+	// filesets written for this namespace.
+	FileSetCompression() compression.Type
+
+	// SetSecondaryTierAge sets the age after which the filesets of flushed
+	// blocks are offloaded to the secondary storage tier, zero disables
+	// offloading.
+	SetSecondaryTierAge(value time.Duration) Options
+
+	// SecondaryTierAge returns the age after which the filesets of flushed
+	// blocks are offloaded to the secondary storage tier, zero disables
+	// offloading.
+	SecondaryTierAge() time.Duration
+
+	// SetOutOfOrderWindow sets the window past the buffer past within which
+	// late writes are accepted into the out of order buffer and merged into
+	// the warm flush of their block, zero disables the window.
+	SetOutOfOrderWindow(value time.Duration) Options
+
+	// OutOfOrderWindow returns the window past the buffer past within which
+	// late writes are accepted into the out of order buffer and merged into
+	// the warm flush of their block, zero disables the window.
+	OutOfOrderWindow() time.Duration
 }
 
 // IndexOptions controls the indexing options for a namespace.
@@ -0,0 +1,202 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package convert converts between the streaming gRPC node types and the
+// thrift RPC types used by the node service.
+package convert
+
+import (
+	goerrors "errors"
+
+	"github.com/m3db/m3/src/dbnode/generated/proto/streaming"
+	"github.com/m3db/m3/src/dbnode/generated/thrift/rpc"
+	tterrors "github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ToStreamingFetchTaggedRequest converts a thrift fetch tagged request with
+// nanosecond range times into a streaming fetch tagged request.
+func ToStreamingFetchTaggedRequest(
+	req rpc.FetchTaggedRequest,
+	shards []uint32,
+	batchSize int,
+) *streaming.FetchTaggedRequest {
+	result := &streaming.FetchTaggedRequest{
+		NameSpace:         req.NameSpace,
+		Query:             req.Query,
+		RangeStartNanos:   req.RangeStart,
+		RangeEndNanos:     req.RangeEnd,
+		FetchData:         req.FetchData,
+		RequireExhaustive: req.RequireExhaustive,
+		RequireNoWait:     req.RequireNoWait,
+		Source:            req.Source,
+		Shards:            shards,
+		BatchSize:         int64(batchSize),
+	}
+	if req.SeriesLimit != nil {
+		result.SeriesLimit = *req.SeriesLimit
+	}
+	if req.DocsLimit != nil {
+		result.DocsLimit = *req.DocsLimit
+	}
+	return result
+}
+
+// FromStreamingFetchTaggedRequest converts a streaming fetch tagged request
+// into a thrift fetch tagged request.
+func FromStreamingFetchTaggedRequest(
+	req *streaming.FetchTaggedRequest,
+) *rpc.FetchTaggedRequest {
+	result := &rpc.FetchTaggedRequest{
+		NameSpace:         req.NameSpace,
+		Query:             req.Query,
+		RangeStart:        req.RangeStartNanos,
+		RangeEnd:          req.RangeEndNanos,
+		RangeTimeType:     rpc.TimeType_UNIX_NANOSECONDS,
+		FetchData:         req.FetchData,
+		RequireExhaustive: req.RequireExhaustive,
+		RequireNoWait:     req.RequireNoWait,
+		Source:            req.Source,
+	}
+	if req.SeriesLimit > 0 {
+		l := req.SeriesLimit
+		result.SeriesLimit = &l
+	}
+	if req.DocsLimit > 0 {
+		l := req.DocsLimit
+		result.DocsLimit = &l
+	}
+	return result
+}
+
+// ToStreamingSegments converts thrift segments into streaming segments.
+func ToStreamingSegments(segments []*rpc.Segments) []*streaming.Segments {
+	result := make([]*streaming.Segments, 0, len(segments))
+	for _, s := range segments {
+		converted := &streaming.Segments{}
+		if s.Merged != nil {
+			converted.Merged = toStreamingSegment(s.Merged)
+		}
+		if len(s.Unmerged) > 0 {
+			converted.Unmerged = make([]*streaming.Segment, 0, len(s.Unmerged))
+			for _, u := range s.Unmerged {
+				converted.Unmerged = append(converted.Unmerged, toStreamingSegment(u))
+			}
+		}
+		result = append(result, converted)
+	}
+	return result
+}
+
+func toStreamingSegment(s *rpc.Segment) *streaming.Segment {
+	result := &streaming.Segment{
+		Head: s.Head,
+		Tail: s.Tail,
+	}
+	if s.StartTime != nil {
+		result.StartTimeNanos = *s.StartTime
+	}
+	if s.BlockSize != nil {
+		result.BlockSizeNanos = *s.BlockSize
+	}
+	if s.Checksum != nil {
+		result.Checksum = *s.Checksum
+	}
+	return result
+}
+
+// FromStreamingSegments converts streaming segments into thrift segments.
+func FromStreamingSegments(segments []*streaming.Segments) []*rpc.Segments {
+	result := make([]*rpc.Segments, 0, len(segments))
+	for _, s := range segments {
+		converted := &rpc.Segments{}
+		if s.Merged != nil {
+			converted.Merged = fromStreamingSegment(s.Merged)
+		}
+		if len(s.Unmerged) > 0 {
+			converted.Unmerged = make([]*rpc.Segment, 0, len(s.Unmerged))
+			for _, u := range s.Unmerged {
+				converted.Unmerged = append(converted.Unmerged, fromStreamingSegment(u))
+			}
+		}
+		result = append(result, converted)
+	}
+	return result
+}
+
+func fromStreamingSegment(s *streaming.Segment) *rpc.Segment {
+	// NB: zero values are equivalent to unset values when the segments are
+	// read so always set the optional fields.
+	var (
+		startTime = s.StartTimeNanos
+		blockSize = s.BlockSizeNanos
+		checksum  = s.Checksum
+	)
+	return &rpc.Segment{
+		Head:      s.Head,
+		Tail:      s.Tail,
+		StartTime: &startTime,
+		BlockSize: &blockSize,
+		Checksum:  &checksum,
+	}
+}
+
+// ToStatusError converts a thrift RPC error into a gRPC status error.
+func ToStatusError(err *rpc.Error) error {
+	if err == nil {
+		return nil
+	}
+
+	code := codes.Internal
+	switch {
+	case tterrors.IsResourceExhaustedErrorFlag(err):
+		code = codes.ResourceExhausted
+	case tterrors.IsBadRequestError(err):
+		code = codes.InvalidArgument
+	case tterrors.IsTimeoutError(err):
+		code = codes.DeadlineExceeded
+	}
+	return status.Error(code, err.Message)
+}
+
+// FromStatusError converts a gRPC status error into a thrift RPC error so that
+// callers can classify it the same as errors returned over thrift.
+func FromStatusError(err error) error {
+	s, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+
+	converted := goerrors.New(s.Message())
+	switch s.Code() {
+	case codes.OK:
+		return nil
+	case codes.ResourceExhausted:
+		return tterrors.NewResourceExhaustedError(converted)
+	case codes.InvalidArgument:
+		return tterrors.NewBadRequestError(converted)
+	case codes.DeadlineExceeded, codes.Canceled:
+		return tterrors.NewTimeoutError(converted)
+	default:
+		return tterrors.NewInternalError(converted)
+	}
+}
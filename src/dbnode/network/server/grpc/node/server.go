@@ -0,0 +1,72 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package node
+
+import (
+	"net"
+
+	"github.com/m3db/m3/src/dbnode/generated/proto/streaming"
+	ns "github.com/m3db/m3/src/dbnode/network/server"
+	ttnode "github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/node"
+	"github.com/m3db/m3/src/x/context"
+	"github.com/m3db/m3/src/x/instrument"
+
+	"google.golang.org/grpc"
+)
+
+type server struct {
+	service     ttnode.Service
+	address     string
+	contextPool context.Pool
+	iOpts       instrument.Options
+}
+
+// NewServer creates a new node gRPC network service that streams read
+// results.
+func NewServer(
+	service ttnode.Service,
+	address string,
+	contextPool context.Pool,
+	iOpts instrument.Options,
+) ns.NetworkService {
+	return &server{
+		service:     service,
+		address:     address,
+		contextPool: contextPool,
+		iOpts:       iOpts,
+	}
+}
+
+func (s *server) ListenAndServe() (ns.Close, error) {
+	listener, err := net.Listen("tcp", s.address)
+	if err != nil {
+		return nil, err
+	}
+
+	server := grpc.NewServer()
+	streaming.RegisterNodeServer(server, NewService(s.service, s.contextPool, s.iOpts))
+
+	go func() {
+		server.Serve(listener)
+	}()
+
+	return server.Stop, nil
+}
@@ -0,0 +1,172 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package node
+
+import (
+	"github.com/m3db/m3/src/dbnode/generated/proto/streaming"
+	"github.com/m3db/m3/src/dbnode/network/server/grpc/convert"
+	ttconvert "github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/convert"
+	ttnode "github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/node"
+	"github.com/m3db/m3/src/x/context"
+	"github.com/m3db/m3/src/x/instrument"
+
+	"github.com/uber-go/tally"
+)
+
+const (
+	// DefaultFetchTaggedBatchSize is the number of series sent in each
+	// response when the request does not specify a batch size.
+	DefaultFetchTaggedBatchSize = 128
+
+	// maxFetchTaggedBatchBytes bounds the encoded size of each response so
+	// that batches of large series do not exceed the message size limits.
+	maxFetchTaggedBatchBytes = 4 << 20
+)
+
+type serviceMetrics struct {
+	fetchTaggedSeries  tally.Counter
+	fetchTaggedBatches tally.Counter
+}
+
+func newServiceMetrics(scope tally.Scope) serviceMetrics {
+	return serviceMetrics{
+		fetchTaggedSeries:  scope.Counter("fetch-tagged-series"),
+		fetchTaggedBatches: scope.Counter("fetch-tagged-batches"),
+	}
+}
+
+type service struct {
+	service     ttnode.Service
+	contextPool context.Pool
+	metrics     serviceMetrics
+}
+
+// NewService creates a new streaming node service which serves the results
+// of the node service over gRPC streams. Results are sent in batches and
+// reading from the database is paused while the client is not consuming the
+// stream since gRPC flow control blocks sends until the client has capacity
+// to receive them.
+func NewService(
+	svc ttnode.Service,
+	contextPool context.Pool,
+	iOpts instrument.Options,
+) streaming.NodeServer {
+	scope := iOpts.MetricsScope().
+		SubScope("service").
+		Tagged(map[string]string{"service-name": "streaming-node"})
+	return &service{
+		service:     svc,
+		contextPool: contextPool,
+		metrics:     newServiceMetrics(scope),
+	}
+}
+
+func (s *service) FetchTagged(
+	req *streaming.FetchTaggedRequest,
+	stream streaming.Node_FetchTaggedServer,
+) error {
+	ctx := s.contextPool.Get()
+	ctx.SetGoContext(stream.Context())
+	defer ctx.Close()
+
+	iter, err := s.service.FetchTaggedIter(ctx,
+		convert.FromStreamingFetchTaggedRequest(req),
+		ttnode.FetchTaggedIterOptions{Shards: req.Shards})
+	if err != nil {
+		return toStatusError(err)
+	}
+
+	err = s.streamFetchTagged(ctx, req, iter, stream)
+	iter.Close(err)
+	return err
+}
+
+func (s *service) streamFetchTagged(
+	ctx context.Context,
+	req *streaming.FetchTaggedRequest,
+	iter ttnode.FetchTaggedResultsIter,
+	stream streaming.Node_FetchTaggedServer,
+) error {
+	batchSize := int(req.BatchSize)
+	if batchSize <= 0 {
+		batchSize = DefaultFetchTaggedBatchSize
+	}
+
+	var (
+		response = &streaming.FetchTaggedResponse{
+			Elements: make([]*streaming.FetchTaggedElement, 0, batchSize),
+		}
+		batchBytes int
+	)
+	for iter.Next(ctx) {
+		cur := iter.Current()
+		tagBytes, err := cur.WriteTags(nil)
+		if err != nil {
+			return toStatusError(err)
+		}
+		segments, err := cur.WriteSegments(ctx, nil)
+		if err != nil {
+			return toStatusError(err)
+		}
+
+		elem := &streaming.FetchTaggedElement{
+			Id:          cur.ID(),
+			EncodedTags: tagBytes,
+			Segments:    convert.ToStreamingSegments(segments),
+		}
+		response.Elements = append(response.Elements, elem)
+		batchBytes += elem.Size()
+		s.metrics.fetchTaggedSeries.Inc(1)
+
+		if len(response.Elements) < batchSize && batchBytes < maxFetchTaggedBatchBytes {
+			continue
+		}
+
+		// NB: Send blocks until the stream has capacity for the response, this
+		// applies backpressure to reading further series.
+		if err := stream.Send(response); err != nil {
+			return err
+		}
+		s.metrics.fetchTaggedBatches.Inc(1)
+		for i := range response.Elements {
+			response.Elements[i] = nil
+		}
+		response.Elements = response.Elements[:0]
+		batchBytes = 0
+	}
+	if err := iter.Err(); err != nil {
+		return toStatusError(err)
+	}
+
+	response.Done = true
+	response.Exhaustive = iter.Exhaustive()
+	response.WaitedIndex = int64(iter.WaitedIndex())
+	response.WaitedSeriesRead = int64(iter.WaitedSeriesRead())
+	if err := stream.Send(response); err != nil {
+		return err
+	}
+	s.metrics.fetchTaggedBatches.Inc(1)
+	return nil
+}
+
+func toStatusError(err error) error {
+	return convert.ToStatusError(ttconvert.ToRPCError(err))
+}
@@ -0,0 +1,230 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package node
+
+import (
+	gocontext "context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/m3db/m3/src/dbnode/generated/proto/streaming"
+	"github.com/m3db/m3/src/dbnode/generated/thrift/rpc"
+	ttnode "github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/node"
+	"github.com/m3db/m3/src/x/context"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	"github.com/m3db/m3/src/x/instrument"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type testIDResult struct {
+	id string
+}
+
+func (r testIDResult) ID() []byte {
+	return []byte(r.id)
+}
+
+func (r testIDResult) WriteTags(dst []byte) ([]byte, error) {
+	return append(dst, "tags-"+r.id...), nil
+}
+
+func (r testIDResult) WriteSegments(
+	_ context.Context,
+	dst []*rpc.Segments,
+) ([]*rpc.Segments, error) {
+	return append(dst, &rpc.Segments{
+		Merged: &rpc.Segment{Head: []byte(r.id), Tail: []byte{1}},
+	}), nil
+}
+
+type testFetchTaggedIter struct {
+	ttnode.FetchTaggedResultsIter
+
+	results  []testIDResult
+	idx      int
+	err      error
+	closed   bool
+	closeErr error
+}
+
+func (i *testFetchTaggedIter) Next(context.Context) bool {
+	if i.idx >= len(i.results) {
+		return false
+	}
+	i.idx++
+	return true
+}
+
+func (i *testFetchTaggedIter) Current() ttnode.IDResult { return i.results[i.idx-1] }
+func (i *testFetchTaggedIter) Err() error               { return i.err }
+func (i *testFetchTaggedIter) Exhaustive() bool         { return true }
+func (i *testFetchTaggedIter) WaitedIndex() int         { return 2 }
+func (i *testFetchTaggedIter) WaitedSeriesRead() int    { return 3 }
+
+func (i *testFetchTaggedIter) Close(err error) {
+	i.closed = true
+	i.closeErr = err
+}
+
+type testNodeService struct {
+	ttnode.Service
+
+	iter  *testFetchTaggedIter
+	err   error
+	req   *rpc.FetchTaggedRequest
+	iOpts ttnode.FetchTaggedIterOptions
+}
+
+func (s *testNodeService) FetchTaggedIter(
+	_ context.Context,
+	req *rpc.FetchTaggedRequest,
+	opts ttnode.FetchTaggedIterOptions,
+) (ttnode.FetchTaggedResultsIter, error) {
+	s.req = req
+	s.iOpts = opts
+	if s.err != nil {
+		return nil, s.err
+	}
+	return s.iter, nil
+}
+
+func newTestNodeClient(
+	t *testing.T,
+	svc ttnode.Service,
+) (streaming.NodeClient, func()) {
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	streaming.RegisterNodeServer(server,
+		NewService(svc, context.NewPool(context.NewOptions()), instrument.NewOptions()))
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	conn, err := grpc.Dial("bufconn", grpc.WithInsecure(),
+		grpc.WithContextDialer(func(gocontext.Context, string) (net.Conn, error) {
+			return listener.Dial()
+		}))
+	require.NoError(t, err)
+
+	return streaming.NewNodeClient(conn), func() {
+		assert.NoError(t, conn.Close())
+		server.Stop()
+	}
+}
+
+func readFetchTaggedStream(
+	t *testing.T,
+	client streaming.NodeClient,
+	req *streaming.FetchTaggedRequest,
+) ([]*streaming.FetchTaggedResponse, error) {
+	stream, err := client.FetchTagged(gocontext.Background(), req)
+	require.NoError(t, err)
+
+	var responses []*streaming.FetchTaggedResponse
+	for {
+		r, err := stream.Recv()
+		if err == io.EOF {
+			return responses, nil
+		}
+		if err != nil {
+			return responses, err
+		}
+		responses = append(responses, r)
+	}
+}
+
+func TestServiceFetchTaggedBatches(t *testing.T) {
+	iter := &testFetchTaggedIter{
+		results: []testIDResult{{id: "a"}, {id: "b"}, {id: "c"}},
+	}
+	svc := &testNodeService{iter: iter}
+	client, closeFn := newTestNodeClient(t, svc)
+	defer closeFn()
+
+	responses, err := readFetchTaggedStream(t, client, &streaming.FetchTaggedRequest{
+		NameSpace:       []byte("ns"),
+		RangeStartNanos: 10,
+		RangeEndNanos:   20,
+		FetchData:       true,
+		Shards:          []uint32{1, 3},
+		BatchSize:       2,
+	})
+	require.NoError(t, err)
+
+	require.Len(t, responses, 2)
+	require.Len(t, responses[0].Elements, 2)
+	assert.False(t, responses[0].Done)
+	assert.Equal(t, []byte("a"), responses[0].Elements[0].Id)
+	assert.Equal(t, []byte("tags-a"), responses[0].Elements[0].EncodedTags)
+	require.Len(t, responses[0].Elements[0].Segments, 1)
+	assert.Equal(t, []byte("a"), responses[0].Elements[0].Segments[0].Merged.Head)
+	assert.Equal(t, []byte("b"), responses[0].Elements[1].Id)
+
+	last := responses[1]
+	require.Len(t, last.Elements, 1)
+	assert.Equal(t, []byte("c"), last.Elements[0].Id)
+	assert.True(t, last.Done)
+	assert.True(t, last.Exhaustive)
+	assert.Equal(t, int64(2), last.WaitedIndex)
+	assert.Equal(t, int64(3), last.WaitedSeriesRead)
+
+	assert.Equal(t, []uint32{1, 3}, svc.iOpts.Shards)
+	assert.Equal(t, []byte("ns"), svc.req.NameSpace)
+	assert.Equal(t, int64(10), svc.req.RangeStart)
+	assert.Equal(t, int64(20), svc.req.RangeEnd)
+	assert.True(t, iter.closed)
+	assert.NoError(t, iter.closeErr)
+}
+
+func TestServiceFetchTaggedErrors(t *testing.T) {
+	svc := &testNodeService{
+		err: xerrors.NewInvalidParamsError(errors.New("bad query")),
+	}
+	client, closeFn := newTestNodeClient(t, svc)
+	defer closeFn()
+
+	_, err := readFetchTaggedStream(t, client, &streaming.FetchTaggedRequest{})
+	require.Error(t, err)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	iter := &testFetchTaggedIter{
+		results: []testIDResult{{id: "a"}},
+		err:     errors.New("read failed"),
+	}
+	svc = &testNodeService{iter: iter}
+	client, closeFn = newTestNodeClient(t, svc)
+	defer closeFn()
+
+	responses, err := readFetchTaggedStream(t, client, &streaming.FetchTaggedRequest{})
+	require.Error(t, err)
+	assert.Equal(t, codes.Internal, status.Code(err))
+	assert.Len(t, responses, 0)
+	assert.True(t, iter.closed)
+	assert.Error(t, iter.closeErr)
+}
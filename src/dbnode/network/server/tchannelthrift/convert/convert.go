@@ -30,6 +30,7 @@ import (
 	tterrors "github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/errors"
 	"github.com/m3db/m3/src/dbnode/storage/index"
 	"github.com/m3db/m3/src/dbnode/storage/limits"
+	"github.com/m3db/m3/src/dbnode/ts"
 	"github.com/m3db/m3/src/dbnode/x/xio"
 	"github.com/m3db/m3/src/dbnode/x/xpool"
 	"github.com/m3db/m3/src/m3ninx/generated/proto/querypb"
@@ -46,6 +47,8 @@ var (
 	errUnknownUnit      = errors.New("unknown unit")
 	errNilTaggedRequest = errors.New("nil write tagged request")
 
+	defaultCardinalityNameTag = []byte("__name__")
+
 	timeZero time.Time
 )
 
@@ -212,7 +215,7 @@ func ToRPCError(err error) *rpc.Error {
 		return rpcErr
 	}
 
-	if limits.IsQueryLimitExceededError(err) {
+	if limits.IsQueryLimitExceededError(err) || limits.IsTenantQuotaExceededError(err) {
 		return tterrors.NewResourceExhaustedError(err)
 	}
 	if xerrors.IsInvalidParams(err) {
@@ -329,6 +332,328 @@ func ToRPCFetchTaggedRequest(
 	return request, nil
 }
 
+// FromRPCDeleteSeriesRequest converts the rpc request type for DeleteSeriesRequest
+// into corresponding Go API types.
+func FromRPCDeleteSeriesRequest(
+	req *rpc.DeleteSeriesRequest,
+) (ident.ID, index.Query, xtime.UnixNano, xtime.UnixNano, error) {
+	start, rangeStartErr := ToTime(req.RangeStart, req.RangeTimeType)
+	if rangeStartErr != nil {
+		return nil, index.Query{}, 0, 0, rangeStartErr
+	}
+
+	end, rangeEndErr := ToTime(req.RangeEnd, req.RangeTimeType)
+	if rangeEndErr != nil {
+		return nil, index.Query{}, 0, 0, rangeEndErr
+	}
+
+	q, err := idx.Unmarshal(req.Query)
+	if err != nil {
+		return nil, index.Query{}, 0, 0, err
+	}
+
+	ns := ident.StringID(string(req.NameSpace))
+	return ns, index.Query{Query: q}, start, end, nil
+}
+
+// ToRPCDeleteSeriesRequest converts the Go `client/` types into rpc request type
+// for DeleteSeriesRequest.
+func ToRPCDeleteSeriesRequest(
+	ns ident.ID,
+	q index.Query,
+	start, end xtime.UnixNano,
+) (rpc.DeleteSeriesRequest, error) {
+	rangeStart, tsErr := ToValue(start, fetchTaggedTimeType)
+	if tsErr != nil {
+		return rpc.DeleteSeriesRequest{}, tsErr
+	}
+
+	rangeEnd, tsErr := ToValue(end, fetchTaggedTimeType)
+	if tsErr != nil {
+		return rpc.DeleteSeriesRequest{}, tsErr
+	}
+
+	query, queryErr := idx.Marshal(q.Query)
+	if queryErr != nil {
+		return rpc.DeleteSeriesRequest{}, queryErr
+	}
+
+	return rpc.DeleteSeriesRequest{
+		NameSpace:     ns.Bytes(),
+		Query:         query,
+		RangeStart:    rangeStart,
+		RangeEnd:      rangeEnd,
+		RangeTimeType: fetchTaggedTimeType,
+	}, nil
+}
+
+// FromRPCFetchExemplarsRequest converts the rpc request type for
+// FetchExemplarsRequest into corresponding Go API types.
+func FromRPCFetchExemplarsRequest(
+	req *rpc.FetchExemplarsRequest,
+) (ident.ID, index.Query, index.QueryOptions, error) {
+	start, rangeStartErr := ToTime(req.RangeStart, req.RangeTimeType)
+	if rangeStartErr != nil {
+		return nil, index.Query{}, index.QueryOptions{}, rangeStartErr
+	}
+
+	end, rangeEndErr := ToTime(req.RangeEnd, req.RangeTimeType)
+	if rangeEndErr != nil {
+		return nil, index.Query{}, index.QueryOptions{}, rangeEndErr
+	}
+
+	opts := index.QueryOptions{
+		StartInclusive: start,
+		EndExclusive:   end,
+	}
+	if l := req.SeriesLimit; l != nil {
+		opts.SeriesLimit = int(*l)
+	}
+	if len(req.Source) > 0 {
+		opts.Source = req.Source
+	}
+
+	q, err := idx.Unmarshal(req.Query)
+	if err != nil {
+		return nil, index.Query{}, index.QueryOptions{}, err
+	}
+
+	ns := ident.StringID(string(req.NameSpace))
+	return ns, index.Query{Query: q}, opts, nil
+}
+
+// ToRPCFetchExemplarsRequest converts the Go `client/` types into rpc
+// request type for FetchExemplarsRequest.
+func ToRPCFetchExemplarsRequest(
+	ns ident.ID,
+	q index.Query,
+	opts index.QueryOptions,
+) (rpc.FetchExemplarsRequest, error) {
+	rangeStart, tsErr := ToValue(opts.StartInclusive, fetchTaggedTimeType)
+	if tsErr != nil {
+		return rpc.FetchExemplarsRequest{}, tsErr
+	}
+
+	rangeEnd, tsErr := ToValue(opts.EndExclusive, fetchTaggedTimeType)
+	if tsErr != nil {
+		return rpc.FetchExemplarsRequest{}, tsErr
+	}
+
+	query, queryErr := idx.Marshal(q.Query)
+	if queryErr != nil {
+		return rpc.FetchExemplarsRequest{}, queryErr
+	}
+
+	request := rpc.FetchExemplarsRequest{
+		NameSpace:     ns.Bytes(),
+		Query:         query,
+		RangeStart:    rangeStart,
+		RangeEnd:      rangeEnd,
+		RangeTimeType: fetchTaggedTimeType,
+		Source:        opts.Source,
+	}
+	if opts.SeriesLimit > 0 {
+		l := int64(opts.SeriesLimit)
+		request.SeriesLimit = &l
+	}
+	return request, nil
+}
+
+// FromRPCCardinalityRequest converts the rpc request type for
+// CardinalityRequest into corresponding Go API types.
+func FromRPCCardinalityRequest(
+	req *rpc.CardinalityRequest,
+) (ident.ID, index.CardinalityOptions, error) {
+	start, rangeStartErr := ToTime(req.RangeStart, req.RangeTimeType)
+	if rangeStartErr != nil {
+		return nil, index.CardinalityOptions{}, rangeStartErr
+	}
+
+	end, rangeEndErr := ToTime(req.RangeEnd, req.RangeTimeType)
+	if rangeEndErr != nil {
+		return nil, index.CardinalityOptions{}, rangeEndErr
+	}
+
+	opts := index.CardinalityOptions{
+		StartInclusive: start,
+		EndExclusive:   end,
+		NameField:      defaultCardinalityNameTag,
+	}
+	if len(req.Shards) > 0 {
+		opts.Shards = make([]uint32, 0, len(req.Shards))
+		for _, shard := range req.Shards {
+			opts.Shards = append(opts.Shards, uint32(shard))
+		}
+	}
+	if t := req.NameTag; t != nil {
+		opts.NameField = []byte(*t)
+	}
+	if l := req.Limit; l != nil {
+		opts.Limit = int(*l)
+	}
+	if len(req.Source) > 0 {
+		opts.Source = req.Source
+	}
+	if l := req.SeriesLimit; l != nil {
+		opts.SeriesLimit = int(*l)
+	}
+	if l := req.DocsLimit; l != nil {
+		opts.DocsLimit = int(*l)
+	}
+	opts.RequireExhaustive = req.RequireExhaustive
+
+	ns := ident.StringID(req.NameSpace)
+	return ns, opts, nil
+}
+
+// ToRPCCardinalityRequest converts the Go `client/` types into rpc
+// request type for CardinalityRequest.
+func ToRPCCardinalityRequest(
+	ns ident.ID,
+	opts index.CardinalityOptions,
+) (rpc.CardinalityRequest, error) {
+	rangeStart, tsErr := ToValue(opts.StartInclusive, fetchTaggedTimeType)
+	if tsErr != nil {
+		return rpc.CardinalityRequest{}, tsErr
+	}
+
+	rangeEnd, tsErr := ToValue(opts.EndExclusive, fetchTaggedTimeType)
+	if tsErr != nil {
+		return rpc.CardinalityRequest{}, tsErr
+	}
+
+	request := rpc.CardinalityRequest{
+		NameSpace:         ns.String(),
+		RangeStart:        rangeStart,
+		RangeEnd:          rangeEnd,
+		RangeTimeType:     fetchTaggedTimeType,
+		Source:            opts.Source,
+		RequireExhaustive: opts.RequireExhaustive,
+	}
+	if len(opts.Shards) > 0 {
+		request.Shards = make([]int32, 0, len(opts.Shards))
+		for _, shard := range opts.Shards {
+			request.Shards = append(request.Shards, int32(shard))
+		}
+	}
+	if len(opts.NameField) > 0 {
+		t := string(opts.NameField)
+		request.NameTag = &t
+	}
+	if opts.Limit > 0 {
+		l := int64(opts.Limit)
+		request.Limit = &l
+	}
+	if opts.SeriesLimit > 0 {
+		l := int64(opts.SeriesLimit)
+		request.SeriesLimit = &l
+	}
+	if opts.DocsLimit > 0 {
+		l := int64(opts.DocsLimit)
+		request.DocsLimit = &l
+	}
+	return request, nil
+}
+
+// FromRPCCardinalityResult converts the rpc cardinality result into
+// corresponding Go API types.
+func FromRPCCardinalityResult(res *rpc.CardinalityResult_) index.CardinalityResult {
+	return index.CardinalityResult{
+		NumSeries:                   res.NumSeries,
+		SeriesCountByMetricName:     fromRPCCardinalityStats(res.SeriesCountByMetricName),
+		LabelValueCountByLabelName:  fromRPCCardinalityStats(res.LabelValueCountByLabelName),
+		MemoryInBytesByLabelName:    fromRPCCardinalityStats(res.MemoryInBytesByLabelName),
+		SeriesCountByLabelValuePair: fromRPCCardinalityStats(res.SeriesCountByLabelValuePair),
+		Exhaustive:                  res.Exhaustive,
+	}
+}
+
+// ToRPCCardinalityResult converts the Go API cardinality result into
+// corresponding rpc types.
+func ToRPCCardinalityResult(res index.CardinalityResult) *rpc.CardinalityResult_ {
+	return &rpc.CardinalityResult_{
+		NumSeries:                   res.NumSeries,
+		SeriesCountByMetricName:     toRPCCardinalityStats(res.SeriesCountByMetricName),
+		LabelValueCountByLabelName:  toRPCCardinalityStats(res.LabelValueCountByLabelName),
+		MemoryInBytesByLabelName:    toRPCCardinalityStats(res.MemoryInBytesByLabelName),
+		SeriesCountByLabelValuePair: toRPCCardinalityStats(res.SeriesCountByLabelValuePair),
+		Exhaustive:                  res.Exhaustive,
+	}
+}
+
+func fromRPCCardinalityStats(stats []*rpc.CardinalityStat) []index.CardinalityStat {
+	result := make([]index.CardinalityStat, 0, len(stats))
+	for _, stat := range stats {
+		if stat == nil {
+			continue
+		}
+		result = append(result, index.CardinalityStat{
+			Name:  stat.Name,
+			Value: stat.Value,
+		})
+	}
+	return result
+}
+
+func toRPCCardinalityStats(stats []index.CardinalityStat) []*rpc.CardinalityStat {
+	result := make([]*rpc.CardinalityStat, 0, len(stats))
+	for _, stat := range stats {
+		result = append(result, &rpc.CardinalityStat{
+			Name:  stat.Name,
+			Value: stat.Value,
+		})
+	}
+	return result
+}
+
+// FromRPCExemplars converts the rpc exemplars into corresponding Go API types.
+func FromRPCExemplars(exemplars []*rpc.Exemplar) ([]ts.Exemplar, error) {
+	result := make([]ts.Exemplar, 0, len(exemplars))
+	for _, exemplar := range exemplars {
+		if exemplar == nil {
+			continue
+		}
+		timestamp, err := ToTime(exemplar.Timestamp, exemplar.TimestampTimeType)
+		if err != nil {
+			return nil, err
+		}
+		labels := make([]ts.ExemplarLabel, 0, len(exemplar.Labels))
+		for _, label := range exemplar.Labels {
+			labels = append(labels, ts.ExemplarLabel{
+				Name:  []byte(label.Name),
+				Value: []byte(label.Value),
+			})
+		}
+		result = append(result, ts.Exemplar{
+			TimestampNanos: timestamp,
+			Value:          exemplar.Value,
+			Labels:         labels,
+		})
+	}
+	return result, nil
+}
+
+// ToRPCExemplars converts the Go API exemplars into corresponding rpc types.
+func ToRPCExemplars(exemplars []ts.Exemplar) []*rpc.Exemplar {
+	result := make([]*rpc.Exemplar, 0, len(exemplars))
+	for _, exemplar := range exemplars {
+		labels := make([]*rpc.Tag, 0, len(exemplar.Labels))
+		for _, label := range exemplar.Labels {
+			labels = append(labels, &rpc.Tag{
+				Name:  string(label.Name),
+				Value: string(label.Value),
+			})
+		}
+		result = append(result, &rpc.Exemplar{
+			Timestamp:         int64(exemplar.TimestampNanos),
+			Value:             exemplar.Value,
+			Labels:            labels,
+			TimestampTimeType: rpc.TimeType_UNIX_NANOSECONDS,
+		})
+	}
+	return result
+}
+
 // FromRPCAggregateQueryRequest converts the rpc request type for AggregateRawQueryRequest into corresponding Go API types.
 func FromRPCAggregateQueryRequest(
 	req *rpc.AggregateQueryRequest,
@@ -32,6 +32,7 @@ import (
 	tterrors "github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/errors"
 	"github.com/m3db/m3/src/dbnode/storage/index"
 	"github.com/m3db/m3/src/dbnode/storage/limits"
+	"github.com/m3db/m3/src/dbnode/ts"
 	"github.com/m3db/m3/src/dbnode/x/xpool"
 	"github.com/m3db/m3/src/m3ninx/idx"
 	xerrors "github.com/m3db/m3/src/x/errors"
@@ -180,6 +181,159 @@ func TestConvertFetchTaggedRequest(t *testing.T) {
 	}
 }
 
+func TestConvertDeleteSeriesRequest(t *testing.T) {
+	var (
+		ns    = ident.StringID("abc")
+		start = xtime.Now().Add(-900 * time.Hour).Truncate(time.Second)
+		end   = xtime.Now().Truncate(time.Second)
+	)
+	q, rpcQ := termQueryTestCase(t)
+
+	req, err := convert.ToRPCDeleteSeriesRequest(ns, index.Query{Query: q}, start, end)
+	require.NoError(t, err)
+	require.Equal(t, rpc.DeleteSeriesRequest{
+		NameSpace:     ns.Bytes(),
+		Query:         rpcQ,
+		RangeStart:    mustToRPCTime(t, start),
+		RangeEnd:      mustToRPCTime(t, end),
+		RangeTimeType: rpc.TimeType_UNIX_NANOSECONDS,
+	}, req)
+
+	// Ensure the range time type on the request is respected.
+	req.RangeStart = int64(start.Seconds())
+	req.RangeEnd = int64(end.Seconds())
+	req.RangeTimeType = rpc.TimeType_UNIX_SECONDS
+	id, observedQuery, observedStart, observedEnd, err := convert.FromRPCDeleteSeriesRequest(&req)
+	require.NoError(t, err)
+	require.Equal(t, ns.String(), id.String())
+	require.True(t, index.NewQueryMatcher(index.Query{Query: q}).Matches(observedQuery))
+	require.Equal(t, start, observedStart)
+	require.Equal(t, end, observedEnd)
+}
+
+func TestConvertFetchExemplarsRequest(t *testing.T) {
+	var (
+		seriesLimit int64 = 10
+		ns                = ident.StringID("abc")
+		start             = xtime.Now().Add(-900 * time.Hour).Truncate(time.Second)
+		end               = xtime.Now().Truncate(time.Second)
+	)
+	q, rpcQ := termQueryTestCase(t)
+	opts := index.QueryOptions{
+		StartInclusive: start,
+		EndExclusive:   end,
+		SeriesLimit:    int(seriesLimit),
+	}
+
+	req, err := convert.ToRPCFetchExemplarsRequest(ns, index.Query{Query: q}, opts)
+	require.NoError(t, err)
+	require.Equal(t, rpc.FetchExemplarsRequest{
+		NameSpace:     ns.Bytes(),
+		Query:         rpcQ,
+		RangeStart:    mustToRPCTime(t, start),
+		RangeEnd:      mustToRPCTime(t, end),
+		RangeTimeType: rpc.TimeType_UNIX_NANOSECONDS,
+		SeriesLimit:   &seriesLimit,
+	}, req)
+
+	id, observedQuery, observedOpts, err := convert.FromRPCFetchExemplarsRequest(&req)
+	require.NoError(t, err)
+	require.Equal(t, ns.String(), id.String())
+	require.True(t, index.NewQueryMatcher(index.Query{Query: q}).Matches(observedQuery))
+	require.Equal(t, opts, observedOpts)
+}
+
+func TestConvertExemplars(t *testing.T) {
+	exemplars := []ts.Exemplar{
+		{
+			TimestampNanos: xtime.Now().Truncate(time.Millisecond),
+			Value:          1.5,
+			Labels: []ts.ExemplarLabel{
+				{Name: []byte("trace_id"), Value: []byte("abc")},
+			},
+		},
+	}
+
+	observed, err := convert.FromRPCExemplars(convert.ToRPCExemplars(exemplars))
+	require.NoError(t, err)
+	require.Equal(t, exemplars, observed)
+
+	// Ensure the timestamp time type is respected.
+	rpcExemplars := []*rpc.Exemplar{{
+		Timestamp:         int64(exemplars[0].TimestampNanos.Seconds()),
+		TimestampTimeType: rpc.TimeType_UNIX_SECONDS,
+	}}
+	observed, err = convert.FromRPCExemplars(rpcExemplars)
+	require.NoError(t, err)
+	require.Equal(t, exemplars[0].TimestampNanos.Truncate(time.Second),
+		observed[0].TimestampNanos)
+}
+
+func TestConvertCardinalityRequest(t *testing.T) {
+	var (
+		limit       int64 = 5
+		seriesLimit int64 = 10
+		docsLimit   int64 = 20
+		nameTag           = "name"
+		ns                = ident.StringID("abc")
+		start             = xtime.Now().Add(-2 * time.Hour).Truncate(time.Second)
+		end               = xtime.Now().Truncate(time.Second)
+	)
+	opts := index.CardinalityOptions{
+		StartInclusive:    start,
+		EndExclusive:      end,
+		Shards:            []uint32{1, 3},
+		NameField:         []byte(nameTag),
+		Limit:             int(limit),
+		SeriesLimit:       int(seriesLimit),
+		DocsLimit:         int(docsLimit),
+		RequireExhaustive: true,
+	}
+
+	req, err := convert.ToRPCCardinalityRequest(ns, opts)
+	require.NoError(t, err)
+	require.Equal(t, rpc.CardinalityRequest{
+		NameSpace:         ns.String(),
+		RangeStart:        mustToRPCTime(t, start),
+		RangeEnd:          mustToRPCTime(t, end),
+		RangeTimeType:     rpc.TimeType_UNIX_NANOSECONDS,
+		Shards:            []int32{1, 3},
+		NameTag:           &nameTag,
+		Limit:             &limit,
+		SeriesLimit:       &seriesLimit,
+		DocsLimit:         &docsLimit,
+		RequireExhaustive: true,
+	}, req)
+
+	id, observedOpts, err := convert.FromRPCCardinalityRequest(&req)
+	require.NoError(t, err)
+	require.Equal(t, ns.String(), id.String())
+	require.Equal(t, opts, observedOpts)
+
+	// Ensure the metric name tag defaults to the Prometheus name label.
+	req.NameTag = nil
+	_, observedOpts, err = convert.FromRPCCardinalityRequest(&req)
+	require.NoError(t, err)
+	require.Equal(t, []byte("__name__"), observedOpts.NameField)
+}
+
+func TestConvertCardinalityResult(t *testing.T) {
+	result := index.CardinalityResult{
+		NumSeries:                   3,
+		SeriesCountByMetricName:     []index.CardinalityStat{{Name: "up", Value: 3}},
+		LabelValueCountByLabelName:  []index.CardinalityStat{{Name: "job", Value: 2}},
+		MemoryInBytesByLabelName:    []index.CardinalityStat{{Name: "job", Value: 5}},
+		SeriesCountByLabelValuePair: []index.CardinalityStat{{Name: "job=api", Value: 2}},
+		Exhaustive:                  true,
+	}
+	observed := convert.FromRPCCardinalityResult(convert.ToRPCCardinalityResult(result))
+	require.Equal(t, result, observed)
+
+	result.Exhaustive = false
+	observed = convert.FromRPCCardinalityResult(convert.ToRPCCardinalityResult(result))
+	require.Equal(t, result, observed)
+}
+
 func TestConvertAggregateRawQueryRequest(t *testing.T) {
 	var (
 		seriesLimit       int64 = 10
@@ -277,6 +431,9 @@ func TestToRPCError(t *testing.T) {
 		convert.ToRPCError(xerrors.Wrap(limitErr, "wrap")),
 	)
 
+	quotaErr := xerrors.NewInvalidParamsError(limits.NewTenantQuotaExceededError("quota"))
+	require.Equal(t, tterrors.NewResourceExhaustedError(quotaErr), convert.ToRPCError(quotaErr))
+
 	require.Equal(t, tterrors.NewBadRequestError(invalidParamsErr), convert.ToRPCError(invalidParamsErr))
 	require.Equal(
 		t,
@@ -30,6 +30,8 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/uber-go/tally"
 
+	"github.com/m3db/m3/src/cluster/shard"
+	"github.com/m3db/m3/src/dbnode/sharding"
 	"github.com/m3db/m3/src/dbnode/storage"
 	"github.com/m3db/m3/src/dbnode/storage/index"
 	"github.com/m3db/m3/src/dbnode/storage/limits/permits"
@@ -100,6 +102,51 @@ func TestFetchResultIterTestNoReleaseWithoutAcquire(t *testing.T) {
 	require.Equal(t, 1, blockPermits.closed)
 }
 
+func TestFetchResultIterShards(t *testing.T) {
+	nsID := ident.StringID("testNs")
+	resMap := index.NewQueryResults(nsID, index.QueryResultsOptions{}, testIndexOptions)
+	for i := 0; i < 20; i++ {
+		id := ident.StringID(fmt.Sprintf("seriesId_%d", i))
+		resMap.Map().Set(id.Bytes(), doc.Document{})
+	}
+
+	shardSet, err := sharding.NewShardSet(
+		sharding.NewShards([]uint32{0, 1, 2, 3}, shard.Available),
+		sharding.DefaultHashFn(4))
+	require.NoError(t, err)
+
+	blockPermits := &fakePermits{available: 10, quotaPerPermit: 1000}
+	iter := newFetchTaggedResultsIter(fetchTaggedResultsIterOpts{
+		queryResult: index.QueryResult{
+			Results: resMap,
+		},
+		nsID:            nsID,
+		blockPermits:    blockPermits,
+		instrumentClose: func(err error) {},
+		shards:          map[uint32]struct{}{1: {}, 3: {}},
+		shardSet:        shardSet,
+	})
+
+	ctx := context.NewBackground()
+	total := 0
+	for iter.Next(ctx) {
+		total++
+		shard := shardSet.Lookup(ident.BytesID(iter.Current().ID()))
+		require.True(t, shard == 1 || shard == 3)
+	}
+	require.NoError(t, iter.Err())
+	iter.Close(nil)
+
+	expected := 0
+	for _, entry := range resMap.Map().Iter() {
+		if shard := shardSet.Lookup(ident.BytesID(entry.Key())); shard == 1 || shard == 3 {
+			expected++
+		}
+	}
+	require.True(t, expected > 0)
+	require.Equal(t, expected, total)
+}
+
 func requireSeriesBlockMetric(t *testing.T, scope tally.TestScope) {
 	values, ok := scope.Snapshot().Histograms()["series-blocks+"]
 	require.True(t, ok)
@@ -35,6 +35,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/network/server/tchannelthrift"
 	"github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/convert"
 	tterrors "github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/errors"
+	"github.com/m3db/m3/src/dbnode/sharding"
 	"github.com/m3db/m3/src/dbnode/storage"
 	"github.com/m3db/m3/src/dbnode/storage/block"
 	"github.com/m3db/m3/src/dbnode/storage/index"
@@ -114,6 +115,12 @@ type serviceMetrics struct {
 	fetchBlocksMetadata     instrument.MethodMetrics
 	repair                  instrument.MethodMetrics
 	truncate                instrument.MethodMetrics
+	deleteSeries            instrument.MethodMetrics
+	writeExemplars          instrument.MethodMetrics
+	fetchExemplars          instrument.MethodMetrics
+	cardinality             instrument.MethodMetrics
+	fetchMerkleTree         instrument.MethodMetrics
+	repairStatus            instrument.MethodMetrics
 	fetchBatchRawRPCS       tally.Counter
 	fetchBatchRaw           instrument.BatchMethodMetrics
 	writeBatchRawRPCs       tally.Counter
@@ -138,6 +145,12 @@ func newServiceMetrics(scope tally.Scope, opts instrument.TimerOptions) serviceM
 		fetchBlocksMetadata:     instrument.NewMethodMetrics(scope, "fetchBlocksMetadata", opts),
 		repair:                  instrument.NewMethodMetrics(scope, "repair", opts),
 		truncate:                instrument.NewMethodMetrics(scope, "truncate", opts),
+		deleteSeries:            instrument.NewMethodMetrics(scope, "deleteSeries", opts),
+		writeExemplars:          instrument.NewMethodMetrics(scope, "writeExemplars", opts),
+		fetchExemplars:          instrument.NewMethodMetrics(scope, "fetchExemplars", opts),
+		cardinality:             instrument.NewMethodMetrics(scope, "cardinality", opts),
+		fetchMerkleTree:         instrument.NewMethodMetrics(scope, "fetchMerkleTree", opts),
+		repairStatus:            instrument.NewMethodMetrics(scope, "repairStatus", opts),
 		fetchBatchRawRPCS:       scope.Counter("fetchBatchRaw-rpcs"),
 		fetchBatchRaw:           instrument.NewBatchMethodMetrics(scope, "fetchBatchRaw", opts),
 		writeBatchRawRPCs:       scope.Counter("writeBatchRaw-rpcs"),
@@ -265,7 +278,11 @@ type Service interface {
 
 	// FetchTaggedIter returns an iterator for the results of FetchTagged.
 	// It is the responsibility of the caller to close the returned iterator.
-	FetchTaggedIter(ctx context.Context, req *rpc.FetchTaggedRequest) (FetchTaggedResultsIter, error)
+	FetchTaggedIter(
+		ctx context.Context,
+		req *rpc.FetchTaggedRequest,
+		opts FetchTaggedIterOptions,
+	) (FetchTaggedResultsIter, error)
 
 	// SetDatabase only safe to be called one time once the service has started.
 	SetDatabase(db storage.Database) error
@@ -281,6 +298,13 @@ type Service interface {
 	Metadata(key string) (string, bool)
 }
 
+// FetchTaggedIterOptions are the options for FetchTaggedIter.
+type FetchTaggedIterOptions struct {
+	// Shards restricts the results to series owned by the shards, all
+	// the shards of the node are returned if empty.
+	Shards []uint32
+}
+
 // NewService creates a new node TChannel Thrift service
 func NewService(db storage.Database, opts tchannelthrift.Options) Service {
 	if opts == nil {
@@ -735,7 +759,7 @@ func (s *service) readDatapoints(
 
 func (s *service) FetchTagged(tctx thrift.Context, req *rpc.FetchTaggedRequest) (*rpc.FetchTaggedResult_, error) {
 	ctx := tchannelthrift.Context(tctx)
-	iter, err := s.FetchTaggedIter(ctx, req)
+	iter, err := s.FetchTaggedIter(ctx, req, FetchTaggedIterOptions{})
 	if err != nil {
 		return nil, convert.ToRPCError(err)
 	}
@@ -788,7 +812,11 @@ func (s *service) fetchTaggedResult(ctx context.Context,
 	return response, nil
 }
 
-func (s *service) FetchTaggedIter(ctx context.Context, req *rpc.FetchTaggedRequest) (FetchTaggedResultsIter, error) {
+func (s *service) FetchTaggedIter(
+	ctx context.Context,
+	req *rpc.FetchTaggedRequest,
+	iterOpts FetchTaggedIterOptions,
+) (FetchTaggedResultsIter, error) {
 	callStart := s.nowFn()
 	ctx = addRequestDataToM3Context(ctx, req.Source, tchannelthrift.FetchTagged)
 	ctx, sp, sampled := ctx.StartSampledTraceSpan(tracepoint.FetchTagged)
@@ -809,7 +837,7 @@ func (s *service) FetchTaggedIter(ctx context.Context, req *rpc.FetchTaggedReque
 
 		s.metrics.fetchTagged.ReportSuccessOrError(err, s.nowFn().Sub(callStart))
 	}
-	iter, err := s.fetchTaggedIter(ctx, req, instrumentClose)
+	iter, err := s.fetchTaggedIter(ctx, req, iterOpts, instrumentClose)
 	if err != nil {
 		instrumentClose(err)
 	}
@@ -819,6 +847,7 @@ func (s *service) FetchTaggedIter(ctx context.Context, req *rpc.FetchTaggedReque
 func (s *service) fetchTaggedIter(
 	ctx context.Context,
 	req *rpc.FetchTaggedRequest,
+	iterOpts FetchTaggedIterOptions,
 	instrumentClose func(error),
 ) (FetchTaggedResultsIter, error) {
 	db, err := s.startReadRPCWithDB()
@@ -844,6 +873,18 @@ func (s *service) fetchTaggedIter(
 		return nil, convert.ToRPCError(err)
 	}
 
+	var (
+		shards   map[uint32]struct{}
+		shardSet sharding.ShardSet
+	)
+	if len(iterOpts.Shards) > 0 {
+		shardSet = db.ShardSet()
+		shards = make(map[uint32]struct{}, len(iterOpts.Shards))
+		for _, shard := range iterOpts.Shards {
+			shards[shard] = struct{}{}
+		}
+	}
+
 	tagEncoder := s.pools.tagEncoder.Get()
 	ctx.RegisterFinalizer(tagEncoder)
 
@@ -860,6 +901,8 @@ func (s *service) fetchTaggedIter(
 		blockPermits:    permits,
 		requireNoWait:   req.RequireNoWait,
 		indexWaited:     queryResult.Waited,
+		shards:          shards,
+		shardSet:        shardSet,
 	}), nil
 }
 
@@ -924,6 +967,9 @@ type fetchTaggedResultsIterOpts struct {
 	blockPermits    permits.Permits
 	requireNoWait   bool
 	indexWaited     int
+	// shards restricts the results to series owned by the shards when set.
+	shards   map[uint32]struct{}
+	shardSet sharding.ShardSet
 }
 
 func newFetchTaggedResultsIter(opts fetchTaggedResultsIterOpts) FetchTaggedResultsIter { //nolint: gocritic
@@ -958,6 +1004,12 @@ func (i *fetchTaggedResultsIter) Next(ctx context.Context) bool {
 	// initialize the iterator state on the first fetch.
 	if i.idx == 0 {
 		for _, entry := range i.queryResult.Results.Map().Iter() { // nolint: gocritic
+			if i.shards != nil {
+				shard := i.shardSet.Lookup(ident.BytesID(entry.Key()))
+				if _, ok := i.shards[shard]; !ok {
+					continue
+				}
+			}
 			result := idResult{
 				queryResult: entry,
 				docReader:   i.docReader,
@@ -986,7 +1038,7 @@ func (i *fetchTaggedResultsIter) Next(ctx context.Context) bool {
 		i.idResults[i.idx-1].blockReaders = nil
 	}
 
-	if i.idx == i.queryResult.Results.Map().Len() {
+	if i.idx == len(i.idResults) {
 		return false
 	}
 
@@ -994,7 +1046,7 @@ func (i *fetchTaggedResultsIter) Next(ctx context.Context) bool {
 		// ensure the blockReaders exist for the current series ID. additionally try to prefetch additional blockReaders
 		// for future seriesID to pipeline the disk reads.
 	readBlocks:
-		for i.blockReadIdx < i.queryResult.Results.Map().Len() {
+		for i.blockReadIdx < len(i.idResults) {
 			currResult := &i.idResults[i.blockReadIdx]
 			blockIter := currResult.blockReadersIter
 
@@ -1577,6 +1629,69 @@ func (s *service) FetchBlocksMetadataRawV2(tctx thrift.Context, req *rpc.FetchBl
 	return result, nil
 }
 
+func (s *service) FetchMerkleTree(
+	tctx thrift.Context,
+	req *rpc.FetchMerkleTreeRequest,
+) (*rpc.FetchMerkleTreeResult_, error) {
+	db, err := s.startReadRPCWithDB()
+	if err != nil {
+		return nil, err
+	}
+	defer s.readRPCCompleted(tctx)
+
+	callStart := s.nowFn()
+	ctx := tchannelthrift.Context(tctx)
+
+	var (
+		nsID       = s.newID(ctx, req.NameSpace)
+		shardID    = uint32(req.Shard)
+		blockStart = xtime.UnixNano(req.BlockStart)
+		depth      = int(req.Depth)
+		level      = int(req.Level)
+		indices    = make([]int, 0, len(req.Indices))
+	)
+	for _, idx := range req.Indices {
+		indices = append(indices, int(idx))
+	}
+
+	tree, err := db.MerkleTree(nsID, shardID, blockStart, depth)
+	if err != nil {
+		s.metrics.fetchMerkleTree.ReportError(s.nowFn().Sub(callStart))
+		return nil, convert.ToRPCError(err)
+	}
+	digests, err := tree.Digests(level, indices)
+	if err != nil {
+		s.metrics.fetchMerkleTree.ReportError(s.nowFn().Sub(callStart))
+		return nil, tterrors.NewBadRequestError(err)
+	}
+
+	result := rpc.NewFetchMerkleTreeResult_()
+	result.Digests = make([]int64, 0, len(digests))
+	for _, digest := range digests {
+		result.Digests = append(result.Digests, int64(digest))
+	}
+
+	if req.IncludeLeafEntries && level == depth {
+		entries, err := db.MerkleTreeLeafEntries(nsID, shardID, blockStart, depth, indices)
+		if err != nil {
+			s.metrics.fetchMerkleTree.ReportError(s.nowFn().Sub(callStart))
+			return nil, convert.ToRPCError(err)
+		}
+		result.LeafEntries = make([]*rpc.MerkleTreeLeafEntry, 0, len(entries))
+		for _, entry := range entries {
+			result.LeafEntries = append(result.LeafEntries, &rpc.MerkleTreeLeafEntry{
+				Leaf:     int32(entry.Leaf),
+				ID:       entry.ID.Bytes(),
+				Size:     entry.Size,
+				Checksum: int64(entry.Checksum),
+			})
+		}
+	}
+
+	s.metrics.fetchMerkleTree.ReportSuccess(s.nowFn().Sub(callStart))
+	return result, nil
+}
+
 func (s *service) getFetchBlocksMetadataRawV2Result(
 	ctx context.Context,
 	nextPageToken storage.PageToken,
@@ -2188,6 +2303,47 @@ func (s *service) Repair(tctx thrift.Context) error {
 	return nil
 }
 
+func (s *service) RepairStatus(
+	tctx thrift.Context,
+	req *rpc.RepairStatusRequest,
+) (*rpc.RepairStatusResult_, error) {
+	db, err := s.startRPCWithDB()
+	if err != nil {
+		return nil, err
+	}
+
+	callStart := s.nowFn()
+	statuses := db.RepairStatus()
+	result := rpc.NewRepairStatusResult_()
+	result.Shards = make([]*rpc.RepairShardStatus, 0, len(statuses))
+	for _, status := range statuses {
+		if req.NameSpace != nil && status.Namespace.String() != *req.NameSpace {
+			continue
+		}
+		shardStatus := &rpc.RepairShardStatus{
+			NameSpace:                    status.Namespace.String(),
+			Shard:                        int32(status.Shard),
+			RepairType:                   status.Type.String(),
+			Running:                      status.Running,
+			BlockStart:                   int64(status.BlockStart),
+			LastStarted:                  int64(status.LastStarted),
+			LastCompleted:                int64(status.LastCompleted),
+			NumRepairs:                   status.NumRepairs,
+			NumComparedMerkleTreeNodes:   status.NumComparedMerkleTreeNodes,
+			NumDivergentMerkleTreeLeaves: status.NumDivergentMerkleTreeLeaves,
+			NumRepairedBlocks:            status.NumRepairedBlocks,
+		}
+		if status.LastError != "" {
+			lastError := status.LastError
+			shardStatus.LastError = &lastError
+		}
+		result.Shards = append(result.Shards, shardStatus)
+	}
+
+	s.metrics.repairStatus.ReportSuccess(s.nowFn().Sub(callStart))
+	return result, nil
+}
+
 func (s *service) Truncate(tctx thrift.Context, req *rpc.TruncateRequest) (r *rpc.TruncateResult_, err error) {
 	db, err := s.startRPCWithDB()
 	if err != nil {
@@ -2210,6 +2366,150 @@ func (s *service) Truncate(tctx thrift.Context, req *rpc.TruncateRequest) (r *rp
 	return res, nil
 }
 
+func (s *service) DeleteSeries(
+	tctx thrift.Context,
+	req *rpc.DeleteSeriesRequest,
+) (*rpc.DeleteSeriesResult_, error) {
+	db, err := s.startRPCWithDB()
+	if err != nil {
+		return nil, err
+	}
+
+	callStart := s.nowFn()
+	ctx := tchannelthrift.Context(tctx)
+	nsID, query, start, end, err := convert.FromRPCDeleteSeriesRequest(req)
+	if err != nil {
+		s.metrics.deleteSeries.ReportError(s.nowFn().Sub(callStart))
+		return nil, tterrors.NewBadRequestError(err)
+	}
+
+	deleted, err := db.DeleteSeries(ctx, nsID, query, start, end)
+	if err != nil {
+		s.metrics.deleteSeries.ReportError(s.nowFn().Sub(callStart))
+		return nil, convert.ToRPCError(err)
+	}
+
+	res := rpc.NewDeleteSeriesResult_()
+	res.NumSeriesByShard = make(map[int32]int64, len(deleted))
+	for shard, n := range deleted {
+		res.NumSeries += n
+		res.NumSeriesByShard[int32(shard)] = n
+	}
+
+	s.metrics.deleteSeries.ReportSuccess(s.nowFn().Sub(callStart))
+
+	return res, nil
+}
+
+func (s *service) WriteExemplars(
+	tctx thrift.Context,
+	req *rpc.WriteExemplarsRequest,
+) error {
+	db, err := s.startWriteRPCWithDB()
+	if err != nil {
+		return err
+	}
+	defer s.writeRPCCompleted()
+
+	callStart := s.nowFn()
+	nsID := ident.StringID(string(req.NameSpace))
+	for _, elem := range req.Elements {
+		if elem == nil {
+			continue
+		}
+
+		exemplars, err := convert.FromRPCExemplars(elem.Exemplars)
+		if err != nil {
+			s.metrics.writeExemplars.ReportError(s.nowFn().Sub(callStart))
+			return tterrors.NewBadRequestError(err)
+		}
+
+		if err := db.WriteExemplars(nsID, ident.BytesID(elem.ID), exemplars); err != nil {
+			s.metrics.writeExemplars.ReportError(s.nowFn().Sub(callStart))
+			return convert.ToRPCError(err)
+		}
+	}
+
+	s.metrics.writeExemplars.ReportSuccess(s.nowFn().Sub(callStart))
+	return nil
+}
+
+func (s *service) FetchExemplars(
+	tctx thrift.Context,
+	req *rpc.FetchExemplarsRequest,
+) (*rpc.FetchExemplarsResult_, error) {
+	db, err := s.startReadRPCWithDB()
+	if err != nil {
+		return nil, err
+	}
+	defer s.readRPCCompleted(tctx)
+
+	callStart := s.nowFn()
+	ctx := tchannelthrift.Context(tctx)
+	nsID, query, opts, err := convert.FromRPCFetchExemplarsRequest(req)
+	if err != nil {
+		s.metrics.fetchExemplars.ReportError(s.nowFn().Sub(callStart))
+		return nil, tterrors.NewBadRequestError(err)
+	}
+
+	result, err := db.FetchExemplars(ctx, nsID, query, opts)
+	if err != nil {
+		s.metrics.fetchExemplars.ReportError(s.nowFn().Sub(callStart))
+		return nil, convert.ToRPCError(err)
+	}
+
+	res := rpc.NewFetchExemplarsResult_()
+	res.Exhaustive = result.Exhaustive
+	res.Elements = make([]*rpc.FetchExemplarsResultElement, 0, len(result.Results))
+	for _, series := range result.Results {
+		enc := s.pools.tagEncoder.Get()
+		ctx.RegisterFinalizer(enc)
+		tags := idxconvert.ToSeriesTags(series.Metadata, idxconvert.Opts{NoClone: true})
+		encodedTags, err := encodeTags(enc, tags, s.opts.InstrumentOptions())
+		if err != nil {
+			s.metrics.fetchExemplars.ReportError(s.nowFn().Sub(callStart))
+			return nil, convert.ToRPCError(err)
+		}
+
+		res.Elements = append(res.Elements, &rpc.FetchExemplarsResultElement{
+			ID:          series.Metadata.ID,
+			EncodedTags: encodedTags.Bytes(),
+			Exemplars:   convert.ToRPCExemplars(series.Exemplars),
+		})
+	}
+
+	s.metrics.fetchExemplars.ReportSuccess(s.nowFn().Sub(callStart))
+	return res, nil
+}
+
+func (s *service) Cardinality(
+	tctx thrift.Context,
+	req *rpc.CardinalityRequest,
+) (*rpc.CardinalityResult_, error) {
+	db, err := s.startReadRPCWithDB()
+	if err != nil {
+		return nil, err
+	}
+	defer s.readRPCCompleted(tctx)
+
+	callStart := s.nowFn()
+	ctx := tchannelthrift.Context(tctx)
+	nsID, opts, err := convert.FromRPCCardinalityRequest(req)
+	if err != nil {
+		s.metrics.cardinality.ReportError(s.nowFn().Sub(callStart))
+		return nil, tterrors.NewBadRequestError(err)
+	}
+
+	result, err := db.Cardinality(ctx, nsID, opts)
+	if err != nil {
+		s.metrics.cardinality.ReportError(s.nowFn().Sub(callStart))
+		return nil, convert.ToRPCError(err)
+	}
+
+	s.metrics.cardinality.ReportSuccess(s.nowFn().Sub(callStart))
+	return convert.ToRPCCardinalityResult(result), nil
+}
+
 func (s *service) GetPersistRateLimit(
 	ctx thrift.Context,
 ) (*rpc.NodePersistRateLimitResult_, error) {
@@ -42,6 +42,7 @@ import (
 	conv "github.com/m3db/m3/src/dbnode/storage/index/convert"
 	"github.com/m3db/m3/src/dbnode/storage/limits"
 	"github.com/m3db/m3/src/dbnode/storage/limits/permits"
+	"github.com/m3db/m3/src/dbnode/storage/repair"
 	"github.com/m3db/m3/src/dbnode/storage/series"
 	"github.com/m3db/m3/src/dbnode/topology"
 	"github.com/m3db/m3/src/dbnode/tracepoint"
@@ -3145,6 +3146,152 @@ func TestServiceRepair(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestServiceFetchMerkleTree(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := storage.NewMockDatabase(ctrl)
+	mockDB.EXPECT().Options().Return(testStorageOpts).AnyTimes()
+	mockDB.EXPECT().IsOverloaded().Return(false).AnyTimes()
+
+	service := NewService(mockDB, testTChannelThriftOptions).(*service)
+
+	tctx, _ := tchannelthrift.NewContext(time.Minute)
+	ctx := tchannelthrift.Context(tctx)
+	defer ctx.Close()
+
+	var (
+		nsID       = "metrics"
+		blockStart = xtime.Now().Truncate(time.Hour)
+		depth      = 1
+		id         = ident.StringID("foo")
+		leaf       = repair.MerkleTreeLeaf(id.Bytes(), depth)
+	)
+	builder, err := repair.NewMerkleTreeBuilder(depth)
+	require.NoError(t, err)
+	builder.Add(id.Bytes(), 42)
+	tree := builder.Build()
+
+	mockDB.EXPECT().
+		MerkleTree(ident.NewIDMatcher(nsID), uint32(1), blockStart, depth).
+		Return(tree, nil).Times(2)
+	mockDB.EXPECT().
+		MerkleTreeLeafEntries(ident.NewIDMatcher(nsID), uint32(1), blockStart, depth, []int{leaf}).
+		Return([]repair.MerkleTreeLeafEntry{
+			{Leaf: leaf, ID: id, Size: 10, Checksum: 42},
+		}, nil)
+
+	r, err := service.FetchMerkleTree(tctx, &rpc.FetchMerkleTreeRequest{
+		NameSpace:  []byte(nsID),
+		Shard:      1,
+		BlockStart: int64(blockStart),
+		Depth:      int32(depth),
+		Level:      0,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []int64{int64(tree.Root())}, r.Digests)
+	require.Nil(t, r.LeafEntries)
+
+	r, err = service.FetchMerkleTree(tctx, &rpc.FetchMerkleTreeRequest{
+		NameSpace:          []byte(nsID),
+		Shard:              1,
+		BlockStart:         int64(blockStart),
+		Depth:              int32(depth),
+		Level:              int32(depth),
+		Indices:            []int32{int32(leaf)},
+		IncludeLeafEntries: true,
+	})
+	require.NoError(t, err)
+	digests, err := tree.Digests(depth, []int{leaf})
+	require.NoError(t, err)
+	require.Equal(t, []int64{int64(digests[0])}, r.Digests)
+	require.Equal(t, []*rpc.MerkleTreeLeafEntry{
+		{Leaf: int32(leaf), ID: id.Bytes(), Size: 10, Checksum: 42},
+	}, r.LeafEntries)
+}
+
+func TestServiceFetchMerkleTreeInvalidLevel(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := storage.NewMockDatabase(ctrl)
+	mockDB.EXPECT().Options().Return(testStorageOpts).AnyTimes()
+	mockDB.EXPECT().IsOverloaded().Return(false)
+
+	service := NewService(mockDB, testTChannelThriftOptions).(*service)
+
+	tctx, _ := tchannelthrift.NewContext(time.Minute)
+	ctx := tchannelthrift.Context(tctx)
+	defer ctx.Close()
+
+	builder, err := repair.NewMerkleTreeBuilder(1)
+	require.NoError(t, err)
+	mockDB.EXPECT().
+		MerkleTree(ident.NewIDMatcher("metrics"), uint32(0), xtime.UnixNano(0), 1).
+		Return(builder.Build(), nil)
+
+	_, err = service.FetchMerkleTree(tctx, &rpc.FetchMerkleTreeRequest{
+		NameSpace: []byte("metrics"),
+		Depth:     1,
+		Level:     2,
+	})
+	require.Error(t, err)
+	rpcErr, ok := err.(*rpc.Error)
+	require.True(t, ok)
+	require.True(t, tterrors.IsBadRequestError(rpcErr))
+}
+
+func TestServiceRepairStatus(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := storage.NewMockDatabase(ctrl)
+	mockDB.EXPECT().Options().Return(testStorageOpts).AnyTimes()
+	mockDB.EXPECT().IsOverloaded().Return(false).Times(2)
+
+	service := NewService(mockDB, testTChannelThriftOptions).(*service)
+
+	tctx, _ := tchannelthrift.NewContext(time.Minute)
+	ctx := tchannelthrift.Context(tctx)
+	defer ctx.Close()
+
+	now := xtime.Now()
+	mockDB.EXPECT().RepairStatus().Return([]repair.ShardStatus{
+		{
+			Namespace:         ident.StringID("metrics"),
+			Shard:             1,
+			Type:              repair.MerkleRepair,
+			LastStarted:       now,
+			LastError:         "boom",
+			NumRepairs:        2,
+			NumRepairedBlocks: 3,
+		},
+		{
+			Namespace: ident.StringID("other"),
+			Shard:     1,
+		},
+	}).Times(2)
+
+	r, err := service.RepairStatus(tctx, &rpc.RepairStatusRequest{})
+	require.NoError(t, err)
+	require.Equal(t, 2, len(r.Shards))
+
+	nsID := "metrics"
+	r, err = service.RepairStatus(tctx, &rpc.RepairStatusRequest{NameSpace: &nsID})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(r.Shards))
+	lastError := "boom"
+	require.Equal(t, &rpc.RepairShardStatus{
+		NameSpace:         nsID,
+		Shard:             1,
+		RepairType:        repair.MerkleRepair.String(),
+		LastStarted:       int64(now),
+		LastError:         &lastError,
+		NumRepairs:        2,
+		NumRepairedBlocks: 3,
+	}, r.Shards[0])
+}
+
 func TestServiceTruncate(t *testing.T) {
 	ctrl := xtest.NewController(t)
 	defer ctrl.Finish()
@@ -3170,6 +3317,219 @@ func TestServiceTruncate(t *testing.T) {
 	assert.Equal(t, truncated, r.NumSeries)
 }
 
+func TestServiceDeleteSeries(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := storage.NewMockDatabase(ctrl)
+	mockDB.EXPECT().Options().Return(testStorageOpts).AnyTimes()
+	mockDB.EXPECT().IsOverloaded().Return(false).Times(2)
+
+	service := NewService(mockDB, testTChannelThriftOptions).(*service)
+
+	tctx, _ := tchannelthrift.NewContext(time.Minute)
+	ctx := tchannelthrift.Context(tctx)
+	defer ctx.Close()
+
+	var (
+		nsID    = "metrics"
+		start   = xtime.Now().Add(-2 * time.Hour).Truncate(time.Second)
+		end     = xtime.Now().Truncate(time.Second)
+		deleted = map[uint32]int64{0: 4, 3: 8}
+	)
+	req, err := idx.NewRegexpQuery([]byte("foo"), []byte("b.*"))
+	require.NoError(t, err)
+	data, err := idx.Marshal(req)
+	require.NoError(t, err)
+
+	mockDB.EXPECT().DeleteSeries(gomock.Any(), ident.NewIDMatcher(nsID),
+		index.NewQueryMatcher(index.Query{Query: req}), start, end).
+		Return(deleted, nil)
+
+	r, err := service.DeleteSeries(tctx, &rpc.DeleteSeriesRequest{
+		NameSpace:     []byte(nsID),
+		Query:         data,
+		RangeStart:    start.Seconds(),
+		RangeEnd:      end.Seconds(),
+		RangeTimeType: rpc.TimeType_UNIX_SECONDS,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(12), r.NumSeries)
+	assert.Equal(t, map[int32]int64{0: 4, 3: 8}, r.NumSeriesByShard)
+
+	// Invalid queries are bad requests.
+	_, err = service.DeleteSeries(tctx, &rpc.DeleteSeriesRequest{
+		NameSpace: []byte(nsID),
+		Query:     []byte("invalid"),
+	})
+	require.Error(t, err)
+	rpcErr, ok := err.(*rpc.Error)
+	require.True(t, ok)
+	assert.True(t, tterrors.IsBadRequestError(rpcErr))
+}
+
+func TestServiceWriteExemplars(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := storage.NewMockDatabase(ctrl)
+	mockDB.EXPECT().Options().Return(testStorageOpts).AnyTimes()
+	mockDB.EXPECT().IsOverloaded().Return(false).AnyTimes()
+
+	service := NewService(mockDB, testTChannelThriftOptions).(*service)
+
+	tctx, _ := tchannelthrift.NewContext(time.Minute)
+	ctx := tchannelthrift.Context(tctx)
+	defer ctx.Close()
+
+	var (
+		nsID = "metrics"
+		now  = xtime.Now().Truncate(time.Second)
+	)
+	mockDB.EXPECT().WriteExemplars(ident.NewIDMatcher(nsID), ident.NewIDMatcher("foo"),
+		[]ts.Exemplar{{
+			TimestampNanos: now,
+			Value:          1.5,
+			Labels: []ts.ExemplarLabel{
+				{Name: []byte("trace_id"), Value: []byte("abc")},
+			},
+		}}).Return(nil)
+
+	require.NoError(t, service.WriteExemplars(tctx, &rpc.WriteExemplarsRequest{
+		NameSpace: []byte(nsID),
+		Elements: []*rpc.WriteExemplarsRequestElement{{
+			ID: []byte("foo"),
+			Exemplars: []*rpc.Exemplar{{
+				Timestamp:         now.Seconds(),
+				Value:             1.5,
+				Labels:            []*rpc.Tag{{Name: "trace_id", Value: "abc"}},
+				TimestampTimeType: rpc.TimeType_UNIX_SECONDS,
+			}},
+		}},
+	}))
+}
+
+func TestServiceFetchExemplars(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := storage.NewMockDatabase(ctrl)
+	mockDB.EXPECT().Options().Return(testStorageOpts).AnyTimes()
+	mockDB.EXPECT().IsOverloaded().Return(false).AnyTimes()
+
+	service := NewService(mockDB, testTChannelThriftOptions).(*service)
+
+	tctx, _ := tchannelthrift.NewContext(time.Minute)
+	ctx := tchannelthrift.Context(tctx)
+	defer ctx.Close()
+
+	var (
+		nsID  = "metrics"
+		start = xtime.Now().Add(-2 * time.Hour).Truncate(time.Second)
+		end   = xtime.Now().Truncate(time.Second)
+	)
+	req, err := idx.NewRegexpQuery([]byte("foo"), []byte("b.*"))
+	require.NoError(t, err)
+	data, err := idx.Marshal(req)
+	require.NoError(t, err)
+
+	exemplars := []ts.Exemplar{{
+		TimestampNanos: start.Add(time.Minute),
+		Value:          2,
+		Labels: []ts.ExemplarLabel{
+			{Name: []byte("trace_id"), Value: []byte("abc")},
+		},
+	}}
+	mockDB.EXPECT().FetchExemplars(gomock.Any(), ident.NewIDMatcher(nsID),
+		index.NewQueryMatcher(index.Query{Query: req}), index.QueryOptions{
+			StartInclusive: start,
+			EndExclusive:   end,
+		}).
+		Return(storage.FetchExemplarsResult{
+			Results: []storage.SeriesExemplars{{
+				Metadata: doc.Metadata{
+					ID:     []byte("foo"),
+					Fields: []doc.Field{{Name: []byte("foo"), Value: []byte("bar")}},
+				},
+				Exemplars: exemplars,
+			}},
+			Exhaustive: true,
+		}, nil)
+
+	r, err := service.FetchExemplars(tctx, &rpc.FetchExemplarsRequest{
+		NameSpace:     []byte(nsID),
+		Query:         data,
+		RangeStart:    start.Seconds(),
+		RangeEnd:      end.Seconds(),
+		RangeTimeType: rpc.TimeType_UNIX_SECONDS,
+	})
+	require.NoError(t, err)
+	assert.True(t, r.Exhaustive)
+	require.Equal(t, 1, len(r.Elements))
+	assert.Equal(t, []byte("foo"), r.Elements[0].ID)
+	assert.NotEmpty(t, r.Elements[0].EncodedTags)
+	observed, err := convert.FromRPCExemplars(r.Elements[0].Exemplars)
+	require.NoError(t, err)
+	assert.Equal(t, exemplars, observed)
+}
+
+func TestServiceCardinality(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := storage.NewMockDatabase(ctrl)
+	mockDB.EXPECT().Options().Return(testStorageOpts).AnyTimes()
+	mockDB.EXPECT().IsOverloaded().Return(false).AnyTimes()
+
+	service := NewService(mockDB, testTChannelThriftOptions).(*service)
+
+	tctx, _ := tchannelthrift.NewContext(time.Minute)
+	ctx := tchannelthrift.Context(tctx)
+	defer ctx.Close()
+
+	var (
+		nsID        = "metrics"
+		limit int64 = 5
+		start       = xtime.Now().Add(-2 * time.Hour).Truncate(time.Second)
+		end         = xtime.Now().Truncate(time.Second)
+	)
+	result := index.CardinalityResult{
+		NumSeries:                   2,
+		SeriesCountByMetricName:     []index.CardinalityStat{{Name: "up", Value: 2}},
+		LabelValueCountByLabelName:  []index.CardinalityStat{{Name: "job", Value: 2}},
+		MemoryInBytesByLabelName:    []index.CardinalityStat{{Name: "job", Value: 5}},
+		SeriesCountByLabelValuePair: []index.CardinalityStat{{Name: "__name__=up", Value: 2}},
+	}
+	mockDB.EXPECT().Cardinality(gomock.Any(), ident.NewIDMatcher(nsID),
+		index.CardinalityOptions{
+			StartInclusive: start,
+			EndExclusive:   end,
+			Shards:         []uint32{2},
+			NameField:      []byte("__name__"),
+			Limit:          int(limit),
+		}).
+		Return(result, nil)
+
+	r, err := service.Cardinality(tctx, &rpc.CardinalityRequest{
+		NameSpace:     nsID,
+		RangeStart:    start.Seconds(),
+		RangeEnd:      end.Seconds(),
+		RangeTimeType: rpc.TimeType_UNIX_SECONDS,
+		Shards:        []int32{2},
+		Limit:         &limit,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, result, convert.FromRPCCardinalityResult(r))
+
+	mockDB.EXPECT().Cardinality(gomock.Any(), ident.NewIDMatcher(nsID), gomock.Any()).
+		Return(index.CardinalityResult{}, errors.New("index error"))
+	_, err = service.Cardinality(tctx, &rpc.CardinalityRequest{
+		NameSpace: nsID,
+		RangeEnd:  end.Seconds(),
+	})
+	require.Error(t, err)
+}
+
 func TestServiceSetPersistRateLimit(t *testing.T) {
 	ctrl := xtest.NewController(t)
 	defer ctrl.Finish()
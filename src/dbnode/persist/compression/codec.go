@@ -0,0 +1,112 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package compression
+
+import (
+	"errors"
+	"runtime"
+	"sync"
+
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+)
+
+var errNoCodec = errors.New("no codec for uncompressed data")
+
+// NewCodec returns the codec for a compression type.
+func NewCodec(t Type) (Codec, error) {
+	switch t {
+	case None:
+		return nil, errNoCodec
+	case Snappy:
+		return snappyCodec{}, nil
+	case Zstd:
+		return newZstdCodec()
+	default:
+		return nil, t.Validate()
+	}
+}
+
+type snappyCodec struct{}
+
+func (snappyCodec) Type() Type {
+	return Snappy
+}
+
+func (snappyCodec) Compress(dst, src []byte) []byte {
+	return snappy.Encode(dst[:cap(dst)], src)
+}
+
+func (snappyCodec) Decompress(dst, src []byte) ([]byte, error) {
+	return snappy.Decode(dst[:cap(dst)], src)
+}
+
+var (
+	zstdOnce    sync.Once
+	zstdErr     error
+	zstdDecoder *zstd.Decoder
+)
+
+// zstdBlockCodec compresses with pooled encoders, each used by a single
+// caller at a time, and decompresses with a single shared decoder. Decoders
+// run background goroutines until closed so they cannot be pooled, instead
+// the shared decoder allows as many concurrent DecodeAll calls as there are
+// processors. Encoders only run goroutines when used as a stream writer so
+// pooled encoders can be garbage collected.
+type zstdBlockCodec struct {
+	encoders *sync.Pool
+	decoder  *zstd.Decoder
+}
+
+var zstdEncoders = &sync.Pool{
+	New: func() interface{} {
+		// NB: options are valid so creating the encoder cannot fail.
+		encoder, _ := zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1))
+		return encoder
+	},
+}
+
+func newZstdCodec() (Codec, error) {
+	zstdOnce.Do(func() {
+		zstdDecoder, zstdErr = zstd.NewReader(nil,
+			zstd.WithDecoderConcurrency(runtime.GOMAXPROCS(0)),
+			zstd.WithDecoderLowmem(false))
+	})
+	if zstdErr != nil {
+		return nil, zstdErr
+	}
+	return zstdBlockCodec{encoders: zstdEncoders, decoder: zstdDecoder}, nil
+}
+
+func (c zstdBlockCodec) Type() Type {
+	return Zstd
+}
+
+func (c zstdBlockCodec) Compress(dst, src []byte) []byte {
+	encoder := c.encoders.Get().(*zstd.Encoder)
+	dst = encoder.EncodeAll(src, dst[:0])
+	c.encoders.Put(encoder)
+	return dst
+}
+
+func (c zstdBlockCodec) Decompress(dst, src []byte) ([]byte, error) {
+	return c.decoder.DecodeAll(src, dst[:0])
+}
@@ -0,0 +1,127 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package compression
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func TestCodecRoundTrip(t *testing.T) {
+	src := bytes.Repeat([]byte("m3tsz-segment-data"), 1024)
+	for _, typ := range []Type{Snappy, Zstd} {
+		t.Run(typ.String(), func(t *testing.T) {
+			codec, err := NewCodec(typ)
+			require.NoError(t, err)
+			assert.Equal(t, typ, codec.Type())
+
+			compressed := codec.Compress(nil, src)
+			assert.True(t, len(compressed) < len(src))
+
+			decompressed, err := codec.Decompress(make([]byte, 0, 16), compressed)
+			require.NoError(t, err)
+			assert.Equal(t, src, decompressed)
+
+			_, err = codec.Decompress(nil, []byte("not compressed"))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestCodecConcurrentRoundTrip(t *testing.T) {
+	for _, typ := range []Type{Snappy, Zstd} {
+		t.Run(typ.String(), func(t *testing.T) {
+			codec, err := NewCodec(typ)
+			require.NoError(t, err)
+
+			var wg sync.WaitGroup
+			for i := 0; i < 16; i++ {
+				i := i
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					src := bytes.Repeat([]byte{byte(i)}, 4096+i)
+					var compressed, decompressed []byte
+					for j := 0; j < 50; j++ {
+						var err error
+						compressed = codec.Compress(compressed, src)
+						decompressed, err = codec.Decompress(decompressed, compressed)
+						if !assert.NoError(t, err) || !assert.Equal(t, src, decompressed) {
+							return
+						}
+					}
+				}()
+			}
+			wg.Wait()
+		})
+	}
+}
+
+func TestNewCodecNone(t *testing.T) {
+	_, err := NewCodec(None)
+	assert.Error(t, err)
+
+	_, err = NewCodec(Type(42))
+	assert.Error(t, err)
+}
+
+func TestParseType(t *testing.T) {
+	for _, typ := range ValidTypes() {
+		parsed, err := ParseType(typ.String())
+		require.NoError(t, err)
+		assert.Equal(t, typ, parsed)
+	}
+
+	parsed, err := ParseType(" ZSTD ")
+	require.NoError(t, err)
+	assert.Equal(t, Zstd, parsed)
+
+	parsed, err = ParseType("")
+	require.NoError(t, err)
+	assert.Equal(t, None, parsed)
+
+	_, err = ParseType("gzip")
+	assert.Error(t, err)
+}
+
+func TestTypeUnmarshalYAML(t *testing.T) {
+	var cfg struct {
+		Compression Type `yaml:"compression"`
+	}
+	require.NoError(t, yaml.Unmarshal([]byte("compression: snappy"), &cfg))
+	assert.Equal(t, Snappy, cfg.Compression)
+
+	assert.Error(t, yaml.Unmarshal([]byte("compression: gzip"), &cfg))
+}
+
+func TestTypeMarshalYAML(t *testing.T) {
+	cfg := struct {
+		Compression Type `yaml:"compression"`
+	}{Compression: Zstd}
+	b, err := yaml.Marshal(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, "compression: zstd\n", string(b))
+}
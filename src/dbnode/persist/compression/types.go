@@ -0,0 +1,124 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package compression provides the block compression codecs that can be
+// applied to the data files of a fileset.
+package compression
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Type is a compression type that can be applied to fileset and commit log
+// data.
+type Type uint8
+
+const (
+	// None indicates that data is not compressed.
+	None Type = iota
+	// Snappy indicates that data is compressed with snappy.
+	Snappy
+	// Zstd indicates that data is compressed with zstd.
+	Zstd
+)
+
+var validTypes = []Type{
+	None,
+	Snappy,
+	Zstd,
+}
+
+// ValidTypes returns the valid compression types.
+func ValidTypes() []Type {
+	return validTypes
+}
+
+// Validate validates that the compression type is valid.
+func (t Type) Validate() error {
+	if t <= Zstd {
+		return nil
+	}
+
+	return fmt.Errorf("invalid compression type: '%v' valid types are: %v",
+		t, validTypes)
+}
+
+func (t Type) String() string {
+	switch t {
+	case None:
+		return "none"
+	case Snappy:
+		return "snappy"
+	case Zstd:
+		return "zstd"
+	default:
+		return fmt.Sprintf("unknown: %d", t)
+	}
+}
+
+// ParseType parses a compression type from its string representation.
+func ParseType(str string) (Type, error) {
+	str = strings.ToLower(strings.TrimSpace(str))
+	if str == "" {
+		return None, nil
+	}
+	for _, valid := range validTypes {
+		if str == valid.String() {
+			return valid, nil
+		}
+	}
+	return None, fmt.Errorf("invalid compression type: '%s' valid types are: %v",
+		str, validTypes)
+}
+
+// UnmarshalYAML unmarshals a compression type.
+func (t *Type) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var str string
+	if err := unmarshal(&str); err != nil {
+		return err
+	}
+
+	parsed, err := ParseType(str)
+	if err != nil {
+		return err
+	}
+
+	*t = parsed
+	return nil
+}
+
+// MarshalYAML marshals a compression type.
+func (t Type) MarshalYAML() (interface{}, error) {
+	return t.String(), nil
+}
+
+// Codec compresses and decompresses blocks of data, it is safe for
+// concurrent use.
+type Codec interface {
+	// Type returns the compression type of the codec.
+	Type() Type
+
+	// Compress compresses src, reusing the capacity of dst if large enough.
+	Compress(dst, src []byte) []byte
+
+	// Decompress decompresses src, reusing the capacity of dst if large enough.
+	Decompress(dst, src []byte) ([]byte, error)
+}
@@ -0,0 +1,135 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// Encrypted blocks are laid out as:
+//
+//	[version][key ID length][key ID][nonce][ciphertext and GCM tag]
+//
+// so that each block can be decrypted on its own with the key it was
+// encrypted with, regardless of which key is current when it is read.
+const (
+	gcmFormatVersion = byte(1)
+	gcmNonceSize     = 12
+	maxKeyIDLength   = 255
+)
+
+var (
+	errEncryptedDataTooShort = errors.New("encrypted data too short")
+	errKeyIDTooLong          = fmt.Errorf("encryption key ID longer than %d bytes", maxKeyIDLength)
+)
+
+type gcmCipher struct {
+	provider KeyProvider
+
+	sync.RWMutex
+	aeads map[string]cipher.AEAD
+}
+
+// NewCipher returns a new AES-GCM cipher that uses keys from the provider.
+func NewCipher(provider KeyProvider) Cipher {
+	return &gcmCipher{
+		provider: provider,
+		aeads:    make(map[string]cipher.AEAD),
+	}
+}
+
+func (c *gcmCipher) aead(key Key) (cipher.AEAD, error) {
+	c.RLock()
+	aead, ok := c.aeads[key.ID]
+	c.RUnlock()
+	if ok {
+		return aead, nil
+	}
+
+	block, err := aes.NewCipher(key.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid encryption key %s: %w", key.ID, err)
+	}
+	aead, err = cipher.NewGCMWithNonceSize(block, gcmNonceSize)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Lock()
+	c.aeads[key.ID] = aead
+	c.Unlock()
+	return aead, nil
+}
+
+func (c *gcmCipher) Encrypt(dst, src []byte) ([]byte, error) {
+	key, err := c.provider.CurrentKey()
+	if err != nil {
+		return nil, err
+	}
+	if len(key.ID) > maxKeyIDLength {
+		return nil, errKeyIDTooLong
+	}
+	aead, err := c.aead(key)
+	if err != nil {
+		return nil, err
+	}
+
+	headerLen := 2 + len(key.ID) + gcmNonceSize
+	dst = append(dst[:0], gcmFormatVersion, byte(len(key.ID)))
+	dst = append(dst, key.ID...)
+	dst = append(dst, make([]byte, gcmNonceSize)...)
+	nonce := dst[headerLen-gcmNonceSize : headerLen]
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(dst, nonce, src, nil), nil
+}
+
+func (c *gcmCipher) Decrypt(dst, src []byte) ([]byte, error) {
+	if len(src) < 2 {
+		return nil, errEncryptedDataTooShort
+	}
+	if version := src[0]; version != gcmFormatVersion {
+		return nil, fmt.Errorf("unknown encrypted data version: %d", version)
+	}
+
+	headerLen := 2 + int(src[1]) + gcmNonceSize
+	if len(src) < headerLen {
+		return nil, errEncryptedDataTooShort
+	}
+	key, err := c.provider.Key(string(src[2 : headerLen-gcmNonceSize]))
+	if err != nil {
+		return nil, err
+	}
+	aead, err := c.aead(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := src[headerLen-gcmNonceSize : headerLen]
+	return aead.Open(dst[:0], nonce, src[headerLen:], nil)
+}
@@ -0,0 +1,150 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package encryption
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestKey(t *testing.T, dir, id string, size int) {
+	key := make([]byte, size)
+	for i := range key {
+		key[i] = byte(len(id) + i)
+	}
+	encoded := base64.StdEncoding.EncodeToString(key)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, id), []byte(encoded+"\n"), 0600))
+}
+
+func newTestKeyDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "encryption-keys")
+	require.NoError(t, err)
+	return dir
+}
+
+func TestCipherRoundTrip(t *testing.T) {
+	dir := newTestKeyDir(t)
+	defer os.RemoveAll(dir)
+
+	for _, size := range []int{16, 24, 32} {
+		id := fmt.Sprintf("key-%d", size)
+		writeTestKey(t, dir, id, size)
+
+		provider, err := NewFileKeyProvider(dir, id)
+		require.NoError(t, err)
+		c := NewCipher(provider)
+
+		for _, plaintext := range [][]byte{nil, []byte("hello world")} {
+			encrypted, err := c.Encrypt(nil, plaintext)
+			require.NoError(t, err)
+			if len(plaintext) > 0 {
+				assert.NotContains(t, string(encrypted), string(plaintext))
+			}
+
+			decrypted, err := c.Decrypt(nil, encrypted)
+			require.NoError(t, err)
+			assert.Equal(t, len(plaintext), len(decrypted))
+			assert.Equal(t, string(plaintext), string(decrypted))
+		}
+	}
+}
+
+func TestCipherNoncesAreUnique(t *testing.T) {
+	dir := newTestKeyDir(t)
+	defer os.RemoveAll(dir)
+	writeTestKey(t, dir, "a", 32)
+
+	provider, err := NewFileKeyProvider(dir, "a")
+	require.NoError(t, err)
+	c := NewCipher(provider)
+
+	first, err := c.Encrypt(nil, []byte("data"))
+	require.NoError(t, err)
+	second, err := c.Encrypt(nil, []byte("data"))
+	require.NoError(t, err)
+	assert.NotEqual(t, first, second)
+}
+
+func TestCipherDecryptsWithRotatedKeys(t *testing.T) {
+	dir := newTestKeyDir(t)
+	defer os.RemoveAll(dir)
+	writeTestKey(t, dir, "old", 32)
+	writeTestKey(t, dir, "new", 32)
+
+	oldProvider, err := NewFileKeyProvider(dir, "old")
+	require.NoError(t, err)
+	encrypted, err := NewCipher(oldProvider).Encrypt(nil, []byte("data"))
+	require.NoError(t, err)
+
+	// Data encrypted with a previous key can still be read after rotating
+	// the current key, or with no current key at all.
+	for _, currentKeyID := range []string{"new", ""} {
+		provider, err := NewFileKeyProvider(dir, currentKeyID)
+		require.NoError(t, err)
+		decrypted, err := NewCipher(provider).Decrypt(nil, encrypted)
+		require.NoError(t, err)
+		assert.Equal(t, "data", string(decrypted))
+	}
+}
+
+func TestCipherDecryptErrors(t *testing.T) {
+	dir := newTestKeyDir(t)
+	defer os.RemoveAll(dir)
+	writeTestKey(t, dir, "a", 32)
+
+	provider, err := NewFileKeyProvider(dir, "a")
+	require.NoError(t, err)
+	c := NewCipher(provider)
+
+	encrypted, err := c.Encrypt(nil, []byte("data"))
+	require.NoError(t, err)
+
+	_, err = c.Decrypt(nil, encrypted[:1])
+	assert.Equal(t, errEncryptedDataTooShort, err)
+
+	_, err = c.Decrypt(nil, encrypted[:5])
+	assert.Equal(t, errEncryptedDataTooShort, err)
+
+	tampered := append([]byte(nil), encrypted...)
+	tampered[len(tampered)-1] ^= 0xff
+	_, err = c.Decrypt(nil, tampered)
+	assert.Error(t, err)
+
+	unknownVersion := append([]byte(nil), encrypted...)
+	unknownVersion[0] = 0
+	_, err = c.Decrypt(nil, unknownVersion)
+	assert.Error(t, err)
+
+	// Rewrite the key so that the cached AEAD of a new cipher uses a
+	// different key than the one the data was encrypted with.
+	writeTestKey(t, dir, "a", 16)
+	provider, err = NewFileKeyProvider(dir, "")
+	require.NoError(t, err)
+	_, err = NewCipher(provider).Decrypt(nil, encrypted)
+	assert.Error(t, err)
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package encryption
+
+import "errors"
+
+var errNoKeyProviderConfigured = errors.New("no encryption key provider configured")
+
+// Configuration configures the encryption of commit logs and fileset data at
+// rest.
+type Configuration struct {
+	// Enabled enables encryption of newly written data, existing encrypted
+	// data can be read as long as a key provider is configured.
+	Enabled bool `yaml:"enabled"`
+
+	// KeyProvider configures the provider of encryption keys.
+	KeyProvider KeyProviderConfiguration `yaml:"keyProvider"`
+}
+
+// KeyProviderConfiguration configures the provider of encryption keys.
+type KeyProviderConfiguration struct {
+	// File configures a provider that reads keys from files.
+	File *FileKeyProviderConfiguration `yaml:"file"`
+}
+
+// FileKeyProviderConfiguration configures a provider that reads keys from
+// the files of a directory.
+type FileKeyProviderConfiguration struct {
+	// Path is the path of the directory holding a file for each key named by
+	// the ID of the key and containing the base64 encoded key.
+	Path string `yaml:"path" validate:"nonzero"`
+
+	// CurrentKeyID is the ID of the key to encrypt new data with.
+	CurrentKeyID string `yaml:"currentKeyID"`
+}
+
+// NewKeyProvider returns the configured key provider.
+func (c KeyProviderConfiguration) NewKeyProvider() (KeyProvider, error) {
+	if c.File != nil {
+		return NewFileKeyProvider(c.File.Path, c.File.CurrentKeyID)
+	}
+	return nil, errNoKeyProviderConfigured
+}
+
+// NewCipher returns the cipher to encrypt and decrypt data with.
+func (c Configuration) NewCipher() (Cipher, error) {
+	provider, err := c.KeyProvider.NewKeyProvider()
+	if err != nil {
+		return nil, err
+	}
+	if c.Enabled {
+		// Fail fast if there is no key to encrypt new data with.
+		if _, err := provider.CurrentKey(); err != nil {
+			return nil, err
+		}
+	}
+	return NewCipher(provider), nil
+}
@@ -0,0 +1,108 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package encryption
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+var (
+	errNoCurrentKeyID = errors.New("no current encryption key ID configured")
+	errInvalidKeyID   = errors.New("invalid encryption key ID")
+)
+
+// fileKeyProvider reads keys from a directory holding a file for each key
+// named by the ID of the key and containing the base64 encoded key, which
+// suits keys mounted from a secret store. Keys are read on first use and
+// cached, so new keys can be added to the directory without a restart but the
+// contents of a key must never change once it has been used.
+type fileKeyProvider struct {
+	dir          string
+	currentKeyID string
+
+	sync.RWMutex
+	keys map[string]Key
+}
+
+// NewFileKeyProvider returns a key provider that reads keys from the files of
+// a directory, the current key ID may be empty if the provider is only used
+// to decrypt data.
+func NewFileKeyProvider(dir string, currentKeyID string) (KeyProvider, error) {
+	p := &fileKeyProvider{
+		dir:          dir,
+		currentKeyID: currentKeyID,
+		keys:         make(map[string]Key),
+	}
+	if currentKeyID != "" {
+		// Fail fast if the current key is missing or invalid.
+		if _, err := p.CurrentKey(); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+func (p *fileKeyProvider) CurrentKey() (Key, error) {
+	if p.currentKeyID == "" {
+		return Key{}, errNoCurrentKeyID
+	}
+	return p.Key(p.currentKeyID)
+}
+
+func (p *fileKeyProvider) Key(id string) (Key, error) {
+	p.RLock()
+	key, ok := p.keys[id]
+	p.RUnlock()
+	if ok {
+		return key, nil
+	}
+
+	if id == "" || id != filepath.Base(id) || strings.HasPrefix(id, ".") {
+		return Key{}, fmt.Errorf("%w: %q", errInvalidKeyID, id)
+	}
+
+	contents, err := ioutil.ReadFile(filepath.Join(p.dir, id))
+	if err != nil {
+		return Key{}, fmt.Errorf("could not read encryption key %s: %w", id, err)
+	}
+	bytes, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(contents)))
+	if err != nil {
+		return Key{}, fmt.Errorf("could not decode encryption key %s: %w", id, err)
+	}
+	switch len(bytes) {
+	case 16, 24, 32:
+	default:
+		return Key{}, fmt.Errorf("encryption key %s must be 16, 24 or 32 bytes, is %d bytes",
+			id, len(bytes))
+	}
+
+	key = Key{ID: id, Bytes: bytes}
+	p.Lock()
+	p.keys[id] = key
+	p.Unlock()
+	return key, nil
+}
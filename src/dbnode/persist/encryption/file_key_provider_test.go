@@ -0,0 +1,120 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package encryption
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileKeyProvider(t *testing.T) {
+	dir := newTestKeyDir(t)
+	defer os.RemoveAll(dir)
+	writeTestKey(t, dir, "a", 32)
+
+	provider, err := NewFileKeyProvider(dir, "a")
+	require.NoError(t, err)
+
+	key, err := provider.CurrentKey()
+	require.NoError(t, err)
+	assert.Equal(t, "a", key.ID)
+	assert.Equal(t, 32, len(key.Bytes))
+
+	// Keys added after construction are picked up on first use.
+	writeTestKey(t, dir, "b", 16)
+	key, err = provider.Key("b")
+	require.NoError(t, err)
+	assert.Equal(t, "b", key.ID)
+	assert.Equal(t, 16, len(key.Bytes))
+
+	_, err = provider.Key("missing")
+	assert.Error(t, err)
+}
+
+func TestFileKeyProviderNoCurrentKey(t *testing.T) {
+	dir := newTestKeyDir(t)
+	defer os.RemoveAll(dir)
+
+	provider, err := NewFileKeyProvider(dir, "")
+	require.NoError(t, err)
+	_, err = provider.CurrentKey()
+	assert.Equal(t, errNoCurrentKeyID, err)
+}
+
+func TestFileKeyProviderInvalidKeys(t *testing.T) {
+	dir := newTestKeyDir(t)
+	defer os.RemoveAll(dir)
+
+	// Missing current key fails on construction.
+	_, err := NewFileKeyProvider(dir, "missing")
+	assert.Error(t, err)
+
+	writeTestKey(t, dir, "short", 8)
+	_, err = NewFileKeyProvider(dir, "short")
+	assert.Error(t, err)
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "garbage"), []byte("!!!"), 0600))
+	_, err = NewFileKeyProvider(dir, "garbage")
+	assert.Error(t, err)
+
+	provider, err := NewFileKeyProvider(dir, "")
+	require.NoError(t, err)
+	for _, id := range []string{"../a", "a/b", ".hidden", "."} {
+		_, err := provider.Key(id)
+		assert.True(t, errors.Is(err, errInvalidKeyID), id)
+	}
+}
+
+func TestConfigurationNewCipher(t *testing.T) {
+	dir := newTestKeyDir(t)
+	defer os.RemoveAll(dir)
+	writeTestKey(t, dir, "a", 32)
+
+	_, err := Configuration{Enabled: true}.NewCipher()
+	assert.Equal(t, errNoKeyProviderConfigured, err)
+
+	_, err = Configuration{
+		Enabled: true,
+		KeyProvider: KeyProviderConfiguration{
+			File: &FileKeyProviderConfiguration{Path: dir},
+		},
+	}.NewCipher()
+	assert.Equal(t, errNoCurrentKeyID, err)
+
+	c, err := Configuration{
+		Enabled: true,
+		KeyProvider: KeyProviderConfiguration{
+			File: &FileKeyProviderConfiguration{Path: dir, CurrentKeyID: "a"},
+		},
+	}.NewCipher()
+	require.NoError(t, err)
+	encrypted, err := c.Encrypt(nil, []byte("data"))
+	require.NoError(t, err)
+	decrypted, err := c.Decrypt(nil, encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, "data", string(decrypted))
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package encryption provides the encryption of commit logs, fileset data and
+// other files holding datapoints at rest with keys from a pluggable key
+// provider. Fileset index files and index segments are not encrypted.
+package encryption
+
+// Key is an encryption key identified by its ID.
+type Key struct {
+	// ID identifies the key so that data encrypted with it can be decrypted
+	// after the current key has been rotated.
+	ID string
+	// Bytes is the key material, 16, 24 or 32 bytes to select AES-128,
+	// AES-192 or AES-256 respectively.
+	Bytes []byte
+}
+
+// KeyProvider provides the keys used to encrypt and decrypt data, it must be
+// safe for concurrent use.
+type KeyProvider interface {
+	// CurrentKey returns the key to encrypt new data with.
+	CurrentKey() (Key, error)
+
+	// Key returns the key with the given ID to decrypt data with.
+	Key(id string) (Key, error)
+}
+
+// Cipher encrypts and decrypts blocks of data, it is safe for concurrent use.
+type Cipher interface {
+	// Encrypt encrypts src with the current key, reusing the capacity of dst
+	// if large enough.
+	Encrypt(dst, src []byte) ([]byte, error)
+
+	// Decrypt decrypts src with the key it was encrypted with, reusing the
+	// capacity of dst if large enough.
+	Decrypt(dst, src []byte) ([]byte, error)
+}
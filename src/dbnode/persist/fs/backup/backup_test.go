@@ -0,0 +1,339 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package backup
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+
+	"github.com/m3db/m3/src/dbnode/digest"
+	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/x/checked"
+	"github.com/m3db/m3/src/x/clock"
+	"github.com/m3db/m3/src/x/ident"
+	"github.com/m3db/m3/src/x/instrument"
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+var (
+	testNamespace = ident.StringID("testns")
+	testBlockSize = 2 * time.Hour
+)
+
+func newTestDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "backup-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return dir
+}
+
+func writeTestFileset(
+	t *testing.T,
+	fsOpts fs.Options,
+	shard uint32,
+	blockStart xtime.UnixNano,
+	volume int,
+	id string,
+) {
+	w, err := fs.NewWriter(fsOpts)
+	require.NoError(t, err)
+	require.NoError(t, w.Open(fs.DataWriterOpenOptions{
+		Identifier: fs.FileSetFileIdentifier{
+			Namespace:   testNamespace,
+			Shard:       shard,
+			BlockStart:  blockStart,
+			VolumeIndex: volume,
+		},
+		BlockSize: testBlockSize,
+	}))
+
+	data := checked.NewBytes([]byte(id), nil)
+	data.IncRef()
+	metadata := persist.NewMetadataFromIDAndTags(ident.StringID(id), ident.Tags{},
+		persist.MetadataOptions{})
+	require.NoError(t, w.Write(metadata, data, digest.Checksum(data.Bytes())))
+	data.DecRef()
+	require.NoError(t, w.Close())
+}
+
+type testSetup struct {
+	opts   Options
+	fsOpts fs.Options
+	scope  tally.TestScope
+	now    time.Time
+}
+
+func newTestSetup(t *testing.T, store Store) *testSetup {
+	s := &testSetup{
+		fsOpts: fs.NewOptions().SetFilePathPrefix(newTestDir(t)),
+		scope:  tally.NewTestScope("", nil),
+		now:    time.Date(2021, time.January, 1, 12, 0, 0, 0, time.UTC),
+	}
+	s.opts = NewOptions().
+		SetStore(store).
+		SetKeyPrefix("node-a").
+		SetFilesystemOptions(s.fsOpts).
+		SetClockOptions(clock.NewOptions().SetNowFn(func() time.Time {
+			return s.now
+		})).
+		SetInstrumentOptions(instrument.NewOptions().SetMetricsScope(s.scope))
+	return s
+}
+
+func (s *testSetup) counter(name string) int64 {
+	c, ok := s.scope.Snapshot().Counters()["backup."+name+"+"]
+	if !ok {
+		return 0
+	}
+	return c.Value()
+}
+
+func TestBackupAndRestore(t *testing.T) {
+	store, err := NewLocalStore(newTestDir(t))
+	require.NoError(t, err)
+
+	var (
+		ctx    = context.Background()
+		src    = newTestSetup(t, store)
+		block1 = xtime.ToUnixNano(src.now.Truncate(testBlockSize).Add(-2 * testBlockSize))
+		block2 = block1.Add(testBlockSize)
+	)
+	writeTestFileset(t, src.fsOpts, 0, block1, 0, "foo")
+	writeTestFileset(t, src.fsOpts, 0, block1, 1, "foo-cold")
+	writeTestFileset(t, src.fsOpts, 0, block2, 0, "bar")
+	writeTestFileset(t, src.fsOpts, 1, block1, 0, "baz")
+
+	manager, err := NewManager(src.opts)
+	require.NoError(t, err)
+	require.NoError(t, manager.Backup(ctx))
+
+	// Only the latest volume of each block is backed up.
+	uploaded := src.counter("uploaded-files")
+	assert.True(t, uploaded > 0)
+	assert.Equal(t, int64(0), src.counter("reused-files"))
+
+	// Backing up again does not upload files that are already backed up.
+	firstBackup := xtime.ToUnixNano(src.now)
+	src.now = src.now.Add(time.Hour)
+	require.NoError(t, manager.Backup(ctx))
+	assert.Equal(t, uploaded, src.counter("uploaded-files"))
+	assert.Equal(t, uploaded, src.counter("reused-files"))
+
+	// Restore shard 0 of the first backup to another node.
+	dst := newTestSetup(t, store)
+	restorer, err := NewRestorer(dst.opts)
+	require.NoError(t, err)
+
+	times, err := restorer.BackupTimes(ctx, testNamespace)
+	require.NoError(t, err)
+	assert.Equal(t, []xtime.UnixNano{firstBackup, xtime.ToUnixNano(src.now)}, times)
+
+	res, err := restorer.Restore(ctx, RestoreOptions{
+		Namespace: testNamespace,
+		Time:      firstBackup.Add(time.Minute),
+		Filter: func(fileset FilesetManifest) bool {
+			return fileset.Shard == 0
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, firstBackup, res.BackupTime)
+	assert.Equal(t, 2, res.Filesets)
+
+	files, err := fs.DataFiles(dst.fsOpts.FilePathPrefix(), testNamespace, 0)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(files))
+	latest, ok := files.LatestVolumeForBlock(block1)
+	require.True(t, ok)
+	assert.Equal(t, 1, latest.ID.VolumeIndex)
+	_, ok = files.LatestVolumeForBlock(block2)
+	require.True(t, ok)
+
+	// The restored files are identical to the backed up files.
+	for _, path := range latest.AbsoluteFilePaths {
+		rel, err := filepath.Rel(dst.fsOpts.FilePathPrefix(), path)
+		require.NoError(t, err)
+		expected, err := ioutil.ReadFile(filepath.Join(src.fsOpts.FilePathPrefix(), rel))
+		require.NoError(t, err)
+		actual, err := ioutil.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, expected, actual)
+	}
+
+	files, err = fs.DataFiles(dst.fsOpts.FilePathPrefix(), testNamespace, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 0, len(files))
+
+	// Restoring again skips filesets that already exist.
+	res, err = restorer.Restore(ctx, RestoreOptions{Namespace: testNamespace})
+	require.NoError(t, err)
+	assert.Equal(t, 1, res.Filesets)
+}
+
+func TestBackupOffloadedFileset(t *testing.T) {
+	store, err := NewLocalStore(newTestDir(t))
+	require.NoError(t, err)
+
+	var (
+		ctx   = context.Background()
+		src   = newTestSetup(t, store)
+		block = xtime.ToUnixNano(src.now.Truncate(testBlockSize).Add(-2 * testBlockSize))
+	)
+	src.fsOpts = src.fsOpts.SetSecondaryTierFilePathPrefix(newTestDir(t))
+	src.opts = src.opts.SetFilesystemOptions(src.fsOpts)
+	writeTestFileset(t, src.fsOpts, 0, block, 0, "foo")
+
+	files, err := fs.DataFiles(src.fsOpts.FilePathPrefix(), testNamespace, 0)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(files))
+	offloaded, err := fs.OffloadDataFileSet(src.fsOpts, files[0])
+	require.NoError(t, err)
+	require.True(t, offloaded)
+
+	manager, err := NewManager(src.opts)
+	require.NoError(t, err)
+	require.NoError(t, manager.Backup(ctx))
+
+	// Offloaded files are restored to the primary tier.
+	dst := newTestSetup(t, store)
+	restorer, err := NewRestorer(dst.opts)
+	require.NoError(t, err)
+	res, err := restorer.Restore(ctx, RestoreOptions{Namespace: testNamespace})
+	require.NoError(t, err)
+	assert.Equal(t, 1, res.Filesets)
+
+	reader, err := fs.NewReader(nil, dst.fsOpts)
+	require.NoError(t, err)
+	require.NoError(t, reader.Open(fs.DataReaderOpenOptions{
+		Identifier: fs.FileSetFileIdentifier{
+			Namespace:  testNamespace,
+			Shard:      0,
+			BlockStart: block,
+		},
+	}))
+	id, _, data, _, err := reader.Read()
+	require.NoError(t, err)
+	assert.Equal(t, "foo", id.String())
+	data.IncRef()
+	assert.Equal(t, []byte("foo"), data.Bytes())
+	data.DecRef()
+	require.NoError(t, reader.Close())
+}
+
+func TestRestoreNoBackup(t *testing.T) {
+	store, err := NewLocalStore(newTestDir(t))
+	require.NoError(t, err)
+
+	s := newTestSetup(t, store)
+	writeTestFileset(t, s.fsOpts, 0, xtime.ToUnixNano(s.now.Truncate(testBlockSize)), 0, "foo")
+
+	manager, err := NewManager(s.opts)
+	require.NoError(t, err)
+	require.NoError(t, manager.Backup(context.Background()))
+
+	restorer, err := NewRestorer(s.opts)
+	require.NoError(t, err)
+
+	_, err = restorer.Restore(context.Background(), RestoreOptions{
+		Namespace: testNamespace,
+		Time:      xtime.ToUnixNano(s.now.Add(-time.Minute)),
+	})
+	assert.True(t, errors.Is(err, ErrNoBackup))
+
+	_, err = restorer.Restore(context.Background(), RestoreOptions{
+		Namespace: ident.StringID("other"),
+	})
+	assert.True(t, errors.Is(err, ErrNoBackup))
+}
+
+func TestRestoreCorruptFile(t *testing.T) {
+	storeDir := newTestDir(t)
+	store, err := NewLocalStore(storeDir)
+	require.NoError(t, err)
+
+	src := newTestSetup(t, store)
+	writeTestFileset(t, src.fsOpts, 0, xtime.ToUnixNano(src.now.Truncate(testBlockSize)), 0, "foo")
+
+	manager, err := NewManager(src.opts)
+	require.NoError(t, err)
+	require.NoError(t, manager.Backup(context.Background()))
+
+	keys, err := store.List(context.Background(), "node-a/"+filesKeyPrefix+"/")
+	require.NoError(t, err)
+	require.True(t, len(keys) > 0)
+	path := filepath.Join(storeDir, filepath.FromSlash(keys[0]))
+	require.NoError(t, ioutil.WriteFile(path, []byte("corrupt"), 0644))
+
+	dst := newTestSetup(t, store)
+	restorer, err := NewRestorer(dst.opts)
+	require.NoError(t, err)
+
+	_, err = restorer.Restore(context.Background(), RestoreOptions{Namespace: testNamespace})
+	require.Error(t, err)
+
+	// No complete fileset is left behind.
+	files, err := fs.DataFiles(dst.fsOpts.FilePathPrefix(), testNamespace, 0)
+	require.NoError(t, err)
+	_, ok := files.LatestVolumeForBlock(xtime.ToUnixNano(src.now.Truncate(testBlockSize)))
+	assert.False(t, ok)
+}
+
+func TestLocalStore(t *testing.T) {
+	store, err := NewLocalStore(newTestDir(t))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	_, err = store.Get(ctx, "a/b")
+	assert.Equal(t, ErrObjectNotFound, err)
+
+	for _, key := range []string{"a/b", "a/c/d", "ab"} {
+		require.NoError(t, store.Put(ctx, key, strings.NewReader(key)))
+	}
+
+	exists, err := store.Exists(ctx, "a/c/d")
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	keys, err := store.List(ctx, "a/")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a/b", "a/c/d"}, keys)
+
+	keys, err = store.List(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a/b", "a/c/d", "ab"}, keys)
+
+	r, err := store.Get(ctx, "a/c/d")
+	require.NoError(t, err)
+	data, err := ioutil.ReadAll(r)
+	require.NoError(t, err)
+	require.NoError(t, r.Close())
+	assert.Equal(t, "a/c/d", string(data))
+}
@@ -0,0 +1,97 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package backup
+
+import (
+	"errors"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/x/instrument"
+)
+
+var errNoStoreConfigured = errors.New("no backup store configured")
+
+// Configuration configures periodic backups of the node.
+type Configuration struct {
+	// Store configures the store backups are stored in.
+	Store StoreConfiguration `yaml:"store"`
+
+	// Interval is the interval at which backups are taken, defaults to an
+	// hour.
+	Interval *time.Duration `yaml:"interval"`
+}
+
+// StoreConfiguration configures the store backups are stored in.
+type StoreConfiguration struct {
+	// KeyPrefix is the prefix of the keys of the objects of backups, this
+	// should be unique for each node sharing a store, e.g. the host ID.
+	KeyPrefix string `yaml:"keyPrefix"`
+
+	// Local configures a store in a local directory.
+	Local *LocalStoreConfiguration `yaml:"local"`
+}
+
+// LocalStoreConfiguration configures a store in a local directory.
+type LocalStoreConfiguration struct {
+	// Path is the path of the directory objects are stored in.
+	Path string `yaml:"path" validate:"nonzero"`
+}
+
+// NewStore returns the configured store.
+func (c StoreConfiguration) NewStore() (Store, error) {
+	if c.Local != nil {
+		return NewLocalStore(c.Local.Path)
+	}
+	return nil, errNoStoreConfigured
+}
+
+// NewOptions returns backup options with the configured store.
+func (c StoreConfiguration) NewOptions(
+	fsOpts fs.Options,
+	instrumentOpts instrument.Options,
+) (Options, error) {
+	store, err := c.NewStore()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewOptions().
+		SetStore(store).
+		SetKeyPrefix(c.KeyPrefix).
+		SetFilesystemOptions(fsOpts).
+		SetInstrumentOptions(instrumentOpts), nil
+}
+
+// NewManager returns a new backup manager.
+func (c Configuration) NewManager(
+	fsOpts fs.Options,
+	instrumentOpts instrument.Options,
+) (Manager, error) {
+	opts, err := c.Store.NewOptions(fsOpts, instrumentOpts)
+	if err != nil {
+		return nil, err
+	}
+	if c.Interval != nil {
+		opts = opts.SetInterval(*c.Interval)
+	}
+	return NewManager(opts)
+}
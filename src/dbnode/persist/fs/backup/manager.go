@@ -0,0 +1,536 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package backup
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/uber-go/tally"
+	"go.uber.org/zap"
+
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/x/ident"
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+var (
+	errManagerAlreadyStarted = errors.New("backup manager already started")
+	errManagerNotStarted     = errors.New("backup manager not started")
+)
+
+type managerMetrics struct {
+	runs          tally.Counter
+	runErrors     tally.Counter
+	runLatency    tally.Timer
+	uploadedFiles tally.Counter
+	uploadedBytes tally.Counter
+	reusedFiles   tally.Counter
+	skipped       tally.Counter
+}
+
+func newManagerMetrics(scope tally.Scope) managerMetrics {
+	return managerMetrics{
+		runs:          scope.Counter("runs"),
+		runErrors:     scope.Counter("run-errors"),
+		runLatency:    scope.Timer("run-latency"),
+		uploadedFiles: scope.Counter("uploaded-files"),
+		uploadedBytes: scope.Counter("uploaded-bytes"),
+		reusedFiles:   scope.Counter("reused-files"),
+		skipped:       scope.Counter("skipped-filesets"),
+	}
+}
+
+type manager struct {
+	sync.Mutex
+
+	opts      Options
+	store     Store
+	keyPrefix string
+	prefix    string
+	fsOpts    fs.Options
+	nowFn     func() time.Time
+	logger    *zap.Logger
+	metrics   managerMetrics
+
+	// backupLock ensures only a single backup runs at a time.
+	backupLock sync.Mutex
+	// uploaded are the files uploaded by previous backups by namespace, used
+	// to avoid reading files that were already backed up.
+	uploaded map[string]map[string]FileManifest
+
+	started bool
+	closeCh chan struct{}
+	doneCh  chan struct{}
+}
+
+// NewManager returns a new backup manager.
+func NewManager(opts Options) (Manager, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	iOpts := opts.InstrumentOptions()
+	return &manager{
+		opts:      opts,
+		store:     opts.Store(),
+		keyPrefix: opts.KeyPrefix(),
+		prefix:    opts.FilesystemOptions().FilePathPrefix(),
+		fsOpts:    opts.FilesystemOptions(),
+		nowFn:     opts.ClockOptions().NowFn(),
+		logger:    iOpts.Logger(),
+		metrics:   newManagerMetrics(iOpts.MetricsScope().SubScope("backup")),
+		uploaded:  make(map[string]map[string]FileManifest),
+	}, nil
+}
+
+func (m *manager) Start() error {
+	m.Lock()
+	defer m.Unlock()
+
+	if m.started {
+		return errManagerAlreadyStarted
+	}
+
+	m.started = true
+	m.closeCh = make(chan struct{})
+	m.doneCh = make(chan struct{})
+	go m.backupLoop(m.closeCh, m.doneCh)
+	return nil
+}
+
+func (m *manager) Close() error {
+	m.Lock()
+	if !m.started {
+		m.Unlock()
+		return errManagerNotStarted
+	}
+	m.started = false
+	close(m.closeCh)
+	doneCh := m.doneCh
+	m.Unlock()
+
+	<-doneCh
+	return nil
+}
+
+func (m *manager) backupLoop(closeCh, doneCh chan struct{}) {
+	defer close(doneCh)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-closeCh
+		cancel()
+	}()
+
+	ticker := time.NewTicker(m.opts.Interval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-closeCh:
+			return
+		case <-ticker.C:
+		}
+
+		if err := m.Backup(ctx); err != nil {
+			m.logger.Error("error backing up filesets", zap.Error(err))
+		}
+	}
+}
+
+func (m *manager) Backup(ctx context.Context) error {
+	m.backupLock.Lock()
+	defer m.backupLock.Unlock()
+
+	m.metrics.runs.Inc(1)
+	start := m.nowFn()
+	err := m.backup(ctx, xtime.ToUnixNano(start))
+	m.metrics.runLatency.Record(m.nowFn().Sub(start))
+	if err != nil {
+		m.metrics.runErrors.Inc(1)
+	}
+	return err
+}
+
+func (m *manager) backup(ctx context.Context, backupTime xtime.UnixNano) error {
+	namespaces, err := m.namespacesOnDisk()
+	if err != nil {
+		return err
+	}
+
+	snapshotMetadata, err := m.latestSnapshotMetadata()
+	if err != nil {
+		return err
+	}
+
+	for _, namespace := range namespaces {
+		nsID := ident.StringID(namespace)
+		uploaded, err := m.uploadedFiles(ctx, nsID)
+		if err != nil {
+			return err
+		}
+
+		filesets, err := m.filesets(nsID)
+		if err != nil {
+			return err
+		}
+
+		manifest := Manifest{
+			Namespace:  namespace,
+			BackupTime: backupTime,
+			Filesets:   make([]FilesetManifest, 0, len(filesets)),
+		}
+		for _, fileset := range filesets {
+			files, err := m.uploadFiles(ctx, fileset.paths, uploaded)
+			if os.IsNotExist(err) {
+				// The fileset was removed during the backup, e.g. by cleanup.
+				m.metrics.skipped.Inc(1)
+				m.logger.Warn("skipping backup of removed fileset",
+					zap.String("namespace", namespace),
+					zap.String("type", string(fileset.manifest.Type)),
+					zap.Uint32("shard", fileset.manifest.Shard),
+					zap.Time("blockStart", fileset.manifest.BlockStart.ToTime()))
+				continue
+			}
+			if err != nil {
+				return err
+			}
+
+			fileset.manifest.Files = files
+			manifest.Filesets = append(manifest.Filesets, fileset.manifest)
+		}
+
+		files, err := m.uploadFiles(ctx, snapshotMetadata, uploaded)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		if err == nil {
+			manifest.SnapshotMetadata = files
+		}
+
+		key := manifestKey(m.keyPrefix, nsID, backupTime)
+		if err := writeManifest(ctx, m.store, key, manifest); err != nil {
+			return err
+		}
+
+		m.uploaded[namespace] = uploaded
+		m.logger.Info("backed up namespace filesets",
+			zap.String("namespace", namespace),
+			zap.Time("backupTime", backupTime.ToTime()),
+			zap.Int("filesets", len(manifest.Filesets)))
+	}
+
+	return nil
+}
+
+// uploadedFiles returns the files already backed up for a namespace, loading
+// them from the latest manifest if this is the first backup since starting.
+func (m *manager) uploadedFiles(
+	ctx context.Context,
+	namespace ident.ID,
+) (map[string]FileManifest, error) {
+	if uploaded, ok := m.uploaded[namespace.String()]; ok {
+		return uploaded, nil
+	}
+
+	uploaded := make(map[string]FileManifest)
+	keys, err := m.store.List(ctx, manifestsKey(m.keyPrefix, namespace))
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return uploaded, nil
+	}
+
+	manifest, err := readManifest(ctx, m.store, keys[len(keys)-1])
+	if err != nil {
+		return nil, err
+	}
+	for _, fileset := range manifest.Filesets {
+		for _, file := range fileset.Files {
+			uploaded[file.Path] = file
+		}
+	}
+	for _, file := range manifest.SnapshotMetadata {
+		uploaded[file.Path] = file
+	}
+	return uploaded, nil
+}
+
+// uploadFiles uploads the files that are not already backed up.
+func (m *manager) uploadFiles(
+	ctx context.Context,
+	paths []string,
+	uploaded map[string]FileManifest,
+) ([]FileManifest, error) {
+	files := make([]FileManifest, 0, len(paths))
+	for _, absPath := range paths {
+		rel, err := m.relativePath(absPath)
+		if err != nil {
+			return nil, err
+		}
+
+		info, err := os.Stat(absPath)
+		if err != nil {
+			return nil, err
+		}
+
+		// NB: sealed files are immutable, so a file with the same path and
+		// size as a backed up file does not need to be read again.
+		if file, ok := uploaded[rel]; ok && file.Size == info.Size() {
+			m.metrics.reusedFiles.Inc(1)
+			files = append(files, file)
+			continue
+		}
+
+		file, err := m.uploadFile(ctx, absPath, rel)
+		if err != nil {
+			return nil, err
+		}
+
+		uploaded[rel] = file
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// relativePath returns the path of a file relative to the file path prefix
+// of the storage tier it is stored in, so that files offloaded to the
+// secondary tier are restored to the same location on the primary tier.
+func (m *manager) relativePath(absPath string) (string, error) {
+	prefix := m.prefix
+	if secondary := m.fsOpts.SecondaryTierFilePathPrefix(); secondary != "" &&
+		strings.HasPrefix(absPath, filepath.Clean(secondary)+string(filepath.Separator)) {
+		prefix = secondary
+	}
+	rel, err := filepath.Rel(prefix, absPath)
+	if err != nil {
+		return "", err
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+func (m *manager) uploadFile(ctx context.Context, absPath, rel string) (FileManifest, error) {
+	fd, err := os.Open(absPath)
+	if err != nil {
+		return FileManifest{}, err
+	}
+	defer fd.Close()
+
+	sum, size, err := checksum(fd)
+	if err != nil {
+		return FileManifest{}, err
+	}
+
+	file := FileManifest{Path: rel, Size: size, Checksum: sum}
+	key := fileKey(m.keyPrefix, file)
+	exists, err := m.store.Exists(ctx, key)
+	if err != nil || exists {
+		return file, err
+	}
+
+	if _, err := fd.Seek(0, 0); err != nil {
+		return FileManifest{}, err
+	}
+	if err := m.store.Put(ctx, key, fd); err != nil {
+		return FileManifest{}, err
+	}
+
+	m.metrics.uploadedFiles.Inc(1)
+	m.metrics.uploadedBytes.Inc(size)
+	return file, nil
+}
+
+type filesetToBackup struct {
+	manifest FilesetManifest
+	paths    []string
+}
+
+// filesets returns the sealed filesets of a namespace, i.e. the filesets with
+// a complete checkpoint file. Only the latest volume of a data or snapshot
+// fileset of a block is backed up since it supersedes previous volumes.
+func (m *manager) filesets(namespace ident.ID) ([]filesetToBackup, error) {
+	var result []filesetToBackup
+
+	dataShards, err := shardsOnDisk(fs.NamespaceDataDirPath(m.prefix, namespace))
+	if err != nil {
+		return nil, err
+	}
+	for _, shard := range dataShards {
+		files, err := fs.DataFiles(m.prefix, namespace, shard)
+		if err != nil {
+			return nil, err
+		}
+		volumes := latestVolumes(DataFileset, shard, files)
+		for i := range volumes {
+			// Include the files of the fileset offloaded to the secondary tier.
+			offloaded, err := fs.SecondaryTierFilePaths(m.fsOpts, volumes[i].paths)
+			if err != nil {
+				return nil, err
+			}
+			if len(offloaded) > 0 {
+				volumes[i].paths = sortCheckpointsLast(append(offloaded, volumes[i].paths...))
+			}
+		}
+		result = append(result, volumes...)
+	}
+
+	snapshotShards, err := shardsOnDisk(fs.NamespaceSnapshotsDirPath(m.prefix, namespace))
+	if err != nil {
+		return nil, err
+	}
+	for _, shard := range snapshotShards {
+		files, err := fs.SnapshotFiles(m.prefix, namespace, shard)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, latestVolumes(SnapshotFileset, shard, files)...)
+	}
+
+	// NB: all complete volumes of an index block are backed up since every
+	// volume of an index block is loaded.
+	indexFiles, err := fs.IndexFiles(m.prefix, namespace)
+	if err != nil {
+		return nil, err
+	}
+	for _, fileset := range indexFiles {
+		if !fileset.HasCompleteCheckpointFile() {
+			continue
+		}
+		result = append(result, newFilesetToBackup(IndexFileset, 0, fileset))
+	}
+
+	return result, nil
+}
+
+func latestVolumes(t FilesetType, shard uint32, files fs.FileSetFilesSlice) []filesetToBackup {
+	var (
+		result     []filesetToBackup
+		blockStart = make(map[xtime.UnixNano]struct{})
+	)
+	for _, fileset := range files {
+		if _, ok := blockStart[fileset.ID.BlockStart]; ok {
+			continue
+		}
+		blockStart[fileset.ID.BlockStart] = struct{}{}
+
+		latest, ok := files.LatestVolumeForBlock(fileset.ID.BlockStart)
+		if !ok {
+			continue
+		}
+		result = append(result, newFilesetToBackup(t, shard, latest))
+	}
+	return result
+}
+
+func newFilesetToBackup(t FilesetType, shard uint32, fileset fs.FileSetFile) filesetToBackup {
+	return filesetToBackup{
+		manifest: FilesetManifest{
+			Type:        t,
+			Shard:       shard,
+			BlockStart:  fileset.ID.BlockStart,
+			VolumeIndex: fileset.ID.VolumeIndex,
+		},
+		paths: sortCheckpointsLast(fileset.AbsoluteFilePaths),
+	}
+}
+
+// latestSnapshotMetadata returns the files of the latest snapshot metadata.
+func (m *manager) latestSnapshotMetadata() ([]string, error) {
+	metadatas, _, err := fs.SortedSnapshotMetadataFiles(m.fsOpts)
+	if err != nil {
+		return nil, err
+	}
+	if len(metadatas) == 0 {
+		return nil, nil
+	}
+	return metadatas[len(metadatas)-1].AbsoluteFilePaths(), nil
+}
+
+// namespacesOnDisk returns the namespaces with filesets on disk.
+func (m *manager) namespacesOnDisk() ([]string, error) {
+	namespaces := make(map[string]struct{})
+	for _, dir := range []string{
+		fs.DataDirPath(m.prefix),
+		fs.IndexDataDirPath(m.prefix),
+		fs.SnapshotsDirPath(m.prefix),
+	} {
+		names, err := subdirectories(dir)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range names {
+			namespaces[name] = struct{}{}
+		}
+	}
+
+	result := make([]string, 0, len(namespaces))
+	for namespace := range namespaces {
+		result = append(result, namespace)
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+func shardsOnDisk(dir string) ([]uint32, error) {
+	names, err := subdirectories(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	shards := make([]uint32, 0, len(names))
+	for _, name := range names {
+		shard, err := strconv.ParseUint(name, 10, 32)
+		if err != nil {
+			continue
+		}
+		shards = append(shards, uint32(shard))
+	}
+	return shards, nil
+}
+
+func subdirectories(dir string) ([]string, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	return names, nil
+}
@@ -0,0 +1,154 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package backup
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/adler32"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/m3db/m3/src/x/ident"
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+const (
+	manifestsKeyPrefix = "manifests"
+	filesKeyPrefix     = "files"
+	manifestKeySuffix  = ".json"
+)
+
+// FilesetType is the type of a fileset in a backup.
+type FilesetType string
+
+const (
+	// DataFileset is a flushed data fileset of a shard.
+	DataFileset FilesetType = "data"
+	// IndexFileset is a flushed index fileset of a namespace.
+	IndexFileset FilesetType = "index"
+	// SnapshotFileset is a snapshot data fileset of a shard.
+	SnapshotFileset FilesetType = "snapshot"
+)
+
+// Manifest describes a backup of the filesets of a namespace.
+type Manifest struct {
+	// Namespace is the backed up namespace.
+	Namespace string `json:"namespace"`
+	// BackupTime is the time the backup was taken.
+	BackupTime xtime.UnixNano `json:"backupTime"`
+	// Filesets are the backed up filesets of the namespace.
+	Filesets []FilesetManifest `json:"filesets"`
+	// SnapshotMetadata are the files of the latest snapshot metadata of the
+	// node at the time of the backup.
+	SnapshotMetadata []FileManifest `json:"snapshotMetadata,omitempty"`
+}
+
+// FilesetManifest describes a backed up fileset.
+type FilesetManifest struct {
+	// Type is the type of the fileset.
+	Type FilesetType `json:"type"`
+	// Shard is the shard of a data or snapshot fileset.
+	Shard uint32 `json:"shard"`
+	// BlockStart is the block start of the fileset.
+	BlockStart xtime.UnixNano `json:"blockStart"`
+	// VolumeIndex is the volume index of the fileset.
+	VolumeIndex int `json:"volumeIndex"`
+	// Files are the files of the fileset.
+	Files []FileManifest `json:"files"`
+}
+
+// FileManifest describes a backed up file.
+type FileManifest struct {
+	// Path is the path of the file relative to the file path prefix of
+	// the node.
+	Path string `json:"path"`
+	// Size is the size of the file in bytes.
+	Size int64 `json:"size"`
+	// Checksum is the adler32 checksum of the contents of the file.
+	Checksum uint32 `json:"checksum"`
+}
+
+func manifestsKey(keyPrefix string, namespace ident.ID) string {
+	return path.Join(keyPrefix, manifestsKeyPrefix, namespace.String()) + "/"
+}
+
+func manifestKey(keyPrefix string, namespace ident.ID, t xtime.UnixNano) string {
+	// NB: zero pad the time so that keys sort in the order of backup times.
+	return manifestsKey(keyPrefix, namespace) +
+		fmt.Sprintf("%020d", int64(t)) + manifestKeySuffix
+}
+
+func backupTimeFromManifestKey(key string) (xtime.UnixNano, error) {
+	name := path.Base(key)
+	if !strings.HasSuffix(name, manifestKeySuffix) {
+		return 0, fmt.Errorf("unexpected manifest key: %s", key)
+	}
+	nanos, err := strconv.ParseInt(strings.TrimSuffix(name, manifestKeySuffix), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("unexpected manifest key: %s: %w", key, err)
+	}
+	return xtime.UnixNano(nanos), nil
+}
+
+// fileKey returns the key of the object of a backed up file, the checksum is
+// part of the key since a file may be rewritten with different contents, e.g.
+// when a node is rebuilt.
+func fileKey(keyPrefix string, file FileManifest) string {
+	return path.Join(keyPrefix, filesKeyPrefix, file.Path) +
+		fmt.Sprintf(".%08x", file.Checksum)
+}
+
+func readManifest(ctx context.Context, store Store, key string) (Manifest, error) {
+	r, err := store.Get(ctx, key)
+	if err != nil {
+		return Manifest{}, err
+	}
+	defer r.Close()
+
+	var manifest Manifest
+	if err := json.NewDecoder(r).Decode(&manifest); err != nil {
+		return Manifest{}, fmt.Errorf("unable to decode manifest %s: %w", key, err)
+	}
+	return manifest, nil
+}
+
+func writeManifest(ctx context.Context, store Store, key string, manifest Manifest) error {
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+	return store.Put(ctx, key, bytes.NewReader(data))
+}
+
+// checksum returns the adler32 checksum and size of the contents of a reader.
+func checksum(r io.Reader) (uint32, int64, error) {
+	h := adler32.New()
+	n, err := io.Copy(h, r)
+	if err != nil {
+		return 0, 0, err
+	}
+	return h.Sum32(), n, nil
+}
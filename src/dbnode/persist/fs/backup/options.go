@@ -0,0 +1,132 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package backup
+
+import (
+	"errors"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/x/clock"
+	"github.com/m3db/m3/src/x/instrument"
+)
+
+const (
+	defaultInterval = time.Hour
+)
+
+var (
+	errNoStore             = errors.New("backup store not set")
+	errNoFilesystemOptions = errors.New("filesystem options not set")
+	errInvalidInterval     = errors.New("backup interval must be positive")
+)
+
+type options struct {
+	store     Store
+	keyPrefix string
+	interval  time.Duration
+	fsOpts    fs.Options
+	clockOpts clock.Options
+	iOpts     instrument.Options
+}
+
+// NewOptions returns new backup options.
+func NewOptions() Options {
+	return &options{
+		interval:  defaultInterval,
+		fsOpts:    fs.NewOptions(),
+		clockOpts: clock.NewOptions(),
+		iOpts:     instrument.NewOptions(),
+	}
+}
+
+func (o *options) Validate() error {
+	if o.store == nil {
+		return errNoStore
+	}
+	if o.fsOpts == nil {
+		return errNoFilesystemOptions
+	}
+	if o.interval <= 0 {
+		return errInvalidInterval
+	}
+	return nil
+}
+
+func (o *options) SetStore(value Store) Options {
+	opts := *o
+	opts.store = value
+	return &opts
+}
+
+func (o *options) Store() Store {
+	return o.store
+}
+
+func (o *options) SetKeyPrefix(value string) Options {
+	opts := *o
+	opts.keyPrefix = value
+	return &opts
+}
+
+func (o *options) KeyPrefix() string {
+	return o.keyPrefix
+}
+
+func (o *options) SetInterval(value time.Duration) Options {
+	opts := *o
+	opts.interval = value
+	return &opts
+}
+
+func (o *options) Interval() time.Duration {
+	return o.interval
+}
+
+func (o *options) SetFilesystemOptions(value fs.Options) Options {
+	opts := *o
+	opts.fsOpts = value
+	return &opts
+}
+
+func (o *options) FilesystemOptions() fs.Options {
+	return o.fsOpts
+}
+
+func (o *options) SetClockOptions(value clock.Options) Options {
+	opts := *o
+	opts.clockOpts = value
+	return &opts
+}
+
+func (o *options) ClockOptions() clock.Options {
+	return o.clockOpts
+}
+
+func (o *options) SetInstrumentOptions(value instrument.Options) Options {
+	opts := *o
+	opts.iOpts = value
+	return &opts
+}
+
+func (o *options) InstrumentOptions() instrument.Options {
+	return o.iOpts
+}
@@ -0,0 +1,275 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package backup
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/x/ident"
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+const checkpointFileSuffix = "checkpoint.db"
+
+type restorer struct {
+	store     Store
+	keyPrefix string
+	prefix    string
+	fsOpts    fs.Options
+	logger    *zap.Logger
+}
+
+// NewRestorer returns a new restorer.
+func NewRestorer(opts Options) (Restorer, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &restorer{
+		store:     opts.Store(),
+		keyPrefix: opts.KeyPrefix(),
+		prefix:    opts.FilesystemOptions().FilePathPrefix(),
+		fsOpts:    opts.FilesystemOptions(),
+		logger:    opts.InstrumentOptions().Logger(),
+	}, nil
+}
+
+func (r *restorer) BackupTimes(
+	ctx context.Context,
+	namespace ident.ID,
+) ([]xtime.UnixNano, error) {
+	keys, err := r.store.List(ctx, manifestsKey(r.keyPrefix, namespace))
+	if err != nil {
+		return nil, err
+	}
+
+	times := make([]xtime.UnixNano, 0, len(keys))
+	for _, key := range keys {
+		t, err := backupTimeFromManifestKey(key)
+		if err != nil {
+			return nil, err
+		}
+		times = append(times, t)
+	}
+
+	sort.Slice(times, func(i, j int) bool {
+		return times[i] < times[j]
+	})
+	return times, nil
+}
+
+func (r *restorer) Restore(
+	ctx context.Context,
+	opts RestoreOptions,
+) (RestoreResult, error) {
+	times, err := r.BackupTimes(ctx, opts.Namespace)
+	if err != nil {
+		return RestoreResult{}, err
+	}
+
+	var (
+		backupTime xtime.UnixNano
+		found      bool
+	)
+	for _, t := range times {
+		if !opts.Time.IsZero() && t.After(opts.Time) {
+			break
+		}
+		backupTime, found = t, true
+	}
+	if !found {
+		return RestoreResult{}, fmt.Errorf("%w: namespace %s at or before %v",
+			ErrNoBackup, opts.Namespace, opts.Time.ToTime())
+	}
+
+	key := manifestKey(r.keyPrefix, opts.Namespace, backupTime)
+	manifest, err := readManifest(ctx, r.store, key)
+	if err != nil {
+		return RestoreResult{}, err
+	}
+
+	result := RestoreResult{BackupTime: backupTime}
+	for _, fileset := range manifest.Filesets {
+		if opts.Filter != nil && !opts.Filter(fileset) {
+			continue
+		}
+
+		exists, err := r.filesetExists(opts.Namespace, fileset)
+		if err != nil {
+			return RestoreResult{}, err
+		}
+		if exists {
+			continue
+		}
+
+		bytes, err := r.restoreFiles(ctx, fileset.Files)
+		if err != nil {
+			return RestoreResult{}, err
+		}
+
+		result.Filesets++
+		result.Files += len(fileset.Files)
+		result.Bytes += bytes
+	}
+
+	if len(manifest.SnapshotMetadata) > 0 {
+		exists, err := fs.FileExists(r.localPath(manifest.SnapshotMetadata[0]))
+		if err != nil {
+			return RestoreResult{}, err
+		}
+		if !exists {
+			bytes, err := r.restoreFiles(ctx, manifest.SnapshotMetadata)
+			if err != nil {
+				return RestoreResult{}, err
+			}
+			result.Files += len(manifest.SnapshotMetadata)
+			result.Bytes += bytes
+		}
+	}
+
+	r.logger.Info("restored namespace filesets from backup",
+		zap.Stringer("namespace", opts.Namespace),
+		zap.Time("backupTime", backupTime.ToTime()),
+		zap.Int("filesets", result.Filesets),
+		zap.Int64("bytes", result.Bytes))
+
+	return result, nil
+}
+
+// filesetExists returns whether the fileset, or a later volume of it for data
+// and snapshot filesets, is already on disk.
+func (r *restorer) filesetExists(namespace ident.ID, fileset FilesetManifest) (bool, error) {
+	var (
+		files fs.FileSetFilesSlice
+		err   error
+	)
+	switch fileset.Type {
+	case DataFileset:
+		files, err = fs.DataFiles(r.prefix, namespace, fileset.Shard)
+	case SnapshotFileset:
+		files, err = fs.SnapshotFiles(r.prefix, namespace, fileset.Shard)
+	case IndexFileset:
+		files, err = fs.IndexFileSetsAt(r.prefix, namespace, fileset.BlockStart)
+		if err != nil {
+			return false, err
+		}
+		return files.VolumeExistsForBlock(fileset.BlockStart, fileset.VolumeIndex), nil
+	default:
+		return false, fmt.Errorf("unknown fileset type: %s", fileset.Type)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	latest, ok := files.LatestVolumeForBlock(fileset.BlockStart)
+	return ok && latest.ID.VolumeIndex >= fileset.VolumeIndex, nil
+}
+
+// restoreFiles downloads files, writing checkpoint files last so that a
+// fileset is only considered complete once all of its files are restored.
+func (r *restorer) restoreFiles(ctx context.Context, files []FileManifest) (int64, error) {
+	var total int64
+	for _, file := range sortFilesCheckpointsLast(files) {
+		if err := r.restoreFile(ctx, file); err != nil {
+			return 0, err
+		}
+		total += file.Size
+	}
+	return total, nil
+}
+
+func (r *restorer) restoreFile(ctx context.Context, file FileManifest) error {
+	key := fileKey(r.keyPrefix, file)
+	src, err := r.store.Get(ctx, key)
+	if err != nil {
+		return fmt.Errorf("unable to get backed up file %s: %w", key, err)
+	}
+	defer src.Close()
+
+	path := r.localPath(file)
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, r.fsOpts.NewDirectoryMode()); err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(dir, "."+filepath.Base(path)+".restore")
+	if err != nil {
+		return err
+	}
+
+	sum, size, err := checksum(io.TeeReader(src, tmp))
+	if err == nil && (sum != file.Checksum || size != file.Size) {
+		err = fmt.Errorf(
+			"backed up file %s is corrupt: expected size %d checksum %d, actual size %d checksum %d",
+			key, file.Size, file.Checksum, size, sum)
+	}
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmp.Name(), r.fsOpts.NewFileMode())
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
+
+func (r *restorer) localPath(file FileManifest) string {
+	return filepath.Join(r.prefix, filepath.FromSlash(file.Path))
+}
+
+func isCheckpointFile(path string) bool {
+	return strings.HasSuffix(path, checkpointFileSuffix)
+}
+
+func sortCheckpointsLast(paths []string) []string {
+	sorted := append([]string(nil), paths...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return !isCheckpointFile(sorted[i]) && isCheckpointFile(sorted[j])
+	})
+	return sorted
+}
+
+func sortFilesCheckpointsLast(files []FileManifest) []FileManifest {
+	sorted := append([]FileManifest(nil), files...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return !isCheckpointFile(sorted[i].Path) && isCheckpointFile(sorted[j].Path)
+	})
+	return sorted
+}
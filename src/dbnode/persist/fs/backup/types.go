@@ -0,0 +1,152 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package backup provides backups of the sealed filesets of a node to an
+// object store and restores of the filesets from those backups.
+package backup
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/x/clock"
+	"github.com/m3db/m3/src/x/ident"
+	"github.com/m3db/m3/src/x/instrument"
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+var (
+	// ErrObjectNotFound is returned by a store when an object does not exist.
+	ErrObjectNotFound = errors.New("object not found")
+
+	// ErrNoBackup is returned by a restore when there is no backup of the
+	// namespace at or before the restore time.
+	ErrNoBackup = errors.New("no backup found")
+)
+
+// Store is an object store that backups are stored in.
+type Store interface {
+	// Put stores an object with the contents of the reader.
+	Put(ctx context.Context, key string, r io.Reader) error
+
+	// Get returns a reader of the contents of an object, returning
+	// ErrObjectNotFound if the object does not exist.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+
+	// Exists returns whether an object exists.
+	Exists(ctx context.Context, key string) (bool, error)
+
+	// List returns the keys of all objects with the key prefix.
+	List(ctx context.Context, prefix string) ([]string, error)
+}
+
+// Manager backs up the sealed filesets of the node.
+type Manager interface {
+	// Backup backs up the sealed filesets of every namespace on disk,
+	// recording a manifest of the backup for each namespace.
+	Backup(ctx context.Context) error
+
+	// Start starts backing up at the configured interval.
+	Start() error
+
+	// Close stops backing up.
+	Close() error
+}
+
+// Restorer restores filesets from backups.
+type Restorer interface {
+	// BackupTimes returns the times of the backups of a namespace in
+	// ascending order.
+	BackupTimes(ctx context.Context, namespace ident.ID) ([]xtime.UnixNano, error)
+
+	// Restore restores the filesets of a namespace that do not exist on
+	// disk from a backup.
+	Restore(ctx context.Context, opts RestoreOptions) (RestoreResult, error)
+}
+
+// RestoreOptions are the options for a restore.
+type RestoreOptions struct {
+	// Namespace is the namespace to restore.
+	Namespace ident.ID
+	// Time selects the latest backup taken at or before it, if zero the
+	// latest backup is restored.
+	Time xtime.UnixNano
+	// Filter returns whether a fileset should be restored, if nil all
+	// filesets of the backup are restored.
+	Filter func(fileset FilesetManifest) bool
+}
+
+// RestoreResult is the result of a restore.
+type RestoreResult struct {
+	// BackupTime is the time of the backup that was restored.
+	BackupTime xtime.UnixNano
+	// Filesets is the number of filesets restored.
+	Filesets int
+	// Files is the number of files restored.
+	Files int
+	// Bytes is the number of bytes restored.
+	Bytes int64
+}
+
+// Options are the options for backups and restores.
+type Options interface {
+	// Validate validates the options.
+	Validate() error
+
+	// SetStore sets the store backups are stored in.
+	SetStore(value Store) Options
+
+	// Store returns the store backups are stored in.
+	Store() Store
+
+	// SetKeyPrefix sets the prefix of the keys of the objects of backups,
+	// allowing multiple nodes to share a store.
+	SetKeyPrefix(value string) Options
+
+	// KeyPrefix returns the prefix of the keys of the objects of backups.
+	KeyPrefix() string
+
+	// SetInterval sets the interval at which backups are taken.
+	SetInterval(value time.Duration) Options
+
+	// Interval returns the interval at which backups are taken.
+	Interval() time.Duration
+
+	// SetFilesystemOptions sets the filesystem options.
+	SetFilesystemOptions(value fs.Options) Options
+
+	// FilesystemOptions returns the filesystem options.
+	FilesystemOptions() fs.Options
+
+	// SetClockOptions sets the clock options.
+	SetClockOptions(value clock.Options) Options
+
+	// ClockOptions returns the clock options.
+	ClockOptions() clock.Options
+
+	// SetInstrumentOptions sets the instrument options.
+	SetInstrumentOptions(value instrument.Options) Options
+
+	// InstrumentOptions returns the instrument options.
+	InstrumentOptions() instrument.Options
+}
@@ -70,6 +70,10 @@ func (c *cloner) Clone(src FileSetID, dest FileSetID, destBlocksize time.Duratio
 	if err != nil {
 		return fmt.Errorf("unable to create fileset writer: %v", err)
 	}
+	dataCompression := reader.Status().Compression
+	if c := c.opts.Compression(); c != nil {
+		dataCompression = *c
+	}
 	writerOpts := fs.DataWriterOpenOptions{
 		BlockSize: destBlocksize,
 		Identifier: fs.FileSetFileIdentifier{
@@ -77,6 +81,7 @@ func (c *cloner) Clone(src FileSetID, dest FileSetID, destBlocksize time.Duratio
 			Shard:      dest.Shard,
 			BlockStart: dest.Blockstart,
 		},
+		Compression: dataCompression,
 	}
 	if err := writer.Open(writerOpts); err != nil {
 		return fmt.Errorf("unable to open fileset writer: %v", err)
@@ -30,6 +30,7 @@ import (
 	"time"
 
 	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/compression"
 	"github.com/m3db/m3/src/dbnode/persist/fs"
 	"github.com/m3db/m3/src/x/checked"
 	"github.com/m3db/m3/src/x/ident"
@@ -47,10 +48,18 @@ var (
 )
 
 func TestCloner(t *testing.T) {
+	testCloner(t, NewOptions(), compression.None)
+}
+
+func TestClonerWithCompression(t *testing.T) {
+	zstd := compression.Zstd
+	testCloner(t, NewOptions().SetCompression(&zstd), compression.Zstd)
+}
+
+func testCloner(t *testing.T, opts Options, expectedCompression compression.Type) {
 	dir, err := ioutil.TempDir("", "clone")
 	require.NoError(t, err)
 	defer os.RemoveAll(dir)
-	opts := NewOptions()
 
 	// generate some fake source data
 	srcBlockSize := time.Hour
@@ -108,6 +117,7 @@ func TestCloner(t *testing.T) {
 		},
 	}
 	require.NoError(t, r2.Open(r2OpenOpts))
+	require.Equal(t, expectedCompression, r2.Status().Compression)
 	for {
 		t1, a1, b1, c1, e1 := r1.Read()
 		t2, a2, b2, c2, e2 := r2.Read()
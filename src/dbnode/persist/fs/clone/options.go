@@ -23,6 +23,7 @@ package clone
 import (
 	"os"
 
+	"github.com/m3db/m3/src/dbnode/persist/compression"
 	"github.com/m3db/m3/src/dbnode/persist/fs/msgpack"
 	"github.com/m3db/m3/src/x/pool"
 )
@@ -39,6 +40,7 @@ type opts struct {
 	bufferSize int
 	fileMode   os.FileMode
 	dirMode    os.FileMode
+	compressed *compression.Type
 }
 
 // NewOptions returns the new options
@@ -96,3 +98,12 @@ func (o *opts) SetDirMode(d os.FileMode) Options {
 func (o *opts) DirMode() os.FileMode {
 	return o.dirMode
 }
+
+func (o *opts) SetCompression(value *compression.Type) Options {
+	o.compressed = value
+	return o
+}
+
+func (o *opts) Compression() *compression.Type {
+	return o.compressed
+}
@@ -24,6 +24,7 @@ import (
 	"os"
 	"time"
 
+	"github.com/m3db/m3/src/dbnode/persist/compression"
 	"github.com/m3db/m3/src/dbnode/persist/fs/msgpack"
 	"github.com/m3db/m3/src/x/pool"
 	xtime "github.com/m3db/m3/src/x/time"
@@ -75,4 +76,12 @@ type Options interface {
 
 	// DirMode returns the file mode used for dir creation
 	DirMode() os.FileMode
+
+	// SetCompression sets the compression of the cloned data files, nil
+	// preserves the compression of the source fileset
+	SetCompression(value *compression.Type) Options
+
+	// Compression returns the compression of the cloned data files, nil
+	// preserves the compression of the source fileset
+	Compression() *compression.Type
 }
@@ -22,10 +22,13 @@ package commitlog
 
 import (
 	"bufio"
+	"fmt"
 	"io"
 	"os"
 
 	"github.com/m3db/m3/src/dbnode/digest"
+	"github.com/m3db/m3/src/dbnode/persist/compression"
+	"github.com/m3db/m3/src/dbnode/persist/encryption"
 )
 
 const (
@@ -43,13 +46,22 @@ type chunkReader struct {
 	chunkData          []byte
 	chunkDataRemaining int
 	charBuff           []byte
+
+	// The data of the current chunk, which is the chunk data itself unless
+	// the chunk is compressed or encrypted.
+	data            []byte
+	cipher          encryption.Cipher
+	codecs          [chunkFlagCompressionMask + 1]compression.Codec
+	compressionBuff []byte
+	cipherBuff      []byte
 }
 
-func newChunkReader(bufferLen int) *chunkReader {
+func newChunkReader(bufferLen int, cipher encryption.Cipher) *chunkReader {
 	return &chunkReader{
 		buffer:    bufio.NewReaderSize(nil, bufferLen),
 		chunkData: make([]byte, bufferLen),
 		charBuff:  make([]byte, 1),
+		cipher:    cipher,
 	}
 }
 
@@ -65,7 +77,8 @@ func (r *chunkReader) readHeader() error {
 		return err
 	}
 
-	size := endianness.Uint32(header[sizeStart:sizeEnd])
+	sizeAndFlags := endianness.Uint32(header[sizeStart:sizeEnd])
+	size := sizeAndFlags & chunkSizeMask
 	checksumSize := digest.
 		Buffer(header[checksumSizeStart:checksumSizeEnd]).
 		ReadDigest()
@@ -111,12 +124,52 @@ func (r *chunkReader) readHeader() error {
 		return errCommitLogReaderChunkSizeChecksumMismatch
 	}
 
+	data, err := r.decode(r.chunkData, sizeAndFlags)
+	if err != nil {
+		return err
+	}
+
 	// Set remaining data to be consumed
-	r.chunkDataRemaining = int(size)
+	r.data = data
+	r.chunkDataRemaining = len(data)
 
 	return nil
 }
 
+// decode decrypts and decompresses the chunk data as described by the flags
+// of the chunk header.
+func (r *chunkReader) decode(data []byte, flags uint32) ([]byte, error) {
+	var err error
+	if flags&chunkFlagEncrypted != 0 {
+		if r.cipher == nil {
+			return nil, errCommitLogReaderChunkEncryptedNoCipher
+		}
+		r.cipherBuff, err = r.cipher.Decrypt(r.cipherBuff, data)
+		if err != nil {
+			return nil, err
+		}
+		data = r.cipherBuff
+	}
+
+	compressionType := compression.Type(flags >> chunkFlagCompressionShift & chunkFlagCompressionMask)
+	if compressionType == compression.None {
+		return data, nil
+	}
+	codec := r.codecs[compressionType]
+	if codec == nil {
+		codec, err = compression.NewCodec(compressionType)
+		if err != nil {
+			return nil, fmt.Errorf("commit log chunk has invalid compression: %w", err)
+		}
+		r.codecs[compressionType] = codec
+	}
+	r.compressionBuff, err = codec.Decompress(r.compressionBuff, data)
+	if err != nil {
+		return nil, err
+	}
+	return r.compressionBuff, nil
+}
+
 func (r *chunkReader) Read(p []byte) (int, error) {
 	size := len(p)
 	read := 0
@@ -124,8 +177,8 @@ func (r *chunkReader) Read(p []byte) (int, error) {
 	if r.chunkDataRemaining < size {
 		// Copy any remaining
 		if r.chunkDataRemaining > 0 {
-			chunkDataOffset := len(r.chunkData) - r.chunkDataRemaining
-			n := copy(p, r.chunkData[chunkDataOffset:])
+			chunkDataOffset := len(r.data) - r.chunkDataRemaining
+			n := copy(p, r.data[chunkDataOffset:])
 			r.chunkDataRemaining -= n
 			read += n
 		}
@@ -144,8 +197,8 @@ func (r *chunkReader) Read(p []byte) (int, error) {
 		return read, err
 	}
 
-	chunkDataOffset := len(r.chunkData) - r.chunkDataRemaining
-	n := copy(p, r.chunkData[chunkDataOffset:][:len(p)])
+	chunkDataOffset := len(r.data) - r.chunkDataRemaining
+	n := copy(p, r.data[chunkDataOffset:][:len(p)])
 	r.chunkDataRemaining -= n
 	read += n
 	return read, nil
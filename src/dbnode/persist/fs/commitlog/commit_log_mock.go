@@ -29,6 +29,7 @@ import (
 	"time"
 
 	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/compression"
 	"github.com/m3db/m3/src/dbnode/persist/fs"
 	"github.com/m3db/m3/src/dbnode/ts"
 	"github.com/m3db/m3/src/dbnode/ts/writes"
@@ -335,6 +336,20 @@ func (mr *MockOptionsMockRecorder) ClockOptions() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClockOptions", reflect.TypeOf((*MockOptions)(nil).ClockOptions))
 }
 
+// Compression mocks base method.
+func (m *MockOptions) Compression() compression.Type {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Compression")
+	ret0, _ := ret[0].(compression.Type)
+	return ret0
+}
+
+// Compression indicates an expected call of Compression.
+func (mr *MockOptionsMockRecorder) Compression() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Compression", reflect.TypeOf((*MockOptions)(nil).Compression))
+}
+
 // FilesystemOptions mocks base method.
 func (m *MockOptions) FilesystemOptions() fs.Options {
 	m.ctrl.T.Helper()
@@ -489,6 +504,20 @@ func (mr *MockOptionsMockRecorder) SetClockOptions(value interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetClockOptions", reflect.TypeOf((*MockOptions)(nil).SetClockOptions), value)
 }
 
+// SetCompression mocks base method.
+func (m *MockOptions) SetCompression(value compression.Type) Options {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCompression", value)
+	ret0, _ := ret[0].(Options)
+	return ret0
+}
+
+// SetCompression indicates an expected call of SetCompression.
+func (mr *MockOptionsMockRecorder) SetCompression(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCompression", reflect.TypeOf((*MockOptions)(nil).SetCompression), value)
+}
+
 // SetFilesystemOptions mocks base method.
 func (m *MockOptions) SetFilesystemOptions(value fs.Options) Options {
 	m.ctrl.T.Helper()
@@ -22,11 +22,13 @@ package commitlog
 
 import (
 	"bytes"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"io/ioutil"
 	"math/rand"
 	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -35,6 +37,8 @@ import (
 
 	"github.com/m3db/bitset"
 	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/compression"
+	"github.com/m3db/m3/src/dbnode/persist/encryption"
 	"github.com/m3db/m3/src/dbnode/persist/fs"
 	"github.com/m3db/m3/src/dbnode/ts"
 	"github.com/m3db/m3/src/dbnode/ts/writes"
@@ -484,6 +488,109 @@ func TestCommitLogWrite(t *testing.T) {
 	}
 }
 
+func newTestEncryptionCipher(t *testing.T) (encryption.Cipher, func()) {
+	dir, err := ioutil.TempDir("", "keys")
+	require.NoError(t, err)
+
+	key := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, 32))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "key"), []byte(key), 0600))
+	provider, err := encryption.NewFileKeyProvider(dir, "key")
+	require.NoError(t, err)
+	return encryption.NewCipher(provider), func() { os.RemoveAll(dir) }
+}
+
+func TestCommitLogWriteCompressedAndEncrypted(t *testing.T) {
+	cipher, cleanupKeys := newTestEncryptionCipher(t)
+	defer cleanupKeys()
+
+	testCases := []struct {
+		compression compression.Type
+		encrypted   bool
+	}{
+		{compression: compression.Snappy},
+		{compression: compression.None, encrypted: true},
+		{compression: compression.Zstd, encrypted: true},
+	}
+
+	for _, testCase := range testCases {
+		name := fmt.Sprintf("%s-encrypted=%v", testCase.compression, testCase.encrypted)
+		t.Run(name, func(t *testing.T) {
+			opts, scope := newTestOptions(t, overrides{
+				strategy: StrategyWriteWait,
+			})
+			defer cleanup(t, opts)
+
+			opts = opts.
+				SetCompression(testCase.compression).
+				SetFilesystemOptions(opts.FilesystemOptions().
+					SetEncryptionEnabled(testCase.encrypted).
+					SetEncryptionCipher(cipher))
+
+			secret := bytes.Repeat([]byte("secret"), 100)
+			writes := []testWrite{
+				{
+					testSeries(t, opts, 0, "foo.bar", ident.NewTags(ident.StringTag("name1", "val1")), 127),
+					xtime.Now(), 123.456, xtime.Second, secret, nil,
+				},
+				{
+					testSeries(t, opts, 1, "foo.baz", ident.NewTags(ident.StringTag("name2", "val2")), 150),
+					xtime.Now(), 456.789, xtime.Second, randomByteSlice(3 * opts.FlushSize()), nil,
+				},
+			}
+
+			commitLog := newTestCommitLog(t, opts)
+			writeCommitLogs(t, scope, commitLog, writes).Wait()
+			require.NoError(t, commitLog.Close())
+
+			// The annotation is stored as is unless the commit log is
+			// compressed or encrypted.
+			files, err := fs.SortedCommitLogFiles(fs.CommitLogsDirPath(
+				opts.FilesystemOptions().FilePathPrefix()))
+			require.NoError(t, err)
+			for _, file := range files {
+				contents, err := ioutil.ReadFile(file)
+				require.NoError(t, err)
+				require.False(t, bytes.Contains(contents, secret))
+			}
+
+			assertCommitLogWritesByIterating(t, commitLog, writes)
+		})
+	}
+}
+
+func TestCommitLogReadEncryptedWithoutCipher(t *testing.T) {
+	cipher, cleanupKeys := newTestEncryptionCipher(t)
+	defer cleanupKeys()
+
+	opts, scope := newTestOptions(t, overrides{
+		strategy: StrategyWriteWait,
+	})
+	defer cleanup(t, opts)
+
+	fsOpts := opts.FilesystemOptions()
+	opts = opts.SetFilesystemOptions(fsOpts.
+		SetEncryptionEnabled(true).
+		SetEncryptionCipher(cipher))
+
+	writes := []testWrite{
+		{
+			testSeries(t, opts, 0, "foo.bar", ident.NewTags(ident.StringTag("name1", "val1")), 127),
+			xtime.Now(), 123.456, xtime.Second, nil, nil,
+		},
+	}
+	commitLog := newTestCommitLog(t, opts)
+	writeCommitLogs(t, scope, commitLog, writes).Wait()
+	require.NoError(t, commitLog.Close())
+
+	files, err := fs.SortedCommitLogFiles(fs.CommitLogsDirPath(fsOpts.FilePathPrefix()))
+	require.NoError(t, err)
+	require.True(t, len(files) > 0)
+
+	r := NewReader(NewReaderOptions(opts.SetFilesystemOptions(fsOpts), false))
+	_, err = r.Open(files[0])
+	require.Equal(t, errCommitLogReaderChunkEncryptedNoCipher, err)
+}
+
 func TestReadCommitLogMissingMetadata(t *testing.T) {
 	readConc := 4
 	// Make sure we're not leaking goroutines
@@ -88,7 +88,7 @@ func ReadLogInfo(filePath string, opts Options) (int64, error) {
 		return 0, fsError{err}
 	}
 
-	chunkReader := newChunkReader(opts.FlushSize())
+	chunkReader := newChunkReader(opts.FlushSize(), opts.FilesystemOptions().EncryptionCipher())
 	chunkReader.reset(fd)
 	size, err := binary.ReadUvarint(chunkReader)
 	if err != nil {
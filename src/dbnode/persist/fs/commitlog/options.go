@@ -26,6 +26,7 @@ import (
 	"runtime"
 	"time"
 
+	"github.com/m3db/m3/src/dbnode/persist/compression"
 	"github.com/m3db/m3/src/dbnode/persist/fs"
 	"github.com/m3db/m3/src/x/clock"
 	"github.com/m3db/m3/src/x/ident"
@@ -75,6 +76,7 @@ type options struct {
 	fsOpts                  fs.Options
 	strategy                Strategy
 	flushSize               int
+	compression             compression.Type
 	flushInterval           time.Duration
 	backlogQueueSize        int
 	backlogQueueChannelSize int
@@ -118,6 +120,10 @@ func (o *options) Validate() error {
 		return errReadConcurrencyPositive
 	}
 
+	if err := o.Compression().Validate(); err != nil {
+		return err
+	}
+
 	if float64(o.BacklogQueueSize())/float64(o.BacklogQueueChannelSize()) > MaximumQueueSizeQueueChannelSizeRatio {
 		return fmt.Errorf(
 			"BacklogQueueSize / BacklogQueueChannelSize ratio must be at most: %f, but was: %f",
@@ -187,6 +193,16 @@ func (o *options) FlushSize() int {
 	return o.flushSize
 }
 
+func (o *options) SetCompression(value compression.Type) Options {
+	opts := *o
+	opts.compression = value
+	return &opts
+}
+
+func (o *options) Compression() compression.Type {
+	return o.compression
+}
+
 func (o *options) SetFlushInterval(value time.Duration) Options {
 	opts := *o
 	opts.flushInterval = value
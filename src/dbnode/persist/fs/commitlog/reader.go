@@ -51,6 +51,7 @@ var (
 	emptyLogInfo schema.LogInfo
 
 	errCommitLogReaderChunkSizeChecksumMismatch = errors.New("commit log reader encountered chunk size checksum mismatch")
+	errCommitLogReaderChunkEncryptedNoCipher    = errors.New("commit log reader encountered encrypted chunk but no encryption cipher is set")
 	errCommitLogReaderIsNotReusable             = errors.New("commit log reader is not reusable")
 	errCommitLogReaderMissingMetadata           = errors.New("commit log reader encountered a datapoint without corresponding metadata")
 )
@@ -116,7 +117,7 @@ func NewReader(opts ReaderOptions) Reader {
 		tagDecoder:             opts.commitLogOptions.FilesystemOptions().TagDecoderPool().Get(),
 		tagDecoderCheckedBytes: tagDecoderCheckedBytes,
 		checkedBytesPool:       opts.commitLogOptions.BytesPool(),
-		chunkReader:            newChunkReader(opts.commitLogOptions.FlushSize()),
+		chunkReader:            newChunkReader(opts.commitLogOptions.FlushSize(), opts.commitLogOptions.FilesystemOptions().EncryptionCipher()),
 		infoDecoder:            msgpack.NewDecoder(opts.commitLogOptions.FilesystemOptions().DecodingOptions()),
 		infoDecoderStream:      msgpack.NewByteDecoderStream(nil),
 		seriesIDReused:         ident.NewReusableBytesID(),
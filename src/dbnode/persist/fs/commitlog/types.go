@@ -24,6 +24,7 @@ import (
 	"time"
 
 	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/compression"
 	"github.com/m3db/m3/src/dbnode/persist/fs"
 	"github.com/m3db/m3/src/dbnode/ts"
 	"github.com/m3db/m3/src/dbnode/ts/writes"
@@ -173,6 +174,14 @@ type Options interface {
 	// FlushSize returns the flush size.
 	FlushSize() int
 
+	// SetCompression sets the compression applied to each chunk of the
+	// commit log.
+	SetCompression(value compression.Type) Options
+
+	// Compression returns the compression applied to each chunk of the
+	// commit log.
+	Compression() compression.Type
+
 	// SetStrategy sets the strategy.
 	SetStrategy(value Strategy) Options
 
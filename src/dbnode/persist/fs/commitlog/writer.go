@@ -24,12 +24,15 @@ import (
 	"bufio"
 	"encoding/binary"
 	"errors"
+	"fmt"
 	"io"
 	"os"
 
 	"github.com/m3db/bitset"
 	"github.com/m3db/m3/src/dbnode/digest"
 	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/compression"
+	"github.com/m3db/m3/src/dbnode/persist/encryption"
 	"github.com/m3db/m3/src/dbnode/persist/fs"
 	"github.com/m3db/m3/src/dbnode/persist/fs/msgpack"
 	"github.com/m3db/m3/src/dbnode/persist/schema"
@@ -51,6 +54,17 @@ const (
 		chunkHeaderChecksumSizeLen +
 		chunkHeaderChecksumDataLen
 
+	// The high bits of the chunk header size hold flags describing how the
+	// chunk data was transformed before being written, chunks written before
+	// the flags were introduced never set them since chunks are bounded by
+	// the flush size:
+	// - bit 31 is set if the chunk data is encrypted
+	// - bits 28-30 hold the compression type of the chunk data
+	chunkSizeMask             = 1<<28 - 1
+	chunkFlagEncrypted        = 1 << 31
+	chunkFlagCompressionShift = 28
+	chunkFlagCompressionMask  = 0x7
+
 	defaultBitSetLength = 65536
 
 	defaultEncoderBuffSize = 16384
@@ -121,7 +135,7 @@ func newCommitLogWriter(
 		newFileMode:         opts.FilesystemOptions().NewFileMode(),
 		newDirectoryMode:    opts.FilesystemOptions().NewDirectoryMode(),
 		nowFn:               opts.ClockOptions().NowFn(),
-		chunkWriter:         newChunkWriter(flushFn, shouldFsync),
+		chunkWriter:         newChunkWriter(flushFn, shouldFsync, opts),
 		chunkReserveHeader:  make([]byte, chunkHeaderLen),
 		buffer:              bufio.NewWriterSize(nil, opts.FlushSize()),
 		sizeBuffer:          make([]byte, binary.MaxVarintLen64),
@@ -294,14 +308,53 @@ type fsChunkWriter struct {
 	flushFn flushFn
 	buff    []byte
 	fsync   bool
+
+	// Optional codec and cipher applied to the data of each chunk.
+	codec           compression.Codec
+	cipher          encryption.Cipher
+	compressionBuff []byte
+	cipherBuff      []byte
 }
 
-func newChunkWriter(flushFn flushFn, fsync bool) chunkWriter {
-	return &fsChunkWriter{
+func newChunkWriter(flushFn flushFn, fsync bool, opts Options) chunkWriter {
+	w := &fsChunkWriter{
 		flushFn: flushFn,
 		buff:    make([]byte, chunkHeaderLen),
 		fsync:   fsync,
 	}
+	if opts.Compression() != compression.None {
+		// NB: The compression type is validated by the options.
+		w.codec, _ = compression.NewCodec(opts.Compression())
+	}
+	if fsOpts := opts.FilesystemOptions(); fsOpts.EncryptionEnabled() {
+		w.cipher = fsOpts.EncryptionCipher()
+	}
+	return w
+}
+
+// transform compresses and encrypts the chunk data if required, returning the
+// data to write along with the flags describing the transformations applied.
+func (w *fsChunkWriter) transform(p []byte) ([]byte, uint32, error) {
+	var flags uint32
+	if w.codec != nil {
+		w.compressionBuff = w.codec.Compress(w.compressionBuff, p)
+		p = w.compressionBuff
+		flags |= uint32(w.codec.Type()) << chunkFlagCompressionShift
+	}
+	if w.cipher != nil {
+		var err error
+		w.cipherBuff, err = w.cipher.Encrypt(w.cipherBuff, p)
+		if err != nil {
+			return nil, 0, err
+		}
+		p = w.cipherBuff
+		flags |= chunkFlagEncrypted
+	}
+	if len(p) > chunkSizeMask {
+		return nil, 0, fmt.Errorf("commit log chunk size %d exceeds max size %d",
+			len(p), chunkSizeMask)
+	}
+	return p, flags, nil
 }
 
 func (w *fsChunkWriter) reset(f xos.File) {
@@ -326,7 +379,12 @@ func (w *fsChunkWriter) sync() error {
 // If the header or p is not fully written to the file, then this method returns number of bytes of p actually written
 // to the file and an error explaining the reason of failure to write fully to the file.
 func (w *fsChunkWriter) Write(p []byte) (int, error) {
-	size := len(p)
+	data, flags, err := w.transform(p)
+	if err != nil {
+		w.flushFn(err)
+		return 0, err
+	}
+	size := len(data)
 
 	sizeStart, sizeEnd :=
 		0, chunkHeaderSizeLen
@@ -336,11 +394,11 @@ func (w *fsChunkWriter) Write(p []byte) (int, error) {
 		checksumSizeEnd, checksumSizeEnd+chunkHeaderChecksumDataLen
 
 	// Write size
-	endianness.PutUint32(w.buff[sizeStart:sizeEnd], uint32(size))
+	endianness.PutUint32(w.buff[sizeStart:sizeEnd], uint32(size)|flags)
 
 	// Calculate checksums
 	checksumSize := digest.Checksum(w.buff[sizeStart:sizeEnd])
-	checksumData := digest.Checksum(p)
+	checksumData := digest.Checksum(data)
 
 	// Write checksums
 	digest.
@@ -351,14 +409,17 @@ func (w *fsChunkWriter) Write(p []byte) (int, error) {
 		WriteDigest(checksumData)
 
 	// Combine buffers to reduce to a single syscall
-	w.buff = append(w.buff[:chunkHeaderLen], p...)
+	w.buff = append(w.buff[:chunkHeaderLen], data...)
 
 	// Write contents to file descriptor
 	n, err := w.fd.Write(w.buff)
-	// Count bytes successfully written from slice p
+	// Count bytes successfully written from slice p, a partially written
+	// transformed chunk cannot be mapped back to bytes of p.
 	pBytesWritten := n - chunkHeaderLen
-	if pBytesWritten < 0 {
+	if pBytesWritten < 0 || (flags != 0 && pBytesWritten < size) {
 		pBytesWritten = 0
+	} else if flags != 0 {
+		pBytesWritten = len(p)
 	}
 
 	if err != nil {
@@ -0,0 +1,402 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fs
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/m3db/m3/src/dbnode/persist/compression"
+	"github.com/m3db/m3/src/dbnode/persist/encryption"
+	"github.com/m3db/m3/src/dbnode/persist/schema"
+)
+
+// Compressed and encrypted data files are laid out as a sequence of
+// independently compressed and/or encrypted pages followed by a page index and
+// a fixed size trailer:
+//
+//	[page 0]...[page N-1][page index entry 0]...[page index entry N-1][trailer]
+//
+// Index entries in the index file keep referring to offsets and sizes of the
+// uncompressed data so that the data file can be read as if it was not
+// compressed. Series data is never split across pages, so reading a single
+// series only requires decoding the page that contains it.
+const (
+	// compressedDataPageSize is the target size of the uncompressed data of
+	// each page, pages only exceed it when a single series is larger.
+	compressedDataPageSize = 1 << 15
+
+	compressedPageIndexEntrySize = 24
+	compressedDataTrailerSize    = 16
+	compressedDataTrailerMagic   = uint32(0x6d33637a)
+)
+
+var (
+	errCompressedDataTrailerInvalid = errors.New("compressed data file has invalid trailer")
+	errCompressedPageNotFound       = errors.New("compressed data file has no page for entry")
+	errEncryptedDataNoCipher        = errors.New("data file is encrypted but no encryption cipher is set")
+)
+
+// dataCompression returns the compression applied to the data file of a
+// fileset given its info file.
+func dataCompression(info schema.IndexInfo) (compression.Type, error) {
+	versionChecker := schema.NewVersionChecker(int(info.MajorVersion), int(info.MinorVersion))
+	if !versionChecker.DataCompressionEnabled() {
+		return compression.None, nil
+	}
+	if err := info.Compression.Validate(); err != nil {
+		return compression.None, err
+	}
+	return info.Compression, nil
+}
+
+// dataEncrypted returns whether the data file of a fileset is encrypted given
+// its info file.
+func dataEncrypted(info schema.IndexInfo) bool {
+	versionChecker := schema.NewVersionChecker(int(info.MajorVersion), int(info.MinorVersion))
+	return versionChecker.DataEncryptionEnabled() && info.Encrypted
+}
+
+// dataPageCodec compresses and then encrypts the pages of a data file, either
+// step is skipped when its codec or cipher is not set.
+type dataPageCodec struct {
+	compression compression.Codec
+	cipher      encryption.Cipher
+}
+
+// newDataPageCodec returns the codec for the pages of a data file, returning
+// false if the data file is neither compressed nor encrypted and so is not
+// paged at all.
+func newDataPageCodec(
+	compressionType compression.Type,
+	encrypted bool,
+	cipher encryption.Cipher,
+) (dataPageCodec, bool, error) {
+	var codec dataPageCodec
+	if compressionType != compression.None {
+		var err error
+		codec.compression, err = compression.NewCodec(compressionType)
+		if err != nil {
+			return dataPageCodec{}, false, err
+		}
+	}
+	if encrypted {
+		if cipher == nil {
+			return dataPageCodec{}, false, errEncryptedDataNoCipher
+		}
+		codec.cipher = cipher
+	}
+	return codec, codec.compression != nil || codec.cipher != nil, nil
+}
+
+// dataPageBuffers are the buffers reused to hold the intermediate and final
+// results of encoding and decoding pages.
+type dataPageBuffers struct {
+	compression []byte
+	cipher      []byte
+}
+
+// encode returns the encoded page, which is only valid until the buffers are
+// next used.
+func (c dataPageCodec) encode(b *dataPageBuffers, page []byte) ([]byte, error) {
+	encoded := page
+	if c.compression != nil {
+		b.compression = c.compression.Compress(b.compression, encoded)
+		encoded = b.compression
+	}
+	if c.cipher != nil {
+		var err error
+		b.cipher, err = c.cipher.Encrypt(b.cipher, encoded)
+		if err != nil {
+			return nil, err
+		}
+		encoded = b.cipher
+	}
+	return encoded, nil
+}
+
+// decode returns the decoded page, which is only valid until the buffers are
+// next used.
+func (c dataPageCodec) decode(b *dataPageBuffers, encoded []byte) ([]byte, error) {
+	var err error
+	if c.cipher != nil {
+		b.cipher, err = c.cipher.Decrypt(b.cipher, encoded)
+		if err != nil {
+			return nil, err
+		}
+		encoded = b.cipher
+	}
+	if c.compression != nil {
+		b.compression, err = c.compression.Decompress(b.compression, encoded)
+		if err != nil {
+			return nil, err
+		}
+		encoded = b.compression
+	}
+	return encoded, nil
+}
+
+// compressedPage describes a single compressed page of a data file.
+type compressedPage struct {
+	// offset and size of the uncompressed data held by the page.
+	offset int64
+	size   uint32
+	// fileOffset and fileSize of the compressed page in the data file.
+	fileOffset int64
+	fileSize   uint32
+}
+
+func (p compressedPage) contains(offset, size int64) bool {
+	return offset >= p.offset && offset+size <= p.offset+int64(p.size)
+}
+
+// compressedPageIndex is the set of pages of a compressed data file ordered
+// by their uncompressed offset.
+type compressedPageIndex []compressedPage
+
+// find returns the page that holds the uncompressed data at the given
+// offset and size.
+func (idx compressedPageIndex) find(offset, size int64) (int, error) {
+	i := sort.Search(len(idx), func(i int) bool {
+		return idx[i].offset+int64(idx[i].size) > offset
+	})
+	if i == len(idx) || !idx[i].contains(offset, size) {
+		return 0, fmt.Errorf("%w: offset=%d, size=%d", errCompressedPageNotFound, offset, size)
+	}
+	return i, nil
+}
+
+// readCompressedPageIndex reads the page index from the end of a compressed
+// data file of the given size.
+func readCompressedPageIndex(r io.ReaderAt, fileSize int64) (compressedPageIndex, error) {
+	if fileSize < compressedDataTrailerSize {
+		return nil, errCompressedDataTrailerInvalid
+	}
+
+	var trailer [compressedDataTrailerSize]byte
+	if _, err := r.ReadAt(trailer[:], fileSize-compressedDataTrailerSize); err != nil {
+		return nil, err
+	}
+	var (
+		indexOffset = int64(binary.BigEndian.Uint64(trailer[0:8]))
+		numPages    = int64(binary.BigEndian.Uint32(trailer[8:12]))
+		magic       = binary.BigEndian.Uint32(trailer[12:16])
+		indexSize   = numPages * compressedPageIndexEntrySize
+	)
+	if magic != compressedDataTrailerMagic ||
+		indexOffset < 0 ||
+		indexOffset+indexSize != fileSize-compressedDataTrailerSize {
+		return nil, errCompressedDataTrailerInvalid
+	}
+
+	buf := make([]byte, indexSize)
+	if _, err := r.ReadAt(buf, indexOffset); err != nil {
+		return nil, err
+	}
+
+	idx := make(compressedPageIndex, 0, numPages)
+	for b := buf; len(b) > 0; b = b[compressedPageIndexEntrySize:] {
+		page := compressedPage{
+			offset:     int64(binary.BigEndian.Uint64(b[0:8])),
+			size:       binary.BigEndian.Uint32(b[8:12]),
+			fileOffset: int64(binary.BigEndian.Uint64(b[12:20])),
+			fileSize:   binary.BigEndian.Uint32(b[20:24]),
+		}
+		if page.fileOffset+int64(page.fileSize) > indexOffset {
+			return nil, errCompressedDataTrailerInvalid
+		}
+		idx = append(idx, page)
+	}
+	return idx, nil
+}
+
+// compressedPageWriter buffers the data of whole series into pages that are
+// encoded and written out once full.
+type compressedPageWriter struct {
+	w          io.Writer
+	codec      dataPageCodec
+	page       []byte
+	buffers    dataPageBuffers
+	index      compressedPageIndex
+	offset     int64
+	fileOffset int64
+}
+
+func (p *compressedPageWriter) reset(w io.Writer, codec dataPageCodec) {
+	p.w = w
+	p.codec = codec
+	p.page = p.page[:0]
+	p.index = p.index[:0]
+	p.offset = 0
+	p.fileOffset = 0
+}
+
+// startEntry must be called before writing the data of each series so that
+// the series is not split across pages.
+func (p *compressedPageWriter) startEntry(size int64) error {
+	if len(p.page) > 0 && int64(len(p.page))+size > compressedDataPageSize {
+		return p.flush()
+	}
+	return nil
+}
+
+func (p *compressedPageWriter) write(data []byte) {
+	p.page = append(p.page, data...)
+}
+
+func (p *compressedPageWriter) flush() error {
+	if len(p.page) == 0 {
+		return nil
+	}
+
+	encoded, err := p.codec.encode(&p.buffers, p.page)
+	if err != nil {
+		return err
+	}
+	if _, err := p.w.Write(encoded); err != nil {
+		return err
+	}
+
+	p.index = append(p.index, compressedPage{
+		offset:     p.offset,
+		size:       uint32(len(p.page)),
+		fileOffset: p.fileOffset,
+		fileSize:   uint32(len(encoded)),
+	})
+	p.offset += int64(len(p.page))
+	p.fileOffset += int64(len(encoded))
+	p.page = p.page[:0]
+	return nil
+}
+
+// close flushes any buffered data and writes out the page index and trailer.
+func (p *compressedPageWriter) close() error {
+	if err := p.flush(); err != nil {
+		return err
+	}
+
+	buf := make([]byte, len(p.index)*compressedPageIndexEntrySize+compressedDataTrailerSize)
+	b := buf
+	for _, page := range p.index {
+		binary.BigEndian.PutUint64(b[0:8], uint64(page.offset))
+		binary.BigEndian.PutUint32(b[8:12], page.size)
+		binary.BigEndian.PutUint64(b[12:20], uint64(page.fileOffset))
+		binary.BigEndian.PutUint32(b[20:24], page.fileSize)
+		b = b[compressedPageIndexEntrySize:]
+	}
+	binary.BigEndian.PutUint64(b[0:8], uint64(p.fileOffset))
+	binary.BigEndian.PutUint32(b[8:12], uint32(len(p.index)))
+	binary.BigEndian.PutUint32(b[12:16], compressedDataTrailerMagic)
+
+	_, err := p.w.Write(buf)
+	p.w = nil
+	return err
+}
+
+// compressedPageReader reads the decoded contents of a compressed or
+// encrypted data file held in memory, both sequentially and at given offsets.
+type compressedPageReader struct {
+	data    []byte
+	codec   dataPageCodec
+	index   compressedPageIndex
+	buffers dataPageBuffers
+
+	currPage int
+	currData []byte
+	currRead int
+}
+
+func newCompressedPageReader(
+	data []byte,
+	codec dataPageCodec,
+	index compressedPageIndex,
+) *compressedPageReader {
+	return &compressedPageReader{
+		data:     data,
+		codec:    codec,
+		index:    index,
+		currPage: -1,
+	}
+}
+
+func (r *compressedPageReader) loadPage(i int) error {
+	if i == r.currPage {
+		return nil
+	}
+
+	page := r.index[i]
+	encoded := r.data[page.fileOffset : page.fileOffset+int64(page.fileSize)]
+	data, err := r.codec.decode(&r.buffers, encoded)
+	if err != nil {
+		r.currPage = -1
+		return err
+	}
+	if len(data) != int(page.size) {
+		r.currPage = -1
+		return fmt.Errorf("decoded page size mismatch: expected=%d, actual=%d",
+			page.size, len(data))
+	}
+
+	r.currPage = i
+	r.currData = data
+	r.currRead = 0
+	return nil
+}
+
+// Read implements io.Reader, reading the decoded data in order.
+func (r *compressedPageReader) Read(b []byte) (int, error) {
+	var n int
+	for n < len(b) {
+		if r.currPage < 0 || r.currRead == len(r.currData) {
+			next := r.currPage + 1
+			if next >= len(r.index) {
+				break
+			}
+			if err := r.loadPage(next); err != nil {
+				return n, err
+			}
+		}
+		read := copy(b[n:], r.currData[r.currRead:])
+		r.currRead += read
+		n += read
+	}
+	if n == 0 && len(b) > 0 {
+		return 0, io.EOF
+	}
+	return n, nil
+}
+
+// readEntry returns the decoded data at the given offset and size, the
+// returned bytes are only valid until the next call to the reader.
+func (r *compressedPageReader) readEntry(offset, size int64) ([]byte, error) {
+	i, err := r.index.find(offset, size)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.loadPage(i); err != nil {
+		return nil, err
+	}
+	start := offset - r.index[i].offset
+	return r.currData[start : start+size], nil
+}
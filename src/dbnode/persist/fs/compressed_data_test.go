@@ -0,0 +1,277 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fs
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/m3db/m3/src/dbnode/digest"
+	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/compression"
+	"github.com/m3db/m3/src/dbnode/persist/encryption"
+	"github.com/m3db/m3/src/x/ident"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testCompressedEntries() []testEntry {
+	// Mix of entries smaller and larger than a single compressed page so
+	// that series are spread over several pages.
+	large := make([]byte, 3*compressedDataPageSize)
+	for i := range large {
+		large[i] = byte(i % 7)
+	}
+	return []testEntry{
+		{"foo", nil, []byte{1, 2, 3}},
+		{"bar", nil, []byte{4, 5, 6}},
+		{"baz", nil, make([]byte, 65536)},
+		{"cat", nil, large},
+		{"foo+bar=baz,qux=qaz", map[string]string{
+			"bar": "baz",
+			"qux": "qaz",
+		}, []byte{7, 8, 9}},
+	}
+}
+
+func writeCompressedTestData(
+	t *testing.T,
+	filePathPrefix string,
+	compressionType compression.Type,
+	entries []testEntry,
+) {
+	w := newTestWriter(t, filePathPrefix)
+	err := w.Open(DataWriterOpenOptions{
+		Identifier: FileSetFileIdentifier{
+			Namespace:  testNs1ID,
+			Shard:      0,
+			BlockStart: testWriterStart,
+		},
+		BlockSize:   testBlockSize,
+		FileSetType: persist.FileSetFlushType,
+		Compression: compressionType,
+	})
+	require.NoError(t, err)
+
+	for i := range entries {
+		metadata := persist.NewMetadataFromIDAndTags(entries[i].ID(),
+			entries[i].Tags(), persist.MetadataOptions{})
+		require.NoError(t, w.Write(metadata,
+			bytesRefd(entries[i].data),
+			digest.Checksum(entries[i].data)))
+	}
+	require.NoError(t, w.Close())
+}
+
+func TestCompressedReadWrite(t *testing.T) {
+	for _, compressionType := range []compression.Type{
+		compression.Snappy,
+		compression.Zstd,
+	} {
+		t.Run(compressionType.String(), func(t *testing.T) {
+			dir := createTempDir(t)
+			filePathPrefix := filepath.Join(dir, "")
+			defer os.RemoveAll(dir)
+
+			entries := testCompressedEntries()
+			writeCompressedTestData(t, filePathPrefix, compressionType, entries)
+
+			r := newTestReader(t, filePathPrefix)
+			readTestData(t, r, 0, testWriterStart, entries)
+		})
+	}
+}
+
+func TestCompressedReadValidateAndStatus(t *testing.T) {
+	dir := createTempDir(t)
+	filePathPrefix := filepath.Join(dir, "")
+	defer os.RemoveAll(dir)
+
+	entries := testCompressedEntries()
+	writeCompressedTestData(t, filePathPrefix, compression.Zstd, entries)
+
+	r := newTestReader(t, filePathPrefix)
+	require.NoError(t, r.Open(DataReaderOpenOptions{
+		Identifier: FileSetFileIdentifier{
+			Namespace:  testNs1ID,
+			Shard:      0,
+			BlockStart: testWriterStart,
+		},
+	}))
+	assert.Equal(t, compression.Zstd, r.Status().Compression)
+
+	for i := 0; i < r.Entries(); i++ {
+		_, _, data, _, err := readData(t, r)
+		require.NoError(t, err)
+		data.IncRef()
+		data.DecRef()
+		data.Finalize()
+	}
+	require.NoError(t, r.Validate())
+	require.NoError(t, r.Close())
+}
+
+func TestCompressedSeek(t *testing.T) {
+	dir := createTempDir(t)
+	filePathPrefix := filepath.Join(dir, "")
+	defer os.RemoveAll(dir)
+
+	entries := testCompressedEntries()
+	writeCompressedTestData(t, filePathPrefix, compression.Snappy, entries)
+
+	resources := newTestReusableSeekerResources()
+	s := newTestSeeker(filePathPrefix)
+	require.NoError(t, s.Open(testNs1ID, 0, testWriterStart, 0, resources))
+
+	// Seek out of write order to exercise random page access.
+	for i := len(entries) - 1; i >= 0; i-- {
+		data, err := s.SeekByID(ident.StringID(entries[i].id), resources)
+		require.NoError(t, err)
+
+		data.IncRef()
+		assert.True(t, bytes.Equal(entries[i].data, data.Bytes()))
+		data.DecRef()
+	}
+
+	_, err := s.SeekByID(ident.StringID("not-exists"), resources)
+	assert.Equal(t, errSeekIDNotFound, err)
+
+	clone, err := s.ConcurrentClone()
+	require.NoError(t, err)
+	data, err := clone.SeekByID(ident.StringID("cat"), resources)
+	require.NoError(t, err)
+	data.IncRef()
+	assert.True(t, bytes.Equal(entries[3].data, data.Bytes()))
+	data.DecRef()
+
+	require.NoError(t, clone.Close())
+	require.NoError(t, s.Close())
+}
+
+func TestCompressedReadLegacyUncompressedFileSet(t *testing.T) {
+	dir := createTempDir(t)
+	filePathPrefix := filepath.Join(dir, "")
+	defer os.RemoveAll(dir)
+
+	entries := testCompressedEntries()
+	writeCompressedTestData(t, filePathPrefix, compression.None, entries)
+
+	r := newTestReader(t, filePathPrefix)
+	readTestData(t, r, 0, testWriterStart, entries)
+}
+
+func newTestEncryptionOptions(t *testing.T, dir string) Options {
+	key := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, 32))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "key"), []byte(key), 0600))
+	provider, err := encryption.NewFileKeyProvider(dir, "key")
+	require.NoError(t, err)
+	return testDefaultOpts.
+		SetEncryptionEnabled(true).
+		SetEncryptionCipher(encryption.NewCipher(provider))
+}
+
+func TestEncryptedReadWriteSeek(t *testing.T) {
+	for _, compressionType := range []compression.Type{
+		compression.None,
+		compression.Zstd,
+	} {
+		t.Run(compressionType.String(), func(t *testing.T) {
+			dir := createTempDir(t)
+			filePathPrefix := filepath.Join(dir, "data")
+			defer os.RemoveAll(dir)
+
+			opts := newTestEncryptionOptions(t, dir).
+				SetFilePathPrefix(filePathPrefix).
+				SetWriterBufferSize(testWriterBufferSize)
+			w, err := NewWriter(opts)
+			require.NoError(t, err)
+			require.NoError(t, w.Open(DataWriterOpenOptions{
+				Identifier: FileSetFileIdentifier{
+					Namespace:  testNs1ID,
+					Shard:      0,
+					BlockStart: testWriterStart,
+				},
+				BlockSize:   testBlockSize,
+				FileSetType: persist.FileSetFlushType,
+				Compression: compressionType,
+			}))
+			entries := testCompressedEntries()
+			for i := range entries {
+				metadata := persist.NewMetadataFromIDAndTags(entries[i].ID(),
+					entries[i].Tags(), persist.MetadataOptions{})
+				require.NoError(t, w.Write(metadata,
+					bytesRefd(entries[i].data),
+					digest.Checksum(entries[i].data)))
+			}
+			require.NoError(t, w.Close())
+
+			// The plain data must not appear in the data file.
+			shardDir := ShardDataDirPath(filePathPrefix, testNs1ID, 0)
+			dataFile := filesetPathFromTimeAndIndex(shardDir, testWriterStart, 0, dataFileSuffix)
+			contents, err := ioutil.ReadFile(dataFile)
+			require.NoError(t, err)
+			assert.False(t, bytes.Contains(contents, entries[1].data))
+
+			r, err := NewReader(testBytesPool, opts)
+			require.NoError(t, err)
+			readTestData(t, r, 0, testWriterStart, entries)
+
+			resources := newTestReusableSeekerResources()
+			s := NewSeeker(filePathPrefix, testReaderBufferSize,
+				testReaderBufferSize, testBytesPool, false, opts)
+			require.NoError(t, s.Open(testNs1ID, 0, testWriterStart, 0, resources))
+			for i := len(entries) - 1; i >= 0; i-- {
+				data, err := s.SeekByID(ident.StringID(entries[i].id), resources)
+				require.NoError(t, err)
+				data.IncRef()
+				assert.True(t, bytes.Equal(entries[i].data, data.Bytes()))
+				data.DecRef()
+			}
+			require.NoError(t, s.Close())
+		})
+	}
+}
+
+func TestEncryptedReadWithoutCipher(t *testing.T) {
+	dir := createTempDir(t)
+	filePathPrefix := filepath.Join(dir, "data")
+	defer os.RemoveAll(dir)
+
+	opts := newTestEncryptionOptions(t, dir).SetFilePathPrefix(filePathPrefix)
+	w, err := NewWriter(opts)
+	require.NoError(t, err)
+	writeTestData(t, w, 0, testWriterStart, testCompressedEntries(), persist.FileSetFlushType)
+
+	r := newTestReader(t, filePathPrefix)
+	err = r.Open(DataReaderOpenOptions{
+		Identifier: FileSetFileIdentifier{
+			Namespace:  testNs1ID,
+			Shard:      0,
+			BlockStart: testWriterStart,
+		},
+	})
+	assert.Equal(t, errEncryptedDataNoCipher, err)
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fs
+
+import (
+	"bytes"
+	"errors"
+)
+
+// Small files that are written and read whole, such as the tombstones and
+// exemplars of a shard, are encrypted as a single block prefixed by a magic
+// so that they can be told apart from files written before encryption was
+// enabled:
+//
+//	[magic][encrypted block]
+var encryptedFileMagic = []byte("M3ENC\x00")
+
+var errEncryptedFileNoCipher = errors.New("file is encrypted but no encryption cipher is set")
+
+// EncryptFileData encrypts the contents of a file that is written whole if
+// encryption is enabled, otherwise the contents are returned as is.
+func EncryptFileData(opts Options, data []byte) ([]byte, error) {
+	if !opts.EncryptionEnabled() {
+		return data, nil
+	}
+
+	encrypted, err := opts.EncryptionCipher().Encrypt(nil, data)
+	if err != nil {
+		return nil, err
+	}
+	return append(append([]byte(nil), encryptedFileMagic...), encrypted...), nil
+}
+
+// DecryptFileData decrypts the contents of a file written by EncryptFileData,
+// contents that are not encrypted are returned as is.
+func DecryptFileData(opts Options, data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, encryptedFileMagic) {
+		return data, nil
+	}
+
+	cipher := opts.EncryptionCipher()
+	if cipher == nil {
+		return nil, errEncryptedFileNoCipher
+	}
+	return cipher.Decrypt(nil, data[len(encryptedFileMagic):])
+}
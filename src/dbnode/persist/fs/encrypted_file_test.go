@@ -0,0 +1,67 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fs
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncryptFileData(t *testing.T) {
+	dir, err := ioutil.TempDir("", "testdir")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	var (
+		opts = newTestEncryptionOptions(t, dir)
+		data = []byte(`{"blocks":[]}`)
+	)
+	encrypted, err := EncryptFileData(opts, data)
+	require.NoError(t, err)
+	assert.NotContains(t, string(encrypted), string(data))
+
+	decrypted, err := DecryptFileData(opts, encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, data, decrypted)
+
+	// Files written before encryption was enabled are read as is.
+	decrypted, err = DecryptFileData(opts, data)
+	require.NoError(t, err)
+	assert.Equal(t, data, decrypted)
+
+	// Files are only encrypted while encryption is enabled, but can still be
+	// decrypted after it has been disabled.
+	opts = opts.SetEncryptionEnabled(false)
+	plain, err := EncryptFileData(opts, data)
+	require.NoError(t, err)
+	assert.Equal(t, data, plain)
+
+	decrypted, err = DecryptFileData(opts, encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, data, decrypted)
+
+	_, err = DecryptFileData(opts.SetEncryptionCipher(nil), encrypted)
+	assert.Equal(t, errEncryptedFileNoCipher, err)
+}
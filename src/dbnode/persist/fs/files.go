@@ -1041,6 +1041,18 @@ func SnapshotFiles(filePathPrefix string, namespace ident.ID, shard uint32) (Fil
 	})
 }
 
+// IndexFiles returns a slice of all the names for all the index fileset files
+// for a given namespace.
+func IndexFiles(filePathPrefix string, namespace ident.ID) (FileSetFilesSlice, error) {
+	return filesetFiles(filesetFilesSelector{
+		fileSetType:    persist.FileSetFlushType,
+		contentType:    persist.FileSetIndexContentType,
+		filePathPrefix: filePathPrefix,
+		namespace:      namespace,
+		pattern:        filesetFilePattern,
+	})
+}
+
 // IndexSnapshotFiles returns a slice of all the names for all the index fileset files
 // for a given namespace.
 func IndexSnapshotFiles(filePathPrefix string, namespace ident.ID) (FileSetFilesSlice, error) {
@@ -24,6 +24,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/namespace"
 	"github.com/m3db/m3/src/dbnode/persist"
 	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/persist/schema"
 	xtime "github.com/m3db/m3/src/x/time"
 )
 
@@ -112,7 +113,8 @@ func (v *toVersion1_1Task) Run() (fs.ReadInfoFileResult, error) {
 	}
 
 	infoFileResult.Info.VolumeIndex = newIndex
-	infoFileResult.Info.MinorVersion = 1
+	// The rewritten files carry the minor version of the current encoder.
+	infoFileResult.Info.MinorVersion = schema.MinorVersion
 
 	return infoFileResult, nil
 }
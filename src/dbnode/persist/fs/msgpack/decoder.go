@@ -27,6 +27,7 @@ import (
 	"io"
 
 	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/compression"
 	"github.com/m3db/m3/src/dbnode/persist/schema"
 	"github.com/m3db/m3/src/x/pool"
 
@@ -311,6 +312,11 @@ func (dec *Decoder) decodeIndexInfo() schema.IndexInfo {
 		opts.override = true
 		opts.numExpectedMinFields = 6
 		opts.numExpectedCurrFields = 10
+	case LegacyEncodingIndexVersionV5:
+		// V5 had 11 fields.
+		opts.override = true
+		opts.numExpectedMinFields = 6
+		opts.numExpectedCurrFields = 11
 	}
 
 	numFieldsToSkip, actual, ok := dec.checkNumFieldsFor(indexInfoType, opts)
@@ -363,6 +369,20 @@ func (dec *Decoder) decodeIndexInfo() schema.IndexInfo {
 	// Decode fields added in V5.
 	indexInfo.MinorVersion = dec.decodeVarint()
 
+	// At this point if its a V5 file we've decoded all the available fields.
+	if dec.legacy.DecodeLegacyIndexInfoVersion == LegacyEncodingIndexVersionV5 || actual < 12 {
+		dec.skip(numFieldsToSkip)
+		return indexInfo
+	}
+
+	// Decode fields added in V6.
+	indexInfo.Compression = compression.Type(dec.decodeVarint())
+	if actual < 13 {
+		dec.skip(numFieldsToSkip)
+		return indexInfo
+	}
+	indexInfo.Encrypted = dec.decodeVarint() != 0
+
 	dec.skip(numFieldsToSkip)
 	return indexInfo
 }
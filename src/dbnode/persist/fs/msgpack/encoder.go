@@ -58,12 +58,13 @@ type Encoder struct {
 type LegacyEncodingIndexInfoVersion int
 
 const (
-	LegacyEncodingIndexVersionCurrent                                = LegacyEncodingIndexVersionV5
+	LegacyEncodingIndexVersionCurrent                                = LegacyEncodingIndexVersionV6
 	LegacyEncodingIndexVersionV1      LegacyEncodingIndexInfoVersion = iota
 	LegacyEncodingIndexVersionV2
 	LegacyEncodingIndexVersionV3
 	LegacyEncodingIndexVersionV4
 	LegacyEncodingIndexVersionV5
+	LegacyEncodingIndexVersionV6
 )
 
 // LegacyEncodingIndexEntryVersion is the encoding/decoding version to use when processing index entries
@@ -151,8 +152,10 @@ func (enc *Encoder) EncodeIndexInfo(info schema.IndexInfo) error {
 		enc.encodeIndexInfoV3(info)
 	case LegacyEncodingIndexVersionV4:
 		enc.encodeIndexInfoV4(info)
-	default:
+	case LegacyEncodingIndexVersionV5:
 		enc.encodeIndexInfoV5(info)
+	default:
+		enc.encodeIndexInfoV6(info)
 	}
 	return enc.err
 }
@@ -278,6 +281,21 @@ func (enc *Encoder) encodeIndexInfoV4(info schema.IndexInfo) {
 }
 
 func (enc *Encoder) encodeIndexInfoV5(info schema.IndexInfo) {
+	enc.encodeArrayLenFn(11) // V5 had 11 fields.
+	enc.encodeVarintFn(info.BlockStart)
+	enc.encodeVarintFn(info.BlockSize)
+	enc.encodeVarintFn(info.Entries)
+	enc.encodeVarintFn(info.MajorVersion)
+	enc.encodeIndexSummariesInfo(info.Summaries)
+	enc.encodeIndexBloomFilterInfo(info.BloomFilter)
+	enc.encodeVarintFn(info.SnapshotTime)
+	enc.encodeVarintFn(int64(info.FileType))
+	enc.encodeBytesFn(info.SnapshotID)
+	enc.encodeVarintFn(int64(info.VolumeIndex))
+	enc.encodeVarintFn(info.MinorVersion)
+}
+
+func (enc *Encoder) encodeIndexInfoV6(info schema.IndexInfo) {
 	enc.encodeNumObjectFieldsForFn(indexInfoType)
 	enc.encodeVarintFn(info.BlockStart)
 	enc.encodeVarintFn(info.BlockSize)
@@ -290,6 +308,15 @@ func (enc *Encoder) encodeIndexInfoV5(info schema.IndexInfo) {
 	enc.encodeBytesFn(info.SnapshotID)
 	enc.encodeVarintFn(int64(info.VolumeIndex))
 	enc.encodeVarintFn(info.MinorVersion)
+	enc.encodeVarintFn(int64(info.Compression))
+	enc.encodeVarintFn(boolToInt64(info.Encrypted))
+}
+
+func boolToInt64(v bool) int64 {
+	if v {
+		return 1
+	}
+	return 0
 }
 
 func (enc *Encoder) encodeIndexSummariesInfo(info schema.IndexSummariesInfo) {
@@ -24,7 +24,9 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 
+	"github.com/m3db/m3/src/dbnode/persist/encryption"
 	"github.com/m3db/m3/src/dbnode/persist/fs/msgpack"
 	"github.com/m3db/m3/src/dbnode/runtime"
 	"github.com/m3db/m3/src/m3ninx/index/segment/fst"
@@ -82,6 +84,10 @@ var (
 
 	errTagEncoderPoolNotSet = errors.New("tag encoder pool is not set")
 	errTagDecoderPoolNotSet = errors.New("tag decoder pool is not set")
+
+	errSecondaryTierFilePathPrefixInvalid = errors.New(
+		"secondary tier file path prefix must differ from the file path prefix")
+	errEncryptionCipherNotSet = errors.New("encryption is enabled but no encryption cipher is set")
 )
 
 type options struct {
@@ -90,6 +96,7 @@ type options struct {
 	runtimeOptsMgr                       runtime.OptionsManager
 	decodingOpts                         msgpack.DecodingOptions
 	filePathPrefix                       string
+	secondaryTierFilePathPrefix          string
 	newFileMode                          os.FileMode
 	newDirectoryMode                     os.FileMode
 	indexSummariesPercent                float64
@@ -109,6 +116,8 @@ type options struct {
 	mmapReporter                         mmap.Reporter
 	indexReaderAutovalidateIndexSegments bool
 	encodingOptions                      msgpack.LegacyEncodingOptions
+	encryptionCipher                     encryption.Cipher
+	encryptionEnabled                    bool
 }
 
 // NewOptions creates a new set of fs options
@@ -166,6 +175,13 @@ func (o *options) Validate() error {
 	if o.tagDecoderPool == nil {
 		return errTagDecoderPoolNotSet
 	}
+	if o.secondaryTierFilePathPrefix != "" &&
+		filepath.Clean(o.secondaryTierFilePathPrefix) == filepath.Clean(o.filePathPrefix) {
+		return errSecondaryTierFilePathPrefixInvalid
+	}
+	if o.encryptionEnabled && o.encryptionCipher == nil {
+		return errEncryptionCipherNotSet
+	}
 	return nil
 }
 
@@ -219,6 +235,16 @@ func (o *options) FilePathPrefix() string {
 	return o.filePathPrefix
 }
 
+func (o *options) SetSecondaryTierFilePathPrefix(value string) Options {
+	opts := *o
+	opts.secondaryTierFilePathPrefix = value
+	return &opts
+}
+
+func (o *options) SecondaryTierFilePathPrefix() string {
+	return o.secondaryTierFilePathPrefix
+}
+
 func (o *options) SetNewFileMode(value os.FileMode) Options {
 	opts := *o
 	opts.newFileMode = value
@@ -408,3 +434,23 @@ func (o *options) SetEncodingOptions(value msgpack.LegacyEncodingOptions) Option
 func (o *options) EncodingOptions() msgpack.LegacyEncodingOptions {
 	return o.encodingOptions
 }
+
+func (o *options) SetEncryptionCipher(value encryption.Cipher) Options {
+	opts := *o
+	opts.encryptionCipher = value
+	return &opts
+}
+
+func (o *options) EncryptionCipher() encryption.Cipher {
+	return o.encryptionCipher
+}
+
+func (o *options) SetEncryptionEnabled(value bool) Options {
+	opts := *o
+	opts.encryptionEnabled = value
+	return &opts
+}
+
+func (o *options) EncryptionEnabled() bool {
+	return o.encryptionEnabled
+}
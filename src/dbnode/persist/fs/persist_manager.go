@@ -496,7 +496,8 @@ func (pm *persistManager) PrepareData(opts persist.DataPrepareOptions) (persist.
 
 	blockSize := nsMetadata.Options().RetentionOptions().BlockSize()
 	dataWriterOpts := DataWriterOpenOptions{
-		BlockSize: blockSize,
+		BlockSize:   blockSize,
+		Compression: nsMetadata.Options().FileSetCompression(),
 		Snapshot: DataWriterSnapshotOptions{
 			SnapshotTime: snapshotTime,
 			SnapshotID:   snapshotID,
@@ -31,6 +31,7 @@ import (
 
 	"github.com/m3db/m3/src/dbnode/digest"
 	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/compression"
 	"github.com/m3db/m3/src/dbnode/persist/fs/msgpack"
 	"github.com/m3db/m3/src/dbnode/persist/schema"
 	"github.com/m3db/m3/src/x/checked"
@@ -51,6 +52,8 @@ var (
 	// errReadNotExpectedSize returned when the size of the next read does not match size specified by the index
 	errReadNotExpectedSize = errors.New("next read not expected size")
 
+	errReadDataDigestMismatch = errors.New("data file digest does not match expected digest")
+
 	errUnexpectedSortByOffset = errors.New("should not sort index by offsets when doing reads sorted by id")
 
 	errStreamingRequired    = errors.New("streaming must be enabled for streaming read methods")
@@ -85,6 +88,10 @@ type reader struct {
 	dataMmap   mmap.Descriptor
 	dataReader digest.ReaderWithDigest
 
+	compression    compression.Type
+	encrypted      bool
+	compressedData *compressedPageReader
+
 	bloomFilterFd *os.File
 
 	entries         int
@@ -219,7 +226,9 @@ func (r *reader) Open(opts DataReaderOpenOptions) error {
 		r.digestFdWithDigestContents.Close()
 	}()
 
-	result, err := mmap.Files(os.Open, map[string]mmap.FileDesc{
+	// NB: the index and data files may have been offloaded to the secondary tier.
+	opener := tieredFileOpener(r.filePathPrefix, r.opts.SecondaryTierFilePathPrefix())
+	result, err := mmap.Files(mmap.FileOpener(opener), map[string]mmap.FileDesc{
 		indexFilepath: {
 			File:       &r.indexFd,
 			Descriptor: &r.indexMmap,
@@ -275,6 +284,10 @@ func (r *reader) Open(opts DataReaderOpenOptions) error {
 		r.Close()
 		return err
 	}
+	if err := r.openCompressedData(); err != nil {
+		r.Close()
+		return err
+	}
 	if opts.StreamingEnabled {
 		r.decoder.Reset(r.indexDecoderStream)
 	} else if err := r.readIndexAndSortByOffsetAsc(); err != nil {
@@ -291,12 +304,14 @@ func (r *reader) Open(opts DataReaderOpenOptions) error {
 
 func (r *reader) Status() DataFileSetReaderStatus {
 	return DataFileSetReaderStatus{
-		Open:       r.open,
-		Namespace:  r.namespace,
-		Shard:      r.shard,
-		Volume:     r.volume,
-		BlockStart: r.start,
-		BlockSize:  r.blockSize,
+		Open:        r.open,
+		Namespace:   r.namespace,
+		Shard:       r.shard,
+		Volume:      r.volume,
+		BlockStart:  r.start,
+		BlockSize:   r.blockSize,
+		Compression: r.compression,
+		Encrypted:   r.encrypted,
 	}
 }
 
@@ -339,6 +354,25 @@ func (r *reader) readInfo(size int) error {
 	r.entriesRead = 0
 	r.metadataRead = 0
 	r.bloomFilterInfo = info.BloomFilter
+	r.compression, err = dataCompression(info)
+	r.encrypted = dataEncrypted(info)
+	return err
+}
+
+func (r *reader) openCompressedData() error {
+	codec, paged, err := newDataPageCodec(r.compression, r.encrypted, r.opts.EncryptionCipher())
+	if err != nil || !paged {
+		return err
+	}
+
+	data := r.dataMmap.Bytes
+	index, err := readCompressedPageIndex(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return err
+	}
+
+	r.compressedData = newCompressedPageReader(data, codec, index)
+	r.dataReader.Reset(r.compressedData)
 	return nil
 }
 
@@ -376,12 +410,20 @@ func (r *reader) StreamingRead() (StreamedDataEntry, error) {
 		return StreamedDataEntry{}, err
 	}
 
-	if entry.Offset+entry.Size > int64(len(r.dataMmap.Bytes)) {
-		return StreamedDataEntry{}, fmt.Errorf(
-			"attempt to read beyond data file size (offset=%d, size=%d, file size=%d)",
-			entry.Offset, entry.Size, len(r.dataMmap.Bytes))
+	var data []byte
+	if r.compressedData != nil {
+		data, err = r.compressedData.readEntry(entry.Offset, entry.Size)
+		if err != nil {
+			return StreamedDataEntry{}, err
+		}
+	} else {
+		if entry.Offset+entry.Size > int64(len(r.dataMmap.Bytes)) {
+			return StreamedDataEntry{}, fmt.Errorf(
+				"attempt to read beyond data file size (offset=%d, size=%d, file size=%d)",
+				entry.Offset, entry.Size, len(r.dataMmap.Bytes))
+		}
+		data = r.dataMmap.Bytes[entry.Offset : entry.Offset+entry.Size]
 	}
-	data := r.dataMmap.Bytes[entry.Offset : entry.Offset+entry.Size]
 
 	// NB(r): _must_ check the checksum against known checksum as the data
 	// file might not have been verified if we haven't read through the file yet.
@@ -558,7 +600,16 @@ func (r *reader) ValidateMetadata() error {
 // NB(xichen): ValidateData should be called after all data is read because
 // the digest is calculated for the entire data file.
 func (r *reader) ValidateData() error {
-	err := r.dataReader.Validate(r.expectedDataDigest)
+	var err error
+	if r.compressedData != nil {
+		// NB: The digest of compressed data files is calculated over the
+		// compressed contents rather than the data read.
+		if digest.Checksum(r.dataMmap.Bytes) != r.expectedDataDigest {
+			err = errReadDataDigestMismatch
+		}
+	} else {
+		err = r.dataReader.Validate(r.expectedDataDigest)
+	}
 	if err != nil {
 		return fmt.Errorf("could not validate data file: %v", err)
 	}
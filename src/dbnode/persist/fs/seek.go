@@ -77,6 +77,12 @@ type seeker struct {
 	indexFd       *os.File
 	indexFileSize int64
 
+	// Codec and page index of the data file, only set if it is compressed or
+	// encrypted.
+	dataPaged bool
+	dataCodec dataPageCodec
+	dataPages compressedPageIndex
+
 	unreadBuf []byte
 
 	// Bloom filter associated with the shard / block the seeker is responsible
@@ -172,8 +178,10 @@ func (s *seeker) Open(
 		}
 	}
 
-	// Open necessary files
-	if err := openFiles(os.Open, map[string]**os.File{
+	// Open necessary files, the index and data files may have been offloaded
+	// to the secondary tier.
+	opener := tieredFileOpener(s.opts.filePathPrefix, s.opts.opts.SecondaryTierFilePathPrefix())
+	if err := openFiles(opener, map[string]**os.File{
 		dataFilesetPathFromTimeAndIndex(shardDir, blockStart, volumeIndex, infoFileSuffix, isLegacy):        &infoFd,
 		dataFilesetPathFromTimeAndIndex(shardDir, blockStart, volumeIndex, indexFileSuffix, isLegacy):       &s.indexFd,
 		dataFilesetPathFromTimeAndIndex(shardDir, blockStart, volumeIndex, dataFileSuffix, isLegacy):        &s.dataFd,
@@ -231,6 +239,11 @@ func (s *seeker) Open(
 	s.blockSize = time.Duration(info.BlockSize)
 	s.versionChecker = schema.NewVersionChecker(int(info.MajorVersion), int(info.MinorVersion))
 
+	if err := s.openCompressedData(info); err != nil {
+		s.Close()
+		return err
+	}
+
 	err = s.validateIndexFileDigest(
 		indexFdWithDigest, expectedDigests.indexDigest)
 	if err != nil {
@@ -290,6 +303,26 @@ func (s *seeker) Open(
 	return err
 }
 
+func (s *seeker) openCompressedData(info schema.IndexInfo) error {
+	dataCompression, err := dataCompression(info)
+	if err != nil {
+		return err
+	}
+	s.dataCodec, s.dataPaged, err = newDataPageCodec(dataCompression,
+		dataEncrypted(info), s.opts.opts.EncryptionCipher())
+	if err != nil || !s.dataPaged {
+		return err
+	}
+
+	dataStat, err := s.dataFd.Stat()
+	if err != nil {
+		return err
+	}
+
+	s.dataPages, err = readCompressedPageIndex(s.dataFd, dataStat.Size())
+	return err
+}
+
 func (s *seeker) prepareUnreadBuf(size int) {
 	if len(s.unreadBuf) < size {
 		// NB(r): Make a little larger so unlikely to occur multiple times
@@ -339,6 +372,10 @@ func (s *seeker) SeekByIndexEntry(
 	entry IndexEntry,
 	resources ReusableSeekerResources,
 ) (checked.Bytes, error) {
+	if s.dataPaged {
+		return s.seekCompressedByIndexEntry(entry, resources)
+	}
+
 	resources.offsetFileReader.reset(s.dataFd, entry.Offset)
 
 	// Obtain an appropriately sized buffer.
@@ -376,6 +413,57 @@ func (s *seeker) SeekByIndexEntry(
 	return buffer, nil
 }
 
+func (s *seeker) seekCompressedByIndexEntry(
+	entry IndexEntry,
+	resources ReusableSeekerResources,
+) (checked.Bytes, error) {
+	i, err := s.dataPages.find(entry.Offset, int64(entry.Size))
+	if err != nil {
+		return nil, err
+	}
+
+	buffers := resources.compressedPageBuffers
+	if buffers == nil {
+		buffers = &compressedPageBuffers{}
+	}
+
+	page := s.dataPages[i]
+	compressed := buffers.compressedBuf(int(page.fileSize))
+	if _, err := s.dataFd.ReadAt(compressed, page.fileOffset); err != nil {
+		return nil, err
+	}
+
+	data, err := s.dataCodec.decode(&buffers.decoded, compressed)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) != int(page.size) {
+		return nil, fmt.Errorf("decoded page size mismatch: expected=%d, actual=%d",
+			page.size, len(data))
+	}
+
+	start := entry.Offset - page.offset
+	underlyingBuf := data[start : start+int64(entry.Size)]
+
+	// NB(r): _must_ check the checksum against known checksum as the data
+	// file might not have been verified if we haven't read through the file yet.
+	if entry.DataChecksum != digest.Checksum(underlyingBuf) {
+		return nil, errSeekChecksumMismatch
+	}
+
+	var buffer checked.Bytes
+	if s.opts.bytesPool != nil {
+		buffer = s.opts.bytesPool.Get(int(entry.Size))
+	} else {
+		buffer = checked.NewBytes(make([]byte, 0, entry.Size), nil)
+	}
+	buffer.IncRef()
+	buffer.AppendAll(underlyingBuf)
+	buffer.DecRef()
+
+	return buffer, nil
+}
+
 // SeekIndexEntry performs the following steps:
 //
 //     1. Go to the indexLookup and it will give us an offset that is a good starting
@@ -615,6 +703,11 @@ func (s *seeker) ConcurrentClone() (ConcurrentDataFileSetSeeker, error) {
 		indexFd: s.indexFd,
 		dataFd:  s.dataFd,
 
+		// The data codec and page index are immutable once opened.
+		dataPaged: s.dataPaged,
+		dataCodec: s.dataCodec,
+		dataPages: s.dataPages,
+
 		versionChecker: s.versionChecker,
 	}
 
@@ -661,10 +754,24 @@ type ReusableSeekerResources struct {
 	// since the ReusableSeekerResources is only ever used by a single seeker at
 	// a time, we can size this pool such that it almost never has to allocate.
 	decodeIndexEntryBytesPool pool.BytesPool
+	// Buffers used when seeking compressed or encrypted data files.
+	compressedPageBuffers *compressedPageBuffers
 
 	seekerOpenResources reusableSeekerOpenResources
 }
 
+type compressedPageBuffers struct {
+	compressed []byte
+	decoded    dataPageBuffers
+}
+
+func (b *compressedPageBuffers) compressedBuf(size int) []byte {
+	if cap(b.compressed) < size {
+		b.compressed = make([]byte, size)
+	}
+	return b.compressed[:size]
+}
+
 // reusableSeekerOpenResources contains resources used for the Open() method of the seeker.
 type reusableSeekerOpenResources struct {
 	infoFDDigestReader           digest.FdWithDigestReader
@@ -694,6 +801,7 @@ func NewReusableSeekerResources(opts Options) ReusableSeekerResources {
 		byteDecoderStream:         xmsgpack.NewByteDecoderStream(nil),
 		offsetFileReader:          newOffsetFileReader(),
 		decodeIndexEntryBytesPool: newSimpleBytesPool(),
+		compressedPageBuffers:     &compressedPageBuffers{},
 		seekerOpenResources:       newReusableSeekerOpenResources(opts),
 	}
 }
@@ -28,6 +28,7 @@ import (
 	"time"
 
 	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/compression"
 	"github.com/m3db/m3/src/dbnode/ts"
 	"github.com/m3db/m3/src/x/ident"
 	xtime "github.com/m3db/m3/src/x/time"
@@ -58,6 +59,8 @@ type StreamingWriterOpenOptions struct {
 	BlockStart  xtime.UnixNano
 	BlockSize   time.Duration
 	VolumeIndex int
+	// Compression is the compression applied to the data file.
+	Compression compression.Type
 
 	// PlannedRecordsCount is an estimate of the number of series to be written.
 	// Must be greater than 0.
@@ -101,6 +104,7 @@ func (w *streamingWriter) Open(opts StreamingWriterOpenOptions) error {
 			VolumeIndex: opts.VolumeIndex,
 		},
 		FileSetType: persist.FileSetFlushType,
+		Compression: opts.Compression,
 	}
 
 	plannedRecordsCount := opts.PlannedRecordsCount
@@ -174,6 +178,9 @@ func (w *streamingWriter) writeData(
 		size:           uint32(size),
 		dataChecksum:   dataChecksum,
 	}
+	if err := w.writer.startData(size); err != nil {
+		return indexEntry{}, false, err
+	}
 	for _, d := range data {
 		if err := w.writer.writeData(d); err != nil {
 			return indexEntry{}, false, err
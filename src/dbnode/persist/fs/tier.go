@@ -0,0 +1,207 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fs
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	xerrors "github.com/m3db/m3/src/x/errors"
+)
+
+const offloadTempFileSuffix = ".tmp"
+
+// offloadedFileSuffixes are the suffixes of the files of a data fileset that
+// are offloaded to the secondary tier. The remaining files of a fileset are
+// small and stay on the primary tier so that filesets can still be listed,
+// validated and bootstrapped without accessing the secondary tier.
+var offloadedFileSuffixes = []string{indexFileSuffix, dataFileSuffix}
+
+// SecondaryTierFilePath returns the path on the secondary tier of a file
+// with the given path on the primary tier.
+func SecondaryTierFilePath(
+	filePathPrefix string,
+	secondaryTierFilePathPrefix string,
+	filePath string,
+) (string, error) {
+	rel, err := filepath.Rel(filePathPrefix, filePath)
+	if err != nil {
+		return "", err
+	}
+	if rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("file %s is not within the file path prefix %s",
+			filePath, filePathPrefix)
+	}
+	return filepath.Join(secondaryTierFilePathPrefix, rel), nil
+}
+
+// tieredFileOpener returns a file opener that opens files from the primary
+// tier, falling back to the secondary tier for files that do not exist on
+// the primary tier since they have been offloaded.
+func tieredFileOpener(
+	filePathPrefix string,
+	secondaryTierFilePathPrefix string,
+) fileOpener {
+	if secondaryTierFilePathPrefix == "" {
+		return os.Open
+	}
+	return func(filePath string) (*os.File, error) {
+		fd, err := os.Open(filePath) //nolint:gosec
+		if err == nil || !os.IsNotExist(err) {
+			return fd, err
+		}
+
+		secondaryFilePath, secondaryErr := SecondaryTierFilePath(
+			filePathPrefix, secondaryTierFilePathPrefix, filePath)
+		if secondaryErr != nil {
+			// Not a fileset file, return the original error.
+			return nil, err
+		}
+		return os.Open(secondaryFilePath) //nolint:gosec
+	}
+}
+
+// offloadedFilePaths returns the paths of the files of a data fileset that
+// are offloaded, derived from the path of its checkpoint file.
+func offloadedFilePaths(checkpointFilePath string) []string {
+	base := strings.TrimSuffix(checkpointFilePath, checkpointFileSuffix+fileSuffix)
+	result := make([]string, 0, len(offloadedFileSuffixes))
+	for _, suffix := range offloadedFileSuffixes {
+		result = append(result, base+suffix+fileSuffix)
+	}
+	return result
+}
+
+// SecondaryTierFilePaths returns the paths of the files on the secondary tier
+// that were offloaded from the data filesets of the given primary tier file
+// paths, only files that exist on the secondary tier are returned.
+func SecondaryTierFilePaths(opts Options, filePaths []string) ([]string, error) {
+	secondaryPrefix := opts.SecondaryTierFilePathPrefix()
+	if secondaryPrefix == "" {
+		return nil, nil
+	}
+
+	var result []string
+	for _, filePath := range filePaths {
+		if !strings.HasSuffix(filePath, checkpointFileSuffix+fileSuffix) {
+			continue
+		}
+		for _, offloaded := range offloadedFilePaths(filePath) {
+			secondaryFilePath, err := SecondaryTierFilePath(
+				opts.FilePathPrefix(), secondaryPrefix, offloaded)
+			if err != nil {
+				return nil, err
+			}
+			exists, err := FileExists(secondaryFilePath)
+			if err != nil {
+				return nil, err
+			}
+			if exists {
+				result = append(result, secondaryFilePath)
+			}
+		}
+	}
+	return result, nil
+}
+
+// OffloadDataFileSet moves the index and data files of a complete data fileset
+// from the primary tier to the secondary tier, returning whether any files
+// were moved. Files are copied and synced to the secondary tier before being
+// removed from the primary tier so that a fileset is always readable from at
+// least one of the tiers.
+func OffloadDataFileSet(opts Options, fileset FileSetFile) (bool, error) {
+	secondaryPrefix := opts.SecondaryTierFilePathPrefix()
+	if secondaryPrefix == "" {
+		return false, nil
+	}
+	if !fileset.HasCompleteCheckpointFile() {
+		return false, nil
+	}
+	checkpointFilePath, ok := fileset.filepath(checkpointFileSuffix)
+	if !ok {
+		return false, nil
+	}
+
+	moved := false
+	for _, filePath := range offloadedFilePaths(checkpointFilePath) {
+		exists, err := FileExists(filePath)
+		if err != nil {
+			return moved, err
+		}
+		if !exists {
+			// Already offloaded.
+			continue
+		}
+
+		secondaryFilePath, err := SecondaryTierFilePath(
+			opts.FilePathPrefix(), secondaryPrefix, filePath)
+		if err != nil {
+			return moved, err
+		}
+		if err := copyFileSync(filePath, secondaryFilePath, opts); err != nil {
+			return moved, err
+		}
+		if err := os.Remove(filePath); err != nil {
+			return moved, err
+		}
+		moved = true
+	}
+	return moved, nil
+}
+
+// copyFileSync copies a file to a temporary file at the destination which is
+// synced and then renamed to the destination path.
+func copyFileSync(srcPath, dstPath string, opts Options) error {
+	dir := filepath.Dir(dstPath)
+	if err := os.MkdirAll(dir, opts.NewDirectoryMode()); err != nil {
+		return err
+	}
+
+	src, err := os.Open(srcPath) //nolint:gosec
+	if err != nil {
+		return err
+	}
+	defer src.Close() //nolint:errcheck
+
+	tmpPath := dstPath + offloadTempFileSuffix
+	dst, err := OpenWritable(tmpPath, opts.NewFileMode())
+	if err != nil {
+		return err
+	}
+
+	_, copyErr := io.Copy(dst, src)
+	if err := xerrors.FirstError(copyErr, dst.Sync(), dst.Close()); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Rename(tmpPath, dstPath); err != nil {
+		return err
+	}
+
+	dirFd, err := os.Open(dir) //nolint:gosec
+	if err != nil {
+		return err
+	}
+	return xerrors.FirstError(dirFd.Sync(), dirFd.Close())
+}
@@ -0,0 +1,115 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fs
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/x/ident"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecondaryTierFilePath(t *testing.T) {
+	path, err := SecondaryTierFilePath("/var/lib/m3db", "/mnt/cold",
+		"/var/lib/m3db/data/ns/0/fileset-0-0-data.db")
+	require.NoError(t, err)
+	assert.Equal(t, "/mnt/cold/data/ns/0/fileset-0-0-data.db", path)
+
+	_, err = SecondaryTierFilePath("/var/lib/m3db", "/mnt/cold",
+		"/var/lib/other/data/ns/0/fileset-0-0-data.db")
+	assert.Error(t, err)
+}
+
+func TestOffloadDataFileSet(t *testing.T) {
+	dir := createTempDir(t)
+	defer os.RemoveAll(dir)
+	var (
+		filePathPrefix = filepath.Join(dir, "primary")
+		secondaryDir   = filepath.Join(dir, "secondary")
+		opts           = testDefaultOpts.
+				SetFilePathPrefix(filePathPrefix).
+				SetSecondaryTierFilePathPrefix(secondaryDir)
+	)
+
+	entries := []testEntry{
+		{"foo", nil, []byte{1, 2, 3}},
+		{"bar", nil, []byte{4, 5, 6}},
+		{"baz", nil, make([]byte, 65536)},
+	}
+	w := newTestWriter(t, filePathPrefix)
+	writeTestData(t, w, 0, testWriterStart, entries, persist.FileSetFlushType)
+
+	filesets, err := DataFiles(filePathPrefix, testNs1ID, 0)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(filesets))
+
+	offloaded, err := OffloadDataFileSet(opts, filesets[0])
+	require.NoError(t, err)
+	require.True(t, offloaded)
+
+	// The data and index files are no longer on the primary tier.
+	filesets, err = DataFiles(filePathPrefix, testNs1ID, 0)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(filesets))
+	for _, path := range filesets[0].AbsoluteFilePaths {
+		assert.NotContains(t, path, dataFileSuffix+fileSuffix)
+		assert.NotContains(t, path, indexFileSuffix+fileSuffix)
+	}
+
+	secondaryPaths, err := SecondaryTierFilePaths(opts, filesets.Filepaths())
+	require.NoError(t, err)
+	require.Equal(t, 2, len(secondaryPaths))
+
+	// Offloading an offloaded fileset is a no-op.
+	offloaded, err = OffloadDataFileSet(opts, filesets[0])
+	require.NoError(t, err)
+	require.False(t, offloaded)
+
+	// Reads transparently fall back to the secondary tier.
+	reader, err := NewReader(testBytesPool, opts.
+		SetInfoReaderBufferSize(testReaderBufferSize).
+		SetDataReaderBufferSize(testReaderBufferSize))
+	require.NoError(t, err)
+	readTestData(t, reader, 0, testWriterStart, entries)
+
+	resources := newTestReusableSeekerResources()
+	seeker := NewSeeker(filePathPrefix, testReaderBufferSize,
+		testReaderBufferSize, testBytesPool, false, opts)
+	require.NoError(t, seeker.Open(testNs1ID, 0, testWriterStart, 0, resources))
+	for _, entry := range entries {
+		data, err := seeker.SeekByID(ident.StringID(entry.id), resources)
+		require.NoError(t, err)
+		data.IncRef()
+		assert.True(t, bytes.Equal(entry.data, data.Bytes()))
+		data.DecRef()
+	}
+	require.NoError(t, seeker.Close())
+
+	// Without the secondary tier the fileset can no longer be read.
+	seeker = newTestSeeker(filePathPrefix)
+	require.Error(t, seeker.Open(testNs1ID, 0, testWriterStart, 0, resources))
+}
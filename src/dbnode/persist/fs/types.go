@@ -29,6 +29,8 @@ import (
 	"github.com/m3db/m3/src/dbnode/encoding"
 	"github.com/m3db/m3/src/dbnode/namespace"
 	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/compression"
+	"github.com/m3db/m3/src/dbnode/persist/encryption"
 	"github.com/m3db/m3/src/dbnode/persist/fs/msgpack"
 	"github.com/m3db/m3/src/dbnode/persist/schema"
 	"github.com/m3db/m3/src/dbnode/runtime"
@@ -74,6 +76,8 @@ type DataWriterOpenOptions struct {
 	BlockSize          time.Duration
 	// Only used when writing snapshot files
 	Snapshot DataWriterSnapshotOptions
+	// Compression is the compression applied to the data file.
+	Compression compression.Type
 }
 
 // DataWriterSnapshotOptions is the options struct for Open method on the DataFileSetWriter
@@ -117,12 +121,14 @@ type SnapshotMetadataFileReader interface {
 
 // DataFileSetReaderStatus describes the status of a file set reader.
 type DataFileSetReaderStatus struct {
-	Namespace  ident.ID
-	BlockStart xtime.UnixNano
-	Shard      uint32
-	Volume     int
-	Open       bool
-	BlockSize  time.Duration
+	Namespace   ident.ID
+	BlockStart  xtime.UnixNano
+	Shard       uint32
+	Volume      int
+	Open        bool
+	BlockSize   time.Duration
+	Compression compression.Type
+	Encrypted   bool
 }
 
 // DataReaderOpenOptions is options struct for the reader open method.
@@ -432,6 +438,16 @@ type Options interface {
 	// FilePathPrefix returns the file path prefix for sharded TSDB files.
 	FilePathPrefix() string
 
+	// SetSecondaryTierFilePathPrefix sets the file path prefix of the secondary
+	// storage tier that the data and index files of old data filesets are
+	// offloaded to, an empty prefix disables the secondary tier.
+	SetSecondaryTierFilePathPrefix(value string) Options
+
+	// SecondaryTierFilePathPrefix returns the file path prefix of the secondary
+	// storage tier that the data and index files of old data filesets are
+	// offloaded to, an empty prefix disables the secondary tier.
+	SecondaryTierFilePathPrefix() string
+
 	// SetNewFileMode sets the new file mode.
 	SetNewFileMode(value os.FileMode) Options
 
@@ -555,6 +571,20 @@ type Options interface {
 
 	// EncodingOptions returns the encoder options used by the encoder.
 	EncodingOptions() msgpack.LegacyEncodingOptions
+
+	// SetEncryptionCipher sets the cipher used to decrypt encrypted data and,
+	// if encryption is enabled, to encrypt newly written data.
+	SetEncryptionCipher(value encryption.Cipher) Options
+
+	// EncryptionCipher returns the cipher used to decrypt encrypted data and,
+	// if encryption is enabled, to encrypt newly written data.
+	EncryptionCipher() encryption.Cipher
+
+	// SetEncryptionEnabled sets whether newly written data is encrypted.
+	SetEncryptionEnabled(value bool) Options
+
+	// EncryptionEnabled returns whether newly written data is encrypted.
+	EncryptionEnabled() bool
 }
 
 // BlockRetrieverOptions represents the options for block retrieval.
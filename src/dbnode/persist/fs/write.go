@@ -31,6 +31,8 @@ import (
 
 	"github.com/m3db/m3/src/dbnode/digest"
 	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/compression"
+	"github.com/m3db/m3/src/dbnode/persist/encryption"
 	"github.com/m3db/m3/src/dbnode/persist/fs/msgpack"
 	"github.com/m3db/m3/src/dbnode/persist/schema"
 	"github.com/m3db/m3/src/dbnode/ts"
@@ -75,6 +77,11 @@ type writer struct {
 	volumeIndex  int
 	snapshotTime xtime.UnixNano
 	snapshotID   uuid.UUID
+	compression  compression.Type
+	encrypted    bool
+	cipher       encryption.Cipher
+	paged        bool
+	dataPages    compressedPageWriter
 
 	currIdx            int64
 	currOffset         int64
@@ -149,6 +156,8 @@ func NewWriter(opts Options) (DataFileSetWriter, error) {
 		singleCheckedBytes:              make([]checked.Bytes, 1),
 		tagsIterator:                    ident.NewTagsIterator(ident.Tags{}),
 		tagEncoderPool:                  opts.TagEncoderPool(),
+		encrypted:                       opts.EncryptionEnabled(),
+		cipher:                          opts.EncryptionCipher(),
 	}, nil
 }
 
@@ -229,6 +238,15 @@ func (w *writer) Open(opts DataWriterOpenOptions) error {
 	w.dataFdWithDigest.Reset(dataFd)
 	w.digestFdWithDigestContents.Reset(digestFd)
 
+	codec, paged, err := newDataPageCodec(w.compression, w.encrypted, w.cipher)
+	if err != nil {
+		return err
+	}
+	if paged {
+		w.dataPages.reset(w.dataFdWithDigest, codec)
+		w.paged = true
+	}
+
 	return nil
 }
 
@@ -238,6 +256,8 @@ func (w *writer) reset(opts DataWriterOpenOptions) {
 	w.volumeIndex = opts.Identifier.VolumeIndex
 	w.snapshotTime = opts.Snapshot.SnapshotTime
 	w.snapshotID = opts.Snapshot.SnapshotID
+	w.compression = opts.Compression
+	w.paged = false
 	w.currIdx = 0
 	w.currOffset = 0
 	w.err = nil
@@ -248,10 +268,23 @@ func (w *writer) reset(opts DataWriterOpenOptions) {
 	w.indexEntries = w.indexEntries[:0]
 }
 
+// startData must be called before writing the data of each series.
+func (w *writer) startData(size int64) error {
+	if !w.paged {
+		return nil
+	}
+	return w.dataPages.startEntry(size)
+}
+
 func (w *writer) writeData(data []byte) error {
 	if len(data) == 0 {
 		return nil
 	}
+	if w.paged {
+		w.dataPages.write(data)
+		w.currOffset += int64(len(data))
+		return nil
+	}
 	written, err := w.dataFdWithDigest.Write(data)
 	if err != nil {
 		return err
@@ -310,6 +343,9 @@ func (w *writer) writeAll(
 		},
 		metadata: metadata,
 	}
+	if err := w.startData(size); err != nil {
+		return err
+	}
 	for _, d := range data {
 		if d == nil {
 			continue
@@ -379,6 +415,12 @@ func (w *writer) close() error {
 }
 
 func (w *writer) closeWOIndex() error {
+	if w.paged && w.dataPages.w != nil {
+		if err := w.dataPages.close(); err != nil {
+			return err
+		}
+	}
+
 	if err := w.digestFdWithDigestContents.WriteDigests(
 		w.infoFdWithDigest.Digest().Sum32(),
 		w.indexFdWithDigest.Digest().Sum32(),
@@ -617,6 +659,8 @@ func (w *writer) writeInfoFileContents(
 		Entries:      entriesCount,
 		MajorVersion: schema.MajorVersion,
 		MinorVersion: schema.MinorVersion,
+		Compression:  w.compression,
+		Encrypted:    w.encrypted,
 		Summaries: schema.IndexSummariesInfo{
 			Summaries: int64(summaries),
 		},
@@ -22,6 +22,7 @@ package schema
 
 import (
 	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/compression"
 	"github.com/m3db/m3/src/dbnode/ts"
 	"github.com/m3db/m3/src/x/ident"
 )
@@ -34,7 +35,9 @@ const MajorVersion = 1
 // MinorVersion is the minor schema version for a set of fileset files.
 // This is only incremented when *non-breaking* changes are introduced that
 // we want to have some level of control around how they're rolled out.
-const MinorVersion = 1
+// Minor version 2 introduced optional compression of data files.
+// Minor version 3 introduced optional encryption of data files.
+const MinorVersion = 3
 
 // IndexInfo stores metadata information about block filesets.
 type IndexInfo struct {
@@ -49,6 +52,8 @@ type IndexInfo struct {
 	SnapshotID   []byte
 	VolumeIndex  int
 	MinorVersion int64
+	Compression  compression.Type
+	Encrypted    bool
 }
 
 // IndexSummariesInfo stores metadata about the summaries.
@@ -41,3 +41,15 @@ func NewVersionChecker(majorVersion int, minorVersion int) VersionChecker {
 func (v *VersionChecker) IndexEntryValidationEnabled() bool {
 	return v.majorVersion >= 2 || v.majorVersion == 1 && v.minorVersion >= 1
 }
+
+// DataCompressionEnabled checks the version to determine if fileset files
+// of the specified version may have compressed data files.
+func (v *VersionChecker) DataCompressionEnabled() bool {
+	return v.majorVersion >= 2 || v.majorVersion == 1 && v.minorVersion >= 2
+}
+
+// DataEncryptionEnabled checks the version to determine if fileset files
+// of the specified version may have encrypted data files.
+func (v *VersionChecker) DataEncryptionEnabled() bool {
+	return v.majorVersion >= 2 || v.majorVersion == 1 && v.minorVersion >= 3
+}
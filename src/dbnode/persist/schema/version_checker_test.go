@@ -44,3 +44,25 @@ func TestIndexEntryValidationDisabled(t *testing.T) {
 	checker := NewVersionChecker(1, 0)
 	require.False(t, checker.IndexEntryValidationEnabled())
 }
+
+func TestDataCompressionEnabled(t *testing.T) {
+	checker := NewVersionChecker(1, 2)
+	require.True(t, checker.DataCompressionEnabled())
+
+	checker = NewVersionChecker(2, 0)
+	require.True(t, checker.DataCompressionEnabled())
+
+	checker = NewVersionChecker(1, 1)
+	require.False(t, checker.DataCompressionEnabled())
+}
+
+func TestDataEncryptionEnabled(t *testing.T) {
+	checker := NewVersionChecker(1, 3)
+	require.True(t, checker.DataEncryptionEnabled())
+
+	checker = NewVersionChecker(2, 0)
+	require.True(t, checker.DataEncryptionEnabled())
+
+	checker = NewVersionChecker(1, 2)
+	require.False(t, checker.DataEncryptionEnabled())
+}
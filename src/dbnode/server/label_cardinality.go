@@ -0,0 +1,89 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package server
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/m3db/m3/src/dbnode/storage"
+	"github.com/m3db/m3/src/dbnode/storage/limits"
+	xhttp "github.com/m3db/m3/src/x/net/http"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// labelCardinalityDebugURL is the debug endpoint reporting the cardinality
+	// of the labels tracked by the label cardinality limits.
+	labelCardinalityDebugURL = "/debug/index/label-cardinality"
+
+	labelCardinalityNamespaceParam = "namespace"
+)
+
+var errLabelCardinalityNamespaceNotFound = errors.New("namespace not found or not indexed")
+
+type labelCardinalityHandler struct {
+	db     storage.Database
+	logger *zap.Logger
+}
+
+// labelCardinalityResponse holds the tracked labels keyed by namespace.
+type labelCardinalityResponse struct {
+	Namespaces map[string][]limits.LabelCardinality `json:"namespaces"`
+}
+
+func newLabelCardinalityHandler(db storage.Database, logger *zap.Logger) http.Handler {
+	return &labelCardinalityHandler{db: db, logger: logger}
+}
+
+// ServeHTTP reports the cardinality of the labels tracked for each indexed
+// namespace, or for a single namespace if given by the namespace parameter.
+func (h *labelCardinalityHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	filter := r.URL.Query().Get(labelCardinalityNamespaceParam)
+	resp := labelCardinalityResponse{
+		Namespaces: make(map[string][]limits.LabelCardinality),
+	}
+	for _, ns := range h.db.Namespaces() {
+		id := ns.ID().String()
+		if filter != "" && filter != id {
+			continue
+		}
+		idx, err := ns.Index()
+		if err != nil {
+			// Namespace is not indexed.
+			continue
+		}
+		labels := idx.LabelCardinality()
+		if labels == nil {
+			labels = []limits.LabelCardinality{}
+		}
+		resp.Namespaces[id] = labels
+	}
+
+	if filter != "" && len(resp.Namespaces) == 0 {
+		xhttp.WriteError(w, xhttp.NewError(errLabelCardinalityNamespaceNotFound,
+			http.StatusNotFound))
+		return
+	}
+
+	xhttp.WriteJSONResponse(w, resp, h.logger)
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/m3db/m3/src/dbnode/storage"
+	"github.com/m3db/m3/src/dbnode/storage/limits"
+	"github.com/m3db/m3/src/x/ident"
+	xtest "github.com/m3db/m3/src/x/test"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestLabelCardinalityHandler(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	labels := []limits.LabelCardinality{
+		{Name: "pod", Values: 5000, Estimated: true, Rejected: 12},
+		{Name: "city", Values: 3},
+	}
+
+	idx := storage.NewMockNamespaceIndex(ctrl)
+	idx.EXPECT().LabelCardinality().Return(labels).AnyTimes()
+
+	indexed := storage.NewMockNamespace(ctrl)
+	indexed.EXPECT().ID().Return(ident.StringID("metrics")).AnyTimes()
+	indexed.EXPECT().Index().Return(idx, nil).AnyTimes()
+
+	unindexed := storage.NewMockNamespace(ctrl)
+	unindexed.EXPECT().ID().Return(ident.StringID("raw")).AnyTimes()
+	unindexed.EXPECT().Index().Return(nil, errors.New("not indexed")).AnyTimes()
+
+	db := storage.NewMockDatabase(ctrl)
+	db.EXPECT().Namespaces().Return([]storage.Namespace{indexed, unindexed}).AnyTimes()
+
+	handler := newLabelCardinalityHandler(db, zap.NewNop())
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, labelCardinalityDebugURL, nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp labelCardinalityResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Equal(t, map[string][]limits.LabelCardinality{"metrics": labels}, resp.Namespaces)
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet,
+		labelCardinalityDebugURL+"?namespace=raw", nil))
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
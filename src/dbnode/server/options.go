@@ -22,10 +22,13 @@ package server
 
 import (
 	"github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/node"
+	"github.com/m3db/m3/src/dbnode/storage"
 )
 
 // StorageOptions are options to apply to the database storage options.
 type StorageOptions struct {
 	TChanChannelFn    node.NewTChanChannelFn
 	TChanNodeServerFn node.NewTChanNodeServerFn
+	// NewTileAggregatorFn overrides the tile aggregator of the database.
+	NewTileAggregatorFn storage.NewTileAggregatorFn
 }
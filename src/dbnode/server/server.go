@@ -52,12 +52,14 @@ import (
 	"github.com/m3db/m3/src/dbnode/environment"
 	"github.com/m3db/m3/src/dbnode/kvconfig"
 	"github.com/m3db/m3/src/dbnode/namespace"
+	grpcnode "github.com/m3db/m3/src/dbnode/network/server/grpc/node"
 	hjcluster "github.com/m3db/m3/src/dbnode/network/server/httpjson/cluster"
 	hjnode "github.com/m3db/m3/src/dbnode/network/server/httpjson/node"
 	"github.com/m3db/m3/src/dbnode/network/server/tchannelthrift"
 	ttcluster "github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/cluster"
 	ttnode "github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/node"
 	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/persist/fs/backup"
 	"github.com/m3db/m3/src/dbnode/persist/fs/commitlog"
 	"github.com/m3db/m3/src/dbnode/ratelimit"
 	"github.com/m3db/m3/src/dbnode/retention"
@@ -477,6 +479,18 @@ func Run(runOpts RunOptions) {
 	if builder := opts.SourceLoggerBuilder(); builder != nil {
 		limitOpts = limitOpts.SetSourceLoggerBuilder(builder)
 	}
+	if quotasConfig := runOpts.Config.Limits.TenantQuotas; quotasConfig != nil {
+		tenantQuotas, err := limits.NewTenantQuotas(quotasConfig.Options(), iOpts)
+		if err != nil {
+			logger.Fatal("could not construct tenant quotas from config", zap.Error(err))
+		}
+		limitOpts = limitOpts.
+			SetTenantQuotas(tenantQuotas).
+			SetTenantTag([]byte(quotasConfig.TenantTag))
+	}
+	if cardinalityConfig := runOpts.Config.Limits.LabelCardinality; cardinalityConfig != nil {
+		limitOpts = limitOpts.SetLabelCardinalityOptions(cardinalityConfig.Options())
+	}
 	opts = opts.SetLimitsOptions(limitOpts)
 
 	seriesReadPermits := permits.NewLookbackLimitPermitsManager(
@@ -617,6 +631,7 @@ func Run(runOpts RunOptions) {
 		SetInstrumentOptions(opts.InstrumentOptions().
 			SetMetricsScope(scope.SubScope("database.fs"))).
 		SetFilePathPrefix(cfg.Filesystem.FilePathPrefixOrDefault()).
+		SetSecondaryTierFilePathPrefix(cfg.Filesystem.SecondaryTierFilePathPrefixOrDefault()).
 		SetNewFileMode(newFileMode).
 		SetNewDirectoryMode(newDirectoryMode).
 		SetWriterBufferSize(cfg.Filesystem.WriteBufferSizeOrDefault()).
@@ -633,6 +648,20 @@ func Run(runOpts RunOptions) {
 		SetIndexBloomFilterFalsePositivePercent(cfg.Filesystem.BloomFilterFalsePositivePercentOrDefault()).
 		SetMmapReporter(mmapReporter)
 
+	if encryptionCfg := cfg.Filesystem.Encryption; encryptionCfg != nil {
+		cipher, err := encryptionCfg.NewCipher()
+		if err != nil {
+			logger.Fatal("could not create encryption cipher", zap.Error(err))
+		}
+		fsopts = fsopts.
+			SetEncryptionCipher(cipher).
+			SetEncryptionEnabled(encryptionCfg.Enabled)
+		if encryptionCfg.Enabled {
+			logger.Info("encrypting commit logs, fileset data, tombstones and exemplars, " +
+				"fileset index files and index segments are not encrypted")
+		}
+	}
+
 	var commitLogQueueSize int
 	cfgCommitLog := cfg.CommitLogOrDefault()
 	specified := cfgCommitLog.Queue.Size
@@ -678,6 +707,7 @@ func Run(runOpts RunOptions) {
 		SetFilesystemOptions(fsopts).
 		SetStrategy(commitlog.StrategyWriteBehind).
 		SetFlushSize(cfgCommitLog.FlushMaxBytes).
+		SetCompression(cfgCommitLog.Compression).
 		SetFlushInterval(cfgCommitLog.FlushEvery).
 		SetBacklogQueueSize(commitLogQueueSize).
 		SetBacklogQueueChannelSize(commitLogQueueChannelSize))
@@ -837,6 +867,17 @@ func Run(runOpts RunOptions) {
 	defer httpjsonNodeClose()
 	logger.Info("node httpjson: listening", zap.String("address", httpListenAddress))
 
+	if grpcListenAddress := cfg.GRPCNodeListenAddress; grpcListenAddress != nil {
+		grpcNodeClose, err := grpcnode.NewServer(service,
+			*grpcListenAddress, contextPool, iOpts).ListenAndServe()
+		if err != nil {
+			logger.Fatal("could not open grpc interface",
+				zap.String("address", *grpcListenAddress), zap.Error(err))
+		}
+		defer grpcNodeClose()
+		logger.Info("node grpc: listening", zap.String("address", *grpcListenAddress))
+	}
+
 	debugListenAddress := cfg.DebugListenAddressOrDefault()
 	if debugListenAddress != "" {
 		var debugWriter xdebug.ZipWriter
@@ -979,6 +1020,9 @@ func Run(runOpts RunOptions) {
 			if cfg.Repair.Concurrency > 0 {
 				repairOpts = repairOpts.SetRepairShardConcurrency(cfg.Repair.Concurrency)
 			}
+			if cfg.Repair.MerkleTreeDepth > 0 {
+				repairOpts = repairOpts.SetMerkleTreeDepth(cfg.Repair.MerkleTreeDepth)
+			}
 
 			if cfg.Repair.DebugShadowComparisonsPercentage > 0 {
 				// Set conditionally to avoid stomping on the default value of 1.0.
@@ -1043,6 +1087,21 @@ func Run(runOpts RunOptions) {
 		opts = opts.SetWideBatchSize(cfg.WideConfig.BatchSize)
 	}
 
+	if cfg.Exemplars != nil {
+		exemplarsOpts := opts.ExemplarsOptions()
+		if cfg.Exemplars.MaxPerSeries != nil {
+			exemplarsOpts.MaxPerSeries = *cfg.Exemplars.MaxPerSeries
+		}
+		exemplarsOpts.PersistEnabled = cfg.Exemplars.Persist
+		opts = opts.SetExemplarsOptions(exemplarsOpts)
+	}
+
+	newTileAggregatorFn := storage.NewTileAggregator
+	if fn := runOpts.StorageOptions.NewTileAggregatorFn; fn != nil {
+		newTileAggregatorFn = fn
+	}
+	opts = opts.SetTileAggregator(newTileAggregatorFn(iOpts))
+
 	db, err := cluster.NewDatabase(hostID, topo, clusterTopoWatch, opts)
 	if err != nil {
 		logger.Fatal("could not construct database", zap.Error(err))
@@ -1060,6 +1119,19 @@ func Run(runOpts RunOptions) {
 	// Now that we've initialized the database we can set it on the service.
 	service.SetDatabase(db)
 
+	// NB: the debug server may already be listening, handlers can be added
+	// to its mux at any time.
+	defaultServeMux.Handle(labelCardinalityDebugURL,
+		newLabelCardinalityHandler(db, logger))
+
+	var backupManager backup.Manager
+	if cfg.Backup != nil {
+		backupManager, err = cfg.Backup.NewManager(fsopts, iOpts)
+		if err != nil {
+			logger.Fatal("could not create backup manager", zap.Error(err))
+		}
+	}
+
 	go func() {
 		if runOpts.BootstrapCh != nil {
 			// Notify on bootstrap chan if specified.
@@ -1074,6 +1146,14 @@ func Run(runOpts RunOptions) {
 		}
 		logger.Info("bootstrapped")
 
+		// Only start backing up once bootstrapped so that filesets restored
+		// during the bootstrap are not backed up again while being restored.
+		if backupManager != nil {
+			if err := backupManager.Start(); err != nil {
+				logger.Error("could not start backup manager", zap.Error(err))
+			}
+		}
+
 		// Only set the write new series limit after bootstrapping
 		kvWatchNewSeriesLimitPerShard(syncCfg.KVStore, logger, topo,
 			runtimeOptsMgr, cfg.Limits.WriteNewSeriesPerSecond)
@@ -1089,6 +1169,10 @@ func Run(runOpts RunOptions) {
 			queryLimits.AggregateDocsLimit(),
 			limitOpts,
 		)
+		if quotasConfig := cfg.Limits.TenantQuotas; quotasConfig != nil {
+			kvWatchTenantQuotas(syncCfg.KVStore, logger,
+				limitOpts.TenantQuotas(), quotasConfig.Options())
+		}
 	}()
 
 	// Wait for process interrupt.
@@ -1096,6 +1180,12 @@ func Run(runOpts RunOptions) {
 		InterruptCh: runOpts.InterruptCh,
 	})
 
+	if backupManager != nil {
+		if err := backupManager.Close(); err != nil {
+			logger.Warn("could not close backup manager", zap.Error(err))
+		}
+	}
+
 	// Attempt graceful server close.
 	closedCh := make(chan struct{})
 	go func() {
@@ -1408,6 +1498,85 @@ func dynamicLimitToLimitOpts(dynamicLimit *kvpb.QueryLimit) limits.LookbackLimit
 	}
 }
 
+func kvWatchTenantQuotas(
+	store kv.Store,
+	logger *zap.Logger,
+	tenantQuotas limits.TenantQuotas,
+	configOpts limits.TenantQuotasOptions,
+) {
+	value, err := store.Get(kvconfig.TenantQuotasKey)
+	if err == nil {
+		dynamicQuotas := &kvpb.TenantQuotas{}
+		err = value.Unmarshal(dynamicQuotas)
+		if err == nil {
+			updateTenantQuotas(logger, tenantQuotas, dynamicQuotas, configOpts)
+		}
+	} else if !errors.Is(err, kv.ErrNotFound) {
+		logger.Warn("error resolving tenant quotas", zap.Error(err))
+	}
+
+	watch, err := store.Watch(kvconfig.TenantQuotasKey)
+	if err != nil {
+		logger.Error("could not watch tenant quotas", zap.Error(err))
+		return
+	}
+
+	go func() {
+		for range watch.C() {
+			if newValue := watch.Get(); newValue != nil {
+				dynamicQuotas := &kvpb.TenantQuotas{}
+				if err := newValue.Unmarshal(dynamicQuotas); err != nil {
+					logger.Warn("unable to parse new tenant quotas", zap.Error(err))
+					continue
+				}
+				updateTenantQuotas(logger, tenantQuotas, dynamicQuotas, configOpts)
+			}
+		}
+	}()
+}
+
+func updateTenantQuotas(
+	logger *zap.Logger,
+	tenantQuotas limits.TenantQuotas,
+	dynamicOpts *kvpb.TenantQuotas,
+	configOpts limits.TenantQuotasOptions,
+) {
+	// Default to the config-based quotas if unset in dynamic quotas.
+	// Otherwise, the dynamic quotas replace the config-based quotas.
+	opts := configOpts
+	if dynamicOpts != nil && (dynamicOpts.DefaultQuota != nil || len(dynamicOpts.Tenants) > 0) {
+		opts = limits.TenantQuotasOptions{
+			Default: dynamicTenantQuota(dynamicOpts.DefaultQuota),
+			Tenants: make(map[string]limits.TenantQuota, len(dynamicOpts.Tenants)),
+		}
+		for _, quota := range dynamicOpts.Tenants {
+			if quota != nil {
+				opts.Tenants[quota.Tenant] = dynamicTenantQuota(quota)
+			}
+		}
+	}
+
+	if err := tenantQuotas.Update(opts); err != nil {
+		logger.Error("error updating tenant quotas", zap.Error(err))
+	}
+}
+
+func dynamicTenantQuota(dynamicQuota *kvpb.TenantQuota) limits.TenantQuota {
+	var quota limits.TenantQuota
+	if dynamicQuota == nil {
+		return quota
+	}
+	quota.SeriesCreatedPerSecond = dynamicQuota.MaxSeriesCreatedPerSecond
+	quota.DatapointsWrittenPerSecond = dynamicQuota.MaxDatapointsWrittenPerSecond
+	if dynamicQuota.MaxRecentlyQueriedSeriesBlocks != nil {
+		quota.DocsMatched = dynamicLimitToLimitOpts(dynamicQuota.MaxRecentlyQueriedSeriesBlocks)
+	}
+	if dynamicQuota.MaxRecentlyQueriedSeriesDiskBytesRead != nil {
+		quota.BytesRead = dynamicLimitToLimitOpts(dynamicQuota.MaxRecentlyQueriedSeriesDiskBytesRead)
+	}
+	return quota
+}
+
 func kvWatchClientConsistencyLevels(
 	store kv.Store,
 	logger *zap.Logger,
@@ -1905,6 +2074,13 @@ func withEncodingAndPoolingOptions(
 		SetAggregateValuesPool(aggregateQueryValuesPool).
 		SetForwardIndexProbability(cfg.Index.ForwardIndexProbability).
 		SetForwardIndexThreshold(cfg.Index.ForwardIndexThreshold)
+	if c := cfg.Index.PersistedCompaction; c != nil {
+		plannerOpts := c.PlannerOptions()
+		if err := plannerOpts.Validate(); err != nil {
+			logger.Fatal("invalid index persisted compaction config", zap.Error(err))
+		}
+		indexOpts = indexOpts.SetPersistedCompactionPlannerOptions(plannerOpts)
+	}
 
 	queryResultsPool.Init(func() index.QueryResults {
 		// NB(r): Need to initialize after setting the index opts so
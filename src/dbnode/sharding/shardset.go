@@ -84,6 +84,14 @@ func (s *shardSet) LookupStateByID(shardID uint32) (shard.State, error) {
 	return hostShard.State(), nil
 }
 
+func (s *shardSet) LookupShardByID(shardID uint32) (shard.Shard, error) {
+	hostShard, ok := s.shardMap[shardID]
+	if !ok {
+		return nil, ErrInvalidShardID
+	}
+	return hostShard, nil
+}
+
 func (s *shardSet) All() []shard.Shard {
 	return s.shards[:]
 }
@@ -145,6 +153,18 @@ func validateShards(shards []shard.Shard) error {
 	return nil
 }
 
+// SplitParentShardID returns the ID of the parent shard that a shard is being
+// split out of and whether the shard is being split at all. A shard that is
+// being split is initializing with a redirect to its parent shard, which the
+// same host keeps serving reads and writes from until the split completes.
+func SplitParentShardID(s shard.Shard) (uint32, bool) {
+	parentID := s.RedirectToShardID()
+	if parentID == nil || s.State() != shard.Initializing {
+		return 0, false
+	}
+	return *parentID, true
+}
+
 // DefaultHashFn generates a HashFn based on murmur32
 func DefaultHashFn(length int) HashFn {
 	return NewHashFn(length, 0)
@@ -73,4 +73,27 @@ func TestLookupShardState(t *testing.T) {
 	shardTwoState, err := ss.LookupStateByID(2)
 	require.Equal(t, ErrInvalidShardID, err)
 	require.Equal(t, noState, shardTwoState)
+
+	shardFive, err := ss.LookupShardByID(5)
+	require.NoError(t, err)
+	require.Equal(t, uint32(5), shardFive.ID())
+
+	_, err = ss.LookupShardByID(2)
+	require.Equal(t, ErrInvalidShardID, err)
+}
+
+func TestSplitParentShardID(t *testing.T) {
+	parentID := uint32(3)
+
+	_, ok := SplitParentShardID(shard.NewShard(11).SetState(shard.Initializing))
+	require.False(t, ok)
+
+	_, ok = SplitParentShardID(shard.NewShard(11).SetState(shard.Available).
+		SetRedirectToShardID(&parentID))
+	require.False(t, ok)
+
+	id, ok := SplitParentShardID(shard.NewShard(11).SetState(shard.Initializing).
+		SetRedirectToShardID(&parentID))
+	require.True(t, ok)
+	require.Equal(t, parentID, id)
 }
@@ -46,6 +46,9 @@ type ShardSet interface {
 	// LookupStateByID returns the state of the shard with a given ID.
 	LookupStateByID(shardID uint32) (shard.State, error)
 
+	// LookupShardByID returns the shard with a given ID.
+	LookupShardByID(shardID uint32) (shard.Shard, error)
+
 	// Min returns the smallest shard owned by this shard set.
 	Min() uint32
 
@@ -0,0 +1,99 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package backup implements bootstrapping from backups of filesets.
+package backup
+
+import (
+	"errors"
+
+	"github.com/m3db/m3/src/dbnode/persist/fs/backup"
+	"github.com/m3db/m3/src/dbnode/storage/bootstrap/result"
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+var (
+	errNoResultOptions = errors.New("result options not set")
+	errNoBackupOptions = errors.New("backup options not set")
+)
+
+type options struct {
+	resultOpts  result.Options
+	backupOpts  backup.Options
+	restoreTime xtime.UnixNano
+	namespaces  []string
+}
+
+// NewOptions creates a new Options.
+func NewOptions() Options {
+	return &options{
+		resultOpts: result.NewOptions(),
+	}
+}
+
+func (o *options) Validate() error {
+	if o.resultOpts == nil {
+		return errNoResultOptions
+	}
+	if o.backupOpts == nil {
+		return errNoBackupOptions
+	}
+	return o.backupOpts.Validate()
+}
+
+func (o *options) SetResultOptions(value result.Options) Options {
+	opts := *o
+	opts.resultOpts = value
+	return &opts
+}
+
+func (o *options) ResultOptions() result.Options {
+	return o.resultOpts
+}
+
+func (o *options) SetBackupOptions(value backup.Options) Options {
+	opts := *o
+	opts.backupOpts = value
+	return &opts
+}
+
+func (o *options) BackupOptions() backup.Options {
+	return o.backupOpts
+}
+
+func (o *options) SetRestoreTime(value xtime.UnixNano) Options {
+	opts := *o
+	opts.restoreTime = value
+	return &opts
+}
+
+func (o *options) RestoreTime() xtime.UnixNano {
+	return o.restoreTime
+}
+
+func (o *options) SetNamespaces(value []string) Options {
+	opts := *o
+	opts.namespaces = value
+	return &opts
+}
+
+func (o *options) Namespaces() []string {
+	return o.namespaces
+}
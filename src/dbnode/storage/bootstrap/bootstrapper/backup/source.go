@@ -0,0 +1,179 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package backup
+
+import (
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/persist/fs/backup"
+	"github.com/m3db/m3/src/dbnode/storage/bootstrap"
+	"github.com/m3db/m3/src/dbnode/storage/bootstrap/result"
+	"github.com/m3db/m3/src/x/context"
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+// backupSource restores filesets that are missing from disk from backups and
+// then returns every requested range as unfulfilled, so that the filesystem
+// source that follows it loads the restored filesets.
+type backupSource struct {
+	opts        Options
+	restorer    backup.Restorer
+	restoreTime xtime.UnixNano
+	namespaces  map[string]struct{}
+	logger      *zap.Logger
+}
+
+func newBackupSource(opts Options) (bootstrap.Source, error) {
+	restorer, err := backup.NewRestorer(opts.BackupOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	namespaces := make(map[string]struct{}, len(opts.Namespaces()))
+	for _, ns := range opts.Namespaces() {
+		namespaces[ns] = struct{}{}
+	}
+
+	return &backupSource{
+		opts:        opts,
+		restorer:    restorer,
+		restoreTime: opts.RestoreTime(),
+		namespaces:  namespaces,
+		logger:      opts.ResultOptions().InstrumentOptions().Logger(),
+	}, nil
+}
+
+func (s *backupSource) AvailableData(
+	_ namespace.Metadata,
+	shardsTimeRanges result.ShardTimeRanges,
+	_ bootstrap.Cache,
+	_ bootstrap.RunOptions,
+) (result.ShardTimeRanges, error) {
+	return shardsTimeRanges, nil
+}
+
+func (s *backupSource) AvailableIndex(
+	_ namespace.Metadata,
+	shardsTimeRanges result.ShardTimeRanges,
+	_ bootstrap.Cache,
+	_ bootstrap.RunOptions,
+) (result.ShardTimeRanges, error) {
+	return shardsTimeRanges, nil
+}
+
+func (s *backupSource) Read(
+	ctx context.Context,
+	namespaces bootstrap.Namespaces,
+	cache bootstrap.Cache,
+) (bootstrap.NamespaceResults, error) {
+	var (
+		results = bootstrap.NamespaceResults{
+			Results: bootstrap.NewNamespaceResultsMap(bootstrap.NamespaceResultsMapOptions{}),
+		}
+		restored bool
+	)
+	for _, elem := range namespaces.Namespaces.Iter() {
+		ns := elem.Value()
+		md := ns.Metadata
+
+		if s.shouldRestore(md) {
+			res, err := s.restorer.Restore(ctx.GoContext(), backup.RestoreOptions{
+				Namespace: md.ID(),
+				Time:      s.restoreTime,
+				Filter:    newRestoreFilter(ns),
+			})
+			if errors.Is(err, backup.ErrNoBackup) {
+				s.logger.Warn("no backup to restore namespace from",
+					zap.Stringer("namespace", md.ID()), zap.Error(err))
+			} else if err != nil {
+				return bootstrap.NamespaceResults{}, err
+			}
+			restored = restored || res.Filesets > 0
+		}
+
+		namespaceResult := bootstrap.NamespaceResult{
+			Metadata:   md,
+			Shards:     ns.Shards,
+			DataResult: ns.DataRunOptions.ShardTimeRanges.ToUnfulfilledDataResult(),
+		}
+		if md.Options().IndexOptions().Enabled() {
+			namespaceResult.IndexResult = ns.IndexRunOptions.ShardTimeRanges.ToUnfulfilledIndexResult()
+		}
+		results.Results.Set(md.ID(), namespaceResult)
+	}
+
+	if restored {
+		// Info files are cached across bootstrappers so the cache must be
+		// evicted for the restored filesets to be read.
+		cache.Evict()
+	}
+
+	return results, nil
+}
+
+func (s *backupSource) shouldRestore(md namespace.Metadata) bool {
+	if len(s.namespaces) == 0 {
+		return true
+	}
+	_, ok := s.namespaces[md.ID().String()]
+	return ok
+}
+
+// newRestoreFilter returns a filter of the filesets of a backup that overlap
+// the ranges being bootstrapped.
+func newRestoreFilter(ns bootstrap.Namespace) func(backup.FilesetManifest) bool {
+	var (
+		nsOpts         = ns.Metadata.Options()
+		blockSize      = nsOpts.RetentionOptions().BlockSize()
+		indexBlockSize = nsOpts.IndexOptions().BlockSize()
+		indexEnabled   = nsOpts.IndexOptions().Enabled()
+		dataRanges     = ns.DataRunOptions.ShardTimeRanges
+		indexRanges    = ns.IndexRunOptions.ShardTimeRanges
+	)
+	return func(fileset backup.FilesetManifest) bool {
+		switch fileset.Type {
+		case backup.DataFileset, backup.SnapshotFileset:
+			ranges, ok := dataRanges.Get(fileset.Shard)
+			return ok && overlaps(ranges, fileset.BlockStart, blockSize)
+		case backup.IndexFileset:
+			if !indexEnabled || indexRanges == nil {
+				return false
+			}
+			for _, ranges := range indexRanges.Iter() {
+				if overlaps(ranges, fileset.BlockStart, indexBlockSize) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+}
+
+func overlaps(ranges xtime.Ranges, blockStart xtime.UnixNano, blockSize time.Duration) bool {
+	return ranges.Overlaps(xtime.Range{
+		Start: blockStart,
+		End:   blockStart.Add(blockSize),
+	})
+}
@@ -0,0 +1,134 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package backup
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/m3db/m3/src/dbnode/digest"
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/persist/fs/backup"
+	"github.com/m3db/m3/src/dbnode/retention"
+	"github.com/m3db/m3/src/dbnode/storage/bootstrap"
+	"github.com/m3db/m3/src/dbnode/storage/bootstrap/result"
+	"github.com/m3db/m3/src/x/checked"
+	"github.com/m3db/m3/src/x/ident"
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+var (
+	testNamespaceID = ident.StringID("testns")
+	testBlockSize   = 2 * time.Hour
+)
+
+func newTestFsOptions(t *testing.T) fs.Options {
+	dir, err := ioutil.TempDir("", "backup-source-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return fs.NewOptions().SetFilePathPrefix(dir)
+}
+
+func writeTestFileset(t *testing.T, fsOpts fs.Options, shard uint32, blockStart xtime.UnixNano) {
+	w, err := fs.NewWriter(fsOpts)
+	require.NoError(t, err)
+	require.NoError(t, w.Open(fs.DataWriterOpenOptions{
+		Identifier: fs.FileSetFileIdentifier{
+			Namespace:  testNamespaceID,
+			Shard:      shard,
+			BlockStart: blockStart,
+		},
+		BlockSize: testBlockSize,
+	}))
+
+	data := checked.NewBytes([]byte("foo"), nil)
+	data.IncRef()
+	metadata := persist.NewMetadataFromIDAndTags(ident.StringID("foo"), ident.Tags{},
+		persist.MetadataOptions{})
+	require.NoError(t, w.Write(metadata, data, digest.Checksum(data.Bytes())))
+	data.DecRef()
+	require.NoError(t, w.Close())
+}
+
+func TestBackupSourceRestoresRequestedFilesets(t *testing.T) {
+	storeDir := newTestFsOptions(t).FilePathPrefix()
+	store, err := backup.NewLocalStore(storeDir)
+	require.NoError(t, err)
+
+	var (
+		srcFsOpts = newTestFsOptions(t)
+		dstFsOpts = newTestFsOptions(t)
+		start     = xtime.Now().Truncate(testBlockSize).Add(-4 * testBlockSize)
+		end       = start.Add(testBlockSize)
+	)
+	writeTestFileset(t, srcFsOpts, 0, start)
+	writeTestFileset(t, srcFsOpts, 1, start)
+	writeTestFileset(t, srcFsOpts, 0, end)
+
+	manager, err := backup.NewManager(backup.NewOptions().
+		SetStore(store).
+		SetFilesystemOptions(srcFsOpts))
+	require.NoError(t, err)
+	require.NoError(t, manager.Backup(context.Background()))
+
+	opts := NewOptions().
+		SetBackupOptions(backup.NewOptions().
+			SetStore(store).
+			SetFilesystemOptions(dstFsOpts))
+	src, err := newBackupSource(opts)
+	require.NoError(t, err)
+
+	md, err := namespace.NewMetadata(testNamespaceID, namespace.NewOptions().
+		SetRetentionOptions(retention.NewOptions().SetBlockSize(testBlockSize)).
+		SetIndexOptions(namespace.NewIndexOptions().SetEnabled(false)))
+	require.NoError(t, err)
+
+	// Only shard 0 of the first block is requested.
+	ranges := result.NewShardTimeRanges().Set(0,
+		xtime.NewRanges(xtime.Range{Start: start, End: end}))
+	tester := bootstrap.BuildNamespacesTesterWithFilesystemOptions(t,
+		bootstrap.NewRunOptions(), ranges, dstFsOpts, md)
+	defer tester.Finish()
+
+	// Populate the cache before restoring to ensure it is evicted.
+	infoFiles, err := tester.Cache.InfoFilesForShard(md, 0)
+	require.NoError(t, err)
+	require.Equal(t, 0, len(infoFiles))
+
+	tester.TestReadWith(src)
+	tester.TestUnfulfilledForNamespace(md, ranges, ranges)
+
+	infoFiles, err = tester.Cache.InfoFilesForShard(md, 0)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(infoFiles))
+	require.Equal(t, int64(start), infoFiles[0].Info.BlockStart)
+
+	files, err := fs.DataFiles(dstFsOpts.FilePathPrefix(), testNamespaceID, 1)
+	require.NoError(t, err)
+	require.Equal(t, 0, len(files))
+}
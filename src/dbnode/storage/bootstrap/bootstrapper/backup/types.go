@@ -0,0 +1,60 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package backup
+
+import (
+	"github.com/m3db/m3/src/dbnode/persist/fs/backup"
+	"github.com/m3db/m3/src/dbnode/storage/bootstrap/result"
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+// Options represents the options for bootstrapping from backups.
+type Options interface {
+	// Validate validates the options.
+	Validate() error
+
+	// SetResultOptions sets the result options.
+	SetResultOptions(value result.Options) Options
+
+	// ResultOptions returns the result options.
+	ResultOptions() result.Options
+
+	// SetBackupOptions sets the options of the backups to restore from.
+	SetBackupOptions(value backup.Options) Options
+
+	// BackupOptions returns the options of the backups to restore from.
+	BackupOptions() backup.Options
+
+	// SetRestoreTime sets the time of the backup to restore, the latest
+	// backup taken at or before the time is restored. If zero the latest
+	// backup is restored.
+	SetRestoreTime(value xtime.UnixNano) Options
+
+	// RestoreTime returns the time of the backup to restore.
+	RestoreTime() xtime.UnixNano
+
+	// SetNamespaces sets the namespaces to restore, if empty all namespaces
+	// are restored.
+	SetNamespaces(value []string) Options
+
+	// Namespaces returns the namespaces to restore.
+	Namespaces() []string
+}
@@ -212,6 +212,11 @@ func (m *cleanupManager) ColdFlushCleanup(t xtime.UnixNano) error {
 			"encountered errors when deleting inactive data files for %v: %v", t, err))
 	}
 
+	if err := m.offloadDataFiles(t, namespaces); err != nil {
+		multiErr = multiErr.Add(fmt.Errorf(
+			"encountered errors when offloading data files for %v: %v", t, err))
+	}
+
 	return multiErr.FinalError()
 }
 
@@ -236,33 +241,54 @@ func (m *cleanupManager) Report() {
 
 func (m *cleanupManager) deleteInactiveNamespaceFiles(namespaces []databaseNamespace) error {
 	var namespaceDirNames []string
-	filePathPrefix := m.database.Options().CommitLogOptions().FilesystemOptions().FilePathPrefix()
-	dataDirPath := fs.DataDirPath(filePathPrefix)
-
 	for _, n := range namespaces {
 		namespaceDirNames = append(namespaceDirNames, n.ID().String())
 	}
 
-	return m.deleteInactiveDirectoriesFn(dataDirPath, namespaceDirNames)
+	multiErr := xerrors.NewMultiError()
+	for _, filePathPrefix := range m.dataFilePathPrefixes() {
+		dataDirPath := fs.DataDirPath(filePathPrefix)
+		multiErr = multiErr.Add(m.deleteInactiveDirectoriesFn(dataDirPath, namespaceDirNames))
+	}
+
+	return multiErr.FinalError()
+}
+
+// dataFilePathPrefixes returns the file path prefixes of the storage tiers
+// that data filesets are stored in.
+func (m *cleanupManager) dataFilePathPrefixes() []string {
+	fsOpts := m.database.Options().CommitLogOptions().FilesystemOptions()
+	prefixes := []string{fsOpts.FilePathPrefix()}
+	if secondary := fsOpts.SecondaryTierFilePathPrefix(); secondary != "" {
+		prefixes = append(prefixes, secondary)
+	}
+	return prefixes
 }
 
 // deleteInactiveDataFiles will delete data files for shards that the node no longer owns
 // which can occur in the case of topology changes
 func (m *cleanupManager) deleteInactiveDataFiles(namespaces []databaseNamespace) error {
-	return m.deleteInactiveDataFileSetFiles(fs.NamespaceDataDirPath, namespaces)
+	multiErr := xerrors.NewMultiError()
+	for _, filePathPrefix := range m.dataFilePathPrefixes() {
+		multiErr = multiErr.Add(m.deleteInactiveDataFileSetFiles(
+			filePathPrefix, fs.NamespaceDataDirPath, namespaces))
+	}
+	return multiErr.FinalError()
 }
 
 // deleteInactiveDataSnapshotFiles will delete snapshot files for shards that the node no longer owns
 // which can occur in the case of topology changes
 func (m *cleanupManager) deleteInactiveDataSnapshotFiles(namespaces []databaseNamespace) error {
-	return m.deleteInactiveDataFileSetFiles(fs.NamespaceSnapshotsDirPath, namespaces)
+	filePathPrefix := m.database.Options().CommitLogOptions().FilesystemOptions().FilePathPrefix()
+	return m.deleteInactiveDataFileSetFiles(filePathPrefix, fs.NamespaceSnapshotsDirPath, namespaces)
 }
 
 func (m *cleanupManager) deleteInactiveDataFileSetFiles(
-	filesetFilesDirPathFn func(string, ident.ID) string, namespaces []databaseNamespace,
+	filePathPrefix string,
+	filesetFilesDirPathFn func(string, ident.ID) string,
+	namespaces []databaseNamespace,
 ) error {
 	multiErr := xerrors.NewMultiError()
-	filePathPrefix := m.database.Options().CommitLogOptions().FilesystemOptions().FilePathPrefix()
 	for _, n := range namespaces {
 		var activeShards []string
 		namespaceDirPath := filesetFilesDirPathFn(filePathPrefix, n.ID())
@@ -292,6 +318,31 @@ func (m *cleanupManager) cleanupDataFiles(t xtime.UnixNano, namespaces []databas
 	return multiErr.FinalError()
 }
 
+// offloadDataFiles offloads the data files of flushed blocks older than the
+// secondary tier age of their namespace to the secondary storage tier.
+func (m *cleanupManager) offloadDataFiles(t xtime.UnixNano, namespaces []databaseNamespace) error {
+	fsOpts := m.database.Options().CommitLogOptions().FilesystemOptions()
+	if fsOpts.SecondaryTierFilePathPrefix() == "" {
+		return nil
+	}
+
+	multiErr := xerrors.NewMultiError()
+	for _, n := range namespaces {
+		age := n.Options().SecondaryTierAge()
+		if age == 0 {
+			continue
+		}
+		offloadBefore := t.Add(-age)
+		for _, shard := range n.OwnedShards() {
+			if !shard.IsBootstrapped() {
+				continue
+			}
+			multiErr = multiErr.Add(shard.OffloadFileSets(offloadBefore))
+		}
+	}
+	return multiErr.FinalError()
+}
+
 func (m *cleanupManager) cleanupExpiredIndexFiles(
 	t xtime.UnixNano, namespaces []databaseNamespace,
 ) error {
@@ -132,6 +132,12 @@ func (m *coldFlushManager) Run(t xtime.UnixNano) bool {
 					zap.Time("time", t.ToTime()), zap.Error(err))
 			})
 	}
+	// NB: compacting persisted index volumes runs after the cold flush so that
+	// it never races with a cold flush writing new volumes for the same block.
+	if err := m.compactIndexFileSets(); err != nil {
+		m.log.Error("error when compacting index filesets",
+			zap.Time("time", t.ToTime()), zap.Error(err))
+	}
 
 	if debugLog != nil {
 		debugLog.Write(zap.String("status", "completed cold flush"), zap.Time("time", t.ToTime()))
@@ -197,6 +203,38 @@ func (m *coldFlushManager) coldFlush() error {
 	return err
 }
 
+func (m *coldFlushManager) compactIndexFileSets() error {
+	if !m.opts.IndexOptions().PersistedCompactionPlannerOptions().Enabled {
+		return nil
+	}
+
+	namespaces, err := m.database.OwnedNamespaces()
+	if err != nil {
+		return err
+	}
+
+	indexFlush, err := m.pm.StartIndexPersist()
+	if err != nil {
+		return err
+	}
+
+	multiErr := xerrors.NewMultiError()
+	for _, ns := range namespaces {
+		if !ns.Options().IndexOptions().Enabled() {
+			continue
+		}
+		idx, err := ns.Index()
+		if err != nil {
+			multiErr = multiErr.Add(err)
+			continue
+		}
+		multiErr = multiErr.Add(idx.CompactPersistedFileSets(indexFlush, ns.OwnedShards()))
+	}
+
+	multiErr = multiErr.Add(indexFlush.DoneIndex())
+	return multiErr.FinalError()
+}
+
 func (m *coldFlushManager) Report() {
 	m.databaseCleanupManager.Report()
 
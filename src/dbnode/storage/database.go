@@ -37,6 +37,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/storage/index"
 	"github.com/m3db/m3/src/dbnode/storage/index/convert"
 	"github.com/m3db/m3/src/dbnode/storage/limits"
+	"github.com/m3db/m3/src/dbnode/storage/repair"
 	"github.com/m3db/m3/src/dbnode/storage/series"
 	"github.com/m3db/m3/src/dbnode/tracepoint"
 	"github.com/m3db/m3/src/dbnode/ts"
@@ -1200,6 +1201,92 @@ func (d *db) Truncate(namespace ident.ID) (int64, error) {
 	return n.Truncate()
 }
 
+func (d *db) DeleteSeries(
+	ctx context.Context,
+	namespace ident.ID,
+	query index.Query,
+	start, end xtime.UnixNano,
+) (map[uint32]int64, error) {
+	n, err := d.namespaceFor(namespace)
+	if err != nil {
+		return nil, err
+	}
+	return n.DeleteSeries(ctx, query, start, end)
+}
+
+func (d *db) WriteExemplars(
+	namespace ident.ID,
+	id ident.ID,
+	exemplars []ts.Exemplar,
+) error {
+	n, err := d.namespaceFor(namespace)
+	if err != nil {
+		d.metrics.unknownNamespaceWrite.Inc(1)
+		return err
+	}
+	return n.WriteExemplars(id, exemplars)
+}
+
+func (d *db) FetchExemplars(
+	ctx context.Context,
+	namespace ident.ID,
+	query index.Query,
+	opts index.QueryOptions,
+) (FetchExemplarsResult, error) {
+	n, err := d.namespaceFor(namespace)
+	if err != nil {
+		d.metrics.unknownNamespaceQueryIDs.Inc(1)
+		return FetchExemplarsResult{}, err
+	}
+	return n.FetchExemplars(ctx, query, opts)
+}
+
+func (d *db) Cardinality(
+	ctx context.Context,
+	namespace ident.ID,
+	opts index.CardinalityOptions,
+) (index.CardinalityResult, error) {
+	n, err := d.namespaceFor(namespace)
+	if err != nil {
+		d.metrics.unknownNamespaceQueryIDs.Inc(1)
+		return index.CardinalityResult{}, err
+	}
+	return n.Cardinality(ctx, opts)
+}
+
+func (d *db) RepairStatus() []repair.ShardStatus {
+	return d.repairer.Status()
+}
+
+func (d *db) MerkleTree(
+	namespace ident.ID,
+	shardID uint32,
+	blockStart xtime.UnixNano,
+	depth int,
+) (*repair.MerkleTree, error) {
+	n, err := d.namespaceFor(namespace)
+	if err != nil {
+		d.metrics.unknownNamespaceFetchBlocks.Inc(1)
+		return nil, xerrors.NewInvalidParamsError(err)
+	}
+	return n.MerkleTree(shardID, blockStart, depth)
+}
+
+func (d *db) MerkleTreeLeafEntries(
+	namespace ident.ID,
+	shardID uint32,
+	blockStart xtime.UnixNano,
+	depth int,
+	leaves []int,
+) ([]repair.MerkleTreeLeafEntry, error) {
+	n, err := d.namespaceFor(namespace)
+	if err != nil {
+		d.metrics.unknownNamespaceFetchBlocks.Inc(1)
+		return nil, xerrors.NewInvalidParamsError(err)
+	}
+	return n.MerkleTreeLeafEntries(shardID, blockStart, depth, leaves)
+}
+
 func (d *db) IsOverloaded() bool {
 	queueSize := float64(d.commitLog.QueueLength())
 	queueCapacity := float64(d.opts.CommitLogOptions().BacklogQueueSize())
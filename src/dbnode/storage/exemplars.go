@@ -0,0 +1,441 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/digest"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/x/ident"
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+const (
+	exemplarsFilePrefix = "exemplars-"
+	exemplarsFileSuffix = ".db"
+
+	exemplarsFileVersion = 1
+)
+
+var (
+	exemplarsFileMagic = []byte("M3EX")
+
+	errExemplarsFileCorrupt  = errors.New("exemplars file is corrupt")
+	errExemplarsFileChecksum = errors.New("exemplars file checksum mismatch")
+)
+
+// seriesExemplars keeps the most recent exemplars of each series of a shard
+// in a bounded ring per series. Exemplars must be written in timestamp order
+// per series, out of order and duplicate exemplars are dropped. The
+// exemplars of a block can optionally be persisted to the shard data
+// directory once the block is flushed so that they are loaded again on
+// bootstrap.
+type seriesExemplars struct {
+	sync.RWMutex
+
+	dir          string
+	fsOpts       fs.Options
+	maxPerSeries int
+	series       map[string]*exemplarsRing
+}
+
+func newSeriesExemplars(
+	fsOpts fs.Options,
+	namespace ident.ID,
+	shard uint32,
+	maxPerSeries int,
+) *seriesExemplars {
+	return &seriesExemplars{
+		dir:          fs.ShardDataDirPath(fsOpts.FilePathPrefix(), namespace, shard),
+		fsOpts:       fsOpts,
+		maxPerSeries: maxPerSeries,
+		series:       make(map[string]*exemplarsRing),
+	}
+}
+
+// Enabled returns whether exemplars are kept.
+func (e *seriesExemplars) Enabled() bool {
+	return e.maxPerSeries > 0
+}
+
+// Add adds the exemplars for the series, returning the number of exemplars
+// that were dropped for being out of order or duplicates.
+func (e *seriesExemplars) Add(id []byte, exemplars []ts.Exemplar) int {
+	if !e.Enabled() || len(exemplars) == 0 {
+		return len(exemplars)
+	}
+
+	e.Lock()
+	defer e.Unlock()
+	ring, ok := e.series[string(id)]
+	if !ok {
+		ring = &exemplarsRing{}
+		e.series[string(id)] = ring
+	}
+	dropped := 0
+	for _, exemplar := range exemplars {
+		if !ring.add(exemplar, e.maxPerSeries) {
+			dropped++
+		}
+	}
+	if len(ring.exemplars) == 0 {
+		delete(e.series, string(id))
+	}
+	return dropped
+}
+
+// Fetch returns the exemplars for the series in the range [start, end) in
+// timestamp order.
+func (e *seriesExemplars) Fetch(id []byte, start, end xtime.UnixNano) []ts.Exemplar {
+	e.RLock()
+	defer e.RUnlock()
+	ring, ok := e.series[string(id)]
+	if !ok {
+		return nil
+	}
+	var result []ts.Exemplar
+	ring.forEach(func(exemplar ts.Exemplar) {
+		if !exemplar.TimestampNanos.Before(start) && exemplar.TimestampNanos.Before(end) {
+			result = append(result, exemplar)
+		}
+	})
+	return result
+}
+
+// RemoveBefore removes the exemplars, and any persisted exemplars files,
+// for all blocks before the given time.
+func (e *seriesExemplars) RemoveBefore(earliest xtime.UnixNano) error {
+	e.Lock()
+	for id, ring := range e.series {
+		ring.removeBefore(earliest)
+		if len(ring.exemplars) == 0 {
+			delete(e.series, id)
+		}
+	}
+	e.Unlock()
+
+	blockStarts, err := e.persistedBlockStarts()
+	if err != nil {
+		return err
+	}
+	for _, blockStart := range blockStarts {
+		if !blockStart.Before(earliest) {
+			continue
+		}
+		if err := os.Remove(e.filePath(blockStart)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// Persist writes the exemplars in the block to the shard data directory,
+// skipping any series for which skip returns true.
+func (e *seriesExemplars) Persist(
+	blockStart xtime.UnixNano,
+	blockSize time.Duration,
+	skip func(id []byte) bool,
+) error {
+	var (
+		blockEnd = blockStart.Add(blockSize)
+		buf      bytes.Buffer
+		scratch  [binary.MaxVarintLen64]byte
+		num      int
+	)
+	writeUvarint := func(v uint64) {
+		n := binary.PutUvarint(scratch[:], v)
+		buf.Write(scratch[:n])
+	}
+	writeBytes := func(b []byte) {
+		writeUvarint(uint64(len(b)))
+		buf.Write(b)
+	}
+
+	buf.Write(exemplarsFileMagic)
+	writeUvarint(exemplarsFileVersion)
+
+	e.RLock()
+	ids := make([]string, 0, len(e.series))
+	for id := range e.series {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		if skip != nil && skip([]byte(id)) {
+			continue
+		}
+		var inBlock []ts.Exemplar
+		e.series[id].forEach(func(exemplar ts.Exemplar) {
+			if !exemplar.TimestampNanos.Before(blockStart) &&
+				exemplar.TimestampNanos.Before(blockEnd) {
+				inBlock = append(inBlock, exemplar)
+			}
+		})
+		if len(inBlock) == 0 {
+			continue
+		}
+		num++
+		writeBytes([]byte(id))
+		writeUvarint(uint64(len(inBlock)))
+		for _, exemplar := range inBlock {
+			n := binary.PutVarint(scratch[:], int64(exemplar.TimestampNanos))
+			buf.Write(scratch[:n])
+			binary.LittleEndian.PutUint64(scratch[:8], math.Float64bits(exemplar.Value))
+			buf.Write(scratch[:8])
+			writeUvarint(uint64(len(exemplar.Labels)))
+			for _, label := range exemplar.Labels {
+				writeBytes(label.Name)
+				writeBytes(label.Value)
+			}
+		}
+	}
+	e.RUnlock()
+
+	if num == 0 {
+		return nil
+	}
+
+	binary.LittleEndian.PutUint32(scratch[:4], digest.Checksum(buf.Bytes()))
+	buf.Write(scratch[:4])
+	data, err := fs.EncryptFileData(e.fsOpts, buf.Bytes())
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(e.dir, e.fsOpts.NewDirectoryMode()); err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so that a partially written file
+	// is never observed.
+	filePath := e.filePath(blockStart)
+	tmpFilePath := filePath + ".tmp"
+	if err := ioutil.WriteFile(tmpFilePath, data, e.fsOpts.NewFileMode()); err != nil {
+		return err
+	}
+	return os.Rename(tmpFilePath, filePath)
+}
+
+// Load reads any previously persisted exemplars.
+func (e *seriesExemplars) Load() error {
+	if !e.Enabled() {
+		return nil
+	}
+
+	blockStarts, err := e.persistedBlockStarts()
+	if err != nil {
+		return err
+	}
+	for _, blockStart := range blockStarts {
+		filePath := e.filePath(blockStart)
+		data, err := ioutil.ReadFile(filePath)
+		if err != nil {
+			return err
+		}
+		data, err = fs.DecryptFileData(e.fsOpts, data)
+		if err != nil {
+			return fmt.Errorf("unable to decrypt exemplars file %s: %w", filePath, err)
+		}
+		if err := e.load(data); err != nil {
+			return fmt.Errorf("unable to load exemplars file %s: %w", filePath, err)
+		}
+	}
+	return nil
+}
+
+func (e *seriesExemplars) load(data []byte) error {
+	if len(data) < len(exemplarsFileMagic)+4 ||
+		!bytes.Equal(data[:len(exemplarsFileMagic)], exemplarsFileMagic) {
+		return errExemplarsFileCorrupt
+	}
+	checksumIdx := len(data) - 4
+	if digest.Checksum(data[:checksumIdx]) != binary.LittleEndian.Uint32(data[checksumIdx:]) {
+		return errExemplarsFileChecksum
+	}
+
+	r := exemplarsFileReader{data: data[len(exemplarsFileMagic):checksumIdx]}
+	if version := r.uvarint(); version != exemplarsFileVersion {
+		return fmt.Errorf("unsupported exemplars file version: %d", version)
+	}
+	for r.err == nil && len(r.data) > 0 {
+		id := r.bytes()
+		exemplars := make([]ts.Exemplar, int(r.uvarint()))
+		for i := range exemplars {
+			exemplars[i].TimestampNanos = xtime.UnixNano(r.varint())
+			exemplars[i].Value = r.float64()
+			exemplars[i].Labels = make([]ts.ExemplarLabel, int(r.uvarint()))
+			for j := range exemplars[i].Labels {
+				exemplars[i].Labels[j].Name = r.bytes()
+				exemplars[i].Labels[j].Value = r.bytes()
+			}
+			if r.err != nil {
+				return r.err
+			}
+		}
+		if r.err == nil {
+			e.Add(id, exemplars)
+		}
+	}
+	return r.err
+}
+
+func (e *seriesExemplars) filePath(blockStart xtime.UnixNano) string {
+	fileName := exemplarsFilePrefix + strconv.FormatInt(int64(blockStart), 10) +
+		exemplarsFileSuffix
+	return path.Join(e.dir, fileName)
+}
+
+// persistedBlockStarts returns the block starts of the persisted exemplars
+// files in ascending order.
+func (e *seriesExemplars) persistedBlockStarts() ([]xtime.UnixNano, error) {
+	matches, err := filepath.Glob(path.Join(e.dir, exemplarsFilePrefix+"*"+exemplarsFileSuffix))
+	if err != nil {
+		return nil, err
+	}
+	blockStarts := make([]xtime.UnixNano, 0, len(matches))
+	for _, match := range matches {
+		name := strings.TrimSuffix(strings.TrimPrefix(path.Base(match),
+			exemplarsFilePrefix), exemplarsFileSuffix)
+		nanos, err := strconv.ParseInt(name, 10, 64)
+		if err != nil {
+			continue
+		}
+		blockStarts = append(blockStarts, xtime.UnixNano(nanos))
+	}
+	sort.Slice(blockStarts, func(i, j int) bool {
+		return blockStarts[i] < blockStarts[j]
+	})
+	return blockStarts, nil
+}
+
+// exemplarsRing is a bounded ring of exemplars in timestamp order.
+type exemplarsRing struct {
+	exemplars []ts.Exemplar
+	// head is the index of the oldest exemplar once the ring is full.
+	head int
+}
+
+func (r *exemplarsRing) add(exemplar ts.Exemplar, max int) bool {
+	if n := len(r.exemplars); n > 0 {
+		latestIdx := (r.head + n - 1) % n
+		if !exemplar.TimestampNanos.After(r.exemplars[latestIdx].TimestampNanos) {
+			return false
+		}
+	}
+	if len(r.exemplars) < max {
+		r.exemplars = append(r.exemplars, exemplar)
+		return true
+	}
+	r.exemplars[r.head] = exemplar
+	r.head = (r.head + 1) % len(r.exemplars)
+	return true
+}
+
+func (r *exemplarsRing) forEach(fn func(exemplar ts.Exemplar)) {
+	n := len(r.exemplars)
+	for i := 0; i < n; i++ {
+		fn(r.exemplars[(r.head+i)%n])
+	}
+}
+
+func (r *exemplarsRing) removeBefore(earliest xtime.UnixNano) {
+	kept := make([]ts.Exemplar, 0, len(r.exemplars))
+	r.forEach(func(exemplar ts.Exemplar) {
+		if !exemplar.TimestampNanos.Before(earliest) {
+			kept = append(kept, exemplar)
+		}
+	})
+	r.exemplars = kept
+	r.head = 0
+}
+
+type exemplarsFileReader struct {
+	data []byte
+	err  error
+}
+
+func (r *exemplarsFileReader) uvarint() uint64 {
+	if r.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(r.data)
+	if n <= 0 {
+		r.err = errExemplarsFileCorrupt
+		return 0
+	}
+	r.data = r.data[n:]
+	return v
+}
+
+func (r *exemplarsFileReader) varint() int64 {
+	if r.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(r.data)
+	if n <= 0 {
+		r.err = errExemplarsFileCorrupt
+		return 0
+	}
+	r.data = r.data[n:]
+	return v
+}
+
+func (r *exemplarsFileReader) float64() float64 {
+	if r.err != nil {
+		return 0
+	}
+	if len(r.data) < 8 {
+		r.err = errExemplarsFileCorrupt
+		return 0
+	}
+	v := math.Float64frombits(binary.LittleEndian.Uint64(r.data))
+	r.data = r.data[8:]
+	return v
+}
+
+func (r *exemplarsFileReader) bytes() []byte {
+	n := r.uvarint()
+	if r.err != nil {
+		return nil
+	}
+	if uint64(len(r.data)) < n {
+		r.err = errExemplarsFileCorrupt
+		return nil
+	}
+	v := r.data[:n:n]
+	r.data = r.data[n:]
+	return v
+}
@@ -0,0 +1,172 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/x/ident"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSeriesExemplars(t *testing.T, maxPerSeries int) (*seriesExemplars, string) {
+	dir, err := ioutil.TempDir("", "exemplars")
+	require.NoError(t, err)
+
+	fsOpts := fs.NewOptions().SetFilePathPrefix(dir)
+	return newSeriesExemplars(fsOpts, ident.StringID("ns"), 0, maxPerSeries), dir
+}
+
+func newTestExemplar(t xtime.UnixNano, value float64, traceID string) ts.Exemplar {
+	return ts.Exemplar{
+		TimestampNanos: t,
+		Value:          value,
+		Labels: []ts.ExemplarLabel{
+			{Name: []byte("trace_id"), Value: []byte(traceID)},
+		},
+	}
+}
+
+func TestSeriesExemplarsAddAndFetch(t *testing.T) {
+	exemplars, dir := newTestSeriesExemplars(t, 2)
+	defer os.RemoveAll(dir)
+
+	start := xtime.Now().Truncate(time.Hour)
+	first := newTestExemplar(start, 1, "a")
+	second := newTestExemplar(start.Add(time.Second), 2, "b")
+	third := newTestExemplar(start.Add(2*time.Second), 3, "c")
+
+	assert.Equal(t, 0, exemplars.Add([]byte("foo"), []ts.Exemplar{first, second}))
+	// Duplicate and out of order exemplars are dropped.
+	assert.Equal(t, 2, exemplars.Add([]byte("foo"), []ts.Exemplar{second, first}))
+	// The oldest exemplar is evicted once the ring is full.
+	assert.Equal(t, 0, exemplars.Add([]byte("foo"), []ts.Exemplar{third}))
+
+	end := start.Add(time.Minute)
+	assert.Equal(t, []ts.Exemplar{second, third},
+		exemplars.Fetch([]byte("foo"), start, end))
+	assert.Equal(t, []ts.Exemplar{second},
+		exemplars.Fetch([]byte("foo"), start, third.TimestampNanos))
+	assert.Empty(t, exemplars.Fetch([]byte("bar"), start, end))
+
+	require.NoError(t, exemplars.RemoveBefore(third.TimestampNanos))
+	assert.Equal(t, []ts.Exemplar{third}, exemplars.Fetch([]byte("foo"), start, end))
+
+	require.NoError(t, exemplars.RemoveBefore(end))
+	assert.Empty(t, exemplars.series)
+}
+
+func TestSeriesExemplarsDisabled(t *testing.T) {
+	exemplars, dir := newTestSeriesExemplars(t, 0)
+	defer os.RemoveAll(dir)
+
+	now := xtime.Now()
+	assert.Equal(t, 1, exemplars.Add([]byte("foo"),
+		[]ts.Exemplar{newTestExemplar(now, 1, "a")}))
+	assert.Empty(t, exemplars.Fetch([]byte("foo"), 0, now.Add(time.Second)))
+}
+
+func TestSeriesExemplarsPersistAndLoad(t *testing.T) {
+	exemplars, dir := newTestSeriesExemplars(t, 10)
+	defer os.RemoveAll(dir)
+
+	var (
+		blockSize  = 2 * time.Hour
+		blockStart = xtime.Now().Truncate(blockSize)
+		nextBlock  = blockStart.Add(blockSize)
+		inBlock    = newTestExemplar(blockStart.Add(time.Minute), 1.5, "a")
+		afterBlock = newTestExemplar(nextBlock.Add(time.Minute), 2.5, "b")
+	)
+	exemplars.Add([]byte("foo"), []ts.Exemplar{inBlock, afterBlock})
+	exemplars.Add([]byte("bar"), []ts.Exemplar{inBlock})
+	exemplars.Add([]byte("baz"), []ts.Exemplar{afterBlock})
+
+	require.NoError(t, exemplars.Persist(blockStart, blockSize, func(id []byte) bool {
+		return string(id) == "bar"
+	}))
+	// Nothing is written for blocks without exemplars.
+	require.NoError(t, exemplars.Persist(nextBlock.Add(blockSize), blockSize, nil))
+
+	blockStarts, err := exemplars.persistedBlockStarts()
+	require.NoError(t, err)
+	assert.Equal(t, []xtime.UnixNano{blockStart}, blockStarts)
+
+	loaded := newSeriesExemplars(exemplars.fsOpts, ident.StringID("ns"), 0, 10)
+	require.NoError(t, loaded.Load())
+	assert.Equal(t, []ts.Exemplar{inBlock},
+		loaded.Fetch([]byte("foo"), blockStart, nextBlock.Add(blockSize)))
+	assert.Empty(t, loaded.Fetch([]byte("bar"), blockStart, nextBlock))
+	assert.Empty(t, loaded.Fetch([]byte("baz"), blockStart, nextBlock.Add(blockSize)))
+
+	// Persisted exemplars are removed once out of retention.
+	require.NoError(t, exemplars.RemoveBefore(nextBlock))
+	_, err = os.Stat(exemplars.filePath(blockStart))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestSeriesExemplarsLoadCorrupt(t *testing.T) {
+	exemplars, dir := newTestSeriesExemplars(t, 10)
+	defer os.RemoveAll(dir)
+
+	blockStart := xtime.Now().Truncate(time.Hour)
+	exemplars.Add([]byte("foo"), []ts.Exemplar{newTestExemplar(blockStart, 1, "a")})
+	require.NoError(t, exemplars.Persist(blockStart, time.Hour, nil))
+
+	filePath := exemplars.filePath(blockStart)
+	data, err := ioutil.ReadFile(filePath)
+	require.NoError(t, err)
+	data[len(data)-5]++
+	require.NoError(t, ioutil.WriteFile(filePath, data, 0644))
+
+	loaded := newSeriesExemplars(exemplars.fsOpts, ident.StringID("ns"), 0, 10)
+	assert.Error(t, loaded.Load())
+}
+
+func TestSeriesExemplarsPersistAndLoadEncrypted(t *testing.T) {
+	exemplars, dir := newTestSeriesExemplars(t, 10)
+	defer os.RemoveAll(dir)
+	exemplars.fsOpts = withTestEncryption(t, exemplars.fsOpts, dir)
+
+	var (
+		blockSize  = 2 * time.Hour
+		blockStart = xtime.Now().Truncate(blockSize)
+		exemplar   = newTestExemplar(blockStart.Add(time.Minute), 1.5, "trace")
+	)
+	exemplars.Add([]byte("foo"), []ts.Exemplar{exemplar})
+	require.NoError(t, exemplars.Persist(blockStart, blockSize, nil))
+
+	data, err := ioutil.ReadFile(exemplars.filePath(blockStart))
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "trace")
+
+	loaded := newSeriesExemplars(exemplars.fsOpts, ident.StringID("ns"), 0, 10)
+	require.NoError(t, loaded.Load())
+	assert.Equal(t, []ts.Exemplar{exemplar},
+		loaded.Fetch([]byte("foo"), blockStart, blockStart.Add(blockSize)))
+}
@@ -322,6 +322,11 @@ func (m *flushManager) namespaceFlushTimes(ns databaseNamespace, curr xtime.Unix
 		blockSize        = rOpts.BlockSize()
 		earliest, latest = m.flushRange(rOpts, curr)
 	)
+	if window := ns.Options().OutOfOrderWindow(); window > 0 {
+		// Blocks still accepting writes within the out of order window are
+		// not warm flushed until the window has passed.
+		_, latest = m.flushRange(rOpts, curr.Add(-window))
+	}
 
 	candidateTimes := timesInRange(earliest, latest, blockSize)
 	var loopErr error
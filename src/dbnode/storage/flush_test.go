@@ -519,6 +519,32 @@ func TestFlushManagerNamespaceFlushTimesAllNeedFlush(t *testing.T) {
 	}
 }
 
+func TestFlushManagerNamespaceFlushTimesOutOfOrderWindow(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	fm, _, _, _ := newMultipleFlushManagerNeedsFlush(t, ctrl)
+	now := xtime.Now()
+
+	opts := namespace.NewOptions()
+	opts = opts.SetOutOfOrderWindow(opts.RetentionOptions().BlockSize())
+	ns := NewMockdatabaseNamespace(ctrl)
+	ns.EXPECT().Options().Return(opts).AnyTimes()
+	ns.EXPECT().NeedsFlush(gomock.Any(), gomock.Any()).Return(true, nil).AnyTimes()
+
+	times, err := fm.namespaceFlushTimes(ns, now)
+	require.NoError(t, err)
+	sort.Sort(timesInOrder(times))
+
+	// The block still within the out of order window is not flushed yet.
+	blockSize := opts.RetentionOptions().BlockSize()
+	start := retention.FlushTimeStart(opts.RetentionOptions(), now)
+	end := retention.FlushTimeEnd(opts.RetentionOptions(), now).Add(-blockSize)
+
+	require.Equal(t, numIntervals(start, end, blockSize), len(times))
+	require.Equal(t, end, times[len(times)-1])
+}
+
 func TestFlushManagerNamespaceFlushTimesSomeNeedFlush(t *testing.T) {
 	ctrl := xtest.NewController(t)
 	defer ctrl.Finish()
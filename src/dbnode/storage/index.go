@@ -82,6 +82,7 @@ var (
 	errDbIndexTerminatingTickCancellation = errors.New("terminating tick early due to cancellation")
 	errDbIndexIsBootstrapping             = errors.New("index is already bootstrapping")
 	errDbIndexDoNotIndexSeries            = errors.New("series matched do not index fields")
+	errDbIndexLabelCardinalityExceeded    = errors.New("series label values exceed label cardinality limit")
 )
 
 const (
@@ -111,6 +112,7 @@ type nsIndex struct {
 	indexFilesetsBeforeFn   indexFilesetsBeforeFn
 	deleteFilesFn           deleteFilesFn
 	readIndexInfoFilesFn    readIndexInfoFilesFn
+	readIndexSegmentsFn     readIndexSegmentsFn
 
 	newBlockFn            index.NewBlockFn
 	logger                *zap.Logger
@@ -136,6 +138,7 @@ type nsIndex struct {
 	forwardIndexDice forwardIndexDice
 
 	doNotIndexWithFields []doc.Field
+	labelCardinality     limits.LabelCardinalityLimiter
 	shardSet             sharding.ShardSet
 
 	activeBlock index.Block
@@ -201,6 +204,8 @@ type indexFilesetsBeforeFn func(dir string,
 
 type readIndexInfoFilesFn func(opts fs.ReadIndexInfoFilesOptions) []fs.ReadIndexInfoFileResult
 
+type readIndexSegmentsFn func(opts fs.ReadIndexSegmentsOptions) (fs.ReadIndexSegmentsResult, error)
+
 type newNamespaceIndexOpts struct {
 	md                      namespace.Metadata
 	namespaceRuntimeOptsMgr namespace.RuntimeOptionsManager
@@ -338,6 +343,11 @@ func newNamespaceIndexWithOptions(
 	nowFn := indexOpts.ClockOptions().NowFn()
 	logger := indexOpts.InstrumentOptions().Logger()
 
+	// Writes within the out of order window are accepted and merged into the
+	// warm flush, so blocks are not sealed until the window has passed.
+	bufferPast := nsMD.Options().RetentionOptions().BufferPast() +
+		nsMD.Options().OutOfOrderWindow()
+
 	var doNotIndexWithFields []doc.Field
 	if m := newIndexOpts.opts.DoNotIndexWithFieldsMap(); m != nil && len(m) != 0 {
 		for k, v := range m {
@@ -348,6 +358,13 @@ func newNamespaceIndexWithOptions(
 		}
 	}
 
+	labelCardinality, err := limits.NewLabelCardinalityLimiter(nsMD.ID().String(),
+		newIndexOpts.opts.LimitsOptions().LabelCardinalityOptions(),
+		nsMD.Options().IndexOptions().BlockSize(), instrumentOpts)
+	if err != nil {
+		return nil, err
+	}
+
 	idx := &nsIndex{
 		state: nsIndexState{
 			closeCh: make(chan struct{}),
@@ -362,13 +379,14 @@ func newNamespaceIndexWithOptions(
 		blockSize:             nsMD.Options().IndexOptions().BlockSize(),
 		retentionPeriod:       nsMD.Options().RetentionOptions().RetentionPeriod(),
 		futureRetentionPeriod: nsMD.Options().RetentionOptions().FutureRetentionPeriod(),
-		bufferPast:            nsMD.Options().RetentionOptions().BufferPast(),
+		bufferPast:            bufferPast,
 		bufferFuture:          nsMD.Options().RetentionOptions().BufferFuture(),
 		coldWritesEnabled:     nsMD.Options().ColdWritesEnabled(),
 
 		namespaceRuntimeOptsMgr: newIndexOpts.namespaceRuntimeOptsMgr,
 		indexFilesetsBeforeFn:   fs.IndexFileSetsBefore,
 		readIndexInfoFilesFn:    fs.ReadIndexInfoFiles,
+		readIndexSegmentsFn:     fs.ReadIndexSegments,
 		deleteFilesFn:           fs.DeleteFiles,
 
 		newBlockFn: newBlockFn,
@@ -383,6 +401,7 @@ func newNamespaceIndexWithOptions(
 		metrics:        newNamespaceIndexMetrics(indexOpts, instrumentOpts),
 
 		doNotIndexWithFields: doNotIndexWithFields,
+		labelCardinality:     labelCardinality,
 		shardSet:             shardSet,
 	}
 
@@ -727,6 +746,7 @@ func (i *nsIndex) writeBatches(
 		notSkipped                 int
 		forwardIndexHits           int
 		forwardIndexMiss           int
+		labelsRejected             []int
 
 		forwardIndexBatch *index.WriteBatch
 	)
@@ -790,6 +810,21 @@ func (i *nsIndex) writeBatches(
 				return
 			}
 
+			// NB: only admit label values of series which are going to be
+			// indexed so that writes outside of retention are not tracked.
+			if i.labelCardinality.Enabled() {
+				labelsRejected = i.labelCardinality.Admit(d.Fields, labelsRejected[:0])
+				if len(labelsRejected) != 0 {
+					var ok bool
+					d, ok = i.applyLabelCardinalityLimit(d, labelsRejected)
+					if !ok {
+						batch.MarkUnmarkedEntryError(errDbIndexLabelCardinalityExceeded, idx)
+						return
+					}
+					batch.SetDoc(idx, d)
+				}
+			}
+
 			if forwardIndexEnabled {
 				if forwardIndexDice.roll(ts) {
 					forwardIndexHits++
@@ -1348,8 +1383,9 @@ func (i *nsIndex) flushBlockSegment(
 
 	for _, shard := range shards {
 		var (
-			first     = true
-			pageToken PageToken
+			first       = true
+			pageToken   PageToken
+			anyDeletion = shard.HasDeletedSeries()
 		)
 		for first || pageToken != nil {
 			first = false
@@ -1378,6 +1414,14 @@ func (i *nsIndex) flushBlockSegment(
 			// Reset docs batch before use.
 			batch.Docs = batch.Docs[:0]
 			for _, result := range results.Results() {
+				if anyDeletion &&
+					shard.IsSeriesDeleted(result.ID, indexBlock.StartTime(), indexBlock.EndTime()) {
+					// Series deleted for the whole block are dropped from the
+					// index even while still present in filesets on disk.
+					i.metrics.flushDocsDeleted.Inc(1)
+					continue
+				}
+
 				doc, exists, err := shard.DocRef(result.ID)
 				if err != nil {
 					return err
@@ -1426,6 +1470,38 @@ func (i *nsIndex) flushBlockSegment(
 	return preparedPersist.Persist(builder)
 }
 
+// applyLabelCardinalityLimit applies the label cardinality limit action to a
+// document with labels that were not admitted, returning the document to index
+// or false if the series should not be indexed at all.
+func (i *nsIndex) applyLabelCardinalityLimit(
+	d doc.Metadata,
+	rejected []int,
+) (doc.Metadata, bool) {
+	if i.labelCardinality.Action() != limits.StripLabelCardinalityAction {
+		i.metrics.labelCardinalityRejected.Inc(1)
+		return d, false
+	}
+
+	// NB: copy the fields since they are shared with the series.
+	fields := make([]doc.Field, 0, len(d.Fields)-len(rejected))
+	for j, f := range d.Fields {
+		if len(rejected) != 0 && rejected[0] == j {
+			rejected = rejected[1:]
+			continue
+		}
+		fields = append(fields, f)
+	}
+	d.Fields = fields
+	i.metrics.labelCardinalityStripped.Inc(1)
+	return d, true
+}
+
+// LabelCardinality returns the cardinality of each label tracked by the
+// label cardinality limits of the namespace.
+func (i *nsIndex) LabelCardinality() []limits.LabelCardinality {
+	return i.labelCardinality.Report()
+}
+
 func (i *nsIndex) sanitizeAllowDuplicatesWriteError(err error) error {
 	if err == nil {
 		return nil
@@ -2540,6 +2616,10 @@ type nsIndexMetrics struct {
 	forwardIndexCounter              tally.Counter
 	insertEndToEndLatency            tally.Timer
 	blocksEvictedMutableSegments     tally.Counter
+	persistedCompactions             tally.Counter
+	persistedCompactedVolumes        tally.Counter
+	persistedCompactionErrors        tally.Counter
+	persistedCompactionDeletedDocs   tally.Counter
 	blockMetrics                     nsIndexBlocksMetrics
 	indexingConcurrencyMin           tally.Gauge
 	indexingConcurrencyMax           tally.Gauge
@@ -2547,6 +2627,9 @@ type nsIndexMetrics struct {
 	flushIndexingConcurrency         tally.Gauge
 	flushDocsNew                     tally.Counter
 	flushDocsCached                  tally.Counter
+	flushDocsDeleted                 tally.Counter
+	labelCardinalityRejected         tally.Counter
+	labelCardinalityStripped         tally.Counter
 	latestBlockNumSegmentsForeground tally.Gauge
 	latestBlockNumDocsForeground     tally.Gauge
 	latestBlockNumSegmentsBackground tally.Gauge
@@ -2606,8 +2689,12 @@ func newNamespaceIndexMetrics(
 		}).Counter(forwardIndexName),
 		insertEndToEndLatency: instrument.NewTimer(scope,
 			"insert-end-to-end-latency", iopts.TimerOptions()),
-		blocksEvictedMutableSegments: scope.Counter("blocks-evicted-mutable-segments"),
-		blockMetrics:                 newNamespaceIndexBlocksMetrics(opts, blocksScope),
+		blocksEvictedMutableSegments:   scope.Counter("blocks-evicted-mutable-segments"),
+		persistedCompactions:           scope.Counter("persisted-compactions"),
+		persistedCompactedVolumes:      scope.Counter("persisted-compacted-volumes"),
+		persistedCompactionErrors:      scope.Counter("persisted-compaction-errors"),
+		persistedCompactionDeletedDocs: scope.Counter("persisted-compaction-deleted-docs"),
+		blockMetrics:                   newNamespaceIndexBlocksMetrics(opts, blocksScope),
 		indexingConcurrencyMin: scope.Tagged(map[string]string{
 			"stat": "min",
 		}).Gauge(indexingConcurrency),
@@ -2624,6 +2711,15 @@ func newNamespaceIndexMetrics(
 		flushDocsCached: scope.Tagged(map[string]string{
 			"status": "cached",
 		}).Counter("flush-docs"),
+		flushDocsDeleted: scope.Tagged(map[string]string{
+			"status": "deleted",
+		}).Counter("flush-docs"),
+		labelCardinalityRejected: scope.Tagged(map[string]string{
+			"action": "reject",
+		}).Counter("label-cardinality-limited-series"),
+		labelCardinalityStripped: scope.Tagged(map[string]string{
+			"action": "strip",
+		}).Counter("label-cardinality-limited-series"),
 		latestBlockNumSegmentsForeground: scope.Tagged(map[string]string{
 			"segment_type": "foreground",
 		}).Gauge("latest-block-num-segments"),
@@ -0,0 +1,219 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package index
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/m3db/m3/src/m3ninx/doc"
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+// DefaultCardinalityLimit is the number of entries returned for each
+// cardinality statistic when no limit is specified.
+const DefaultCardinalityLimit = 10
+
+// CardinalityOptions are the options for a cardinality query.
+type CardinalityOptions struct {
+	// StartInclusive is the start time for the query.
+	StartInclusive xtime.UnixNano
+	// EndExclusive is the exclusive end for the query.
+	EndExclusive xtime.UnixNano
+	// Shards restricts the query to series owned by the given shards,
+	// if empty all shards are included.
+	Shards []uint32
+	// NameField is the field holding the metric name of a series.
+	NameField []byte
+	// Limit is the number of entries returned for each statistic.
+	Limit int
+	// Source is an optional query source.
+	Source []byte
+	// SeriesLimit is the maximum number of series to match.
+	SeriesLimit int
+	// DocsLimit is the maximum number of documents to match.
+	DocsLimit int
+	// RequireExhaustive requires the query to match all series rather than
+	// returning statistics of a subset of series when a limit is reached.
+	RequireExhaustive bool
+}
+
+// CardinalityStat is a single named cardinality statistic.
+type CardinalityStat struct {
+	Name  string
+	Value int64
+}
+
+// CardinalityResult is the result of a cardinality query, each list of
+// statistics is sorted by value in descending order and truncated to
+// the query limit.
+type CardinalityResult struct {
+	// NumSeries is the number of series matched.
+	NumSeries int64
+	// SeriesCountByMetricName is the number of series per metric name.
+	SeriesCountByMetricName []CardinalityStat
+	// LabelValueCountByLabelName is the number of distinct values per label name.
+	LabelValueCountByLabelName []CardinalityStat
+	// MemoryInBytesByLabelName is the total length of distinct values per label name.
+	MemoryInBytesByLabelName []CardinalityStat
+	// SeriesCountByLabelValuePair is the number of series per label name/value pair.
+	SeriesCountByLabelValuePair []CardinalityStat
+	// Exhaustive is false when a limit was reached and the statistics only
+	// cover a subset of the matched series.
+	Exhaustive bool
+}
+
+// CardinalityAccumulator accumulates cardinality statistics over a
+// set of series, each series must be added at most once.
+type CardinalityAccumulator struct {
+	nameField     []byte
+	numSeries     int64
+	seriesByName  map[string]int64
+	seriesByPair  map[string]int64
+	valuesByLabel map[string]map[string]struct{}
+}
+
+// NewCardinalityAccumulator returns a new cardinality accumulator that
+// reads metric names from the given field.
+func NewCardinalityAccumulator(nameField []byte) *CardinalityAccumulator {
+	return &CardinalityAccumulator{
+		nameField:     nameField,
+		seriesByName:  make(map[string]int64),
+		seriesByPair:  make(map[string]int64),
+		valuesByLabel: make(map[string]map[string]struct{}),
+	}
+}
+
+// Add adds the metadata of a series to the accumulated statistics.
+func (a *CardinalityAccumulator) Add(m doc.Metadata) {
+	a.numSeries++
+	for _, f := range m.Fields { // nolint:gocritic
+		var (
+			name  = string(f.Name)
+			value = string(f.Value)
+		)
+		if len(a.nameField) > 0 && bytes.Equal(f.Name, a.nameField) {
+			a.seriesByName[value]++
+		}
+		a.seriesByPair[name+"="+value]++
+
+		values, ok := a.valuesByLabel[name]
+		if !ok {
+			values = make(map[string]struct{})
+			a.valuesByLabel[name] = values
+		}
+		values[value] = struct{}{}
+	}
+}
+
+// Result returns the accumulated statistics, keeping the top limit
+// entries of each statistic.
+func (a *CardinalityAccumulator) Result(limit int) CardinalityResult {
+	var (
+		valueCounts = make(map[string]int64, len(a.valuesByLabel))
+		memory      = make(map[string]int64, len(a.valuesByLabel))
+	)
+	for name, values := range a.valuesByLabel {
+		valueCounts[name] = int64(len(values))
+		for value := range values {
+			memory[name] += int64(len(value))
+		}
+	}
+
+	return CardinalityResult{
+		NumSeries:                   a.numSeries,
+		SeriesCountByMetricName:     topCardinalityStats(a.seriesByName, limit),
+		LabelValueCountByLabelName:  topCardinalityStats(valueCounts, limit),
+		MemoryInBytesByLabelName:    topCardinalityStats(memory, limit),
+		SeriesCountByLabelValuePair: topCardinalityStats(a.seriesByPair, limit),
+		Exhaustive:                  true,
+	}
+}
+
+// MergeCardinalityResults merges results computed over disjoint sets of
+// series, e.g. results from hosts queried for distinct shards. Series counts
+// are summed, whereas label value counts and memory usage take the maximum
+// across results since the same label values are generally present in every
+// shard; these are therefore a lower bound of the true values. The merged
+// result is only exhaustive if every result is exhaustive.
+func MergeCardinalityResults(
+	results []CardinalityResult,
+	limit int,
+) CardinalityResult {
+	var (
+		merged       = CardinalityResult{Exhaustive: true}
+		seriesByName = make(map[string]int64)
+		seriesByPair = make(map[string]int64)
+		valueCounts  = make(map[string]int64)
+		memory       = make(map[string]int64)
+	)
+	for _, r := range results { // nolint:gocritic
+		merged.NumSeries += r.NumSeries
+		merged.Exhaustive = merged.Exhaustive && r.Exhaustive
+		sumCardinalityStats(seriesByName, r.SeriesCountByMetricName)
+		sumCardinalityStats(seriesByPair, r.SeriesCountByLabelValuePair)
+		maxCardinalityStats(valueCounts, r.LabelValueCountByLabelName)
+		maxCardinalityStats(memory, r.MemoryInBytesByLabelName)
+	}
+
+	merged.SeriesCountByMetricName = topCardinalityStats(seriesByName, limit)
+	merged.LabelValueCountByLabelName = topCardinalityStats(valueCounts, limit)
+	merged.MemoryInBytesByLabelName = topCardinalityStats(memory, limit)
+	merged.SeriesCountByLabelValuePair = topCardinalityStats(seriesByPair, limit)
+	return merged
+}
+
+func sumCardinalityStats(dst map[string]int64, stats []CardinalityStat) {
+	for _, s := range stats {
+		dst[s.Name] += s.Value
+	}
+}
+
+func maxCardinalityStats(dst map[string]int64, stats []CardinalityStat) {
+	for _, s := range stats {
+		if v, ok := dst[s.Name]; !ok || s.Value > v {
+			dst[s.Name] = s.Value
+		}
+	}
+}
+
+// topCardinalityStats returns the limit largest values, ties are ordered
+// by name so that results are deterministic.
+func topCardinalityStats(values map[string]int64, limit int) []CardinalityStat {
+	if limit <= 0 {
+		limit = DefaultCardinalityLimit
+	}
+
+	stats := make([]CardinalityStat, 0, len(values))
+	for name, value := range values {
+		stats = append(stats, CardinalityStat{Name: name, Value: value})
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Value != stats[j].Value {
+			return stats[i].Value > stats[j].Value
+		}
+		return stats[i].Name < stats[j].Name
+	})
+	if len(stats) > limit {
+		stats = stats[:limit]
+	}
+	return stats
+}
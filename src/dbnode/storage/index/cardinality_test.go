@@ -0,0 +1,116 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package index
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/m3db/m3/src/m3ninx/doc"
+)
+
+func cardinalityTestMetadata(id string, fields ...string) doc.Metadata {
+	m := doc.Metadata{ID: []byte(id)}
+	for i := 0; i < len(fields); i += 2 {
+		m.Fields = append(m.Fields, doc.Field{
+			Name:  []byte(fields[i]),
+			Value: []byte(fields[i+1]),
+		})
+	}
+	return m
+}
+
+func TestCardinalityAccumulator(t *testing.T) {
+	acc := NewCardinalityAccumulator([]byte("__name__"))
+	acc.Add(cardinalityTestMetadata("a", "__name__", "up", "job", "api", "instance", "h1"))
+	acc.Add(cardinalityTestMetadata("b", "__name__", "up", "job", "api", "instance", "h2"))
+	acc.Add(cardinalityTestMetadata("c", "__name__", "up", "job", "db", "instance", "h1"))
+	acc.Add(cardinalityTestMetadata("d", "__name__", "requests", "job", "api"))
+
+	res := acc.Result(2)
+	require.Equal(t, int64(4), res.NumSeries)
+	require.Equal(t, []CardinalityStat{
+		{Name: "up", Value: 3},
+		{Name: "requests", Value: 1},
+	}, res.SeriesCountByMetricName)
+	require.Equal(t, []CardinalityStat{
+		{Name: "__name__", Value: 2},
+		{Name: "instance", Value: 2},
+	}, res.LabelValueCountByLabelName)
+	require.Equal(t, []CardinalityStat{
+		{Name: "__name__", Value: 10},
+		{Name: "job", Value: 5},
+	}, res.MemoryInBytesByLabelName)
+	require.Equal(t, []CardinalityStat{
+		{Name: "__name__=up", Value: 3},
+		{Name: "job=api", Value: 3},
+	}, res.SeriesCountByLabelValuePair)
+}
+
+func TestCardinalityAccumulatorDefaultLimit(t *testing.T) {
+	acc := NewCardinalityAccumulator([]byte("__name__"))
+	for i := 0; i < 2*DefaultCardinalityLimit; i++ {
+		acc.Add(cardinalityTestMetadata("id", "__name__", string(rune('a'+i))))
+	}
+
+	res := acc.Result(0)
+	require.Equal(t, int64(2*DefaultCardinalityLimit), res.NumSeries)
+	require.Len(t, res.SeriesCountByMetricName, DefaultCardinalityLimit)
+	require.Equal(t, "a", res.SeriesCountByMetricName[0].Name)
+}
+
+func TestMergeCardinalityResults(t *testing.T) {
+	results := []CardinalityResult{
+		{
+			NumSeries:                   3,
+			SeriesCountByMetricName:     []CardinalityStat{{Name: "up", Value: 2}, {Name: "foo", Value: 1}},
+			LabelValueCountByLabelName:  []CardinalityStat{{Name: "job", Value: 2}},
+			MemoryInBytesByLabelName:    []CardinalityStat{{Name: "job", Value: 5}},
+			SeriesCountByLabelValuePair: []CardinalityStat{{Name: "job=api", Value: 2}},
+			Exhaustive:                  true,
+		},
+		{
+			NumSeries:                   4,
+			SeriesCountByMetricName:     []CardinalityStat{{Name: "foo", Value: 3}, {Name: "up", Value: 1}},
+			LabelValueCountByLabelName:  []CardinalityStat{{Name: "job", Value: 3}, {Name: "env", Value: 1}},
+			MemoryInBytesByLabelName:    []CardinalityStat{{Name: "job", Value: 8}, {Name: "env", Value: 4}},
+			SeriesCountByLabelValuePair: []CardinalityStat{{Name: "job=api", Value: 1}, {Name: "env=prod", Value: 4}},
+		},
+	}
+
+	res := MergeCardinalityResults(results, 10)
+	require.Equal(t, CardinalityResult{
+		NumSeries:                   7,
+		SeriesCountByMetricName:     []CardinalityStat{{Name: "foo", Value: 4}, {Name: "up", Value: 3}},
+		LabelValueCountByLabelName:  []CardinalityStat{{Name: "job", Value: 3}, {Name: "env", Value: 1}},
+		MemoryInBytesByLabelName:    []CardinalityStat{{Name: "job", Value: 8}, {Name: "env", Value: 4}},
+		SeriesCountByLabelValuePair: []CardinalityStat{{Name: "env=prod", Value: 4}, {Name: "job=api", Value: 3}},
+		Exhaustive:                  false,
+	}, res)
+
+	res = MergeCardinalityResults(results, 1)
+	require.Equal(t, []CardinalityStat{{Name: "foo", Value: 4}}, res.SeriesCountByMetricName)
+
+	results[1].Exhaustive = true
+	res = MergeCardinalityResults(results, 10)
+	require.True(t, res.Exhaustive)
+}
@@ -0,0 +1,195 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package compaction
+
+import (
+	"errors"
+	"sort"
+	"time"
+)
+
+var (
+	errPersistedMinBlockAgeNegative = errors.New("persisted compaction min block age must be positive")
+	errPersistedMinVolumesTooLow    = errors.New("persisted compaction min volumes per task must be at least two")
+)
+
+var (
+	// DefaultPersistedLevels are the default size tiers, in bytes on disk,
+	// used to group persisted index volumes for compaction,
+	// i.e. tiers for compaction [0, 64MiB), [64MiB, 512MiB), [512MiB, 4GiB).
+	DefaultPersistedLevels = []Level{
+		{
+			MinSizeInclusive: 0,
+			MaxSizeExclusive: 1 << 26,
+		},
+		{
+			MinSizeInclusive: 1 << 26,
+			MaxSizeExclusive: 1 << 29,
+		},
+		{
+			MinSizeInclusive: 1 << 29,
+			MaxSizeExclusive: 1 << 32,
+		},
+	}
+
+	// DefaultPersistedOptions are the default PersistedPlannerOptions.
+	DefaultPersistedOptions = PersistedPlannerOptions{
+		Enabled:           false,                  // opt-in since it rewrites index filesets
+		MinBlockAge:       24 * time.Hour,         // only compact cold blocks
+		MinVolumesPerTask: 2,                      // merging a single volume achieves nothing
+		Levels:            DefaultPersistedLevels, // sizes defined above
+	}
+)
+
+// PersistedPlannerOptions are the knobs to tweak planning of compactions
+// of persisted index volumes, i.e. index filesets already flushed to disk.
+type PersistedPlannerOptions struct {
+	// Enabled determines whether persisted index volumes are compacted.
+	Enabled bool
+	// MinBlockAge is the minimum time that must have passed since the end
+	// of an index block before its persisted volumes are compacted.
+	MinBlockAge time.Duration
+	// MinVolumesPerTask is the minimum number of volumes within a level
+	// required to produce a compaction task.
+	MinVolumesPerTask int
+	// Levels define the size tiers, in bytes on disk, volumes are grouped
+	// by before compaction.
+	Levels []Level
+}
+
+// Validate ensures the receiver PersistedPlannerOptions specify valid values
+// for each of the knobs.
+func (o PersistedPlannerOptions) Validate() error {
+	if o.MinBlockAge < 0 {
+		return errPersistedMinBlockAgeNegative
+	}
+	if o.MinVolumesPerTask < 2 {
+		return errPersistedMinVolumesTooLow
+	}
+	return PlannerOptions{Levels: o.Levels}.Validate()
+}
+
+// PersistedVolume identifies a persisted index volume candidate for compaction.
+type PersistedVolume struct {
+	VolumeIndex int
+	Size        int64
+	Shards      []uint32
+}
+
+// PersistedTask identifies a collection of persisted volumes to merge into a
+// single volume.
+type PersistedTask struct {
+	Volumes []PersistedVolume
+}
+
+// CumulativeSize returns the total size of the volumes in the task.
+func (t PersistedTask) CumulativeSize() int64 {
+	var size int64
+	for _, v := range t.Volumes {
+		size += v.Size
+	}
+	return size
+}
+
+// NewPersistedPlan returns the compaction tasks for the persisted volumes of
+// a single index block.
+//
+// The compacted volume is written with a higher volume index than any of the
+// volumes of the block and fileset cleanup deletes any volume followed by a
+// volume covering all of its shards, so a task is only ever the run of
+// volumes, in volume index order, ending at the newest volume. The run is
+// accumulated while volumes fall into the same level as the newest volume,
+// until the cumulative size reaches the max of the level, and is then extended
+// to any older volumes whose shards the run covers since the compacted volume
+// supersedes those too. Any run with fewer than the minimum volumes per task
+// is left as is, as is a newest volume outside all levels.
+func NewPersistedPlan(
+	volumes []PersistedVolume,
+	opts PersistedPlannerOptions,
+) ([]PersistedTask, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	if len(volumes) == 0 {
+		return nil, nil
+	}
+
+	// NB: making copies to ensure we don't modify any input vars.
+	sorted := make([]PersistedVolume, len(volumes))
+	copy(sorted, volumes)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].VolumeIndex < sorted[j].VolumeIndex
+	})
+
+	newest := len(sorted) - 1
+	level, ok := persistedLevel(opts.Levels, sorted[newest].Size)
+	if !ok {
+		return nil, nil
+	}
+
+	var (
+		first           = newest
+		accumulatedSize int64
+	)
+	for j := newest; j >= 0; j-- {
+		if l, ok := persistedLevel(opts.Levels, sorted[j].Size); !ok || l != level {
+			break
+		}
+		first = j
+		accumulatedSize += sorted[j].Size
+		if accumulatedSize >= level.MaxSizeExclusive {
+			break
+		}
+	}
+	if newest-first+1 < opts.MinVolumesPerTask {
+		return nil, nil
+	}
+
+	shards := make(map[uint32]struct{})
+	for _, v := range sorted[first:] {
+		for _, shard := range v.Shards {
+			shards[shard] = struct{}{}
+		}
+	}
+	for first > 0 && coversShards(shards, sorted[first-1].Shards) {
+		first--
+	}
+
+	return []PersistedTask{{Volumes: sorted[first:]}}, nil
+}
+
+func persistedLevel(levels []Level, size int64) (Level, bool) {
+	for _, l := range levels {
+		if l.MinSizeInclusive <= size && size < l.MaxSizeExclusive {
+			return l, true
+		}
+	}
+	return Level{}, false
+}
+
+func coversShards(covered map[uint32]struct{}, shards []uint32) bool {
+	for _, shard := range shards {
+		if _, ok := covered[shard]; !ok {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,154 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package compaction
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultPersistedOptsValidate(t *testing.T) {
+	require.NoError(t, DefaultPersistedOptions.Validate())
+}
+
+func TestPersistedOptsValidateInvalid(t *testing.T) {
+	opts := DefaultPersistedOptions
+	opts.MinVolumesPerTask = 1
+	require.Error(t, opts.Validate())
+
+	opts = DefaultPersistedOptions
+	opts.MinBlockAge = -1
+	require.Error(t, opts.Validate())
+
+	opts = DefaultPersistedOptions
+	opts.Levels = nil
+	require.Error(t, opts.Validate())
+}
+
+func TestPersistedPlanSingleVolume(t *testing.T) {
+	tasks, err := NewPersistedPlan([]PersistedVolume{
+		{VolumeIndex: 0, Size: 10},
+	}, DefaultPersistedOptions)
+	require.NoError(t, err)
+	require.Empty(t, tasks)
+}
+
+func TestPersistedPlanRunEndsAtNewestVolume(t *testing.T) {
+	opts := DefaultPersistedOptions
+	opts.Levels = []Level{
+		{MinSizeInclusive: 100, MaxSizeExclusive: 1000},
+		{MinSizeInclusive: 0, MaxSizeExclusive: 100},
+	}
+	tasks, err := NewPersistedPlan([]PersistedVolume{
+		{VolumeIndex: 3, Size: 10, Shards: []uint32{3}},
+		{VolumeIndex: 0, Size: 20, Shards: []uint32{0}},
+		{VolumeIndex: 1, Size: 500, Shards: []uint32{1}},
+		{VolumeIndex: 2, Size: 20, Shards: []uint32{2}},
+		{VolumeIndex: 4, Size: 30, Shards: []uint32{4}},
+	}, opts)
+	require.NoError(t, err)
+	// Volume 0 is in the same level as the newest volume but is not part of
+	// the run since volume 1 is in another level.
+	require.Equal(t, []PersistedTask{
+		{Volumes: []PersistedVolume{
+			{VolumeIndex: 2, Size: 20, Shards: []uint32{2}},
+			{VolumeIndex: 3, Size: 10, Shards: []uint32{3}},
+			{VolumeIndex: 4, Size: 30, Shards: []uint32{4}},
+		}},
+	}, tasks)
+}
+
+func TestPersistedPlanMiddleVolumeInOtherLevel(t *testing.T) {
+	opts := DefaultPersistedOptions
+	opts.Levels = []Level{
+		{MinSizeInclusive: 100, MaxSizeExclusive: 1000},
+		{MinSizeInclusive: 0, MaxSizeExclusive: 100},
+	}
+	tasks, err := NewPersistedPlan([]PersistedVolume{
+		{VolumeIndex: 0, Size: 10, Shards: []uint32{0, 1}},
+		{VolumeIndex: 1, Size: 500, Shards: []uint32{1}},
+		{VolumeIndex: 2, Size: 20, Shards: []uint32{2}},
+	}, opts)
+	require.NoError(t, err)
+	require.Empty(t, tasks)
+}
+
+func TestPersistedPlanNewestVolumeOutsideLevels(t *testing.T) {
+	opts := DefaultPersistedOptions
+	opts.Levels = []Level{
+		{MinSizeInclusive: 0, MaxSizeExclusive: 100},
+	}
+	tasks, err := NewPersistedPlan([]PersistedVolume{
+		{VolumeIndex: 0, Size: 10},
+		{VolumeIndex: 1, Size: 20},
+		{VolumeIndex: 2, Size: 2000},
+	}, opts)
+	require.NoError(t, err)
+	require.Empty(t, tasks)
+}
+
+func TestPersistedPlanSplitsAtLevelMax(t *testing.T) {
+	opts := DefaultPersistedOptions
+	opts.Levels = []Level{
+		{MinSizeInclusive: 0, MaxSizeExclusive: 100},
+	}
+	tasks, err := NewPersistedPlan([]PersistedVolume{
+		{VolumeIndex: 0, Size: 60, Shards: []uint32{0}},
+		{VolumeIndex: 1, Size: 50, Shards: []uint32{1}},
+		{VolumeIndex: 2, Size: 90, Shards: []uint32{2}},
+		{VolumeIndex: 3, Size: 20, Shards: []uint32{3}},
+		{VolumeIndex: 4, Size: 30, Shards: []uint32{4}},
+	}, opts)
+	require.NoError(t, err)
+	require.Equal(t, []PersistedTask{
+		{Volumes: []PersistedVolume{
+			{VolumeIndex: 2, Size: 90, Shards: []uint32{2}},
+			{VolumeIndex: 3, Size: 20, Shards: []uint32{3}},
+			{VolumeIndex: 4, Size: 30, Shards: []uint32{4}},
+		}},
+	}, tasks)
+	require.Equal(t, int64(140), tasks[0].CumulativeSize())
+}
+
+func TestPersistedPlanIncludesCoveredVolumes(t *testing.T) {
+	opts := DefaultPersistedOptions
+	opts.Levels = []Level{
+		{MinSizeInclusive: 100, MaxSizeExclusive: 1000},
+		{MinSizeInclusive: 0, MaxSizeExclusive: 100},
+	}
+	tasks, err := NewPersistedPlan([]PersistedVolume{
+		{VolumeIndex: 0, Size: 500, Shards: []uint32{0, 2}},
+		{VolumeIndex: 1, Size: 500, Shards: []uint32{0, 1}},
+		{VolumeIndex: 2, Size: 20, Shards: []uint32{0}},
+		{VolumeIndex: 3, Size: 30, Shards: []uint32{1}},
+	}, opts)
+	require.NoError(t, err)
+	// Volume 1 is in another level but the compacted volume covers all of its
+	// shards, so it would be deleted by fileset cleanup if left out.
+	require.Equal(t, []PersistedTask{
+		{Volumes: []PersistedVolume{
+			{VolumeIndex: 1, Size: 500, Shards: []uint32{0, 1}},
+			{VolumeIndex: 2, Size: 20, Shards: []uint32{0}},
+			{VolumeIndex: 3, Size: 30, Shards: []uint32{1}},
+		}},
+	}, tasks)
+}
@@ -21,11 +21,13 @@
 package convert
 
 import (
+	"bytes"
 	"errors"
 
 	"github.com/m3db/m3/src/dbnode/ts"
 	"github.com/m3db/m3/src/m3ninx/doc"
 	"github.com/m3db/m3/src/x/ident"
+	"github.com/m3db/m3/src/x/serialize"
 )
 
 var (
@@ -97,3 +99,35 @@ func (t TagMetadataResolver) Resolve(id ident.ID) (doc.Metadata, error) {
 		return doc.Metadata{}, ErrUnknownTagMetadataResolverType
 	}
 }
+
+// TagValue returns the value of the tag with the given name without
+// resolving the full metadata, the bool is false if there is no such tag.
+func (t TagMetadataResolver) TagValue(name []byte) ([]byte, bool, error) {
+	switch t.resolverType {
+	case tagResolverEncodedTags:
+		if len(t.encodedTags) == 0 {
+			return nil, false, nil
+		}
+		return serialize.TagValueFromEncodedTagsFast(t.encodedTags, name)
+	case tagResolverIter:
+		tagsIter := t.tagsIter.Duplicate()
+		defer tagsIter.Close()
+
+		for tagsIter.Next() {
+			tag := tagsIter.Current()
+			if bytes.Equal(tag.Name.Bytes(), name) {
+				return tag.Value.Bytes(), true, nil
+			}
+		}
+		return nil, false, tagsIter.Err()
+	case tagResolverTags:
+		for _, tag := range t.tags.Values() {
+			if bytes.Equal(tag.Name.Bytes(), name) {
+				return tag.Value.Bytes(), true, nil
+			}
+		}
+		return nil, false, nil
+	default:
+		return nil, false, ErrUnknownTagMetadataResolverType
+	}
+}
@@ -68,6 +68,36 @@ func TestTagsMetadataResolver(t *testing.T) {
 	assertFieldValue(t, metadata, "__name__", "foo")
 }
 
+func TestTagMetadataResolverTagValue(t *testing.T) {
+	encodedTags, err := base64.StdEncoding.DecodeString(encodedTagSample)
+	require.NoError(t, err)
+
+	tags := ident.NewTags(
+		ident.StringTag("name", "foo"),
+		ident.StringTag("team", "SF"))
+	resolvers := map[string]TagMetadataResolver{
+		"encoded": NewEncodedTagsMetadataResolver(encodedTags),
+		"iter":    NewTagsIterMetadataResolver(ident.NewTagsIterator(tags)),
+		"tags":    NewTagsMetadataResolver(tags),
+	}
+	for name, sut := range resolvers {
+		t.Run(name, func(t *testing.T) {
+			value, ok, err := sut.TagValue([]byte("team"))
+			require.NoError(t, err)
+			require.True(t, ok)
+			require.Equal(t, "SF", string(value))
+
+			_, ok, err = sut.TagValue([]byte("missing"))
+			require.NoError(t, err)
+			require.False(t, ok)
+		})
+	}
+
+	_, ok, err := EmptyTagMetadataResolver.TagValue([]byte("team"))
+	require.NoError(t, err)
+	require.False(t, ok)
+}
+
 func assertFieldValue(t *testing.T, metadata doc.Metadata, expectedFieldName, expectedValue string) {
 	val, ok := metadata.Get([]byte(expectedFieldName))
 	require.True(t, ok)
@@ -1895,6 +1895,20 @@ func (mr *MockOptionsMockRecorder) MmapReporter() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MmapReporter", reflect.TypeOf((*MockOptions)(nil).MmapReporter))
 }
 
+// PersistedCompactionPlannerOptions mocks base method.
+func (m *MockOptions) PersistedCompactionPlannerOptions() compaction.PersistedPlannerOptions {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PersistedCompactionPlannerOptions")
+	ret0, _ := ret[0].(compaction.PersistedPlannerOptions)
+	return ret0
+}
+
+// PersistedCompactionPlannerOptions indicates an expected call of PersistedCompactionPlannerOptions.
+func (mr *MockOptionsMockRecorder) PersistedCompactionPlannerOptions() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PersistedCompactionPlannerOptions", reflect.TypeOf((*MockOptions)(nil).PersistedCompactionPlannerOptions))
+}
+
 // PostingsListCache mocks base method.
 func (m *MockOptions) PostingsListCache() *PostingsListCache {
 	m.ctrl.T.Helper()
@@ -2217,6 +2231,20 @@ func (mr *MockOptionsMockRecorder) SetMmapReporter(mmapReporter interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMmapReporter", reflect.TypeOf((*MockOptions)(nil).SetMmapReporter), mmapReporter)
 }
 
+// SetPersistedCompactionPlannerOptions mocks base method.
+func (m *MockOptions) SetPersistedCompactionPlannerOptions(v compaction.PersistedPlannerOptions) Options {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPersistedCompactionPlannerOptions", v)
+	ret0, _ := ret[0].(Options)
+	return ret0
+}
+
+// SetPersistedCompactionPlannerOptions indicates an expected call of SetPersistedCompactionPlannerOptions.
+func (mr *MockOptionsMockRecorder) SetPersistedCompactionPlannerOptions(v interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPersistedCompactionPlannerOptions", reflect.TypeOf((*MockOptions)(nil).SetPersistedCompactionPlannerOptions), v)
+}
+
 // SetPostingsListCache mocks base method.
 func (m *MockOptions) SetPostingsListCache(value *PostingsListCache) Options {
 	m.ctrl.T.Helper()
@@ -131,6 +131,7 @@ type options struct {
 	aggResultsEntryArrayPool        AggregateResultsEntryArrayPool
 	foregroundCompactionPlannerOpts compaction.PlannerOptions
 	backgroundCompactionPlannerOpts compaction.PlannerOptions
+	persistedCompactionPlannerOpts  compaction.PersistedPlannerOptions
 	postingsListCache               *PostingsListCache
 	searchPostingsListCache         *PostingsListCache
 	readThroughSegmentOptions       ReadThroughSegmentOptions
@@ -195,6 +196,7 @@ func NewOptions() Options {
 		aggResultsEntryArrayPool:        aggResultsEntryArrayPool,
 		foregroundCompactionPlannerOpts: defaultForegroundCompactionOpts,
 		backgroundCompactionPlannerOpts: defaultBackgroundCompactionOpts,
+		persistedCompactionPlannerOpts:  compaction.DefaultPersistedOptions,
 		queryLimits:                     limits.NoOpQueryLimits(),
 	}
 	resultsPool.Init(func() QueryResults {
@@ -402,6 +404,16 @@ func (o *options) BackgroundCompactionPlannerOptions() compaction.PlannerOptions
 	return o.backgroundCompactionPlannerOpts
 }
 
+func (o *options) SetPersistedCompactionPlannerOptions(value compaction.PersistedPlannerOptions) Options {
+	opts := *o
+	opts.persistedCompactionPlannerOpts = value
+	return &opts
+}
+
+func (o *options) PersistedCompactionPlannerOptions() compaction.PersistedPlannerOptions {
+	return o.persistedCompactionPlannerOpts
+}
+
 func (o *options) SetPostingsListCache(value *PostingsListCache) Options {
 	opts := *o
 	opts.postingsListCache = value
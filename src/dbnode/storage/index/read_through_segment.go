@@ -322,6 +322,24 @@ func (s *readThroughSegmentReader) MatchField(field []byte) (postings.List, erro
 	return pl, err
 }
 
+// MatchPrefix is a pass through call, prefix matches are not cached.
+func (s *readThroughSegmentReader) MatchPrefix(field, prefix []byte) (postings.List, error) {
+	return s.reader.MatchPrefix(field, prefix)
+}
+
+// MatchSuffix is a pass through call, suffix matches are not cached.
+func (s *readThroughSegmentReader) MatchSuffix(field, suffix []byte) (postings.List, error) {
+	return s.reader.MatchSuffix(field, suffix)
+}
+
+// MatchNumericRange is a pass through call, numeric range matches are not cached.
+func (s *readThroughSegmentReader) MatchNumericRange(
+	field []byte,
+	min, max float64,
+) (postings.List, error) {
+	return s.reader.MatchNumericRange(field, min, max)
+}
+
 // MatchAll is a pass through call, since there's no postings list to cache.
 // NB(r): The postings list returned by match all is just an iterator
 // from zero to the maximum document number indexed by the segment and as such
@@ -830,6 +830,12 @@ func (b *WriteBatch) MarkUnmarkedEntriesError(err error) {
 	}
 }
 
+// SetDoc replaces the document of the entry at index, i.e. to index the
+// series with a subset of its fields.
+func (b *WriteBatch) SetDoc(idx int, d doc.Metadata) {
+	b.docs[idx] = d
+}
+
 // MarkUnmarkedEntryError marks an unmarked entry at index as error.
 func (b *WriteBatch) MarkUnmarkedEntryError(
 	err error,
@@ -1082,6 +1088,14 @@ type Options interface {
 	// BackgroundCompactionPlannerOptions returns the compaction planner options.
 	BackgroundCompactionPlannerOptions() compaction.PlannerOptions
 
+	// SetPersistedCompactionPlannerOptions sets the persisted index volume
+	// compaction planner options.
+	SetPersistedCompactionPlannerOptions(v compaction.PersistedPlannerOptions) Options
+
+	// PersistedCompactionPlannerOptions returns the persisted index volume
+	// compaction planner options.
+	PersistedCompactionPlannerOptions() compaction.PersistedPlannerOptions
+
 	// SetPostingsListCache sets the postings list cache.
 	SetPostingsListCache(value *PostingsListCache) Options
 
@@ -266,6 +266,112 @@ func TestNamespaceIndexWrite(t *testing.T) {
 	require.NoError(t, idx.WriteBatch(batch))
 }
 
+func TestNamespaceIndexWriteLabelCardinalityLimit(t *testing.T) {
+	for _, action := range []limits.LabelCardinalityAction{
+		limits.RejectLabelCardinalityAction,
+		limits.StripLabelCardinalityAction,
+	} {
+		t.Run(action.String(), func(t *testing.T) {
+			testNamespaceIndexWriteLabelCardinalityLimit(t, action)
+		})
+	}
+}
+
+func testNamespaceIndexWriteLabelCardinalityLimit(
+	t *testing.T,
+	action limits.LabelCardinalityAction,
+) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	blockSize := time.Hour
+	now := xtime.Now().Truncate(blockSize).Add(2 * time.Minute)
+	nowFn := func() time.Time { return now.ToTime() }
+	opts := DefaultTestOptions()
+	opts = opts.SetClockOptions(opts.ClockOptions().SetNowFn(nowFn))
+	opts = opts.SetLimitsOptions(opts.LimitsOptions().SetLabelCardinalityOptions(
+		limits.LabelCardinalityOptions{
+			Default: limits.LabelCardinalityLimit{
+				MaxValuesPerLabel: 1,
+				Action:            action,
+			},
+			ExemptLabels: []string{"name"},
+		}))
+
+	var written []doc.Metadata
+	mockBlock := index.NewMockBlock(ctrl)
+	mockBlock.EXPECT().Stats(gomock.Any()).Return(nil).AnyTimes()
+	mockBlock.EXPECT().Close().Return(nil).Times(2) // active and normal
+	mockBlock.EXPECT().StartTime().Return(now.Truncate(blockSize)).AnyTimes()
+	mockBlock.EXPECT().
+		WriteBatch(gomock.Any()).
+		Return(index.WriteBatchResult{}, nil).
+		Do(func(batch *index.WriteBatch) {
+			written = append(written, batch.PendingDocs()...)
+		}).
+		AnyTimes()
+	newBlockFn := func(
+		ts xtime.UnixNano,
+		md namespace.Metadata,
+		_ index.BlockOptions,
+		_ namespace.RuntimeOptionsManager,
+		io index.Options,
+	) (index.Block, error) {
+		return mockBlock, nil
+	}
+	md := testNamespaceMetadata(blockSize, 4*time.Hour)
+	idx, err := newNamespaceIndexWithNewBlockFn(md,
+		namespace.NewRuntimeOptionsManager(md.ID().String()),
+		testShardSet, newBlockFn, opts)
+	require.NoError(t, err)
+
+	defer func() {
+		require.NoError(t, idx.Close())
+	}()
+
+	write := func(id, name, city string, ts xtime.UnixNano) error {
+		lifecycle := doc.NewMockOnIndexSeries(ctrl)
+		lifecycle.EXPECT().IfAlreadyIndexedMarkIndexSuccessAndFinalize(gomock.Any()).
+			Return(false).
+			AnyTimes()
+		lifecycle.EXPECT().OnIndexFinalize(gomock.Any()).AnyTimes()
+		tags := ident.NewTags(
+			ident.StringTag("city", city),
+			ident.StringTag("name", name),
+		)
+		entry, d := testWriteBatchEntry(ident.StringID(id), tags, ts, lifecycle)
+		batch := testWriteBatch(entry, d, testWriteBatchBlockSizeOption(blockSize))
+		return idx.WriteBatch(batch)
+	}
+
+	// Writes which are not indexed do not count towards the limit.
+	require.Error(t, write("qux", "qux", "la", now.Add(blockSize)))
+	require.NoError(t, write("foo", "foo", "nyc", now))
+	require.NoError(t, write("bar", "bar", "nyc", now))
+	err = write("baz", "baz", "sf", now)
+
+	var ids []string
+	for _, d := range written {
+		ids = append(ids, string(d.ID))
+	}
+	if action == limits.RejectLabelCardinalityAction {
+		require.Error(t, err)
+		require.Equal(t, []string{"foo", "bar"}, ids)
+	} else {
+		require.NoError(t, err)
+		require.Equal(t, []string{"foo", "bar", "baz"}, ids)
+		require.Equal(t, []doc.Field{
+			{Name: []byte("name"), Value: []byte("baz")},
+		}, written[2].Fields)
+	}
+
+	report := idx.LabelCardinality()
+	require.Equal(t, 1, len(report))
+	require.Equal(t, "city", report[0].Name)
+	require.Equal(t, int64(1), report[0].Values)
+	require.Equal(t, int64(1), report[0].Rejected)
+}
+
 func TestNamespaceIndexWriteCreatesBlock(t *testing.T) {
 	ctrl := xtest.NewController(t)
 	defer ctrl.Finish()
@@ -0,0 +1,399 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/storage/bootstrap/result"
+	"github.com/m3db/m3/src/dbnode/storage/index"
+	"github.com/m3db/m3/src/dbnode/storage/index/compaction"
+	"github.com/m3db/m3/src/m3ninx/doc"
+	"github.com/m3db/m3/src/m3ninx/index/segment"
+	"github.com/m3db/m3/src/m3ninx/index/segment/builder"
+	idxpersist "github.com/m3db/m3/src/m3ninx/persist"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	"github.com/m3db/m3/src/x/ident"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"go.uber.org/zap"
+)
+
+// CompactPersistedFileSets merges the persisted default index volumes of
+// blocks that have been sealed for at least the configured minimum age.
+//
+// The persisted compaction planner picks the run of volumes ending at the
+// newest volume of a block that fall into the same size tier, and the run is
+// rewritten as a single new volume, claimed from the index claims manager like
+// any other flush. The new volume is only visible to readers once its
+// checkpoint file is written, so a failure part way through leaves the
+// existing volumes untouched. Once written, the block swaps its in-memory
+// segments for the compacted ones and the compacted volumes are deleted by
+// CleanupDuplicateFileSets since the new volume covers all of their shards.
+// The planner includes every volume the new volume supersedes this way, so
+// cleanup never deletes a volume whose series were not compacted. Series
+// deleted for the whole block are dropped from the new volume.
+//
+// NB: only the volumes of a single index block are compacted, volumes of
+// adjacent index blocks are deliberately not merged. Index filesets, their
+// claims, bootstrapping, the blocks queried for a time range and retention are
+// all keyed by the start of a single index block, so a volume spanning blocks
+// would need a new fileset identity and would have to be kept until its newest
+// block expired. Long retention namespaces should instead use a larger index
+// block size, which reduces the number of persisted segments in the same way.
+func (i *nsIndex) CompactPersistedFileSets(
+	flush persist.IndexFlush,
+	shards []databaseShard,
+) error {
+	opts := i.opts.IndexOptions().PersistedCompactionPlannerOptions()
+	if !opts.Enabled {
+		return nil
+	}
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+
+	i.state.RLock()
+	if i.state.closed {
+		i.state.RUnlock()
+		return errDbIndexAlreadyClosed
+	}
+	bootstrapped := i.state.bootstrapState == Bootstrapped
+	i.state.RUnlock()
+	if !bootstrapped {
+		return nil
+	}
+
+	var (
+		now         = xtime.ToUnixNano(i.nowFn())
+		infoFiles   = i.readInfoFilesAsMap()
+		blockStarts = make([]xtime.UnixNano, 0, len(infoFiles))
+	)
+	for blockStart := range infoFiles {
+		blockStarts = append(blockStarts, blockStart)
+	}
+	sort.Slice(blockStarts, func(a, b int) bool {
+		return blockStarts[a].Before(blockStarts[b])
+	})
+
+	var (
+		segmentsBuilder segment.SegmentsBuilder
+		multiErr        = xerrors.NewMultiError()
+	)
+	for _, blockStart := range blockStarts {
+		if now.Sub(blockStart.Add(i.blockSize)) < opts.MinBlockAge {
+			// Blocks are sorted so all remaining blocks are too recent.
+			break
+		}
+
+		block, ok := i.persistedCompactableBlock(blockStart)
+		if !ok {
+			continue
+		}
+
+		if segmentsBuilder == nil {
+			segmentsBuilder = builder.NewBuilderFromSegments(
+				i.opts.IndexOptions().SegmentBuilderOptions())
+		}
+
+		err := i.compactPersistedBlock(flush, block, shards,
+			infoFiles[blockStart], segmentsBuilder, opts)
+		if err != nil {
+			i.metrics.persistedCompactionErrors.Inc(1)
+			i.logger.Error("could not compact persisted index volumes",
+				zap.Time("blockStart", blockStart.ToTime()),
+				zap.Error(err))
+			multiErr = multiErr.Add(err)
+		}
+	}
+
+	return multiErr.FinalError()
+}
+
+func (i *nsIndex) persistedCompactableBlock(
+	blockStart xtime.UnixNano,
+) (index.Block, bool) {
+	i.state.RLock()
+	block, ok := i.state.blocksByTime[blockStart]
+	i.state.RUnlock()
+	if !ok {
+		return nil, false
+	}
+
+	// Only compact blocks that can no longer be written to and have no
+	// outstanding data waiting to be flushed, otherwise a concurrent flush
+	// could write a volume the compacted volume does not account for.
+	if !block.IsSealed() ||
+		block.NeedsMutableSegmentsEvicted() ||
+		block.NeedsColdMutableSegmentsEvicted() {
+		return nil, false
+	}
+
+	return block, true
+}
+
+func (i *nsIndex) compactPersistedBlock(
+	flush persist.IndexFlush,
+	block index.Block,
+	shards []databaseShard,
+	infoFiles []fs.ReadIndexInfoFileResult,
+	segmentsBuilder segment.SegmentsBuilder,
+	opts compaction.PersistedPlannerOptions,
+) error {
+	var (
+		volumes    = make(map[int]fs.ReadIndexInfoFileResult, len(infoFiles))
+		candidates = make([]compaction.PersistedVolume, 0, len(infoFiles))
+	)
+	i.state.RLock()
+	assigned := i.state.shardsAssigned
+	i.state.RUnlock()
+	for _, infoFile := range infoFiles {
+		if infoFile.Info.IndexVolumeType != nil &&
+			idxpersist.IndexVolumeType(infoFile.Info.IndexVolumeType.Value) !=
+				idxpersist.DefaultIndexVolumeType {
+			continue
+		}
+
+		// NB: CleanupDuplicateFileSets only compares the owned shards of
+		// volumes, so while a block has volumes of shards no longer owned a
+		// compacted volume could supersede a volume holding series it does
+		// not, leave the block as is until those volumes are cleaned up.
+		for _, shard := range infoFile.Info.Shards {
+			if _, ok := assigned[shard]; !ok {
+				return nil
+			}
+		}
+
+		size, err := indexFileSetSize(infoFile.AbsoluteFilePaths)
+		if err != nil {
+			return err
+		}
+
+		volumes[infoFile.ID.VolumeIndex] = infoFile
+		candidates = append(candidates, compaction.PersistedVolume{
+			VolumeIndex: infoFile.ID.VolumeIndex,
+			Size:        size,
+			Shards:      infoFile.Info.Shards,
+		})
+	}
+
+	tasks, err := compaction.NewPersistedPlan(candidates, opts)
+	if err != nil {
+		return err
+	}
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	var (
+		compacted = make(map[int]struct{})
+		filter    = i.deletedSeriesFilter(shards, block)
+		persisted []segment.Segment
+		success   bool
+	)
+	defer func() {
+		if success {
+			return
+		}
+		for _, seg := range persisted {
+			seg.Close()
+		}
+	}()
+
+	for _, task := range tasks {
+		taskVolumes := make([]fs.ReadIndexInfoFileResult, 0, len(task.Volumes))
+		for _, v := range task.Volumes {
+			taskVolumes = append(taskVolumes, volumes[v.VolumeIndex])
+		}
+
+		segments, err := i.compactPersistedVolumes(flush, block, taskVolumes,
+			segmentsBuilder, filter)
+		if err != nil {
+			return err
+		}
+
+		persisted = append(persisted, segments...)
+		for _, v := range task.Volumes {
+			compacted[v.VolumeIndex] = struct{}{}
+		}
+		i.metrics.persistedCompactions.Inc(1)
+		i.metrics.persistedCompactedVolumes.Inc(int64(len(task.Volumes)))
+	}
+
+	// Reload the volumes left as is so the block can replace every segment it
+	// currently holds for these volumes in a single swap.
+	fulfilled := result.NewShardTimeRanges()
+	for _, infoFile := range volumes {
+		fulfilled.AddRanges(result.NewShardTimeRangesFromRange(
+			block.StartTime(), block.EndTime(), infoFile.Info.Shards...))
+		if _, ok := compacted[infoFile.ID.VolumeIndex]; ok {
+			continue
+		}
+
+		segments, err := i.readPersistedVolume(infoFile)
+		if err != nil {
+			return err
+		}
+		persisted = append(persisted, segments...)
+	}
+
+	results := make([]result.Segment, 0, len(persisted))
+	for _, seg := range persisted {
+		results = append(results, result.NewSegment(seg, true))
+	}
+	blockResults := result.NewIndexBlockByVolumeType(block.StartTime())
+	blockResults.SetBlock(idxpersist.DefaultIndexVolumeType,
+		result.NewIndexBlock(results, fulfilled))
+	if err := block.AddResults(blockResults); err != nil {
+		return err
+	}
+
+	success = true
+	return nil
+}
+
+func (i *nsIndex) compactPersistedVolumes(
+	flush persist.IndexFlush,
+	block index.Block,
+	volumes []fs.ReadIndexInfoFileResult,
+	segmentsBuilder segment.SegmentsBuilder,
+	filter segment.DocumentsFilter,
+) ([]segment.Segment, error) {
+	var (
+		shards = make(map[uint32]struct{})
+		inputs []segment.Segment
+	)
+	defer func() {
+		for _, seg := range inputs {
+			seg.Close()
+		}
+	}()
+
+	for _, infoFile := range volumes {
+		for _, shard := range infoFile.Info.Shards {
+			shards[shard] = struct{}{}
+		}
+
+		segments, err := i.readPersistedVolume(infoFile)
+		if err != nil {
+			return nil, err
+		}
+		inputs = append(inputs, segments...)
+	}
+
+	// NB: the builder drops any series present in more than one volume.
+	segmentsBuilder.Reset()
+	segmentsBuilder.SetFilter(filter, i.metrics.persistedCompactionDeletedDocs)
+	if err := segmentsBuilder.AddSegments(inputs); err != nil {
+		return nil, err
+	}
+
+	volumeIndex, err := i.opts.IndexClaimsManager().ClaimNextIndexFileSetVolumeIndex(
+		i.nsMetadata,
+		block.StartTime(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim next index volume index: %w", err)
+	}
+
+	preparedPersist, err := flush.PrepareIndex(persist.IndexPrepareOptions{
+		NamespaceMetadata: i.nsMetadata,
+		BlockStart:        block.StartTime(),
+		FileSetType:       persist.FileSetFlushType,
+		Shards:            shards,
+		IndexVolumeType:   idxpersist.DefaultIndexVolumeType,
+		VolumeIndex:       volumeIndex,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := preparedPersist.Persist(segmentsBuilder); err != nil {
+		segments, _ := preparedPersist.Close()
+		for _, seg := range segments {
+			seg.Close()
+		}
+		return nil, err
+	}
+
+	return preparedPersist.Close()
+}
+
+// deletedSeriesFilter returns a filter that retains the documents of series
+// not deleted for the whole block, or nil if no shard has deleted series.
+func (i *nsIndex) deletedSeriesFilter(
+	shards []databaseShard,
+	block index.Block,
+) segment.DocumentsFilter {
+	deleted := make(map[uint32]databaseShard)
+	for _, shard := range shards {
+		if shard.HasDeletedSeries() {
+			deleted[shard.ID()] = shard
+		}
+	}
+	if len(deleted) == 0 {
+		return nil
+	}
+
+	i.state.RLock()
+	shardForID := i.state.shardFilteredForID
+	i.state.RUnlock()
+
+	start, end := block.StartTime(), block.EndTime()
+	return segment.DocumentsFilterFn(func(d doc.Metadata) bool {
+		id := ident.BytesID(d.ID)
+		shardID, _ := shardForID(id)
+		shard, ok := deleted[shardID]
+		return !ok || !shard.IsSeriesDeleted(id, start, end)
+	})
+}
+
+func (i *nsIndex) readPersistedVolume(
+	infoFile fs.ReadIndexInfoFileResult,
+) ([]segment.Segment, error) {
+	result, err := i.readIndexSegmentsFn(fs.ReadIndexSegmentsOptions{
+		ReaderOptions: fs.IndexReaderOpenOptions{
+			Identifier:  infoFile.ID,
+			FileSetType: persist.FileSetFlushType,
+		},
+		FilesystemOptions: i.opts.CommitLogOptions().FilesystemOptions(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.Segments, nil
+}
+
+func indexFileSetSize(paths []string) (int64, error) {
+	var size int64
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return 0, err
+		}
+		size += info.Size()
+	}
+	return size, nil
+}
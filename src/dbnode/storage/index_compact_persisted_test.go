@@ -0,0 +1,490 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	indexpb "github.com/m3db/m3/src/dbnode/generated/proto/index"
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/storage/bootstrap/result"
+	"github.com/m3db/m3/src/dbnode/storage/index"
+	"github.com/m3db/m3/src/dbnode/storage/index/compaction"
+	"github.com/m3db/m3/src/m3ninx/doc"
+	"github.com/m3db/m3/src/m3ninx/index/segment"
+	"github.com/m3db/m3/src/m3ninx/index/segment/builder"
+	"github.com/m3db/m3/src/m3ninx/index/segment/fst"
+	"github.com/m3db/m3/src/m3ninx/index/segment/mem"
+	idxpersist "github.com/m3db/m3/src/m3ninx/persist"
+	"github.com/m3db/m3/src/x/ident"
+	xtest "github.com/m3db/m3/src/x/test"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNamespaceIndexCompactPersistedFileSets(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	dir, err := ioutil.TempDir("", t.Name())
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	plannerOpts := compaction.DefaultPersistedOptions
+	plannerOpts.Enabled = true
+	plannerOpts.MinBlockAge = time.Hour
+	plannerOpts.Levels = []compaction.Level{
+		{MinSizeInclusive: 0, MaxSizeExclusive: 1000},
+	}
+
+	opts := DefaultTestOptions()
+	opts = opts.
+		SetIndexOptions(opts.IndexOptions().
+			SetPersistedCompactionPlannerOptions(plannerOpts)).
+		SetCommitLogOptions(opts.CommitLogOptions().
+			SetFilesystemOptions(opts.CommitLogOptions().FilesystemOptions().
+				SetFilePathPrefix(dir)))
+
+	md := testNamespaceMetadata(time.Hour, 24*time.Hour)
+	nsIdx, err := newNamespaceIndex(md,
+		namespace.NewRuntimeOptionsManager(md.ID().String()),
+		testShardSet, opts)
+	require.NoError(t, err)
+
+	var (
+		idx        = nsIdx.(*nsIndex)
+		blockSize  = md.Options().IndexOptions().BlockSize()
+		now        = xtime.Now().Truncate(blockSize)
+		blockStart = now.Add(-4 * blockSize)
+		blockEnd   = blockStart.Add(blockSize)
+	)
+	idx.nowFn = func() time.Time { return now.ToTime() }
+
+	block := index.NewMockBlock(ctrl)
+	block.EXPECT().StartTime().Return(blockStart).AnyTimes()
+	block.EXPECT().EndTime().Return(blockEnd).AnyTimes()
+	block.EXPECT().IsSealed().Return(true)
+	block.EXPECT().NeedsMutableSegmentsEvicted().Return(false)
+	block.EXPECT().NeedsColdMutableSegmentsEvicted().Return(false)
+	block.EXPECT().Close().Return(nil).AnyTimes()
+
+	idx.state.Lock()
+	idx.state.bootstrapState = Bootstrapped
+	idx.state.blocksByTime[blockStart] = block
+	idx.state.Unlock()
+	defer func() {
+		require.NoError(t, idx.Close())
+	}()
+
+	// Volumes 1 and 2 fit the only tier, volume 0 is too large to compact.
+	var (
+		sizes     = []int{2000, 100, 200}
+		shards    = [][]uint32{{3}, {0, 1}, {1, 2}}
+		infoFiles []fs.ReadIndexInfoFileResult
+	)
+	for volumeIndex, size := range sizes {
+		path := filepath.Join(dir, t.Name()+"-"+string(rune('0'+volumeIndex)))
+		require.NoError(t, ioutil.WriteFile(path, make([]byte, size), 0600))
+		infoFiles = append(infoFiles, fs.ReadIndexInfoFileResult{
+			ID: fs.FileSetFileIdentifier{
+				BlockStart:  blockStart,
+				VolumeIndex: volumeIndex,
+			},
+			Info: indexpb.IndexVolumeInfo{
+				BlockStart: int64(blockStart),
+				BlockSize:  int64(blockSize),
+				Shards:     shards[volumeIndex],
+			},
+			AbsoluteFilePaths: []string{path},
+		})
+	}
+	idx.readIndexInfoFilesFn = func(_ fs.ReadIndexInfoFilesOptions) []fs.ReadIndexInfoFileResult {
+		return infoFiles
+	}
+
+	volumeDocs := [][]doc.Metadata{
+		{testCompactDoc("qux")},
+		{testCompactDoc("foo"), testCompactDoc("bar")},
+		{testCompactDoc("bar"), testCompactDoc("baz")},
+	}
+	var read []int
+	idx.readIndexSegmentsFn = func(opts fs.ReadIndexSegmentsOptions) (fs.ReadIndexSegmentsResult, error) {
+		volumeIndex := opts.ReaderOptions.Identifier.VolumeIndex
+		read = append(read, volumeIndex)
+		return fs.ReadIndexSegmentsResult{
+			Segments: []segment.Segment{testCompactSegment(t, volumeDocs[volumeIndex]...)},
+		}, nil
+	}
+
+	compacted := testCompactSegment(t, testCompactDoc("bar"),
+		testCompactDoc("baz"), testCompactDoc("foo"))
+	flush := persist.NewMockIndexFlush(ctrl)
+	flush.EXPECT().
+		PrepareIndex(gomock.Any()).
+		DoAndReturn(func(opts persist.IndexPrepareOptions) (persist.PreparedIndexPersist, error) {
+			require.Equal(t, blockStart, opts.BlockStart)
+			require.Equal(t, idxpersist.DefaultIndexVolumeType, opts.IndexVolumeType)
+			require.Equal(t, map[uint32]struct{}{0: {}, 1: {}, 2: {}}, opts.Shards)
+			return persist.PreparedIndexPersist{
+				Persist: func(b segment.Builder) error {
+					require.Len(t, b.Docs(), 3)
+					return nil
+				},
+				Close: func() ([]segment.Segment, error) {
+					return []segment.Segment{compacted}, nil
+				},
+			}, nil
+		})
+
+	block.EXPECT().
+		AddResults(gomock.Any()).
+		DoAndReturn(func(results result.IndexBlockByVolumeType) error {
+			blockResult, ok := results.GetBlock(idxpersist.DefaultIndexVolumeType)
+			require.True(t, ok)
+			require.Len(t, blockResult.Segments(), 2)
+			require.Equal(t, compacted, blockResult.Segments()[0].Segment())
+			require.True(t, blockResult.Fulfilled().Equal(
+				result.NewShardTimeRangesFromRange(blockStart, blockEnd, 0, 1, 2, 3)))
+			return nil
+		})
+
+	require.NoError(t, idx.CompactPersistedFileSets(flush, nil))
+	require.Equal(t, []int{1, 2, 0}, read)
+}
+
+func TestNamespaceIndexCompactPersistedFileSetsKeepsSkippedVolumes(t *testing.T) {
+	tests := []struct {
+		name string
+		// shards are the shards of each volume, the volume at index 1 is
+		// the only one in the large tier.
+		shards [][]uint32
+		// deleted are the docs of series deleted from their shards, which
+		// must be in compacted volumes.
+		deleted []string
+		// expectedVolumes are the volumes left after compaction and cleanup.
+		expectedVolumes []int
+	}{
+		{
+			name:            "middle volume in other tier",
+			shards:          [][]uint32{{0, 1}, {1}, {2}},
+			expectedVolumes: []int{0, 1, 2},
+		},
+		{
+			name:            "newest volumes compacted",
+			shards:          [][]uint32{{0, 1}, {1}, {2}, {3}},
+			expectedVolumes: []int{0, 1, 4},
+		},
+		{
+			name:            "deleted series dropped",
+			shards:          [][]uint32{{0, 1}, {1}, {2}, {3}},
+			deleted:         []string{"vol2-doc0", "vol3-doc1"},
+			expectedVolumes: []int{0, 1, 4},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testNamespaceIndexCompactPersistedFileSetsOnDisk(t, tt.shards,
+				tt.deleted, tt.expectedVolumes)
+		})
+	}
+}
+
+func testNamespaceIndexCompactPersistedFileSetsOnDisk(
+	t *testing.T,
+	volumeShards [][]uint32,
+	deleted []string,
+	expectedVolumes []int,
+) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	dir, err := ioutil.TempDir("", "compact-persisted")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	var (
+		opts   = DefaultTestOptions()
+		fsOpts = opts.CommitLogOptions().FilesystemOptions().
+			SetFilePathPrefix(dir)
+		md         = testNamespaceMetadata(time.Hour, 24*time.Hour)
+		blockSize  = md.Options().IndexOptions().BlockSize()
+		now        = xtime.Now().Truncate(blockSize)
+		blockStart = now.Add(-4 * blockSize)
+		blockEnd   = blockStart.Add(blockSize)
+	)
+
+	// Write the volumes, each with its own docs, the volume at index 1 with
+	// many more docs than the others so that it falls into the large tier.
+	var expectedDocs []doc.Metadata
+	for volumeIndex, shards := range volumeShards {
+		numDocs := 2
+		if volumeIndex == 1 {
+			numDocs = 500
+		}
+		var docs []doc.Metadata
+		for j := 0; j < numDocs; j++ {
+			docs = append(docs, testCompactDoc(fmt.Sprintf("vol%d-doc%d", volumeIndex, j)))
+		}
+		testWriteIndexVolume(t, fsOpts, md, blockStart, volumeIndex, shards, docs)
+		expectedDocs = append(expectedDocs, docs...)
+	}
+
+	md, err = namespace.NewMetadata(md.ID(), md.Options().
+		SetRetentionOptions(md.Options().RetentionOptions().SetBlockSize(blockSize)).
+		SetIndexOptions(md.Options().IndexOptions().SetEnabled(true)))
+	require.NoError(t, err)
+
+	infoFiles := testReadIndexInfoFiles(fsOpts, md)
+	require.Len(t, infoFiles, len(volumeShards))
+	largeSize, err := indexFileSetSize(infoFiles[1].AbsoluteFilePaths)
+	require.NoError(t, err)
+
+	plannerOpts := compaction.DefaultPersistedOptions
+	plannerOpts.Enabled = true
+	plannerOpts.MinBlockAge = time.Hour
+	plannerOpts.Levels = []compaction.Level{
+		{MinSizeInclusive: 0, MaxSizeExclusive: largeSize},
+		{MinSizeInclusive: largeSize, MaxSizeExclusive: 100 * largeSize},
+	}
+
+	fs.ResetIndexClaimsManagersUnsafe()
+	icm, err := fs.NewIndexClaimsManager(fsOpts)
+	require.NoError(t, err)
+	opts = opts.
+		SetIndexClaimsManager(icm).
+		SetIndexOptions(opts.IndexOptions().
+			SetPersistedCompactionPlannerOptions(plannerOpts)).
+		SetCommitLogOptions(opts.CommitLogOptions().
+			SetFilesystemOptions(fsOpts))
+
+	nsIdx, err := newNamespaceIndex(md,
+		namespace.NewRuntimeOptionsManager(md.ID().String()),
+		testShardSet, opts)
+	require.NoError(t, err)
+
+	idx := nsIdx.(*nsIndex)
+	idx.nowFn = func() time.Time { return now.ToTime() }
+
+	block := index.NewMockBlock(ctrl)
+	block.EXPECT().StartTime().Return(blockStart).AnyTimes()
+	block.EXPECT().EndTime().Return(blockEnd).AnyTimes()
+	block.EXPECT().IsSealed().Return(true)
+	block.EXPECT().NeedsMutableSegmentsEvicted().Return(false)
+	block.EXPECT().NeedsColdMutableSegmentsEvicted().Return(false)
+	block.EXPECT().Close().Return(nil).AnyTimes()
+	block.EXPECT().
+		AddResults(gomock.Any()).
+		DoAndReturn(func(results result.IndexBlockByVolumeType) error {
+			blockResult, ok := results.GetBlock(idxpersist.DefaultIndexVolumeType)
+			require.True(t, ok)
+			for _, seg := range blockResult.Segments() {
+				require.NoError(t, seg.Segment().Close())
+			}
+			return nil
+		}).
+		AnyTimes()
+
+	idx.state.Lock()
+	idx.state.bootstrapState = Bootstrapped
+	idx.state.blocksByTime[blockStart] = block
+	idx.state.Unlock()
+	defer func() {
+		require.NoError(t, idx.Close())
+	}()
+
+	deletedIDs := make(map[string]struct{}, len(deleted))
+	for _, id := range deleted {
+		deletedIDs[id] = struct{}{}
+	}
+	shards := make([]databaseShard, 0, len(testShardSet.AllIDs()))
+	for _, shardID := range testShardSet.AllIDs() {
+		shardID := shardID
+		shard := NewMockdatabaseShard(ctrl)
+		shard.EXPECT().ID().Return(shardID).AnyTimes()
+		shard.EXPECT().HasDeletedSeries().Return(len(deleted) > 0).AnyTimes()
+		shard.EXPECT().
+			IsSeriesDeleted(gomock.Any(), blockStart, blockEnd).
+			DoAndReturn(func(id ident.ID, _, _ xtime.UnixNano) bool {
+				require.Equal(t, shardID, testShardSet.Lookup(id))
+				_, ok := deletedIDs[id.String()]
+				return ok
+			}).
+			AnyTimes()
+		shards = append(shards, shard)
+	}
+
+	pm, err := fs.NewPersistManager(fsOpts)
+	require.NoError(t, err)
+	flush, err := pm.StartIndexPersist()
+	require.NoError(t, err)
+	require.NoError(t, idx.CompactPersistedFileSets(flush, shards))
+	require.NoError(t, flush.DoneIndex())
+
+	require.NoError(t, idx.CleanupDuplicateFileSets(testShardSet.AllIDs()))
+
+	// Reload the volumes left on disk and ensure every doc is still there
+	// other than those of deleted series.
+	infoFiles = testReadIndexInfoFiles(fsOpts, md)
+	var (
+		volumes  []int
+		segments []segment.Segment
+	)
+	for _, infoFile := range infoFiles {
+		volumes = append(volumes, infoFile.ID.VolumeIndex)
+		result, err := fs.ReadIndexSegments(fs.ReadIndexSegmentsOptions{
+			ReaderOptions: fs.IndexReaderOpenOptions{
+				Identifier:  infoFile.ID,
+				FileSetType: persist.FileSetFlushType,
+			},
+			FilesystemOptions: fsOpts,
+		})
+		require.NoError(t, err)
+		segments = append(segments, result.Segments...)
+	}
+	defer func() {
+		for _, seg := range segments {
+			require.NoError(t, seg.Close())
+		}
+	}()
+	require.Equal(t, expectedVolumes, volumes)
+
+	for _, d := range expectedDocs {
+		var found bool
+		for _, seg := range segments {
+			ok, err := seg.ContainsID(d.ID)
+			require.NoError(t, err)
+			found = found || ok
+		}
+		if _, ok := deletedIDs[string(d.ID)]; ok {
+			require.False(t, found, "deleted doc %s found", d.ID)
+			continue
+		}
+		require.True(t, found, "doc %s not found", d.ID)
+	}
+}
+
+func TestNamespaceIndexCompactPersistedFileSetsDisabled(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	md := testNamespaceMetadata(time.Hour, 24*time.Hour)
+	nsIdx, err := newNamespaceIndex(md,
+		namespace.NewRuntimeOptionsManager(md.ID().String()),
+		testShardSet, DefaultTestOptions())
+	require.NoError(t, err)
+
+	idx := nsIdx.(*nsIndex)
+	defer func() {
+		require.NoError(t, idx.Close())
+	}()
+	idx.readIndexInfoFilesFn = func(_ fs.ReadIndexInfoFilesOptions) []fs.ReadIndexInfoFileResult {
+		require.FailNow(t, "should not read info files when disabled")
+		return nil
+	}
+
+	require.NoError(t, idx.CompactPersistedFileSets(persist.NewMockIndexFlush(ctrl), nil))
+}
+
+func testCompactDoc(id string) doc.Metadata {
+	return doc.Metadata{
+		ID: []byte(id),
+		Fields: []doc.Field{
+			{Name: []byte("name"), Value: []byte(id)},
+		},
+	}
+}
+
+func testCompactSegment(t *testing.T, docs ...doc.Metadata) segment.Segment {
+	seg, err := mem.NewSegment(mem.NewOptions())
+	require.NoError(t, err)
+	for _, d := range docs {
+		_, err := seg.Insert(d)
+		require.NoError(t, err)
+	}
+	return seg
+}
+
+func testWriteIndexVolume(
+	t *testing.T,
+	fsOpts fs.Options,
+	md namespace.Metadata,
+	blockStart xtime.UnixNano,
+	volumeIndex int,
+	shards []uint32,
+	docs []doc.Metadata,
+) {
+	writer, err := fs.NewIndexWriter(fsOpts)
+	require.NoError(t, err)
+	segmentWriter, err := idxpersist.NewMutableSegmentFileSetWriter(fst.WriterOptions{})
+	require.NoError(t, err)
+
+	shardsMap := make(map[uint32]struct{}, len(shards))
+	for _, shard := range shards {
+		shardsMap[shard] = struct{}{}
+	}
+	require.NoError(t, writer.Open(fs.IndexWriterOpenOptions{
+		Identifier: fs.FileSetFileIdentifier{
+			Namespace:   md.ID(),
+			BlockStart:  blockStart,
+			VolumeIndex: volumeIndex,
+		},
+		BlockSize:       md.Options().IndexOptions().BlockSize(),
+		Shards:          shardsMap,
+		IndexVolumeType: idxpersist.DefaultIndexVolumeType,
+	}))
+
+	b, err := builder.NewBuilderFromDocuments(builder.NewOptions())
+	require.NoError(t, err)
+	for _, d := range docs {
+		_, err := b.Insert(d)
+		require.NoError(t, err)
+	}
+	require.NoError(t, segmentWriter.Reset(b))
+	require.NoError(t, writer.WriteSegmentFileSet(segmentWriter))
+	require.NoError(t, b.Close())
+	require.NoError(t, writer.Close())
+}
+
+func testReadIndexInfoFiles(
+	fsOpts fs.Options,
+	md namespace.Metadata,
+) []fs.ReadIndexInfoFileResult {
+	infoFiles := fs.ReadIndexInfoFiles(fs.ReadIndexInfoFilesOptions{
+		FilePathPrefix:   fsOpts.FilePathPrefix(),
+		Namespace:        md.ID(),
+		ReaderBufferSize: fsOpts.InfoReaderBufferSize(),
+	})
+	sort.Slice(infoFiles, func(i, j int) bool {
+		return infoFiles[i].ID.VolumeIndex < infoFiles[j].ID.VolumeIndex
+	})
+	return infoFiles
+}
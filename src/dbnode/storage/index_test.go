@@ -894,6 +894,7 @@ func verifyFlushForShards(
 
 		resultsID1 := ident.StringID("CACHED")
 		resultsID2 := ident.StringID("NEW")
+		resultsID3 := ident.StringID("DELETED")
 		doc1 := doc.Metadata{
 			ID:     resultsID1.Bytes(),
 			Fields: []doc.Field{},
@@ -918,6 +919,7 @@ func verifyFlushForShards(
 
 			resultsTags1 := ident.NewTagsIterator(ident.NewTags())
 			resultsTags2 := ident.NewTagsIterator(ident.NewTags())
+			resultsTags3 := ident.NewTagsIterator(ident.NewTags())
 			resultsInShard := []block.FetchBlocksMetadataResult{
 				{
 					ID:   resultsID1,
@@ -927,10 +929,21 @@ func verifyFlushForShards(
 					ID:   resultsID2,
 					Tags: resultsTags2,
 				},
+				{
+					ID:   resultsID3,
+					Tags: resultsTags3,
+				},
 			}
 			results.EXPECT().Results().Return(resultsInShard)
 			results.EXPECT().Close()
 
+			// Deleted series are not indexed.
+			mockShard.EXPECT().HasDeletedSeries().Return(true)
+			blockEnd := blockStart.Add(idx.blockSize)
+			mockShard.EXPECT().IsSeriesDeleted(resultsID1, blockStart, blockEnd).Return(false)
+			mockShard.EXPECT().IsSeriesDeleted(resultsID2, blockStart, blockEnd).Return(false)
+			mockShard.EXPECT().IsSeriesDeleted(resultsID3, blockStart, blockEnd).Return(true)
+
 			mockShard.EXPECT().DocRef(resultsID1).Return(doc1, true, nil)
 			mockShard.EXPECT().DocRef(resultsID2).Return(doc.Metadata{}, false, nil)
 
@@ -55,3 +55,38 @@ func IsQueryLimitExceededError(err error) bool {
 	}
 	return false
 }
+
+type tenantQuotaExceededError struct {
+	msg string
+}
+
+// NewTenantQuotaExceededError creates a tenant quota exceeded error.
+func NewTenantQuotaExceededError(msg string) error {
+	return &tenantQuotaExceededError{
+		msg: msg,
+	}
+}
+
+func (err *tenantQuotaExceededError) Error() string {
+	return err.msg
+}
+
+// IsTenantQuotaExceededError returns true if the error is a tenant quota
+// exceeded error.
+func IsTenantQuotaExceededError(err error) bool {
+	//nolint:errorlint
+	for err != nil {
+		if _, ok := err.(*tenantQuotaExceededError); ok {
+			return true
+		}
+		if multiErr, ok := err.(xerrors.MultiError); ok {
+			for _, e := range multiErr.Errors() {
+				if IsTenantQuotaExceededError(e) {
+					return true
+				}
+			}
+		}
+		err = xerrors.InnerError(err)
+	}
+	return false
+}
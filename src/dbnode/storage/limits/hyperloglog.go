@@ -0,0 +1,83 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package limits
+
+import (
+	"math"
+	"math/bits"
+)
+
+const (
+	// hllPrecision of 12 bits gives 4096 single byte registers and a standard
+	// error of roughly 1.6%.
+	hllPrecision = 12
+	hllRegisters = 1 << hllPrecision
+)
+
+var hllAlpha = 0.7213 / (1 + 1.079/float64(hllRegisters))
+
+// hyperLogLog is a HyperLogLog sketch of a set of hashes, the harmonic sum of
+// the registers is maintained on insert so estimating is constant time.
+type hyperLogLog struct {
+	registers [hllRegisters]uint8
+	sum       float64
+	zeros     int
+}
+
+func newHyperLogLog() *hyperLogLog {
+	return &hyperLogLog{
+		sum:   float64(hllRegisters),
+		zeros: hllRegisters,
+	}
+}
+
+func hllPosition(hash uint64) (uint64, uint8) {
+	idx := hash >> (64 - hllPrecision)
+	// NB: set a bit past the precision so that rank is bounded.
+	w := hash<<hllPrecision | 1<<(hllPrecision-1)
+	return idx, uint8(bits.LeadingZeros64(w)) + 1
+}
+
+// insert adds the hash to the sketch and returns whether the sketch changed.
+func (h *hyperLogLog) insert(hash uint64) bool {
+	idx, rank := hllPosition(hash)
+	curr := h.registers[idx]
+	if rank <= curr {
+		return false
+	}
+	if curr == 0 {
+		h.zeros--
+	}
+	h.sum += math.Ldexp(1, -int(rank)) - math.Ldexp(1, -int(curr))
+	h.registers[idx] = rank
+	return true
+}
+
+// estimate returns the estimated number of distinct hashes inserted.
+func (h *hyperLogLog) estimate() int64 {
+	m := float64(hllRegisters)
+	est := hllAlpha * m * m / h.sum
+	if est <= 2.5*m && h.zeros > 0 {
+		// Use linear counting for small cardinalities.
+		est = m * math.Log(m/float64(h.zeros))
+	}
+	return int64(est + 0.5)
+}
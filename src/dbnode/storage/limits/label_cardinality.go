@@ -0,0 +1,370 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package limits
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/m3db/bloom/v4"
+	"github.com/uber-go/tally"
+
+	"github.com/m3db/m3/src/m3ninx/doc"
+	"github.com/m3db/m3/src/x/instrument"
+)
+
+const (
+	defaultLabelCardinalityExactValuesThreshold = 1024
+
+	// labelCardinalityFalsePositiveRate is the rate at which values never seen
+	// before are admitted once a label is tracked by a sketch.
+	labelCardinalityFalsePositiveRate = 0.01
+)
+
+var errLabelCardinalityWindowNotPositive = errors.New("label cardinality window must be positive")
+
+func (a LabelCardinalityAction) String() string {
+	switch a {
+	case RejectLabelCardinalityAction:
+		return "reject"
+	case StripLabelCardinalityAction:
+		return "strip"
+	}
+	return "unknown"
+}
+
+// ParseLabelCardinalityAction parses a label cardinality action.
+func ParseLabelCardinalityAction(str string) (LabelCardinalityAction, error) {
+	for _, a := range []LabelCardinalityAction{
+		RejectLabelCardinalityAction,
+		StripLabelCardinalityAction,
+	} {
+		if strings.EqualFold(str, a.String()) {
+			return a, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown label cardinality action: %s", str)
+}
+
+// UnmarshalYAML unmarshals a label cardinality action.
+func (a *LabelCardinalityAction) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var str string
+	if err := unmarshal(&str); err != nil {
+		return err
+	}
+	if str == "" {
+		*a = RejectLabelCardinalityAction
+		return nil
+	}
+	parsed, err := ParseLabelCardinalityAction(str)
+	if err != nil {
+		return err
+	}
+	*a = parsed
+	return nil
+}
+
+// Validate validates the label cardinality options.
+func (o LabelCardinalityOptions) Validate() error {
+	if o.ExactValuesThreshold < 0 {
+		return errors.New("exact values threshold must not be negative")
+	}
+	limits := []LabelCardinalityLimit{o.Default}
+	for _, limit := range o.Namespaces {
+		limits = append(limits, limit)
+	}
+	for _, limit := range limits {
+		if limit.MaxValuesPerLabel < 0 {
+			return errors.New("max values per label must not be negative")
+		}
+		if limit.Action > StripLabelCardinalityAction {
+			return fmt.Errorf("unknown label cardinality action: %d", limit.Action)
+		}
+	}
+	return nil
+}
+
+// Limit returns the label cardinality limit of the namespace.
+func (o LabelCardinalityOptions) Limit(namespace string) LabelCardinalityLimit {
+	if limit, ok := o.Namespaces[namespace]; ok {
+		return limit
+	}
+	return o.Default
+}
+
+type labelCardinalityLimiter struct {
+	sync.Mutex
+
+	limit          LabelCardinalityLimit
+	exact          int
+	exempt         map[string]struct{}
+	window         time.Duration
+	windowStart    time.Time
+	curr           map[string]*labelValues
+	prev           map[string]*labelValues
+	nowFn          func() time.Time
+	scope          tally.Scope
+	trackedLabels  tally.Gauge
+	rejectedValues tally.Counter
+}
+
+// labelValues are the distinct values of a label, tracked exactly until the
+// exact threshold is reached. Past the threshold the number of values is
+// estimated by a HyperLogLog sketch and whether a value was seen before by a
+// bloom filter sized for the limit.
+type labelValues struct {
+	exact    map[string]struct{}
+	sketch   *hyperLogLog
+	filter   *bloom.BloomFilter
+	rejected int64
+	exceeded tally.Counter
+}
+
+var _ LabelCardinalityLimiter = (*labelCardinalityLimiter)(nil)
+
+// NewLabelCardinalityLimiter returns a new label cardinality limiter for the
+// namespace, values are tracked for each window of time which should match
+// the index block size of the namespace.
+func NewLabelCardinalityLimiter(
+	namespace string,
+	opts LabelCardinalityOptions,
+	window time.Duration,
+	instrumentOpts instrument.Options,
+) (LabelCardinalityLimiter, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	if window <= 0 {
+		return nil, errLabelCardinalityWindowNotPositive
+	}
+
+	limit := opts.Limit(namespace)
+	if limit.MaxValuesPerLabel == 0 {
+		return NoOpLabelCardinalityLimiter(), nil
+	}
+
+	exact := opts.ExactValuesThreshold
+	if exact == 0 {
+		exact = defaultLabelCardinalityExactValuesThreshold
+	}
+
+	exempt := make(map[string]struct{}, len(opts.ExemptLabels))
+	for _, name := range opts.ExemptLabels {
+		exempt[name] = struct{}{}
+	}
+
+	scope := instrumentOpts.MetricsScope().SubScope("label-cardinality")
+	return &labelCardinalityLimiter{
+		limit:          limit,
+		exact:          exact,
+		exempt:         exempt,
+		window:         window,
+		curr:           make(map[string]*labelValues),
+		prev:           make(map[string]*labelValues),
+		nowFn:          time.Now,
+		scope:          scope,
+		trackedLabels:  scope.Gauge("tracked-labels"),
+		rejectedValues: scope.Counter("rejected-values"),
+	}, nil
+}
+
+func (l *labelCardinalityLimiter) Enabled() bool {
+	return true
+}
+
+func (l *labelCardinalityLimiter) Action() LabelCardinalityAction {
+	return l.limit.Action
+}
+
+func (l *labelCardinalityLimiter) Admit(fields []doc.Field, rejected []int) []int {
+	l.Lock()
+	defer l.Unlock()
+
+	l.maybeRotateWithLock()
+	for i, f := range fields {
+		if !l.admitWithLock(f.Name, f.Value) {
+			rejected = append(rejected, i)
+		}
+	}
+
+	// NB: values are only recorded once the series is admitted, so that the
+	// values of a series which is rejected do not count towards the limit.
+	if len(rejected) != 0 && l.limit.Action == RejectLabelCardinalityAction {
+		return rejected
+	}
+	next := rejected
+	for i, f := range fields {
+		if len(next) != 0 && next[0] == i {
+			next = next[1:]
+			continue
+		}
+		l.recordWithLock(f.Name, f.Value)
+	}
+	return rejected
+}
+
+func (l *labelCardinalityLimiter) maybeRotateWithLock() {
+	windowStart := l.nowFn().Truncate(l.window)
+	if !windowStart.After(l.windowStart) {
+		return
+	}
+	// NB: values of the previous window are kept around so that series which
+	// continue to be written to the next index block are still admitted.
+	if windowStart.Sub(l.windowStart) == l.window {
+		l.prev = l.curr
+	} else {
+		l.prev = make(map[string]*labelValues)
+	}
+	l.curr = make(map[string]*labelValues, len(l.prev))
+	l.windowStart = windowStart
+	l.trackedLabels.Update(0)
+}
+
+func (l *labelCardinalityLimiter) admitWithLock(name, value []byte) bool {
+	if _, ok := l.exempt[string(name)]; ok {
+		return true
+	}
+
+	curr, ok := l.curr[string(name)]
+	if !ok || curr.contains(value) || curr.count() < l.limit.MaxValuesPerLabel {
+		return true
+	}
+
+	if prev, ok := l.prev[string(name)]; ok && prev.contains(value) {
+		return true
+	}
+
+	if curr.exceeded == nil {
+		curr.exceeded = l.scope.Tagged(map[string]string{
+			"label":  string(name),
+			"action": l.limit.Action.String(),
+		}).Counter("exceeded")
+	}
+	curr.rejected++
+	curr.exceeded.Inc(1)
+	l.rejectedValues.Inc(1)
+	return false
+}
+
+func (l *labelCardinalityLimiter) recordWithLock(name, value []byte) {
+	if _, ok := l.exempt[string(name)]; ok {
+		return
+	}
+
+	curr, ok := l.curr[string(name)]
+	if !ok {
+		curr = &labelValues{exact: make(map[string]struct{})}
+		l.curr[string(name)] = curr
+		l.trackedLabels.Update(float64(len(l.curr)))
+	}
+
+	if !curr.contains(value) {
+		curr.insert(value, l.exact, l.limit.MaxValuesPerLabel)
+	}
+}
+
+func (l *labelCardinalityLimiter) Report() []LabelCardinality {
+	l.Lock()
+	report := make([]LabelCardinality, 0, len(l.curr))
+	for name, values := range l.curr {
+		report = append(report, LabelCardinality{
+			Name:      name,
+			Values:    values.count(),
+			Estimated: values.sketch != nil,
+			Rejected:  values.rejected,
+		})
+	}
+	l.Unlock()
+
+	sort.Slice(report, func(i, j int) bool {
+		if report[i].Values != report[j].Values {
+			return report[i].Values > report[j].Values
+		}
+		return report[i].Name < report[j].Name
+	})
+	return report
+}
+
+func (v *labelValues) contains(value []byte) bool {
+	if v.filter != nil {
+		return v.filter.Test(value)
+	}
+	_, ok := v.exact[string(value)]
+	return ok
+}
+
+func (v *labelValues) insert(value []byte, exactThreshold int, limit int64) {
+	if v.sketch != nil {
+		v.sketch.insert(xxhash.Sum64(value))
+		v.filter.Add(value)
+		return
+	}
+
+	v.exact[string(value)] = struct{}{}
+	if len(v.exact) <= exactThreshold {
+		return
+	}
+
+	m, k := bloom.EstimateFalsePositiveRate(uint(limit), labelCardinalityFalsePositiveRate)
+	v.sketch = newHyperLogLog()
+	v.filter = bloom.NewBloomFilter(m, k)
+	for value := range v.exact {
+		v.sketch.insert(xxhash.Sum64String(value))
+		v.filter.Add([]byte(value))
+	}
+	v.exact = nil
+}
+
+func (v *labelValues) count() int64 {
+	if v.sketch != nil {
+		return v.sketch.estimate()
+	}
+	return int64(len(v.exact))
+}
+
+type noOpLabelCardinalityLimiter struct{}
+
+// NoOpLabelCardinalityLimiter returns a label cardinality limiter that admits
+// all label values without tracking them.
+func NoOpLabelCardinalityLimiter() LabelCardinalityLimiter {
+	return noOpLabelCardinalityLimiter{}
+}
+
+func (noOpLabelCardinalityLimiter) Enabled() bool {
+	return false
+}
+
+func (noOpLabelCardinalityLimiter) Action() LabelCardinalityAction {
+	return RejectLabelCardinalityAction
+}
+
+func (noOpLabelCardinalityLimiter) Admit(_ []doc.Field, rejected []int) []int {
+	return rejected
+}
+
+func (noOpLabelCardinalityLimiter) Report() []LabelCardinality {
+	return nil
+}
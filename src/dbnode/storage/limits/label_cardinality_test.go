@@ -0,0 +1,182 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package limits
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+
+	"github.com/m3db/m3/src/m3ninx/doc"
+	"github.com/m3db/m3/src/x/instrument"
+)
+
+func newTestLabelCardinalityLimiter(
+	t *testing.T,
+	opts LabelCardinalityOptions,
+	scope tally.Scope,
+) (*labelCardinalityLimiter, *time.Time) {
+	iOpts := instrument.NewOptions().SetMetricsScope(scope)
+	l, err := NewLabelCardinalityLimiter("ns", opts, time.Hour, iOpts)
+	require.NoError(t, err)
+
+	now := time.Now().Truncate(time.Hour)
+	limiter := l.(*labelCardinalityLimiter)
+	limiter.nowFn = func() time.Time {
+		return now
+	}
+	return limiter, &now
+}
+
+func testFields(kvs ...string) []doc.Field {
+	fields := make([]doc.Field, 0, len(kvs)/2)
+	for i := 0; i < len(kvs); i += 2 {
+		fields = append(fields, doc.Field{Name: []byte(kvs[i]), Value: []byte(kvs[i+1])})
+	}
+	return fields
+}
+
+func TestLabelCardinalityLimiterDisabled(t *testing.T) {
+	l, err := NewLabelCardinalityLimiter("ns", LabelCardinalityOptions{
+		Namespaces: map[string]LabelCardinalityLimit{
+			"other": {MaxValuesPerLabel: 1},
+		},
+	}, time.Hour, instrument.NewOptions())
+	require.NoError(t, err)
+	require.False(t, l.Enabled())
+	require.Empty(t, l.Admit(testFields("a", "1"), nil))
+}
+
+func TestLabelCardinalityLimiterAdmit(t *testing.T) {
+	scope := tally.NewTestScope("", nil)
+	l, _ := newTestLabelCardinalityLimiter(t, LabelCardinalityOptions{
+		Default:      LabelCardinalityLimit{MaxValuesPerLabel: 2},
+		ExemptLabels: []string{"exempt"},
+	}, scope)
+	require.True(t, l.Enabled())
+
+	require.Empty(t, l.Admit(testFields("city", "a", "exempt", "1"), nil))
+	require.Empty(t, l.Admit(testFields("city", "b", "exempt", "2"), nil))
+	// Third value for city exceeds the limit, exempt labels are unlimited.
+	require.Equal(t, []int{0}, l.Admit(testFields("city", "c", "exempt", "3"), nil))
+	// Values already seen are still admitted.
+	require.Empty(t, l.Admit(testFields("city", "a", "other", "1"), nil))
+
+	require.Equal(t, []LabelCardinality{
+		{Name: "city", Values: 2, Rejected: 1},
+		{Name: "other", Values: 1},
+	}, l.Report())
+
+	counters := scope.Snapshot().Counters()
+	exceeded, ok := counters["label-cardinality.exceeded+action=reject,label=city"]
+	require.True(t, ok)
+	require.Equal(t, int64(1), exceeded.Value())
+}
+
+func TestLabelCardinalityLimiterRecordsAdmittedSeries(t *testing.T) {
+	for _, action := range []LabelCardinalityAction{
+		RejectLabelCardinalityAction,
+		StripLabelCardinalityAction,
+	} {
+		t.Run(action.String(), func(t *testing.T) {
+			l, _ := newTestLabelCardinalityLimiter(t, LabelCardinalityOptions{
+				Default: LabelCardinalityLimit{MaxValuesPerLabel: 1, Action: action},
+			}, tally.NoopScope)
+
+			require.Empty(t, l.Admit(testFields("city", "a"), nil))
+			require.Equal(t, []int{0}, l.Admit(testFields("city", "b", "host", "a"), nil))
+
+			// The values of a rejected series are not recorded, whereas a
+			// stripped series records the values of the labels it keeps.
+			if action == RejectLabelCardinalityAction {
+				require.Empty(t, l.Admit(testFields("city", "a", "host", "b"), nil))
+			} else {
+				require.Equal(t, []int{1}, l.Admit(testFields("city", "a", "host", "b"), nil))
+			}
+		})
+	}
+}
+
+func TestLabelCardinalityLimiterNamespaceLimit(t *testing.T) {
+	l, _ := newTestLabelCardinalityLimiter(t, LabelCardinalityOptions{
+		Default: LabelCardinalityLimit{MaxValuesPerLabel: 1},
+		Namespaces: map[string]LabelCardinalityLimit{
+			"ns": {MaxValuesPerLabel: 3, Action: StripLabelCardinalityAction},
+		},
+	}, tally.NoopScope)
+	require.Equal(t, StripLabelCardinalityAction, l.Action())
+	for i := 0; i < 3; i++ {
+		require.Empty(t, l.Admit(testFields("a", strconv.Itoa(i)), nil))
+	}
+	require.Equal(t, []int{0}, l.Admit(testFields("a", "3"), nil))
+}
+
+func TestLabelCardinalityLimiterRotates(t *testing.T) {
+	l, now := newTestLabelCardinalityLimiter(t, LabelCardinalityOptions{
+		Default: LabelCardinalityLimit{MaxValuesPerLabel: 1},
+	}, tally.NoopScope)
+
+	require.Empty(t, l.Admit(testFields("a", "1"), nil))
+	require.Equal(t, []int{0}, l.Admit(testFields("a", "2"), nil))
+
+	// The next window starts afresh but keeps admitting values of the
+	// previous window so existing series continue to be indexed.
+	*now = now.Add(time.Hour)
+	require.Empty(t, l.Admit(testFields("a", "3"), nil))
+	require.Empty(t, l.Admit(testFields("a", "1"), nil))
+	require.Equal(t, []int{0}, l.Admit(testFields("a", "4"), nil))
+
+	// Values are forgotten after skipping a whole window.
+	*now = now.Add(2 * time.Hour)
+	require.Empty(t, l.Admit(testFields("a", "4"), nil))
+	require.Equal(t, []int{0}, l.Admit(testFields("a", "1"), nil))
+}
+
+func TestLabelCardinalityLimiterSketch(t *testing.T) {
+	l, _ := newTestLabelCardinalityLimiter(t, LabelCardinalityOptions{
+		Default:              LabelCardinalityLimit{MaxValuesPerLabel: 5000},
+		ExactValuesThreshold: 100,
+	}, tally.NoopScope)
+
+	var rejected int
+	for i := 0; i < 10000; i++ {
+		rejected += len(l.Admit(testFields("id", strconv.Itoa(i)), nil))
+	}
+
+	report := l.Report()
+	require.Len(t, report, 1)
+	require.True(t, report[0].Estimated)
+	require.InEpsilon(t, 5000, report[0].Values, 0.05)
+	require.InEpsilon(t, 5000, rejected, 0.05)
+	require.Equal(t, int64(rejected), report[0].Rejected)
+}
+
+func TestParseLabelCardinalityAction(t *testing.T) {
+	a, err := ParseLabelCardinalityAction("Strip")
+	require.NoError(t, err)
+	require.Equal(t, StripLabelCardinalityAction, a)
+
+	_, err = ParseLabelCardinalityAction("drop")
+	require.Error(t, err)
+}
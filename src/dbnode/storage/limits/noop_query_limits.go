@@ -26,9 +26,13 @@ type noOpQueryLimits struct {
 type noOpLookbackLimit struct {
 }
 
+type noOpTenantQuotas struct {
+}
+
 var (
 	_ QueryLimits   = (*noOpQueryLimits)(nil)
 	_ LookbackLimit = (*noOpLookbackLimit)(nil)
+	_ TenantQuotas  = (*noOpTenantQuotas)(nil)
 )
 
 // NoOpQueryLimits returns inactive query limits.
@@ -75,3 +79,32 @@ func (q *noOpLookbackLimit) Start() {
 
 func (q *noOpLookbackLimit) Stop() {
 }
+
+// NoOpTenantQuotas returns inactive tenant quotas.
+func NoOpTenantQuotas() TenantQuotas {
+	return &noOpTenantQuotas{}
+}
+
+func (q *noOpTenantQuotas) IncSeriesCreated([]byte, int) error {
+	return nil
+}
+
+func (q *noOpTenantQuotas) IncDatapointsWritten([]byte, int) error {
+	return nil
+}
+
+func (q *noOpTenantQuotas) IncDocsMatched([]byte, int) error {
+	return nil
+}
+
+func (q *noOpTenantQuotas) IncBytesRead([]byte, int) error {
+	return nil
+}
+
+func (q *noOpTenantQuotas) Options() TenantQuotasOptions {
+	return TenantQuotasOptions{}
+}
+
+func (q *noOpTenantQuotas) Update(TenantQuotasOptions) error {
+	return nil
+}
@@ -34,12 +34,19 @@ type limitOpts struct {
 	diskSeriesReadLimitOpts    LookbackLimitOptions
 	diskAggregateDocsLimitOpts LookbackLimitOptions
 	sourceLoggerBuilder        SourceLoggerBuilder
+	tenantQuotas               TenantQuotas
+	tenantTag                  []byte
+	labelCardinalityOpts       LabelCardinalityOptions
 }
 
 // NewOptions creates limit options with default values.
 func NewOptions() Options {
 	return &limitOpts{
 		sourceLoggerBuilder: &sourceLoggerBuilder{},
+		tenantQuotas:        NoOpTenantQuotas(),
+		labelCardinalityOpts: LabelCardinalityOptions{
+			ExactValuesThreshold: defaultLabelCardinalityExactValuesThreshold,
+		},
 	}
 }
 
@@ -57,6 +64,14 @@ func (o *limitOpts) Validate() error {
 		return fmt.Errorf("bytes limit options invalid: %w", err)
 	}
 
+	if o.tenantQuotas == nil {
+		return errors.New("limit options invalid: no tenant quotas")
+	}
+
+	if err := o.labelCardinalityOpts.Validate(); err != nil {
+		return fmt.Errorf("label cardinality options invalid: %w", err)
+	}
+
 	return nil
 }
 
@@ -131,3 +146,39 @@ func (o *limitOpts) SetSourceLoggerBuilder(value SourceLoggerBuilder) Options {
 func (o *limitOpts) SourceLoggerBuilder() SourceLoggerBuilder {
 	return o.sourceLoggerBuilder
 }
+
+// SetTenantQuotas sets the tenant quotas.
+func (o *limitOpts) SetTenantQuotas(value TenantQuotas) Options {
+	opts := *o
+	opts.tenantQuotas = value
+	return &opts
+}
+
+// TenantQuotas returns the tenant quotas.
+func (o *limitOpts) TenantQuotas() TenantQuotas {
+	return o.tenantQuotas
+}
+
+// SetTenantTag sets the name of the tag identifying the tenant of a series.
+func (o *limitOpts) SetTenantTag(value []byte) Options {
+	opts := *o
+	opts.tenantTag = value
+	return &opts
+}
+
+// TenantTag returns the name of the tag identifying the tenant of a series.
+func (o *limitOpts) TenantTag() []byte {
+	return o.tenantTag
+}
+
+// SetLabelCardinalityOptions sets the label cardinality limit options.
+func (o *limitOpts) SetLabelCardinalityOptions(value LabelCardinalityOptions) Options {
+	opts := *o
+	opts.labelCardinalityOpts = value
+	return &opts
+}
+
+// LabelCardinalityOptions returns the label cardinality limit options.
+func (o *limitOpts) LabelCardinalityOptions() LabelCardinalityOptions {
+	return o.labelCardinalityOpts
+}
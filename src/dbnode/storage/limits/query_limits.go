@@ -55,6 +55,9 @@ type lookbackLimit struct {
 	stoppedCh chan struct{}
 	lock      sync.RWMutex
 	iOpts     instrument.Options
+
+	// tenantQuota enforces the quota of the tenant issuing the query, if set.
+	tenantQuota func(tenant []byte, n int) error
 }
 
 type lookbackLimitMetrics struct {
@@ -123,8 +126,14 @@ func NewQueryLimits(options Options) (QueryLimits, error) {
 			metricName: docsMatched,
 			metricType: "aggregate",
 		}, aggDocsLimitOpts, iOpts, sourceLoggerBuilder)
+		tenantQuotas = options.TenantQuotas()
 	)
 
+	// NB: queries are attributed to tenants by their source.
+	docsLimit.tenantQuota = tenantQuotas.IncDocsMatched
+	aggregatedDocsLimit.tenantQuota = tenantQuotas.IncDocsMatched
+	bytesReadLimit.tenantQuota = tenantQuotas.IncBytesRead
+
 	return &queryLimits{
 		docsLimit:           docsLimit,
 		bytesReadLimit:      bytesReadLimit,
@@ -287,7 +296,14 @@ func (q *lookbackLimit) Inc(val int, source []byte) error {
 	q.metrics.sourceLogger.LogSourceValue(valI64, source)
 
 	// Enforce limit (if specified).
-	return q.checkLimit(recent)
+	if err := q.checkLimit(recent); err != nil {
+		return err
+	}
+
+	if q.tenantQuota == nil {
+		return nil
+	}
+	return q.tenantQuota(source, val)
 }
 
 func (q *lookbackLimit) exceeded() error {
@@ -0,0 +1,277 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package limits
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/uber-go/tally"
+	"go.uber.org/atomic"
+	"go.uber.org/zap"
+
+	xerrors "github.com/m3db/m3/src/x/errors"
+	"github.com/m3db/m3/src/x/instrument"
+)
+
+type tenantQuotaType int
+
+const (
+	seriesCreatedTenantQuota tenantQuotaType = iota
+	datapointsWrittenTenantQuota
+	docsMatchedTenantQuota
+	bytesReadTenantQuota
+
+	numTenantQuotaTypes
+)
+
+func (t tenantQuotaType) String() string {
+	switch t {
+	case seriesCreatedTenantQuota:
+		return "series-created"
+	case datapointsWrittenTenantQuota:
+		return "datapoints-written"
+	case docsMatchedTenantQuota:
+		return "docs-matched"
+	case bytesReadTenantQuota:
+		return "disk-bytes-read"
+	}
+	return "unknown"
+}
+
+// window returns the limit and lookback of the quota type, a zero limit
+// means the quota is disabled.
+func (t tenantQuotaType) window(q TenantQuota) (int64, time.Duration, bool) {
+	switch t {
+	case seriesCreatedTenantQuota:
+		return q.SeriesCreatedPerSecond, time.Second, false
+	case datapointsWrittenTenantQuota:
+		return q.DatapointsWrittenPerSecond, time.Second, false
+	case docsMatchedTenantQuota:
+		return q.DocsMatched.Limit, q.DocsMatched.Lookback, q.DocsMatched.ForceExceeded
+	case bytesReadTenantQuota:
+		return q.BytesRead.Limit, q.BytesRead.Lookback, q.BytesRead.ForceExceeded
+	}
+	return disabledLimitValue, 0, false
+}
+
+type tenantQuotas struct {
+	sync.RWMutex
+
+	opts    TenantQuotasOptions
+	tenants map[string]*tenantUsage
+	scope   tally.Scope
+	logger  *zap.Logger
+	nowFn   func() time.Time
+}
+
+type tenantUsage struct {
+	quota    TenantQuota
+	counters [numTenantQuotaTypes]tenantCounter
+	exceeded [numTenantQuotaTypes]tally.Counter
+}
+
+// tenantCounter counts usage within a window which is reset lazily on the
+// first increment after the window elapsed.
+type tenantCounter struct {
+	windowStart atomic.Int64
+	value       atomic.Int64
+}
+
+var _ TenantQuotas = (*tenantQuotas)(nil)
+
+// NewTenantQuotas returns new tenant quotas.
+func NewTenantQuotas(
+	opts TenantQuotasOptions,
+	instrumentOpts instrument.Options,
+) (TenantQuotas, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &tenantQuotas{
+		opts:    opts,
+		tenants: make(map[string]*tenantUsage),
+		scope:   instrumentOpts.MetricsScope().SubScope("tenant-quota"),
+		logger:  instrumentOpts.Logger(),
+		nowFn:   time.Now,
+	}, nil
+}
+
+func (q *tenantQuotas) IncSeriesCreated(tenant []byte, n int) error {
+	return q.inc(seriesCreatedTenantQuota, tenant, n)
+}
+
+func (q *tenantQuotas) IncDatapointsWritten(tenant []byte, n int) error {
+	return q.inc(datapointsWrittenTenantQuota, tenant, n)
+}
+
+func (q *tenantQuotas) IncDocsMatched(tenant []byte, n int) error {
+	return q.inc(docsMatchedTenantQuota, tenant, n)
+}
+
+func (q *tenantQuotas) IncBytesRead(tenant []byte, n int) error {
+	return q.inc(bytesReadTenantQuota, tenant, n)
+}
+
+func (q *tenantQuotas) Options() TenantQuotasOptions {
+	q.RLock()
+	o := q.opts
+	q.RUnlock()
+	return o
+}
+
+func (q *tenantQuotas) Update(opts TenantQuotasOptions) error {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+
+	q.Lock()
+	q.opts = opts
+	for tenant, usage := range q.tenants {
+		usage.quota = opts.quota(tenant)
+	}
+	q.Unlock()
+
+	q.logger.Info("tenant quota options updated",
+		zap.Any("default", opts.Default),
+		zap.Int("tenants", len(opts.Tenants)))
+
+	return nil
+}
+
+func (q *tenantQuotas) inc(t tenantQuotaType, tenant []byte, n int) error {
+	if len(tenant) == 0 || n <= 0 {
+		return nil
+	}
+
+	usage, quota := q.usage(t, tenant)
+	if usage == nil {
+		return nil
+	}
+
+	limit, lookback, forceExceeded := t.window(quota)
+	if forceExceeded {
+		usage.exceeded[t].Inc(1)
+		return xerrors.NewInvalidParamsError(NewTenantQuotaExceededError(fmt.Sprintf(
+			"tenant quota forced exceeded: tenant=%s, quota=%s", tenant, t)))
+	}
+
+	var (
+		counter = &usage.counters[t]
+		now     = q.nowFn().UnixNano()
+		start   = counter.windowStart.Load()
+	)
+	if now-start >= int64(lookback) && counter.windowStart.CAS(start, now) {
+		counter.value.Store(0)
+	}
+
+	current := counter.value.Add(int64(n))
+	if current <= limit {
+		return nil
+	}
+
+	usage.exceeded[t].Inc(1)
+	return xerrors.NewInvalidParamsError(NewTenantQuotaExceededError(fmt.Sprintf(
+		"tenant quota exceeded: tenant=%s, quota=%s, limit=%d, current=%d, within=%s",
+		tenant, t, limit, current, lookback)))
+}
+
+// usage returns the usage of the tenant and its quota, or nil if the quota
+// type is not enforced for the tenant.
+func (q *tenantQuotas) usage(t tenantQuotaType, tenant []byte) (*tenantUsage, TenantQuota) {
+	q.RLock()
+	usage, ok := q.tenants[string(tenant)]
+	if ok {
+		quota := usage.quota
+		q.RUnlock()
+		if !t.enabled(quota) {
+			return nil, quota
+		}
+		return usage, quota
+	}
+	quota := q.opts.quota(string(tenant))
+	q.RUnlock()
+
+	// Avoid tracking tenants which are not subject to the quota type.
+	if !t.enabled(quota) {
+		return nil, quota
+	}
+
+	q.Lock()
+	defer q.Unlock()
+
+	usage, ok = q.tenants[string(tenant)]
+	if ok {
+		return usage, usage.quota
+	}
+
+	name := string(tenant)
+	usage = &tenantUsage{quota: q.opts.quota(name)}
+	for i := tenantQuotaType(0); i < numTenantQuotaTypes; i++ {
+		usage.exceeded[i] = q.scope.Tagged(map[string]string{
+			"tenant": name,
+			"quota":  i.String(),
+		}).Counter("exceeded")
+	}
+	q.tenants[name] = usage
+	return usage, usage.quota
+}
+
+func (t tenantQuotaType) enabled(q TenantQuota) bool {
+	limit, _, forceExceeded := t.window(q)
+	return limit != disabledLimitValue || forceExceeded
+}
+
+func (opts TenantQuotasOptions) quota(tenant string) TenantQuota {
+	if quota, ok := opts.Tenants[tenant]; ok {
+		return quota
+	}
+	return opts.Default
+}
+
+// Validate validates the tenant quota options.
+func (opts TenantQuotasOptions) Validate() error {
+	if err := opts.Default.Validate(); err != nil {
+		return fmt.Errorf("default tenant quota invalid: %w", err)
+	}
+	for tenant, quota := range opts.Tenants {
+		if err := quota.Validate(); err != nil {
+			return fmt.Errorf("tenant quota invalid: tenant=%s, %w", tenant, err)
+		}
+	}
+	return nil
+}
+
+// Validate validates the tenant quota.
+func (q TenantQuota) Validate() error {
+	for t := tenantQuotaType(0); t < numTenantQuotaTypes; t++ {
+		limit, lookback, _ := t.window(q)
+		if limit < 0 {
+			return fmt.Errorf("tenant quota requires limit >= 0: quota=%s, limit=%d", t, limit)
+		}
+		if limit > 0 && lookback <= 0 {
+			return fmt.Errorf("tenant quota requires lookback > 0: quota=%s, lookback=%s", t, lookback)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package limits
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+
+	xerrors "github.com/m3db/m3/src/x/errors"
+	"github.com/m3db/m3/src/x/instrument"
+	"github.com/m3db/m3/src/x/tallytest"
+)
+
+func newTestTenantQuotas(
+	t *testing.T,
+	opts TenantQuotasOptions,
+	scope tally.Scope,
+) (*tenantQuotas, *time.Time) {
+	iOpts := instrument.NewOptions().SetMetricsScope(scope)
+	q, err := NewTenantQuotas(opts, iOpts)
+	require.NoError(t, err)
+
+	now := time.Now()
+	quotas := q.(*tenantQuotas)
+	quotas.nowFn = func() time.Time {
+		return now
+	}
+	return quotas, &now
+}
+
+func requireTenantQuotaExceeded(t *testing.T, err error) {
+	require.Error(t, err)
+	require.True(t, xerrors.IsInvalidParams(err))
+	require.True(t, IsTenantQuotaExceededError(err))
+}
+
+func TestTenantQuotasPerSecond(t *testing.T) {
+	scope := tally.NewTestScope("", nil)
+	q, now := newTestTenantQuotas(t, TenantQuotasOptions{
+		Default: TenantQuota{
+			SeriesCreatedPerSecond:     2,
+			DatapointsWrittenPerSecond: 10,
+		},
+		Tenants: map[string]TenantQuota{
+			"big": {DatapointsWrittenPerSecond: 100},
+		},
+	}, scope)
+
+	tenant := []byte("small")
+	require.NoError(t, q.IncSeriesCreated(tenant, 1))
+	require.NoError(t, q.IncSeriesCreated(tenant, 1))
+	requireTenantQuotaExceeded(t, q.IncSeriesCreated(tenant, 1))
+
+	require.NoError(t, q.IncDatapointsWritten(tenant, 10))
+	requireTenantQuotaExceeded(t, q.IncDatapointsWritten(tenant, 1))
+
+	// Other tenants have their own usage and quotas.
+	require.NoError(t, q.IncSeriesCreated([]byte("other"), 2))
+	require.NoError(t, q.IncDatapointsWritten([]byte("big"), 100))
+	// Series created are not limited for the big tenant.
+	require.NoError(t, q.IncSeriesCreated([]byte("big"), 1000))
+
+	// Writes without a tenant are never limited.
+	require.NoError(t, q.IncDatapointsWritten(nil, 1000))
+
+	// Usage resets once the window elapsed.
+	*now = now.Add(time.Second)
+	require.NoError(t, q.IncSeriesCreated(tenant, 2))
+	require.NoError(t, q.IncDatapointsWritten(tenant, 10))
+
+	tallytest.AssertCounterValue(t, 1, scope.Snapshot(), "tenant-quota.exceeded",
+		map[string]string{"tenant": "small", "quota": "series-created"})
+	tallytest.AssertCounterValue(t, 1, scope.Snapshot(), "tenant-quota.exceeded",
+		map[string]string{"tenant": "small", "quota": "datapoints-written"})
+}
+
+func TestTenantQuotasLookback(t *testing.T) {
+	q, now := newTestTenantQuotas(t, TenantQuotasOptions{
+		Tenants: map[string]TenantQuota{
+			"a": {
+				DocsMatched: LookbackLimitOptions{Limit: 5, Lookback: time.Minute},
+				BytesRead:   LookbackLimitOptions{ForceExceeded: true},
+			},
+		},
+	}, tally.NoopScope)
+
+	tenant := []byte("a")
+	require.NoError(t, q.IncDocsMatched(tenant, 5))
+	*now = now.Add(30 * time.Second)
+	requireTenantQuotaExceeded(t, q.IncDocsMatched(tenant, 1))
+	*now = now.Add(30 * time.Second)
+	require.NoError(t, q.IncDocsMatched(tenant, 5))
+
+	requireTenantQuotaExceeded(t, q.IncBytesRead(tenant, 1))
+
+	// Tenants without a quota are not tracked.
+	require.NoError(t, q.IncDocsMatched([]byte("b"), 100))
+	_, ok := q.tenants["b"]
+	assert.False(t, ok)
+}
+
+func TestTenantQuotasUpdate(t *testing.T) {
+	q, _ := newTestTenantQuotas(t, TenantQuotasOptions{
+		Default: TenantQuota{DatapointsWrittenPerSecond: 1},
+	}, tally.NoopScope)
+
+	tenant := []byte("a")
+	require.NoError(t, q.IncDatapointsWritten(tenant, 1))
+	requireTenantQuotaExceeded(t, q.IncDatapointsWritten(tenant, 1))
+
+	updated := TenantQuotasOptions{
+		Tenants: map[string]TenantQuota{
+			"a": {DatapointsWrittenPerSecond: 10},
+		},
+	}
+	require.NoError(t, q.Update(updated))
+	assert.Equal(t, updated, q.Options())
+	require.NoError(t, q.IncDatapointsWritten(tenant, 5))
+
+	require.Error(t, q.Update(TenantQuotasOptions{
+		Default: TenantQuota{SeriesCreatedPerSecond: -1},
+	}))
+	require.Error(t, q.Update(TenantQuotasOptions{
+		Default: TenantQuota{DocsMatched: LookbackLimitOptions{Limit: 1}},
+	}))
+	assert.Equal(t, updated, q.Options())
+}
+
+func TestQueryLimitsTenantQuotas(t *testing.T) {
+	quotas, err := NewTenantQuotas(TenantQuotasOptions{
+		Default: TenantQuota{
+			DocsMatched: LookbackLimitOptions{Limit: 2, Lookback: time.Minute},
+			BytesRead:   LookbackLimitOptions{Limit: 2, Lookback: time.Minute},
+		},
+	}, instrument.NewOptions())
+	require.NoError(t, err)
+
+	opts := DefaultLimitsOptions(instrument.NewOptions()).SetTenantQuotas(quotas)
+	queryLimits, err := NewQueryLimits(opts)
+	require.NoError(t, err)
+
+	source := []byte("tenant")
+	require.NoError(t, queryLimits.FetchDocsLimit().Inc(2, source))
+	requireTenantQuotaExceeded(t, queryLimits.FetchDocsLimit().Inc(1, source))
+	require.NoError(t, queryLimits.FetchDocsLimit().Inc(1, []byte("other")))
+
+	// Docs matched by aggregate queries count towards the same quota.
+	requireTenantQuotaExceeded(t, queryLimits.AggregateDocsLimit().Inc(1, source))
+
+	require.NoError(t, queryLimits.BytesReadLimit().Inc(2, source))
+	requireTenantQuotaExceeded(t, queryLimits.BytesReadLimit().Inc(1, source))
+	require.NoError(t, queryLimits.BytesReadLimit().Inc(1, nil))
+}
@@ -24,6 +24,7 @@ package limits
 import (
 	"time"
 
+	"github.com/m3db/m3/src/m3ninx/doc"
 	"github.com/m3db/m3/src/x/instrument"
 )
 
@@ -81,6 +82,120 @@ type LookbackLimitOptions struct {
 	ForceWaited bool
 }
 
+// TenantQuotas tracks and enforces the quotas of each tenant, so that a
+// single tenant cannot exhaust the resources of a node. Usage without a
+// tenant is not subject to any tenant quota.
+type TenantQuotas interface {
+	// IncSeriesCreated increments the series created by the tenant and
+	// returns an error if the tenant exceeded its quota.
+	IncSeriesCreated(tenant []byte, n int) error
+	// IncDatapointsWritten increments the datapoints written by the tenant and
+	// returns an error if the tenant exceeded its quota.
+	IncDatapointsWritten(tenant []byte, n int) error
+	// IncDocsMatched increments the index docs matched by queries of the
+	// tenant and returns an error if the tenant exceeded its quota, it is
+	// called by the docs limits of the query limits with the query source.
+	IncDocsMatched(tenant []byte, n int) error
+	// IncBytesRead increments the bytes read from disk by queries of the
+	// tenant and returns an error if the tenant exceeded its quota, it is
+	// called by the bytes read limit of the query limits with the query source.
+	IncBytesRead(tenant []byte, n int) error
+
+	// Options returns the current tenant quota options.
+	Options() TenantQuotasOptions
+	// Update changes the tenant quota options.
+	Update(opts TenantQuotasOptions) error
+}
+
+// TenantQuotasOptions holds the quotas enforced for each tenant.
+type TenantQuotasOptions struct {
+	// Default is the quota of each tenant without a quota of its own.
+	Default TenantQuota
+	// Tenants holds the quotas of specific tenants keyed by tenant.
+	Tenants map[string]TenantQuota
+}
+
+// TenantQuota holds the quotas of a tenant, a zero limit disables a quota.
+type TenantQuota struct {
+	// SeriesCreatedPerSecond is the number of new series a tenant can create
+	// each second.
+	SeriesCreatedPerSecond int64
+	// DatapointsWrittenPerSecond is the number of datapoints a tenant can
+	// write each second.
+	DatapointsWrittenPerSecond int64
+	// DocsMatched limits the index docs matched by queries of a tenant within
+	// a lookback period.
+	DocsMatched LookbackLimitOptions
+	// BytesRead limits the bytes read from disk by queries of a tenant within
+	// a lookback period.
+	BytesRead LookbackLimitOptions
+}
+
+// LabelCardinalityAction is the action taken on a label value that is not
+// admitted by a LabelCardinalityLimiter.
+type LabelCardinalityAction uint8
+
+const (
+	// RejectLabelCardinalityAction does not index series with the label value.
+	RejectLabelCardinalityAction LabelCardinalityAction = iota
+	// StripLabelCardinalityAction indexes series without the label value.
+	StripLabelCardinalityAction
+)
+
+// LabelCardinalityLimiter tracks the distinct values of each label indexed by
+// a namespace and limits how many values each label may have. Once a label
+// reaches its limit only values it has already seen are admitted.
+type LabelCardinalityLimiter interface {
+	// Enabled returns whether label values are tracked and limited at all.
+	Enabled() bool
+	// Action returns the action to take on label values not admitted.
+	Action() LabelCardinalityAction
+	// Admit tracks the labels of a series and appends the position of every
+	// label not admitted to rejected, which is returned. Label values are only
+	// recorded if the series is admitted by the action of the limit.
+	Admit(fields []doc.Field, rejected []int) []int
+	// Report returns the cardinality of each tracked label, ordered by
+	// descending number of values.
+	Report() []LabelCardinality
+}
+
+// LabelCardinalityOptions holds the label cardinality limits of each namespace.
+type LabelCardinalityOptions struct {
+	// Default is the limit of each namespace without a limit of its own.
+	Default LabelCardinalityLimit
+	// Namespaces holds the limits of specific namespaces keyed by namespace.
+	Namespaces map[string]LabelCardinalityLimit
+	// ExemptLabels are label names that are never tracked or limited.
+	ExemptLabels []string
+	// ExactValuesThreshold is the number of distinct values of a label that
+	// are tracked exactly before switching to an estimating sketch, zero
+	// uses the default.
+	ExactValuesThreshold int
+}
+
+// LabelCardinalityLimit is the label cardinality limit of a namespace, a zero
+// limit disables it.
+type LabelCardinalityLimit struct {
+	// MaxValuesPerLabel is the number of distinct values each label may have
+	// within an index block.
+	MaxValuesPerLabel int64
+	// Action is the action taken on values beyond the limit.
+	Action LabelCardinalityAction
+}
+
+// LabelCardinality is the tracked cardinality of a label.
+type LabelCardinality struct {
+	// Name is the label name.
+	Name string `json:"name"`
+	// Values is the number of distinct values within the current index block.
+	Values int64 `json:"values"`
+	// Estimated is true if values is estimated by a sketch.
+	Estimated bool `json:"estimated"`
+	// Rejected is the number of values not admitted within the current
+	// index block.
+	Rejected int64 `json:"rejected"`
+}
+
 // SourceLoggerBuilder builds a SourceLogger given instrument options.
 type SourceLoggerBuilder interface {
 	// NewSourceLogger builds a source logger.
@@ -133,4 +248,24 @@ type Options interface {
 
 	// SourceLogger sets the source logger.
 	SourceLoggerBuilder() SourceLoggerBuilder
+
+	// SetTenantQuotas sets the tenant quotas.
+	SetTenantQuotas(value TenantQuotas) Options
+
+	// TenantQuotas returns the tenant quotas.
+	TenantQuotas() TenantQuotas
+
+	// SetTenantTag sets the name of the tag identifying the tenant of a
+	// series, tenant quotas are not enforced for writes if not set.
+	SetTenantTag(value []byte) Options
+
+	// TenantTag returns the name of the tag identifying the tenant of a
+	// series.
+	TenantTag() []byte
+
+	// SetLabelCardinalityOptions sets the label cardinality limit options.
+	SetLabelCardinalityOptions(value LabelCardinalityOptions) Options
+
+	// LabelCardinalityOptions returns the label cardinality limit options.
+	LabelCardinalityOptions() LabelCardinalityOptions
 }
@@ -48,6 +48,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/ts"
 	"github.com/m3db/m3/src/dbnode/ts/writes"
 	"github.com/m3db/m3/src/m3ninx/doc"
+	"github.com/m3db/m3/src/m3ninx/index/segment/fst/encoding/docs"
 	"github.com/m3db/m3/src/x/clock"
 	"github.com/m3db/m3/src/x/context"
 	xerrors "github.com/m3db/m3/src/x/errors"
@@ -132,6 +133,12 @@ type dbNamespace struct {
 	// entry will be nil when this shard does not belong to current database
 	shards []databaseShard
 
+	// shardSplits maps the IDs of shards that are being split out of a
+	// parent shard to the ID of their parent shard, reads and writes of the
+	// series of a shard are served by its parent shard until the split of the
+	// shard has completed.
+	shardSplits map[uint32]uint32
+
 	increasingIndex increasingIndex
 	commitLogWriter commitLogWriter
 	reverseIndex    NamespaceIndex
@@ -170,6 +177,10 @@ type databaseNamespaceMetrics struct {
 	queryIDs            instrument.MethodMetrics
 	wideQuery           instrument.MethodMetrics
 	aggregateQuery      instrument.MethodMetrics
+	deleteSeries        instrument.MethodMetrics
+	writeExemplars      instrument.MethodMetrics
+	fetchExemplars      instrument.MethodMetrics
+	cardinality         instrument.MethodMetrics
 
 	unfulfilled             tally.Counter
 	bootstrapStart          tally.Counter
@@ -260,6 +271,10 @@ func newDatabaseNamespaceMetrics(
 		queryIDs:            instrument.NewMethodMetrics(scope, "queryIDs", opts),
 		wideQuery:           instrument.NewMethodMetrics(scope, "wideQuery", opts),
 		aggregateQuery:      instrument.NewMethodMetrics(scope, "aggregateQuery", opts),
+		deleteSeries:        instrument.NewMethodMetrics(scope, "deleteSeries", opts),
+		writeExemplars:      instrument.NewMethodMetrics(scope, "writeExemplars", opts),
+		fetchExemplars:      instrument.NewMethodMetrics(scope, "fetchExemplars", opts),
+		cardinality:         instrument.NewMethodMetrics(scope, "cardinality", opts),
 
 		unfulfilled:             bootstrapScope.Counter("unfulfilled"),
 		bootstrapStart:          bootstrapScope.Counter("start"),
@@ -344,7 +359,8 @@ func newDatabaseNamespace(
 
 	seriesOpts := NewSeriesOptionsFromOptions(opts, nopts.RetentionOptions()).
 		SetStats(series.NewStats(scope)).
-		SetColdWritesEnabled(nopts.ColdWritesEnabled())
+		SetColdWritesEnabled(nopts.ColdWritesEnabled()).
+		SetOutOfOrderWindow(nopts.OutOfOrderWindow())
 	if err := seriesOpts.Validate(); err != nil {
 		return nil, fmt.Errorf(
 			"unable to create namespace %v, invalid series options: %v",
@@ -495,6 +511,47 @@ func (n *dbNamespace) AssignShardSet(shardSet sharding.ShardSet) {
 	})
 }
 
+// shardSplitsWithLock returns the shard splits that are in progress after the
+// assignment of a shard set. A split is in progress for shards being split
+// out of an owned parent shard that were either just created or were already
+// being split, shards that completed their split stay split even if the shard
+// set still redirects them to their parent until they are marked available.
+func (n *dbNamespace) shardSplitsWithLock(
+	shardSet sharding.ShardSet,
+	createdShardIDs []uint32,
+) map[uint32]uint32 {
+	var splits map[uint32]uint32
+	for _, s := range shardSet.All() {
+		parentID, ok := sharding.SplitParentShardID(s)
+		if !ok {
+			continue
+		}
+		if _, err := shardSet.LookupShardByID(parentID); err != nil {
+			continue
+		}
+
+		shardID := s.ID()
+		_, inProgress := n.shardSplits[shardID]
+		if !inProgress {
+			for _, createdShardID := range createdShardIDs {
+				if createdShardID == shardID {
+					inProgress = true
+					break
+				}
+			}
+		}
+		if !inProgress {
+			continue
+		}
+
+		if splits == nil {
+			splits = make(map[uint32]uint32)
+		}
+		splits[shardID] = parentID
+	}
+	return splits
+}
+
 type assignShardSetOptions struct {
 	needsBootstrap    bool
 	initialAssignment bool
@@ -548,6 +605,8 @@ func (n *dbNamespace) assignShardSet(
 		}
 	}
 
+	n.shardSplits = n.shardSplitsWithLock(shardSet, createdShardIds)
+
 	if len(createdShardIds) > 0 {
 		n.log.Info("created new shards",
 			zap.Stringer("namespace", n.ID()),
@@ -784,6 +843,14 @@ func (n *dbNamespace) SeriesRefResolver(
 	tags ident.TagIterator,
 ) (bootstrap.SeriesRefResolver, bool, error) {
 	n.RLock()
+	if owner := n.shardSet.Lookup(id); owner != shardID {
+		// NB: Series that were written to the commit log before their shard
+		// was split out of its parent shard are recorded against the parent
+		// shard, resolve them in the shard they now belong to when owned.
+		if _, owned, _ := n.shardAtWithRLock(owner); owned {
+			shardID = owner
+		}
+	}
 	shard, owned, err := n.shardAtWithRLock(shardID)
 	n.RUnlock()
 	if err != nil {
@@ -831,6 +898,15 @@ func (n *dbNamespace) QueryIDs(
 	res, err := n.reverseIndex.Query(ctx, query, opts)
 	if err != nil {
 		sp.LogFields(opentracinglog.Error(err))
+	} else if isDeleted := n.deletedSeriesFn(opts.StartInclusive, opts.EndExclusive); isDeleted != nil {
+		// Remove any series deleted for the whole query range.
+		results := res.Results.Map()
+		for _, entry := range results.Iter() {
+			id := entry.Key()
+			if isDeleted(ident.BytesID(id), opts.StartInclusive, opts.EndExclusive) {
+				results.Delete(id)
+			}
+		}
 	}
 	n.metrics.queryIDs.ReportSuccessOrError(err, n.nowFn().Sub(callStart))
 	return res, err
@@ -896,11 +972,128 @@ func (n *dbNamespace) AggregateQuery(
 			xerrors.NewRetryableError(errIndexNotBootstrappedToRead)
 	}
 
-	res, err := n.reverseIndex.AggregateQuery(ctx, query, opts)
+	var (
+		res index.AggregateQueryResult
+		err error
+	)
+	// Only resolve the query from the matching series when series may have
+	// been deleted for the whole query range, otherwise the index terms are
+	// accurate.
+	isDeleted := n.deletedSeriesFn(opts.StartInclusive, opts.EndExclusive)
+	if isDeleted != nil {
+		res, err = n.aggregateQueryExcludingDeleted(ctx, query, opts, isDeleted)
+	} else {
+		res, err = n.reverseIndex.AggregateQuery(ctx, query, opts)
+	}
 	n.metrics.aggregateQuery.ReportSuccessOrError(err, n.nowFn().Sub(callStart))
 	return res, err
 }
 
+// aggregateQueryExcludingDeleted resolves an aggregate query from the
+// matching series rather than the index terms so that deleted series can be
+// excluded from the results.
+func (n *dbNamespace) aggregateQueryExcludingDeleted(
+	ctx context.Context,
+	query index.Query,
+	opts index.AggregationOptions,
+	isDeleted deletedSeriesFn,
+) (index.AggregateQueryResult, error) {
+	queryRes, err := n.reverseIndex.Query(ctx, query, opts.QueryOptions)
+	if err != nil {
+		return index.AggregateQueryResult{}, err
+	}
+
+	results := index.NewAggregateResults(n.id, index.AggregateResultsOptions{
+		SizeLimit:   opts.SeriesLimit,
+		DocsLimit:   opts.DocsLimit,
+		FieldFilter: opts.FieldFilter,
+		Type:        opts.Type,
+	}, n.opts.IndexOptions())
+	ctx.RegisterFinalizer(results)
+
+	var (
+		reader = docs.NewEncodedDocumentReader()
+		batch  []index.AggregateResultsEntry
+	)
+	for _, entry := range queryRes.Results.Map().Iter() {
+		if isDeleted(ident.BytesID(entry.Key()), opts.StartInclusive, opts.EndExclusive) {
+			continue
+		}
+
+		metadata, err := docs.MetadataFromDocument(entry.Value(), reader)
+		if err != nil {
+			return index.AggregateQueryResult{}, err
+		}
+
+		for _, field := range metadata.Fields {
+			if !opts.FieldFilter.Allow(field.Name) {
+				continue
+			}
+
+			aggEntry := index.AggregateResultsEntry{
+				Field: ident.BytesID(append([]byte(nil), field.Name...)),
+			}
+			if opts.Type == index.AggregateTagNamesAndValues {
+				aggEntry.Terms = []ident.ID{
+					ident.BytesID(append([]byte(nil), field.Value...)),
+				}
+			}
+			batch = append(batch, aggEntry)
+		}
+	}
+	results.AddFields(batch)
+
+	return index.AggregateQueryResult{
+		Results:    results,
+		Exhaustive: queryRes.Exhaustive,
+		Waited:     queryRes.Waited,
+	}, nil
+}
+
+// hasUnflushedDeletedSeries returns whether any owned shard has deleted
+// series that have not yet been removed from disk.
+func (n *dbNamespace) hasUnflushedDeletedSeries() bool {
+	for _, shard := range n.OwnedShards() {
+		if shard.HasUnflushedDeletedSeries() {
+			return true
+		}
+	}
+	return false
+}
+
+// deletedSeriesFn returns whether a series has been deleted for every block
+// in the time range.
+type deletedSeriesFn func(id ident.ID, start, end xtime.UnixNano) bool
+
+// deletedSeriesFn returns a function to check whether series have been
+// deleted for the time range, or nil if no series in the namespace can have
+// been deleted for the whole time range.
+func (n *dbNamespace) deletedSeriesFn(start, end xtime.UnixNano) deletedSeriesFn {
+	var shards map[uint32]databaseShard
+	n.RLock()
+	shardSet := n.shardSet
+	for _, shardID := range shardSet.AllIDs() {
+		shard := n.shards[shardID]
+		if shard == nil || !shard.HasDeletedSeriesInRange(start, end) {
+			continue
+		}
+		if shards == nil {
+			shards = make(map[uint32]databaseShard)
+		}
+		shards[shardID] = shard
+	}
+	n.RUnlock()
+
+	if len(shards) == 0 {
+		return nil
+	}
+
+	return func(id ident.ID, start, end xtime.UnixNano) bool {
+		shard, ok := shards[shardSet.Lookup(id)]
+		return ok && shard.IsSeriesDeleted(id, start, end)
+	}
+}
+
 func (n *dbNamespace) PrepareBootstrap(ctx context.Context) ([]databaseShard, error) {
 	ctx, span, sampled := ctx.StartSampledTraceSpan(tracepoint.NSPrepareBootstrap)
 	defer span.Finish()
@@ -914,6 +1107,8 @@ func (n *dbNamespace) PrepareBootstrap(ctx context.Context) ([]databaseShard, er
 		multiErrLock sync.Mutex
 		multiErr     xerrors.MultiError
 		shards       = n.OwnedShards()
+		splits       = n.shardSplitParents()
+		hashFn       = n.shardSet.HashFn()
 	)
 	for _, shard := range shards {
 		shard := shard
@@ -921,6 +1116,17 @@ func (n *dbNamespace) PrepareBootstrap(ctx context.Context) ([]databaseShard, er
 		go func() {
 			defer wg.Done()
 
+			// Split the filesets of the parent shard of a shard being split out
+			// so that the shard bootstraps the data it owns from disk.
+			if parent, ok := splits[shard.ID()]; ok {
+				if err := parent.SplitFileSetsInto(shard.ID(), hashFn); err != nil {
+					multiErrLock.Lock()
+					multiErr = multiErr.Add(err)
+					multiErrLock.Unlock()
+					return
+				}
+			}
+
 			err := shard.PrepareBootstrap(ctx)
 			if err != nil {
 				multiErrLock.Lock()
@@ -939,6 +1145,59 @@ func (n *dbNamespace) PrepareBootstrap(ctx context.Context) ([]databaseShard, er
 	return shards, nil
 }
 
+// shardSplitParents returns the parent shards of the shards that are being
+// split out of them keyed by the ID of the shard being split out.
+func (n *dbNamespace) shardSplitParents() map[uint32]databaseShard {
+	n.RLock()
+	defer n.RUnlock()
+
+	parents := make(map[uint32]databaseShard, len(n.shardSplits))
+	for shardID, parentID := range n.shardSplits {
+		parent, _, err := n.shardAtWithRLock(parentID)
+		if err != nil {
+			continue
+		}
+		parents[shardID] = parent
+	}
+	return parents
+}
+
+// completeShardSplits completes the splits of shards that have bootstrapped
+// by routing reads and writes of their series to them and loading the series
+// still buffered in memory by their parent shards into them.
+func (n *dbNamespace) completeShardSplits(
+	ctx context.Context,
+	nsCtx namespace.Context,
+) error {
+	var (
+		multiErr = xerrors.NewMultiError()
+		hashFn   = n.shardSet.HashFn()
+	)
+	for shardID, parent := range n.shardSplitParents() {
+		n.RLock()
+		shard, _, err := n.shardAtWithRLock(shardID)
+		n.RUnlock()
+		if err != nil || !shard.IsBootstrapped() {
+			continue
+		}
+
+		n.Lock()
+		delete(n.shardSplits, shardID)
+		n.Unlock()
+
+		if err := parent.SplitSeriesInto(ctx, shard, hashFn, nsCtx); err != nil {
+			multiErr = multiErr.Add(err)
+			continue
+		}
+
+		n.log.Info("completed shard split",
+			zap.Stringer("namespace", n.id),
+			zap.Uint32("shard", shardID),
+			zap.Uint32("parentShard", parent.ID()))
+	}
+	return multiErr.FinalError()
+}
+
 func (n *dbNamespace) ReadEncoded(
 	ctx context.Context,
 	id ident.ID,
@@ -1123,6 +1382,10 @@ func (n *dbNamespace) Bootstrap(
 	}
 	wg.Wait()
 
+	if err := n.completeShardSplits(ctx, nsCtx); err != nil {
+		multiErr = multiErr.Add(err)
+	}
+
 	if n.reverseIndex != nil {
 		indexResults := bootstrapResult.IndexResult.IndexResults()
 		n.log.Info("bootstrap index with bootstrapped index segments",
@@ -1302,8 +1565,9 @@ func (n *dbNamespace) ColdFlush(flushPersist persist.FlushPreparer) error {
 	n.RUnlock()
 
 	// If repair has run we still need cold flush regardless of whether cold writes is
-	// enabled since repairs are dependent on the cold flushing logic.
-	enabled := n.nopts.ColdWritesEnabled() || repairsAny
+	// enabled since repairs are dependent on the cold flushing logic. The same goes
+	// for series deletes, which are removed from disk by cold flushing their blocks.
+	enabled := n.nopts.ColdWritesEnabled() || repairsAny || n.hasUnflushedDeletedSeries()
 	if n.ReadOnly() || !enabled {
 		n.metrics.flushColdData.ReportSuccess(n.nowFn().Sub(callStart))
 		return nil
@@ -1523,6 +1787,184 @@ func (n *dbNamespace) Truncate() (int64, error) {
 	return totalNumSeries, nil
 }
 
+func (n *dbNamespace) DeleteSeries(
+	ctx context.Context,
+	query index.Query,
+	start, end xtime.UnixNano,
+) (map[uint32]int64, error) {
+	callStart := n.nowFn()
+	res, err := n.QueryIDs(ctx, query, index.QueryOptions{
+		StartInclusive:    start,
+		EndExclusive:      end,
+		RequireExhaustive: true,
+	})
+	if err != nil {
+		n.metrics.deleteSeries.ReportError(n.nowFn().Sub(callStart))
+		return nil, err
+	}
+
+	idsByShard := make(map[uint32][]ident.ID)
+	n.RLock()
+	for _, entry := range res.Results.Map().Iter() {
+		id := ident.BytesID(append([]byte(nil), entry.Key()...))
+		shardID := n.shardSet.Lookup(id)
+		idsByShard[shardID] = append(idsByShard[shardID], id)
+	}
+	n.RUnlock()
+
+	numSeries := make(map[uint32]int64, len(idsByShard))
+	for shardID, ids := range idsByShard {
+		shard, _, err := n.ReadableShardAt(shardID)
+		if err != nil {
+			n.metrics.deleteSeries.ReportError(n.nowFn().Sub(callStart))
+			return numSeries, err
+		}
+		if err := shard.DeleteSeries(ids, start, end); err != nil {
+			n.metrics.deleteSeries.ReportError(n.nowFn().Sub(callStart))
+			return numSeries, err
+		}
+		numSeries[shardID] = int64(len(ids))
+	}
+
+	n.metrics.deleteSeries.ReportSuccess(n.nowFn().Sub(callStart))
+	return numSeries, nil
+}
+
+func (n *dbNamespace) WriteExemplars(id ident.ID, exemplars []ts.Exemplar) error {
+	callStart := n.nowFn()
+	shard, _, err := n.shardFor(id)
+	if err != nil {
+		n.metrics.writeExemplars.ReportError(n.nowFn().Sub(callStart))
+		return err
+	}
+	shard.WriteExemplars(id, exemplars)
+	n.metrics.writeExemplars.ReportSuccess(n.nowFn().Sub(callStart))
+	return nil
+}
+
+func (n *dbNamespace) FetchExemplars(
+	ctx context.Context,
+	query index.Query,
+	opts index.QueryOptions,
+) (FetchExemplarsResult, error) {
+	callStart := n.nowFn()
+	res, err := n.QueryIDs(ctx, query, opts)
+	if err != nil {
+		n.metrics.fetchExemplars.ReportError(n.nowFn().Sub(callStart))
+		return FetchExemplarsResult{}, err
+	}
+
+	var (
+		result = FetchExemplarsResult{Exhaustive: res.Exhaustive}
+		reader = docs.NewEncodedDocumentReader()
+	)
+	for _, entry := range res.Results.Map().Iter() {
+		id := ident.BytesID(entry.Key())
+		shard, _, err := n.readableShardFor(id)
+		if err != nil {
+			n.metrics.fetchExemplars.ReportError(n.nowFn().Sub(callStart))
+			return FetchExemplarsResult{}, err
+		}
+
+		exemplars := shard.FetchExemplars(id, opts.StartInclusive, opts.EndExclusive)
+		if len(exemplars) == 0 {
+			continue
+		}
+
+		metadata, err := docs.MetadataFromDocument(entry.Value(), reader)
+		if err != nil {
+			n.metrics.fetchExemplars.ReportError(n.nowFn().Sub(callStart))
+			return FetchExemplarsResult{}, err
+		}
+		result.Results = append(result.Results, SeriesExemplars{
+			Metadata:  metadata,
+			Exemplars: exemplars,
+		})
+	}
+
+	n.metrics.fetchExemplars.ReportSuccess(n.nowFn().Sub(callStart))
+	return result, nil
+}
+
+func (n *dbNamespace) Cardinality(
+	ctx context.Context,
+	opts index.CardinalityOptions,
+) (index.CardinalityResult, error) {
+	callStart := n.nowFn()
+	res, err := n.QueryIDs(ctx, index.Query{Query: allQuery}, index.QueryOptions{
+		StartInclusive:    opts.StartInclusive,
+		EndExclusive:      opts.EndExclusive,
+		SeriesLimit:       opts.SeriesLimit,
+		DocsLimit:         opts.DocsLimit,
+		RequireExhaustive: opts.RequireExhaustive,
+		Source:            opts.Source,
+	})
+	if err != nil {
+		n.metrics.cardinality.ReportError(n.nowFn().Sub(callStart))
+		return index.CardinalityResult{}, err
+	}
+
+	var shards map[uint32]struct{}
+	if len(opts.Shards) > 0 {
+		shards = make(map[uint32]struct{}, len(opts.Shards))
+		for _, shard := range opts.Shards {
+			shards[shard] = struct{}{}
+		}
+	}
+
+	var (
+		acc    = index.NewCardinalityAccumulator(opts.NameField)
+		reader = docs.NewEncodedDocumentReader()
+	)
+	n.RLock()
+	shardSet := n.shardSet
+	n.RUnlock()
+	for _, entry := range res.Results.Map().Iter() {
+		if shards != nil {
+			if _, ok := shards[shardSet.Lookup(ident.BytesID(entry.Key()))]; !ok {
+				continue
+			}
+		}
+
+		metadata, err := docs.MetadataFromDocument(entry.Value(), reader)
+		if err != nil {
+			n.metrics.cardinality.ReportError(n.nowFn().Sub(callStart))
+			return index.CardinalityResult{}, err
+		}
+		acc.Add(metadata)
+	}
+
+	result := acc.Result(opts.Limit)
+	result.Exhaustive = res.Exhaustive
+	n.metrics.cardinality.ReportSuccess(n.nowFn().Sub(callStart))
+	return result, nil
+}
+
+func (n *dbNamespace) MerkleTree(
+	shardID uint32,
+	blockStart xtime.UnixNano,
+	depth int,
+) (*repair.MerkleTree, error) {
+	shard, _, err := n.ReadableShardAt(shardID)
+	if err != nil {
+		return nil, err
+	}
+	return shard.MerkleTree(blockStart, depth)
+}
+
+func (n *dbNamespace) MerkleTreeLeafEntries(
+	shardID uint32,
+	blockStart xtime.UnixNano,
+	depth int,
+	leaves []int,
+) ([]repair.MerkleTreeLeafEntry, error) {
+	shard, _, err := n.ReadableShardAt(shardID)
+	if err != nil {
+		return nil, err
+	}
+	return shard.MerkleTreeLeafEntries(blockStart, depth, leaves)
+}
+
 func (n *dbNamespace) Repair(
 	repairer databaseShardRepairer,
 	tr xtime.Range,
@@ -1680,7 +2122,7 @@ func (n *dbNamespace) SetReadOnly(value bool) {
 func (n *dbNamespace) shardFor(id ident.ID) (databaseShard, namespace.Context, error) {
 	n.RLock()
 	nsCtx := n.nsContextWithRLock()
-	shardID := n.shardSet.Lookup(id)
+	shardID := n.seriesShardIDWithRLock(id)
 	shard, _, err := n.shardAtWithRLock(shardID)
 	n.RUnlock()
 	return shard, nsCtx, err
@@ -1689,12 +2131,23 @@ func (n *dbNamespace) shardFor(id ident.ID) (databaseShard, namespace.Context, e
 func (n *dbNamespace) readableShardFor(id ident.ID) (databaseShard, namespace.Context, error) {
 	n.RLock()
 	nsCtx := n.nsContextWithRLock()
-	shardID := n.shardSet.Lookup(id)
+	shardID := n.seriesShardIDWithRLock(id)
 	shard, err := n.readableShardAtWithRLock(shardID)
 	n.RUnlock()
 	return shard, nsCtx, err
 }
 
+// seriesShardIDWithRLock returns the ID of the shard that serves a series,
+// which is the parent shard of the shard the series belongs to while that
+// shard is being split out of its parent.
+func (n *dbNamespace) seriesShardIDWithRLock(id ident.ID) uint32 {
+	shardID := n.shardSet.Lookup(id)
+	if parentID, ok := n.shardSplits[shardID]; ok {
+		return parentID
+	}
+	return shardID
+}
+
 func (n *dbNamespace) ReadableShardAt(shardID uint32) (databaseShard, namespace.Context, error) {
 	n.RLock()
 	nsCtx := n.nsContextWithRLock()
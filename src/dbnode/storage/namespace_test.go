@@ -41,6 +41,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/storage/series"
 	"github.com/m3db/m3/src/dbnode/tracepoint"
 	xmetrics "github.com/m3db/m3/src/dbnode/x/metrics"
+	"github.com/m3db/m3/src/m3ninx/doc"
 	xidx "github.com/m3db/m3/src/m3ninx/idx"
 	"github.com/m3db/m3/src/x/context"
 	xerrors "github.com/m3db/m3/src/x/errors"
@@ -681,6 +682,7 @@ func TestNamespaceFlushSkipShardNotBootstrapped(t *testing.T) {
 	shard := NewMockdatabaseShard(ctrl)
 	shard.EXPECT().ID().Return(testShardIDs[0].ID()).AnyTimes()
 	shard.EXPECT().IsBootstrapped().Return(false)
+	shard.EXPECT().HasUnflushedDeletedSeries().Return(false)
 	ns.shards[testShardIDs[0].ID()] = shard
 
 	err := ns.WarmFlush(blockStart, nil)
@@ -688,6 +690,32 @@ func TestNamespaceFlushSkipShardNotBootstrapped(t *testing.T) {
 	require.NoError(t, ns.ColdFlush(nil))
 }
 
+func TestNamespaceColdFlushDeletedSeriesColdWritesDisabled(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	ns, closer := newTestNamespace(t)
+	defer closer()
+
+	require.False(t, ns.nopts.ColdWritesEnabled())
+	ns.bootstrapState = Bootstrapped
+
+	// Only a single shard has deletes to remove from disk, but once any
+	// shard does every shard is cold flushed.
+	for i, shardID := range testShardIDs {
+		shard := NewMockdatabaseShard(ctrl)
+		shard.EXPECT().ID().Return(shardID.ID()).AnyTimes()
+		shard.EXPECT().HasUnflushedDeletedSeries().Return(i == 0).MaxTimes(1)
+		shard.EXPECT().IsBootstrapped().Return(true)
+		shard.EXPECT().
+			ColdFlush(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(shardColdFlush{}, nil)
+		ns.shards[shardID.ID()] = shard
+	}
+
+	require.NoError(t, ns.ColdFlush(nil))
+}
+
 type snapshotTestCase struct {
 	isSnapshotting                bool
 	expectSnapshot                bool
@@ -977,6 +1005,52 @@ func TestNamespaceAssignShardSet(t *testing.T) {
 	}
 }
 
+func TestNamespaceAssignShardSetSplittingShard(t *testing.T) {
+	metadata, err := namespace.NewMetadata(defaultTestNs1ID, namespace.NewOptions())
+	require.NoError(t, err)
+	hashFn := func(identifier ident.ID) uint32 { return 1 }
+	shardSet, err := sharding.NewShardSet(
+		sharding.NewShards([]uint32{0}, shard.Available), hashFn)
+	require.NoError(t, err)
+	oNs, err := newDatabaseNamespace(metadata,
+		namespace.NewRuntimeOptionsManager(metadata.ID().String()),
+		shardSet, nil, nil, nil, DefaultTestOptions())
+	require.NoError(t, err)
+	ns := oNs.(*dbNamespace)
+	defer ns.Close()
+
+	parentID := uint32(0)
+	splitShardSet, err := sharding.NewShardSet([]shard.Shard{
+		shard.NewShard(0).SetState(shard.Available),
+		shard.NewShard(1).SetState(shard.Initializing).SetRedirectToShardID(&parentID),
+	}, hashFn)
+	require.NoError(t, err)
+
+	ns.AssignShardSet(splitShardSet)
+	require.Equal(t, map[uint32]uint32{1: 0}, ns.shardSplits)
+
+	// Series of the shard being split are served by its parent shard.
+	s, _, err := ns.shardFor(ident.StringID("foo"))
+	require.NoError(t, err)
+	require.Equal(t, uint32(0), s.ID())
+
+	// Reassigning the shard set keeps the split in progress.
+	ns.AssignShardSet(splitShardSet)
+	require.Equal(t, map[uint32]uint32{1: 0}, ns.shardSplits)
+
+	// Once the split completes the shard serves its series even while the
+	// shard set still redirects it to its parent shard.
+	ns.Lock()
+	delete(ns.shardSplits, 1)
+	ns.Unlock()
+	ns.AssignShardSet(splitShardSet)
+	require.Empty(t, ns.shardSplits)
+
+	s, _, err = ns.shardFor(ident.StringID("foo"))
+	require.NoError(t, err)
+	require.Equal(t, uint32(1), s.ID())
+}
+
 type needsFlushTestCase struct {
 	shardNum   uint32
 	needsFlush map[xtime.UnixNano]bool
@@ -1475,6 +1549,168 @@ func TestNamespaceAggregateQuery(t *testing.T) {
 	require.NoError(t, ns.Close())
 }
 
+func TestNamespaceAggregateQueryDeletedSeries(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	idx := NewMockNamespaceIndex(ctrl)
+	idx.EXPECT().Bootstrapped().Return(true).AnyTimes()
+
+	ns, closer := newTestNamespaceWithIndex(t, idx)
+	defer closer()
+
+	ctx := context.NewBackground()
+	defer ctx.Close()
+
+	var (
+		blockSize  = ns.Options().RetentionOptions().BlockSize()
+		blockStart = xtime.ToUnixNano(ns.nowFn()).Truncate(blockSize)
+		query      = index.Query{Query: allQuery}
+	)
+	// All test series are hashed to the first shard.
+	shard := ns.shards[testShardIDs[0].ID()]
+	require.NoError(t, shard.DeleteSeries([]ident.ID{ident.StringID("foo")},
+		blockStart, blockStart.Add(blockSize)))
+
+	// The index terms are used when the query range includes a block without
+	// any deleted series.
+	aggOpts := index.AggregationOptions{
+		QueryOptions: index.QueryOptions{
+			StartInclusive: blockStart.Add(-blockSize),
+			EndExclusive:   blockStart.Add(blockSize),
+		},
+		Type: index.AggregateTagNamesAndValues,
+	}
+	idx.EXPECT().AggregateQuery(ctx, query, aggOpts).
+		Return(index.AggregateQueryResult{Exhaustive: true}, nil)
+	_, err := ns.AggregateQuery(ctx, query, aggOpts)
+	require.NoError(t, err)
+
+	// Otherwise the deleted series are excluded from the matching series.
+	results := index.NewQueryResults(ns.ID(), index.QueryResultsOptions{},
+		ns.opts.IndexOptions())
+	_, _, err = results.AddDocuments([]doc.Document{
+		doc.NewDocumentFromMetadata(doc.Metadata{
+			ID:     []byte("foo"),
+			Fields: []doc.Field{{Name: []byte("job"), Value: []byte("api")}},
+		}),
+		doc.NewDocumentFromMetadata(doc.Metadata{
+			ID:     []byte("bar"),
+			Fields: []doc.Field{{Name: []byte("job"), Value: []byte("db")}},
+		}),
+	})
+	require.NoError(t, err)
+
+	aggOpts.StartInclusive = blockStart
+	idx.EXPECT().Query(ctx, query, aggOpts.QueryOptions).
+		Return(index.QueryResult{Results: results, Exhaustive: true}, nil)
+	res, err := ns.AggregateQuery(ctx, query, aggOpts)
+	require.NoError(t, err)
+	require.True(t, res.Exhaustive)
+
+	values, ok := res.Results.Map().Get(ident.StringID("job"))
+	require.True(t, ok)
+	require.Equal(t, 1, values.Size())
+	_, ok = values.Map().Get(ident.StringID("db"))
+	require.True(t, ok)
+
+	idx.EXPECT().Close().Return(nil)
+	require.NoError(t, ns.Close())
+}
+
+func TestNamespaceCardinality(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	idx := NewMockNamespaceIndex(ctrl)
+	idx.EXPECT().Bootstrapped().Return(true).AnyTimes()
+
+	ns, closer := newTestNamespaceWithIndex(t, idx)
+	defer closer()
+
+	ctx := context.NewBackground()
+	defer ctx.Close()
+
+	results := index.NewQueryResults(ns.ID(), index.QueryResultsOptions{},
+		ns.opts.IndexOptions())
+	_, _, err := results.AddDocuments([]doc.Document{
+		doc.NewDocumentFromMetadata(doc.Metadata{
+			ID: []byte("foo"),
+			Fields: []doc.Field{
+				{Name: []byte("__name__"), Value: []byte("up")},
+				{Name: []byte("job"), Value: []byte("api")},
+			},
+		}),
+		doc.NewDocumentFromMetadata(doc.Metadata{
+			ID: []byte("bar"),
+			Fields: []doc.Field{
+				{Name: []byte("__name__"), Value: []byte("up")},
+				{Name: []byte("job"), Value: []byte("db")},
+			},
+		}),
+	})
+	require.NoError(t, err)
+
+	var (
+		end   = xtime.Now().Truncate(time.Second)
+		start = end.Add(-time.Hour)
+		opts  = index.CardinalityOptions{
+			StartInclusive: start,
+			EndExclusive:   end,
+			NameField:      []byte("__name__"),
+			SeriesLimit:    100,
+			DocsLimit:      200,
+		}
+	)
+	idx.EXPECT().Query(gomock.Any(), index.Query{Query: allQuery}, index.QueryOptions{
+		StartInclusive: start,
+		EndExclusive:   end,
+		SeriesLimit:    100,
+		DocsLimit:      200,
+	}).Return(index.QueryResult{Results: results, Exhaustive: true}, nil).Times(3)
+
+	res, err := ns.Cardinality(ctx, opts)
+	require.NoError(t, err)
+	require.True(t, res.Exhaustive)
+	require.Equal(t, int64(2), res.NumSeries)
+	require.Equal(t, []index.CardinalityStat{{Name: "up", Value: 2}},
+		res.SeriesCountByMetricName)
+	require.Equal(t, []index.CardinalityStat{
+		{Name: "__name__=up", Value: 2},
+		{Name: "job=api", Value: 1},
+		{Name: "job=db", Value: 1},
+	}, res.SeriesCountByLabelValuePair)
+
+	// Only series of the requested shards are included, all test series
+	// are hashed to the first shard.
+	opts.Shards = []uint32{testShardIDs[1].ID()}
+	res, err = ns.Cardinality(ctx, opts)
+	require.NoError(t, err)
+	require.Equal(t, int64(0), res.NumSeries)
+
+	opts.Shards = []uint32{testShardIDs[0].ID()}
+	res, err = ns.Cardinality(ctx, opts)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), res.NumSeries)
+
+	// Statistics computed over a subset of series due to the query limits
+	// are reported as not exhaustive.
+	opts.Shards = nil
+	opts.SeriesLimit = 1
+	idx.EXPECT().Query(gomock.Any(), index.Query{Query: allQuery}, index.QueryOptions{
+		StartInclusive: start,
+		EndExclusive:   end,
+		SeriesLimit:    1,
+		DocsLimit:      200,
+	}).Return(index.QueryResult{Results: results, Exhaustive: false}, nil)
+	res, err = ns.Cardinality(ctx, opts)
+	require.NoError(t, err)
+	require.False(t, res.Exhaustive)
+
+	idx.EXPECT().Close().Return(nil)
+	require.NoError(t, ns.Close())
+}
+
 func TestNamespaceTicksIndex(t *testing.T) {
 	ctrl := xtest.NewController(t)
 	defer ctrl.Finish()
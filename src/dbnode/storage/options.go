@@ -79,6 +79,10 @@ const (
 
 	// defaultWideBatchSize is the default batch size for wide queries.
 	defaultWideBatchSize = 1024
+
+	// defaultMaxExemplarsPerSeries is the default number of exemplars kept
+	// for each series.
+	defaultMaxExemplarsPerSeries = 10
 )
 
 var (
@@ -98,6 +102,7 @@ var (
 	errBlockLeaserNotSet          = errors.New("block leaser is not set")
 	errOnColdFlushNotSet          = errors.New("on cold flush is not set, requires at least a no-op implementation")
 	errLimitsOptionsNotSet        = errors.New("limits options are not set")
+	errMaxExemplarsNegative       = errors.New("max exemplars per series must not be negative")
 )
 
 // NewSeriesOptionsFromOptions creates a new set of database series options from provided options.
@@ -178,6 +183,7 @@ type options struct {
 	tileAggregator                  TileAggregator
 	permitsOptions                  permits.Options
 	limitsOptions                   limits.Options
+	exemplarsOptions                ExemplarsOptions
 }
 
 // NewOptions creates a new set of storage options with defaults.
@@ -253,6 +259,9 @@ func newOptions(poolOpts pool.ObjectPoolOptions) Options {
 		tileAggregator:                  &noopTileAggregator{},
 		permitsOptions:                  permits.NewOptions(),
 		limitsOptions:                   limits.DefaultLimitsOptions(iOpts),
+		exemplarsOptions: ExemplarsOptions{
+			MaxPerSeries: defaultMaxExemplarsPerSeries,
+		},
 	}
 	return o.SetEncodingM3TSZPooled()
 }
@@ -319,6 +328,10 @@ func (o *options) Validate() error {
 		return errLimitsOptionsNotSet
 	}
 
+	if o.exemplarsOptions.MaxPerSeries < 0 {
+		return errMaxExemplarsNegative
+	}
+
 	return nil
 }
 
@@ -936,6 +949,16 @@ func (o *options) TileAggregator() TileAggregator {
 	return o.tileAggregator
 }
 
+func (o *options) SetExemplarsOptions(value ExemplarsOptions) Options {
+	opts := *o
+	opts.exemplarsOptions = value
+	return &opts
+}
+
+func (o *options) ExemplarsOptions() ExemplarsOptions {
+	return o.exemplarsOptions
+}
+
 type noOpColdFlush struct{}
 
 func (n *noOpColdFlush) ColdFlushNamespace(Namespace, ColdFlushNsOpts) (OnColdFlushNamespace, error) {
@@ -25,11 +25,13 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"sort"
 	"strconv"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/m3db/m3/src/cluster/shard"
 	"github.com/m3db/m3/src/dbnode/client"
 	"github.com/m3db/m3/src/dbnode/namespace"
 	"github.com/m3db/m3/src/dbnode/retention"
@@ -64,22 +66,29 @@ type recordFn func(
 
 // TODO(rartoul): See if we can find a way to guard against too much metadata.
 type shardRepairer struct {
-	opts    Options
-	rpopts  repair.Options
-	clients []client.AdminClient
-	record  recordFn
-	nowFn   clock.NowFn
-	logger  *zap.Logger
-	scope   tally.Scope
-	metrics shardRepairerMetrics
+	opts     Options
+	rpopts   repair.Options
+	clients  []client.AdminClient
+	record   recordFn
+	nowFn    clock.NowFn
+	logger   *zap.Logger
+	scope    tally.Scope
+	metrics  shardRepairerMetrics
+	statuses *shardRepairStatuses
 }
 
 type shardRepairerMetrics struct {
-	runDefault     tally.Counter
-	runOnlyCompare tally.Counter
+	runDefault            tally.Counter
+	runOnlyCompare        tally.Counter
+	runMerkle             tally.Counter
+	merkleExchanges       tally.Counter
+	merkleComparedNodes   tally.Counter
+	merkleDivergentLeaves tally.Counter
+	merkleRepairedBlocks  tally.Counter
 }
 
 func newShardRepairerMetrics(scope tally.Scope) shardRepairerMetrics {
+	merkleScope := scope.SubScope("merkle")
 	return shardRepairerMetrics{
 		runDefault: scope.Tagged(map[string]string{
 			"repair_type": "default",
@@ -87,6 +96,13 @@ func newShardRepairerMetrics(scope tally.Scope) shardRepairerMetrics {
 		runOnlyCompare: scope.Tagged(map[string]string{
 			"repair_type": "only_compare",
 		}).Counter("run"),
+		runMerkle: scope.Tagged(map[string]string{
+			"repair_type": "merkle",
+		}).Counter("run"),
+		merkleExchanges:       merkleScope.Counter("exchanges"),
+		merkleComparedNodes:   merkleScope.Counter("compared-nodes"),
+		merkleDivergentLeaves: merkleScope.Counter("divergent-leaves"),
+		merkleRepairedBlocks:  merkleScope.Counter("repaired-blocks"),
 	}
 }
 
@@ -95,13 +111,14 @@ func newShardRepairer(opts Options, rpopts repair.Options) databaseShardRepairer
 	scope := iopts.MetricsScope().SubScope("repair")
 
 	r := shardRepairer{
-		opts:    opts,
-		rpopts:  rpopts,
-		clients: rpopts.AdminClients(),
-		nowFn:   opts.ClockOptions().NowFn(),
-		logger:  iopts.Logger(),
-		scope:   scope,
-		metrics: newShardRepairerMetrics(scope),
+		opts:     opts,
+		rpopts:   rpopts,
+		clients:  rpopts.AdminClients(),
+		nowFn:    opts.ClockOptions().NowFn(),
+		logger:   iopts.Logger(),
+		scope:    scope,
+		metrics:  newShardRepairerMetrics(scope),
+		statuses: newShardRepairStatuses(),
 	}
 	r.record = r.recordDifferences
 
@@ -112,6 +129,10 @@ func (r shardRepairer) Options() repair.Options {
 	return r.rpopts
 }
 
+func (r shardRepairer) Status() []repair.ShardStatus {
+	return r.statuses.snapshot()
+}
+
 func (r shardRepairer) Repair(
 	ctx context.Context,
 	nsCtx namespace.Context,
@@ -125,24 +146,41 @@ func (r shardRepairer) Repair(
 		defer r.metrics.runDefault.Inc(1)
 	case repair.OnlyCompareRepair:
 		defer r.metrics.runOnlyCompare.Inc(1)
+	case repair.MerkleRepair:
+		defer r.metrics.runMerkle.Inc(1)
 	default:
 		// Unknown repair type.
 		err := fmt.Errorf("unknown repair type: %v", repairType)
 		return repair.MetadataComparisonResult{}, err
 	}
 
+	r.statuses.start(nsCtx.ID, shard.ID(), repairType, tr.Start, xtime.ToUnixNano(r.nowFn()))
+
+	var (
+		metadataRes repair.MetadataComparisonResult
+		err         error
+	)
+	if repairType == repair.MerkleRepair {
+		metadataRes, err = r.merkleRepair(ctx, nsCtx, nsMeta, tr, shard)
+	} else {
+		metadataRes, err = r.metadataRepair(ctx, nsCtx, nsMeta, tr, shard)
+	}
+
+	r.statuses.complete(nsCtx.ID, shard.ID(), xtime.ToUnixNano(r.nowFn()), err)
+	return metadataRes, err
+}
+
+func (r shardRepairer) sessions() ([]sessionAndTopo, error) {
 	var sessions []sessionAndTopo
 	for _, c := range r.clients {
 		session, err := c.DefaultAdminSession()
 		if err != nil {
-			fmtErr := fmt.Errorf("error obtaining default admin session: %v", err)
-			return repair.MetadataComparisonResult{}, fmtErr
+			return nil, fmt.Errorf("error obtaining default admin session: %v", err)
 		}
 
 		topo, err := session.TopologyMap()
 		if err != nil {
-			fmtErr := fmt.Errorf("error obtaining topology map: %v", err)
-			return repair.MetadataComparisonResult{}, fmtErr
+			return nil, fmt.Errorf("error obtaining topology map: %v", err)
 		}
 
 		sessions = append(sessions, sessionAndTopo{
@@ -150,10 +188,27 @@ func (r shardRepairer) Repair(
 			topo:    topo,
 		})
 	}
+	return sessions, nil
+}
+
+// metadataRepair repairs a shard by comparing the metadata of every series
+// block of the shard with the metadata fetched from peers.
+func (r shardRepairer) metadataRepair(
+	ctx context.Context,
+	nsCtx namespace.Context,
+	nsMeta namespace.Metadata,
+	tr xtime.Range,
+	shard databaseShard,
+) (repair.MetadataComparisonResult, error) {
+	sessions, err := r.sessions()
+	if err != nil {
+		return repair.MetadataComparisonResult{}, err
+	}
 
 	var (
-		start = tr.Start
-		end   = tr.End
+		repairType = r.rpopts.Type()
+		start      = tr.Start
+		end        = tr.End
 		// Guaranteed to have at least one session and all should have an identical
 		// origin (both assumptions guaranteed by options validation).
 		origin = sessions[0].session.Origin()
@@ -170,7 +225,6 @@ func (r shardRepairer) Repair(
 	var (
 		accumLocalMetadata = block.NewFetchBlocksMetadataResults()
 		pageToken          PageToken
-		err                error
 	)
 	// Safe to register since by the time this function completes we won't be using the metadata
 	// for anything anymore.
@@ -304,6 +358,240 @@ func (r shardRepairer) Repair(
 		}
 	}
 
+	if err := r.repairBlocksFromPeers(nsMeta, shard, sessions, metadatasToFetchBlocksForPerSession); err != nil {
+		return repair.MetadataComparisonResult{}, err
+	}
+
+	return metadataRes, nil
+}
+
+// merkleRepair repairs a shard by comparing the merkle trees of the flushed
+// blocks of the shard with those of its peers, only fetching the metadata of
+// the series in divergent leaves and then only the series blocks that differ.
+func (r shardRepairer) merkleRepair(
+	ctx context.Context,
+	nsCtx namespace.Context,
+	nsMeta namespace.Metadata,
+	tr xtime.Range,
+	shard databaseShard,
+) (repair.MetadataComparisonResult, error) {
+	sessions, err := r.sessions()
+	if err != nil {
+		return repair.MetadataComparisonResult{}, err
+	}
+
+	var (
+		blockSize = nsMeta.Options().RetentionOptions().BlockSize()
+		depth     = r.rpopts.MerkleTreeDepth()
+		slicePool = r.rpopts.ReplicaMetadataSlicePool()
+		// Guaranteed to have at least one session and all should have an identical
+		// origin (both assumptions guaranteed by options validation).
+		origin      = sessions[0].session.Origin()
+		metadataRes = repair.MetadataComparisonResult{
+			SizeDifferences:     repair.NewReplicaSeriesMetadata(),
+			ChecksumDifferences: repair.NewReplicaSeriesMetadata(),
+		}
+		peerResults = make(map[string]*repair.PeerMetadataComparisonResult)
+		// TODO(rartoul): Pool these slices.
+		metadatasToFetchBlocksForPerSession = make([][]block.ReplicaMetadata, len(sessions))
+	)
+
+	for blockStart := tr.Start.Truncate(blockSize); blockStart.Before(tr.End); blockStart = blockStart.Add(blockSize) {
+		r.statuses.setBlockStart(nsCtx.ID, shard.ID(), blockStart)
+
+		tree, err := shard.MerkleTree(blockStart, depth)
+		if err != nil {
+			return repair.MetadataComparisonResult{}, err
+		}
+
+		var localEntries map[string]repair.MerkleTreeLeafEntry
+		for i, sesTopo := range sessions {
+			peers, err := r.availablePeers(sesTopo.topo, shard.ID(), origin)
+			if err != nil {
+				return repair.MetadataComparisonResult{}, err
+			}
+
+			for _, peer := range peers {
+				session := sesTopo.session
+				nodesOpts := client.FetchMerkleTreeNodesOptions{
+					Namespace:  nsCtx.ID,
+					Shard:      shard.ID(),
+					BlockStart: blockStart,
+					Depth:      depth,
+				}
+				comparison, err := repair.CompareMerkleTrees(tree, func(level int, indices []int) ([]uint64, error) {
+					opts := nodesOpts
+					opts.Level = level
+					opts.Indices = indices
+					nodes, err := session.FetchMerkleTreeNodesFromPeer(peer, opts)
+					if err != nil {
+						return nil, err
+					}
+					return nodes.Digests, nil
+				})
+				if err != nil {
+					return repair.MetadataComparisonResult{}, err
+				}
+
+				r.metrics.merkleExchanges.Inc(comparison.Exchanges)
+				r.metrics.merkleComparedNodes.Inc(comparison.ComparedNodes)
+				r.metrics.merkleDivergentLeaves.Inc(int64(len(comparison.DivergentLeaves)))
+				r.statuses.addMerkleTreeComparison(nsCtx.ID, shard.ID(), comparison)
+
+				peerResult, ok := peerResults[peer.ID()]
+				if !ok {
+					peerResult = &repair.PeerMetadataComparisonResult{ID: peer.ID()}
+					peerResults[peer.ID()] = peerResult
+				}
+				if len(comparison.DivergentLeaves) == 0 {
+					continue
+				}
+
+				if localEntries == nil {
+					// Lazily read the local entries of all the leaves the first time any
+					// peer diverges since the divergent leaves are usually similar across
+					// peers.
+					entries, err := shard.MerkleTreeLeafEntries(blockStart, depth, nil)
+					if err != nil {
+						return repair.MetadataComparisonResult{}, err
+					}
+					localEntries = make(map[string]repair.MerkleTreeLeafEntry, len(entries))
+					for _, entry := range entries {
+						localEntries[entry.ID.String()] = entry
+					}
+				}
+
+				opts := nodesOpts
+				opts.Level = depth
+				opts.Indices = comparison.DivergentLeaves
+				opts.IncludeLeafEntries = true
+				nodes, err := session.FetchMerkleTreeNodesFromPeer(peer, opts)
+				if err != nil {
+					return repair.MetadataComparisonResult{}, err
+				}
+
+				divergentLeaves := make(map[int]struct{}, len(comparison.DivergentLeaves))
+				for _, leaf := range comparison.DivergentLeaves {
+					divergentLeaves[leaf] = struct{}{}
+				}
+				var (
+					local   []repair.MerkleTreeLeafEntry
+					peerIDs = make(map[string]struct{}, len(nodes.LeafEntries))
+					peers   = make([]repair.MerkleTreeLeafEntry, 0, len(nodes.LeafEntries))
+				)
+				for _, entry := range localEntries {
+					if _, ok := divergentLeaves[entry.Leaf]; ok {
+						local = append(local, entry)
+					}
+				}
+				for _, entry := range nodes.LeafEntries {
+					peerIDs[entry.ID.String()] = struct{}{}
+					peers = append(peers, repair.MerkleTreeLeafEntry{
+						Leaf:     entry.Leaf,
+						ID:       entry.ID,
+						Size:     entry.Size,
+						Checksum: entry.Checksum,
+					})
+				}
+
+				peerResult.ComparedBlocks += int64(len(peers))
+				for _, entry := range local {
+					if _, ok := peerIDs[entry.ID.String()]; !ok {
+						peerResult.ComparedExtraBlocks++
+						peerResult.ComparedDifferingBlocks++
+					}
+				}
+
+				for _, entry := range repair.DivergentMerkleTreeLeafEntries(local, peers) {
+					peerMetadata := newMerkleTreeReplicaMetadata(peer, blockStart, entry)
+					localEntry, existsLocally := localEntries[entry.ID.String()]
+					if existsLocally {
+						peerResult.ComparedMismatchBlocks++
+					} else {
+						peerResult.ComparedMissingBlocks++
+					}
+					peerResult.ComparedDifferingBlocks++
+
+					differences := []repair.ReplicaSeriesMetadata{metadataRes.ChecksumDifferences}
+					if !existsLocally || localEntry.Size != entry.Size {
+						differences = append(differences, metadataRes.SizeDifferences)
+					}
+					for _, diff := range differences {
+						replicaBlock := diff.GetOrAdd(entry.ID).GetOrAdd(blockStart, slicePool)
+						if existsLocally {
+							replicaBlock.Add(newMerkleTreeReplicaMetadata(origin, blockStart, localEntry))
+						}
+						replicaBlock.Add(peerMetadata)
+					}
+
+					metadatasToFetchBlocksForPerSession[i] = append(metadatasToFetchBlocksForPerSession[i], peerMetadata)
+				}
+			}
+		}
+
+		if localEntries != nil {
+			metadataRes.NumBlocks += int64(len(localEntries))
+		}
+	}
+
+	metadataRes.NumSeries = metadataRes.ChecksumDifferences.NumSeries()
+	for _, peerResult := range peerResults {
+		metadataRes.PeerMetadataComparisonResults = append(metadataRes.PeerMetadataComparisonResults, *peerResult)
+	}
+	sort.Slice(metadataRes.PeerMetadataComparisonResults, func(i, j int) bool {
+		return metadataRes.PeerMetadataComparisonResults[i].ID < metadataRes.PeerMetadataComparisonResults[j].ID
+	})
+
+	r.record(origin, nsCtx.ID, shard, metadataRes)
+
+	if err := r.repairBlocksFromPeers(nsMeta, shard, sessions, metadatasToFetchBlocksForPerSession); err != nil {
+		return repair.MetadataComparisonResult{}, err
+	}
+
+	return metadataRes, nil
+}
+
+// availablePeers returns the peers other than the origin which have the
+// shard available in the topology.
+func (r shardRepairer) availablePeers(
+	topo topology.Map,
+	shardID uint32,
+	origin topology.Host,
+) ([]topology.Host, error) {
+	var peers []topology.Host
+	err := topo.RouteShardForEach(shardID, func(_ int, s shard.Shard, host topology.Host) {
+		if host.ID() == origin.ID() || s.State() != shard.Available {
+			return
+		}
+		peers = append(peers, host)
+	})
+	return peers, err
+}
+
+func newMerkleTreeReplicaMetadata(
+	host topology.Host,
+	blockStart xtime.UnixNano,
+	entry repair.MerkleTreeLeafEntry,
+) block.ReplicaMetadata {
+	checksum := entry.Checksum
+	return block.ReplicaMetadata{
+		Host:     host,
+		Metadata: block.NewMetadata(entry.ID, ident.Tags{}, blockStart, entry.Size, &checksum, 0),
+	}
+}
+
+// repairBlocksFromPeers fetches the given series blocks from the peers of
+// each session and loads them into the shard.
+func (r shardRepairer) repairBlocksFromPeers(
+	nsMeta namespace.Metadata,
+	shard databaseShard,
+	sessions []sessionAndTopo,
+	metadatasToFetchBlocksForPerSession [][]block.ReplicaMetadata,
+) error {
+	var (
+		rsOpts = r.opts.RepairOptions().ResultOptions()
+		level  = r.rpopts.RepairConsistencyLevel()
+	)
 	// TODO(rartoul): Copying the IDs for the purposes of the map key is wasteful. Considering using
 	// SetUnsafe or marking as NoFinalize() and making the map check IsNoFinalize().
 	results := result.NewShardResult(rsOpts)
@@ -315,7 +603,7 @@ func (r shardRepairer) Repair(
 		session := sessions[i].session
 		perSeriesReplicaIter, err := session.FetchBlocksFromPeers(nsMeta, shard.ID(), level, metadatasToFetchBlocksFor, rsOpts)
 		if err != nil {
-			return repair.MetadataComparisonResult{}, err
+			return err
 		}
 
 		for perSeriesReplicaIter.Next() {
@@ -323,7 +611,7 @@ func (r shardRepairer) Repair(
 			if existing, ok := results.BlockAt(id, block.StartTime()); ok {
 				// Merge contents with existing block.
 				if err := existing.Merge(block); err != nil {
-					return repair.MetadataComparisonResult{}, err
+					return err
 				}
 				continue
 			}
@@ -331,13 +619,11 @@ func (r shardRepairer) Repair(
 			// Add block for first time to results.
 			results.AddBlock(id, tags, block)
 		}
-	}
 
-	if err := r.loadDataIntoShard(shard, results); err != nil {
-		return repair.MetadataComparisonResult{}, err
+		r.statuses.addRepairedBlocks(nsMeta.ID(), shard.ID(), int64(len(metadatasToFetchBlocksFor)))
 	}
 
-	return metadataRes, nil
+	return r.loadDataIntoShard(shard, results)
 }
 
 // TODO(rartoul): Currently throttling via the MemoryTracker can only occur at the level of an entire
@@ -769,6 +1055,10 @@ func (r *dbRepairer) Report() {
 	}
 }
 
+func (r *dbRepairer) Status() []repair.ShardStatus {
+	return r.shardRepairer.Status()
+}
+
 func (r *dbRepairer) repairNamespaceBlockstart(n databaseNamespace, blockStart xtime.UnixNano) error {
 	var (
 		blockSize   = n.Options().RetentionOptions().BlockSize()
@@ -815,6 +1105,8 @@ func (r repairerNoOp) Stop()         {}
 func (r repairerNoOp) Repair() error { return nil }
 func (r repairerNoOp) Report()       {}
 
+func (r repairerNoOp) Status() []repair.ShardStatus { return nil }
+
 func (r shardRepairer) shadowCompare(
 	start xtime.UnixNano,
 	end xtime.UnixNano,
@@ -1005,6 +1297,125 @@ func (r shardRepairer) shadowCompare(
 	return nil
 }
 
+type shardRepairStatusKey struct {
+	namespace string
+	shard     uint32
+}
+
+// shardRepairStatuses tracks the progress of the repairs of each shard.
+type shardRepairStatuses struct {
+	sync.Mutex
+	statuses map[shardRepairStatusKey]*repair.ShardStatus
+}
+
+func newShardRepairStatuses() *shardRepairStatuses {
+	return &shardRepairStatuses{
+		statuses: make(map[shardRepairStatusKey]*repair.ShardStatus),
+	}
+}
+
+func (s *shardRepairStatuses) statusWithLock(
+	nsID ident.ID,
+	shard uint32,
+) *repair.ShardStatus {
+	key := shardRepairStatusKey{namespace: nsID.String(), shard: shard}
+	status, ok := s.statuses[key]
+	if !ok {
+		status = &repair.ShardStatus{
+			Namespace: ident.StringID(key.namespace),
+			Shard:     shard,
+		}
+		s.statuses[key] = status
+	}
+	return status
+}
+
+func (s *shardRepairStatuses) start(
+	nsID ident.ID,
+	shard uint32,
+	repairType repair.Type,
+	blockStart xtime.UnixNano,
+	now xtime.UnixNano,
+) {
+	s.Lock()
+	status := s.statusWithLock(nsID, shard)
+	status.Type = repairType
+	status.Running = true
+	status.BlockStart = blockStart
+	status.LastStarted = now
+	s.Unlock()
+}
+
+func (s *shardRepairStatuses) setBlockStart(
+	nsID ident.ID,
+	shard uint32,
+	blockStart xtime.UnixNano,
+) {
+	s.Lock()
+	s.statusWithLock(nsID, shard).BlockStart = blockStart
+	s.Unlock()
+}
+
+func (s *shardRepairStatuses) addMerkleTreeComparison(
+	nsID ident.ID,
+	shard uint32,
+	comparison repair.MerkleTreeComparison,
+) {
+	s.Lock()
+	status := s.statusWithLock(nsID, shard)
+	status.NumComparedMerkleTreeNodes += comparison.ComparedNodes
+	status.NumDivergentMerkleTreeLeaves += int64(len(comparison.DivergentLeaves))
+	s.Unlock()
+}
+
+func (s *shardRepairStatuses) addRepairedBlocks(
+	nsID ident.ID,
+	shard uint32,
+	n int64,
+) {
+	s.Lock()
+	s.statusWithLock(nsID, shard).NumRepairedBlocks += n
+	s.Unlock()
+}
+
+func (s *shardRepairStatuses) complete(
+	nsID ident.ID,
+	shard uint32,
+	now xtime.UnixNano,
+	err error,
+) {
+	s.Lock()
+	status := s.statusWithLock(nsID, shard)
+	status.Running = false
+	status.NumRepairs++
+	status.LastError = ""
+	if err != nil {
+		status.LastError = err.Error()
+	} else {
+		status.LastCompleted = now
+	}
+	s.Unlock()
+}
+
+// snapshot returns the statuses of all shards sorted by namespace and shard.
+func (s *shardRepairStatuses) snapshot() []repair.ShardStatus {
+	s.Lock()
+	statuses := make([]repair.ShardStatus, 0, len(s.statuses))
+	for _, status := range s.statuses {
+		statuses = append(statuses, *status)
+	}
+	s.Unlock()
+
+	sort.Slice(statuses, func(i, j int) bool {
+		nsI, nsJ := statuses[i].Namespace.String(), statuses[j].Namespace.String()
+		if nsI != nsJ {
+			return nsI < nsJ
+		}
+		return statuses[i].Shard < statuses[j].Shard
+	})
+	return statuses
+}
+
 type sessionAndTopo struct {
 	session client.AdminSession
 	topo    topology.Map
@@ -0,0 +1,286 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package repair
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/m3db/m3/src/x/ident"
+
+	"github.com/cespare/xxhash/v2"
+)
+
+const (
+	// MerkleTreeFanout is the number of children of each inner node of a
+	// merkle tree.
+	MerkleTreeFanout = 16
+
+	// MinMerkleTreeDepth is the minimum depth of a merkle tree.
+	MinMerkleTreeDepth = 1
+
+	// MaxMerkleTreeDepth is the maximum depth of a merkle tree.
+	MaxMerkleTreeDepth = 5
+)
+
+var (
+	errMerkleTreeDigestsLength = errors.New("merkle tree peer returned unexpected number of digests")
+)
+
+// MerkleTreeLeafEntry is the checksum of a series block that belongs to a
+// leaf of a merkle tree.
+type MerkleTreeLeafEntry struct {
+	Leaf     int
+	ID       ident.ID
+	Size     int64
+	Checksum uint32
+}
+
+// MerkleTree is a tree of xxhash64 digests of the checksums of the series
+// blocks of a shard block. Each series belongs to the leaf selected by the hash of its ID,
+// the digest of a leaf covers the checksums of its series in ID order and the
+// digest of an inner node covers the digests of its children, so replicas with
+// matching digests for a node hold the same data for the series of the node.
+type MerkleTree struct {
+	depth  int
+	levels [][]uint64
+}
+
+// NumMerkleTreeNodes returns the number of nodes at the given level of a
+// merkle tree, level zero being the root and level depth being the leaves.
+func NumMerkleTreeNodes(level int) int {
+	n := 1
+	for i := 0; i < level; i++ {
+		n *= MerkleTreeFanout
+	}
+	return n
+}
+
+// MerkleTreeLeaf returns the leaf of a merkle tree of the given depth that a
+// series belongs to.
+func MerkleTreeLeaf(id []byte, depth int) int {
+	return int(xxhash.Sum64(id) % uint64(NumMerkleTreeNodes(depth)))
+}
+
+// ValidateMerkleTreeDepth returns an error if the depth of a merkle tree is
+// out of the supported range.
+func ValidateMerkleTreeDepth(depth int) error {
+	if depth < MinMerkleTreeDepth || depth > MaxMerkleTreeDepth {
+		return fmt.Errorf("merkle tree depth %d must be between %d and %d",
+			depth, MinMerkleTreeDepth, MaxMerkleTreeDepth)
+	}
+	return nil
+}
+
+// Depth returns the depth of the tree.
+func (t *MerkleTree) Depth() int {
+	return t.depth
+}
+
+// Root returns the digest of the root of the tree.
+func (t *MerkleTree) Root() uint64 {
+	return t.levels[0][0]
+}
+
+// Digests returns the digests of the nodes at the given indices of a level
+// of the tree, or of all nodes of the level when no indices are given.
+func (t *MerkleTree) Digests(level int, indices []int) ([]uint64, error) {
+	if level < 0 || level > t.depth {
+		return nil, fmt.Errorf("merkle tree level %d out of range, depth is %d",
+			level, t.depth)
+	}
+	nodes := t.levels[level]
+	if len(indices) == 0 {
+		return append([]uint64(nil), nodes...), nil
+	}
+	digests := make([]uint64, 0, len(indices))
+	for _, idx := range indices {
+		if idx < 0 || idx >= len(nodes) {
+			return nil, fmt.Errorf("merkle tree node %d out of range at level %d",
+				idx, level)
+		}
+		digests = append(digests, nodes[idx])
+	}
+	return digests, nil
+}
+
+// MerkleTreeBuilder builds a merkle tree from the checksums of series blocks.
+type MerkleTreeBuilder struct {
+	depth  int
+	leaves [][]merkleTreeLeafChecksum
+}
+
+type merkleTreeLeafChecksum struct {
+	id       []byte
+	checksum uint32
+}
+
+// NewMerkleTreeBuilder returns a new merkle tree builder for a tree of the
+// given depth.
+func NewMerkleTreeBuilder(depth int) (*MerkleTreeBuilder, error) {
+	if err := ValidateMerkleTreeDepth(depth); err != nil {
+		return nil, err
+	}
+	return &MerkleTreeBuilder{
+		depth:  depth,
+		leaves: make([][]merkleTreeLeafChecksum, NumMerkleTreeNodes(depth)),
+	}, nil
+}
+
+// Add adds the checksum of the block of a series to the tree, the ID is
+// copied so the caller may reuse it.
+func (b *MerkleTreeBuilder) Add(id []byte, checksum uint32) {
+	leaf := MerkleTreeLeaf(id, b.depth)
+	b.leaves[leaf] = append(b.leaves[leaf], merkleTreeLeafChecksum{
+		id:       append([]byte(nil), id...),
+		checksum: checksum,
+	})
+}
+
+// Build returns the merkle tree of the checksums added to the builder.
+func (b *MerkleTreeBuilder) Build() *MerkleTree {
+	var (
+		levels = make([][]uint64, b.depth+1)
+		d      = xxhash.New()
+		buf    [8]byte
+	)
+	leaves := make([]uint64, len(b.leaves))
+	for i, checksums := range b.leaves {
+		sort.Slice(checksums, func(i, j int) bool {
+			return string(checksums[i].id) < string(checksums[j].id)
+		})
+		d.Reset()
+		for _, c := range checksums {
+			binary.BigEndian.PutUint32(buf[:4], uint32(len(c.id)))
+			d.Write(buf[:4])
+			d.Write(c.id)
+			binary.BigEndian.PutUint32(buf[:4], c.checksum)
+			d.Write(buf[:4])
+		}
+		leaves[i] = d.Sum64()
+	}
+	levels[b.depth] = leaves
+
+	for level := b.depth - 1; level >= 0; level-- {
+		var (
+			children = levels[level+1]
+			nodes    = make([]uint64, len(children)/MerkleTreeFanout)
+		)
+		for i := range nodes {
+			d.Reset()
+			for _, child := range children[i*MerkleTreeFanout : (i+1)*MerkleTreeFanout] {
+				binary.BigEndian.PutUint64(buf[:], child)
+				d.Write(buf[:])
+			}
+			nodes[i] = d.Sum64()
+		}
+		levels[level] = nodes
+	}
+
+	return &MerkleTree{depth: b.depth, levels: levels}
+}
+
+// MerkleTreeDigestsFn returns the digests of the nodes at the given indices
+// of a level of the merkle tree of a peer.
+type MerkleTreeDigestsFn func(level int, indices []int) ([]uint64, error)
+
+// MerkleTreeComparison is the result of comparing a merkle tree with the
+// merkle tree of a peer.
+type MerkleTreeComparison struct {
+	// DivergentLeaves are the leaves with digests that differ from the peer.
+	DivergentLeaves []int
+
+	// ComparedNodes is the number of nodes compared with the peer.
+	ComparedNodes int64
+
+	// Exchanges is the number of tree levels exchanged with the peer.
+	Exchanges int64
+}
+
+// CompareMerkleTrees compares a merkle tree with the merkle tree of a peer one
+// level at a time, descending only into the children of nodes with digests
+// that differ from the peer, and returns the leaves that differ.
+func CompareMerkleTrees(
+	local *MerkleTree,
+	peerDigests MerkleTreeDigestsFn,
+) (MerkleTreeComparison, error) {
+	var (
+		result  MerkleTreeComparison
+		indices = []int{0}
+	)
+	for level := 0; level <= local.depth && len(indices) > 0; level++ {
+		localDigests, err := local.Digests(level, indices)
+		if err != nil {
+			return MerkleTreeComparison{}, err
+		}
+		digests, err := peerDigests(level, indices)
+		if err != nil {
+			return MerkleTreeComparison{}, err
+		}
+		if len(digests) != len(indices) {
+			return MerkleTreeComparison{}, errMerkleTreeDigestsLength
+		}
+		result.Exchanges++
+		result.ComparedNodes += int64(len(indices))
+
+		var divergent []int
+		for i, idx := range indices {
+			if localDigests[i] != digests[i] {
+				divergent = append(divergent, idx)
+			}
+		}
+		if level == local.depth {
+			result.DivergentLeaves = divergent
+			break
+		}
+
+		indices = indices[:0:0]
+		for _, idx := range divergent {
+			for child := 0; child < MerkleTreeFanout; child++ {
+				indices = append(indices, idx*MerkleTreeFanout+child)
+			}
+		}
+	}
+	return result, nil
+}
+
+// DivergentMerkleTreeLeafEntries returns the entries of a peer that are
+// missing locally or that have checksums which differ from the local entries.
+func DivergentMerkleTreeLeafEntries(
+	local []MerkleTreeLeafEntry,
+	peer []MerkleTreeLeafEntry,
+) []MerkleTreeLeafEntry {
+	checksums := make(map[string]uint32, len(local))
+	for _, entry := range local {
+		checksums[entry.ID.String()] = entry.Checksum
+	}
+	var divergent []MerkleTreeLeafEntry
+	for _, entry := range peer {
+		checksum, ok := checksums[entry.ID.String()]
+		if ok && checksum == entry.Checksum {
+			continue
+		}
+		divergent = append(divergent, entry)
+	}
+	return divergent
+}
@@ -0,0 +1,137 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package repair
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/m3db/m3/src/x/ident"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestMerkleTree(t *testing.T, depth int, checksums map[string]uint32) *MerkleTree {
+	b, err := NewMerkleTreeBuilder(depth)
+	require.NoError(t, err)
+	for id, checksum := range checksums {
+		b.Add([]byte(id), checksum)
+	}
+	return b.Build()
+}
+
+func testMerkleTreeChecksums(n int) map[string]uint32 {
+	checksums := make(map[string]uint32, n)
+	for i := 0; i < n; i++ {
+		checksums[fmt.Sprintf("series-%d", i)] = uint32(i)
+	}
+	return checksums
+}
+
+func TestNewMerkleTreeBuilderInvalidDepth(t *testing.T) {
+	_, err := NewMerkleTreeBuilder(MinMerkleTreeDepth - 1)
+	require.Error(t, err)
+	_, err = NewMerkleTreeBuilder(MaxMerkleTreeDepth + 1)
+	require.Error(t, err)
+}
+
+func TestMerkleTreeBuildIsOrderIndependent(t *testing.T) {
+	checksums := testMerkleTreeChecksums(100)
+	a := newTestMerkleTree(t, 2, checksums)
+	b := newTestMerkleTree(t, 2, checksums)
+	require.Equal(t, a.Root(), b.Root())
+
+	for level := 0; level <= 2; level++ {
+		digests, err := a.Digests(level, nil)
+		require.NoError(t, err)
+		require.Len(t, digests, NumMerkleTreeNodes(level))
+	}
+
+	_, err := a.Digests(3, nil)
+	require.Error(t, err)
+	_, err = a.Digests(1, []int{MerkleTreeFanout})
+	require.Error(t, err)
+}
+
+func TestCompareMerkleTreesEqual(t *testing.T) {
+	checksums := testMerkleTreeChecksums(100)
+	local := newTestMerkleTree(t, 3, checksums)
+	peer := newTestMerkleTree(t, 3, checksums)
+
+	result, err := CompareMerkleTrees(local, peer.Digests)
+	require.NoError(t, err)
+	require.Empty(t, result.DivergentLeaves)
+	require.Equal(t, int64(1), result.Exchanges)
+	require.Equal(t, int64(1), result.ComparedNodes)
+}
+
+func TestCompareMerkleTreesDivergent(t *testing.T) {
+	var (
+		depth          = 3
+		localChecksums = testMerkleTreeChecksums(100)
+		peerChecksums  = testMerkleTreeChecksums(100)
+	)
+	peerChecksums["series-7"] = 1000
+	peerChecksums["series-extra"] = 1
+	local := newTestMerkleTree(t, depth, localChecksums)
+	peer := newTestMerkleTree(t, depth, peerChecksums)
+
+	result, err := CompareMerkleTrees(local, peer.Digests)
+	require.NoError(t, err)
+	require.Equal(t, int64(depth+1), result.Exchanges)
+
+	expected := map[int]struct{}{
+		MerkleTreeLeaf([]byte("series-7"), depth):     {},
+		MerkleTreeLeaf([]byte("series-extra"), depth): {},
+	}
+	require.Len(t, result.DivergentLeaves, len(expected))
+	for _, leaf := range result.DivergentLeaves {
+		require.Contains(t, expected, leaf)
+	}
+	// Only the children of divergent nodes are compared below the root.
+	require.True(t, result.ComparedNodes < int64(NumMerkleTreeNodes(depth)))
+}
+
+func TestCompareMerkleTreesUnexpectedDigests(t *testing.T) {
+	local := newTestMerkleTree(t, 1, testMerkleTreeChecksums(10))
+	_, err := CompareMerkleTrees(local, func(level int, indices []int) ([]uint64, error) {
+		return nil, nil
+	})
+	require.Equal(t, errMerkleTreeDigestsLength, err)
+}
+
+func TestDivergentMerkleTreeLeafEntries(t *testing.T) {
+	local := []MerkleTreeLeafEntry{
+		{ID: ident.StringID("a"), Checksum: 1},
+		{ID: ident.StringID("b"), Checksum: 2},
+		{ID: ident.StringID("c"), Checksum: 3},
+	}
+	peer := []MerkleTreeLeafEntry{
+		{ID: ident.StringID("a"), Checksum: 1},
+		{ID: ident.StringID("b"), Checksum: 20},
+		{ID: ident.StringID("d"), Checksum: 4},
+	}
+
+	divergent := DivergentMerkleTreeLeafEntries(local, peer)
+	require.Len(t, divergent, 2)
+	require.Equal(t, "b", divergent[0].ID.String())
+	require.Equal(t, "d", divergent[1].ID.String())
+}
@@ -41,6 +41,7 @@ const (
 	defaultRepairShardConcurrency           = 1
 	defaultDebugShadowComparisonsEnabled    = false
 	defaultDebugShadowComparisonsPercentage = 1.0
+	defaultMerkleTreeDepth                  = 3
 )
 
 var (
@@ -65,6 +66,7 @@ type options struct {
 	resultOptions                    result.Options
 	debugShadowComparisonsEnabled    bool
 	debugShadowComparisonsPercentage float64
+	merkleTreeDepth                  int
 }
 
 // NewOptions creates new bootstrap options
@@ -80,6 +82,7 @@ func NewOptions() Options {
 		resultOptions:                    result.NewOptions(),
 		debugShadowComparisonsEnabled:    defaultDebugShadowComparisonsEnabled,
 		debugShadowComparisonsPercentage: defaultDebugShadowComparisonsPercentage,
+		merkleTreeDepth:                  defaultMerkleTreeDepth,
 	}
 }
 
@@ -203,6 +206,16 @@ func (o *options) DebugShadowComparisonsPercentage() float64 {
 	return o.debugShadowComparisonsPercentage
 }
 
+func (o *options) SetMerkleTreeDepth(value int) Options {
+	opts := *o
+	opts.merkleTreeDepth = value
+	return &opts
+}
+
+func (o *options) MerkleTreeDepth() int {
+	return o.merkleTreeDepth
+}
+
 func (o *options) Validate() error {
 	if len(o.adminClients) == 0 {
 		return errNoAdminClient
@@ -239,5 +252,8 @@ func (o *options) Validate() error {
 		o.debugShadowComparisonsPercentage < 0 {
 		return errInvalidDebugShadowComparisonsPercentage
 	}
+	if err := ValidateMerkleTreeDepth(o.merkleTreeDepth); err != nil {
+		return err
+	}
 	return nil
 }
@@ -41,11 +41,15 @@ const (
 	// OnlyCompareRepair will compare node's integrity to other replicas without repairing blocks,
 	// this is useful for looking at the metrics emitted by the comparison.
 	OnlyCompareRepair
+	// MerkleRepair will compare merkle trees of the digests of node's flushed blocks with the
+	// merkle trees of other replicas and then only repair the series blocks that diverge.
+	MerkleRepair
 )
 
 var validTypes = []Type{
 	DefaultRepair,
 	OnlyCompareRepair,
+	MerkleRepair,
 }
 
 // UnmarshalYAML unmarshals an Type into a valid type from string.
@@ -78,6 +82,8 @@ func (t Type) String() string {
 		return "default"
 	case OnlyCompareRepair:
 		return "only_compare"
+	case MerkleRepair:
+		return "merkle"
 	default:
 		return "unknown"
 	}
@@ -400,6 +406,54 @@ type Options interface {
 	// DebugShadowComparisonsPercentage returns the debug shadow comparisons percentage.
 	DebugShadowComparisonsPercentage() float64
 
+	// SetMerkleTreeDepth sets the depth of the merkle trees compared by merkle repairs.
+	SetMerkleTreeDepth(value int) Options
+
+	// MerkleTreeDepth returns the depth of the merkle trees compared by merkle repairs.
+	MerkleTreeDepth() int
+
 	// Validate checks if the options are valid.
 	Validate() error
 }
+
+// ShardStatus is the status of the repairs of a shard of a namespace.
+type ShardStatus struct {
+	// Namespace is the namespace of the shard.
+	Namespace ident.ID
+
+	// Shard is the ID of the shard.
+	Shard uint32
+
+	// Type is the type of repair run for the shard.
+	Type Type
+
+	// Running is whether a repair of the shard is in progress.
+	Running bool
+
+	// BlockStart is the block start of the current or last repair of the shard.
+	BlockStart xtime.UnixNano
+
+	// LastStarted is when the current or last repair of the shard started.
+	LastStarted xtime.UnixNano
+
+	// LastCompleted is when the last repair of the shard completed.
+	LastCompleted xtime.UnixNano
+
+	// LastError is the error of the last repair of the shard if it failed.
+	LastError string
+
+	// NumRepairs is the number of repairs of the shard that completed.
+	NumRepairs int64
+
+	// NumComparedMerkleTreeNodes is the number of merkle tree nodes compared
+	// with peers by merkle repairs of the shard.
+	NumComparedMerkleTreeNodes int64
+
+	// NumDivergentMerkleTreeLeaves is the number of merkle tree leaves that
+	// diverged from peers in merkle repairs of the shard.
+	NumDivergentMerkleTreeLeaves int64
+
+	// NumRepairedBlocks is the number of series blocks streamed from peers
+	// to repair the shard.
+	NumRepairedBlocks int64
+}
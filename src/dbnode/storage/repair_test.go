@@ -22,10 +22,12 @@ package storage
 
 import (
 	"errors"
+	"sort"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/m3db/m3/src/cluster/shard"
 	"github.com/m3db/m3/src/dbnode/client"
 	"github.com/m3db/m3/src/dbnode/namespace"
 	"github.com/m3db/m3/src/dbnode/retention"
@@ -584,6 +586,180 @@ func TestDatabaseShardRepairerRepairMultiSession(t *testing.T) {
 		gaugesSnapshot[tally.KeyForPrefixedStringMap("repair.max-block-size-diff-as-percentage", scopeTags)].Value())
 }
 
+func TestDatabaseShardRepairerRepairMerkle(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		origin  = topology.NewHost("0", "addr0")
+		peer    = topology.NewHost("1", "addr1")
+		shardID = uint32(0)
+		depth   = 1
+	)
+
+	topoMap := topology.NewMockMap(ctrl)
+	topoMap.EXPECT().RouteShardForEach(shardID, gomock.Any()).DoAndReturn(
+		func(id uint32, fn topology.RouteForEachFn) error {
+			fn(0, shard.NewShard(id).SetState(shard.Available), origin)
+			fn(1, shard.NewShard(id).SetState(shard.Available), peer)
+			return nil
+		})
+
+	session := client.NewMockAdminSession(ctrl)
+	session.EXPECT().Origin().Return(origin).AnyTimes()
+	session.EXPECT().TopologyMap().Return(topoMap, nil)
+
+	mockClient := client.NewMockAdminClient(ctrl)
+	mockClient.EXPECT().DefaultAdminSession().Return(session, nil)
+
+	var (
+		rpOpts = testRepairOptions(ctrl).
+			SetAdminClients([]client.AdminClient{mockClient}).
+			SetType(repair.MerkleRepair).
+			SetMerkleTreeDepth(depth)
+		now       = xtime.Now()
+		nowFn     = func() time.Time { return now.ToTime() }
+		opts      = DefaultTestOptions()
+		blockSize = defaultTestRetentionOpts.BlockSize()
+		start     = now.Truncate(blockSize)
+		nsID      = ident.StringID("testNamespace")
+	)
+	opts = opts.
+		SetClockOptions(opts.ClockOptions().SetNowFn(nowFn)).
+		SetInstrumentOptions(opts.InstrumentOptions().SetMetricsScope(tally.NoopScope))
+
+	newEntries := func(checksums map[string]uint32) []repair.MerkleTreeLeafEntry {
+		var entries []repair.MerkleTreeLeafEntry
+		for id, checksum := range checksums {
+			entries = append(entries, repair.MerkleTreeLeafEntry{
+				Leaf:     repair.MerkleTreeLeaf([]byte(id), depth),
+				ID:       ident.StringID(id),
+				Size:     1,
+				Checksum: checksum,
+			})
+		}
+		return entries
+	}
+	newTree := func(entries []repair.MerkleTreeLeafEntry) *repair.MerkleTree {
+		b, err := repair.NewMerkleTreeBuilder(depth)
+		require.NoError(t, err)
+		for _, entry := range entries {
+			b.Add(entry.ID.Bytes(), entry.Checksum)
+		}
+		return b.Build()
+	}
+
+	var (
+		localEntries = newEntries(map[string]uint32{"foo": 1, "bar": 2})
+		// Series "bar" has a different checksum and "baz" is missing locally.
+		peerEntries = newEntries(map[string]uint32{"foo": 1, "bar": 3, "baz": 4})
+		peerTree    = newTree(peerEntries)
+	)
+
+	dbShard := NewMockdatabaseShard(ctrl)
+	dbShard.EXPECT().ID().Return(shardID).AnyTimes()
+	dbShard.EXPECT().MerkleTree(start, depth).Return(newTree(localEntries), nil)
+	dbShard.EXPECT().MerkleTreeLeafEntries(start, depth, nil).Return(localEntries, nil)
+	dbShard.EXPECT().LoadBlocks(gomock.Any()).Return(nil)
+
+	session.EXPECT().FetchMerkleTreeNodesFromPeer(peer, gomock.Any()).DoAndReturn(
+		func(_ topology.Host, opts client.FetchMerkleTreeNodesOptions) (client.MerkleTreeNodes, error) {
+			require.True(t, nsID.Equal(opts.Namespace))
+			require.Equal(t, start, opts.BlockStart)
+			digests, err := peerTree.Digests(opts.Level, opts.Indices)
+			if err != nil {
+				return client.MerkleTreeNodes{}, err
+			}
+			nodes := client.MerkleTreeNodes{Digests: digests}
+			if !opts.IncludeLeafEntries {
+				return nodes, nil
+			}
+			for _, entry := range peerEntries {
+				for _, leaf := range opts.Indices {
+					if entry.Leaf == leaf {
+						nodes.LeafEntries = append(nodes.LeafEntries, client.MerkleTreeLeafEntry{
+							Leaf:     entry.Leaf,
+							ID:       entry.ID,
+							Size:     entry.Size,
+							Checksum: entry.Checksum,
+						})
+					}
+				}
+			}
+			return nodes, nil
+		}).AnyTimes()
+
+	nsMeta, err := namespace.NewMetadata(nsID, namespace.NewOptions())
+	require.NoError(t, err)
+
+	peerBlocksIter := client.NewMockPeerBlocksIter(ctrl)
+	peerBlocksIter.EXPECT().Next().Return(false)
+	var fetched []string
+	session.EXPECT().
+		FetchBlocksFromPeers(nsMeta, shardID, rpOpts.RepairConsistencyLevel(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ namespace.Metadata, _ uint32, _ topology.ReadConsistencyLevel,
+			metadatas []block.ReplicaMetadata, _ result.Options) (client.PeerBlocksIter, error) {
+			for _, m := range metadatas {
+				require.Equal(t, peer.ID(), m.Host.ID())
+				require.Equal(t, start, m.Start)
+				fetched = append(fetched, m.ID.String())
+			}
+			return peerBlocksIter, nil
+		})
+
+	databaseShardRepairer := newShardRepairer(opts, rpOpts)
+	repairer := databaseShardRepairer.(shardRepairer)
+	var resDiff repair.MetadataComparisonResult
+	repairer.record = func(_ topology.Host, _ ident.ID, _ databaseShard,
+		diffRes repair.MetadataComparisonResult) {
+		resDiff = diffRes
+	}
+
+	ctx := context.NewBackground()
+	defer ctx.Close()
+
+	_, err = repairer.Repair(ctx, namespace.Context{ID: nsID}, nsMeta,
+		xtime.Range{Start: start, End: start.Add(blockSize)}, dbShard)
+	require.NoError(t, err)
+
+	sort.Strings(fetched)
+	require.Equal(t, []string{"bar", "baz"}, fetched)
+	require.Equal(t, 2, resDiff.ChecksumDifferences.Series().Len())
+	require.Equal(t, 1, resDiff.SizeDifferences.Series().Len())
+	// Only the peer entries of the divergent leaves are compared.
+	divergentLeaves := map[int]struct{}{
+		repair.MerkleTreeLeaf([]byte("bar"), depth): {},
+		repair.MerkleTreeLeaf([]byte("baz"), depth): {},
+	}
+	var comparedBlocks int64
+	for _, entry := range peerEntries {
+		if _, ok := divergentLeaves[entry.Leaf]; ok {
+			comparedBlocks++
+		}
+	}
+	require.Equal(t, repair.PeerMetadataComparisonResults{{
+		ID:                      peer.ID(),
+		ComparedBlocks:          comparedBlocks,
+		ComparedDifferingBlocks: 2,
+		ComparedMismatchBlocks:  1,
+		ComparedMissingBlocks:   1,
+	}}, resDiff.PeerMetadataComparisonResults)
+
+	statuses := repairer.Status()
+	require.Equal(t, 1, len(statuses))
+	status := statuses[0]
+	require.Equal(t, nsID.String(), status.Namespace.String())
+	require.Equal(t, shardID, status.Shard)
+	require.Equal(t, repair.MerkleRepair, status.Type)
+	require.False(t, status.Running)
+	require.Equal(t, start, status.BlockStart)
+	require.Equal(t, now, status.LastCompleted)
+	require.Equal(t, int64(1), status.NumRepairs)
+	require.Equal(t, int64(2), status.NumRepairedBlocks)
+	require.True(t, status.NumDivergentMerkleTreeLeaves > 0)
+	require.True(t, status.NumComparedMerkleTreeNodes > 0)
+}
+
 type expectedRepair struct {
 	expectedRepairRange xtime.Range
 	mockRepairResult    error
@@ -306,6 +306,7 @@ func (b *dbBuffer) Write(
 		blockSize    = ropts.BlockSize()
 		blockStart   = timestamp.Truncate(blockSize)
 		writeType    WriteType
+		outOfOrder   bool
 	)
 
 	switch {
@@ -325,7 +326,17 @@ func (b *dbBuffer) Write(
 
 	case timestamp.Before(pastLimit):
 		writeType = ColdWrite
-		if !b.opts.ColdWritesEnabled() {
+		accepted, err := b.acceptsOutOfOrder(timestamp, pastLimit, blockStart)
+		if err != nil {
+			return false, writeType, err
+		}
+		if accepted {
+			// Late writes within the out of order window are buffered separately
+			// and merged into the warm flush of the block.
+			writeType = WarmWrite
+			outOfOrder = true
+			b.opts.Stats().IncOutOfOrderWrites()
+		} else if !b.opts.ColdWritesEnabled() {
 			return false, writeType, xerrors.NewInvalidParamsError(
 				fmt.Errorf("datapoint too far in past: "+
 					"id=%s, off_by=%s, timestamp=%s, past_limit=%s, "+
@@ -408,10 +419,29 @@ func (b *dbBuffer) Write(
 		value = wOpts.TransformOptions.ForceValue
 	}
 
-	ok, err := buckets.write(timestamp, value, unit, annotation, writeType, wOpts.SchemaDesc)
+	ok, err := buckets.write(timestamp, value, unit, annotation, writeType,
+		outOfOrder, wOpts.SchemaDesc)
 	return ok, writeType, err
 }
 
+// acceptsOutOfOrder returns whether a write behind the past limit falls within
+// the out of order window of a block that has not been warm flushed yet.
+func (b *dbBuffer) acceptsOutOfOrder(
+	timestamp xtime.UnixNano,
+	pastLimit xtime.UnixNano,
+	blockStart xtime.UnixNano,
+) (bool, error) {
+	window := b.opts.OutOfOrderWindow()
+	if window <= 0 || timestamp.Before(pastLimit.Add(-window)) {
+		return false, nil
+	}
+	flushed, err := b.blockRetriever.IsBlockRetrievable(blockStart)
+	if err != nil {
+		return false, err
+	}
+	return !flushed, nil
+}
+
 func (b *dbBuffer) IsEmpty() bool {
 	// A buffer can only be empty if there are no buckets in its map, since
 	// buckets are only created when a write for a new block start is done, and
@@ -650,6 +680,8 @@ func (b *dbBuffer) WarmFlush(
 		return FlushOutcomeErr, err
 	}
 
+	outOfOrderBucket, hasOutOfOrder := buckets.outOfOrderBucket()
+
 	var (
 		stream xio.SegmentReader
 		ok     bool
@@ -662,11 +694,16 @@ func (b *dbBuffer) WarmFlush(
 		// here. Only when a previous flush fails midway through a shard will
 		// there be buckets for previous versions. In this case, we need to try
 		// to flush them again, so we merge them together to one stream and
-		// persist it.
+		// persist it. Writes buffered within the out of order window are also
+		// merged in here.
+		start := b.nowFn()
 		encoder, _, err := mergeStreamsToEncoder(blockStart, streams, b.opts, nsCtx)
 		if err != nil {
 			return FlushOutcomeErr, err
 		}
+		if hasOutOfOrder {
+			b.opts.Stats().RecordOutOfOrderMergeLatency(b.nowFn().Sub(start))
+		}
 
 		stream, ok = encoder.Stream(ctx)
 		encoder.Close()
@@ -698,6 +735,9 @@ func (b *dbBuffer) WarmFlush(
 		// set this to 1.
 		bucket.version = 1
 	}
+	if hasOutOfOrder {
+		outOfOrderBucket.version = 1
+	}
 
 	return FlushOutcomeFlushedToDisk, nil
 }
@@ -1086,8 +1126,12 @@ func (b *BufferBucketVersions) write(
 	unit xtime.Unit,
 	annotation []byte,
 	writeType WriteType,
+	outOfOrder bool,
 	schema namespace.SchemaDescr,
 ) (bool, error) {
+	if outOfOrder {
+		return b.outOfOrderBucketCreate().write(timestamp, value, unit, annotation, schema)
+	}
 	return b.writableBucketCreate(writeType).write(timestamp, value, unit, annotation, schema)
 }
 
@@ -1142,7 +1186,8 @@ func (b *BufferBucketVersions) lastRead() xtime.UnixNano {
 
 func (b *BufferBucketVersions) writableBucket(writeType WriteType) (*BufferBucket, bool) {
 	for _, bucket := range b.buckets {
-		if bucket.version == writableBucketVersion && bucket.writeType == writeType {
+		if bucket.version == writableBucketVersion && bucket.writeType == writeType &&
+			!bucket.outOfOrder {
 			return bucket, true
 		}
 	}
@@ -1150,6 +1195,32 @@ func (b *BufferBucketVersions) writableBucket(writeType WriteType) (*BufferBucke
 	return nil, false
 }
 
+// outOfOrderBucket returns the writable bucket holding the warm writes that
+// arrived within the out of order window.
+func (b *BufferBucketVersions) outOfOrderBucket() (*BufferBucket, bool) {
+	for _, bucket := range b.buckets {
+		if bucket.version == writableBucketVersion && bucket.outOfOrder {
+			return bucket, true
+		}
+	}
+
+	return nil, false
+}
+
+func (b *BufferBucketVersions) outOfOrderBucketCreate() *BufferBucket {
+	bucket, exists := b.outOfOrderBucket()
+
+	if exists {
+		return bucket
+	}
+
+	newBucket := b.bucketPool.Get()
+	newBucket.resetTo(b.start, WarmWrite, b.opts)
+	newBucket.outOfOrder = true
+	b.buckets = append(b.buckets, newBucket)
+	return newBucket
+}
+
 func (b *BufferBucketVersions) writableBucketCreate(writeType WriteType) *BufferBucket {
 	bucket, exists := b.writableBucket(writeType)
 
@@ -1212,6 +1283,7 @@ type BufferBucket struct {
 	loadedBlocks []block.DatabaseBlock
 	version      int
 	writeType    WriteType
+	outOfOrder   bool
 	firstWrite   xtime.UnixNano
 }
 
@@ -1239,6 +1311,7 @@ func (b *BufferBucket) resetTo(
 	// We would only ever create a bucket for it to be writable.
 	b.version = writableBucketVersion
 	b.writeType = writeType
+	b.outOfOrder = false
 	b.firstWrite = 0
 }
 
@@ -44,6 +44,7 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
 )
 
 var testID = ident.StringID("foo")
@@ -165,6 +166,124 @@ func TestBufferWriteTooPast(t *testing.T) {
 	assert.True(t, strings.Contains(err.Error(), "past_limit="))
 }
 
+func TestBufferWriteOutOfOrderWindow(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		scope  = tally.NewTestScope("", nil)
+		opts   = newBufferTestOptions().SetStats(NewStats(scope)).SetOutOfOrderWindow(30 * time.Second)
+		rops   = opts.RetentionOptions()
+		start  = xtime.Now().Truncate(rops.BlockSize())
+		curr   = start.Add(time.Minute)
+		buffer = newDatabaseBuffer().(*dbBuffer)
+		nsCtx  namespace.Context
+	)
+	opts = opts.SetClockOptions(opts.ClockOptions().SetNowFn(func() time.Time {
+		return curr.ToTime()
+	}))
+	retriever := NewMockQueryableBlockRetriever(ctrl)
+	retriever.EXPECT().IsBlockRetrievable(start).Return(false, nil).AnyTimes()
+	buffer.Reset(databaseBufferResetOptions{
+		Options:        opts,
+		BlockRetriever: retriever,
+	})
+	ctx := context.NewBackground()
+	defer ctx.Close()
+
+	data := []DecodedTestValue{
+		{curr.Add(-time.Second), 1, xtime.Second, nil},
+		{curr.Add(-rops.BufferPast() - 20*time.Second), 2, xtime.Second, nil},
+		{curr.Add(-rops.BufferPast() - 10*time.Second), 3, xtime.Second, nil},
+	}
+	for _, v := range data {
+		wasWritten, writeType, err := buffer.Write(ctx, testID, v.Timestamp, v.Value,
+			v.Unit, v.Annotation, WriteOptions{})
+		require.NoError(t, err)
+		require.True(t, wasWritten)
+		require.Equal(t, WarmWrite, writeType)
+	}
+
+	// Writes behind the out of order window are still rejected.
+	wasWritten, _, err := buffer.Write(ctx, testID,
+		curr.Add(-rops.BufferPast()-31*time.Second), 4, xtime.Second,
+		nil, WriteOptions{})
+	require.False(t, wasWritten)
+	require.Error(t, err)
+	require.True(t, strings.Contains(err.Error(), "datapoint too far in past"))
+
+	counters := scope.Snapshot().Counters()
+	require.Equal(t, int64(2), counters["series.out-of-order-writes+"].Value())
+
+	buckets, ok := buffer.bucketVersionsAt(start)
+	require.True(t, ok)
+	warmBucket, ok := buckets.writableBucket(WarmWrite)
+	require.True(t, ok)
+	outOfOrderBucket, ok := buckets.outOfOrderBucket()
+	require.True(t, ok)
+	require.NotEqual(t, warmBucket, outOfOrderBucket)
+
+	var persisted bool
+	persistFn := func(_ persist.Metadata, segment ts.Segment, _ uint32) error {
+		expected := make([]DecodedTestValue, len(data))
+		copy(expected, data)
+		sort.Sort(ValuesByTime(expected))
+		actual := [][]xio.BlockReader{{
+			xio.BlockReader{
+				SegmentReader: xio.NewSegmentReader(segment),
+			},
+		}}
+		requireReaderValuesEqual(t, expected, actual, opts, nsCtx)
+		persisted = true
+		return nil
+	}
+	metadata := persist.NewMetadata(doc.Metadata{
+		ID: []byte("some-id"),
+	})
+	outcome, err := buffer.WarmFlush(ctx, start, metadata, persistFn, nsCtx)
+	require.NoError(t, err)
+	require.Equal(t, FlushOutcomeFlushedToDisk, outcome)
+	require.True(t, persisted)
+
+	// Both buckets are evicted by the next tick once the block is retrievable.
+	require.Equal(t, 1, warmBucket.version)
+	require.Equal(t, 1, outOfOrderBucket.version)
+	_, ok = scope.Snapshot().Timers()["series.out-of-order-merge-latency+"]
+	require.True(t, ok)
+}
+
+func TestBufferWriteOutOfOrderWindowFlushedBlock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		opts   = newBufferTestOptions().SetOutOfOrderWindow(30 * time.Second)
+		rops   = opts.RetentionOptions()
+		start  = xtime.Now().Truncate(rops.BlockSize())
+		curr   = start.Add(time.Minute)
+		buffer = newDatabaseBuffer().(*dbBuffer)
+	)
+	opts = opts.SetClockOptions(opts.ClockOptions().SetNowFn(func() time.Time {
+		return curr.ToTime()
+	}))
+	retriever := NewMockQueryableBlockRetriever(ctrl)
+	retriever.EXPECT().IsBlockRetrievable(start).Return(true, nil)
+	buffer.Reset(databaseBufferResetOptions{
+		Options:        opts,
+		BlockRetriever: retriever,
+	})
+	ctx := context.NewBackground()
+	defer ctx.Close()
+
+	// A block that has been warm flushed can only take cold writes.
+	wasWritten, _, err := buffer.Write(ctx, testID,
+		curr.Add(-rops.BufferPast()-time.Second), 1, xtime.Second,
+		nil, WriteOptions{})
+	require.False(t, wasWritten)
+	require.Error(t, err)
+	require.True(t, xerrors.IsInvalidParams(err))
+}
+
 func maxDuration(a, b time.Duration) time.Duration {
 	if a > b {
 		return a
@@ -21,6 +21,8 @@
 package series
 
 import (
+	"time"
+
 	"github.com/m3db/m3/src/dbnode/encoding"
 	"github.com/m3db/m3/src/dbnode/retention"
 	m3dbruntime "github.com/m3db/m3/src/dbnode/runtime"
@@ -45,6 +47,7 @@ type options struct {
 	identifierPool                ident.Pool
 	stats                         Stats
 	coldWritesEnabled             bool
+	outOfOrderWindow              time.Duration
 	bufferBucketPool              *BufferBucketPool
 	bufferBucketVersionsPool      *BufferBucketVersionsPool
 	runtimeOptsMgr                m3dbruntime.OptionsManager
@@ -201,6 +204,16 @@ func (o *options) ColdWritesEnabled() bool {
 	return o.coldWritesEnabled
 }
 
+func (o *options) SetOutOfOrderWindow(value time.Duration) Options {
+	opts := *o
+	opts.outOfOrderWindow = value
+	return &opts
+}
+
+func (o *options) OutOfOrderWindow() time.Duration {
+	return o.outOfOrderWindow
+}
+
 func (o *options) SetBufferBucketVersionsPool(value *BufferBucketVersionsPool) Options {
 	opts := *o
 	opts.bufferBucketVersionsPool = value
@@ -381,6 +381,14 @@ type Options interface {
 	// ColdWritesEnabled returns whether cold writes are enabled.
 	ColdWritesEnabled() bool
 
+	// SetOutOfOrderWindow sets the window past the buffer past within which
+	// writes are accepted into the out of order buffer.
+	SetOutOfOrderWindow(value time.Duration) Options
+
+	// OutOfOrderWindow returns the window past the buffer past within which
+	// writes are accepted into the out of order buffer.
+	OutOfOrderWindow() time.Duration
+
 	// SetBufferBucketVersionsPool sets the BufferBucketVersionsPool.
 	SetBufferBucketVersionsPool(value *BufferBucketVersionsPool) Options
 
@@ -407,6 +415,8 @@ type Stats struct {
 	encodersPerBlock          tally.Histogram
 	encoderLimitWriteRejected tally.Counter
 	snapshotMergesEachBucket  tally.Counter
+	outOfOrderWrites          tally.Counter
+	outOfOrderMergeLatency    tally.Timer
 }
 
 // NewStats returns a new Stats for the provided scope.
@@ -421,6 +431,8 @@ func NewStats(scope tally.Scope) Stats {
 		encodersPerBlock:          subScope.Histogram("encoders-per-block", buckets),
 		encoderLimitWriteRejected: subScope.Counter("encoder-limit-write-rejected"),
 		snapshotMergesEachBucket:  subScope.Counter("snapshot-merges-each-bucket"),
+		outOfOrderWrites:          subScope.Counter("out-of-order-writes"),
+		outOfOrderMergeLatency:    subScope.Timer("out-of-order-merge-latency"),
 	}
 }
 
@@ -439,6 +451,17 @@ func (s Stats) RecordEncodersPerBlock(num int) {
 	s.encodersPerBlock.RecordValue(float64(num))
 }
 
+// IncOutOfOrderWrites incs the OutOfOrderWrites stat.
+func (s Stats) IncOutOfOrderWrites() {
+	s.outOfOrderWrites.Inc(1)
+}
+
+// RecordOutOfOrderMergeLatency records the time taken to merge the out of
+// order buffer into a warm flush.
+func (s Stats) RecordOutOfOrderMergeLatency(value time.Duration) {
+	s.outOfOrderMergeLatency.Record(value)
+}
+
 // IncEncoderLimitWriteRejected incs the encoderLimitWriteRejected stat.
 func (s Stats) IncEncoderLimitWriteRejected() {
 	s.encoderLimitWriteRejected.Inc(1)
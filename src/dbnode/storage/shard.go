@@ -36,11 +36,13 @@ import (
 	"github.com/m3db/m3/src/dbnode/persist/schema"
 	"github.com/m3db/m3/src/dbnode/retention"
 	"github.com/m3db/m3/src/dbnode/runtime"
+	"github.com/m3db/m3/src/dbnode/sharding"
 	"github.com/m3db/m3/src/dbnode/storage/block"
 	"github.com/m3db/m3/src/dbnode/storage/bootstrap"
 	"github.com/m3db/m3/src/dbnode/storage/bootstrap/result"
 	"github.com/m3db/m3/src/dbnode/storage/index"
 	"github.com/m3db/m3/src/dbnode/storage/index/convert"
+	"github.com/m3db/m3/src/dbnode/storage/limits"
 	"github.com/m3db/m3/src/dbnode/storage/repair"
 	"github.com/m3db/m3/src/dbnode/storage/series"
 	"github.com/m3db/m3/src/dbnode/tracepoint"
@@ -66,6 +68,7 @@ import (
 const (
 	shardIterateBatchPercent = 0.01
 	shardIterateBatchMinSize = 16
+	splitSeriesBatchSize     = 1024
 )
 
 var (
@@ -148,6 +151,12 @@ type dbShard struct {
 	logger                   *zap.Logger
 	metrics                  dbShardMetrics
 	tileAggregator           TileAggregator
+	tombstones               *seriesTombstones
+	exemplars                *seriesExemplars
+	merkleTrees              *shardMerkleTrees
+	tenantQuotas             limits.TenantQuotas
+	tenantTag                []byte
+	exemplarsPersistEnabled  bool
 	ticking                  bool
 	shard                    uint32
 	coldWritesEnabled        bool
@@ -183,11 +192,14 @@ type dbShardMetrics struct {
 	snapshotChecksumLatency             tally.Timer
 	snapshotPersistLatency              tally.Timer
 	snapshotCloseLatency                tally.Timer
+	exemplarsWritten                    tally.Counter
+	exemplarsDropped                    tally.Counter
 }
 
 func newDatabaseShardMetrics(shardID uint32, scope tally.Scope) dbShardMetrics {
 	const insertErrorName = "insert-async.errors"
 	snapshotScope := scope.SubScope("snapshot")
+	exemplarsScope := scope.SubScope("exemplars")
 	return dbShardMetrics{
 		create:       scope.Counter("create"),
 		close:        scope.Counter("close"),
@@ -220,6 +232,8 @@ func newDatabaseShardMetrics(shardID uint32, scope tally.Scope) dbShardMetrics {
 		snapshotChecksumLatency:           snapshotScope.Timer("checksum-latency"),
 		snapshotPersistLatency:            snapshotScope.Timer("persist-latency"),
 		snapshotCloseLatency:              snapshotScope.Timer("close-latency"),
+		exemplarsWritten:                  exemplarsScope.Counter("written"),
+		exemplarsDropped:                  exemplarsScope.Counter("dropped"),
 	}
 }
 
@@ -285,6 +299,14 @@ func newDatabaseShard(
 		logger:               opts.InstrumentOptions().Logger(),
 		metrics:              newDatabaseShardMetrics(shard, scope),
 		tileAggregator:       opts.TileAggregator(),
+		tombstones: newSeriesTombstones(opts.CommitLogOptions().FilesystemOptions(),
+			namespaceMetadata.ID(), shard),
+		exemplars: newSeriesExemplars(opts.CommitLogOptions().FilesystemOptions(),
+			namespaceMetadata.ID(), shard, opts.ExemplarsOptions().MaxPerSeries),
+		exemplarsPersistEnabled: opts.ExemplarsOptions().PersistEnabled,
+		merkleTrees:             newShardMerkleTrees(),
+		tenantQuotas:            opts.LimitsOptions().TenantQuotas(),
+		tenantTag:               opts.LimitsOptions().TenantTag(),
 	}
 	s.insertQueue = newDatabaseShardInsertQueue(s.insertSeriesBatch,
 		s.nowFn, scope, opts.InstrumentOptions().Logger())
@@ -677,6 +699,8 @@ func (s *dbShard) isClosingWithLock() bool {
 
 func (s *dbShard) Tick(c context.Cancellable, startTime xtime.UnixNano, nsCtx namespace.Context) (tickResult, error) {
 	s.removeAnyFlushStatesTooEarly(startTime)
+	s.removeAnyTombstonesTooEarly(startTime)
+	s.removeAnyExemplarsTooEarly(startTime)
 	return s.tickAndExpire(c, tickPolicyRegular, nsCtx)
 }
 
@@ -891,6 +915,31 @@ func (s *dbShard) Write(
 		value, unit, annotation, wOpts, false)
 }
 
+// checkTenantQuotas enforces the quotas of the tenant of the series written
+// to, if tenant quotas are enabled.
+func (s *dbShard) checkTenantQuotas(
+	tagResolver convert.TagMetadataResolver,
+	newSeries bool,
+) error {
+	if len(s.tenantTag) == 0 {
+		return nil
+	}
+
+	tenant, ok, err := tagResolver.TagValue(s.tenantTag)
+	if err != nil || !ok {
+		// NB: invalid tags are rejected when the series is indexed.
+		return nil
+	}
+
+	if err := s.tenantQuotas.IncDatapointsWritten(tenant, 1); err != nil {
+		return err
+	}
+	if newSeries {
+		return s.tenantQuotas.IncSeriesCreated(tenant, 1)
+	}
+	return nil
+}
+
 func (s *dbShard) writeAndIndex(
 	ctx context.Context,
 	id ident.ID,
@@ -910,6 +959,13 @@ func (s *dbShard) writeAndIndex(
 
 	writable := entry != nil
 
+	if err := s.checkTenantQuotas(tagResolver, !writable); err != nil {
+		if writable {
+			entry.DecrementReaderWriterCount()
+		}
+		return SeriesWrite{}, err
+	}
+
 	// If no entry and we are not writing new series asynchronously.
 	if !writable && !opts.WriteNewSeriesAsync {
 		// Avoid double lookup by enqueueing insert immediately.
@@ -1102,15 +1158,21 @@ func (s *dbShard) ReadEncoded(
 		return nil, err
 	}
 
+	var iter series.BlockReaderIter
 	if entry != nil {
-		return entry.Series.ReadEncoded(ctx, start, end, nsCtx)
+		iter, err = entry.Series.ReadEncoded(ctx, start, end, nsCtx)
+	} else {
+		retriever := s.seriesBlockRetriever
+		onRetrieve := s.seriesOnRetrieveBlock
+		opts := s.seriesOpts
+		reader := series.NewReaderUsingRetriever(id, retriever, onRetrieve, nil, opts)
+		iter, err = reader.ReadEncoded(ctx, start, end, nsCtx)
+	}
+	if err != nil || iter == nil || s.tombstones.IsEmpty() {
+		return iter, err
 	}
 
-	retriever := s.seriesBlockRetriever
-	onRetrieve := s.seriesOnRetrieveBlock
-	opts := s.seriesOpts
-	reader := series.NewReaderUsingRetriever(id, retriever, onRetrieve, nil, opts)
-	return reader.ReadEncoded(ctx, start, end, nsCtx)
+	return newTombstonesBlockReaderIter(iter, id, s.tombstones), nil
 }
 
 func (s *dbShard) FetchWideEntry(
@@ -1617,18 +1679,32 @@ func (s *dbShard) FetchBlocks(
 		return nil, err
 	}
 
+	var results []block.FetchBlockResult
 	if entry != nil {
-		return entry.Series.FetchBlocks(ctx, starts, nsCtx)
+		results, err = entry.Series.FetchBlocks(ctx, starts, nsCtx)
+	} else {
+		retriever := s.seriesBlockRetriever
+		onRetrieve := s.seriesOnRetrieveBlock
+		opts := s.seriesOpts
+		// Nil for onRead callback because we don't want peer bootstrapping to impact
+		// the behavior of the LRU
+		var onReadCb block.OnReadBlock
+		reader := series.NewReaderUsingRetriever(id, retriever, onRetrieve, onReadCb, opts)
+		results, err = reader.FetchBlocks(ctx, starts, nsCtx)
+	}
+	if err != nil || s.tombstones.IsEmpty() {
+		return results, err
+	}
+
+	// Filter out any blocks the series has been deleted from.
+	filtered := results[:0]
+	for _, result := range results {
+		if s.tombstones.Contains(id.Bytes(), result.Start) {
+			continue
+		}
+		filtered = append(filtered, result)
 	}
-
-	retriever := s.seriesBlockRetriever
-	onRetrieve := s.seriesOnRetrieveBlock
-	opts := s.seriesOpts
-	// Nil for onRead callback because we don't want peer bootstrapping to impact
-	// the behavior of the LRU
-	var onReadCb block.OnReadBlock
-	reader := series.NewReaderUsingRetriever(id, retriever, onRetrieve, onReadCb, opts)
-	return reader.FetchBlocks(ctx, starts, nsCtx)
+	return filtered, nil
 }
 
 func (s *dbShard) FetchBlocksForColdFlush(
@@ -2042,6 +2118,18 @@ func (s *dbShard) Bootstrap(
 		multiErr = multiErr.Add(err)
 	}
 
+	// Load any series deletes that have not yet expired.
+	if err := s.tombstones.Load(); err != nil {
+		multiErr = multiErr.Add(err)
+	}
+
+	// Load any persisted exemplars that have not yet expired.
+	if s.exemplarsPersistEnabled {
+		if err := s.exemplars.Load(); err != nil {
+			multiErr = multiErr.Add(err)
+		}
+	}
+
 	// Now that this shard has finished bootstrapping, attempt to cache all of its seekers. Cannot call
 	// this earlier as block lease verification will fail due to the shards not being bootstrapped
 	// (and as a result no leases can be verified since the flush state is not yet known).
@@ -2238,6 +2326,8 @@ func (s *dbShard) WarmFlush(
 		DeleteIfExists: false,
 		FileSetType:    persist.FileSetFlushType,
 	}
+	tombstonesVersion := s.tombstones.Version(blockStart)
+	flushPreparer = newTombstonesFlushPreparer(flushPreparer, s.tombstones)
 	prepared, err := flushPreparer.PrepareData(prepareOpts)
 	if err != nil {
 		return err
@@ -2274,6 +2364,22 @@ func (s *dbShard) WarmFlush(
 		multiErr = multiErr.Add(err)
 	}
 
+	if multiErr.Empty() && tombstonesVersion > 0 {
+		if err := s.tombstones.MarkFlushed(blockStart, tombstonesVersion); err != nil {
+			multiErr = multiErr.Add(err)
+		}
+	}
+
+	if multiErr.Empty() && s.exemplarsPersistEnabled {
+		blockSize := s.namespace.Options().RetentionOptions().BlockSize()
+		err := s.exemplars.Persist(blockStart, blockSize, func(id []byte) bool {
+			return s.tombstones.Contains(id, blockStart)
+		})
+		if err != nil {
+			multiErr = multiErr.Add(err)
+		}
+	}
+
 	return s.markWarmDataFlushStateSuccessOrError(blockStart, multiErr.FinalError())
 }
 
@@ -2332,6 +2438,11 @@ func (s *dbShard) ColdFlush(
 				return
 			}
 
+			if s.tombstones.Contains(seriesMetadata.ID, t) {
+				// Deleted series are dropped rather than merged.
+				return
+			}
+
 			seriesList := dirtySeriesToWrite[t]
 			if seriesList == nil {
 				seriesList = newIDList(idElementPool)
@@ -2351,7 +2462,25 @@ func (s *dbShard) ColdFlush(
 		return shardColdFlush{}, loopErr
 	}
 
-	if dirtySeries.Len() == 0 {
+	// Blocks with series deletes that have not been applied to disk yet
+	// also need to be rewritten.
+	tombstonedBlockStarts := 0
+	for _, t := range s.tombstones.UnflushedBlockStarts() {
+		hasWarmFlushed, err := s.hasWarmFlushed(t)
+		if err != nil {
+			return shardColdFlush{}, err
+		}
+		if !hasWarmFlushed {
+			// Deleted series will be dropped by the warm flush.
+			continue
+		}
+		if dirtySeriesToWrite[t] == nil {
+			dirtySeriesToWrite[t] = newIDList(idElementPool)
+		}
+		tombstonedBlockStarts++
+	}
+
+	if dirtySeries.Len() == 0 && tombstonedBlockStarts == 0 {
 		// Early exit if there is nothing dirty to merge. dirtySeriesToWrite
 		// may be non-empty when dirtySeries is empty because we purposely
 		// leave empty seriesLists in the dirtySeriesToWrite map to avoid having
@@ -2359,6 +2488,8 @@ func (s *dbShard) ColdFlush(
 		return shardColdFlush{}, nil
 	}
 
+	flushPreparer = newTombstonesFlushPreparer(flushPreparer, s.tombstones)
+
 	flush := shardColdFlush{
 		shard:   s,
 		doneFns: make([]shardColdFlushDone, 0, len(dirtySeriesToWrite)),
@@ -2386,6 +2517,7 @@ func (s *dbShard) ColdFlush(
 		}
 
 		nextVersion := coldVersion + 1
+		tombstonesVersion := s.tombstones.Version(startTime)
 		close, err := merger.Merge(fsID, mergeWithMem, nextVersion, flushPreparer, nsCtx,
 			onFlushSeries)
 		if err != nil {
@@ -2393,9 +2525,10 @@ func (s *dbShard) ColdFlush(
 			continue
 		}
 		flush.doneFns = append(flush.doneFns, shardColdFlushDone{
-			startTime:   startTime,
-			nextVersion: nextVersion,
-			close:       close,
+			startTime:         startTime,
+			nextVersion:       nextVersion,
+			tombstonesVersion: tombstonesVersion,
+			close:             close,
 		})
 	}
 	return flush, multiErr.FinalError()
@@ -2621,6 +2754,112 @@ func (s *dbShard) removeAnyFlushStatesTooEarly(startTime xtime.UnixNano) {
 	s.flushState.Unlock()
 }
 
+func (s *dbShard) removeAnyTombstonesTooEarly(startTime xtime.UnixNano) {
+	earliest := retention.FlushTimeStart(s.namespace.Options().RetentionOptions(), startTime)
+	if err := s.tombstones.RemoveBefore(earliest); err != nil {
+		s.logger.Error("failed to remove expired series tombstones",
+			zap.Stringer("namespace", s.namespace.ID()),
+			zap.Uint32("shard", s.ID()),
+			zap.Error(err))
+	}
+}
+
+func (s *dbShard) removeAnyExemplarsTooEarly(startTime xtime.UnixNano) {
+	earliest := retention.FlushTimeStart(s.namespace.Options().RetentionOptions(), startTime)
+	if err := s.exemplars.RemoveBefore(earliest); err != nil {
+		s.logger.Error("failed to remove expired exemplars",
+			zap.Stringer("namespace", s.namespace.ID()),
+			zap.Uint32("shard", s.ID()),
+			zap.Error(err))
+	}
+}
+
+func (s *dbShard) WriteExemplars(id ident.ID, exemplars []ts.Exemplar) {
+	var (
+		ropts    = s.namespace.Options().RetentionOptions()
+		now      = xtime.ToUnixNano(s.nowFn())
+		earliest = retention.FlushTimeStart(ropts, now)
+		latest   = now.Add(ropts.FutureRetentionPeriod() + ropts.BufferFuture())
+		valid    = make([]ts.Exemplar, 0, len(exemplars))
+	)
+	for _, exemplar := range exemplars {
+		if exemplar.TimestampNanos.Before(earliest) || exemplar.TimestampNanos.After(latest) {
+			continue
+		}
+		valid = append(valid, exemplar)
+	}
+
+	dropped := len(exemplars) - len(valid) + s.exemplars.Add(id.Bytes(), valid)
+	s.metrics.exemplarsWritten.Inc(int64(len(exemplars) - dropped))
+	s.metrics.exemplarsDropped.Inc(int64(dropped))
+}
+
+func (s *dbShard) FetchExemplars(id ident.ID, start, end xtime.UnixNano) []ts.Exemplar {
+	exemplars := s.exemplars.Fetch(id.Bytes(), start, end)
+	if len(exemplars) == 0 || s.tombstones.IsEmpty() {
+		return exemplars
+	}
+
+	blockSize := s.namespace.Options().RetentionOptions().BlockSize()
+	result := exemplars[:0]
+	for _, exemplar := range exemplars {
+		if !s.tombstones.Contains(id.Bytes(), exemplar.TimestampNanos.Truncate(blockSize)) {
+			result = append(result, exemplar)
+		}
+	}
+	return result
+}
+
+func (s *dbShard) DeleteSeries(ids []ident.ID, start, end xtime.UnixNano) error {
+	return s.tombstones.Add(ids, s.blockStartsInRetention(start, end))
+}
+
+func (s *dbShard) IsSeriesDeleted(id ident.ID, start, end xtime.UnixNano) bool {
+	if s.tombstones.IsEmpty() {
+		return false
+	}
+	return s.tombstones.ContainsAll(id.Bytes(), s.blockStartsInRetention(start, end))
+}
+
+func (s *dbShard) HasDeletedSeries() bool {
+	return !s.tombstones.IsEmpty()
+}
+
+func (s *dbShard) HasDeletedSeriesInRange(start, end xtime.UnixNano) bool {
+	if s.tombstones.IsEmpty() {
+		return false
+	}
+	return s.tombstones.ContainsBlocks(s.blockStartsInRetention(start, end))
+}
+
+func (s *dbShard) HasUnflushedDeletedSeries() bool {
+	return len(s.tombstones.UnflushedBlockStarts()) > 0
+}
+
+// blockStartsInRetention returns the block starts that overlap [start, end)
+// and are within retention. Deletes tombstone each of these blocks in full
+// since tombstones are tracked per block, so a time range that is not block
+// aligned deletes the data of the first and last blocks outside the range.
+func (s *dbShard) blockStartsInRetention(start, end xtime.UnixNano) []xtime.UnixNano {
+	var (
+		ropts     = s.namespace.Options().RetentionOptions()
+		blockSize = ropts.BlockSize()
+		now       = xtime.ToUnixNano(s.nowFn())
+		earliest  = retention.FlushTimeStart(ropts, now)
+		latest    = now.Add(ropts.FutureRetentionPeriod() + ropts.BufferFuture()).Truncate(blockSize)
+	)
+	blockStart := start.Truncate(blockSize)
+	if blockStart.Before(earliest) {
+		blockStart = earliest
+	}
+
+	var blockStarts []xtime.UnixNano
+	for ; blockStart.Before(end) && !blockStart.After(latest); blockStart = blockStart.Add(blockSize) {
+		blockStarts = append(blockStarts, blockStart)
+	}
+	return blockStarts
+}
+
 func (s *dbShard) CleanupExpiredFileSets(earliestToRetain xtime.UnixNano) error {
 	filePathPrefix := s.opts.CommitLogOptions().FilesystemOptions().FilePathPrefix()
 	expired, err := s.filesetPathsBeforeFn(filePathPrefix, s.namespace.ID(), s.ID(), earliestToRetain)
@@ -2629,7 +2868,9 @@ func (s *dbShard) CleanupExpiredFileSets(earliestToRetain xtime.UnixNano) error
 			filePathPrefix, s.namespace.ID(), s.ID(), err)
 	}
 
-	return s.deleteFilesFn(expired)
+	s.merkleTrees.removeBefore(earliestToRetain)
+
+	return s.deleteFileSetFiles(expired)
 }
 
 func (s *dbShard) CleanupCompactedFileSets() error {
@@ -2658,7 +2899,336 @@ func (s *dbShard) CleanupCompactedFileSets() error {
 		}
 	}
 
-	return s.deleteFilesFn(toDelete.Filepaths())
+	return s.deleteFileSetFiles(toDelete.Filepaths())
+}
+
+// deleteFileSetFiles deletes the files of data filesets, including any of
+// their files that were offloaded to the secondary tier.
+func (s *dbShard) deleteFileSetFiles(filePaths []string) error {
+	fsOpts := s.opts.CommitLogOptions().FilesystemOptions()
+	offloaded, err := fs.SecondaryTierFilePaths(fsOpts, filePaths)
+	if err != nil {
+		return err
+	}
+	// NB: delete offloaded files first since they are found through the
+	// checkpoint files on the primary tier.
+	return s.deleteFilesFn(append(offloaded, filePaths...))
+}
+
+func (s *dbShard) OffloadFileSets(offloadBefore xtime.UnixNano) error {
+	fsOpts := s.opts.CommitLogOptions().FilesystemOptions()
+	if fsOpts.SecondaryTierFilePathPrefix() == "" {
+		return nil
+	}
+
+	filePathPrefix := fsOpts.FilePathPrefix()
+	filesets, err := s.filesetsFn(filePathPrefix, s.namespace.ID(), s.ID())
+	if err != nil {
+		return fmt.Errorf("encountered errors when getting fileset files for prefix %s namespace %s shard %d: %v",
+			filePathPrefix, s.namespace.ID(), s.ID(), err)
+	}
+
+	blockStates := s.BlockStatesSnapshot()
+	blockStatesSnapshot, bootstrapped := blockStates.UnwrapValue()
+	if !bootstrapped {
+		return errShardIsNotBootstrapped
+	}
+
+	var (
+		blockSize = s.namespace.Options().RetentionOptions().BlockSize()
+		multiErr  = xerrors.NewMultiError()
+	)
+	for _, fileset := range filesets {
+		fileID := fileset.ID
+		if fileID.BlockStart.Add(blockSize).After(offloadBefore) {
+			continue
+		}
+		// Compacted volumes are about to be cleaned up, skip offloading them.
+		if fileID.VolumeIndex < blockStatesSnapshot.Snapshot[fileID.BlockStart].ColdVersion {
+			continue
+		}
+
+		offloaded, err := fs.OffloadDataFileSet(fsOpts, fileset)
+		if err != nil {
+			multiErr = multiErr.Add(err)
+			continue
+		}
+		if !offloaded {
+			continue
+		}
+
+		// Reopen any leases on the fileset so that the seekers holding the
+		// removed primary tier files are closed and their space is released.
+		_, err = s.opts.BlockLeaseManager().UpdateOpenLeases(block.LeaseDescriptor{
+			Namespace:  s.namespace.ID(),
+			Shard:      s.ID(),
+			BlockStart: fileID.BlockStart,
+		}, block.LeaseState{Volume: fileID.VolumeIndex})
+		if err != nil {
+			multiErr = multiErr.Add(err)
+		}
+	}
+
+	return multiErr.FinalError()
+}
+
+func (s *dbShard) SplitFileSetsInto(childID uint32, hashFn sharding.HashFn) error {
+	var (
+		fsOpts         = s.opts.CommitLogOptions().FilesystemOptions()
+		filePathPrefix = fsOpts.FilePathPrefix()
+		nsID           = s.namespace.ID()
+	)
+	filesets, err := s.filesetsFn(filePathPrefix, nsID, s.ID())
+	if err != nil {
+		return fmt.Errorf("encountered errors when getting fileset files for prefix %s namespace %s shard %d: %v",
+			filePathPrefix, nsID, s.ID(), err)
+	}
+	childFilesets, err := s.filesetsFn(filePathPrefix, nsID, childID)
+	if err != nil {
+		return fmt.Errorf("encountered errors when getting fileset files for prefix %s namespace %s shard %d: %v",
+			filePathPrefix, nsID, childID, err)
+	}
+
+	// Only the latest complete volume of each block holds all of the data of
+	// the block, earlier volumes are about to be cleaned up.
+	latestVolumes := make(map[xtime.UnixNano]fs.FileSetFile, len(filesets))
+	for _, fileset := range filesets {
+		if !fileset.HasCompleteCheckpointFile() {
+			continue
+		}
+		blockStart := fileset.ID.BlockStart
+		if latest, ok := latestVolumes[blockStart]; ok &&
+			latest.ID.VolumeIndex > fileset.ID.VolumeIndex {
+			continue
+		}
+		latestVolumes[blockStart] = fileset
+	}
+
+	reader, err := fs.NewReader(nil, fsOpts)
+	if err != nil {
+		return err
+	}
+	writer, err := fs.NewStreamingWriter(fsOpts)
+	if err != nil {
+		return err
+	}
+
+	multiErr := xerrors.NewMultiError()
+	for blockStart, fileset := range latestVolumes {
+		// The split of a block is only complete once the checkpoint file of
+		// the child fileset has been written, so blocks split before a restart
+		// do not need to be split again.
+		if _, ok := childFilesets.LatestVolumeForBlock(blockStart); ok {
+			continue
+		}
+		if err := s.splitFileSetInto(reader, writer, fileset.ID, childID, hashFn); err != nil {
+			multiErr = multiErr.Add(fmt.Errorf(
+				"failed to split fileset of shard %d block %s volume %d into shard %d: %w",
+				s.ID(), blockStart.String(), fileset.ID.VolumeIndex, childID, err))
+		}
+	}
+
+	return multiErr.FinalError()
+}
+
+func (s *dbShard) splitFileSetInto(
+	reader fs.DataFileSetReader,
+	writer fs.StreamingWriter,
+	fileID fs.FileSetFileIdentifier,
+	childID uint32,
+	hashFn sharding.HashFn,
+) error {
+	err := reader.Open(fs.DataReaderOpenOptions{
+		Identifier:       fileID,
+		FileSetType:      persist.FileSetFlushType,
+		StreamingEnabled: true,
+	})
+	if err != nil {
+		return err
+	}
+	defer reader.Close() // nolint: errcheck
+
+	plannedRecordsCount := uint(reader.Entries())
+	if plannedRecordsCount == 0 {
+		plannedRecordsCount = 1
+	}
+	status := reader.Status()
+	err = writer.Open(fs.StreamingWriterOpenOptions{
+		NamespaceID:         s.namespace.ID(),
+		ShardID:             childID,
+		BlockStart:          fileID.BlockStart,
+		BlockSize:           status.BlockSize,
+		VolumeIndex:         fileID.VolumeIndex,
+		Compression:         status.Compression,
+		PlannedRecordsCount: plannedRecordsCount,
+	})
+	if err != nil {
+		return err
+	}
+
+	data := make([][]byte, 1)
+	for {
+		entry, err := reader.StreamingRead()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			_ = writer.Abort()
+			return err
+		}
+		if hashFn(entry.ID) != childID {
+			continue
+		}
+
+		data[0] = entry.Data
+		err = writer.WriteAll(entry.ID, entry.EncodedTags, data, entry.DataChecksum)
+		if err != nil {
+			_ = writer.Abort()
+			return err
+		}
+	}
+
+	return writer.Close()
+}
+
+func (s *dbShard) SplitSeriesInto(
+	ctx context.Context,
+	child databaseShard,
+	hashFn sharding.HashFn,
+	nsCtx namespace.Context,
+) error {
+	var entries []*Entry
+	s.forEachShardEntry(func(entry *Entry) bool {
+		if hashFn(entry.Series.ID()) == child.ID() {
+			entry.IncrementReaderWriterCount()
+			entries = append(entries, entry)
+		}
+		return true
+	})
+	defer func() {
+		for _, entry := range entries {
+			entry.DecrementReaderWriterCount()
+		}
+	}()
+
+	multiErr := xerrors.NewMultiError()
+	for len(entries) > 0 {
+		batch := entries
+		if len(batch) > splitSeriesBatchSize {
+			batch = batch[:splitSeriesBatchSize]
+		}
+		entries = entries[len(batch):]
+
+		seriesToLoad, err := s.splitSeriesBlocks(ctx, batch, nsCtx)
+		if err != nil {
+			multiErr = multiErr.Add(err)
+		}
+		if seriesToLoad.Len() == 0 {
+			continue
+		}
+		for {
+			err := child.LoadBlocks(seriesToLoad)
+			if err == ErrDatabaseLoadLimitHit {
+				// Wait for some of the outstanding data to be flushed before trying again.
+				s.opts.MemoryTracker().WaitForDec()
+				continue
+			}
+			if err != nil {
+				multiErr = multiErr.Add(err)
+			}
+			break
+		}
+	}
+
+	return multiErr.FinalError()
+}
+
+// splitSeriesBlocks returns copies of the blocks that the series hold in
+// their buffers, keyed by the series ID.
+func (s *dbShard) splitSeriesBlocks(
+	ctx context.Context,
+	entries []*Entry,
+	nsCtx namespace.Context,
+) (*result.Map, error) {
+	var (
+		blockOpts    = s.opts.DatabaseBlockOptions()
+		blockSize    = s.namespace.Options().RetentionOptions().BlockSize()
+		seriesBlocks = result.NewMap(result.MapOptions{})
+		multiErr     = xerrors.NewMultiError()
+	)
+	for _, entry := range entries {
+		metadata, err := entry.Series.FetchBlocksMetadata(ctx, 0, xtime.UnixNano(math.MaxInt64),
+			series.FetchBlocksMetadataOptions{})
+		if err != nil {
+			multiErr = multiErr.Add(err)
+			continue
+		}
+		var starts []xtime.UnixNano
+		for _, blockMetadata := range metadata.Blocks.Results() {
+			starts = append(starts, blockMetadata.Start)
+		}
+		metadata.Blocks.Close()
+		if len(starts) == 0 {
+			continue
+		}
+
+		fetched, err := entry.Series.FetchBlocks(ctx, starts, nsCtx)
+		if err != nil {
+			multiErr = multiErr.Add(err)
+			continue
+		}
+
+		var (
+			id     = ident.BytesID(append([]byte(nil), entry.Series.ID().Bytes()...))
+			blocks = block.NewDatabaseSeriesBlocks(len(fetched))
+		)
+		for _, fetchedBlock := range fetched {
+			if fetchedBlock.Err != nil {
+				multiErr = multiErr.Add(fetchedBlock.Err)
+				continue
+			}
+			for _, reader := range fetchedBlock.Blocks {
+				segment, err := reader.Segment()
+				if err != nil {
+					multiErr = multiErr.Add(err)
+					continue
+				}
+				if segment.Len() == 0 {
+					continue
+				}
+				// NB: Copy the segment since the data of the reader is only
+				// valid until the context is closed.
+				b := block.NewDatabaseBlock(fetchedBlock.Start, blockSize,
+					segment.Clone(nil), blockOpts, nsCtx)
+				if existing, ok := blocks.BlockAt(fetchedBlock.Start); ok {
+					if err := existing.Merge(b); err != nil {
+						multiErr = multiErr.Add(err)
+					}
+					continue
+				}
+				blocks.AddBlock(b)
+			}
+		}
+		if blocks.Len() == 0 {
+			continue
+		}
+
+		fields := entry.Series.Metadata().Fields
+		tags := make([]ident.Tag, 0, len(fields))
+		for _, field := range fields {
+			tags = append(tags, ident.Tag{
+				Name:  ident.BytesID(append([]byte(nil), field.Name...)),
+				Value: ident.BytesID(append([]byte(nil), field.Value...)),
+			})
+		}
+		seriesBlocks.Set(id, result.DatabaseSeriesBlocks{
+			ID:     id,
+			Tags:   ident.NewTags(tags...),
+			Blocks: blocks,
+		})
+	}
+
+	return seriesBlocks, multiErr.FinalError()
 }
 
 func (s *dbShard) Repair(
@@ -2821,9 +3391,10 @@ func (s *dbShard) finishWriting(
 }
 
 type shardColdFlushDone struct {
-	startTime   xtime.UnixNano
-	nextVersion int
-	close       persist.DataCloser
+	startTime         xtime.UnixNano
+	nextVersion       int
+	tombstonesVersion int
+	close             persist.DataCloser
 }
 
 type shardColdFlush struct {
@@ -2845,6 +3416,14 @@ func (s shardColdFlush) Done() error {
 		err := s.shard.finishWriting(startTime, nextVersion, false)
 		if err != nil {
 			multiErr = multiErr.Add(err)
+			continue
+		}
+
+		if done.tombstonesVersion > 0 {
+			err := s.shard.tombstones.MarkFlushed(startTime, done.tombstonesVersion)
+			if err != nil {
+				multiErr = multiErr.Add(err)
+			}
 		}
 	}
 	return multiErr.FinalError()
@@ -29,6 +29,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/runtime"
 	"github.com/m3db/m3/src/dbnode/storage/index"
 	"github.com/m3db/m3/src/dbnode/storage/index/convert"
+	"github.com/m3db/m3/src/dbnode/storage/limits"
 	"github.com/m3db/m3/src/dbnode/storage/series"
 	"github.com/m3db/m3/src/m3ninx/doc"
 	"github.com/m3db/m3/src/x/context"
@@ -104,6 +105,91 @@ func TestShardInsertNamespaceIndex(t *testing.T) {
 	require.Equal(t, []byte("value"), indexWrites[0].Fields[0].Value)
 }
 
+type testTenantQuotas struct {
+	limits.TenantQuotas
+
+	sync.Mutex
+	seriesCreated     map[string]int
+	datapointsWritten map[string]int
+	rejectNewSeries   bool
+}
+
+func (q *testTenantQuotas) IncSeriesCreated(tenant []byte, n int) error {
+	q.Lock()
+	defer q.Unlock()
+	q.seriesCreated[string(tenant)] += n
+	if q.rejectNewSeries {
+		return limits.NewTenantQuotaExceededError("series created")
+	}
+	return nil
+}
+
+func (q *testTenantQuotas) IncDatapointsWritten(tenant []byte, n int) error {
+	q.Lock()
+	defer q.Unlock()
+	q.datapointsWritten[string(tenant)] += n
+	return nil
+}
+
+func TestShardWriteTaggedTenantQuotas(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	quotas := &testTenantQuotas{
+		TenantQuotas:      limits.NoOpTenantQuotas(),
+		seriesCreated:     make(map[string]int),
+		datapointsWritten: make(map[string]int),
+	}
+	opts := DefaultTestOptions()
+	opts = opts.SetLimitsOptions(opts.LimitsOptions().
+		SetTenantQuotas(quotas).
+		SetTenantTag([]byte("tenant")))
+
+	now := xtime.Now()
+	idx := NewMockNamespaceIndex(ctrl)
+	idx.EXPECT().BlockStartForWriteTime(gomock.Any()).Return(now.Truncate(time.Hour)).AnyTimes()
+	idx.EXPECT().WriteBatch(gomock.Any()).Do(
+		func(batch *index.WriteBatch) {
+			for i, e := range batch.PendingEntries() {
+				e.OnIndexSeries.OnIndexSuccess(now.Truncate(time.Hour))
+				e.OnIndexSeries.OnIndexFinalize(now.Truncate(time.Hour))
+				batch.PendingEntries()[i].OnIndexSeries = nil
+			}
+		}).Return(nil).AnyTimes()
+
+	shard := testDatabaseShardWithIndexFn(t, opts, idx, false)
+	shard.SetRuntimeOptions(runtime.NewOptions().SetWriteNewSeriesAsync(false))
+	defer shard.Close()
+
+	ctx := context.NewBackground()
+	defer ctx.Close()
+
+	write := func(id, tenant string) error {
+		tags := ident.NewTags(ident.StringTag("name", "value"))
+		if tenant != "" {
+			tags.Append(ident.StringTag("tenant", tenant))
+		}
+		_, err := shard.WriteTagged(ctx, ident.StringID(id),
+			convert.NewTagsMetadataResolver(tags),
+			now, 1.0, xtime.Second, nil, series.WriteOptions{})
+		return err
+	}
+
+	require.NoError(t, write("foo", "a"))
+	require.NoError(t, write("bar", "b"))
+	require.NoError(t, write("baz", ""))
+	require.NoError(t, write("foo", "a"))
+
+	assert.Equal(t, map[string]int{"a": 1, "b": 1}, quotas.seriesCreated)
+	assert.Equal(t, map[string]int{"a": 2, "b": 1}, quotas.datapointsWritten)
+
+	quotas.rejectNewSeries = true
+	err := write("qux", "a")
+	require.Error(t, err)
+	require.True(t, limits.IsTenantQuotaExceededError(err))
+	require.NoError(t, write("foo", "a"))
+}
+
 func TestShardAsyncInsertMarkIndexedForBlockStart(t *testing.T) {
 	ctrl := xtest.NewController(t)
 	defer ctrl.Finish()
@@ -0,0 +1,211 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/storage/repair"
+	"github.com/m3db/m3/src/x/ident"
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+type shardMerkleTreeKey struct {
+	blockStart xtime.UnixNano
+	depth      int
+}
+
+type shardMerkleTree struct {
+	volumeIndex int
+	tree        *repair.MerkleTree
+}
+
+// shardMerkleTrees caches the merkle trees of the flushed blocks of a shard,
+// the tree of a block is only rebuilt once a new volume of the block is
+// flushed since volumes are immutable.
+type shardMerkleTrees struct {
+	sync.Mutex
+	trees map[shardMerkleTreeKey]shardMerkleTree
+}
+
+func newShardMerkleTrees() *shardMerkleTrees {
+	return &shardMerkleTrees{
+		trees: make(map[shardMerkleTreeKey]shardMerkleTree),
+	}
+}
+
+func (t *shardMerkleTrees) get(
+	key shardMerkleTreeKey,
+	volumeIndex int,
+) (*repair.MerkleTree, bool) {
+	t.Lock()
+	defer t.Unlock()
+	cached, ok := t.trees[key]
+	if !ok || cached.volumeIndex != volumeIndex {
+		return nil, false
+	}
+	return cached.tree, true
+}
+
+func (t *shardMerkleTrees) set(
+	key shardMerkleTreeKey,
+	volumeIndex int,
+	tree *repair.MerkleTree,
+) {
+	t.Lock()
+	t.trees[key] = shardMerkleTree{volumeIndex: volumeIndex, tree: tree}
+	t.Unlock()
+}
+
+func (t *shardMerkleTrees) removeBefore(earliestToRetain xtime.UnixNano) {
+	t.Lock()
+	for key := range t.trees {
+		if key.blockStart.Before(earliestToRetain) {
+			delete(t.trees, key)
+		}
+	}
+	t.Unlock()
+}
+
+func (s *dbShard) MerkleTree(
+	blockStart xtime.UnixNano,
+	depth int,
+) (*repair.MerkleTree, error) {
+	builder, err := repair.NewMerkleTreeBuilder(depth)
+	if err != nil {
+		return nil, err
+	}
+
+	fileset, ok, err := s.latestFileSetForBlock(blockStart)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		// Nothing flushed for the block, every leaf of the tree is empty.
+		return builder.Build(), nil
+	}
+
+	var (
+		key         = shardMerkleTreeKey{blockStart: blockStart, depth: depth}
+		volumeIndex = fileset.ID.VolumeIndex
+	)
+	if tree, ok := s.merkleTrees.get(key, volumeIndex); ok {
+		return tree, nil
+	}
+
+	err = s.forEachFileSetMetadata(fileset.ID, func(entry fs.StreamedMetadataEntry) {
+		builder.Add(entry.ID, entry.DataChecksum)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tree := builder.Build()
+	s.merkleTrees.set(key, volumeIndex, tree)
+	return tree, nil
+}
+
+func (s *dbShard) MerkleTreeLeafEntries(
+	blockStart xtime.UnixNano,
+	depth int,
+	leaves []int,
+) ([]repair.MerkleTreeLeafEntry, error) {
+	if err := repair.ValidateMerkleTreeDepth(depth); err != nil {
+		return nil, err
+	}
+
+	fileset, ok, err := s.latestFileSetForBlock(blockStart)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	requested := make(map[int]struct{}, len(leaves))
+	for _, leaf := range leaves {
+		requested[leaf] = struct{}{}
+	}
+
+	var entries []repair.MerkleTreeLeafEntry
+	err = s.forEachFileSetMetadata(fileset.ID, func(entry fs.StreamedMetadataEntry) {
+		leaf := repair.MerkleTreeLeaf(entry.ID, depth)
+		if _, ok := requested[leaf]; !ok && len(requested) > 0 {
+			return
+		}
+		entries = append(entries, repair.MerkleTreeLeafEntry{
+			Leaf:     leaf,
+			ID:       ident.BytesID(append([]byte(nil), entry.ID...)),
+			Size:     int64(entry.Length),
+			Checksum: entry.DataChecksum,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (s *dbShard) latestFileSetForBlock(
+	blockStart xtime.UnixNano,
+) (fs.FileSetFile, bool, error) {
+	filePathPrefix := s.opts.CommitLogOptions().FilesystemOptions().FilePathPrefix()
+	filesets, err := s.filesetsFn(filePathPrefix, s.namespace.ID(), s.ID())
+	if err != nil {
+		return fs.FileSetFile{}, false, fmt.Errorf(
+			"encountered errors when getting fileset files for prefix %s namespace %s shard %d: %v",
+			filePathPrefix, s.namespace.ID(), s.ID(), err)
+	}
+	fileset, ok := filesets.LatestVolumeForBlock(blockStart)
+	return fileset, ok, nil
+}
+
+func (s *dbShard) forEachFileSetMetadata(
+	fileID fs.FileSetFileIdentifier,
+	fn func(entry fs.StreamedMetadataEntry),
+) error {
+	reader, err := s.newReaderFn(s.opts.BytesPool(), s.opts.CommitLogOptions().FilesystemOptions())
+	if err != nil {
+		return err
+	}
+	err = reader.Open(fs.DataReaderOpenOptions{
+		Identifier:       fileID,
+		FileSetType:      persist.FileSetFlushType,
+		StreamingEnabled: true,
+	})
+	if err != nil {
+		return err
+	}
+	defer reader.Close() // nolint: errcheck
+
+	for {
+		entry, err := reader.StreamingReadMetadata()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		fn(entry)
+	}
+}
@@ -23,8 +23,10 @@ package storage
 import (
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
+	"sort"
 	"strconv"
 	"sync"
 	"sync/atomic"
@@ -32,15 +34,18 @@ import (
 	"time"
 	"unsafe"
 
+	"github.com/m3db/m3/src/dbnode/digest"
 	"github.com/m3db/m3/src/dbnode/encoding"
 	"github.com/m3db/m3/src/dbnode/namespace"
 	"github.com/m3db/m3/src/dbnode/persist"
 	"github.com/m3db/m3/src/dbnode/persist/fs"
 	"github.com/m3db/m3/src/dbnode/retention"
 	"github.com/m3db/m3/src/dbnode/runtime"
+	"github.com/m3db/m3/src/dbnode/sharding"
 	"github.com/m3db/m3/src/dbnode/storage/block"
 	"github.com/m3db/m3/src/dbnode/storage/bootstrap/result"
 	"github.com/m3db/m3/src/dbnode/storage/index/convert"
+	"github.com/m3db/m3/src/dbnode/storage/repair"
 	"github.com/m3db/m3/src/dbnode/storage/series"
 	"github.com/m3db/m3/src/dbnode/ts"
 	xmetrics "github.com/m3db/m3/src/dbnode/x/metrics"
@@ -1584,6 +1589,325 @@ func TestShardCleanupExpiredFileSets(t *testing.T) {
 	require.Equal(t, []string{defaultTestNs1ID.String(), "0"}, deletedFiles)
 }
 
+func TestShardOffloadFileSets(t *testing.T) {
+	dir, err := ioutil.TempDir("", "testdir")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	secondaryDir, err := ioutil.TempDir("", "testdir-secondary")
+	require.NoError(t, err)
+	defer os.RemoveAll(secondaryDir)
+
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		opts   = DefaultTestOptions()
+		fsOpts = opts.CommitLogOptions().FilesystemOptions().
+			SetFilePathPrefix(dir).
+			SetSecondaryTierFilePathPrefix(secondaryDir)
+		leaseMgr = block.NewMockLeaseManager(ctrl)
+	)
+	leaseMgr.EXPECT().RegisterLeaser(gomock.Any()).Return(nil)
+	opts = opts.
+		SetCommitLogOptions(opts.CommitLogOptions().SetFilesystemOptions(fsOpts)).
+		SetBlockLeaseManager(leaseMgr)
+
+	s := testDatabaseShard(t, opts)
+	defer s.Close()
+
+	writer, err := fs.NewWriter(fsOpts)
+	require.NoError(t, err)
+
+	var (
+		blockSize = defaultTestRetentionOpts.BlockSize()
+		now       = xtime.Now().Truncate(blockSize)
+		oldStart  = now.Add(-4 * blockSize)
+		newStart  = now.Add(-blockSize)
+	)
+	for _, start := range []xtime.UnixNano{oldStart, newStart} {
+		require.NoError(t, writer.Open(fs.DataWriterOpenOptions{
+			FileSetType: persist.FileSetFlushType,
+			BlockSize:   blockSize,
+			Identifier: fs.FileSetFileIdentifier{
+				Namespace:  defaultTestNs1ID,
+				Shard:      s.ID(),
+				BlockStart: start,
+			},
+		}))
+		require.NoError(t, writer.Close())
+	}
+
+	ctx := context.NewBackground()
+	defer ctx.Close()
+	require.NoError(t, s.Bootstrap(ctx, namespace.Context{ID: defaultTestNs1ID}))
+
+	leaseMgr.EXPECT().
+		UpdateOpenLeases(gomock.Any(), block.LeaseState{Volume: 0}).
+		DoAndReturn(func(
+			descriptor block.LeaseDescriptor,
+			_ block.LeaseState,
+		) (block.UpdateLeasesResult, error) {
+			require.True(t, defaultTestNs1ID.Equal(descriptor.Namespace))
+			require.Equal(t, s.ID(), descriptor.Shard)
+			require.Equal(t, oldStart, descriptor.BlockStart)
+			return block.UpdateLeasesResult{}, nil
+		})
+
+	offloadBefore := now.Add(-2 * blockSize)
+	require.NoError(t, s.OffloadFileSets(offloadBefore))
+
+	// Offloading again is a no-op.
+	require.NoError(t, s.OffloadFileSets(offloadBefore))
+
+	filesets, err := fs.DataFiles(dir, defaultTestNs1ID, s.ID())
+	require.NoError(t, err)
+	require.Equal(t, 2, len(filesets))
+
+	offloaded, err := fs.SecondaryTierFilePaths(fsOpts, filesets.Filepaths())
+	require.NoError(t, err)
+	require.Equal(t, 2, len(offloaded))
+
+	// Both filesets remain readable, the old one from the secondary tier.
+	reader, err := fs.NewReader(nil, fsOpts)
+	require.NoError(t, err)
+	for _, start := range []xtime.UnixNano{oldStart, newStart} {
+		require.NoError(t, reader.Open(fs.DataReaderOpenOptions{
+			Identifier: fs.FileSetFileIdentifier{
+				Namespace:  defaultTestNs1ID,
+				Shard:      s.ID(),
+				BlockStart: start,
+			},
+			FileSetType: persist.FileSetFlushType,
+		}))
+		require.NoError(t, reader.Close())
+	}
+
+	// Cleaning up the expired old fileset deletes it from both tiers.
+	require.NoError(t, s.CleanupExpiredFileSets(newStart))
+	offloaded, err = fs.SecondaryTierFilePaths(fsOpts, filesets.Filepaths())
+	require.NoError(t, err)
+	require.Equal(t, 0, len(offloaded))
+}
+
+func TestShardSplitFileSetsInto(t *testing.T) {
+	dir, err := ioutil.TempDir("", "testdir")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	var (
+		opts   = DefaultTestOptions()
+		fsOpts = opts.CommitLogOptions().FilesystemOptions().
+			SetFilePathPrefix(dir)
+	)
+	opts = opts.SetCommitLogOptions(opts.CommitLogOptions().SetFilesystemOptions(fsOpts))
+
+	s := testDatabaseShard(t, opts)
+	defer s.Close()
+
+	writer, err := fs.NewWriter(fsOpts)
+	require.NoError(t, err)
+
+	var (
+		blockSize = defaultTestRetentionOpts.BlockSize()
+		start     = xtime.Now().Truncate(blockSize).Add(-blockSize)
+		childID   = s.ID() + 1
+		hashFn    = sharding.DefaultHashFn(2)
+		childIDs  []string
+	)
+	require.NoError(t, writer.Open(fs.DataWriterOpenOptions{
+		FileSetType: persist.FileSetFlushType,
+		BlockSize:   blockSize,
+		Identifier: fs.FileSetFileIdentifier{
+			Namespace:   defaultTestNs1ID,
+			Shard:       s.ID(),
+			BlockStart:  start,
+			VolumeIndex: 1,
+		},
+	}))
+	for i := 0; i < 20; i++ {
+		id := ident.StringID(fmt.Sprintf("foo.%d", i))
+		if hashFn(id) == childID {
+			childIDs = append(childIDs, id.String())
+		}
+		data := checked.NewBytes([]byte{byte(i)}, nil)
+		data.IncRef()
+		metadata := persist.NewMetadataFromIDAndTags(id, ident.Tags{},
+			persist.MetadataOptions{})
+		require.NoError(t, writer.Write(metadata, data, digest.Checksum(data.Bytes())))
+	}
+	require.NoError(t, writer.Close())
+	require.NotEmpty(t, childIDs)
+
+	require.NoError(t, s.SplitFileSetsInto(childID, hashFn))
+
+	// Splitting again is a no-op as the child fileset is complete.
+	require.NoError(t, s.SplitFileSetsInto(childID, hashFn))
+
+	filesets, err := fs.DataFiles(dir, defaultTestNs1ID, childID)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(filesets))
+	require.Equal(t, 1, filesets[0].ID.VolumeIndex)
+
+	reader, err := fs.NewReader(nil, fsOpts)
+	require.NoError(t, err)
+	require.NoError(t, reader.Open(fs.DataReaderOpenOptions{
+		Identifier:  filesets[0].ID,
+		FileSetType: persist.FileSetFlushType,
+	}))
+	defer reader.Close()
+
+	var splitIDs []string
+	for {
+		id, _, data, _, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		require.Equal(t, childID, hashFn(id))
+		splitIDs = append(splitIDs, id.String())
+		data.IncRef()
+		data.DecRef()
+		data.Finalize()
+	}
+	sort.Strings(childIDs)
+	require.Equal(t, childIDs, splitIDs)
+}
+
+func TestShardMerkleTree(t *testing.T) {
+	dir, err := ioutil.TempDir("", "testdir")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	var (
+		opts   = DefaultTestOptions()
+		fsOpts = opts.CommitLogOptions().FilesystemOptions().
+			SetFilePathPrefix(dir)
+	)
+	opts = opts.SetCommitLogOptions(opts.CommitLogOptions().SetFilesystemOptions(fsOpts))
+
+	s := testDatabaseShard(t, opts)
+	defer s.Close()
+
+	var (
+		depth     = 2
+		blockSize = defaultTestRetentionOpts.BlockSize()
+		start     = xtime.Now().Truncate(blockSize).Add(-blockSize)
+		builder   *repair.MerkleTreeBuilder
+	)
+	writeVolume := func(volumeIndex int, numSeries int) {
+		builder, err = repair.NewMerkleTreeBuilder(depth)
+		require.NoError(t, err)
+
+		writer, err := fs.NewWriter(fsOpts)
+		require.NoError(t, err)
+		require.NoError(t, writer.Open(fs.DataWriterOpenOptions{
+			FileSetType: persist.FileSetFlushType,
+			BlockSize:   blockSize,
+			Identifier: fs.FileSetFileIdentifier{
+				Namespace:   defaultTestNs1ID,
+				Shard:       s.ID(),
+				BlockStart:  start,
+				VolumeIndex: volumeIndex,
+			},
+		}))
+		for i := 0; i < numSeries; i++ {
+			id := ident.StringID(fmt.Sprintf("foo.%d", i))
+			data := checked.NewBytes([]byte{byte(i)}, nil)
+			data.IncRef()
+			checksum := digest.Checksum(data.Bytes())
+			builder.Add(id.Bytes(), checksum)
+			metadata := persist.NewMetadataFromIDAndTags(id, ident.Tags{},
+				persist.MetadataOptions{})
+			require.NoError(t, writer.Write(metadata, data, checksum))
+		}
+		require.NoError(t, writer.Close())
+	}
+
+	// Nothing flushed yet, the tree is empty.
+	empty, err := s.MerkleTree(start, depth)
+	require.NoError(t, err)
+	emptyBuilder, err := repair.NewMerkleTreeBuilder(depth)
+	require.NoError(t, err)
+	require.Equal(t, emptyBuilder.Build().Root(), empty.Root())
+
+	writeVolume(0, 20)
+	tree, err := s.MerkleTree(start, depth)
+	require.NoError(t, err)
+	require.Equal(t, builder.Build().Root(), tree.Root())
+
+	// Cached until a new volume is flushed.
+	cached, err := s.MerkleTree(start, depth)
+	require.NoError(t, err)
+	require.True(t, tree == cached)
+
+	writeVolume(1, 25)
+	tree, err = s.MerkleTree(start, depth)
+	require.NoError(t, err)
+	require.Equal(t, builder.Build().Root(), tree.Root())
+	require.False(t, tree == cached)
+
+	leaf := repair.MerkleTreeLeaf([]byte("foo.3"), depth)
+	entries, err := s.MerkleTreeLeafEntries(start, depth, []int{leaf})
+	require.NoError(t, err)
+	require.NotEmpty(t, entries)
+	found := false
+	for _, entry := range entries {
+		require.Equal(t, leaf, entry.Leaf)
+		if entry.ID.String() == "foo.3" {
+			found = true
+			require.Equal(t, int64(1), entry.Size)
+			require.Equal(t, digest.Checksum([]byte{3}), entry.Checksum)
+		}
+	}
+	require.True(t, found)
+
+	// All the entries are returned when no leaves are given.
+	entries, err = s.MerkleTreeLeafEntries(start, depth, nil)
+	require.NoError(t, err)
+	require.Equal(t, 25, len(entries))
+}
+
+func TestShardSplitSeriesInto(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	opts := DefaultTestOptions()
+	s := testDatabaseShard(t, opts)
+	defer s.Close()
+
+	ctx := context.NewBackground()
+	defer ctx.Close()
+
+	nowFn := opts.ClockOptions().NowFn()
+	for _, id := range []string{"foo", "bar"} {
+		_, err := s.Write(ctx, ident.StringID(id), xtime.ToUnixNano(nowFn()),
+			1.0, xtime.Second, nil, series.WriteOptions{})
+		require.NoError(t, err)
+	}
+
+	var (
+		childID = uint32(1)
+		hashFn  = func(id ident.ID) uint32 {
+			if id.String() == "bar" {
+				return childID
+			}
+			return 0
+		}
+		child = NewMockdatabaseShard(ctrl)
+	)
+	child.EXPECT().ID().Return(childID).AnyTimes()
+	child.EXPECT().LoadBlocks(gomock.Any()).DoAndReturn(func(seriesToLoad *result.Map) error {
+		require.Equal(t, 1, seriesToLoad.Len())
+		loaded, ok := seriesToLoad.Get(ident.StringID("bar"))
+		require.True(t, ok)
+		require.Equal(t, 1, loaded.Blocks.Len())
+		return nil
+	})
+
+	require.NoError(t, s.SplitSeriesInto(ctx, child, hashFn, namespace.Context{ID: defaultTestNs1ID}))
+}
+
 type testCloser struct {
 	called int
 }
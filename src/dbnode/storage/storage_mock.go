@@ -47,6 +47,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/storage/limits/permits"
 	"github.com/m3db/m3/src/dbnode/storage/repair"
 	"github.com/m3db/m3/src/dbnode/storage/series"
+	"github.com/m3db/m3/src/dbnode/ts"
 	"github.com/m3db/m3/src/dbnode/ts/writes"
 	"github.com/m3db/m3/src/dbnode/x/xio"
 	"github.com/m3db/m3/src/dbnode/x/xpool"
@@ -219,6 +220,21 @@ func (mr *MockDatabaseMockRecorder) BootstrapState() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BootstrapState", reflect.TypeOf((*MockDatabase)(nil).BootstrapState))
 }
 
+// Cardinality mocks base method.
+func (m *MockDatabase) Cardinality(ctx context.Context, namespace ident.ID, opts index.CardinalityOptions) (index.CardinalityResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cardinality", ctx, namespace, opts)
+	ret0, _ := ret[0].(index.CardinalityResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Cardinality indicates an expected call of Cardinality.
+func (mr *MockDatabaseMockRecorder) Cardinality(ctx, namespace, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cardinality", reflect.TypeOf((*MockDatabase)(nil).Cardinality), ctx, namespace, opts)
+}
+
 // Close mocks base method.
 func (m *MockDatabase) Close() error {
 	m.ctrl.T.Helper()
@@ -233,6 +249,21 @@ func (mr *MockDatabaseMockRecorder) Close() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockDatabase)(nil).Close))
 }
 
+// DeleteSeries mocks base method.
+func (m *MockDatabase) DeleteSeries(ctx context.Context, namespace ident.ID, query index.Query, start, end time0.UnixNano) (map[uint32]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSeries", ctx, namespace, query, start, end)
+	ret0, _ := ret[0].(map[uint32]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteSeries indicates an expected call of DeleteSeries.
+func (mr *MockDatabaseMockRecorder) DeleteSeries(ctx, namespace, query, start, end interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSeries", reflect.TypeOf((*MockDatabase)(nil).DeleteSeries), ctx, namespace, query, start, end)
+}
+
 // FetchBlocks mocks base method.
 func (m *MockDatabase) FetchBlocks(ctx context.Context, namespace ident.ID, shard uint32, id ident.ID, starts []time0.UnixNano) ([]block.FetchBlockResult, error) {
 	m.ctrl.T.Helper()
@@ -264,6 +295,21 @@ func (mr *MockDatabaseMockRecorder) FetchBlocksMetadataV2(ctx, namespace, shard,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchBlocksMetadataV2", reflect.TypeOf((*MockDatabase)(nil).FetchBlocksMetadataV2), ctx, namespace, shard, start, end, limit, pageToken, opts)
 }
 
+// FetchExemplars mocks base method.
+func (m *MockDatabase) FetchExemplars(ctx context.Context, namespace ident.ID, query index.Query, opts index.QueryOptions) (FetchExemplarsResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchExemplars", ctx, namespace, query, opts)
+	ret0, _ := ret[0].(FetchExemplarsResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchExemplars indicates an expected call of FetchExemplars.
+func (mr *MockDatabaseMockRecorder) FetchExemplars(ctx, namespace, query, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchExemplars", reflect.TypeOf((*MockDatabase)(nil).FetchExemplars), ctx, namespace, query, opts)
+}
+
 // FlushState mocks base method.
 func (m *MockDatabase) FlushState(namespace ident.ID, shardID uint32, blockStart time0.UnixNano) (fileOpState, error) {
 	m.ctrl.T.Helper()
@@ -321,6 +367,36 @@ func (mr *MockDatabaseMockRecorder) IsOverloaded() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsOverloaded", reflect.TypeOf((*MockDatabase)(nil).IsOverloaded))
 }
 
+// MerkleTree mocks base method.
+func (m *MockDatabase) MerkleTree(namespace ident.ID, shardID uint32, blockStart time0.UnixNano, depth int) (*repair.MerkleTree, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MerkleTree", namespace, shardID, blockStart, depth)
+	ret0, _ := ret[0].(*repair.MerkleTree)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MerkleTree indicates an expected call of MerkleTree.
+func (mr *MockDatabaseMockRecorder) MerkleTree(namespace, shardID, blockStart, depth interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MerkleTree", reflect.TypeOf((*MockDatabase)(nil).MerkleTree), namespace, shardID, blockStart, depth)
+}
+
+// MerkleTreeLeafEntries mocks base method.
+func (m *MockDatabase) MerkleTreeLeafEntries(namespace ident.ID, shardID uint32, blockStart time0.UnixNano, depth int, leaves []int) ([]repair.MerkleTreeLeafEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MerkleTreeLeafEntries", namespace, shardID, blockStart, depth, leaves)
+	ret0, _ := ret[0].([]repair.MerkleTreeLeafEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MerkleTreeLeafEntries indicates an expected call of MerkleTreeLeafEntries.
+func (mr *MockDatabaseMockRecorder) MerkleTreeLeafEntries(namespace, shardID, blockStart, depth, leaves interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MerkleTreeLeafEntries", reflect.TypeOf((*MockDatabase)(nil).MerkleTreeLeafEntries), namespace, shardID, blockStart, depth, leaves)
+}
+
 // Namespace mocks base method.
 func (m *MockDatabase) Namespace(ns ident.ID) (Namespace, bool) {
 	m.ctrl.T.Helper()
@@ -422,6 +498,20 @@ func (mr *MockDatabaseMockRecorder) Repair() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Repair", reflect.TypeOf((*MockDatabase)(nil).Repair))
 }
 
+// RepairStatus mocks base method.
+func (m *MockDatabase) RepairStatus() []repair.ShardStatus {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RepairStatus")
+	ret0, _ := ret[0].([]repair.ShardStatus)
+	return ret0
+}
+
+// RepairStatus indicates an expected call of RepairStatus.
+func (mr *MockDatabaseMockRecorder) RepairStatus() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RepairStatus", reflect.TypeOf((*MockDatabase)(nil).RepairStatus))
+}
+
 // ShardSet mocks base method.
 func (m *MockDatabase) ShardSet() sharding.ShardSet {
 	m.ctrl.T.Helper()
@@ -508,6 +598,20 @@ func (mr *MockDatabaseMockRecorder) WriteBatch(ctx, namespace, writes, errHandle
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteBatch", reflect.TypeOf((*MockDatabase)(nil).WriteBatch), ctx, namespace, writes, errHandler)
 }
 
+// WriteExemplars mocks base method.
+func (m *MockDatabase) WriteExemplars(namespace, id ident.ID, exemplars []ts.Exemplar) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WriteExemplars", namespace, id, exemplars)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WriteExemplars indicates an expected call of WriteExemplars.
+func (mr *MockDatabaseMockRecorder) WriteExemplars(namespace, id, exemplars interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteExemplars", reflect.TypeOf((*MockDatabase)(nil).WriteExemplars), namespace, id, exemplars)
+}
+
 // WriteTagged mocks base method.
 func (m *MockDatabase) WriteTagged(ctx context.Context, namespace, id ident.ID, tagResolver convert.TagMetadataResolver, timestamp time0.UnixNano, value float64, unit time0.Unit, annotation []byte) error {
 	m.ctrl.T.Helper()
@@ -658,6 +762,21 @@ func (mr *MockdatabaseMockRecorder) BootstrapState() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BootstrapState", reflect.TypeOf((*Mockdatabase)(nil).BootstrapState))
 }
 
+// Cardinality mocks base method.
+func (m *Mockdatabase) Cardinality(ctx context.Context, namespace ident.ID, opts index.CardinalityOptions) (index.CardinalityResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cardinality", ctx, namespace, opts)
+	ret0, _ := ret[0].(index.CardinalityResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Cardinality indicates an expected call of Cardinality.
+func (mr *MockdatabaseMockRecorder) Cardinality(ctx, namespace, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cardinality", reflect.TypeOf((*Mockdatabase)(nil).Cardinality), ctx, namespace, opts)
+}
+
 // Close mocks base method.
 func (m *Mockdatabase) Close() error {
 	m.ctrl.T.Helper()
@@ -672,6 +791,21 @@ func (mr *MockdatabaseMockRecorder) Close() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*Mockdatabase)(nil).Close))
 }
 
+// DeleteSeries mocks base method.
+func (m *Mockdatabase) DeleteSeries(ctx context.Context, namespace ident.ID, query index.Query, start, end time0.UnixNano) (map[uint32]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSeries", ctx, namespace, query, start, end)
+	ret0, _ := ret[0].(map[uint32]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteSeries indicates an expected call of DeleteSeries.
+func (mr *MockdatabaseMockRecorder) DeleteSeries(ctx, namespace, query, start, end interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSeries", reflect.TypeOf((*Mockdatabase)(nil).DeleteSeries), ctx, namespace, query, start, end)
+}
+
 // FetchBlocks mocks base method.
 func (m *Mockdatabase) FetchBlocks(ctx context.Context, namespace ident.ID, shard uint32, id ident.ID, starts []time0.UnixNano) ([]block.FetchBlockResult, error) {
 	m.ctrl.T.Helper()
@@ -703,6 +837,21 @@ func (mr *MockdatabaseMockRecorder) FetchBlocksMetadataV2(ctx, namespace, shard,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchBlocksMetadataV2", reflect.TypeOf((*Mockdatabase)(nil).FetchBlocksMetadataV2), ctx, namespace, shard, start, end, limit, pageToken, opts)
 }
 
+// FetchExemplars mocks base method.
+func (m *Mockdatabase) FetchExemplars(ctx context.Context, namespace ident.ID, query index.Query, opts index.QueryOptions) (FetchExemplarsResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchExemplars", ctx, namespace, query, opts)
+	ret0, _ := ret[0].(FetchExemplarsResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchExemplars indicates an expected call of FetchExemplars.
+func (mr *MockdatabaseMockRecorder) FetchExemplars(ctx, namespace, query, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchExemplars", reflect.TypeOf((*Mockdatabase)(nil).FetchExemplars), ctx, namespace, query, opts)
+}
+
 // FlushState mocks base method.
 func (m *Mockdatabase) FlushState(namespace ident.ID, shardID uint32, blockStart time0.UnixNano) (fileOpState, error) {
 	m.ctrl.T.Helper()
@@ -760,6 +909,36 @@ func (mr *MockdatabaseMockRecorder) IsOverloaded() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsOverloaded", reflect.TypeOf((*Mockdatabase)(nil).IsOverloaded))
 }
 
+// MerkleTree mocks base method.
+func (m *Mockdatabase) MerkleTree(namespace ident.ID, shardID uint32, blockStart time0.UnixNano, depth int) (*repair.MerkleTree, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MerkleTree", namespace, shardID, blockStart, depth)
+	ret0, _ := ret[0].(*repair.MerkleTree)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MerkleTree indicates an expected call of MerkleTree.
+func (mr *MockdatabaseMockRecorder) MerkleTree(namespace, shardID, blockStart, depth interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MerkleTree", reflect.TypeOf((*Mockdatabase)(nil).MerkleTree), namespace, shardID, blockStart, depth)
+}
+
+// MerkleTreeLeafEntries mocks base method.
+func (m *Mockdatabase) MerkleTreeLeafEntries(namespace ident.ID, shardID uint32, blockStart time0.UnixNano, depth int, leaves []int) ([]repair.MerkleTreeLeafEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MerkleTreeLeafEntries", namespace, shardID, blockStart, depth, leaves)
+	ret0, _ := ret[0].([]repair.MerkleTreeLeafEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MerkleTreeLeafEntries indicates an expected call of MerkleTreeLeafEntries.
+func (mr *MockdatabaseMockRecorder) MerkleTreeLeafEntries(namespace, shardID, blockStart, depth, leaves interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MerkleTreeLeafEntries", reflect.TypeOf((*Mockdatabase)(nil).MerkleTreeLeafEntries), namespace, shardID, blockStart, depth, leaves)
+}
+
 // Namespace mocks base method.
 func (m *Mockdatabase) Namespace(ns ident.ID) (Namespace, bool) {
 	m.ctrl.T.Helper()
@@ -876,6 +1055,20 @@ func (mr *MockdatabaseMockRecorder) Repair() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Repair", reflect.TypeOf((*Mockdatabase)(nil).Repair))
 }
 
+// RepairStatus mocks base method.
+func (m *Mockdatabase) RepairStatus() []repair.ShardStatus {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RepairStatus")
+	ret0, _ := ret[0].([]repair.ShardStatus)
+	return ret0
+}
+
+// RepairStatus indicates an expected call of RepairStatus.
+func (mr *MockdatabaseMockRecorder) RepairStatus() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RepairStatus", reflect.TypeOf((*Mockdatabase)(nil).RepairStatus))
+}
+
 // ShardSet mocks base method.
 func (m *Mockdatabase) ShardSet() sharding.ShardSet {
 	m.ctrl.T.Helper()
@@ -976,6 +1169,20 @@ func (mr *MockdatabaseMockRecorder) WriteBatch(ctx, namespace, writes, errHandle
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteBatch", reflect.TypeOf((*Mockdatabase)(nil).WriteBatch), ctx, namespace, writes, errHandler)
 }
 
+// WriteExemplars mocks base method.
+func (m *Mockdatabase) WriteExemplars(namespace, id ident.ID, exemplars []ts.Exemplar) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WriteExemplars", namespace, id, exemplars)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WriteExemplars indicates an expected call of WriteExemplars.
+func (mr *MockdatabaseMockRecorder) WriteExemplars(namespace, id, exemplars interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteExemplars", reflect.TypeOf((*Mockdatabase)(nil).WriteExemplars), namespace, id, exemplars)
+}
+
 // WriteTagged mocks base method.
 func (m *Mockdatabase) WriteTagged(ctx context.Context, namespace, id ident.ID, tagResolver convert.TagMetadataResolver, timestamp time0.UnixNano, value float64, unit time0.Unit, annotation []byte) error {
 	m.ctrl.T.Helper()
@@ -1334,6 +1541,21 @@ func (mr *MockdatabaseNamespaceMockRecorder) BootstrapState() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BootstrapState", reflect.TypeOf((*MockdatabaseNamespace)(nil).BootstrapState))
 }
 
+// Cardinality mocks base method.
+func (m *MockdatabaseNamespace) Cardinality(ctx context.Context, opts index.CardinalityOptions) (index.CardinalityResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cardinality", ctx, opts)
+	ret0, _ := ret[0].(index.CardinalityResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Cardinality indicates an expected call of Cardinality.
+func (mr *MockdatabaseNamespaceMockRecorder) Cardinality(ctx, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cardinality", reflect.TypeOf((*MockdatabaseNamespace)(nil).Cardinality), ctx, opts)
+}
+
 // Close mocks base method.
 func (m *MockdatabaseNamespace) Close() error {
 	m.ctrl.T.Helper()
@@ -1362,6 +1584,21 @@ func (mr *MockdatabaseNamespaceMockRecorder) ColdFlush(flush interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ColdFlush", reflect.TypeOf((*MockdatabaseNamespace)(nil).ColdFlush), flush)
 }
 
+// DeleteSeries mocks base method.
+func (m *MockdatabaseNamespace) DeleteSeries(ctx context.Context, query index.Query, start, end time0.UnixNano) (map[uint32]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSeries", ctx, query, start, end)
+	ret0, _ := ret[0].(map[uint32]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteSeries indicates an expected call of DeleteSeries.
+func (mr *MockdatabaseNamespaceMockRecorder) DeleteSeries(ctx, query, start, end interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSeries", reflect.TypeOf((*MockdatabaseNamespace)(nil).DeleteSeries), ctx, query, start, end)
+}
+
 // DocRef mocks base method.
 func (m *MockdatabaseNamespace) DocRef(id ident.ID) (doc.Metadata, bool, error) {
 	m.ctrl.T.Helper()
@@ -1409,6 +1646,21 @@ func (mr *MockdatabaseNamespaceMockRecorder) FetchBlocksMetadataV2(ctx, shardID,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchBlocksMetadataV2", reflect.TypeOf((*MockdatabaseNamespace)(nil).FetchBlocksMetadataV2), ctx, shardID, start, end, limit, pageToken, opts)
 }
 
+// FetchExemplars mocks base method.
+func (m *MockdatabaseNamespace) FetchExemplars(ctx context.Context, query index.Query, opts index.QueryOptions) (FetchExemplarsResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchExemplars", ctx, query, opts)
+	ret0, _ := ret[0].(FetchExemplarsResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchExemplars indicates an expected call of FetchExemplars.
+func (mr *MockdatabaseNamespaceMockRecorder) FetchExemplars(ctx, query, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchExemplars", reflect.TypeOf((*MockdatabaseNamespace)(nil).FetchExemplars), ctx, query, opts)
+}
+
 // FetchWideEntry mocks base method.
 func (m *MockdatabaseNamespace) FetchWideEntry(ctx context.Context, id ident.ID, blockStart time0.UnixNano, filter schema.WideEntryFilter) (block.StreamedWideEntry, error) {
 	m.ctrl.T.Helper()
@@ -1482,6 +1734,36 @@ func (mr *MockdatabaseNamespaceMockRecorder) Index() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Index", reflect.TypeOf((*MockdatabaseNamespace)(nil).Index))
 }
 
+// MerkleTree mocks base method.
+func (m *MockdatabaseNamespace) MerkleTree(shardID uint32, blockStart time0.UnixNano, depth int) (*repair.MerkleTree, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MerkleTree", shardID, blockStart, depth)
+	ret0, _ := ret[0].(*repair.MerkleTree)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MerkleTree indicates an expected call of MerkleTree.
+func (mr *MockdatabaseNamespaceMockRecorder) MerkleTree(shardID, blockStart, depth interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MerkleTree", reflect.TypeOf((*MockdatabaseNamespace)(nil).MerkleTree), shardID, blockStart, depth)
+}
+
+// MerkleTreeLeafEntries mocks base method.
+func (m *MockdatabaseNamespace) MerkleTreeLeafEntries(shardID uint32, blockStart time0.UnixNano, depth int, leaves []int) ([]repair.MerkleTreeLeafEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MerkleTreeLeafEntries", shardID, blockStart, depth, leaves)
+	ret0, _ := ret[0].([]repair.MerkleTreeLeafEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MerkleTreeLeafEntries indicates an expected call of MerkleTreeLeafEntries.
+func (mr *MockdatabaseNamespaceMockRecorder) MerkleTreeLeafEntries(shardID, blockStart, depth, leaves interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MerkleTreeLeafEntries", reflect.TypeOf((*MockdatabaseNamespace)(nil).MerkleTreeLeafEntries), shardID, blockStart, depth, leaves)
+}
+
 // Metadata mocks base method.
 func (m *MockdatabaseNamespace) Metadata() namespace.Metadata {
 	m.ctrl.T.Helper()
@@ -1826,6 +2108,20 @@ func (mr *MockdatabaseNamespaceMockRecorder) Write(ctx, id, timestamp, value, un
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Write", reflect.TypeOf((*MockdatabaseNamespace)(nil).Write), ctx, id, timestamp, value, unit, annotation)
 }
 
+// WriteExemplars mocks base method.
+func (m *MockdatabaseNamespace) WriteExemplars(id ident.ID, exemplars []ts.Exemplar) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WriteExemplars", id, exemplars)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WriteExemplars indicates an expected call of WriteExemplars.
+func (mr *MockdatabaseNamespaceMockRecorder) WriteExemplars(id, exemplars interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteExemplars", reflect.TypeOf((*MockdatabaseNamespace)(nil).WriteExemplars), id, exemplars)
+}
+
 // WritePendingIndexInserts mocks base method.
 func (m *MockdatabaseNamespace) WritePendingIndexInserts(pending []writes.PendingIndexInsert) error {
 	m.ctrl.T.Helper()
@@ -2088,6 +2384,20 @@ func (mr *MockdatabaseShardMockRecorder) ColdFlush(flush, resources, nsCtx, onFl
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ColdFlush", reflect.TypeOf((*MockdatabaseShard)(nil).ColdFlush), flush, resources, nsCtx, onFlush)
 }
 
+// DeleteSeries mocks base method.
+func (m *MockdatabaseShard) DeleteSeries(ids []ident.ID, start, end time0.UnixNano) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSeries", ids, start, end)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSeries indicates an expected call of DeleteSeries.
+func (mr *MockdatabaseShardMockRecorder) DeleteSeries(ids, start, end interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSeries", reflect.TypeOf((*MockdatabaseShard)(nil).DeleteSeries), ids, start, end)
+}
+
 // DocRef mocks base method.
 func (m *MockdatabaseShard) DocRef(id ident.ID) (doc.Metadata, bool, error) {
 	m.ctrl.T.Helper()
@@ -2150,6 +2460,20 @@ func (mr *MockdatabaseShardMockRecorder) FetchBlocksMetadataV2(ctx, start, end,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchBlocksMetadataV2", reflect.TypeOf((*MockdatabaseShard)(nil).FetchBlocksMetadataV2), ctx, start, end, limit, pageToken, opts)
 }
 
+// FetchExemplars mocks base method.
+func (m *MockdatabaseShard) FetchExemplars(id ident.ID, start, end time0.UnixNano) []ts.Exemplar {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchExemplars", id, start, end)
+	ret0, _ := ret[0].([]ts.Exemplar)
+	return ret0
+}
+
+// FetchExemplars indicates an expected call of FetchExemplars.
+func (mr *MockdatabaseShardMockRecorder) FetchExemplars(id, start, end interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchExemplars", reflect.TypeOf((*MockdatabaseShard)(nil).FetchExemplars), id, start, end)
+}
+
 // FetchWideEntry mocks base method.
 func (m *MockdatabaseShard) FetchWideEntry(ctx context.Context, id ident.ID, blockStart time0.UnixNano, filter schema.WideEntryFilter, nsCtx namespace.Context) (block.StreamedWideEntry, error) {
 	m.ctrl.T.Helper()
@@ -2180,6 +2504,48 @@ func (mr *MockdatabaseShardMockRecorder) FlushState(blockStart interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FlushState", reflect.TypeOf((*MockdatabaseShard)(nil).FlushState), blockStart)
 }
 
+// HasDeletedSeries mocks base method.
+func (m *MockdatabaseShard) HasDeletedSeries() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasDeletedSeries")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// HasDeletedSeries indicates an expected call of HasDeletedSeries.
+func (mr *MockdatabaseShardMockRecorder) HasDeletedSeries() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasDeletedSeries", reflect.TypeOf((*MockdatabaseShard)(nil).HasDeletedSeries))
+}
+
+// HasDeletedSeriesInRange mocks base method.
+func (m *MockdatabaseShard) HasDeletedSeriesInRange(start, end time0.UnixNano) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasDeletedSeriesInRange", start, end)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// HasDeletedSeriesInRange indicates an expected call of HasDeletedSeriesInRange.
+func (mr *MockdatabaseShardMockRecorder) HasDeletedSeriesInRange(start, end interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasDeletedSeriesInRange", reflect.TypeOf((*MockdatabaseShard)(nil).HasDeletedSeriesInRange), start, end)
+}
+
+// HasUnflushedDeletedSeries mocks base method.
+func (m *MockdatabaseShard) HasUnflushedDeletedSeries() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasUnflushedDeletedSeries")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// HasUnflushedDeletedSeries indicates an expected call of HasUnflushedDeletedSeries.
+func (mr *MockdatabaseShardMockRecorder) HasUnflushedDeletedSeries() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasUnflushedDeletedSeries", reflect.TypeOf((*MockdatabaseShard)(nil).HasUnflushedDeletedSeries))
+}
+
 // ID mocks base method.
 func (m *MockdatabaseShard) ID() uint32 {
 	m.ctrl.T.Helper()
@@ -2208,6 +2574,20 @@ func (mr *MockdatabaseShardMockRecorder) IsBootstrapped() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsBootstrapped", reflect.TypeOf((*MockdatabaseShard)(nil).IsBootstrapped))
 }
 
+// IsSeriesDeleted mocks base method.
+func (m *MockdatabaseShard) IsSeriesDeleted(id ident.ID, start, end time0.UnixNano) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsSeriesDeleted", id, start, end)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsSeriesDeleted indicates an expected call of IsSeriesDeleted.
+func (mr *MockdatabaseShardMockRecorder) IsSeriesDeleted(id, start, end interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsSeriesDeleted", reflect.TypeOf((*MockdatabaseShard)(nil).IsSeriesDeleted), id, start, end)
+}
+
 // LatestVolume mocks base method.
 func (m *MockdatabaseShard) LatestVolume(blockStart time0.UnixNano) (int, error) {
 	m.ctrl.T.Helper()
@@ -2249,6 +2629,36 @@ func (mr *MockdatabaseShardMockRecorder) MarkWarmIndexFlushStateSuccessOrError(b
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkWarmIndexFlushStateSuccessOrError", reflect.TypeOf((*MockdatabaseShard)(nil).MarkWarmIndexFlushStateSuccessOrError), blockStart, err)
 }
 
+// MerkleTree mocks base method.
+func (m *MockdatabaseShard) MerkleTree(blockStart time0.UnixNano, depth int) (*repair.MerkleTree, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MerkleTree", blockStart, depth)
+	ret0, _ := ret[0].(*repair.MerkleTree)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MerkleTree indicates an expected call of MerkleTree.
+func (mr *MockdatabaseShardMockRecorder) MerkleTree(blockStart, depth interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MerkleTree", reflect.TypeOf((*MockdatabaseShard)(nil).MerkleTree), blockStart, depth)
+}
+
+// MerkleTreeLeafEntries mocks base method.
+func (m *MockdatabaseShard) MerkleTreeLeafEntries(blockStart time0.UnixNano, depth int, leaves []int) ([]repair.MerkleTreeLeafEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MerkleTreeLeafEntries", blockStart, depth, leaves)
+	ret0, _ := ret[0].([]repair.MerkleTreeLeafEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MerkleTreeLeafEntries indicates an expected call of MerkleTreeLeafEntries.
+func (mr *MockdatabaseShardMockRecorder) MerkleTreeLeafEntries(blockStart, depth, leaves interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MerkleTreeLeafEntries", reflect.TypeOf((*MockdatabaseShard)(nil).MerkleTreeLeafEntries), blockStart, depth, leaves)
+}
+
 // NumSeries mocks base method.
 func (m *MockdatabaseShard) NumSeries() int64 {
 	m.ctrl.T.Helper()
@@ -2263,6 +2673,20 @@ func (mr *MockdatabaseShardMockRecorder) NumSeries() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NumSeries", reflect.TypeOf((*MockdatabaseShard)(nil).NumSeries))
 }
 
+// OffloadFileSets mocks base method.
+func (m *MockdatabaseShard) OffloadFileSets(offloadBefore time0.UnixNano) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OffloadFileSets", offloadBefore)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// OffloadFileSets indicates an expected call of OffloadFileSets.
+func (mr *MockdatabaseShardMockRecorder) OffloadFileSets(offloadBefore interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OffloadFileSets", reflect.TypeOf((*MockdatabaseShard)(nil).OffloadFileSets), offloadBefore)
+}
+
 // OnEvictedFromWiredList mocks base method.
 func (m *MockdatabaseShard) OnEvictedFromWiredList(id ident.ID, blockStart time0.UnixNano) {
 	m.ctrl.T.Helper()
@@ -2364,6 +2788,34 @@ func (mr *MockdatabaseShardMockRecorder) Snapshot(blockStart, snapshotStart, flu
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Snapshot", reflect.TypeOf((*MockdatabaseShard)(nil).Snapshot), blockStart, snapshotStart, flush, nsCtx)
 }
 
+// SplitFileSetsInto mocks base method.
+func (m *MockdatabaseShard) SplitFileSetsInto(childID uint32, hashFn sharding.HashFn) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SplitFileSetsInto", childID, hashFn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SplitFileSetsInto indicates an expected call of SplitFileSetsInto.
+func (mr *MockdatabaseShardMockRecorder) SplitFileSetsInto(childID, hashFn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SplitFileSetsInto", reflect.TypeOf((*MockdatabaseShard)(nil).SplitFileSetsInto), childID, hashFn)
+}
+
+// SplitSeriesInto mocks base method.
+func (m *MockdatabaseShard) SplitSeriesInto(ctx context.Context, child databaseShard, hashFn sharding.HashFn, nsCtx namespace.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SplitSeriesInto", ctx, child, hashFn, nsCtx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SplitSeriesInto indicates an expected call of SplitSeriesInto.
+func (mr *MockdatabaseShardMockRecorder) SplitSeriesInto(ctx, child, hashFn, nsCtx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SplitSeriesInto", reflect.TypeOf((*MockdatabaseShard)(nil).SplitSeriesInto), ctx, child, hashFn, nsCtx)
+}
+
 // Tick mocks base method.
 func (m *MockdatabaseShard) Tick(c context.Cancellable, startTime time0.UnixNano, nsCtx namespace.Context) (tickResult, error) {
 	m.ctrl.T.Helper()
@@ -2436,6 +2888,18 @@ func (mr *MockdatabaseShardMockRecorder) Write(ctx, id, timestamp, value, unit,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Write", reflect.TypeOf((*MockdatabaseShard)(nil).Write), ctx, id, timestamp, value, unit, annotation, wOpts)
 }
 
+// WriteExemplars mocks base method.
+func (m *MockdatabaseShard) WriteExemplars(id ident.ID, exemplars []ts.Exemplar) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "WriteExemplars", id, exemplars)
+}
+
+// WriteExemplars indicates an expected call of WriteExemplars.
+func (mr *MockdatabaseShardMockRecorder) WriteExemplars(id, exemplars interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteExemplars", reflect.TypeOf((*MockdatabaseShard)(nil).WriteExemplars), id, exemplars)
+}
+
 // WriteTagged mocks base method.
 func (m *MockdatabaseShard) WriteTagged(ctx context.Context, id ident.ID, tagResolver convert.TagMetadataResolver, timestamp time0.UnixNano, value float64, unit time0.Unit, annotation []byte, wOpts series.WriteOptions) (SeriesWrite, error) {
 	m.ctrl.T.Helper()
@@ -2678,6 +3142,20 @@ func (mr *MockNamespaceIndexMockRecorder) ColdFlush(shards interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ColdFlush", reflect.TypeOf((*MockNamespaceIndex)(nil).ColdFlush), shards)
 }
 
+// CompactPersistedFileSets mocks base method.
+func (m *MockNamespaceIndex) CompactPersistedFileSets(flush persist.IndexFlush, shards []databaseShard) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompactPersistedFileSets", flush, shards)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompactPersistedFileSets indicates an expected call of CompactPersistedFileSets.
+func (mr *MockNamespaceIndexMockRecorder) CompactPersistedFileSets(flush, shards interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompactPersistedFileSets", reflect.TypeOf((*MockNamespaceIndex)(nil).CompactPersistedFileSets), flush, shards)
+}
+
 // DebugMemorySegments mocks base method.
 func (m *MockNamespaceIndex) DebugMemorySegments(opts DebugMemorySegmentsOptions) error {
 	m.ctrl.T.Helper()
@@ -2692,6 +3170,20 @@ func (mr *MockNamespaceIndexMockRecorder) DebugMemorySegments(opts interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DebugMemorySegments", reflect.TypeOf((*MockNamespaceIndex)(nil).DebugMemorySegments), opts)
 }
 
+// LabelCardinality mocks base method.
+func (m *MockNamespaceIndex) LabelCardinality() []limits.LabelCardinality {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LabelCardinality")
+	ret0, _ := ret[0].([]limits.LabelCardinality)
+	return ret0
+}
+
+// LabelCardinality indicates an expected call of LabelCardinality.
+func (mr *MockNamespaceIndexMockRecorder) LabelCardinality() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LabelCardinality", reflect.TypeOf((*MockNamespaceIndex)(nil).LabelCardinality))
+}
+
 // Query mocks base method.
 func (m *MockNamespaceIndex) Query(ctx context.Context, query index.Query, opts index.QueryOptions) (index.QueryResult, error) {
 	m.ctrl.T.Helper()
@@ -3384,6 +3876,20 @@ func (mr *MockdatabaseShardRepairerMockRecorder) Repair(ctx, nsCtx, nsMeta, tr,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Repair", reflect.TypeOf((*MockdatabaseShardRepairer)(nil).Repair), ctx, nsCtx, nsMeta, tr, shard)
 }
 
+// Status mocks base method.
+func (m *MockdatabaseShardRepairer) Status() []repair.ShardStatus {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Status")
+	ret0, _ := ret[0].([]repair.ShardStatus)
+	return ret0
+}
+
+// Status indicates an expected call of Status.
+func (mr *MockdatabaseShardRepairerMockRecorder) Status() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Status", reflect.TypeOf((*MockdatabaseShardRepairer)(nil).Status))
+}
+
 // MockBackgroundProcess is a mock of BackgroundProcess interface.
 type MockBackgroundProcess struct {
 	ctrl     *gomock.Controller
@@ -3539,6 +4045,20 @@ func (mr *MockdatabaseRepairerMockRecorder) Start() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockdatabaseRepairer)(nil).Start))
 }
 
+// Status mocks base method.
+func (m *MockdatabaseRepairer) Status() []repair.ShardStatus {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Status")
+	ret0, _ := ret[0].([]repair.ShardStatus)
+	return ret0
+}
+
+// Status indicates an expected call of Status.
+func (mr *MockdatabaseRepairerMockRecorder) Status() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Status", reflect.TypeOf((*MockdatabaseRepairer)(nil).Status))
+}
+
 // Stop mocks base method.
 func (m *MockdatabaseRepairer) Stop() {
 	m.ctrl.T.Helper()
@@ -4233,6 +4753,20 @@ func (mr *MockOptionsMockRecorder) ErrorWindowForLoad() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ErrorWindowForLoad", reflect.TypeOf((*MockOptions)(nil).ErrorWindowForLoad))
 }
 
+// ExemplarsOptions mocks base method.
+func (m *MockOptions) ExemplarsOptions() ExemplarsOptions {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExemplarsOptions")
+	ret0, _ := ret[0].(ExemplarsOptions)
+	return ret0
+}
+
+// ExemplarsOptions indicates an expected call of ExemplarsOptions.
+func (mr *MockOptionsMockRecorder) ExemplarsOptions() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExemplarsOptions", reflect.TypeOf((*MockOptions)(nil).ExemplarsOptions))
+}
+
 // FetchBlockMetadataResultsPool mocks base method.
 func (m *MockOptions) FetchBlockMetadataResultsPool() block.FetchBlockMetadataResultsPool {
 	m.ctrl.T.Helper()
@@ -4891,6 +5425,20 @@ func (mr *MockOptionsMockRecorder) SetErrorWindowForLoad(value interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetErrorWindowForLoad", reflect.TypeOf((*MockOptions)(nil).SetErrorWindowForLoad), value)
 }
 
+// SetExemplarsOptions mocks base method.
+func (m *MockOptions) SetExemplarsOptions(value ExemplarsOptions) Options {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetExemplarsOptions", value)
+	ret0, _ := ret[0].(Options)
+	return ret0
+}
+
+// SetExemplarsOptions indicates an expected call of SetExemplarsOptions.
+func (mr *MockOptionsMockRecorder) SetExemplarsOptions(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetExemplarsOptions", reflect.TypeOf((*MockOptions)(nil).SetExemplarsOptions), value)
+}
+
 // SetFetchBlockMetadataResultsPool mocks base method.
 func (m *MockOptions) SetFetchBlockMetadataResultsPool(value block.FetchBlockMetadataResultsPool) Options {
 	m.ctrl.T.Helper()
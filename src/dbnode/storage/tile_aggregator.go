@@ -0,0 +1,481 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/storage/index/convert"
+	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/dbnode/x/xio"
+	"github.com/m3db/m3/src/m3ninx/doc"
+	"github.com/m3db/m3/src/x/checked"
+	"github.com/m3db/m3/src/x/context"
+	"github.com/m3db/m3/src/x/ident"
+	"github.com/m3db/m3/src/x/instrument"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"go.uber.org/zap"
+)
+
+type tileStreamKind uint
+
+const (
+	// tileSourceStream reads a block of the source namespace.
+	tileSourceStream tileStreamKind = iota
+	// tileTargetStream reads the latest volume of the target block, which
+	// the aggregated volume replaces.
+	tileTargetStream
+	// tilePreviousStream reads the target block preceding the aggregated
+	// one, only to continue the counters of its series.
+	tilePreviousStream
+)
+
+// tileStream is a streaming reader of a fileset positioned at its current
+// entry, entries are in increasing order of their IDs.
+type tileStream struct {
+	kind   tileStreamKind
+	reader fs.DataFileSetReader
+	entry  fs.StreamedDataEntry
+	done   bool
+}
+
+func (s *tileStream) next() error {
+	entry, err := s.reader.StreamingRead()
+	if errors.Is(err, io.EOF) {
+		s.done = true
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	s.entry = entry
+	return nil
+}
+
+type tileAggregator struct {
+	logger *zap.Logger
+}
+
+// NewTileAggregator creates a TileAggregator which downsamples the series of
+// the source namespace into tiles according to their metric type.
+func NewTileAggregator(iOpts instrument.Options) TileAggregator {
+	return &tileAggregator{
+		logger: iOpts.Logger(),
+	}
+}
+
+func (a *tileAggregator) AggregateTiles(
+	ctx context.Context,
+	sourceNs, targetNs Namespace,
+	shardID uint32,
+	onFlushSeries persist.OnFlushSeries,
+	opts AggregateTilesOptions,
+) (int64, int, error) {
+	if opts.Step <= 0 {
+		return 0, 0, fmt.Errorf("tile aggregation step must be positive, got %s", opts.Step)
+	}
+
+	sourceShard, _, err := sourceNs.ReadableShardAt(shardID)
+	if err != nil {
+		return 0, 0, err
+	}
+	targetShard, _, err := targetNs.ReadableShardAt(shardID)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var (
+		storageOpts      = targetNs.StorageOptions()
+		fsOpts           = storageOpts.CommitLogOptions().FilesystemOptions()
+		sourceBlockSize  = sourceNs.Options().RetentionOptions().BlockSize()
+		targetBlockSize  = targetNs.Options().RetentionOptions().BlockSize()
+		targetBlockStart = opts.Start.Truncate(targetBlockSize)
+		streams          []*tileStream
+	)
+	defer func() {
+		for _, stream := range streams {
+			stream.reader.Close() // nolint: errcheck
+		}
+	}()
+
+	openStream := func(
+		kind tileStreamKind,
+		ns Namespace,
+		shard databaseShard,
+		blockStart xtime.UnixNano,
+	) (bool, error) {
+		stream, err := openTileStream(kind, fsOpts, ns.ID(), shard, blockStart)
+		if err != nil || stream == nil {
+			return false, err
+		}
+		streams = append(streams, stream)
+		return true, nil
+	}
+
+	for blockStart := opts.Start.Truncate(sourceBlockSize); blockStart.Before(opts.End); blockStart = blockStart.Add(sourceBlockSize) {
+		if _, err := openStream(tileSourceStream, sourceNs, sourceShard, blockStart); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	latestVolume, err := targetShard.LatestVolume(targetBlockStart)
+	if err != nil {
+		return 0, 0, err
+	}
+	nextVolume := latestVolume
+	targetExists, err := openStream(tileTargetStream, targetNs, targetShard, targetBlockStart)
+	if err != nil {
+		return 0, 0, err
+	}
+	if targetExists {
+		nextVolume = latestVolume + 1
+	}
+	if opts.Start == targetBlockStart {
+		// NB: counters continue from the tiles of the previous block when the
+		// aggregation starts at the beginning of the target block.
+		previousBlockStart := targetBlockStart.Add(-targetBlockSize)
+		if _, err := openStream(tilePreviousStream, targetNs, targetShard, previousBlockStart); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	var plannedRecordsCount uint
+	for _, stream := range streams {
+		if stream.kind != tilePreviousStream {
+			plannedRecordsCount += uint(stream.reader.Entries())
+		}
+	}
+	if plannedRecordsCount == 0 {
+		plannedRecordsCount = 1
+	}
+
+	writer, err := fs.NewStreamingWriter(fsOpts)
+	if err != nil {
+		return 0, 0, err
+	}
+	err = writer.Open(fs.StreamingWriterOpenOptions{
+		NamespaceID:         targetNs.ID(),
+		ShardID:             shardID,
+		BlockStart:          targetBlockStart,
+		BlockSize:           targetBlockSize,
+		VolumeIndex:         nextVolume,
+		Compression:         targetNs.Options().FileSetCompression(),
+		PlannedRecordsCount: plannedRecordsCount,
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	shardAggregator := &shardTileAggregator{
+		opts:             opts,
+		storageOpts:      storageOpts,
+		schema:           targetNs.Schema(),
+		shardID:          shardID,
+		targetBlockStart: targetBlockStart,
+		writer:           writer,
+		onFlushSeries:    onFlushSeries,
+	}
+	processedTileCount, err := shardAggregator.aggregate(streams)
+	if err != nil {
+		_ = writer.Abort()
+		return 0, 0, err
+	}
+	if err := writer.Close(); err != nil {
+		return 0, 0, err
+	}
+	if err := onFlushSeries.CheckpointAndMaybeCompact(); err != nil {
+		return 0, 0, err
+	}
+
+	a.logger.Debug("aggregated tiles of shard",
+		zap.Uint32("shard", shardID),
+		zap.Time("targetBlockStart", targetBlockStart.ToTime()),
+		zap.Int("volume", nextVolume),
+		zap.Int64("processedTiles", processedTileCount))
+
+	return processedTileCount, nextVolume, nil
+}
+
+// openTileStream opens a stream over the latest volume of the block of the
+// shard, nil is returned if the block has no fileset.
+func openTileStream(
+	kind tileStreamKind,
+	fsOpts fs.Options,
+	nsID ident.ID,
+	shard databaseShard,
+	blockStart xtime.UnixNano,
+) (*tileStream, error) {
+	volume, err := shard.LatestVolume(blockStart)
+	if err != nil {
+		return nil, err
+	}
+	exists, err := fs.DataFileSetExists(
+		fsOpts.FilePathPrefix(), nsID, shard.ID(), blockStart, volume)
+	if err != nil || !exists {
+		return nil, err
+	}
+
+	reader, err := shard.OpenStreamingReader(blockStart)
+	if err != nil {
+		return nil, err
+	}
+	stream := &tileStream{kind: kind, reader: reader}
+	if err := stream.next(); err != nil {
+		reader.Close() // nolint: errcheck
+		return nil, err
+	}
+	return stream, nil
+}
+
+// tileSeries is the data of a series across the streams of a shard.
+type tileSeries struct {
+	id          ident.BytesID
+	encodedTags ts.EncodedTags
+	source      [][]byte
+	target      *fs.StreamedDataEntry
+	previous    []byte
+}
+
+type shardTileAggregator struct {
+	opts             AggregateTilesOptions
+	storageOpts      Options
+	schema           namespace.SchemaDescr
+	shardID          uint32
+	targetBlockStart xtime.UnixNano
+	writer           fs.StreamingWriter
+	onFlushSeries    persist.OnFlushSeries
+}
+
+// aggregate merges the streams by series ID and writes the aggregated
+// series in ID order.
+func (a *shardTileAggregator) aggregate(streams []*tileStream) (int64, error) {
+	var (
+		processedTileCount int64
+		holding            []*tileStream
+	)
+	for {
+		var minID ident.BytesID
+		for _, stream := range streams {
+			if stream.done {
+				continue
+			}
+			if minID == nil || bytes.Compare(stream.entry.ID, minID) < 0 {
+				minID = stream.entry.ID
+			}
+		}
+		if minID == nil {
+			return processedTileCount, nil
+		}
+
+		series := tileSeries{id: minID}
+		holding = holding[:0]
+		for _, stream := range streams {
+			if stream.done || !bytes.Equal(stream.entry.ID, minID) {
+				continue
+			}
+			holding = append(holding, stream)
+			series.encodedTags = stream.entry.EncodedTags
+			switch stream.kind {
+			case tileSourceStream:
+				series.source = append(series.source, stream.entry.Data)
+			case tileTargetStream:
+				entry := stream.entry
+				series.target = &entry
+			case tilePreviousStream:
+				series.previous = stream.entry.Data
+			}
+		}
+
+		count, err := a.aggregateSeries(series)
+		if err != nil {
+			return 0, fmt.Errorf("failed to aggregate tiles of series %s: %w", minID.String(), err)
+		}
+		processedTileCount += count
+
+		// NB: advancing a stream invalidates its entry, including the series
+		// ID, so the streams holding the series are advanced once it is written.
+		for _, stream := range holding {
+			if err := stream.next(); err != nil {
+				return 0, err
+			}
+		}
+	}
+}
+
+func (a *shardTileAggregator) aggregateSeries(series tileSeries) (int64, error) {
+	if len(series.source) == 0 && series.target == nil {
+		// Only held by the previous block.
+		return 0, nil
+	}
+
+	var samples []tileDatapoint
+	for _, data := range series.source {
+		decoded, err := a.decode(data, nil)
+		if err != nil {
+			return 0, err
+		}
+		for _, dp := range decoded {
+			if !dp.TimestampNanos.Before(a.opts.Start) && dp.TimestampNanos.Before(a.opts.End) {
+				samples = append(samples, dp)
+			}
+		}
+	}
+
+	metadata, err := convert.FromSeriesIDAndEncodedTags(series.id, series.encodedTags)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		existing []tileDatapoint
+		prev     *tileDatapoint
+	)
+	if series.target != nil {
+		if existing, err = a.decode(series.target.Data, nil); err != nil {
+			return 0, err
+		}
+		for i := range existing {
+			if !existing[i].TimestampNanos.Before(a.opts.Start) {
+				break
+			}
+			prev = &existing[i]
+		}
+	}
+	if prev == nil && len(series.previous) > 0 {
+		previous, err := a.decode(series.previous, nil)
+		if err != nil {
+			return 0, err
+		}
+		if len(previous) > 0 {
+			prev = &previous[len(previous)-1]
+		}
+	}
+
+	payload := seriesTilePayload(metadata, samples, a.opts)
+	tiles, err := aggregateSeriesTiles(samples, payload, prev, a.opts.Step, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(tiles) == 0 {
+		if series.target == nil {
+			return 0, nil
+		}
+		// NB: the existing datapoints of the series are kept as is when the
+		// series has no samples in the aggregated range.
+		err := a.writer.WriteAll(series.id, series.encodedTags,
+			[][]byte{series.target.Data}, series.target.DataChecksum)
+		if err != nil {
+			return 0, err
+		}
+		return 0, a.onFlushNewSeries(metadata)
+	}
+
+	// The tiles replace the existing datapoints in the aggregated range.
+	merged := make([]tileDatapoint, 0, len(existing)+len(tiles))
+	for _, dp := range existing {
+		if dp.TimestampNanos.Before(a.opts.Start) {
+			merged = append(merged, dp)
+		}
+	}
+	merged = append(merged, tiles...)
+	for _, dp := range existing {
+		if !dp.TimestampNanos.Before(a.opts.End) {
+			merged = append(merged, dp)
+		}
+	}
+
+	if err := a.write(series, merged); err != nil {
+		return 0, err
+	}
+	return int64(len(tiles)), a.onFlushNewSeries(metadata)
+}
+
+// decode appends the datapoints of the encoded data to dst.
+func (a *shardTileAggregator) decode(data []byte, dst []tileDatapoint) ([]tileDatapoint, error) {
+	iter := a.storageOpts.ReaderIteratorPool().Get()
+	defer iter.Close()
+
+	segment := ts.NewSegment(checked.NewBytes(data, nil), nil, 0, ts.FinalizeNone)
+	iter.Reset(xio.NewSegmentReader(segment), a.schema)
+
+	var annot ts.Annotation
+	for iter.Next() {
+		dp, unit, currAnnot := iter.Current()
+		if len(currAnnot) > 0 {
+			annot = append(ts.Annotation(nil), currAnnot...)
+		}
+		dst = append(dst, tileDatapoint{Datapoint: dp, unit: unit, annotation: annot})
+	}
+	return dst, iter.Err()
+}
+
+func (a *shardTileAggregator) write(series tileSeries, dps []tileDatapoint) error {
+	encoder := a.storageOpts.EncoderPool().Get()
+	encoder.Reset(a.targetBlockStart, len(dps), a.schema)
+	if err := encodeTileDatapoints(encoder, dps); err != nil {
+		encoder.Close()
+		return err
+	}
+
+	segment := encoder.Discard()
+	defer segment.Finalize()
+
+	data := make([][]byte, 0, 2)
+	if segment.Head != nil {
+		data = append(data, segment.Head.Bytes())
+	}
+	if segment.Tail != nil {
+		data = append(data, segment.Tail.Bytes())
+	}
+	return a.writer.WriteAll(series.id, series.encodedTags, data, segment.CalculateChecksum())
+}
+
+func encodeTileDatapoints(encoder encoding.Encoder, dps []tileDatapoint) error {
+	for _, dp := range dps {
+		if err := encoder.Encode(dp.Datapoint, dp.unit, dp.annotation); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *shardTileAggregator) onFlushNewSeries(metadata doc.Metadata) error {
+	return a.onFlushSeries.OnFlushNewSeries(persist.OnFlushNewSeriesEvent{
+		Shard:      a.shardID,
+		BlockStart: a.targetBlockStart,
+		FirstWrite: a.opts.Start,
+		SeriesMetadata: persist.SeriesMetadata{
+			Type:     persist.SeriesDocumentType,
+			Document: metadata,
+			// The lifetime of the shard series metadata is longly lived.
+			LifeTime: persist.SeriesLifeTimeLong,
+		},
+	})
+}
@@ -0,0 +1,298 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/generated/proto/annotation"
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/retention"
+	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/dbnode/x/xio"
+	"github.com/m3db/m3/src/m3ninx/doc"
+	"github.com/m3db/m3/src/metrics/metric"
+	"github.com/m3db/m3/src/x/checked"
+	"github.com/m3db/m3/src/x/context"
+	"github.com/m3db/m3/src/x/ident"
+	"github.com/m3db/m3/src/x/instrument"
+	xtest "github.com/m3db/m3/src/x/test"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testTileFileSetSeries struct {
+	id     string
+	fields doc.Fields
+	dps    []tileDatapoint
+}
+
+func writeTestTileFileSet(
+	t *testing.T,
+	opts Options,
+	nsID ident.ID,
+	blockStart xtime.UnixNano,
+	blockSize time.Duration,
+	series []testTileFileSetSeries,
+) {
+	writer, err := fs.NewWriter(opts.CommitLogOptions().FilesystemOptions())
+	require.NoError(t, err)
+	require.NoError(t, writer.Open(fs.DataWriterOpenOptions{
+		FileSetType: persist.FileSetFlushType,
+		Identifier: fs.FileSetFileIdentifier{
+			Namespace:  nsID,
+			BlockStart: blockStart,
+		},
+		BlockSize: blockSize,
+	}))
+
+	for _, s := range series {
+		encoder := opts.EncoderPool().Get()
+		encoder.Reset(blockStart, 0, nil)
+		require.NoError(t, encodeTileDatapoints(encoder, s.dps))
+		segment := encoder.Discard()
+
+		metadata := persist.NewMetadata(doc.Metadata{ID: []byte(s.id), Fields: s.fields})
+		require.NoError(t, writer.WriteAll(metadata,
+			[]checked.Bytes{segment.Head, segment.Tail}, segment.CalculateChecksum()))
+	}
+	require.NoError(t, writer.Close())
+}
+
+func readTestTileFileSet(
+	t *testing.T,
+	opts Options,
+	nsID ident.ID,
+	blockStart xtime.UnixNano,
+	volume int,
+) map[string][]tileDatapoint {
+	reader, err := fs.NewReader(opts.BytesPool(), opts.CommitLogOptions().FilesystemOptions())
+	require.NoError(t, err)
+	require.NoError(t, reader.Open(fs.DataReaderOpenOptions{
+		Identifier: fs.FileSetFileIdentifier{
+			Namespace:   nsID,
+			BlockStart:  blockStart,
+			VolumeIndex: volume,
+		},
+		FileSetType: persist.FileSetFlushType,
+	}))
+	defer reader.Close()
+
+	results := make(map[string][]tileDatapoint)
+	for {
+		id, tags, data, _, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		tags.Close()
+
+		iter := opts.ReaderIteratorPool().Get()
+		iter.Reset(xio.NewSegmentReader(ts.NewSegment(data, nil, 0, ts.FinalizeNone)), nil)
+		var dps []tileDatapoint
+		for iter.Next() {
+			dp, unit, annot := iter.Current()
+			dps = append(dps, tileDatapoint{
+				Datapoint:  dp,
+				unit:       unit,
+				annotation: append(ts.Annotation(nil), annot...),
+			})
+		}
+		require.NoError(t, iter.Err())
+		iter.Close()
+
+		results[id.String()] = dps
+	}
+	return results
+}
+
+func TestTileAggregatorAggregateTiles(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	dir, err := ioutil.TempDir("", "testdir")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	ctx := context.NewBackground()
+	defer ctx.Close()
+
+	var (
+		testOpts = DefaultTestOptions()
+		fsOpts   = testOpts.CommitLogOptions().FilesystemOptions().SetFilePathPrefix(dir)
+		opts     = testOpts.SetCommitLogOptions(
+			testOpts.CommitLogOptions().SetFilesystemOptions(fsOpts))
+
+		sourceNsID      = ident.StringID("source")
+		targetNsID      = ident.StringID("target")
+		sourceBlockSize = time.Hour
+		targetBlockSize = 4 * time.Hour
+		start           = xtime.Now().Truncate(targetBlockSize)
+		counter         = annotation.Payload{
+			MetricType:        annotation.MetricType_COUNTER,
+			HandleValueResets: true,
+		}
+		gaugeFields = doc.Fields{
+			{Name: tileMetricNameTag, Value: []byte("temperature")},
+			{Name: metric.M3TypeTag, Value: metric.M3GaugeValue},
+		}
+	)
+
+	sample := func(offset time.Duration, value float64, annot ts.Annotation) tileDatapoint {
+		return tileDatapoint{
+			Datapoint:  ts.Datapoint{TimestampNanos: start.Add(offset), Value: value},
+			unit:       xtime.Second,
+			annotation: annot,
+		}
+	}
+
+	// The previous tile of the counter has a reset offset to continue from.
+	writeTestTileFileSet(t, opts, targetNsID, start.Add(-targetBlockSize), targetBlockSize,
+		[]testTileFileSetSeries{{id: "counter", dps: []tileDatapoint{
+			sample(-time.Minute, 100, testTileAnnotation(t, annotation.Payload{
+				MetricType:        counter.MetricType,
+				HandleValueResets: true,
+				Tile:              &annotation.Tile{ResetOffset: 40},
+			})),
+		}}})
+	writeTestTileFileSet(t, opts, targetNsID, start, targetBlockSize,
+		[]testTileFileSetSeries{
+			{id: "existing", dps: []tileDatapoint{sample(3*time.Hour, 7, nil)}},
+			{id: "gauge", fields: gaugeFields, dps: []tileDatapoint{sample(3*time.Hour, 9, nil)}},
+		})
+	writeTestTileFileSet(t, opts, sourceNsID, start, sourceBlockSize,
+		[]testTileFileSetSeries{
+			{id: "counter", dps: []tileDatapoint{
+				sample(0, 70, testTileAnnotation(t, counter)),
+				sample(20*time.Minute, 80, testTileAnnotation(t, counter)),
+			}},
+			{id: "gauge", fields: gaugeFields, dps: []tileDatapoint{
+				sample(5*time.Minute, 1, nil),
+				sample(10*time.Minute, 3, nil),
+			}},
+		})
+	writeTestTileFileSet(t, opts, sourceNsID, start.Add(sourceBlockSize), sourceBlockSize,
+		[]testTileFileSetSeries{
+			{id: "counter", dps: []tileDatapoint{sample(70*time.Minute, 5, testTileAnnotation(t, counter))}},
+			{id: "gauge", fields: gaugeFields, dps: []tileDatapoint{sample(65*time.Minute, 2, nil)}},
+		})
+
+	newTestShard := func(nsID ident.ID) *MockdatabaseShard {
+		shard := NewMockdatabaseShard(ctrl)
+		shard.EXPECT().ID().Return(uint32(0)).AnyTimes()
+		shard.EXPECT().LatestVolume(gomock.Any()).Return(0, nil).AnyTimes()
+		shard.EXPECT().OpenStreamingReader(gomock.Any()).DoAndReturn(
+			func(blockStart xtime.UnixNano) (fs.DataFileSetReader, error) {
+				reader, err := fs.NewReader(opts.BytesPool(), fsOpts)
+				if err != nil {
+					return nil, err
+				}
+				return reader, reader.Open(fs.DataReaderOpenOptions{
+					Identifier: fs.FileSetFileIdentifier{
+						Namespace:  nsID,
+						BlockStart: blockStart,
+					},
+					FileSetType:      persist.FileSetFlushType,
+					StreamingEnabled: true,
+				})
+			}).AnyTimes()
+		return shard
+	}
+	newTestNamespace := func(nsID ident.ID, blockSize time.Duration) *MockNamespace {
+		ns := NewMockNamespace(ctrl)
+		ns.EXPECT().ID().Return(nsID).AnyTimes()
+		ns.EXPECT().Options().Return(namespace.NewOptions().SetRetentionOptions(
+			retention.NewOptions().SetBlockSize(blockSize))).AnyTimes()
+		ns.EXPECT().ReadableShardAt(uint32(0)).
+			Return(newTestShard(nsID), namespace.Context{}, nil).AnyTimes()
+		ns.EXPECT().StorageOptions().Return(opts).AnyTimes()
+		ns.EXPECT().Schema().Return(nil).AnyTimes()
+		return ns
+	}
+
+	var (
+		sourceNs      = newTestNamespace(sourceNsID, sourceBlockSize)
+		targetNs      = newTestNamespace(targetNsID, targetBlockSize)
+		onFlushSeries = persist.NewMockOnFlushSeries(ctrl)
+		flushedIDs    []string
+	)
+	onFlushSeries.EXPECT().OnFlushNewSeries(gomock.Any()).DoAndReturn(
+		func(event persist.OnFlushNewSeriesEvent) error {
+			assert.Equal(t, start, event.BlockStart)
+			flushedIDs = append(flushedIDs, string(event.SeriesMetadata.Document.ID))
+			return nil
+		}).Times(3)
+	onFlushSeries.EXPECT().CheckpointAndMaybeCompact().Return(nil)
+
+	aggregator := NewTileAggregator(instrument.NewOptions())
+	processedTileCount, nextVolume, err := aggregator.AggregateTiles(ctx, sourceNs, targetNs, 0,
+		onFlushSeries, AggregateTilesOptions{
+			Start: start,
+			End:   start.Add(2 * time.Hour),
+			Step:  30 * time.Minute,
+		})
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), processedTileCount)
+	assert.Equal(t, 1, nextVolume)
+	assert.Equal(t, []string{"counter", "existing", "gauge"}, flushedIDs)
+
+	results := readTestTileFileSet(t, opts, targetNsID, start, nextVolume)
+	require.Len(t, results, 3)
+
+	// The counter continues from the previous tile and is corrected for the
+	// reset in the second source block.
+	dps := results["counter"]
+	require.Len(t, dps, 2)
+	assert.Equal(t, start.Add(20*time.Minute), dps[0].TimestampNanos)
+	assert.Equal(t, 120.0, dps[0].Value)
+	assert.Equal(t, annotation.Tile{ResetOffset: 40}, *requireTilePayload(t, dps[0]).Tile)
+	assert.Equal(t, start.Add(70*time.Minute), dps[1].TimestampNanos)
+	assert.Equal(t, 125.0, dps[1].Value)
+	assert.Equal(t, annotation.Tile{ResetOffset: 120}, *requireTilePayload(t, dps[1]).Tile)
+
+	// The gauge keeps its existing datapoint outside of the aggregated range.
+	dps = results["gauge"]
+	require.Len(t, dps, 3)
+	assert.Equal(t, start.Add(10*time.Minute), dps[0].TimestampNanos)
+	assert.Equal(t, 3.0, dps[0].Value)
+	payload := requireTilePayload(t, dps[0])
+	assert.Equal(t, annotation.MetricType_GAUGE, payload.MetricType)
+	assert.Equal(t, annotation.Tile{Min: 1, Max: 3, Sum: 4, Count: 2}, *payload.Tile)
+	assert.Equal(t, start.Add(65*time.Minute), dps[1].TimestampNanos)
+	assert.Equal(t, 2.0, dps[1].Value)
+	assert.Equal(t, annotation.Tile{Min: 2, Max: 2, Sum: 2, Count: 1}, *requireTilePayload(t, dps[1]).Tile)
+	assert.Equal(t, start.Add(3*time.Hour), dps[2].TimestampNanos)
+	assert.Equal(t, 9.0, dps[2].Value)
+
+	dps = results["existing"]
+	require.Len(t, dps, 1)
+	assert.Equal(t, 7.0, dps[0].Value)
+}
@@ -0,0 +1,308 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"bytes"
+	"math"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/generated/proto/annotation"
+	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/m3ninx/doc"
+	"github.com/m3db/m3/src/metrics/metric"
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+// tileMetricNameTag is the tag holding the name of the metric, used to key
+// the memorized metric types.
+var tileMetricNameTag = []byte("__name__")
+
+type tileKind uint
+
+const (
+	// gaugeTileKind keeps the last value of the tile along with the min, max,
+	// sum and count of its samples.
+	gaugeTileKind tileKind = iota
+	// counterTileKind keeps the last value of a cumulative counter corrected
+	// for the resets of the counter.
+	counterTileKind
+	// deltaCounterTileKind keeps the sum of the samples of a counter whose
+	// samples are increments, along with the aggregates of a gauge tile.
+	deltaCounterTileKind
+	// nativeHistogramTileKind keeps the last sample of the tile along with
+	// its histogram annotation.
+	nativeHistogramTileKind
+)
+
+// tileDatapoint is a datapoint of a series being aggregated, the annotation
+// is carried forward from the last datapoint that was encoded with one.
+type tileDatapoint struct {
+	ts.Datapoint
+
+	unit       xtime.Unit
+	annotation ts.Annotation
+}
+
+// payloadTileKind returns the kind of tiles the series of the payload is
+// downsampled into.
+func payloadTileKind(payload annotation.Payload) tileKind {
+	switch {
+	case len(payload.NativeHistogram) > 0:
+		return nativeHistogramTileKind
+	case payload.HandleValueResets:
+		return counterTileKind
+	case payload.MetricType == annotation.MetricType_COUNTER:
+		return deltaCounterTileKind
+	default:
+		return gaugeTileKind
+	}
+}
+
+// seriesTilePayload resolves the metric type of a series from the
+// annotations of its samples first, then from its metric type tags and
+// lastly from the metric types memorized by earlier aggregations.
+func seriesTilePayload(
+	metadata doc.Metadata,
+	samples []tileDatapoint,
+	opts AggregateTilesOptions,
+) annotation.Payload {
+	var name []byte
+	for _, field := range metadata.Fields {
+		if bytes.Equal(field.Name, tileMetricNameTag) {
+			name = field.Value
+			break
+		}
+	}
+
+	if payload, ok := samplesTilePayload(samples); ok {
+		if opts.MemorizeMetricTypes && len(name) > 0 {
+			opts.MetricTypeByName[string(name)] = annotation.Payload{
+				MetricType:        payload.MetricType,
+				HandleValueResets: payload.HandleValueResets,
+			}
+		}
+		return payload
+	}
+
+	if payload, ok := tagsTilePayload(metadata.Fields); ok {
+		return payload
+	}
+
+	if opts.BackfillMetricTypes && len(name) > 0 {
+		if payload, ok := opts.MetricTypeByName[string(name)]; ok {
+			return payload
+		}
+	}
+
+	return annotation.Payload{}
+}
+
+func samplesTilePayload(samples []tileDatapoint) (annotation.Payload, bool) {
+	var last ts.Annotation
+	for _, sample := range samples {
+		if len(sample.annotation) == 0 || bytes.Equal(sample.annotation, last) {
+			continue
+		}
+		last = sample.annotation
+
+		var payload annotation.Payload
+		if err := payload.Unmarshal(sample.annotation); err != nil {
+			// NB: annotations not written by the coordinator carry no type.
+			continue
+		}
+		if payload.MetricType != annotation.MetricType_UNKNOWN ||
+			payload.HandleValueResets || len(payload.NativeHistogram) > 0 {
+			payload.Tile = nil
+			return payload, true
+		}
+	}
+
+	return annotation.Payload{}, false
+}
+
+func tagsTilePayload(fields doc.Fields) (annotation.Payload, bool) {
+	var promType, m3Type []byte
+	for _, field := range fields {
+		switch {
+		case bytes.Equal(field.Name, metric.M3PromTypeTag):
+			promType = field.Value
+		case bytes.Equal(field.Name, metric.M3TypeTag):
+			m3Type = field.Value
+		}
+	}
+
+	switch {
+	case bytes.Equal(promType, metric.PromCounterValue):
+		return annotation.Payload{MetricType: annotation.MetricType_COUNTER, HandleValueResets: true}, true
+	case bytes.Equal(promType, metric.PromHistogramValue):
+		return annotation.Payload{MetricType: annotation.MetricType_HISTOGRAM, HandleValueResets: true}, true
+	case bytes.Equal(promType, metric.PromSummaryValue):
+		return annotation.Payload{MetricType: annotation.MetricType_SUMMARY, HandleValueResets: true}, true
+	case bytes.Equal(promType, metric.PromGaugeValue):
+		return annotation.Payload{MetricType: annotation.MetricType_GAUGE}, true
+	case bytes.Equal(promType, metric.PromGaugeHistogramValue):
+		return annotation.Payload{MetricType: annotation.MetricType_GAUGE_HISTOGRAM}, true
+	case bytes.Equal(promType, metric.PromInfoValue):
+		return annotation.Payload{MetricType: annotation.MetricType_INFO}, true
+	case bytes.Equal(promType, metric.PromStateSetValue):
+		return annotation.Payload{MetricType: annotation.MetricType_STATESET}, true
+	}
+
+	switch {
+	case bytes.Equal(m3Type, metric.M3CounterValue):
+		return annotation.Payload{MetricType: annotation.MetricType_COUNTER}, true
+	case bytes.Equal(m3Type, metric.M3GaugeValue), bytes.Equal(m3Type, metric.M3TimerValue):
+		return annotation.Payload{MetricType: annotation.MetricType_GAUGE}, true
+	}
+
+	return annotation.Payload{}, false
+}
+
+// tileCounter corrects the samples of a cumulative counter for its resets.
+type tileCounter struct {
+	offset  float64
+	prevRaw float64
+	hasPrev bool
+}
+
+// newTileCounter returns a counter continuing from the last tile written
+// for the series, if any.
+func newTileCounter(prev *tileDatapoint) tileCounter {
+	if prev == nil {
+		return tileCounter{}
+	}
+
+	var offset float64
+	if len(prev.annotation) > 0 {
+		var payload annotation.Payload
+		if err := payload.Unmarshal(prev.annotation); err == nil && payload.Tile != nil {
+			offset = payload.Tile.ResetOffset
+		}
+	}
+
+	return tileCounter{
+		offset:  offset,
+		prevRaw: prev.Value - offset,
+		hasPrev: true,
+	}
+}
+
+// add returns the cumulative value of the raw sample.
+func (c *tileCounter) add(raw float64) float64 {
+	if c.hasPrev && raw < c.prevRaw {
+		c.offset += c.prevRaw
+	}
+	c.prevRaw = raw
+	c.hasPrev = true
+	return raw + c.offset
+}
+
+// aggregateSeriesTiles downsamples the samples of a series into a datapoint
+// per step, each written at the time of the last sample of its step. The
+// samples must be in time order, prev is the last tile written for the
+// series before the samples and is used to carry the counter reset offset.
+func aggregateSeriesTiles(
+	samples []tileDatapoint,
+	payload annotation.Payload,
+	prev *tileDatapoint,
+	step time.Duration,
+	dst []tileDatapoint,
+) ([]tileDatapoint, error) {
+	var (
+		kind    = payloadTileKind(payload)
+		counter = newTileCounter(prev)
+	)
+	for i := 0; i < len(samples); {
+		var (
+			tileStart = samples[i].TimestampNanos.Truncate(step)
+			j         = i + 1
+		)
+		for j < len(samples) && samples[j].TimestampNanos.Truncate(step) == tileStart {
+			j++
+		}
+		tile := samples[i:j]
+		i = j
+
+		if kind == nativeHistogramTileKind {
+			dst = append(dst, tile[len(tile)-1])
+			continue
+		}
+
+		var (
+			last     tileDatapoint
+			agg      = annotation.Tile{Min: math.Inf(1), Max: math.Inf(-1)}
+			value    float64
+			hasValue bool
+		)
+		for _, sample := range tile {
+			// NB: NaN samples, such as Prometheus staleness markers, are not
+			// aggregated into the tiles.
+			if math.IsNaN(sample.Value) {
+				continue
+			}
+			last = sample
+			hasValue = true
+			if kind == counterTileKind {
+				value = counter.add(sample.Value)
+				continue
+			}
+			agg.Min = math.Min(agg.Min, sample.Value)
+			agg.Max = math.Max(agg.Max, sample.Value)
+			agg.Sum += sample.Value
+			agg.Count++
+			value = sample.Value
+		}
+		if !hasValue {
+			continue
+		}
+
+		result := annotation.Payload{
+			MetricType:        payload.MetricType,
+			HandleValueResets: payload.HandleValueResets,
+		}
+		switch kind {
+		case counterTileKind:
+			result.Tile = &annotation.Tile{ResetOffset: counter.offset}
+		case deltaCounterTileKind:
+			value = agg.Sum
+			result.Tile = &agg
+		default:
+			result.Tile = &agg
+		}
+
+		annot, err := result.Marshal()
+		if err != nil {
+			return nil, err
+		}
+
+		dst = append(dst, tileDatapoint{
+			Datapoint: ts.Datapoint{
+				TimestampNanos: last.TimestampNanos,
+				Value:          value,
+			},
+			unit:       last.unit,
+			annotation: annot,
+		})
+	}
+
+	return dst, nil
+}
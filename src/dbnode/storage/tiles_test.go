@@ -0,0 +1,199 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/generated/proto/annotation"
+	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/m3ninx/doc"
+	"github.com/m3db/m3/src/metrics/metric"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testTileAnnotation(t *testing.T, payload annotation.Payload) ts.Annotation {
+	annot, err := payload.Marshal()
+	require.NoError(t, err)
+	return annot
+}
+
+func testTileSamples(
+	start xtime.UnixNano,
+	interval time.Duration,
+	annot ts.Annotation,
+	values ...float64,
+) []tileDatapoint {
+	samples := make([]tileDatapoint, 0, len(values))
+	for i, value := range values {
+		samples = append(samples, tileDatapoint{
+			Datapoint: ts.Datapoint{
+				TimestampNanos: start.Add(time.Duration(i) * interval),
+				Value:          value,
+			},
+			unit:       xtime.Second,
+			annotation: annot,
+		})
+	}
+	return samples
+}
+
+func requireTilePayload(t *testing.T, dp tileDatapoint) annotation.Payload {
+	var payload annotation.Payload
+	require.NoError(t, payload.Unmarshal(dp.annotation))
+	require.NotNil(t, payload.Tile)
+	return payload
+}
+
+func TestAggregateSeriesTilesGauge(t *testing.T) {
+	var (
+		start   = xtime.UnixNano(0).Add(time.Hour)
+		annot   = testTileAnnotation(t, annotation.Payload{MetricType: annotation.MetricType_GAUGE})
+		samples = testTileSamples(start, time.Minute, annot, 3, 1, 5, math.NaN(), 2, 4)
+	)
+
+	tiles, err := aggregateSeriesTiles(samples,
+		annotation.Payload{MetricType: annotation.MetricType_GAUGE}, nil, 3*time.Minute, nil)
+	require.NoError(t, err)
+	require.Len(t, tiles, 2)
+
+	assert.Equal(t, start.Add(2*time.Minute), tiles[0].TimestampNanos)
+	assert.Equal(t, 5.0, tiles[0].Value)
+	assert.Equal(t, xtime.Second, tiles[0].unit)
+	payload := requireTilePayload(t, tiles[0])
+	assert.Equal(t, annotation.MetricType_GAUGE, payload.MetricType)
+	assert.Equal(t, annotation.Tile{Min: 1, Max: 5, Sum: 9, Count: 3}, *payload.Tile)
+
+	// The NaN sample is not aggregated.
+	assert.Equal(t, start.Add(5*time.Minute), tiles[1].TimestampNanos)
+	assert.Equal(t, 4.0, tiles[1].Value)
+	payload = requireTilePayload(t, tiles[1])
+	assert.Equal(t, annotation.Tile{Min: 2, Max: 4, Sum: 6, Count: 2}, *payload.Tile)
+}
+
+func TestAggregateSeriesTilesCounterResets(t *testing.T) {
+	var (
+		start   = xtime.UnixNano(0).Add(time.Hour)
+		payload = annotation.Payload{
+			MetricType:        annotation.MetricType_COUNTER,
+			HandleValueResets: true,
+		}
+		samples = testTileSamples(start, time.Minute, testTileAnnotation(t, payload),
+			10, 15, 3, 8, 2, 6)
+	)
+
+	tiles, err := aggregateSeriesTiles(samples, payload, nil, 2*time.Minute, nil)
+	require.NoError(t, err)
+	require.Len(t, tiles, 3)
+
+	expected := []struct {
+		value, offset float64
+	}{
+		{value: 15, offset: 0},
+		{value: 23, offset: 15},
+		{value: 29, offset: 23},
+	}
+	for i, e := range expected {
+		assert.Equal(t, start.Add(time.Duration(2*i+1)*time.Minute), tiles[i].TimestampNanos)
+		assert.Equal(t, e.value, tiles[i].Value)
+		tilePayload := requireTilePayload(t, tiles[i])
+		assert.True(t, tilePayload.HandleValueResets)
+		assert.Equal(t, annotation.Tile{ResetOffset: e.offset}, *tilePayload.Tile)
+	}
+
+	// Aggregating the following samples continues from the last tile.
+	next := testTileSamples(start.Add(6*time.Minute), time.Minute, nil, 1, 4)
+	tiles, err = aggregateSeriesTiles(next, payload, &tiles[len(tiles)-1], 2*time.Minute, nil)
+	require.NoError(t, err)
+	require.Len(t, tiles, 1)
+	assert.Equal(t, 33.0, tiles[0].Value)
+	assert.Equal(t, annotation.Tile{ResetOffset: 29}, *requireTilePayload(t, tiles[0]).Tile)
+}
+
+func TestAggregateSeriesTilesDeltaCounter(t *testing.T) {
+	var (
+		start   = xtime.UnixNano(0).Add(time.Hour)
+		payload = annotation.Payload{MetricType: annotation.MetricType_COUNTER}
+		samples = testTileSamples(start, time.Minute, nil, 1, 2, 3)
+	)
+
+	tiles, err := aggregateSeriesTiles(samples, payload, nil, time.Hour, nil)
+	require.NoError(t, err)
+	require.Len(t, tiles, 1)
+	assert.Equal(t, 6.0, tiles[0].Value)
+	assert.Equal(t, annotation.Tile{Min: 1, Max: 3, Sum: 6, Count: 3},
+		*requireTilePayload(t, tiles[0]).Tile)
+}
+
+func TestAggregateSeriesTilesNativeHistogram(t *testing.T) {
+	var (
+		start   = xtime.UnixNano(0).Add(time.Hour)
+		payload = annotation.Payload{NativeHistogram: []byte{1}}
+		samples = testTileSamples(start, time.Minute, testTileAnnotation(t, payload), 1, 2, 3)
+	)
+	samples[2].annotation = testTileAnnotation(t, annotation.Payload{NativeHistogram: []byte{2}})
+
+	tiles, err := aggregateSeriesTiles(samples, payload, nil, time.Hour, nil)
+	require.NoError(t, err)
+	require.Equal(t, samples[2:], tiles)
+}
+
+func TestSeriesTilePayload(t *testing.T) {
+	var (
+		name         = []byte("requests")
+		nameField    = doc.Field{Name: tileMetricNameTag, Value: name}
+		counter      = annotation.Payload{MetricType: annotation.MetricType_COUNTER, HandleValueResets: true}
+		start        = xtime.UnixNano(0)
+		metricTypes  = make(map[string]annotation.Payload)
+		memorizeOpts = AggregateTilesOptions{MemorizeMetricTypes: true, MetricTypeByName: metricTypes}
+		backfillOpts = AggregateTilesOptions{BackfillMetricTypes: true, MetricTypeByName: metricTypes}
+	)
+
+	// The type is resolved from the annotations of the samples first.
+	samples := testTileSamples(start, time.Minute, nil, 1, 2)
+	samples[1].annotation = testTileAnnotation(t, counter)
+	metadata := doc.Metadata{Fields: doc.Fields{
+		nameField,
+		{Name: metric.M3TypeTag, Value: metric.M3GaugeValue},
+	}}
+	assert.Equal(t, counter, seriesTilePayload(metadata, samples, memorizeOpts))
+	assert.Equal(t, map[string]annotation.Payload{"requests": counter}, metricTypes)
+
+	// Then from the metric type tags.
+	samples = testTileSamples(start, time.Minute, nil, 1, 2)
+	assert.Equal(t, annotation.Payload{MetricType: annotation.MetricType_GAUGE},
+		seriesTilePayload(metadata, samples, backfillOpts))
+	metadata.Fields = append(metadata.Fields,
+		doc.Field{Name: metric.M3PromTypeTag, Value: metric.PromHistogramValue})
+	assert.Equal(t,
+		annotation.Payload{MetricType: annotation.MetricType_HISTOGRAM, HandleValueResets: true},
+		seriesTilePayload(metadata, samples, backfillOpts))
+
+	// Then from the memorized metric types.
+	metadata.Fields = doc.Fields{nameField}
+	assert.Equal(t, counter, seriesTilePayload(metadata, samples, backfillOpts))
+	assert.Equal(t, annotation.Payload{}, seriesTilePayload(metadata, samples, AggregateTilesOptions{}))
+}
@@ -0,0 +1,405 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"sync"
+
+	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/storage/series"
+	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/dbnode/x/xio"
+	"github.com/m3db/m3/src/x/context"
+	"github.com/m3db/m3/src/x/ident"
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+const tombstonesFileName = "tombstones.json"
+
+// seriesTombstones tracks the series of a shard that have been deleted, per
+// block start. Tombstoned series are filtered from reads and index queries
+// and are dropped when the block is next flushed. Tombstones are persisted
+// to the shard data directory so that deletes survive restarts and are kept
+// until the block falls out of retention.
+type seriesTombstones struct {
+	sync.RWMutex
+
+	filePath string
+	fsOpts   fs.Options
+	blocks   map[xtime.UnixNano]*tombstonesBlock
+}
+
+type tombstonesBlock struct {
+	ids map[string]struct{}
+	// version is incremented each time series are tombstoned for the block
+	// and flushedVersion is the version last written to disk without the
+	// tombstoned series.
+	version        int
+	flushedVersion int
+}
+
+func (b *tombstonesBlock) flushed() bool {
+	return b.flushedVersion == b.version
+}
+
+type tombstonesFile struct {
+	Blocks []tombstonesFileBlock `json:"blocks"`
+}
+
+type tombstonesFileBlock struct {
+	BlockStart int64    `json:"blockStart"`
+	Flushed    bool     `json:"flushed"`
+	IDs        [][]byte `json:"ids"`
+}
+
+func newSeriesTombstones(
+	fsOpts fs.Options,
+	namespace ident.ID,
+	shard uint32,
+) *seriesTombstones {
+	dir := fs.ShardDataDirPath(fsOpts.FilePathPrefix(), namespace, shard)
+	return &seriesTombstones{
+		filePath: path.Join(dir, tombstonesFileName),
+		fsOpts:   fsOpts,
+		blocks:   make(map[xtime.UnixNano]*tombstonesBlock),
+	}
+}
+
+// Load reads any previously persisted tombstones.
+func (t *seriesTombstones) Load() error {
+	data, err := ioutil.ReadFile(t.filePath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	data, err = fs.DecryptFileData(t.fsOpts, data)
+	if err != nil {
+		return err
+	}
+
+	var file tombstonesFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return err
+	}
+
+	t.Lock()
+	defer t.Unlock()
+	for _, b := range file.Blocks {
+		block := t.blockWithLock(xtime.UnixNano(b.BlockStart))
+		block.version = 1
+		if b.Flushed {
+			block.flushedVersion = 1
+		}
+		for _, id := range b.IDs {
+			block.ids[string(id)] = struct{}{}
+		}
+	}
+	return nil
+}
+
+// Add tombstones the series for the given block starts and persists the
+// updated tombstones.
+func (t *seriesTombstones) Add(ids []ident.ID, blockStarts []xtime.UnixNano) error {
+	if len(ids) == 0 || len(blockStarts) == 0 {
+		return nil
+	}
+
+	t.Lock()
+	defer t.Unlock()
+	for _, blockStart := range blockStarts {
+		block := t.blockWithLock(blockStart)
+		block.version++
+		for _, id := range ids {
+			block.ids[id.String()] = struct{}{}
+		}
+	}
+	return t.persistWithLock()
+}
+
+// Contains returns whether the series is tombstoned for the block start.
+func (t *seriesTombstones) Contains(id []byte, blockStart xtime.UnixNano) bool {
+	t.RLock()
+	block, ok := t.blocks[blockStart]
+	if !ok {
+		t.RUnlock()
+		return false
+	}
+	_, ok = block.ids[string(id)]
+	t.RUnlock()
+	return ok
+}
+
+// ContainsAll returns whether the series is tombstoned for every one of the
+// block starts, it returns false if there are no block starts.
+func (t *seriesTombstones) ContainsAll(id []byte, blockStarts []xtime.UnixNano) bool {
+	if len(blockStarts) == 0 {
+		return false
+	}
+
+	t.RLock()
+	defer t.RUnlock()
+	for _, blockStart := range blockStarts {
+		block, ok := t.blocks[blockStart]
+		if !ok {
+			return false
+		}
+		if _, ok := block.ids[string(id)]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// ContainsBlocks returns whether there are tombstones for every one of the
+// block starts, it returns false if there are no block starts.
+func (t *seriesTombstones) ContainsBlocks(blockStarts []xtime.UnixNano) bool {
+	if len(blockStarts) == 0 {
+		return false
+	}
+
+	t.RLock()
+	defer t.RUnlock()
+	for _, blockStart := range blockStarts {
+		if _, ok := t.blocks[blockStart]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// IsEmpty returns whether there are any tombstones.
+func (t *seriesTombstones) IsEmpty() bool {
+	t.RLock()
+	empty := len(t.blocks) == 0
+	t.RUnlock()
+	return empty
+}
+
+// UnflushedBlockStarts returns the block starts that have tombstones which
+// have not yet been applied to the data on disk.
+func (t *seriesTombstones) UnflushedBlockStarts() []xtime.UnixNano {
+	t.RLock()
+	defer t.RUnlock()
+	var result []xtime.UnixNano
+	for blockStart, block := range t.blocks {
+		if !block.flushed() {
+			result = append(result, blockStart)
+		}
+	}
+	return result
+}
+
+// Version returns the current tombstones version for the block start, it
+// should be retrieved before a flush and passed to MarkFlushed once the
+// flush has completed.
+func (t *seriesTombstones) Version(blockStart xtime.UnixNano) int {
+	t.RLock()
+	defer t.RUnlock()
+	block, ok := t.blocks[blockStart]
+	if !ok {
+		return 0
+	}
+	return block.version
+}
+
+// MarkFlushed marks the tombstones for the block start as of the given
+// version as having been applied to the data on disk.
+func (t *seriesTombstones) MarkFlushed(blockStart xtime.UnixNano, version int) error {
+	t.Lock()
+	defer t.Unlock()
+	block, ok := t.blocks[blockStart]
+	if !ok || version <= block.flushedVersion {
+		return nil
+	}
+	block.flushedVersion = version
+	return t.persistWithLock()
+}
+
+// RemoveBefore removes the tombstones for all blocks before the given time.
+func (t *seriesTombstones) RemoveBefore(earliest xtime.UnixNano) error {
+	t.Lock()
+	defer t.Unlock()
+	removed := false
+	for blockStart := range t.blocks {
+		if blockStart.Before(earliest) {
+			delete(t.blocks, blockStart)
+			removed = true
+		}
+	}
+	if !removed {
+		return nil
+	}
+	return t.persistWithLock()
+}
+
+func (t *seriesTombstones) blockWithLock(blockStart xtime.UnixNano) *tombstonesBlock {
+	block, ok := t.blocks[blockStart]
+	if !ok {
+		block = &tombstonesBlock{ids: make(map[string]struct{})}
+		t.blocks[blockStart] = block
+	}
+	return block
+}
+
+func (t *seriesTombstones) persistWithLock() error {
+	if len(t.blocks) == 0 {
+		err := os.Remove(t.filePath)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	file := tombstonesFile{
+		Blocks: make([]tombstonesFileBlock, 0, len(t.blocks)),
+	}
+	for blockStart, block := range t.blocks {
+		ids := make([][]byte, 0, len(block.ids))
+		for id := range block.ids {
+			ids = append(ids, []byte(id))
+		}
+		file.Blocks = append(file.Blocks, tombstonesFileBlock{
+			BlockStart: int64(blockStart),
+			Flushed:    block.flushed(),
+			IDs:        ids,
+		})
+	}
+	sort.Slice(file.Blocks, func(i, j int) bool {
+		return file.Blocks[i].BlockStart < file.Blocks[j].BlockStart
+	})
+
+	data, err := json.Marshal(file)
+	if err != nil {
+		return err
+	}
+	data, err = fs.EncryptFileData(t.fsOpts, data)
+	if err != nil {
+		return err
+	}
+
+	dir := path.Dir(t.filePath)
+	if err := os.MkdirAll(dir, t.fsOpts.NewDirectoryMode()); err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so that a partially written file
+	// is never observed.
+	tmpFilePath := t.filePath + ".tmp"
+	if err := ioutil.WriteFile(tmpFilePath, data, t.fsOpts.NewFileMode()); err != nil {
+		return err
+	}
+	return os.Rename(tmpFilePath, t.filePath)
+}
+
+// tombstonesBlockReaderIter filters the block readers for tombstoned blocks.
+type tombstonesBlockReaderIter struct {
+	series.BlockReaderIter
+
+	id         ident.ID
+	tombstones *seriesTombstones
+}
+
+func newTombstonesBlockReaderIter(
+	iter series.BlockReaderIter,
+	id ident.ID,
+	tombstones *seriesTombstones,
+) series.BlockReaderIter {
+	return &tombstonesBlockReaderIter{
+		BlockReaderIter: iter,
+		id:              id,
+		tombstones:      tombstones,
+	}
+}
+
+func (i *tombstonesBlockReaderIter) Next(ctx context.Context) bool {
+	for i.BlockReaderIter.Next(ctx) {
+		if !i.tombstoned(i.BlockReaderIter.Current()) {
+			return true
+		}
+	}
+	return false
+}
+
+func (i *tombstonesBlockReaderIter) ToSlices(ctx context.Context) ([][]xio.BlockReader, error) {
+	var results [][]xio.BlockReader
+	for i.Next(ctx) {
+		results = append(results, i.Current())
+	}
+	return results, i.Err()
+}
+
+func (i *tombstonesBlockReaderIter) tombstoned(readers []xio.BlockReader) bool {
+	if len(readers) == 0 {
+		return false
+	}
+	return i.tombstones.Contains(i.id.Bytes(), readers[0].Start)
+}
+
+// tombstonesFlushPreparer drops tombstoned series from the data being
+// persisted by a flush.
+type tombstonesFlushPreparer struct {
+	persist.FlushPreparer
+
+	tombstones *seriesTombstones
+}
+
+func newTombstonesFlushPreparer(
+	preparer persist.FlushPreparer,
+	tombstones *seriesTombstones,
+) persist.FlushPreparer {
+	return &tombstonesFlushPreparer{
+		FlushPreparer: preparer,
+		tombstones:    tombstones,
+	}
+}
+
+func (p *tombstonesFlushPreparer) PrepareData(
+	opts persist.DataPrepareOptions,
+) (persist.PreparedDataPersist, error) {
+	prepared, err := p.FlushPreparer.PrepareData(opts)
+	if err != nil {
+		return prepared, err
+	}
+
+	persistFn := prepared.Persist
+	prepared.Persist = func(
+		metadata persist.Metadata,
+		segment ts.Segment,
+		checksum uint32,
+	) error {
+		if p.tombstones.Contains(metadata.BytesID(), opts.BlockStart) {
+			// The writer would otherwise finalize the metadata once the
+			// volume is written.
+			metadata.Finalize()
+			return nil
+		}
+		return persistFn(metadata, segment, checksum)
+	}
+	return prepared, nil
+}
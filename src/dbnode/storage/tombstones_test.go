@@ -0,0 +1,140 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/persist/encryption"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/x/ident"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSeriesTombstones(t *testing.T) (*seriesTombstones, string) {
+	dir, err := ioutil.TempDir("", "tombstones")
+	require.NoError(t, err)
+
+	fsOpts := fs.NewOptions().SetFilePathPrefix(dir)
+	return newSeriesTombstones(fsOpts, ident.StringID("ns"), 0), dir
+}
+
+func TestSeriesTombstonesAddAndContains(t *testing.T) {
+	tombstones, dir := newTestSeriesTombstones(t)
+	defer os.RemoveAll(dir)
+
+	var (
+		blockSize = 2 * time.Hour
+		first     = xtime.Now().Truncate(blockSize)
+		second    = first.Add(blockSize)
+	)
+	require.True(t, tombstones.IsEmpty())
+	require.NoError(t, tombstones.Add(
+		[]ident.ID{ident.StringID("foo")}, []xtime.UnixNano{first}))
+
+	assert.False(t, tombstones.IsEmpty())
+	assert.True(t, tombstones.Contains([]byte("foo"), first))
+	assert.False(t, tombstones.Contains([]byte("foo"), second))
+	assert.False(t, tombstones.Contains([]byte("bar"), first))
+	assert.True(t, tombstones.ContainsAll([]byte("foo"), []xtime.UnixNano{first}))
+	assert.False(t, tombstones.ContainsAll([]byte("foo"),
+		[]xtime.UnixNano{first, second}))
+	assert.False(t, tombstones.ContainsAll([]byte("foo"), nil))
+	assert.True(t, tombstones.ContainsBlocks([]xtime.UnixNano{first}))
+	assert.False(t, tombstones.ContainsBlocks([]xtime.UnixNano{first, second}))
+	assert.False(t, tombstones.ContainsBlocks(nil))
+
+	assert.Equal(t, []xtime.UnixNano{first}, tombstones.UnflushedBlockStarts())
+	require.NoError(t, tombstones.MarkFlushed(first, tombstones.Version(first)))
+	assert.Empty(t, tombstones.UnflushedBlockStarts())
+
+	// Tombstoning further series makes the block unflushed again.
+	version := tombstones.Version(first)
+	require.NoError(t, tombstones.Add(
+		[]ident.ID{ident.StringID("bar")}, []xtime.UnixNano{first}))
+	require.NoError(t, tombstones.MarkFlushed(first, version))
+	assert.Equal(t, []xtime.UnixNano{first}, tombstones.UnflushedBlockStarts())
+
+	require.NoError(t, tombstones.RemoveBefore(second))
+	assert.True(t, tombstones.IsEmpty())
+	_, err := os.Stat(tombstones.filePath)
+	assert.True(t, os.IsNotExist(err))
+}
+
+// withTestEncryption enables encryption with a key written to dir.
+func withTestEncryption(t *testing.T, fsOpts fs.Options, dir string) fs.Options {
+	key := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, 32))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "key"), []byte(key), 0600))
+	provider, err := encryption.NewFileKeyProvider(dir, "key")
+	require.NoError(t, err)
+	return fsOpts.
+		SetEncryptionEnabled(true).
+		SetEncryptionCipher(encryption.NewCipher(provider))
+}
+
+func TestSeriesTombstonesLoad(t *testing.T) {
+	tombstones, dir := newTestSeriesTombstones(t)
+	defer os.RemoveAll(dir)
+
+	var (
+		blockSize = 2 * time.Hour
+		first     = xtime.Now().Truncate(blockSize)
+		second    = first.Add(blockSize)
+	)
+	require.NoError(t, tombstones.Add(
+		[]ident.ID{ident.StringID("foo"), ident.StringID("bar")},
+		[]xtime.UnixNano{first, second}))
+	require.NoError(t, tombstones.MarkFlushed(first, tombstones.Version(first)))
+
+	loaded := newSeriesTombstones(tombstones.fsOpts, ident.StringID("ns"), 0)
+	require.NoError(t, loaded.Load())
+
+	assert.True(t, loaded.ContainsAll([]byte("foo"), []xtime.UnixNano{first, second}))
+	assert.True(t, loaded.ContainsAll([]byte("bar"), []xtime.UnixNano{first, second}))
+	assert.Equal(t, []xtime.UnixNano{second}, loaded.UnflushedBlockStarts())
+}
+
+func TestSeriesTombstonesLoadEncrypted(t *testing.T) {
+	tombstones, dir := newTestSeriesTombstones(t)
+	defer os.RemoveAll(dir)
+	tombstones.fsOpts = withTestEncryption(t, tombstones.fsOpts, dir)
+
+	blockStart := xtime.Now().Truncate(2 * time.Hour)
+	require.NoError(t, tombstones.Add(
+		[]ident.ID{ident.StringID("foo")}, []xtime.UnixNano{blockStart}))
+
+	data, err := ioutil.ReadFile(tombstones.filePath)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), base64.StdEncoding.EncodeToString([]byte("foo")))
+
+	loaded := newSeriesTombstones(tombstones.fsOpts, ident.StringID("ns"), 0)
+	require.NoError(t, loaded.Load())
+	assert.True(t, loaded.ContainsAll([]byte("foo"), []xtime.UnixNano{blockStart}))
+}
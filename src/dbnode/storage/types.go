@@ -240,9 +240,66 @@ type Database interface {
 	// Repair will issue a repair and return nil on success or error on error.
 	Repair() error
 
+	// RepairStatus returns the status of the repairs of the shards of the
+	// database.
+	RepairStatus() []repair.ShardStatus
+
+	// MerkleTree returns the merkle tree of the checksums of the series
+	// blocks flushed for the given shard and block start in the given
+	// namespace.
+	MerkleTree(
+		namespace ident.ID,
+		shardID uint32,
+		blockStart xtime.UnixNano,
+		depth int,
+	) (*repair.MerkleTree, error)
+
+	// MerkleTreeLeafEntries returns the checksums of the series blocks
+	// flushed for the given shard and block start in the given namespace that
+	// belong to the given leaves of the merkle tree of the block, or to any
+	// leaf when no leaves are given.
+	MerkleTreeLeafEntries(
+		namespace ident.ID,
+		shardID uint32,
+		blockStart xtime.UnixNano,
+		depth int,
+		leaves []int,
+	) ([]repair.MerkleTreeLeafEntry, error)
+
 	// Truncate truncates data for the given namespace.
 	Truncate(namespace ident.ID) (int64, error)
 
+	// DeleteSeries tombstones the series matching the query in the given
+	// namespace for all blocks that overlap the time range, returning the
+	// number of series tombstoned per shard.
+	DeleteSeries(
+		ctx context.Context,
+		namespace ident.ID,
+		query index.Query,
+		start, end xtime.UnixNano,
+	) (map[uint32]int64, error)
+
+	// WriteExemplars writes the exemplars for the series in the given
+	// namespace.
+	WriteExemplars(namespace ident.ID, id ident.ID, exemplars []ts.Exemplar) error
+
+	// FetchExemplars returns the exemplars in the query time range for the
+	// series matching the query in the given namespace.
+	FetchExemplars(
+		ctx context.Context,
+		namespace ident.ID,
+		query index.Query,
+		opts index.QueryOptions,
+	) (FetchExemplarsResult, error)
+
+	// Cardinality returns the cardinality statistics of the series indexed
+	// in the query time range in the given namespace.
+	Cardinality(
+		ctx context.Context,
+		namespace ident.ID,
+		opts index.CardinalityOptions,
+	) (index.CardinalityResult, error)
+
 	// BootstrapState captures and returns a snapshot of the databases'
 	// bootstrap state.
 	BootstrapState() DatabaseBootstrapState
@@ -450,6 +507,52 @@ type databaseNamespace interface {
 	// Truncate truncates the in-memory data for this namespace.
 	Truncate() (int64, error)
 
+	// DeleteSeries tombstones the series matching the query for all blocks
+	// that overlap the time range, returning the number of series tombstoned
+	// per shard.
+	DeleteSeries(
+		ctx context.Context,
+		query index.Query,
+		start, end xtime.UnixNano,
+	) (map[uint32]int64, error)
+
+	// WriteExemplars writes the exemplars for the series.
+	WriteExemplars(id ident.ID, exemplars []ts.Exemplar) error
+
+	// FetchExemplars returns the exemplars in the query time range for the
+	// series matching the query.
+	FetchExemplars(
+		ctx context.Context,
+		query index.Query,
+		opts index.QueryOptions,
+	) (FetchExemplarsResult, error)
+
+	// Cardinality returns the cardinality statistics of the series indexed
+	// in the query time range.
+	Cardinality(
+		ctx context.Context,
+		opts index.CardinalityOptions,
+	) (index.CardinalityResult, error)
+
+	// MerkleTree returns the merkle tree of the checksums of the series
+	// blocks flushed for the given shard and block start.
+	MerkleTree(
+		shardID uint32,
+		blockStart xtime.UnixNano,
+		depth int,
+	) (*repair.MerkleTree, error)
+
+	// MerkleTreeLeafEntries returns the checksums of the series blocks
+	// flushed for the given shard and block start that belong to the given
+	// leaves of the merkle tree of the block, or to any leaf when no leaves
+	// are given.
+	MerkleTreeLeafEntries(
+		shardID uint32,
+		blockStart xtime.UnixNano,
+		depth int,
+		leaves []int,
+	) ([]repair.MerkleTreeLeafEntry, error)
+
 	// Repair repairs the namespace data for a given time range.
 	Repair(repairer databaseShardRepairer, tr xtime.Range, opts NamespaceRepairOptions) error
 
@@ -649,6 +752,38 @@ type databaseShard interface {
 	// fileset for that block.
 	CleanupCompactedFileSets() error
 
+	// OffloadFileSets offloads the filesets of flushed blocks that ended
+	// before the given time to the secondary storage tier.
+	OffloadFileSets(offloadBefore xtime.UnixNano) error
+
+	// SplitFileSetsInto writes the data of the series that belong to a child
+	// shard being split out of this shard, as determined by the hash function,
+	// from the flushed filesets of this shard into filesets of the child shard.
+	SplitFileSetsInto(childID uint32, hashFn sharding.HashFn) error
+
+	// SplitSeriesInto loads the data that the series which belong to a child
+	// shard being split out of this shard, as determined by the hash function,
+	// hold in memory into the child shard.
+	SplitSeriesInto(
+		ctx context.Context,
+		child databaseShard,
+		hashFn sharding.HashFn,
+		nsCtx namespace.Context,
+	) error
+
+	// MerkleTree returns the merkle tree of the checksums of the series
+	// blocks flushed for the given block start.
+	MerkleTree(blockStart xtime.UnixNano, depth int) (*repair.MerkleTree, error)
+
+	// MerkleTreeLeafEntries returns the checksums of the series blocks
+	// flushed for the given block start that belong to the given leaves of
+	// the merkle tree of the block, or to any leaf when no leaves are given.
+	MerkleTreeLeafEntries(
+		blockStart xtime.UnixNano,
+		depth int,
+		leaves []int,
+	) ([]repair.MerkleTreeLeafEntry, error)
+
 	// Repair repairs the shard data for a given time.
 	Repair(
 		ctx context.Context,
@@ -681,6 +816,36 @@ type databaseShard interface {
 
 	// LatestVolume returns the latest volume for the combination of shard+blockStart.
 	LatestVolume(blockStart xtime.UnixNano) (int, error)
+
+	// DeleteSeries tombstones the series for all blocks that overlap the
+	// time range and are within retention. Whole blocks are tombstoned, so
+	// data outside of the time range in the first and last blocks is also
+	// deleted.
+	DeleteSeries(ids []ident.ID, start, end xtime.UnixNano) error
+
+	// IsSeriesDeleted returns whether the series is tombstoned for every
+	// block that overlaps the time range and is within retention.
+	IsSeriesDeleted(id ident.ID, start, end xtime.UnixNano) bool
+
+	// HasDeletedSeries returns whether the shard has any tombstoned series.
+	HasDeletedSeries() bool
+
+	// HasDeletedSeriesInRange returns whether every block that overlaps the
+	// time range and is within retention has tombstoned series, i.e. whether
+	// any series may be deleted for the whole time range.
+	HasDeletedSeriesInRange(start, end xtime.UnixNano) bool
+
+	// HasUnflushedDeletedSeries returns whether the shard has tombstoned
+	// series that have not yet been removed from the filesets on disk.
+	HasUnflushedDeletedSeries() bool
+
+	// WriteExemplars writes the exemplars for the series, exemplars that are
+	// out of retention, out of order or duplicates are dropped.
+	WriteExemplars(id ident.ID, exemplars []ts.Exemplar)
+
+	// FetchExemplars returns the exemplars for the series in the range
+	// [start, end) in timestamp order.
+	FetchExemplars(id ident.ID, start, end xtime.UnixNano) []ts.Exemplar
 }
 
 // ShardSnapshotResult is a result from a shard snapshot.
@@ -760,6 +925,11 @@ type NamespaceIndex interface {
 	// CleanupDuplicateFileSets removes duplicate fileset files.
 	CleanupDuplicateFileSets(activeShards []uint32) error
 
+	// CompactPersistedFileSets merges the persisted volumes of cold, sealed
+	// index blocks into fewer volumes, dropping the series deleted from the
+	// shards. The volumes it supersedes are removed by CleanupDuplicateFileSets.
+	CompactPersistedFileSets(flush persist.IndexFlush, shards []databaseShard) error
+
 	// Tick performs internal house keeping in the index, including block rotation,
 	// data eviction, and so on.
 	Tick(c context.Cancellable, startTime xtime.UnixNano) (namespaceIndexTickResult, error)
@@ -785,6 +955,10 @@ type NamespaceIndex interface {
 	// BackgroundCompact background compacts eligible segments.
 	BackgroundCompact()
 
+	// LabelCardinality returns the cardinality of each label tracked by the
+	// label cardinality limits of the namespace.
+	LabelCardinality() []limits.LabelCardinality
+
 	// Close will release the index resources and close the index.
 	Close() error
 }
@@ -978,6 +1152,9 @@ type databaseShardRepairer interface {
 		tr xtime.Range,
 		shard databaseShard,
 	) (repair.MetadataComparisonResult, error)
+
+	// Status returns the status of the repairs of the shards.
+	Status() []repair.ShardStatus
 }
 
 // BackgroundProcess is a background process that is run by the database.
@@ -1013,6 +1190,9 @@ type databaseRepairer interface {
 
 	// Repair repairs in-memory data.
 	Repair() error
+
+	// Status returns the status of the repairs of the shards.
+	Status() []repair.ShardStatus
 }
 
 // databaseTickManager performs periodic ticking.
@@ -1426,6 +1606,37 @@ type Options interface {
 
 	// SetLimitsOptions sets the limits options.
 	SetLimitsOptions(value limits.Options) Options
+
+	// SetExemplarsOptions sets the exemplars options.
+	SetExemplarsOptions(value ExemplarsOptions) Options
+
+	// ExemplarsOptions returns the exemplars options.
+	ExemplarsOptions() ExemplarsOptions
+}
+
+// SeriesExemplars are the exemplars of a series.
+type SeriesExemplars struct {
+	// Metadata is the series metadata, it is only valid until the context
+	// used to fetch the exemplars is closed.
+	Metadata  doc.Metadata
+	Exemplars []ts.Exemplar
+}
+
+// FetchExemplarsResult is the result of fetching the exemplars of the series
+// matching a query, series without exemplars are omitted.
+type FetchExemplarsResult struct {
+	Results    []SeriesExemplars
+	Exhaustive bool
+}
+
+// ExemplarsOptions are the options for storing exemplars.
+type ExemplarsOptions struct {
+	// MaxPerSeries is the number of most recent exemplars kept for each
+	// series, exemplars are dropped if zero.
+	MaxPerSeries int
+	// PersistEnabled persists the exemplars of a block alongside the shard
+	// data when the block is flushed so that they survive restarts.
+	PersistEnabled bool
 }
 
 // MemoryTracker tracks memory.
@@ -21,6 +21,8 @@
 package ts
 
 import (
+	"bytes"
+
 	"github.com/m3db/m3/src/x/ident"
 	xtime "github.com/m3db/m3/src/x/time"
 )
@@ -56,6 +58,35 @@ func (d Datapoint) Equal(x Datapoint) bool {
 	return d.TimestampNanos == x.TimestampNanos && d.Value == x.Value
 }
 
+// An Exemplar is a sample, typically referencing a trace, that is
+// associated with a datapoint of a series.
+type Exemplar struct {
+	TimestampNanos xtime.UnixNano
+	Value          float64
+	Labels         []ExemplarLabel
+}
+
+// Equal returns whether one Exemplar is equal to another.
+func (e Exemplar) Equal(x Exemplar) bool {
+	if e.TimestampNanos != x.TimestampNanos || e.Value != x.Value ||
+		len(e.Labels) != len(x.Labels) {
+		return false
+	}
+	for i, l := range e.Labels {
+		if !bytes.Equal(l.Name, x.Labels[i].Name) ||
+			!bytes.Equal(l.Value, x.Labels[i].Value) {
+			return false
+		}
+	}
+	return true
+}
+
+// ExemplarLabel is a label of an exemplar.
+type ExemplarLabel struct {
+	Name  []byte
+	Value []byte
+}
+
 // EncodedTags represents the encoded tags for the series.
 type EncodedTags []byte
 
@@ -43,6 +43,18 @@ func TestMarshal(t *testing.T) {
 			name:  "regexp query",
 			query: MustCreateRegexpQuery([]byte("fruit"), []byte(".*ple")),
 		},
+		{
+			name:  "prefix query",
+			query: NewPrefixQuery([]byte("fruit"), []byte("app")),
+		},
+		{
+			name:  "suffix query",
+			query: NewSuffixQuery([]byte("fruit"), []byte("ple")),
+		},
+		{
+			name:  "numeric range query",
+			query: NewNumericRangeQuery([]byte("weight"), 0.5, 10),
+		},
 		{
 			name:  "negation query",
 			query: NewNegationQuery(NewTermQuery([]byte("fruit"), []byte("apple"))),
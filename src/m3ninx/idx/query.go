@@ -94,6 +94,30 @@ func MustCreateRegexpQuery(field, regexp []byte) Query {
 	}
 }
 
+// NewPrefixQuery returns a new query for finding documents which have a term for
+// the field starting with the given prefix.
+func NewPrefixQuery(field, prefix []byte) Query {
+	return Query{
+		query: query.NewPrefixQuery(field, prefix),
+	}
+}
+
+// NewSuffixQuery returns a new query for finding documents which have a term for
+// the field ending with the given suffix.
+func NewSuffixQuery(field, suffix []byte) Query {
+	return Query{
+		query: query.NewSuffixQuery(field, suffix),
+	}
+}
+
+// NewNumericRangeQuery returns a new query for finding documents which have a term
+// for the field that is an integer within the inclusive range [min, max].
+func NewNumericRangeQuery(field []byte, min, max float64) Query {
+	return Query{
+		query: query.NewNumericRangeQuery(field, min, max),
+	}
+}
+
 // NewNegationQuery returns a new query for finding documents which don't match a given query.
 func NewNegationQuery(q Query) Query {
 	return Query{
@@ -82,6 +82,43 @@ func TestQueryMatcherRegexpQuery(t *testing.T) {
 	}
 }
 
+func TestQueryMatcherPrefixSuffixNumericRangeQuery(t *testing.T) {
+	for _, tc := range []struct {
+		left     idx.Query
+		right    idx.Query
+		expected bool
+	}{
+		{
+			left:     idx.NewPrefixQuery([]byte("abc"), []byte("def")),
+			right:    idx.NewPrefixQuery([]byte("abc"), []byte("def")),
+			expected: true,
+		},
+		{
+			left:     idx.NewPrefixQuery([]byte("abc"), []byte("def")),
+			right:    idx.NewSuffixQuery([]byte("abc"), []byte("def")),
+			expected: false,
+		},
+		{
+			left:     idx.NewSuffixQuery([]byte("abc"), []byte("def")),
+			right:    idx.NewSuffixQuery([]byte("abc"), []byte("def1")),
+			expected: false,
+		},
+		{
+			left:     idx.NewNumericRangeQuery([]byte("abc"), 1, 10),
+			right:    idx.NewNumericRangeQuery([]byte("abc"), 1, 10),
+			expected: true,
+		},
+		{
+			left:     idx.NewNumericRangeQuery([]byte("abc"), 1, 10),
+			right:    idx.NewNumericRangeQuery([]byte("abc"), 1, 11),
+			expected: false,
+		},
+	} {
+		require.Equal(t, tc.expected, idx.NewQueryMatcher(tc.left).Matches(tc.right))
+		require.Equal(t, tc.expected, idx.NewQueryMatcher(tc.right).Matches(tc.left))
+	}
+}
+
 func TestQueryMatcherNegationQuery(t *testing.T) {
 	for _, tc := range []struct {
 		left     idx.Query
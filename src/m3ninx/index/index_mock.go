@@ -145,6 +145,36 @@ func (mr *MockReaderMockRecorder) MatchField(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MatchField", reflect.TypeOf((*MockReader)(nil).MatchField), arg0)
 }
 
+// MatchNumericRange mocks base method.
+func (m *MockReader) MatchNumericRange(arg0 []byte, arg1, arg2 float64) (postings.List, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MatchNumericRange", arg0, arg1, arg2)
+	ret0, _ := ret[0].(postings.List)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MatchNumericRange indicates an expected call of MatchNumericRange.
+func (mr *MockReaderMockRecorder) MatchNumericRange(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MatchNumericRange", reflect.TypeOf((*MockReader)(nil).MatchNumericRange), arg0, arg1, arg2)
+}
+
+// MatchPrefix mocks base method.
+func (m *MockReader) MatchPrefix(arg0, arg1 []byte) (postings.List, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MatchPrefix", arg0, arg1)
+	ret0, _ := ret[0].(postings.List)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MatchPrefix indicates an expected call of MatchPrefix.
+func (mr *MockReaderMockRecorder) MatchPrefix(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MatchPrefix", reflect.TypeOf((*MockReader)(nil).MatchPrefix), arg0, arg1)
+}
+
 // MatchRegexp mocks base method.
 func (m *MockReader) MatchRegexp(arg0 []byte, arg1 CompiledRegex) (postings.List, error) {
 	m.ctrl.T.Helper()
@@ -160,6 +190,21 @@ func (mr *MockReaderMockRecorder) MatchRegexp(arg0, arg1 interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MatchRegexp", reflect.TypeOf((*MockReader)(nil).MatchRegexp), arg0, arg1)
 }
 
+// MatchSuffix mocks base method.
+func (m *MockReader) MatchSuffix(arg0, arg1 []byte) (postings.List, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MatchSuffix", arg0, arg1)
+	ret0, _ := ret[0].(postings.List)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MatchSuffix indicates an expected call of MatchSuffix.
+func (mr *MockReaderMockRecorder) MatchSuffix(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MatchSuffix", reflect.TypeOf((*MockReader)(nil).MatchSuffix), arg0, arg1)
+}
+
 // MatchTerm mocks base method.
 func (m *MockReader) MatchTerm(arg0, arg1 []byte) (postings.List, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,63 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package index
+
+import (
+	"strconv"
+
+	xunsafe "github.com/m3db/m3/src/x/unsafe"
+)
+
+// NumericTermInRange returns whether the term is an integer within the inclusive
+// range [min, max]. Only canonical decimal integers, i.e. without a sign for
+// positive values, leading zeros, a fractional part or an exponent, that fit in
+// 64 bits are numeric so that numeric range queries are equivalent to regexps
+// matching the same integers.
+func NumericTermInRange(term []byte, min, max float64) bool {
+	if !isCanonicalInteger(term) {
+		return false
+	}
+	value, err := strconv.ParseInt(xunsafe.String(term), 10, 64)
+	if err != nil {
+		return false
+	}
+	v := float64(value)
+	return v >= min && v <= max
+}
+
+func isCanonicalInteger(term []byte) bool {
+	if len(term) > 0 && term[0] == '-' {
+		term = term[1:]
+		if len(term) == 1 && term[0] == '0' {
+			// Negative zero.
+			return false
+		}
+	}
+	if len(term) == 0 || (term[0] == '0' && len(term) > 1) {
+		return false
+	}
+	for _, c := range term {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
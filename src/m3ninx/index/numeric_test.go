@@ -0,0 +1,62 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package index
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNumericTermInRange(t *testing.T) {
+	tests := []struct {
+		term     string
+		min, max float64
+		expected bool
+	}{
+		{term: "500", min: 500, max: 599, expected: true},
+		{term: "599", min: 500, max: 599, expected: true},
+		{term: "600", min: 500, max: 599, expected: false},
+		{term: "0", min: 0, max: 0, expected: true},
+		{term: "-12", min: -20, max: 0, expected: true},
+		{term: "-0", min: -1, max: 1, expected: false},
+		{term: "+500", min: 500, max: 599, expected: false},
+		{term: "0500", min: 500, max: 599, expected: false},
+		{term: "500.5", min: 500, max: 599, expected: false},
+		{term: "500.0", min: 500, max: 599, expected: false},
+		{term: "5e2", min: 500, max: 599, expected: false},
+		{term: "-1.5", min: -2, max: 0, expected: false},
+		{term: "99999999999999999999", min: 0, max: math.Inf(1), expected: false},
+		{term: "Inf", min: math.Inf(-1), max: math.Inf(1), expected: false},
+		{term: "-", min: math.Inf(-1), max: math.Inf(1), expected: false},
+		{term: "NaN", min: math.Inf(-1), max: math.Inf(1), expected: false},
+		{term: "abc", min: math.Inf(-1), max: math.Inf(1), expected: false},
+		{term: "", min: math.Inf(-1), max: math.Inf(1), expected: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.term, func(t *testing.T) {
+			require.Equal(t, test.expected,
+				NumericTermInRange([]byte(test.term), test.min, test.max))
+		})
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fst
+
+import (
+	"github.com/m3dbx/vellum"
+)
+
+// newTermsFSTIterator returns an iterator over the terms of the FST within
+// [start, end) that are accepted by the automaton, a nil automaton accepts
+// every term.
+func newTermsFSTIterator(
+	fst *vellum.FST,
+	aut vellum.Automaton,
+	start, end []byte,
+) (*vellum.FSTIterator, error) {
+	if aut == nil {
+		return fst.Iterator(start, end)
+	}
+	return fst.Search(aut, start, end)
+}
+
+// suffixAutomaton is a vellum automaton that matches terms ending with a
+// suffix, it tracks the length of the longest prefix of the suffix that is
+// also a suffix of the bytes seen so far (i.e. the Knuth-Morris-Pratt state).
+type suffixAutomaton struct {
+	suffix []byte
+	// failure[i] is the length of the longest proper prefix of suffix[:i+1]
+	// that is also a suffix of it.
+	failure []int
+}
+
+var _ vellum.Automaton = (*suffixAutomaton)(nil)
+
+func newSuffixAutomaton(suffix []byte) *suffixAutomaton {
+	failure := make([]int, len(suffix))
+	for i, k := 1, 0; i < len(suffix); i++ {
+		for k > 0 && suffix[i] != suffix[k] {
+			k = failure[k-1]
+		}
+		if suffix[i] == suffix[k] {
+			k++
+		}
+		failure[i] = k
+	}
+	return &suffixAutomaton{
+		suffix:  suffix,
+		failure: failure,
+	}
+}
+
+func (a *suffixAutomaton) Start() int {
+	return 0
+}
+
+func (a *suffixAutomaton) IsMatch(state int) bool {
+	return state == len(a.suffix)
+}
+
+func (a *suffixAutomaton) CanMatch(int) bool {
+	// Any term can still be extended to end with the suffix.
+	return true
+}
+
+func (a *suffixAutomaton) WillAlwaysMatch(state int) bool {
+	return len(a.suffix) == 0
+}
+
+func (a *suffixAutomaton) Accept(state int, b byte) int {
+	if len(a.suffix) == 0 {
+		return 0
+	}
+	if state == len(a.suffix) {
+		state = a.failure[state-1]
+	}
+	for state > 0 && a.suffix[state] != b {
+		state = a.failure[state-1]
+	}
+	if a.suffix[state] == b {
+		state++
+	}
+	return state
+}
@@ -33,6 +33,7 @@ import (
 	sgmt "github.com/m3db/m3/src/m3ninx/index/segment"
 	"github.com/m3db/m3/src/m3ninx/index/segment/fst/encoding"
 	"github.com/m3db/m3/src/m3ninx/index/segment/fst/encoding/docs"
+	fstregexp "github.com/m3db/m3/src/m3ninx/index/segment/fst/regexp"
 	"github.com/m3db/m3/src/m3ninx/postings"
 	"github.com/m3db/m3/src/m3ninx/postings/pilosa"
 	"github.com/m3db/m3/src/m3ninx/postings/roaring"
@@ -549,6 +550,62 @@ func (r *fsSegment) matchRegexpNotClosedMaybeFinalizedWithRLock(
 		return nil, errReaderNilRegexp
 	}
 
+	return r.matchTermsNotClosedMaybeFinalizedWithRLock(field, re,
+		compiled.PrefixBegin, compiled.PrefixEnd, nil)
+}
+
+func (r *fsSegment) matchPrefixNotClosedMaybeFinalizedWithRLock(
+	field []byte,
+	prefix []byte,
+) (postings.List, error) {
+	if r.finalized {
+		return nil, errReaderFinalized
+	}
+
+	var end []byte
+	if len(prefix) > 0 {
+		end = fstregexp.IncrementBytes(prefix)
+	}
+	return r.matchTermsNotClosedMaybeFinalizedWithRLock(field, nil, prefix, end, nil)
+}
+
+func (r *fsSegment) matchSuffixNotClosedMaybeFinalizedWithRLock(
+	field []byte,
+	suffix []byte,
+) (postings.List, error) {
+	if r.finalized {
+		return nil, errReaderFinalized
+	}
+
+	return r.matchTermsNotClosedMaybeFinalizedWithRLock(field,
+		newSuffixAutomaton(suffix), nil, nil, nil)
+}
+
+func (r *fsSegment) matchNumericRangeNotClosedMaybeFinalizedWithRLock(
+	field []byte,
+	min, max float64,
+) (postings.List, error) {
+	if r.finalized {
+		return nil, errReaderFinalized
+	}
+
+	// NB: terms are ordered lexicographically rather than numerically so every
+	// term of the field needs to be visited.
+	return r.matchTermsNotClosedMaybeFinalizedWithRLock(field, nil, nil, nil,
+		func(term []byte) bool {
+			return index.NumericTermInRange(term, min, max)
+		})
+}
+
+// matchTermsNotClosedMaybeFinalizedWithRLock returns the union of the postings
+// lists of the terms of the field within [start, end) accepted by the automaton
+// and the filter, a nil automaton or filter accepts every term.
+func (r *fsSegment) matchTermsNotClosedMaybeFinalizedWithRLock(
+	field []byte,
+	aut vellum.Automaton,
+	start, end []byte,
+	filter func(term []byte) bool,
+) (postings.List, error) {
 	termsFST, exists, err := r.retrieveTermsFSTWithRLock(field)
 	if err != nil {
 		return nil, err
@@ -561,7 +618,7 @@ func (r *fsSegment) matchRegexpNotClosedMaybeFinalizedWithRLock(
 
 	var (
 		fstCloser     = x.NewSafeCloser(termsFST)
-		iter, iterErr = termsFST.Search(re, compiled.PrefixBegin, compiled.PrefixEnd)
+		iter, iterErr = newTermsFSTIterator(termsFST, aut, start, end)
 		iterCloser    = x.NewSafeCloser(iter)
 		// NB(prateek): way quicker to union the PLs together at the end, rathen than one at a time.
 		pls []postings.List // TODO: pool this slice allocation
@@ -580,7 +637,12 @@ func (r *fsSegment) matchRegexpNotClosedMaybeFinalizedWithRLock(
 			return nil, iterErr
 		}
 
-		_, postingsOffset := iter.Current()
+		term, postingsOffset := iter.Current()
+		if filter != nil && !filter(term) {
+			iterErr = iter.Next()
+			continue
+		}
+
 		nextPl, err := r.retrievePostingsListWithRLock(postingsOffset)
 		if err != nil {
 			return nil, err
@@ -986,6 +1048,42 @@ func (sr *fsSegmentReader) MatchRegexp(
 	return pl, err
 }
 
+func (sr *fsSegmentReader) MatchPrefix(field, prefix []byte) (postings.List, error) {
+	if sr.closed {
+		return nil, errReaderClosed
+	}
+	// NB(r): We are allowed to call match field after Close called on
+	// the segment but not after it is finalized.
+	sr.fsSegment.RLock()
+	pl, err := sr.fsSegment.matchPrefixNotClosedMaybeFinalizedWithRLock(field, prefix)
+	sr.fsSegment.RUnlock()
+	return pl, err
+}
+
+func (sr *fsSegmentReader) MatchSuffix(field, suffix []byte) (postings.List, error) {
+	if sr.closed {
+		return nil, errReaderClosed
+	}
+	// NB(r): We are allowed to call match field after Close called on
+	// the segment but not after it is finalized.
+	sr.fsSegment.RLock()
+	pl, err := sr.fsSegment.matchSuffixNotClosedMaybeFinalizedWithRLock(field, suffix)
+	sr.fsSegment.RUnlock()
+	return pl, err
+}
+
+func (sr *fsSegmentReader) MatchNumericRange(field []byte, min, max float64) (postings.List, error) {
+	if sr.closed {
+		return nil, errReaderClosed
+	}
+	// NB(r): We are allowed to call match field after Close called on
+	// the segment but not after it is finalized.
+	sr.fsSegment.RLock()
+	pl, err := sr.fsSegment.matchNumericRangeNotClosedMaybeFinalizedWithRLock(field, min, max)
+	sr.fsSegment.RUnlock()
+	return pl, err
+}
+
 func (sr *fsSegmentReader) MatchAll() (postings.List, error) {
 	if sr.closed {
 		return nil, errReaderClosed
@@ -23,6 +23,7 @@ package fst
 import (
 	"bytes"
 	"fmt"
+	"math"
 	"sort"
 	"sync"
 	"testing"
@@ -455,6 +456,111 @@ func TestPostingsListRegexAll(t *testing.T) {
 	}
 }
 
+func TestPostingsListEqualForMatchPrefixSuffix(t *testing.T) {
+	for _, test := range testDocuments {
+		t.Run(test.name, func(t *testing.T) {
+			for _, tc := range newTestCases(t, test.docs) {
+				t.Run(tc.name, func(t *testing.T) {
+					expSeg, obsSeg := tc.expected, tc.observed
+					expReader, err := expSeg.Reader()
+					require.NoError(t, err)
+					obsReader, err := obsSeg.Reader()
+					require.NoError(t, err)
+
+					fieldsIter, err := expSeg.FieldsIterable().Fields()
+					require.NoError(t, err)
+					for _, f := range toSlice(t, fieldsIter) {
+						termsIter, err := expSeg.TermsIterable().Terms(f)
+						require.NoError(t, err)
+						for term := range toTermPostings(t, termsIter) {
+							prefix, suffix := []byte(term[:len(term)/2]), []byte(term[len(term)/2:])
+
+							expPl, err := expReader.MatchPrefix(f, prefix)
+							require.NoError(t, err)
+							obsPl, err := obsReader.MatchPrefix(f, prefix)
+							require.NoError(t, err)
+							require.True(t, expPl.Equal(obsPl),
+								fmt.Sprintf("prefix %s:%s - [%v] != [%v]", string(f), prefix, pprintIter(expPl), pprintIter(obsPl)))
+
+							expPl, err = expReader.MatchSuffix(f, suffix)
+							require.NoError(t, err)
+							obsPl, err = obsReader.MatchSuffix(f, suffix)
+							require.NoError(t, err)
+							require.True(t, expPl.Equal(obsPl),
+								fmt.Sprintf("suffix %s:%s - [%v] != [%v]", string(f), suffix, pprintIter(expPl), pprintIter(obsPl)))
+						}
+					}
+				})
+			}
+		})
+	}
+}
+
+func TestPostingsListEqualForMatchNumericRange(t *testing.T) {
+	ranges := []struct {
+		min, max float64
+	}{
+		{min: 0, max: 0},
+		{min: 0, max: 10},
+		{min: -1, max: 1e6},
+		{min: math.Inf(-1), max: math.Inf(1)},
+	}
+	for _, test := range testDocuments {
+		t.Run(test.name, func(t *testing.T) {
+			for _, tc := range newTestCases(t, test.docs) {
+				t.Run(tc.name, func(t *testing.T) {
+					expSeg, obsSeg := tc.expected, tc.observed
+					expReader, err := expSeg.Reader()
+					require.NoError(t, err)
+					obsReader, err := obsSeg.Reader()
+					require.NoError(t, err)
+
+					fieldsIter, err := expSeg.FieldsIterable().Fields()
+					require.NoError(t, err)
+					for _, f := range toSlice(t, fieldsIter) {
+						for _, r := range ranges {
+							expPl, err := expReader.MatchNumericRange(f, r.min, r.max)
+							require.NoError(t, err)
+							obsPl, err := obsReader.MatchNumericRange(f, r.min, r.max)
+							require.NoError(t, err)
+							require.True(t, expPl.Equal(obsPl),
+								fmt.Sprintf("%s:[%v,%v] - [%v] != [%v]", string(f), r.min, r.max, pprintIter(expPl), pprintIter(obsPl)))
+						}
+					}
+				})
+			}
+		})
+	}
+}
+
+func TestSegmentReaderMatchPrefixSuffix(t *testing.T) {
+	_, fstSeg := newTestSegments(t, fewTestDocuments)
+	reader, err := fstSeg.Reader()
+	require.NoError(t, err)
+
+	pl, err := reader.MatchPrefix([]byte("fruit"), []byte("pine"))
+	require.NoError(t, err)
+	require.Equal(t, 1, pl.Len())
+
+	pl, err = reader.MatchSuffix([]byte("fruit"), []byte("apple"))
+	require.NoError(t, err)
+	require.Equal(t, 2, pl.Len())
+
+	pl, err = reader.MatchSuffix([]byte("fruit"), []byte("nana"))
+	require.NoError(t, err)
+	require.Equal(t, 1, pl.Len())
+
+	pl, err = reader.MatchPrefix([]byte("fruit"), []byte("cherry"))
+	require.NoError(t, err)
+	require.Equal(t, 0, pl.Len())
+
+	pl, err = reader.MatchNumericRange([]byte("fruit"), math.Inf(-1), math.Inf(1))
+	require.NoError(t, err)
+	require.Equal(t, 0, pl.Len())
+
+	require.NoError(t, reader.Close())
+}
+
 func TestSegmentDocs(t *testing.T) {
 	for _, test := range testDocuments {
 		t.Run(test.name, func(t *testing.T) {
@@ -122,3 +122,26 @@ func (m *concurrentPostingsMap) GetRegex(re *regexp.Regexp) (postings.List, bool
 	}
 	return pl, true
 }
+
+// GetMatching returns the union of the postings lists of the terms accepted
+// by the match function.
+func (m *concurrentPostingsMap) GetMatching(match func(term []byte) bool) (postings.List, bool) {
+	var pl postings.MutableList
+
+	m.RLock()
+	for _, mapEntry := range m.postingsMap.Iter() {
+		if match(mapEntry.Key()) {
+			if pl == nil {
+				pl = mapEntry.Value().Clone()
+			} else {
+				pl.Union(mapEntry.Value())
+			}
+		}
+	}
+	m.RUnlock()
+
+	if pl == nil {
+		return nil, false
+	}
+	return pl, true
+}
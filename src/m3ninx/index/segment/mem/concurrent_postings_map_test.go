@@ -58,6 +58,19 @@ func TestConcurrentPostingsMap(t *testing.T) {
 	re = regexp.MustCompile("abc.*")
 	_, ok = pm.GetRegex(re)
 	require.False(t, ok)
+
+	pl, ok = pm.GetMatching(func(term []byte) bool {
+		return term[0] == 'f'
+	})
+	require.True(t, ok)
+	require.Equal(t, 2, pl.Len())
+	require.True(t, pl.Contains(1))
+	require.True(t, pl.Contains(3))
+
+	_, ok = pm.GetMatching(func(term []byte) bool {
+		return false
+	})
+	require.False(t, ok)
 }
 
 func TestConcurrentPostingsMapKeys(t *testing.T) {
@@ -21,6 +21,7 @@
 package mem
 
 import (
+	"bytes"
 	"errors"
 	"sync"
 
@@ -113,6 +114,36 @@ func (r *reader) MatchRegexp(field []byte, compiled index.CompiledRegex) (postin
 	return r.segment.matchRegexp(field, compileRE)
 }
 
+func (r *reader) MatchPrefix(field, prefix []byte) (postings.List, error) {
+	return r.matchTerms(field, func(term []byte) bool {
+		return bytes.HasPrefix(term, prefix)
+	})
+}
+
+func (r *reader) MatchSuffix(field, suffix []byte) (postings.List, error) {
+	return r.matchTerms(field, func(term []byte) bool {
+		return bytes.HasSuffix(term, suffix)
+	})
+}
+
+func (r *reader) MatchNumericRange(field []byte, min, max float64) (postings.List, error) {
+	return r.matchTerms(field, func(term []byte) bool {
+		return index.NumericTermInRange(term, min, max)
+	})
+}
+
+func (r *reader) matchTerms(field []byte, match func(term []byte) bool) (postings.List, error) {
+	r.RLock()
+	defer r.RUnlock()
+	if r.closed {
+		return nil, errSegmentReaderClosed
+	}
+
+	// As with MatchTerm, the reader can return IDs in the postings list which
+	// are greater than its limit and only filters them when fetching documents.
+	return r.segment.matchTerms(field, match)
+}
+
 func (r *reader) MatchAll() (postings.List, error) {
 	r.RLock()
 	defer r.RUnlock()
@@ -85,6 +85,44 @@ func TestReaderMatchRegex(t *testing.T) {
 	require.NoError(t, reader.Close())
 }
 
+func TestReaderMatchPrefixSuffixNumericRange(t *testing.T) {
+	segment, err := NewSegment(testOptions)
+	require.NoError(t, err)
+
+	for _, value := range []string{"200", "404", "500", "503", "unknown"} {
+		_, err := segment.Insert(doc.Metadata{
+			Fields: []doc.Field{
+				{Name: []byte("status"), Value: []byte(value)},
+			},
+		})
+		require.NoError(t, err)
+	}
+
+	reader, err := segment.Reader()
+	require.NoError(t, err)
+
+	pl, err := reader.MatchPrefix([]byte("status"), []byte("50"))
+	require.NoError(t, err)
+	require.Equal(t, 2, pl.Len())
+
+	pl, err = reader.MatchSuffix([]byte("status"), []byte("00"))
+	require.NoError(t, err)
+	require.Equal(t, 2, pl.Len())
+
+	pl, err = reader.MatchNumericRange([]byte("status"), 400, 599)
+	require.NoError(t, err)
+	require.Equal(t, 3, pl.Len())
+
+	pl, err = reader.MatchNumericRange([]byte("code"), 400, 599)
+	require.NoError(t, err)
+	require.Equal(t, 0, pl.Len())
+
+	require.NoError(t, reader.Close())
+
+	_, err = reader.MatchPrefix([]byte("status"), []byte("50"))
+	require.Error(t, err)
+}
+
 func TestReaderMatchAll(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
@@ -396,6 +396,19 @@ func (s *memSegment) matchRegexp(field []byte, compiled *re.Regexp) (postings.Li
 	return s.termsDict.MatchRegexp(field, compiled), nil
 }
 
+func (s *memSegment) matchTerms(
+	field []byte,
+	match func(term []byte) bool,
+) (postings.List, error) {
+	s.state.RLock()
+	defer s.state.RUnlock()
+	if s.state.closed {
+		return nil, segment.ErrClosed
+	}
+
+	return s.termsDict.MatchTerms(field, match), nil
+}
+
 func (s *memSegment) getDoc(id postings.ID) (doc.Metadata, error) {
 	s.state.RLock()
 	defer s.state.RUnlock()
@@ -316,6 +316,36 @@ func (mr *MockReaderMockRecorder) MatchField(field interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MatchField", reflect.TypeOf((*MockReader)(nil).MatchField), field)
 }
 
+// MatchNumericRange mocks base method.
+func (m *MockReader) MatchNumericRange(field []byte, min, max float64) (postings.List, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MatchNumericRange", field, min, max)
+	ret0, _ := ret[0].(postings.List)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MatchNumericRange indicates an expected call of MatchNumericRange.
+func (mr *MockReaderMockRecorder) MatchNumericRange(field, min, max interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MatchNumericRange", reflect.TypeOf((*MockReader)(nil).MatchNumericRange), field, min, max)
+}
+
+// MatchPrefix mocks base method.
+func (m *MockReader) MatchPrefix(field, prefix []byte) (postings.List, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MatchPrefix", field, prefix)
+	ret0, _ := ret[0].(postings.List)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MatchPrefix indicates an expected call of MatchPrefix.
+func (mr *MockReaderMockRecorder) MatchPrefix(field, prefix interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MatchPrefix", reflect.TypeOf((*MockReader)(nil).MatchPrefix), field, prefix)
+}
+
 // MatchRegexp mocks base method.
 func (m *MockReader) MatchRegexp(field []byte, c index.CompiledRegex) (postings.List, error) {
 	m.ctrl.T.Helper()
@@ -331,6 +361,21 @@ func (mr *MockReaderMockRecorder) MatchRegexp(field, c interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MatchRegexp", reflect.TypeOf((*MockReader)(nil).MatchRegexp), field, c)
 }
 
+// MatchSuffix mocks base method.
+func (m *MockReader) MatchSuffix(field, suffix []byte) (postings.List, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MatchSuffix", field, suffix)
+	ret0, _ := ret[0].(postings.List)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MatchSuffix indicates an expected call of MatchSuffix.
+func (mr *MockReaderMockRecorder) MatchSuffix(field, suffix interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MatchSuffix", reflect.TypeOf((*MockReader)(nil).MatchSuffix), field, suffix)
+}
+
 // MatchTerm mocks base method.
 func (m *MockReader) MatchTerm(field, term []byte) (postings.List, error) {
 	m.ctrl.T.Helper()
@@ -74,6 +74,18 @@ type Readable interface {
 	// regular expression.
 	MatchRegexp(field []byte, c CompiledRegex) (postings.List, error)
 
+	// MatchPrefix returns a postings list over all documents which have a term
+	// for the given field starting with the given prefix.
+	MatchPrefix(field, prefix []byte) (postings.List, error)
+
+	// MatchSuffix returns a postings list over all documents which have a term
+	// for the given field ending with the given suffix.
+	MatchSuffix(field, suffix []byte) (postings.List, error)
+
+	// MatchNumericRange returns a postings list over all documents which have a
+	// term for the given field that is an integer within [min, max].
+	MatchNumericRange(field []byte, min, max float64) (postings.List, error)
+
 	// MatchAll returns a postings list for all documents known to the Reader.
 	MatchAll() (postings.List, error)
 
@@ -64,6 +64,15 @@ func UnmarshalProto(q *querypb.Query) (search.Query, error) {
 	case *querypb.Query_Regexp:
 		return NewRegexpQuery(q.Regexp.Field, q.Regexp.Regexp)
 
+	case *querypb.Query_Prefix:
+		return NewPrefixQuery(q.Prefix.Field, q.Prefix.Prefix), nil
+
+	case *querypb.Query_Suffix:
+		return NewSuffixQuery(q.Suffix.Field, q.Suffix.Suffix), nil
+
+	case *querypb.Query_NumericRange:
+		return NewNumericRangeQuery(q.NumericRange.Field, q.NumericRange.Min, q.NumericRange.Max), nil
+
 	case *querypb.Query_Negation:
 		inner, err := UnmarshalProto(q.Negation.Query)
 		if err != nil {
@@ -0,0 +1,104 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package query
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+
+	"github.com/m3db/m3/src/m3ninx/generated/proto/querypb"
+	"github.com/m3db/m3/src/m3ninx/search"
+	"github.com/m3db/m3/src/m3ninx/search/searcher"
+)
+
+// NumericRangeQuery finds documents which have a term for the given field that
+// is an integer within the inclusive range [min, max].
+type NumericRangeQuery struct {
+	str   string
+	field []byte
+	min   float64
+	max   float64
+}
+
+// NewNumericRangeQuery constructs a new NumericRangeQuery for the given field
+// and inclusive range.
+func NewNumericRangeQuery(field []byte, min, max float64) search.Query {
+	q := &NumericRangeQuery{
+		field: field,
+		min:   min,
+		max:   max,
+	}
+	// NB(r): Calculate string value up front so
+	// not allocated every time String() is called to determine
+	// the cache key.
+	q.str = q.string()
+	return q
+}
+
+// Searcher returns a searcher over the provided readers.
+func (q *NumericRangeQuery) Searcher() (search.Searcher, error) {
+	return searcher.NewNumericRangeSearcher(q.field, q.min, q.max), nil
+}
+
+// Equal reports whether q is equivalent to o.
+func (q *NumericRangeQuery) Equal(o search.Query) bool {
+	o, ok := singular(o)
+	if !ok {
+		return false
+	}
+
+	inner, ok := o.(*NumericRangeQuery)
+	if !ok {
+		return false
+	}
+
+	return bytes.Equal(q.field, inner.field) && q.min == inner.min && q.max == inner.max
+}
+
+// ToProto returns the Protobuf query struct corresponding to the numeric range query.
+func (q *NumericRangeQuery) ToProto() *querypb.Query {
+	numericRange := querypb.NumericRangeQuery{
+		Field: q.field,
+		Min:   q.min,
+		Max:   q.max,
+	}
+
+	return &querypb.Query{
+		Query: &querypb.Query_NumericRange{NumericRange: &numericRange},
+	}
+}
+
+func (q *NumericRangeQuery) String() string {
+	return q.str
+}
+
+func (q *NumericRangeQuery) string() string {
+	var str strings.Builder
+	str.WriteString("numeric_range(")
+	str.Write(q.field)
+	str.WriteRune(',')
+	str.WriteString(strconv.FormatFloat(q.min, 'g', -1, 64))
+	str.WriteRune(',')
+	str.WriteString(strconv.FormatFloat(q.max, 'g', -1, 64))
+	str.WriteRune(')')
+	return str.String()
+}
@@ -22,6 +22,7 @@ package query
 
 import (
 	"bytes"
+	"regexp/syntax"
 	"strings"
 
 	"github.com/m3db/m3/src/m3ninx/generated/proto/querypb"
@@ -36,6 +37,9 @@ type RegexpQuery struct {
 	field    []byte
 	regexp   []byte
 	compiled index.CompiledRegex
+
+	// rewrite is an equivalent query which is cheaper to evaluate, if any.
+	rewrite search.Query
 }
 
 // NewRegexpQuery constructs a new query for the given regular expression.
@@ -49,6 +53,7 @@ func NewRegexpQuery(field, regexp []byte) (search.Query, error) {
 		field:    field,
 		regexp:   regexp,
 		compiled: compiled,
+		rewrite:  rewriteRegexp(field, regexp),
 	}
 	// NB(r): Calculate string value up front so
 	// not allocated every time String() is called to determine
@@ -68,6 +73,9 @@ func MustCreateRegexpQuery(field, regexp []byte) search.Query {
 
 // Searcher returns a searcher over the provided readers.
 func (q *RegexpQuery) Searcher() (search.Searcher, error) {
+	if q.rewrite != nil {
+		return q.rewrite.Searcher()
+	}
 	return searcher.NewRegexpSearcher(q.field, q.compiled), nil
 }
 
@@ -111,3 +119,160 @@ func (q *RegexpQuery) string() string {
 	str.WriteRune(')')
 	return str.String()
 }
+
+// maxRewriteTerms is the maximum number of terms a regular expression matching
+// a finite set of terms is rewritten to.
+const maxRewriteTerms = 32
+
+// rewriteRegexp returns a query equivalent to the regular expression which does
+// not need to evaluate an automaton over the terms of the field, or nil if there
+// is none.
+// NB: as with the segments' MatchField, `.` is treated as matching any value.
+func rewriteRegexp(field, regexp []byte) search.Query {
+	re, err := syntax.Parse(string(regexp), syntax.Perl)
+	if err != nil {
+		return nil
+	}
+
+	switch {
+	case IsDotStar(re):
+		return NewFieldQuery(field)
+	case isDotPlus(re):
+		return NewConjunctionQuery([]search.Query{
+			NewFieldQuery(field),
+			NewNegationQuery(NewTermQuery(field, nil)),
+		})
+	}
+
+	if re.Op == syntax.OpConcat && len(re.Sub) == 2 {
+		first, second := re.Sub[0], re.Sub[1]
+		if IsLiteral(first) && IsDotStar(second) {
+			return NewPrefixQuery(field, []byte(string(first.Rune)))
+		}
+		if IsDotStar(first) && IsLiteral(second) {
+			return NewSuffixQuery(field, []byte(string(second.Rune)))
+		}
+	}
+
+	terms, ok := finiteTerms(re, maxRewriteTerms)
+	if !ok {
+		return nil
+	}
+	if len(terms) == 1 {
+		return NewTermQuery(field, []byte(terms[0]))
+	}
+	qs := make([]search.Query, 0, len(terms))
+	for _, term := range terms {
+		qs = append(qs, NewTermQuery(field, []byte(term)))
+	}
+	return NewDisjunctionQuery(qs)
+}
+
+// IsLiteral returns whether the parsed regular expression is a case sensitive
+// literal string.
+func IsLiteral(re *syntax.Regexp) bool {
+	return re.Op == syntax.OpLiteral && re.Flags&syntax.FoldCase == 0
+}
+
+func isAnyChar(re *syntax.Regexp) bool {
+	return re.Op == syntax.OpAnyChar || re.Op == syntax.OpAnyCharNotNL
+}
+
+// IsDotStar returns whether the parsed regular expression is `.*`, `.` is
+// treated as matching any character including newlines.
+func IsDotStar(re *syntax.Regexp) bool {
+	return re.Op == syntax.OpStar && isAnyChar(re.Sub[0])
+}
+
+func isDotPlus(re *syntax.Regexp) bool {
+	return re.Op == syntax.OpPlus && isAnyChar(re.Sub[0])
+}
+
+// finiteTerms returns the terms matched by a regular expression if it matches
+// at most limit terms, e.g. `foo|ba[rz]`.
+func finiteTerms(re *syntax.Regexp, limit int) ([]string, bool) {
+	switch re.Op {
+	case syntax.OpEmptyMatch:
+		return []string{""}, true
+
+	case syntax.OpLiteral:
+		if re.Flags&syntax.FoldCase != 0 {
+			return nil, false
+		}
+		return []string{string(re.Rune)}, true
+
+	case syntax.OpCharClass:
+		var terms []string
+		for i := 0; i+1 < len(re.Rune); i += 2 {
+			for r := re.Rune[i]; r <= re.Rune[i+1]; r++ {
+				if len(terms) == limit {
+					return nil, false
+				}
+				terms = append(terms, string(r))
+			}
+		}
+		return terms, true
+
+	case syntax.OpCapture:
+		return finiteTerms(re.Sub[0], limit)
+
+	case syntax.OpQuest:
+		terms, ok := finiteTerms(re.Sub[0], limit)
+		if !ok {
+			return nil, false
+		}
+		return appendUniqueTerms([]string{""}, terms, limit)
+
+	case syntax.OpAlternate:
+		var terms []string
+		for _, sub := range re.Sub {
+			subTerms, ok := finiteTerms(sub, limit)
+			if !ok {
+				return nil, false
+			}
+			if terms, ok = appendUniqueTerms(terms, subTerms, limit); !ok {
+				return nil, false
+			}
+		}
+		return terms, true
+
+	case syntax.OpConcat:
+		terms := []string{""}
+		for _, sub := range re.Sub {
+			subTerms, ok := finiteTerms(sub, limit)
+			if !ok || len(terms)*len(subTerms) > limit {
+				return nil, false
+			}
+			product := make([]string, 0, len(terms)*len(subTerms))
+			for _, prefix := range terms {
+				for _, suffix := range subTerms {
+					product = append(product, prefix+suffix)
+				}
+			}
+			terms = product
+		}
+		return appendUniqueTerms(nil, terms, limit)
+	}
+
+	return nil, false
+}
+
+func appendUniqueTerms(terms, add []string, limit int) ([]string, bool) {
+	for _, term := range add {
+		exists := false
+		for _, existing := range terms {
+			if existing == term {
+				exists = true
+				break
+			}
+		}
+		if exists {
+			continue
+		}
+		if len(terms) == limit {
+			return nil, false
+		}
+		terms = append(terms, term)
+	}
+	return terms, true
+}
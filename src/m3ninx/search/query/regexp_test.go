@@ -112,3 +112,41 @@ func TestRegexpQueryEqual(t *testing.T) {
 		})
 	}
 }
+
+func TestRegexpQueryRewrite(t *testing.T) {
+	tests := []struct {
+		regexp   string
+		expected string
+	}{
+		{regexp: ".*", expected: "field(job)"},
+		{regexp: ".+", expected: "conjunction(field(job),negation(term(job,)))"},
+		{regexp: "api", expected: "term(job,api)"},
+		{regexp: "", expected: "term(job,)"},
+		{regexp: "api|db", expected: "disjunction(term(job,api), term(job,db))"},
+		{regexp: "(api|db)-[12]", expected: "disjunction(term(job,api-1), term(job,api-2), term(job,db-1), term(job,db-2))"},
+		{regexp: "api-?", expected: "disjunction(term(job,api), term(job,api-))"},
+		{regexp: "api.*", expected: "prefix(job,api)"},
+		{regexp: ".*api", expected: "suffix(job,api)"},
+		{regexp: "(?i)api"},
+		{regexp: "api.+"},
+		{regexp: ".*api.*"},
+		{regexp: "api-[0-9]+"},
+		{regexp: "[a-z][a-z]"},
+		{regexp: "^api$"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.regexp, func(t *testing.T) {
+			q := MustCreateRegexpQuery([]byte("job"), []byte(test.regexp)).(*RegexpQuery)
+			if test.expected == "" {
+				require.Nil(t, q.rewrite)
+				return
+			}
+			require.NotNil(t, q.rewrite)
+			require.Equal(t, test.expected, q.rewrite.String())
+
+			// The query itself is unchanged.
+			require.Equal(t, "regexp(job,"+test.regexp+")", q.String())
+		})
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package query
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/m3db/m3/src/m3ninx/generated/proto/querypb"
+	"github.com/m3db/m3/src/m3ninx/search"
+	"github.com/m3db/m3/src/m3ninx/search/searcher"
+)
+
+// SuffixQuery finds documents which have a term for the given field that
+// end withs the given suffix.
+type SuffixQuery struct {
+	str    string
+	field  []byte
+	suffix []byte
+}
+
+// NewSuffixQuery constructs a new SuffixQuery for the given field and suffix.
+func NewSuffixQuery(field, suffix []byte) search.Query {
+	q := &SuffixQuery{
+		field:  field,
+		suffix: suffix,
+	}
+	// NB(r): Calculate string value up front so
+	// not allocated every time String() is called to determine
+	// the cache key.
+	q.str = q.string()
+	return q
+}
+
+// Searcher returns a searcher over the provided readers.
+func (q *SuffixQuery) Searcher() (search.Searcher, error) {
+	return searcher.NewSuffixSearcher(q.field, q.suffix), nil
+}
+
+// Equal reports whether q is equivalent to o.
+func (q *SuffixQuery) Equal(o search.Query) bool {
+	o, ok := singular(o)
+	if !ok {
+		return false
+	}
+
+	inner, ok := o.(*SuffixQuery)
+	if !ok {
+		return false
+	}
+
+	return bytes.Equal(q.field, inner.field) && bytes.Equal(q.suffix, inner.suffix)
+}
+
+// ToProto returns the Protobuf query struct corresponding to the suffix query.
+func (q *SuffixQuery) ToProto() *querypb.Query {
+	suffix := querypb.SuffixQuery{
+		Field:  q.field,
+		Suffix: q.suffix,
+	}
+
+	return &querypb.Query{
+		Query: &querypb.Query_Suffix{Suffix: &suffix},
+	}
+}
+
+func (q *SuffixQuery) String() string {
+	return q.str
+}
+
+func (q *SuffixQuery) string() string {
+	var str strings.Builder
+	str.WriteString("suffix(")
+	str.Write(q.field)
+	str.WriteRune(',')
+	str.Write(q.suffix)
+	str.WriteRune(')')
+	return str.String()
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package query
+
+import (
+	"testing"
+
+	"github.com/m3db/m3/src/m3ninx/search"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuffixQueryEqual(t *testing.T) {
+	tests := []struct {
+		name        string
+		left, right search.Query
+		expected    bool
+	}{
+		{
+			name:     "same field and suffix",
+			left:     NewSuffixQuery([]byte("fruit"), []byte("pple")),
+			right:    NewSuffixQuery([]byte("fruit"), []byte("pple")),
+			expected: true,
+		},
+		{
+			name: "singular conjunction query",
+			left: NewSuffixQuery([]byte("fruit"), []byte("pple")),
+			right: NewConjunctionQuery([]search.Query{
+				NewSuffixQuery([]byte("fruit"), []byte("pple")),
+			}),
+			expected: true,
+		},
+		{
+			name:     "different field",
+			left:     NewSuffixQuery([]byte("fruit"), []byte("pple")),
+			right:    NewSuffixQuery([]byte("food"), []byte("pple")),
+			expected: false,
+		},
+		{
+			name:     "different suffix",
+			left:     NewSuffixQuery([]byte("fruit"), []byte("pple")),
+			right:    NewSuffixQuery([]byte("fruit"), []byte("ban")),
+			expected: false,
+		},
+		{
+			name:     "term query",
+			left:     NewSuffixQuery([]byte("fruit"), []byte("pple")),
+			right:    NewTermQuery([]byte("fruit"), []byte("pple")),
+			expected: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.expected, test.left.Equal(test.right))
+		})
+	}
+}
+
+func TestSuffixQueryString(t *testing.T) {
+	q := NewSuffixQuery([]byte("fruit"), []byte("pple"))
+	require.Equal(t, "suffix(fruit,pple)", q.String())
+}
@@ -36,3 +36,7 @@ func NewAllSearcher() search.Searcher {
 func (s *all) Search(r index.Reader) (postings.List, error) {
 	return r.MatchAll()
 }
+
+func (s *all) Cost() search.Cost {
+	return search.CostLookup
+}
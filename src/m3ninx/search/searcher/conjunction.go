@@ -21,8 +21,11 @@
 package searcher
 
 import (
+	"sort"
+
 	"github.com/m3db/m3/src/m3ninx/index"
 	"github.com/m3db/m3/src/m3ninx/postings"
+	"github.com/m3db/m3/src/m3ninx/postings/roaring"
 	"github.com/m3db/m3/src/m3ninx/search"
 )
 
@@ -32,7 +35,8 @@ type conjunctionSearcher struct {
 }
 
 // NewConjunctionSearcher returns a new Searcher which matches documents which match each
-// of the given searchers and none of the negations.
+// of the given searchers and none of the negations. For each segment the searchers are
+// evaluated in order of the number of documents they are estimated to match in it.
 func NewConjunctionSearcher(searchers, negations search.Searchers) (search.Searcher, error) {
 	if len(searchers) == 0 {
 		return nil, errEmptySearchers
@@ -44,15 +48,66 @@ func NewConjunctionSearcher(searchers, negations search.Searchers) (search.Searc
 	}, nil
 }
 
+// plannedSearcher is a searcher of a conjunction with the number of documents
+// it is estimated to match in a segment.
+type plannedSearcher struct {
+	searcher search.Searcher
+	estimate int
+	// postings is the postings list of the searcher if resolved while
+	// estimating.
+	postings postings.List
+}
+
 func (s *conjunctionSearcher) Search(r index.Reader) (postings.List, error) {
-	var pl postings.MutableList
+	// Lookups are cheap to resolve and their postings lists size them exactly,
+	// all other searchers are sized from the statistics of the segment before
+	// resolving any of their postings lists.
+	planned := make([]plannedSearcher, 0, len(s.searchers))
 	for _, sr := range s.searchers {
-		curr, err := sr.Search(r)
-		if err != nil {
-			return nil, err
+		p := plannedSearcher{searcher: sr}
+		if search.SearcherCost(sr) == search.CostLookup {
+			pl, err := sr.Search(r)
+			if err != nil {
+				return nil, err
+			}
+
+			// We can skip the remaining searchers if any of the lookups is empty.
+			if pl.IsEmpty() {
+				return pl.Clone(), nil
+			}
+			p.postings = pl
+			p.estimate = pl.Len()
+		} else {
+			estimate, err := search.SearcherEstimate(sr, r)
+			if err != nil {
+				return nil, err
+			}
+
+			// Likewise if any of the searchers can not match any documents.
+			if estimate == 0 {
+				return roaring.NewPostingsList(), nil
+			}
+			p.estimate = estimate
+		}
+		planned = append(planned, p)
+	}
+
+	// Take the intersection in order of increasing size.
+	sort.SliceStable(planned, func(i, j int) bool {
+		return planned[i].estimate < planned[j].estimate
+	})
+
+	var pl postings.MutableList
+	for _, p := range planned {
+		curr := p.postings
+		if curr == nil {
+			var err error
+			curr, err = p.searcher.Search(r)
+			if err != nil {
+				return nil, err
+			}
 		}
 
-		// TODO: Sort the iterators so that we take the intersection in order of increasing size.
 		if pl == nil {
 			pl = curr.Clone()
 		} else {
@@ -61,9 +116,10 @@ func (s *conjunctionSearcher) Search(r index.Reader) (postings.List, error) {
 			}
 		}
 
-		// We can break early if the interescted postings list is ever empty.
+		// We can return early if the intersected postings list is ever empty,
+		// which avoids evaluating the remaining searchers altogether.
 		if pl.IsEmpty() {
-			break
+			return pl, nil
 		}
 	}
 
@@ -73,7 +129,6 @@ func (s *conjunctionSearcher) Search(r index.Reader) (postings.List, error) {
 			return nil, err
 		}
 
-		// TODO: Sort the iterators so that we take the set differences in order of decreasing size.
 		if err := pl.Difference(curr); err != nil {
 			return nil, err
 		}
@@ -86,3 +141,36 @@ func (s *conjunctionSearcher) Search(r index.Reader) (postings.List, error) {
 
 	return pl, nil
 }
+
+// Estimate returns the smallest estimate of the searchers since the
+// conjunction can match no more documents than any one of them.
+func (s *conjunctionSearcher) Estimate(r index.Reader) (int, error) {
+	result := search.EstimateUnknown
+	for _, sr := range s.searchers {
+		estimate, err := search.SearcherEstimate(sr, r)
+		if err != nil {
+			return 0, err
+		}
+		if estimate < result {
+			result = estimate
+		}
+	}
+	return result, nil
+}
+
+func (s *conjunctionSearcher) Cost() search.Cost {
+	return maxCost(s.searchers, s.negations)
+}
+
+// maxCost returns the highest cost of the searchers.
+func maxCost(searchers ...search.Searchers) search.Cost {
+	cost := search.CostLookup
+	for _, srs := range searchers {
+		for _, sr := range srs {
+			if c := search.SearcherCost(sr); c > cost {
+				cost = c
+			}
+		}
+	}
+	return cost
+}
@@ -120,3 +120,88 @@ func TestConjunctionSearcherError(t *testing.T) {
 		})
 	}
 }
+
+func TestConjunctionSearcherOrdersByEstimate(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	var (
+		fruit        = []byte("fruit")
+		color        = []byte("color")
+		firstReader  = index.NewMockReader(mockCtrl)
+		secondReader = index.NewMockReader(mockCtrl)
+	)
+
+	compiled, err := index.CompileRegex([]byte("app.*e"))
+	require.NoError(t, err)
+
+	fruitPL := roaring.NewPostingsList()
+	require.NoError(t, fruitPL.AddRange(0, 100))
+	colorPL := roaring.NewPostingsList()
+	require.NoError(t, colorPL.Insert(postings.ID(42)))
+	termPL := roaring.NewPostingsList()
+	require.NoError(t, termPL.AddRange(0, 50))
+	prefixPL := roaring.NewPostingsList()
+	require.NoError(t, prefixPL.Insert(postings.ID(200)))
+
+	gomock.InOrder(
+		// The regexp and prefix are sized by their fields and the term by its
+		// postings list, so the prefix is evaluated first and the regexp is
+		// never evaluated since the intersection is already empty.
+		firstReader.EXPECT().MatchField(fruit).Return(fruitPL, nil),
+		firstReader.EXPECT().MatchField(color).Return(colorPL, nil),
+		firstReader.EXPECT().MatchTerm(fruit, []byte("apple")).Return(termPL, nil),
+		firstReader.EXPECT().MatchPrefix(color, []byte("re")).Return(prefixPL, nil),
+
+		// No searcher is evaluated when the segment has no documents with
+		// the regexp's field.
+		secondReader.EXPECT().MatchField(fruit).Return(roaring.NewPostingsList(), nil),
+	)
+
+	searchers := []search.Searcher{
+		NewRegexpSearcher(fruit, compiled),
+		NewPrefixSearcher(color, []byte("re")),
+		NewTermSearcher(fruit, []byte("apple")),
+	}
+
+	s, err := NewConjunctionSearcher(searchers, nil)
+	require.NoError(t, err)
+	require.Equal(t, search.CostAutomatonScan, search.SearcherCost(s))
+
+	pl, err := s.Search(firstReader)
+	require.NoError(t, err)
+	require.True(t, pl.IsEmpty())
+
+	pl, err = s.Search(secondReader)
+	require.NoError(t, err)
+	require.True(t, pl.IsEmpty())
+}
+
+func TestConjunctionSearcherEstimate(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	var (
+		fruit  = []byte("fruit")
+		reader = index.NewMockReader(mockCtrl)
+	)
+
+	fruitPL := roaring.NewPostingsList()
+	require.NoError(t, fruitPL.AddRange(0, 100))
+	termPL := roaring.NewPostingsList()
+	require.NoError(t, termPL.AddRange(0, 50))
+
+	reader.EXPECT().MatchField(fruit).Return(fruitPL, nil)
+	reader.EXPECT().MatchTerm(fruit, []byte("apple")).Return(termPL, nil)
+
+	s, err := NewConjunctionSearcher([]search.Searcher{
+		NewSuffixSearcher(fruit, []byte("le")),
+		NewTermSearcher(fruit, []byte("apple")),
+		NewAllSearcher(),
+	}, nil)
+	require.NoError(t, err)
+
+	estimate, err := search.SearcherEstimate(s, reader)
+	require.NoError(t, err)
+	require.Equal(t, 50, estimate)
+}
@@ -59,3 +59,24 @@ func (s *disjunctionSearcher) Search(r index.Reader) (postings.List, error) {
 	}
 	return pl, nil
 }
+
+func (s *disjunctionSearcher) Cost() search.Cost {
+	return maxCost(s.searchers)
+}
+
+// Estimate returns the sum of the estimates of the searchers since the
+// disjunction can match no more documents than all of them together.
+func (s *disjunctionSearcher) Estimate(r index.Reader) (int, error) {
+	result := 0
+	for _, sr := range s.searchers {
+		estimate, err := search.SearcherEstimate(sr, r)
+		if err != nil {
+			return 0, err
+		}
+		if estimate > search.EstimateUnknown-result {
+			return search.EstimateUnknown, nil
+		}
+		result += estimate
+	}
+	return result, nil
+}
@@ -116,3 +116,42 @@ func TestDisjunctionSearcherError(t *testing.T) {
 		})
 	}
 }
+
+func TestDisjunctionSearcherEstimate(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	var (
+		fruit  = []byte("fruit")
+		reader = index.NewMockReader(mockCtrl)
+	)
+
+	fruitPL := roaring.NewPostingsList()
+	require.NoError(t, fruitPL.AddRange(0, 100))
+	termPL := roaring.NewPostingsList()
+	require.NoError(t, termPL.AddRange(0, 10))
+
+	reader.EXPECT().MatchTerm(fruit, []byte("apple")).Return(termPL, nil)
+	reader.EXPECT().MatchField(fruit).Return(fruitPL, nil)
+
+	s, err := NewDisjunctionSearcher([]search.Searcher{
+		NewTermSearcher(fruit, []byte("apple")),
+		NewPrefixSearcher(fruit, []byte("ban")),
+	})
+	require.NoError(t, err)
+
+	estimate, err := search.SearcherEstimate(s, reader)
+	require.NoError(t, err)
+	require.Equal(t, 110, estimate)
+
+	// Searchers that can not be estimated make the disjunction unknown.
+	s, err = NewDisjunctionSearcher([]search.Searcher{
+		NewEmptySearcher(),
+		NewAllSearcher(),
+	})
+	require.NoError(t, err)
+
+	estimate, err = search.SearcherEstimate(s, reader)
+	require.NoError(t, err)
+	require.Equal(t, search.EstimateUnknown, estimate)
+}
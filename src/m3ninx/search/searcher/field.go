@@ -40,3 +40,21 @@ func NewFieldSearcher(field []byte) (search.Searcher, error) {
 func (s *fieldSearcher) Search(r index.Reader) (postings.List, error) {
 	return r.MatchField(s.field)
 }
+
+func (s *fieldSearcher) Cost() search.Cost {
+	return search.CostLookup
+}
+
+func (s *fieldSearcher) Estimate(r index.Reader) (int, error) {
+	return fieldEstimate(r, s.field)
+}
+
+// fieldEstimate returns the number of documents with the field, which bounds
+// the documents matched by any of the field's terms.
+func fieldEstimate(r index.Reader, field []byte) (int, error) {
+	pl, err := r.MatchField(field)
+	if err != nil {
+		return 0, err
+	}
+	return pl.Len(), nil
+}
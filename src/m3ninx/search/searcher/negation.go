@@ -64,3 +64,7 @@ func (s *negationSearcher) Search(r index.Reader) (postings.List, error) {
 	}
 	return result, nil
 }
+
+func (s *negationSearcher) Cost() search.Cost {
+	return search.SearcherCost(s.searcher)
+}
@@ -43,3 +43,11 @@ func NewRegexpSearcher(field []byte, compiled index.CompiledRegex) search.Search
 func (s *regexpSearcher) Search(r index.Reader) (postings.List, error) {
 	return r.MatchRegexp(s.field, s.compiled)
 }
+
+func (s *regexpSearcher) Cost() search.Cost {
+	return search.CostAutomatonScan
+}
+
+func (s *regexpSearcher) Estimate(r index.Reader) (int, error) {
+	return fieldEstimate(r, s.field)
+}
@@ -41,3 +41,15 @@ func NewTermSearcher(field, term []byte) search.Searcher {
 func (s *termSearcher) Search(r index.Reader) (postings.List, error) {
 	return r.MatchTerm(s.field, s.term)
 }
+
+func (s *termSearcher) Cost() search.Cost {
+	return search.CostLookup
+}
+
+func (s *termSearcher) Estimate(r index.Reader) (int, error) {
+	pl, err := r.MatchTerm(s.field, s.term)
+	if err != nil {
+		return 0, err
+	}
+	return pl.Len(), nil
+}
@@ -68,3 +68,82 @@ type Searchers []Searcher
 type ReadThroughSegmentSearcher interface {
 	Search(query Query, searcher Searcher) (postings.List, error)
 }
+
+// Cost is the relative cost of evaluating a searcher against any segment, a
+// conjunction resolves the postings lists of its lookups to size them exactly
+// and estimates the size of the rest from segment statistics.
+type Cost int
+
+const (
+	// CostLookup is the cost of searchers which look up postings lists directly
+	// (e.g. terms and fields).
+	CostLookup Cost = iota
+	// CostPrefixScan is the cost of searchers which walk the terms of a field
+	// starting with a prefix.
+	CostPrefixScan
+	// CostAutomatonScan is the cost of searchers which walk the terms of a field
+	// accepted by an automaton (e.g. regular expressions).
+	CostAutomatonScan
+	// CostFullScan is the cost of searchers which walk every term of a field.
+	CostFullScan
+)
+
+func (c Cost) String() string {
+	switch c {
+	case CostLookup:
+		return "lookup"
+	case CostPrefixScan:
+		return "prefix_scan"
+	case CostAutomatonScan:
+		return "automaton_scan"
+	case CostFullScan:
+		return "full_scan"
+	}
+	return fmt.Sprintf("unknown(%d)", int(c))
+}
+
+// MarshalText returns the text encoding of the cost.
+func (c Cost) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// CostedSearcher is a Searcher which reports its cost of evaluation.
+type CostedSearcher interface {
+	Searcher
+
+	// Cost returns the relative cost of evaluating the searcher.
+	Cost() Cost
+}
+
+// SearcherCost returns the cost of evaluating the searcher, searchers that do
+// not report their cost are assumed to walk every term of a field.
+func SearcherCost(s Searcher) Cost {
+	if costed, ok := s.(CostedSearcher); ok {
+		return costed.Cost()
+	}
+	return CostFullScan
+}
+
+// EstimateUnknown is the estimate of searchers which can not estimate the
+// number of documents they match.
+const EstimateUnknown = int(^uint(0) >> 1)
+
+// EstimatingSearcher is a Searcher which can estimate the number of documents
+// it matches in a segment from the statistics of the segment, without
+// resolving its own postings list.
+type EstimatingSearcher interface {
+	Searcher
+
+	// Estimate returns an upper bound of the number of documents the
+	// searcher matches in the segment read by the given Reader.
+	Estimate(index.Reader) (int, error)
+}
+
+// SearcherEstimate returns the estimated number of documents the searcher
+// matches in the segment read by the given Reader.
+func SearcherEstimate(s Searcher, r index.Reader) (int, error) {
+	if estimating, ok := s.(EstimatingSearcher); ok {
+		return estimating.Estimate(r)
+	}
+	return EstimateUnknown, nil
+}
@@ -53,6 +53,10 @@ const (
 	P99
 	P999
 	P9999
+	// Sketch aggregates timer values into a mergeable sketch from which any
+	// quantile can be computed. Its value is the number of values aggregated
+	// and the encoded sketch is emitted as the annotation of the value.
+	Sketch
 
 	nextTypeID = iota
 )
@@ -98,6 +102,7 @@ var (
 		P99:    emptyStruct,
 		P999:   emptyStruct,
 		P9999:  emptyStruct,
+		Sketch: emptyStruct,
 	}
 
 	typeStringMap map[string]Type
@@ -125,6 +130,7 @@ var (
 		P99:    []byte("p99"),
 		P999:   []byte("p999"),
 		P9999:  []byte("p9999"),
+		Sketch: []byte("sketch"),
 	}
 
 	typeQuantileBytes = map[Type][]byte{
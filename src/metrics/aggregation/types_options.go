@@ -151,6 +151,7 @@ var (
 		Count:  []byte("count"),
 		Stdev:  []byte("stdev"),
 		Median: []byte("median"),
+		Sketch: []byte("sketch"),
 	}
 )
 
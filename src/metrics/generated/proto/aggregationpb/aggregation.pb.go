@@ -75,6 +75,7 @@ const (
 	AggregationType_P99     AggregationType = 20
 	AggregationType_P999    AggregationType = 21
 	AggregationType_P9999   AggregationType = 22
+	AggregationType_SKETCH  AggregationType = 23
 )
 
 var AggregationType_name = map[int32]string{
@@ -101,6 +102,7 @@ var AggregationType_name = map[int32]string{
 	20: "P99",
 	21: "P999",
 	22: "P9999",
+	23: "SKETCH",
 }
 var AggregationType_value = map[string]int32{
 	"UNKNOWN": 0,
@@ -126,6 +128,7 @@ var AggregationType_value = map[string]int32{
 	"P99":     20,
 	"P999":    21,
 	"P9999":   22,
+	"SKETCH":  23,
 }
 
 func (x AggregationType) String() string {
@@ -388,25 +391,26 @@ func init() {
 }
 
 var fileDescriptorAggregation = []byte{
-	// 320 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0xd1, 0x3d, 0x4e, 0xc3, 0x30,
-	0x1c, 0x05, 0xf0, 0xba, 0xdf, 0x75, 0x69, 0xfb, 0xc7, 0x7c, 0x88, 0x29, 0x20, 0x26, 0xc4, 0x50,
-	0x1b, 0x42, 0x81, 0x48, 0x2c, 0x81, 0x76, 0xa8, 0x20, 0x6e, 0x21, 0x09, 0x20, 0xb6, 0xa6, 0x89,
-	0x42, 0x86, 0x34, 0x55, 0x1a, 0x06, 0x6e, 0xc1, 0xb1, 0x18, 0x39, 0x02, 0x0a, 0x37, 0xe0, 0x04,
-	0xc8, 0xff, 0x0e, 0x94, 0x99, 0xed, 0xe7, 0xf7, 0x9e, 0x64, 0x4b, 0xa6, 0x32, 0x8c, 0xb2, 0xe7,
-	0x17, 0xaf, 0x3b, 0x4d, 0x62, 0x1e, 0xeb, 0xbe, 0xc7, 0x63, 0x9d, 0x2f, 0xd2, 0x29, 0x8f, 0x83,
-	0x2c, 0x8d, 0xa6, 0x0b, 0x1e, 0x06, 0xb3, 0x20, 0x9d, 0x64, 0x81, 0xcf, 0xe7, 0x69, 0x92, 0x25,
-	0x7c, 0x12, 0x86, 0x69, 0x10, 0x4e, 0xb2, 0x28, 0x99, 0xcd, 0xbd, 0xd5, 0x53, 0x17, 0x7b, 0xd6,
-	0xfa, 0x33, 0xd8, 0xdf, 0xa5, 0x2d, 0xf3, 0x37, 0x18, 0xf6, 0x59, 0x9b, 0x16, 0x23, 0x7f, 0x87,
-	0xec, 0x91, 0x83, 0xf2, 0x5d, 0x31, 0xf2, 0x0f, 0xbf, 0x09, 0xed, 0xac, 0x2c, 0x9c, 0xd7, 0x79,
-	0xc0, 0x9a, 0xb4, 0xe6, 0xca, 0x6b, 0x39, 0x7a, 0x90, 0x50, 0x60, 0x75, 0x5a, 0xbe, 0x31, 0x6d,
-	0x07, 0x08, 0xab, 0xd1, 0x92, 0x35, 0x94, 0x50, 0x44, 0x98, 0x8f, 0x50, 0x52, 0x9d, 0x35, 0x30,
-	0x25, 0x94, 0x19, 0xa5, 0x55, 0x6b, 0xd0, 0x1f, 0x9a, 0x12, 0x2a, 0xac, 0x41, 0x2b, 0x57, 0x23,
-	0x57, 0x3a, 0x50, 0x55, 0x4b, 0xdb, 0xb5, 0xa0, 0xa6, 0x32, 0xdb, 0xb5, 0xec, 0x5b, 0xa8, 0x23,
-	0x9d, 0xfe, 0xe0, 0x1e, 0x1a, 0xaa, 0x1e, 0x1f, 0x09, 0xa0, 0x88, 0x63, 0x01, 0x4d, 0x84, 0x2e,
-	0x60, 0x0d, 0x71, 0x22, 0xa0, 0x85, 0xe8, 0x09, 0x68, 0x23, 0x4e, 0x05, 0x74, 0x10, 0x67, 0x02,
-	0x00, 0x71, 0x2e, 0x60, 0x1d, 0x61, 0x08, 0x60, 0x4b, 0xf4, 0x60, 0x63, 0x09, 0x03, 0x36, 0xd5,
-	0x13, 0xc7, 0x86, 0x61, 0xc0, 0x96, 0xba, 0x57, 0xc9, 0x80, 0xed, 0x4b, 0xf9, 0x9e, 0x6b, 0xe4,
-	0x23, 0xd7, 0xc8, 0x67, 0xae, 0x91, 0xb7, 0x2f, 0xad, 0xf0, 0x74, 0xf1, 0x9f, 0x6f, 0xf0, 0xaa,
-	0x18, 0xea, 0x3f, 0x01, 0x00, 0x00, 0xff, 0xff, 0x82, 0xc4, 0x3c, 0xea, 0xcd, 0x01, 0x00, 0x00,
+	// 324 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0xd1, 0xcb, 0x4e, 0xc2, 0x40,
+	0x18, 0x05, 0x60, 0x86, 0x3b, 0x83, 0xc0, 0xef, 0x78, 0x5d, 0x55, 0xe3, 0xca, 0xb8, 0x60, 0x46,
+	0x2b, 0x6a, 0x13, 0x37, 0x15, 0x9a, 0x48, 0xb0, 0x03, 0xda, 0x56, 0x8d, 0x3b, 0x4a, 0x27, 0xb5,
+	0x8b, 0x52, 0x52, 0xea, 0xc2, 0xb7, 0xf0, 0xb1, 0x5c, 0xfa, 0x08, 0x06, 0xdf, 0xc3, 0x98, 0x19,
+	0x16, 0xe2, 0xda, 0xdd, 0xd7, 0x73, 0x4e, 0xd2, 0x3f, 0x19, 0xcc, 0xc3, 0x28, 0x7b, 0x7e, 0xf1,
+	0xdb, 0x93, 0x24, 0xa6, 0xb1, 0x1e, 0xf8, 0x34, 0xd6, 0xe9, 0x3c, 0x9d, 0xd0, 0x58, 0x64, 0x69,
+	0x34, 0x99, 0xd3, 0x50, 0x4c, 0x45, 0x3a, 0xce, 0x44, 0x40, 0x67, 0x69, 0x92, 0x25, 0x74, 0x1c,
+	0x86, 0xa9, 0x08, 0xc7, 0x59, 0x94, 0x4c, 0x67, 0xfe, 0xea, 0x57, 0x5b, 0xf5, 0xa4, 0xf1, 0x67,
+	0x70, 0xb0, 0x87, 0x1b, 0xe6, 0x6f, 0xd0, 0xef, 0x91, 0x26, 0xce, 0x47, 0xc1, 0x2e, 0xda, 0x47,
+	0x87, 0xc5, 0xbb, 0x7c, 0x14, 0x1c, 0x7d, 0x23, 0xdc, 0x5a, 0x59, 0xb8, 0xaf, 0x33, 0x41, 0xea,
+	0xb8, 0xe2, 0xf1, 0x01, 0x1f, 0x3e, 0x70, 0xc8, 0x91, 0x2a, 0x2e, 0xde, 0x98, 0x8e, 0x0b, 0x88,
+	0x54, 0x70, 0xc1, 0xee, 0x73, 0xc8, 0x2b, 0x98, 0x8f, 0x50, 0x90, 0x9d, 0x6d, 0x99, 0x1c, 0x8a,
+	0x04, 0xe3, 0xb2, 0x6d, 0xf5, 0xfa, 0x26, 0x87, 0x12, 0xa9, 0xe1, 0x52, 0x77, 0xe8, 0x71, 0x17,
+	0xca, 0x72, 0xe9, 0x78, 0x36, 0x54, 0x64, 0xe6, 0x78, 0xb6, 0x73, 0x0b, 0x55, 0x45, 0xb7, 0x67,
+	0xdd, 0x43, 0x4d, 0xd6, 0xa3, 0x63, 0x06, 0x58, 0xe1, 0x84, 0x41, 0x5d, 0x41, 0x67, 0xb0, 0xa6,
+	0x70, 0xca, 0xa0, 0xa1, 0xd0, 0x61, 0xd0, 0x54, 0x38, 0x63, 0xd0, 0x52, 0x38, 0x67, 0x00, 0x0a,
+	0x17, 0x0c, 0xd6, 0x15, 0x0c, 0x06, 0x64, 0x89, 0x0e, 0x6c, 0x2c, 0x61, 0xc0, 0xa6, 0x3c, 0x71,
+	0x64, 0x18, 0x06, 0x6c, 0xc9, 0xff, 0x4a, 0x19, 0xb0, 0x2d, 0xaf, 0x75, 0x06, 0x96, 0xdb, 0xbd,
+	0x86, 0x9d, 0x2b, 0xfe, 0xbe, 0xd0, 0xd0, 0xc7, 0x42, 0x43, 0x9f, 0x0b, 0x0d, 0xbd, 0x7d, 0x69,
+	0xb9, 0xa7, 0xcb, 0xff, 0x3c, 0x89, 0x5f, 0x56, 0xa1, 0xfe, 0x33, 0x00, 0xf4, 0xb6, 0xa5, 0xb8,
+	0xd9, 0x01, 0x00, 0x00,
 }
@@ -60,6 +60,10 @@ var (
 	PromStateSetValue       = []byte("state_set")
 	PromQuantileName        = []byte("quantile")
 
+	PromNativeHistogramEncodedValue = []byte("encoded")
+	PromNativeHistogramBucketValue  = []byte("bucket")
+	PromNativeHistogramSumValue     = []byte("sum")
+
 	M3MetricsPrefix       = []byte("__m3")
 	M3MetricsPrefixString = string(M3MetricsPrefix)
 
@@ -69,6 +73,7 @@ var (
 	M3MetricsDropTimestamp       = []byte(M3MetricsPrefixString + "_drop_timestamp__")
 	M3PromTypeTag                = []byte(M3MetricsPrefixString + "_prom_type__")
 	M3MetricsPromSummary         = []byte(M3MetricsPrefixString + "_prom_summary__")
+	M3PromNativeHistogramTag     = []byte(M3MetricsPrefixString + "_prom_native_histogram__")
 )
 
 func (t Type) String() string {
@@ -186,6 +186,9 @@ func testLocalType(t *testing.T, providedType string, placementExists bool) {
 						"schemaOptions": null,
 						"coldWritesEnabled": false,
 						"extendedOptions": null,
+						"fileSetCompression": "NONE",
+						"secondaryTierAgeNanos": "0",
+						"outOfOrderWindowNanos": "0",
 						"stagingState": {
 							"status": "UNKNOWN"
 						}
@@ -362,6 +365,9 @@ func TestLocalTypeWithNumShards(t *testing.T) {
 						"schemaOptions": null,
 						"coldWritesEnabled": false,
 						"extendedOptions": null,
+						"fileSetCompression": "NONE",
+						"secondaryTierAgeNanos": "0",
+						"outOfOrderWindowNanos": "0",
 						"stagingState": {
 							"status": "UNKNOWN"
 						}
@@ -491,6 +497,9 @@ func TestLocalWithBlockSizeNanos(t *testing.T) {
 						"schemaOptions": null,
 						"coldWritesEnabled": false,
 						"extendedOptions": null,
+						"fileSetCompression": "NONE",
+						"secondaryTierAgeNanos": "0",
+						"outOfOrderWindowNanos": "0",
 						"stagingState": {
 							"status": "UNKNOWN"
 						}
@@ -625,6 +634,9 @@ func TestLocalWithBlockSizeExpectedSeriesDatapointsPerHour(t *testing.T) {
 						"schemaOptions": null,
 						"coldWritesEnabled": false,
 						"extendedOptions": null,
+						"fileSetCompression": "NONE",
+						"secondaryTierAgeNanos": "0",
+						"outOfOrderWindowNanos": "0",
 						"stagingState": {
 							"status": "UNKNOWN"
 						}
@@ -889,6 +901,9 @@ func testClusterTypeHosts(t *testing.T, placementExists bool) {
 						"schemaOptions": null,
 						"coldWritesEnabled": false,
 						"extendedOptions": null,
+						"fileSetCompression": "NONE",
+						"secondaryTierAgeNanos": "0",
+						"outOfOrderWindowNanos": "0",
 						"stagingState": {
 							"status": "UNKNOWN"
 						}
@@ -1046,6 +1061,9 @@ func TestClusterTypeHostsWithIsolationGroup(t *testing.T) {
 						"schemaOptions": null,
 						"coldWritesEnabled": false,
 						"extendedOptions": null,
+						"fileSetCompression": "NONE",
+						"secondaryTierAgeNanos": "0",
+						"outOfOrderWindowNanos": "0",
 						"stagingState": {
 							"status": "UNKNOWN"
 						}
@@ -1265,6 +1283,9 @@ func TestLocalTypeWithAggregatedNamespace(t *testing.T) {
 						"schemaOptions": null,
 						"coldWritesEnabled": false,
 						"extendedOptions": null,
+						"fileSetCompression": "NONE",
+						"secondaryTierAgeNanos": "0",
+						"outOfOrderWindowNanos": "0",
 						"stagingState": {
 							"status": "UNKNOWN"
 						}
@@ -1307,6 +1328,9 @@ func TestLocalTypeWithAggregatedNamespace(t *testing.T) {
 						"schemaOptions": null,
 						"coldWritesEnabled": false,
 						"extendedOptions": null,
+						"fileSetCompression": "NONE",
+						"secondaryTierAgeNanos": "0",
+						"outOfOrderWindowNanos": "0",
 						"stagingState": {
 							"status": "UNKNOWN"
 						}
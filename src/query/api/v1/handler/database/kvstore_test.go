@@ -206,6 +206,51 @@ func TestUpdateQueryLimits(t *testing.T) {
 	}
 }
 
+func TestUpdateTenantQuotas(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	quotas := &kvpb.TenantQuotas{
+		DefaultQuota: &kvpb.TenantQuota{
+			MaxSeriesCreatedPerSecond: 100,
+		},
+		Tenants: []*kvpb.TenantQuota{
+			{
+				Tenant:                        "tenant-a",
+				MaxDatapointsWrittenPerSecond: 1000,
+				MaxRecentlyQueriedSeriesBlocks: &kvpb.QueryLimit{
+					Limit:           10,
+					LookbackSeconds: 15,
+				},
+			},
+		},
+	}
+	quotasJSON, err := json.Marshal(quotas)
+	require.NoError(t, err)
+
+	update := &KeyValueUpdate{
+		Key:    kvconfig.TenantQuotasKey,
+		Value:  json.RawMessage(quotasJSON),
+		Commit: true,
+	}
+
+	storeMock := kv.NewMockStore(ctrl)
+	storeMock.EXPECT().Get(kvconfig.TenantQuotasKey).Return(nil, kv.ErrNotFound)
+	storeMock.EXPECT().Set(kvconfig.TenantQuotasKey, gomock.Any()).
+		DoAndReturn(func(_ string, v *kvpb.TenantQuotas) (int, error) {
+			require.Equal(t, quotas, v)
+			return 1, nil
+		})
+
+	handler := &KeyValueStoreHandler{}
+	r, err := handler.update(zap.NewNop(), storeMock, update)
+	require.NoError(t, err)
+	require.Equal(t, kvconfig.TenantQuotasKey, r.Key)
+	require.Equal(t, json.RawMessage("{}"), r.Old)
+	require.Equal(t, json.RawMessage(quotasJSON), r.New)
+	require.Equal(t, 1, r.Version)
+}
+
 func TestProtoParser(t *testing.T) {
 	handler := &KeyValueStoreHandler{
 		kvStoreProtoParser: func(k string) (protoiface.MessageV1, error) {
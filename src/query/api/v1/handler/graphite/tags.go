@@ -0,0 +1,374 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package graphite
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/handleroptions"
+	"github.com/m3db/m3/src/query/api/v1/options"
+	"github.com/m3db/m3/src/query/api/v1/route"
+	"github.com/m3db/m3/src/query/graphite/graphite"
+	graphitestorage "github.com/m3db/m3/src/query/graphite/storage"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/query/util/json"
+	"github.com/m3db/m3/src/query/util/logging"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	"github.com/m3db/m3/src/x/instrument"
+	xhttp "github.com/m3db/m3/src/x/net/http"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// TagsURL is the url for listing graphite tags.
+	TagsURL = route.Prefix + "/graphite/tags"
+
+	// AutoCompleteTagsURL is the url for auto completing graphite tags.
+	AutoCompleteTagsURL = TagsURL + "/autoComplete/tags"
+
+	// AutoCompleteValuesURL is the url for auto completing graphite tag values.
+	AutoCompleteValuesURL = TagsURL + "/autoComplete/values"
+
+	// defaultAutoCompleteLimit is the default limit of auto complete results,
+	// the same as the graphite default.
+	defaultAutoCompleteLimit = 100
+)
+
+// TagsHTTPMethods are the HTTP methods for the tags handlers.
+var TagsHTTPMethods = []string{http.MethodGet, http.MethodPost}
+
+type tagsHandlerType int
+
+const (
+	tagsHandlerTypeTags tagsHandlerType = iota
+	tagsHandlerTypeAutoCompleteTags
+	tagsHandlerTypeAutoCompleteValues
+)
+
+type graphiteTagsHandler struct {
+	handlerType         tagsHandlerType
+	storage             graphitestorage.Storage
+	searchStorage       storage.Storage
+	fetchOptionsBuilder handleroptions.FetchOptionsBuilder
+	instrumentOpts      instrument.Options
+}
+
+// NewTagsHandler returns a new instance of a handler that lists the tags of
+// graphite series, optionally filtered by a regular expression.
+func NewTagsHandler(opts options.HandlerOptions) http.Handler {
+	return newTagsHandler(opts, tagsHandlerTypeTags)
+}
+
+// NewAutoCompleteTagsHandler returns a new instance of a handler that auto
+// completes the tags of graphite series matching tag expressions.
+func NewAutoCompleteTagsHandler(opts options.HandlerOptions) http.Handler {
+	return newTagsHandler(opts, tagsHandlerTypeAutoCompleteTags)
+}
+
+// NewAutoCompleteValuesHandler returns a new instance of a handler that auto
+// completes the values of a tag of graphite series matching tag expressions.
+func NewAutoCompleteValuesHandler(opts options.HandlerOptions) http.Handler {
+	return newTagsHandler(opts, tagsHandlerTypeAutoCompleteValues)
+}
+
+func newTagsHandler(
+	opts options.HandlerOptions,
+	handlerType tagsHandlerType,
+) http.Handler {
+	wrappedStore := graphitestorage.NewM3WrappedStorage(opts.Storage(),
+		opts.M3DBOptions(), opts.InstrumentOpts(), opts.GraphiteStorageOptions())
+	return &graphiteTagsHandler{
+		handlerType:         handlerType,
+		storage:             wrappedStore,
+		searchStorage:       opts.Storage(),
+		fetchOptionsBuilder: opts.GraphiteFindFetchOptionsBuilder(),
+		instrumentOpts:      opts.InstrumentOpts(),
+	}
+}
+
+type tagsParams struct {
+	from     time.Time
+	until    time.Time
+	filter   *regexp.Regexp
+	prefix   string
+	tag      string
+	exprs    []string
+	matchers models.Matchers
+	limit    int
+}
+
+func (h *graphiteTagsHandler) parseParams(r *http.Request) (tagsParams, error) {
+	from, until, err := parseTimeRange(r)
+	if err != nil {
+		return tagsParams{}, err
+	}
+
+	params := tagsParams{from: from, until: until}
+	if err := r.ParseForm(); err != nil {
+		return tagsParams{}, xerrors.NewInvalidParamsError(err)
+	}
+
+	if h.handlerType != tagsHandlerTypeTags {
+		params.limit = defaultAutoCompleteLimit
+	}
+	if str := r.FormValue("limit"); str != "" {
+		params.limit, err = strconv.Atoi(str)
+		if err != nil || params.limit < 0 {
+			return tagsParams{}, xerrors.NewInvalidParamsError(
+				fmt.Errorf("invalid 'limit': %s", str))
+		}
+	}
+
+	switch h.handlerType {
+	case tagsHandlerTypeTags:
+		if str := r.FormValue("filter"); str != "" {
+			// Graphite filters match the start of the tags.
+			params.filter, err = regexp.Compile("^(?:" + str + ")")
+			if err != nil {
+				return tagsParams{}, xerrors.NewInvalidParamsError(
+					fmt.Errorf("invalid 'filter': %s", str))
+			}
+		}
+	case tagsHandlerTypeAutoCompleteTags:
+		params.prefix = r.FormValue("tagPrefix")
+	case tagsHandlerTypeAutoCompleteValues:
+		params.prefix = r.FormValue("valuePrefix")
+		params.tag = r.FormValue("tag")
+		if params.tag == "" {
+			return tagsParams{}, xerrors.NewInvalidParamsError(
+				fmt.Errorf("missing 'tag'"))
+		}
+	}
+
+	params.exprs = r.Form["expr"]
+	if len(params.exprs) == 0 {
+		// Match all graphite series, which have a __g0__ tag.
+		params.matchers = models.Matchers{{
+			Type:  models.MatchRegexp,
+			Name:  graphite.TagName(0),
+			Value: []byte(graphite.MatchAllPattern),
+		}}
+		return params, nil
+	}
+
+	query := graphite.SeriesByTagQuery(params.exprs)
+	params.matchers, err = graphitestorage.TranslateSeriesByTagQueryToMatchers(query)
+	if err != nil {
+		return tagsParams{}, xerrors.NewInvalidParamsError(
+			fmt.Errorf("invalid 'expr': %v", err))
+	}
+
+	return params, nil
+}
+
+func (h *graphiteTagsHandler) ServeHTTP(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	ctx, opts, err := h.fetchOptionsBuilder.NewFetchOptions(r.Context(), r)
+	if err != nil {
+		xhttp.WriteError(w, err)
+		return
+	}
+
+	logger := logging.WithContext(ctx, h.instrumentOpts)
+	w.Header().Set(xhttp.HeaderContentType, xhttp.ContentTypeJSON)
+
+	params, err := h.parseParams(r)
+	if err != nil {
+		xhttp.WriteError(w, err)
+		return
+	}
+
+	var results []string
+	if h.handlerType == tagsHandlerTypeAutoCompleteValues {
+		results, err = h.completeValues(ctx, params, opts)
+	} else {
+		results, err = h.completeTags(ctx, params, opts)
+	}
+	if err != nil {
+		logger.Error("unable to complete graphite tags", zap.Error(err))
+		xhttp.WriteError(w, err)
+		return
+	}
+
+	sort.Strings(results)
+	if params.limit > 0 && len(results) > params.limit {
+		results = results[:params.limit]
+	}
+
+	jw := json.NewWriter(w)
+	jw.BeginArray()
+	for _, result := range results {
+		if h.handlerType == tagsHandlerTypeTags {
+			jw.BeginObject()
+			jw.BeginObjectField("tag")
+			jw.WriteString(result)
+			jw.EndObject()
+			continue
+		}
+		jw.WriteString(result)
+	}
+	jw.EndArray()
+	if err := jw.Close(); err != nil {
+		logger.Error("unable to render graphite tags results", zap.Error(err))
+	}
+}
+
+func (h *graphiteTagsHandler) completeTags(
+	ctx context.Context,
+	params tagsParams,
+	opts *storage.FetchOptions,
+) ([]string, error) {
+	result, err := h.storage.CompleteTags(ctx, &storage.CompleteTagsQuery{
+		CompleteNameOnly: true,
+		TagMatchers:      params.matchers,
+		Start:            xtime.ToUnixNano(params.from),
+		End:              xtime.ToUnixNano(params.until),
+	}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Tags given in the expressions are not auto completed.
+	exclude := make(map[string]struct{}, len(params.exprs))
+	for _, expr := range params.exprs {
+		if parsed, err := graphite.ParseTagExpression(expr); err == nil {
+			exclude[parsed.Name] = struct{}{}
+		}
+	}
+
+	// Every graphite series has the name tag, which holds the path given
+	// by the path tags.
+	names := []string{graphite.NameTag}
+	for _, tag := range result.CompletedTags {
+		if _, isPathTag := graphite.TagIndex(tag.Name); isPathTag {
+			continue
+		}
+		names = append(names, string(tag.Name))
+	}
+
+	results := make([]string, 0, len(names))
+	for _, name := range names {
+		if _, ok := exclude[name]; ok {
+			continue
+		}
+		if !strings.HasPrefix(name, params.prefix) {
+			continue
+		}
+		if params.filter != nil && !params.filter.MatchString(name) {
+			continue
+		}
+		results = append(results, name)
+	}
+
+	return results, nil
+}
+
+func (h *graphiteTagsHandler) completeValues(
+	ctx context.Context,
+	params tagsParams,
+	opts *storage.FetchOptions,
+) ([]string, error) {
+	if params.tag == graphite.NameTag {
+		return h.completePaths(ctx, params, opts)
+	}
+
+	result, err := h.storage.CompleteTags(ctx, &storage.CompleteTagsQuery{
+		CompleteNameOnly: false,
+		FilterNameTags:   [][]byte{[]byte(params.tag)},
+		TagMatchers:      params.matchers,
+		Start:            xtime.ToUnixNano(params.from),
+		End:              xtime.ToUnixNano(params.until),
+	}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []string
+	for _, tag := range result.CompletedTags {
+		if string(tag.Name) != params.tag {
+			continue
+		}
+		for _, value := range tag.Values {
+			if strings.HasPrefix(string(value), params.prefix) {
+				results = append(results, string(value))
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// completePaths completes the values of the name tag, which are not indexed
+// as a tag but given by the path tags of each series.
+func (h *graphiteTagsHandler) completePaths(
+	ctx context.Context,
+	params tagsParams,
+	opts *storage.FetchOptions,
+) ([]string, error) {
+	result, err := h.searchStorage.SearchSeries(ctx, &storage.FetchQuery{
+		TagMatchers: params.matchers,
+		Start:       params.from,
+		End:         params.until,
+	}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		seen    = make(map[string]struct{}, len(result.Metrics))
+		results = make([]string, 0, len(result.Metrics))
+		parts   []string
+	)
+	for _, metric := range result.Metrics {
+		parts = parts[:0]
+		for _, tag := range metric.Tags.Tags {
+			idx, ok := graphite.TagIndex(tag.Name)
+			if !ok {
+				continue
+			}
+			for len(parts) <= idx {
+				parts = append(parts, "")
+			}
+			parts[idx] = string(tag.Value)
+		}
+
+		path := strings.Join(parts, ".")
+		if _, ok := seen[path]; ok || !strings.HasPrefix(path, params.prefix) {
+			continue
+		}
+		seen[path] = struct{}{}
+		results = append(results, path)
+	}
+
+	return results, nil
+}
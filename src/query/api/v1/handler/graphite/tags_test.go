@@ -0,0 +1,212 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package graphite
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/handleroptions"
+	"github.com/m3db/m3/src/query/api/v1/options"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/query/storage/m3/consolidators"
+	xtest "github.com/m3db/m3/src/x/test"
+)
+
+func newTestTagsHandlerOptions(t *testing.T, store storage.Storage) options.HandlerOptions {
+	builder, err := handleroptions.NewFetchOptionsBuilder(
+		handleroptions.FetchOptionsBuilderOptions{
+			Timeout: 15 * time.Second,
+		})
+	require.NoError(t, err)
+
+	return options.EmptyHandlerOptions().
+		SetGraphiteFindFetchOptionsBuilder(builder).
+		SetStorage(store)
+}
+
+func serveTags(h http.Handler, params url.Values) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/?"+params.Encode(), nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	return w
+}
+
+type completeTagsQueryMatcher struct {
+	completeNameOnly bool
+	filterNameTags   [][]byte
+	matchers         models.Matchers
+}
+
+func (m completeTagsQueryMatcher) String() string {
+	q := storage.CompleteTagsQuery{TagMatchers: m.matchers}
+	return q.String()
+}
+
+func (m completeTagsQueryMatcher) Matches(x interface{}) bool {
+	q, ok := x.(*storage.CompleteTagsQuery)
+	if !ok {
+		return false
+	}
+
+	return q.CompleteNameOnly == m.completeNameOnly &&
+		assert.ObjectsAreEqual(m.filterNameTags, q.FilterNameTags) &&
+		assert.ObjectsAreEqual(m.matchers, q.TagMatchers)
+}
+
+func TestTagsHandler(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	store := storage.NewMockStorage(ctrl)
+	store.EXPECT().
+		CompleteTags(gomock.Any(), completeTagsQueryMatcher{
+			completeNameOnly: true,
+			matchers: models.Matchers{
+				{Type: models.MatchRegexp, Name: b("__g0__"), Value: b(".*")},
+			},
+		}, gomock.Any()).
+		Return(&consolidators.CompleteTagsResult{
+			CompleteNameOnly: true,
+			CompletedTags: []consolidators.CompletedTag{
+				{Name: b("__g0__")},
+				{Name: b("__g1__")},
+				{Name: b("host")},
+				{Name: b("dc")},
+				{Name: b("disk")},
+			},
+		}, nil).Times(2)
+
+	h := NewTagsHandler(newTestTagsHandlerOptions(t, store))
+	w := serveTags(h, url.Values{})
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t,
+		`[{"tag":"dc"},{"tag":"disk"},{"tag":"host"},{"tag":"name"}]`,
+		w.Body.String())
+
+	w = serveTags(h, url.Values{"filter": []string{"d|na"}, "limit": []string{"2"}})
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `[{"tag":"dc"},{"tag":"disk"}]`, w.Body.String())
+
+	w = serveTags(h, url.Values{"filter": []string{"("}})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestAutoCompleteTagsHandler(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	store := storage.NewMockStorage(ctrl)
+	store.EXPECT().
+		CompleteTags(gomock.Any(), completeTagsQueryMatcher{
+			completeNameOnly: true,
+			matchers: models.Matchers{
+				{Type: models.MatchRegexp, Name: b("__g0__"), Value: b(".*")},
+				{Type: models.MatchEqual, Name: b("dc"), Value: b("east")},
+			},
+		}, gomock.Any()).
+		Return(&consolidators.CompleteTagsResult{
+			CompleteNameOnly: true,
+			CompletedTags: []consolidators.CompletedTag{
+				{Name: b("__g0__")},
+				{Name: b("host")},
+				{Name: b("dc")},
+				{Name: b("disk")},
+			},
+		}, nil)
+
+	h := NewAutoCompleteTagsHandler(newTestTagsHandlerOptions(t, store))
+	w := serveTags(h, url.Values{
+		"expr":      []string{"dc=east"},
+		"tagPrefix": []string{"d"},
+	})
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `["disk"]`, w.Body.String())
+
+	w = serveTags(h, url.Values{"expr": []string{"dc!=east"}})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestAutoCompleteValuesHandler(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	store := storage.NewMockStorage(ctrl)
+	store.EXPECT().
+		CompleteTags(gomock.Any(), completeTagsQueryMatcher{
+			filterNameTags: [][]byte{b("host")},
+			matchers: models.Matchers{
+				{Type: models.MatchRegexp, Name: b("__g0__"), Value: b(".*")},
+				{Type: models.MatchEqual, Name: b("dc"), Value: b("east")},
+			},
+		}, gomock.Any()).
+		Return(&consolidators.CompleteTagsResult{
+			CompletedTags: []consolidators.CompletedTag{
+				{Name: b("host"), Values: bs("b2", "a1", "a2")},
+			},
+		}, nil)
+
+	tagOpts := models.NewTagOptions().SetIDSchemeType(models.TypeGraphite)
+	store.EXPECT().
+		SearchSeries(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&storage.SearchResults{
+			Metrics: models.Metrics{
+				{Tags: models.NewTags(2, tagOpts).AddTags([]models.Tag{
+					{Name: b("__g0__"), Value: b("disk")},
+					{Name: b("__g1__"), Value: b("used")},
+					{Name: b("dc"), Value: b("east")},
+				})},
+				{Tags: models.NewTags(2, tagOpts).AddTags([]models.Tag{
+					{Name: b("__g0__"), Value: b("disk")},
+					{Name: b("__g1__"), Value: b("free")},
+				})},
+				{Tags: models.NewTags(2, tagOpts).AddTags([]models.Tag{
+					{Name: b("__g0__"), Value: b("disk")},
+					{Name: b("__g1__"), Value: b("used")},
+					{Name: b("dc"), Value: b("west")},
+				})},
+			},
+		}, nil)
+
+	h := NewAutoCompleteValuesHandler(newTestTagsHandlerOptions(t, store))
+	w := serveTags(h, url.Values{
+		"expr":        []string{"dc=east"},
+		"tag":         []string{"host"},
+		"valuePrefix": []string{"a"},
+	})
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `["a1","a2"]`, w.Body.String())
+
+	w = serveTags(h, url.Values{"tag": []string{"name"}})
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `["disk.free","disk.used"]`, w.Body.String())
+
+	w = serveTags(h, url.Values{})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -148,10 +148,13 @@ func TestNamespaceAddHandler(t *testing.T) {
 							"enabled":        true,
 							"blockSizeNanos": "7200000000000",
 						},
-						"runtimeOptions":    nil,
-						"schemaOptions":     nil,
-						"coldWritesEnabled": false,
-						"extendedOptions":   xtest.NewTestExtendedOptionsJSON("foo"),
+						"runtimeOptions":        nil,
+						"schemaOptions":         nil,
+						"coldWritesEnabled":     false,
+						"extendedOptions":       xtest.NewTestExtendedOptionsJSON("foo"),
+						"fileSetCompression":    "NONE",
+						"secondaryTierAgeNanos": "0",
+						"outOfOrderWindowNanos": "0",
 					},
 				},
 			},
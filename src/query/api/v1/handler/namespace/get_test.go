@@ -139,12 +139,15 @@ func TestNamespaceGetHandler(t *testing.T) {
 							"futureRetentionPeriodNanos":               "0",
 							"retentionPeriodNanos":                     "172800000000000",
 						},
-						"runtimeOptions":    nil,
-						"schemaOptions":     nil,
-						"snapshotEnabled":   true,
-						"stagingState":      xjson.Map{"status": "READY"},
-						"writesToCommitLog": true,
-						"extendedOptions":   xtest.NewTestExtendedOptionsJSON("foo"),
+						"runtimeOptions":        nil,
+						"schemaOptions":         nil,
+						"snapshotEnabled":       true,
+						"stagingState":          xjson.Map{"status": "READY"},
+						"writesToCommitLog":     true,
+						"extendedOptions":       xtest.NewTestExtendedOptionsJSON("foo"),
+						"fileSetCompression":    "NONE",
+						"secondaryTierAgeNanos": "0",
+						"outOfOrderWindowNanos": "0",
 					},
 				},
 			},
@@ -224,12 +227,15 @@ func TestNamespaceGetHandlerWithDebug(t *testing.T) {
 							"futureRetentionPeriodDuration":               "0s",
 							"retentionPeriodDuration":                     "48h0m0s",
 						},
-						"runtimeOptions":    nil,
-						"schemaOptions":     nil,
-						"stagingState":      xjson.Map{"status": "UNKNOWN"},
-						"snapshotEnabled":   true,
-						"writesToCommitLog": true,
-						"extendedOptions":   nil,
+						"runtimeOptions":           nil,
+						"schemaOptions":            nil,
+						"stagingState":             xjson.Map{"status": "UNKNOWN"},
+						"snapshotEnabled":          true,
+						"writesToCommitLog":        true,
+						"extendedOptions":          nil,
+						"fileSetCompression":       "NONE",
+						"secondaryTierAgeDuration": "0s",
+						"outOfOrderWindowDuration": "0s",
 					},
 				},
 			},
@@ -199,10 +199,13 @@ func TestNamespaceUpdateHandler(t *testing.T) {
 							"flushIndexingPerCPUConcurrency": nil,
 							"writeIndexingPerCPUConcurrency": 16,
 						},
-						"schemaOptions":     nil,
-						"stagingState":      xjson.Map{"status": "UNKNOWN"},
-						"coldWritesEnabled": false,
-						"extendedOptions":   xtest.NewTestExtendedOptionsJSON("bar"),
+						"schemaOptions":         nil,
+						"stagingState":          xjson.Map{"status": "UNKNOWN"},
+						"coldWritesEnabled":     false,
+						"extendedOptions":       xtest.NewTestExtendedOptionsJSON("bar"),
+						"fileSetCompression":    "NONE",
+						"secondaryTierAgeNanos": "0",
+						"outOfOrderWindowNanos": "0",
 					},
 				},
 			},
@@ -256,11 +259,14 @@ func TestNamespaceUpdateHandler(t *testing.T) {
 							"enabled":        false,
 							"blockSizeNanos": "7200000000000",
 						},
-						"runtimeOptions":    nil,
-						"schemaOptions":     nil,
-						"stagingState":      xjson.Map{"status": "UNKNOWN"},
-						"coldWritesEnabled": false,
-						"extendedOptions":   xtest.NewTestExtendedOptionsJSON("foo"),
+						"runtimeOptions":        nil,
+						"schemaOptions":         nil,
+						"stagingState":          xjson.Map{"status": "UNKNOWN"},
+						"coldWritesEnabled":     false,
+						"extendedOptions":       xtest.NewTestExtendedOptionsJSON("foo"),
+						"fileSetCompression":    "NONE",
+						"secondaryTierAgeNanos": "0",
+						"outOfOrderWindowNanos": "0",
 					},
 				},
 			},
@@ -0,0 +1,542 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package otlp
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/m3db/m3/src/cmd/services/m3query/config"
+	"github.com/m3db/m3/src/query/generated/proto/prompb"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/query/ts"
+	"github.com/m3db/m3/src/x/clock"
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+const (
+	// defaultDeltaStaleness is the default duration after which an idle
+	// delta to cumulative accumulator is discarded.
+	defaultDeltaStaleness = 10 * time.Minute
+
+	jobLabel          = "job"
+	instanceLabel     = "instance"
+	scopeNameLabel    = "otel_scope_name"
+	scopeVersionLabel = "otel_scope_version"
+	quantileLabel     = "quantile"
+
+	serviceNameAttribute       = "service.name"
+	serviceNamespaceAttribute  = "service.namespace"
+	serviceInstanceIDAttribute = "service.instance.id"
+
+	scopeNameAttribute    = "name"
+	scopeVersionAttribute = "version"
+
+	totalSuffix  = "_total"
+	bucketSuffix = "_bucket"
+	sumSuffix    = "_sum"
+	countSuffix  = "_count"
+)
+
+var (
+	promNameLabel   = []byte("__name__")
+	promBucketLabel = []byte("le")
+	posInf          = strconv.FormatFloat(math.Inf(1), 'f', -1, 64)
+)
+
+// attributePolicy decides which OTLP attributes are promoted to tags.
+type attributePolicy struct {
+	promoteAll bool
+	promote    map[string]struct{}
+	drop       map[string]struct{}
+}
+
+func newAttributePolicy(cfg config.OTLPAttributesConfiguration) attributePolicy {
+	p := attributePolicy{
+		promoteAll: cfg.PromoteAll,
+		promote:    make(map[string]struct{}, len(cfg.Promote)),
+		drop:       make(map[string]struct{}, len(cfg.Drop)),
+	}
+	for _, k := range cfg.Promote {
+		p.promote[k] = struct{}{}
+	}
+	for _, k := range cfg.Drop {
+		p.drop[k] = struct{}{}
+	}
+	return p
+}
+
+func (p attributePolicy) dropped(key string) bool {
+	_, ok := p.drop[key]
+	return ok
+}
+
+func (p attributePolicy) promoted(key string) bool {
+	if p.dropped(key) {
+		return false
+	}
+	if p.promoteAll {
+		return true
+	}
+	_, ok := p.promote[key]
+	return ok
+}
+
+// deltaAccumulator converts delta temporality points into cumulative values
+// so that they can be stored and queried like Prometheus counters. Running
+// totals are held in memory by each coordinator, so every point of a delta
+// series must be sent to the same coordinator.
+type deltaAccumulator struct {
+	sync.Mutex
+
+	staleness time.Duration
+	nowFn     clock.NowFn
+	lastSweep time.Time
+	series    map[string]*deltaState
+}
+
+type deltaState struct {
+	value    float64
+	end      uint64
+	lastSeen time.Time
+}
+
+func newDeltaAccumulator(staleness time.Duration, nowFn clock.NowFn) *deltaAccumulator {
+	if staleness <= 0 {
+		staleness = defaultDeltaStaleness
+	}
+	return &deltaAccumulator{
+		staleness: staleness,
+		nowFn:     nowFn,
+		lastSweep: nowFn(),
+		series:    make(map[string]*deltaState),
+	}
+}
+
+// add adds the delta of the point covering (start, end] to the running total
+// of the series and returns the new cumulative value, or false if the point
+// ends no later than the last point added and has already been accounted for.
+// The running total restarts from zero when the point does not start where
+// the last point added ended.
+func (a *deltaAccumulator) add(
+	id string,
+	start, end uint64,
+	delta float64,
+) (float64, bool) {
+	a.Lock()
+	defer a.Unlock()
+
+	now := a.nowFn()
+	if now.Sub(a.lastSweep) >= a.staleness {
+		for k, s := range a.series {
+			if now.Sub(s.lastSeen) >= a.staleness {
+				delete(a.series, k)
+			}
+		}
+		a.lastSweep = now
+	}
+
+	s, ok := a.series[id]
+	switch {
+	case !ok:
+		s = &deltaState{}
+		a.series[id] = s
+	case end <= s.end:
+		// NB: the point is a retry of, or older than, the last point added.
+		s.lastSeen = now
+		return 0, false
+	case start != s.end:
+		// NB: points in between were missed, e.g. they were sent to another
+		// coordinator or the exporter restarted, so the total is unknown.
+		s.value = 0
+	}
+	s.value += delta
+	s.end = end
+	s.lastSeen = now
+	return s.value, true
+}
+
+// series is a single converted series ready to be written.
+type series struct {
+	tags       models.Tags
+	datapoints ts.Datapoints
+	attributes ts.SeriesAttributes
+}
+
+// converter converts OTLP metrics into M3 series.
+type converter struct {
+	tagOpts    models.TagOptions
+	resource   attributePolicy
+	scope      attributePolicy
+	deltas     *deltaAccumulator
+	series     []series
+	seriesByID map[string]int
+}
+
+func newConverter(
+	tagOpts models.TagOptions,
+	resource attributePolicy,
+	scope attributePolicy,
+	deltas *deltaAccumulator,
+) *converter {
+	return &converter{
+		tagOpts:    tagOpts,
+		resource:   resource,
+		scope:      scope,
+		deltas:     deltas,
+		seriesByID: make(map[string]int),
+	}
+}
+
+// labelSet is the set of labels common to all points of a metric.
+type labelSet map[string]string
+
+func (c *converter) resourceLabels(
+	resourceAttrs []keyValue,
+	scope instrumentationScope,
+) labelSet {
+	labels := make(labelSet, len(resourceAttrs)+2)
+
+	var serviceName, serviceNamespace string
+	for _, kv := range resourceAttrs {
+		key, value := kv.key, kv.value
+		switch key {
+		case serviceNameAttribute:
+			serviceName = value
+		case serviceNamespaceAttribute:
+			serviceNamespace = value
+		case serviceInstanceIDAttribute:
+			if !c.resource.dropped(key) {
+				labels[instanceLabel] = value
+			}
+		}
+		if c.resource.promoted(key) {
+			labels[sanitizeLabelName(key)] = value
+		}
+	}
+
+	// The service name is always mapped to the job label, following the
+	// Prometheus OTLP translation conventions.
+	if serviceName != "" && !c.resource.dropped(serviceNameAttribute) {
+		job := serviceName
+		if serviceNamespace != "" {
+			job = serviceNamespace + "/" + serviceName
+		}
+		labels[jobLabel] = job
+	}
+
+	if scope.name != "" && c.scope.promoted(scopeNameAttribute) {
+		labels[scopeNameLabel] = scope.name
+	}
+	if scope.version != "" && c.scope.promoted(scopeVersionAttribute) {
+		labels[scopeVersionLabel] = scope.version
+	}
+	return labels
+}
+
+func (c *converter) convert(resourceMetrics []resourceMetrics) error {
+	for _, rm := range resourceMetrics {
+		for _, sm := range rm.scopeMetrics {
+			common := c.resourceLabels(rm.resourceAttributes, sm.scope)
+			for _, m := range sm.metrics {
+				if err := c.convertMetric(common, m); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (c *converter) convertMetric(common labelSet, m metric) error {
+	name := sanitizeMetricName(m.name)
+	if name == "" {
+		return fmt.Errorf("metric has empty name")
+	}
+
+	var (
+		gauge   = ts.SeriesAttributes{PromType: ts.PromMetricTypeGauge}
+		counter = ts.SeriesAttributes{
+			PromType:          ts.PromMetricTypeCounter,
+			HandleValueResets: true,
+		}
+		delta = m.temporality == aggregationTemporalityDelta
+	)
+
+	// NB: points flagged as having no recorded value only signal that the
+	// series went away and are skipped.
+	switch m.metricType {
+	case metricTypeGauge:
+		for _, p := range m.numberPoints {
+			if p.flags&dataPointFlagNoRecordedValue != 0 {
+				continue
+			}
+			c.add(name, common, p.attributes, nil, p.start, p.time, p.value, gauge, false)
+		}
+	case metricTypeSum:
+		attrs, sumName := sumAttributes(name, m.monotonic, gauge, counter)
+		for _, p := range m.numberPoints {
+			if p.flags&dataPointFlagNoRecordedValue != 0 {
+				continue
+			}
+			c.add(sumName, common, p.attributes, nil, p.start, p.time, p.value, attrs, delta)
+		}
+	case metricTypeHistogram:
+		for _, p := range m.histogramPoints {
+			if p.flags&dataPointFlagNoRecordedValue != 0 {
+				continue
+			}
+			c.addHistogram(name, common, p, delta)
+		}
+	case metricTypeExponentialHistogram:
+		for _, p := range m.exponentialHistogramPoints {
+			if p.flags&dataPointFlagNoRecordedValue != 0 {
+				continue
+			}
+			c.addExponentialHistogram(name, common, p, delta)
+		}
+	case metricTypeSummary:
+		summary := ts.SeriesAttributes{
+			PromType:          ts.PromMetricTypeSummary,
+			HandleValueResets: true,
+		}
+		for _, p := range m.summaryPoints {
+			if p.flags&dataPointFlagNoRecordedValue != 0 {
+				continue
+			}
+			for _, q := range p.quantiles {
+				extra := []prompb.Label{{
+					Name:  []byte(quantileLabel),
+					Value: []byte(formatFloat(q.quantile)),
+				}}
+				c.add(name, common, p.attributes, extra, p.start, p.time, q.value,
+					ts.SeriesAttributes{PromType: ts.PromMetricTypeSummary}, false)
+			}
+			c.add(name+sumSuffix, common, p.attributes, nil, p.start, p.time, p.sum, summary, false)
+			c.add(name+countSuffix, common, p.attributes, nil, p.start, p.time, float64(p.count), summary, false)
+		}
+	case metricTypeNone:
+		// Metric with no data, nothing to write.
+	}
+	return nil
+}
+
+func (c *converter) addHistogram(
+	name string,
+	common labelSet,
+	p histogramDataPoint,
+	delta bool,
+) {
+	attrs := ts.SeriesAttributes{
+		PromType:          ts.PromMetricTypeHistogram,
+		HandleValueResets: true,
+	}
+
+	// OTLP bucket counts are per bucket, Prometheus buckets are cumulative.
+	var cumulative uint64
+	for i, bc := range p.bucketCounts {
+		cumulative += bc
+		le := posInf
+		if i < len(p.explicitBounds) {
+			le = formatFloat(p.explicitBounds[i])
+		}
+		extra := []prompb.Label{{Name: promBucketLabel, Value: []byte(le)}}
+		c.add(name+bucketSuffix, common, p.attributes, extra, p.start, p.time,
+			float64(cumulative), attrs, delta)
+	}
+	c.add(name+sumSuffix, common, p.attributes, nil, p.start, p.time, p.sum, attrs, delta)
+	c.add(name+countSuffix, common, p.attributes, nil, p.start, p.time, float64(p.count), attrs, delta)
+}
+
+// addExponentialHistogram writes an exponential histogram as a classic
+// histogram whose bucket boundaries are the exponential bucket boundaries.
+// Positive bucket i covers (base^i, base^(i+1)] and negative bucket i covers
+// [-base^(i+1), -base^i), where base is 2^(2^-scale).
+func (c *converter) addExponentialHistogram(
+	name string,
+	common labelSet,
+	p exponentialHistogramDataPoint,
+	delta bool,
+) {
+	var (
+		attrs = ts.SeriesAttributes{
+			PromType:          ts.PromMetricTypeHistogram,
+			HandleValueResets: true,
+		}
+		cumulative uint64
+		bucketName = name + bucketSuffix
+	)
+	addBucket := func(le string) {
+		extra := []prompb.Label{{Name: promBucketLabel, Value: []byte(le)}}
+		c.add(bucketName, common, p.attributes, extra, p.start, p.time,
+			float64(cumulative), attrs, delta)
+	}
+
+	for i := len(p.negative.bucketCounts) - 1; i >= 0; i-- {
+		cumulative += p.negative.bucketCounts[i]
+		index := int64(p.negative.offset) + int64(i)
+		addBucket(formatFloat(-exponentialBound(index, p.scale)))
+	}
+	cumulative += p.zeroCount
+	addBucket(formatFloat(p.zeroThreshold))
+	for i, bc := range p.positive.bucketCounts {
+		cumulative += bc
+		index := int64(p.positive.offset) + int64(i)
+		addBucket(formatFloat(exponentialBound(index+1, p.scale)))
+	}
+	cumulative = p.count
+	addBucket(posInf)
+
+	c.add(name+sumSuffix, common, p.attributes, nil, p.start, p.time, p.sum, attrs, delta)
+	c.add(name+countSuffix, common, p.attributes, nil, p.start, p.time, float64(p.count), attrs, delta)
+}
+
+// exponentialBound returns base^index for the base of the given scale.
+func exponentialBound(index int64, scale int32) float64 {
+	if scale <= 0 {
+		return math.Ldexp(1, int(index<<uint(-scale)))
+	}
+	// NB: split the exponent index/2^scale into its integer and fractional
+	// parts so that only the fractional part is subject to rounding.
+	var (
+		exp  = index >> uint(scale)
+		frac = index & (1<<uint(scale) - 1)
+	)
+	return math.Ldexp(math.Pow(2, float64(frac)/float64(int64(1)<<uint(scale))), int(exp))
+}
+
+func (c *converter) add(
+	name string,
+	common labelSet,
+	pointLabels []keyValue,
+	extra []prompb.Label,
+	start, t uint64,
+	value float64,
+	attrs ts.SeriesAttributes,
+	delta bool,
+) {
+	labels := make(labelSet, len(common)+len(pointLabels))
+	for k, v := range common {
+		labels[k] = v
+	}
+	// Data point labels take precedence over resource and scope labels.
+	for _, l := range pointLabels {
+		labels[sanitizeLabelName(l.key)] = l.value
+	}
+
+	promLabels := make([]prompb.Label, 0, len(labels)+len(extra)+1)
+	promLabels = append(promLabels, prompb.Label{
+		Name:  promNameLabel,
+		Value: []byte(name),
+	})
+	for k, v := range labels {
+		promLabels = append(promLabels, prompb.Label{
+			Name:  []byte(k),
+			Value: []byte(v),
+		})
+	}
+	promLabels = append(promLabels, extra...)
+
+	tags := storage.PromLabelsToM3Tags(promLabels, c.tagOpts)
+	id := string(tags.ID())
+	if delta {
+		var ok bool
+		value, ok = c.deltas.add(id, start, t, value)
+		if !ok {
+			return
+		}
+	}
+
+	// Timestamps are truncated to millisecond precision to match the
+	// Prometheus remote write ingestion path.
+	dp := ts.Datapoint{
+		Timestamp: xtime.UnixNano(t).Truncate(time.Millisecond),
+		Value:     value,
+	}
+	if idx, ok := c.seriesByID[id]; ok {
+		c.series[idx].datapoints = append(c.series[idx].datapoints, dp)
+		return
+	}
+
+	c.seriesByID[id] = len(c.series)
+	c.series = append(c.series, series{
+		tags:       tags,
+		datapoints: ts.Datapoints{dp},
+		attributes: attrs,
+	})
+}
+
+func sumAttributes(
+	name string,
+	monotonic bool,
+	gauge, counter ts.SeriesAttributes,
+) (ts.SeriesAttributes, string) {
+	if !monotonic {
+		return gauge, name
+	}
+	if !strings.HasSuffix(name, totalSuffix) {
+		name += totalSuffix
+	}
+	return counter, name
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// sanitizeMetricName replaces characters that are not valid in a Prometheus
+// metric name with underscores.
+func sanitizeMetricName(name string) string {
+	return sanitize(name, true)
+}
+
+// sanitizeLabelName replaces characters that are not valid in a Prometheus
+// label name with underscores.
+func sanitizeLabelName(name string) string {
+	return sanitize(name, false)
+}
+
+func sanitize(name string, allowColon bool) string {
+	if name == "" {
+		return name
+	}
+
+	var b strings.Builder
+	b.Grow(len(name) + 1)
+	if name[0] >= '0' && name[0] <= '9' {
+		b.WriteByte('_')
+	}
+	for i := 0; i < len(name); i++ {
+		ch := name[i]
+		valid := (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z') ||
+			(ch >= '0' && ch <= '9') || ch == '_' || (allowColon && ch == ':')
+		if !valid {
+			ch = '_'
+		}
+		b.WriteByte(ch)
+	}
+	return b.String()
+}
@@ -0,0 +1,327 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package otlp
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/cmd/services/m3query/config"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/ts"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testTime = time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC)
+
+func testResourceMetrics(metrics ...metric) []resourceMetrics {
+	return []resourceMetrics{{
+		resourceAttributes: []keyValue{
+			{key: "service.name", value: "api"},
+			{key: "service.instance.id", value: "host-1"},
+			{key: "host.arch", value: "amd64"},
+			{key: "k8s.pod.uid", value: "abc"},
+		},
+		scopeMetrics: []scopeMetrics{{
+			scope: instrumentationScope{
+				name:    "otel-go",
+				version: "1.0.0",
+			},
+			metrics: metrics,
+		}},
+	}}
+}
+
+func newTestConverter(resource, scope config.OTLPAttributesConfiguration) *converter {
+	return newConverter(models.NewTagOptions(),
+		newAttributePolicy(resource), newAttributePolicy(scope),
+		newDeltaAccumulator(time.Minute, time.Now))
+}
+
+func seriesStrings(t *testing.T, c *converter) []string {
+	strs := make([]string, 0, len(c.series))
+	for _, s := range c.series {
+		for _, dp := range s.datapoints {
+			strs = append(strs, fmt.Sprintf("%s %v", s.tags.String(), dp.Value))
+		}
+	}
+	return strs
+}
+
+func TestConvertGaugeWithAttributePolicy(t *testing.T) {
+	c := newTestConverter(config.OTLPAttributesConfiguration{
+		PromoteAll: true,
+		Drop:       []string{"k8s.pod.uid"},
+	}, config.OTLPAttributesConfiguration{
+		Promote: []string{"name"},
+	})
+
+	err := c.convert(testResourceMetrics(metric{
+		name:       "process.cpu.load",
+		metricType: metricTypeGauge,
+		numberPoints: []numberDataPoint{{
+			attributes: []keyValue{{key: "cpu", value: "0"}},
+			time:       uint64(testTime.UnixNano()),
+			value:      0.5,
+		}},
+	}))
+	require.NoError(t, err)
+
+	require.Equal(t, 1, len(c.series))
+	assert.Equal(t, ts.PromMetricTypeGauge, c.series[0].attributes.PromType)
+	assert.Equal(t, []string{
+		"__name__: process_cpu_load, cpu: 0, host_arch: amd64, instance: host-1, " +
+			"job: api, otel_scope_name: otel-go, service_instance_id: host-1, " +
+			"service_name: api 0.5",
+	}, seriesStrings(t, c))
+}
+
+func TestConvertMonotonicDeltaSum(t *testing.T) {
+	c := newTestConverter(config.OTLPAttributesConfiguration{},
+		config.OTLPAttributesConfiguration{})
+
+	require.NoError(t, c.convert(testResourceMetrics(
+		deltaSum(3, 0, time.Second),
+		deltaSum(4, time.Second, 2*time.Second),
+	)))
+
+	require.Equal(t, 1, len(c.series))
+	assert.Equal(t, ts.PromMetricTypeCounter, c.series[0].attributes.PromType)
+	assert.True(t, c.series[0].attributes.HandleValueResets)
+	assert.Equal(t, []string{
+		"__name__: requests_total, instance: host-1, job: api 3",
+		"__name__: requests_total, instance: host-1, job: api 7",
+	}, seriesStrings(t, c))
+}
+
+func TestConvertMonotonicDeltaSumRepeatedPoint(t *testing.T) {
+	c := newTestConverter(config.OTLPAttributesConfiguration{},
+		config.OTLPAttributesConfiguration{})
+
+	// NB: exporters retry requests which failed, the points of which may have
+	// been written already.
+	require.NoError(t, c.convert(testResourceMetrics(
+		deltaSum(3, 0, time.Second),
+		deltaSum(4, time.Second, 2*time.Second),
+		deltaSum(4, time.Second, 2*time.Second),
+		deltaSum(3, 0, time.Second),
+		deltaSum(5, 2*time.Second, 3*time.Second),
+	)))
+
+	assert.Equal(t, []string{
+		"__name__: requests_total, instance: host-1, job: api 3",
+		"__name__: requests_total, instance: host-1, job: api 7",
+		"__name__: requests_total, instance: host-1, job: api 12",
+	}, seriesStrings(t, c))
+}
+
+func TestConvertMonotonicDeltaSumMisalignedStart(t *testing.T) {
+	c := newTestConverter(config.OTLPAttributesConfiguration{},
+		config.OTLPAttributesConfiguration{})
+
+	require.NoError(t, c.convert(testResourceMetrics(
+		deltaSum(3, 0, time.Second),
+		deltaSum(4, 2*time.Second, 3*time.Second),
+		deltaSum(5, 3*time.Second, 4*time.Second),
+	)))
+
+	assert.Equal(t, []string{
+		"__name__: requests_total, instance: host-1, job: api 3",
+		"__name__: requests_total, instance: host-1, job: api 4",
+		"__name__: requests_total, instance: host-1, job: api 9",
+	}, seriesStrings(t, c))
+}
+
+func deltaSum(v float64, start, end time.Duration) metric {
+	return metric{
+		name:        "requests",
+		metricType:  metricTypeSum,
+		temporality: aggregationTemporalityDelta,
+		monotonic:   true,
+		numberPoints: []numberDataPoint{{
+			start: uint64(testTime.Add(start).UnixNano()),
+			time:  uint64(testTime.Add(end).UnixNano()),
+			value: v,
+		}},
+	}
+}
+
+func TestConvertNonMonotonicSumIsGauge(t *testing.T) {
+	c := newTestConverter(config.OTLPAttributesConfiguration{},
+		config.OTLPAttributesConfiguration{})
+
+	err := c.convert(testResourceMetrics(metric{
+		name:        "queue.size",
+		metricType:  metricTypeSum,
+		temporality: aggregationTemporalityCumulative,
+		numberPoints: []numberDataPoint{{
+			time:  uint64(testTime.UnixNano()),
+			value: 12,
+		}},
+	}))
+	require.NoError(t, err)
+
+	require.Equal(t, 1, len(c.series))
+	assert.Equal(t, ts.PromMetricTypeGauge, c.series[0].attributes.PromType)
+	assert.Equal(t, []string{
+		"__name__: queue_size, instance: host-1, job: api 12",
+	}, seriesStrings(t, c))
+}
+
+func TestConvertHistogram(t *testing.T) {
+	c := newTestConverter(config.OTLPAttributesConfiguration{},
+		config.OTLPAttributesConfiguration{})
+
+	err := c.convert(testResourceMetrics(metric{
+		name:        "latency",
+		metricType:  metricTypeHistogram,
+		temporality: aggregationTemporalityCumulative,
+		histogramPoints: []histogramDataPoint{{
+			time:           uint64(testTime.UnixNano()),
+			count:          6,
+			sum:            4.5,
+			bucketCounts:   []uint64{1, 2, 3},
+			explicitBounds: []float64{0.1, 1},
+		}},
+	}))
+	require.NoError(t, err)
+
+	for _, s := range c.series {
+		assert.Equal(t, ts.PromMetricTypeHistogram, s.attributes.PromType)
+	}
+	assert.Equal(t, []string{
+		"__name__: latency_bucket, instance: host-1, job: api, le: 0.1 1",
+		"__name__: latency_bucket, instance: host-1, job: api, le: 1 3",
+		"__name__: latency_bucket, instance: host-1, job: api, le: +Inf 6",
+		"__name__: latency_sum, instance: host-1, job: api 4.5",
+		"__name__: latency_count, instance: host-1, job: api 6",
+	}, seriesStrings(t, c))
+}
+
+func TestConvertExponentialHistogram(t *testing.T) {
+	c := newTestConverter(config.OTLPAttributesConfiguration{},
+		config.OTLPAttributesConfiguration{})
+
+	// NB: with scale 1 the base is sqrt(2), so positive bucket 2 covers
+	// (2, 2.83] and negative bucket 0 covers [-1.41, -1).
+	err := c.convert(testResourceMetrics(metric{
+		name:        "payload",
+		metricType:  metricTypeExponentialHistogram,
+		temporality: aggregationTemporalityCumulative,
+		exponentialHistogramPoints: []exponentialHistogramDataPoint{{
+			time:      uint64(testTime.UnixNano()),
+			count:     7,
+			sum:       9,
+			scale:     1,
+			zeroCount: 1,
+			positive:  exponentialBuckets{offset: 2, bucketCounts: []uint64{2, 3}},
+			negative:  exponentialBuckets{offset: 0, bucketCounts: []uint64{1}},
+		}},
+	}))
+	require.NoError(t, err)
+
+	for _, s := range c.series {
+		assert.Equal(t, ts.PromMetricTypeHistogram, s.attributes.PromType)
+	}
+	assert.Equal(t, []string{
+		"__name__: payload_bucket, instance: host-1, job: api, le: -1 1",
+		"__name__: payload_bucket, instance: host-1, job: api, le: 0 2",
+		"__name__: payload_bucket, instance: host-1, job: api, le: 2.8284271247461903 4",
+		"__name__: payload_bucket, instance: host-1, job: api, le: 4 7",
+		"__name__: payload_bucket, instance: host-1, job: api, le: +Inf 7",
+		"__name__: payload_sum, instance: host-1, job: api 9",
+		"__name__: payload_count, instance: host-1, job: api 7",
+	}, seriesStrings(t, c))
+}
+
+func TestConvertSkipsNoRecordedValue(t *testing.T) {
+	c := newTestConverter(config.OTLPAttributesConfiguration{},
+		config.OTLPAttributesConfiguration{})
+
+	err := c.convert(testResourceMetrics(metric{
+		name:       "temperature",
+		metricType: metricTypeGauge,
+		numberPoints: []numberDataPoint{
+			{time: uint64(testTime.UnixNano()), value: 20},
+			{time: uint64(testTime.Add(time.Second).UnixNano()), flags: dataPointFlagNoRecordedValue},
+		},
+	}))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"__name__: temperature, instance: host-1, job: api 20",
+	}, seriesStrings(t, c))
+}
+
+func TestConvertSummary(t *testing.T) {
+	c := newTestConverter(config.OTLPAttributesConfiguration{},
+		config.OTLPAttributesConfiguration{})
+
+	err := c.convert(testResourceMetrics(metric{
+		name:       "rpc.duration",
+		metricType: metricTypeSummary,
+		summaryPoints: []summaryDataPoint{{
+			time:  uint64(testTime.UnixNano()),
+			count: 10,
+			sum:   20,
+			quantiles: []quantileValue{
+				{quantile: 0.5, value: 1.5},
+				{quantile: 0.99, value: 4},
+			},
+		}},
+	}))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"__name__: rpc_duration, instance: host-1, job: api, quantile: 0.5 1.5",
+		"__name__: rpc_duration, instance: host-1, job: api, quantile: 0.99 4",
+		"__name__: rpc_duration_sum, instance: host-1, job: api 20",
+		"__name__: rpc_duration_count, instance: host-1, job: api 10",
+	}, seriesStrings(t, c))
+}
+
+func TestDeltaAccumulatorStaleness(t *testing.T) {
+	now := testTime
+	acc := newDeltaAccumulator(time.Minute, func() time.Time { return now })
+
+	add := func(id string, start, end uint64, delta float64) float64 {
+		value, ok := acc.add(id, start, end, delta)
+		require.True(t, ok)
+		return value
+	}
+
+	assert.Equal(t, 2.0, add("foo", 0, 1, 2))
+	assert.Equal(t, 5.0, add("foo", 1, 2, 3))
+
+	now = now.Add(2 * time.Minute)
+	assert.Equal(t, 1.0, add("bar", 0, 1, 1))
+	assert.Equal(t, 1.0, add("foo", 2, 3, 1))
+}
+
+func TestSanitize(t *testing.T) {
+	assert.Equal(t, "http_server_duration", sanitizeMetricName("http.server.duration"))
+	assert.Equal(t, "ns:metric", sanitizeMetricName("ns:metric"))
+	assert.Equal(t, "_1xx", sanitizeMetricName("1xx"))
+	assert.Equal(t, "ns_label", sanitizeLabelName("ns:label"))
+}
@@ -0,0 +1,680 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package otlp
+
+import (
+	"encoding/base64"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// This file decodes the OTLP metrics protocol (opentelemetry-proto v0.19 and
+// later, which has the same wire format as OTLP 1.x) directly from the wire
+// format. The generated OTLP bindings cannot be used since the generated gRPC
+// services require a newer gRPC than the one the module is pinned to. Only
+// the fields needed for conversion are decoded, unknown fields are skipped.
+
+// aggregationTemporality mirrors the OTLP AggregationTemporality enum.
+type aggregationTemporality int32
+
+const (
+	aggregationTemporalityUnspecified aggregationTemporality = 0
+	aggregationTemporalityDelta       aggregationTemporality = 1
+	aggregationTemporalityCumulative  aggregationTemporality = 2
+)
+
+// dataPointFlagNoRecordedValue marks a data point that replaces a point
+// which would otherwise have been reported, e.g. when the series went away.
+const dataPointFlagNoRecordedValue = 1
+
+// metricType is the type of data held by a metric.
+type metricType int
+
+const (
+	metricTypeNone metricType = iota
+	metricTypeGauge
+	metricTypeSum
+	metricTypeHistogram
+	metricTypeExponentialHistogram
+	metricTypeSummary
+)
+
+// exportRequest is an ExportMetricsServiceRequest.
+type exportRequest struct {
+	resourceMetrics []resourceMetrics
+}
+
+type resourceMetrics struct {
+	resourceAttributes []keyValue
+	scopeMetrics       []scopeMetrics
+}
+
+type scopeMetrics struct {
+	scope   instrumentationScope
+	metrics []metric
+}
+
+type instrumentationScope struct {
+	name    string
+	version string
+}
+
+// keyValue is an attribute with its AnyValue value rendered as a string.
+type keyValue struct {
+	key   string
+	value string
+}
+
+type metric struct {
+	name        string
+	metricType  metricType
+	temporality aggregationTemporality
+	monotonic   bool
+
+	numberPoints               []numberDataPoint
+	histogramPoints            []histogramDataPoint
+	exponentialHistogramPoints []exponentialHistogramDataPoint
+	summaryPoints              []summaryDataPoint
+}
+
+type numberDataPoint struct {
+	attributes []keyValue
+	start      uint64
+	time       uint64
+	value      float64
+	flags      uint32
+}
+
+type histogramDataPoint struct {
+	attributes     []keyValue
+	start          uint64
+	time           uint64
+	count          uint64
+	sum            float64
+	bucketCounts   []uint64
+	explicitBounds []float64
+	flags          uint32
+}
+
+type exponentialHistogramDataPoint struct {
+	attributes    []keyValue
+	start         uint64
+	time          uint64
+	count         uint64
+	sum           float64
+	scale         int32
+	zeroCount     uint64
+	zeroThreshold float64
+	positive      exponentialBuckets
+	negative      exponentialBuckets
+	flags         uint32
+}
+
+type exponentialBuckets struct {
+	offset       int32
+	bucketCounts []uint64
+}
+
+type summaryDataPoint struct {
+	attributes []keyValue
+	start      uint64
+	time       uint64
+	count      uint64
+	sum        float64
+	quantiles  []quantileValue
+	flags      uint32
+}
+
+type quantileValue struct {
+	quantile float64
+	value    float64
+}
+
+func (r *exportRequest) unmarshal(b []byte) error {
+	return rangeFields(b, "ExportMetricsServiceRequest", func(f field) error {
+		if f.num != 1 {
+			return nil
+		}
+		v, err := f.message()
+		if err != nil {
+			return err
+		}
+		var rm resourceMetrics
+		if err := rm.unmarshal(v); err != nil {
+			return err
+		}
+		r.resourceMetrics = append(r.resourceMetrics, rm)
+		return nil
+	})
+}
+
+func (r *resourceMetrics) unmarshal(b []byte) error {
+	return rangeFields(b, "ResourceMetrics", func(f field) error {
+		switch f.num {
+		case 1:
+			v, err := f.message()
+			if err != nil {
+				return err
+			}
+			return rangeFields(v, "Resource", func(f field) error {
+				if f.num != 1 {
+					return nil
+				}
+				return f.appendKeyValue(&r.resourceAttributes)
+			})
+		case 2:
+			v, err := f.message()
+			if err != nil {
+				return err
+			}
+			var sm scopeMetrics
+			if err := sm.unmarshal(v); err != nil {
+				return err
+			}
+			r.scopeMetrics = append(r.scopeMetrics, sm)
+		}
+		return nil
+	})
+}
+
+func (s *scopeMetrics) unmarshal(b []byte) error {
+	return rangeFields(b, "ScopeMetrics", func(f field) error {
+		switch f.num {
+		case 1:
+			v, err := f.message()
+			if err != nil {
+				return err
+			}
+			return s.scope.unmarshal(v)
+		case 2:
+			v, err := f.message()
+			if err != nil {
+				return err
+			}
+			var m metric
+			if err := m.unmarshal(v); err != nil {
+				return err
+			}
+			s.metrics = append(s.metrics, m)
+		}
+		return nil
+	})
+}
+
+func (s *instrumentationScope) unmarshal(b []byte) error {
+	return rangeFields(b, "InstrumentationScope", func(f field) error {
+		var err error
+		switch f.num {
+		case 1:
+			s.name, err = f.string()
+		case 2:
+			s.version, err = f.string()
+		}
+		return err
+	})
+}
+
+func (m *metric) unmarshal(b []byte) error {
+	return rangeFields(b, "Metric", func(f field) error {
+		var dataMsg string
+		switch f.num {
+		case 1:
+			var err error
+			m.name, err = f.string()
+			return err
+		case 5:
+			m.metricType, dataMsg = metricTypeGauge, "Gauge"
+		case 7:
+			m.metricType, dataMsg = metricTypeSum, "Sum"
+		case 9:
+			m.metricType, dataMsg = metricTypeHistogram, "Histogram"
+		case 10:
+			m.metricType, dataMsg = metricTypeExponentialHistogram, "ExponentialHistogram"
+		case 11:
+			m.metricType, dataMsg = metricTypeSummary, "Summary"
+		default:
+			return nil
+		}
+		data, err := f.message()
+		if err != nil {
+			return err
+		}
+		return rangeFields(data, dataMsg, m.unmarshalDataField)
+	})
+}
+
+// unmarshalDataField decodes a field of the Gauge, Sum, Histogram,
+// ExponentialHistogram or Summary message held by the metric.
+func (m *metric) unmarshalDataField(f field) error {
+	switch f.num {
+	case 1:
+		v, err := f.message()
+		if err != nil {
+			return err
+		}
+		switch m.metricType {
+		case metricTypeGauge, metricTypeSum:
+			var p numberDataPoint
+			if err := p.unmarshal(v); err != nil {
+				return err
+			}
+			m.numberPoints = append(m.numberPoints, p)
+		case metricTypeHistogram:
+			var p histogramDataPoint
+			if err := p.unmarshal(v); err != nil {
+				return err
+			}
+			m.histogramPoints = append(m.histogramPoints, p)
+		case metricTypeExponentialHistogram:
+			var p exponentialHistogramDataPoint
+			if err := p.unmarshal(v); err != nil {
+				return err
+			}
+			m.exponentialHistogramPoints = append(m.exponentialHistogramPoints, p)
+		case metricTypeSummary:
+			var p summaryDataPoint
+			if err := p.unmarshal(v); err != nil {
+				return err
+			}
+			m.summaryPoints = append(m.summaryPoints, p)
+		}
+	case 2:
+		if m.metricType == metricTypeGauge || m.metricType == metricTypeSummary {
+			return nil
+		}
+		v, err := f.varint()
+		if err != nil {
+			return err
+		}
+		m.temporality = aggregationTemporality(v)
+	case 3:
+		if m.metricType != metricTypeSum {
+			return nil
+		}
+		v, err := f.varint()
+		if err != nil {
+			return err
+		}
+		m.monotonic = protowire.DecodeBool(v)
+	}
+	return nil
+}
+
+func (p *numberDataPoint) unmarshal(b []byte) error {
+	return rangeFields(b, "NumberDataPoint", func(f field) error {
+		var err error
+		switch f.num {
+		case 2:
+			p.start, err = f.fixed64()
+		case 3:
+			p.time, err = f.fixed64()
+		case 4:
+			p.value, err = f.double()
+		case 6:
+			var v uint64
+			v, err = f.fixed64()
+			p.value = float64(int64(v))
+		case 7:
+			err = f.appendKeyValue(&p.attributes)
+		case 8:
+			p.flags, err = f.uint32()
+		}
+		return err
+	})
+}
+
+func (p *histogramDataPoint) unmarshal(b []byte) error {
+	return rangeFields(b, "HistogramDataPoint", func(f field) error {
+		var err error
+		switch f.num {
+		case 2:
+			p.start, err = f.fixed64()
+		case 3:
+			p.time, err = f.fixed64()
+		case 4:
+			p.count, err = f.fixed64()
+		case 5:
+			p.sum, err = f.double()
+		case 6:
+			p.bucketCounts, err = f.appendFixed64s(p.bucketCounts)
+		case 7:
+			var bounds []uint64
+			bounds, err = f.appendFixed64s(nil)
+			for _, v := range bounds {
+				p.explicitBounds = append(p.explicitBounds, math.Float64frombits(v))
+			}
+		case 9:
+			err = f.appendKeyValue(&p.attributes)
+		case 10:
+			p.flags, err = f.uint32()
+		}
+		return err
+	})
+}
+
+func (p *exponentialHistogramDataPoint) unmarshal(b []byte) error {
+	return rangeFields(b, "ExponentialHistogramDataPoint", func(f field) error {
+		var err error
+		switch f.num {
+		case 1:
+			err = f.appendKeyValue(&p.attributes)
+		case 2:
+			p.start, err = f.fixed64()
+		case 3:
+			p.time, err = f.fixed64()
+		case 4:
+			p.count, err = f.fixed64()
+		case 5:
+			p.sum, err = f.double()
+		case 6:
+			p.scale, err = f.sint32()
+		case 7:
+			p.zeroCount, err = f.fixed64()
+		case 8:
+			err = p.positive.unmarshalField(f)
+		case 9:
+			err = p.negative.unmarshalField(f)
+		case 10:
+			p.flags, err = f.uint32()
+		case 14:
+			p.zeroThreshold, err = f.double()
+		}
+		return err
+	})
+}
+
+func (e *exponentialBuckets) unmarshalField(f field) error {
+	v, err := f.message()
+	if err != nil {
+		return err
+	}
+	return rangeFields(v, "Buckets", func(f field) error {
+		var err error
+		switch f.num {
+		case 1:
+			e.offset, err = f.sint32()
+		case 2:
+			e.bucketCounts, err = f.appendVarints(e.bucketCounts)
+		}
+		return err
+	})
+}
+
+func (p *summaryDataPoint) unmarshal(b []byte) error {
+	return rangeFields(b, "SummaryDataPoint", func(f field) error {
+		var err error
+		switch f.num {
+		case 2:
+			p.start, err = f.fixed64()
+		case 3:
+			p.time, err = f.fixed64()
+		case 4:
+			p.count, err = f.fixed64()
+		case 5:
+			p.sum, err = f.double()
+		case 6:
+			var v []byte
+			if v, err = f.message(); err != nil {
+				return err
+			}
+			var q quantileValue
+			err = rangeFields(v, "ValueAtQuantile", func(f field) error {
+				var err error
+				switch f.num {
+				case 1:
+					q.quantile, err = f.double()
+				case 2:
+					q.value, err = f.double()
+				}
+				return err
+			})
+			p.quantiles = append(p.quantiles, q)
+		case 7:
+			err = f.appendKeyValue(&p.attributes)
+		case 8:
+			p.flags, err = f.uint32()
+		}
+		return err
+	})
+}
+
+// anyValueString decodes an AnyValue and renders it as a label value.
+func anyValueString(b []byte) (string, error) {
+	var value string
+	err := rangeFields(b, "AnyValue", func(f field) error {
+		var err error
+		switch f.num {
+		case 1:
+			value, err = f.string()
+		case 2:
+			var v uint64
+			v, err = f.varint()
+			value = strconv.FormatBool(protowire.DecodeBool(v))
+		case 3:
+			var v uint64
+			v, err = f.varint()
+			value = strconv.FormatInt(int64(v), 10)
+		case 4:
+			var v float64
+			v, err = f.double()
+			value = formatFloat(v)
+		case 5:
+			var (
+				v    []byte
+				strs []string
+			)
+			if v, err = f.message(); err != nil {
+				return err
+			}
+			err = rangeFields(v, "ArrayValue", func(f field) error {
+				if f.num != 1 {
+					return nil
+				}
+				elem, err := f.message()
+				if err != nil {
+					return err
+				}
+				str, err := anyValueString(elem)
+				strs = append(strs, str)
+				return err
+			})
+			value = "[" + strings.Join(strs, ",") + "]"
+		case 6:
+			var (
+				v   []byte
+				kvs []keyValue
+			)
+			if v, err = f.message(); err != nil {
+				return err
+			}
+			err = rangeFields(v, "KeyValueList", func(f field) error {
+				if f.num != 1 {
+					return nil
+				}
+				return f.appendKeyValue(&kvs)
+			})
+			strs := make([]string, 0, len(kvs))
+			for _, kv := range kvs {
+				strs = append(strs, kv.key+"="+kv.value)
+			}
+			sort.Strings(strs)
+			value = "{" + strings.Join(strs, ",") + "}"
+		case 7:
+			var v []byte
+			v, err = f.message()
+			value = base64.StdEncoding.EncodeToString(v)
+		}
+		return err
+	})
+	return value, err
+}
+
+// field is a single encoded field of a message.
+type field struct {
+	msg   string
+	num   protowire.Number
+	typ   protowire.Type
+	value []byte
+}
+
+// rangeFields calls fn with each field of the encoded message b.
+func rangeFields(b []byte, msg string, fn func(f field) error) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return fmt.Errorf("proto: %s: %v", msg, protowire.ParseError(n))
+		}
+		b = b[n:]
+		n = protowire.ConsumeFieldValue(num, typ, b)
+		if n < 0 {
+			return fmt.Errorf("proto: %s: field %d: %v", msg, num, protowire.ParseError(n))
+		}
+		if err := fn(field{msg: msg, num: num, typ: typ, value: b[:n]}); err != nil {
+			return err
+		}
+		b = b[n:]
+	}
+	return nil
+}
+
+func (f field) wrongType() error {
+	return fmt.Errorf("proto: %s: wrong wire type %d for field %d", f.msg, f.typ, f.num)
+}
+
+func (f field) message() ([]byte, error) {
+	if f.typ != protowire.BytesType {
+		return nil, f.wrongType()
+	}
+	v, _ := protowire.ConsumeBytes(f.value)
+	return v, nil
+}
+
+func (f field) string() (string, error) {
+	v, err := f.message()
+	return string(v), err
+}
+
+func (f field) varint() (uint64, error) {
+	if f.typ != protowire.VarintType {
+		return 0, f.wrongType()
+	}
+	v, _ := protowire.ConsumeVarint(f.value)
+	return v, nil
+}
+
+func (f field) uint32() (uint32, error) {
+	v, err := f.varint()
+	return uint32(v), err
+}
+
+func (f field) sint32() (int32, error) {
+	v, err := f.varint()
+	return int32(protowire.DecodeZigZag(v & math.MaxUint32)), err
+}
+
+func (f field) fixed64() (uint64, error) {
+	if f.typ != protowire.Fixed64Type {
+		return 0, f.wrongType()
+	}
+	v, _ := protowire.ConsumeFixed64(f.value)
+	return v, nil
+}
+
+func (f field) double() (float64, error) {
+	v, err := f.fixed64()
+	return math.Float64frombits(v), err
+}
+
+// appendFixed64s appends the values of a packed or unpacked repeated 64-bit
+// field.
+func (f field) appendFixed64s(dst []uint64) ([]uint64, error) {
+	if f.typ == protowire.Fixed64Type {
+		v, err := f.fixed64()
+		return append(dst, v), err
+	}
+	b, err := f.message()
+	if err != nil {
+		return dst, err
+	}
+	for len(b) > 0 {
+		v, n := protowire.ConsumeFixed64(b)
+		if n < 0 {
+			return dst, fmt.Errorf("proto: %s: field %d: %v", f.msg, f.num, protowire.ParseError(n))
+		}
+		dst = append(dst, v)
+		b = b[n:]
+	}
+	return dst, nil
+}
+
+// appendVarints appends the values of a packed or unpacked repeated varint
+// field.
+func (f field) appendVarints(dst []uint64) ([]uint64, error) {
+	if f.typ == protowire.VarintType {
+		v, err := f.varint()
+		return append(dst, v), err
+	}
+	b, err := f.message()
+	if err != nil {
+		return dst, err
+	}
+	for len(b) > 0 {
+		v, n := protowire.ConsumeVarint(b)
+		if n < 0 {
+			return dst, fmt.Errorf("proto: %s: field %d: %v", f.msg, f.num, protowire.ParseError(n))
+		}
+		dst = append(dst, v)
+		b = b[n:]
+	}
+	return dst, nil
+}
+
+// appendKeyValue decodes a KeyValue message and appends it to dst.
+func (f field) appendKeyValue(dst *[]keyValue) error {
+	v, err := f.message()
+	if err != nil {
+		return err
+	}
+	var kv keyValue
+	err = rangeFields(v, "KeyValue", func(f field) error {
+		switch f.num {
+		case 1:
+			var err error
+			kv.key, err = f.string()
+			return err
+		case 2:
+			b, err := f.message()
+			if err != nil {
+				return err
+			}
+			kv.value, err = anyValueString(b)
+			return err
+		}
+		return nil
+	})
+	*dst = append(*dst, kv)
+	return err
+}
@@ -0,0 +1,144 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package otlp
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/m3db/m3/src/cmd/services/m3query/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testSDKRequestBody returns an export request recorded from the OTLP/HTTP
+// exporter of the OpenTelemetry Go SDK v1.43.0, holding a counter, an up down
+// counter, a gauge, an explicit bucket histogram and an exponential histogram.
+func testSDKRequestBody(t *testing.T) []byte {
+	body, err := ioutil.ReadFile("testdata/otel_go_sdk_metrics.pb")
+	require.NoError(t, err)
+	return body
+}
+
+func TestUnmarshalSDKExportRequest(t *testing.T) {
+	var req exportRequest
+	require.NoError(t, req.unmarshal(testSDKRequestBody(t)))
+
+	require.Equal(t, 1, len(req.resourceMetrics))
+	rm := req.resourceMetrics[0]
+	assert.Equal(t, []keyValue{
+		{key: "deployment.environment", value: "prod"},
+		{key: "service.instance.id", value: "pod-1"},
+		{key: "service.name", value: "checkout"},
+		{key: "service.namespace", value: "shop"},
+	}, rm.resourceAttributes)
+
+	require.Equal(t, 1, len(rm.scopeMetrics))
+	sm := rm.scopeMetrics[0]
+	assert.Equal(t, instrumentationScope{name: "shop/checkout", version: "1.2.3"}, sm.scope)
+	require.Equal(t, 5, len(sm.metrics))
+
+	counter := sm.metrics[0]
+	assert.Equal(t, "http.requests", counter.name)
+	assert.Equal(t, metricTypeSum, counter.metricType)
+	assert.Equal(t, aggregationTemporalityCumulative, counter.temporality)
+	assert.True(t, counter.monotonic)
+	require.Equal(t, 1, len(counter.numberPoints))
+	assert.Equal(t, numberDataPoint{
+		attributes: []keyValue{{key: "code", value: "200"}, {key: "method", value: "GET"}},
+		start:      1792351913528130364,
+		time:       1792351913528241883,
+		value:      5,
+	}, counter.numberPoints[0])
+
+	upDown := sm.metrics[1]
+	assert.Equal(t, "queue.depth", upDown.name)
+	assert.Equal(t, metricTypeSum, upDown.metricType)
+	assert.False(t, upDown.monotonic)
+	require.Equal(t, 1, len(upDown.numberPoints))
+	assert.Equal(t, 2.5, upDown.numberPoints[0].value)
+
+	gauge := sm.metrics[2]
+	assert.Equal(t, "temperature", gauge.name)
+	assert.Equal(t, metricTypeGauge, gauge.metricType)
+	require.Equal(t, 1, len(gauge.numberPoints))
+	assert.Equal(t, 21.0, gauge.numberPoints[0].value)
+
+	histogram := sm.metrics[3]
+	assert.Equal(t, "http.latency", histogram.name)
+	assert.Equal(t, metricTypeHistogram, histogram.metricType)
+	require.Equal(t, 1, len(histogram.histogramPoints))
+	hp := histogram.histogramPoints[0]
+	assert.Equal(t, uint64(3), hp.count)
+	assert.Equal(t, 5.55, hp.sum)
+	assert.Equal(t, []uint64{1, 1, 1, 0}, hp.bucketCounts)
+	assert.Equal(t, []float64{0.1, 1, 10}, hp.explicitBounds)
+
+	exponential := sm.metrics[4]
+	assert.Equal(t, "rpc.payload", exponential.name)
+	assert.Equal(t, metricTypeExponentialHistogram, exponential.metricType)
+	require.Equal(t, 1, len(exponential.exponentialHistogramPoints))
+	ep := exponential.exponentialHistogramPoints[0]
+	assert.Equal(t, uint64(4), ep.count)
+	assert.Equal(t, 5.0, ep.sum)
+	assert.Equal(t, int32(2), ep.scale)
+	assert.Equal(t, uint64(1), ep.zeroCount)
+	assert.Equal(t, exponentialBuckets{offset: 6, bucketCounts: []uint64{1, 0, 0, 1}}, ep.positive)
+	assert.Equal(t, exponentialBuckets{offset: 6, bucketCounts: []uint64{1}}, ep.negative)
+}
+
+func TestUnmarshalExportRequestInvalid(t *testing.T) {
+	body := testSDKRequestBody(t)
+	var req exportRequest
+	require.Error(t, req.unmarshal(body[:len(body)-1]))
+}
+
+func TestConvertSDKExportRequest(t *testing.T) {
+	var req exportRequest
+	require.NoError(t, req.unmarshal(testSDKRequestBody(t)))
+
+	c := newTestConverter(config.OTLPAttributesConfiguration{},
+		config.OTLPAttributesConfiguration{})
+	require.NoError(t, c.convert(req.resourceMetrics))
+
+	const common = "instance: pod-1, job: shop/checkout"
+	assert.Equal(t, []string{
+		"__name__: http_requests_total, code: 200, " + common + ", method: GET 5",
+		"__name__: queue_depth, " + common + " 2.5",
+		"__name__: temperature, " + common + ", room: a 21",
+		"__name__: http_latency_bucket, code: 200, " + common + ", le: 0.1, method: GET 1",
+		"__name__: http_latency_bucket, code: 200, " + common + ", le: 1, method: GET 2",
+		"__name__: http_latency_bucket, code: 200, " + common + ", le: 10, method: GET 3",
+		"__name__: http_latency_bucket, code: 200, " + common + ", le: +Inf, method: GET 3",
+		"__name__: http_latency_sum, code: 200, " + common + ", method: GET 5.55",
+		"__name__: http_latency_count, code: 200, " + common + ", method: GET 3",
+		"__name__: rpc_payload_bucket, " + common + ", le: -2.8284271247461903 1",
+		"__name__: rpc_payload_bucket, " + common + ", le: 0 2",
+		"__name__: rpc_payload_bucket, " + common + ", le: 3.363585661014858 3",
+		"__name__: rpc_payload_bucket, " + common + ", le: 4 3",
+		"__name__: rpc_payload_bucket, " + common + ", le: 4.756828460010884 3",
+		"__name__: rpc_payload_bucket, " + common + ", le: 5.656854249492381 4",
+		"__name__: rpc_payload_bucket, " + common + ", le: +Inf 4",
+		"__name__: rpc_payload_sum, " + common + " 5",
+		"__name__: rpc_payload_count, " + common + " 4",
+	}, seriesStrings(t, c))
+}
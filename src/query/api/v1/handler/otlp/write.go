@@ -0,0 +1,360 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package otlp provides an OpenTelemetry (OTLP/HTTP) metrics ingestion handler.
+package otlp
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/m3db/m3/src/cmd/services/m3coordinator/ingest"
+	"github.com/m3db/m3/src/dbnode/client"
+	"github.com/m3db/m3/src/query/api/v1/options"
+	"github.com/m3db/m3/src/query/api/v1/route"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/query/ts"
+	"github.com/m3db/m3/src/query/util/logging"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	"github.com/m3db/m3/src/x/instrument"
+	xhttp "github.com/m3db/m3/src/x/net/http"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/uber-go/tally"
+	"go.uber.org/zap"
+)
+
+const (
+	// WriteURL is the url for the OTLP metrics write handler.
+	WriteURL = route.Prefix + "/otlp/v1/metrics"
+
+	// WriteHTTPMethod is the HTTP method used with this resource.
+	WriteHTTPMethod = http.MethodPost
+)
+
+var (
+	errNoDownsamplerAndWriter = errors.New("no downsampler and writer set")
+	errNoTagOptions           = errors.New("no tag options set")
+	errNoNowFn                = errors.New("no now fn set")
+
+	defaultValue = ingest.IterValue{
+		Tags:       models.EmptyTags(),
+		Attributes: ts.DefaultSeriesAttributes(),
+		Metadata:   ts.Metadata{},
+	}
+)
+
+// WriteHandler is a handler for OTLP/HTTP protobuf metrics exports.
+type WriteHandler struct {
+	downsamplerAndWriter ingest.DownsamplerAndWriter
+	tagOptions           models.TagOptions
+	storeMetricsType     bool
+	resourcePolicy       attributePolicy
+	scopePolicy          attributePolicy
+	deltas               *deltaAccumulator
+	instrumentOpts       instrument.Options
+	metrics              writeMetrics
+}
+
+// NewWriteHandler returns a new OTLP metrics write handler.
+func NewWriteHandler(options options.HandlerOptions) (http.Handler, error) {
+	var (
+		downsamplerAndWriter = options.DownsamplerAndWriter()
+		tagOptions           = options.TagOptions()
+		nowFn                = options.NowFn()
+		cfg                  = options.Config().OTLP
+	)
+
+	if downsamplerAndWriter == nil {
+		return nil, errNoDownsamplerAndWriter
+	}
+
+	if tagOptions == nil {
+		return nil, errNoTagOptions
+	}
+
+	if nowFn == nil {
+		return nil, errNoNowFn
+	}
+
+	scope := options.InstrumentOpts().
+		MetricsScope().
+		Tagged(map[string]string{"handler": "otlp-write"})
+
+	return &WriteHandler{
+		downsamplerAndWriter: downsamplerAndWriter,
+		tagOptions:           tagOptions,
+		storeMetricsType:     options.StoreMetricsType(),
+		resourcePolicy:       newAttributePolicy(cfg.ResourceAttributes),
+		scopePolicy:          newAttributePolicy(cfg.ScopeAttributes),
+		deltas:               newDeltaAccumulator(cfg.DeltaStaleness, nowFn),
+		instrumentOpts:       options.InstrumentOpts(),
+		metrics:              newWriteMetrics(scope),
+	}, nil
+}
+
+type writeMetrics struct {
+	writeSuccess      tally.Counter
+	writeErrorsServer tally.Counter
+	writeErrorsClient tally.Counter
+	writeSeries       tally.Counter
+}
+
+func newWriteMetrics(scope tally.Scope) writeMetrics {
+	return writeMetrics{
+		writeSuccess:      scope.SubScope("write").Counter("success"),
+		writeErrorsServer: scope.SubScope("write").Tagged(map[string]string{"code": "5XX"}).Counter("errors"),
+		writeErrorsClient: scope.SubScope("write").Tagged(map[string]string{"code": "4XX"}).Counter("errors"),
+		writeSeries:       scope.SubScope("write").Counter("series"),
+	}
+}
+
+func (m *writeMetrics) incError(err error) {
+	if xhttp.IsClientError(err) {
+		m.writeErrorsClient.Inc(1)
+	} else {
+		m.writeErrorsServer.Inc(1)
+	}
+}
+
+func (h *WriteHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	req, err := h.parseRequest(r)
+	if err != nil {
+		err = xerrors.NewInvalidParamsError(err)
+		h.metrics.incError(err)
+		xhttp.WriteError(w, err)
+		return
+	}
+
+	conv := newConverter(h.tagOptions, h.resourcePolicy, h.scopePolicy, h.deltas)
+	if err := conv.convert(req.resourceMetrics); err != nil {
+		err = xerrors.NewInvalidParamsError(err)
+		h.metrics.incError(err)
+		xhttp.WriteError(w, err)
+		return
+	}
+
+	h.metrics.writeSeries.Inc(int64(len(conv.series)))
+	iter := newSeriesIter(conv.series, h.storeMetricsType)
+	batchErr := h.downsamplerAndWriter.WriteBatch(r.Context(), iter, ingest.WriteOptions{})
+	if batchErr != nil {
+		err := h.batchError(r, batchErr)
+		h.metrics.incError(err)
+		xhttp.WriteError(w, err)
+		return
+	}
+
+	// NB: a successful export is answered with an empty
+	// ExportMetricsServiceResponse, which encodes to an empty body.
+	w.Header().Set(xhttp.HeaderContentType, xhttp.ContentTypeProtobuf)
+	w.WriteHeader(http.StatusOK)
+	h.metrics.writeSuccess.Inc(1)
+}
+
+func (h *WriteHandler) parseRequest(
+	r *http.Request,
+) (*exportRequest, error) {
+	if r.Body == nil {
+		return nil, errors.New("empty request body")
+	}
+	defer r.Body.Close()
+
+	if ct := r.Header.Get(xhttp.HeaderContentType); ct != "" &&
+		!strings.HasPrefix(ct, xhttp.ContentTypeProtobuf) {
+		return nil, fmt.Errorf("unsupported content type: %s", ct)
+	}
+
+	var body io.Reader = r.Body
+	switch enc := r.Header.Get("Content-Encoding"); enc {
+	case "":
+	case "gzip":
+		gz, err := gzip.NewReader(r.Body)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		body = gz
+	default:
+		return nil, fmt.Errorf("unsupported content encoding: %s", enc)
+	}
+
+	data, err := ioutil.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+
+	var req exportRequest
+	if err := req.unmarshal(data); err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+func (h *WriteHandler) batchError(r *http.Request, batchErr ingest.BatchError) error {
+	var (
+		errs              = batchErr.Errors()
+		lastRegularErr    string
+		lastBadRequestErr string
+		numRegular        int
+		numBadRequest     int
+	)
+	for _, err := range errs {
+		switch {
+		case client.IsBadRequestError(err):
+			numBadRequest++
+			lastBadRequestErr = err.Error()
+		case xerrors.IsInvalidParams(err):
+			numBadRequest++
+			lastBadRequestErr = err.Error()
+		default:
+			numRegular++
+			lastRegularErr = err.Error()
+		}
+	}
+
+	var status int
+	switch {
+	case numBadRequest == len(errs):
+		status = http.StatusBadRequest
+	default:
+		status = http.StatusInternalServerError
+	}
+
+	logger := logging.WithContext(r.Context(), h.instrumentOpts)
+	logger.Error("write error",
+		zap.String("remoteAddr", r.RemoteAddr),
+		zap.Int("httpResponseStatusCode", status),
+		zap.Int("numRegularErrors", numRegular),
+		zap.Int("numBadRequestErrors", numBadRequest),
+		zap.String("lastRegularError", lastRegularErr),
+		zap.String("lastBadRequestErr", lastBadRequestErr))
+
+	var resultErr string
+	if lastRegularErr != "" {
+		resultErr = fmt.Sprintf("retryable_errors: count=%d, last=%s",
+			numRegular, lastRegularErr)
+	}
+	if lastBadRequestErr != "" {
+		var sep string
+		if lastRegularErr != "" {
+			sep = ", "
+		}
+		resultErr = fmt.Sprintf("%s%sbad_request_errors: count=%d, last=%s",
+			resultErr, sep, numBadRequest, lastBadRequestErr)
+	}
+	return xhttp.NewError(errors.New(resultErr), status)
+}
+
+type seriesIter struct {
+	idx        int
+	err        error
+	series     []series
+	metadatas  []ts.Metadata
+	annotation []byte
+
+	storeMetricsType bool
+}
+
+func newSeriesIter(series []series, storeMetricsType bool) *seriesIter {
+	return &seriesIter{
+		idx:              -1,
+		series:           series,
+		storeMetricsType: storeMetricsType,
+	}
+}
+
+func (i *seriesIter) Next() bool {
+	if i.err != nil {
+		return false
+	}
+
+	i.idx++
+	if i.idx >= len(i.series) {
+		return false
+	}
+
+	if !i.storeMetricsType {
+		return true
+	}
+
+	annotationPayload, err := storage.SeriesAttributesToAnnotationPayload(i.series[i.idx].attributes)
+	if err != nil {
+		i.err = err
+		return false
+	}
+
+	i.annotation, err = annotationPayload.Marshal()
+	if err != nil {
+		i.err = err
+		return false
+	}
+
+	if len(i.annotation) == 0 {
+		i.annotation = nil
+	}
+
+	return true
+}
+
+func (i *seriesIter) Current() ingest.IterValue {
+	if i.idx < 0 || i.idx >= len(i.series) {
+		return defaultValue
+	}
+
+	s := i.series[i.idx]
+	value := ingest.IterValue{
+		Tags:       s.tags,
+		Datapoints: s.datapoints,
+		Attributes: s.attributes,
+		Unit:       xtime.Millisecond,
+		Annotation: i.annotation,
+	}
+	if i.idx < len(i.metadatas) {
+		value.Metadata = i.metadatas[i.idx]
+	}
+	return value
+}
+
+func (i *seriesIter) Reset() error {
+	i.idx = -1
+	i.err = nil
+	i.annotation = nil
+	return nil
+}
+
+func (i *seriesIter) Error() error {
+	return i.err
+}
+
+func (i *seriesIter) SetCurrentMetadata(metadata ts.Metadata) {
+	if len(i.metadatas) == 0 {
+		i.metadatas = make([]ts.Metadata, len(i.series))
+	}
+	if i.idx < 0 || i.idx >= len(i.metadatas) {
+		return
+	}
+	i.metadatas[i.idx] = metadata
+}
@@ -0,0 +1,146 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package otlp
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/cmd/services/m3coordinator/ingest"
+	"github.com/m3db/m3/src/cmd/services/m3query/config"
+	"github.com/m3db/m3/src/query/api/v1/options"
+	"github.com/m3db/m3/src/query/models"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	xhttp "github.com/m3db/m3/src/x/net/http"
+	xtest "github.com/m3db/m3/src/x/test"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func makeOptions(ds ingest.DownsamplerAndWriter) options.HandlerOptions {
+	return options.EmptyHandlerOptions().
+		SetNowFn(time.Now).
+		SetDownsamplerAndWriter(ds).
+		SetTagOptions(models.NewTagOptions()).
+		SetConfig(config.Configuration{}).
+		SetStoreMetricsType(true)
+}
+
+func TestOTLPWrite(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	mockDownsamplerAndWriter := ingest.NewMockDownsamplerAndWriter(ctrl)
+	mockDownsamplerAndWriter.
+		EXPECT().
+		WriteBatch(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(
+			_ context.Context,
+			iter ingest.DownsampleAndWriteIter,
+			_ ingest.WriteOptions,
+		) ingest.BatchError {
+			require.True(t, iter.Next())
+			value := iter.Current()
+			assert.Equal(t, "__name__: http_requests_total, code: 200, instance: pod-1, "+
+				"job: shop/checkout, method: GET", value.Tags.String())
+			require.Equal(t, 1, len(value.Datapoints))
+			assert.Equal(t, xtime.UnixNano(1792351913528000000), value.Datapoints[0].Timestamp)
+			assert.Equal(t, 5.0, value.Datapoints[0].Value)
+			assert.Equal(t, xtime.Millisecond, value.Unit)
+			assert.NotNil(t, value.Annotation)
+
+			n := 1
+			for iter.Next() {
+				n++
+			}
+			require.NoError(t, iter.Error())
+			assert.Equal(t, 18, n)
+			return nil
+		})
+
+	handler, err := NewWriteHandler(makeOptions(mockDownsamplerAndWriter))
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	_, err = gz.Write(testSDKRequestBody(t))
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+
+	req := httptest.NewRequest(WriteHTTPMethod, WriteURL, &buf)
+	req.Header.Set(xhttp.HeaderContentType, xhttp.ContentTypeProtobuf)
+	req.Header.Set("Content-Encoding", "gzip")
+
+	writer := httptest.NewRecorder()
+	handler.ServeHTTP(writer, req)
+	resp := writer.Result()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, xhttp.ContentTypeProtobuf, resp.Header.Get(xhttp.HeaderContentType))
+}
+
+func TestOTLPWriteBadContentType(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	handler, err := NewWriteHandler(makeOptions(ingest.NewMockDownsamplerAndWriter(ctrl)))
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(WriteHTTPMethod, WriteURL,
+		bytes.NewReader(testSDKRequestBody(t)))
+	req.Header.Set(xhttp.HeaderContentType, xhttp.ContentTypeJSON)
+
+	writer := httptest.NewRecorder()
+	handler.ServeHTTP(writer, req)
+	require.Equal(t, http.StatusBadRequest, writer.Result().StatusCode)
+}
+
+func TestOTLPWriteError(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	var multiErr xerrors.MultiError
+	multiErr = multiErr.Add(errors.New("an error"))
+
+	mockDownsamplerAndWriter := ingest.NewMockDownsamplerAndWriter(ctrl)
+	mockDownsamplerAndWriter.
+		EXPECT().
+		WriteBatch(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(multiErr)
+
+	handler, err := NewWriteHandler(makeOptions(mockDownsamplerAndWriter))
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(WriteHTTPMethod, WriteURL,
+		bytes.NewReader(testSDKRequestBody(t)))
+
+	writer := httptest.NewRecorder()
+	handler.ServeHTTP(writer, req)
+	require.Equal(t, http.StatusInternalServerError, writer.Result().StatusCode)
+}
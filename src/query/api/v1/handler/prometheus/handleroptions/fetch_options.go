@@ -253,6 +253,11 @@ func (b fetchOptionsBuilder) newFetchOptions(
 	if source := req.Header.Get(headers.SourceHeader); len(source) > 0 {
 		fetchOpts.Source = []byte(source)
 	}
+	// NB: dbnodes attribute queries to tenants by their source, so the tenant
+	// is sent as the source of the query.
+	if tenant := req.Header.Get(headers.TenantHeader); len(tenant) > 0 {
+		fetchOpts.Source = []byte(tenant)
+	}
 
 	seriesLimit, err := ParseLimit(req, headers.LimitMaxSeriesHeader,
 		"limit", b.opts.Limits.SeriesLimit)
@@ -499,6 +499,25 @@ func TestFetchOptionsWithHeader(t *testing.T) {
 	require.Equal(t, ex, opts.RestrictQueryOptions)
 }
 
+func TestFetchOptionsTenantHeader(t *testing.T) {
+	builder, err := NewFetchOptionsBuilder(FetchOptionsBuilderOptions{
+		Timeout: 10 * time.Second,
+	})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Add(headers.SourceHeader, "source")
+	_, opts, err := builder.NewFetchOptions(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, []byte("source"), opts.Source)
+
+	// The tenant takes precedence over the source.
+	req.Header.Add(headers.TenantHeader, "tenant")
+	_, opts, err = builder.NewFetchOptions(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, []byte("tenant"), opts.Source)
+}
+
 func stripSpace(str string) string {
 	return regexp.MustCompile(`\s+`).ReplaceAllString(str, "")
 }
@@ -0,0 +1,147 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package native
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/client"
+	"github.com/m3db/m3/src/query/api/v1/handler/prometheus"
+	"github.com/m3db/m3/src/query/api/v1/options"
+	"github.com/m3db/m3/src/query/api/v1/route"
+	"github.com/m3db/m3/src/query/cache"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/parser/promql"
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/query/storage/m3"
+	"github.com/m3db/m3/src/query/util/logging"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	"github.com/m3db/m3/src/x/instrument"
+	xhttp "github.com/m3db/m3/src/x/net/http"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// DeleteSeriesURL is the url for the delete series endpoint.
+	DeleteSeriesURL = route.Prefix + "/admin/tsdb/delete_series"
+)
+
+var (
+	// DeleteSeriesHTTPMethods are the HTTP methods for this handler.
+	DeleteSeriesHTTPMethods = []string{http.MethodPost, http.MethodPut}
+
+	errNoClusters = errors.New("no clusters configured")
+)
+
+// DeleteSeriesHandler deletes the series matching the given selectors from
+// every namespace of the configured clusters.
+type DeleteSeriesHandler struct {
+	clusters       m3.Clusters
+	resultCache    cache.Cache
+	parseOpts      promql.ParseOptions
+	tagOpts        models.TagOptions
+	instrumentOpts instrument.Options
+}
+
+// NewDeleteSeriesHandler returns a new instance of handler.
+func NewDeleteSeriesHandler(opts options.HandlerOptions) http.Handler {
+	return &DeleteSeriesHandler{
+		clusters:    opts.Clusters(),
+		resultCache: opts.ResultCache(),
+		parseOpts: promql.NewParseOptions().
+			SetNowFn(opts.NowFn()),
+		tagOpts:        opts.TagOptions(),
+		instrumentOpts: opts.InstrumentOpts(),
+	}
+}
+
+func (h *DeleteSeriesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := logging.WithContext(r.Context(), h.instrumentOpts)
+
+	if h.clusters == nil {
+		xhttp.WriteError(w, errNoClusters)
+		return
+	}
+
+	queries, err := prometheus.ParseSeriesMatchQuery(r, h.parseOpts, h.tagOpts)
+	if err != nil {
+		logger.Error("unable to parse delete series request", zap.Error(err))
+		xhttp.WriteError(w, err)
+		return
+	}
+
+	// Match the Prometheus semantics of deleting all data for the series
+	// when no end time is specified rather than only data up until now.
+	if r.FormValue("end") == "" {
+		for _, query := range queries {
+			query.End = time.Unix(0, math.MaxInt64)
+		}
+	}
+
+	if h.resultCache != nil {
+		// NB: invalidate cached results even if only some of the series were
+		// deleted before an error.
+		defer h.resultCache.Invalidate()
+	}
+
+	for _, query := range queries {
+		m3Query, err := storage.FetchQueryToM3Query(query, storage.NewFetchOptions())
+		if err != nil {
+			xhttp.WriteError(w, xerrors.NewInvalidParamsError(err))
+			return
+		}
+
+		start := xtime.ToUnixNano(query.Start)
+		end := xtime.ToUnixNano(query.End)
+		for _, ns := range h.clusters.ClusterNamespaces() {
+			session, ok := ns.Session().(client.AdminSession)
+			if !ok {
+				err := fmt.Errorf("session for namespace %s does not support deletes",
+					ns.NamespaceID().String())
+				xhttp.WriteError(w, err)
+				return
+			}
+
+			n, err := session.DeleteSeries(ns.NamespaceID(), m3Query, start, end)
+			if err != nil {
+				logger.Error("unable to delete series",
+					zap.String("namespace", ns.NamespaceID().String()),
+					zap.String("query", query.Raw),
+					zap.Error(err))
+				xhttp.WriteError(w, err)
+				return
+			}
+
+			logger.Info("deleted series",
+				zap.String("namespace", ns.NamespaceID().String()),
+				zap.String("query", query.Raw),
+				zap.Int64("numSeries", n))
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,115 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package native
+
+import (
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/client"
+	"github.com/m3db/m3/src/query/api/v1/options"
+	"github.com/m3db/m3/src/query/cache"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/storage/m3"
+	"github.com/m3db/m3/src/x/ident"
+	xtest "github.com/m3db/m3/src/x/test"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testResultCache struct {
+	cache.Cache
+	invalidated int
+}
+
+func (c *testResultCache) Invalidate() {
+	c.invalidated++
+}
+
+func newDeleteSeriesTestHandler(
+	t *testing.T,
+	session client.Session,
+	resultCache cache.Cache,
+) http.Handler {
+	clusters, err := m3.NewClusters(m3.UnaggregatedClusterNamespaceDefinition{
+		NamespaceID: ident.StringID("test-ns"),
+		Session:     session,
+		Retention:   24 * time.Hour,
+	})
+	require.NoError(t, err)
+
+	opts := options.EmptyHandlerOptions().
+		SetClusters(clusters).
+		SetNowFn(time.Now).
+		SetTagOptions(models.NewTagOptions())
+	if resultCache != nil {
+		opts = opts.SetResultCache(resultCache)
+	}
+	return NewDeleteSeriesHandler(opts)
+}
+
+func TestDeleteSeries(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	session := client.NewMockAdminSession(ctrl)
+	session.EXPECT().
+		DeleteSeries(ident.NewIDMatcher("test-ns"), gomock.Any(),
+			xtime.UnixNano(100*int64(time.Second)), xtime.UnixNano(math.MaxInt64)).
+		Return(int64(3), nil)
+
+	resultCache := &testResultCache{}
+	handler := newDeleteSeriesTestHandler(t, session, resultCache)
+
+	form := url.Values{}
+	form.Set("match[]", `foo{bar="baz"}`)
+	form.Set("start", "100")
+	req := httptest.NewRequest(http.MethodPost, DeleteSeriesURL,
+		strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	// Cached results which may include the deleted series are invalidated.
+	assert.Equal(t, 1, resultCache.invalidated)
+}
+
+func TestDeleteSeriesRequiresMatchers(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	handler := newDeleteSeriesTestHandler(t, client.NewMockAdminSession(ctrl), nil)
+
+	req := httptest.NewRequest(http.MethodPost, DeleteSeriesURL, nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
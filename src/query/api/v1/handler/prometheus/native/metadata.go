@@ -0,0 +1,255 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package native
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/m3db/m3/src/query/api/v1/options"
+	"github.com/m3db/m3/src/query/api/v1/route"
+	"github.com/m3db/m3/src/query/generated/proto/prompb"
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/query/util/json"
+	"github.com/m3db/m3/src/query/util/logging"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	"github.com/m3db/m3/src/x/instrument"
+	xhttp "github.com/m3db/m3/src/x/net/http"
+
+	promparser "github.com/prometheus/prometheus/promql/parser"
+	"go.uber.org/zap"
+)
+
+const (
+	// MetadataURL is the url for the metric metadata endpoint.
+	MetadataURL = route.MetadataURL
+
+	// TargetsMetadataURL is the url for the targets metric metadata endpoint.
+	TargetsMetadataURL = route.TargetsMetadataURL
+
+	metricParam      = "metric"
+	limitParam       = "limit"
+	matchTargetParam = "match_target"
+)
+
+var (
+	// MetadataHTTPMethods are the HTTP methods for the metadata handlers.
+	MetadataHTTPMethods = []string{http.MethodGet}
+
+	errNoMetricMetadataStorage = errors.New("no metric metadata storage configured")
+
+	// metricTypeNames are the names Prometheus gives metric types in its
+	// metadata responses.
+	metricTypeNames = map[prompb.MetricType]string{
+		prompb.MetricType_UNKNOWN:         "unknown",
+		prompb.MetricType_COUNTER:         "counter",
+		prompb.MetricType_GAUGE:           "gauge",
+		prompb.MetricType_HISTOGRAM:       "histogram",
+		prompb.MetricType_GAUGE_HISTOGRAM: "gaugehistogram",
+		prompb.MetricType_SUMMARY:         "summary",
+		prompb.MetricType_INFO:            "info",
+		prompb.MetricType_STATESET:        "stateset",
+	}
+)
+
+// MetadataHandler returns the metadata of metric families, written by
+// Prometheus remote write, in the Prometheus metadata response format.
+type MetadataHandler struct {
+	storage        storage.MetricMetadataStorage
+	targets        bool
+	instrumentOpts instrument.Options
+}
+
+// NewMetadataHandler returns a new instance of handler for the metric
+// metadata endpoint.
+func NewMetadataHandler(opts options.HandlerOptions) http.Handler {
+	return &MetadataHandler{
+		storage:        opts.MetricMetadataStorage(),
+		instrumentOpts: opts.InstrumentOpts(),
+	}
+}
+
+// NewTargetsMetadataHandler returns a new instance of handler for the targets
+// metric metadata endpoint. Remote write does not send the targets metadata
+// was scraped from, so the metadata is returned for a single target without
+// any labels.
+func NewTargetsMetadataHandler(opts options.HandlerOptions) http.Handler {
+	return &MetadataHandler{
+		storage:        opts.MetricMetadataStorage(),
+		targets:        true,
+		instrumentOpts: opts.InstrumentOpts(),
+	}
+}
+
+func (h *MetadataHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(xhttp.HeaderContentType, xhttp.ContentTypeJSON)
+
+	if h.storage == nil {
+		xhttp.WriteError(w, errNoMetricMetadataStorage)
+		return
+	}
+
+	query, err := parseMetricMetadataQuery(r)
+	if err != nil {
+		xhttp.WriteError(w, xerrors.NewInvalidParamsError(err))
+		return
+	}
+
+	matchTarget := true
+	if h.targets {
+		matchTarget, err = matchesEmptyTarget(r.FormValue(matchTargetParam))
+		if err != nil {
+			xhttp.WriteError(w, xerrors.NewInvalidParamsError(err))
+			return
+		}
+	}
+
+	var metadata []prompb.MetricMetadata
+	if matchTarget {
+		metadata, err = h.storage.FetchMetricMetadata(r.Context(), query)
+		if err != nil {
+			logger := logging.WithContext(r.Context(), h.instrumentOpts)
+			logger.Error("unable to fetch metric metadata", zap.Error(err))
+			xhttp.WriteError(w, err)
+			return
+		}
+	}
+
+	if h.targets {
+		err = renderTargetsMetadataResultsJSON(w, metadata,
+			query.MetricFamilyName == "")
+	} else {
+		err = renderMetadataResultsJSON(w, metadata)
+	}
+	if err != nil {
+		logger := logging.WithContext(r.Context(), h.instrumentOpts)
+		logger.Error("unable to render metric metadata results", zap.Error(err))
+	}
+}
+
+func parseMetricMetadataQuery(r *http.Request) (storage.MetricMetadataQuery, error) {
+	query := storage.MetricMetadataQuery{
+		MetricFamilyName: r.FormValue(metricParam),
+	}
+	if str := r.FormValue(limitParam); str != "" {
+		limit, err := strconv.Atoi(str)
+		if err != nil {
+			return storage.MetricMetadataQuery{},
+				fmt.Errorf("could not parse limit: input=%s, err=%v", str, err)
+		}
+		// NB: Prometheus treats a negative limit as no limit.
+		if limit > 0 {
+			query.Limit = limit
+		}
+	}
+	return query, nil
+}
+
+// matchesEmptyTarget returns whether the target selector matches a target
+// without any labels, the only target metadata written by remote write is
+// known for.
+func matchesEmptyTarget(selector string) (bool, error) {
+	if strings.TrimSpace(selector) == "" {
+		return true, nil
+	}
+
+	matchers, err := promparser.ParseMetricSelector(selector)
+	if err != nil {
+		return false, err
+	}
+	for _, m := range matchers {
+		if !m.Matches("") {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func renderMetadataResultsJSON(
+	w http.ResponseWriter,
+	metadata []prompb.MetricMetadata,
+) error {
+	jw := json.NewWriter(w)
+	jw.BeginObject()
+
+	jw.BeginObjectField("status")
+	jw.WriteString("success")
+
+	jw.BeginObjectField("data")
+	jw.BeginObject()
+	for _, m := range metadata {
+		jw.BeginObjectField(m.MetricFamilyName)
+		jw.BeginArray()
+		jw.BeginObject()
+		writeMetadataFields(jw, m)
+		jw.EndObject()
+		jw.EndArray()
+	}
+	jw.EndObject()
+
+	jw.EndObject()
+	return jw.Close()
+}
+
+func renderTargetsMetadataResultsJSON(
+	w http.ResponseWriter,
+	metadata []prompb.MetricMetadata,
+	includeMetric bool,
+) error {
+	jw := json.NewWriter(w)
+	jw.BeginObject()
+
+	jw.BeginObjectField("status")
+	jw.WriteString("success")
+
+	jw.BeginObjectField("data")
+	jw.BeginArray()
+	for _, m := range metadata {
+		jw.BeginObject()
+		jw.BeginObjectField("target")
+		jw.BeginObject()
+		jw.EndObject()
+		// NB: Prometheus only includes the metric name when the results
+		// are not for a single metric.
+		if includeMetric {
+			jw.BeginObjectField("metric")
+			jw.WriteString(m.MetricFamilyName)
+		}
+		writeMetadataFields(jw, m)
+		jw.EndObject()
+	}
+	jw.EndArray()
+
+	jw.EndObject()
+	return jw.Close()
+}
+
+func writeMetadataFields(jw json.Writer, m prompb.MetricMetadata) {
+	jw.BeginObjectField("type")
+	jw.WriteString(metricTypeNames[m.Type])
+	jw.BeginObjectField("help")
+	jw.WriteString(m.Help)
+	jw.BeginObjectField("unit")
+	jw.WriteString(m.Unit)
+}
@@ -0,0 +1,142 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package native
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/m3db/m3/src/cluster/kv/mem"
+	"github.com/m3db/m3/src/query/api/v1/options"
+	"github.com/m3db/m3/src/query/generated/proto/prompb"
+	"github.com/m3db/m3/src/query/storage/metricmetadata"
+	"github.com/m3db/m3/src/x/instrument"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newMetadataTestOptions(t *testing.T) options.HandlerOptions {
+	store := metricmetadata.NewKVStorage(mem.NewStore(), instrument.NewOptions())
+	require.NoError(t, store.WriteMetricMetadata(context.Background(),
+		[]prompb.MetricMetadata{
+			{
+				Type:             prompb.MetricType_COUNTER,
+				MetricFamilyName: "http_requests_total",
+				Help:             "Total number of HTTP requests.",
+			},
+			{
+				Type:             prompb.MetricType_GAUGE,
+				MetricFamilyName: "memory_usage",
+				Help:             "Memory usage.",
+				Unit:             "bytes",
+			},
+		}))
+	// NB: closing the storage writes the pending metadata to the KV store.
+	store.Close()
+	return options.EmptyHandlerOptions().SetMetricMetadataStorage(store)
+}
+
+func serveMetadata(
+	t *testing.T,
+	handler http.Handler,
+	path string,
+	params url.Values,
+) (int, string) {
+	req := httptest.NewRequest(http.MethodGet, path+"?"+params.Encode(), nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	body, err := ioutil.ReadAll(w.Body)
+	require.NoError(t, err)
+	return w.Code, string(body)
+}
+
+func TestMetadata(t *testing.T) {
+	handler := NewMetadataHandler(newMetadataTestOptions(t))
+
+	code, body := serveMetadata(t, handler, MetadataURL, url.Values{})
+	require.Equal(t, http.StatusOK, code)
+	expected := `{"status":"success","data":{` +
+		`"http_requests_total":[{"type":"counter","help":"Total number of HTTP requests.","unit":""}],` +
+		`"memory_usage":[{"type":"gauge","help":"Memory usage.","unit":"bytes"}]` +
+		`}}`
+	assert.Equal(t, expected, body)
+
+	code, body = serveMetadata(t, handler, MetadataURL,
+		url.Values{"metric": []string{"memory_usage"}})
+	require.Equal(t, http.StatusOK, code)
+	expected = `{"status":"success","data":{` +
+		`"memory_usage":[{"type":"gauge","help":"Memory usage.","unit":"bytes"}]` +
+		`}}`
+	assert.Equal(t, expected, body)
+
+	code, body = serveMetadata(t, handler, MetadataURL,
+		url.Values{"limit": []string{"1"}})
+	require.Equal(t, http.StatusOK, code)
+	expected = `{"status":"success","data":{` +
+		`"http_requests_total":[{"type":"counter","help":"Total number of HTTP requests.","unit":""}]` +
+		`}}`
+	assert.Equal(t, expected, body)
+
+	code, _ = serveMetadata(t, handler, MetadataURL,
+		url.Values{"limit": []string{"foo"}})
+	assert.Equal(t, http.StatusBadRequest, code)
+}
+
+func TestTargetsMetadata(t *testing.T) {
+	handler := NewTargetsMetadataHandler(newMetadataTestOptions(t))
+
+	code, body := serveMetadata(t, handler, TargetsMetadataURL, url.Values{
+		"match_target": []string{`{job=~".*"}`},
+		"limit":        []string{"1"},
+	})
+	require.Equal(t, http.StatusOK, code)
+	expected := `{"status":"success","data":[` +
+		`{"target":{},"metric":"http_requests_total","type":"counter","help":"Total number of HTTP requests.","unit":""}` +
+		`]}`
+	assert.Equal(t, expected, body)
+
+	code, body = serveMetadata(t, handler, TargetsMetadataURL,
+		url.Values{"metric": []string{"memory_usage"}})
+	require.Equal(t, http.StatusOK, code)
+	expected = `{"status":"success","data":[` +
+		`{"target":{},"type":"gauge","help":"Memory usage.","unit":"bytes"}` +
+		`]}`
+	assert.Equal(t, expected, body)
+
+	// Remote write does not send targets, no metadata matches a selector
+	// that requires a target label.
+	code, body = serveMetadata(t, handler, TargetsMetadataURL,
+		url.Values{"match_target": []string{`{job="prometheus"}`}})
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, `{"status":"success","data":[]}`, body)
+}
+
+func TestMetadataNoStorage(t *testing.T) {
+	handler := NewMetadataHandler(options.EmptyHandlerOptions())
+	code, _ := serveMetadata(t, handler, MetadataURL, url.Values{})
+	assert.Equal(t, http.StatusInternalServerError, code)
+}
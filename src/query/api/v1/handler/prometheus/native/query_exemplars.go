@@ -0,0 +1,219 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package native
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+
+	"github.com/m3db/m3/src/query/api/v1/handler/prometheus"
+	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/handleroptions"
+	"github.com/m3db/m3/src/query/api/v1/options"
+	"github.com/m3db/m3/src/query/api/v1/route"
+	"github.com/m3db/m3/src/query/functions/utils"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/parser/promql"
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/query/util/json"
+	"github.com/m3db/m3/src/query/util/logging"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	"github.com/m3db/m3/src/x/instrument"
+	xhttp "github.com/m3db/m3/src/x/net/http"
+
+	promparser "github.com/prometheus/prometheus/promql/parser"
+	"go.uber.org/zap"
+)
+
+const (
+	// QueryExemplarsURL is the url for the query exemplars endpoint.
+	QueryExemplarsURL = route.QueryExemplarsURL
+
+	queryParam = "query"
+)
+
+var (
+	// QueryExemplarsHTTPMethods are the HTTP methods for this handler.
+	QueryExemplarsHTTPMethods = []string{http.MethodGet, http.MethodPost}
+
+	errNoExemplarStorage = errors.New("no exemplar storage configured")
+	errNoQueryParam      = errors.New("query param is required")
+)
+
+// QueryExemplarsHandler returns the exemplars of the series selected by a
+// PromQL expression in the Prometheus exemplar query response format.
+type QueryExemplarsHandler struct {
+	storage             storage.ExemplarStorage
+	fetchOptionsBuilder handleroptions.FetchOptionsBuilder
+	parseOpts           promql.ParseOptions
+	tagOpts             models.TagOptions
+	instrumentOpts      instrument.Options
+}
+
+// NewQueryExemplarsHandler returns a new instance of handler.
+func NewQueryExemplarsHandler(opts options.HandlerOptions) http.Handler {
+	return &QueryExemplarsHandler{
+		storage:             opts.ExemplarStorage(),
+		fetchOptionsBuilder: opts.FetchOptionsBuilder(),
+		parseOpts: promql.NewParseOptions().
+			SetNowFn(opts.NowFn()),
+		tagOpts:        opts.TagOptions(),
+		instrumentOpts: opts.InstrumentOpts(),
+	}
+}
+
+func (h *QueryExemplarsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(xhttp.HeaderContentType, xhttp.ContentTypeJSON)
+
+	if h.storage == nil {
+		xhttp.WriteError(w, errNoExemplarStorage)
+		return
+	}
+
+	ctx, fetchOpts, rErr := h.fetchOptionsBuilder.NewFetchOptions(r.Context(), r)
+	if rErr != nil {
+		xhttp.WriteError(w, rErr)
+		return
+	}
+
+	start, end, err := prometheus.ParseStartAndEnd(r, h.parseOpts)
+	if err != nil {
+		xhttp.WriteError(w, err)
+		return
+	}
+
+	query := r.FormValue(queryParam)
+	if query == "" {
+		xhttp.WriteError(w, xerrors.NewInvalidParamsError(errNoQueryParam))
+		return
+	}
+
+	expr, err := h.parseOpts.ParseFn()(query)
+	if err != nil {
+		xhttp.WriteError(w, xerrors.NewInvalidParamsError(err))
+		return
+	}
+
+	logger := logging.WithContext(ctx, h.instrumentOpts)
+
+	// Each selector of the expression is fetched separately, series that are
+	// matched by more than one selector are only returned once.
+	var (
+		results []storage.SeriesExemplars
+		seen    = make(map[string]struct{})
+	)
+	for _, selector := range promparser.ExtractSelectors(expr) {
+		matchers, err := promql.LabelMatchersToModelMatcher(selector, h.tagOpts)
+		if err != nil {
+			xhttp.WriteError(w, xerrors.NewInvalidParamsError(err))
+			return
+		}
+
+		fetchQuery := &storage.FetchQuery{
+			Raw:         query,
+			TagMatchers: matchers,
+			Start:       start,
+			End:         end,
+		}
+		series, err := h.storage.FetchExemplars(ctx, fetchQuery, fetchOpts)
+		if err != nil {
+			logger.Error("unable to fetch exemplars", zap.Error(err))
+			xhttp.WriteError(w, err)
+			return
+		}
+
+		for _, elem := range series {
+			id := string(elem.Tags.ID())
+			if _, ok := seen[id]; ok {
+				continue
+			}
+			seen[id] = struct{}{}
+			results = append(results, elem)
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return string(results[i].Tags.ID()) < string(results[j].Tags.ID())
+	})
+
+	if err := renderQueryExemplarsResultsJSON(w, results); err != nil {
+		logger.Error("unable to render exemplars results", zap.Error(err))
+	}
+}
+
+func renderQueryExemplarsResultsJSON(
+	w http.ResponseWriter,
+	results []storage.SeriesExemplars,
+) error {
+	jw := json.NewWriter(w)
+	jw.BeginObject()
+
+	jw.BeginObjectField("status")
+	jw.WriteString("success")
+
+	jw.BeginObjectField("data")
+	jw.BeginArray()
+
+	for _, result := range results {
+		if len(result.Exemplars) == 0 {
+			continue
+		}
+
+		jw.BeginObject()
+		jw.BeginObjectField("seriesLabels")
+		jw.BeginObject()
+		for _, t := range result.Tags.Tags {
+			jw.BeginObjectBytesField(t.Name)
+			jw.WriteBytesString(t.Value)
+		}
+		jw.EndObject()
+
+		jw.BeginObjectField("exemplars")
+		jw.BeginArray()
+		for _, exemplar := range result.Exemplars {
+			jw.BeginObject()
+			jw.BeginObjectField("labels")
+			jw.BeginObject()
+			for _, l := range exemplar.Labels {
+				jw.BeginObjectBytesField(l.Name)
+				jw.WriteBytesString(l.Value)
+			}
+			jw.EndObject()
+
+			jw.BeginObjectField("value")
+			jw.WriteString(utils.FormatFloat(exemplar.Value))
+
+			// NB: Prometheus renders exemplar timestamps as fractional
+			// seconds with millisecond precision.
+			jw.BeginObjectField("timestamp")
+			jw.WriteFloat64(float64(int64(exemplar.TimestampNanos)/1e6) / 1e3)
+			jw.EndObject()
+		}
+		jw.EndArray()
+
+		jw.EndObject()
+	}
+
+	jw.EndArray()
+	jw.EndObject()
+
+	return jw.Close()
+}
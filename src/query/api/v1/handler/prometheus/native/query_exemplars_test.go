@@ -0,0 +1,128 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package native
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/client"
+	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/handleroptions"
+	"github.com/m3db/m3/src/query/api/v1/options"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/storage/m3"
+	"github.com/m3db/m3/src/x/ident"
+	xtest "github.com/m3db/m3/src/x/test"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newQueryExemplarsTestHandler(
+	t *testing.T,
+	session client.Session,
+) http.Handler {
+	clusters, err := m3.NewClusters(m3.UnaggregatedClusterNamespaceDefinition{
+		NamespaceID: ident.StringID("test-ns"),
+		Session:     session,
+		Retention:   24 * time.Hour,
+	})
+	require.NoError(t, err)
+
+	fb, err := handleroptions.NewFetchOptionsBuilder(
+		handleroptions.FetchOptionsBuilderOptions{Timeout: 15 * time.Second})
+	require.NoError(t, err)
+
+	tagOpts := models.NewTagOptions()
+	opts := options.EmptyHandlerOptions().
+		SetExemplarStorage(m3.NewExemplarStorage(clusters, tagOpts)).
+		SetFetchOptionsBuilder(fb).
+		SetNowFn(time.Now).
+		SetTagOptions(tagOpts)
+	return NewQueryExemplarsHandler(opts)
+}
+
+func TestQueryExemplars(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	session := client.NewMockSession(ctrl)
+	result := []client.SeriesExemplars{
+		{
+			ID:   ident.StringID("foo"),
+			Tags: ident.NewTags(ident.StringTag("__name__", "foo")),
+			Exemplars: []ts.Exemplar{
+				{
+					TimestampNanos: 1600096945479000000,
+					Value:          1.5,
+					Labels: []ts.ExemplarLabel{
+						{Name: []byte("trace_id"), Value: []byte("abc")},
+					},
+				},
+			},
+		},
+	}
+	// Both selectors match the same series which is only returned once.
+	session.EXPECT().
+		FetchExemplars(gomock.Any(), ident.NewIDMatcher("test-ns"),
+			gomock.Any(), gomock.Any()).
+		Return(result, client.FetchResponseMetadata{Exhaustive: true}, nil).
+		Times(2)
+
+	handler := newQueryExemplarsTestHandler(t, session)
+
+	params := url.Values{}
+	params.Set("query", `foo + foo{bar!="baz"}`)
+	params.Set("start", "1600096900")
+	params.Set("end", "1600097000")
+	req := httptest.NewRequest(http.MethodGet,
+		QueryExemplarsURL+"?"+params.Encode(), nil)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	body, err := ioutil.ReadAll(w.Body)
+	require.NoError(t, err)
+	expected := `{"status":"success","data":[{` +
+		`"seriesLabels":{"__name__":"foo"},` +
+		`"exemplars":[{"labels":{"trace_id":"abc"},"value":"1.5","timestamp":1600096945.479000}]` +
+		`}]}`
+	assert.Equal(t, expected, string(body))
+}
+
+func TestQueryExemplarsRequiresQuery(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	handler := newQueryExemplarsTestHandler(t, client.NewMockSession(ctrl))
+
+	req := httptest.NewRequest(http.MethodGet, QueryExemplarsURL, nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
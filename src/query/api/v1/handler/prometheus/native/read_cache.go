@@ -0,0 +1,173 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package native
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	pql "github.com/prometheus/prometheus/promql/parser"
+
+	"github.com/m3db/m3/src/query/block"
+	"github.com/m3db/m3/src/query/cache"
+	"github.com/m3db/m3/src/query/storage"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+// readWithCache executes a range query using the result cache, if set, to
+// only execute the extents of the query that are not already cached.
+func (h *promReadHandler) readWithCache(
+	ctx context.Context,
+	parsed ParsedOptions,
+) (ReadResult, error) {
+	resultCache := h.opts.ResultCache()
+	if h.instant || resultCache == nil {
+		return read(ctx, parsed, h.opts)
+	}
+
+	params := parsed.Params
+	if params.Step <= 0 {
+		return read(ctx, parsed, h.opts)
+	}
+
+	steps := int64(params.ExclusiveEnd().Sub(params.Start) / params.Step)
+	if steps < 1 {
+		return read(ctx, parsed, h.opts)
+	}
+
+	parseFn := h.opts.Engine().Options().ParseOptions().ParseFn()
+	expr, err := parseFn(params.Query)
+	if err != nil {
+		return ReadResult{}, xerrors.NewInvalidParamsError(err)
+	}
+
+	if dependsOnQueryRange(expr) {
+		// The result of every step depends on the full range of the query, so
+		// results from a differing query range cannot be reused.
+		return read(ctx, parsed, h.opts)
+	}
+
+	var (
+		blockType = block.BlockEmpty
+		query     = cache.Query{
+			Key:   resultCacheKey(parsed),
+			Start: params.Start,
+			End:   params.Start.Add(params.Step * time.Duration(steps-1)),
+			Step:  params.Step,
+		}
+	)
+	result, err := resultCache.Query(ctx, query, func(
+		ctx context.Context,
+		start, end xtime.UnixNano,
+	) (cache.Result, error) {
+		extentParsed := parsed
+		extentParsed.Params.Start = start
+		extentParsed.Params.End = end
+		extentParsed.Params.IncludeEnd = true
+
+		res, err := read(ctx, extentParsed, h.opts)
+		if err != nil {
+			return cache.Result{}, err
+		}
+
+		blockType = res.BlockType
+		return cache.Result{Series: res.Series, Meta: res.Meta}, nil
+	})
+	if err != nil {
+		return ReadResult{}, err
+	}
+
+	return ReadResult{
+		Series:    result.Series,
+		Meta:      result.Meta,
+		BlockType: blockType,
+	}, nil
+}
+
+// dependsOnQueryRange returns true if the expression uses the start or end of
+// the query range with the @ modifier.
+func dependsOnQueryRange(expr pql.Expr) bool {
+	var found bool
+	pql.Inspect(expr, func(node pql.Node, _ []pql.Node) error {
+		switch n := node.(type) {
+		case *pql.VectorSelector:
+			found = found || n.StartOrEnd == pql.START || n.StartOrEnd == pql.END
+		case *pql.SubqueryExpr:
+			found = found || n.StartOrEnd == pql.START || n.StartOrEnd == pql.END
+		}
+		return nil
+	})
+	return found
+}
+
+// resultCacheKey returns the key identifying the query and the options that
+// change the values of its result.
+func resultCacheKey(parsed ParsedOptions) string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "query=%s", parsed.Params.Query)
+	fmt.Fprintf(&buf, ",lookback=%s", parsed.Params.LookbackDuration)
+
+	if opts := parsed.QueryOpts; opts != nil {
+		if r := opts.QueryContextOptions.RestrictFetchType; r != nil {
+			fmt.Fprintf(&buf, ",fetchType=%d:%s", r.MetricsType, r.StoragePolicy)
+		}
+	}
+
+	fetchOpts := parsed.FetchOpts
+	if fetchOpts == nil {
+		return buf.String()
+	}
+
+	if f := fetchOpts.FanoutOptions; f != nil {
+		fmt.Fprintf(&buf, ",fanout=%d:%d:%d", f.FanoutUnaggregated,
+			f.FanoutAggregated, f.FanoutAggregatedOptimized)
+	}
+
+	r := fetchOpts.RestrictQueryOptions
+	if r == nil {
+		return buf.String()
+	}
+
+	if t := r.RestrictByType; t != nil {
+		writeRestrictByType(&buf, t)
+	}
+	for _, t := range r.RestrictByTypes {
+		writeRestrictByType(&buf, t)
+	}
+	if t := r.RestrictByTag; t != nil {
+		fmt.Fprintf(&buf, ",restrictTags=%s", t.Restrict)
+		for _, strip := range t.Strip {
+			fmt.Fprintf(&buf, ",strip=%s", strip)
+		}
+	}
+
+	return buf.String()
+}
+
+func writeRestrictByType(buf *bytes.Buffer, t *storage.RestrictByType) {
+	if t == nil {
+		return
+	}
+	fmt.Fprintf(buf, ",restrictType=%s:%s", t.MetricsType, t.StoragePolicy)
+}
@@ -0,0 +1,88 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package native
+
+import (
+	"testing"
+	"time"
+
+	pql "github.com/prometheus/prometheus/promql/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/storage"
+)
+
+func TestDependsOnQueryRange(t *testing.T) {
+	tests := []struct {
+		query    string
+		expected bool
+	}{
+		{query: "up", expected: false},
+		{query: "rate(up[5m] @ 1609746000)", expected: false},
+		{query: "up @ start()", expected: true},
+		{query: "sum(rate(up[5m] @ end()))", expected: true},
+		{query: "max_over_time(rate(up[1m])[10m:1m] @ end())", expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			expr, err := pql.ParseExpr(tt.query)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, dependsOnQueryRange(expr))
+		})
+	}
+}
+
+func TestResultCacheKey(t *testing.T) {
+	lookback := time.Minute
+	parsed := ParsedOptions{
+		FetchOpts: storage.NewFetchOptions(),
+		Params: models.RequestParams{
+			Query:            "up",
+			LookbackDuration: lookback,
+		},
+	}
+	key := resultCacheKey(parsed)
+
+	other := parsed
+	other.Params.Query = "down"
+	assert.NotEqual(t, key, resultCacheKey(other))
+
+	other = parsed
+	other.Params.LookbackDuration = 2 * lookback
+	assert.NotEqual(t, key, resultCacheKey(other))
+
+	other = parsed
+	other.FetchOpts = storage.NewFetchOptions()
+	other.FetchOpts.RestrictQueryOptions = &storage.RestrictQueryOptions{
+		RestrictByTag: &storage.RestrictByTag{
+			Strip: [][]byte{[]byte("foo")},
+		},
+	}
+	assert.NotEqual(t, key, resultCacheKey(other))
+
+	// Time range of the query does not change the key.
+	other = parsed
+	other.Params.Start = parsed.Params.Start.Add(time.Hour)
+	assert.Equal(t, key, resultCacheKey(other))
+}
@@ -0,0 +1,303 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package native
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/m3db/m3/src/query/api/v1/options"
+	"github.com/m3db/m3/src/query/api/v1/route"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/ruler"
+	"github.com/m3db/m3/src/query/util/json"
+	"github.com/m3db/m3/src/query/util/logging"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	"github.com/m3db/m3/src/x/instrument"
+	xhttp "github.com/m3db/m3/src/x/net/http"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// RulesURL is the url for the recording and alerting rules endpoint.
+	RulesURL = route.RulesURL
+
+	// AlertsURL is the url for the active alerts endpoint.
+	AlertsURL = route.AlertsURL
+
+	ruleTypeParam       = "type"
+	alertingRuleType    = "alert"
+	recordingRuleType   = "record"
+	alertingRuleFormat  = "alerting"
+	recordingRuleFormat = "recording"
+)
+
+// RulesHTTPMethods are the HTTP methods for the rules and alerts handlers.
+var RulesHTTPMethods = []string{http.MethodGet}
+
+// RulesHandler returns the rule groups evaluated by the rule manager in the
+// Prometheus rules response format.
+type RulesHandler struct {
+	manager        ruler.Manager
+	instrumentOpts instrument.Options
+}
+
+// NewRulesHandler returns a new instance of handler for the rules endpoint.
+func NewRulesHandler(opts options.HandlerOptions) http.Handler {
+	return &RulesHandler{
+		manager:        opts.RuleManager(),
+		instrumentOpts: opts.InstrumentOpts(),
+	}
+}
+
+func (h *RulesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(xhttp.HeaderContentType, xhttp.ContentTypeJSON)
+
+	ruleType := r.FormValue(ruleTypeParam)
+	if ruleType != "" && ruleType != alertingRuleType && ruleType != recordingRuleType {
+		xhttp.WriteError(w, xerrors.NewInvalidParamsError(
+			fmt.Errorf("invalid rule type: %s", ruleType)))
+		return
+	}
+
+	var groups []*ruler.Group
+	if h.manager != nil {
+		groups = h.manager.RuleGroups()
+	}
+
+	if err := renderRulesResultsJSON(w, groups, ruleType); err != nil {
+		logger := logging.WithContext(r.Context(), h.instrumentOpts)
+		logger.Error("unable to render rules results", zap.Error(err))
+	}
+}
+
+// AlertsHandler returns the active alerts of the rule manager in the
+// Prometheus alerts response format.
+type AlertsHandler struct {
+	manager        ruler.Manager
+	instrumentOpts instrument.Options
+}
+
+// NewAlertsHandler returns a new instance of handler for the alerts endpoint.
+func NewAlertsHandler(opts options.HandlerOptions) http.Handler {
+	return &AlertsHandler{
+		manager:        opts.RuleManager(),
+		instrumentOpts: opts.InstrumentOpts(),
+	}
+}
+
+func (h *AlertsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(xhttp.HeaderContentType, xhttp.ContentTypeJSON)
+
+	var alerts []*ruler.Alert
+	if h.manager != nil {
+		for _, rule := range h.manager.AlertingRules() {
+			alerts = append(alerts, rule.ActiveAlerts()...)
+		}
+	}
+
+	if err := renderAlertsResultsJSON(w, alerts); err != nil {
+		logger := logging.WithContext(r.Context(), h.instrumentOpts)
+		logger.Error("unable to render alerts results", zap.Error(err))
+	}
+}
+
+func renderRulesResultsJSON(
+	w http.ResponseWriter,
+	groups []*ruler.Group,
+	ruleType string,
+) error {
+	jw := json.NewWriter(w)
+	jw.BeginObject()
+
+	jw.BeginObjectField("status")
+	jw.WriteString("success")
+
+	jw.BeginObjectField("data")
+	jw.BeginObject()
+	jw.BeginObjectField("groups")
+	jw.BeginArray()
+	for _, group := range groups {
+		jw.BeginObject()
+		jw.BeginObjectField("name")
+		jw.WriteString(group.Name())
+		jw.BeginObjectField("file")
+		jw.WriteString(group.File())
+
+		jw.BeginObjectField("rules")
+		jw.BeginArray()
+		for _, rule := range group.Rules() {
+			switch rule := rule.(type) {
+			case *ruler.AlertingRule:
+				if ruleType == recordingRuleType {
+					continue
+				}
+				writeAlertingRule(jw, rule)
+			default:
+				if ruleType == alertingRuleType {
+					continue
+				}
+				writeRecordingRule(jw, rule)
+			}
+		}
+		jw.EndArray()
+
+		jw.BeginObjectField("interval")
+		jw.WriteFloat64(group.Interval().Seconds())
+		jw.BeginObjectField("evaluationTime")
+		jw.WriteFloat64(group.EvaluationDuration().Seconds())
+		jw.BeginObjectField("lastEvaluation")
+		writeTime(jw, group.LastEvaluation())
+		jw.EndObject()
+	}
+	jw.EndArray()
+	jw.EndObject()
+
+	jw.EndObject()
+	return jw.Close()
+}
+
+func writeAlertingRule(jw json.Writer, rule *ruler.AlertingRule) {
+	jw.BeginObject()
+	jw.BeginObjectField("state")
+	jw.WriteString(rule.State().String())
+	jw.BeginObjectField("name")
+	jw.WriteString(rule.Name())
+	jw.BeginObjectField("query")
+	jw.WriteString(rule.Query())
+	jw.BeginObjectField("duration")
+	jw.WriteFloat64(rule.HoldDuration().Seconds())
+	jw.BeginObjectField("labels")
+	writeStringMap(jw, rule.Labels())
+	jw.BeginObjectField("annotations")
+	writeStringMap(jw, rule.Annotations())
+
+	jw.BeginObjectField("alerts")
+	jw.BeginArray()
+	for _, alert := range rule.ActiveAlerts() {
+		writeAlert(jw, alert)
+	}
+	jw.EndArray()
+
+	writeRuleEvaluation(jw, rule)
+	jw.BeginObjectField("type")
+	jw.WriteString(alertingRuleFormat)
+	jw.EndObject()
+}
+
+func writeRecordingRule(jw json.Writer, rule ruler.Rule) {
+	jw.BeginObject()
+	jw.BeginObjectField("name")
+	jw.WriteString(rule.Name())
+	jw.BeginObjectField("query")
+	jw.WriteString(rule.Query())
+	if labels := rule.Labels(); len(labels) > 0 {
+		jw.BeginObjectField("labels")
+		writeStringMap(jw, labels)
+	}
+
+	writeRuleEvaluation(jw, rule)
+	jw.BeginObjectField("type")
+	jw.WriteString(recordingRuleFormat)
+	jw.EndObject()
+}
+
+func writeRuleEvaluation(jw json.Writer, rule ruler.Rule) {
+	jw.BeginObjectField("health")
+	jw.WriteString(string(rule.Health()))
+	if err := rule.LastError(); err != nil {
+		jw.BeginObjectField("lastError")
+		jw.WriteString(err.Error())
+	}
+	jw.BeginObjectField("evaluationTime")
+	jw.WriteFloat64(rule.EvaluationDuration().Seconds())
+	jw.BeginObjectField("lastEvaluation")
+	writeTime(jw, rule.LastEvaluation())
+}
+
+func renderAlertsResultsJSON(w http.ResponseWriter, alerts []*ruler.Alert) error {
+	jw := json.NewWriter(w)
+	jw.BeginObject()
+
+	jw.BeginObjectField("status")
+	jw.WriteString("success")
+
+	jw.BeginObjectField("data")
+	jw.BeginObject()
+	jw.BeginObjectField("alerts")
+	jw.BeginArray()
+	for _, alert := range alerts {
+		writeAlert(jw, alert)
+	}
+	jw.EndArray()
+	jw.EndObject()
+
+	jw.EndObject()
+	return jw.Close()
+}
+
+func writeAlert(jw json.Writer, alert *ruler.Alert) {
+	jw.BeginObject()
+	jw.BeginObjectField("labels")
+	writeTags(jw, alert.Labels)
+	jw.BeginObjectField("annotations")
+	writeStringMap(jw, alert.Annotations)
+	jw.BeginObjectField("state")
+	jw.WriteString(alert.State.String())
+	jw.BeginObjectField("activeAt")
+	writeTime(jw, alert.ActiveAt)
+	// NB: Prometheus renders alert values as strings.
+	jw.BeginObjectField("value")
+	jw.WriteString(strconv.FormatFloat(alert.Value, 'e', -1, 64))
+	jw.EndObject()
+}
+
+func writeTags(jw json.Writer, tags models.Tags) {
+	jw.BeginObject()
+	for _, tag := range tags.Tags {
+		jw.BeginObjectBytesField(tag.Name)
+		jw.WriteBytesString(tag.Value)
+	}
+	jw.EndObject()
+}
+
+func writeStringMap(jw json.Writer, m map[string]string) {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	jw.BeginObject()
+	for _, name := range names {
+		jw.BeginObjectField(name)
+		jw.WriteString(m[name])
+	}
+	jw.EndObject()
+}
+
+func writeTime(jw json.Writer, t time.Time) {
+	jw.WriteString(t.UTC().Format(time.RFC3339Nano))
+}
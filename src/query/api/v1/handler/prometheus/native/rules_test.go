@@ -0,0 +1,167 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package native
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/cmd/services/m3coordinator/ingest"
+	"github.com/m3db/m3/src/query/api/v1/options"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/ruler"
+	"github.com/m3db/m3/src/x/clock"
+	xtest "github.com/m3db/m3/src/x/test"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testRuleManager struct {
+	groups []*ruler.Group
+}
+
+func (m *testRuleManager) Start() error { return nil }
+
+func (m *testRuleManager) RuleGroups() []*ruler.Group { return m.groups }
+
+func (m *testRuleManager) AlertingRules() []*ruler.AlertingRule {
+	var rules []*ruler.AlertingRule
+	for _, group := range m.groups {
+		for _, rule := range group.Rules() {
+			if alertingRule, ok := rule.(*ruler.AlertingRule); ok {
+				rules = append(rules, alertingRule)
+			}
+		}
+	}
+	return rules
+}
+
+func (m *testRuleManager) Close() error { return nil }
+
+func newRulesTestOptions(ctrl *gomock.Controller) options.HandlerOptions {
+	now := time.Unix(1600000000, 0)
+	writer := ingest.NewMockDownsamplerAndWriter(ctrl)
+	writer.EXPECT().
+		Write(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+			gomock.Any(), gomock.Any()).
+		Return(nil).
+		AnyTimes()
+
+	opts := ruler.NewOptions().
+		SetWriter(writer).
+		SetClockOptions(clock.NewOptions().SetNowFn(func() time.Time {
+			return now
+		})).
+		SetQueryFunc(func(
+			_ context.Context,
+			query string,
+			_ time.Time,
+		) (ruler.Vector, error) {
+			tags := models.NewTags(1, models.NewTagOptions()).
+				AddTag(models.Tag{Name: []byte("job"), Value: []byte("api")})
+			return ruler.Vector{{Tags: tags, Value: 12}}, nil
+		})
+
+	group := ruler.NewGroup("requests", "rules.yml", time.Minute, []ruler.Rule{
+		ruler.NewRecordingRule("job:requests:sum", "sum(requests) by (job)",
+			map[string]string{"env": "prod"}, opts),
+		ruler.NewAlertingRule("TooManyRequests", "job:requests:sum > 10",
+			5*time.Minute, map[string]string{"severity": "page"},
+			map[string]string{"summary": "{{ $labels.job }} is busy"}, opts),
+	}, opts)
+	group.Eval(context.Background(), now)
+
+	return options.EmptyHandlerOptions().
+		SetRuleManager(&testRuleManager{groups: []*ruler.Group{group}})
+}
+
+func TestRules(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	handler := NewRulesHandler(newRulesTestOptions(ctrl))
+
+	code, body := serveMetadata(t, handler, RulesURL, url.Values{})
+	require.Equal(t, http.StatusOK, code)
+	expected := `{"status":"success","data":{"groups":[{` +
+		`"name":"requests","file":"rules.yml","rules":[` +
+		`{"name":"job:requests:sum","query":"sum(requests) by (job)",` +
+		`"labels":{"env":"prod"},"health":"ok","evaluationTime":0.000000,` +
+		`"lastEvaluation":"2020-09-13T12:26:40Z","type":"recording"},` +
+		`{"state":"pending","name":"TooManyRequests","query":"job:requests:sum > 10",` +
+		`"duration":300.000000,"labels":{"severity":"page"},` +
+		`"annotations":{"summary":"{{ $labels.job }} is busy"},` +
+		`"alerts":[{"labels":{"alertname":"TooManyRequests","job":"api","severity":"page"},` +
+		`"annotations":{"summary":"api is busy"},"state":"pending",` +
+		`"activeAt":"2020-09-13T12:26:40Z","value":"1.2e+01"}],` +
+		`"health":"ok","evaluationTime":0.000000,` +
+		`"lastEvaluation":"2020-09-13T12:26:40Z","type":"alerting"}],` +
+		`"interval":60.000000,"evaluationTime":0.000000,"lastEvaluation":"2020-09-13T12:26:40Z"}]}}`
+	assert.Equal(t, expected, body)
+
+	code, body = serveMetadata(t, handler, RulesURL,
+		url.Values{"type": []string{"record"}})
+	require.Equal(t, http.StatusOK, code)
+	assert.Contains(t, body, `"type":"recording"`)
+	assert.NotContains(t, body, `"type":"alerting"`)
+
+	code, body = serveMetadata(t, handler, RulesURL,
+		url.Values{"type": []string{"alert"}})
+	require.Equal(t, http.StatusOK, code)
+	assert.NotContains(t, body, `"type":"recording"`)
+	assert.Contains(t, body, `"type":"alerting"`)
+
+	code, _ = serveMetadata(t, handler, RulesURL,
+		url.Values{"type": []string{"foo"}})
+	assert.Equal(t, http.StatusBadRequest, code)
+}
+
+func TestAlerts(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	handler := NewAlertsHandler(newRulesTestOptions(ctrl))
+
+	code, body := serveMetadata(t, handler, AlertsURL, url.Values{})
+	require.Equal(t, http.StatusOK, code)
+	expected := `{"status":"success","data":{"alerts":[` +
+		`{"labels":{"alertname":"TooManyRequests","job":"api","severity":"page"},` +
+		`"annotations":{"summary":"api is busy"},"state":"pending",` +
+		`"activeAt":"2020-09-13T12:26:40Z","value":"1.2e+01"}]}}`
+	assert.Equal(t, expected, body)
+}
+
+func TestRulesNoManager(t *testing.T) {
+	code, body := serveMetadata(t, NewRulesHandler(options.EmptyHandlerOptions()),
+		RulesURL, url.Values{})
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, `{"status":"success","data":{"groups":[]}}`, body)
+
+	code, body = serveMetadata(t, NewAlertsHandler(options.EmptyHandlerOptions()),
+		AlertsURL, url.Values{})
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, `{"status":"success","data":{"alerts":[]}}`, body)
+}
@@ -0,0 +1,215 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package native
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/handleroptions"
+	"github.com/m3db/m3/src/query/api/v1/options"
+	"github.com/m3db/m3/src/query/api/v1/route"
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/query/util"
+	"github.com/m3db/m3/src/query/util/json"
+	"github.com/m3db/m3/src/query/util/logging"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	"github.com/m3db/m3/src/x/instrument"
+	xhttp "github.com/m3db/m3/src/x/net/http"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// StatusTSDBURL is the url for the TSDB cardinality status endpoint.
+	StatusTSDBURL = route.StatusTSDBURL
+
+	// defaultStatusTSDBRange is the time range analysed when no start is
+	// given, matching the range of the Prometheus head block.
+	defaultStatusTSDBRange = 2 * time.Hour
+	defaultStatusTSDBLimit = 10
+)
+
+var (
+	// StatusTSDBHTTPMethods are the HTTP methods for this handler.
+	StatusTSDBHTTPMethods = []string{http.MethodGet}
+
+	errNoCardinalityStorage = errors.New("no cardinality storage configured")
+)
+
+// StatusTSDBHandler returns the cardinality statistics of the stored series
+// in the Prometheus TSDB status response format.
+type StatusTSDBHandler struct {
+	storage             storage.CardinalityStorage
+	fetchOptionsBuilder handleroptions.FetchOptionsBuilder
+	nowFn               func() time.Time
+	instrumentOpts      instrument.Options
+}
+
+// NewStatusTSDBHandler returns a new instance of handler.
+func NewStatusTSDBHandler(opts options.HandlerOptions) http.Handler {
+	return &StatusTSDBHandler{
+		storage:             opts.CardinalityStorage(),
+		fetchOptionsBuilder: opts.FetchOptionsBuilder(),
+		nowFn:               opts.NowFn(),
+		instrumentOpts:      opts.InstrumentOpts(),
+	}
+}
+
+func (h *StatusTSDBHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(xhttp.HeaderContentType, xhttp.ContentTypeJSON)
+
+	if h.storage == nil {
+		xhttp.WriteError(w, errNoCardinalityStorage)
+		return
+	}
+
+	// The limit parameter of this endpoint is the number of entries of each
+	// statistic as with Prometheus rather than the series limit, so series
+	// and docs limits are only taken from headers and the configured limits.
+	if err := r.ParseForm(); err != nil {
+		xhttp.WriteError(w, xerrors.NewInvalidParamsError(err))
+		return
+	}
+	fetchReq := r.Clone(r.Context())
+	fetchReq.Form.Del(limitParam)
+	ctx, fetchOpts, rErr := h.fetchOptionsBuilder.NewFetchOptions(r.Context(), fetchReq)
+	if rErr != nil {
+		xhttp.WriteError(w, rErr)
+		return
+	}
+
+	query, err := h.parseQuery(r)
+	if err != nil {
+		xhttp.WriteError(w, xerrors.NewInvalidParamsError(err))
+		return
+	}
+
+	logger := logging.WithContext(ctx, h.instrumentOpts)
+	result, err := h.storage.Cardinality(ctx, query, fetchOpts)
+	if err != nil {
+		logger.Error("unable to fetch cardinality", zap.Error(err))
+		xhttp.WriteError(w, err)
+		return
+	}
+
+	err = handleroptions.AddDBResultResponseHeaders(w, result.Metadata, fetchOpts)
+	if err != nil {
+		logger.Error("error writing database limit headers", zap.Error(err))
+		xhttp.WriteError(w, err)
+		return
+	}
+
+	if err := renderStatusTSDBResultsJSON(w, query, result); err != nil {
+		logger.Error("unable to render cardinality results", zap.Error(err))
+	}
+}
+
+func (h *StatusTSDBHandler) parseQuery(r *http.Request) (*storage.CardinalityQuery, error) {
+	end, err := util.ParseTimeStringWithDefault(r.FormValue("end"), h.nowFn())
+	if err != nil {
+		return nil, err
+	}
+
+	start, err := util.ParseTimeStringWithDefault(r.FormValue("start"),
+		end.Add(-defaultStatusTSDBRange))
+	if err != nil {
+		return nil, err
+	}
+
+	if start.After(end) {
+		return nil, fmt.Errorf("start %v must be before end %v", start, end)
+	}
+
+	query := &storage.CardinalityQuery{
+		Start: start,
+		End:   end,
+		Limit: defaultStatusTSDBLimit,
+	}
+	if str := r.FormValue(limitParam); str != "" {
+		limit, err := strconv.Atoi(str)
+		if err != nil || limit <= 0 {
+			return nil, fmt.Errorf("limit must be a positive integer: input=%s", str)
+		}
+		query.Limit = limit
+	}
+	return query, nil
+}
+
+func renderStatusTSDBResultsJSON(
+	w http.ResponseWriter,
+	query *storage.CardinalityQuery,
+	result *storage.CardinalityResult,
+) error {
+	jw := json.NewWriter(w)
+	jw.BeginObject()
+
+	jw.BeginObjectField("status")
+	jw.WriteString("success")
+
+	jw.BeginObjectField("data")
+	jw.BeginObject()
+
+	jw.BeginObjectField("headStats")
+	jw.BeginObject()
+	jw.BeginObjectField("numSeries")
+	jw.WriteInt(int(result.NumSeries))
+	jw.BeginObjectField("minTime")
+	jw.WriteInt(int(query.Start.UnixNano() / int64(time.Millisecond)))
+	jw.BeginObjectField("maxTime")
+	jw.WriteInt(int(query.End.UnixNano() / int64(time.Millisecond)))
+	jw.EndObject()
+
+	renderCardinalityStatsJSON(jw, "seriesCountByMetricName",
+		result.SeriesCountByMetricName)
+	renderCardinalityStatsJSON(jw, "labelValueCountByLabelName",
+		result.LabelValueCountByLabelName)
+	renderCardinalityStatsJSON(jw, "memoryInBytesByLabelName",
+		result.MemoryInBytesByLabelName)
+	renderCardinalityStatsJSON(jw, "seriesCountByLabelValuePair",
+		result.SeriesCountByLabelValuePair)
+
+	jw.EndObject()
+
+	jw.EndObject()
+	return jw.Close()
+}
+
+func renderCardinalityStatsJSON(
+	jw json.Writer,
+	field string,
+	stats []storage.CardinalityStat,
+) {
+	jw.BeginObjectField(field)
+	jw.BeginArray()
+	for _, stat := range stats {
+		jw.BeginObject()
+		jw.BeginObjectField("name")
+		jw.WriteString(stat.Name)
+		jw.BeginObjectField("value")
+		jw.WriteInt(int(stat.Value))
+		jw.EndObject()
+	}
+	jw.EndArray()
+}
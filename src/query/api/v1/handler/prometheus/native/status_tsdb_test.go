@@ -0,0 +1,181 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package native
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/client"
+	"github.com/m3db/m3/src/dbnode/storage/index"
+	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/handleroptions"
+	"github.com/m3db/m3/src/query/api/v1/options"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/storage/m3"
+	"github.com/m3db/m3/src/x/headers"
+	"github.com/m3db/m3/src/x/ident"
+	xtest "github.com/m3db/m3/src/x/test"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newStatusTSDBTestHandler(
+	t *testing.T,
+	session client.Session,
+	now time.Time,
+) http.Handler {
+	clusters, err := m3.NewClusters(m3.UnaggregatedClusterNamespaceDefinition{
+		NamespaceID: ident.StringID("test-ns"),
+		Session:     session,
+		Retention:   24 * time.Hour,
+	})
+	require.NoError(t, err)
+
+	fb, err := handleroptions.NewFetchOptionsBuilder(
+		handleroptions.FetchOptionsBuilderOptions{Timeout: 15 * time.Second})
+	require.NoError(t, err)
+
+	tagOpts := models.NewTagOptions()
+	opts := options.EmptyHandlerOptions().
+		SetCardinalityStorage(m3.NewCardinalityStorage(clusters, tagOpts)).
+		SetFetchOptionsBuilder(fb).
+		SetNowFn(func() time.Time { return now }).
+		SetTagOptions(tagOpts)
+	return NewStatusTSDBHandler(opts)
+}
+
+func TestStatusTSDB(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Unix(1600000000, 0)
+	session := client.NewMockSession(ctrl)
+	session.EXPECT().
+		Cardinality(gomock.Any(), ident.NewIDMatcher("test-ns"), index.CardinalityOptions{
+			StartInclusive: xtime.ToUnixNano(now.Add(-2 * time.Hour)),
+			EndExclusive:   xtime.ToUnixNano(now),
+			NameField:      []byte("__name__"),
+			Limit:          10,
+		}).
+		Return(index.CardinalityResult{
+			NumSeries: 3,
+			SeriesCountByMetricName: []index.CardinalityStat{
+				{Name: "up", Value: 2},
+				{Name: "requests", Value: 1},
+			},
+			LabelValueCountByLabelName:  []index.CardinalityStat{{Name: "job", Value: 2}},
+			MemoryInBytesByLabelName:    []index.CardinalityStat{{Name: "job", Value: 5}},
+			SeriesCountByLabelValuePair: []index.CardinalityStat{{Name: "job=api", Value: 2}},
+			Exhaustive:                  true,
+		}, nil)
+
+	handler := newStatusTSDBTestHandler(t, session, now)
+
+	code, body := serveMetadata(t, handler, StatusTSDBURL, url.Values{})
+	require.Equal(t, http.StatusOK, code)
+	expected := `{"status":"success","data":{` +
+		`"headStats":{"numSeries":3,"minTime":1599992800000,"maxTime":1600000000000},` +
+		`"seriesCountByMetricName":[{"name":"up","value":2},{"name":"requests","value":1}],` +
+		`"labelValueCountByLabelName":[{"name":"job","value":2}],` +
+		`"memoryInBytesByLabelName":[{"name":"job","value":5}],` +
+		`"seriesCountByLabelValuePair":[{"name":"job=api","value":2}]` +
+		`}}`
+	assert.Equal(t, expected, body)
+}
+
+func TestStatusTSDBLimits(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Unix(1600000000, 0)
+	session := client.NewMockSession(ctrl)
+	session.EXPECT().
+		Cardinality(gomock.Any(), gomock.Any(), index.CardinalityOptions{
+			StartInclusive:    xtime.ToUnixNano(now.Add(-2 * time.Hour)),
+			EndExclusive:      xtime.ToUnixNano(now),
+			NameField:         []byte("__name__"),
+			Limit:             10,
+			SeriesLimit:       5,
+			DocsLimit:         50,
+			RequireExhaustive: false,
+		}).
+		Return(index.CardinalityResult{NumSeries: 5, Exhaustive: false}, nil)
+
+	handler := newStatusTSDBTestHandler(t, session, now)
+
+	req := httptest.NewRequest(http.MethodGet, StatusTSDBURL, nil)
+	req.Header.Set(headers.LimitMaxSeriesHeader, "5")
+	req.Header.Set(headers.LimitMaxDocsHeader, "50")
+	req.Header.Set(headers.LimitRequireExhaustiveHeader, "false")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, headers.LimitHeaderSeriesLimitApplied,
+		w.Header().Get(headers.LimitHeader))
+}
+
+func TestStatusTSDBParams(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Unix(1600000000, 0)
+	session := client.NewMockSession(ctrl)
+	session.EXPECT().
+		Cardinality(gomock.Any(), gomock.Any(), index.CardinalityOptions{
+			StartInclusive: xtime.ToUnixNano(time.Unix(1599990000, 0)),
+			EndExclusive:   xtime.ToUnixNano(time.Unix(1599995000, 0)),
+			NameField:      []byte("__name__"),
+			Limit:          3,
+		}).
+		Return(index.CardinalityResult{}, nil)
+
+	handler := newStatusTSDBTestHandler(t, session, now)
+
+	code, _ := serveMetadata(t, handler, StatusTSDBURL, url.Values{
+		"start": []string{"1599990000"},
+		"end":   []string{"1599995000"},
+		"limit": []string{"3"},
+	})
+	require.Equal(t, http.StatusOK, code)
+
+	code, _ = serveMetadata(t, handler, StatusTSDBURL, url.Values{
+		"limit": []string{"-1"},
+	})
+	require.Equal(t, http.StatusBadRequest, code)
+
+	code, _ = serveMetadata(t, handler, StatusTSDBURL, url.Values{
+		"start": []string{"1600000100"},
+	})
+	require.Equal(t, http.StatusBadRequest, code)
+}
+
+func TestStatusTSDBNoStorage(t *testing.T) {
+	handler := NewStatusTSDBHandler(options.EmptyHandlerOptions())
+	code, _ := serveMetadata(t, handler, StatusTSDBURL, url.Values{})
+	require.Equal(t, http.StatusInternalServerError, code)
+}
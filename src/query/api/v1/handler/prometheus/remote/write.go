@@ -33,6 +33,9 @@ import (
 
 	"github.com/m3db/m3/src/cmd/services/m3coordinator/ingest"
 	"github.com/m3db/m3/src/dbnode/client"
+	"github.com/m3db/m3/src/dbnode/encoding/histogram"
+	"github.com/m3db/m3/src/dbnode/generated/proto/annotation"
+	"github.com/m3db/m3/src/metrics/metric"
 	"github.com/m3db/m3/src/metrics/policy"
 	"github.com/m3db/m3/src/query/api/v1/handler/prometheus"
 	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/handleroptions"
@@ -108,6 +111,8 @@ var (
 // PromWriteHandler represents a handler for prometheus write endpoint.
 type PromWriteHandler struct {
 	downsamplerAndWriter   ingest.DownsamplerAndWriter
+	exemplarStorage        storage.ExemplarStorage
+	metadataStorage        storage.MetricMetadataStorage
 	tagOptions             models.TagOptions
 	storeMetricsType       bool
 	forwarding             handleroptions.PromWriteHandlerForwardingOptions
@@ -178,6 +183,8 @@ func NewPromWriteHandler(options options.HandlerOptions) (http.Handler, error) {
 
 	return &PromWriteHandler{
 		downsamplerAndWriter:   downsamplerAndWriter,
+		exemplarStorage:        options.ExemplarStorage(),
+		metadataStorage:        options.MetricMetadataStorage(),
 		tagOptions:             tagOptions,
 		storeMetricsType:       options.StoreMetricsType(),
 		forwarding:             forwarding,
@@ -204,6 +211,11 @@ type promWriteMetrics struct {
 	forwardErrors            tally.Counter
 	forwardDropped           tally.Counter
 	forwardLatency           tally.Histogram
+	exemplarsSuccess         tally.Counter
+	exemplarsErrors          tally.Counter
+	metadataSuccess          tally.Counter
+	metadataErrors           tally.Counter
+	histogramsSuccess        tally.Counter
 }
 
 func (m *promWriteMetrics) incError(err error) {
@@ -231,6 +243,11 @@ func newPromWriteMetrics(scope tally.Scope) (promWriteMetrics, error) {
 		forwardErrors:            scope.SubScope("forward").Counter("errors"),
 		forwardDropped:           scope.SubScope("forward").Counter("dropped"),
 		forwardLatency:           scope.SubScope("forward").Histogram("latency", buckets.WriteLatencyBuckets),
+		exemplarsSuccess:         scope.SubScope("exemplars").Counter("success"),
+		exemplarsErrors:          scope.SubScope("exemplars").Counter("errors"),
+		metadataSuccess:          scope.SubScope("metadata").Counter("success"),
+		metadataErrors:           scope.SubScope("metadata").Counter("errors"),
+		histogramsSuccess:        scope.SubScope("histograms").Counter("success"),
 	}, nil
 }
 
@@ -301,6 +318,22 @@ func (h *PromWriteHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	batchErr := h.write(r.Context(), req, opts)
 
+	// Exemplars are best effort and failing to write them does not fail
+	// the write of the datapoints.
+	if err := h.writeExemplars(r.Context(), req); err != nil {
+		h.metrics.exemplarsErrors.Inc(1)
+		logger := logging.WithContext(r.Context(), h.instrumentOpts)
+		logger.Error("write exemplars error", zap.Error(err))
+	}
+
+	// Metric metadata is also best effort, Prometheus resends it
+	// periodically.
+	if err := h.writeMetadata(r.Context(), req); err != nil {
+		h.metrics.metadataErrors.Inc(1)
+		logger := logging.WithContext(r.Context(), h.instrumentOpts)
+		logger.Error("write metric metadata error", zap.Error(err))
+	}
+
 	// Record ingestion delay latency
 	now := h.nowFn()
 	for _, series := range req.Timeseries {
@@ -498,7 +531,106 @@ func (h *PromWriteHandler) write(
 		var errs xerrors.MultiError
 		return errs.Add(err)
 	}
-	return h.downsamplerAndWriter.WriteBatch(ctx, iter, opts)
+	batchErr := h.downsamplerAndWriter.WriteBatch(ctx, iter, opts)
+
+	histogramsErr := h.writeHistograms(ctx, r, opts)
+	if histogramsErr == nil {
+		return batchErr
+	}
+
+	var errs xerrors.MultiError
+	if batchErr != nil {
+		for _, err := range batchErr.Errors() {
+			errs = errs.Add(err)
+		}
+	}
+	for _, err := range histogramsErr.Errors() {
+		errs = errs.Add(err)
+	}
+	return errs
+}
+
+func (h *PromWriteHandler) writeHistograms(
+	ctx context.Context,
+	r *prompb.WriteRequest,
+	opts ingest.WriteOptions,
+) ingest.BatchError {
+	iter, err := newPromHistogramsIter(r.Timeseries, h.tagOptions)
+	if err != nil {
+		var errs xerrors.MultiError
+		return errs.Add(err)
+	}
+	if len(iter.tags) == 0 {
+		return nil
+	}
+
+	// Native histograms can not be aggregated by the downsampler, so they
+	// are only written to the unaggregated namespace.
+	opts.DownsampleOverride = true
+	opts.DownsampleMappingRules = nil
+	if batchErr := h.downsamplerAndWriter.WriteBatch(ctx, iter, opts); batchErr != nil {
+		return batchErr
+	}
+
+	h.metrics.histogramsSuccess.Inc(int64(len(iter.tags)))
+	return nil
+}
+
+func (h *PromWriteHandler) writeExemplars(
+	ctx context.Context,
+	r *prompb.WriteRequest,
+) error {
+	if h.exemplarStorage == nil {
+		return nil
+	}
+
+	var (
+		series          []storage.SeriesExemplars
+		graphiteTagOpts = h.tagOptions.SetIDSchemeType(models.TypeGraphite)
+	)
+	for _, promTS := range r.Timeseries {
+		if len(promTS.Exemplars) == 0 {
+			continue
+		}
+
+		// Set the tag options based on the incoming source so that the
+		// exemplars are stored against the same series ID as the datapoints.
+		opts := h.tagOptions
+		if promTS.Source == prompb.Source_GRAPHITE {
+			opts = graphiteTagOpts
+		}
+
+		series = append(series, storage.SeriesExemplars{
+			Tags:      storage.PromLabelsToM3Tags(promTS.Labels, opts),
+			Exemplars: storage.PromExemplarsToM3Exemplars(promTS.Exemplars),
+		})
+	}
+	if len(series) == 0 {
+		return nil
+	}
+
+	if err := h.exemplarStorage.WriteExemplars(ctx, series); err != nil {
+		return err
+	}
+
+	h.metrics.exemplarsSuccess.Inc(int64(len(series)))
+	return nil
+}
+
+func (h *PromWriteHandler) writeMetadata(
+	ctx context.Context,
+	r *prompb.WriteRequest,
+) error {
+	if h.metadataStorage == nil || len(r.Metadata) == 0 {
+		return nil
+	}
+
+	if err := h.metadataStorage.WriteMetricMetadata(ctx, r.Metadata); err != nil {
+		return err
+	}
+
+	h.metrics.metadataSuccess.Inc(int64(len(r.Metadata)))
+	return nil
 }
 
 func (h *PromWriteHandler) forward(
@@ -570,6 +702,11 @@ func newPromTSIter(
 
 	graphiteTagOpts := tagOpts.SetIDSchemeType(models.TypeGraphite)
 	for _, promTS := range timeseries {
+		if len(promTS.Samples) == 0 && len(promTS.Histograms) > 0 {
+			// Series with only native histograms are written separately.
+			continue
+		}
+
 		attributes, err := storage.PromTimeSeriesToSeriesAttributes(promTS)
 		if err != nil {
 			return nil, err
@@ -595,6 +732,69 @@ func newPromTSIter(
 	}, nil
 }
 
+// newPromHistogramsIter returns an iterator over the native histograms of
+// the timeseries. Each histogram is written as its own datapoint whose value
+// is the histogram count and whose annotation is the encoded histogram.
+func newPromHistogramsIter(
+	timeseries []prompb.TimeSeries,
+	tagOpts models.TagOptions,
+) (*promTSIter, error) {
+	var (
+		tags        []models.Tags
+		datapoints  []ts.Datapoints
+		attributes  []ts.SeriesAttributes
+		annotations [][]byte
+	)
+	for _, promTS := range timeseries {
+		if len(promTS.Histograms) == 0 {
+			continue
+		}
+
+		seriesTags := storage.PromLabelsToM3Tags(promTS.Labels, tagOpts).
+			AddOrUpdateTag(models.Tag{
+				Name:  metric.M3PromNativeHistogramTag,
+				Value: metric.PromNativeHistogramEncodedValue,
+			})
+		seriesAttributes := ts.SeriesAttributes{
+			PromType: ts.PromMetricTypeHistogram,
+			Source:   ts.SourceTypePrometheus,
+		}
+		for _, promHistogram := range promTS.Histograms {
+			var (
+				timestamp = xtime.ToUnixNano(storage.PromTimestampToTime(promHistogram.Timestamp))
+				value     = storage.PromHistogramToM3Histogram(promHistogram)
+			)
+			if err := value.Validate(); err != nil {
+				return nil, xerrors.NewInvalidParamsError(err)
+			}
+
+			payload := annotation.Payload{
+				MetricType:      annotation.MetricType_HISTOGRAM,
+				NativeHistogram: histogram.Encode(timestamp, value),
+			}
+			encoded, err := payload.Marshal()
+			if err != nil {
+				return nil, err
+			}
+
+			tags = append(tags, seriesTags)
+			datapoints = append(datapoints, ts.Datapoints{
+				{Timestamp: timestamp, Value: value.Count},
+			})
+			attributes = append(attributes, seriesAttributes)
+			annotations = append(annotations, encoded)
+		}
+	}
+
+	return &promTSIter{
+		attributes:  attributes,
+		idx:         -1,
+		tags:        tags,
+		datapoints:  datapoints,
+		annotations: annotations,
+	}, nil
+}
+
 type promTSIter struct {
 	idx        int
 	err        error
@@ -603,6 +803,9 @@ type promTSIter struct {
 	datapoints []ts.Datapoints
 	metadatas  []ts.Metadata
 	annotation []byte
+	// annotations when set are the annotations of each of the series and
+	// take precedence over the annotations derived from the attributes.
+	annotations [][]byte
 
 	storeMetricsType bool
 }
@@ -617,6 +820,11 @@ func (i *promTSIter) Next() bool {
 		return false
 	}
 
+	if i.annotations != nil {
+		i.annotation = i.annotations[i.idx]
+		return true
+	}
+
 	if !i.storeMetricsType {
 		return true
 	}
@@ -32,21 +32,28 @@ import (
 	"testing"
 	"time"
 
+	"github.com/m3db/m3/src/cluster/kv/mem"
 	"github.com/m3db/m3/src/cmd/services/m3coordinator/ingest"
 	"github.com/m3db/m3/src/cmd/services/m3query/config"
+	"github.com/m3db/m3/src/dbnode/encoding/histogram"
 	"github.com/m3db/m3/src/dbnode/generated/proto/annotation"
+	"github.com/m3db/m3/src/metrics/metric"
 	"github.com/m3db/m3/src/metrics/policy"
 	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/handleroptions"
 	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/remote/test"
 	"github.com/m3db/m3/src/query/api/v1/options"
 	"github.com/m3db/m3/src/query/generated/proto/prompb"
 	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/storage"
 	"github.com/m3db/m3/src/query/storage/m3/storagemetadata"
+	"github.com/m3db/m3/src/query/storage/metricmetadata"
+	"github.com/m3db/m3/src/query/ts"
 	xclock "github.com/m3db/m3/src/x/clock"
 	xerrors "github.com/m3db/m3/src/x/errors"
 	"github.com/m3db/m3/src/x/headers"
 	"github.com/m3db/m3/src/x/instrument"
 	xtest "github.com/m3db/m3/src/x/test"
+	xtime "github.com/m3db/m3/src/x/time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -443,6 +450,234 @@ func BenchmarkWriteDatapoints(b *testing.B) {
 	}
 }
 
+type testExemplarStorage struct {
+	written []storage.SeriesExemplars
+	err     error
+}
+
+func (s *testExemplarStorage) WriteExemplars(
+	_ context.Context,
+	series []storage.SeriesExemplars,
+) error {
+	s.written = append(s.written, series...)
+	return s.err
+}
+
+func (s *testExemplarStorage) FetchExemplars(
+	_ context.Context,
+	_ *storage.FetchQuery,
+	_ *storage.FetchOptions,
+) ([]storage.SeriesExemplars, error) {
+	return nil, s.err
+}
+
+func TestPromWriteExemplars(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	mockDownsamplerAndWriter := ingest.NewMockDownsamplerAndWriter(ctrl)
+	mockDownsamplerAndWriter.
+		EXPECT().
+		WriteBatch(gomock.Any(), gomock.Any(), gomock.Any())
+
+	exemplarStorage := &testExemplarStorage{}
+	opts := makeOptions(mockDownsamplerAndWriter).
+		SetExemplarStorage(exemplarStorage)
+
+	promReq := test.GeneratePromWriteRequest()
+	promReq.Timeseries[0].Exemplars = []prompb.Exemplar{
+		{
+			Labels:    []prompb.Label{{Name: []byte("trace_id"), Value: []byte("abc")}},
+			Value:     1.5,
+			Timestamp: 1000,
+		},
+	}
+	executeWriteRequest(t, opts, promReq)
+
+	// Only series with exemplars are written to the exemplar storage.
+	require.Equal(t, 1, len(exemplarStorage.written))
+	written := exemplarStorage.written[0]
+	name, ok := written.Tags.Name()
+	require.True(t, ok)
+	assert.Equal(t, "first", string(name))
+	require.Equal(t, 1, len(written.Exemplars))
+	assert.Equal(t, 1.5, written.Exemplars[0].Value)
+	assert.Equal(t, xtime.UnixNano(time.Second), written.Exemplars[0].TimestampNanos)
+	assert.Equal(t, "trace_id", string(written.Exemplars[0].Labels[0].Name))
+}
+
+func TestPromWriteExemplarsErrorDoesNotFailWrite(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	mockDownsamplerAndWriter := ingest.NewMockDownsamplerAndWriter(ctrl)
+	mockDownsamplerAndWriter.
+		EXPECT().
+		WriteBatch(gomock.Any(), gomock.Any(), gomock.Any())
+
+	exemplarStorage := &testExemplarStorage{err: errors.New("write error")}
+	opts := makeOptions(mockDownsamplerAndWriter).
+		SetExemplarStorage(exemplarStorage)
+
+	promReq := test.GeneratePromWriteRequest()
+	promReq.Timeseries[1].Exemplars = []prompb.Exemplar{
+		{Value: 2, Timestamp: 1000},
+	}
+	executeWriteRequest(t, opts, promReq)
+	require.Equal(t, 1, len(exemplarStorage.written))
+}
+
+func TestPromWriteMetricMetadata(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	mockDownsamplerAndWriter := ingest.NewMockDownsamplerAndWriter(ctrl)
+	mockDownsamplerAndWriter.
+		EXPECT().
+		WriteBatch(gomock.Any(), gomock.Any(), gomock.Any())
+
+	metadataStorage := metricmetadata.NewKVStorage(mem.NewStore(),
+		instrument.NewOptions())
+	opts := makeOptions(mockDownsamplerAndWriter).
+		SetMetricMetadataStorage(metadataStorage)
+
+	metadata := []prompb.MetricMetadata{
+		{
+			Type:             prompb.MetricType_COUNTER,
+			MetricFamilyName: "http_requests_total",
+			Help:             "Total number of HTTP requests.",
+		},
+	}
+	executeWriteRequest(t, opts, &prompb.WriteRequest{Metadata: metadata})
+
+	// Metadata is written to the KV store asynchronously, closing the storage
+	// writes any pending metadata.
+	metadataStorage.Close()
+	result, err := metadataStorage.FetchMetricMetadata(context.Background(),
+		storage.MetricMetadataQuery{})
+	require.NoError(t, err)
+	assert.Equal(t, metadata, result)
+}
+
+func TestPromWriteNativeHistograms(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		capturedIters   []ingest.DownsampleAndWriteIter
+		capturedOptions []ingest.WriteOptions
+	)
+	mockDownsamplerAndWriter := ingest.NewMockDownsamplerAndWriter(ctrl)
+	mockDownsamplerAndWriter.
+		EXPECT().
+		WriteBatch(gomock.Any(), gomock.Any(), gomock.Any()).
+		Do(func(_ context.Context, iter ingest.DownsampleAndWriteIter, opts ingest.WriteOptions) ingest.BatchError {
+			capturedIters = append(capturedIters, iter)
+			capturedOptions = append(capturedOptions, opts)
+			return nil
+		}).
+		Times(2)
+
+	opts := makeOptions(mockDownsamplerAndWriter)
+
+	promReq := &prompb.WriteRequest{
+		Timeseries: []prompb.TimeSeries{
+			{
+				Labels:  []prompb.Label{{Name: []byte("__name__"), Value: []byte("float")}},
+				Samples: []prompb.Sample{{Value: 1, Timestamp: 1000}},
+			},
+			{
+				Labels: []prompb.Label{{Name: []byte("__name__"), Value: []byte("native")}},
+				Histograms: []prompb.Histogram{
+					{
+						CountInt:       3,
+						Sum:            4.5,
+						PositiveSpans:  []prompb.BucketSpan{{Offset: 0, Length: 2}},
+						PositiveDeltas: []int64{1, 1},
+						Timestamp:      2000,
+					},
+				},
+			},
+		},
+	}
+
+	executeWriteRequest(t, opts, promReq)
+	require.Equal(t, 2, len(capturedIters))
+
+	// Series with only native histograms are not written as float samples.
+	samplesIter := capturedIters[0]
+	require.True(t, samplesIter.Next())
+	name, ok := samplesIter.Current().Tags.Name()
+	require.True(t, ok)
+	assert.Equal(t, "float", string(name))
+	require.False(t, samplesIter.Next())
+
+	// Native histograms are not downsampled.
+	assert.True(t, capturedOptions[1].DownsampleOverride)
+	assert.Equal(t, 0, len(capturedOptions[1].DownsampleMappingRules))
+
+	histogramsIter := capturedIters[1]
+	require.True(t, histogramsIter.Next())
+	value := histogramsIter.Current()
+	marker, ok := value.Tags.Get(metric.M3PromNativeHistogramTag)
+	require.True(t, ok)
+	assert.Equal(t, metric.PromNativeHistogramEncodedValue, marker)
+	assert.Equal(t, ts.Datapoints{
+		{Timestamp: xtime.UnixNano(2 * time.Second), Value: 3},
+	}, value.Datapoints)
+
+	payload := unmarshalAnnotation(t, value.Annotation)
+	assert.Equal(t, annotation.MetricType_HISTOGRAM, payload.MetricType)
+	decodedTime, decoded, err := histogram.Decode(payload.NativeHistogram)
+	require.NoError(t, err)
+	assert.Equal(t, xtime.UnixNano(2*time.Second), decodedTime)
+	assert.Equal(t, histogram.Histogram{
+		Count:           3,
+		Sum:             4.5,
+		PositiveSpans:   []histogram.Span{{Offset: 0, Length: 2}},
+		PositiveBuckets: []float64{1, 2},
+	}, decoded)
+	require.False(t, histogramsIter.Next())
+}
+
+func TestPromWriteInvalidNativeHistogram(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	mockDownsamplerAndWriter := ingest.NewMockDownsamplerAndWriter(ctrl)
+	mockDownsamplerAndWriter.
+		EXPECT().
+		WriteBatch(gomock.Any(), gomock.Any(), gomock.Any())
+
+	handler, err := NewPromWriteHandler(makeOptions(mockDownsamplerAndWriter))
+	require.NoError(t, err)
+
+	promReq := &prompb.WriteRequest{
+		Timeseries: []prompb.TimeSeries{
+			{
+				Labels: []prompb.Label{{Name: []byte("__name__"), Value: []byte("native")}},
+				Histograms: []prompb.Histogram{
+					{
+						CountInt:       3,
+						PositiveSpans:  []prompb.BucketSpan{{Offset: 0, Length: 3}},
+						PositiveDeltas: []int64{1, 1},
+						Timestamp:      2000,
+					},
+				},
+			},
+		},
+	}
+
+	promReqBody := test.GeneratePromWriteRequestBody(t, promReq)
+	req := httptest.NewRequest(PromWriteHTTPMethod, PromWriteURL, promReqBody)
+
+	writer := httptest.NewRecorder()
+	handler.ServeHTTP(writer, req)
+	resp := writer.Result()
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	require.NoError(t, resp.Body.Close())
+}
+
 func verifyIterValueAnnotation(
 	t *testing.T,
 	iter ingest.DownsampleAndWriteIter,
@@ -41,6 +41,7 @@ import (
 	m3json "github.com/m3db/m3/src/query/api/v1/handler/json"
 	"github.com/m3db/m3/src/query/api/v1/handler/namespace"
 	"github.com/m3db/m3/src/query/api/v1/handler/openapi"
+	"github.com/m3db/m3/src/query/api/v1/handler/otlp"
 	"github.com/m3db/m3/src/query/api/v1/handler/prom"
 	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/native"
 	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/remote"
@@ -241,6 +242,21 @@ func (h *Handler) RegisterRoutes() error {
 		return err
 	}
 
+	// OpenTelemetry OTLP/HTTP metrics write endpoint.
+	otlpWriteHandler, err := otlp.NewWriteHandler(remoteSourceOpts)
+	if err != nil {
+		return err
+	}
+	if err := h.registry.Register(queryhttp.RegisterOptions{
+		Path:    otlp.WriteURL,
+		Handler: otlpWriteHandler,
+		Methods: methods(otlp.WriteHTTPMethod),
+		// Register with no response logging for write calls since so frequent.
+		MiddlewareOverride: middleware.WithNoResponseLogging,
+	}); err != nil {
+		return err
+	}
+
 	// InfluxDB write endpoint.
 	if err := h.registry.Register(queryhttp.RegisterOptions{
 		Path:    influxdb.InfluxWriteURL,
@@ -268,6 +284,15 @@ func (h *Handler) RegisterRoutes() error {
 		return err
 	}
 
+	// Series deletion endpoint.
+	if err := h.registry.Register(queryhttp.RegisterOptions{
+		Path:    native.DeleteSeriesURL,
+		Handler: native.NewDeleteSeriesHandler(h.options),
+		Methods: native.DeleteSeriesHTTPMethods,
+	}); err != nil {
+		return err
+	}
+
 	// Readiness endpoint.
 	if err := h.registry.Register(queryhttp.RegisterOptions{
 		Path:    handler.ReadyURL,
@@ -331,6 +356,57 @@ func (h *Handler) RegisterRoutes() error {
 		return err
 	}
 
+	// Exemplar query endpoints.
+	if err := h.registry.Register(queryhttp.RegisterOptions{
+		Path:               native.QueryExemplarsURL,
+		Handler:            native.NewQueryExemplarsHandler(h.options),
+		Methods:            native.QueryExemplarsHTTPMethods,
+		MiddlewareOverride: native.WithQueryParams,
+	}); err != nil {
+		return err
+	}
+
+	// Metric metadata endpoints.
+	if err := h.registry.Register(queryhttp.RegisterOptions{
+		Path:    native.MetadataURL,
+		Handler: native.NewMetadataHandler(h.options),
+		Methods: native.MetadataHTTPMethods,
+	}); err != nil {
+		return err
+	}
+	if err := h.registry.Register(queryhttp.RegisterOptions{
+		Path:    native.TargetsMetadataURL,
+		Handler: native.NewTargetsMetadataHandler(h.options),
+		Methods: native.MetadataHTTPMethods,
+	}); err != nil {
+		return err
+	}
+
+	// Rule evaluation endpoints.
+	if err := h.registry.Register(queryhttp.RegisterOptions{
+		Path:    native.RulesURL,
+		Handler: native.NewRulesHandler(h.options),
+		Methods: native.RulesHTTPMethods,
+	}); err != nil {
+		return err
+	}
+	if err := h.registry.Register(queryhttp.RegisterOptions{
+		Path:    native.AlertsURL,
+		Handler: native.NewAlertsHandler(h.options),
+		Methods: native.RulesHTTPMethods,
+	}); err != nil {
+		return err
+	}
+
+	// Cardinality status endpoint.
+	if err := h.registry.Register(queryhttp.RegisterOptions{
+		Path:    native.StatusTSDBURL,
+		Handler: native.NewStatusTSDBHandler(h.options),
+		Methods: native.StatusTSDBHTTPMethods,
+	}); err != nil {
+		return err
+	}
+
 	// Graphite routable endpoints.
 	h.options.GraphiteRenderRouter().Setup(options.GraphiteRenderRouterOptions{
 		RenderHandler: graphite.NewRenderHandler(h.options).ServeHTTP,
@@ -352,6 +428,27 @@ func (h *Handler) RegisterRoutes() error {
 	}); err != nil {
 		return err
 	}
+	if err := h.registry.Register(queryhttp.RegisterOptions{
+		Path:    graphite.TagsURL,
+		Handler: graphite.NewTagsHandler(h.options),
+		Methods: graphite.TagsHTTPMethods,
+	}); err != nil {
+		return err
+	}
+	if err := h.registry.Register(queryhttp.RegisterOptions{
+		Path:    graphite.AutoCompleteTagsURL,
+		Handler: graphite.NewAutoCompleteTagsHandler(h.options),
+		Methods: graphite.TagsHTTPMethods,
+	}); err != nil {
+		return err
+	}
+	if err := h.registry.Register(queryhttp.RegisterOptions{
+		Path:    graphite.AutoCompleteValuesURL,
+		Handler: graphite.NewAutoCompleteValuesHandler(h.options),
+		Methods: graphite.TagsHTTPMethods,
+	}); err != nil {
+		return err
+	}
 
 	placementOpts, err := h.placementOpts()
 	if err != nil {
@@ -33,6 +33,7 @@ import (
 
 	"github.com/m3db/m3/src/query/api/v1/handler/prometheus"
 	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/handleroptions"
+	"github.com/m3db/m3/src/query/parser/promql"
 	"github.com/m3db/m3/src/query/storage"
 	xhttp "github.com/m3db/m3/src/x/net/http"
 )
@@ -136,7 +137,7 @@ func rewriteRangeDuration(
 	}
 
 	// Rewrite ranges within the query, if necessary
-	expr, err := parser.ParseExpr(query)
+	expr, err := promql.ParseExpr(query)
 	if err != nil {
 		return err
 	}
@@ -39,11 +39,14 @@ import (
 	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/handleroptions"
 	"github.com/m3db/m3/src/query/api/v1/middleware"
 	"github.com/m3db/m3/src/query/api/v1/validators"
+	"github.com/m3db/m3/src/query/cache"
 	"github.com/m3db/m3/src/query/executor"
 	graphite "github.com/m3db/m3/src/query/graphite/storage"
 	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/ruler"
 	"github.com/m3db/m3/src/query/storage"
 	"github.com/m3db/m3/src/query/storage/m3"
+	"github.com/m3db/m3/src/query/storage/metricmetadata"
 	"github.com/m3db/m3/src/query/ts"
 	"github.com/m3db/m3/src/x/clock"
 	"github.com/m3db/m3/src/x/instrument"
@@ -133,6 +136,31 @@ type HandlerOptions interface {
 	// SetStorage sets the set storage.
 	SetStorage(s storage.Storage) HandlerOptions
 
+	// ExemplarStorage returns the set exemplar storage.
+	ExemplarStorage() storage.ExemplarStorage
+	// SetExemplarStorage sets the set exemplar storage.
+	SetExemplarStorage(s storage.ExemplarStorage) HandlerOptions
+
+	// CardinalityStorage returns the set cardinality storage.
+	CardinalityStorage() storage.CardinalityStorage
+	// SetCardinalityStorage sets the set cardinality storage.
+	SetCardinalityStorage(s storage.CardinalityStorage) HandlerOptions
+
+	// MetricMetadataStorage returns the set metric metadata storage.
+	MetricMetadataStorage() storage.MetricMetadataStorage
+	// SetMetricMetadataStorage sets the set metric metadata storage.
+	SetMetricMetadataStorage(s storage.MetricMetadataStorage) HandlerOptions
+
+	// RuleManager returns the set rule manager.
+	RuleManager() ruler.Manager
+	// SetRuleManager sets the set rule manager.
+	SetRuleManager(m ruler.Manager) HandlerOptions
+
+	// ResultCache returns the set query result cache.
+	ResultCache() cache.Cache
+	// SetResultCache sets the set query result cache.
+	SetResultCache(c cache.Cache) HandlerOptions
+
 	// DownsamplerAndWriter returns the set downsampler and writer.
 	DownsamplerAndWriter() ingest.DownsamplerAndWriter
 	// SetDownsamplerAndWriter sets the set downsampler and writer.
@@ -279,6 +307,11 @@ type HandlerOptions interface {
 // HandlerOptions represents handler options.
 type handlerOptions struct {
 	storage                           storage.Storage
+	exemplarStorage                   storage.ExemplarStorage
+	cardinalityStorage                storage.CardinalityStorage
+	metricMetadataStorage             storage.MetricMetadataStorage
+	ruleManager                       ruler.Manager
+	resultCache                       cache.Cache
 	downsamplerAndWriter              ingest.DownsamplerAndWriter
 	engine                            executor.Engine
 	prometheusEngine                  *promql.Engine
@@ -348,8 +381,24 @@ func NewHandlerOptions(
 	if cfg.StoreMetricsType != nil {
 		storeMetricsType = *cfg.StoreMetricsType
 	}
+	var (
+		exemplarStorage    storage.ExemplarStorage
+		cardinalityStorage storage.CardinalityStorage
+	)
+	if m3dbClusters != nil {
+		exemplarStorage = m3.NewExemplarStorage(m3dbClusters, tagOptions)
+		cardinalityStorage = m3.NewCardinalityStorage(m3dbClusters, tagOptions)
+	}
+	var metricMetadataStorage storage.MetricMetadataStorage
+	if clusterClient != nil {
+		metricMetadataStorage = metricmetadata.NewClusterStorage(clusterClient,
+			instrumentOpts)
+	}
 	return &handlerOptions{
 		storage:                           downsamplerAndWriter.Storage(),
+		exemplarStorage:                   exemplarStorage,
+		cardinalityStorage:                cardinalityStorage,
+		metricMetadataStorage:             metricMetadataStorage,
 		downsamplerAndWriter:              downsamplerAndWriter,
 		engine:                            engine,
 		prometheusEngine:                  prometheusEngine,
@@ -395,6 +444,58 @@ func (o *handlerOptions) SetStorage(s storage.Storage) HandlerOptions {
 	return &opts
 }
 
+func (o *handlerOptions) ExemplarStorage() storage.ExemplarStorage {
+	return o.exemplarStorage
+}
+
+func (o *handlerOptions) SetExemplarStorage(s storage.ExemplarStorage) HandlerOptions {
+	opts := *o
+	opts.exemplarStorage = s
+	return &opts
+}
+
+func (o *handlerOptions) CardinalityStorage() storage.CardinalityStorage {
+	return o.cardinalityStorage
+}
+
+func (o *handlerOptions) SetCardinalityStorage(s storage.CardinalityStorage) HandlerOptions {
+	opts := *o
+	opts.cardinalityStorage = s
+	return &opts
+}
+
+func (o *handlerOptions) MetricMetadataStorage() storage.MetricMetadataStorage {
+	return o.metricMetadataStorage
+}
+
+func (o *handlerOptions) SetMetricMetadataStorage(
+	s storage.MetricMetadataStorage,
+) HandlerOptions {
+	opts := *o
+	opts.metricMetadataStorage = s
+	return &opts
+}
+
+func (o *handlerOptions) RuleManager() ruler.Manager {
+	return o.ruleManager
+}
+
+func (o *handlerOptions) SetRuleManager(m ruler.Manager) HandlerOptions {
+	opts := *o
+	opts.ruleManager = m
+	return &opts
+}
+
+func (o *handlerOptions) ResultCache() cache.Cache {
+	return o.resultCache
+}
+
+func (o *handlerOptions) SetResultCache(c cache.Cache) HandlerOptions {
+	opts := *o
+	opts.resultCache = c
+	return &opts
+}
+
 func (o *handlerOptions) DownsamplerAndWriter() ingest.DownsamplerAndWriter {
 	return o.downsamplerAndWriter
 }
@@ -35,4 +35,23 @@ const (
 
 	// QueryURL return the url for the query endpoint.
 	QueryURL = Prefix + "/query"
+
+	// QueryExemplarsURL returns the url for the query exemplars endpoint.
+	QueryExemplarsURL = Prefix + "/query_exemplars"
+
+	// MetadataURL returns the url for the metric metadata endpoint.
+	MetadataURL = Prefix + "/metadata"
+
+	// TargetsMetadataURL returns the url for the targets metric metadata
+	// endpoint.
+	TargetsMetadataURL = Prefix + "/targets/metadata"
+
+	// RulesURL returns the url for the recording and alerting rules endpoint.
+	RulesURL = Prefix + "/rules"
+
+	// AlertsURL returns the url for the active alerts endpoint.
+	AlertsURL = Prefix + "/alerts"
+
+	// StatusTSDBURL returns the url for the TSDB cardinality status endpoint.
+	StatusTSDBURL = Prefix + "/status/tsdb"
 )
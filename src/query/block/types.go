@@ -81,6 +81,10 @@ type AccumulatorBlock interface {
 type SeriesMeta struct {
 	Tags models.Tags
 	Name []byte
+	// TileCounts is set if each datapoint of the series is the number of
+	// samples it represents, as fetched for the count over time of
+	// downsampled tiles.
+	TileCounts bool
 }
 
 // Iterator is the base iterator.
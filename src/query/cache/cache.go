@@ -0,0 +1,343 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/m3db/m3/src/query/block"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/ts"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/uber-go/tally"
+	"go.uber.org/atomic"
+	"go.uber.org/zap"
+)
+
+type cacheMetrics struct {
+	hits        tally.Counter
+	misses      tally.Counter
+	uncacheable tally.Counter
+	executions  tally.Counter
+	getErrors   tally.Counter
+	setErrors   tally.Counter
+
+	invalidations tally.Counter
+}
+
+func newCacheMetrics(scope tally.Scope) cacheMetrics {
+	return cacheMetrics{
+		hits:        scope.Counter("extent-hits"),
+		misses:      scope.Counter("extent-misses"),
+		uncacheable: scope.Counter("uncacheable-queries"),
+		executions:  scope.Counter("executions"),
+		getErrors:   scope.Tagged(map[string]string{"op": "get"}).Counter("backend-errors"),
+		setErrors:   scope.Tagged(map[string]string{"op": "set"}).Counter("backend-errors"),
+
+		invalidations: scope.Counter("invalidations"),
+	}
+}
+
+type resultCache struct {
+	splitInterval time.Duration
+	maxFreshness  time.Duration
+	backend       Backend
+	tagOpts       models.TagOptions
+	nowFn         func() time.Time
+	logger        *zap.Logger
+	metrics       cacheMetrics
+	// generation is part of the keys of extents, incrementing it invalidates
+	// every extent cached so far regardless of the backend.
+	generation atomic.Int64
+}
+
+// NewCache returns a new results cache.
+func NewCache(opts Options) (Cache, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	iOpts := opts.InstrumentOptions()
+	return &resultCache{
+		splitInterval: opts.SplitInterval(),
+		maxFreshness:  opts.MaxFreshness(),
+		backend:       opts.Backend(),
+		tagOpts:       opts.TagOptions(),
+		nowFn:         opts.ClockOptions().NowFn(),
+		logger:        iOpts.Logger(),
+		metrics:       newCacheMetrics(iOpts.MetricsScope().SubScope("result-cache")),
+	}, nil
+}
+
+// extent is the range of steps of a query within a single split interval.
+type extent struct {
+	// start and end are the first and last steps of the extent.
+	start xtime.UnixNano
+	end   xtime.UnixNano
+	// key is only set if the extent can be cached, i.e. it spans the whole
+	// split interval and its results are no longer expected to change.
+	key    string
+	cached *Result
+}
+
+func (c *resultCache) Query(
+	ctx context.Context,
+	query Query,
+	fn QueryFunc,
+) (Result, error) {
+	if query.Step <= 0 || query.Step > c.splitInterval || query.End.Before(query.Start) {
+		c.metrics.uncacheable.Inc(1)
+		return fn(ctx, query.Start, query.End)
+	}
+
+	// Align the end to the last step of the query.
+	steps := query.End.Sub(query.Start) / query.Step
+	query.End = query.Start.Add(steps * query.Step)
+
+	extents := c.split(query)
+	for i := range extents {
+		if extents[i].key == "" {
+			continue
+		}
+		extents[i].cached = c.get(ctx, extents[i], query.Step)
+	}
+
+	results := make([]Result, 0, len(extents))
+	for i := 0; i < len(extents); {
+		if extents[i].cached != nil {
+			results = append(results, *extents[i].cached)
+			i++
+			continue
+		}
+
+		// Execute consecutive extents that are not cached as a single query.
+		j := i
+		for j+1 < len(extents) && extents[j+1].cached == nil {
+			j++
+		}
+
+		c.metrics.executions.Inc(1)
+		res, err := fn(ctx, extents[i].start, extents[j].end)
+		if err != nil {
+			return Result{}, err
+		}
+
+		if i == 0 && j == len(extents)-1 {
+			// Nothing was cached so the result is for the whole query.
+			c.setAll(ctx, extents, query.Step, res)
+			return res, nil
+		}
+
+		c.setAll(ctx, extents[i:j+1], query.Step, res)
+		results = append(results, res)
+		i = j + 1
+	}
+
+	return mergeResults(query, results), nil
+}
+
+// split splits the query into extents aligned to the split interval.
+func (c *resultCache) split(query Query) []extent {
+	var (
+		step        = query.Step
+		cacheBefore = xtime.ToUnixNano(c.nowFn()).Add(-c.maxFreshness)
+		keyPrefix   = c.keyPrefix(query)
+		extents     []extent
+	)
+	for windowStart := query.Start.Truncate(c.splitInterval); !windowStart.After(query.End); {
+		var (
+			windowEnd = windowStart.Add(c.splitInterval)
+			first     = alignToStep(windowStart, query.Start, step)
+			last      = alignToStep(windowEnd, query.Start, step).Add(-step)
+			start     = first
+			end       = last
+		)
+		if start.Before(query.Start) {
+			start = query.Start
+		}
+		if end.After(query.End) {
+			end = query.End
+		}
+
+		if !start.After(end) {
+			e := extent{start: start, end: end}
+			if start == first && end == last && !windowEnd.After(cacheBefore) {
+				e.key = fmt.Sprintf("%s:%d", keyPrefix, int64(windowStart))
+			}
+			extents = append(extents, e)
+		}
+
+		windowStart = windowEnd
+	}
+	return extents
+}
+
+// keyPrefix returns the prefix of the keys of the extents of the query,
+// queries only share extents if their steps are at the same times.
+func (c *resultCache) keyPrefix(query Query) string {
+	phase := int64(query.Start) % int64(query.Step)
+	if phase < 0 {
+		phase += int64(query.Step)
+	}
+	return fmt.Sprintf("%x:%d:%d:%d:%d", sha256.Sum256([]byte(query.Key)),
+		c.generation.Load(), int64(c.splitInterval), int64(query.Step), phase)
+}
+
+func (c *resultCache) Invalidate() {
+	c.generation.Inc()
+	c.metrics.invalidations.Inc(1)
+}
+
+func (c *resultCache) get(ctx context.Context, e extent, step time.Duration) *Result {
+	data, ok, err := c.backend.Get(ctx, e.key)
+	if err != nil {
+		c.metrics.getErrors.Inc(1)
+		c.logger.Warn("unable to get cached extent", zap.String("key", e.key), zap.Error(err))
+		return nil
+	}
+	if !ok {
+		c.metrics.misses.Inc(1)
+		return nil
+	}
+
+	res, err := decodeExtent(data, e.start, e.end, step, c.tagOpts)
+	if err != nil {
+		c.metrics.getErrors.Inc(1)
+		c.logger.Warn("unable to decode cached extent", zap.String("key", e.key), zap.Error(err))
+		return nil
+	}
+
+	c.metrics.hits.Inc(1)
+	return &res
+}
+
+// setAll caches each cacheable extent of the result, results that may be
+// incomplete are never cached.
+func (c *resultCache) setAll(
+	ctx context.Context,
+	extents []extent,
+	step time.Duration,
+	res Result,
+) {
+	if !res.Meta.Exhaustive || len(res.Meta.Warnings) > 0 {
+		return
+	}
+
+	for _, e := range extents {
+		if e.key == "" {
+			continue
+		}
+
+		data, err := encodeExtent(res, e.start, e.end, step)
+		if err == nil {
+			err = c.backend.Set(ctx, e.key, data)
+		}
+		if err != nil {
+			c.metrics.setErrors.Inc(1)
+			c.logger.Warn("unable to cache extent", zap.String("key", e.key), zap.Error(err))
+		}
+	}
+}
+
+// alignToStep returns the first step at or after t of a query with its first
+// step at start.
+func alignToStep(t, start xtime.UnixNano, step time.Duration) xtime.UnixNano {
+	// NB: integer division truncates towards zero so this is the ceiling of
+	// the number of steps when t is before start.
+	steps := int64(t-start) / int64(step)
+	aligned := start.Add(time.Duration(steps) * step)
+	if aligned.Before(t) {
+		aligned = aligned.Add(step)
+	}
+	return aligned
+}
+
+// stepIndex returns the index of the step at t of a range of steps.
+func stepIndex(t, start, end xtime.UnixNano, step time.Duration) (int, bool) {
+	if t.Before(start) || t.After(end) {
+		return 0, false
+	}
+	offset := t.Sub(start)
+	if offset%step != 0 {
+		return 0, false
+	}
+	return int(offset / step), true
+}
+
+// mergeResults merges the results of the extents of a query, series are
+// matched across results by their tags.
+func mergeResults(query Query, results []Result) Result {
+	var (
+		numSteps = int(query.End.Sub(query.Start)/query.Step) + 1
+		meta     = block.NewResultMetadata()
+		keepNaNs bool
+		series   []*ts.Series
+		values   []ts.FixedResolutionMutableValues
+		byKey    = make(map[string]int)
+	)
+	for _, res := range results {
+		meta = meta.CombineMetadata(res.Meta)
+		keepNaNs = keepNaNs || res.Meta.KeepNaNs
+
+		for _, s := range res.Series {
+			key := seriesKey(s.Tags)
+			idx, ok := byKey[key]
+			if !ok {
+				idx = len(series)
+				byKey[key] = idx
+				v := ts.NewFixedStepValues(query.Step, numSteps, math.NaN(), query.Start)
+				values = append(values, v)
+				series = append(series, ts.NewSeries(s.Name(), v, s.Tags))
+			}
+
+			vals := s.Values()
+			for i := 0; i < vals.Len(); i++ {
+				dp := vals.DatapointAt(i)
+				if n, ok := stepIndex(dp.Timestamp, query.Start, query.End, query.Step); ok {
+					values[idx].SetValueAt(n, dp.Value)
+				}
+			}
+		}
+	}
+
+	meta.KeepNaNs = keepNaNs
+	return Result{Series: series, Meta: meta}
+}
+
+func seriesKey(tags models.Tags) string {
+	var (
+		buf    []byte
+		varint [binary.MaxVarintLen64]byte
+	)
+	for _, tag := range tags.Tags {
+		n := binary.PutUvarint(varint[:], uint64(len(tag.Name)))
+		buf = append(append(buf, varint[:n]...), tag.Name...)
+		n = binary.PutUvarint(varint[:], uint64(len(tag.Value)))
+		buf = append(append(buf, varint[:n]...), tag.Value...)
+	}
+	return string(buf)
+}
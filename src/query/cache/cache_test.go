@@ -0,0 +1,372 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cache
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/m3db/m3/src/query/block"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/ts"
+	"github.com/m3db/m3/src/x/clock"
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+var testNow = time.Date(2021, time.January, 10, 0, 0, 0, 0, time.UTC)
+
+type executedRange struct {
+	start xtime.UnixNano
+	end   xtime.UnixNano
+}
+
+type testQueryFn struct {
+	step     time.Duration
+	meta     block.ResultMetadata
+	executed []executedRange
+}
+
+func newTestQueryFn(step time.Duration) *testQueryFn {
+	return &testQueryFn{step: step, meta: block.NewResultMetadata()}
+}
+
+// query returns two series with the value of each step set to the unix
+// seconds of the step.
+func (f *testQueryFn) query(
+	_ context.Context,
+	start, end xtime.UnixNano,
+) (Result, error) {
+	f.executed = append(f.executed, executedRange{start: start, end: end})
+	return Result{
+		Series: []*ts.Series{
+			testSeries("a", start, end, f.step, 1),
+			testSeries("b", start, end, f.step, 2),
+		},
+		Meta: f.meta,
+	}, nil
+}
+
+func testSeries(
+	value string,
+	start, end xtime.UnixNano,
+	step time.Duration,
+	multiplier float64,
+) *ts.Series {
+	numSteps := int(end.Sub(start)/step) + 1
+	values := ts.NewFixedStepValues(step, numSteps, math.NaN(), start)
+	for i := 0; i < numSteps; i++ {
+		t := start.Add(time.Duration(i) * step)
+		values.SetValueAt(i, multiplier*float64(t.Seconds()))
+	}
+
+	tags := models.NewTags(1, models.NewTagOptions()).
+		AddTag(models.Tag{Name: []byte("foo"), Value: []byte(value)})
+	return ts.NewSeries([]byte("name"), values, tags)
+}
+
+func newTestCache(t *testing.T) Cache {
+	opts := NewOptions().
+		SetSplitInterval(time.Hour).
+		SetMaxFreshness(10 * time.Minute).
+		SetBackend(NewMemoryBackend(MemoryBackendOptions{})).
+		SetTagOptions(models.NewTagOptions()).
+		SetClockOptions(clock.NewOptions().SetNowFn(func() time.Time {
+			return testNow
+		}))
+	c, err := NewCache(opts)
+	require.NoError(t, err)
+	return c
+}
+
+func assertResultEqual(t *testing.T, expected, actual Result) {
+	require.Equal(t, len(expected.Series), len(actual.Series))
+	for i, ex := range expected.Series {
+		ac := actual.Series[i]
+		assert.Equal(t, ex.Name(), ac.Name())
+		assert.Equal(t, ex.Tags.Tags, ac.Tags.Tags)
+
+		exValues, acValues := ex.Values(), ac.Values()
+		require.Equal(t, exValues.Len(), acValues.Len())
+		for j := 0; j < exValues.Len(); j++ {
+			assert.Equal(t, exValues.DatapointAt(j), acValues.DatapointAt(j))
+		}
+	}
+}
+
+func TestCacheQuerySplitsAndCachesPastExtents(t *testing.T) {
+	var (
+		c     = newTestCache(t)
+		ctx   = context.Background()
+		step  = time.Minute
+		now   = xtime.ToUnixNano(testNow)
+		query = Query{
+			Key:   "foo",
+			Start: now.Add(-150 * time.Minute),
+			End:   now.Add(-time.Minute),
+			Step:  step,
+		}
+		fn = newTestQueryFn(step)
+	)
+
+	expected, err := newTestQueryFn(step).query(ctx, query.Start, query.End)
+	require.NoError(t, err)
+
+	// Nothing is cached so the whole query is executed.
+	res, err := c.Query(ctx, query, fn.query)
+	require.NoError(t, err)
+	assertResultEqual(t, expected, res)
+	assert.Equal(t, []executedRange{
+		{start: query.Start, end: query.End},
+	}, fn.executed)
+
+	// Only the full extent that is no longer fresh is cached.
+	fn.executed = nil
+	res, err = c.Query(ctx, query, fn.query)
+	require.NoError(t, err)
+	assertResultEqual(t, expected, res)
+	assert.Equal(t, []executedRange{
+		{start: query.Start, end: now.Add(-2*time.Hour - step)},
+		{start: now.Add(-time.Hour), end: query.End},
+	}, fn.executed)
+}
+
+func TestCacheQueryAlignsEndToStep(t *testing.T) {
+	var (
+		c     = newTestCache(t)
+		ctx   = context.Background()
+		step  = time.Minute
+		now   = xtime.ToUnixNano(testNow)
+		query = Query{
+			Key:   "foo",
+			Start: now.Add(-3 * time.Hour),
+			End:   now.Add(-time.Hour - 30*time.Second),
+			Step:  step,
+		}
+		fn = newTestQueryFn(step)
+	)
+
+	_, err := c.Query(ctx, query, fn.query)
+	require.NoError(t, err)
+	assert.Equal(t, []executedRange{
+		{start: query.Start, end: now.Add(-time.Hour - step)},
+	}, fn.executed)
+
+	fn.executed = nil
+	res, err := c.Query(ctx, query, fn.query)
+	require.NoError(t, err)
+	assert.Equal(t, 0, len(fn.executed))
+
+	expected, err := newTestQueryFn(step).query(ctx, query.Start,
+		now.Add(-time.Hour-step))
+	require.NoError(t, err)
+	assertResultEqual(t, expected, res)
+}
+
+func TestCacheQueryDoesNotCacheIncompleteResults(t *testing.T) {
+	var (
+		c     = newTestCache(t)
+		ctx   = context.Background()
+		step  = time.Minute
+		now   = xtime.ToUnixNano(testNow)
+		query = Query{
+			Key:   "foo",
+			Start: now.Add(-3 * time.Hour),
+			End:   now.Add(-time.Minute),
+			Step:  step,
+		}
+		fn = newTestQueryFn(step)
+	)
+	fn.meta.Exhaustive = false
+
+	for i := 0; i < 2; i++ {
+		fn.executed = nil
+		_, err := c.Query(ctx, query, fn.query)
+		require.NoError(t, err)
+		assert.Equal(t, []executedRange{
+			{start: query.Start, end: query.End},
+		}, fn.executed)
+	}
+}
+
+func TestCacheQueryKeysByStepAndQuery(t *testing.T) {
+	var (
+		c     = newTestCache(t)
+		ctx   = context.Background()
+		step  = time.Minute
+		now   = xtime.ToUnixNano(testNow)
+		query = Query{
+			Key:   "foo",
+			Start: now.Add(-3 * time.Hour),
+			End:   now.Add(-2*time.Hour - step),
+			Step:  step,
+		}
+		fn = newTestQueryFn(step)
+	)
+
+	_, err := c.Query(ctx, query, fn.query)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(fn.executed))
+
+	// A differing key is not served from the cache.
+	fn.executed = nil
+	other := query
+	other.Key = "bar"
+	_, err = c.Query(ctx, other, fn.query)
+	require.NoError(t, err)
+	assert.Equal(t, 1, len(fn.executed))
+
+	// Neither are steps at differing times.
+	fn.executed = nil
+	other = query
+	other.Start = query.Start.Add(30 * time.Second)
+	_, err = c.Query(ctx, other, fn.query)
+	require.NoError(t, err)
+	assert.Equal(t, 1, len(fn.executed))
+
+	fn.executed = nil
+	_, err = c.Query(ctx, query, fn.query)
+	require.NoError(t, err)
+	assert.Equal(t, 0, len(fn.executed))
+}
+
+func TestCacheQueryUncacheable(t *testing.T) {
+	var (
+		c     = newTestCache(t)
+		ctx   = context.Background()
+		step  = 2 * time.Hour
+		now   = xtime.ToUnixNano(testNow)
+		query = Query{
+			Key:   "foo",
+			Start: now.Add(-24 * time.Hour),
+			End:   now.Add(-12 * time.Hour),
+			Step:  step,
+		}
+		fn = newTestQueryFn(step)
+	)
+
+	for i := 0; i < 2; i++ {
+		fn.executed = nil
+		_, err := c.Query(ctx, query, fn.query)
+		require.NoError(t, err)
+		assert.Equal(t, []executedRange{
+			{start: query.Start, end: query.End},
+		}, fn.executed)
+	}
+}
+
+func TestCacheInvalidate(t *testing.T) {
+	var (
+		c     = newTestCache(t)
+		ctx   = context.Background()
+		step  = time.Minute
+		now   = xtime.ToUnixNano(testNow)
+		query = Query{
+			Key:   "foo",
+			Start: now.Add(-3 * time.Hour),
+			End:   now.Add(-2*time.Hour - step),
+			Step:  step,
+		}
+		fn = newTestQueryFn(step)
+	)
+
+	_, err := c.Query(ctx, query, fn.query)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(fn.executed))
+
+	// Results cached before the cache was invalidated are not served.
+	c.Invalidate()
+	fn.executed = nil
+	_, err = c.Query(ctx, query, fn.query)
+	require.NoError(t, err)
+	assert.Equal(t, 1, len(fn.executed))
+
+	fn.executed = nil
+	_, err = c.Query(ctx, query, fn.query)
+	require.NoError(t, err)
+	assert.Equal(t, 0, len(fn.executed))
+}
+
+func TestMemoryBackendBoundsBytes(t *testing.T) {
+	ctx := context.Background()
+	b := NewMemoryBackend(MemoryBackendOptions{MaxBytes: 10})
+
+	require.NoError(t, b.Set(ctx, "a", []byte("aaaa")))
+	require.NoError(t, b.Set(ctx, "b", []byte("bbbb")))
+	_, ok, err := b.Get(ctx, "a")
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	// The least recently used extent is evicted once the size is exceeded.
+	require.NoError(t, b.Set(ctx, "c", []byte("cccc")))
+	_, ok, err = b.Get(ctx, "b")
+	require.NoError(t, err)
+	assert.False(t, ok)
+	value, ok, err := b.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []byte("aaaa"), value)
+
+	// Extents larger than the cache are not cached.
+	require.NoError(t, b.Set(ctx, "d", make([]byte, 11)))
+	_, ok, err = b.Get(ctx, "d")
+	require.NoError(t, err)
+	assert.False(t, ok)
+	_, ok, err = b.Get(ctx, "c")
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestMemoryBackendTTL(t *testing.T) {
+	var (
+		ctx = context.Background()
+		now = testNow
+		b   = NewMemoryBackend(MemoryBackendOptions{
+			TTL:   time.Minute,
+			NowFn: func() time.Time { return now },
+		})
+	)
+
+	require.NoError(t, b.Set(ctx, "a", []byte("a")))
+	_, ok, err := b.Get(ctx, "a")
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	now = now.Add(time.Minute)
+	_, ok, err = b.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestOptionsValidate(t *testing.T) {
+	opts := NewOptions()
+	assert.Error(t, opts.Validate())
+
+	opts = opts.SetBackend(NewMemoryBackend(MemoryBackendOptions{}))
+	assert.NoError(t, opts.Validate())
+
+	assert.Error(t, opts.SetSplitInterval(0).Validate())
+	assert.Error(t, opts.SetMaxFreshness(-time.Minute).Validate())
+}
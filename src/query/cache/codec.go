@@ -0,0 +1,129 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cache
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/m3db/m3/src/query/block"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/ts"
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+// cachedExtent is the encoded form of the result of an extent.
+type cachedExtent struct {
+	Resolutions []time.Duration
+	KeepNaNs    bool
+	Series      []cachedSeries
+}
+
+type cachedSeries struct {
+	Name   []byte
+	Tags   []models.Tag
+	Values []float64
+}
+
+// encodeExtent encodes the steps of the result within the extent.
+func encodeExtent(
+	res Result,
+	start, end xtime.UnixNano,
+	step time.Duration,
+) ([]byte, error) {
+	var (
+		numSteps = int(end.Sub(start)/step) + 1
+		extent   = cachedExtent{
+			Resolutions: res.Meta.Resolutions,
+			KeepNaNs:    res.Meta.KeepNaNs,
+			Series:      make([]cachedSeries, 0, len(res.Series)),
+		}
+	)
+	for _, s := range res.Series {
+		values := make([]float64, numSteps)
+		for i := range values {
+			values[i] = math.NaN()
+		}
+
+		vals := s.Values()
+		for i := 0; i < vals.Len(); i++ {
+			dp := vals.DatapointAt(i)
+			if n, ok := stepIndex(dp.Timestamp, start, end, step); ok {
+				values[n] = dp.Value
+			}
+		}
+
+		extent.Series = append(extent.Series, cachedSeries{
+			Name:   s.Name(),
+			Tags:   s.Tags.Tags,
+			Values: values,
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(extent); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeExtent decodes the result of the extent.
+func decodeExtent(
+	data []byte,
+	start, end xtime.UnixNano,
+	step time.Duration,
+	tagOpts models.TagOptions,
+) (Result, error) {
+	var extent cachedExtent
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&extent); err != nil {
+		return Result{}, err
+	}
+
+	numSteps := int(end.Sub(start)/step) + 1
+	meta := block.NewResultMetadata()
+	meta.Resolutions = extent.Resolutions
+	meta.KeepNaNs = extent.KeepNaNs
+
+	series := make([]*ts.Series, 0, len(extent.Series))
+	for _, s := range extent.Series {
+		if len(s.Values) != numSteps {
+			return Result{}, fmt.Errorf("cached extent has %d steps, expected %d",
+				len(s.Values), numSteps)
+		}
+
+		values := ts.NewFixedStepValues(step, numSteps, math.NaN(), start)
+		for i, v := range s.Values {
+			values.SetValueAt(i, v)
+		}
+		// NB: tags are restored in their original order so that series are
+		// matched with those of executed extents.
+		tags := models.NewTags(len(s.Tags), tagOpts)
+		for _, tag := range s.Tags {
+			tags = tags.AddTagWithoutNormalizing(tag)
+		}
+		series = append(series, ts.NewSeries(s.Name, values, tags))
+	}
+
+	return Result{Series: series, Meta: meta}, nil
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cache
+
+import (
+	"time"
+
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/x/instrument"
+)
+
+// Configuration configures the results cache of range queries.
+type Configuration struct {
+	// SplitInterval is the interval range queries are split into extents
+	// by, defaults to a day.
+	SplitInterval *time.Duration `yaml:"splitInterval"`
+
+	// MaxFreshness is how far in the past an extent must end before it is
+	// cached, defaults to ten minutes. This should be at least as large as
+	// the delay before late data is written.
+	MaxFreshness *time.Duration `yaml:"maxFreshness"`
+
+	// Memory configures the in memory backend.
+	Memory MemoryConfiguration `yaml:"memory"`
+}
+
+// MemoryConfiguration configures the in memory backend of the results cache.
+type MemoryConfiguration struct {
+	// MaxBytes is the maximum size in bytes of the extents cached, defaults
+	// to 256MiB.
+	MaxBytes int `yaml:"maxBytes"`
+
+	// TTL is how long extents are cached for, defaults to an hour. Series
+	// deleted through another coordinator can be returned for up to the TTL.
+	TTL time.Duration `yaml:"ttl"`
+}
+
+// NewCache returns a new results cache with an in memory backend.
+func (c Configuration) NewCache(
+	tagOpts models.TagOptions,
+	instrumentOpts instrument.Options,
+) (Cache, error) {
+	opts := NewOptions().
+		SetBackend(NewMemoryBackend(MemoryBackendOptions{
+			MaxBytes:          c.Memory.MaxBytes,
+			TTL:               c.Memory.TTL,
+			InstrumentOptions: instrumentOpts,
+		})).
+		SetTagOptions(tagOpts).
+		SetInstrumentOptions(instrumentOpts)
+	if c.SplitInterval != nil {
+		opts = opts.SetSplitInterval(*c.SplitInterval)
+	}
+	if c.MaxFreshness != nil {
+		opts = opts.SetMaxFreshness(*c.MaxFreshness)
+	}
+	return NewCache(opts)
+}
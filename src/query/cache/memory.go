@@ -0,0 +1,158 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/uber-go/tally"
+
+	"github.com/m3db/m3/src/x/instrument"
+)
+
+const (
+	defaultMemoryMaxBytes = 256 << 20
+	defaultMemoryTTL      = time.Hour
+)
+
+// MemoryBackendOptions are the options for an in memory backend.
+type MemoryBackendOptions struct {
+	// MaxBytes is the maximum size in bytes of the extents cached, the least
+	// recently used extents are evicted once reached.
+	MaxBytes int
+	// TTL is how long extents are cached for.
+	TTL time.Duration
+	// InstrumentOptions are the instrument options.
+	InstrumentOptions instrument.Options
+	// NowFn is the function used to get the current time.
+	NowFn func() time.Time
+}
+
+type memoryBackend struct {
+	sync.Mutex
+
+	maxBytes int
+	ttl      time.Duration
+	nowFn    func() time.Time
+	size     int
+	entries  map[string]*list.Element
+	// byAccess orders the entries from the most to the least recently used.
+	byAccess *list.List
+	metrics  memoryBackendMetrics
+}
+
+type memoryEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+type memoryBackendMetrics struct {
+	size      tally.Gauge
+	evictions tally.Counter
+	expired   tally.Counter
+}
+
+// NewMemoryBackend returns a new backend that caches extents in memory.
+func NewMemoryBackend(opts MemoryBackendOptions) Backend {
+	if opts.MaxBytes <= 0 {
+		opts.MaxBytes = defaultMemoryMaxBytes
+	}
+	if opts.TTL <= 0 {
+		opts.TTL = defaultMemoryTTL
+	}
+	if opts.InstrumentOptions == nil {
+		opts.InstrumentOptions = instrument.NewOptions()
+	}
+	if opts.NowFn == nil {
+		opts.NowFn = time.Now
+	}
+
+	scope := opts.InstrumentOptions.MetricsScope().
+		SubScope("result-cache").SubScope("memory")
+	return &memoryBackend{
+		maxBytes: opts.MaxBytes,
+		ttl:      opts.TTL,
+		nowFn:    opts.NowFn,
+		entries:  make(map[string]*list.Element),
+		byAccess: list.New(),
+		metrics: memoryBackendMetrics{
+			size:      scope.Gauge("size-bytes"),
+			evictions: scope.Counter("evictions"),
+			expired:   scope.Counter("expired"),
+		},
+	}
+}
+
+func (b *memoryBackend) Get(_ context.Context, key string) ([]byte, bool, error) {
+	b.Lock()
+	defer b.Unlock()
+
+	elem, ok := b.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+
+	entry := elem.Value.(*memoryEntry)
+	if !b.nowFn().Before(entry.expiresAt) {
+		b.metrics.expired.Inc(1)
+		b.removeWithLock(elem)
+		return nil, false, nil
+	}
+
+	b.byAccess.MoveToFront(elem)
+	return entry.value, true, nil
+}
+
+func (b *memoryBackend) Set(_ context.Context, key string, value []byte) error {
+	b.Lock()
+	defer b.Unlock()
+
+	if elem, ok := b.entries[key]; ok {
+		b.removeWithLock(elem)
+	}
+	// NB: values larger than the cache would evict every other extent.
+	if len(value) > b.maxBytes {
+		return nil
+	}
+
+	b.entries[key] = b.byAccess.PushFront(&memoryEntry{
+		key:       key,
+		value:     value,
+		expiresAt: b.nowFn().Add(b.ttl),
+	})
+	b.size += len(value)
+	for b.size > b.maxBytes {
+		b.metrics.evictions.Inc(1)
+		b.removeWithLock(b.byAccess.Back())
+	}
+	b.metrics.size.Update(float64(b.size))
+	return nil
+}
+
+func (b *memoryBackend) removeWithLock(elem *list.Element) {
+	entry := b.byAccess.Remove(elem).(*memoryEntry)
+	delete(b.entries, entry.key)
+	b.size -= len(entry.value)
+}
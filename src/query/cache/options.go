@@ -0,0 +1,138 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cache
+
+import (
+	"errors"
+	"time"
+
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/x/clock"
+	"github.com/m3db/m3/src/x/instrument"
+)
+
+const (
+	defaultSplitInterval = 24 * time.Hour
+	defaultMaxFreshness  = 10 * time.Minute
+)
+
+var (
+	errNoBackend            = errors.New("no backend set")
+	errNoTagOptions         = errors.New("no tag options set")
+	errInvalidSplitInterval = errors.New("split interval must be positive")
+	errInvalidMaxFreshness  = errors.New("max freshness must not be negative")
+)
+
+type options struct {
+	splitInterval  time.Duration
+	maxFreshness   time.Duration
+	backend        Backend
+	tagOpts        models.TagOptions
+	clockOpts      clock.Options
+	instrumentOpts instrument.Options
+}
+
+// NewOptions returns a new set of results cache options.
+func NewOptions() Options {
+	return &options{
+		splitInterval:  defaultSplitInterval,
+		maxFreshness:   defaultMaxFreshness,
+		tagOpts:        models.NewTagOptions(),
+		clockOpts:      clock.NewOptions(),
+		instrumentOpts: instrument.NewOptions(),
+	}
+}
+
+func (o *options) Validate() error {
+	if o.backend == nil {
+		return errNoBackend
+	}
+	if o.tagOpts == nil {
+		return errNoTagOptions
+	}
+	if o.splitInterval <= 0 {
+		return errInvalidSplitInterval
+	}
+	if o.maxFreshness < 0 {
+		return errInvalidMaxFreshness
+	}
+	return nil
+}
+
+func (o *options) SetSplitInterval(value time.Duration) Options {
+	opts := *o
+	opts.splitInterval = value
+	return &opts
+}
+
+func (o *options) SplitInterval() time.Duration {
+	return o.splitInterval
+}
+
+func (o *options) SetMaxFreshness(value time.Duration) Options {
+	opts := *o
+	opts.maxFreshness = value
+	return &opts
+}
+
+func (o *options) MaxFreshness() time.Duration {
+	return o.maxFreshness
+}
+
+func (o *options) SetBackend(value Backend) Options {
+	opts := *o
+	opts.backend = value
+	return &opts
+}
+
+func (o *options) Backend() Backend {
+	return o.backend
+}
+
+func (o *options) SetTagOptions(value models.TagOptions) Options {
+	opts := *o
+	opts.tagOpts = value
+	return &opts
+}
+
+func (o *options) TagOptions() models.TagOptions {
+	return o.tagOpts
+}
+
+func (o *options) SetClockOptions(value clock.Options) Options {
+	opts := *o
+	opts.clockOpts = value
+	return &opts
+}
+
+func (o *options) ClockOptions() clock.Options {
+	return o.clockOpts
+}
+
+func (o *options) SetInstrumentOptions(value instrument.Options) Options {
+	opts := *o
+	opts.instrumentOpts = value
+	return &opts
+}
+
+func (o *options) InstrumentOptions() instrument.Options {
+	return o.instrumentOpts
+}
@@ -0,0 +1,125 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package cache provides a results cache for range queries that splits
+// queries into step aligned extents and only executes the extents that are
+// not already cached.
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/m3db/m3/src/query/block"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/ts"
+	"github.com/m3db/m3/src/x/clock"
+	"github.com/m3db/m3/src/x/instrument"
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+// Cache is a results cache for range queries.
+type Cache interface {
+	// Query returns the result of the query, using cached results for the
+	// extents of the query that are cached and executing the query with the
+	// given function over the remaining ranges.
+	Query(ctx context.Context, query Query, fn QueryFunc) (Result, error)
+
+	// Invalidate invalidates all the results cached so far, so that queries
+	// are executed again, e.g. once series are deleted.
+	Invalidate()
+}
+
+// Query is a range query.
+type Query struct {
+	// Key identifies the query and any options that change its result.
+	Key string
+	// Start is the time of the first step of the query.
+	Start xtime.UnixNano
+	// End is the time of the last step of the query.
+	End xtime.UnixNano
+	// Step is the query step.
+	Step time.Duration
+}
+
+// Result is the result of a range query.
+type Result struct {
+	Series []*ts.Series
+	Meta   block.ResultMetadata
+}
+
+// QueryFunc executes the query over the steps in the given range, with
+// both the start and end inclusive.
+type QueryFunc func(ctx context.Context, start, end xtime.UnixNano) (Result, error)
+
+// Backend stores encoded result extents, allowing results to be cached in
+// stores other than the process memory, e.g. ones shared by coordinators.
+type Backend interface {
+	// Get returns the value stored for the key, if any.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+
+	// Set stores the value for the key.
+	Set(ctx context.Context, key string, value []byte) error
+}
+
+// Options are the options for a results cache.
+type Options interface {
+	// Validate validates the options.
+	Validate() error
+
+	// SetSplitInterval sets the interval queries are split into extents by.
+	SetSplitInterval(value time.Duration) Options
+
+	// SplitInterval returns the interval queries are split into extents by.
+	SplitInterval() time.Duration
+
+	// SetMaxFreshness sets how far in the past an extent must end before it
+	// is cached, results more recent than this may still change as late
+	// data arrives.
+	SetMaxFreshness(value time.Duration) Options
+
+	// MaxFreshness returns how far in the past an extent must end before it
+	// is cached.
+	MaxFreshness() time.Duration
+
+	// SetBackend sets the backend extents are stored in.
+	SetBackend(value Backend) Options
+
+	// Backend returns the backend extents are stored in.
+	Backend() Backend
+
+	// SetTagOptions sets the tag options.
+	SetTagOptions(value models.TagOptions) Options
+
+	// TagOptions returns the tag options.
+	TagOptions() models.TagOptions
+
+	// SetClockOptions sets the clock options.
+	SetClockOptions(value clock.Options) Options
+
+	// ClockOptions returns the clock options.
+	ClockOptions() clock.Options
+
+	// SetInstrumentOptions sets the instrument options.
+	SetInstrumentOptions(value instrument.Options) Options
+
+	// InstrumentOptions returns the instrument options.
+	InstrumentOptions() instrument.Options
+}
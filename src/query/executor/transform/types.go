@@ -117,6 +117,9 @@ type TimeSpec struct {
 	Now time.Time
 	// Step is the step size for the query.
 	Step time.Duration
+	// QueryStart is the start of the query, Start may be before it to
+	// include the range of the query.
+	QueryStart xtime.UnixNano
 }
 
 // Bounds transforms the timespec to bounds.
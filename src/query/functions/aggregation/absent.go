@@ -38,11 +38,23 @@ const (
 
 // NewAbsentOp creates a new absent operation.
 func NewAbsentOp() parser.Params {
-	return newAbsentOp()
+	return newAbsentOp(nil, false)
+}
+
+// NewAbsentOpWithTags creates a new absent operation which returns a series
+// with the given tags if no series are present, or if none of the present
+// series have a value at a given step.
+func NewAbsentOpWithTags(tags []models.Tag) parser.Params {
+	return newAbsentOp(tags, true)
 }
 
 // absentOp stores required properties for absent ops.
-type absentOp struct{}
+type absentOp struct {
+	tags []models.Tag
+	// onlyTags uses only the given tags for the returned series, rather than
+	// any tags common to the present series.
+	onlyTags bool
+}
 
 // OpType for the operator.
 func (o absentOp) OpType() string {
@@ -65,14 +77,14 @@ func (o absentOp) Node(
 	}
 }
 
-func newAbsentOp() absentOp {
-	return absentOp{}
+func newAbsentOp(tags []models.Tag, onlyTags bool) absentOp {
+	return absentOp{tags: tags, onlyTags: onlyTags}
 }
 
 // absentNode is different from base node as it uses no grouping and has
 // special handling for the 0-series case.
 type absentNode struct {
-	op         parser.Params
+	op         absentOp
 	controller *transform.Controller
 }
 
@@ -101,14 +113,6 @@ func (n *absentNode) ProcessBlock(
 		tagOpts     = meta.Tags.Opts
 	)
 
-	// If no series in the input, return a scalar block with value 1.
-	if len(seriesMetas) == 0 {
-		return block.NewScalar(1, meta), nil
-	}
-
-	// NB: pull any common tags out into the created series.
-	dupeTags, _ := utils.DedupeMetadata(seriesMetas, tagOpts)
-	meta.Tags = meta.Tags.Add(dupeTags).Normalize()
 	emptySeriesMeta := []block.SeriesMeta{
 		block.SeriesMeta{
 			Tags: models.NewTags(0, tagOpts),
@@ -116,6 +120,36 @@ func (n *absentNode) ProcessBlock(
 		},
 	}
 
+	// If no series in the input, return a series with value 1 at every step.
+	if len(seriesMetas) == 0 {
+		meta.Tags = meta.Tags.AddTagsIfNotExists(n.op.tags)
+		builder, err := n.controller.BlockBuilder(queryCtx, meta, emptySeriesMeta)
+		if err != nil {
+			return nil, err
+		}
+
+		steps := meta.Bounds.Steps()
+		if err := builder.AddCols(steps); err != nil {
+			return nil, err
+		}
+
+		for i := 0; i < steps; i++ {
+			if err := builder.AppendValue(i, 1); err != nil {
+				return nil, err
+			}
+		}
+
+		return builder.Build(), nil
+	}
+
+	if n.op.onlyTags {
+		meta.Tags = meta.Tags.AddTagsIfNotExists(n.op.tags)
+	} else {
+		// NB: pull any common tags out into the created series.
+		dupeTags, _ := utils.DedupeMetadata(seriesMetas, tagOpts)
+		meta.Tags = meta.Tags.Add(dupeTags).Normalize()
+	}
+
 	setupBuilderWithValuesToIndex := func(idx int) (block.Builder, error) {
 		builder, err := n.controller.BlockBuilder(queryCtx, meta, emptySeriesMeta)
 		if err != nil {
@@ -147,3 +147,56 @@ func TestAbsent(t *testing.T) {
 		})
 	}
 }
+
+func TestAbsentWithTags(t *testing.T) {
+	block := test.NewBlockFromValuesWithMetaAndSeriesMeta(
+		test.MustMakeMeta(testBound, "A", "B"),
+		[]block.SeriesMeta{},
+		[][]float64{},
+	)
+
+	c, sink := executor.NewControllerWithSink(parser.NodeID(rune(1)))
+	absentOp := NewAbsentOpWithTags([]models.Tag{
+		{Name: []byte("C"), Value: []byte("D")},
+	})
+	op, ok := absentOp.(transform.Params)
+	require.True(t, ok)
+
+	node := op.Node(c, transform.Options{})
+	err := node.Process(models.NoopQueryContext(), parser.NodeID(rune(0)), block)
+	require.NoError(t, err)
+
+	require.Equal(t, 1, len(sink.Values))
+	compare.EqualsWithNans(t, []float64{1, 1, 1, 1}, sink.Values[0])
+	assert.True(t, test.MustMakeMeta(testBound, "A", "B", "C", "D").Equals(sink.Meta))
+}
+
+func TestAbsentWithTagsSeriesWithoutValues(t *testing.T) {
+	block := test.NewBlockFromValuesWithMetaAndSeriesMeta(
+		test.MustMakeMeta(testBound, "A", "B"),
+		[]block.SeriesMeta{
+			test.MustMakeSeriesMeta("E", "F"),
+			test.MustMakeSeriesMeta("E", "F"),
+		},
+		[][]float64{
+			{1, math.NaN(), math.NaN(), math.NaN()},
+			{1, 1, math.NaN(), math.NaN()},
+		},
+	)
+
+	c, sink := executor.NewControllerWithSink(parser.NodeID(rune(1)))
+	absentOp := NewAbsentOpWithTags([]models.Tag{
+		{Name: []byte("C"), Value: []byte("D")},
+	})
+	op, ok := absentOp.(transform.Params)
+	require.True(t, ok)
+
+	node := op.Node(c, transform.Options{})
+	err := node.Process(models.NoopQueryContext(), parser.NodeID(rune(0)), block)
+	require.NoError(t, err)
+
+	// NB: tags common to the present series are not added to the result.
+	require.Equal(t, 1, len(sink.Values))
+	compare.EqualsWithNans(t, []float64{nan, nan, 1, 1}, sink.Values[0])
+	assert.True(t, test.MustMakeMeta(testBound, "A", "B", "C", "D").Equals(sink.Meta))
+}
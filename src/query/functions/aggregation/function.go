@@ -41,6 +41,9 @@ const (
 	StandardVarianceType = "var"
 	// CountType counts all non nan elements in a list of series.
 	CountType = "count"
+	// GroupType returns 1 for each group with any non nan elements in a list
+	// of series.
+	GroupType = "group"
 )
 
 func absentFn(values []float64, bucket []int) float64 {
@@ -163,3 +166,13 @@ func countFn(values []float64, bucket []int) float64 {
 	_, count := sumAndCount(values, bucket)
 	return count
 }
+
+func groupFn(values []float64, bucket []int) float64 {
+	for _, idx := range bucket {
+		if !math.IsNaN(values[idx]) {
+			return 1
+		}
+	}
+
+	return math.NaN()
+}
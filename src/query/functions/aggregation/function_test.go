@@ -52,6 +52,7 @@ var fnTest = []struct {
 			{StandardDeviationType, stddevFn, []float64{}},
 			{StandardVarianceType, varianceFn, []float64{}},
 			{CountType, countFn, []float64{}},
+			{GroupType, groupFn, []float64{}},
 		},
 	},
 	{
@@ -63,6 +64,7 @@ var fnTest = []struct {
 			{StandardDeviationType, stddevFn, []float64{0}},
 			{StandardVarianceType, varianceFn, []float64{0}},
 			{CountType, countFn, []float64{1}},
+			{GroupType, groupFn, []float64{1}},
 		},
 	},
 	{
@@ -110,6 +112,7 @@ var fnTest = []struct {
 			{StandardDeviationType, stddevFn, []float64{2, 36.73403}},
 			{StandardVarianceType, varianceFn, []float64{4, 1349.38889}},
 			{CountType, countFn, []float64{6, 6}},
+			{GroupType, groupFn, []float64{1, 1}},
 		},
 	},
 	{
@@ -123,6 +126,7 @@ var fnTest = []struct {
 			{StandardDeviationType, stddevFn, []float64{2.44949}},
 			{StandardVarianceType, varianceFn, []float64{6}},
 			{CountType, countFn, []float64{4}},
+			{GroupType, groupFn, []float64{1}},
 			{AbsentType, absentFn, []float64{nan}},
 		},
 	},
@@ -137,6 +141,7 @@ var fnTest = []struct {
 			{StandardDeviationType, stddevFn, []float64{nan}},
 			{StandardVarianceType, varianceFn, []float64{nan}},
 			{CountType, countFn, []float64{0}},
+			{GroupType, groupFn, []float64{nan}},
 			{AbsentType, absentFn, []float64{1}},
 		},
 	},
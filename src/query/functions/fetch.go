@@ -32,6 +32,7 @@ import (
 	"github.com/m3db/m3/src/query/util/logging"
 	"github.com/m3db/m3/src/x/instrument"
 	"github.com/m3db/m3/src/x/opentracing"
+	xtime "github.com/m3db/m3/src/x/time"
 
 	"go.uber.org/zap"
 )
@@ -46,6 +47,23 @@ type FetchOp struct {
 	Range    time.Duration
 	Offset   time.Duration
 	Matchers models.Matchers
+	// At is the time the fetch is evaluated at when set by the @ modifier.
+	At *FetchAt
+	// FunctionHint is the name of the function over time applied to the
+	// fetched series, used to select the aggregate of downsampled tiles.
+	FunctionHint string
+}
+
+// FetchAt is the time set by the @ modifier that a fetch is evaluated at,
+// every step of the fetch has the values as of that time.
+type FetchAt struct {
+	// Timestamp is the time the fetch is evaluated at, unless it is
+	// evaluated at the start or the end of the query.
+	Timestamp xtime.UnixNano
+	// Start is set if the fetch is evaluated at the start of the query.
+	Start bool
+	// End is set if the fetch is evaluated at the end of the query.
+	End bool
 }
 
 // FetchNode is a fetch execution node.
@@ -76,6 +94,12 @@ func (o FetchOp) Bounds() transform.BoundSpec {
 
 // String is the string representation for this operation.
 func (o FetchOp) String() string {
+	if o.At != nil {
+		return fmt.Sprintf("type: %s. name: %s, range: %v, offset: %v, "+
+			"matchers: %v, at: %v", o.OpType(), o.Name, o.Range, o.Offset,
+			o.Matchers, *o.At)
+	}
+
 	return fmt.Sprintf("type: %s. name: %s, range: %v, offset: %v, matchers: %v",
 		o.OpType(), o.Name, o.Range, o.Offset, o.Matchers)
 }
@@ -114,7 +138,11 @@ func (n *FetchNode) fetch(queryCtx *models.QueryContext) (block.Result, error) {
 		return block.Result{}, err
 	}
 
-	offset := n.op.Offset
+	if n.op.FunctionHint != "" {
+		opts.FunctionHint = n.op.FunctionHint
+	}
+
+	offset := n.offset()
 	return n.storage.FetchBlocks(ctx, &storage.FetchQuery{
 		Start:       startTime.Add(-1 * offset).ToTime(),
 		End:         endTime.Add(-1 * offset).ToTime(),
@@ -123,6 +151,51 @@ func (n *FetchNode) fetch(queryCtx *models.QueryContext) (block.Result, error) {
 	}, opts)
 }
 
+// offset returns the offset to fetch at. If the fetch is evaluated at a time
+// set by the @ modifier, the fetch is offset so that its last step is at that
+// time.
+func (n *FetchNode) offset() time.Duration {
+	at := n.op.At
+	if at == nil {
+		return n.op.Offset
+	}
+
+	var (
+		timeSpec = n.timespec
+		steps    = timeSpec.Bounds().Steps()
+		lastStep = timeSpec.Start.Add(time.Duration(steps-1) * timeSpec.Step)
+		atTime   = at.Timestamp
+	)
+
+	if at.Start {
+		atTime = timeSpec.QueryStart
+	} else if at.End {
+		atTime = lastStep
+	}
+
+	return lastStep.Sub(atTime) + n.op.Offset
+}
+
+// atBlock re-times a block fetched at a time set by the @ modifier to the
+// time of the query.
+func (n *FetchNode) atBlock(b block.Block) block.Block {
+	offset := n.offset()
+	if n.op.At == nil || offset == 0 {
+		return b
+	}
+
+	lazyOpts := block.NewLazyOptions().
+		SetTimeTransform(func(t xtime.UnixNano) xtime.UnixNano {
+			return t.Add(offset)
+		}).
+		SetMetaTransform(func(meta block.Metadata) block.Metadata {
+			meta.Bounds.Start = meta.Bounds.Start.Add(offset)
+			return meta
+		})
+
+	return block.NewLazyBlock(b, lazyOpts)
+}
+
 // Execute runs the fetch node operation
 func (n *FetchNode) Execute(queryCtx *models.QueryContext) error {
 	ctx := queryCtx.Ctx
@@ -132,6 +205,7 @@ func (n *FetchNode) Execute(queryCtx *models.QueryContext) error {
 	}
 
 	for _, block := range blockResult.Blocks {
+		block = n.atBlock(block)
 		if n.debug {
 			// Ignore any errors
 			iter, _ := block.StepIter()
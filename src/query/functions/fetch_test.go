@@ -123,6 +123,94 @@ func TestOffsetFetch(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestAtFetch(t *testing.T) {
+	now := time.Now().Truncate(time.Minute)
+	start := now.Add(time.Hour * -1)
+	// NB: the last step of the query is a minute before its exclusive end.
+	lastStep := now.Add(-time.Minute)
+
+	tests := []struct {
+		name   string
+		at     FetchAt
+		offset time.Duration
+	}{
+		{
+			name:   "timestamp",
+			at:     FetchAt{Timestamp: xtime.ToUnixNano(start.Add(10 * time.Minute))},
+			offset: 49 * time.Minute,
+		},
+		{
+			name:   "start",
+			at:     FetchAt{Start: true},
+			offset: 59 * time.Minute,
+		},
+		{
+			name:   "end",
+			at:     FetchAt{End: true},
+			offset: 0,
+		},
+		{
+			name:   "after the query",
+			at:     FetchAt{Timestamp: xtime.ToUnixNano(now.Add(time.Hour))},
+			offset: -61 * time.Minute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			at := tt.at
+			op := &FetchOp{
+				Offset: time.Minute,
+				At:     &at,
+			}
+
+			opts := transformtest.Options(t, transform.OptionsParams{
+				TimeSpec: transform.TimeSpec{
+					Start:      xtime.ToUnixNano(start),
+					End:        xtime.ToUnixNano(now),
+					Now:        now,
+					Step:       time.Minute,
+					QueryStart: xtime.ToUnixNano(start),
+				},
+			})
+
+			offset := tt.offset + time.Minute
+			qMatcher := &predicateMatcher{
+				name: "query",
+				fn: func(i interface{}) bool {
+					q, ok := i.(*storage.FetchQuery)
+					if !ok {
+						return false
+					}
+
+					return q.Start.Equal(start.Add(-offset)) &&
+						q.End.Equal(now.Add(-offset)) &&
+						lastStep.Add(-offset).Equal(q.End.Add(-time.Minute))
+				},
+			}
+
+			values, bounds := test.GenerateValuesAndBounds(nil, nil)
+			bounds.Start = xtime.ToUnixNano(start.Add(-offset))
+			b := test.NewBlockFromValues(bounds, values)
+
+			store := storage.NewMockStorage(ctrl)
+			store.EXPECT().FetchBlocks(gomock.Any(), qMatcher, gomock.Any()).
+				Return(block.Result{Blocks: []block.Block{b}}, nil)
+
+			c, sink := executor.NewControllerWithSink(parser.NodeID(rune(1)))
+			node := op.Node(c, store, opts)
+
+			err := node.Execute(models.NoopQueryContext())
+			require.NoError(t, err)
+			assert.Equal(t, values, sink.Values)
+			assert.Equal(t, xtime.ToUnixNano(start), sink.Meta.Bounds.Start)
+		})
+	}
+}
+
 func TestFetchWithRestrictFetch(t *testing.T) {
 	values, bounds := test.GenerateValuesAndBounds(nil, nil)
 	b := test.NewBlockFromValues(bounds, values)
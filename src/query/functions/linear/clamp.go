@@ -37,6 +37,10 @@ const (
 	// ClampMaxType ensures all values except NaNs are lesser
 	// than or equal to provided argument.
 	ClampMaxType = "clamp_max"
+
+	// ClampType ensures all values except NaNs are greater than or equal
+	// to the first provided argument and lesser than or equal to the second.
+	ClampType = "clamp"
 )
 
 type clampOp struct {
@@ -57,6 +61,33 @@ func parseClampArgs(args []interface{}) (float64, error) {
 	return scalar, nil
 }
 
+func parseClampRangeArgs(args []interface{}) (float64, float64, error) {
+	if len(args) != 2 {
+		return 0, 0, fmt.Errorf("invalid number of args for clamp: %d", len(args))
+	}
+
+	min, ok := args[0].(float64)
+	if !ok {
+		return 0, 0, fmt.Errorf("unable to cast to scalar argument: %v", args[0])
+	}
+
+	max, ok := args[1].(float64)
+	if !ok {
+		return 0, 0, fmt.Errorf("unable to cast to scalar argument: %v", args[1])
+	}
+
+	return min, max, nil
+}
+
+func clampRangeFn(min, max float64) block.ValueTransform {
+	// NB: Prometheus returns no series when max is lesser than min.
+	if max < min {
+		return func(float64) float64 { return math.NaN() }
+	}
+
+	return func(v float64) float64 { return math.Max(min, math.Min(max, v)) }
+}
+
 func clampFn(max bool, roundTo float64) block.ValueTransform {
 	if max {
 		return func(v float64) float64 { return math.Min(v, roundTo) }
@@ -75,6 +106,18 @@ func removeName(meta []block.SeriesMeta) []block.SeriesMeta {
 
 // NewClampOp creates a new clamp op based on the type and arguments
 func NewClampOp(args []interface{}, opType string) (parser.Params, error) {
+	if opType == ClampType {
+		min, max, err := parseClampRangeArgs(args)
+		if err != nil {
+			return nil, err
+		}
+
+		lazyOpts := block.NewLazyOptions().
+			SetValueTransform(clampRangeFn(min, max)).
+			SetSeriesMetaTransform(removeName)
+		return lazy.NewLazyOp(opType, lazyOpts)
+	}
+
 	isMax := opType == ClampMaxType
 	if opType != ClampMinType && !isMax {
 		return nil, fmt.Errorf("unknown clamp type: %s", opType)
@@ -132,3 +132,23 @@ func TestClampWithArgs(t *testing.T) {
 	min := runClamp(t, toArgs(2), ClampMinType, v)
 	compare.EqualsWithNans(t, exMin, min)
 }
+
+func TestClampRangeWithArgs(t *testing.T) {
+	var (
+		v       = []float64{math.NaN(), 0, 1, 2, 3, math.Inf(1), math.Inf(-1)}
+		ex      = []float64{math.NaN(), 1, 1, 2, 2, 2, 1}
+		exEmpty = []float64{math.NaN(), math.NaN(), math.NaN(), math.NaN(),
+			math.NaN(), math.NaN(), math.NaN()}
+	)
+
+	clamped := runClamp(t, []interface{}{1.0, 2.0}, ClampType, v)
+	compare.EqualsWithNans(t, ex, clamped)
+
+	clamped = runClamp(t, []interface{}{2.0, 1.0}, ClampType, v)
+	compare.EqualsWithNans(t, exEmpty, clamped)
+}
+
+func TestClampRangeFailsParse(t *testing.T) {
+	_, err := NewClampOp(toArgs(1), ClampType)
+	assert.Error(t, err)
+}
@@ -0,0 +1,193 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package linear
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/m3db/m3/src/metrics/metric"
+	"github.com/m3db/m3/src/query/block"
+	"github.com/m3db/m3/src/query/executor/transform"
+	"github.com/m3db/m3/src/query/functions/utils"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/parser"
+)
+
+const (
+	// HistogramCountType returns the count of observations of native
+	// histograms.
+	//
+	// NB: native histograms are expanded into a series per bucket when they
+	// are fetched, the count is the value of the +Inf bucket series.
+	HistogramCountType = "histogram_count"
+
+	// HistogramSumType returns the sum of observations of native histograms.
+	HistogramSumType = "histogram_sum"
+)
+
+// NewHistogramFieldOp creates a new operation selecting a field of native
+// histograms.
+func NewHistogramFieldOp(opType string) (parser.Params, error) {
+	var selectFn histogramFieldSelectFn
+	switch opType {
+	case HistogramCountType:
+		selectFn = isHistogramCountSeries
+	case HistogramSumType:
+		selectFn = isHistogramSumSeries
+	default:
+		return nil, fmt.Errorf("operator not supported: %s", opType)
+	}
+
+	return histogramFieldOp{
+		opType:   opType,
+		selectFn: selectFn,
+	}, nil
+}
+
+type histogramFieldSelectFn func(tags models.Tags) bool
+
+func isHistogramCountSeries(tags models.Tags) bool {
+	marker, ok := tags.Get(metric.M3PromNativeHistogramTag)
+	if !ok || !bytes.Equal(marker, metric.PromNativeHistogramBucketValue) {
+		return false
+	}
+
+	value, ok := tags.Bucket()
+	if !ok {
+		return false
+	}
+
+	bound, err := strconv.ParseFloat(string(value), 64)
+	return err == nil && math.IsInf(bound, 1)
+}
+
+func isHistogramSumSeries(tags models.Tags) bool {
+	marker, ok := tags.Get(metric.M3PromNativeHistogramTag)
+	return ok && bytes.Equal(marker, metric.PromNativeHistogramSumValue)
+}
+
+// histogramFieldOp stores required properties for histogram field ops.
+type histogramFieldOp struct {
+	opType   string
+	selectFn histogramFieldSelectFn
+}
+
+// OpType for the operator.
+func (o histogramFieldOp) OpType() string {
+	return o.opType
+}
+
+// String representation.
+func (o histogramFieldOp) String() string {
+	return fmt.Sprintf("type: %s", o.OpType())
+}
+
+// Node creates an execution node.
+func (o histogramFieldOp) Node(
+	controller *transform.Controller,
+	_ transform.Options,
+) transform.OpNode {
+	return &histogramFieldNode{
+		op:         o,
+		controller: controller,
+	}
+}
+
+type histogramFieldNode struct {
+	op         histogramFieldOp
+	controller *transform.Controller
+}
+
+func (n *histogramFieldNode) Params() parser.Params {
+	return n.op
+}
+
+// Process the block
+func (n *histogramFieldNode) Process(
+	queryCtx *models.QueryContext,
+	ID parser.NodeID,
+	b block.Block,
+) error {
+	return transform.ProcessSimpleBlock(n, n.controller, queryCtx, ID, b)
+}
+
+func (n *histogramFieldNode) ProcessBlock(
+	queryCtx *models.QueryContext,
+	ID parser.NodeID,
+	b block.Block,
+) (block.Block, error) {
+	stepIter, err := b.StepIter()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		meta        = b.Meta()
+		seriesMetas = utils.FlattenMetadata(meta, stepIter.SeriesMeta())
+		indices     = make([]int, 0, len(seriesMetas))
+		metas       = make([]block.SeriesMeta, 0, len(seriesMetas))
+	)
+	for i, seriesMeta := range seriesMetas {
+		tags := seriesMeta.Tags
+		if !n.op.selectFn(tags) {
+			continue
+		}
+
+		tags = tags.TagsWithoutKeys([][]byte{
+			tags.Opts.MetricName(),
+			tags.Opts.BucketName(),
+			metric.M3PromNativeHistogramTag,
+		})
+		indices = append(indices, i)
+		metas = append(metas, block.SeriesMeta{Tags: tags})
+	}
+
+	meta.Tags, metas = utils.DedupeMetadata(metas, meta.Tags.Opts)
+	builder, err := n.controller.BlockBuilder(queryCtx, meta, metas)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = builder.AddCols(stepIter.StepCount()); err != nil {
+		return nil, err
+	}
+
+	values := make([]float64, len(indices))
+	for index := 0; stepIter.Next(); index++ {
+		stepValues := stepIter.Current().Values()
+		for i, idx := range indices {
+			values[i] = stepValues[idx]
+		}
+
+		if err := builder.AppendValues(index, values); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = stepIter.Err(); err != nil {
+		return nil, err
+	}
+
+	return builder.Build(), nil
+}
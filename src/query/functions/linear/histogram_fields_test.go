@@ -0,0 +1,115 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package linear
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/metrics/metric"
+	"github.com/m3db/m3/src/query/block"
+	"github.com/m3db/m3/src/query/executor/transform"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/parser"
+	"github.com/m3db/m3/src/query/test"
+	"github.com/m3db/m3/src/query/test/executor"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewHistogramFieldOp(t *testing.T) {
+	op, err := NewHistogramFieldOp(HistogramCountType)
+	require.NoError(t, err)
+	assert.Equal(t, HistogramCountType, op.OpType())
+
+	op, err = NewHistogramFieldOp(HistogramSumType)
+	require.NoError(t, err)
+	assert.Equal(t, HistogramSumType, op.OpType())
+
+	_, err = NewHistogramFieldOp(HistogramQuantileType)
+	require.Error(t, err)
+}
+
+func testHistogramField(
+	t *testing.T,
+	opType string,
+) ([]block.SeriesMeta, [][]float64) {
+	op, err := NewHistogramFieldOp(opType)
+	require.NoError(t, err)
+
+	tagOpts := models.NewTagOptions()
+	tags := models.NewTags(3, tagOpts).SetName([]byte("foo")).AddTag(models.Tag{
+		Name:  []byte("bar"),
+		Value: []byte("baz"),
+	})
+	bucketTags := tags.Clone().AddTag(models.Tag{
+		Name:  metric.M3PromNativeHistogramTag,
+		Value: metric.PromNativeHistogramBucketValue,
+	})
+
+	seriesMetas := []block.SeriesMeta{
+		{Tags: bucketTags.Clone().SetBucket([]byte("1"))},
+		{Tags: bucketTags.Clone().SetBucket([]byte("+Inf"))},
+		{Tags: tags.Clone().AddTag(models.Tag{
+			Name:  metric.M3PromNativeHistogramTag,
+			Value: metric.PromNativeHistogramSumValue,
+		})},
+		// Classic histogram buckets are not native histogram buckets.
+		{Tags: tags.Clone().SetBucket([]byte("+Inf"))},
+	}
+
+	v := [][]float64{
+		{1, 2, 3},
+		{4, 5, 6},
+		{7, 8, 9},
+		{10, 11, 12},
+	}
+
+	bounds := models.Bounds{
+		Start:    xtime.Now(),
+		Duration: time.Minute * 3,
+		StepSize: time.Minute,
+	}
+
+	bl := test.NewBlockFromValuesWithSeriesMeta(bounds, seriesMetas, v)
+	c, sink := executor.NewControllerWithSink(parser.NodeID(rune(1)))
+	node := op.(histogramFieldOp).Node(c, transform.Options{})
+	err = node.Process(models.NoopQueryContext(), parser.NodeID(rune(0)), bl)
+	require.NoError(t, err)
+
+	return sink.Metas, sink.Values
+}
+
+func TestHistogramCount(t *testing.T) {
+	metas, values := testHistogramField(t, HistogramCountType)
+	assert.Equal(t, [][]float64{{4, 5, 6}}, values)
+	require.Equal(t, 1, len(metas))
+	assert.Equal(t, 0, metas[0].Tags.Len())
+}
+
+func TestHistogramSum(t *testing.T) {
+	metas, values := testHistogramField(t, HistogramSumType)
+	assert.Equal(t, [][]float64{{7, 8, 9}}, values)
+	require.Equal(t, 1, len(metas))
+	assert.Equal(t, 0, metas[0].Tags.Len())
+}
@@ -26,6 +26,7 @@ import (
 	"sort"
 	"strconv"
 
+	"github.com/m3db/m3/src/metrics/metric"
 	"github.com/m3db/m3/src/query/block"
 	"github.com/m3db/m3/src/query/executor/transform"
 	"github.com/m3db/m3/src/query/functions/utils"
@@ -166,7 +167,13 @@ func gatherSeriesToBuckets(metas []block.SeriesMeta) validSeriesBuckets {
 			continue
 		}
 
-		excludeTags := [][]byte{tags.Opts.MetricName(), tags.Opts.BucketName()}
+		// NB: the native histogram marker tag is excluded so that expanded
+		// native histograms are returned the same as classic histograms.
+		excludeTags := [][]byte{
+			tags.Opts.MetricName(),
+			tags.Opts.BucketName(),
+			metric.M3PromNativeHistogramTag,
+		}
 		tagsWithoutKeys := tags.TagsWithoutKeys(excludeTags)
 		id := string(tagsWithoutKeys.ID())
 		newBucket := indexedBucket{
@@ -0,0 +1,305 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package linear
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/m3db/m3/src/metrics/metric"
+	"github.com/m3db/m3/src/query/block"
+	"github.com/m3db/m3/src/query/executor/transform"
+	"github.com/m3db/m3/src/query/functions/utils"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/parser"
+)
+
+const (
+	// HistogramStdDevType returns the estimated standard deviation of
+	// observations of native histograms.
+	//
+	// NB: each observation is estimated as the geometric mean of the
+	// boundaries of its bucket, or as zero for a bucket spanning zero.
+	HistogramStdDevType = "histogram_stddev"
+
+	// HistogramStdVarType returns the estimated standard variance of
+	// observations of native histograms.
+	HistogramStdVarType = "histogram_stdvar"
+)
+
+// NewHistogramStdVarOp creates a new operation estimating the standard
+// deviation or variance of native histograms.
+func NewHistogramStdVarOp(opType string) (parser.Params, error) {
+	if opType != HistogramStdDevType && opType != HistogramStdVarType {
+		return nil, fmt.Errorf("operator not supported: %s", opType)
+	}
+
+	return histogramStdVarOp{opType: opType}, nil
+}
+
+// histogramStdVarOp stores required properties for histogram variance ops.
+type histogramStdVarOp struct {
+	opType string
+}
+
+// OpType for the operator.
+func (o histogramStdVarOp) OpType() string {
+	return o.opType
+}
+
+// String representation.
+func (o histogramStdVarOp) String() string {
+	return fmt.Sprintf("type: %s", o.OpType())
+}
+
+// Node creates an execution node.
+func (o histogramStdVarOp) Node(
+	controller *transform.Controller,
+	_ transform.Options,
+) transform.OpNode {
+	return &histogramStdVarNode{
+		op:         o,
+		controller: controller,
+	}
+}
+
+type histogramStdVarNode struct {
+	op         histogramStdVarOp
+	controller *transform.Controller
+}
+
+// nativeHistogramSeries are the indices of the bucket and sum series that a
+// native histogram series is expanded into.
+type nativeHistogramSeries struct {
+	buckets indexedBuckets
+	sumIdx  int
+}
+
+func gatherNativeHistogramSeries(
+	metas []block.SeriesMeta,
+) []nativeHistogramSeries {
+	var (
+		series    []nativeHistogramSeries
+		seriesIdx = make(map[string]int, initIndexBucketLength)
+	)
+	for i, meta := range metas {
+		tags := meta.Tags
+		marker, ok := tags.Get(metric.M3PromNativeHistogramTag)
+		if !ok {
+			continue
+		}
+
+		isSum := bytes.Equal(marker, metric.PromNativeHistogramSumValue)
+		var bound float64
+		if !isSum {
+			if !bytes.Equal(marker, metric.PromNativeHistogramBucketValue) {
+				continue
+			}
+
+			value, ok := tags.Bucket()
+			if !ok {
+				continue
+			}
+
+			var err error
+			if bound, err = strconv.ParseFloat(string(value), 64); err != nil {
+				continue
+			}
+		}
+
+		tags = tags.TagsWithoutKeys([][]byte{
+			tags.Opts.MetricName(),
+			tags.Opts.BucketName(),
+			metric.M3PromNativeHistogramTag,
+		})
+		id := string(tags.ID())
+		idx, ok := seriesIdx[id]
+		if !ok {
+			idx = len(series)
+			seriesIdx[id] = idx
+			series = append(series, nativeHistogramSeries{
+				buckets: indexedBuckets{tags: tags},
+				sumIdx:  -1,
+			})
+		}
+
+		if isSum {
+			series[idx].sumIdx = i
+			continue
+		}
+
+		series[idx].buckets.buckets = append(series[idx].buckets.buckets,
+			indexedBucket{upperBound: bound, idx: i})
+	}
+
+	valid := series[:0]
+	for _, s := range series {
+		if s.sumIdx < 0 || len(s.buckets.buckets) == 0 {
+			continue
+		}
+
+		sort.Sort(s.buckets)
+		if !math.IsInf(s.buckets.buckets[len(s.buckets.buckets)-1].upperBound, 1) {
+			continue
+		}
+
+		valid = append(valid, s)
+	}
+
+	return valid
+}
+
+// bucketObservation returns the value that estimates the observations in
+// the bucket with the given boundaries.
+func bucketObservation(lower, upper float64) float64 {
+	switch {
+	case math.IsInf(lower, -1):
+		return upper
+	case math.IsInf(upper, 1):
+		return lower
+	case lower <= 0 && 0 <= upper:
+		return 0
+	}
+
+	val := math.Sqrt(lower * upper)
+	if upper < 0 {
+		return -val
+	}
+
+	return val
+}
+
+// NativeHistogramStdVar returns the estimated standard variance of the
+// observations of a native histogram, given the upper bounds of its buckets
+// in increasing order with their cumulative counts and the sum of its
+// observations.
+func NativeHistogramStdVar(upperBounds, counts []float64, sum float64) float64 {
+	if len(counts) == 0 {
+		return math.NaN()
+	}
+
+	count := counts[len(counts)-1]
+	if math.IsNaN(count) || math.IsNaN(sum) || count <= 0 {
+		return math.NaN()
+	}
+
+	var (
+		mean       = sum / count
+		lower      = math.Inf(-1)
+		cumulative float64
+		variance   float64
+	)
+	for i, upper := range upperBounds {
+		value := counts[i]
+		if math.IsNaN(value) {
+			return math.NaN()
+		}
+
+		if bucketCount := value - cumulative; bucketCount > 0 {
+			delta := bucketObservation(lower, upper) - mean
+			variance += bucketCount * delta * delta
+		}
+
+		lower = upper
+		cumulative = value
+	}
+
+	return variance / count
+}
+
+func (n *histogramStdVarNode) Params() parser.Params {
+	return n.op
+}
+
+// Process the block
+func (n *histogramStdVarNode) Process(
+	queryCtx *models.QueryContext,
+	ID parser.NodeID,
+	b block.Block,
+) error {
+	return transform.ProcessSimpleBlock(n, n.controller, queryCtx, ID, b)
+}
+
+func (n *histogramStdVarNode) ProcessBlock(
+	queryCtx *models.QueryContext,
+	ID parser.NodeID,
+	b block.Block,
+) (block.Block, error) {
+	stepIter, err := b.StepIter()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		meta   = b.Meta()
+		series = gatherNativeHistogramSeries(
+			utils.FlattenMetadata(meta, stepIter.SeriesMeta()))
+		metas = make([]block.SeriesMeta, 0, len(series))
+	)
+	for _, s := range series {
+		metas = append(metas, block.SeriesMeta{Tags: s.buckets.tags})
+	}
+
+	meta.Tags, metas = utils.DedupeMetadata(metas, meta.Tags.Opts)
+	builder, err := n.controller.BlockBuilder(queryCtx, meta, metas)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = builder.AddCols(stepIter.StepCount()); err != nil {
+		return nil, err
+	}
+
+	var (
+		values      = make([]float64, len(series))
+		upperBounds []float64
+		counts      []float64
+	)
+	for index := 0; stepIter.Next(); index++ {
+		stepValues := stepIter.Current().Values()
+		for i, s := range series {
+			upperBounds, counts = upperBounds[:0], counts[:0]
+			for _, bucket := range s.buckets.buckets {
+				upperBounds = append(upperBounds, bucket.upperBound)
+				counts = append(counts, stepValues[bucket.idx])
+			}
+
+			values[i] = NativeHistogramStdVar(upperBounds, counts,
+				stepValues[s.sumIdx])
+			if n.op.opType == HistogramStdDevType {
+				values[i] = math.Sqrt(values[i])
+			}
+		}
+
+		if err := builder.AppendValues(index, values); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = stepIter.Err(); err != nil {
+		return nil, err
+	}
+
+	return builder.Build(), nil
+}
@@ -0,0 +1,156 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package linear
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/metrics/metric"
+	"github.com/m3db/m3/src/query/block"
+	"github.com/m3db/m3/src/query/executor/transform"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/parser"
+	"github.com/m3db/m3/src/query/test"
+	"github.com/m3db/m3/src/query/test/compare"
+	"github.com/m3db/m3/src/query/test/executor"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewHistogramStdVarOp(t *testing.T) {
+	op, err := NewHistogramStdVarOp(HistogramStdDevType)
+	require.NoError(t, err)
+	assert.Equal(t, HistogramStdDevType, op.OpType())
+
+	op, err = NewHistogramStdVarOp(HistogramStdVarType)
+	require.NoError(t, err)
+	assert.Equal(t, HistogramStdVarType, op.OpType())
+
+	_, err = NewHistogramStdVarOp(HistogramSumType)
+	require.Error(t, err)
+}
+
+func testHistogramStdVar(
+	t *testing.T,
+	opType string,
+) ([]block.SeriesMeta, [][]float64) {
+	op, err := NewHistogramStdVarOp(opType)
+	require.NoError(t, err)
+
+	tagOpts := models.NewTagOptions()
+	tags := models.NewTags(3, tagOpts).SetName([]byte("foo")).AddTag(models.Tag{
+		Name:  []byte("bar"),
+		Value: []byte("baz"),
+	})
+	bucketTags := tags.Clone().AddTag(models.Tag{
+		Name:  metric.M3PromNativeHistogramTag,
+		Value: metric.PromNativeHistogramBucketValue,
+	})
+	sumTags := tags.Clone().AddTag(models.Tag{
+		Name:  metric.M3PromNativeHistogramTag,
+		Value: metric.PromNativeHistogramSumValue,
+	})
+	otherBucketTags := bucketTags.Clone().AddTag(models.Tag{
+		Name:  []byte("qux"),
+		Value: []byte("quz"),
+	})
+
+	seriesMetas := []block.SeriesMeta{
+		{Tags: bucketTags.Clone().SetBucket([]byte("+Inf"))},
+		{Tags: bucketTags.Clone().SetBucket([]byte("0.5"))},
+		{Tags: bucketTags.Clone().SetBucket([]byte("2"))},
+		{Tags: bucketTags.Clone().SetBucket([]byte("1"))},
+		{Tags: bucketTags.Clone().SetBucket([]byte("4"))},
+		{Tags: sumTags},
+		// Native histograms without a sum are dropped.
+		{Tags: otherBucketTags.Clone().SetBucket([]byte("+Inf"))},
+		// Classic histogram buckets are not native histogram buckets.
+		{Tags: tags.Clone().SetBucket([]byte("+Inf"))},
+	}
+
+	v := [][]float64{
+		{4, 0, 1},
+		{0, 0, 0},
+		{3, 0, 1},
+		{1, 0, 0},
+		{4, 0, 1},
+		{6, 0, -2},
+		{1, 1, 1},
+		{1, 1, 1},
+	}
+
+	bounds := models.Bounds{
+		Start:    xtime.Now(),
+		Duration: time.Minute * 3,
+		StepSize: time.Minute,
+	}
+
+	bl := test.NewBlockFromValuesWithSeriesMeta(bounds, seriesMetas, v)
+	c, sink := executor.NewControllerWithSink(parser.NodeID(rune(1)))
+	node := op.(histogramStdVarOp).Node(c, transform.Options{})
+	err = node.Process(models.NoopQueryContext(), parser.NodeID(rune(0)), bl)
+	require.NoError(t, err)
+
+	return sink.Metas, sink.Values
+}
+
+func expectedHistogramStdVar() []float64 {
+	// NB: the first step has observations in (0.5, 1], (1, 2] twice and
+	// (2, 4] with a mean of 1.5, the second has no observations and the
+	// third has a single observation in (1, 2] of -2.
+	var (
+		mean   = 1.5
+		first  = math.Pow(math.Sqrt(0.5)-mean, 2)
+		second = math.Pow(math.Sqrt(2)-mean, 2)
+		third  = math.Pow(math.Sqrt(8)-mean, 2)
+	)
+
+	return []float64{
+		(first + 2*second + third) / 4,
+		math.NaN(),
+		math.Pow(math.Sqrt(2)+2, 2),
+	}
+}
+
+func TestHistogramStdVar(t *testing.T) {
+	metas, values := testHistogramStdVar(t, HistogramStdVarType)
+	require.Equal(t, 1, len(values))
+	compare.EqualsWithNansWithDelta(t, expectedHistogramStdVar(), values[0], 0.0001)
+	require.Equal(t, 1, len(metas))
+	assert.Equal(t, 0, metas[0].Tags.Len())
+}
+
+func TestHistogramStdDev(t *testing.T) {
+	metas, values := testHistogramStdVar(t, HistogramStdDevType)
+	expected := expectedHistogramStdVar()
+	for i, v := range expected {
+		expected[i] = math.Sqrt(v)
+	}
+
+	require.Equal(t, 1, len(values))
+	compare.EqualsWithNansWithDelta(t, expected, values[0], 0.0001)
+	require.Equal(t, 1, len(metas))
+	assert.Equal(t, 0, metas[0].Tags.Len())
+}
@@ -25,7 +25,9 @@ import (
 	"math"
 
 	"github.com/m3db/m3/src/query/block"
+	"github.com/m3db/m3/src/query/executor/transform"
 	"github.com/m3db/m3/src/query/functions/lazy"
+	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/query/parser"
 )
 
@@ -60,6 +62,10 @@ const (
 
 	// Log10Type calculates the decimal logarithm for values.
 	Log10Type = "log10"
+
+	// SgnType returns the sign of all values, 1 for positive values, -1 for
+	// negative values and 0 for zero values.
+	SgnType = "sgn"
 )
 
 var (
@@ -72,15 +78,87 @@ var (
 		LnType:    math.Log,
 		Log2Type:  math.Log2,
 		Log10Type: math.Log10,
+		SgnType:   sgn,
 	}
 )
 
+func sgn(v float64) float64 {
+	if v > 0 {
+		return 1
+	} else if v < 0 {
+		return -1
+	}
+
+	// NB: returns NaN for NaN values and 0 for both positive and negative zero.
+	return v
+}
+
 // NewMathOp creates a new math op based on the type.
 func NewMathOp(opType string) (parser.Params, error) {
 	if fn, ok := mathFuncs[opType]; ok {
-		lazyOpts := block.NewLazyOptions().SetValueTransform(fn)
+		lazyOpts := block.NewLazyOptions().
+			SetValueTransform(fn).
+			SetSeriesMetaTransform(removeName)
+		if opType == SgnType {
+			return sgnOp{lazyOpts: lazyOpts}, nil
+		}
+
 		return lazy.NewLazyOp(opType, lazyOpts)
 	}
 
 	return nil, fmt.Errorf("unknown math type: %s", opType)
 }
+
+// sgnOp is a math op which keeps NaN values in the results of instant queries,
+// as with Prometheus the sign of a NaN sample is NaN rather than no value.
+type sgnOp struct {
+	lazyOpts block.LazyOptions
+}
+
+// OpType for the operator
+func (o sgnOp) OpType() string {
+	return SgnType
+}
+
+// String representation
+func (o sgnOp) String() string {
+	return fmt.Sprintf("type: %s", SgnType)
+}
+
+// Node creates an execution node
+func (o sgnOp) Node(
+	controller *transform.Controller,
+	_ transform.Options,
+) transform.OpNode {
+	return &sgnNode{
+		op:         o,
+		controller: controller,
+	}
+}
+
+type sgnNode struct {
+	op         sgnOp
+	controller *transform.Controller
+}
+
+func (n *sgnNode) Params() parser.Params {
+	return n.op
+}
+
+func (n *sgnNode) Process(
+	queryCtx *models.QueryContext,
+	_ parser.NodeID,
+	b block.Block,
+) error {
+	lazyOpts := n.op.lazyOpts
+	if queryCtx.Options.Instantaneous {
+		lazyOpts = lazyOpts.SetMetaTransform(keepNaNs)
+	}
+
+	return n.controller.Process(queryCtx, block.NewLazyBlock(b, lazyOpts))
+}
+
+func keepNaNs(meta block.Metadata) block.Metadata {
+	meta.ResultMetadata.KeepNaNs = true
+	return meta
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package functions
+
+import (
+	"fmt"
+
+	"github.com/m3db/m3/src/query/block"
+	"github.com/m3db/m3/src/query/executor/transform"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/parser"
+)
+
+// StepInvariantType repeats the values of the last step of a block at every
+// step. It follows the expressions evaluated at a time set by the @
+// modifier, which are fetched so that their last step is at that time.
+const StepInvariantType = "step_invariant"
+
+// NewStepInvariantOp creates a new step invariant operation.
+func NewStepInvariantOp() parser.Params {
+	return stepInvariantOp{}
+}
+
+type stepInvariantOp struct{}
+
+// OpType for the operator.
+func (o stepInvariantOp) OpType() string {
+	return StepInvariantType
+}
+
+// String representation.
+func (o stepInvariantOp) String() string {
+	return fmt.Sprintf("type: %s", o.OpType())
+}
+
+// Node creates an execution node.
+func (o stepInvariantOp) Node(
+	controller *transform.Controller,
+	_ transform.Options,
+) transform.OpNode {
+	return &stepInvariantNode{
+		op:         o,
+		controller: controller,
+	}
+}
+
+type stepInvariantNode struct {
+	op         stepInvariantOp
+	controller *transform.Controller
+}
+
+func (n *stepInvariantNode) Params() parser.Params {
+	return n.op
+}
+
+// Process the block
+func (n *stepInvariantNode) Process(
+	queryCtx *models.QueryContext,
+	ID parser.NodeID,
+	b block.Block,
+) error {
+	return transform.ProcessSimpleBlock(n, n.controller, queryCtx, ID, b)
+}
+
+func (n *stepInvariantNode) ProcessBlock(
+	queryCtx *models.QueryContext,
+	ID parser.NodeID,
+	b block.Block,
+) (block.Block, error) {
+	stepIter, err := b.StepIter()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		seriesMetas = stepIter.SeriesMeta()
+		last        = make([]float64, len(seriesMetas))
+		steps       int
+	)
+	for ; stepIter.Next(); steps++ {
+		copy(last, stepIter.Current().Values())
+	}
+
+	if err = stepIter.Err(); err != nil {
+		return nil, err
+	}
+
+	builder, err := n.controller.BlockBuilder(queryCtx, b.Meta(), seriesMetas)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = builder.AddCols(steps); err != nil {
+		return nil, err
+	}
+
+	for index := 0; index < steps; index++ {
+		if err := builder.AppendValues(index, last); err != nil {
+			return nil, err
+		}
+	}
+
+	return builder.Build(), nil
+}
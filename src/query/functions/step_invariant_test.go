@@ -0,0 +1,61 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package functions
+
+import (
+	"math"
+	"testing"
+
+	"github.com/m3db/m3/src/query/executor/transform"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/parser"
+	"github.com/m3db/m3/src/query/test"
+	"github.com/m3db/m3/src/query/test/compare"
+	"github.com/m3db/m3/src/query/test/executor"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStepInvariant(t *testing.T) {
+	v := [][]float64{
+		{0, 1, 2, 3, 4},
+		{5, 6, 7, 8, math.NaN()},
+	}
+
+	values, bounds := test.GenerateValuesAndBounds(v, nil)
+	b := test.NewBlockFromValues(bounds, values)
+	c, sink := executor.NewControllerWithSink(parser.NodeID(rune(1)))
+	op := NewStepInvariantOp()
+	assert.Equal(t, StepInvariantType, op.OpType())
+
+	node := op.(transform.Params).Node(c, transform.Options{})
+	err := node.Process(models.NoopQueryContext(), parser.NodeID(rune(0)), b)
+	require.NoError(t, err)
+
+	nan := math.NaN()
+	expected := [][]float64{
+		{4, 4, 4, 4, 4},
+		{nan, nan, nan, nan, nan},
+	}
+	compare.EqualsWithNans(t, expected, sink.Values)
+	assert.Equal(t, bounds, sink.Meta.Bounds)
+}
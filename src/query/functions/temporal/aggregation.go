@@ -52,6 +52,21 @@ const (
 	// StdVarType calculates the standard variance of all values in the specified interval.
 	StdVarType = "stdvar_over_time"
 
+	// LastType returns the most recent value in the specified interval.
+	LastType = "last_over_time"
+
+	// PresentType returns 1 for any series with values in the specified interval.
+	PresentType = "present_over_time"
+
+	// MadType calculates the median absolute deviation of all values in the specified interval.
+	MadType = "mad_over_time"
+
+	// AbsentType returns 1 if there are no values for any series in the
+	// specified interval.
+	//
+	// NB: this is evaluated as the absent aggregation of present_over_time.
+	AbsentType = "absent_over_time"
+
 	// QuantileType calculates the φ-quantile (0 ≤ φ ≤ 1) of the values in the specified interval.
 	QuantileType = "quantile_over_time"
 )
@@ -60,13 +75,16 @@ type aggFunc func([]float64) float64
 
 var (
 	aggFuncs = map[string]aggFunc{
-		AvgType:    avgOverTime,
-		CountType:  countOverTime,
-		MinType:    minOverTime,
-		MaxType:    maxOverTime,
-		SumType:    sumOverTime,
-		StdDevType: stddevOverTime,
-		StdVarType: stdvarOverTime,
+		AvgType:     avgOverTime,
+		CountType:   countOverTime,
+		MinType:     minOverTime,
+		MaxType:     maxOverTime,
+		SumType:     sumOverTime,
+		StdDevType:  stddevOverTime,
+		StdVarType:  stdvarOverTime,
+		LastType:    lastOverTime,
+		PresentType: presentOverTime,
+		MadType:     madOverTime,
 	}
 )
 
@@ -220,6 +238,38 @@ func stdvarOverTime(values []float64) float64 {
 	return aux / count
 }
 
+func lastOverTime(values []float64) float64 {
+	if len(values) == 0 {
+		return math.NaN()
+	}
+
+	return values[len(values)-1]
+}
+
+func presentOverTime(values []float64) float64 {
+	for _, v := range values {
+		if !math.IsNaN(v) {
+			return 1
+		}
+	}
+
+	return math.NaN()
+}
+
+func madOverTime(values []float64) float64 {
+	values = removeNaNs(values)
+	if len(values) == 0 {
+		return math.NaN()
+	}
+
+	median := quantile(0.5, values)
+	for i, v := range values {
+		values[i] = math.Abs(v - median)
+	}
+
+	return quantile(0.5, values)
+}
+
 func sumAndCount(values []float64) (float64, float64) {
 	sum := 0.0
 	count := 0.0
@@ -119,6 +119,11 @@ func (c *baseNode) Process(
 
 	resultMeta := b.Meta().ResultMetadata
 	resultMeta.VerifyTemporalRange(c.op.duration)
+	if c.op.operatorType == LastType && queryCtx.Options.Instantaneous {
+		// NB: last_over_time returns the last sample as is, so NaN samples must
+		// be kept when rendering instant results, as Prometheus does.
+		resultMeta.KeepNaNs = true
+	}
 
 	meta := b.Meta()
 	bounds := meta.Bounds
@@ -133,6 +138,9 @@ func (c *baseNode) Process(
 		stepSize:    xtime.UnixNano(bounds.StepSize),
 		steps:       bounds.Steps(),
 		resultMeta:  resultMeta,
+		// NB: last_over_time acts like an offset, so it keeps the
+		// __name__ tag of the series the same as Prometheus does.
+		keepName: c.op.operatorType == LastType,
 	}
 
 	concurrency := runtime.GOMAXPROCS(0)
@@ -167,6 +175,7 @@ type blockMeta struct {
 	queryCtx    *models.QueryContext
 	steps       int
 	resultMeta  block.ResultMetadata
+	keepName    bool
 }
 
 func (c *baseNode) batchProcess(
@@ -206,9 +215,10 @@ func (c *baseNode) batchProcess(
 		loopIndex := idx
 		batch := batch
 		idx = idx + batch.Size
-		p := c.makeProcessor.initialize(c.op.duration, c.transformOpts)
+		p, tileCountP := c.initializeProcessors()
 		go func() {
-			err := parallelProcess(ctx, loopIndex, batch.Iter, builder, m, p, &mu)
+			err := parallelProcess(ctx, loopIndex, batch.Iter, builder, m, p,
+				tileCountP, &mu)
 			if err != nil {
 				mu.Lock()
 				// NB: this no-ops if the error is nil.
@@ -231,6 +241,7 @@ func parallelProcess(
 	builder block.Builder,
 	blockMeta blockMeta,
 	processor processor,
+	tileCountProcessor processor,
 	mu *sync.Mutex,
 ) error {
 	var (
@@ -269,16 +280,25 @@ func parallelProcess(
 			datapoints = series.Datapoints()
 			stats      = series.Stats()
 			seriesMeta = metas[i]
+			p          = processor
 		)
 
+		if seriesMeta.TileCounts {
+			p = tileCountProcessor
+			seriesMeta.TileCounts = false
+		}
+
 		if stats.Enabled {
 			decodeDuration += stats.DecodeDuration
 		}
 
 		// rename series to exclude their __name__ tag as
 		// part of function processing.
-		seriesMeta.Tags = seriesMeta.Tags.WithoutName()
-		seriesMeta.Name = seriesMeta.Tags.ID()
+		if !blockMeta.keepName {
+			seriesMeta.Tags = seriesMeta.Tags.WithoutName()
+			seriesMeta.Name = seriesMeta.Tags.ID()
+		}
+
 		values = values[:0]
 		for i := 0; i < blockMeta.steps; i++ {
 			iterBounds := iterationBounds{
@@ -288,10 +308,10 @@ func parallelProcess(
 
 			l, r, b := getIndices(datapoints, start, end, init)
 			if !b {
-				newVal = processor.process(ts.Datapoints{}, iterBounds)
+				newVal = p.process(ts.Datapoints{}, iterBounds)
 			} else {
 				init = l
-				newVal = processor.process(datapoints[l:r], iterBounds)
+				newVal = p.process(datapoints[l:r], iterBounds)
 			}
 
 			values = append(values, newVal)
@@ -344,8 +364,14 @@ func (c *baseNode) singleProcess(
 
 	// rename series to exclude their __name__ tag as part of function processing.
 	resultSeriesMeta := make([]block.SeriesMeta, 0, len(seriesIter.SeriesMeta()))
-	for _, m := range seriesIter.SeriesMeta() {
-		tags := m.Tags.WithoutName()
+	for _, meta := range seriesIter.SeriesMeta() {
+		if m.keepName {
+			meta.TileCounts = false
+			resultSeriesMeta = append(resultSeriesMeta, meta)
+			continue
+		}
+
+		tags := meta.Tags.WithoutName()
 		resultSeriesMeta = append(resultSeriesMeta, block.SeriesMeta{
 			Name: tags.ID(),
 			Tags: tags,
@@ -364,8 +390,11 @@ func (c *baseNode) singleProcess(
 		return nil, err
 	}
 
-	p := c.makeProcessor.initialize(c.op.duration, c.transformOpts)
-	for seriesIter.Next() {
+	var (
+		seriesMetas         = seriesIter.SeriesMeta()
+		seriesP, tileCountP = c.initializeProcessors()
+	)
+	for idx := 0; seriesIter.Next(); idx++ {
 		var (
 			newVal float64
 			init   = 0
@@ -376,8 +405,13 @@ func (c *baseNode) singleProcess(
 			series     = seriesIter.Current()
 			datapoints = series.Datapoints()
 			stats      = series.Stats()
+			p          = seriesP
 		)
 
+		if idx < len(seriesMetas) && seriesMetas[idx].TileCounts {
+			p = tileCountP
+		}
+
 		if stats.Enabled {
 			decodeDuration += stats.DecodeDuration
 		}
@@ -408,6 +442,19 @@ func (c *baseNode) singleProcess(
 	return builder, seriesIter.Err()
 }
 
+// initializeProcessors initializes the processor of the series, and the
+// processor of series fetched as the number of samples of each datapoint,
+// whose count over time is the sum over time of their values.
+func (c *baseNode) initializeProcessors() (processor, processor) {
+	p := c.makeProcessor.initialize(c.op.duration, c.transformOpts)
+	if c.op.operatorType != CountType {
+		return p, p
+	}
+
+	return p, aggProcessor{aggFunc: sumOverTime}.
+		initialize(c.op.duration, c.transformOpts)
+}
+
 // getIndices returns the index of the points on the left and the right of the
 // datapoint list given a starting index, as well as a boolean indicating if
 // the returned indices are valid.
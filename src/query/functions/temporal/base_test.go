@@ -154,6 +154,11 @@ func testTemporalFunc(t *testing.T, opGen opGenerator, tests []testCase) {
 				// NB: name should be dropped from series tags, and the name
 				// should be the updated ID.
 				expectedSeriesMetas := []block.SeriesMeta{metaOne, metaTwo}
+				if tt.opType == LastType {
+					// NB: last_over_time keeps the series as they are.
+					expectedSeriesMetas = seriesMetas
+				}
+
 				require.Equal(t, expectedSeriesMetas, sink.Metas)
 			})
 		}
@@ -349,3 +354,55 @@ func testParallelProcess(t *testing.T, warning bool) {
 
 	verifyResultMetadata(t, sink.Meta.ResultMetadata, warning)
 }
+
+func TestProcessTileCounts(t *testing.T) {
+	for _, batched := range []bool{true, false} {
+		t.Run(fmt.Sprintf("batched %v", batched), func(t *testing.T) {
+			ctrl := xtest.NewController(t)
+			defer ctrl.Finish()
+
+			c, sink := executor.NewControllerWithSink(parser.NodeID(rune(1)))
+			node := baseNode{
+				controller:    c,
+				op:            baseOp{operatorType: CountType, duration: time.Minute},
+				makeProcessor: aggProcessor{aggFunc: countOverTime},
+				transformOpts: transform.Options{},
+			}
+
+			bl := block.NewMockBlock(ctrl)
+			bl.EXPECT().Meta().Return(block.Metadata{
+				ResultMetadata: block.NewResultMetadata(),
+				Bounds: models.Bounds{
+					StepSize: time.Minute,
+					Duration: time.Minute,
+				}}).AnyTimes()
+
+			// NB: the datapoint of a series fetched as tile counts is the number
+			// of samples it represents.
+			seriesMetas := []block.SeriesMeta{
+				{Name: []byte("a"), Tags: models.MustMakeTags("tag", "a"), TileCounts: true},
+				{Name: []byte("b"), Tags: models.MustMakeTags("tag", "b")},
+			}
+			iter := &dummySeriesIter{idx: -1, vals: []float64{5, 5}, metas: seriesMetas}
+			if batched {
+				bl.EXPECT().MultiSeriesIter(gomock.Any()).Return([]block.SeriesIterBatch{
+					{Iter: iter, Size: 2},
+				}, nil)
+			} else {
+				bl.EXPECT().MultiSeriesIter(gomock.Any()).Return(nil, fmt.Errorf("unsupported"))
+				bl.EXPECT().SeriesIter().Return(iter, nil)
+			}
+			bl.EXPECT().Close()
+
+			err := node.Process(models.NoopQueryContext(), parser.NodeID(rune(0)), bl)
+			require.NoError(t, err)
+
+			require.Equal(t, 2, len(sink.Values))
+			assert.Equal(t, []float64{5}, sink.Values[0])
+			assert.Equal(t, []float64{1}, sink.Values[1])
+			for _, meta := range sink.Metas {
+				assert.False(t, meta.TileCounts)
+			}
+		})
+	}
+}
@@ -121,6 +121,33 @@ func (x Source) String() string {
 }
 func (Source) EnumDescriptor() ([]byte, []int) { return fileDescriptorTypes, []int{2} }
 
+type Histogram_ResetHint int32
+
+const (
+	Histogram_UNKNOWN Histogram_ResetHint = 0
+	Histogram_YES     Histogram_ResetHint = 1
+	Histogram_NO      Histogram_ResetHint = 2
+	Histogram_GAUGE   Histogram_ResetHint = 3
+)
+
+var Histogram_ResetHint_name = map[int32]string{
+	0: "UNKNOWN",
+	1: "YES",
+	2: "NO",
+	3: "GAUGE",
+}
+var Histogram_ResetHint_value = map[string]int32{
+	"UNKNOWN": 0,
+	"YES":     1,
+	"NO":      2,
+	"GAUGE":   3,
+}
+
+func (x Histogram_ResetHint) String() string {
+	return proto.EnumName(Histogram_ResetHint_name, int32(x))
+}
+func (Histogram_ResetHint) EnumDescriptor() ([]byte, []int) { return fileDescriptorTypes, []int{2, 0} }
+
 type LabelMatcher_Type int32
 
 const (
@@ -146,7 +173,7 @@ var LabelMatcher_Type_value = map[string]int32{
 func (x LabelMatcher_Type) String() string {
 	return proto.EnumName(LabelMatcher_Type_name, int32(x))
 }
-func (LabelMatcher_Type) EnumDescriptor() ([]byte, []int) { return fileDescriptorTypes, []int{4, 0} }
+func (LabelMatcher_Type) EnumDescriptor() ([]byte, []int) { return fileDescriptorTypes, []int{7, 0} }
 
 type Sample struct {
 	Value     float64 `protobuf:"fixed64,1,opt,name=value,proto3" json:"value,omitempty"`
@@ -172,22 +199,210 @@ func (m *Sample) GetTimestamp() int64 {
 	return 0
 }
 
+type Exemplar struct {
+	Labels    []Label `protobuf:"bytes,1,rep,name=labels" json:"labels"`
+	Value     float64 `protobuf:"fixed64,2,opt,name=value,proto3" json:"value,omitempty"`
+	Timestamp int64   `protobuf:"varint,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+}
+
+func (m *Exemplar) Reset()                    { *m = Exemplar{} }
+func (m *Exemplar) String() string            { return proto.CompactTextString(m) }
+func (*Exemplar) ProtoMessage()               {}
+func (*Exemplar) Descriptor() ([]byte, []int) { return fileDescriptorTypes, []int{1} }
+
+func (m *Exemplar) GetLabels() []Label {
+	if m != nil {
+		return m.Labels
+	}
+	return nil
+}
+
+func (m *Exemplar) GetValue() float64 {
+	if m != nil {
+		return m.Value
+	}
+	return 0
+}
+
+func (m *Exemplar) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+type Histogram struct {
+	// NB: The integer and float counts are a oneof in the Prometheus
+	// definition, only one of each pair is set by a sender.
+	CountInt       uint64              `protobuf:"varint,1,opt,name=count_int,json=countInt,proto3" json:"count_int,omitempty"`
+	CountFloat     float64             `protobuf:"fixed64,2,opt,name=count_float,json=countFloat,proto3" json:"count_float,omitempty"`
+	Sum            float64             `protobuf:"fixed64,3,opt,name=sum,proto3" json:"sum,omitempty"`
+	Schema         int32               `protobuf:"zigzag32,4,opt,name=schema,proto3" json:"schema,omitempty"`
+	ZeroThreshold  float64             `protobuf:"fixed64,5,opt,name=zero_threshold,json=zeroThreshold,proto3" json:"zero_threshold,omitempty"`
+	ZeroCountInt   uint64              `protobuf:"varint,6,opt,name=zero_count_int,json=zeroCountInt,proto3" json:"zero_count_int,omitempty"`
+	ZeroCountFloat float64             `protobuf:"fixed64,7,opt,name=zero_count_float,json=zeroCountFloat,proto3" json:"zero_count_float,omitempty"`
+	NegativeSpans  []BucketSpan        `protobuf:"bytes,8,rep,name=negative_spans,json=negativeSpans" json:"negative_spans"`
+	NegativeDeltas []int64             `protobuf:"zigzag64,9,rep,packed,name=negative_deltas,json=negativeDeltas" json:"negative_deltas,omitempty"`
+	NegativeCounts []float64           `protobuf:"fixed64,10,rep,packed,name=negative_counts,json=negativeCounts" json:"negative_counts,omitempty"`
+	PositiveSpans  []BucketSpan        `protobuf:"bytes,11,rep,name=positive_spans,json=positiveSpans" json:"positive_spans"`
+	PositiveDeltas []int64             `protobuf:"zigzag64,12,rep,packed,name=positive_deltas,json=positiveDeltas" json:"positive_deltas,omitempty"`
+	PositiveCounts []float64           `protobuf:"fixed64,13,rep,packed,name=positive_counts,json=positiveCounts" json:"positive_counts,omitempty"`
+	ResetHint      Histogram_ResetHint `protobuf:"varint,14,opt,name=reset_hint,json=resetHint,proto3,enum=m3prometheus.Histogram_ResetHint" json:"reset_hint,omitempty"`
+	Timestamp      int64               `protobuf:"varint,15,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+}
+
+func (m *Histogram) Reset()                    { *m = Histogram{} }
+func (m *Histogram) String() string            { return proto.CompactTextString(m) }
+func (*Histogram) ProtoMessage()               {}
+func (*Histogram) Descriptor() ([]byte, []int) { return fileDescriptorTypes, []int{2} }
+
+func (m *Histogram) GetCountInt() uint64 {
+	if m != nil {
+		return m.CountInt
+	}
+	return 0
+}
+
+func (m *Histogram) GetCountFloat() float64 {
+	if m != nil {
+		return m.CountFloat
+	}
+	return 0
+}
+
+func (m *Histogram) GetSum() float64 {
+	if m != nil {
+		return m.Sum
+	}
+	return 0
+}
+
+func (m *Histogram) GetSchema() int32 {
+	if m != nil {
+		return m.Schema
+	}
+	return 0
+}
+
+func (m *Histogram) GetZeroThreshold() float64 {
+	if m != nil {
+		return m.ZeroThreshold
+	}
+	return 0
+}
+
+func (m *Histogram) GetZeroCountInt() uint64 {
+	if m != nil {
+		return m.ZeroCountInt
+	}
+	return 0
+}
+
+func (m *Histogram) GetZeroCountFloat() float64 {
+	if m != nil {
+		return m.ZeroCountFloat
+	}
+	return 0
+}
+
+func (m *Histogram) GetNegativeSpans() []BucketSpan {
+	if m != nil {
+		return m.NegativeSpans
+	}
+	return nil
+}
+
+func (m *Histogram) GetNegativeDeltas() []int64 {
+	if m != nil {
+		return m.NegativeDeltas
+	}
+	return nil
+}
+
+func (m *Histogram) GetNegativeCounts() []float64 {
+	if m != nil {
+		return m.NegativeCounts
+	}
+	return nil
+}
+
+func (m *Histogram) GetPositiveSpans() []BucketSpan {
+	if m != nil {
+		return m.PositiveSpans
+	}
+	return nil
+}
+
+func (m *Histogram) GetPositiveDeltas() []int64 {
+	if m != nil {
+		return m.PositiveDeltas
+	}
+	return nil
+}
+
+func (m *Histogram) GetPositiveCounts() []float64 {
+	if m != nil {
+		return m.PositiveCounts
+	}
+	return nil
+}
+
+func (m *Histogram) GetResetHint() Histogram_ResetHint {
+	if m != nil {
+		return m.ResetHint
+	}
+	return Histogram_UNKNOWN
+}
+
+func (m *Histogram) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+type BucketSpan struct {
+	Offset int32  `protobuf:"zigzag32,1,opt,name=offset,proto3" json:"offset,omitempty"`
+	Length uint32 `protobuf:"varint,2,opt,name=length,proto3" json:"length,omitempty"`
+}
+
+func (m *BucketSpan) Reset()                    { *m = BucketSpan{} }
+func (m *BucketSpan) String() string            { return proto.CompactTextString(m) }
+func (*BucketSpan) ProtoMessage()               {}
+func (*BucketSpan) Descriptor() ([]byte, []int) { return fileDescriptorTypes, []int{3} }
+
+func (m *BucketSpan) GetOffset() int32 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+func (m *BucketSpan) GetLength() uint32 {
+	if m != nil {
+		return m.Length
+	}
+	return 0
+}
+
 type TimeSeries struct {
-	Labels  []Label    `protobuf:"bytes,1,rep,name=labels" json:"labels"`
-	Samples []Sample   `protobuf:"bytes,2,rep,name=samples" json:"samples"`
-	Type    MetricType `protobuf:"varint,3,opt,name=type,proto3,enum=m3prometheus.MetricType" json:"type,omitempty"`
-	Unit    string     `protobuf:"bytes,4,opt,name=unit,proto3" json:"unit,omitempty"`
-	Help    string     `protobuf:"bytes,5,opt,name=help,proto3" json:"help,omitempty"`
+	Labels     []Label     `protobuf:"bytes,1,rep,name=labels" json:"labels"`
+	Samples    []Sample    `protobuf:"bytes,2,rep,name=samples" json:"samples"`
+	Exemplars  []Exemplar  `protobuf:"bytes,3,rep,name=exemplars" json:"exemplars"`
+	Histograms []Histogram `protobuf:"bytes,4,rep,name=histograms" json:"histograms"`
 	// NB: These are custom fields that M3 uses. They start at 101 so that they
 	// should never clash with prometheus fields.
-	M3Type M3Type `protobuf:"varint,101,opt,name=m3_type,json=m3Type,proto3,enum=m3prometheus.M3Type" json:"m3_type,omitempty"`
-	Source Source `protobuf:"varint,102,opt,name=source,proto3,enum=m3prometheus.Source" json:"source,omitempty"`
+	M3Type M3Type     `protobuf:"varint,101,opt,name=m3_type,json=m3Type,proto3,enum=m3prometheus.M3Type" json:"m3_type,omitempty"`
+	Source Source     `protobuf:"varint,102,opt,name=source,proto3,enum=m3prometheus.Source" json:"source,omitempty"`
+	Type   MetricType `protobuf:"varint,103,opt,name=type,proto3,enum=m3prometheus.MetricType" json:"type,omitempty"`
+	Unit   string     `protobuf:"bytes,104,opt,name=unit,proto3" json:"unit,omitempty"`
+	Help   string     `protobuf:"bytes,105,opt,name=help,proto3" json:"help,omitempty"`
 }
 
 func (m *TimeSeries) Reset()                    { *m = TimeSeries{} }
 func (m *TimeSeries) String() string            { return proto.CompactTextString(m) }
 func (*TimeSeries) ProtoMessage()               {}
-func (*TimeSeries) Descriptor() ([]byte, []int) { return fileDescriptorTypes, []int{1} }
+func (*TimeSeries) Descriptor() ([]byte, []int) { return fileDescriptorTypes, []int{4} }
 
 func (m *TimeSeries) GetLabels() []Label {
 	if m != nil {
@@ -203,25 +418,18 @@ func (m *TimeSeries) GetSamples() []Sample {
 	return nil
 }
 
-func (m *TimeSeries) GetType() MetricType {
-	if m != nil {
-		return m.Type
-	}
-	return MetricType_UNKNOWN
-}
-
-func (m *TimeSeries) GetUnit() string {
+func (m *TimeSeries) GetExemplars() []Exemplar {
 	if m != nil {
-		return m.Unit
+		return m.Exemplars
 	}
-	return ""
+	return nil
 }
 
-func (m *TimeSeries) GetHelp() string {
+func (m *TimeSeries) GetHistograms() []Histogram {
 	if m != nil {
-		return m.Help
+		return m.Histograms
 	}
-	return ""
+	return nil
 }
 
 func (m *TimeSeries) GetM3Type() M3Type {
@@ -238,6 +446,27 @@ func (m *TimeSeries) GetSource() Source {
 	return Source_PROMETHEUS
 }
 
+func (m *TimeSeries) GetType() MetricType {
+	if m != nil {
+		return m.Type
+	}
+	return MetricType_UNKNOWN
+}
+
+func (m *TimeSeries) GetUnit() string {
+	if m != nil {
+		return m.Unit
+	}
+	return ""
+}
+
+func (m *TimeSeries) GetHelp() string {
+	if m != nil {
+		return m.Help
+	}
+	return ""
+}
+
 type Label struct {
 	Name  []byte `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
 	Value []byte `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
@@ -246,7 +475,7 @@ type Label struct {
 func (m *Label) Reset()                    { *m = Label{} }
 func (m *Label) String() string            { return proto.CompactTextString(m) }
 func (*Label) ProtoMessage()               {}
-func (*Label) Descriptor() ([]byte, []int) { return fileDescriptorTypes, []int{2} }
+func (*Label) Descriptor() ([]byte, []int) { return fileDescriptorTypes, []int{5} }
 
 func (m *Label) GetName() []byte {
 	if m != nil {
@@ -269,7 +498,7 @@ type Labels struct {
 func (m *Labels) Reset()                    { *m = Labels{} }
 func (m *Labels) String() string            { return proto.CompactTextString(m) }
 func (*Labels) ProtoMessage()               {}
-func (*Labels) Descriptor() ([]byte, []int) { return fileDescriptorTypes, []int{3} }
+func (*Labels) Descriptor() ([]byte, []int) { return fileDescriptorTypes, []int{6} }
 
 func (m *Labels) GetLabels() []Label {
 	if m != nil {
@@ -288,7 +517,7 @@ type LabelMatcher struct {
 func (m *LabelMatcher) Reset()                    { *m = LabelMatcher{} }
 func (m *LabelMatcher) String() string            { return proto.CompactTextString(m) }
 func (*LabelMatcher) ProtoMessage()               {}
-func (*LabelMatcher) Descriptor() ([]byte, []int) { return fileDescriptorTypes, []int{4} }
+func (*LabelMatcher) Descriptor() ([]byte, []int) { return fileDescriptorTypes, []int{7} }
 
 func (m *LabelMatcher) GetType() LabelMatcher_Type {
 	if m != nil {
@@ -311,15 +540,81 @@ func (m *LabelMatcher) GetValue() []byte {
 	return nil
 }
 
+// MetricMetadata is the metadata of a metric family sent by Prometheus
+// remote write.
+type MetricMetadata struct {
+	Type             MetricType `protobuf:"varint,1,opt,name=type,proto3,enum=m3prometheus.MetricType" json:"type,omitempty"`
+	MetricFamilyName string     `protobuf:"bytes,2,opt,name=metric_family_name,json=metricFamilyName,proto3" json:"metric_family_name,omitempty"`
+	Help             string     `protobuf:"bytes,4,opt,name=help,proto3" json:"help,omitempty"`
+	Unit             string     `protobuf:"bytes,5,opt,name=unit,proto3" json:"unit,omitempty"`
+}
+
+func (m *MetricMetadata) Reset()                    { *m = MetricMetadata{} }
+func (m *MetricMetadata) String() string            { return proto.CompactTextString(m) }
+func (*MetricMetadata) ProtoMessage()               {}
+func (*MetricMetadata) Descriptor() ([]byte, []int) { return fileDescriptorTypes, []int{8} }
+
+func (m *MetricMetadata) GetType() MetricType {
+	if m != nil {
+		return m.Type
+	}
+	return MetricType_UNKNOWN
+}
+
+func (m *MetricMetadata) GetMetricFamilyName() string {
+	if m != nil {
+		return m.MetricFamilyName
+	}
+	return ""
+}
+
+func (m *MetricMetadata) GetHelp() string {
+	if m != nil {
+		return m.Help
+	}
+	return ""
+}
+
+func (m *MetricMetadata) GetUnit() string {
+	if m != nil {
+		return m.Unit
+	}
+	return ""
+}
+
+// MetricMetadataSet is a set of metric metadata, the metadata written by
+// Prometheus remote write is stored in the cluster KV store as sets.
+type MetricMetadataSet struct {
+	Metadata []MetricMetadata `protobuf:"bytes,1,rep,name=metadata" json:"metadata"`
+}
+
+func (m *MetricMetadataSet) Reset()                    { *m = MetricMetadataSet{} }
+func (m *MetricMetadataSet) String() string            { return proto.CompactTextString(m) }
+func (*MetricMetadataSet) ProtoMessage()               {}
+func (*MetricMetadataSet) Descriptor() ([]byte, []int) { return fileDescriptorTypes, []int{9} }
+
+func (m *MetricMetadataSet) GetMetadata() []MetricMetadata {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*Sample)(nil), "m3prometheus.Sample")
+	proto.RegisterType((*Exemplar)(nil), "m3prometheus.Exemplar")
+	proto.RegisterType((*Histogram)(nil), "m3prometheus.Histogram")
+	proto.RegisterType((*BucketSpan)(nil), "m3prometheus.BucketSpan")
 	proto.RegisterType((*TimeSeries)(nil), "m3prometheus.TimeSeries")
 	proto.RegisterType((*Label)(nil), "m3prometheus.Label")
 	proto.RegisterType((*Labels)(nil), "m3prometheus.Labels")
 	proto.RegisterType((*LabelMatcher)(nil), "m3prometheus.LabelMatcher")
+	proto.RegisterType((*MetricMetadata)(nil), "m3prometheus.MetricMetadata")
+	proto.RegisterType((*MetricMetadataSet)(nil), "m3prometheus.MetricMetadataSet")
 	proto.RegisterEnum("m3prometheus.MetricType", MetricType_name, MetricType_value)
 	proto.RegisterEnum("m3prometheus.M3Type", M3Type_name, M3Type_value)
 	proto.RegisterEnum("m3prometheus.Source", Source_name, Source_value)
+	proto.RegisterEnum("m3prometheus.Histogram_ResetHint", Histogram_ResetHint_name, Histogram_ResetHint_value)
 	proto.RegisterEnum("m3prometheus.LabelMatcher_Type", LabelMatcher_Type_name, LabelMatcher_Type_value)
 }
 func (m *Sample) Marshal() (dAtA []byte, err error) {
@@ -351,7 +646,7 @@ func (m *Sample) MarshalTo(dAtA []byte) (int, error) {
 	return i, nil
 }
 
-func (m *TimeSeries) Marshal() (dAtA []byte, err error) {
+func (m *Exemplar) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalTo(dAtA)
@@ -361,7 +656,7 @@ func (m *TimeSeries) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *TimeSeries) MarshalTo(dAtA []byte) (int, error) {
+func (m *Exemplar) MarshalTo(dAtA []byte) (int, error) {
 	var i int
 	_ = i
 	var l int
@@ -378,53 +673,21 @@ func (m *TimeSeries) MarshalTo(dAtA []byte) (int, error) {
 			i += n
 		}
 	}
-	if len(m.Samples) > 0 {
-		for _, msg := range m.Samples {
-			dAtA[i] = 0x12
-			i++
-			i = encodeVarintTypes(dAtA, i, uint64(msg.Size()))
-			n, err := msg.MarshalTo(dAtA[i:])
-			if err != nil {
-				return 0, err
-			}
-			i += n
-		}
-	}
-	if m.Type != 0 {
-		dAtA[i] = 0x18
-		i++
-		i = encodeVarintTypes(dAtA, i, uint64(m.Type))
-	}
-	if len(m.Unit) > 0 {
-		dAtA[i] = 0x22
-		i++
-		i = encodeVarintTypes(dAtA, i, uint64(len(m.Unit)))
-		i += copy(dAtA[i:], m.Unit)
-	}
-	if len(m.Help) > 0 {
-		dAtA[i] = 0x2a
-		i++
-		i = encodeVarintTypes(dAtA, i, uint64(len(m.Help)))
-		i += copy(dAtA[i:], m.Help)
-	}
-	if m.M3Type != 0 {
-		dAtA[i] = 0xa8
-		i++
-		dAtA[i] = 0x6
+	if m.Value != 0 {
+		dAtA[i] = 0x11
 		i++
-		i = encodeVarintTypes(dAtA, i, uint64(m.M3Type))
+		binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.Value))))
+		i += 8
 	}
-	if m.Source != 0 {
-		dAtA[i] = 0xb0
-		i++
-		dAtA[i] = 0x6
+	if m.Timestamp != 0 {
+		dAtA[i] = 0x18
 		i++
-		i = encodeVarintTypes(dAtA, i, uint64(m.Source))
+		i = encodeVarintTypes(dAtA, i, uint64(m.Timestamp))
 	}
 	return i, nil
 }
 
-func (m *Label) Marshal() (dAtA []byte, err error) {
+func (m *Histogram) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalTo(dAtA)
@@ -434,28 +697,306 @@ func (m *Label) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *Label) MarshalTo(dAtA []byte) (int, error) {
+func (m *Histogram) MarshalTo(dAtA []byte) (int, error) {
 	var i int
 	_ = i
 	var l int
 	_ = l
-	if len(m.Name) > 0 {
-		dAtA[i] = 0xa
+	if m.CountInt != 0 {
+		dAtA[i] = 0x8
 		i++
-		i = encodeVarintTypes(dAtA, i, uint64(len(m.Name)))
-		i += copy(dAtA[i:], m.Name)
+		i = encodeVarintTypes(dAtA, i, uint64(m.CountInt))
 	}
-	if len(m.Value) > 0 {
-		dAtA[i] = 0x12
+	if m.CountFloat != 0 {
+		dAtA[i] = 0x11
 		i++
-		i = encodeVarintTypes(dAtA, i, uint64(len(m.Value)))
-		i += copy(dAtA[i:], m.Value)
+		binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.CountFloat))))
+		i += 8
 	}
-	return i, nil
-}
-
-func (m *Labels) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
+	if m.Sum != 0 {
+		dAtA[i] = 0x19
+		i++
+		binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.Sum))))
+		i += 8
+	}
+	if m.Schema != 0 {
+		dAtA[i] = 0x20
+		i++
+		i = encodeVarintTypes(dAtA, i, uint64((uint32(m.Schema)<<1)^uint32((m.Schema>>31))))
+	}
+	if m.ZeroThreshold != 0 {
+		dAtA[i] = 0x29
+		i++
+		binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.ZeroThreshold))))
+		i += 8
+	}
+	if m.ZeroCountInt != 0 {
+		dAtA[i] = 0x30
+		i++
+		i = encodeVarintTypes(dAtA, i, uint64(m.ZeroCountInt))
+	}
+	if m.ZeroCountFloat != 0 {
+		dAtA[i] = 0x39
+		i++
+		binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.ZeroCountFloat))))
+		i += 8
+	}
+	if len(m.NegativeSpans) > 0 {
+		for _, msg := range m.NegativeSpans {
+			dAtA[i] = 0x42
+			i++
+			i = encodeVarintTypes(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if len(m.NegativeDeltas) > 0 {
+		dAtA1 := make([]byte, len(m.NegativeDeltas)*10)
+		var j2 int
+		for _, num := range m.NegativeDeltas {
+			x3 := (uint64(num) << 1) ^ uint64((num >> 63))
+			for x3 >= 1<<7 {
+				dAtA1[j2] = uint8(uint64(x3)&0x7f | 0x80)
+				j2++
+				x3 >>= 7
+			}
+			dAtA1[j2] = uint8(x3)
+			j2++
+		}
+		dAtA[i] = 0x4a
+		i++
+		i = encodeVarintTypes(dAtA, i, uint64(j2))
+		i += copy(dAtA[i:], dAtA1[:j2])
+	}
+	if len(m.NegativeCounts) > 0 {
+		dAtA[i] = 0x52
+		i++
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.NegativeCounts)*8))
+		for _, num := range m.NegativeCounts {
+			f4 := math.Float64bits(float64(num))
+			binary.LittleEndian.PutUint64(dAtA[i:], uint64(f4))
+			i += 8
+		}
+	}
+	if len(m.PositiveSpans) > 0 {
+		for _, msg := range m.PositiveSpans {
+			dAtA[i] = 0x5a
+			i++
+			i = encodeVarintTypes(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if len(m.PositiveDeltas) > 0 {
+		dAtA5 := make([]byte, len(m.PositiveDeltas)*10)
+		var j6 int
+		for _, num := range m.PositiveDeltas {
+			x7 := (uint64(num) << 1) ^ uint64((num >> 63))
+			for x7 >= 1<<7 {
+				dAtA5[j6] = uint8(uint64(x7)&0x7f | 0x80)
+				j6++
+				x7 >>= 7
+			}
+			dAtA5[j6] = uint8(x7)
+			j6++
+		}
+		dAtA[i] = 0x62
+		i++
+		i = encodeVarintTypes(dAtA, i, uint64(j6))
+		i += copy(dAtA[i:], dAtA5[:j6])
+	}
+	if len(m.PositiveCounts) > 0 {
+		dAtA[i] = 0x6a
+		i++
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.PositiveCounts)*8))
+		for _, num := range m.PositiveCounts {
+			f8 := math.Float64bits(float64(num))
+			binary.LittleEndian.PutUint64(dAtA[i:], uint64(f8))
+			i += 8
+		}
+	}
+	if m.ResetHint != 0 {
+		dAtA[i] = 0x70
+		i++
+		i = encodeVarintTypes(dAtA, i, uint64(m.ResetHint))
+	}
+	if m.Timestamp != 0 {
+		dAtA[i] = 0x78
+		i++
+		i = encodeVarintTypes(dAtA, i, uint64(m.Timestamp))
+	}
+	return i, nil
+}
+
+func (m *BucketSpan) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *BucketSpan) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Offset != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintTypes(dAtA, i, uint64((uint32(m.Offset)<<1)^uint32((m.Offset>>31))))
+	}
+	if m.Length != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintTypes(dAtA, i, uint64(m.Length))
+	}
+	return i, nil
+}
+
+func (m *TimeSeries) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TimeSeries) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Labels) > 0 {
+		for _, msg := range m.Labels {
+			dAtA[i] = 0xa
+			i++
+			i = encodeVarintTypes(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if len(m.Samples) > 0 {
+		for _, msg := range m.Samples {
+			dAtA[i] = 0x12
+			i++
+			i = encodeVarintTypes(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if len(m.Exemplars) > 0 {
+		for _, msg := range m.Exemplars {
+			dAtA[i] = 0x1a
+			i++
+			i = encodeVarintTypes(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if len(m.Histograms) > 0 {
+		for _, msg := range m.Histograms {
+			dAtA[i] = 0x22
+			i++
+			i = encodeVarintTypes(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if m.M3Type != 0 {
+		dAtA[i] = 0xa8
+		i++
+		dAtA[i] = 0x6
+		i++
+		i = encodeVarintTypes(dAtA, i, uint64(m.M3Type))
+	}
+	if m.Source != 0 {
+		dAtA[i] = 0xb0
+		i++
+		dAtA[i] = 0x6
+		i++
+		i = encodeVarintTypes(dAtA, i, uint64(m.Source))
+	}
+	if m.Type != 0 {
+		dAtA[i] = 0xb8
+		i++
+		dAtA[i] = 0x6
+		i++
+		i = encodeVarintTypes(dAtA, i, uint64(m.Type))
+	}
+	if len(m.Unit) > 0 {
+		dAtA[i] = 0xc2
+		i++
+		dAtA[i] = 0x6
+		i++
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.Unit)))
+		i += copy(dAtA[i:], m.Unit)
+	}
+	if len(m.Help) > 0 {
+		dAtA[i] = 0xca
+		i++
+		dAtA[i] = 0x6
+		i++
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.Help)))
+		i += copy(dAtA[i:], m.Help)
+	}
+	return i, nil
+}
+
+func (m *Label) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Label) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Name) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.Name)))
+		i += copy(dAtA[i:], m.Name)
+	}
+	if len(m.Value) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.Value)))
+		i += copy(dAtA[i:], m.Value)
+	}
+	return i, nil
+}
+
+func (m *Labels) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalTo(dAtA)
 	if err != nil {
@@ -519,33 +1060,199 @@ func (m *LabelMatcher) MarshalTo(dAtA []byte) (int, error) {
 	return i, nil
 }
 
-func encodeVarintTypes(dAtA []byte, offset int, v uint64) int {
-	for v >= 1<<7 {
-		dAtA[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
-		offset++
+func (m *MetricMetadata) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
 	}
-	dAtA[offset] = uint8(v)
-	return offset + 1
+	return dAtA[:n], nil
 }
-func (m *Sample) Size() (n int) {
+
+func (m *MetricMetadata) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
 	var l int
 	_ = l
-	if m.Value != 0 {
-		n += 9
+	if m.Type != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintTypes(dAtA, i, uint64(m.Type))
 	}
-	if m.Timestamp != 0 {
-		n += 1 + sovTypes(uint64(m.Timestamp))
+	if len(m.MetricFamilyName) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.MetricFamilyName)))
+		i += copy(dAtA[i:], m.MetricFamilyName)
 	}
-	return n
+	if len(m.Help) > 0 {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.Help)))
+		i += copy(dAtA[i:], m.Help)
+	}
+	if len(m.Unit) > 0 {
+		dAtA[i] = 0x2a
+		i++
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.Unit)))
+		i += copy(dAtA[i:], m.Unit)
+	}
+	return i, nil
 }
 
-func (m *TimeSeries) Size() (n int) {
+func (m *MetricMetadataSet) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *MetricMetadataSet) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
 	var l int
 	_ = l
-	if len(m.Labels) > 0 {
-		for _, e := range m.Labels {
-			l = e.Size()
+	if len(m.Metadata) > 0 {
+		for _, msg := range m.Metadata {
+			dAtA[i] = 0xa
+			i++
+			i = encodeVarintTypes(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	return i, nil
+}
+
+func encodeVarintTypes(dAtA []byte, offset int, v uint64) int {
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return offset + 1
+}
+func (m *Sample) Size() (n int) {
+	var l int
+	_ = l
+	if m.Value != 0 {
+		n += 9
+	}
+	if m.Timestamp != 0 {
+		n += 1 + sovTypes(uint64(m.Timestamp))
+	}
+	return n
+}
+
+func (m *Exemplar) Size() (n int) {
+	var l int
+	_ = l
+	if len(m.Labels) > 0 {
+		for _, e := range m.Labels {
+			l = e.Size()
+			n += 1 + l + sovTypes(uint64(l))
+		}
+	}
+	if m.Value != 0 {
+		n += 9
+	}
+	if m.Timestamp != 0 {
+		n += 1 + sovTypes(uint64(m.Timestamp))
+	}
+	return n
+}
+
+func (m *Histogram) Size() (n int) {
+	var l int
+	_ = l
+	if m.CountInt != 0 {
+		n += 1 + sovTypes(uint64(m.CountInt))
+	}
+	if m.CountFloat != 0 {
+		n += 9
+	}
+	if m.Sum != 0 {
+		n += 9
+	}
+	if m.Schema != 0 {
+		n += 1 + sozTypes(uint64(m.Schema))
+	}
+	if m.ZeroThreshold != 0 {
+		n += 9
+	}
+	if m.ZeroCountInt != 0 {
+		n += 1 + sovTypes(uint64(m.ZeroCountInt))
+	}
+	if m.ZeroCountFloat != 0 {
+		n += 9
+	}
+	if len(m.NegativeSpans) > 0 {
+		for _, e := range m.NegativeSpans {
+			l = e.Size()
+			n += 1 + l + sovTypes(uint64(l))
+		}
+	}
+	if len(m.NegativeDeltas) > 0 {
+		l = 0
+		for _, e := range m.NegativeDeltas {
+			l += sozTypes(uint64(e))
+		}
+		n += 1 + sovTypes(uint64(l)) + l
+	}
+	if len(m.NegativeCounts) > 0 {
+		n += 1 + sovTypes(uint64(len(m.NegativeCounts)*8)) + len(m.NegativeCounts)*8
+	}
+	if len(m.PositiveSpans) > 0 {
+		for _, e := range m.PositiveSpans {
+			l = e.Size()
+			n += 1 + l + sovTypes(uint64(l))
+		}
+	}
+	if len(m.PositiveDeltas) > 0 {
+		l = 0
+		for _, e := range m.PositiveDeltas {
+			l += sozTypes(uint64(e))
+		}
+		n += 1 + sovTypes(uint64(l)) + l
+	}
+	if len(m.PositiveCounts) > 0 {
+		n += 1 + sovTypes(uint64(len(m.PositiveCounts)*8)) + len(m.PositiveCounts)*8
+	}
+	if m.ResetHint != 0 {
+		n += 1 + sovTypes(uint64(m.ResetHint))
+	}
+	if m.Timestamp != 0 {
+		n += 1 + sovTypes(uint64(m.Timestamp))
+	}
+	return n
+}
+
+func (m *BucketSpan) Size() (n int) {
+	var l int
+	_ = l
+	if m.Offset != 0 {
+		n += 1 + sozTypes(uint64(m.Offset))
+	}
+	if m.Length != 0 {
+		n += 1 + sovTypes(uint64(m.Length))
+	}
+	return n
+}
+
+func (m *TimeSeries) Size() (n int) {
+	var l int
+	_ = l
+	if len(m.Labels) > 0 {
+		for _, e := range m.Labels {
+			l = e.Size()
 			n += 1 + l + sovTypes(uint64(l))
 		}
 	}
@@ -555,16 +1262,17 @@ func (m *TimeSeries) Size() (n int) {
 			n += 1 + l + sovTypes(uint64(l))
 		}
 	}
-	if m.Type != 0 {
-		n += 1 + sovTypes(uint64(m.Type))
-	}
-	l = len(m.Unit)
-	if l > 0 {
-		n += 1 + l + sovTypes(uint64(l))
+	if len(m.Exemplars) > 0 {
+		for _, e := range m.Exemplars {
+			l = e.Size()
+			n += 1 + l + sovTypes(uint64(l))
+		}
 	}
-	l = len(m.Help)
-	if l > 0 {
-		n += 1 + l + sovTypes(uint64(l))
+	if len(m.Histograms) > 0 {
+		for _, e := range m.Histograms {
+			l = e.Size()
+			n += 1 + l + sovTypes(uint64(l))
+		}
 	}
 	if m.M3Type != 0 {
 		n += 2 + sovTypes(uint64(m.M3Type))
@@ -572,6 +1280,17 @@ func (m *TimeSeries) Size() (n int) {
 	if m.Source != 0 {
 		n += 2 + sovTypes(uint64(m.Source))
 	}
+	if m.Type != 0 {
+		n += 2 + sovTypes(uint64(m.Type))
+	}
+	l = len(m.Unit)
+	if l > 0 {
+		n += 2 + l + sovTypes(uint64(l))
+	}
+	l = len(m.Help)
+	if l > 0 {
+		n += 2 + l + sovTypes(uint64(l))
+	}
 	return n
 }
 
@@ -598,40 +1317,737 @@ func (m *Labels) Size() (n int) {
 			n += 1 + l + sovTypes(uint64(l))
 		}
 	}
-	return n
-}
-
-func (m *LabelMatcher) Size() (n int) {
-	var l int
-	_ = l
-	if m.Type != 0 {
-		n += 1 + sovTypes(uint64(m.Type))
-	}
-	l = len(m.Name)
-	if l > 0 {
-		n += 1 + l + sovTypes(uint64(l))
-	}
-	l = len(m.Value)
-	if l > 0 {
-		n += 1 + l + sovTypes(uint64(l))
-	}
-	return n
-}
+	return n
+}
+
+func (m *LabelMatcher) Size() (n int) {
+	var l int
+	_ = l
+	if m.Type != 0 {
+		n += 1 + sovTypes(uint64(m.Type))
+	}
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	l = len(m.Value)
+	if l > 0 {
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	return n
+}
+
+func (m *MetricMetadata) Size() (n int) {
+	var l int
+	_ = l
+	if m.Type != 0 {
+		n += 1 + sovTypes(uint64(m.Type))
+	}
+	l = len(m.MetricFamilyName)
+	if l > 0 {
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	l = len(m.Help)
+	if l > 0 {
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	l = len(m.Unit)
+	if l > 0 {
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	return n
+}
+
+func (m *MetricMetadataSet) Size() (n int) {
+	var l int
+	_ = l
+	if len(m.Metadata) > 0 {
+		for _, e := range m.Metadata {
+			l = e.Size()
+			n += 1 + l + sovTypes(uint64(l))
+		}
+	}
+	return n
+}
+
+func sovTypes(x uint64) (n int) {
+	for {
+		n++
+		x >>= 7
+		if x == 0 {
+			break
+		}
+	}
+	return n
+}
+func sozTypes(x uint64) (n int) {
+	return sovTypes(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *Sample) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTypes
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Sample: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Sample: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.Value = float64(math.Float64frombits(v))
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Timestamp", wireType)
+			}
+			m.Timestamp = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Timestamp |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTypes(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Exemplar) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTypes
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Exemplar: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Exemplar: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Labels", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Labels = append(m.Labels, Label{})
+			if err := m.Labels[len(m.Labels)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.Value = float64(math.Float64frombits(v))
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Timestamp", wireType)
+			}
+			m.Timestamp = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Timestamp |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTypes(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Histogram) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTypes
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Histogram: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Histogram: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CountInt", wireType)
+			}
+			m.CountInt = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.CountInt |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CountFloat", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.CountFloat = float64(math.Float64frombits(v))
+		case 3:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Sum", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.Sum = float64(math.Float64frombits(v))
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Schema", wireType)
+			}
+			var v int32
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			v = int32((uint32(v) >> 1) ^ uint32(((v&1)<<31)>>31))
+			m.Schema = v
+		case 5:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ZeroThreshold", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.ZeroThreshold = float64(math.Float64frombits(v))
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ZeroCountInt", wireType)
+			}
+			m.ZeroCountInt = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ZeroCountInt |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ZeroCountFloat", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.ZeroCountFloat = float64(math.Float64frombits(v))
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NegativeSpans", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.NegativeSpans = append(m.NegativeSpans, BucketSpan{})
+			if err := m.NegativeSpans[len(m.NegativeSpans)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 9:
+			if wireType == 0 {
+				var v uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowTypes
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					v |= (uint64(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				v = (v >> 1) ^ uint64((int64(v&1)<<63)>>63)
+				m.NegativeDeltas = append(m.NegativeDeltas, int64(v))
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowTypes
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= (int(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthTypes
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				for iNdEx < postIndex {
+					var v uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowTypes
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						v |= (uint64(b) & 0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					v = (v >> 1) ^ uint64((int64(v&1)<<63)>>63)
+					m.NegativeDeltas = append(m.NegativeDeltas, int64(v))
+				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field NegativeDeltas", wireType)
+			}
+		case 10:
+			if wireType == 1 {
+				var v uint64
+				if (iNdEx + 8) > l {
+					return io.ErrUnexpectedEOF
+				}
+				v = uint64(binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+				iNdEx += 8
+				v2 := float64(math.Float64frombits(v))
+				m.NegativeCounts = append(m.NegativeCounts, v2)
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowTypes
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= (int(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthTypes
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				for iNdEx < postIndex {
+					var v uint64
+					if (iNdEx + 8) > l {
+						return io.ErrUnexpectedEOF
+					}
+					v = uint64(binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+					iNdEx += 8
+					v2 := float64(math.Float64frombits(v))
+					m.NegativeCounts = append(m.NegativeCounts, v2)
+				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field NegativeCounts", wireType)
+			}
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PositiveSpans", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.PositiveSpans = append(m.PositiveSpans, BucketSpan{})
+			if err := m.PositiveSpans[len(m.PositiveSpans)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 12:
+			if wireType == 0 {
+				var v uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowTypes
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					v |= (uint64(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				v = (v >> 1) ^ uint64((int64(v&1)<<63)>>63)
+				m.PositiveDeltas = append(m.PositiveDeltas, int64(v))
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowTypes
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= (int(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthTypes
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				for iNdEx < postIndex {
+					var v uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowTypes
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						v |= (uint64(b) & 0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					v = (v >> 1) ^ uint64((int64(v&1)<<63)>>63)
+					m.PositiveDeltas = append(m.PositiveDeltas, int64(v))
+				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field PositiveDeltas", wireType)
+			}
+		case 13:
+			if wireType == 1 {
+				var v uint64
+				if (iNdEx + 8) > l {
+					return io.ErrUnexpectedEOF
+				}
+				v = uint64(binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+				iNdEx += 8
+				v2 := float64(math.Float64frombits(v))
+				m.PositiveCounts = append(m.PositiveCounts, v2)
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowTypes
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= (int(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthTypes
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				for iNdEx < postIndex {
+					var v uint64
+					if (iNdEx + 8) > l {
+						return io.ErrUnexpectedEOF
+					}
+					v = uint64(binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+					iNdEx += 8
+					v2 := float64(math.Float64frombits(v))
+					m.PositiveCounts = append(m.PositiveCounts, v2)
+				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field PositiveCounts", wireType)
+			}
+		case 14:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ResetHint", wireType)
+			}
+			m.ResetHint = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ResetHint |= (Histogram_ResetHint(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 15:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Timestamp", wireType)
+			}
+			m.Timestamp = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Timestamp |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTypes(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
 
-func sovTypes(x uint64) (n int) {
-	for {
-		n++
-		x >>= 7
-		if x == 0 {
-			break
-		}
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
 	}
-	return n
-}
-func sozTypes(x uint64) (n int) {
-	return sovTypes(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+	return nil
 }
-func (m *Sample) Unmarshal(dAtA []byte) error {
+func (m *BucketSpan) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -654,28 +2070,38 @@ func (m *Sample) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Sample: wiretype end group for non-group")
+			return fmt.Errorf("proto: BucketSpan: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Sample: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: BucketSpan: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 1 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Offset", wireType)
 			}
-			var v uint64
-			if (iNdEx + 8) > l {
-				return io.ErrUnexpectedEOF
+			var v int32
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			v = uint64(binary.LittleEndian.Uint64(dAtA[iNdEx:]))
-			iNdEx += 8
-			m.Value = float64(math.Float64frombits(v))
+			v = int32((uint32(v) >> 1) ^ uint32(((v&1)<<31)>>31))
+			m.Offset = v
 		case 2:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Timestamp", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Length", wireType)
 			}
-			m.Timestamp = 0
+			m.Length = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTypes
@@ -685,7 +2111,7 @@ func (m *Sample) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Timestamp |= (int64(b) & 0x7F) << shift
+				m.Length |= (uint32(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -803,10 +2229,10 @@ func (m *TimeSeries) Unmarshal(dAtA []byte) error {
 			}
 			iNdEx = postIndex
 		case 3:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Exemplars", wireType)
 			}
-			m.Type = 0
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTypes
@@ -816,16 +2242,28 @@ func (m *TimeSeries) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Type |= (MetricType(b) & 0x7F) << shift
+				msglen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			if msglen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Exemplars = append(m.Exemplars, Exemplar{})
+			if err := m.Exemplars[len(m.Exemplars)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		case 4:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Unit", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Histograms", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTypes
@@ -835,26 +2273,28 @@ func (m *TimeSeries) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= (uint64(b) & 0x7F) << shift
+				msglen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthTypes
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Unit = string(dAtA[iNdEx:postIndex])
+			m.Histograms = append(m.Histograms, Histogram{})
+			if err := m.Histograms[len(m.Histograms)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
-		case 5:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Help", wireType)
+		case 101:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field M3Type", wireType)
 			}
-			var stringLen uint64
+			m.M3Type = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTypes
@@ -864,26 +2304,35 @@ func (m *TimeSeries) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= (uint64(b) & 0x7F) << shift
+				m.M3Type |= (M3Type(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthTypes
+		case 102:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Source", wireType)
 			}
-			postIndex := iNdEx + intStringLen
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
+			m.Source = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Source |= (Source(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			m.Help = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 101:
+		case 103:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field M3Type", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
 			}
-			m.M3Type = 0
+			m.Type = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTypes
@@ -893,16 +2342,45 @@ func (m *TimeSeries) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.M3Type |= (M3Type(b) & 0x7F) << shift
+				m.Type |= (MetricType(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 104:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Unit", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 102:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Source", wireType)
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTypes
 			}
-			m.Source = 0
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Unit = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 105:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Help", wireType)
+			}
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTypes
@@ -912,11 +2390,21 @@ func (m *TimeSeries) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Source |= (Source(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Help = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTypes(dAtA[iNdEx:])
@@ -1262,6 +2750,243 @@ func (m *LabelMatcher) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *MetricMetadata) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTypes
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: MetricMetadata: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: MetricMetadata: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
+			}
+			m.Type = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Type |= (MetricType(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MetricFamilyName", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.MetricFamilyName = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Help", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Help = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Unit", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Unit = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTypes(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *MetricMetadataSet) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTypes
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: MetricMetadataSet: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: MetricMetadataSet: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Metadata", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Metadata = append(m.Metadata, MetricMetadata{})
+			if err := m.Metadata[len(m.Metadata)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTypes(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipTypes(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
@@ -1372,42 +3097,70 @@ func init() {
 }
 
 var fileDescriptorTypes = []byte{
-	// 590 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x53, 0xcb, 0x6e, 0xd3, 0x40,
-	0x14, 0xcd, 0xd8, 0x8e, 0xd3, 0xdc, 0x86, 0x32, 0x9a, 0x76, 0x61, 0x21, 0x94, 0x46, 0x59, 0xa0,
-	0xa8, 0x6a, 0x63, 0xb5, 0xee, 0x0e, 0x24, 0x94, 0x22, 0x93, 0x46, 0xd4, 0x4e, 0x3b, 0x76, 0x84,
-	0x60, 0x13, 0xd9, 0xe9, 0x34, 0xb1, 0x94, 0xa9, 0x8d, 0x1f, 0x48, 0xe5, 0x2b, 0xd8, 0xf1, 0x0b,
-	0x7c, 0x4a, 0x97, 0x7c, 0x01, 0x42, 0xe5, 0x47, 0xd0, 0xcc, 0x04, 0xa5, 0x45, 0xdd, 0xb0, 0x49,
-	0xe6, 0x9e, 0x7b, 0xce, 0xbd, 0xc7, 0x67, 0x34, 0xf0, 0x7a, 0x9e, 0x94, 0x8b, 0x2a, 0xee, 0xcf,
-	0x52, 0x6e, 0x73, 0xe7, 0x32, 0xb6, 0xb9, 0x63, 0x17, 0xf9, 0xcc, 0xfe, 0x54, 0xb1, 0xfc, 0xc6,
-	0x9e, 0xb3, 0x6b, 0x96, 0x47, 0x25, 0xbb, 0xb4, 0xb3, 0x3c, 0x2d, 0x53, 0xf1, 0xcb, 0xb3, 0xd8,
-	0x2e, 0x6f, 0x32, 0x56, 0xf4, 0x25, 0x44, 0x5a, 0xdc, 0x11, 0x28, 0x2b, 0x17, 0xac, 0x2a, 0x9e,
-	0x1d, 0xdc, 0x1b, 0x37, 0x4f, 0xe7, 0xa9, 0xd2, 0xc5, 0xd5, 0x95, 0xac, 0xd4, 0x10, 0x71, 0x52,
-	0xe2, 0xee, 0x2b, 0x30, 0x83, 0x88, 0x67, 0x4b, 0x46, 0x76, 0xa0, 0xfe, 0x39, 0x5a, 0x56, 0xcc,
-	0x42, 0x1d, 0xd4, 0x43, 0x54, 0x15, 0xe4, 0x39, 0x34, 0xcb, 0x84, 0xb3, 0xa2, 0x8c, 0x78, 0x66,
-	0x69, 0x1d, 0xd4, 0xd3, 0xe9, 0x1a, 0xe8, 0x7e, 0xd7, 0x00, 0xc2, 0x84, 0xb3, 0x80, 0xe5, 0x09,
-	0x2b, 0xc8, 0x21, 0x98, 0xcb, 0x28, 0x66, 0xcb, 0xc2, 0x42, 0x1d, 0xbd, 0xb7, 0x79, 0xb4, 0xdd,
-	0xbf, 0x6f, 0xad, 0x7f, 0x26, 0x7a, 0x27, 0xc6, 0xed, 0xcf, 0xdd, 0x1a, 0x5d, 0x11, 0xc9, 0x31,
-	0x34, 0x0a, 0xb9, 0xbf, 0xb0, 0x34, 0xa9, 0xd9, 0x79, 0xa8, 0x51, 0xe6, 0x56, 0xa2, 0xbf, 0x54,
-	0xb2, 0x0f, 0x86, 0x48, 0xc0, 0xd2, 0x3b, 0xa8, 0xb7, 0x75, 0x64, 0x3d, 0x94, 0x78, 0xac, 0xcc,
-	0x93, 0x59, 0x78, 0x93, 0x31, 0x2a, 0x59, 0x84, 0x80, 0x51, 0x5d, 0x27, 0xa5, 0x65, 0x74, 0x50,
-	0xaf, 0x49, 0xe5, 0x59, 0x60, 0x0b, 0xb6, 0xcc, 0xac, 0xba, 0xc2, 0xc4, 0x99, 0x1c, 0x40, 0x83,
-	0x3b, 0x53, 0x39, 0x98, 0xc9, 0xc1, 0xff, 0x78, 0xf1, 0x1c, 0x39, 0xd4, 0xe4, 0xf2, 0x9f, 0xec,
-	0x83, 0x59, 0xa4, 0x55, 0x3e, 0x63, 0xd6, 0xd5, 0x63, 0xec, 0x40, 0xf6, 0xe8, 0x8a, 0xd3, 0x3d,
-	0x84, 0xba, 0xfc, 0x7e, 0xb1, 0xf9, 0x3a, 0xe2, 0x2a, 0xe6, 0x16, 0x95, 0xe7, 0x75, 0xf6, 0x9a,
-	0x04, 0x55, 0xd1, 0x7d, 0x09, 0xe6, 0x99, 0x4a, 0xe9, 0xff, 0x83, 0xed, 0x7e, 0x43, 0xd0, 0x92,
-	0xb8, 0x17, 0x95, 0xb3, 0x05, 0xcb, 0x89, 0xb3, 0xca, 0x0c, 0x49, 0xb3, 0xbb, 0x8f, 0x4c, 0x58,
-	0x31, 0xfb, 0x0f, 0xa3, 0x93, 0x66, 0xb5, 0xc7, 0xcc, 0xea, 0xf7, 0xcd, 0xf6, 0xc0, 0x90, 0xa9,
-	0x98, 0xa0, 0xb9, 0x17, 0xb8, 0x46, 0x1a, 0xa0, 0xfb, 0xee, 0x05, 0x46, 0x02, 0xa0, 0x2e, 0xd6,
-	0x24, 0x40, 0x5d, 0xac, 0xef, 0x7d, 0x01, 0x58, 0x5f, 0x11, 0xd9, 0x84, 0xc6, 0xc4, 0x7f, 0xe7,
-	0x8f, 0xdf, 0xfb, 0xb8, 0x26, 0x8a, 0x37, 0xe3, 0x89, 0x1f, 0xba, 0x14, 0x23, 0xd2, 0x84, 0xfa,
-	0x70, 0x30, 0x19, 0x0a, 0xed, 0x13, 0x68, 0x9e, 0x8e, 0x82, 0x70, 0x3c, 0xa4, 0x03, 0x0f, 0xeb,
-	0x64, 0x1b, 0x9e, 0xca, 0xce, 0x74, 0x0d, 0x1a, 0x42, 0x1b, 0x4c, 0x3c, 0x6f, 0x40, 0x3f, 0xe0,
-	0x3a, 0xd9, 0x00, 0x63, 0xe4, 0xbf, 0x1d, 0x63, 0x93, 0xb4, 0x60, 0x23, 0x08, 0x07, 0xa1, 0x1b,
-	0xb8, 0x21, 0x6e, 0xec, 0x1d, 0x83, 0xa9, 0x6e, 0x51, 0xe0, 0x9e, 0x33, 0x55, 0x0b, 0x6a, 0x64,
-	0x0b, 0xc0, 0x73, 0xa6, 0xeb, 0xdd, 0xaa, 0x1b, 0x8e, 0x3c, 0x97, 0x62, 0x6d, 0xef, 0x05, 0x98,
-	0xea, 0x36, 0x05, 0xef, 0x9c, 0x8e, 0x3d, 0x37, 0x3c, 0x75, 0x27, 0x01, 0xae, 0x09, 0xde, 0x90,
-	0x0e, 0xce, 0x4f, 0x47, 0xa1, 0x8b, 0xd1, 0x89, 0x75, 0x7b, 0xd7, 0x46, 0x3f, 0xee, 0xda, 0xe8,
-	0xd7, 0x5d, 0x1b, 0x7d, 0xfd, 0xdd, 0xae, 0x7d, 0x34, 0xd5, 0x7b, 0x8d, 0x4d, 0xf9, 0xda, 0x9c,
-	0x3f, 0x01, 0x00, 0x00, 0xff, 0xff, 0xcf, 0x9f, 0x2c, 0x6d, 0xed, 0x03, 0x00, 0x00,
+	// 1039 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x56, 0x5b, 0x6e, 0xdb, 0x46,
+	0x14, 0x35, 0x45, 0x89, 0x92, 0xae, 0x65, 0x85, 0x99, 0x04, 0x29, 0xd1, 0x06, 0x8e, 0x2a, 0xf4,
+	0x21, 0x18, 0x8e, 0x84, 0x44, 0xfe, 0x6a, 0xd3, 0x87, 0x9d, 0xd2, 0x0f, 0x34, 0x94, 0x92, 0x21,
+	0x8d, 0x22, 0xfd, 0x11, 0x28, 0x79, 0x24, 0x12, 0xe5, 0x2b, 0x9c, 0x51, 0x50, 0x67, 0x15, 0xfd,
+	0x28, 0xd0, 0x05, 0x74, 0x07, 0x5d, 0x45, 0x3e, 0xbb, 0x82, 0xa2, 0x70, 0x37, 0x52, 0xcc, 0x0c,
+	0x1f, 0xa2, 0xe1, 0xa0, 0x8f, 0x1f, 0x7b, 0xe6, 0xdc, 0x73, 0xee, 0x1c, 0xde, 0x7b, 0x67, 0x20,
+	0xf8, 0x6a, 0xe5, 0x33, 0x6f, 0x3d, 0x1f, 0x2e, 0xe2, 0x70, 0x14, 0x8e, 0x2f, 0xe6, 0xa3, 0x70,
+	0x3c, 0xa2, 0xe9, 0x62, 0xf4, 0x6a, 0x4d, 0xd2, 0xcb, 0xd1, 0x8a, 0x44, 0x24, 0x75, 0x19, 0xb9,
+	0x18, 0x25, 0x69, 0xcc, 0x62, 0xfe, 0x37, 0x4c, 0xe6, 0x23, 0x76, 0x99, 0x10, 0x3a, 0x14, 0x10,
+	0xea, 0x84, 0x63, 0x8e, 0x12, 0xe6, 0x91, 0x35, 0x7d, 0xff, 0xe1, 0x46, 0xba, 0x55, 0xbc, 0x8a,
+	0xa5, 0x6e, 0xbe, 0x5e, 0x8a, 0x9d, 0x4c, 0xc2, 0x57, 0x52, 0xdc, 0x7f, 0x02, 0x9a, 0xed, 0x86,
+	0x49, 0x40, 0xd0, 0x5d, 0x68, 0xbc, 0x76, 0x83, 0x35, 0x31, 0x94, 0x9e, 0x32, 0x50, 0xb0, 0xdc,
+	0xa0, 0xfb, 0xd0, 0x66, 0x7e, 0x48, 0x28, 0x73, 0xc3, 0xc4, 0xa8, 0xf5, 0x94, 0x81, 0x8a, 0x4b,
+	0xa0, 0xff, 0x0a, 0x5a, 0xe6, 0x8f, 0x24, 0x4c, 0x02, 0x37, 0x45, 0x8f, 0x40, 0x0b, 0xdc, 0x39,
+	0x09, 0xa8, 0xa1, 0xf4, 0xd4, 0xc1, 0xf6, 0xe3, 0x3b, 0xc3, 0x4d, 0x5f, 0xc3, 0x67, 0x3c, 0x76,
+	0x54, 0x7f, 0xfb, 0xc7, 0x83, 0x2d, 0x9c, 0x11, 0xcb, 0x23, 0x6b, 0xef, 0x3c, 0x52, 0xbd, 0x7e,
+	0xe4, 0x6f, 0x0d, 0x68, 0x9f, 0xfa, 0x94, 0xc5, 0xab, 0xd4, 0x0d, 0xd1, 0x07, 0xd0, 0x5e, 0xc4,
+	0xeb, 0x88, 0xcd, 0xfc, 0x88, 0x09, 0xe3, 0x75, 0xdc, 0x12, 0xc0, 0x59, 0xc4, 0xd0, 0x03, 0xd8,
+	0x96, 0xc1, 0x65, 0x10, 0xbb, 0x2c, 0x3b, 0x04, 0x04, 0x74, 0xcc, 0x11, 0xa4, 0x83, 0x4a, 0xd7,
+	0xa1, 0x38, 0x43, 0xc1, 0x7c, 0x89, 0xee, 0x81, 0x46, 0x17, 0x1e, 0x09, 0x5d, 0xa3, 0xde, 0x53,
+	0x06, 0xb7, 0x71, 0xb6, 0x43, 0x1f, 0x43, 0xf7, 0x0d, 0x49, 0xe3, 0x19, 0xf3, 0x52, 0x42, 0xbd,
+	0x38, 0xb8, 0x30, 0x1a, 0x42, 0xb4, 0xc3, 0x51, 0x27, 0x07, 0xd1, 0x47, 0x19, 0xad, 0xf4, 0xa4,
+	0x09, 0x4f, 0x1d, 0x8e, 0x3e, 0xcd, 0x7d, 0x0d, 0x40, 0xdf, 0x60, 0x49, 0x73, 0x4d, 0x91, 0xae,
+	0x5b, 0xf0, 0xa4, 0x41, 0x13, 0xba, 0x11, 0x59, 0xb9, 0xcc, 0x7f, 0x4d, 0x66, 0x34, 0x71, 0x23,
+	0x6a, 0xb4, 0x44, 0x6d, 0x8d, 0x6a, 0x6d, 0x8f, 0xd6, 0x8b, 0x1f, 0x08, 0xb3, 0x13, 0x37, 0xca,
+	0x0a, 0xbc, 0x93, 0xab, 0x38, 0x46, 0xd1, 0xa7, 0x70, 0xab, 0x48, 0x73, 0x41, 0x02, 0xe6, 0x52,
+	0xa3, 0xdd, 0x53, 0x07, 0x08, 0x17, 0xd9, 0xbf, 0x11, 0x68, 0x85, 0x28, 0xdc, 0x51, 0x03, 0x7a,
+	0x2a, 0x37, 0x96, 0xc3, 0xc2, 0x1c, 0xe5, 0xc6, 0x92, 0x98, 0xfa, 0x1b, 0xc6, 0xb6, 0xff, 0x9d,
+	0xb1, 0x5c, 0x55, 0x18, 0x2b, 0xd2, 0x64, 0xc6, 0x3a, 0xd2, 0x58, 0x0e, 0x97, 0xc6, 0x0a, 0x62,
+	0x66, 0x6c, 0x47, 0x1a, 0xcb, 0xe1, 0xcc, 0xd8, 0xd7, 0x00, 0x29, 0xa1, 0x84, 0xcd, 0x3c, 0x5e,
+	0xfd, 0x6e, 0x4f, 0x19, 0x74, 0x1f, 0x7f, 0x58, 0x35, 0x55, 0x4c, 0xcf, 0x10, 0x73, 0xe6, 0xa9,
+	0x1f, 0x31, 0xdc, 0x4e, 0xf3, 0x65, 0x75, 0xfc, 0x6e, 0x5d, 0x1f, 0xbf, 0x03, 0x68, 0x17, 0x2a,
+	0xb4, 0x0d, 0xcd, 0xf3, 0xc9, 0xb7, 0x93, 0xe9, 0x77, 0x13, 0x7d, 0x0b, 0x35, 0x41, 0x7d, 0x69,
+	0xda, 0xba, 0x82, 0x34, 0xa8, 0x4d, 0xa6, 0x7a, 0x0d, 0xb5, 0xa1, 0x71, 0x72, 0x78, 0x7e, 0x62,
+	0xea, 0x6a, 0xff, 0x09, 0x40, 0x59, 0x0a, 0x3e, 0x64, 0xf1, 0x72, 0x49, 0x89, 0x9c, 0xd8, 0xdb,
+	0x38, 0xdb, 0x71, 0x3c, 0x20, 0xd1, 0x8a, 0x79, 0x62, 0x54, 0x77, 0x70, 0xb6, 0xeb, 0xff, 0xaa,
+	0x02, 0x38, 0x7e, 0x48, 0x6c, 0x92, 0xfa, 0x84, 0xfe, 0x9f, 0x8b, 0x76, 0x00, 0x4d, 0x2a, 0x6e,
+	0x39, 0x35, 0x6a, 0x42, 0x73, 0xb7, 0xaa, 0x91, 0x4f, 0x40, 0x26, 0xca, 0xa9, 0xe8, 0x33, 0x68,
+	0x93, 0xec, 0x76, 0x53, 0x43, 0x15, 0xba, 0x7b, 0x55, 0x5d, 0x7e, 0xf9, 0x33, 0x65, 0x49, 0x47,
+	0x5f, 0x00, 0x78, 0x79, 0x9d, 0xa9, 0x51, 0x17, 0xe2, 0xf7, 0xde, 0xd1, 0x87, 0x4c, 0xbd, 0x21,
+	0x40, 0x0f, 0xa1, 0x19, 0x8e, 0x67, 0xfc, 0x95, 0x33, 0x88, 0xe8, 0xe1, 0x35, 0xc3, 0xd6, 0xd8,
+	0xb9, 0x4c, 0x08, 0xd6, 0x42, 0xf1, 0x1f, 0xed, 0x83, 0x46, 0xe3, 0x75, 0xba, 0x20, 0xc6, 0xf2,
+	0x26, 0xb6, 0x2d, 0x62, 0x38, 0xe3, 0xa0, 0x7d, 0xa8, 0x8b, 0xcc, 0x2b, 0xc1, 0xbd, 0x36, 0xb2,
+	0x16, 0x61, 0xa9, 0xbf, 0x10, 0xd9, 0x05, 0x0b, 0x21, 0xa8, 0xaf, 0x23, 0x9f, 0x19, 0x5e, 0x4f,
+	0x19, 0xb4, 0xb1, 0x58, 0x73, 0xcc, 0x23, 0x41, 0x62, 0xf8, 0x12, 0xe3, 0xeb, 0xfe, 0x23, 0x68,
+	0x88, 0xd2, 0xf3, 0x60, 0xe4, 0x86, 0xf2, 0x1d, 0xed, 0x60, 0xb1, 0xae, 0xbe, 0x74, 0x9d, 0xec,
+	0xa5, 0xeb, 0x7f, 0x0e, 0xda, 0x33, 0xd9, 0xa0, 0xff, 0xde, 0xd3, 0xfe, 0x2f, 0x0a, 0x74, 0x04,
+	0x6e, 0xb9, 0x6c, 0xe1, 0x91, 0x14, 0x8d, 0xb3, 0xcf, 0x52, 0xc4, 0x67, 0x3d, 0xb8, 0x21, 0x43,
+	0xc6, 0x1c, 0x56, 0xbf, 0x4e, 0x98, 0xad, 0xdd, 0x64, 0x56, 0xdd, 0x34, 0x3b, 0x80, 0xba, 0xa8,
+	0xb5, 0x06, 0x35, 0xf3, 0x85, 0x9c, 0xf7, 0x89, 0xf9, 0x42, 0xce, 0x3b, 0x36, 0xf5, 0x9a, 0x00,
+	0x30, 0x9f, 0xf6, 0x9f, 0x15, 0xe8, 0xca, 0x32, 0x5a, 0x84, 0xb9, 0x17, 0x2e, 0x73, 0xd1, 0x7e,
+	0xc5, 0xdb, 0x3f, 0x95, 0x7c, 0x1f, 0x50, 0x28, 0xb0, 0xd9, 0xd2, 0x0d, 0xfd, 0xe0, 0x72, 0x56,
+	0x58, 0x6c, 0x63, 0x5d, 0x46, 0x8e, 0x45, 0x60, 0xc2, 0xed, 0xe6, 0xcd, 0xa8, 0x97, 0xcd, 0x28,
+	0x9a, 0xd6, 0x28, 0x9b, 0xd6, 0xb7, 0xe1, 0x76, 0xd5, 0x95, 0x4d, 0x18, 0xfa, 0x12, 0x5a, 0x61,
+	0xb6, 0xcd, 0x4a, 0x7f, 0xff, 0x26, 0x73, 0xb9, 0x24, 0xeb, 0x41, 0xa1, 0xd9, 0x7b, 0x03, 0x50,
+	0xda, 0xaf, 0x3e, 0x08, 0xdb, 0xd0, 0x7c, 0x3a, 0x3d, 0x9f, 0x38, 0x26, 0xd6, 0x95, 0xf2, 0x31,
+	0xa8, 0xa1, 0x1d, 0x68, 0x9f, 0x9e, 0xd9, 0xce, 0xf4, 0x04, 0x1f, 0x5a, 0xba, 0x8a, 0xee, 0xc0,
+	0x2d, 0x11, 0x99, 0x95, 0x60, 0x9d, 0x6b, 0xed, 0x73, 0xcb, 0x3a, 0xc4, 0x2f, 0xf5, 0x06, 0x6a,
+	0x41, 0xfd, 0x6c, 0x72, 0x3c, 0xd5, 0x35, 0xd4, 0x81, 0x96, 0xed, 0x1c, 0x3a, 0xa6, 0x6d, 0x3a,
+	0x7a, 0x73, 0xef, 0x00, 0x34, 0x79, 0x0f, 0x38, 0x6e, 0x8d, 0x67, 0xf2, 0x80, 0x2d, 0xd4, 0x05,
+	0xb0, 0xc6, 0xb3, 0xf2, 0x6c, 0x19, 0x75, 0xce, 0x2c, 0x13, 0xeb, 0xb5, 0xbd, 0x4f, 0x40, 0x93,
+	0xf7, 0x81, 0xf3, 0x9e, 0xe3, 0xa9, 0x65, 0x3a, 0xa7, 0xe6, 0xb9, 0xad, 0x6f, 0x71, 0xde, 0x09,
+	0x3e, 0x7c, 0x7e, 0x7a, 0xe6, 0x98, 0xba, 0x72, 0x64, 0xbc, 0xbd, 0xda, 0x55, 0x7e, 0xbf, 0xda,
+	0x55, 0xfe, 0xbc, 0xda, 0x55, 0x7e, 0xfa, 0x6b, 0x77, 0xeb, 0x7b, 0x4d, 0xfe, 0xf8, 0x98, 0x6b,
+	0xe2, 0xa7, 0xc3, 0xf8, 0xef, 0x01, 0x00, 0xfa, 0xcd, 0x11, 0xb4, 0xba, 0x08, 0x00, 0x00,
 }
@@ -0,0 +1,253 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package graphite
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	// SeriesByTagFunction is the name of the function used to query tagged
+	// series, a query of the form seriesByTag('tag=value', ...) is executed
+	// as a tag query rather than a path query.
+	SeriesByTagFunction = "seriesByTag"
+
+	// NameTag is the tag that holds the path of a tagged series.
+	NameTag = "name"
+
+	// TaggedSeriesSeparator separates the path and the tags of the name of
+	// a tagged series, i.e. path;tag1=value1;tag2=value2.
+	TaggedSeriesSeparator = ";"
+)
+
+var (
+	errNoTagExpressions   = errors.New("seriesByTag requires at least one tag expression")
+	errNoNonEmptyMatching = errors.New("seriesByTag requires at least one tag " +
+		"expression that does not match empty values")
+)
+
+// TagMatchType is the type of a tag expression.
+type TagMatchType int
+
+const (
+	// TagMatchEqual matches tags with the value, i.e. tag=value.
+	TagMatchEqual TagMatchType = iota
+	// TagMatchNotEqual matches tags without the value, i.e. tag!=value.
+	TagMatchNotEqual
+	// TagMatchRegexp matches tags with values that start with a match of the
+	// regular expression, i.e. tag=~regexp.
+	TagMatchRegexp
+	// TagMatchNotRegexp matches tags with values that do not start with a
+	// match of the regular expression, i.e. tag!=~regexp.
+	TagMatchNotRegexp
+)
+
+// String returns the operator of the tag match type.
+func (t TagMatchType) String() string {
+	switch t {
+	case TagMatchEqual:
+		return "="
+	case TagMatchNotEqual:
+		return "!="
+	case TagMatchRegexp:
+		return "=~"
+	case TagMatchNotRegexp:
+		return "!=~"
+	default:
+		return "unknown"
+	}
+}
+
+// TagExpression is a tag expression of a seriesByTag query.
+type TagExpression struct {
+	Name  string
+	Type  TagMatchType
+	Value string
+}
+
+// String returns the tag expression as given to seriesByTag.
+func (e TagExpression) String() string {
+	return e.Name + e.Type.String() + e.Value
+}
+
+// MatchesEmpty returns true if the tag expression matches series without
+// the tag.
+func (e TagExpression) MatchesEmpty() (bool, error) {
+	switch e.Type {
+	case TagMatchEqual:
+		return e.Value == "", nil
+	case TagMatchNotEqual:
+		return e.Value != "", nil
+	}
+
+	re, err := regexp.Compile("^(?:" + e.Value + ")")
+	if err != nil {
+		return false, err
+	}
+
+	matches := re.MatchString("")
+	if e.Type == TagMatchNotRegexp {
+		return !matches, nil
+	}
+
+	return matches, nil
+}
+
+// ParseTagExpression parses a tag expression of the form tag=value,
+// tag!=value, tag=~regexp or tag!=~regexp.
+func ParseTagExpression(expr string) (TagExpression, error) {
+	idx := strings.IndexAny(expr, "!=")
+	if idx <= 0 {
+		return TagExpression{}, fmt.Errorf("invalid tag expression: %s", expr)
+	}
+
+	var (
+		name = expr[:idx]
+		rest = expr[idx:]
+		t    TagMatchType
+	)
+	switch {
+	case strings.HasPrefix(rest, "!=~"):
+		t, rest = TagMatchNotRegexp, rest[3:]
+	case strings.HasPrefix(rest, "!="):
+		t, rest = TagMatchNotEqual, rest[2:]
+	case strings.HasPrefix(rest, "=~"):
+		t, rest = TagMatchRegexp, rest[2:]
+	case strings.HasPrefix(rest, "="):
+		t, rest = TagMatchEqual, rest[1:]
+	default:
+		return TagExpression{}, fmt.Errorf("invalid tag expression: %s", expr)
+	}
+
+	return TagExpression{Name: name, Type: t, Value: rest}, nil
+}
+
+// SeriesByTagQuery returns the query for series matching all of the given
+// tag expressions.
+func SeriesByTagQuery(exprs []string) string {
+	quoted := make([]string, 0, len(exprs))
+	for _, expr := range exprs {
+		quoted = append(quoted, "'"+strings.ReplaceAll(expr, "'", "\\'")+"'")
+	}
+
+	return SeriesByTagFunction + "(" + strings.Join(quoted, ",") + ")"
+}
+
+// IsSeriesByTagQuery returns true if the query is a seriesByTag query.
+func IsSeriesByTagQuery(query string) bool {
+	return strings.HasPrefix(query, SeriesByTagFunction+"(") &&
+		strings.HasSuffix(query, ")")
+}
+
+// ParseSeriesByTagQuery parses the tag expressions of a seriesByTag query,
+// as returned by SeriesByTagQuery. At least one of the expressions must not
+// match series without the tag, as otherwise every series would match.
+func ParseSeriesByTagQuery(query string) ([]TagExpression, error) {
+	if !IsSeriesByTagQuery(query) {
+		return nil, fmt.Errorf("invalid seriesByTag query: %s", query)
+	}
+
+	args := query[len(SeriesByTagFunction)+1 : len(query)-1]
+	var (
+		exprs       []TagExpression
+		nonEmptyArg bool
+	)
+	for len(args) > 0 {
+		if args[0] != '\'' && args[0] != '"' {
+			return nil, fmt.Errorf("invalid seriesByTag query: %s", query)
+		}
+
+		var (
+			quote  = args[0]
+			arg    strings.Builder
+			closed bool
+			i      = 1
+		)
+		for ; i < len(args); i++ {
+			c := args[i]
+			if c == '\\' && i+1 < len(args) {
+				i++
+				arg.WriteByte(args[i])
+				continue
+			}
+			if c == quote {
+				closed = true
+				break
+			}
+			arg.WriteByte(c)
+		}
+		if !closed {
+			return nil, fmt.Errorf("invalid seriesByTag query: %s", query)
+		}
+
+		args = strings.TrimLeft(args[i+1:], " ")
+		if len(args) > 0 {
+			if args[0] != ',' {
+				return nil, fmt.Errorf("invalid seriesByTag query: %s", query)
+			}
+			args = strings.TrimLeft(args[1:], " ")
+		}
+
+		expr, err := ParseTagExpression(arg.String())
+		if err != nil {
+			return nil, err
+		}
+
+		matchesEmpty, err := expr.MatchesEmpty()
+		if err != nil {
+			return nil, err
+		}
+
+		nonEmptyArg = nonEmptyArg || !matchesEmpty
+		exprs = append(exprs, expr)
+	}
+
+	if len(exprs) == 0 {
+		return nil, errNoTagExpressions
+	}
+
+	if !nonEmptyArg {
+		return nil, errNoNonEmptyMatching
+	}
+
+	return exprs, nil
+}
+
+// ParseTaggedSeriesName splits the name of a series into its path and tags,
+// the path is also returned as the value of the "name" tag. Names of series
+// that are not tagged only have the "name" tag.
+func ParseTaggedSeriesName(name string) (string, map[string]string) {
+	parts := strings.Split(name, TaggedSeriesSeparator)
+	tags := make(map[string]string, len(parts))
+	tags[NameTag] = parts[0]
+	for _, part := range parts[1:] {
+		idx := strings.Index(part, "=")
+		if idx <= 0 {
+			continue
+		}
+		tags[part[:idx]] = part[idx+1:]
+	}
+
+	return parts[0], tags
+}
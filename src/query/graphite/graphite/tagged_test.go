@@ -0,0 +1,98 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package graphite
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTagExpression(t *testing.T) {
+	tests := []struct {
+		expr     string
+		expected TagExpression
+	}{
+		{"dc=east", TagExpression{Name: "dc", Type: TagMatchEqual, Value: "east"}},
+		{"dc!=east", TagExpression{Name: "dc", Type: TagMatchNotEqual, Value: "east"}},
+		{"dc=~e.*", TagExpression{Name: "dc", Type: TagMatchRegexp, Value: "e.*"}},
+		{"dc!=~e.*", TagExpression{Name: "dc", Type: TagMatchNotRegexp, Value: "e.*"}},
+		{"dc=", TagExpression{Name: "dc", Type: TagMatchEqual, Value: ""}},
+		{"dc==a", TagExpression{Name: "dc", Type: TagMatchEqual, Value: "=a"}},
+	}
+
+	for _, test := range tests {
+		expr, err := ParseTagExpression(test.expr)
+		require.NoError(t, err)
+		assert.Equal(t, test.expected, expr)
+		assert.Equal(t, test.expr, expr.String())
+	}
+
+	for _, expr := range []string{"dc", "=east", "dc!east"} {
+		_, err := ParseTagExpression(expr)
+		assert.Error(t, err, expr)
+	}
+}
+
+func TestParseSeriesByTagQuery(t *testing.T) {
+	query := SeriesByTagQuery([]string{"name=disk.used", "dc!=~it's"})
+	assert.Equal(t, `seriesByTag('name=disk.used','dc!=~it\'s')`, query)
+	assert.True(t, IsSeriesByTagQuery(query))
+	assert.False(t, IsSeriesByTagQuery("disk.used"))
+
+	exprs, err := ParseSeriesByTagQuery(query)
+	require.NoError(t, err)
+	assert.Equal(t, []TagExpression{
+		{Name: "name", Type: TagMatchEqual, Value: "disk.used"},
+		{Name: "dc", Type: TagMatchNotRegexp, Value: "it's"},
+	}, exprs)
+
+	exprs, err = ParseSeriesByTagQuery(`seriesByTag("dc=east", 'host=a')`)
+	require.NoError(t, err)
+	assert.Equal(t, 2, len(exprs))
+
+	for _, query := range []string{
+		"seriesByTag()",
+		"seriesByTag('dc=east'",
+		"seriesByTag('dc=east' 'host=a')",
+		"seriesByTag('dc!=east')",
+		"seriesByTag('dc=~.*')",
+		"seriesByTag('dc=~(')",
+	} {
+		_, err := ParseSeriesByTagQuery(query)
+		assert.Error(t, err, query)
+	}
+}
+
+func TestParseTaggedSeriesName(t *testing.T) {
+	path, tags := ParseTaggedSeriesName("disk.used;dc=east;host=a")
+	assert.Equal(t, "disk.used", path)
+	assert.Equal(t, map[string]string{
+		"name": "disk.used",
+		"dc":   "east",
+		"host": "a",
+	}, tags)
+
+	path, tags = ParseTaggedSeriesName("disk.used")
+	assert.Equal(t, "disk.used", path)
+	assert.Equal(t, map[string]string{"name": "disk.used"}, tags)
+}
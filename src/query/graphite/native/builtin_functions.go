@@ -2835,6 +2835,7 @@ func init() {
 	MustRegisterFunction(aggregateLine).WithDefaultParams(map[uint8]interface{}{
 		2: "avg", // f
 	})
+	MustRegisterFunction(aggregateWithTags)
 	MustRegisterFunction(aggregateWithWildcards).WithDefaultParams(map[uint8]interface{}{
 		3: -1, // positions
 	})
@@ -2881,6 +2882,7 @@ func init() {
 		3: "average", // fname
 	})
 	MustRegisterFunction(groupByNodes)
+	MustRegisterFunction(groupByTags)
 	MustRegisterFunction(highest).WithDefaultParams(map[uint8]interface{}{
 		2: 1,         // n,
 		3: "average", // f
@@ -2986,6 +2988,7 @@ func init() {
 	})
 	MustRegisterFunction(scale)
 	MustRegisterFunction(scaleToSeconds)
+	MustRegisterFunction(seriesByTag)
 	MustRegisterFunction(sortBy).WithDefaultParams(map[uint8]interface{}{
 		2: "average", // fn
 		3: false,     // reverse
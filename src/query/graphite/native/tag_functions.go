@@ -0,0 +1,193 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package native
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/m3db/m3/src/query/graphite/common"
+	"github.com/m3db/m3/src/query/graphite/graphite"
+	"github.com/m3db/m3/src/query/graphite/ts"
+	xerrors "github.com/m3db/m3/src/x/errors"
+)
+
+// seriesByTag returns the tagged series matching all of the tag expressions,
+// each of the form tag=value, tag!=value, tag=~regexp or tag!=~regexp. The
+// path of tagged series is matched by the "name" tag.
+func seriesByTag(ctx *common.Context, tagExpressions ...string) (ts.SeriesList, error) {
+	query := graphite.SeriesByTagQuery(tagExpressions)
+	if _, err := graphite.ParseSeriesByTagQuery(query); err != nil {
+		return ts.NewSeriesList(), xerrors.NewInvalidParamsError(err)
+	}
+
+	return newFetchExpression(query).Execute(ctx)
+}
+
+// seriesTags returns the tags of a series, parsed from the name of the tagged
+// series it was derived from, with its path as the "name" tag.
+func seriesTags(series *ts.Series) (map[string]string, error) {
+	name := series.Name()
+	path, err := getFirstPathExpression(name)
+	if err != nil {
+		return nil, err
+	}
+
+	idx := strings.Index(name, path+graphite.TaggedSeriesSeparator)
+	if idx < 0 {
+		return map[string]string{graphite.NameTag: path}, nil
+	}
+
+	tagged := name[idx+len(path):]
+	if end := strings.IndexAny(tagged, ",)"); end >= 0 {
+		// Trim the rest of any function call the series was derived from.
+		tagged = tagged[:end]
+	}
+
+	_, tags := graphite.ParseTaggedSeriesName(path + tagged)
+	return tags, nil
+}
+
+// taggedSeriesName returns the name of a tagged series with the path and
+// tags, with the tags ordered by name.
+func taggedSeriesName(path string, tags map[string]string) string {
+	names := make([]string, 0, len(tags))
+	for name := range tags {
+		if name != graphite.NameTag {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString(path)
+	for _, name := range names {
+		b.WriteString(graphite.TaggedSeriesSeparator)
+		b.WriteString(name)
+		b.WriteString("=")
+		b.WriteString(tags[name])
+	}
+	return b.String()
+}
+
+// groupByTags takes a seriesList and groups the series by the values of the
+// given tags, aggregating each group with the given function. The series of
+// each group are named as a tagged series with the tags, and with the common
+// path of all series as the path, or the function name if they differ, or
+// the path of the group if grouped by the "name" tag.
+func groupByTags(ctx *common.Context, seriesList singlePathSpec, fname string, tags ...string) (ts.SeriesList, error) {
+	if len(tags) == 0 {
+		return ts.NewSeriesList(), xerrors.NewInvalidParamsError(
+			fmt.Errorf("groupByTags requires at least one tag"))
+	}
+
+	tagsBySeries := make([]map[string]string, 0, len(seriesList.Values))
+	commonPath := ""
+	for i, series := range seriesList.Values {
+		seriesTags, err := seriesTags(series)
+		if err != nil {
+			return ts.NewSeriesList(), err
+		}
+
+		path := seriesTags[graphite.NameTag]
+		if i == 0 {
+			commonPath = path
+		} else if commonPath != path {
+			commonPath = fname
+		}
+		tagsBySeries = append(tagsBySeries, seriesTags)
+	}
+
+	metaSeries := make(map[string][]*ts.Series)
+	for i, series := range seriesList.Values {
+		var (
+			path      = commonPath
+			groupTags = make(map[string]string, len(tags))
+		)
+		for _, tag := range tags {
+			if tag == graphite.NameTag {
+				path = tagsBySeries[i][graphite.NameTag]
+				continue
+			}
+			groupTags[tag] = tagsBySeries[i][tag]
+		}
+
+		key := taggedSeriesName(path, groupTags)
+		metaSeries[key] = append(metaSeries[key], series)
+	}
+
+	return applyFnToMetaSeries(ctx, seriesList, metaSeries, fname)
+}
+
+// aggregateWithTags takes a seriesList and aggregates the series with the
+// given function, grouped by the values of any given tags. The series of each
+// group are named as a tagged series with the tags whose values are the same
+// for all series of the group, and with the common path of the series of the
+// group as the path, or the function name if they differ.
+func aggregateWithTags(ctx *common.Context, seriesList singlePathSpec, fname string, groupTags ...string) (ts.SeriesList, error) {
+	type group struct {
+		series []*ts.Series
+		tags   map[string]string
+	}
+
+	var (
+		groups = make(map[string]*group)
+		keys   = make([]string, 0, len(groupTags))
+	)
+	for _, series := range seriesList.Values {
+		seriesTags, err := seriesTags(series)
+		if err != nil {
+			return ts.NewSeriesList(), err
+		}
+
+		keys = keys[:0]
+		for _, tag := range groupTags {
+			keys = append(keys, tag+"="+seriesTags[tag])
+		}
+
+		key := strings.Join(keys, graphite.TaggedSeriesSeparator)
+		g, ok := groups[key]
+		if !ok {
+			groups[key] = &group{series: []*ts.Series{series}, tags: seriesTags}
+			continue
+		}
+
+		g.series = append(g.series, series)
+		for name, value := range g.tags {
+			if seriesTags[name] != value {
+				if name == graphite.NameTag {
+					g.tags[name] = fname
+					continue
+				}
+				delete(g.tags, name)
+			}
+		}
+	}
+
+	metaSeries := make(map[string][]*ts.Series, len(groups))
+	for _, g := range groups {
+		key := taggedSeriesName(g.tags[graphite.NameTag], g.tags)
+		metaSeries[key] = append(metaSeries[key], g.series...)
+	}
+
+	return applyFnToMetaSeries(ctx, seriesList, metaSeries, fname)
+}
@@ -0,0 +1,152 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package native
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/query/graphite/common"
+	"github.com/m3db/m3/src/query/graphite/storage"
+	"github.com/m3db/m3/src/query/graphite/ts"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	xgomock "github.com/m3db/m3/src/x/test"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTaggedSeries(ctx *common.Context, start time.Time) []*ts.Series {
+	return []*ts.Series{
+		ts.NewSeries(ctx, "disk.used;dc=east;host=a", start,
+			common.NewTestSeriesValues(ctx, 60000, []float64{1, 2, 3})),
+		ts.NewSeries(ctx, "disk.used;dc=east;host=b", start,
+			common.NewTestSeriesValues(ctx, 60000, []float64{10, 20, 30})),
+		ts.NewSeries(ctx, "disk.used;dc=west;host=c", start,
+			common.NewTestSeriesValues(ctx, 60000, []float64{100, 200, 300})),
+	}
+}
+
+func TestSeriesByTag(t *testing.T) {
+	var (
+		ctrl     = xgomock.NewController(t)
+		store    = storage.NewMockStorage(ctrl)
+		engine   = NewEngine(store, CompileOptions{})
+		start, _ = time.Parse(time.RFC1123, "Mon, 27 Jul 2015 19:41:19 GMT")
+		end, _   = time.Parse(time.RFC1123, "Mon, 27 Jul 2015 19:43:19 GMT")
+		ctx      = common.NewContext(common.ContextOptions{Start: start, End: end, Engine: engine})
+		query    = "seriesByTag('name=disk.used','dc=east')"
+	)
+
+	defer ctrl.Finish()
+	defer ctx.Close()
+
+	store.EXPECT().FetchByQuery(gomock.Any(), query, gomock.Any()).Return(
+		&storage.FetchResult{SeriesList: newTestTaggedSeries(ctx, start)[:2]}, nil)
+
+	expr, err := engine.Compile(`aliasByTags(seriesByTag("name=disk.used", "dc=east"), 1, -1)`)
+	require.NoError(t, err)
+
+	res, err := expr.Execute(ctx)
+	require.NoError(t, err)
+	common.CompareOutputsAndExpected(t, 60000, start, []common.TestSeries{
+		{Name: "used.used", Data: []float64{1, 2, 3}},
+		{Name: "used.used", Data: []float64{10, 20, 30}},
+	}, res.Values)
+}
+
+func TestSeriesByTagInvalid(t *testing.T) {
+	ctx := common.NewTestContext()
+	defer ctx.Close()
+
+	for _, exprs := range [][]string{
+		nil,
+		{"dc"},
+		{"dc!=east"},
+		{"dc=~.*"},
+	} {
+		_, err := seriesByTag(ctx, exprs...)
+		require.Error(t, err, "expected error for %v", exprs)
+		assert.True(t, xerrors.IsInvalidParams(err))
+	}
+}
+
+func TestGroupByTags(t *testing.T) {
+	ctx := common.NewTestContext()
+	defer ctx.Close()
+
+	start := ctx.StartTime
+	inputs := newTestTaggedSeries(ctx, start)
+
+	res, err := groupByTags(ctx, singlePathSpec{Values: inputs}, "sum", "dc")
+	require.NoError(t, err)
+	res, err = sortByName(ctx, singlePathSpec(res), false, false)
+	require.NoError(t, err)
+	common.CompareOutputsAndExpected(t, 60000, start, []common.TestSeries{
+		{Name: "disk.used;dc=east", Data: []float64{11, 22, 33}},
+		{Name: "disk.used;dc=west", Data: []float64{100, 200, 300}},
+	}, res.Values)
+
+	inputs = append(inputs, ts.NewSeries(ctx, "disk.free;dc=east;host=a", start,
+		common.NewTestSeriesValues(ctx, 60000, []float64{5, 5, 5})))
+	res, err = groupByTags(ctx, singlePathSpec{Values: inputs}, "max", "dc", "host")
+	require.NoError(t, err)
+	res, err = sortByName(ctx, singlePathSpec(res), false, false)
+	require.NoError(t, err)
+	common.CompareOutputsAndExpected(t, 60000, start, []common.TestSeries{
+		{Name: "max;dc=east;host=a", Data: []float64{5, 5, 5}},
+		{Name: "max;dc=east;host=b", Data: []float64{10, 20, 30}},
+		{Name: "max;dc=west;host=c", Data: []float64{100, 200, 300}},
+	}, res.Values)
+
+	_, err = groupByTags(ctx, singlePathSpec{Values: inputs}, "sum")
+	require.Error(t, err)
+}
+
+func TestAggregateWithTags(t *testing.T) {
+	ctx := common.NewTestContext()
+	defer ctx.Close()
+
+	start := ctx.StartTime
+	inputs := newTestTaggedSeries(ctx, start)
+
+	res, err := aggregateWithTags(ctx, singlePathSpec{Values: inputs}, "sum")
+	require.NoError(t, err)
+	common.CompareOutputsAndExpected(t, 60000, start, []common.TestSeries{
+		{Name: "disk.used", Data: []float64{111, 222, 333}},
+	}, res.Values)
+
+	res, err = aggregateWithTags(ctx, singlePathSpec{Values: inputs[:2]}, "sum")
+	require.NoError(t, err)
+	common.CompareOutputsAndExpected(t, 60000, start, []common.TestSeries{
+		{Name: "disk.used;dc=east", Data: []float64{11, 22, 33}},
+	}, res.Values)
+
+	res, err = aggregateWithTags(ctx, singlePathSpec{Values: inputs}, "max", "dc")
+	require.NoError(t, err)
+	res, err = sortByName(ctx, singlePathSpec(res), false, false)
+	require.NoError(t, err)
+	common.CompareOutputsAndExpected(t, 60000, start, []common.TestSeries{
+		{Name: "disk.used;dc=east", Data: []float64{10, 20, 30}},
+		{Name: "disk.used;dc=west;host=c", Data: []float64{100, 200, 300}},
+	}, res.Values)
+}
@@ -21,6 +21,11 @@
 package storage
 
 import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/m3db/m3/src/m3ninx/doc"
 	"github.com/m3db/m3/src/query/graphite/graphite"
 	"github.com/m3db/m3/src/query/models"
 )
@@ -78,3 +83,97 @@ func matcherTerminator(count int) models.Matcher {
 		Name: graphite.TagName(count),
 	}
 }
+
+// convertTagExpressionToMatchers converts a seriesByTag tag expression to
+// tag matchers. Graphite regular expressions are only anchored at the start
+// of the value, while M3 regular expressions match the entire value.
+func convertTagExpressionToMatchers(
+	expr graphite.TagExpression,
+) (models.Matchers, error) {
+	if expr.Name == graphite.NameTag {
+		return convertNameTagExpressionToMatchers(expr)
+	}
+
+	name := []byte(expr.Name)
+	switch expr.Type {
+	case graphite.TagMatchEqual:
+		if expr.Value == "" {
+			return models.Matchers{{Type: models.MatchNotField, Name: name}}, nil
+		}
+		return models.Matchers{{
+			Type:  models.MatchEqual,
+			Name:  name,
+			Value: []byte(expr.Value),
+		}}, nil
+	case graphite.TagMatchNotEqual:
+		if expr.Value == "" {
+			return models.Matchers{{Type: models.MatchField, Name: name}}, nil
+		}
+		return models.Matchers{{
+			Type:  models.MatchNotEqual,
+			Name:  name,
+			Value: []byte(expr.Value),
+		}}, nil
+	case graphite.TagMatchRegexp, graphite.TagMatchNotRegexp:
+		if _, err := regexp.Compile(expr.Value); err != nil {
+			return nil, err
+		}
+		matchType := models.MatchRegexp
+		if expr.Type == graphite.TagMatchNotRegexp {
+			matchType = models.MatchNotRegexp
+		}
+		return models.Matchers{{
+			Type:  matchType,
+			Name:  name,
+			Value: []byte("(?:" + expr.Value + ").*"),
+		}}, nil
+	}
+
+	return nil, fmt.Errorf("invalid tag expression: %s", expr.String())
+}
+
+// convertNameTagExpressionToMatchers converts a tag expression on the path of
+// tagged series, an exact match is converted to path tag matchers while
+// other expressions match the series ID, the path followed by any tags.
+func convertNameTagExpressionToMatchers(
+	expr graphite.TagExpression,
+) (models.Matchers, error) {
+	if expr.Type == graphite.TagMatchEqual {
+		if expr.Value == "" {
+			return nil, fmt.Errorf("invalid tag expression: %s", expr.String())
+		}
+		parts := strings.Split(expr.Value, ".")
+		matchers := make(models.Matchers, 0, len(parts)+1)
+		for i, part := range parts {
+			matchers = append(matchers, models.Matcher{
+				Type:  models.MatchEqual,
+				Name:  graphite.TagName(i),
+				Value: []byte(part),
+			})
+		}
+		return append(matchers, matcherTerminator(len(parts))), nil
+	}
+
+	var pattern, matchType = "", models.MatchRegexp
+	switch expr.Type {
+	case graphite.TagMatchNotEqual:
+		pattern = regexp.QuoteMeta(expr.Value)
+		matchType = models.MatchNotRegexp
+	case graphite.TagMatchRegexp, graphite.TagMatchNotRegexp:
+		if _, err := regexp.Compile(expr.Value); err != nil {
+			return nil, err
+		}
+		pattern = "(?:" + expr.Value + ")[^;]*"
+		if expr.Type == graphite.TagMatchNotRegexp {
+			matchType = models.MatchNotRegexp
+		}
+	default:
+		return nil, fmt.Errorf("invalid tag expression: %s", expr.String())
+	}
+
+	return models.Matchers{{
+		Type:  matchType,
+		Name:  doc.IDReservedFieldName,
+		Value: []byte(pattern + "(?:;.*)?"),
+	}}, nil
+}
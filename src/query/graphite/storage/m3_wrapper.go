@@ -160,6 +160,44 @@ func TranslateQueryToMatchersWithTerminator(
 	return matchers, TerminatedTranslatedQuery, nil
 }
 
+// TranslateSeriesByTagQueryToMatchers converts a graphite seriesByTag query
+// to tag matchers.
+func TranslateSeriesByTagQueryToMatchers(
+	query string,
+) (models.Matchers, error) {
+	exprs, err := graphite.ParseSeriesByTagQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		matchers    models.Matchers
+		hasPathName bool
+	)
+	for _, expr := range exprs {
+		m, err := convertTagExpressionToMatchers(expr)
+		if err != nil {
+			return nil, err
+		}
+
+		if expr.Name == graphite.NameTag && expr.Type == graphite.TagMatchEqual {
+			hasPathName = true
+		}
+		matchers = append(matchers, m...)
+	}
+
+	if !hasPathName {
+		// Ensure only graphite metrics, which have a __g0__ tag, are matched.
+		hasFirstPathMatcher, err := convertMetricPartToMatcher(0, wildcard)
+		if err != nil {
+			return nil, err
+		}
+		matchers = append(models.Matchers{hasFirstPathMatcher}, matchers...)
+	}
+
+	return matchers, nil
+}
+
 // GetQueryTerminatorTagName will return the name for the terminator matcher in
 // the given pattern. This is useful for filtering out any additional results.
 func GetQueryTerminatorTagName(query string) []byte {
@@ -172,7 +210,15 @@ func translateQuery(
 	fetchOpts FetchOptions,
 	opts M3WrappedStorageOptions,
 ) (*storage.FetchQuery, error) {
-	matchers, _, err := TranslateQueryToMatchersWithTerminator(query)
+	var (
+		matchers models.Matchers
+		err      error
+	)
+	if graphite.IsSeriesByTagQuery(query) {
+		matchers, err = TranslateSeriesByTagQueryToMatchers(query)
+	} else {
+		matchers, _, err = TranslateQueryToMatchersWithTerminator(query)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -110,6 +110,48 @@ func TestTranslateQueryStarStar(t *testing.T) {
 	assert.Equal(t, expected, matchers)
 }
 
+func TestTranslateSeriesByTagQuery(t *testing.T) {
+	query := `seriesByTag('name=disk.used','dc=east','host!=a','env=','rack=~r1','az!=~z')`
+	end := time.Now()
+	start := end.Add(time.Hour * -2)
+	opts := FetchOptions{
+		StartTime: start,
+		EndTime:   end,
+		DataOptions: DataOptions{
+			Timeout: time.Minute,
+		},
+	}
+
+	translated, err := translateQuery(query, opts, M3WrappedStorageOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, query, translated.Raw)
+	expected := models.Matchers{
+		{Type: models.MatchEqual, Name: graphite.TagName(0), Value: []byte("disk")},
+		{Type: models.MatchEqual, Name: graphite.TagName(1), Value: []byte("used")},
+		{Type: models.MatchNotField, Name: graphite.TagName(2)},
+		{Type: models.MatchEqual, Name: []byte("dc"), Value: []byte("east")},
+		{Type: models.MatchNotEqual, Name: []byte("host"), Value: []byte("a")},
+		{Type: models.MatchNotField, Name: []byte("env")},
+		{Type: models.MatchRegexp, Name: []byte("rack"), Value: []byte("(?:r1).*")},
+		{Type: models.MatchNotRegexp, Name: []byte("az"), Value: []byte("(?:z).*")},
+	}
+	assert.Equal(t, expected, translated.TagMatchers)
+
+	query = `seriesByTag('name=~disk\\.u','dc!=')`
+	translated, err = translateQuery(query, opts, M3WrappedStorageOptions{})
+	require.NoError(t, err)
+	expected = models.Matchers{
+		{Type: models.MatchRegexp, Name: graphite.TagName(0), Value: []byte(".*")},
+		{Type: models.MatchRegexp, Name: doc.IDReservedFieldName,
+			Value: []byte(`(?:disk\.u)[^;]*(?:;.*)?`)},
+		{Type: models.MatchField, Name: []byte("dc")},
+	}
+	assert.Equal(t, expected, translated.TagMatchers)
+
+	_, err = translateQuery(`seriesByTag('dc!=east')`, opts, M3WrappedStorageOptions{})
+	require.Error(t, err)
+}
+
 func TestTranslateQueryTrailingDot(t *testing.T) {
 	query := `foo.`
 	end := time.Now()
@@ -29,6 +29,7 @@ var validIDSchemes = []IDSchemeType{
 	TypeQuoted,
 	TypePrependMeta,
 	TypeGraphite,
+	TypeGraphiteTagged,
 }
 
 // Validate validates that the scheme type is valid.
@@ -37,7 +38,7 @@ func (t IDSchemeType) Validate() error {
 		return errors.New("id scheme type not set")
 	}
 
-	if t >= TypeQuoted && t <= TypeGraphite {
+	if t >= TypeQuoted && t <= TypeGraphiteTagged {
 		return nil
 	}
 
@@ -45,6 +46,10 @@ func (t IDSchemeType) Validate() error {
 		t, validIDSchemes)
 }
 
+func (t IDSchemeType) isGraphite() bool {
+	return t == TypeGraphite || t == TypeGraphiteTagged
+}
+
 func (t IDSchemeType) String() string {
 	switch t {
 	case TypeDefault:
@@ -55,6 +60,8 @@ func (t IDSchemeType) String() string {
 		return "prepend_meta"
 	case TypeGraphite:
 		return "graphite"
+	case TypeGraphiteTagged:
+		return "graphite_tagged"
 	default:
 		// Should never get here.
 		return "unknown"
@@ -69,10 +76,10 @@ func (t *IDSchemeType) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	}
 
 	for _, valid := range validIDSchemes {
-		if valid == TypeGraphite {
-			// NB: while the graphite scheme is valid, it is not available to choose
-			// as a general ID scheme; instead, it is set on any metric coming through
-			// the graphite ingestion path.
+		if valid.isGraphite() {
+			// NB: while the graphite schemes are valid, they are not available to
+			// choose as a general ID scheme; instead, they are set on any metric
+			// coming through the graphite ingestion path.
 			continue
 		}
 
@@ -38,9 +38,11 @@ func TestIDSchemeValidation(t *testing.T) {
 	assert.NoError(t, err)
 	err = TypeGraphite.Validate()
 	assert.NoError(t, err)
+	err = TypeGraphiteTagged.Validate()
+	assert.NoError(t, err)
 	err = IDSchemeType(5).Validate()
 	assert.EqualError(t, err, "invalid config id schema type 'unknown':"+
-		" should be one of [quoted prepend_meta graphite]")
+		" should be one of [quoted prepend_meta graphite graphite_tagged]")
 }
 
 func TestMetricsTypeUnmarshalYAML(t *testing.T) {
@@ -65,6 +67,7 @@ func TestMetricsTypeUnmarshalYAML(t *testing.T) {
 	var cfg config
 	// Graphite fails.
 	require.Error(t, yaml.Unmarshal([]byte("type: graphite\n"), &cfg))
+	require.Error(t, yaml.Unmarshal([]byte("type: graphite_tagged\n"), &cfg))
 	// Bad type fails.
 	require.Error(t, yaml.Unmarshal([]byte("type: not_a_known_type\n"), &cfg))
 
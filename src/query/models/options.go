@@ -45,6 +45,7 @@ type tagOptions struct {
 	filters                Filters
 	allowTagNameDuplicates bool
 	allowTagValueEmpty     bool
+	numericFields          [][]byte
 }
 
 // NewTagOptions builds a new tag options with default values.
@@ -131,10 +132,33 @@ func (o *tagOptions) AllowTagValueEmpty() bool {
 	return o.allowTagValueEmpty
 }
 
+func (o *tagOptions) SetNumericFields(value [][]byte) TagOptions {
+	opts := *o
+	opts.numericFields = value
+	return &opts
+}
+
+func (o *tagOptions) NumericFields() [][]byte {
+	return o.numericFields
+}
+
 func (o *tagOptions) Equals(other TagOptions) bool {
 	return o.idScheme == other.IDSchemeType() &&
 		bytes.Equal(o.metricName, other.MetricName()) &&
 		bytes.Equal(o.bucketName, other.BucketName()) &&
 		o.allowTagNameDuplicates == other.AllowTagNameDuplicates() &&
-		o.allowTagValueEmpty == other.AllowTagValueEmpty()
+		o.allowTagValueEmpty == other.AllowTagValueEmpty() &&
+		numericFieldsEqual(o.numericFields, other.NumericFields())
+}
+
+func numericFieldsEqual(a, b [][]byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !bytes.Equal(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
 }
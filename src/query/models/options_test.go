@@ -69,7 +69,7 @@ func TestBadBucketTagOptions(t *testing.T) {
 
 func TestBadSchemeTagOptions(t *testing.T) {
 	msg := "invalid config id schema type 'unknown': should be one of" +
-		" [quoted prepend_meta graphite]"
+		" [quoted prepend_meta graphite graphite_tagged]"
 	opts := NewTagOptions().
 		SetIDSchemeType(IDSchemeType(6))
 	assert.EqualError(t, opts.Validate(), msg)
@@ -92,4 +92,11 @@ func TestOptionsEquals(t *testing.T) {
 	opts = opts.SetMetricName(n)
 	opts = opts.SetIDSchemeType(IDSchemeType(10))
 	assert.False(t, opts.Equals(other))
+
+	opts = opts.SetIDSchemeType(other.IDSchemeType())
+	assert.True(t, opts.Equals(other))
+	opts = opts.SetNumericFields([][]byte{[]byte("code")})
+	assert.False(t, opts.Equals(other))
+	other = other.SetNumericFields([][]byte{[]byte("code")})
+	assert.True(t, opts.Equals(other))
 }
@@ -35,6 +35,9 @@ import (
 
 var (
 	errNoTags = errors.New("no tags")
+
+	graphitePathTagPrefix = []byte("__g")
+	graphitePathTagSuffix = []byte("__")
 )
 
 // NewTags builds a tags with the given size and tag options.
@@ -246,6 +249,19 @@ func (t sortableTagsNumericallyAsc) Swap(i, j int) {
 }
 func (t sortableTagsNumericallyAsc) Less(i, j int) bool {
 	iName, jName := t.Tags[i].Name, t.Tags[j].Name
+	if t.Opts.IDSchemeType() == TypeGraphiteTagged {
+		// Path tags are ordered before any tags given with the tagged format
+		// (e.g. "path;tag=value"), which are then ordered lexically.
+		iPath, jPath := isGraphitePathTag(iName), isGraphitePathTag(jName)
+		if iPath != jPath {
+			return iPath
+		}
+
+		if !iPath {
+			return bytes.Compare(iName, jName) == -1
+		}
+	}
+
 	lenDiff := len(iName) - len(jName)
 	if lenDiff < 0 {
 		return true
@@ -258,10 +274,29 @@ func (t sortableTagsNumericallyAsc) Less(i, j int) bool {
 	return bytes.Compare(iName, jName) == -1
 }
 
+// isGraphitePathTag returns true if the name is a graphite path tag, i.e.
+// one of the form "__g0__".
+func isGraphitePathTag(name []byte) bool {
+	n := len(graphitePathTagPrefix)
+	if len(name) <= n+len(graphitePathTagSuffix) ||
+		!bytes.HasPrefix(name, graphitePathTagPrefix) ||
+		!bytes.HasSuffix(name, graphitePathTagSuffix) {
+		return false
+	}
+
+	for _, c := range name[n : len(name)-len(graphitePathTagSuffix)] {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
 // Normalize normalizes the tags by sorting them in place.
 // In the future, it might also ensure other things like uniqueness.
 func (t Tags) Normalize() Tags {
-	if t.Opts.IDSchemeType() == TypeGraphite {
+	if t.Opts.IDSchemeType().isGraphite() {
 		// Graphite tags are sorted numerically rather than lexically.
 		sort.Sort(sortableTagsNumericallyAsc(t))
 	} else {
@@ -289,7 +324,7 @@ func (t Tags) validate() error {
 		return errNoTags
 	}
 
-	if t.Opts.IDSchemeType() == TypeGraphite {
+	if t.Opts.IDSchemeType().isGraphite() {
 		// Graphite tags are sorted numerically rather than lexically.
 		tags := sortableTagsNumericallyAsc(t)
 		for i, tag := range tags.Tags {
@@ -42,6 +42,8 @@ func id(t Tags) []byte {
 		return prependMetaID(t)
 	case TypeGraphite:
 		return graphiteID(t)
+	case TypeGraphiteTagged:
+		return graphiteTaggedID(t)
 	default:
 		// Default to quoted meta
 		// NB: realistically, schema defaults should be set by here.
@@ -231,3 +233,46 @@ func graphiteID(t Tags) []byte {
 	copy(id[idx:], t.Tags[lastIndex].Value)
 	return id
 }
+
+func idLenGraphiteTagged(t Tags) int {
+	idLen := 0
+	for i, tag := range t.Tags {
+		idLen += len(tag.Value)
+		if !isGraphitePathTag(tag.Name) {
+			// Account for the separator, name and equals sign of tags of
+			// tagged series.
+			idLen += len(tag.Name) + 2
+		} else if i > 0 {
+			idLen++ // account for separator
+		}
+	}
+
+	return idLen
+}
+
+func graphiteTaggedID(t Tags) []byte {
+	// TODO: pool these bytes.
+	id := make([]byte, idLenGraphiteTagged(t))
+	idx := 0
+	for i, tag := range t.Tags {
+		if !isGraphitePathTag(tag.Name) {
+			// Tags other than path tags are appended in the graphite tagged
+			// series format, i.e. path;tag=value.
+			id[idx] = graphiteTagSep
+			idx++
+			idx += copy(id[idx:], tag.Name)
+			id[idx] = eq
+			idx++
+			idx += copy(id[idx:], tag.Value)
+			continue
+		}
+
+		if i > 0 {
+			id[idx] = graphiteSep
+			idx++
+		}
+		idx += copy(id[idx:], tag.Value)
+	}
+
+	return id
+}
@@ -76,6 +76,31 @@ func TestLongTagNewIDOutOfOrderGraphite(t *testing.T) {
 	assert.Equal(t, []byte("v0.v1.v2.v3.v4.v5.v6.v7.v8.v9.v10.v11.v12"), actual)
 }
 
+func testTaggedGraphiteTags(scheme IDSchemeType) Tags {
+	opts := NewTagOptions().SetIDSchemeType(scheme)
+	return NewTags(5, opts).AddTags([]Tag{
+		{Name: []byte("env"), Value: []byte("prod")},
+		{Name: graphite.TagName(10), Value: []byte("v10")},
+		{Name: []byte("dc"), Value: []byte("east")},
+		{Name: graphite.TagName(0), Value: []byte("v0")},
+		{Name: graphite.TagName(2), Value: []byte("v2")},
+	})
+}
+
+func TestTaggedNewIDOutOfOrderGraphiteTagged(t *testing.T) {
+	tags := testTaggedGraphiteTags(TypeGraphiteTagged)
+	require.NoError(t, tags.Validate())
+	assert.Equal(t, []byte("v0.v2.v10;dc=east;env=prod"), tags.ID())
+}
+
+func TestTaggedNewIDOutOfOrderGraphite(t *testing.T) {
+	// NB: the IDs of graphite series with tags other than path tags must not
+	// change, only series using the tagged scheme get tagged IDs.
+	tags := testTaggedGraphiteTags(TypeGraphite)
+	require.NoError(t, tags.Validate())
+	assert.Equal(t, []byte("east.prod.v0.v2.v10"), tags.ID())
+}
+
 func TestLongTagNewIDOutOfOrderQuotedWithEscape(t *testing.T) {
 	tags := testLongTagIDOutOfOrder(t, TypeQuoted)
 	tags = tags.AddTag(Tag{Name: []byte(`t5""`), Value: []byte(`v"5`)})
@@ -566,6 +591,7 @@ func TestEmptyTags(t *testing.T) {
 	}{
 		{TypePrependMeta, ""},
 		{TypeGraphite, ""},
+		{TypeGraphiteTagged, ""},
 		{TypeQuoted, "{}"},
 	}
 
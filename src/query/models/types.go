@@ -27,12 +27,13 @@ import (
 
 // Separators for tags.
 const (
-	graphiteSep  = byte('.')
-	sep          = byte(',')
-	finish       = byte('!')
-	eq           = byte('=')
-	leftBracket  = byte('{')
-	rightBracket = byte('}')
+	graphiteSep    = byte('.')
+	graphiteTagSep = byte(';')
+	sep            = byte(',')
+	finish         = byte('!')
+	eq             = byte('=')
+	leftBracket    = byte('{')
+	rightBracket   = byte('}')
 )
 
 // IDSchemeType determines the scheme for generating
@@ -70,6 +71,19 @@ const (
 	// a general ID scheme; instead, it is set on any metric coming through the
 	// graphite ingestion path.
 	TypeGraphite
+	// TypeGraphiteTagged describes a scheme where IDs are generated to match
+	// the graphite tagged series representation of the tags, the path tags are
+	// followed by the other tags in the graphite tagged format.
+	// {__g0__:v1},{__g1__:v2},{t1:v3} -> v1.v2;t1=v3
+	//
+	// NB: when TypeGraphiteTagged is specified, path tags are ordered
+	// numerically rather than lexically, followed by any other tags ordered
+	// lexically.
+	//
+	// NB 2: like the graphite scheme it is not available to choose as a general
+	// ID scheme; instead, it is set on any metric coming through the graphite
+	// ingestion path with tags, i.e. of the form "path;tag=value".
+	TypeGraphiteTagged
 )
 
 // TagOptions describes additional options for tags.
@@ -113,6 +127,13 @@ type TagOptions interface {
 	// AllowTagValueEmpty returns the value to allow empty tag values to appear.
 	AllowTagValueEmpty() bool
 
+	// SetNumericFields sets the tag names whose values are numeric, regexp
+	// matchers on these tags may be evaluated as numeric range queries.
+	SetNumericFields(value [][]byte) TagOptions
+
+	// NumericFields returns the tag names whose values are numeric.
+	NumericFields() [][]byte
+
 	// Equals determines if two tag options are equivalent.
 	Equals(other TagOptions) bool
 }
@@ -0,0 +1,194 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package promql
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/m3db/m3/src/query/functions/linear"
+	"github.com/m3db/m3/src/query/functions/temporal"
+
+	pql "github.com/prometheus/prometheus/promql/parser"
+)
+
+// function is a function supported by the M3 query engine that is missing
+// from the vendored Prometheus parser.
+type function struct {
+	pql.Function
+
+	// placeholder is a function known to the Prometheus parser with the same
+	// signature, which the function is parsed as.
+	placeholder string
+}
+
+// m3Functions is the table of functions that are only supported by the M3 query
+// engine. Queries using them are parsed by ParseExpr without registering them
+// with the global Prometheus function tables, so they remain unknown to the
+// Prometheus engine.
+var m3Functions = map[string]*function{
+	temporal.PresentType: newFunction(
+		temporal.PresentType, pql.ValueTypeMatrix, "changes"),
+	temporal.MadType: newFunction(
+		temporal.MadType, pql.ValueTypeMatrix, "changes"),
+	linear.HistogramCountType: newFunction(
+		linear.HistogramCountType, pql.ValueTypeVector, "abs"),
+	linear.HistogramSumType: newFunction(
+		linear.HistogramSumType, pql.ValueTypeVector, "abs"),
+	linear.HistogramStdDevType: newFunction(
+		linear.HistogramStdDevType, pql.ValueTypeVector, "abs"),
+	linear.HistogramStdVarType: newFunction(
+		linear.HistogramStdVarType, pql.ValueTypeVector, "abs"),
+}
+
+func newFunction(
+	name string,
+	argType pql.ValueType,
+	placeholder string,
+) *function {
+	return &function{
+		Function: pql.Function{
+			Name:       name,
+			ArgTypes:   []pql.ValueType{argType},
+			ReturnType: pql.ValueTypeVector,
+		},
+		placeholder: placeholder,
+	}
+}
+
+// parens is a pair of parentheses in a query, with the function called if
+// they enclose the arguments of a function in the M3 function table.
+type parens struct {
+	open  pql.Pos
+	close pql.Pos
+	fn    *function
+}
+
+// ParseExpr parses a PromQL query, resolving calls to the functions that are
+// only supported by the M3 query engine from its own function table.
+func ParseExpr(query string) (pql.Expr, error) {
+	var (
+		calls     = make(map[pql.Pos]*function)
+		rewritten = []byte(query)
+		lexer     = pql.Lex(query)
+		allParens []*parens
+		open      []*parens
+		prev      pql.Item
+	)
+	for {
+		var item pql.Item
+		lexer.NextItem(&item)
+		if item.Typ == pql.EOF || item.Typ == pql.ERROR {
+			break
+		}
+
+		switch item.Typ {
+		case pql.SPACE, pql.COMMENT:
+			continue
+		case pql.LEFT_PAREN:
+			p := &parens{open: item.Pos, close: pql.Pos(len(query))}
+			if fn, ok := m3Functions[prev.Val]; ok && prev.Typ == pql.IDENTIFIER {
+				// NB: the placeholder is padded with spaces so that the positions
+				// of the parsed expressions match the query.
+				p.fn = fn
+				calls[prev.Pos] = fn
+				copy(rewritten[prev.Pos:item.Pos], fn.placeholder+
+					strings.Repeat(" ", int(item.Pos-prev.Pos)-len(fn.placeholder)))
+			}
+			allParens = append(allParens, p)
+			open = append(open, p)
+		case pql.RIGHT_PAREN:
+			if len(open) > 0 {
+				open[len(open)-1].close = item.Pos
+				open = open[:len(open)-1]
+			}
+		}
+
+		prev = item
+	}
+
+	if len(calls) == 0 {
+		return pql.ParseExpr(query)
+	}
+
+	expr, err := pql.ParseExpr(string(rewritten))
+	if err != nil {
+		return nil, restoreParseErrors(err, query, calls, allParens)
+	}
+
+	pql.Inspect(expr, func(node pql.Node, _ []pql.Node) error {
+		if call, ok := node.(*pql.Call); ok {
+			if fn, ok := calls[call.PosRange.Start]; ok {
+				call.Func = &fn.Function
+			}
+		}
+
+		return nil
+	})
+
+	return expr, nil
+}
+
+// restoreParseErrors restores the query and the names of the functions in the
+// M3 function table in errors parsing the rewritten query. Errors in a call
+// are either at the start of the call or of one of its arguments.
+func restoreParseErrors(
+	err error,
+	query string,
+	calls map[pql.Pos]*function,
+	allParens []*parens,
+) error {
+	errs, ok := err.(pql.ParseErrors)
+	if !ok {
+		return err
+	}
+
+	restored := make(pql.ParseErrors, 0, len(errs))
+	for _, e := range errs {
+		e.Query = query
+		pos := e.PositionRange.Start
+		fn, ok := calls[pos]
+		if !ok {
+			var innermost *parens
+			for _, p := range allParens {
+				if p.open < pos && pos < p.close &&
+					(innermost == nil || p.open > innermost.open) {
+					innermost = p
+				}
+			}
+
+			if innermost != nil {
+				fn = innermost.fn
+			}
+		}
+
+		if fn != nil {
+			e.Err = errors.New(strings.Replace(e.Err.Error(),
+				fmt.Sprintf("%q", fn.placeholder),
+				fmt.Sprintf("%q", fn.Name), -1))
+		}
+
+		restored = append(restored, e)
+	}
+
+	return restored
+}
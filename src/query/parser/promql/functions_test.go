@@ -0,0 +1,114 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package promql
+
+import (
+	"testing"
+
+	"github.com/m3db/m3/src/query/functions/linear"
+	"github.com/m3db/m3/src/query/functions/temporal"
+
+	pql "github.com/prometheus/prometheus/promql/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseExprM3Functions(t *testing.T) {
+	tests := []struct {
+		query    string
+		name     string
+		expected string
+	}{
+		{
+			query:    "present_over_time(foo[5m])",
+			name:     temporal.PresentType,
+			expected: "present_over_time(foo[5m])",
+		},
+		{
+			query:    "sum(mad_over_time (foo[5m]))",
+			name:     temporal.MadType,
+			expected: "sum(mad_over_time(foo[5m]))",
+		},
+		{
+			query:    "histogram_sum(foo) / histogram_count(bar)",
+			name:     linear.HistogramSumType,
+			expected: "histogram_sum(foo) / histogram_count(bar)",
+		},
+		{
+			query:    "histogram_count(rate(foo[5m]))",
+			name:     linear.HistogramCountType,
+			expected: "histogram_count(rate(foo[5m]))",
+		},
+		{
+			query:    "histogram_stddev(rate(foo[5m]))",
+			name:     linear.HistogramStdDevType,
+			expected: "histogram_stddev(rate(foo[5m]))",
+		},
+		{
+			query:    "histogram_stdvar(foo) / 2",
+			name:     linear.HistogramStdVarType,
+			expected: "histogram_stdvar(foo) / 2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			expr, err := ParseExpr(tt.query)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, expr.String())
+
+			var calls []*pql.Call
+			pql.Inspect(expr, func(node pql.Node, _ []pql.Node) error {
+				if call, ok := node.(*pql.Call); ok && call.Func.Name == tt.name {
+					calls = append(calls, call)
+				}
+				return nil
+			})
+			require.Equal(t, 1, len(calls))
+			assert.Equal(t, tt.name,
+				tt.query[calls[0].PosRange.Start:calls[0].PosRange.Start+pql.Pos(len(tt.name))])
+
+			// NB: the functions are not registered with the Prometheus parser.
+			_, err = pql.ParseExpr(tt.query)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestParseExprM3FunctionsMetricName(t *testing.T) {
+	expr, err := ParseExpr(`present_over_time{a="mad_over_time("}`)
+	require.NoError(t, err)
+	assert.Equal(t, `present_over_time{a="mad_over_time("}`, expr.String())
+}
+
+func TestParseExprM3FunctionsError(t *testing.T) {
+	_, err := ParseExpr("1 + present_over_time(foo)")
+	require.Error(t, err)
+	assert.Equal(t, "1:23: parse error: expected type range vector in call "+
+		`to function "present_over_time", got instant vector`, err.Error())
+}
+
+func TestParseExprM3FunctionsErrorInPlaceholderCall(t *testing.T) {
+	_, err := ParseExpr("present_over_time(foo[5m]) + changes(foo)")
+	require.Error(t, err)
+	assert.Equal(t, "1:38: parse error: expected type range vector in call "+
+		`to function "changes", got instant vector`, err.Error())
+}
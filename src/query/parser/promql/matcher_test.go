@@ -160,3 +160,19 @@ func TestSanitizeRegex(t *testing.T) {
 		assert.NoError(t, err)
 	}
 }
+
+func TestAbsentTags(t *testing.T) {
+	matchers := []*labels.Matcher{
+		labels.MustNewMatcher(labels.MatchEqual, labels.MetricName, "up"),
+		labels.MustNewMatcher(labels.MatchEqual, "job", "a"),
+		labels.MustNewMatcher(labels.MatchEqual, "handler", "/foo"),
+		labels.MustNewMatcher(labels.MatchEqual, "handler", "/bar"),
+		labels.MustNewMatcher(labels.MatchEqual, "instance", "x"),
+		labels.MustNewMatcher(labels.MatchRegexp, "instance", "y"),
+		labels.MustNewMatcher(labels.MatchNotEqual, "path", "/baz"),
+	}
+
+	assert.Equal(t, []models.Tag{
+		{Name: []byte("job"), Value: []byte("a")},
+	}, absentTags(matchers))
+}
@@ -22,6 +22,7 @@ package promql
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/m3db/m3/src/query/block"
 	"github.com/m3db/m3/src/query/functions"
@@ -35,6 +36,7 @@ import (
 	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/query/parser"
 	"github.com/m3db/m3/src/query/parser/common"
+	xtime "github.com/m3db/m3/src/x/time"
 
 	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/pkg/labels"
@@ -55,6 +57,7 @@ func NewSelectorFromVector(
 		Name:     n.Name,
 		Offset:   n.Offset,
 		Matchers: matchers,
+		At:       newFetchAt(n),
 	}, nil
 }
 
@@ -74,9 +77,33 @@ func NewSelectorFromMatrix(
 		Offset:   vectorSelector.Offset,
 		Matchers: matchers,
 		Range:    n.Range,
+		At:       newFetchAt(vectorSelector),
 	}, nil
 }
 
+// isAtSelector returns true if the selector is evaluated at a time set by the
+// @ modifier.
+func isAtSelector(n *promql.VectorSelector) bool {
+	return n.Timestamp != nil ||
+		n.StartOrEnd == promql.START ||
+		n.StartOrEnd == promql.END
+}
+
+func newFetchAt(n *promql.VectorSelector) *functions.FetchAt {
+	switch {
+	case n.StartOrEnd == promql.START:
+		return &functions.FetchAt{Start: true}
+	case n.StartOrEnd == promql.END:
+		return &functions.FetchAt{End: true}
+	case n.Timestamp != nil:
+		return &functions.FetchAt{
+			Timestamp: xtime.UnixNano(*n.Timestamp * int64(time.Millisecond)),
+		}
+	}
+
+	return nil
+}
+
 // NewAggregationOperator creates a new aggregation operator based on the type.
 func NewAggregationOperator(expr *promql.AggregateExpr) (parser.Params, error) {
 	opType := expr.Op
@@ -162,6 +189,8 @@ func getAggOpType(opType promql.ItemType) string {
 		return aggregation.StandardVarianceType
 	case promql.COUNT:
 		return aggregation.CountType
+	case promql.GROUP:
+		return aggregation.GroupType
 
 	case promql.TOPK:
 		return aggregation.TopKType
@@ -235,7 +264,7 @@ func NewFunctionExpr(
 	switch name {
 	case linear.AbsType, linear.CeilType, linear.ExpType,
 		linear.FloorType, linear.LnType, linear.Log10Type,
-		linear.Log2Type, linear.SqrtType:
+		linear.Log2Type, linear.SqrtType, linear.SgnType:
 		p, err = linear.NewMathOp(name)
 		return p, true, err
 
@@ -243,7 +272,7 @@ func NewFunctionExpr(
 		p = aggregation.NewAbsentOp()
 		return p, true, err
 
-	case linear.ClampMinType, linear.ClampMaxType:
+	case linear.ClampMinType, linear.ClampMaxType, linear.ClampType:
 		p, err = linear.NewClampOp(argValues, name)
 		return p, true, err
 
@@ -251,6 +280,14 @@ func NewFunctionExpr(
 		p, err = linear.NewHistogramQuantileOp(argValues, name)
 		return p, true, err
 
+	case linear.HistogramCountType, linear.HistogramSumType:
+		p, err = linear.NewHistogramFieldOp(name)
+		return p, true, err
+
+	case linear.HistogramStdDevType, linear.HistogramStdVarType:
+		p, err = linear.NewHistogramStdVarOp(name)
+		return p, true, err
+
 	case linear.RoundType:
 		p, err = linear.NewRoundOp(argValues)
 		return p, true, err
@@ -261,7 +298,8 @@ func NewFunctionExpr(
 
 	case temporal.AvgType, temporal.CountType, temporal.MinType,
 		temporal.MaxType, temporal.SumType, temporal.StdDevType,
-		temporal.StdVarType:
+		temporal.StdVarType, temporal.LastType, temporal.PresentType,
+		temporal.MadType:
 		p, err = temporal.NewAggOp(argValues, name)
 		return p, true, err
 
@@ -520,3 +558,25 @@ func promMatchingToM3(
 		}
 	}
 }
+
+// absentTags returns the tags of the series returned when no series match the
+// given matchers, as with Prometheus these are the labels other than the metric
+// name with a single equality matcher and no other matchers.
+func absentTags(matchers []*labels.Matcher) []models.Tag {
+	counts := make(map[string]int, len(matchers))
+	for _, m := range matchers {
+		counts[m.Name]++
+	}
+
+	tags := make([]models.Tag, 0, len(matchers))
+	for _, m := range matchers {
+		if m.Name == labels.MetricName || m.Type != labels.MatchEqual ||
+			counts[m.Name] > 1 {
+			continue
+		}
+
+		tags = append(tags, models.Tag{Name: []byte(m.Name), Value: []byte(m.Value)})
+	}
+
+	return tags
+}
@@ -47,7 +47,7 @@ type ParseFunctionExpr func(
 type ParseFn func(query string) (pql.Expr, error)
 
 func defaultParseFn(query string) (pql.Expr, error) {
-	return pql.ParseExpr(query)
+	return ParseExpr(query)
 }
 
 // MetricSelectorFn is a function that parses a query to Prometheus selectors.
@@ -87,6 +87,13 @@ type ParseOptions interface {
 	RequireStartEndTime() bool
 	// SetRequireStartEndTime sets whether requests require a start and end time.
 	SetRequireStartEndTime(bool) ParseOptions
+
+	// TileAverages returns whether avg_over_time is evaluated as the sum over
+	// time divided by the count over time of downsampled tiles.
+	TileAverages() bool
+	// SetTileAverages sets whether avg_over_time is evaluated as the sum over
+	// time divided by the count over time of downsampled tiles.
+	SetTileAverages(bool) ParseOptions
 }
 
 type parseOptions struct {
@@ -95,6 +102,7 @@ type parseOptions struct {
 	fnParseExpr         ParseFunctionExpr
 	nowFn               xclock.NowFn
 	requireStartEndTime bool
+	tileAverages        bool
 }
 
 // NewParseOptions creates a new parse options.
@@ -156,3 +164,13 @@ func (o *parseOptions) SetRequireStartEndTime(r bool) ParseOptions {
 	opts.requireStartEndTime = r
 	return &opts
 }
+
+func (o *parseOptions) TileAverages() bool {
+	return o.tileAverages
+}
+
+func (o *parseOptions) SetTileAverages(t bool) ParseOptions {
+	opts := *o
+	opts.tileAverages = t
+	return &opts
+}
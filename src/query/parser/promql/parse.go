@@ -27,9 +27,12 @@ import (
 	pql "github.com/prometheus/prometheus/promql/parser"
 
 	"github.com/m3db/m3/src/query/block"
+	"github.com/m3db/m3/src/query/functions"
+	"github.com/m3db/m3/src/query/functions/aggregation"
 	"github.com/m3db/m3/src/query/functions/binary"
 	"github.com/m3db/m3/src/query/functions/lazy"
 	"github.com/m3db/m3/src/query/functions/scalar"
+	"github.com/m3db/m3/src/query/functions/temporal"
 	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/query/parser"
 	xtime "github.com/m3db/m3/src/x/time"
@@ -40,6 +43,7 @@ type promParser struct {
 	expr              pql.Expr
 	tagOpts           models.TagOptions
 	parseFunctionExpr ParseFunctionExpr
+	tileAverages      bool
 }
 
 // Parse takes a promQL string and converts parses it into a DAG.
@@ -60,6 +64,7 @@ func Parse(
 		stepSize:          stepSize,
 		tagOpts:           tagOpts,
 		parseFunctionExpr: parseOptions.FunctionParseExpr(),
+		tileAverages:      parseOptions.TileAverages(),
 	}, nil
 }
 
@@ -68,6 +73,7 @@ func (p *promParser) DAG() (parser.Nodes, parser.Edges, error) {
 		stepSize:          p.stepSize,
 		tagOpts:           p.tagOpts,
 		parseFunctionExpr: p.parseFunctionExpr,
+		tileAverages:      p.tileAverages,
 	}
 
 	err := state.walk(p.expr)
@@ -88,6 +94,10 @@ type parseState struct {
 	transforms        parser.Nodes
 	tagOpts           models.TagOptions
 	parseFunctionExpr ParseFunctionExpr
+	tileAverages      bool
+	// functionHint is the function over time applied to the range of the
+	// matrix selector being walked.
+	functionHint string
 }
 
 func (p *parseState) lastTransformID() parser.NodeID {
@@ -102,6 +112,15 @@ func (p *parseState) transformLen() int {
 	return len(p.transforms)
 }
 
+func (p *parseState) addTransform(op parser.Params) {
+	opTransform := parser.NewTransformFromOperation(op, p.transformLen())
+	p.edges = append(p.edges, parser.Edge{
+		ParentID: p.lastTransformID(),
+		ChildID:  opTransform.ID,
+	})
+	p.transforms = append(p.transforms, opTransform)
+}
+
 func (p *parseState) addLazyUnaryTransform(unaryOp string) error {
 	// NB: if unary type is "+", we do not apply any offsets.
 	if unaryOp == binary.PlusType {
@@ -127,11 +146,9 @@ func (p *parseState) addLazyUnaryTransform(unaryOp string) error {
 }
 
 func (p *parseState) addLazyOffsetTransform(offset time.Duration) error {
-	// NB: if offset is <= 0, we do not apply any offsets.
+	// NB: if offset is 0, we do not apply any offsets.
 	if offset == 0 {
 		return nil
-	} else if offset < 0 {
-		return fmt.Errorf("offset must be positive, received: %v", offset)
 	}
 
 	var (
@@ -170,9 +187,85 @@ func adjustOffset(offset time.Duration, step time.Duration) time.Duration {
 
 	// NB: Prometheus rounds offsets up to step size, e.g. a 61 second offset with
 	// a 1 minute stepsize gets rounded to a 2 minute offset.
+	if align < 0 {
+		// NB: negative offsets are rounded away from zero the same way, e.g. a
+		// -61 second offset gets rounded to a -2 minute offset.
+		return offset - step - align
+	}
+
 	return offset + step - align
 }
 
+// tileAverageExpr returns avg_over_time as the sum over time divided by the
+// count over time of the same range, which are fetched separately so that the
+// average over downsampled tiles is weighted by the samples of each tile.
+func tileAverageExpr(n *pql.Call) (pql.Expr, bool) {
+	if len(n.Args) != 1 {
+		return nil, false
+	}
+
+	matrix, ok := unwrapParenExpr(n.Args[0]).(*pql.MatrixSelector)
+	if !ok {
+		return nil, false
+	}
+
+	var (
+		countMatrix   = *matrix
+		countSelector = *matrix.VectorSelector.(*pql.VectorSelector)
+	)
+	countMatrix.VectorSelector = &countSelector
+	return &pql.BinaryExpr{
+		Op: pql.DIV,
+		LHS: &pql.Call{
+			Func:     pql.Functions[temporal.SumType],
+			Args:     pql.Expressions{matrix},
+			PosRange: n.PosRange,
+		},
+		RHS: &pql.Call{
+			Func:     pql.Functions[temporal.CountType],
+			Args:     pql.Expressions{&countMatrix},
+			PosRange: n.PosRange,
+		},
+		VectorMatching: &pql.VectorMatching{Card: pql.CardOneToOne},
+	}, true
+}
+
+// walkAbsentOverTime walks absent_over_time, which is evaluated as the absent
+// aggregation of present_over_time over the same range.
+func (p *parseState) walkAbsentOverTime(n *pql.Call) error {
+	if len(n.Args) != 1 {
+		return fmt.Errorf(
+			"%s operation must be called with 1 argument, got %d",
+			temporal.AbsentType, len(n.Args),
+		)
+	}
+
+	matrix, ok := unwrapParenExpr(n.Args[0]).(*pql.MatrixSelector)
+	if !ok {
+		return fmt.Errorf("%s operation must be called with a range vector, "+
+			"got %s", temporal.AbsentType, n.Args[0].String())
+	}
+
+	if err := p.walk(matrix); err != nil {
+		return err
+	}
+
+	present, err := temporal.NewAggOp(
+		[]interface{}{matrix.Range}, temporal.PresentType)
+	if err != nil {
+		return err
+	}
+
+	p.addTransform(present)
+	if isAtSelector(matrix.VectorSelector.(*pql.VectorSelector)) {
+		p.addTransform(functions.NewStepInvariantOp())
+	}
+
+	tags := absentTags(matrix.VectorSelector.(*pql.VectorSelector).LabelMatchers)
+	p.addTransform(aggregation.NewAbsentOpWithTags(tags))
+	return nil
+}
+
 func (p *parseState) walk(node pql.Node) error {
 	if node == nil {
 		return nil
@@ -202,21 +295,48 @@ func (p *parseState) walk(node pql.Node) error {
 	case *pql.MatrixSelector:
 		// Align offset to stepSize.
 		vectorSelector := n.VectorSelector.(*pql.VectorSelector)
-		vectorSelector.Offset = adjustOffset(vectorSelector.OriginalOffset, p.stepSize)
+		isAt := isAtSelector(vectorSelector)
+		if isAt {
+			// NB: selectors evaluated at a time set by the @ modifier are
+			// fetched at the exact offset from that time and re-timed by the
+			// fetch itself.
+			vectorSelector.Offset = vectorSelector.OriginalOffset
+		} else {
+			vectorSelector.Offset = adjustOffset(vectorSelector.OriginalOffset, p.stepSize)
+		}
+
 		operation, err := NewSelectorFromMatrix(n, p.tagOpts)
 		if err != nil {
 			return err
 		}
 
+		if fetch, ok := operation.(functions.FetchOp); ok {
+			fetch.FunctionHint = p.functionHint
+			operation = fetch
+		}
+
 		p.transforms = append(
 			p.transforms,
 			parser.NewTransformFromOperation(operation, p.transformLen()),
 		)
+
+		if isAt {
+			// NB: the step invariant transform follows the function over the
+			// range instead.
+			return nil
+		}
+
 		return p.addLazyOffsetTransform(vectorSelector.OriginalOffset)
 
 	case *pql.VectorSelector:
 		// Align offset to stepSize.
-		n.Offset = adjustOffset(n.OriginalOffset, p.stepSize)
+		isAt := isAtSelector(n)
+		if isAt {
+			n.Offset = n.OriginalOffset
+		} else {
+			n.Offset = adjustOffset(n.OriginalOffset, p.stepSize)
+		}
+
 		operation, err := NewSelectorFromVector(n, p.tagOpts)
 		if err != nil {
 			return err
@@ -227,6 +347,11 @@ func (p *parseState) walk(node pql.Node) error {
 			parser.NewTransformFromOperation(operation, p.transformLen()),
 		)
 
+		if isAt {
+			p.addTransform(functions.NewStepInvariantOp())
+			return nil
+		}
+
 		return p.addLazyOffsetTransform(n.OriginalOffset)
 
 	case *pql.Call:
@@ -253,8 +378,23 @@ func (p *parseState) walk(node pql.Node) error {
 			return nil
 		}
 
+		if n.Func.Name == temporal.AbsentType {
+			return p.walkAbsentOverTime(n)
+		}
+
+		if n.Func.Name == temporal.AvgType && p.tileAverages {
+			if expr, ok := tileAverageExpr(n); ok {
+				return p.walk(expr)
+			}
+		}
+
+		var hasAtMatrix bool
 		for i, expr := range n.Args {
 			n.Args[i] = unwrapParenExpr(expr)
+			if m, ok := n.Args[i].(*pql.MatrixSelector); ok {
+				hasAtMatrix = hasAtMatrix ||
+					isAtSelector(m.VectorSelector.(*pql.VectorSelector))
+			}
 		}
 
 		var (
@@ -303,9 +443,12 @@ func (p *parseState) walk(node pql.Node) error {
 			} else {
 				if e, ok := expr.(*pql.MatrixSelector); ok {
 					argValues = append(argValues, e.Range)
+					p.functionHint = n.Func.Name
 				}
 
-				if err := p.walk(expr); err != nil {
+				err := p.walk(expr)
+				p.functionHint = ""
+				if err != nil {
 					return err
 				}
 			}
@@ -347,6 +490,12 @@ func (p *parseState) walk(node pql.Node) error {
 		}
 
 		p.transforms = append(p.transforms, opTransform)
+		if hasAtMatrix {
+			// NB: functions over a range evaluated at a time set by the @
+			// modifier have the same value at every step.
+			p.addTransform(functions.NewStepInvariantOp())
+		}
+
 		return nil
 
 	case *pql.BinaryExpr:
@@ -22,6 +22,7 @@ package promql
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -35,6 +36,7 @@ import (
 	"github.com/m3db/m3/src/query/functions/temporal"
 	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/query/parser"
+	xtime "github.com/m3db/m3/src/x/time"
 
 	pql "github.com/prometheus/prometheus/promql/parser"
 	"github.com/stretchr/testify/assert"
@@ -77,10 +79,150 @@ func TestDAGWithOffset(t *testing.T) {
 		"offset should be the child")
 }
 
-func TestInvalidOffset(t *testing.T) {
+func TestDAGWithNegativeOffset(t *testing.T) {
 	q := "up offset -2m"
-	_, err := Parse(q, time.Second, models.NewTagOptions(), NewParseOptions())
+	p, err := Parse(q, time.Second, models.NewTagOptions(), NewParseOptions())
+	require.NoError(t, err)
+	transforms, edges, err := p.DAG()
+	require.NoError(t, err)
+	require.Len(t, transforms, 2)
+	fetch, ok := transforms[0].Op.(functions.FetchOp)
+	require.True(t, ok)
+	assert.Equal(t, -2*time.Minute, fetch.Offset)
+	assert.Equal(t, transforms[1].Op.OpType(), lazy.OffsetType)
+	assert.Len(t, edges, 1)
+}
+
+func TestAdjustOffset(t *testing.T) {
+	assert.Equal(t, time.Duration(0), adjustOffset(0, time.Minute))
+	assert.Equal(t, 2*time.Minute, adjustOffset(2*time.Minute, time.Minute))
+	assert.Equal(t, 2*time.Minute, adjustOffset(61*time.Second, time.Minute))
+	assert.Equal(t, -2*time.Minute, adjustOffset(-2*time.Minute, time.Minute))
+	assert.Equal(t, -2*time.Minute, adjustOffset(-61*time.Second, time.Minute))
+}
+
+var atModifierTests = []struct {
+	q             string
+	expectedTypes []string
+	expectedAt    functions.FetchAt
+}{
+	{
+		q:             "up @ 100",
+		expectedTypes: []string{functions.FetchType, functions.StepInvariantType},
+		expectedAt:    functions.FetchAt{Timestamp: xtime.UnixNano(100 * time.Second)},
+	},
+	{
+		q:             "up @ start()",
+		expectedTypes: []string{functions.FetchType, functions.StepInvariantType},
+		expectedAt:    functions.FetchAt{Start: true},
+	},
+	{
+		q: "rate(up[5m] @ end())",
+		expectedTypes: []string{functions.FetchType, temporal.RateType,
+			functions.StepInvariantType},
+		expectedAt: functions.FetchAt{End: true},
+	},
+	{
+		q: "sum(rate(up[5m] @ 100 offset 61s))",
+		expectedTypes: []string{functions.FetchType, temporal.RateType,
+			functions.StepInvariantType, aggregation.SumType},
+		expectedAt: functions.FetchAt{Timestamp: xtime.UnixNano(100 * time.Second)},
+	},
+}
+
+func TestAtModifierParses(t *testing.T) {
+	for _, tt := range atModifierTests {
+		t.Run(tt.q, func(t *testing.T) {
+			p, err := Parse(tt.q, time.Minute, models.NewTagOptions(), NewParseOptions())
+			require.NoError(t, err)
+			transforms, edges, err := p.DAG()
+			require.NoError(t, err)
+			require.Len(t, transforms, len(tt.expectedTypes))
+			for i, expected := range tt.expectedTypes {
+				assert.Equal(t, expected, transforms[i].Op.OpType())
+			}
+
+			require.Len(t, edges, len(tt.expectedTypes)-1)
+			for i, edge := range edges {
+				assert.Equal(t, transforms[i].ID, edge.ParentID)
+				assert.Equal(t, transforms[i+1].ID, edge.ChildID)
+			}
+
+			fetch, ok := transforms[0].Op.(functions.FetchOp)
+			require.True(t, ok)
+			require.NotNil(t, fetch.At)
+			assert.Equal(t, tt.expectedAt, *fetch.At)
+			if strings.Contains(tt.q, "offset") {
+				// NB: offsets from the @ modifier are not rounded to the step.
+				assert.Equal(t, 61*time.Second, fetch.Offset)
+			}
+		})
+	}
+}
+
+func TestAbsentOverTimeParses(t *testing.T) {
+	p, err := Parse("absent_over_time(up[5m])", time.Second,
+		models.NewTagOptions(), NewParseOptions())
+	require.NoError(t, err)
+	transforms, edges, err := p.DAG()
+	require.NoError(t, err)
+	require.Len(t, transforms, 3)
+	assert.Equal(t, functions.FetchType, transforms[0].Op.OpType())
+	assert.Equal(t, temporal.PresentType, transforms[1].Op.OpType())
+	assert.Equal(t, aggregation.AbsentType, transforms[2].Op.OpType())
+	require.Len(t, edges, 2)
+	assert.Equal(t, transforms[0].ID, edges[0].ParentID)
+	assert.Equal(t, transforms[1].ID, edges[0].ChildID)
+	assert.Equal(t, transforms[1].ID, edges[1].ParentID)
+	assert.Equal(t, transforms[2].ID, edges[1].ChildID)
+}
+
+func TestFunctionHintParses(t *testing.T) {
+	p, err := Parse("sum_over_time(up[5m]) + up", time.Second,
+		models.NewTagOptions(), NewParseOptions())
+	require.NoError(t, err)
+	transforms, _, err := p.DAG()
+	require.NoError(t, err)
+	require.Len(t, transforms, 4)
+
+	fetch, ok := transforms[0].Op.(functions.FetchOp)
+	require.True(t, ok)
+	assert.Equal(t, temporal.SumType, fetch.FunctionHint)
+	assert.Equal(t, temporal.SumType, transforms[1].Op.OpType())
+
+	fetch, ok = transforms[2].Op.(functions.FetchOp)
+	require.True(t, ok)
+	assert.Equal(t, "", fetch.FunctionHint)
+}
+
+func TestTileAveragesParses(t *testing.T) {
+	p, err := Parse("avg_over_time(up[5m])", time.Second,
+		models.NewTagOptions(), NewParseOptions().SetTileAverages(true))
+	require.NoError(t, err)
+	transforms, edges, err := p.DAG()
+	require.NoError(t, err)
+	require.Len(t, transforms, 5)
+
+	for i, expected := range []string{temporal.SumType, temporal.CountType} {
+		fetch, ok := transforms[2*i].Op.(functions.FetchOp)
+		require.True(t, ok)
+		assert.Equal(t, expected, fetch.FunctionHint)
+		assert.Equal(t, 5*time.Minute, fetch.Range)
+		assert.Equal(t, expected, transforms[2*i+1].Op.OpType())
+	}
+
+	assert.Equal(t, binary.DivType, transforms[4].Op.OpType())
+	require.Len(t, edges, 4)
+	assert.Equal(t, transforms[1].ID, edges[2].ParentID)
+	assert.Equal(t, transforms[3].ID, edges[3].ParentID)
+
+	p, err = Parse("avg_over_time(up[5m])", time.Second,
+		models.NewTagOptions(), NewParseOptions())
+	require.NoError(t, err)
+	transforms, _, err = p.DAG()
 	require.NoError(t, err)
+	require.Len(t, transforms, 2)
+	assert.Equal(t, temporal.AvgType, transforms[1].Op.OpType())
 }
 
 func TestNegativeUnary(t *testing.T) {
@@ -149,6 +291,7 @@ var aggregateParseTests = []struct {
 	{"stddev(up)", aggregation.StandardDeviationType},
 	{"stdvar(up)", aggregation.StandardVarianceType},
 	{"count(up)", aggregation.CountType},
+	{"group(up)", aggregation.GroupType},
 
 	{"topk(3, up)", aggregation.TopKType},
 	{"bottomk(3, up)", aggregation.BottomKType},
@@ -215,12 +358,14 @@ var linearParseTests = []struct {
 	{"ceil(up)", linear.CeilType},
 	{"clamp_min(up, 1)", linear.ClampMinType},
 	{"clamp_max(up, 1)", linear.ClampMaxType},
+	{"clamp(up, 1, 2)", linear.ClampType},
 	{"exp(up)", linear.ExpType},
 	{"floor(up)", linear.FloorType},
 	{"ln(up)", linear.LnType},
 	{"log2(up)", linear.Log2Type},
 	{"log10(up)", linear.Log10Type},
 	{"sqrt(up)", linear.SqrtType},
+	{"sgn(up)", linear.SgnType},
 	{"round(up)", linear.RoundType},
 	{"round(up, 10)", linear.RoundType},
 
@@ -235,6 +380,10 @@ var linearParseTests = []struct {
 	{"year(up)", linear.YearType},
 
 	{"histogram_quantile(1,up)", linear.HistogramQuantileType},
+	{"histogram_count(up)", linear.HistogramCountType},
+	{"histogram_sum(up)", linear.HistogramSumType},
+	{"histogram_stddev(up)", linear.HistogramStdDevType},
+	{"histogram_stdvar(up)", linear.HistogramStdVarType},
 }
 
 func TestLinearParses(t *testing.T) {
@@ -469,6 +618,9 @@ var temporalParseTests = []struct {
 	{"sum_over_time(up[5m])", temporal.SumType},
 	{"stddev_over_time(up[5m])", temporal.StdDevType},
 	{"stdvar_over_time(up[5m])", temporal.StdVarType},
+	{"last_over_time(up[5m])", temporal.LastType},
+	{"present_over_time(up[5m])", temporal.PresentType},
+	{"mad_over_time(up[5m])", temporal.MadType},
 	{"quantile_over_time(0.2, up[5m])", temporal.QuantileType},
 	{"irate(up[5m])", temporal.IRateType},
 	{"idelta(up[5m])", temporal.IDeltaType},
@@ -65,10 +65,11 @@ func NewPhysicalPlan(
 		steps:    cloned.Steps,
 		pipeline: cloned.Pipeline,
 		TimeSpec: transform.TimeSpec{
-			Start: params.Start,
-			End:   params.ExclusiveEnd(),
-			Now:   params.Now,
-			Step:  params.Step,
+			Start:      params.Start,
+			End:        params.ExclusiveEnd(),
+			Now:        params.Now,
+			Step:       params.Step,
+			QueryStart: params.Start,
 		},
 		Debug:            params.Debug,
 		BlockType:        params.BlockType,
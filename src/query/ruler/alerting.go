@@ -0,0 +1,407 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ruler
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/m3db/m3/src/query/models"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/pkg/timestamp"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/template"
+	"go.uber.org/zap"
+)
+
+const (
+	// AlertsMetricName is the name of the series alert states are recorded
+	// under.
+	AlertsMetricName = "ALERTS"
+	// AlertNameLabel is the label holding the name of the alerting rule.
+	AlertNameLabel = "alertname"
+	// AlertStateLabel is the label holding the state of the alert.
+	AlertStateLabel = "alertstate"
+
+	// resolvedRetention is how long resolved alerts are kept so that they
+	// are reported as resolved to the notifier.
+	resolvedRetention = 15 * time.Minute
+
+	templatePreamble = "{{$labels := .Labels}}{{$externalLabels := .ExternalLabels}}" +
+		"{{$externalURL := .ExternalURL}}{{$value := .Value}}"
+)
+
+// AlertState is the state of an alert.
+type AlertState int
+
+const (
+	// StateInactive is the state of an alert that is no longer active.
+	StateInactive AlertState = iota
+	// StatePending is the state of an active alert that has not been active
+	// for the hold duration of its rule yet.
+	StatePending
+	// StateFiring is the state of an active alert that has been active for
+	// the hold duration of its rule.
+	StateFiring
+)
+
+func (s AlertState) String() string {
+	switch s {
+	case StateInactive:
+		return "inactive"
+	case StatePending:
+		return "pending"
+	case StateFiring:
+		return "firing"
+	}
+	return "unknown"
+}
+
+// Alert is an alert generated by an alerting rule.
+type Alert struct {
+	State       AlertState
+	Labels      models.Tags
+	Annotations map[string]string
+	// Value is the value of the series that caused the alert.
+	Value float64
+
+	ActiveAt   time.Time
+	FiredAt    time.Time
+	ResolvedAt time.Time
+	LastSentAt time.Time
+	ValidUntil time.Time
+}
+
+func (a *Alert) needsSending(t time.Time, resendDelay time.Duration) bool {
+	if a.State == StatePending {
+		return false
+	}
+
+	// Resolved alerts that have not been sent since being resolved are sent
+	// immediately.
+	if a.ResolvedAt.After(a.LastSentAt) {
+		return true
+	}
+
+	return a.LastSentAt.Add(resendDelay).Before(t)
+}
+
+// AlertingRule generates alerts from the series returned by its query.
+type AlertingRule struct {
+	ruleEvaluation
+
+	name         string
+	query        string
+	holdDuration time.Duration
+	labels       map[string]string
+	annotations  map[string]string
+
+	externalLabels map[string]string
+	externalURL    *url.URL
+	tagOpts        models.TagOptions
+	logger         *zap.Logger
+
+	alertsLock sync.RWMutex
+	active     map[uint64]*Alert
+}
+
+// NewAlertingRule returns a new alerting rule that alerts on every series
+// returned by its query for at least the hold duration.
+func NewAlertingRule(
+	name string,
+	query string,
+	holdDuration time.Duration,
+	labels map[string]string,
+	annotations map[string]string,
+	opts Options,
+) *AlertingRule {
+	return &AlertingRule{
+		ruleEvaluation: newRuleEvaluation(),
+		name:           name,
+		query:          query,
+		holdDuration:   holdDuration,
+		labels:         labels,
+		annotations:    annotations,
+		externalLabels: opts.ExternalLabels(),
+		externalURL:    opts.ExternalURL(),
+		tagOpts:        opts.TagOptions(),
+		logger:         opts.InstrumentOptions().Logger(),
+		active:         make(map[uint64]*Alert),
+	}
+}
+
+// Name returns the name of the alert.
+func (r *AlertingRule) Name() string {
+	return r.name
+}
+
+// Query returns the query of the rule.
+func (r *AlertingRule) Query() string {
+	return r.query
+}
+
+// HoldDuration returns how long a series must be returned by the query
+// before the alert fires.
+func (r *AlertingRule) HoldDuration() time.Duration {
+	return r.holdDuration
+}
+
+// Labels returns the labels added to alerts.
+func (r *AlertingRule) Labels() map[string]string {
+	return r.labels
+}
+
+// Annotations returns the annotation templates of the rule.
+func (r *AlertingRule) Annotations() map[string]string {
+	return r.annotations
+}
+
+// State returns the most severe state of the active alerts of the rule.
+func (r *AlertingRule) State() AlertState {
+	r.alertsLock.RLock()
+	defer r.alertsLock.RUnlock()
+
+	state := StateInactive
+	for _, a := range r.active {
+		if a.State > state {
+			state = a.State
+		}
+	}
+	return state
+}
+
+// ActiveAlerts returns copies of the pending and firing alerts of the rule,
+// ordered by their labels.
+func (r *AlertingRule) ActiveAlerts() []*Alert {
+	r.alertsLock.RLock()
+	alerts := make([]*Alert, 0, len(r.active))
+	for _, a := range r.active {
+		if a.State != StateInactive {
+			alert := *a
+			alerts = append(alerts, &alert)
+		}
+	}
+	r.alertsLock.RUnlock()
+
+	sort.Slice(alerts, func(i, j int) bool {
+		return alerts[i].Labels.String() < alerts[j].Labels.String()
+	})
+	return alerts
+}
+
+// Eval evaluates the rule, updating the state of its alerts and returning
+// the alert state series to record.
+func (r *AlertingRule) Eval(
+	ctx context.Context,
+	t time.Time,
+	query QueryFunc,
+) (Vector, error) {
+	vector, err := query(ctx, r.query, t)
+	if err != nil {
+		return nil, err
+	}
+
+	alerts := make(map[uint64]*Alert, len(vector))
+	for _, sample := range vector {
+		var (
+			tags           = sample.Tags.WithoutName()
+			templateLabels = tagsToMap(tags)
+			expand         = func(text string) string {
+				return r.expandTemplate(ctx, text, templateLabels,
+					sample.Value, t, query)
+			}
+		)
+
+		for _, name := range sortedKeys(r.labels) {
+			tags = tags.AddOrUpdateTag(models.Tag{
+				Name:  []byte(name),
+				Value: []byte(expand(r.labels[name])),
+			})
+		}
+		tags = tags.AddOrUpdateTag(models.Tag{
+			Name:  []byte(AlertNameLabel),
+			Value: []byte(r.name),
+		})
+
+		annotations := make(map[string]string, len(r.annotations))
+		for name, text := range r.annotations {
+			annotations[name] = expand(text)
+		}
+
+		id := tags.HashedID()
+		if _, ok := alerts[id]; ok {
+			return nil, fmt.Errorf(
+				"alerting rule %s: vector contains series with the same tags "+
+					"after applying rule labels", r.name)
+		}
+
+		alerts[id] = &Alert{
+			State:       StatePending,
+			Labels:      tags,
+			Annotations: annotations,
+			Value:       sample.Value,
+			ActiveAt:    t,
+		}
+	}
+
+	r.alertsLock.Lock()
+	defer r.alertsLock.Unlock()
+
+	for id, a := range alerts {
+		// Alerts that are already active keep their state and only have their
+		// value and annotations updated.
+		if existing, ok := r.active[id]; ok && existing.State != StateInactive {
+			existing.Value = a.Value
+			existing.Annotations = a.Annotations
+			continue
+		}
+		r.active[id] = a
+	}
+
+	var result Vector
+	for id, a := range r.active {
+		if _, ok := alerts[id]; !ok {
+			// Firing alerts that resolve are kept for a while so that they are
+			// reported as resolved to the notifier.
+			if a.State == StatePending ||
+				(!a.ResolvedAt.IsZero() && t.Sub(a.ResolvedAt) > resolvedRetention) {
+				delete(r.active, id)
+			}
+			if a.State != StateInactive {
+				a.State = StateInactive
+				a.ResolvedAt = t
+			}
+			continue
+		}
+
+		if a.State == StatePending && t.Sub(a.ActiveAt) >= r.holdDuration {
+			a.State = StateFiring
+			a.FiredAt = t
+		}
+
+		result = append(result, r.alertSample(a))
+	}
+
+	return result, nil
+}
+
+// alertsToSend returns copies of the alerts that need to be sent to the
+// notifier at the given time, marking them as sent.
+func (r *AlertingRule) alertsToSend(
+	t time.Time,
+	resendDelay time.Duration,
+	interval time.Duration,
+) []*Alert {
+	r.alertsLock.Lock()
+	defer r.alertsLock.Unlock()
+
+	delta := resendDelay
+	if interval > delta {
+		delta = interval
+	}
+
+	var alerts []*Alert
+	for _, a := range r.active {
+		if !a.needsSending(t, resendDelay) {
+			continue
+		}
+
+		a.LastSentAt = t
+		// Alerts are valid until a few evaluations are missed so that the
+		// receiver resolves them if evaluation stops.
+		a.ValidUntil = t.Add(4 * delta)
+		alert := *a
+		alerts = append(alerts, &alert)
+	}
+	return alerts
+}
+
+func (r *AlertingRule) alertSample(a *Alert) Sample {
+	tags := a.Labels.Clone().
+		AddOrUpdateTag(models.Tag{
+			Name:  r.tagOpts.MetricName(),
+			Value: []byte(AlertsMetricName),
+		}).
+		AddOrUpdateTag(models.Tag{
+			Name:  []byte(AlertStateLabel),
+			Value: []byte(a.State.String()),
+		})
+	return Sample{Tags: tags, Value: 1}
+}
+
+func (r *AlertingRule) expandTemplate(
+	ctx context.Context,
+	text string,
+	labels map[string]string,
+	value float64,
+	t time.Time,
+	query QueryFunc,
+) string {
+	var externalURL string
+	if r.externalURL != nil {
+		externalURL = r.externalURL.String()
+	}
+
+	data := template.AlertTemplateData(labels, r.externalLabels, externalURL, value)
+	expander := template.NewTemplateExpander(ctx, templatePreamble+text,
+		"__alert_"+r.name, data, model.TimeFromUnixNano(t.UnixNano()),
+		templateQueryFunc(query), r.externalURL)
+	result, err := expander.Expand()
+	if err != nil {
+		r.logger.Warn("could not expand alert template",
+			zap.String("alert", r.name), zap.Error(err))
+		return fmt.Sprintf("<error expanding template: %v>", err)
+	}
+	return result
+}
+
+// templateQueryFunc adapts a query function to the one used by templates.
+func templateQueryFunc(query QueryFunc) template.QueryFunc {
+	return func(ctx context.Context, q string, t time.Time) (promql.Vector, error) {
+		vector, err := query(ctx, q, t)
+		if err != nil {
+			return nil, err
+		}
+
+		result := make(promql.Vector, 0, len(vector))
+		for _, sample := range vector {
+			result = append(result, promql.Sample{
+				Point:  promql.Point{T: timestamp.FromTime(t), V: sample.Value},
+				Metric: labels.FromMap(tagsToMap(sample.Tags)),
+			})
+		}
+		return result, nil
+	}
+}
+
+func tagsToMap(tags models.Tags) map[string]string {
+	m := make(map[string]string, tags.Len())
+	for _, tag := range tags.Tags {
+		m[string(tag.Name)] = string(tag.Value)
+	}
+	return m
+}
@@ -0,0 +1,145 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ruler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlertingRuleEval(t *testing.T) {
+	var (
+		ctx     = context.Background()
+		start   = time.Unix(1600000000, 0)
+		results = map[string]Vector{
+			"up == 0": {
+				{Tags: newTestTags("__name__", "up", "instance", "a"), Value: 0},
+			},
+		}
+		query = newTestQueryFunc(results)
+		rule  = NewAlertingRule("InstanceDown", "up == 0", 2*time.Minute,
+			map[string]string{"severity": "page"},
+			map[string]string{"summary": "{{ $labels.instance }} is down ({{ $value }})"},
+			NewOptions())
+	)
+
+	// Alert is pending until it has been active for the hold duration.
+	vector, err := rule.Eval(ctx, start, query)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(vector))
+	assert.Equal(t,
+		"__name__: ALERTS, alertname: InstanceDown, alertstate: pending, "+
+			"instance: a, severity: page",
+		vector[0].Tags.String())
+	assert.Equal(t, 1.0, vector[0].Value)
+	assert.Equal(t, StatePending, rule.State())
+	assert.Equal(t, 0, len(rule.alertsToSend(start, time.Minute, time.Minute)))
+
+	alerts := rule.ActiveAlerts()
+	require.Equal(t, 1, len(alerts))
+	assert.Equal(t, map[string]string{"summary": "a is down (0)"},
+		alerts[0].Annotations)
+	assert.Equal(t, start, alerts[0].ActiveAt)
+
+	vector, err = rule.Eval(ctx, start.Add(2*time.Minute), query)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(vector))
+	assert.Equal(t,
+		"__name__: ALERTS, alertname: InstanceDown, alertstate: firing, "+
+			"instance: a, severity: page",
+		vector[0].Tags.String())
+	assert.Equal(t, StateFiring, rule.State())
+
+	// Firing alerts are sent, and resent after the resend delay.
+	sent := rule.alertsToSend(start.Add(2*time.Minute), time.Minute, time.Minute)
+	require.Equal(t, 1, len(sent))
+	assert.Equal(t, start.Add(2*time.Minute), sent[0].FiredAt)
+	assert.Equal(t, start.Add(6*time.Minute), sent[0].ValidUntil)
+	assert.Equal(t, 0, len(rule.alertsToSend(start.Add(3*time.Minute),
+		time.Minute, time.Minute)))
+	assert.Equal(t, 1, len(rule.alertsToSend(start.Add(4*time.Minute),
+		time.Minute, time.Minute)))
+
+	// Once the series disappears the alert resolves and is sent as resolved.
+	results["up == 0"] = nil
+	vector, err = rule.Eval(ctx, start.Add(5*time.Minute), query)
+	require.NoError(t, err)
+	assert.Equal(t, 0, len(vector))
+	assert.Equal(t, StateInactive, rule.State())
+	assert.Equal(t, 0, len(rule.ActiveAlerts()))
+
+	sent = rule.alertsToSend(start.Add(5*time.Minute), time.Minute, time.Minute)
+	require.Equal(t, 1, len(sent))
+	assert.Equal(t, StateInactive, sent[0].State)
+	assert.Equal(t, start.Add(5*time.Minute), sent[0].ResolvedAt)
+
+	// Resolved alerts are forgotten after the retention.
+	_, err = rule.Eval(ctx, start.Add(5*time.Minute+resolvedRetention+time.Second), query)
+	require.NoError(t, err)
+	assert.Equal(t, 0, len(rule.active))
+}
+
+func TestAlertingRulePendingAlertResets(t *testing.T) {
+	var (
+		ctx     = context.Background()
+		start   = time.Unix(1600000000, 0)
+		results = map[string]Vector{
+			"up == 0": {{Tags: newTestTags("instance", "a")}},
+		}
+		query = newTestQueryFunc(results)
+		rule  = NewAlertingRule("InstanceDown", "up == 0", 2*time.Minute,
+			nil, nil, NewOptions())
+	)
+
+	_, err := rule.Eval(ctx, start, query)
+	require.NoError(t, err)
+
+	results["up == 0"] = nil
+	_, err = rule.Eval(ctx, start.Add(time.Minute), query)
+	require.NoError(t, err)
+	assert.Equal(t, 0, len(rule.active))
+
+	results["up == 0"] = Vector{{Tags: newTestTags("instance", "a")}}
+	_, err = rule.Eval(ctx, start.Add(2*time.Minute), query)
+	require.NoError(t, err)
+
+	alerts := rule.ActiveAlerts()
+	require.Equal(t, 1, len(alerts))
+	assert.Equal(t, StatePending, alerts[0].State)
+	assert.Equal(t, start.Add(2*time.Minute), alerts[0].ActiveAt)
+}
+
+func TestAlertingRuleDuplicateLabels(t *testing.T) {
+	query := newTestQueryFunc(map[string]Vector{
+		"up == 0": {
+			{Tags: newTestTags("__name__", "up", "instance", "a")},
+			{Tags: newTestTags("__name__", "down", "instance", "a")},
+		},
+	})
+	rule := NewAlertingRule("InstanceDown", "up == 0", 0, nil, nil, NewOptions())
+
+	_, err := rule.Eval(context.Background(), time.Now(), query)
+	require.Error(t, err)
+}
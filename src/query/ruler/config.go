@@ -0,0 +1,215 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ruler
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	clusterclient "github.com/m3db/m3/src/cluster/client"
+	"github.com/m3db/m3/src/cluster/services"
+	"github.com/m3db/m3/src/cmd/services/m3coordinator/ingest"
+	"github.com/m3db/m3/src/query/executor"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/x/instrument"
+)
+
+// Configuration configures the evaluation of recording and alerting rules.
+type Configuration struct {
+	// RuleFiles are the paths, or glob patterns, of the Prometheus format
+	// rule files to load.
+	RuleFiles []string `yaml:"ruleFiles"`
+
+	// EvaluationInterval is the interval rule groups are evaluated at when
+	// they do not specify one.
+	EvaluationInterval *time.Duration `yaml:"evaluationInterval"`
+
+	// QueryTimeout is the timeout of rule queries, defaults to the
+	// evaluation interval of the group.
+	QueryTimeout *time.Duration `yaml:"queryTimeout"`
+
+	// ExternalLabels are added to alerts sent to Alertmanager and made
+	// available to alert templates.
+	ExternalLabels map[string]string `yaml:"externalLabels"`
+
+	// ExternalURL is the URL alerts link back to.
+	ExternalURL string `yaml:"externalURL"`
+
+	// Alertmanager configures where alerts are sent, alerts are only
+	// recorded if not set.
+	Alertmanager *AlertmanagerConfiguration `yaml:"alertmanager"`
+
+	// LeaderElection elects a single instance to evaluate the rules using
+	// the cluster management client, if not set every instance evaluates the
+	// rules, writing duplicate results and sending duplicate alerts.
+	LeaderElection *LeaderElectionConfiguration `yaml:"leaderElection"`
+}
+
+// LeaderElectionConfiguration configures the election of the instance
+// evaluating the rules.
+type LeaderElectionConfiguration struct {
+	// ServiceID is the service the election is held for.
+	ServiceID services.ServiceIDConfiguration `yaml:"serviceID"`
+
+	// ElectionID is the ID of the election, defaults to m3query-ruler.
+	ElectionID string `yaml:"electionID"`
+
+	// LeaderValue is the value the leader announces, defaults to the hostname.
+	LeaderValue string `yaml:"leaderValue"`
+
+	// Election configures the timeouts and TTL of the election.
+	Election services.ElectionConfiguration `yaml:"election"`
+}
+
+func (c LeaderElectionConfiguration) newOptions(
+	clusterClient clusterclient.Client,
+	opts Options,
+) (Options, error) {
+	if clusterClient == nil {
+		return nil, errors.New("rule leader election requires a cluster management client")
+	}
+
+	campaignOpts, err := services.NewCampaignOptions()
+	if err != nil {
+		return nil, err
+	}
+	if c.LeaderValue != "" {
+		campaignOpts = campaignOpts.SetLeaderValue(c.LeaderValue)
+	}
+
+	svcs, err := clusterClient.Services(services.NewOverrideOptions())
+	if err != nil {
+		return nil, err
+	}
+	leaderService, err := svcs.LeaderService(c.ServiceID.NewServiceID(),
+		c.Election.NewOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	opts = opts.
+		SetLeaderService(leaderService).
+		SetCampaignOptions(campaignOpts)
+	if c.ElectionID != "" {
+		opts = opts.SetElectionID(c.ElectionID)
+	}
+	return opts, nil
+}
+
+// AlertmanagerConfiguration configures sending alerts to Alertmanager.
+type AlertmanagerConfiguration struct {
+	// URLs are the Alertmanager compatible endpoints alerts are POSTed to,
+	// e.g. http://alertmanager:9093/api/v2/alerts.
+	URLs []string `yaml:"urls" validate:"nonzero"`
+
+	// Timeout is the timeout of requests to Alertmanager.
+	Timeout *time.Duration `yaml:"timeout"`
+
+	// ResendDelay is the minimum delay before a firing alert is resent.
+	ResendDelay *time.Duration `yaml:"resendDelay"`
+
+	// QueueCapacity is the number of alerts queued before the oldest alerts
+	// are dropped.
+	QueueCapacity int `yaml:"queueCapacity"`
+}
+
+// NewManager returns a new rule manager that evaluates rules with the given
+// engine and writes their results with the given writer, the cluster client
+// is only required for leader election.
+func (c Configuration) NewManager(
+	engine executor.Engine,
+	writer ingest.DownsamplerAndWriter,
+	clusterClient clusterclient.Client,
+	tagOpts models.TagOptions,
+	instrumentOpts instrument.Options,
+) (Manager, error) {
+	opts := NewOptions().
+		SetRuleFiles(c.RuleFiles).
+		SetWriter(writer).
+		SetTagOptions(tagOpts).
+		SetExternalLabels(c.ExternalLabels).
+		SetInstrumentOptions(instrumentOpts)
+	if c.EvaluationInterval != nil {
+		opts = opts.SetEvaluationInterval(*c.EvaluationInterval)
+	}
+
+	var timeout time.Duration
+	if c.QueryTimeout != nil {
+		timeout = *c.QueryTimeout
+	}
+	opts = opts.SetQueryFunc(NewEngineQueryFunc(engine, tagOpts, timeout))
+
+	if c.ExternalURL != "" {
+		externalURL, err := url.Parse(c.ExternalURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid external URL: %w", err)
+		}
+		opts = opts.SetExternalURL(externalURL)
+	}
+
+	if am := c.Alertmanager; am != nil {
+		if am.ResendDelay != nil {
+			opts = opts.SetResendDelay(*am.ResendDelay)
+		}
+
+		notifierOpts := NotifierOptions{
+			URLs:              am.URLs,
+			QueueCapacity:     am.QueueCapacity,
+			ExternalLabels:    c.ExternalLabels,
+			ExternalURL:       opts.ExternalURL(),
+			InstrumentOptions: instrumentOpts,
+		}
+		if am.Timeout != nil {
+			notifierOpts.Timeout = *am.Timeout
+		}
+
+		notifier, err := NewNotifier(notifierOpts)
+		if err != nil {
+			return nil, err
+		}
+		opts = opts.SetNotifier(notifier)
+	}
+
+	if le := c.LeaderElection; le != nil {
+		electionOpts, err := le.newOptions(clusterClient, opts)
+		if err != nil {
+			if notifier := opts.Notifier(); notifier != nil {
+				_ = notifier.Close()
+			}
+			return nil, fmt.Errorf("could not set up rule leader election: %w", err)
+		}
+		opts = electionOpts
+	}
+
+	manager, err := NewManager(opts)
+	if err != nil {
+		if leaderService := opts.LeaderService(); leaderService != nil {
+			_ = leaderService.Close()
+		}
+		if notifier := opts.Notifier(); notifier != nil {
+			_ = notifier.Close()
+		}
+		return nil, err
+	}
+	return manager, nil
+}
@@ -0,0 +1,220 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ruler
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/m3db/m3/src/cmd/services/m3coordinator/ingest"
+	"github.com/m3db/m3/src/query/ts"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/uber-go/tally"
+	"go.uber.org/zap"
+)
+
+type groupMetrics struct {
+	evaluations        tally.Counter
+	evaluationErrors   tally.Counter
+	writeErrors        tally.Counter
+	missedIterations   tally.Counter
+	evaluationDuration tally.Timer
+}
+
+func newGroupMetrics(scope tally.Scope) groupMetrics {
+	return groupMetrics{
+		evaluations:        scope.Counter("rule-evaluations"),
+		evaluationErrors:   scope.Counter("rule-evaluation-errors"),
+		writeErrors:        scope.Counter("write-errors"),
+		missedIterations:   scope.Counter("missed-iterations"),
+		evaluationDuration: scope.Timer("group-evaluation-duration"),
+	}
+}
+
+// Group is a set of rules evaluated sequentially on a shared interval.
+type Group struct {
+	name     string
+	file     string
+	interval time.Duration
+	rules    []Rule
+
+	queryFn     QueryFunc
+	writer      ingest.DownsamplerAndWriter
+	notifier    Notifier
+	resendDelay time.Duration
+	nowFn       func() time.Time
+	logger      *zap.Logger
+	metrics     groupMetrics
+
+	mu                 sync.RWMutex
+	lastEvaluation     time.Time
+	evaluationDuration time.Duration
+}
+
+// NewGroup returns a new rule group.
+func NewGroup(
+	name string,
+	file string,
+	interval time.Duration,
+	rules []Rule,
+	opts Options,
+) *Group {
+	iOpts := opts.InstrumentOptions()
+	scope := iOpts.MetricsScope().SubScope("ruler").
+		Tagged(map[string]string{"rule-group": name})
+	return &Group{
+		name:        name,
+		file:        file,
+		interval:    interval,
+		rules:       rules,
+		queryFn:     opts.QueryFunc(),
+		writer:      opts.Writer(),
+		notifier:    opts.Notifier(),
+		resendDelay: opts.ResendDelay(),
+		nowFn:       opts.ClockOptions().NowFn(),
+		logger: iOpts.Logger().With(
+			zap.String("group", name), zap.String("file", file)),
+		metrics: newGroupMetrics(scope),
+	}
+}
+
+// Name returns the name of the group.
+func (g *Group) Name() string {
+	return g.name
+}
+
+// File returns the file the group was loaded from.
+func (g *Group) File() string {
+	return g.file
+}
+
+// Interval returns the interval the group is evaluated at.
+func (g *Group) Interval() time.Duration {
+	return g.interval
+}
+
+// Rules returns the rules of the group.
+func (g *Group) Rules() []Rule {
+	return g.rules
+}
+
+// LastEvaluation returns the time the group was last evaluated.
+func (g *Group) LastEvaluation() time.Time {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.lastEvaluation
+}
+
+// EvaluationDuration returns how long the last evaluation of the group took.
+func (g *Group) EvaluationDuration() time.Duration {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.evaluationDuration
+}
+
+// Eval evaluates the rules of the group at the given time, writing their
+// results and sending any alerts that need to be sent.
+func (g *Group) Eval(ctx context.Context, t time.Time) {
+	start := g.nowFn()
+	for _, rule := range g.rules {
+		g.evalRule(ctx, t, rule)
+	}
+	duration := g.nowFn().Sub(start)
+
+	g.metrics.evaluationDuration.Record(duration)
+	g.mu.Lock()
+	g.lastEvaluation = t
+	g.evaluationDuration = duration
+	g.mu.Unlock()
+}
+
+func (g *Group) evalRule(ctx context.Context, t time.Time, rule Rule) {
+	g.metrics.evaluations.Inc(1)
+
+	start := g.nowFn()
+	vector, err := rule.Eval(ctx, t, g.queryFn)
+	rule.setEvaluationResult(t, g.nowFn().Sub(start), err)
+	if err != nil {
+		g.metrics.evaluationErrors.Inc(1)
+		g.logger.Warn("rule evaluation failed",
+			zap.String("rule", rule.Name()), zap.Error(err))
+		return
+	}
+
+	if alertingRule, ok := rule.(*AlertingRule); ok && g.notifier != nil {
+		alerts := alertingRule.alertsToSend(t, g.resendDelay, g.interval)
+		if len(alerts) > 0 {
+			g.notifier.Send(alerts...)
+		}
+	}
+
+	datapoints := ts.Datapoints{{Timestamp: xtime.ToUnixNano(t)}}
+	for _, sample := range vector {
+		datapoints[0].Value = sample.Value
+		err := g.writer.Write(ctx, sample.Tags, datapoints, xtime.Millisecond,
+			nil, ingest.WriteOptions{})
+		if err != nil {
+			g.metrics.writeErrors.Inc(1)
+			g.logger.Warn("could not write rule result",
+				zap.String("rule", rule.Name()),
+				zap.Stringer("series", sample.Tags),
+				zap.Error(err))
+		}
+	}
+}
+
+// run evaluates the group on its interval until done is closed, skipping
+// the evaluations when shouldEval returns false.
+func (g *Group) run(done <-chan struct{}, shouldEval func() bool) {
+	ticker := time.NewTicker(g.interval)
+	defer ticker.Stop()
+
+	for {
+		start := g.nowFn()
+		if !shouldEval() {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+			continue
+		}
+
+		g.evalWithTimeout(start)
+		if missed := g.nowFn().Sub(start) / g.interval; missed > 0 {
+			g.metrics.missedIterations.Inc(int64(missed))
+		}
+
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (g *Group) evalWithTimeout(t time.Time) {
+	ctx, cancel := context.WithTimeout(context.Background(), g.interval)
+	defer cancel()
+	g.Eval(ctx, t)
+}
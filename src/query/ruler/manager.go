@@ -0,0 +1,335 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ruler
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/m3db/m3/src/cluster/services/leader/campaign"
+	xerrors "github.com/m3db/m3/src/x/errors"
+
+	"github.com/prometheus/prometheus/pkg/rulefmt"
+	"github.com/uber-go/tally"
+	"go.uber.org/zap"
+)
+
+const campaignRetryInterval = 5 * time.Second
+
+var (
+	errManagerAlreadyStarted = errors.New("rule manager already started")
+	errManagerClosed         = errors.New("rule manager closed")
+)
+
+type managerMetrics struct {
+	leader         tally.Gauge
+	campaignErrors tally.Counter
+}
+
+func newManagerMetrics(scope tally.Scope) managerMetrics {
+	return managerMetrics{
+		leader:         scope.Gauge("leader"),
+		campaignErrors: scope.Counter("campaign-errors"),
+	}
+}
+
+type manager struct {
+	sync.Mutex
+
+	opts    Options
+	groups  []*Group
+	logger  *zap.Logger
+	metrics managerMetrics
+	started bool
+	closed  bool
+	done    chan struct{}
+	wg      sync.WaitGroup
+
+	// leader is set while the rule groups should be evaluated, which is
+	// always if there is no leader service.
+	leader int32
+}
+
+// NewManager returns a new rule manager that evaluates the rule groups
+// loaded from the rule files of the options.
+func NewManager(opts Options) (Manager, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	groups, err := loadGroups(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	iOpts := opts.InstrumentOptions()
+	m := &manager{
+		opts:    opts,
+		groups:  groups,
+		logger:  iOpts.Logger(),
+		metrics: newManagerMetrics(iOpts.MetricsScope().SubScope("ruler")),
+		done:    make(chan struct{}),
+	}
+	if opts.LeaderService() == nil {
+		m.leader = 1
+	}
+	return m, nil
+}
+
+func (m *manager) Start() error {
+	m.Lock()
+	defer m.Unlock()
+
+	if m.closed {
+		return errManagerClosed
+	}
+	if m.started {
+		return errManagerAlreadyStarted
+	}
+	m.started = true
+
+	m.logger.Info("starting rule evaluation", zap.Int("groups", len(m.groups)))
+	m.updateLeaderMetric()
+	if leaderService := m.opts.LeaderService(); leaderService != nil {
+		m.wg.Add(1)
+		go func() {
+			defer m.wg.Done()
+			m.campaignUntilClosed()
+		}()
+	}
+	for _, group := range m.groups {
+		group := group
+		m.wg.Add(1)
+		go func() {
+			defer m.wg.Done()
+			group.run(m.done, m.isLeader)
+		}()
+	}
+
+	return nil
+}
+
+func (m *manager) isLeader() bool {
+	return atomic.LoadInt32(&m.leader) == 1
+}
+
+func (m *manager) setLeader(leader bool) {
+	var value int32
+	if leader {
+		value = 1
+	}
+	if atomic.SwapInt32(&m.leader, value) != value {
+		m.logger.Info("rule evaluation leadership changed",
+			zap.Bool("leader", leader))
+		m.updateLeaderMetric()
+	}
+}
+
+func (m *manager) updateLeaderMetric() {
+	var value float64
+	if m.isLeader() {
+		value = 1
+	}
+	m.metrics.leader.Update(value)
+}
+
+// campaignUntilClosed campaigns to be the single instance evaluating the
+// rules, campaigning again whenever the campaign ends until the manager is
+// closed.
+func (m *manager) campaignUntilClosed() {
+	var (
+		leaderService = m.opts.LeaderService()
+		electionID    = m.opts.ElectionID()
+		statusCh      <-chan campaign.Status
+	)
+	for {
+		if statusCh == nil {
+			var err error
+			statusCh, err = leaderService.Campaign(electionID,
+				m.opts.CampaignOptions())
+			if err != nil {
+				m.metrics.campaignErrors.Inc(1)
+				m.logger.Error("could not campaign for rule evaluation",
+					zap.String("electionID", electionID), zap.Error(err))
+				if !m.waitToCampaign() {
+					return
+				}
+				continue
+			}
+		}
+
+		select {
+		case <-m.done:
+			return
+		case status, ok := <-statusCh:
+			if !ok {
+				// NB: the campaign ends when the session expires, stop
+				// evaluating the rules and campaign again.
+				m.setLeader(false)
+				statusCh = nil
+				if !m.waitToCampaign() {
+					return
+				}
+				continue
+			}
+			switch status.State {
+			case campaign.Leader:
+				m.setLeader(true)
+			case campaign.Error:
+				m.metrics.campaignErrors.Inc(1)
+				m.logger.Error("error campaigning for rule evaluation",
+					zap.String("electionID", electionID), zap.Error(status.Err))
+				m.setLeader(false)
+			default:
+				m.setLeader(false)
+			}
+		}
+	}
+}
+
+// waitToCampaign waits before campaigning again, returning false if the
+// manager was closed while waiting.
+func (m *manager) waitToCampaign() bool {
+	select {
+	case <-m.done:
+		return false
+	case <-time.After(campaignRetryInterval):
+		return true
+	}
+}
+
+func (m *manager) RuleGroups() []*Group {
+	return m.groups
+}
+
+func (m *manager) AlertingRules() []*AlertingRule {
+	var rules []*AlertingRule
+	for _, group := range m.groups {
+		for _, rule := range group.Rules() {
+			if alertingRule, ok := rule.(*AlertingRule); ok {
+				rules = append(rules, alertingRule)
+			}
+		}
+	}
+	return rules
+}
+
+func (m *manager) Close() error {
+	m.Lock()
+	if m.closed {
+		m.Unlock()
+		return errManagerClosed
+	}
+	m.closed = true
+	m.Unlock()
+
+	close(m.done)
+	m.wg.Wait()
+
+	multiErr := xerrors.NewMultiError()
+	if leaderService := m.opts.LeaderService(); leaderService != nil {
+		if m.isLeader() {
+			if err := leaderService.Resign(m.opts.ElectionID()); err != nil {
+				multiErr = multiErr.Add(err)
+			}
+		}
+		if err := leaderService.Close(); err != nil {
+			multiErr = multiErr.Add(err)
+		}
+	}
+	if notifier := m.opts.Notifier(); notifier != nil {
+		if err := notifier.Close(); err != nil {
+			multiErr = multiErr.Add(err)
+		}
+	}
+	return multiErr.FinalError()
+}
+
+// loadGroups loads the rule groups from the rule files matching the rule
+// file patterns of the options.
+func loadGroups(opts Options) ([]*Group, error) {
+	var (
+		groups []*Group
+		seen   = make(map[string]struct{})
+	)
+	for _, pattern := range opts.RuleFiles() {
+		files, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rule file pattern %s: %w", pattern, err)
+		}
+
+		for _, file := range files {
+			if _, ok := seen[file]; ok {
+				continue
+			}
+			seen[file] = struct{}{}
+
+			fileGroups, err := loadFile(file, opts)
+			if err != nil {
+				return nil, err
+			}
+			groups = append(groups, fileGroups...)
+		}
+	}
+
+	return groups, nil
+}
+
+func loadFile(file string, opts Options) ([]*Group, error) {
+	ruleGroups, errs := rulefmt.ParseFile(file)
+	if len(errs) > 0 {
+		multiErr := xerrors.NewMultiError()
+		for _, err := range errs {
+			multiErr = multiErr.Add(err)
+		}
+		return nil, fmt.Errorf("could not load rule file %s: %w",
+			file, multiErr.FinalError())
+	}
+
+	groups := make([]*Group, 0, len(ruleGroups.Groups))
+	for _, ruleGroup := range ruleGroups.Groups {
+		interval := opts.EvaluationInterval()
+		if ruleGroup.Interval > 0 {
+			interval = time.Duration(ruleGroup.Interval)
+		}
+
+		rules := make([]Rule, 0, len(ruleGroup.Rules))
+		for _, rule := range ruleGroup.Rules {
+			if rule.Alert.Value != "" {
+				rules = append(rules, NewAlertingRule(rule.Alert.Value,
+					rule.Expr.Value, time.Duration(rule.For), rule.Labels,
+					rule.Annotations, opts))
+				continue
+			}
+			rules = append(rules, NewRecordingRule(rule.Record.Value,
+				rule.Expr.Value, rule.Labels, opts))
+		}
+
+		groups = append(groups,
+			NewGroup(ruleGroup.Name, file, interval, rules, opts))
+	}
+
+	return groups, nil
+}
@@ -0,0 +1,343 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ruler
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/cluster/services"
+	"github.com/m3db/m3/src/cluster/services/leader/campaign"
+	"github.com/m3db/m3/src/cmd/services/m3coordinator/ingest"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/ts"
+	xtest "github.com/m3db/m3/src/x/test"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testRuleFile = `
+groups:
+  - name: requests
+    interval: 30s
+    rules:
+      - record: job:requests:sum
+        expr: sum(requests) by (job)
+        labels:
+          env: prod
+      - alert: TooManyRequests
+        expr: job:requests:sum > 10
+        labels:
+          severity: page
+        annotations:
+          summary: "{{ $labels.job }} has {{ $value }} requests"
+  - name: defaults
+    rules:
+      - record: up:count
+        expr: count(up)
+`
+
+type testNotifier struct {
+	sync.Mutex
+
+	alerts []*Alert
+	closed bool
+}
+
+func (n *testNotifier) Send(alerts ...*Alert) {
+	n.Lock()
+	n.alerts = append(n.alerts, alerts...)
+	n.Unlock()
+}
+
+func (n *testNotifier) Close() error {
+	n.Lock()
+	n.closed = true
+	n.Unlock()
+	return nil
+}
+
+func writeTestRuleFile(t *testing.T, contents string) string {
+	dir, err := ioutil.TempDir("", "ruler_test")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	file := filepath.Join(dir, "rules.yml")
+	require.NoError(t, ioutil.WriteFile(file, []byte(contents), 0600))
+	return file
+}
+
+func TestManagerLoadsRuleFiles(t *testing.T) {
+	file := writeTestRuleFile(t, testRuleFile)
+
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	opts := NewOptions().
+		SetRuleFiles([]string{filepath.Join(filepath.Dir(file), "*.yml"), file}).
+		SetEvaluationInterval(time.Minute).
+		SetQueryFunc(newTestQueryFunc(nil)).
+		SetWriter(ingest.NewMockDownsamplerAndWriter(ctrl))
+	manager, err := NewManager(opts)
+	require.NoError(t, err)
+
+	groups := manager.RuleGroups()
+	require.Equal(t, 2, len(groups))
+
+	assert.Equal(t, "requests", groups[0].Name())
+	assert.Equal(t, file, groups[0].File())
+	assert.Equal(t, 30*time.Second, groups[0].Interval())
+	require.Equal(t, 2, len(groups[0].Rules()))
+	assert.Equal(t, "job:requests:sum", groups[0].Rules()[0].Name())
+	assert.Equal(t, "sum(requests) by (job)", groups[0].Rules()[0].Query())
+	assert.Equal(t, map[string]string{"env": "prod"}, groups[0].Rules()[0].Labels())
+
+	assert.Equal(t, "defaults", groups[1].Name())
+	assert.Equal(t, time.Minute, groups[1].Interval())
+
+	alertingRules := manager.AlertingRules()
+	require.Equal(t, 1, len(alertingRules))
+	assert.Equal(t, "TooManyRequests", alertingRules[0].Name())
+	assert.Equal(t, time.Duration(0), alertingRules[0].HoldDuration())
+}
+
+func TestManagerInvalidRuleFile(t *testing.T) {
+	file := writeTestRuleFile(t, `
+groups:
+  - name: invalid
+    rules:
+      - record: invalid
+        expr: sum(
+`)
+
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	opts := NewOptions().
+		SetRuleFiles([]string{file}).
+		SetQueryFunc(newTestQueryFunc(nil)).
+		SetWriter(ingest.NewMockDownsamplerAndWriter(ctrl))
+	_, err := NewManager(opts)
+	require.Error(t, err)
+}
+
+func TestGroupEval(t *testing.T) {
+	file := writeTestRuleFile(t, testRuleFile)
+
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		now    = time.Unix(1600000000, 0)
+		writer = ingest.NewMockDownsamplerAndWriter(ctrl)
+		notif  = &testNotifier{}
+		query  = newTestQueryFunc(map[string]Vector{
+			"sum(requests) by (job)": {
+				{Tags: newTestTags("job", "api"), Value: 12},
+			},
+			"job:requests:sum > 10": {
+				{Tags: newTestTags("__name__", "job:requests:sum", "job", "api"), Value: 12},
+			},
+		})
+		written []string
+	)
+
+	writer.EXPECT().
+		Write(gomock.Any(), gomock.Any(), gomock.Any(), xtime.Millisecond,
+			gomock.Any(), ingest.WriteOptions{}).
+		DoAndReturn(func(
+			_ context.Context,
+			tags models.Tags,
+			datapoints ts.Datapoints,
+			_ xtime.Unit,
+			_ []byte,
+			_ ingest.WriteOptions,
+		) error {
+			require.Equal(t, ts.Datapoints{{
+				Timestamp: xtime.ToUnixNano(now),
+				Value:     datapoints[0].Value,
+			}}, datapoints)
+			written = append(written, tags.String())
+			return nil
+		}).
+		Times(2)
+
+	opts := NewOptions().
+		SetRuleFiles([]string{file}).
+		SetQueryFunc(query).
+		SetWriter(writer).
+		SetNotifier(notif)
+	manager, err := NewManager(opts)
+	require.NoError(t, err)
+
+	group := manager.RuleGroups()[0]
+	group.Eval(context.Background(), now)
+
+	assert.Equal(t, []string{
+		"__name__: job:requests:sum, env: prod, job: api",
+		"__name__: ALERTS, alertname: TooManyRequests, alertstate: firing, " +
+			"job: api, severity: page",
+	}, written)
+	assert.Equal(t, now, group.LastEvaluation())
+	for _, rule := range group.Rules() {
+		assert.Equal(t, HealthGood, rule.Health())
+		assert.Equal(t, now, rule.LastEvaluation())
+	}
+
+	require.Equal(t, 1, len(notif.alerts))
+	assert.Equal(t, StateFiring, notif.alerts[0].State)
+	assert.Equal(t, map[string]string{"summary": "api has 12 requests"},
+		notif.alerts[0].Annotations)
+
+	require.NoError(t, manager.Close())
+	assert.True(t, notif.closed)
+}
+
+func TestGroupEvalQueryError(t *testing.T) {
+	file := writeTestRuleFile(t, testRuleFile)
+
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	opts := NewOptions().
+		SetRuleFiles([]string{file}).
+		SetQueryFunc(newTestQueryFunc(nil)).
+		SetWriter(ingest.NewMockDownsamplerAndWriter(ctrl))
+	manager, err := NewManager(opts)
+	require.NoError(t, err)
+
+	group := manager.RuleGroups()[1]
+	group.Eval(context.Background(), time.Now())
+
+	rule := group.Rules()[0]
+	assert.Equal(t, HealthBad, rule.Health())
+	assert.Error(t, rule.LastError())
+}
+
+func TestManagerLeaderElection(t *testing.T) {
+	file := writeTestRuleFile(t, testRuleFile)
+
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	campaignOpts, err := services.NewCampaignOptions()
+	require.NoError(t, err)
+
+	var (
+		statusCh      = make(chan campaign.Status)
+		leaderService = services.NewMockLeaderService(ctrl)
+		opts          = NewOptions().
+				SetRuleFiles([]string{file}).
+				SetQueryFunc(newTestQueryFunc(nil)).
+				SetWriter(ingest.NewMockDownsamplerAndWriter(ctrl)).
+				SetLeaderService(leaderService).
+				SetElectionID("test-election").
+				SetCampaignOptions(campaignOpts)
+	)
+	leaderService.EXPECT().
+		Campaign("test-election", campaignOpts).
+		Return((<-chan campaign.Status)(statusCh), nil)
+
+	mgr, err := NewManager(opts)
+	require.NoError(t, err)
+	m := mgr.(*manager)
+	assert.False(t, m.isLeader())
+
+	require.NoError(t, m.Start())
+
+	statusCh <- campaign.NewStatus(campaign.Follower)
+	statusCh <- campaign.NewStatus(campaign.Leader)
+	require.True(t, waitUntil(m.isLeader))
+
+	statusCh <- campaign.NewStatus(campaign.Follower)
+	require.True(t, waitUntil(func() bool { return !m.isLeader() }))
+
+	statusCh <- campaign.NewStatus(campaign.Leader)
+	require.True(t, waitUntil(m.isLeader))
+
+	leaderService.EXPECT().Resign("test-election").Return(nil)
+	leaderService.EXPECT().Close().Return(nil)
+	require.NoError(t, m.Close())
+}
+
+func TestManagerWithoutLeaderElection(t *testing.T) {
+	file := writeTestRuleFile(t, testRuleFile)
+
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	opts := NewOptions().
+		SetRuleFiles([]string{file}).
+		SetQueryFunc(newTestQueryFunc(nil)).
+		SetWriter(ingest.NewMockDownsamplerAndWriter(ctrl))
+	mgr, err := NewManager(opts)
+	require.NoError(t, err)
+	assert.True(t, mgr.(*manager).isLeader())
+}
+
+func TestGroupRunSkipsEvaluationUnlessLeader(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	opts := NewOptions().
+		SetQueryFunc(newTestQueryFunc(map[string]Vector{"up": nil})).
+		SetWriter(ingest.NewMockDownsamplerAndWriter(ctrl))
+	rule := NewRecordingRule("up:count", "up", nil, opts)
+
+	var leader int32
+	group := NewGroup("test", "test.yml", time.Millisecond, []Rule{rule}, opts)
+	done := make(chan struct{})
+	runDone := make(chan struct{})
+	go func() {
+		defer close(runDone)
+		group.run(done, func() bool { return atomic.LoadInt32(&leader) == 1 })
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	assert.True(t, group.LastEvaluation().IsZero())
+
+	atomic.StoreInt32(&leader, 1)
+	assert.True(t, waitUntil(func() bool {
+		return !group.LastEvaluation().IsZero()
+	}))
+
+	close(done)
+	<-runDone
+}
+
+func waitUntil(fn func() bool) bool {
+	for i := 0; i < 500; i++ {
+		if fn() {
+			return true
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return false
+}
@@ -0,0 +1,304 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ruler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/m3db/m3/src/x/instrument"
+	xhttp "github.com/m3db/m3/src/x/net/http"
+
+	"github.com/uber-go/tally"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultNotifierTimeout       = 10 * time.Second
+	defaultNotifierQueueCapacity = 10000
+	maxNotificationBatchSize     = 64
+)
+
+var (
+	errNoNotifierURLs = errors.New("no alertmanager URLs set")
+	errNotifierClosed = errors.New("notifier closed")
+)
+
+// NotifierOptions are the options for an Alertmanager notifier.
+type NotifierOptions struct {
+	// URLs are the Alertmanager compatible endpoints alerts are POSTed to,
+	// e.g. http://alertmanager:9093/api/v2/alerts.
+	URLs []string
+	// Timeout is the timeout of each request.
+	Timeout time.Duration
+	// QueueCapacity is the number of alerts queued before the oldest alerts
+	// are dropped.
+	QueueCapacity int
+	// ExternalLabels are added to alerts that do not already have them.
+	ExternalLabels map[string]string
+	// ExternalURL is set as the generator URL of alerts.
+	ExternalURL *url.URL
+	// InstrumentOptions are the instrument options.
+	InstrumentOptions instrument.Options
+}
+
+type notifierMetrics struct {
+	sent    tally.Counter
+	errors  tally.Counter
+	dropped tally.Counter
+}
+
+func newNotifierMetrics(scope tally.Scope) notifierMetrics {
+	return notifierMetrics{
+		sent:    scope.Counter("alerts-sent"),
+		errors:  scope.Counter("alerts-send-errors"),
+		dropped: scope.Counter("alerts-dropped"),
+	}
+}
+
+// alertmanagerAlert is the JSON representation of an alert accepted by the
+// Alertmanager alerts API.
+type alertmanagerAlert struct {
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations"`
+	StartsAt     time.Time         `json:"startsAt,omitempty"`
+	EndsAt       time.Time         `json:"endsAt,omitempty"`
+	GeneratorURL string            `json:"generatorURL,omitempty"`
+}
+
+type alertmanagerNotifier struct {
+	sync.Mutex
+
+	urls           []string
+	client         *http.Client
+	capacity       int
+	externalLabels map[string]string
+	generatorURL   string
+	logger         *zap.Logger
+	metrics        notifierMetrics
+
+	queue  []alertmanagerAlert
+	more   chan struct{}
+	closed bool
+	done   chan struct{}
+	wg     sync.WaitGroup
+}
+
+// NewNotifier returns a new notifier that sends alerts to Alertmanager
+// compatible endpoints in the background.
+func NewNotifier(opts NotifierOptions) (Notifier, error) {
+	if len(opts.URLs) == 0 {
+		return nil, errNoNotifierURLs
+	}
+	for _, u := range opts.URLs {
+		if _, err := url.Parse(u); err != nil {
+			return nil, fmt.Errorf("invalid alertmanager URL %s: %w", u, err)
+		}
+	}
+
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = defaultNotifierTimeout
+	}
+	capacity := opts.QueueCapacity
+	if capacity <= 0 {
+		capacity = defaultNotifierQueueCapacity
+	}
+	iOpts := opts.InstrumentOptions
+	if iOpts == nil {
+		iOpts = instrument.NewOptions()
+	}
+	var generatorURL string
+	if opts.ExternalURL != nil {
+		generatorURL = opts.ExternalURL.String()
+	}
+
+	n := &alertmanagerNotifier{
+		urls:           opts.URLs,
+		client:         &http.Client{Timeout: timeout},
+		capacity:       capacity,
+		externalLabels: opts.ExternalLabels,
+		generatorURL:   generatorURL,
+		logger:         iOpts.Logger(),
+		metrics: newNotifierMetrics(
+			iOpts.MetricsScope().SubScope("ruler").SubScope("notifier")),
+		more: make(chan struct{}, 1),
+		done: make(chan struct{}),
+	}
+
+	n.wg.Add(1)
+	go n.run()
+
+	return n, nil
+}
+
+func (n *alertmanagerNotifier) Send(alerts ...*Alert) {
+	n.Lock()
+	defer n.Unlock()
+
+	if n.closed {
+		n.metrics.dropped.Inc(int64(len(alerts)))
+		return
+	}
+
+	for _, alert := range alerts {
+		n.queue = append(n.queue, n.alertmanagerAlert(alert))
+	}
+	if dropped := len(n.queue) - n.capacity; dropped > 0 {
+		n.metrics.dropped.Inc(int64(dropped))
+		n.logger.Warn("alert queue full, dropping oldest alerts",
+			zap.Int("dropped", dropped))
+		n.queue = n.queue[dropped:]
+	}
+
+	select {
+	case n.more <- struct{}{}:
+	default:
+	}
+}
+
+func (n *alertmanagerNotifier) Close() error {
+	n.Lock()
+	if n.closed {
+		n.Unlock()
+		return errNotifierClosed
+	}
+	n.closed = true
+	n.Unlock()
+
+	close(n.done)
+	n.wg.Wait()
+	return nil
+}
+
+func (n *alertmanagerNotifier) alertmanagerAlert(alert *Alert) alertmanagerAlert {
+	labels := tagsToMap(alert.Labels)
+	for name, value := range n.externalLabels {
+		if _, ok := labels[name]; !ok {
+			labels[name] = value
+		}
+	}
+
+	endsAt := alert.ValidUntil
+	if !alert.ResolvedAt.IsZero() {
+		endsAt = alert.ResolvedAt
+	}
+
+	return alertmanagerAlert{
+		Labels:       labels,
+		Annotations:  alert.Annotations,
+		StartsAt:     alert.FiredAt,
+		EndsAt:       endsAt,
+		GeneratorURL: n.generatorURL,
+	}
+}
+
+func (n *alertmanagerNotifier) run() {
+	defer n.wg.Done()
+
+	for {
+		select {
+		case <-n.done:
+			return
+		case <-n.more:
+		}
+
+		for {
+			batch := n.nextBatch()
+			if len(batch) == 0 {
+				break
+			}
+			n.sendBatch(batch)
+		}
+	}
+}
+
+func (n *alertmanagerNotifier) nextBatch() []alertmanagerAlert {
+	n.Lock()
+	defer n.Unlock()
+
+	size := len(n.queue)
+	if size > maxNotificationBatchSize {
+		size = maxNotificationBatchSize
+	}
+	batch := n.queue[:size:size]
+	n.queue = n.queue[size:]
+	return batch
+}
+
+func (n *alertmanagerNotifier) sendBatch(batch []alertmanagerAlert) {
+	body, err := json.Marshal(batch)
+	if err != nil {
+		n.metrics.errors.Inc(int64(len(batch)))
+		n.logger.Error("could not encode alerts", zap.Error(err))
+		return
+	}
+
+	for _, u := range n.urls {
+		if err := n.post(u, body); err != nil {
+			n.metrics.errors.Inc(int64(len(batch)))
+			n.logger.Warn("could not send alerts",
+				zap.String("url", u), zap.Error(err))
+			continue
+		}
+		n.metrics.sent.Inc(int64(len(batch)))
+	}
+}
+
+func (n *alertmanagerNotifier) post(u string, body []byte) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-n.done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u,
+		bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set(xhttp.HeaderContentType, xhttp.ContentTypeJSON)
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	return nil
+}
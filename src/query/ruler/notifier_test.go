@@ -0,0 +1,96 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ruler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotifierSend(t *testing.T) {
+	received := make(chan []alertmanagerAlert, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/v2/alerts", r.URL.Path)
+
+		var alerts []alertmanagerAlert
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&alerts))
+		received <- alerts
+	}))
+	defer server.Close()
+
+	externalURL, err := url.Parse("http://m3query:7201")
+	require.NoError(t, err)
+
+	notifier, err := NewNotifier(NotifierOptions{
+		URLs:           []string{server.URL + "/api/v2/alerts"},
+		ExternalLabels: map[string]string{"cluster": "a", "severity": "none"},
+		ExternalURL:    externalURL,
+	})
+	require.NoError(t, err)
+	defer notifier.Close()
+
+	firedAt := time.Unix(1600000000, 0).UTC()
+	notifier.Send(&Alert{
+		State:       StateFiring,
+		Labels:      newTestTags("alertname", "InstanceDown", "severity", "page"),
+		Annotations: map[string]string{"summary": "down"},
+		FiredAt:     firedAt,
+		ValidUntil:  firedAt.Add(4 * time.Minute),
+	}, &Alert{
+		State:      StateInactive,
+		Labels:     newTestTags("alertname", "InstanceDown", "instance", "b"),
+		FiredAt:    firedAt,
+		ResolvedAt: firedAt.Add(time.Minute),
+		ValidUntil: firedAt.Add(4 * time.Minute),
+	})
+
+	select {
+	case alerts := <-received:
+		require.Equal(t, 2, len(alerts))
+		assert.Equal(t, map[string]string{
+			"alertname": "InstanceDown",
+			"severity":  "page",
+			"cluster":   "a",
+		}, alerts[0].Labels)
+		assert.Equal(t, map[string]string{"summary": "down"}, alerts[0].Annotations)
+		assert.True(t, firedAt.Equal(alerts[0].StartsAt))
+		assert.True(t, firedAt.Add(4*time.Minute).Equal(alerts[0].EndsAt))
+		assert.Equal(t, "http://m3query:7201", alerts[0].GeneratorURL)
+
+		assert.Equal(t, "b", alerts[1].Labels["instance"])
+		assert.True(t, firedAt.Add(time.Minute).Equal(alerts[1].EndsAt))
+	case <-time.After(10 * time.Second):
+		require.FailNow(t, "timed out waiting for alerts")
+	}
+}
+
+func TestNotifierNoURLs(t *testing.T) {
+	_, err := NewNotifier(NotifierOptions{})
+	require.Error(t, err)
+}
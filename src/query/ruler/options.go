@@ -0,0 +1,245 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ruler
+
+import (
+	"errors"
+	"net/url"
+	"time"
+
+	"github.com/m3db/m3/src/cluster/services"
+	"github.com/m3db/m3/src/cmd/services/m3coordinator/ingest"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/x/clock"
+	"github.com/m3db/m3/src/x/instrument"
+)
+
+const (
+	defaultEvaluationInterval = time.Minute
+	defaultResendDelay        = time.Minute
+	defaultElectionID         = "m3query-ruler"
+)
+
+var (
+	errNoQueryFunc               = errors.New("no query function set")
+	errNoWriter                  = errors.New("no writer set")
+	errNoTagOptions              = errors.New("no tag options set")
+	errInvalidEvaluationInterval = errors.New("evaluation interval must be positive")
+	errInvalidResendDelay        = errors.New("resend delay must not be negative")
+	errNoElectionID              = errors.New("no election ID set")
+	errNoCampaignOptions         = errors.New("no campaign options set")
+)
+
+type options struct {
+	ruleFiles          []string
+	evaluationInterval time.Duration
+	resendDelay        time.Duration
+	queryFn            QueryFunc
+	writer             ingest.DownsamplerAndWriter
+	notifier           Notifier
+	externalLabels     map[string]string
+	externalURL        *url.URL
+	leaderService      services.LeaderService
+	electionID         string
+	campaignOpts       services.CampaignOptions
+	tagOpts            models.TagOptions
+	clockOpts          clock.Options
+	instrumentOpts     instrument.Options
+}
+
+// NewOptions returns a new set of rule manager options.
+func NewOptions() Options {
+	return &options{
+		evaluationInterval: defaultEvaluationInterval,
+		resendDelay:        defaultResendDelay,
+		electionID:         defaultElectionID,
+		tagOpts:            models.NewTagOptions(),
+		clockOpts:          clock.NewOptions(),
+		instrumentOpts:     instrument.NewOptions(),
+	}
+}
+
+func (o *options) Validate() error {
+	if o.queryFn == nil {
+		return errNoQueryFunc
+	}
+	if o.writer == nil {
+		return errNoWriter
+	}
+	if o.tagOpts == nil {
+		return errNoTagOptions
+	}
+	if o.evaluationInterval <= 0 {
+		return errInvalidEvaluationInterval
+	}
+	if o.resendDelay < 0 {
+		return errInvalidResendDelay
+	}
+	if o.leaderService != nil {
+		if o.electionID == "" {
+			return errNoElectionID
+		}
+		if o.campaignOpts == nil {
+			return errNoCampaignOptions
+		}
+	}
+	return o.tagOpts.Validate()
+}
+
+func (o *options) SetRuleFiles(value []string) Options {
+	opts := *o
+	opts.ruleFiles = value
+	return &opts
+}
+
+func (o *options) RuleFiles() []string {
+	return o.ruleFiles
+}
+
+func (o *options) SetEvaluationInterval(value time.Duration) Options {
+	opts := *o
+	opts.evaluationInterval = value
+	return &opts
+}
+
+func (o *options) EvaluationInterval() time.Duration {
+	return o.evaluationInterval
+}
+
+func (o *options) SetResendDelay(value time.Duration) Options {
+	opts := *o
+	opts.resendDelay = value
+	return &opts
+}
+
+func (o *options) ResendDelay() time.Duration {
+	return o.resendDelay
+}
+
+func (o *options) SetQueryFunc(value QueryFunc) Options {
+	opts := *o
+	opts.queryFn = value
+	return &opts
+}
+
+func (o *options) QueryFunc() QueryFunc {
+	return o.queryFn
+}
+
+func (o *options) SetWriter(value ingest.DownsamplerAndWriter) Options {
+	opts := *o
+	opts.writer = value
+	return &opts
+}
+
+func (o *options) Writer() ingest.DownsamplerAndWriter {
+	return o.writer
+}
+
+func (o *options) SetNotifier(value Notifier) Options {
+	opts := *o
+	opts.notifier = value
+	return &opts
+}
+
+func (o *options) Notifier() Notifier {
+	return o.notifier
+}
+
+func (o *options) SetExternalLabels(value map[string]string) Options {
+	opts := *o
+	opts.externalLabels = value
+	return &opts
+}
+
+func (o *options) ExternalLabels() map[string]string {
+	return o.externalLabels
+}
+
+func (o *options) SetExternalURL(value *url.URL) Options {
+	opts := *o
+	opts.externalURL = value
+	return &opts
+}
+
+func (o *options) ExternalURL() *url.URL {
+	return o.externalURL
+}
+
+func (o *options) SetLeaderService(value services.LeaderService) Options {
+	opts := *o
+	opts.leaderService = value
+	return &opts
+}
+
+func (o *options) LeaderService() services.LeaderService {
+	return o.leaderService
+}
+
+func (o *options) SetElectionID(value string) Options {
+	opts := *o
+	opts.electionID = value
+	return &opts
+}
+
+func (o *options) ElectionID() string {
+	return o.electionID
+}
+
+func (o *options) SetCampaignOptions(value services.CampaignOptions) Options {
+	opts := *o
+	opts.campaignOpts = value
+	return &opts
+}
+
+func (o *options) CampaignOptions() services.CampaignOptions {
+	return o.campaignOpts
+}
+
+func (o *options) SetTagOptions(value models.TagOptions) Options {
+	opts := *o
+	opts.tagOpts = value
+	return &opts
+}
+
+func (o *options) TagOptions() models.TagOptions {
+	return o.tagOpts
+}
+
+func (o *options) SetClockOptions(value clock.Options) Options {
+	opts := *o
+	opts.clockOpts = value
+	return &opts
+}
+
+func (o *options) ClockOptions() clock.Options {
+	return o.clockOpts
+}
+
+func (o *options) SetInstrumentOptions(value instrument.Options) Options {
+	opts := *o
+	opts.instrumentOpts = value
+	return &opts
+}
+
+func (o *options) InstrumentOptions() instrument.Options {
+	return o.instrumentOpts
+}
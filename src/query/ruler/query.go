@@ -0,0 +1,114 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ruler
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/m3db/m3/src/query/executor"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/parser/promql"
+	"github.com/m3db/m3/src/query/storage"
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+// instantQueryStep is the step instant queries are evaluated with.
+const instantQueryStep = time.Second
+
+// NewEngineQueryFunc returns a query function that evaluates instant queries
+// with the given engine.
+func NewEngineQueryFunc(
+	engine executor.Engine,
+	tagOpts models.TagOptions,
+	timeout time.Duration,
+) QueryFunc {
+	return func(ctx context.Context, query string, t time.Time) (Vector, error) {
+		engineOpts := engine.Options()
+		parser, err := promql.Parse(query, instantQueryStep, tagOpts,
+			engineOpts.ParseOptions())
+		if err != nil {
+			return nil, err
+		}
+
+		fetchOpts := storage.NewFetchOptions()
+		fetchOpts.Timeout = timeout
+		params := models.RequestParams{
+			Start:            xtime.ToUnixNano(t),
+			End:              xtime.ToUnixNano(t),
+			Now:              t,
+			Timeout:          timeout,
+			Step:             instantQueryStep,
+			Query:            query,
+			IncludeEnd:       true,
+			BlockType:        models.TypeSingleBlock,
+			LookbackDuration: engineOpts.LookbackDuration(),
+		}
+		queryOpts := &executor.QueryOptions{
+			QueryContextOptions: models.QueryContextOptions{
+				Instantaneous: true,
+			},
+		}
+
+		bl, err := engine.ExecuteExpr(ctx, parser, queryOpts, fetchOpts, params)
+		if err != nil {
+			return nil, err
+		}
+		defer bl.Close()
+
+		it, err := bl.StepIter()
+		if err != nil {
+			return nil, err
+		}
+
+		// Instant queries may return more than one step, the value of each
+		// series is its last non NaN value.
+		seriesMeta := it.SeriesMeta()
+		values := make([]float64, len(seriesMeta))
+		for i := range values {
+			values[i] = math.NaN()
+		}
+		for it.Next() {
+			for i, v := range it.Current().Values() {
+				if !math.IsNaN(v) {
+					values[i] = v
+				}
+			}
+		}
+		if err := it.Err(); err != nil {
+			return nil, err
+		}
+
+		vector := make(Vector, 0, len(seriesMeta))
+		for i, meta := range seriesMeta {
+			if math.IsNaN(values[i]) {
+				continue
+			}
+			vector = append(vector, Sample{
+				Tags:  meta.Tags.AddTags(bl.Meta().Tags.Tags),
+				Value: values[i],
+			})
+		}
+
+		return vector, nil
+	}
+}
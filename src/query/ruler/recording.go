@@ -0,0 +1,179 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ruler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/m3db/m3/src/query/models"
+)
+
+// ruleEvaluation tracks the outcome of the last evaluation of a rule.
+type ruleEvaluation struct {
+	mu             sync.RWMutex
+	health         RuleHealth
+	lastError      error
+	lastEvaluation time.Time
+	duration       time.Duration
+}
+
+func newRuleEvaluation() ruleEvaluation {
+	return ruleEvaluation{health: HealthUnknown}
+}
+
+func (r *ruleEvaluation) Health() RuleHealth {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.health
+}
+
+func (r *ruleEvaluation) LastError() error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.lastError
+}
+
+func (r *ruleEvaluation) LastEvaluation() time.Time {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.lastEvaluation
+}
+
+func (r *ruleEvaluation) EvaluationDuration() time.Duration {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.duration
+}
+
+func (r *ruleEvaluation) setEvaluationResult(
+	t time.Time,
+	duration time.Duration,
+	err error,
+) {
+	r.mu.Lock()
+	r.lastEvaluation = t
+	r.duration = duration
+	r.lastError = err
+	r.health = HealthGood
+	if err != nil {
+		r.health = HealthBad
+	}
+	r.mu.Unlock()
+}
+
+// RecordingRule records the result of a query as a new series.
+type RecordingRule struct {
+	ruleEvaluation
+
+	name    string
+	query   string
+	labels  map[string]string
+	tagOpts models.TagOptions
+}
+
+// NewRecordingRule returns a new recording rule that records the result of
+// the query with the given name and labels.
+func NewRecordingRule(
+	name string,
+	query string,
+	labels map[string]string,
+	opts Options,
+) *RecordingRule {
+	return &RecordingRule{
+		ruleEvaluation: newRuleEvaluation(),
+		name:           name,
+		query:          query,
+		labels:         labels,
+		tagOpts:        opts.TagOptions(),
+	}
+}
+
+// Name returns the name of the recorded series.
+func (r *RecordingRule) Name() string {
+	return r.name
+}
+
+// Query returns the query of the rule.
+func (r *RecordingRule) Query() string {
+	return r.query
+}
+
+// Labels returns the labels added to the recorded series.
+func (r *RecordingRule) Labels() map[string]string {
+	return r.labels
+}
+
+// Eval evaluates the rule, returning the series to record.
+func (r *RecordingRule) Eval(
+	ctx context.Context,
+	t time.Time,
+	query QueryFunc,
+) (Vector, error) {
+	vector, err := query(ctx, r.query, t)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[uint64]struct{}, len(vector))
+	for i, sample := range vector {
+		tags := sample.Tags.AddOrUpdateTag(models.Tag{
+			Name:  r.tagOpts.MetricName(),
+			Value: []byte(r.name),
+		})
+		tags = addLabels(tags, r.labels)
+
+		id := tags.HashedID()
+		if _, ok := seen[id]; ok {
+			return nil, fmt.Errorf(
+				"recording rule %s: vector contains series with the same tags "+
+					"after applying rule labels", r.name)
+		}
+		seen[id] = struct{}{}
+		vector[i].Tags = tags
+	}
+
+	return vector, nil
+}
+
+// addLabels adds or overrides the tags with the given labels, in name order
+// so that the result does not depend on map iteration order.
+func addLabels(tags models.Tags, labels map[string]string) models.Tags {
+	for _, name := range sortedKeys(labels) {
+		tags = tags.AddOrUpdateTag(models.Tag{
+			Name:  []byte(name),
+			Value: []byte(labels[name]),
+		})
+	}
+	return tags
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
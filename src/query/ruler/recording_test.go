@@ -0,0 +1,96 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ruler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/query/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTags(nameValues ...string) models.Tags {
+	tags := models.NewTags(len(nameValues)/2, models.NewTagOptions())
+	for i := 0; i < len(nameValues); i += 2 {
+		tags = tags.AddTag(models.Tag{
+			Name:  []byte(nameValues[i]),
+			Value: []byte(nameValues[i+1]),
+		})
+	}
+	return tags
+}
+
+func newTestQueryFunc(results map[string]Vector) QueryFunc {
+	return func(_ context.Context, query string, _ time.Time) (Vector, error) {
+		result, ok := results[query]
+		if !ok {
+			return nil, errors.New("unexpected query: " + query)
+		}
+
+		// Return copies since rules modify the tags of the results.
+		vector := make(Vector, 0, len(result))
+		for _, sample := range result {
+			vector = append(vector, Sample{
+				Tags:  sample.Tags.Clone(),
+				Value: sample.Value,
+			})
+		}
+		return vector, nil
+	}
+}
+
+func TestRecordingRuleEval(t *testing.T) {
+	query := newTestQueryFunc(map[string]Vector{
+		"sum(rate(requests[1m])) by (job)": {
+			{Tags: newTestTags("job", "api"), Value: 3},
+			{Tags: newTestTags("job", "web"), Value: 5},
+		},
+	})
+
+	rule := NewRecordingRule("job:requests:rate1m",
+		"sum(rate(requests[1m])) by (job)",
+		map[string]string{"env": "prod", "job": "overridden"}, NewOptions())
+	assert.Equal(t, HealthUnknown, rule.Health())
+
+	_, err := rule.Eval(context.Background(), time.Now(), query)
+	require.Error(t, err)
+
+	rule = NewRecordingRule("job:requests:rate1m",
+		"sum(rate(requests[1m])) by (job)",
+		map[string]string{"env": "prod"}, NewOptions())
+	vector, err := rule.Eval(context.Background(), time.Now(), query)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(vector))
+
+	assert.Equal(t,
+		"__name__: job:requests:rate1m, env: prod, job: api",
+		vector[0].Tags.String())
+	assert.Equal(t, 3.0, vector[0].Value)
+	assert.Equal(t,
+		"__name__: job:requests:rate1m, env: prod, job: web",
+		vector[1].Tags.String())
+	assert.Equal(t, 5.0, vector[1].Value)
+}
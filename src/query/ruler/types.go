@@ -0,0 +1,220 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package ruler evaluates Prometheus format recording and alerting rules
+// against the query engine, writing recording rule results back through the
+// write path and sending alerts to Alertmanager compatible receivers.
+package ruler
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"github.com/m3db/m3/src/cluster/services"
+	"github.com/m3db/m3/src/cmd/services/m3coordinator/ingest"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/x/clock"
+	"github.com/m3db/m3/src/x/instrument"
+)
+
+// Sample is a single value of an instant vector.
+type Sample struct {
+	// Tags are the tags of the series the value belongs to.
+	Tags models.Tags
+	// Value is the value of the series.
+	Value float64
+}
+
+// Vector is the result of an instant query.
+type Vector []Sample
+
+// QueryFunc evaluates an instant query at the given time.
+type QueryFunc func(ctx context.Context, query string, t time.Time) (Vector, error)
+
+// Manager manages the evaluation of a set of rule groups.
+type Manager interface {
+	// Start starts evaluating the rule groups on their intervals, if a
+	// leader service is set the groups are only evaluated while elected.
+	Start() error
+
+	// RuleGroups returns the rule groups being evaluated.
+	RuleGroups() []*Group
+
+	// AlertingRules returns the alerting rules of all the rule groups.
+	AlertingRules() []*AlertingRule
+
+	// Close stops evaluating the rule groups and resigns from the election.
+	Close() error
+}
+
+// Rule is a rule that is evaluated as part of a rule group.
+type Rule interface {
+	// Name returns the name of the rule.
+	Name() string
+
+	// Query returns the query of the rule.
+	Query() string
+
+	// Labels returns the labels the rule adds to its output.
+	Labels() map[string]string
+
+	// Eval evaluates the rule at the given time, returning the samples to
+	// write back to storage.
+	Eval(ctx context.Context, t time.Time, query QueryFunc) (Vector, error)
+
+	// Health returns the health of the rule as of its last evaluation.
+	Health() RuleHealth
+
+	// LastError returns the error of the last evaluation, if any.
+	LastError() error
+
+	// LastEvaluation returns the time the rule was last evaluated.
+	LastEvaluation() time.Time
+
+	// EvaluationDuration returns how long the last evaluation took.
+	EvaluationDuration() time.Duration
+
+	// setEvaluationResult records the outcome of an evaluation.
+	setEvaluationResult(t time.Time, duration time.Duration, err error)
+}
+
+// RuleHealth describes the health of a rule.
+type RuleHealth string
+
+const (
+	// HealthUnknown is the health of a rule that has not been evaluated.
+	HealthUnknown RuleHealth = "unknown"
+	// HealthGood is the health of a rule whose last evaluation succeeded.
+	HealthGood RuleHealth = "ok"
+	// HealthBad is the health of a rule whose last evaluation failed.
+	HealthBad RuleHealth = "err"
+)
+
+// Notifier sends alerts to an alert receiver.
+type Notifier interface {
+	// Send queues alerts to be sent.
+	Send(alerts ...*Alert)
+
+	// Close stops sending alerts.
+	Close() error
+}
+
+// Options are the options for the rule manager.
+type Options interface {
+	// Validate validates the options.
+	Validate() error
+
+	// SetRuleFiles sets the paths, or glob patterns, of the rule files.
+	SetRuleFiles(value []string) Options
+
+	// RuleFiles returns the paths, or glob patterns, of the rule files.
+	RuleFiles() []string
+
+	// SetEvaluationInterval sets the interval rule groups are evaluated at
+	// when they do not specify one.
+	SetEvaluationInterval(value time.Duration) Options
+
+	// EvaluationInterval returns the interval rule groups are evaluated at
+	// when they do not specify one.
+	EvaluationInterval() time.Duration
+
+	// SetResendDelay sets the minimum delay before a firing alert is
+	// resent to the notifier.
+	SetResendDelay(value time.Duration) Options
+
+	// ResendDelay returns the minimum delay before a firing alert is
+	// resent to the notifier.
+	ResendDelay() time.Duration
+
+	// SetQueryFunc sets the function used to evaluate rule queries.
+	SetQueryFunc(value QueryFunc) Options
+
+	// QueryFunc returns the function used to evaluate rule queries.
+	QueryFunc() QueryFunc
+
+	// SetWriter sets the writer recording rule and alert state samples
+	// are written with.
+	SetWriter(value ingest.DownsamplerAndWriter) Options
+
+	// Writer returns the writer recording rule and alert state samples
+	// are written with.
+	Writer() ingest.DownsamplerAndWriter
+
+	// SetNotifier sets the notifier alerts are sent with, alerts are not
+	// sent if no notifier is set.
+	SetNotifier(value Notifier) Options
+
+	// Notifier returns the notifier alerts are sent with.
+	Notifier() Notifier
+
+	// SetExternalLabels sets the labels added to alerts sent to the
+	// notifier and made available to alert templates.
+	SetExternalLabels(value map[string]string) Options
+
+	// ExternalLabels returns the labels added to alerts sent to the
+	// notifier and made available to alert templates.
+	ExternalLabels() map[string]string
+
+	// SetExternalURL sets the URL alerts link back to.
+	SetExternalURL(value *url.URL) Options
+
+	// ExternalURL returns the URL alerts link back to.
+	ExternalURL() *url.URL
+
+	// SetLeaderService sets the leader service used to elect the single
+	// instance evaluating the rules, every instance evaluates the rules if
+	// not set.
+	SetLeaderService(value services.LeaderService) Options
+
+	// LeaderService returns the leader service used to elect the single
+	// instance evaluating the rules.
+	LeaderService() services.LeaderService
+
+	// SetElectionID sets the ID of the election campaigned in.
+	SetElectionID(value string) Options
+
+	// ElectionID returns the ID of the election campaigned in.
+	ElectionID() string
+
+	// SetCampaignOptions sets the options of the campaign.
+	SetCampaignOptions(value services.CampaignOptions) Options
+
+	// CampaignOptions returns the options of the campaign.
+	CampaignOptions() services.CampaignOptions
+
+	// SetTagOptions sets the tag options.
+	SetTagOptions(value models.TagOptions) Options
+
+	// TagOptions returns the tag options.
+	TagOptions() models.TagOptions
+
+	// SetClockOptions sets the clock options.
+	SetClockOptions(value clock.Options) Options
+
+	// ClockOptions returns the clock options.
+	ClockOptions() clock.Options
+
+	// SetInstrumentOptions sets the instrument options.
+	SetInstrumentOptions(value instrument.Options) Options
+
+	// InstrumentOptions returns the instrument options.
+	InstrumentOptions() instrument.Options
+}
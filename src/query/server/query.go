@@ -57,6 +57,7 @@ import (
 	"github.com/m3db/m3/src/query/storage/fanout"
 	"github.com/m3db/m3/src/query/storage/m3"
 	"github.com/m3db/m3/src/query/storage/m3/consolidators"
+	"github.com/m3db/m3/src/query/storage/metricmetadata"
 	"github.com/m3db/m3/src/query/storage/promremote"
 	"github.com/m3db/m3/src/query/storage/remote"
 	"github.com/m3db/m3/src/query/stores/m3db"
@@ -540,6 +541,10 @@ func Run(runOpts RunOptions) RunResult {
 		engineOpts = engineOpts.
 			SetParseOptions(engineOpts.ParseOptions().SetParseFn(fn))
 	}
+	if cfg.Query.TileAverages {
+		engineOpts = engineOpts.
+			SetParseOptions(engineOpts.ParseOptions().SetTileAverages(true))
+	}
 
 	engine := executor.NewEngine(engineOpts)
 	downsamplerAndWriter, err := newDownsamplerAndWriter(
@@ -642,6 +647,38 @@ func Run(runOpts RunOptions) RunResult {
 	if err != nil {
 		logger.Fatal("unable to set up handler options", zap.Error(err))
 	}
+	if metadataStorage, ok := handlerOptions.MetricMetadataStorage().(metricmetadata.Storage); ok {
+		// NB: write any pending metric metadata once the server is shut down.
+		defer metadataStorage.Close()
+	}
+
+	if cfg.Rules != nil {
+		ruleManager, err := cfg.Rules.NewManager(engine, downsamplerAndWriter,
+			clusterClient, tagOptions, instrumentOptions)
+		if err != nil {
+			logger.Fatal("unable to create rule manager", zap.Error(err))
+		}
+		if err := ruleManager.Start(); err != nil {
+			logger.Fatal("unable to start rule manager", zap.Error(err))
+		}
+		defer func() {
+			if err := ruleManager.Close(); err != nil {
+				logger.Error("error closing rule manager", zap.Error(err))
+			}
+		}()
+
+		handlerOptions = handlerOptions.SetRuleManager(ruleManager)
+	}
+
+	if cfg.Query.ResultCache != nil {
+		resultCache, err := cfg.Query.ResultCache.NewCache(tagOptions,
+			instrumentOptions)
+		if err != nil {
+			logger.Fatal("unable to create query result cache", zap.Error(err))
+		}
+
+		handlerOptions = handlerOptions.SetResultCache(resultCache)
+	}
 
 	var customHandlerOpts options.CustomHandlerOptions
 	if runOpts.CustomHandlerOptions != nil {
@@ -0,0 +1,74 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/m3db/m3/src/query/block"
+)
+
+// CardinalityStorage returns the cardinality statistics of stored series.
+type CardinalityStorage interface {
+	// Cardinality returns the cardinality statistics of the series
+	// within the query time range, the series and docs limits of the
+	// fetch options bound the number of series the statistics are
+	// computed over.
+	Cardinality(
+		ctx context.Context,
+		query *CardinalityQuery,
+		options *FetchOptions,
+	) (*CardinalityResult, error)
+}
+
+// CardinalityQuery is a query for the cardinality statistics of series.
+type CardinalityQuery struct {
+	// Start is the inclusive start of the query time range.
+	Start time.Time
+	// End is the exclusive end of the query time range.
+	End time.Time
+	// Limit is the number of entries returned for each statistic.
+	Limit int
+}
+
+// CardinalityStat is a single named cardinality statistic.
+type CardinalityStat struct {
+	Name  string
+	Value int64
+}
+
+// CardinalityResult is the result of a cardinality query, each list of
+// statistics is sorted by value in descending order.
+type CardinalityResult struct {
+	// NumSeries is the number of series matched.
+	NumSeries int64
+	// SeriesCountByMetricName is the number of series per metric name.
+	SeriesCountByMetricName []CardinalityStat
+	// LabelValueCountByLabelName is the number of distinct values per label name.
+	LabelValueCountByLabelName []CardinalityStat
+	// MemoryInBytesByLabelName is the total length of distinct values per label name.
+	MemoryInBytesByLabelName []CardinalityStat
+	// SeriesCountByLabelValuePair is the number of series per label name/value pair.
+	SeriesCountByLabelValuePair []CardinalityStat
+	// Metadata describes any metadata for the operation.
+	Metadata block.ResultMetadata
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"context"
+
+	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/query/generated/proto/prompb"
+	"github.com/m3db/m3/src/query/models"
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+// ExemplarStorage writes and fetches the exemplars of series.
+type ExemplarStorage interface {
+	// WriteExemplars writes the exemplars of each of the series.
+	WriteExemplars(ctx context.Context, series []SeriesExemplars) error
+
+	// FetchExemplars fetches the exemplars of the series matching the query
+	// within the query time range.
+	FetchExemplars(
+		ctx context.Context,
+		query *FetchQuery,
+		options *FetchOptions,
+	) ([]SeriesExemplars, error)
+}
+
+// SeriesExemplars are the exemplars of a series.
+type SeriesExemplars struct {
+	Tags      models.Tags
+	Exemplars []ts.Exemplar
+}
+
+// PromExemplarsToM3Exemplars converts Prometheus exemplars to M3 exemplars.
+func PromExemplarsToM3Exemplars(exemplars []prompb.Exemplar) []ts.Exemplar {
+	result := make([]ts.Exemplar, 0, len(exemplars))
+	for _, exemplar := range exemplars {
+		labels := make([]ts.ExemplarLabel, 0, len(exemplar.Labels))
+		for _, label := range exemplar.Labels {
+			labels = append(labels, ts.ExemplarLabel{
+				Name:  label.Name,
+				Value: label.Value,
+			})
+		}
+		result = append(result, ts.Exemplar{
+			TimestampNanos: xtime.ToUnixNano(PromTimestampToTime(exemplar.Timestamp)),
+			Value:          exemplar.Value,
+			Labels:         labels,
+		})
+	}
+	return result
+}
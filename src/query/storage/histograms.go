@@ -0,0 +1,153 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"github.com/m3db/m3/src/aggregator/aggregation/quantile/ddsketch"
+	"github.com/m3db/m3/src/dbnode/encoding/histogram"
+	"github.com/m3db/m3/src/dbnode/generated/proto/annotation"
+	"github.com/m3db/m3/src/query/generated/proto/prompb"
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+var sketchOptions = ddsketch.NewOptions()
+
+// PromHistogramToM3Histogram converts a Prometheus native histogram to an M3
+// histogram, resolving the bucket deltas of integer histograms to absolute
+// bucket counts.
+func PromHistogramToM3Histogram(h prompb.Histogram) histogram.Histogram {
+	result := histogram.Histogram{
+		Schema:        h.Schema,
+		ZeroThreshold: h.ZeroThreshold,
+		Sum:           h.Sum,
+		PositiveSpans: promBucketSpansToM3(h.PositiveSpans),
+		NegativeSpans: promBucketSpansToM3(h.NegativeSpans),
+	}
+
+	if isPromFloatHistogram(h) {
+		result.Count = h.CountFloat
+		result.ZeroCount = h.ZeroCountFloat
+		result.PositiveBuckets = append([]float64(nil), h.PositiveCounts...)
+		result.NegativeBuckets = append([]float64(nil), h.NegativeCounts...)
+		return result
+	}
+
+	result.Count = float64(h.CountInt)
+	result.ZeroCount = float64(h.ZeroCountInt)
+	result.PositiveBuckets = promBucketDeltasToCounts(h.PositiveDeltas)
+	result.NegativeBuckets = promBucketDeltasToCounts(h.NegativeDeltas)
+	return result
+}
+
+// isPromFloatHistogram returns whether the histogram has float counts, in
+// Prometheus the count is a oneof of an integer and a float count.
+func isPromFloatHistogram(h prompb.Histogram) bool {
+	return h.CountInt == 0 && h.ZeroCountInt == 0 && len(h.PositiveDeltas) == 0 &&
+		len(h.NegativeDeltas) == 0 &&
+		(h.CountFloat != 0 || h.ZeroCountFloat != 0 ||
+			len(h.PositiveCounts) != 0 || len(h.NegativeCounts) != 0)
+}
+
+func promBucketSpansToM3(spans []prompb.BucketSpan) []histogram.Span {
+	if len(spans) == 0 {
+		return nil
+	}
+	result := make([]histogram.Span, 0, len(spans))
+	for _, span := range spans {
+		result = append(result, histogram.Span{
+			Offset: span.Offset,
+			Length: span.Length,
+		})
+	}
+	return result
+}
+
+func promBucketDeltasToCounts(deltas []int64) []float64 {
+	if len(deltas) == 0 {
+		return nil
+	}
+	var (
+		result = make([]float64, 0, len(deltas))
+		count  int64
+	)
+	for _, delta := range deltas {
+		count += delta
+		result = append(result, float64(count))
+	}
+	return result
+}
+
+// SketchToM3Histogram converts the sketch of a timer aggregated with sketches
+// to an M3 histogram, the buckets of a sketch are those of a native histogram
+// with the same schema.
+func SketchToM3Histogram(s *ddsketch.Sketch) histogram.Histogram {
+	result := histogram.Histogram{
+		Schema:    s.Schema(),
+		ZeroCount: float64(s.ZeroCount()),
+		Count:     float64(s.Count()),
+		Sum:       s.Sum(),
+	}
+	result.PositiveSpans, result.PositiveBuckets = sketchBucketsToM3(s.ForEachPositiveBucket)
+	result.NegativeSpans, result.NegativeBuckets = sketchBucketsToM3(s.ForEachNegativeBucket)
+	return result
+}
+
+// SketchAnnotationToHistogramAnnotation decodes the encoded sketch annotation
+// of a timer aggregated with sketches and returns the annotation of the
+// equivalent native histogram, from which any quantile of the timer can be
+// computed at query time.
+func SketchAnnotationToHistogramAnnotation(
+	timestamp xtime.UnixNano,
+	encoded []byte,
+) ([]byte, error) {
+	sketch := ddsketch.NewSketch(sketchOptions)
+	if err := sketch.Decode(encoded); err != nil {
+		return nil, err
+	}
+	payload := annotation.Payload{
+		MetricType:      annotation.MetricType_HISTOGRAM,
+		NativeHistogram: histogram.Encode(timestamp, SketchToM3Histogram(sketch)),
+	}
+	return payload.Marshal()
+}
+
+func sketchBucketsToM3(
+	forEach func(fn func(idx int32, count uint64)),
+) ([]histogram.Span, []float64) {
+	var (
+		spans   []histogram.Span
+		buckets []float64
+		lastIdx int32
+	)
+	forEach(func(idx int32, count uint64) {
+		switch {
+		case len(spans) == 0:
+			spans = append(spans, histogram.Span{Offset: idx, Length: 1})
+		case idx == lastIdx+1:
+			spans[len(spans)-1].Length++
+		default:
+			spans = append(spans, histogram.Span{Offset: idx - lastIdx - 1, Length: 1})
+		}
+		buckets = append(buckets, float64(count))
+		lastIdx = idx
+	})
+	return spans, buckets
+}
@@ -0,0 +1,141 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"testing"
+
+	"github.com/m3db/m3/src/aggregator/aggregation/quantile/ddsketch"
+	"github.com/m3db/m3/src/dbnode/encoding/histogram"
+	"github.com/m3db/m3/src/dbnode/generated/proto/annotation"
+	"github.com/m3db/m3/src/query/generated/proto/prompb"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPromHistogramToM3Histogram(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    prompb.Histogram
+		expected histogram.Histogram
+	}{
+		{
+			name: "integer histogram",
+			input: prompb.Histogram{
+				CountInt:       12,
+				Sum:            18.4,
+				Schema:         1,
+				ZeroThreshold:  0.001,
+				ZeroCountInt:   2,
+				PositiveSpans:  []prompb.BucketSpan{{Offset: 0, Length: 2}, {Offset: 1, Length: 2}},
+				PositiveDeltas: []int64{1, 1, -1, 0},
+				NegativeSpans:  []prompb.BucketSpan{{Offset: 0, Length: 2}},
+				NegativeDeltas: []int64{2, 2},
+			},
+			expected: histogram.Histogram{
+				Schema:          1,
+				ZeroThreshold:   0.001,
+				ZeroCount:       2,
+				Count:           12,
+				Sum:             18.4,
+				PositiveSpans:   []histogram.Span{{Offset: 0, Length: 2}, {Offset: 1, Length: 2}},
+				PositiveBuckets: []float64{1, 2, 1, 1},
+				NegativeSpans:   []histogram.Span{{Offset: 0, Length: 2}},
+				NegativeBuckets: []float64{2, 4},
+			},
+		},
+		{
+			name: "float histogram",
+			input: prompb.Histogram{
+				CountFloat:     3.5,
+				Sum:            7,
+				PositiveSpans:  []prompb.BucketSpan{{Offset: -1, Length: 2}},
+				PositiveCounts: []float64{1.5, 2},
+			},
+			expected: histogram.Histogram{
+				Count:           3.5,
+				Sum:             7,
+				PositiveSpans:   []histogram.Span{{Offset: -1, Length: 2}},
+				PositiveBuckets: []float64{1.5, 2},
+			},
+		},
+		{
+			name:     "empty histogram",
+			input:    prompb.Histogram{},
+			expected: histogram.Histogram{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, PromHistogramToM3Histogram(tt.input))
+		})
+	}
+}
+
+func newTestSketch(t *testing.T) *ddsketch.Sketch {
+	// A relative accuracy of 0.34 results in a schema of 0, whose bucket
+	// boundaries are powers of 2.
+	opts := ddsketch.NewOptions().SetRelativeAccuracy(0.34)
+	require.Equal(t, int32(0), opts.Schema())
+	sketch := ddsketch.NewSketch(opts)
+	sketch.AddBatch([]float64{1, 2, 3, 4, 0, -1, 100})
+	return sketch
+}
+
+func TestSketchToM3Histogram(t *testing.T) {
+	expected := histogram.Histogram{
+		Schema:          0,
+		ZeroCount:       1,
+		Count:           7,
+		Sum:             109,
+		PositiveSpans:   []histogram.Span{{Offset: 0, Length: 3}, {Offset: 4, Length: 1}},
+		PositiveBuckets: []float64{1, 1, 2, 1},
+		NegativeSpans:   []histogram.Span{{Offset: 0, Length: 1}},
+		NegativeBuckets: []float64{1},
+	}
+	actual := SketchToM3Histogram(newTestSketch(t))
+	require.NoError(t, actual.Validate())
+	assert.Equal(t, expected, actual)
+
+	empty := SketchToM3Histogram(ddsketch.NewSketch(ddsketch.NewOptions()))
+	assert.Equal(t, histogram.Histogram{Schema: ddsketch.NewOptions().Schema()}, empty)
+}
+
+func TestSketchAnnotationToHistogramAnnotation(t *testing.T) {
+	sketch := newTestSketch(t)
+	encoded, err := SketchAnnotationToHistogramAnnotation(1234, sketch.Encode())
+	require.NoError(t, err)
+
+	var payload annotation.Payload
+	require.NoError(t, payload.Unmarshal(encoded))
+	assert.Equal(t, annotation.MetricType_HISTOGRAM, payload.MetricType)
+
+	timestamp, h, err := histogram.Decode(payload.NativeHistogram)
+	require.NoError(t, err)
+	assert.Equal(t, xtime.UnixNano(1234), timestamp)
+	assert.Equal(t, SketchToM3Histogram(sketch), h)
+
+	_, err = SketchAnnotationToHistogramAnnotation(1234, []byte("foo"))
+	require.Error(t, err)
+}
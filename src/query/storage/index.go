@@ -21,11 +21,14 @@
 package storage
 
 import (
+	"bytes"
 	"fmt"
+	"regexp/syntax"
 	"time"
 
 	"github.com/m3db/m3/src/dbnode/storage/index"
 	"github.com/m3db/m3/src/m3ninx/idx"
+	m3ninxquery "github.com/m3db/m3/src/m3ninx/search/query"
 	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/query/storage/m3/consolidators"
 	xerrors "github.com/m3db/m3/src/x/errors"
@@ -162,6 +165,17 @@ func FetchOptionsToAggregateOptions(
 func FetchQueryToM3Query(
 	fetchQuery *FetchQuery,
 	options *FetchOptions,
+) (index.Query, error) {
+	return FetchQueryToM3QueryWithNumericFields(fetchQuery, options, nil)
+}
+
+// FetchQueryToM3QueryWithNumericFields converts an m3coordinator fetch query
+// to an M3 query, regexp matchers on the numeric fields that match a range of
+// integers are converted to numeric range queries.
+func FetchQueryToM3QueryWithNumericFields(
+	fetchQuery *FetchQuery,
+	options *FetchOptions,
+	numericFields [][]byte,
 ) (index.Query, error) {
 	fetchQuery = fetchQuery.WithAppliedOptions(options)
 	matchers := fetchQuery.TagMatchers
@@ -186,7 +200,7 @@ func FetchQueryToM3Query(
 			return index.Query{Query: specialCase.query}, nil
 		}
 
-		q, err := matcherToQuery(matchers[0])
+		q, err := matcherToQuery(matchers[0], numericFields)
 		if err != nil {
 			return index.Query{}, err
 		}
@@ -206,7 +220,7 @@ func FetchQueryToM3Query(
 			continue
 		}
 
-		q, err := matcherToQuery(matcher)
+		q, err := matcherToQuery(matcher, numericFields)
 		if err != nil {
 			return index.Query{}, err
 		}
@@ -276,7 +290,7 @@ func isSpecialCaseMatcher(matcher models.Matcher) specialCase {
 	return specialCase{}
 }
 
-func matcherToQuery(matcher models.Matcher) (idx.Query, error) {
+func matcherToQuery(matcher models.Matcher, numericFields [][]byte) (idx.Query, error) {
 	negate := false
 	switch matcher.Type {
 	// Support for Regexp types
@@ -285,14 +299,13 @@ func matcherToQuery(matcher models.Matcher) (idx.Query, error) {
 		fallthrough
 
 	case models.MatchRegexp:
-		var (
-			query idx.Query
-			err   error
-		)
-
-		query, err = idx.NewRegexpQuery(matcher.Name, matcher.Value)
-		if err != nil {
-			return idx.Query{}, err
+		query, ok := regexpToSpecializedQuery(matcher, numericFields)
+		if !ok {
+			var err error
+			query, err = idx.NewRegexpQuery(matcher.Name, matcher.Value)
+			if err != nil {
+				return idx.Query{}, err
+			}
 		}
 
 		if negate {
@@ -333,3 +346,109 @@ func matcherToQuery(matcher models.Matcher) (idx.Query, error) {
 		return idx.Query{}, fmt.Errorf("unsupported query type: %v", matcher)
 	}
 }
+
+// regexpToSpecializedQuery returns a prefix, suffix or numeric range query
+// equivalent to the regexp matcher if there is one, these walk the terms
+// directly rather than evaluating the regexp automaton.
+func regexpToSpecializedQuery(
+	matcher models.Matcher,
+	numericFields [][]byte,
+) (idx.Query, bool) {
+	re, err := syntax.Parse(string(matcher.Value), syntax.Perl)
+	if err != nil {
+		// NB: let the regexp query surface the parse error.
+		return idx.Query{}, false
+	}
+
+	if re.Op == syntax.OpConcat && len(re.Sub) == 2 {
+		first, second := re.Sub[0], re.Sub[1]
+		if m3ninxquery.IsLiteral(first) && m3ninxquery.IsDotStar(second) {
+			return idx.NewPrefixQuery(matcher.Name, []byte(string(first.Rune))), true
+		}
+		if m3ninxquery.IsDotStar(first) && m3ninxquery.IsLiteral(second) {
+			return idx.NewSuffixQuery(matcher.Name, []byte(string(second.Rune))), true
+		}
+	}
+
+	for _, field := range numericFields {
+		if !bytes.Equal(field, matcher.Name) {
+			continue
+		}
+		if min, max, ok := integerRange(re); ok {
+			return idx.NewNumericRangeQuery(matcher.Name, min, max), true
+		}
+		break
+	}
+
+	return idx.Query{}, false
+}
+
+// maxIntegerRangeDigits is the maximum number of digits of the integers matched
+// by a regexp rewritten to a numeric range, so that they are exact as floats.
+const maxIntegerRangeDigits = 15
+
+// integerRange returns the inclusive range of integers matched by a regexp made
+// of a fixed number of digit positions, e.g. `5[0-9][0-9]`, `50[0-4]` or
+// `[45]\d\d`, if the regexp matches exactly the integers without leading zeros
+// in that range. The positions must be literal digits followed by at most one
+// digit range and then any digit, so that the integers matched are contiguous,
+// and the first of several positions must not match a zero. Regexps using `.`
+// are never rewritten since it also matches values that are not integers.
+func integerRange(re *syntax.Regexp) (float64, float64, bool) {
+	subs := []*syntax.Regexp{re}
+	if re.Op == syntax.OpConcat {
+		subs = re.Sub
+	}
+
+	type digitRange struct {
+		lo, hi rune
+	}
+	var positions []digitRange
+	for _, sub := range subs {
+		switch sub.Op {
+		case syntax.OpLiteral:
+			if sub.Flags&syntax.FoldCase != 0 {
+				return 0, 0, false
+			}
+			for _, r := range sub.Rune {
+				if r < '0' || r > '9' {
+					return 0, 0, false
+				}
+				positions = append(positions, digitRange{lo: r, hi: r})
+			}
+		case syntax.OpCharClass:
+			if len(sub.Rune) != 2 || sub.Rune[0] < '0' || sub.Rune[1] > '9' {
+				return 0, 0, false
+			}
+			positions = append(positions, digitRange{lo: sub.Rune[0], hi: sub.Rune[1]})
+		default:
+			return 0, 0, false
+		}
+	}
+
+	if len(positions) == 0 || len(positions) > maxIntegerRangeDigits {
+		return 0, 0, false
+	}
+	if len(positions) > 1 && positions[0].lo == '0' {
+		// The regexp matches values with leading zeros.
+		return 0, 0, false
+	}
+
+	var (
+		min, max float64
+		ranged   bool
+	)
+	for _, p := range positions {
+		if ranged && (p.lo != '0' || p.hi != '9') {
+			// Positions after the first ranged one must match any digit.
+			return 0, 0, false
+		}
+		if p.lo != p.hi {
+			ranged = true
+		}
+		min = min*10 + float64(p.lo-'0')
+		max = max*10 + float64(p.hi-'0')
+	}
+
+	return min, max, true
+}
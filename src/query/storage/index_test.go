@@ -193,8 +193,8 @@ func TestFetchQueryToM3Query(t *testing.T) {
 			},
 		},
 		{
-			name:     "regexp match dot star with trailing characters -> regex",
-			expected: "regexp(t1,.*foo)",
+			name:     "regexp match dot star with trailing characters -> suffix",
+			expected: "suffix(t1,foo)",
 			matchers: models.Matchers{
 				{
 					Type:  models.MatchRegexp,
@@ -215,8 +215,8 @@ func TestFetchQueryToM3Query(t *testing.T) {
 			},
 		},
 		{
-			name:     "not regexp match dot star with trailing characters -> regex",
-			expected: "negation(regexp(t1,.*foo))",
+			name:     "not regexp match dot star with trailing characters -> suffix",
+			expected: "negation(suffix(t1,foo))",
 			matchers: models.Matchers{
 				{
 					Type:  models.MatchNotRegexp,
@@ -236,6 +236,171 @@ func TestFetchQueryToM3Query(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:     "regexp match with trailing dot star -> prefix",
+			expected: "prefix(t1,foo)",
+			matchers: models.Matchers{
+				{
+					Type:  models.MatchRegexp,
+					Name:  []byte("t1"),
+					Value: []byte("foo.*"),
+				},
+			},
+		},
+		{
+			name:     "not regexp match with trailing dot star -> prefix",
+			expected: "negation(prefix(t1,foo))",
+			matchers: models.Matchers{
+				{
+					Type:  models.MatchNotRegexp,
+					Name:  []byte("t1"),
+					Value: []byte("foo.*"),
+				},
+			},
+		},
+		{
+			name:     "case insensitive regexp match with trailing dot star -> regex",
+			expected: "regexp(t1,(?i)foo.*)",
+			matchers: models.Matchers{
+				{
+					Type:  models.MatchRegexp,
+					Name:  []byte("t1"),
+					Value: []byte("(?i)foo.*"),
+				},
+			},
+		},
+		{
+			name:     "regexp match with dot star on both sides -> regex",
+			expected: "regexp(t1,.*foo.*)",
+			matchers: models.Matchers{
+				{
+					Type:  models.MatchRegexp,
+					Name:  []byte("t1"),
+					Value: []byte(".*foo.*"),
+				},
+			},
+		},
+		{
+			name:     "regexp match digits on non numeric field -> regex",
+			expected: "regexp(code,5..)",
+			matchers: models.Matchers{
+				{
+					Type:  models.MatchRegexp,
+					Name:  []byte("code"),
+					Value: []byte("5.."),
+				},
+			},
+		},
+		{
+			name:     "regexp match digits on numeric field -> numeric range",
+			expected: "numeric_range(status,500,599)",
+			matchers: models.Matchers{
+				{
+					Type:  models.MatchRegexp,
+					Name:  []byte("status"),
+					Value: []byte("5[0-9][0-9]"),
+				},
+			},
+		},
+		{
+			name:     "regexp match dots on numeric field -> regex",
+			expected: "regexp(status,5..)",
+			matchers: models.Matchers{
+				{
+					Type:  models.MatchRegexp,
+					Name:  []byte("status"),
+					Value: []byte("5.."),
+				},
+			},
+		},
+		{
+			name:     "regexp match digit classes on numeric field -> numeric range",
+			expected: "numeric_range(status,400,499)",
+			matchers: models.Matchers{
+				{
+					Type:  models.MatchRegexp,
+					Name:  []byte("status"),
+					Value: []byte(`4\d[0-9]`),
+				},
+			},
+		},
+		{
+			name:     "regexp match partial digit class on numeric field -> numeric range",
+			expected: "numeric_range(status,502,504)",
+			matchers: models.Matchers{
+				{
+					Type:  models.MatchRegexp,
+					Name:  []byte("status"),
+					Value: []byte("50[2-4]"),
+				},
+			},
+		},
+		{
+			name:     "regexp match leading digit range on numeric field -> numeric range",
+			expected: "numeric_range(status,10,59)",
+			matchers: models.Matchers{
+				{
+					Type:  models.MatchRegexp,
+					Name:  []byte("status"),
+					Value: []byte(`[1-5]\d`),
+				},
+			},
+		},
+		{
+			name:     "regexp match leading zero on numeric field -> regex",
+			expected: `regexp(status,[0-5]\d)`,
+			matchers: models.Matchers{
+				{
+					Type:  models.MatchRegexp,
+					Name:  []byte("status"),
+					Value: []byte(`[0-5]\d`),
+				},
+			},
+		},
+		{
+			name:     "regexp match single digit on numeric field -> numeric range",
+			expected: "numeric_range(status,0,9)",
+			matchers: models.Matchers{
+				{
+					Type:  models.MatchRegexp,
+					Name:  []byte("status"),
+					Value: []byte(`\d`),
+				},
+			},
+		},
+		{
+			name:     "not regexp match digits on numeric field -> numeric range",
+			expected: "negation(numeric_range(status,200,299))",
+			matchers: models.Matchers{
+				{
+					Type:  models.MatchNotRegexp,
+					Name:  []byte("status"),
+					Value: []byte("2[0-9][0-9]"),
+				},
+			},
+		},
+		{
+			name:     "regexp match non contiguous digits on numeric field -> regex",
+			expected: "regexp(status,[45]0[1-3])",
+			matchers: models.Matchers{
+				{
+					Type:  models.MatchRegexp,
+					Name:  []byte("status"),
+					Value: []byte("[45]0[1-3]"),
+				},
+			},
+		},
+		{
+			name:     "regexp match alternation on numeric field -> regex",
+			expected: "regexp(status,200|404)",
+			matchers: models.Matchers{
+				{
+					Type:  models.MatchRegexp,
+					Name:  []byte("status"),
+					Value: []byte("200|404"),
+				},
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -248,7 +413,8 @@ func TestFetchQueryToM3Query(t *testing.T) {
 				Interval:    15 * time.Second,
 			}
 
-			m3Query, err := FetchQueryToM3Query(fetchQuery, nil)
+			m3Query, err := FetchQueryToM3QueryWithNumericFields(fetchQuery, nil,
+				[][]byte{[]byte("status")})
 			require.NoError(t, err)
 			assert.Equal(t, test.expected, m3Query.String())
 		})
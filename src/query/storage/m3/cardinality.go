@@ -0,0 +1,98 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package m3
+
+import (
+	"context"
+
+	"github.com/m3db/m3/src/dbnode/storage/index"
+	"github.com/m3db/m3/src/query/block"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/storage"
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+type cardinalityStorage struct {
+	clusters   Clusters
+	tagOptions models.TagOptions
+}
+
+// NewCardinalityStorage returns a new cardinality storage that computes the
+// cardinality statistics of the series of the unaggregated cluster namespace.
+func NewCardinalityStorage(
+	clusters Clusters,
+	tagOptions models.TagOptions,
+) storage.CardinalityStorage {
+	return &cardinalityStorage{
+		clusters:   clusters,
+		tagOptions: tagOptions,
+	}
+}
+
+func (s *cardinalityStorage) Cardinality(
+	ctx context.Context,
+	query *storage.CardinalityQuery,
+	options *storage.FetchOptions,
+) (*storage.CardinalityResult, error) {
+	namespace, exists := s.clusters.UnaggregatedClusterNamespace()
+	if !exists {
+		return nil, errUnaggregatedNamespaceUninitialized
+	}
+
+	opts := index.CardinalityOptions{
+		StartInclusive: xtime.ToUnixNano(query.Start),
+		EndExclusive:   xtime.ToUnixNano(query.End),
+		NameField:      s.tagOptions.MetricName(),
+		Limit:          query.Limit,
+	}
+	if options != nil {
+		opts.SeriesLimit = options.SeriesLimit
+		opts.DocsLimit = options.DocsLimit
+		opts.RequireExhaustive = options.RequireExhaustive
+	}
+
+	result, err := namespace.Session().Cardinality(ctx, namespace.NamespaceID(), opts)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := block.NewResultMetadata()
+	metadata.Exhaustive = result.Exhaustive
+	return &storage.CardinalityResult{
+		NumSeries:                   result.NumSeries,
+		SeriesCountByMetricName:     fromIndexCardinalityStats(result.SeriesCountByMetricName),
+		LabelValueCountByLabelName:  fromIndexCardinalityStats(result.LabelValueCountByLabelName),
+		MemoryInBytesByLabelName:    fromIndexCardinalityStats(result.MemoryInBytesByLabelName),
+		SeriesCountByLabelValuePair: fromIndexCardinalityStats(result.SeriesCountByLabelValuePair),
+		Metadata:                    metadata,
+	}, nil
+}
+
+func fromIndexCardinalityStats(stats []index.CardinalityStat) []storage.CardinalityStat {
+	result := make([]storage.CardinalityStat, 0, len(stats))
+	for _, stat := range stats {
+		result = append(result, storage.CardinalityStat{
+			Name:  stat.Name,
+			Value: stat.Value,
+		})
+	}
+	return result
+}
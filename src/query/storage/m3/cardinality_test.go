@@ -0,0 +1,100 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package m3
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/client"
+	"github.com/m3db/m3/src/dbnode/storage/index"
+	"github.com/m3db/m3/src/query/block"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/x/ident"
+	xtest "github.com/m3db/m3/src/x/test"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCardinalityStorage(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	session := client.NewMockSession(ctrl)
+	clusters, err := NewClusters(UnaggregatedClusterNamespaceDefinition{
+		NamespaceID: ident.StringID("metrics_unaggregated"),
+		Session:     session,
+		Retention:   test1MonthRetention,
+	})
+	require.NoError(t, err)
+	store := NewCardinalityStorage(clusters, models.NewTagOptions())
+
+	var (
+		end   = time.Now().Truncate(time.Second)
+		start = end.Add(-time.Hour)
+	)
+	session.EXPECT().
+		Cardinality(gomock.Any(), gomock.Any(), index.CardinalityOptions{
+			StartInclusive:    xtime.ToUnixNano(start),
+			EndExclusive:      xtime.ToUnixNano(end),
+			NameField:         []byte("__name__"),
+			Limit:             5,
+			SeriesLimit:       100,
+			DocsLimit:         200,
+			RequireExhaustive: true,
+		}).
+		DoAndReturn(func(
+			_ context.Context,
+			namespace ident.ID,
+			_ index.CardinalityOptions,
+		) (index.CardinalityResult, error) {
+			assert.Equal(t, "metrics_unaggregated", namespace.String())
+			return index.CardinalityResult{
+				NumSeries:               4,
+				SeriesCountByMetricName: []index.CardinalityStat{{Name: "up", Value: 4}},
+				Exhaustive:              true,
+			}, nil
+		})
+
+	fetchOpts := storage.NewFetchOptions()
+	fetchOpts.SeriesLimit = 100
+	fetchOpts.DocsLimit = 200
+	fetchOpts.RequireExhaustive = true
+	result, err := store.Cardinality(context.Background(), &storage.CardinalityQuery{
+		Start: start,
+		End:   end,
+		Limit: 5,
+	}, fetchOpts)
+	require.NoError(t, err)
+	assert.Equal(t, &storage.CardinalityResult{
+		NumSeries:                   4,
+		SeriesCountByMetricName:     []storage.CardinalityStat{{Name: "up", Value: 4}},
+		LabelValueCountByLabelName:  []storage.CardinalityStat{},
+		MemoryInBytesByLabelName:    []storage.CardinalityStat{},
+		SeriesCountByLabelValuePair: []storage.CardinalityStat{},
+		Metadata:                    block.NewResultMetadata(),
+	}, result)
+}
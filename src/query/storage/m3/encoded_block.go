@@ -91,8 +91,9 @@ func newEncodedBlock(
 		}
 
 		seriesMetas = append(seriesMetas, block.SeriesMeta{
-			Name: iter.ID().Bytes(),
-			Tags: tags,
+			Name:       iter.ID().Bytes(),
+			Tags:       tags,
+			TileCounts: isTileCountIterator(iter),
 		})
 	}
 
@@ -73,8 +73,9 @@ func (b *encodedBlockBuilder) add(
 	}
 
 	seriesMeta := block.SeriesMeta{
-		Name: iter.ID().Bytes(),
-		Tags: tags,
+		Name:       iter.ID().Bytes(),
+		Tags:       tags,
+		TileCounts: isTileCountIterator(iter),
 	}
 
 	for idx, bl := range b.blocksAtTime {
@@ -0,0 +1,118 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package m3
+
+import (
+	"context"
+
+	"github.com/m3db/m3/src/dbnode/client"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/query/storage/m3/consolidators"
+	"github.com/m3db/m3/src/x/ident"
+)
+
+type exemplarStorage struct {
+	clusters   Clusters
+	tagOptions models.TagOptions
+}
+
+// NewExemplarStorage returns a new exemplar storage that stores exemplars
+// alongside the series of the unaggregated cluster namespace.
+func NewExemplarStorage(
+	clusters Clusters,
+	tagOptions models.TagOptions,
+) storage.ExemplarStorage {
+	return &exemplarStorage{
+		clusters:   clusters,
+		tagOptions: tagOptions,
+	}
+}
+
+func (s *exemplarStorage) WriteExemplars(
+	_ context.Context,
+	series []storage.SeriesExemplars,
+) error {
+	namespace, exists := s.clusters.UnaggregatedClusterNamespace()
+	if !exists {
+		return errUnaggregatedNamespaceUninitialized
+	}
+
+	toWrite := make([]client.SeriesExemplars, 0, len(series))
+	for _, elem := range series {
+		if len(elem.Exemplars) == 0 {
+			continue
+		}
+		// NB: the ID must be generated the same way as it is for writes of
+		// the series datapoints so that the exemplars are found by queries.
+		toWrite = append(toWrite, client.SeriesExemplars{
+			ID:        ident.BytesID(elem.Tags.ID()),
+			Exemplars: elem.Exemplars,
+		})
+	}
+	if len(toWrite) == 0 {
+		return nil
+	}
+
+	return namespace.Session().WriteExemplars(namespace.NamespaceID(), toWrite)
+}
+
+func (s *exemplarStorage) FetchExemplars(
+	ctx context.Context,
+	query *storage.FetchQuery,
+	options *storage.FetchOptions,
+) ([]storage.SeriesExemplars, error) {
+	namespace, exists := s.clusters.UnaggregatedClusterNamespace()
+	if !exists {
+		return nil, errUnaggregatedNamespaceUninitialized
+	}
+
+	m3query, err := storage.FetchQueryToM3QueryWithNumericFields(query, options,
+		s.tagOptions.NumericFields())
+	if err != nil {
+		return nil, err
+	}
+
+	m3opts, err := storage.FetchOptionsToM3Options(options, query)
+	if err != nil {
+		return nil, err
+	}
+
+	results, _, err := namespace.Session().FetchExemplars(ctx,
+		namespace.NamespaceID(), m3query, m3opts)
+	if err != nil {
+		return nil, err
+	}
+
+	series := make([]storage.SeriesExemplars, 0, len(results))
+	for _, result := range results {
+		tags, err := consolidators.FromIdentTagIteratorToTags(
+			ident.NewTagsIterator(result.Tags), s.tagOptions)
+		if err != nil {
+			return nil, err
+		}
+		series = append(series, storage.SeriesExemplars{
+			Tags:      tags,
+			Exemplars: result.Exemplars,
+		})
+	}
+	return series, nil
+}
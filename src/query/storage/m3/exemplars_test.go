@@ -0,0 +1,117 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package m3
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/client"
+	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/x/ident"
+	xtest "github.com/m3db/m3/src/x/test"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestExemplarStorage(
+	t *testing.T,
+	ctrl *gomock.Controller,
+) (storage.ExemplarStorage, *client.MockSession) {
+	session := client.NewMockSession(ctrl)
+	clusters, err := NewClusters(UnaggregatedClusterNamespaceDefinition{
+		NamespaceID: ident.StringID("metrics_unaggregated"),
+		Session:     session,
+		Retention:   test1MonthRetention,
+	})
+	require.NoError(t, err)
+	return NewExemplarStorage(clusters, models.NewTagOptions()), session
+}
+
+func TestExemplarStorageWrite(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	store, session := newTestExemplarStorage(t, ctrl)
+
+	tags := models.NewTags(1, nil).AddTag(models.Tag{
+		Name:  []byte("foo"),
+		Value: []byte("bar"),
+	})
+	exemplars := []ts.Exemplar{{TimestampNanos: 1000, Value: 1.5}}
+
+	session.EXPECT().
+		WriteExemplars(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(namespace ident.ID, series []client.SeriesExemplars) error {
+			assert.Equal(t, "metrics_unaggregated", namespace.String())
+			require.Equal(t, 1, len(series))
+			assert.Equal(t, string(tags.ID()), series[0].ID.String())
+			assert.Equal(t, exemplars, series[0].Exemplars)
+			return nil
+		})
+
+	err := store.WriteExemplars(context.Background(), []storage.SeriesExemplars{
+		{Tags: tags, Exemplars: exemplars},
+		// Series without exemplars are not written.
+		{Tags: tags},
+	})
+	require.NoError(t, err)
+}
+
+func TestExemplarStorageFetch(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	store, session := newTestExemplarStorage(t, ctrl)
+
+	exemplars := []ts.Exemplar{{TimestampNanos: 1000, Value: 1.5}}
+	session.EXPECT().
+		FetchExemplars(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return([]client.SeriesExemplars{
+			{
+				ID:        ident.StringID("foo=bar"),
+				Tags:      ident.NewTags(ident.StringTag("foo", "bar")),
+				Exemplars: exemplars,
+			},
+		}, client.FetchResponseMetadata{Exhaustive: true}, nil)
+
+	query := &storage.FetchQuery{
+		TagMatchers: models.Matchers{
+			{Type: models.MatchEqual, Name: []byte("foo"), Value: []byte("bar")},
+		},
+		Start: time.Now().Add(-time.Hour),
+		End:   time.Now(),
+	}
+	results, err := store.FetchExemplars(context.Background(), query,
+		buildFetchOpts())
+	require.NoError(t, err)
+	require.Equal(t, 1, len(results))
+
+	value, ok := results[0].Tags.Get([]byte("foo"))
+	require.True(t, ok)
+	assert.Equal(t, "bar", string(value))
+	assert.Equal(t, exemplars, results[0].Exemplars)
+}
@@ -0,0 +1,362 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package m3
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/dbnode/encoding/histogram"
+	"github.com/m3db/m3/src/dbnode/encoding/m3tsz"
+	"github.com/m3db/m3/src/dbnode/generated/proto/annotation"
+	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/dbnode/x/xio"
+	"github.com/m3db/m3/src/metrics/metric"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/x/ident"
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+var (
+	nativeHistogramEncodingOpts = encoding.NewOptions()
+	nativeHistogramIterAlloc    = m3tsz.DefaultReaderIteratorAllocFn(
+		nativeHistogramEncodingOpts)
+	nativeHistogramInfBound = []byte("+Inf")
+)
+
+type nativeHistogramSample struct {
+	timestamp xtime.UnixNano
+	unit      xtime.Unit
+	histogram histogram.Histogram
+	buckets   []histogram.Bucket
+}
+
+// expandNativeHistograms expands each series of native histograms into a
+// series per bucket boundary holding the cumulative count of the bucket,
+// labelled with the bucket tag the same as classic Prometheus histograms, and
+// a series holding the sum of the histogram. Other series are returned as is.
+func expandNativeHistograms(
+	iters encoding.SeriesIterators,
+	tagOpts models.TagOptions,
+) (encoding.SeriesIterators, error) {
+	if iters == nil {
+		return iters, nil
+	}
+
+	var (
+		seriesIters = iters.Iters()
+		hasNative   = false
+	)
+	for _, iter := range seriesIters {
+		if isNativeHistogramSeries(iter) {
+			hasNative = true
+			break
+		}
+	}
+	if !hasNative {
+		return iters, nil
+	}
+
+	var (
+		results  = make([]encoding.SeriesIterator, 0, len(seriesIters))
+		native   = make([]bool, len(seriesIters))
+		expanded []encoding.SeriesIterator
+	)
+	for i, iter := range seriesIters {
+		if !isNativeHistogramSeries(iter) {
+			results = append(results, iter)
+			continue
+		}
+
+		native[i] = true
+		expandedIters, err := expandNativeHistogram(iter, tagOpts)
+		if err != nil {
+			closeSeriesIterators(expanded)
+			iters.Close()
+			return nil, err
+		}
+		expanded = append(expanded, expandedIters...)
+		results = append(results, expandedIters...)
+	}
+
+	// The series iterators that were not expanded are now owned by the
+	// returned series iterators, so only the native histogram series
+	// iterators are closed along with the original series iterators.
+	mutable, ok := iters.(encoding.MutableSeriesIterators)
+	for i, iter := range seriesIters {
+		switch {
+		case native[i] && !ok:
+			iter.Close()
+		case !native[i] && ok:
+			mutable.SetAt(i, nil)
+		}
+	}
+	if ok {
+		mutable.Close()
+	}
+
+	return encoding.NewSeriesIterators(results, nil), nil
+}
+
+func closeSeriesIterators(iters []encoding.SeriesIterator) {
+	for _, iter := range iters {
+		iter.Close()
+	}
+}
+
+func isNativeHistogramSeries(iter encoding.SeriesIterator) bool {
+	// NB: the series ID is generated from the tags of the series, so it is
+	// checked for the marker tag first to avoid iterating the tags of every
+	// series fetched.
+	if id := iter.ID(); id == nil ||
+		!bytes.Contains(id.Bytes(), metric.M3PromNativeHistogramTag) {
+		return false
+	}
+
+	value, ok := tagValue(iter.Tags(), metric.M3PromNativeHistogramTag)
+	return ok && bytes.Equal(value, metric.PromNativeHistogramEncodedValue)
+}
+
+func tagValue(tags ident.TagIterator, name []byte) ([]byte, bool) {
+	if tags == nil {
+		return nil, false
+	}
+
+	tags = tags.Duplicate()
+	defer tags.Close()
+	for tags.Next() {
+		tag := tags.Current()
+		if bytes.Equal(tag.Name.Bytes(), name) {
+			return tag.Value.Bytes(), true
+		}
+	}
+	return nil, false
+}
+
+func expandNativeHistogram(
+	iter encoding.SeriesIterator,
+	tagOpts models.TagOptions,
+) ([]encoding.SeriesIterator, error) {
+	samples, err := readNativeHistogramSamples(iter)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		bounds    = nativeHistogramBounds(samples)
+		bucketDps = make([][]ts.Datapoint, len(bounds)+1)
+		sumDps    = make([]ts.Datapoint, 0, len(samples))
+	)
+	for i := range bucketDps {
+		bucketDps[i] = make([]ts.Datapoint, 0, len(samples))
+	}
+	for _, sample := range samples {
+		var (
+			cumulative float64
+			b          int
+		)
+		for i, bound := range bounds {
+			for ; b < len(sample.buckets) && sample.buckets[b].Upper <= bound; b++ {
+				cumulative += sample.buckets[b].Count
+			}
+			bucketDps[i] = append(bucketDps[i], ts.Datapoint{
+				TimestampNanos: sample.timestamp,
+				Value:          cumulative,
+			})
+		}
+		bucketDps[len(bounds)] = append(bucketDps[len(bounds)], ts.Datapoint{
+			TimestampNanos: sample.timestamp,
+			Value:          sample.histogram.Count,
+		})
+		sumDps = append(sumDps, ts.Datapoint{
+			TimestampNanos: sample.timestamp,
+			Value:          sample.histogram.Sum,
+		})
+	}
+
+	unit := xtime.Millisecond
+	if len(samples) > 0 {
+		unit = samples[0].unit
+	}
+
+	results := make([]encoding.SeriesIterator, 0, len(bucketDps)+1)
+	for i, dps := range bucketDps {
+		bound := nativeHistogramInfBound
+		if i < len(bounds) {
+			bound = []byte(strconv.FormatFloat(bounds[i], 'g', -1, 64))
+		}
+		result, err := newNativeHistogramSeriesIterator(iter, dps, unit,
+			metric.PromNativeHistogramBucketValue, tagOpts.BucketName(), bound)
+		if err != nil {
+			closeSeriesIterators(results)
+			return nil, err
+		}
+		results = append(results, result)
+	}
+
+	result, err := newNativeHistogramSeriesIterator(iter, sumDps, unit,
+		metric.PromNativeHistogramSumValue, nil, nil)
+	if err != nil {
+		closeSeriesIterators(results)
+		return nil, err
+	}
+	results = append(results, result)
+	return results, nil
+}
+
+// readNativeHistogramSamples reads the histograms of each of the datapoints
+// of the series.
+func readNativeHistogramSamples(
+	iter encoding.SeriesIterator,
+) ([]nativeHistogramSample, error) {
+	var samples []nativeHistogramSample
+	for iter.Next() {
+		dp, unit, annot := iter.Current()
+		if len(annot) == 0 {
+			// NB: each histogram is encoded with its timestamp so every
+			// datapoint written has an annotation, datapoints without one
+			// have no known histogram and are skipped.
+			continue
+		}
+
+		var payload annotation.Payload
+		if err := payload.Unmarshal(annot); err != nil {
+			return nil, err
+		}
+		if len(payload.NativeHistogram) == 0 {
+			continue
+		}
+
+		timestamp, value, err := histogram.Decode(payload.NativeHistogram)
+		if err != nil {
+			return nil, err
+		}
+		if timestamp != dp.TimestampNanos {
+			continue
+		}
+
+		samples = append(samples, nativeHistogramSample{
+			timestamp: timestamp,
+			unit:      unit,
+			histogram: value,
+			buckets:   value.Buckets(),
+		})
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+
+	return samples, nil
+}
+
+// nativeHistogramBounds returns the union of the finite boundaries of the
+// buckets of all the samples in increasing order. The lower boundaries are
+// included so that the boundaries of every populated bucket are kept, even
+// when the bucket below it is not populated.
+func nativeHistogramBounds(samples []nativeHistogramSample) []float64 {
+	seen := make(map[float64]struct{})
+	for _, sample := range samples {
+		for _, bucket := range sample.buckets {
+			for _, bound := range []float64{bucket.Lower, bucket.Upper} {
+				if math.IsInf(bound, 0) {
+					continue
+				}
+				seen[bound] = struct{}{}
+			}
+		}
+	}
+
+	bounds := make([]float64, 0, len(seen))
+	for bound := range seen {
+		bounds = append(bounds, bound)
+	}
+	sort.Float64s(bounds)
+	return bounds
+}
+
+// newNativeHistogramSeriesIterator returns a series iterator over the
+// datapoints with the tags of the native histogram series, the marker tag set
+// to the given value and the additional tag if set.
+func newNativeHistogramSeriesIterator(
+	iter encoding.SeriesIterator,
+	dps []ts.Datapoint,
+	unit xtime.Unit,
+	markerValue []byte,
+	name, value []byte,
+) (encoding.SeriesIterator, error) {
+	var (
+		start = iter.Start()
+		end   = iter.End()
+	)
+	encoder := m3tsz.NewEncoder(start, nil, true, nativeHistogramEncodingOpts)
+	for _, dp := range dps {
+		if err := encoder.Encode(dp, unit, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	var readers [][]xio.BlockReader
+	if len(dps) > 0 {
+		readers = [][]xio.BlockReader{{{
+			SegmentReader: xio.NewSegmentReader(encoder.Discard()),
+			Start:         start,
+			BlockSize:     end.Sub(start),
+		}}}
+	}
+	multiReader := encoding.NewMultiReaderIterator(nativeHistogramIterAlloc, nil)
+	multiReader.ResetSliceOfSlices(
+		xio.NewReaderSliceOfSlicesFromBlockReadersIterator(readers), nil)
+
+	tags := ident.NewTags()
+	if iterTags := iter.Tags(); iterTags != nil {
+		iterTags = iterTags.Duplicate()
+		for iterTags.Next() {
+			tag := iterTags.Current()
+			tagValue := tag.Value.Bytes()
+			if bytes.Equal(tag.Name.Bytes(), metric.M3PromNativeHistogramTag) {
+				tagValue = markerValue
+			}
+			tags.Append(ident.StringTag(tag.Name.String(), string(tagValue)))
+		}
+		iterTags.Close()
+	}
+
+	id := iter.ID().String()
+	if len(name) > 0 {
+		tags.Append(ident.StringTag(string(name), string(value)))
+		id = fmt.Sprintf("%s,%s=%s", id, name, value)
+	} else {
+		id = fmt.Sprintf("%s,%s=%s", id, metric.M3PromNativeHistogramTag, markerValue)
+	}
+
+	return encoding.NewSeriesIterator(encoding.SeriesIteratorOptions{
+		ID:             ident.StringID(id),
+		Namespace:      ident.StringID(iter.Namespace().String()),
+		Tags:           ident.NewTagsIterator(tags),
+		StartInclusive: start,
+		EndExclusive:   end,
+		Replicas:       []encoding.MultiReaderIterator{multiReader},
+	}, nil), nil
+}
@@ -0,0 +1,286 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package m3
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/dbnode/encoding/histogram"
+	"github.com/m3db/m3/src/dbnode/encoding/m3tsz"
+	"github.com/m3db/m3/src/dbnode/generated/proto/annotation"
+	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/dbnode/x/xio"
+	"github.com/m3db/m3/src/metrics/metric"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/x/ident"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testNativeHistogramSeries struct {
+	id     string
+	tags   map[string]string
+	dps    []ts.Datapoint
+	annots [][]byte
+}
+
+func newTestNativeHistogramSeriesIterator(
+	t *testing.T,
+	start xtime.UnixNano,
+	series testNativeHistogramSeries,
+) encoding.SeriesIterator {
+	encoder := m3tsz.NewEncoder(start, nil, true, nativeHistogramEncodingOpts)
+	for i, dp := range series.dps {
+		var annot ts.Annotation
+		if i < len(series.annots) {
+			annot = series.annots[i]
+		}
+		require.NoError(t, encoder.Encode(dp, xtime.Millisecond, annot))
+	}
+
+	multiReader := encoding.NewMultiReaderIterator(nativeHistogramIterAlloc, nil)
+	multiReader.ResetSliceOfSlices(
+		xio.NewReaderSliceOfSlicesFromBlockReadersIterator([][]xio.BlockReader{{{
+			SegmentReader: xio.NewSegmentReader(encoder.Discard()),
+			Start:         start,
+			BlockSize:     time.Hour,
+		}}}), nil)
+
+	tags := ident.NewTags()
+	for name, value := range series.tags {
+		tags.Append(ident.StringTag(name, value))
+	}
+
+	return encoding.NewSeriesIterator(encoding.SeriesIteratorOptions{
+		ID:             ident.StringID(series.id),
+		Namespace:      ident.StringID("ns"),
+		Tags:           ident.NewTagsIterator(tags),
+		StartInclusive: start,
+		EndExclusive:   start.Add(time.Hour),
+		Replicas:       []encoding.MultiReaderIterator{multiReader},
+	}, nil)
+}
+
+func encodeTestNativeHistogram(
+	t *testing.T,
+	timestamp xtime.UnixNano,
+	h histogram.Histogram,
+) []byte {
+	payload := annotation.Payload{
+		MetricType:      annotation.MetricType_HISTOGRAM,
+		NativeHistogram: histogram.Encode(timestamp, h),
+	}
+	annot, err := payload.Marshal()
+	require.NoError(t, err)
+	return annot
+}
+
+type testExpandedSeries struct {
+	id   string
+	tags map[string]string
+	dps  []ts.Datapoint
+}
+
+func readTestExpandedSeries(
+	t *testing.T,
+	iters encoding.SeriesIterators,
+) []testExpandedSeries {
+	var result []testExpandedSeries
+	for _, iter := range iters.Iters() {
+		series := testExpandedSeries{
+			id:   iter.ID().String(),
+			tags: make(map[string]string),
+		}
+		tags := iter.Tags()
+		for tags.Next() {
+			tag := tags.Current()
+			series.tags[tag.Name.String()] = tag.Value.String()
+		}
+		require.NoError(t, tags.Err())
+		for iter.Next() {
+			dp, _, _ := iter.Current()
+			series.dps = append(series.dps, ts.Datapoint{
+				TimestampNanos: dp.TimestampNanos,
+				Value:          dp.Value,
+			})
+		}
+		require.NoError(t, iter.Err())
+		result = append(result, series)
+	}
+	return result
+}
+
+func TestExpandNativeHistograms(t *testing.T) {
+	var (
+		start  = xtime.Now().Truncate(time.Hour)
+		t1     = start.Add(time.Minute)
+		t2     = start.Add(2 * time.Minute)
+		t3     = start.Add(3 * time.Minute)
+		marker = string(metric.M3PromNativeHistogramTag)
+		id     = `{__name__="native",` + marker + `="encoded"}`
+	)
+
+	first := histogram.Histogram{
+		Count:           3,
+		Sum:             2.5,
+		PositiveSpans:   []histogram.Span{{Offset: 0, Length: 2}},
+		PositiveBuckets: []float64{1, 2},
+	}
+	second := histogram.Histogram{
+		Count:           6,
+		Sum:             9,
+		ZeroCount:       1,
+		ZeroThreshold:   0.25,
+		PositiveSpans:   []histogram.Span{{Offset: 1, Length: 2}},
+		PositiveBuckets: []float64{3, 2},
+	}
+
+	native := newTestNativeHistogramSeriesIterator(t, start, testNativeHistogramSeries{
+		id: id,
+		tags: map[string]string{
+			"__name__": "native",
+			marker:     "encoded",
+		},
+		dps: []ts.Datapoint{
+			{TimestampNanos: t1, Value: 3},
+			{TimestampNanos: t2, Value: 6},
+			// Datapoints without a histogram are skipped.
+			{TimestampNanos: t3, Value: 6},
+		},
+		annots: [][]byte{
+			encodeTestNativeHistogram(t, t1, first),
+			encodeTestNativeHistogram(t, t2, second),
+		},
+	})
+	float := newTestNativeHistogramSeriesIterator(t, start, testNativeHistogramSeries{
+		id:   `{__name__="float"}`,
+		tags: map[string]string{"__name__": "float"},
+		dps:  []ts.Datapoint{{TimestampNanos: t1, Value: 42}},
+	})
+
+	iters, err := expandNativeHistograms(
+		encoding.NewSeriesIterators([]encoding.SeriesIterator{float, native}, nil),
+		models.NewTagOptions())
+	require.NoError(t, err)
+	defer iters.Close()
+
+	bucketTags := func(le string) map[string]string {
+		return map[string]string{
+			"__name__": "native",
+			marker:     "bucket",
+			"le":       le,
+		}
+	}
+	assert.Equal(t, []testExpandedSeries{
+		{
+			id:   `{__name__="float"}`,
+			tags: map[string]string{"__name__": "float"},
+			dps:  []ts.Datapoint{{TimestampNanos: t1, Value: 42}},
+		},
+		{
+			id:   id + ",le=-0.25",
+			tags: bucketTags("-0.25"),
+			dps: []ts.Datapoint{
+				{TimestampNanos: t1, Value: 0},
+				{TimestampNanos: t2, Value: 0},
+			},
+		},
+		{
+			id:   id + ",le=0.25",
+			tags: bucketTags("0.25"),
+			dps: []ts.Datapoint{
+				{TimestampNanos: t1, Value: 0},
+				{TimestampNanos: t2, Value: 1},
+			},
+		},
+		{
+			id:   id + ",le=0.5",
+			tags: bucketTags("0.5"),
+			dps: []ts.Datapoint{
+				{TimestampNanos: t1, Value: 0},
+				{TimestampNanos: t2, Value: 1},
+			},
+		},
+		{
+			id:   id + ",le=1",
+			tags: bucketTags("1"),
+			dps: []ts.Datapoint{
+				{TimestampNanos: t1, Value: 1},
+				{TimestampNanos: t2, Value: 1},
+			},
+		},
+		{
+			id:   id + ",le=2",
+			tags: bucketTags("2"),
+			dps: []ts.Datapoint{
+				{TimestampNanos: t1, Value: 3},
+				{TimestampNanos: t2, Value: 4},
+			},
+		},
+		{
+			id:   id + ",le=4",
+			tags: bucketTags("4"),
+			dps: []ts.Datapoint{
+				{TimestampNanos: t1, Value: 3},
+				{TimestampNanos: t2, Value: 6},
+			},
+		},
+		{
+			id:   id + ",le=+Inf",
+			tags: bucketTags("+Inf"),
+			dps: []ts.Datapoint{
+				{TimestampNanos: t1, Value: 3},
+				{TimestampNanos: t2, Value: 6},
+			},
+		},
+		{
+			id: id + "," + marker + "=sum",
+			tags: map[string]string{
+				"__name__": "native",
+				marker:     "sum",
+			},
+			dps: []ts.Datapoint{
+				{TimestampNanos: t1, Value: 2.5},
+				{TimestampNanos: t2, Value: 9},
+			},
+		},
+	}, readTestExpandedSeries(t, iters))
+}
+
+func TestExpandNativeHistogramsNoNativeHistograms(t *testing.T) {
+	start := xtime.Now().Truncate(time.Hour)
+	iters := encoding.NewSeriesIterators([]encoding.SeriesIterator{
+		newTestNativeHistogramSeriesIterator(t, start, testNativeHistogramSeries{
+			id:   `{__name__="float"}`,
+			tags: map[string]string{"__name__": "float"},
+			dps:  []ts.Datapoint{{TimestampNanos: start, Value: 1}},
+		}),
+	}, nil)
+	defer iters.Close()
+
+	result, err := expandNativeHistograms(iters, models.NewTagOptions())
+	require.NoError(t, err)
+	assert.Equal(t, iters, result)
+}
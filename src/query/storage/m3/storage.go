@@ -32,7 +32,9 @@ import (
 	"go.uber.org/zap/zapcore"
 
 	"github.com/m3db/m3/src/dbnode/client"
+	"github.com/m3db/m3/src/dbnode/encoding"
 	"github.com/m3db/m3/src/dbnode/storage/index"
+	"github.com/m3db/m3/src/dbnode/storage/limits"
 	"github.com/m3db/m3/src/query/block"
 	"github.com/m3db/m3/src/query/errors"
 	"github.com/m3db/m3/src/query/models"
@@ -42,6 +44,7 @@ import (
 	"github.com/m3db/m3/src/query/tracepoint"
 	"github.com/m3db/m3/src/query/ts"
 	xcontext "github.com/m3db/m3/src/x/context"
+	xerrors "github.com/m3db/m3/src/x/errors"
 	"github.com/m3db/m3/src/x/ident"
 	"github.com/m3db/m3/src/x/instrument"
 	xtime "github.com/m3db/m3/src/x/time"
@@ -294,7 +297,8 @@ func (s *m3storage) fetchCompressed(
 	default:
 	}
 
-	m3query, err := storage.FetchQueryToM3Query(query, options)
+	m3query, err := storage.FetchQueryToM3QueryWithNumericFields(query, options,
+		s.opts.TagOptions().NumericFields())
 	if err != nil {
 		return nil, index.Query{}, err
 	}
@@ -375,7 +379,13 @@ func (s *m3storage) fetchCompressed(
 
 			session := namespace.Session()
 			namespaceID := namespace.NamespaceID()
-			iters, metadata, err := session.FetchTagged(ctx, namespaceID, m3query, queryOptions)
+			iters, metadata, err := fetchTagged(ctx, session, namespaceID, m3query, queryOptions)
+			if err == nil {
+				iters, err = expandNativeHistograms(iters, tagOpts)
+			}
+			if err == nil {
+				iters = selectTileAggregates(iters, options.FunctionHint)
+			}
 			if err == nil && sampled {
 				span.LogFields(
 					log.String("namespace", namespaceID.String()),
@@ -413,6 +423,54 @@ func (s *m3storage) fetchCompressed(
 	return result, m3query, err
 }
 
+// fetchTagged fetches the series matching the query, streaming them from
+// the nodes when the session has streaming reads enabled so that the
+// results from every replica are not buffered in memory at once. The
+// streamed series are buffered until the fetch completes, bounded by the
+// series limit of the query and the streaming reads bytes limit of the
+// session.
+func fetchTagged(
+	ctx context.Context,
+	session client.Session,
+	namespaceID ident.ID,
+	query index.Query,
+	opts index.QueryOptions,
+) (encoding.SeriesIterators, client.FetchResponseMetadata, error) {
+	streamingSession, ok := session.(client.StreamingReadsSession)
+	if !ok || !streamingSession.StreamingReadsEnabled() {
+		return session.FetchTagged(ctx, namespaceID, query, opts)
+	}
+
+	var (
+		iters   []encoding.SeriesIterator
+		dropped bool
+	)
+	metadata, err := session.FetchTaggedStream(ctx, namespaceID, query, opts,
+		func(iter encoding.SeriesIterator) error {
+			if opts.SeriesLimit > 0 && len(iters) >= opts.SeriesLimit {
+				iter.Close()
+				if opts.RequireExhaustive {
+					return xerrors.NewInvalidParamsError(limits.NewQueryLimitExceededError(
+						fmt.Sprintf("query exceeded limit: require_exhaustive=%v, series_limit=%d",
+							opts.RequireExhaustive, opts.SeriesLimit)))
+				}
+				dropped = true
+				return nil
+			}
+			iters = append(iters, iter)
+			return nil
+		})
+	result := encoding.NewSeriesIterators(iters, nil)
+	if err != nil {
+		result.Close()
+		return nil, client.FetchResponseMetadata{}, err
+	}
+	if dropped {
+		metadata.Exhaustive = false
+	}
+	return result, metadata, nil
+}
+
 func (s *m3storage) SearchSeries(
 	ctx context.Context,
 	query *storage.FetchQuery,
@@ -466,7 +524,8 @@ func (s *m3storage) CompleteTags(
 		TagMatchers: query.TagMatchers,
 	}
 
-	m3query, err := storage.FetchQueryToM3Query(fetchQuery, options)
+	m3query, err := storage.FetchQueryToM3QueryWithNumericFields(fetchQuery, options,
+		s.opts.TagOptions().NumericFields())
 	if err != nil {
 		return nil, err
 	}
@@ -629,7 +688,8 @@ func (s *m3storage) SearchCompressed(
 	default:
 	}
 
-	m3query, err := storage.FetchQueryToM3Query(query, options)
+	m3query, err := storage.FetchQueryToM3QueryWithNumericFields(query, options,
+		s.opts.TagOptions().NumericFields())
 	if err != nil {
 		return tagResult, noop, err
 	}
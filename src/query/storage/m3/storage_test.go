@@ -30,6 +30,7 @@ import (
 
 	"github.com/m3db/m3/src/dbnode/client"
 	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/dbnode/storage/index"
 	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/query/storage"
 	"github.com/m3db/m3/src/query/storage/m3/consolidators"
@@ -891,3 +892,63 @@ func TestInvalidBlockTypes(t *testing.T) {
 	defer instrument.SetShouldPanicEnvironmentVariable(true)()
 	require.Panics(t, func() { _, _ = s.FetchBlocks(context.TODO(), query, fetchOpts) })
 }
+
+type testStreamingSession struct {
+	*client.MockSession
+}
+
+func (s testStreamingSession) StreamingReadsEnabled() bool {
+	return true
+}
+
+func TestFetchTaggedStreamBoundsBufferedSeries(t *testing.T) {
+	for _, requireExhaustive := range []bool{false, true} {
+		t.Run(fmt.Sprintf("require exhaustive %v", requireExhaustive), func(t *testing.T) {
+			ctrl := xtest.NewController(t)
+			defer ctrl.Finish()
+
+			const (
+				seriesLimit    = 5
+				streamedSeries = 50
+			)
+			var closed int
+			session := client.NewMockSession(ctrl)
+			session.EXPECT().
+				FetchTaggedStream(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				DoAndReturn(func(
+					_ context.Context,
+					_ ident.ID,
+					_ index.Query,
+					_ index.QueryOptions,
+					fn func(encoding.SeriesIterator) error,
+				) (client.FetchResponseMetadata, error) {
+					for i := 0; i < streamedSeries; i++ {
+						iter := encoding.NewMockSeriesIterator(ctrl)
+						iter.EXPECT().Close().Do(func() { closed++ }).AnyTimes()
+						if err := fn(iter); err != nil {
+							return client.FetchResponseMetadata{}, err
+						}
+					}
+					return testFetchResponseMetadata, nil
+				})
+
+			iters, meta, err := fetchTagged(context.Background(),
+				testStreamingSession{MockSession: session}, ident.StringID("ns"),
+				index.Query{}, index.QueryOptions{
+					SeriesLimit:       seriesLimit,
+					RequireExhaustive: requireExhaustive,
+				})
+			if requireExhaustive {
+				require.Error(t, err)
+				// The buffered series are closed along with the series over the limit.
+				assert.Equal(t, seriesLimit+1, closed)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, seriesLimit, iters.Len())
+			assert.Equal(t, streamedSeries-seriesLimit, closed)
+			assert.False(t, meta.Exhaustive)
+		})
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package m3
+
+import (
+	"bytes"
+	"math"
+
+	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/dbnode/generated/proto/annotation"
+	"github.com/m3db/m3/src/dbnode/ts"
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+type tileAggregate uint
+
+const (
+	// lastTileAggregate is the value of a tile, the last sample of gauges
+	// and the reset corrected value of counters.
+	lastTileAggregate tileAggregate = iota
+	minTileAggregate
+	maxTileAggregate
+	sumTileAggregate
+	// countTileAggregate is the number of samples of a tile, datapoints that
+	// are not tiles are a single sample. The count over time is the sum over
+	// time of these values.
+	countTileAggregate
+)
+
+// tileAggregateForFunction returns the aggregate of tiles which the function
+// over time applied to the series is best evaluated on. The average over time
+// has no aggregate, since the mean of each tile is not weighted by its count;
+// it is instead evaluated as the sum over time divided by the count over time.
+func tileAggregateForFunction(name string) tileAggregate {
+	switch name {
+	case "min_over_time":
+		return minTileAggregate
+	case "max_over_time":
+		return maxTileAggregate
+	case "sum_over_time":
+		return sumTileAggregate
+	case "count_over_time":
+		return countTileAggregate
+	default:
+		return lastTileAggregate
+	}
+}
+
+// selectTileAggregates replaces the values of the datapoints downsampled
+// into tiles by the aggregate of their tile matching the function applied to
+// the series. Datapoints that are not tiles are returned as is.
+func selectTileAggregates(
+	iters encoding.SeriesIterators,
+	functionHint string,
+) encoding.SeriesIterators {
+	aggregate := tileAggregateForFunction(functionHint)
+	if iters == nil || aggregate == lastTileAggregate {
+		return iters
+	}
+
+	seriesIters := iters.Iters()
+	results := make([]encoding.SeriesIterator, 0, len(seriesIters))
+	for _, iter := range seriesIters {
+		results = append(results, &tileSeriesIterator{
+			SeriesIterator: iter,
+			aggregate:      aggregate,
+		})
+	}
+
+	// The series iterators are now owned by the returned series iterators.
+	if mutable, ok := iters.(encoding.MutableSeriesIterators); ok {
+		for i := range seriesIters {
+			mutable.SetAt(i, nil)
+		}
+		mutable.Close()
+	}
+
+	return encoding.NewSeriesIterators(results, nil)
+}
+
+// isTileCountIterator returns true if the datapoints of the series iterator
+// are the number of samples of each datapoint.
+func isTileCountIterator(iter encoding.SeriesIterator) bool {
+	tileIter, ok := iter.(*tileSeriesIterator)
+	return ok && tileIter.aggregate == countTileAggregate
+}
+
+// tileSeriesIterator selects the aggregate of the tile of each datapoint,
+// the tile is carried forward from the last annotation of the series since
+// annotations are only encoded when they change.
+type tileSeriesIterator struct {
+	encoding.SeriesIterator
+
+	aggregate  tileAggregate
+	annotation ts.Annotation
+	tile       *annotation.Tile
+	dp         ts.Datapoint
+	unit       xtime.Unit
+	currAnnot  ts.Annotation
+}
+
+func (it *tileSeriesIterator) Next() bool {
+	if !it.SeriesIterator.Next() {
+		return false
+	}
+
+	it.dp, it.unit, it.currAnnot = it.SeriesIterator.Current()
+	if len(it.currAnnot) > 0 && !bytes.Equal(it.currAnnot, it.annotation) {
+		it.annotation = append(it.annotation[:0], it.currAnnot...)
+		it.tile = nil
+		var payload annotation.Payload
+		if err := payload.Unmarshal(it.currAnnot); err == nil {
+			it.tile = payload.Tile
+		}
+	}
+
+	// NB: counter tiles have no samples counted and keep their value.
+	isTile := it.tile != nil && it.tile.Count > 0
+	switch {
+	case it.aggregate == countTileAggregate && isTile:
+		it.dp.Value = float64(it.tile.Count)
+	case it.aggregate == countTileAggregate && !math.IsNaN(it.dp.Value):
+		it.dp.Value = 1
+	case it.aggregate == minTileAggregate && isTile:
+		it.dp.Value = it.tile.Min
+	case it.aggregate == maxTileAggregate && isTile:
+		it.dp.Value = it.tile.Max
+	case it.aggregate == sumTileAggregate && isTile:
+		it.dp.Value = it.tile.Sum
+	}
+	return true
+}
+
+func (it *tileSeriesIterator) Current() (ts.Datapoint, xtime.Unit, ts.Annotation) {
+	return it.dp, it.unit, it.currAnnot
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package m3
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/dbnode/generated/proto/annotation"
+	"github.com/m3db/m3/src/dbnode/ts"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func encodeTestTile(t *testing.T, tile *annotation.Tile) []byte {
+	payload := annotation.Payload{MetricType: annotation.MetricType_GAUGE, Tile: tile}
+	annot, err := payload.Marshal()
+	require.NoError(t, err)
+	return annot
+}
+
+func readTestTileValues(t *testing.T, iters encoding.SeriesIterators) []float64 {
+	require.Len(t, iters.Iters(), 1)
+	var (
+		iter   = iters.Iters()[0]
+		values []float64
+	)
+	for iter.Next() {
+		dp, _, _ := iter.Current()
+		values = append(values, dp.Value)
+	}
+	require.NoError(t, iter.Err())
+	return values
+}
+
+func TestSelectTileAggregates(t *testing.T) {
+	var (
+		start  = xtime.Now().Truncate(time.Hour)
+		series = testNativeHistogramSeries{
+			id: "tiles",
+			dps: []ts.Datapoint{
+				{TimestampNanos: start.Add(time.Minute), Value: 3},
+				{TimestampNanos: start.Add(2 * time.Minute), Value: 4},
+				{TimestampNanos: start.Add(3 * time.Minute), Value: 10},
+				{TimestampNanos: start.Add(4 * time.Minute), Value: 5},
+			},
+			annots: [][]byte{
+				encodeTestTile(t, &annotation.Tile{Min: 1, Max: 5, Sum: 9, Count: 3}),
+				encodeTestTile(t, &annotation.Tile{Min: 2, Max: 8, Sum: 10, Count: 2}),
+				// A counter tile keeps its value.
+				encodeTestTile(t, &annotation.Tile{ResetOffset: 7}),
+				// Not a tile.
+				encodeTestTile(t, nil),
+			},
+		}
+	)
+
+	tests := []struct {
+		function string
+		expected []float64
+	}{
+		{function: "", expected: []float64{3, 4, 10, 5}},
+		{function: "rate", expected: []float64{3, 4, 10, 5}},
+		{function: "min_over_time", expected: []float64{1, 2, 10, 5}},
+		{function: "max", expected: []float64{3, 4, 10, 5}},
+		{function: "max_over_time", expected: []float64{5, 8, 10, 5}},
+		{function: "sum_over_time", expected: []float64{9, 10, 10, 5}},
+		{function: "count_over_time", expected: []float64{3, 2, 1, 1}},
+		{function: "avg_over_time", expected: []float64{3, 4, 10, 5}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.function, func(t *testing.T) {
+			iters := encoding.NewSeriesIterators([]encoding.SeriesIterator{
+				newTestNativeHistogramSeriesIterator(t, start, series),
+			}, nil)
+
+			result := selectTileAggregates(iters, tt.function)
+			defer result.Close()
+			assert.Equal(t, tt.expected, readTestTileValues(t, result))
+		})
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"context"
+
+	"github.com/m3db/m3/src/query/generated/proto/prompb"
+)
+
+// MetricMetadataStorage writes and fetches the metadata of metric families.
+type MetricMetadataStorage interface {
+	// WriteMetricMetadata writes the metadata of metric families, replacing
+	// any metadata previously written for the same metric families.
+	WriteMetricMetadata(ctx context.Context, metadata []prompb.MetricMetadata) error
+
+	// FetchMetricMetadata fetches the metadata of the metric families
+	// matching the query, ordered by metric family name.
+	FetchMetricMetadata(
+		ctx context.Context,
+		query MetricMetadataQuery,
+	) ([]prompb.MetricMetadata, error)
+}
+
+// MetricMetadataQuery is a query for the metadata of metric families.
+type MetricMetadataQuery struct {
+	// MetricFamilyName restricts the results to the metric family with the
+	// name, when set.
+	MetricFamilyName string
+	// Limit is the maximum number of metric families returned, when set.
+	Limit int
+}
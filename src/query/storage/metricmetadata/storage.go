@@ -0,0 +1,387 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package metricmetadata implements a storage of the metadata of metric
+// families, written by Prometheus remote write, in the cluster KV store.
+package metricmetadata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/uber-go/tally"
+	"go.uber.org/zap"
+
+	clusterclient "github.com/m3db/m3/src/cluster/client"
+	"github.com/m3db/m3/src/cluster/kv"
+	"github.com/m3db/m3/src/query/generated/proto/prompb"
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/x/instrument"
+)
+
+const (
+	// KeyPrefix is the prefix of the KV keys the metadata is stored under.
+	KeyPrefix = "_prom_metric_metadata"
+
+	// numKeys is the number of KV keys the metadata is spread across, to
+	// keep the size of each value well below the limits of the KV store.
+	numKeys = 16
+
+	// maxMetricFamiliesPerKey bounds the metric families stored under each
+	// KV key, the least recently written metric families are evicted first.
+	maxMetricFamiliesPerKey = 1024
+
+	// maxPendingMetricFamilies bounds the metric families waiting to be
+	// written to the KV store, metadata written past the bound is dropped.
+	maxPendingMetricFamilies = 16384
+
+	// flushInterval is the interval at which pending metadata is written to
+	// the KV store.
+	flushInterval = 10 * time.Second
+
+	// refreshInterval is the interval after which unchanged metadata is
+	// written again, so that metric families still being written are not
+	// evicted as the least recently written.
+	refreshInterval = time.Hour
+
+	maxCheckAndSetAttempts = 5
+)
+
+var errTooManyCheckAndSetAttempts = errors.New(
+	"too many attempts to update metric metadata in the KV store")
+
+type kvStorage struct {
+	sync.RWMutex
+
+	storeFn func() (kv.Store, error)
+	nowFn   func() time.Time
+	logger  *zap.Logger
+	metrics kvStorageMetrics
+	// stored is the metadata last known to be stored under each key by
+	// metric family name, metadata is resent periodically by Prometheus and
+	// it is only written to the KV store when it changes or is due a refresh.
+	stored [numKeys]map[string]storedMetadata
+
+	pendingLock sync.Mutex
+	pending     map[string]prompb.MetricMetadata
+
+	closeOnce sync.Once
+	closeCh   chan struct{}
+	doneCh    chan struct{}
+}
+
+type storedMetadata struct {
+	metadata  prompb.MetricMetadata
+	writtenAt time.Time
+}
+
+type kvStorageMetrics struct {
+	written  tally.Counter
+	dropped  tally.Counter
+	evicted  tally.Counter
+	kvErrors tally.Counter
+}
+
+func newKVStorageMetrics(scope tally.Scope) kvStorageMetrics {
+	return kvStorageMetrics{
+		written:  scope.Counter("written"),
+		dropped:  scope.Counter("dropped"),
+		evicted:  scope.Counter("evicted"),
+		kvErrors: scope.Counter("kv-errors"),
+	}
+}
+
+// Storage is a metric metadata storage which writes metadata to the KV store
+// asynchronously, and must be closed to stop writing.
+type Storage interface {
+	storage.MetricMetadataStorage
+
+	// Close writes any pending metadata and stops writing to the KV store.
+	Close()
+}
+
+// NewKVStorage returns a new metric metadata storage that stores metadata
+// in the KV store.
+func NewKVStorage(store kv.Store, instrumentOpts instrument.Options) Storage {
+	s := newKVStorage(func() (kv.Store, error) {
+		return store, nil
+	}, instrumentOpts)
+	go s.flushUntilClosed()
+	return s
+}
+
+// NewClusterStorage returns a new metric metadata storage that stores
+// metadata in the KV store of the cluster client. The KV store is resolved
+// when metadata is first written or fetched since the cluster client may
+// not be initialized yet.
+func NewClusterStorage(
+	client clusterclient.Client,
+	instrumentOpts instrument.Options,
+) Storage {
+	s := newKVStorage(client.KV, instrumentOpts)
+	go s.flushUntilClosed()
+	return s
+}
+
+func newKVStorage(
+	storeFn func() (kv.Store, error),
+	instrumentOpts instrument.Options,
+) *kvStorage {
+	s := &kvStorage{
+		storeFn: storeFn,
+		nowFn:   time.Now,
+		logger:  instrumentOpts.Logger(),
+		metrics: newKVStorageMetrics(instrumentOpts.MetricsScope().
+			SubScope("metric-metadata")),
+		pending: make(map[string]prompb.MetricMetadata),
+		closeCh: make(chan struct{}),
+		doneCh:  make(chan struct{}),
+	}
+	for k := range s.stored {
+		s.stored[k] = make(map[string]storedMetadata)
+	}
+	return s
+}
+
+// WriteMetricMetadata queues the metadata to be written to the KV store,
+// it does not wait for the metadata to be written and never fails since
+// Prometheus resends metadata periodically.
+func (s *kvStorage) WriteMetricMetadata(
+	_ context.Context,
+	metadata []prompb.MetricMetadata,
+) error {
+	now := s.nowFn()
+	s.RLock()
+	s.pendingLock.Lock()
+	for _, m := range metadata {
+		if m.MetricFamilyName == "" {
+			continue
+		}
+		stored, ok := s.stored[keyIndex(m.MetricFamilyName)][m.MetricFamilyName]
+		if ok && stored.metadata == m && now.Sub(stored.writtenAt) < refreshInterval {
+			continue
+		}
+		if _, ok := s.pending[m.MetricFamilyName]; !ok &&
+			len(s.pending) >= maxPendingMetricFamilies {
+			s.metrics.dropped.Inc(1)
+			continue
+		}
+		s.pending[m.MetricFamilyName] = m
+	}
+	s.pendingLock.Unlock()
+	s.RUnlock()
+	return nil
+}
+
+func (s *kvStorage) flushUntilClosed() {
+	defer close(s.doneCh)
+
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.flush()
+		case <-s.closeCh:
+			s.flush()
+			return
+		}
+	}
+}
+
+func (s *kvStorage) Close() {
+	s.closeOnce.Do(func() {
+		close(s.closeCh)
+		<-s.doneCh
+	})
+}
+
+// flush writes the pending metadata to the KV store, metadata which fails to
+// be written is dropped and written again once it is resent.
+func (s *kvStorage) flush() {
+	s.pendingLock.Lock()
+	pending := s.pending
+	s.pending = make(map[string]prompb.MetricMetadata, len(pending))
+	s.pendingLock.Unlock()
+
+	if len(pending) == 0 {
+		return
+	}
+
+	updates := make(map[int][]prompb.MetricMetadata)
+	for _, m := range pending {
+		k := keyIndex(m.MetricFamilyName)
+		updates[k] = append(updates[k], m)
+	}
+
+	store, err := s.storeFn()
+	if err != nil {
+		s.metrics.kvErrors.Inc(1)
+		s.logger.Error("could not resolve metric metadata KV store", zap.Error(err))
+		return
+	}
+
+	for k, update := range updates {
+		if err := s.update(store, k, update); err != nil {
+			s.metrics.kvErrors.Inc(1)
+			s.logger.Error("could not write metric metadata to KV store",
+				zap.String("key", metadataKey(k)), zap.Error(err))
+			continue
+		}
+		s.metrics.written.Inc(int64(len(update)))
+	}
+}
+
+func (s *kvStorage) update(
+	store kv.Store,
+	k int,
+	update []prompb.MetricMetadata,
+) error {
+	key := metadataKey(k)
+	for attempt := 0; attempt < maxCheckAndSetAttempts; attempt++ {
+		set, version, err := get(store, key)
+		if err != nil {
+			return err
+		}
+
+		var evicted int
+		set.Metadata, evicted = mergeMetadata(set.Metadata, update)
+		_, err = store.CheckAndSet(key, version, set)
+		if errors.Is(err, kv.ErrVersionMismatch) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		s.metrics.evicted.Inc(int64(evicted))
+
+		now := s.nowFn()
+		stored := make(map[string]storedMetadata, len(set.Metadata))
+		for _, m := range set.Metadata {
+			stored[m.MetricFamilyName] = storedMetadata{metadata: m, writtenAt: now}
+		}
+		s.Lock()
+		s.stored[k] = stored
+		s.Unlock()
+		return nil
+	}
+
+	return errTooManyCheckAndSetAttempts
+}
+
+func get(store kv.Store, key string) (*prompb.MetricMetadataSet, int, error) {
+	var set prompb.MetricMetadataSet
+	value, err := store.Get(key)
+	if errors.Is(err, kv.ErrNotFound) {
+		return &set, 0, nil
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if err := value.Unmarshal(&set); err != nil {
+		return nil, 0, err
+	}
+	return &set, value.Version(), nil
+}
+
+func (s *kvStorage) FetchMetricMetadata(
+	_ context.Context,
+	query storage.MetricMetadataQuery,
+) ([]prompb.MetricMetadata, error) {
+	keys := make([]int, 0, numKeys)
+	if query.MetricFamilyName != "" {
+		keys = append(keys, keyIndex(query.MetricFamilyName))
+	} else {
+		for k := 0; k < numKeys; k++ {
+			keys = append(keys, k)
+		}
+	}
+
+	store, err := s.storeFn()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []prompb.MetricMetadata
+	for _, k := range keys {
+		set, _, err := get(store, metadataKey(k))
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range set.Metadata {
+			if query.MetricFamilyName != "" &&
+				query.MetricFamilyName != m.MetricFamilyName {
+				continue
+			}
+			result = append(result, m)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].MetricFamilyName < result[j].MetricFamilyName
+	})
+	if query.Limit > 0 && len(result) > query.Limit {
+		result = result[:query.Limit]
+	}
+	return result, nil
+}
+
+// mergeMetadata returns the existing metadata with the metadata of the
+// metric families of the update replaced, ordered from the most to the least
+// recently written, along with the number of metric families evicted to
+// bound the metadata to maxMetricFamiliesPerKey.
+func mergeMetadata(
+	existing []prompb.MetricMetadata,
+	update []prompb.MetricMetadata,
+) ([]prompb.MetricMetadata, int) {
+	updated := make(map[string]struct{}, len(update))
+	merged := make([]prompb.MetricMetadata, 0, len(existing)+len(update))
+	for _, m := range update {
+		updated[m.MetricFamilyName] = struct{}{}
+		merged = append(merged, m)
+	}
+	for _, m := range existing {
+		if _, ok := updated[m.MetricFamilyName]; !ok {
+			merged = append(merged, m)
+		}
+	}
+
+	if len(merged) <= maxMetricFamiliesPerKey {
+		return merged, 0
+	}
+	return merged[:maxMetricFamiliesPerKey], len(merged) - maxMetricFamiliesPerKey
+}
+
+func keyIndex(metricFamilyName string) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(metricFamilyName))
+	return int(h.Sum32() % numKeys)
+}
+
+func metadataKey(k int) string {
+	return fmt.Sprintf("%s/%d", KeyPrefix, k)
+}
@@ -0,0 +1,251 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package metricmetadata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/cluster/kv"
+	"github.com/m3db/m3/src/cluster/kv/mem"
+	"github.com/m3db/m3/src/query/generated/proto/prompb"
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/x/instrument"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestKVStorage(store kv.Store) *kvStorage {
+	return newKVStorage(func() (kv.Store, error) {
+		return store, nil
+	}, instrument.NewOptions())
+}
+
+func TestKVStorageWriteFetch(t *testing.T) {
+	ctx := context.Background()
+	store := newTestKVStorage(mem.NewStore())
+
+	require.NoError(t, store.WriteMetricMetadata(ctx, []prompb.MetricMetadata{
+		{
+			Type:             prompb.MetricType_COUNTER,
+			MetricFamilyName: "http_requests_total",
+			Help:             "Total number of HTTP requests.",
+		},
+		{
+			Type:             prompb.MetricType_GAUGE,
+			MetricFamilyName: "memory_usage",
+			Help:             "Memory usage.",
+			Unit:             "bytes",
+		},
+		{Type: prompb.MetricType_GAUGE},
+	}))
+	store.flush()
+
+	// A later write replaces the metadata of a metric family.
+	require.NoError(t, store.WriteMetricMetadata(ctx, []prompb.MetricMetadata{
+		{
+			Type:             prompb.MetricType_HISTOGRAM,
+			MetricFamilyName: "http_request_duration_seconds",
+			Help:             "HTTP request durations.",
+			Unit:             "seconds",
+		},
+		{
+			Type:             prompb.MetricType_GAUGE,
+			MetricFamilyName: "memory_usage",
+			Help:             "Resident memory usage.",
+			Unit:             "bytes",
+		},
+	}))
+	store.flush()
+
+	result, err := store.FetchMetricMetadata(ctx, storage.MetricMetadataQuery{})
+	require.NoError(t, err)
+	assert.Equal(t, []prompb.MetricMetadata{
+		{
+			Type:             prompb.MetricType_HISTOGRAM,
+			MetricFamilyName: "http_request_duration_seconds",
+			Help:             "HTTP request durations.",
+			Unit:             "seconds",
+		},
+		{
+			Type:             prompb.MetricType_COUNTER,
+			MetricFamilyName: "http_requests_total",
+			Help:             "Total number of HTTP requests.",
+		},
+		{
+			Type:             prompb.MetricType_GAUGE,
+			MetricFamilyName: "memory_usage",
+			Help:             "Resident memory usage.",
+			Unit:             "bytes",
+		},
+	}, result)
+
+	result, err = store.FetchMetricMetadata(ctx, storage.MetricMetadataQuery{
+		MetricFamilyName: "memory_usage",
+	})
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	assert.Equal(t, "Resident memory usage.", result[0].Help)
+
+	result, err = store.FetchMetricMetadata(ctx, storage.MetricMetadataQuery{
+		Limit: 2,
+	})
+	require.NoError(t, err)
+	require.Len(t, result, 2)
+	assert.Equal(t, "http_requests_total", result[1].MetricFamilyName)
+}
+
+func TestKVStorageSharedStore(t *testing.T) {
+	ctx := context.Background()
+	kvStore := mem.NewStore()
+	first := newTestKVStorage(kvStore)
+	second := newTestKVStorage(kvStore)
+
+	metadata := prompb.MetricMetadata{
+		Type:             prompb.MetricType_COUNTER,
+		MetricFamilyName: "http_requests_total",
+	}
+	require.NoError(t, first.WriteMetricMetadata(ctx,
+		[]prompb.MetricMetadata{metadata}))
+	first.flush()
+
+	// Metadata written by another coordinator is kept when metadata of a
+	// metric family stored under the same key is written.
+	other := prompb.MetricMetadata{
+		Type:             prompb.MetricType_GAUGE,
+		MetricFamilyName: "memory_usage",
+	}
+	for i := 0; keyIndex(other.MetricFamilyName) !=
+		keyIndex(metadata.MetricFamilyName); i++ {
+		other.MetricFamilyName = "memory_usage_" + string(rune('a'+i%26)) +
+			string(rune('a'+i/26%26))
+	}
+	require.NoError(t, second.WriteMetricMetadata(ctx,
+		[]prompb.MetricMetadata{other}))
+	second.flush()
+
+	result, err := first.FetchMetricMetadata(ctx, storage.MetricMetadataQuery{})
+	require.NoError(t, err)
+	assert.Equal(t, []prompb.MetricMetadata{metadata, other}, result)
+
+	// Unchanged metadata is not written again.
+	key := metadataKey(keyIndex(metadata.MetricFamilyName))
+	value, err := kvStore.Get(key)
+	require.NoError(t, err)
+	require.NoError(t, second.WriteMetricMetadata(ctx,
+		[]prompb.MetricMetadata{other}))
+	second.flush()
+	unchanged, err := kvStore.Get(key)
+	require.NoError(t, err)
+	assert.Equal(t, value.Version(), unchanged.Version())
+}
+
+func TestKVStorageRefreshesUnchangedMetadata(t *testing.T) {
+	ctx := context.Background()
+	kvStore := mem.NewStore()
+	store := newTestKVStorage(kvStore)
+	now := time.Now()
+	store.nowFn = func() time.Time { return now }
+
+	metadata := []prompb.MetricMetadata{{
+		Type:             prompb.MetricType_COUNTER,
+		MetricFamilyName: "http_requests_total",
+	}}
+	require.NoError(t, store.WriteMetricMetadata(ctx, metadata))
+	store.flush()
+	key := metadataKey(keyIndex(metadata[0].MetricFamilyName))
+	value, err := kvStore.Get(key)
+	require.NoError(t, err)
+
+	// Unchanged metadata is written again once due a refresh, so that it is
+	// the most recently written.
+	now = now.Add(refreshInterval)
+	require.NoError(t, store.WriteMetricMetadata(ctx, metadata))
+	store.flush()
+	refreshed, err := kvStore.Get(key)
+	require.NoError(t, err)
+	assert.Equal(t, value.Version()+1, refreshed.Version())
+}
+
+func TestMergeMetadataEvictsLeastRecentlyWritten(t *testing.T) {
+	var existing []prompb.MetricMetadata
+	for i := 0; i < maxMetricFamiliesPerKey; i++ {
+		existing = append(existing, prompb.MetricMetadata{
+			MetricFamilyName: fmt.Sprintf("metric_%d", i),
+		})
+	}
+
+	merged, evicted := mergeMetadata(existing, []prompb.MetricMetadata{
+		{MetricFamilyName: "metric_1", Help: "updated"},
+		{MetricFamilyName: "new_metric"},
+	})
+	require.Equal(t, 1, evicted)
+	require.Len(t, merged, maxMetricFamiliesPerKey)
+	assert.Equal(t, "metric_1", merged[0].MetricFamilyName)
+	assert.Equal(t, "updated", merged[0].Help)
+	assert.Equal(t, "new_metric", merged[1].MetricFamilyName)
+	assert.Equal(t, "metric_0", merged[2].MetricFamilyName)
+	assert.Equal(t, fmt.Sprintf("metric_%d", maxMetricFamiliesPerKey-2),
+		merged[len(merged)-1].MetricFamilyName)
+}
+
+func TestKVStorageFailsSoft(t *testing.T) {
+	ctx := context.Background()
+	store := newKVStorage(func() (kv.Store, error) {
+		return nil, errors.New("kv unavailable")
+	}, instrument.NewOptions())
+
+	// Writes never fail, metadata which can not be written is dropped.
+	require.NoError(t, store.WriteMetricMetadata(ctx, []prompb.MetricMetadata{{
+		Type:             prompb.MetricType_COUNTER,
+		MetricFamilyName: "http_requests_total",
+	}}))
+	store.flush()
+	assert.Empty(t, store.pending)
+
+	// Metadata past the bound of pending metric families is dropped.
+	for i := 0; i < maxPendingMetricFamilies+1; i++ {
+		require.NoError(t, store.WriteMetricMetadata(ctx, []prompb.MetricMetadata{{
+			MetricFamilyName: fmt.Sprintf("metric_%d", i),
+		}}))
+	}
+	assert.Len(t, store.pending, maxPendingMetricFamilies)
+}
+
+func TestKVStorageCloseWritesPending(t *testing.T) {
+	ctx := context.Background()
+	store := NewKVStorage(mem.NewStore(), instrument.NewOptions())
+
+	metadata := []prompb.MetricMetadata{{
+		Type:             prompb.MetricType_COUNTER,
+		MetricFamilyName: "http_requests_total",
+	}}
+	require.NoError(t, store.WriteMetricMetadata(ctx, metadata))
+	store.Close()
+
+	result, err := store.FetchMetricMetadata(ctx, storage.MetricMetadataQuery{})
+	require.NoError(t, err)
+	assert.Equal(t, metadata, result)
+}
@@ -41,6 +41,16 @@ import (
 	"github.com/m3db/m3/src/x/instrument"
 )
 
+// tileFunctionHints are the functions passed as hints to select the aggregate
+// of downsampled tiles. The Prometheus engine counts and averages the samples
+// itself, so the count and the average over time of tiles are only evaluated
+// by the M3 query engine.
+var tileFunctionHints = map[string]struct{}{
+	"min_over_time": {},
+	"max_over_time": {},
+	"sum_over_time": {},
+}
+
 type prometheusQueryable struct {
 	storage storage.Storage
 	scope   tally.Scope
@@ -136,6 +146,10 @@ func (q *querier) Select(
 		q.logger.Error("fetch options not provided in context", zap.Error(err))
 		return promstorage.ErrSeriesSet(err)
 	}
+	if _, ok := tileFunctionHints[hints.Func]; ok {
+		fetchOptions = fetchOptions.Clone()
+		fetchOptions.FunctionHint = hints.Func
+	}
 
 	result, err := q.storage.FetchProm(q.ctx, query, fetchOptions)
 	if err != nil {
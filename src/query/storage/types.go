@@ -147,6 +147,9 @@ type FetchOptions struct {
 	Timeout time.Duration
 	// Source is the source for the query.
 	Source []byte
+	// FunctionHint is the name of the function applied to the fetched
+	// series, if known, used to select the aggregate of downsampled tiles.
+	FunctionHint string
 }
 
 // FanoutOptions describes which namespaces should be fanned out to for
@@ -180,6 +180,36 @@ func (s *AsyncSession) FetchTagged(
 	return s.session.FetchTagged(ctx, namespace, q, opts)
 }
 
+// FetchTaggedStream resolves the provided query to known IDs, and
+// streams the data for them.
+func (s *AsyncSession) FetchTaggedStream(
+	ctx context.Context,
+	namespace ident.ID,
+	q index.Query,
+	opts index.QueryOptions,
+	fn func(encoding.SeriesIterator) error,
+) (client.FetchResponseMetadata, error) {
+	s.RLock()
+	defer s.RUnlock()
+	if s.err != nil {
+		return client.FetchResponseMetadata{}, s.err
+	}
+
+	return s.session.FetchTaggedStream(ctx, namespace, q, opts, fn)
+}
+
+// StreamingReadsEnabled returns whether streaming reads are enabled.
+func (s *AsyncSession) StreamingReadsEnabled() bool {
+	s.RLock()
+	defer s.RUnlock()
+	if s.err != nil {
+		return false
+	}
+
+	session, ok := s.session.(client.StreamingReadsSession)
+	return ok && session.StreamingReadsEnabled()
+}
+
 // FetchTaggedIDs resolves the provided query to known IDs.
 func (s *AsyncSession) FetchTaggedIDs(
 	ctx context.Context,
@@ -196,6 +226,53 @@ func (s *AsyncSession) FetchTaggedIDs(
 	return s.session.FetchTaggedIDs(ctx, namespace, q, opts)
 }
 
+// WriteExemplars writes the exemplars for each of the series.
+func (s *AsyncSession) WriteExemplars(
+	namespace ident.ID,
+	series []client.SeriesExemplars,
+) error {
+	s.RLock()
+	defer s.RUnlock()
+	if s.err != nil {
+		return s.err
+	}
+
+	return s.session.WriteExemplars(namespace, series)
+}
+
+// FetchExemplars resolves the provided query to known IDs, and fetches the
+// exemplars for them.
+func (s *AsyncSession) FetchExemplars(
+	ctx context.Context,
+	namespace ident.ID,
+	q index.Query,
+	opts index.QueryOptions,
+) ([]client.SeriesExemplars, client.FetchResponseMetadata, error) {
+	s.RLock()
+	defer s.RUnlock()
+	if s.err != nil {
+		return nil, client.FetchResponseMetadata{}, s.err
+	}
+
+	return s.session.FetchExemplars(ctx, namespace, q, opts)
+}
+
+// Cardinality returns the cardinality statistics of the series indexed
+// within the time range.
+func (s *AsyncSession) Cardinality(
+	ctx context.Context,
+	namespace ident.ID,
+	opts index.CardinalityOptions,
+) (index.CardinalityResult, error) {
+	s.RLock()
+	defer s.RUnlock()
+	if s.err != nil {
+		return index.CardinalityResult{}, s.err
+	}
+
+	return s.session.Cardinality(ctx, namespace, opts)
+}
+
 // Aggregate aggregates values from the database for the given set of constraints.
 func (s *AsyncSession) Aggregate(
 	ctx context.Context,
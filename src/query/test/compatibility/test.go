@@ -49,6 +49,7 @@ import (
 
 	cparser "github.com/m3db/m3/src/cmd/services/m3comparator/main/parser"
 	"github.com/m3db/m3/src/query/api/v1/handler/prometheus"
+	xpromql "github.com/m3db/m3/src/query/parser/promql"
 
 	"github.com/pkg/errors"
 	"github.com/prometheus/common/model"
@@ -155,7 +156,7 @@ func (t *Test) parseEval(lines []string, i int) (int, *evalCmd, error) {
 		at   = parts[2]
 		expr = parts[3]
 	)
-	_, err := parser.ParseExpr(expr)
+	_, err := xpromql.ParseExpr(expr)
 	if err != nil {
 		if perr, ok := err.(*parser.ParseErr); ok {
 			perr.LineOffset = i
@@ -508,11 +509,23 @@ func (t *Test) exec(tc testCommand) error {
 		return cmd.append()
 
 	case *evalCmd:
-		expr, err := parser.ParseExpr(cmd.expr)
+		expr, err := xpromql.ParseExpr(cmd.expr)
 		if err != nil {
 			return err
 		}
 
+		// NB: samples are loaded relative to the starting time, so @ modifier
+		// timestamps are shifted by it as well.
+		parser.Inspect(expr, func(node parser.Node, _ []parser.Node) error {
+			switch n := node.(type) {
+			case *parser.VectorSelector:
+				shiftTimestamp(n.Timestamp)
+			case *parser.SubqueryExpr:
+				shiftTimestamp(n.Timestamp)
+			}
+			return nil
+		})
+
 		t := time.Unix(0, startingTime+(cmd.start.Unix()*1000000000))
 		bodyBytes, err := cmd.m3query.query(expr.String(), t)
 		if err != nil {
@@ -536,6 +549,14 @@ func (t *Test) exec(tc testCommand) error {
 	return nil
 }
 
+// shiftTimestamp shifts an @ modifier timestamp in milliseconds by the
+// starting time.
+func shiftTimestamp(ts *int64) {
+	if ts != nil {
+		*ts += startingTime / int64(time.Millisecond)
+	}
+}
+
 // clear the current test storage of all inserted samples.
 func (t *Test) clear() error {
 	return t.m3comparator.clear()
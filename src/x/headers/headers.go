@@ -62,6 +62,10 @@ const (
 	// SourceHeader tracks bytes and docs read for the given source, if provided.
 	SourceHeader = M3HeaderPrefix + "Source"
 
+	// TenantHeader sets the tenant a query is attributed to by the tenant
+	// quotas of dbnodes, if provided, taking precedence over the source.
+	TenantHeader = M3HeaderPrefix + "Tenant"
+
 	// DefaultWriteType is the default write type.
 	DefaultWriteType = "default"
 